@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	adminAPIKey string
+	adminActor  string
+	adminReason string
+
+	exportConsumerID string
+	exportSessionIDs []string
+	exportFile       string
+
+	importFile     string
+	importTakeOver bool
+
+	auditTerminationsAll bool
+)
+
+// exportPassphraseEnv holds the passphrase that encrypts SSH keys in session
+// exports. It is read from the environment so it stays out of shell history.
+const exportPassphraseEnv = "GPU_SHOPPER_EXPORT_PASSPHRASE"
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Audited operator commands",
+	Long: `Operator commands that use the admin API. Requires the admin API key
+(--admin-key or GPU_SHOPPER_ADMIN_KEY) and an operator name (--actor).`,
+}
+
+var adminExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export sessions for disaster recovery drills or migration",
+	Long: `Export sessions with their status history, cost records and SSH keys.
+Keys are encrypted with the passphrase in ` + exportPassphraseEnv + `.`,
+	RunE: runAdminExport,
+}
+
+var adminImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import sessions exported from another deployment",
+	Long: `Import a session export. Sessions that already exist are skipped.
+Sessions that were still active are imported as stopped unless --take-over is
+set, in which case this deployment manages (and on expiry destroys) their
+instances. Only take over after the source deployment has been shut down.`,
+	RunE: runAdminImport,
+}
+
+var adminAuditTerminationsCmd = &cobra.Command{
+	Use:   "audit-terminations",
+	Short: "Find provider instances that may never be terminated",
+	Long: `List every instance the providers report, cross-referenced with sessions.
+Instances are flagged when no active session owns them, they carry no expiry
+tag, their expiry has passed, or their session has stopped sending heartbeats.
+Nothing is destroyed; use cleanup-orphans or the reconciler for that.`,
+	RunE: runAdminAuditTerminations,
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminExportCmd)
+	adminCmd.AddCommand(adminImportCmd)
+	adminCmd.AddCommand(adminAuditTerminationsCmd)
+
+	adminCmd.PersistentFlags().StringVar(&adminAPIKey, "admin-key", getEnvOrDefault("GPU_SHOPPER_ADMIN_KEY", ""), "Admin API key")
+	adminCmd.PersistentFlags().StringVar(&adminActor, "actor", getEnvOrDefault("USER", ""), "Operator name recorded in the audit log")
+	adminCmd.PersistentFlags().StringVar(&adminReason, "reason", "", "Reason recorded in the audit log (e.g. ticket ID)")
+
+	adminExportCmd.Flags().StringVarP(&exportConsumerID, "consumer", "c", "", "Export all sessions of a consumer")
+	adminExportCmd.Flags().StringSliceVarP(&exportSessionIDs, "session", "s", nil, "Session ID to export (repeatable)")
+	adminExportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "Write the export to a file instead of stdout")
+
+	adminImportCmd.Flags().StringVarP(&importFile, "file", "f", "", "Export file to import (required)")
+	adminImportCmd.Flags().BoolVar(&importTakeOver, "take-over", false, "Keep active sessions active and manage their instances")
+	adminImportCmd.MarkFlagRequired("file")
+
+	adminAuditTerminationsCmd.Flags().BoolVar(&auditTerminationsAll, "all", false, "Also list instances with no issues")
+}
+
+func runAdminExport(cmd *cobra.Command, args []string) error {
+	passphrase := os.Getenv(exportPassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set", exportPassphraseEnv)
+	}
+	if exportConsumerID == "" && len(exportSessionIDs) == 0 {
+		return fmt.Errorf("--consumer or --session is required")
+	}
+
+	body, err := adminPost("/api/v1/admin/sessions/export", map[string]interface{}{
+		"consumer_id": exportConsumerID,
+		"session_ids": exportSessionIDs,
+		"passphrase":  passphrase,
+	})
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if exportFile == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+
+	var bundle struct {
+		Sessions []json.RawMessage `json:"sessions"`
+	}
+	if err := json.Unmarshal(body, &bundle); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if err := os.WriteFile(exportFile, body, 0600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	fmt.Fprintf(progressOut(), "Exported %d sessions to %s\n", len(bundle.Sessions), exportFile)
+	return nil
+}
+
+func runAdminImport(cmd *cobra.Command, args []string) error {
+	passphrase := os.Getenv(exportPassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set", exportPassphraseEnv)
+	}
+
+	data, err := os.ReadFile(importFile)
+	if err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf("%s is not a valid export file", importFile)
+	}
+
+	body, err := adminPost("/api/v1/admin/sessions/import", map[string]interface{}{
+		"export":     json.RawMessage(data),
+		"passphrase": passphrase,
+		"take_over":  importTakeOver,
+	})
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	var result struct {
+		Imported    []string `json:"imported"`
+		Skipped     []string `json:"skipped"`
+		Deactivated []string `json:"deactivated"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Printf("Imported:    %d\n", len(result.Imported))
+	fmt.Printf("Skipped:     %d (already present)\n", len(result.Skipped))
+	fmt.Printf("Deactivated: %d (imported as stopped)\n", len(result.Deactivated))
+	return nil
+}
+
+func runAdminAuditTerminations(cmd *cobra.Command, args []string) error {
+	body, err := adminGet("/api/v1/admin/terminations")
+	if err != nil {
+		return fmt.Errorf("termination audit failed: %w", err)
+	}
+
+	var audit struct {
+		Providers []struct {
+			Provider         string `json:"provider"`
+			Instances        int    `json:"instances"`
+			OtherDeployments int    `json:"other_deployments"`
+			Flagged          int    `json:"flagged"`
+			Error            string `json:"error"`
+		} `json:"providers"`
+		Instances []struct {
+			Provider      string   `json:"provider"`
+			InstanceID    string   `json:"instance_id"`
+			Status        string   `json:"status"`
+			PricePerHour  float64  `json:"price_per_hour"`
+			SessionID     string   `json:"session_id"`
+			SessionStatus string   `json:"session_status"`
+			ExpiresAt     string   `json:"expires_at"`
+			Issues        []string `json:"issues"`
+		} `json:"instances"`
+		Flagged int `json:"flagged"`
+	}
+	if err := json.Unmarshal(body, &audit); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if outputFormat == "json" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tINSTANCE\tSTATUS\tPRICE/HR\tSESSION\tEXPIRES\tISSUES")
+	for _, inst := range audit.Instances {
+		if len(inst.Issues) == 0 && !auditTerminationsAll {
+			continue
+		}
+		session := inst.SessionID
+		if inst.SessionStatus != "" {
+			session += " (" + inst.SessionStatus + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t$%.2f\t%s\t%s\t%s\n",
+			inst.Provider, inst.InstanceID, inst.Status, inst.PricePerHour,
+			valueOrDash(session), valueOrDash(inst.ExpiresAt), valueOrDash(strings.Join(inst.Issues, ",")))
+	}
+	w.Flush()
+
+	fmt.Println()
+	for _, p := range audit.Providers {
+		if p.Error != "" {
+			fmt.Printf("%s: audit failed: %s\n", p.Provider, p.Error)
+			continue
+		}
+		fmt.Printf("%s: %d instances, %d flagged", p.Provider, p.Instances, p.Flagged)
+		if p.OtherDeployments > 0 {
+			fmt.Printf(", %d from other deployments skipped", p.OtherDeployments)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\nFlagged: %d\n", audit.Flagged)
+	return nil
+}
+
+// valueOrDash returns s, or "-" for an empty table cell
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// adminPost sends an authenticated admin request and returns the response body
+func adminPost(path string, payload interface{}) ([]byte, error) {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return adminRequest(http.MethodPost, path, bytes.NewReader(jsonBody))
+}
+
+// adminGet sends an authenticated admin GET and returns the response body
+func adminGet(path string) ([]byte, error) {
+	return adminRequest(http.MethodGet, path, nil)
+}
+
+func adminRequest(method, path string, reqBody io.Reader) ([]byte, error) {
+	if adminAPIKey == "" {
+		return nil, fmt.Errorf("admin API key is required (--admin-key or GPU_SHOPPER_ADMIN_KEY)")
+	}
+	if adminActor == "" {
+		return nil, fmt.Errorf("--actor is required")
+	}
+
+	req, err := http.NewRequest(method, serverURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+adminAPIKey)
+	req.Header.Set("X-Admin-Actor", adminActor)
+	if adminReason != "" {
+		req.Header.Set("X-Admin-Reason", adminReason)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server error: %s", string(body))
+	}
+	return body, nil
+}
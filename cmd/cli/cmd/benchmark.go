@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -17,6 +21,27 @@ var (
 	benchGPU    string
 	benchLimit  int
 	benchMinTPS float64
+
+	benchProvider string
+	benchSince    string
+	benchUntil    string
+
+	benchReportFormat string
+	benchReportFile   string
+
+	benchRunModels    []string
+	benchRunGPUs      []string
+	benchRunProviders []string
+	benchRunBudget    float64
+	benchRunParallel  int
+	benchRunLocation  string
+	benchRunEndpoint  string
+	benchRunSession   string
+	benchRunSweep     []int
+	benchRunQuants    []string
+	benchRunBackends  []string
+	benchRunWorkload  string
+	benchRunFollow    bool
 )
 
 // BenchmarkResult represents a benchmark from the API
@@ -30,6 +55,7 @@ type BenchmarkResult struct {
 	Provider  string       `json:"provider"`
 	Location  string       `json:"location"`
 	Price     float64      `json:"price_per_hour"`
+	Workload  string       `json:"workload"`
 }
 
 type HardwareInfo struct {
@@ -60,15 +86,21 @@ type PerfResults struct {
 	AvgLatency    float64 `json:"avg_latency_ms"`
 	P95Latency    float64 `json:"p95_latency_ms"`
 	ErrorRate     float64 `json:"error_rate"`
+	TokensPerWatt float64 `json:"tokens_per_watt"`
+
+	Throughput     float64 `json:"throughput"`
+	ThroughputUnit string  `json:"throughput_unit"`
 }
 
 type GPUStats struct {
-	AvgUtil   float64 `json:"avg_utilization_pct"`
-	MaxUtil   float64 `json:"max_utilization_pct"`
-	AvgTemp   float64 `json:"avg_temperature_c"`
-	MaxTemp   float64 `json:"max_temperature_c"`
-	AvgPower  float64 `json:"avg_power_draw_w"`
-	MaxMemMiB int     `json:"max_memory_used_mib"`
+	AvgUtil    float64 `json:"avg_utilization_pct"`
+	MaxUtil    float64 `json:"max_utilization_pct"`
+	AvgTemp    float64 `json:"avg_temperature_c"`
+	MaxTemp    float64 `json:"max_temperature_c"`
+	AvgPower   float64 `json:"avg_power_draw_w"`
+	TotalPower float64 `json:"avg_total_power_w"`
+	EnergyWh   float64 `json:"energy_wh"`
+	MaxMemMiB  int     `json:"max_memory_used_mib"`
 }
 
 type CostAnalysis struct {
@@ -78,6 +110,53 @@ type CostAnalysis struct {
 	EstimatedMonthly     float64 `json:"estimated_monthly_24x7"`
 }
 
+// BenchmarkHistoryEntry is one run in the benchmark history
+type BenchmarkHistoryEntry struct {
+	ID                   string  `json:"id"`
+	Timestamp            string  `json:"timestamp"`
+	Model                string  `json:"model"`
+	GPUName              string  `json:"gpu_name"`
+	GPUCount             int     `json:"gpu_count"`
+	Provider             string  `json:"provider"`
+	Location             string  `json:"location,omitempty"`
+	AvgTPS               float64 `json:"avg_tokens_per_second"`
+	AvgLatency           float64 `json:"avg_latency_ms"`
+	P95Latency           float64 `json:"p95_latency_ms"`
+	ErrorRate            float64 `json:"error_rate"`
+	Price                float64 `json:"price_per_hour"`
+	RunCost              float64 `json:"run_cost"`
+	CostPerMillionTokens float64 `json:"cost_per_million_tokens,omitempty"`
+	Trend                string  `json:"trend,omitempty"`
+	ChangePct            float64 `json:"change_pct,omitempty"`
+}
+
+type BenchmarkHistoryResponse struct {
+	History []BenchmarkHistoryEntry `json:"history"`
+	Count   int                     `json:"count"`
+}
+
+// BenchmarkRun is an automated benchmark run started on the server
+type BenchmarkRun struct {
+	ID             string  `json:"id"`
+	Status         string  `json:"status"`
+	TotalEntries   int     `json:"total_entries"`
+	Completed      int     `json:"completed"`
+	Failed         int     `json:"failed"`
+	Running        int     `json:"running"`
+	Pending        int     `json:"pending"`
+	Skipped        int     `json:"skipped"`
+	TotalCost      float64 `json:"total_cost"`
+	BudgetExceeded bool    `json:"budget_exceeded,omitempty"`
+	Request        struct {
+		MaxBudget float64 `json:"max_budget,omitempty"`
+		Parallel  int     `json:"parallel,omitempty"`
+	} `json:"request"`
+}
+
+type BenchmarkRunResponse struct {
+	Run BenchmarkRun `json:"run"`
+}
+
 type BenchmarkResponse struct {
 	Benchmarks []*BenchmarkResult `json:"benchmarks"`
 	Count      int                `json:"count"`
@@ -98,9 +177,14 @@ type Recommendation struct {
 	Model           string   `json:"model"`
 	MinVRAMGiB      int      `json:"min_vram_gib"`
 	RecommendedGPUs []string `json:"recommended_gpus"`
+	Quantization    string   `json:"quantization,omitempty"`
+	Runtime         string   `json:"runtime,omitempty"`
 	ExpectedTPS     float64  `json:"expected_tps"`
 	EstimatedCost   float64  `json:"estimated_cost_per_hour"`
 	Notes           string   `json:"notes"`
+
+	ExpectedThroughput float64 `json:"expected_throughput"`
+	ThroughputUnit     string  `json:"throughput_unit"`
 }
 
 var benchmarkCmd = &cobra.Command{
@@ -113,7 +197,10 @@ Examples:
   gpu-shopper benchmarks --model deepseek-r1  # Filter by model
   gpu-shopper benchmarks --gpu 4090           # Filter by GPU
   gpu-shopper benchmarks best --model llama   # Best benchmark for model
-  gpu-shopper benchmarks recommend --model x  # Hardware recommendations`,
+  gpu-shopper benchmarks recommend --model x  # Hardware recommendations
+  gpu-shopper benchmarks history --model x    # Past runs with trends
+  gpu-shopper benchmarks report --format html # Shareable report
+  gpu-shopper benchmarks run --model x --parallel 3  # Start a benchmark run`,
 	RunE: runBenchmarks,
 }
 
@@ -135,6 +222,64 @@ var benchmarkRecommendCmd = &cobra.Command{
 	RunE:  runBenchmarkRecommend,
 }
 
+var benchmarkHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show past benchmark runs with throughput trends",
+	Long: `Show past benchmark runs, newest first, with throughput, latency and cost
+per run. The trend column compares each run's throughput with the previous
+run of the same model on the same GPU (up/down beyond 5%).
+
+Examples:
+  gpu-shopper benchmarks history --model llama3.1:8b
+  gpu-shopper benchmarks history --gpu 4090 --provider vastai
+  gpu-shopper benchmarks history --since 2026-01-01 --until 2026-01-31`,
+	RunE: runBenchmarkHistory,
+}
+
+var benchmarkReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a benchmark report (markdown, JSON, HTML or CSV)",
+	Long: `Generate a report over stored benchmark runs: summary statistics,
+recommended GPUs per model, per-GPU averages, benchmark spend by provider
+and the full list of runs. The HTML report is a single file with embedded
+charts, suitable for sharing. CSV lists one run per row for spreadsheets.
+
+Examples:
+  gpu-shopper benchmarks report                                  # Markdown to stdout
+  gpu-shopper benchmarks report --model llama3.1:8b --format json
+  gpu-shopper benchmarks report --since 2026-01-01 --format html --file report.html
+  gpu-shopper benchmarks report --format csv --file runs.csv`,
+	RunE: runBenchmarkReport,
+}
+
+var benchmarkRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Start an automated benchmark run",
+	Long: `Start a benchmark run on the server for every combination of model, GPU
+type and provider. Each combination provisions an instance, benchmarks the
+model and tears the instance down.
+
+--parallel runs that many combinations at once. Combinations share the
+--max-budget ceiling: once the instances of the run have cost that much in
+total, in-flight combinations are aborted and the rest are skipped.
+
+--endpoint and --session skip provisioning and benchmark an OpenAI-compatible
+server that is already running, such as a long-lived vLLM deployment. The
+model defaults to the one the server serves; --gpu and --provider only label
+the results of an external endpoint.
+
+--follow streams the run's progress until it ends and exits non-zero if the
+run failed or was cancelled, for use in CI.
+
+Examples:
+  gpu-shopper benchmarks run --model llama3.1:8b --gpu "RTX 4090" --gpu "RTX 3090"
+  gpu-shopper benchmarks run --model llama3.1:8b,qwen2.5:14b --parallel 4 --max-budget 10
+  gpu-shopper benchmarks run --endpoint http://10.0.0.5:8000 --gpu H100
+  gpu-shopper benchmarks run --session sess-abc123
+  gpu-shopper benchmarks run --model llama3.1:8b --gpu "RTX 4090" --follow`,
+	RunE: runBenchmarkRun,
+}
+
 var benchmarkCompareCmd = &cobra.Command{
 	Use:   "compare",
 	Short: "Compare benchmarks for a model across hardware",
@@ -147,6 +292,9 @@ func init() {
 	benchmarkCmd.AddCommand(benchmarkCheapestCmd)
 	benchmarkCmd.AddCommand(benchmarkRecommendCmd)
 	benchmarkCmd.AddCommand(benchmarkCompareCmd)
+	benchmarkCmd.AddCommand(benchmarkHistoryCmd)
+	benchmarkCmd.AddCommand(benchmarkReportCmd)
+	benchmarkCmd.AddCommand(benchmarkRunCmd)
 
 	// List flags
 	benchmarkCmd.Flags().StringVarP(&benchModel, "model", "m", "", "Filter by model name")
@@ -168,6 +316,39 @@ func init() {
 	// Compare flags
 	benchmarkCompareCmd.Flags().StringVarP(&benchModel, "model", "m", "", "Model name (required)")
 	benchmarkCompareCmd.MarkFlagRequired("model")
+
+	// History flags
+	benchmarkHistoryCmd.Flags().StringVarP(&benchModel, "model", "m", "", "Filter by model name")
+	benchmarkHistoryCmd.Flags().StringVarP(&benchGPU, "gpu", "g", "", "Filter by GPU name")
+	benchmarkHistoryCmd.Flags().StringVarP(&benchProvider, "provider", "p", "", "Filter by provider")
+	benchmarkHistoryCmd.Flags().StringVar(&benchSince, "since", "", "Only runs on or after this date (YYYY-MM-DD or RFC3339)")
+	benchmarkHistoryCmd.Flags().StringVar(&benchUntil, "until", "", "Only runs up to this date (YYYY-MM-DD or RFC3339)")
+	benchmarkHistoryCmd.Flags().IntVarP(&benchLimit, "limit", "l", 20, "Maximum runs to return")
+
+	// Report flags
+	benchmarkReportCmd.Flags().StringVarP(&benchModel, "model", "m", "", "Filter by model name")
+	benchmarkReportCmd.Flags().StringVarP(&benchGPU, "gpu", "g", "", "Filter by GPU name")
+	benchmarkReportCmd.Flags().StringVarP(&benchProvider, "provider", "p", "", "Filter by provider")
+	benchmarkReportCmd.Flags().StringVar(&benchSince, "since", "", "Only runs on or after this date (YYYY-MM-DD or RFC3339)")
+	benchmarkReportCmd.Flags().StringVar(&benchUntil, "until", "", "Only runs up to this date (YYYY-MM-DD or RFC3339)")
+	benchmarkReportCmd.Flags().StringVarP(&benchReportFormat, "format", "f", "markdown", "Report format (markdown, json, html, csv)")
+	benchmarkReportCmd.Flags().StringVar(&benchReportFile, "file", "", "Write the report to this file instead of stdout")
+
+	// Run flags
+	benchmarkRunCmd.Flags().StringSliceVarP(&benchRunModels, "model", "m", nil, "Models to benchmark (repeatable; required unless --endpoint or --session)")
+	benchmarkRunCmd.Flags().StringSliceVarP(&benchRunGPUs, "gpu", "g", nil, "GPU types to benchmark (default: all available)")
+	benchmarkRunCmd.Flags().StringSliceVarP(&benchRunProviders, "provider", "p", nil, "Providers to use (default: all)")
+	benchmarkRunCmd.Flags().Float64Var(&benchRunBudget, "max-budget", 0, "Total cost ceiling for the run in USD (0 = no limit)")
+	benchmarkRunCmd.Flags().IntVar(&benchRunParallel, "parallel", 1, "Combinations to run at once")
+	benchmarkRunCmd.Flags().StringVar(&benchRunLocation, "location", "", "Country code filter for offers (e.g. US)")
+	benchmarkRunCmd.Flags().StringVar(&benchRunEndpoint, "endpoint", "", "Benchmark this running OpenAI-compatible server instead of provisioning")
+	benchmarkRunCmd.Flags().StringVar(&benchRunSession, "session", "", "Benchmark this running session's API endpoint instead of provisioning")
+	benchmarkRunCmd.Flags().IntSliceVar(&benchRunSweep, "concurrency", nil, "Sweep these concurrency levels against --endpoint or --session (e.g. 1,2,4,8)")
+	benchmarkRunCmd.Flags().StringVar(&benchRunWorkload, "workload", "", "What to measure against --endpoint or --session: chat (default), embeddings, transcription or image")
+	benchmarkRunCmd.Flags().StringSliceVar(&benchRunQuants, "quantization", nil, "Deploy vLLM with each quantization (fp16, awq, gptq, fp8) instead of Ollama")
+	benchmarkRunCmd.Flags().StringSliceVar(&benchRunBackends, "backend", nil, "Deploy each model with these inference servers (vllm, tgi, sglang, llamacpp) instead of Ollama")
+	benchmarkRunCmd.Flags().BoolVar(&benchRunFollow, "follow", false, "Stream progress until the run ends; exit non-zero unless it completed")
+	benchmarkRunCmd.MarkFlagsMutuallyExclusive("endpoint", "session")
 }
 
 func runBenchmarks(cmd *cobra.Command, args []string) error {
@@ -346,6 +527,310 @@ func runBenchmarkCompare(cmd *cobra.Command, args []string) error {
 	return encoder.Encode(comparison)
 }
 
+func runBenchmarkHistory(cmd *cobra.Command, args []string) error {
+	params := url.Values{}
+	if benchModel != "" {
+		params.Set("model", benchModel)
+	}
+	if benchGPU != "" {
+		params.Set("gpu", benchGPU)
+	}
+	if benchProvider != "" {
+		params.Set("provider", benchProvider)
+	}
+	if benchSince != "" {
+		params.Set("since", benchSince)
+	}
+	if benchUntil != "" {
+		params.Set("until", benchUntil)
+	}
+	params.Set("limit", fmt.Sprintf("%d", benchLimit))
+
+	reqURL := fmt.Sprintf("%s/api/v1/benchmarks/history?%s", serverURL, params.Encode())
+
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error: %s", string(body))
+	}
+
+	var result BenchmarkHistoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	printBenchmarkHistory(result.History)
+	return nil
+}
+
+func runBenchmarkReport(cmd *cobra.Command, args []string) error {
+	params := url.Values{}
+	params.Set("format", benchReportFormat)
+	if benchModel != "" {
+		params.Set("model", benchModel)
+	}
+	if benchGPU != "" {
+		params.Set("gpu", benchGPU)
+	}
+	if benchProvider != "" {
+		params.Set("provider", benchProvider)
+	}
+	if benchSince != "" {
+		params.Set("since", benchSince)
+	}
+	if benchUntil != "" {
+		params.Set("until", benchUntil)
+	}
+
+	reqURL := fmt.Sprintf("%s/api/v1/benchmarks/report?%s", serverURL, params.Encode())
+
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server error: %s", string(body))
+	}
+
+	if benchReportFile == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	if err := os.WriteFile(benchReportFile, body, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Fprintf(progressOut(), "Report written to %s\n", benchReportFile)
+	return nil
+}
+
+func runBenchmarkRun(cmd *cobra.Command, args []string) error {
+	if benchRunParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	if len(benchRunModels) == 0 && benchRunEndpoint == "" && benchRunSession == "" {
+		return fmt.Errorf("--model is required unless --endpoint or --session is set")
+	}
+	if len(benchRunSweep) > 0 && benchRunEndpoint == "" && benchRunSession == "" {
+		return fmt.Errorf("--concurrency requires --endpoint or --session")
+	}
+	workload := strings.ToLower(benchRunWorkload)
+	if workload != "" && workload != "chat" && benchRunEndpoint == "" && benchRunSession == "" {
+		return fmt.Errorf("--workload %s requires --endpoint or --session", workload)
+	}
+
+	reqBody := map[string]interface{}{
+		"models":   benchRunModels,
+		"parallel": benchRunParallel,
+	}
+	if benchRunEndpoint != "" {
+		reqBody["endpoint"] = benchRunEndpoint
+	}
+	if benchRunSession != "" {
+		reqBody["session_id"] = benchRunSession
+	}
+	if len(benchRunSweep) > 0 {
+		reqBody["concurrency_levels"] = benchRunSweep
+	}
+	if workload != "" {
+		reqBody["workload"] = workload
+	}
+	if len(benchRunQuants) > 0 {
+		quants := make([]string, len(benchRunQuants))
+		for i, q := range benchRunQuants {
+			quants[i] = strings.ToLower(q)
+		}
+		reqBody["quantizations"] = quants
+	}
+	if len(benchRunBackends) > 0 {
+		backends := make([]string, len(benchRunBackends))
+		for i, b := range benchRunBackends {
+			backends[i] = strings.ToLower(b)
+		}
+		reqBody["backends"] = backends
+	}
+	if len(benchRunGPUs) > 0 {
+		reqBody["gpu_types"] = benchRunGPUs
+	}
+	if len(benchRunProviders) > 0 {
+		reqBody["providers"] = benchRunProviders
+	}
+	if benchRunBudget > 0 {
+		reqBody["max_budget"] = benchRunBudget
+	}
+	if benchRunLocation != "" {
+		reqBody["location"] = benchRunLocation
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.Post(serverURL+"/api/v1/benchmark-runs", "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error: %s", string(body))
+	}
+
+	var result BenchmarkRunResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil || !benchRunFollow {
+			return err
+		}
+		return followBenchmarkRun(result.Run.ID)
+	}
+
+	run := result.Run
+	fmt.Println("Benchmark run started!")
+	fmt.Println()
+	fmt.Printf("Run ID:        %s\n", run.ID)
+	fmt.Printf("Combinations:  %d\n", run.TotalEntries)
+	fmt.Printf("Parallel:      %d\n", run.Request.Parallel)
+	if run.Request.MaxBudget > 0 {
+		fmt.Printf("Max Budget:    $%.2f\n", run.Request.MaxBudget)
+	}
+	fmt.Println()
+	if benchRunFollow {
+		return followBenchmarkRun(run.ID)
+	}
+	fmt.Printf("Check progress: curl %s/api/v1/benchmark-runs/%s\n", serverURL, run.ID)
+	return nil
+}
+
+// followBenchmarkRun prints a run's progress from the server's event stream
+// until the run ends. It fails unless the run completed, so CI jobs can gate
+// on it.
+func followBenchmarkRun(runID string) error {
+	resp, err := http.Get(serverURL + "/api/v1/benchmark-runs/" + url.PathEscape(runID) + "/events")
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error: %s", string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 8*1024*1024) // Progress events carry every entry
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimPrefix(line, "data:")
+		case line == "" && event != "":
+			switch event {
+			case "progress":
+				var progress BenchmarkRunResponse
+				if err := json.Unmarshal([]byte(data), &progress); err != nil {
+					return fmt.Errorf("failed to parse progress: %w", err)
+				}
+				r := progress.Run
+				fmt.Printf("%s  %-9s %d/%d done, %d failed, %d running, $%.2f\n",
+					time.Now().Format("15:04:05"), r.Status,
+					r.Completed+r.Failed+r.Skipped, r.TotalEntries, r.Failed, r.Running, r.TotalCost)
+			case "done":
+				var done struct {
+					Status string `json:"status"`
+				}
+				if err := json.Unmarshal([]byte(data), &done); err != nil {
+					return fmt.Errorf("failed to parse result: %w", err)
+				}
+				if done.Status != "completed" {
+					return fmt.Errorf("benchmark run %s %s", runID, done.Status)
+				}
+				fmt.Printf("Benchmark run %s completed\n", runID)
+				return nil
+			}
+			event, data = "", ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read progress: %w", err)
+	}
+	return fmt.Errorf("progress stream for run %s ended before the run did", runID)
+}
+
+func printBenchmarkHistory(history []BenchmarkHistoryEntry) {
+	if len(history) == 0 {
+		fmt.Println("No benchmark runs found")
+		return
+	}
+
+	fmt.Printf("Found %d benchmark runs\n\n", len(history))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tMODEL\tGPU\tPROVIDER\tAVG TPS\tTREND\tAVG LAT\tP95 LAT\t$/HR\tRUN COST\t$/1M TOK")
+	fmt.Fprintln(w, "----\t-----\t---\t--------\t-------\t-----\t-------\t-------\t----\t--------\t--------")
+
+	for _, h := range history {
+		date := h.Timestamp
+		if t, err := time.Parse(time.RFC3339, h.Timestamp); err == nil {
+			date = t.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f\t%s\t%.0fms\t%.0fms\t$%.2f\t$%.4f\t$%.2f\n",
+			date,
+			h.Model,
+			h.GPUName,
+			h.Provider,
+			h.AvgTPS,
+			formatTrend(h.Trend, h.ChangePct),
+			h.AvgLatency,
+			h.P95Latency,
+			h.Price,
+			h.RunCost,
+			h.CostPerMillionTokens,
+		)
+	}
+	w.Flush()
+}
+
+// formatTrend renders a throughput trend as an arrow with the change
+func formatTrend(trend string, changePct float64) string {
+	switch trend {
+	case "up":
+		return fmt.Sprintf("↑ %+.1f%%", changePct)
+	case "down":
+		return fmt.Sprintf("↓ %+.1f%%", changePct)
+	case "flat":
+		return fmt.Sprintf("→ %+.1f%%", changePct)
+	default:
+		return "-"
+	}
+}
+
 func printBenchmarkList(benchmarks []*BenchmarkResult) {
 	if len(benchmarks) == 0 {
 		fmt.Println("No benchmarks found")
@@ -398,9 +883,15 @@ func printBenchmarkDetail(b *BenchmarkResult, cost *CostAnalysis) {
 	}
 	fmt.Printf("  Size:             %.1f GB\n", b.Model.SizeGB)
 	fmt.Printf("  Runtime:          %s\n", b.Model.Runtime)
+	if b.Workload != "" && b.Workload != "chat" {
+		fmt.Printf("  Workload:         %s\n", b.Workload)
+	}
 	fmt.Println()
 
 	fmt.Println("Performance")
+	if b.Results.Throughput > 0 {
+		fmt.Printf("  Throughput:       %.2f %s\n", b.Results.Throughput, b.Results.ThroughputUnit)
+	}
 	fmt.Printf("  Avg Tokens/sec:   %.2f\n", b.Results.AvgTPS)
 	fmt.Printf("  P50 Tokens/sec:   %.2f\n", b.Results.P50TPS)
 	fmt.Printf("  P95 Tokens/sec:   %.2f\n", b.Results.P95TPS)
@@ -419,6 +910,14 @@ func printBenchmarkDetail(b *BenchmarkResult, cost *CostAnalysis) {
 	fmt.Printf("  Peak VRAM:        %d MiB\n", b.GPUStats.MaxMemMiB)
 	fmt.Println()
 
+	if b.Results.TokensPerWatt > 0 {
+		fmt.Println("Energy")
+		fmt.Printf("  Total GPU Power:  %.1f W\n", b.GPUStats.TotalPower)
+		fmt.Printf("  Energy Used:      %.1f Wh\n", b.GPUStats.EnergyWh)
+		fmt.Printf("  Tokens/Watt:      %.3f\n", b.Results.TokensPerWatt)
+		fmt.Println()
+	}
+
 	if cost != nil {
 		fmt.Println("Cost Analysis")
 		fmt.Printf("  Price/Hour:       $%.3f\n", cost.CostPerHour)
@@ -442,18 +941,28 @@ func printRecommendations(model string, recs []Recommendation) {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "GPU\tVRAM\tEXP TPS\t$/HR\tNOTES")
-	fmt.Fprintln(w, "---\t----\t-------\t----\t-----")
+	fmt.Fprintln(w, "GPU\tVRAM\tEXPECTED\t$/HR\tNOTES")
+	fmt.Fprintln(w, "---\t----\t--------\t----\t-----")
 
 	for _, r := range recs {
 		gpus := ""
 		if len(r.RecommendedGPUs) > 0 {
 			gpus = r.RecommendedGPUs[0]
 		}
-		fmt.Fprintf(w, "%s\t%dGB\t%.1f\t$%.2f\t%s\n",
+		if r.Runtime != "" && r.Runtime != "ollama" {
+			gpus += " (" + r.Runtime + ")"
+		}
+		if r.Quantization != "" {
+			gpus += " + " + strings.ToUpper(r.Quantization)
+		}
+		expected := fmt.Sprintf("%.1f tok/s", r.ExpectedTPS)
+		if r.ThroughputUnit != "" {
+			expected = fmt.Sprintf("%.2f %s", r.ExpectedThroughput, r.ThroughputUnit)
+		}
+		fmt.Fprintf(w, "%s\t%dGB\t%s\t$%.2f\t%s\n",
 			gpus,
 			r.MinVRAMGiB,
-			r.ExpectedTPS,
+			expected,
 			r.EstimatedCost,
 			r.Notes,
 		)
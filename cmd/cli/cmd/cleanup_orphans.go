@@ -13,6 +13,7 @@ import (
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/config"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/bluelobster"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/middleware"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/tensordock"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/vastai"
 	"github.com/spf13/cobra"
@@ -232,12 +233,18 @@ func runCleanupOrphans(cmd *cobra.Command, args []string) error {
 // initializeProviders creates provider clients based on config and filter
 func initializeProviders(cfg *config.Config, providerFilter string) ([]provider.Provider, error) {
 	var providers []provider.Provider
+	providerRetry := middleware.WithRetry(middleware.RetryPolicy{
+		MaxAttempts: cfg.Providers.Retry.MaxAttempts,
+		BaseDelay:   cfg.Providers.Retry.BaseDelay,
+		MaxDelay:    cfg.Providers.Retry.MaxDelay,
+		Jitter:      cfg.Providers.Retry.Jitter,
+	})
 
 	// Vast.ai
 	if cfg.Providers.VastAI.APIKey != "" {
 		if providerFilter == "" || providerFilter == "vastai" {
 			client := vastai.NewClient(cfg.Providers.VastAI.APIKey)
-			providers = append(providers, client)
+			providers = append(providers, middleware.Wrap(client, providerRetry))
 		}
 	}
 
@@ -245,7 +252,7 @@ func initializeProviders(cfg *config.Config, providerFilter string) ([]provider.
 	if cfg.Providers.BlueLobster.APIKey != "" {
 		if providerFilter == "" || providerFilter == "bluelobster" {
 			client := bluelobster.NewClient(cfg.Providers.BlueLobster.APIKey)
-			providers = append(providers, client)
+			providers = append(providers, middleware.Wrap(client, providerRetry))
 		}
 	}
 
@@ -261,7 +268,7 @@ func initializeProviders(cfg *config.Config, providerFilter string) ([]provider.
 				cfg.Providers.TensorDock.APIToken,
 				opts...,
 			)
-			providers = append(providers, client)
+			providers = append(providers, middleware.Wrap(client, providerRetry))
 		}
 	}
 
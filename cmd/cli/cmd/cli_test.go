@@ -30,14 +30,19 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
 )
 
 // testMu protects global state during tests that cannot run in parallel.
@@ -49,6 +54,7 @@ var testMu sync.Mutex
 type globalStateSnapshot struct {
 	serverURL    string
 	outputFormat string
+	apiKey       string
 
 	// inventory flags
 	inventoryProvider    string
@@ -56,6 +62,11 @@ type globalStateSnapshot struct {
 	inventoryMaxPrice    float64
 	inventoryMinVRAM     int
 	inventoryMinGPUCount int
+	inventoryRegion      string
+	inventoryMinCUDA     float64
+	inventoryMinDriver   string
+	inventoryMinInetDown float64
+	inventoryMinDiskBW   float64
 
 	// provision flags
 	provisionConsumerID  string
@@ -63,14 +74,21 @@ type globalStateSnapshot struct {
 	provisionWorkload    string
 	provisionHours       int
 	provisionIdleTimeout int
+	provisionIdleGPUUtil int
 	provisionStorage     string
 	provisionSaveKey     string
 	provisionGPUType     string
+	provisionMinVRAM     int
+	provisionMaxPrice    float64
+	provisionRegion      string
 
 	// sessions flags
 	sessionsConsumerID string
 	sessionsStatus     string
+	sessionsHealth     string
+	sessionsAll        bool
 	extendHours        int
+	sessionsLogTail    int
 
 	// costs flags
 	costsConsumerID string
@@ -79,6 +97,10 @@ type globalStateSnapshot struct {
 	costsStartDate  string
 	costsEndDate    string
 
+	costsProvider     string
+	costsExportFormat string
+	costsExportFile   string
+
 	// shutdown flags
 	shutdownForce bool
 
@@ -91,6 +113,69 @@ type globalStateSnapshot struct {
 	transferKeyFile string
 	transferTimeout time.Duration
 
+	// admin flags
+	adminAPIKey      string
+	adminActor       string
+	adminReason      string
+	exportConsumerID string
+	exportSessionIDs []string
+	exportFile       string
+	importFile       string
+	importTakeOver   bool
+
+	auditTerminationsAll bool
+
+	// benchmark flags
+	benchModel    string
+	benchGPU      string
+	benchLimit    int
+	benchMinTPS   float64
+	benchProvider string
+	benchSince    string
+	benchUntil    string
+
+	benchReportFormat string
+	benchReportFile   string
+
+	benchRunModels    []string
+	benchRunGPUs      []string
+	benchRunProviders []string
+	benchRunBudget    float64
+	benchRunParallel  int
+	benchRunLocation  string
+	benchRunEndpoint  string
+	benchRunSession   string
+	benchRunSweep     []int
+	benchRunQuants    []string
+	benchRunBackends  []string
+	benchRunFollow    bool
+	benchRunWorkload  string
+
+	// smoke-test flags
+	smokeMaxCost      float64
+	smokeConsumerID   string
+	smokeGPUType      string
+	smokeProvider     string
+	smokeTimeout      time.Duration
+	smokePollInterval time.Duration
+
+	// shop flags
+	shopConsumerID string
+	shopGPUType    string
+	shopMaxPrice   float64
+	shopRegion     string
+	shopWorkload   string
+	shopHours      int
+	shopSSHDir     string
+	shopWait       time.Duration
+
+	// ssh flags
+	sshKeyFile       string
+	sshRegenerateKey bool
+	sshBinary        string
+	scpBinary        string
+	cpRecursive      bool
+
 	// environment variables that might be set
 	envGPUShopperURL string
 }
@@ -101,33 +186,99 @@ func saveGlobalState() globalStateSnapshot {
 	return globalStateSnapshot{
 		serverURL:            serverURL,
 		outputFormat:         outputFormat,
+		apiKey:               apiKey,
 		inventoryProvider:    inventoryProvider,
 		inventoryGPUType:     inventoryGPUType,
 		inventoryMaxPrice:    inventoryMaxPrice,
 		inventoryMinVRAM:     inventoryMinVRAM,
 		inventoryMinGPUCount: inventoryMinGPUCount,
+		inventoryRegion:      inventoryRegion,
+		inventoryMinCUDA:     inventoryMinCUDA,
+		inventoryMinDriver:   inventoryMinDriver,
+		inventoryMinInetDown: inventoryMinInetDown,
+		inventoryMinDiskBW:   inventoryMinDiskBW,
 		provisionConsumerID:  provisionConsumerID,
 		provisionOfferID:     provisionOfferID,
 		provisionWorkload:    provisionWorkload,
 		provisionHours:       provisionHours,
 		provisionIdleTimeout: provisionIdleTimeout,
+		provisionIdleGPUUtil: provisionIdleGPUUtil,
 		provisionStorage:     provisionStorage,
 		provisionSaveKey:     provisionSaveKey,
 		provisionGPUType:     provisionGPUType,
+		provisionMinVRAM:     provisionMinVRAM,
+		provisionMaxPrice:    provisionMaxPrice,
+		provisionRegion:      provisionRegion,
 		sessionsConsumerID:   sessionsConsumerID,
 		sessionsStatus:       sessionsStatus,
+		sessionsHealth:       sessionsHealth,
+		sessionsAll:          sessionsAll,
 		extendHours:          extendHours,
+		sessionsLogTail:      sessionsLogTail,
 		costsConsumerID:      costsConsumerID,
 		costsSessionID:       costsSessionID,
 		costsPeriod:          costsPeriod,
 		costsStartDate:       costsStartDate,
 		costsEndDate:         costsEndDate,
+		costsProvider:        costsProvider,
+		costsExportFormat:    costsExportFormat,
+		costsExportFile:      costsExportFile,
 		shutdownForce:        shutdownForce,
 		cleanupExecute:       cleanupExecute,
 		cleanupForce:         cleanupForce,
 		cleanupProvider:      cleanupProvider,
 		transferKeyFile:      transferKeyFile,
 		transferTimeout:      transferTimeout,
+		adminAPIKey:          adminAPIKey,
+		adminActor:           adminActor,
+		adminReason:          adminReason,
+		exportConsumerID:     exportConsumerID,
+		exportSessionIDs:     exportSessionIDs,
+		exportFile:           exportFile,
+		importFile:           importFile,
+		importTakeOver:       importTakeOver,
+		auditTerminationsAll: auditTerminationsAll,
+		benchModel:           benchModel,
+		benchGPU:             benchGPU,
+		benchLimit:           benchLimit,
+		benchMinTPS:          benchMinTPS,
+		benchProvider:        benchProvider,
+		benchSince:           benchSince,
+		benchUntil:           benchUntil,
+		benchReportFormat:    benchReportFormat,
+		benchReportFile:      benchReportFile,
+		benchRunModels:       benchRunModels,
+		benchRunGPUs:         benchRunGPUs,
+		benchRunProviders:    benchRunProviders,
+		benchRunBudget:       benchRunBudget,
+		benchRunParallel:     benchRunParallel,
+		benchRunLocation:     benchRunLocation,
+		benchRunEndpoint:     benchRunEndpoint,
+		benchRunSession:      benchRunSession,
+		benchRunSweep:        benchRunSweep,
+		benchRunQuants:       benchRunQuants,
+		benchRunBackends:     benchRunBackends,
+		benchRunFollow:       benchRunFollow,
+		benchRunWorkload:     benchRunWorkload,
+		smokeMaxCost:         smokeMaxCost,
+		smokeConsumerID:      smokeConsumerID,
+		smokeGPUType:         smokeGPUType,
+		smokeProvider:        smokeProvider,
+		smokeTimeout:         smokeTimeout,
+		smokePollInterval:    smokePollInterval,
+		shopConsumerID:       shopConsumerID,
+		shopGPUType:          shopGPUType,
+		shopMaxPrice:         shopMaxPrice,
+		shopRegion:           shopRegion,
+		shopWorkload:         shopWorkload,
+		shopHours:            shopHours,
+		shopSSHDir:           shopSSHDir,
+		shopWait:             shopWait,
+		sshKeyFile:           sshKeyFile,
+		sshRegenerateKey:     sshRegenerateKey,
+		sshBinary:            sshBinary,
+		scpBinary:            scpBinary,
+		cpRecursive:          cpRecursive,
 		envGPUShopperURL:     os.Getenv("GPU_SHOPPER_URL"),
 	}
 }
@@ -137,33 +288,99 @@ func saveGlobalState() globalStateSnapshot {
 func restoreGlobalState(saved globalStateSnapshot) {
 	serverURL = saved.serverURL
 	outputFormat = saved.outputFormat
+	apiKey = saved.apiKey
 	inventoryProvider = saved.inventoryProvider
 	inventoryGPUType = saved.inventoryGPUType
 	inventoryMaxPrice = saved.inventoryMaxPrice
 	inventoryMinVRAM = saved.inventoryMinVRAM
 	inventoryMinGPUCount = saved.inventoryMinGPUCount
+	inventoryRegion = saved.inventoryRegion
+	inventoryMinCUDA = saved.inventoryMinCUDA
+	inventoryMinDriver = saved.inventoryMinDriver
+	inventoryMinInetDown = saved.inventoryMinInetDown
+	inventoryMinDiskBW = saved.inventoryMinDiskBW
 	provisionConsumerID = saved.provisionConsumerID
 	provisionOfferID = saved.provisionOfferID
 	provisionWorkload = saved.provisionWorkload
 	provisionHours = saved.provisionHours
 	provisionIdleTimeout = saved.provisionIdleTimeout
+	provisionIdleGPUUtil = saved.provisionIdleGPUUtil
 	provisionStorage = saved.provisionStorage
 	provisionSaveKey = saved.provisionSaveKey
 	provisionGPUType = saved.provisionGPUType
+	provisionMinVRAM = saved.provisionMinVRAM
+	provisionMaxPrice = saved.provisionMaxPrice
+	provisionRegion = saved.provisionRegion
 	sessionsConsumerID = saved.sessionsConsumerID
 	sessionsStatus = saved.sessionsStatus
+	sessionsHealth = saved.sessionsHealth
+	sessionsAll = saved.sessionsAll
 	extendHours = saved.extendHours
+	sessionsLogTail = saved.sessionsLogTail
 	costsConsumerID = saved.costsConsumerID
 	costsSessionID = saved.costsSessionID
 	costsPeriod = saved.costsPeriod
 	costsStartDate = saved.costsStartDate
 	costsEndDate = saved.costsEndDate
+	costsProvider = saved.costsProvider
+	costsExportFormat = saved.costsExportFormat
+	costsExportFile = saved.costsExportFile
 	shutdownForce = saved.shutdownForce
 	cleanupExecute = saved.cleanupExecute
 	cleanupForce = saved.cleanupForce
 	cleanupProvider = saved.cleanupProvider
 	transferKeyFile = saved.transferKeyFile
 	transferTimeout = saved.transferTimeout
+	adminAPIKey = saved.adminAPIKey
+	adminActor = saved.adminActor
+	adminReason = saved.adminReason
+	exportConsumerID = saved.exportConsumerID
+	exportSessionIDs = saved.exportSessionIDs
+	exportFile = saved.exportFile
+	importFile = saved.importFile
+	importTakeOver = saved.importTakeOver
+	auditTerminationsAll = saved.auditTerminationsAll
+	benchModel = saved.benchModel
+	benchGPU = saved.benchGPU
+	benchLimit = saved.benchLimit
+	benchMinTPS = saved.benchMinTPS
+	benchProvider = saved.benchProvider
+	benchSince = saved.benchSince
+	benchUntil = saved.benchUntil
+	benchReportFormat = saved.benchReportFormat
+	benchReportFile = saved.benchReportFile
+	benchRunModels = saved.benchRunModels
+	benchRunGPUs = saved.benchRunGPUs
+	benchRunProviders = saved.benchRunProviders
+	benchRunBudget = saved.benchRunBudget
+	benchRunParallel = saved.benchRunParallel
+	benchRunLocation = saved.benchRunLocation
+	benchRunEndpoint = saved.benchRunEndpoint
+	benchRunSession = saved.benchRunSession
+	benchRunSweep = saved.benchRunSweep
+	benchRunQuants = saved.benchRunQuants
+	benchRunBackends = saved.benchRunBackends
+	benchRunFollow = saved.benchRunFollow
+	benchRunWorkload = saved.benchRunWorkload
+	smokeMaxCost = saved.smokeMaxCost
+	smokeConsumerID = saved.smokeConsumerID
+	smokeGPUType = saved.smokeGPUType
+	smokeProvider = saved.smokeProvider
+	smokeTimeout = saved.smokeTimeout
+	smokePollInterval = saved.smokePollInterval
+	shopConsumerID = saved.shopConsumerID
+	shopGPUType = saved.shopGPUType
+	shopMaxPrice = saved.shopMaxPrice
+	shopRegion = saved.shopRegion
+	shopWorkload = saved.shopWorkload
+	shopHours = saved.shopHours
+	shopSSHDir = saved.shopSSHDir
+	shopWait = saved.shopWait
+	sshKeyFile = saved.sshKeyFile
+	sshRegenerateKey = saved.sshRegenerateKey
+	sshBinary = saved.sshBinary
+	scpBinary = saved.scpBinary
+	cpRecursive = saved.cpRecursive
 
 	// Restore environment variable
 	if saved.envGPUShopperURL != "" {
@@ -178,33 +395,96 @@ func restoreGlobalState(saved globalStateSnapshot) {
 func resetGlobalStateToDefaults() {
 	serverURL = "http://localhost:8080"
 	outputFormat = "table"
+	apiKey = ""
 	inventoryProvider = ""
 	inventoryGPUType = ""
 	inventoryMaxPrice = 0
 	inventoryMinVRAM = 0
 	inventoryMinGPUCount = 0
+	inventoryRegion = ""
+	inventoryMinCUDA = 0
+	inventoryMinDriver = ""
+	inventoryMinInetDown = 0
+	inventoryMinDiskBW = 0
 	provisionConsumerID = ""
 	provisionOfferID = ""
 	provisionWorkload = "llm"
 	provisionHours = 2
 	provisionIdleTimeout = 0
+	provisionIdleGPUUtil = 0
 	provisionStorage = "destroy"
 	provisionSaveKey = ""
 	provisionGPUType = ""
 	sessionsConsumerID = ""
 	sessionsStatus = ""
+	sessionsHealth = ""
+	sessionsAll = false
 	extendHours = 1
+	sessionsLogTail = 200
 	costsConsumerID = ""
 	costsSessionID = ""
 	costsPeriod = ""
 	costsStartDate = ""
 	costsEndDate = ""
+	costsProvider = ""
+	costsExportFormat = "csv"
+	costsExportFile = ""
 	shutdownForce = false
 	cleanupExecute = false
 	cleanupForce = false
 	cleanupProvider = ""
 	transferKeyFile = ""
 	transferTimeout = 5 * time.Minute
+	adminAPIKey = ""
+	adminActor = ""
+	adminReason = ""
+	exportConsumerID = ""
+	exportSessionIDs = nil
+	exportFile = ""
+	importFile = ""
+	importTakeOver = false
+	auditTerminationsAll = false
+	benchModel = ""
+	benchGPU = ""
+	benchLimit = 20
+	benchMinTPS = 0
+	benchProvider = ""
+	benchSince = ""
+	benchUntil = ""
+	benchReportFormat = "markdown"
+	benchReportFile = ""
+	benchRunModels = nil
+	benchRunGPUs = nil
+	benchRunProviders = nil
+	benchRunBudget = 0
+	benchRunParallel = 1
+	benchRunLocation = ""
+	benchRunEndpoint = ""
+	benchRunSession = ""
+	benchRunSweep = nil
+	benchRunQuants = nil
+	benchRunBackends = nil
+	benchRunFollow = false
+	benchRunWorkload = ""
+	smokeMaxCost = 0.50
+	smokeConsumerID = "smoke-test"
+	smokeGPUType = ""
+	smokeProvider = ""
+	smokeTimeout = 20 * time.Minute
+	smokePollInterval = 10 * time.Millisecond
+	shopConsumerID = ""
+	shopGPUType = ""
+	shopMaxPrice = 0
+	shopRegion = ""
+	shopWorkload = "interactive"
+	shopHours = 2
+	shopSSHDir = ""
+	shopWait = 5 * time.Second
+	sshKeyFile = ""
+	sshRegenerateKey = false
+	sshBinary = "ssh"
+	scpBinary = "scp"
+	cpRecursive = false
 }
 
 // setupTestWithCleanup sets up a test with proper global state management.
@@ -357,6 +637,11 @@ func TestInventoryCommand_WithFilters(t *testing.T) {
 	inventoryMaxPrice = 1.50
 	inventoryMinVRAM = 40
 	inventoryMinGPUCount = 2
+	inventoryRegion = "EU"
+	inventoryMinCUDA = 12.1
+	inventoryMinDriver = "535.104"
+	inventoryMinInetDown = 500
+	inventoryMinDiskBW = 1000
 
 	output := captureOutput(func() {
 		err := runInventory(nil, nil)
@@ -381,6 +666,21 @@ func TestInventoryCommand_WithFilters(t *testing.T) {
 	if !strings.Contains(capturedQuery, "min_gpu_count=2") {
 		t.Errorf("expected min_gpu_count filter in query, got: %s", capturedQuery)
 	}
+	if !strings.Contains(capturedQuery, "region=EU") {
+		t.Errorf("expected region filter in query, got: %s", capturedQuery)
+	}
+	if !strings.Contains(capturedQuery, "min_cuda=12.1") {
+		t.Errorf("expected min_cuda filter in query, got: %s", capturedQuery)
+	}
+	if !strings.Contains(capturedQuery, "min_driver=535.104") {
+		t.Errorf("expected min_driver filter in query, got: %s", capturedQuery)
+	}
+	if !strings.Contains(capturedQuery, "min_inet_down=500") {
+		t.Errorf("expected min_inet_down filter in query, got: %s", capturedQuery)
+	}
+	if !strings.Contains(capturedQuery, "min_disk_bw=1000") {
+		t.Errorf("expected min_disk_bw filter in query, got: %s", capturedQuery)
+	}
 
 	if output == "" {
 		t.Error("expected non-empty output")
@@ -440,6 +740,32 @@ func TestInventoryCommand_JSON(t *testing.T) {
 }
 
 // TestProvisionCommand_WithOffer tests the provision command with a specific offer
+func TestInventoryCommand_SendsAPIKey(t *testing.T) {
+	setupTestWithCleanup(t)
+	apiKey = "dash-key"
+	var capturedAuth string
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+
+		response := map[string]interface{}{
+			"offers": []interface{}{mockOffer},
+			"count":  1,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	captureOutput(func() {
+		if err := runInventory(nil, nil); err != nil {
+			t.Errorf("runInventory returned error: %v", err)
+		}
+	})
+
+	if capturedAuth != "Bearer dash-key" {
+		t.Errorf("expected bearer API key, got: %q", capturedAuth)
+	}
+}
+
 func TestProvisionCommand_WithOffer(t *testing.T) {
 	setupTestWithCleanup(t)
 	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -459,6 +785,9 @@ func TestProvisionCommand_WithOffer(t *testing.T) {
 		if reqBody["consumer_id"] != "test-consumer" {
 			t.Errorf("expected consumer_id 'test-consumer', got: %v", reqBody["consumer_id"])
 		}
+		if reqBody["idle_threshold_minutes"] != float64(30) || reqBody["idle_gpu_util_pct"] != float64(10) {
+			t.Errorf("expected idle policy in request, got: %v", reqBody)
+		}
 
 		response := SessionResponse{
 			Session: Session{
@@ -483,6 +812,8 @@ func TestProvisionCommand_WithOffer(t *testing.T) {
 	// Set up provision flags
 	provisionConsumerID = "test-consumer"
 	provisionOfferID = "offer-456"
+	provisionIdleTimeout = 30
+	provisionIdleGPUUtil = 10
 
 	output := captureOutput(func() {
 		err := runProvision(nil, nil)
@@ -503,52 +834,52 @@ func TestProvisionCommand_WithOffer(t *testing.T) {
 	}
 }
 
-// TestProvisionCommand_WithGPU tests the provision command with GPU auto-selection
+// TestProvisionCommand_WithGPU tests that the provision command leaves offer
+// selection for a GPU spec to the server
 func TestProvisionCommand_WithGPU(t *testing.T) {
 	setupTestWithCleanup(t)
 	callCount := 0
 	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
 		callCount++
 
-		if r.URL.Path == "/api/v1/inventory" {
-			// Inventory request for auto-select
-			response := map[string]interface{}{
-				"offers": []interface{}{mockOffer},
-				"count":  1,
-			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
-			return
+		if r.URL.Path != "/api/v1/sessions" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
 
-		if r.URL.Path == "/api/v1/sessions" {
-			// Session creation request
-			response := SessionResponse{
-				Session: Session{
-					ID:           "sess-auto",
-					ConsumerID:   "test-consumer",
-					Provider:     "vastai",
-					GPUType:      "RTX4090",
-					GPUCount:     1,
-					Status:       "provisioning",
-					WorkloadType: "llm",
-					PricePerHour: 0.45,
-					CreatedAt:    "2024-01-30T10:00:00Z",
-					ExpiresAt:    "2024-01-30T12:00:00Z",
-				},
-			}
-			w.WriteHeader(http.StatusCreated)
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
-			return
+		var reqBody map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		if _, ok := reqBody["offer_id"]; ok {
+			t.Errorf("expected no offer_id, got: %v", reqBody["offer_id"])
+		}
+		spec, _ := reqBody["offer"].(map[string]interface{})
+		if spec["gpu_type"] != "RTX4090" || spec["max_price"] != 0.5 {
+			t.Errorf("unexpected offer spec: %v", reqBody["offer"])
 		}
 
-		t.Errorf("unexpected path: %s", r.URL.Path)
+		response := SessionResponse{
+			Session: Session{
+				ID:           "sess-auto",
+				ConsumerID:   "test-consumer",
+				Provider:     "vastai",
+				GPUType:      "RTX4090",
+				GPUCount:     1,
+				Status:       "provisioning",
+				WorkloadType: "llm",
+				PricePerHour: 0.45,
+				CreatedAt:    "2024-01-30T10:00:00Z",
+				ExpiresAt:    "2024-01-30T12:00:00Z",
+			},
+			SelectedOfferID: "offer-123",
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
 	})
 
 	// Set up provision flags with GPU type instead of offer ID
 	provisionConsumerID = "test-consumer"
 	provisionGPUType = "RTX4090"
+	provisionMaxPrice = 0.5
 
 	output := captureOutput(func() {
 		err := runProvision(nil, nil)
@@ -557,13 +888,12 @@ func TestProvisionCommand_WithGPU(t *testing.T) {
 		}
 	})
 
-	// Should have made 2 calls: inventory + sessions
-	if callCount != 2 {
-		t.Errorf("expected 2 API calls, got: %d", callCount)
+	if callCount != 1 {
+		t.Errorf("expected 1 API call, got: %d", callCount)
 	}
 
 	// Verify auto-selection message
-	if !strings.Contains(output, "Auto-selected offer") {
+	if !strings.Contains(output, "Auto-selected offer offer-123") {
 		t.Errorf("expected auto-selection message, got: %s", output)
 	}
 }
@@ -586,6 +916,21 @@ func TestProvisionCommand_InvalidWorkload(t *testing.T) {
 	}
 }
 
+// TestProvisionCommand_IdleGPUUtilWithoutTimeout tests that an idle
+// utilization threshold needs an idle timeout
+func TestProvisionCommand_IdleGPUUtilWithoutTimeout(t *testing.T) {
+	setupTestWithCleanup(t)
+
+	provisionConsumerID = "test-consumer"
+	provisionOfferID = "offer-456"
+	provisionIdleGPUUtil = 10
+
+	err := runProvision(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "--idle-timeout") {
+		t.Errorf("expected --idle-timeout error, got: %v", err)
+	}
+}
+
 // TestProvisionCommand_NoOfferOrGPU tests that provision fails without offer or GPU
 func TestProvisionCommand_NoOfferOrGPU(t *testing.T) {
 	setupTestWithCleanup(t)
@@ -656,6 +1001,8 @@ func TestSessionsListCommand_WithFilters(t *testing.T) {
 
 	sessionsConsumerID = "consumer-1"
 	sessionsStatus = "running"
+	sessionsHealth = "degraded"
+	sessionsAll = true
 
 	captureOutput(func() {
 		err := runSessionsList(nil, nil)
@@ -671,6 +1018,12 @@ func TestSessionsListCommand_WithFilters(t *testing.T) {
 	if !strings.Contains(capturedQuery, "status=running") {
 		t.Errorf("expected status filter in query, got: %s", capturedQuery)
 	}
+	if !strings.Contains(capturedQuery, "health=degraded") {
+		t.Errorf("expected health filter in query, got: %s", capturedQuery)
+	}
+	if !strings.Contains(capturedQuery, "include_terminal=true") {
+		t.Errorf("expected include_terminal in query, got: %s", capturedQuery)
+	}
 }
 
 // TestSessionsListCommand_Empty tests sessions list when no sessions exist
@@ -853,6 +1206,36 @@ func TestSessionsDeleteCommand(t *testing.T) {
 }
 
 // TestSessionsDeleteCommand_Error tests session delete when request fails
+func TestSessionsLogsCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sessions/sess-123/logs" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("tail"); got != "50" {
+			t.Errorf("expected tail=50, got: %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"session_id": "sess-123",
+			"tail":       50,
+			"logs":       "loading model\nCUDA out of memory\n",
+		})
+	})
+
+	sessionsLogTail = 50
+	output := captureOutput(func() {
+		if err := runSessionsLogs(nil, []string{"sess-123"}); err != nil {
+			t.Errorf("runSessionsLogs returned error: %v", err)
+		}
+	})
+
+	if output != "loading model\nCUDA out of memory\n" {
+		t.Errorf("expected raw logs in output, got: %q", output)
+	}
+}
+
 func TestSessionsDeleteCommand_Error(t *testing.T) {
 	setupTestWithCleanup(t)
 	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -876,7 +1259,7 @@ func TestSessionsExtendCommand(t *testing.T) {
 		if r.URL.Path != "/api/v1/sessions/sess-123/extend" {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
-		if r.Method != http.MethodPost {
+		if r.Method != http.MethodPatch {
 			t.Errorf("unexpected method: %s", r.Method)
 		}
 
@@ -890,6 +1273,7 @@ func TestSessionsExtendCommand(t *testing.T) {
 		response := map[string]interface{}{
 			"status":         "extended",
 			"new_expires_at": "2024-01-30T14:00:00Z",
+			"projected_cost": 2.5,
 		}
 		w.WriteHeader(http.StatusOK)
 		w.Header().Set("Content-Type", "application/json")
@@ -911,6 +1295,9 @@ func TestSessionsExtendCommand(t *testing.T) {
 	if !strings.Contains(output, "extended by 2 hours") {
 		t.Errorf("expected 'extended by 2 hours' message, got: %s", output)
 	}
+	if !strings.Contains(output, "Projected cost: $2.50") {
+		t.Errorf("expected projected cost in output, got: %s", output)
+	}
 	if !strings.Contains(output, "New expiration") {
 		t.Errorf("expected 'New expiration' in output, got: %s", output)
 	}
@@ -1214,3 +1601,1186 @@ func TestParseSessionPath(t *testing.T) {
 		})
 	}
 }
+
+// TestAdminExportCommand tests exporting sessions to a file
+func TestAdminExportCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	t.Setenv(exportPassphraseEnv, "drill passphrase 2026")
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/sessions/export" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer admin-secret" {
+			t.Errorf("unexpected authorization: %s", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Admin-Actor") != "ops-bob" {
+			t.Errorf("unexpected actor: %s", r.Header.Get("X-Admin-Actor"))
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["consumer_id"] != "consumer-1" || body["passphrase"] != "drill passphrase 2026" {
+			t.Errorf("unexpected body: %v", body)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"version": 1, "sessions": [{"session": {"id": "sess-123"}}]}`))
+	})
+
+	adminAPIKey = "admin-secret"
+	adminActor = "ops-bob"
+	exportConsumerID = "consumer-1"
+	exportFile = filepath.Join(t.TempDir(), "export.json")
+
+	output := captureOutput(func() {
+		if err := runAdminExport(nil, nil); err != nil {
+			t.Errorf("runAdminExport returned error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Exported 1 sessions") {
+		t.Errorf("expected export summary, got: %s", output)
+	}
+	info, err := os.Stat(exportFile)
+	if err != nil {
+		t.Fatalf("export file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+}
+
+// TestAdminImportCommand tests importing an export file
+func TestAdminImportCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	t.Setenv(exportPassphraseEnv, "drill passphrase 2026")
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/sessions/import" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var body struct {
+			Export   map[string]interface{} `json:"export"`
+			TakeOver bool                   `json:"take_over"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Export["version"] != float64(1) || !body.TakeOver {
+			t.Errorf("unexpected body: %+v", body)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"imported": ["sess-123"], "skipped": ["sess-456"], "deactivated": []}`))
+	})
+
+	adminAPIKey = "admin-secret"
+	adminActor = "ops-bob"
+	importTakeOver = true
+	importFile = filepath.Join(t.TempDir(), "export.json")
+	if err := os.WriteFile(importFile, []byte(`{"version": 1, "sessions": []}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureOutput(func() {
+		if err := runAdminImport(nil, nil); err != nil {
+			t.Errorf("runAdminImport returned error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Imported:    1") || !strings.Contains(output, "Skipped:     1") {
+		t.Errorf("expected import summary, got: %s", output)
+	}
+}
+
+// TestAdminAuditTerminationsCommand tests that only flagged instances are listed by default
+func TestAdminAuditTerminationsCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/admin/terminations" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer admin-secret" {
+			t.Errorf("expected admin key, got: %s", r.Header.Get("Authorization"))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"providers": [
+				{"provider": "tensordock", "instances": 2, "other_deployments": 0, "flagged": 1},
+				{"provider": "vastai", "error": "provider unavailable"}
+			],
+			"instances": [
+				{"provider": "tensordock", "instance_id": "td-zombie", "status": "running", "price_per_hour": 0.5,
+				 "session_id": "sess-old", "issues": ["no_session", "expired"]},
+				{"provider": "tensordock", "instance_id": "td-ok", "status": "running", "price_per_hour": 0.5,
+				 "session_id": "sess-ok", "session_status": "running", "issues": []}
+			],
+			"flagged": 1
+		}`))
+	})
+
+	adminAPIKey = "admin-secret"
+	adminActor = "ops-bob"
+
+	output := captureOutput(func() {
+		if err := runAdminAuditTerminations(nil, nil); err != nil {
+			t.Errorf("runAdminAuditTerminations returned error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "td-zombie") || !strings.Contains(output, "no_session,expired") {
+		t.Errorf("expected flagged instance, got: %s", output)
+	}
+	if strings.Contains(output, "td-ok") {
+		t.Errorf("expected instances without issues to be hidden, got: %s", output)
+	}
+	if !strings.Contains(output, "vastai: audit failed: provider unavailable") {
+		t.Errorf("expected provider error, got: %s", output)
+	}
+	if !strings.Contains(output, "Flagged: 1") {
+		t.Errorf("expected flagged count, got: %s", output)
+	}
+}
+
+// TestAdminExportCommand_RequiresPassphrase tests that export refuses to run without a passphrase
+func TestAdminExportCommand_RequiresPassphrase(t *testing.T) {
+	setupTestWithCleanup(t)
+	t.Setenv(exportPassphraseEnv, "")
+
+	adminAPIKey = "admin-secret"
+	adminActor = "ops-bob"
+	exportConsumerID = "consumer-1"
+
+	err := runAdminExport(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), exportPassphraseEnv) {
+		t.Errorf("expected passphrase error, got: %v", err)
+	}
+}
+
+// TestSmokeTestCommand_NoOfferWithinCap tests that nothing is provisioned when no offer fits the cost cap
+func TestSmokeTestCommand_NoOfferWithinCap(t *testing.T) {
+	setupTestWithCleanup(t)
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/inventory" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		if got := r.URL.Query().Get("max_price"); got != "0.5000" {
+			t.Errorf("expected max_price 0.5000, got: %s", got)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"offers": []map[string]interface{}{
+				{"id": "offer-pricey", "provider": "vastai", "gpu_type": "A100", "price_per_hour": 1.20, "available": true},
+			},
+		})
+	})
+
+	var err error
+	captureOutput(func() {
+		err = runSmokeTest(nil, nil)
+	})
+	if err == nil {
+		t.Fatal("expected error when no offer fits the cost cap")
+	}
+	if !strings.Contains(err.Error(), "no available offer") {
+		t.Errorf("expected cost cap error, got: %v", err)
+	}
+}
+
+// TestSmokeTestCommand_DestroysOnFailure tests that the cheapest offer is used and the session is always destroyed
+func TestSmokeTestCommand_DestroysOnFailure(t *testing.T) {
+	setupTestWithCleanup(t)
+	destroyed := false
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/inventory":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"offers": []map[string]interface{}{
+					{"id": "offer-a", "provider": "vastai", "gpu_type": "RTX4090", "price_per_hour": 0.40, "available": true},
+					{"id": "offer-b", "provider": "vastai", "gpu_type": "RTX3090", "price_per_hour": 0.20, "available": true},
+				},
+			})
+		case r.URL.Path == "/api/v1/sessions" && r.Method == http.MethodPost:
+			var reqBody map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&reqBody)
+			if reqBody["offer_id"] != "offer-b" {
+				t.Errorf("expected cheapest offer 'offer-b', got: %v", reqBody["offer_id"])
+			}
+			if reqBody["reservation_hours"] != float64(1) {
+				t.Errorf("expected 1 reservation hour, got: %v", reqBody["reservation_hours"])
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(SessionResponse{
+				Session:       Session{ID: "sess-smoke", Status: "provisioning", PricePerHour: 0.20},
+				SSHPrivateKey: "key",
+			})
+		case r.URL.Path == "/api/v1/sessions/sess-smoke" && r.Method == http.MethodGet:
+			status := "failed"
+			if destroyed {
+				status = "stopped"
+			}
+			json.NewEncoder(w).Encode(Session{ID: "sess-smoke", Status: status, Error: "instance never booted"})
+		case r.URL.Path == "/api/v1/sessions/sess-smoke" && r.Method == http.MethodDelete:
+			destroyed = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"message": "session destroyed", "session_id": "sess-smoke"})
+		case r.URL.Path == "/api/v1/costs":
+			json.NewEncoder(w).Encode(map[string]interface{}{"session_id": "sess-smoke", "total_cost": 0.20, "currency": "USD"})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	var err error
+	output := captureOutput(func() {
+		err = runSmokeTest(nil, nil)
+	})
+	if err == nil {
+		t.Fatal("expected error for failed session")
+	}
+	if !strings.Contains(err.Error(), "instance never booted") {
+		t.Errorf("expected session error, got: %v", err)
+	}
+	if !destroyed {
+		t.Error("expected session to be destroyed")
+	}
+	if !strings.Contains(output, "verified stopped") {
+		t.Errorf("expected verified destroy in report, got: %s", output)
+	}
+	if !strings.Contains(output, "Result:         FAIL") {
+		t.Errorf("expected FAIL result, got: %s", output)
+	}
+}
+
+// TestSmokeTestCommand_JSON tests the JSON report when SSH is unreachable
+func TestSmokeTestCommand_JSON(t *testing.T) {
+	setupTestWithCleanup(t)
+	outputFormat = "json"
+	destroyed := false
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/inventory":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"offers": []map[string]interface{}{
+					{"id": "offer-a", "provider": "vastai", "gpu_type": "RTX4090", "price_per_hour": 0.40, "available": true},
+				},
+			})
+		case r.URL.Path == "/api/v1/sessions" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(SessionResponse{Session: Session{ID: "sess-smoke", Status: "provisioning"}})
+		case r.URL.Path == "/api/v1/sessions/sess-smoke" && r.Method == http.MethodGet:
+			status := "running"
+			if destroyed {
+				status = "stopped"
+			}
+			// Port 1 on loopback refuses connections, so the SSH step fails fast
+			json.NewEncoder(w).Encode(Session{ID: "sess-smoke", Status: status, SSHHost: "127.0.0.1", SSHPort: 1, SSHUser: "root"})
+		case r.URL.Path == "/api/v1/sessions/sess-smoke" && r.Method == http.MethodDelete:
+			destroyed = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"message": "session destroyed"})
+		case r.URL.Path == "/api/v1/costs":
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_cost": 0.40})
+		}
+	})
+
+	var err error
+	output := captureOutput(func() {
+		err = runSmokeTest(nil, nil)
+	})
+	if err == nil {
+		t.Fatal("expected error when SSH is unreachable")
+	}
+
+	var report SmokeReport
+	if jsonErr := json.Unmarshal([]byte(output), &report); jsonErr != nil {
+		t.Fatalf("failed to parse JSON report: %v\n%s", jsonErr, output)
+	}
+	if report.Passed {
+		t.Error("expected report to fail")
+	}
+	if report.SessionID != "sess-smoke" {
+		t.Errorf("expected session_id 'sess-smoke', got: %s", report.SessionID)
+	}
+	var names []string
+	for _, s := range report.Steps {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "select offer,provision,wait running,ssh,destroy" {
+		t.Errorf("unexpected steps: %s", got)
+	}
+	if report.RecordedCost == nil || *report.RecordedCost != 0.40 {
+		t.Errorf("expected recorded cost 0.40, got: %v", report.RecordedCost)
+	}
+}
+
+// TestBenchmarkHistoryCommand tests the benchmarks history command
+func TestBenchmarkHistoryCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	var capturedPath, capturedQuery string
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		capturedPath = r.URL.Path
+		capturedQuery = r.URL.RawQuery
+
+		response := BenchmarkHistoryResponse{
+			History: []BenchmarkHistoryEntry{
+				{ID: "b2", Timestamp: "2026-03-02T12:00:00Z", Model: "llama3.1:8b", GPUName: "RTX 4090",
+					Provider: "vastai", AvgTPS: 90, Price: 0.40, RunCost: 0.07, Trend: "down", ChangePct: -10},
+				{ID: "b1", Timestamp: "2026-03-01T12:00:00Z", Model: "llama3.1:8b", GPUName: "RTX 4090",
+					Provider: "vastai", AvgTPS: 100, Price: 0.40, RunCost: 0.07},
+			},
+			Count: 2,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	benchModel = "llama3.1:8b"
+	benchProvider = "vastai"
+	benchSince = "2026-03-01"
+
+	output := captureOutput(func() {
+		if err := runBenchmarkHistory(nil, nil); err != nil {
+			t.Errorf("runBenchmarkHistory returned error: %v", err)
+		}
+	})
+
+	if capturedPath != "/api/v1/benchmarks/history" {
+		t.Errorf("unexpected path: %s", capturedPath)
+	}
+	for _, want := range []string{"model=llama3.1%3A8b", "provider=vastai", "since=2026-03-01", "limit=20"} {
+		if !strings.Contains(capturedQuery, want) {
+			t.Errorf("expected %s in query, got: %s", want, capturedQuery)
+		}
+	}
+	if !strings.Contains(output, "Found 2 benchmark runs") {
+		t.Errorf("expected run count in output, got: %s", output)
+	}
+	if !strings.Contains(output, "↓ -10.0%") {
+		t.Errorf("expected downward trend in output, got: %s", output)
+	}
+}
+
+// TestBenchmarkReportCommand tests writing a benchmark report to a file
+func TestBenchmarkReportCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	var capturedQuery string
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/benchmarks/report" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		capturedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<!DOCTYPE html><html></html>"))
+	})
+
+	benchReportFormat = "html"
+	benchGPU = "4090"
+	benchReportFile = filepath.Join(t.TempDir(), "report.html")
+
+	output := captureOutput(func() {
+		if err := runBenchmarkReport(nil, nil); err != nil {
+			t.Errorf("runBenchmarkReport returned error: %v", err)
+		}
+	})
+
+	if !strings.Contains(capturedQuery, "format=html") || !strings.Contains(capturedQuery, "gpu=4090") {
+		t.Errorf("expected format and gpu in query, got: %s", capturedQuery)
+	}
+	data, err := os.ReadFile(benchReportFile)
+	if err != nil {
+		t.Fatalf("report file not written: %v", err)
+	}
+	if string(data) != "<!DOCTYPE html><html></html>" {
+		t.Errorf("unexpected report content: %s", data)
+	}
+	if !strings.Contains(output, "Report written to") {
+		t.Errorf("expected confirmation in output, got: %s", output)
+	}
+}
+
+// TestBenchmarkRunCommand tests starting a parallel benchmark run
+func TestBenchmarkRunCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	var captured map[string]interface{}
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/benchmark-runs" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&captured)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"run": map[string]interface{}{
+				"id":            "run-abc123",
+				"status":        "pending",
+				"total_entries": 4,
+				"request":       map[string]interface{}{"parallel": 3, "max_budget": 10.0},
+			},
+		})
+	})
+
+	benchRunModels = []string{"llama3.1:8b", "qwen2.5:14b"}
+	benchRunGPUs = []string{"RTX 4090", "RTX 3090"}
+	benchRunParallel = 3
+	benchRunBudget = 10
+
+	output := captureOutput(func() {
+		if err := runBenchmarkRun(nil, nil); err != nil {
+			t.Errorf("runBenchmarkRun returned error: %v", err)
+		}
+	})
+
+	if captured["parallel"] != float64(3) || captured["max_budget"] != float64(10) {
+		t.Errorf("expected parallel and max_budget in request, got: %v", captured)
+	}
+	if models, _ := captured["models"].([]interface{}); len(models) != 2 {
+		t.Errorf("expected two models in request, got: %v", captured["models"])
+	}
+	if _, ok := captured["providers"]; ok {
+		t.Errorf("expected providers to be omitted, got: %v", captured["providers"])
+	}
+	if !strings.Contains(output, "run-abc123") || !strings.Contains(output, "Parallel:      3") {
+		t.Errorf("expected run ID and parallelism in output, got: %s", output)
+	}
+
+	benchRunParallel = 0
+	if err := runBenchmarkRun(nil, nil); err == nil {
+		t.Error("expected error for --parallel 0")
+	}
+
+	// An existing endpoint needs no model
+	benchRunParallel = 1
+	benchRunModels = nil
+	benchRunEndpoint = "http://10.0.0.5:8000"
+	benchRunSweep = []int{1, 4, 16}
+	captureOutput(func() {
+		if err := runBenchmarkRun(nil, nil); err != nil {
+			t.Errorf("runBenchmarkRun returned error: %v", err)
+		}
+	})
+	if captured["endpoint"] != "http://10.0.0.5:8000" {
+		t.Errorf("expected endpoint in request, got: %v", captured)
+	}
+	if levels, _ := captured["concurrency_levels"].([]interface{}); len(levels) != 3 {
+		t.Errorf("expected three concurrency levels in request, got: %v", captured["concurrency_levels"])
+	}
+
+	benchRunWorkload = "Image"
+	captureOutput(func() {
+		if err := runBenchmarkRun(nil, nil); err != nil {
+			t.Errorf("runBenchmarkRun returned error: %v", err)
+		}
+	})
+	if captured["workload"] != "image" {
+		t.Errorf("expected lowercased workload in request, got: %v", captured["workload"])
+	}
+
+	benchRunEndpoint = ""
+	benchRunModels = []string{"llama3.1:8b"}
+	benchRunWorkload = ""
+	if err := runBenchmarkRun(nil, nil); err == nil {
+		t.Error("expected error for --concurrency without --endpoint or --session")
+	}
+
+	benchRunSweep = nil
+	benchRunWorkload = "image"
+	if err := runBenchmarkRun(nil, nil); err == nil {
+		t.Error("expected error for --workload image without --endpoint or --session")
+	}
+	benchRunWorkload = ""
+
+	benchRunSweep = nil
+	benchRunQuants = []string{"FP16", "awq"}
+	captureOutput(func() {
+		if err := runBenchmarkRun(nil, nil); err != nil {
+			t.Errorf("runBenchmarkRun returned error: %v", err)
+		}
+	})
+	if quants, _ := captured["quantizations"].([]interface{}); len(quants) != 2 || quants[0] != "fp16" {
+		t.Errorf("expected lowercased quantizations in request, got: %v", captured["quantizations"])
+	}
+
+	benchRunQuants = nil
+	benchRunBackends = []string{"vLLM", "SGLang"}
+	captureOutput(func() {
+		if err := runBenchmarkRun(nil, nil); err != nil {
+			t.Errorf("runBenchmarkRun returned error: %v", err)
+		}
+	})
+	if backends, _ := captured["backends"].([]interface{}); len(backends) != 2 || backends[1] != "sglang" {
+		t.Errorf("expected lowercased backends in request, got: %v", captured["backends"])
+	}
+
+	benchRunBackends = nil
+	benchRunModels = nil
+	if err := runBenchmarkRun(nil, nil); err == nil {
+		t.Error("expected error without --model, --endpoint or --session")
+	}
+}
+
+func TestBenchmarkRunFollow(t *testing.T) {
+	setupTestWithCleanup(t)
+	finalStatus := "completed"
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/benchmark-runs":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"run": map[string]interface{}{"id": "run-abc123", "status": "pending", "total_entries": 2},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/benchmark-runs/run-abc123/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event:progress\ndata:{\"run\":{\"id\":\"run-abc123\",\"status\":\"running\",\"total_entries\":2,\"completed\":1,\"failed\":0,\"running\":1,\"total_cost\":0.42},\"entries\":[]}\n\n")
+			fmt.Fprint(w, ": keepalive\n\n")
+			fmt.Fprintf(w, "event:done\ndata:{\"run_id\":\"run-abc123\",\"status\":%q}\n\n", finalStatus)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	benchRunModels = []string{"llama3.1:8b"}
+	benchRunFollow = true
+
+	output := captureOutput(func() {
+		if err := runBenchmarkRun(nil, nil); err != nil {
+			t.Errorf("runBenchmarkRun returned error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "1/2 done") || !strings.Contains(output, "$0.42") {
+		t.Errorf("expected progress line in output, got: %s", output)
+	}
+	if !strings.Contains(output, "completed") {
+		t.Errorf("expected final status in output, got: %s", output)
+	}
+
+	finalStatus = "failed"
+	var err error
+	captureOutput(func() {
+		err = runBenchmarkRun(nil, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("expected error for failed run, got: %v", err)
+	}
+}
+
+// TestCostsExportCommand tests exporting costs as CSV to stdout
+func TestCostsExportCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	var capturedQuery string
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/costs/export" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		capturedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Write([]byte("hour,session_id,consumer_id,provider,gpu_type,category,amount,currency\n"))
+	})
+
+	costsConsumerID = "consumer-1"
+	costsStartDate = "2026-01-01"
+
+	output := captureOutput(func() {
+		if err := runCostsExport(nil, nil); err != nil {
+			t.Errorf("runCostsExport returned error: %v", err)
+		}
+	})
+
+	for _, want := range []string{"format=csv", "consumer_id=consumer-1", "start_date=2026-01-01"} {
+		if !strings.Contains(capturedQuery, want) {
+			t.Errorf("expected %s in query, got: %s", want, capturedQuery)
+		}
+	}
+	if !strings.HasPrefix(output, "hour,session_id") {
+		t.Errorf("expected CSV header in output, got: %s", output)
+	}
+}
+
+// topKey returns the key message for a single keypress
+func topKey(key string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func TestTopModel(t *testing.T) {
+	setupTestWithCleanup(t)
+
+	var destroyed, extended []string
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/sessions":
+			if got := r.URL.Query().Get("consumer_id"); got != "team-a" {
+				t.Errorf("expected consumer_id team-a, got %q", got)
+			}
+			var sessions []Session
+			switch r.URL.Query().Get("status") {
+			case "running":
+				util := 87.0
+				sessions = []Session{{
+					ID: "sess-old", ConsumerID: "team-a", Provider: "vastai", GPUType: "RTX 4090", GPUCount: 1,
+					Status: "running", PricePerHour: 0.50, CreatedAt: "2026-03-18T18:00:00Z", ExpiresAt: "2026-03-18T22:00:00Z",
+					Health: &SessionHealth{Status: "healthy", GPUUtilization: &util},
+				}}
+			case "provisioning":
+				sessions = []Session{{
+					ID: "sess-new", ConsumerID: "team-a", Provider: "tensordock", GPUType: "A100", GPUCount: 2,
+					Status: "provisioning", PricePerHour: 3.00, CreatedAt: "2026-03-18T20:00:00Z", ExpiresAt: "2026-03-18T21:00:00Z",
+				}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sessions": sessions, "count": len(sessions)})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/costs/forecast":
+			fmt.Fprint(w, `{"burn_rate_per_hour": 3.5, "spent_today": 7.25, "sessions": [{"session_id": "sess-old", "accrued_cost": 1.5}]}`)
+		case r.Method == http.MethodDelete:
+			destroyed = append(destroyed, strings.TrimPrefix(r.URL.Path, "/api/v1/sessions/"))
+			fmt.Fprint(w, `{"status": "destroyed"}`)
+		case r.Method == http.MethodPatch:
+			extended = append(extended, r.URL.Path)
+			fmt.Fprint(w, `{"new_expires_at": "2026-03-18T23:00:00Z"}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	m := newTopModel(serverURL, "team-a", time.Second)
+	m.now = func() time.Time { return time.Date(2026, 3, 18, 20, 30, 0, 0, time.UTC) }
+	m.Update(m.fetch()())
+
+	view := m.View()
+	for _, want := range []string{"2 active", "burn $3.50/h", "spent today $7.25", "87%", "$1.50", "1h30m0s", "2x A100"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view, got:\n%s", want, view)
+		}
+	}
+	if !strings.Contains(view, "> sess-new") {
+		t.Errorf("expected the newest session selected first, got:\n%s", view)
+	}
+
+	// Any key but y cancels a destroy
+	m.Update(topKey("j"))
+	m.Update(topKey("d"))
+	if !strings.Contains(m.View(), "Destroy sess-old? (y/N)") {
+		t.Errorf("expected destroy confirmation, got:\n%s", m.View())
+	}
+	if _, cmd := m.Update(topKey("n")); cmd != nil {
+		t.Errorf("expected no command after cancelling")
+	}
+
+	m.Update(topKey("d"))
+	_, cmd := m.Update(topKey("y"))
+	if cmd == nil {
+		t.Fatal("expected a destroy command after confirming")
+	}
+	m.Update(cmd())
+	if len(destroyed) != 1 || destroyed[0] != "sess-old" {
+		t.Errorf("expected sess-old destroyed, got %v", destroyed)
+	}
+	if !strings.Contains(m.View(), "session sess-old destroyed") {
+		t.Errorf("expected destroy result in view, got:\n%s", m.View())
+	}
+
+	_, cmd = m.Update(topKey("e"))
+	if cmd == nil {
+		t.Fatal("expected an extend command")
+	}
+	m.Update(cmd())
+	if len(extended) != 1 || extended[0] != "/api/v1/sessions/sess-old/extend" {
+		t.Errorf("expected sess-old extended, got %v", extended)
+	}
+
+	// Selection follows the session across refreshes
+	m.Update(m.fetch()())
+	if !strings.Contains(m.View(), "> sess-old") {
+		t.Errorf("expected sess-old to stay selected, got:\n%s", m.View())
+	}
+}
+
+func TestOfferPicker(t *testing.T) {
+	offers := []GPUOffer{
+		{ID: "vastai-1", Provider: "vastai", GPUType: "RTX 4090", GPUCount: 1, Location: "US-CA", PricePerHour: 0.40},
+		{ID: "tensordock-very-long-offer-id", Provider: "tensordock", GPUType: "A100 80GB", GPUCount: 1, Location: "EU-DE", PricePerHour: 1.20},
+		{ID: "vastai-2", Provider: "vastai", GPUType: "A100 40GB", GPUCount: 2, Location: "US-TX", PricePerHour: 2.50},
+	}
+
+	p := newOfferPicker(offers, offerFilter{MaxPrice: 2})
+	if !strings.Contains(p.View(), "2 of 3 offers") {
+		t.Errorf("expected the initial price filter applied, got:\n%s", p.View())
+	}
+
+	// Narrow to A100s, then by region
+	p.Update(topKey("g"))
+	for _, r := range "a100" {
+		p.Update(topKey(string(r)))
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(p.visible) != 1 || offers[p.visible[0]].ID != "tensordock-very-long-offer-id" {
+		t.Errorf("expected only the cheap A100, got %v", p.visible)
+	}
+
+	p.Update(topKey("p"))
+	p.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(p.visible) != 2 {
+		t.Errorf("expected clearing the price to show both A100s, got %v", p.visible)
+	}
+
+	p.Update(topKey("r"))
+	for _, r := range "us" {
+		p.Update(topKey(string(r)))
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(p.visible) != 1 || offers[p.visible[0]].ID != "vastai-2" {
+		t.Errorf("expected only the US A100, got %v", p.visible)
+	}
+
+	p.Update(topKey("p"))
+	p.Update(topKey("x"))
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(p.View(), `invalid price "x"`) {
+		t.Errorf("expected an invalid price message, got:\n%s", p.View())
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("expected enter to quit the picker")
+	}
+	if p.chosen == nil || p.chosen.ID != "vastai-2" {
+		t.Errorf("expected vastai-2 chosen, got %+v", p.chosen)
+	}
+}
+
+func TestShopCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+
+	polls := 0
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/inventory":
+			if got := r.URL.Query().Get("gpu_type"); got != "A100" {
+				t.Errorf("expected gpu_type A100, got %q", got)
+			}
+			fmt.Fprint(w, `{"offers": [{"id": "tensordock-abc", "provider": "tensordock", "gpu_type": "A100", "gpu_count": 1, "location": "EU", "price_per_hour": 1.2}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/sessions":
+			var req map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req["offer_id"] != "tensordock-abc" || req["consumer_id"] != "my-app" {
+				t.Errorf("unexpected create request: %v", req)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"session": {"id": "sess-1", "status": "provisioning"}, "ssh_private_key": "PRIVATE KEY"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/sessions/sess-1":
+			polls++
+			if polls < 2 {
+				fmt.Fprint(w, `{"id": "sess-1", "status": "provisioning", "provision_phase": "booting"}`)
+				return
+			}
+			fmt.Fprint(w, `{"id": "sess-1", "status": "running", "provision_phase": "running", "ssh_host": "10.0.0.5", "ssh_port": 2222, "ssh_user": "root"}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	saved := pickOffer
+	t.Cleanup(func() { pickOffer = saved })
+	pickOffer = func(offers []GPUOffer, filter offerFilter) (*GPUOffer, error) {
+		if filter.GPU != "A100" {
+			t.Errorf("expected the --gpu filter passed to the picker, got %+v", filter)
+		}
+		return &offers[0], nil
+	}
+
+	sshDir := t.TempDir()
+	os.WriteFile(filepath.Join(sshDir, "config"), []byte("Host example\n  User me\n"), 0600)
+	shopConsumerID = "my-app"
+	shopGPUType = "A100"
+	shopSSHDir = sshDir
+
+	output := captureOutput(func() {
+		if err := runShop(nil, nil); err != nil {
+			t.Errorf("runShop returned error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "ssh gpu-sess-1") {
+		t.Errorf("expected connect instructions, got: %s", output)
+	}
+	if !strings.Contains(output, "  booting\n  running\n") {
+		t.Errorf("expected provisioning phases shown while waiting, got: %s", output)
+	}
+
+	keyPath := filepath.Join(sshDir, "gpu-shopper", "sess-1.key")
+	if key, err := os.ReadFile(keyPath); err != nil || string(key) != "PRIVATE KEY" {
+		t.Errorf("expected key saved to %s, got %q (%v)", keyPath, key, err)
+	}
+	if info, err := os.Stat(keyPath); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("expected key mode 0600, got %v", info.Mode().Perm())
+	}
+
+	hostConfig, _ := os.ReadFile(filepath.Join(sshDir, "gpu-shopper", "config"))
+	for _, want := range []string{"Host gpu-sess-1", "HostName 10.0.0.5", "Port 2222", "User root", `IdentityFile "` + keyPath + `"`} {
+		if !strings.Contains(string(hostConfig), want) {
+			t.Errorf("expected %q in host config, got:\n%s", want, hostConfig)
+		}
+	}
+
+	// The include goes first and is only added once
+	writeSSHHostEntry(sshDir, &Session{ID: "sess-2", SSHHost: "10.0.0.6", SSHPort: 22, SSHUser: "root"}, keyPath)
+	mainConfig, _ := os.ReadFile(filepath.Join(sshDir, "config"))
+	include := "Include " + filepath.Join(sshDir, "gpu-shopper", "config")
+	if !strings.HasPrefix(string(mainConfig), include) || strings.Count(string(mainConfig), include) != 1 {
+		t.Errorf("expected one leading include, got:\n%s", mainConfig)
+	}
+	if !strings.Contains(string(mainConfig), "Host example") {
+		t.Errorf("expected the existing config kept, got:\n%s", mainConfig)
+	}
+}
+
+// stubSSH points binary (sshBinary or scpBinary) at a script that records its
+// arguments and the contents of the identity file it was given
+func stubSSH(t *testing.T, binary *string) (argsFile, keyFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	keyFile = filepath.Join(dir, "key")
+	script := filepath.Join(dir, "ssh")
+	body := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\ncat \"$2\" > " + keyFile + "\n"
+	if err := os.WriteFile(script, []byte(body), 0700); err != nil {
+		t.Fatal(err)
+	}
+	*binary = script
+	return argsFile, keyFile
+}
+
+func TestSSHCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	status := "running"
+	var regenerated bool
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/sessions/sess-1":
+			json.NewEncoder(w).Encode(Session{ID: "sess-1", ConsumerID: "team-a", Status: status, SSHHost: "10.0.0.5", SSHPort: 2222, SSHUser: "root"})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/consumers/team-a/sessions/sess-1/ssh-key":
+			if r.Header.Get("Authorization") != "Bearer admin-key" || r.Header.Get("X-Admin-Actor") != "alice" {
+				t.Errorf("expected admin credentials, got %v", r.Header)
+			}
+			regenerated = true
+			fmt.Fprint(w, `{"ssh_private_key": "NEW KEY"}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	argsFile, keyFile := stubSSH(t, &sshBinary)
+
+	// Without a saved key, --key or --regenerate-key there is nothing to use
+	if err := runSSH(nil, []string{"sess-1"}); err == nil || !strings.Contains(err.Error(), "no saved key") {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+
+	// The key saved by shop is picked up
+	savedKey, err := saveSessionKey(filepath.Join(home, ".ssh"), "sess-1", "SAVED KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runSSH(nil, []string{"sess-1", "nvidia-smi"}); err != nil {
+		t.Fatalf("runSSH returned error: %v", err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	for _, want := range []string{"-i\n" + savedKey + "\n", "-p\n2222\n", "root@10.0.0.5\nnvidia-smi\n"} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("expected %q in ssh args, got:\n%s", want, args)
+		}
+	}
+	if regenerated {
+		t.Error("expected no key regeneration with a saved key")
+	}
+
+	// A regenerated key is written to a temporary file and removed afterwards
+	sshRegenerateKey = true
+	adminAPIKey = "admin-key"
+	adminActor = "alice"
+	if err := runSSH(nil, []string{"sess-1"}); err != nil {
+		t.Fatalf("runSSH returned error: %v", err)
+	}
+	if key, _ := os.ReadFile(keyFile); string(key) != "NEW KEY" {
+		t.Errorf("expected ssh to get the regenerated key, got %q", key)
+	}
+	args, _ = os.ReadFile(argsFile)
+	tempKey := strings.Split(string(args), "\n")[1]
+	if _, err := os.Stat(tempKey); !os.IsNotExist(err) {
+		t.Errorf("expected temporary key %s removed, got %v", tempKey, err)
+	}
+
+	status = "provisioning"
+	if err := runSSH(nil, []string{"sess-1"}); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("expected a not running error, got %v", err)
+	}
+}
+
+// setupRunningSession serves sess-1 as a running session and saves its key
+// under a temporary HOME, returning the key path
+func setupRunningSession(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sessions/sess-1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(Session{ID: "sess-1", Status: "running", SSHHost: "10.0.0.5", SSHPort: 2222, SSHUser: "root"})
+	})
+	keyPath, err := saveSessionKey(filepath.Join(home, ".ssh"), "sess-1", "KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPath
+}
+
+func TestPortForwardCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	setupRunningSession(t)
+	argsFile, _ := stubSSH(t, &sshBinary)
+
+	output := captureOutput(func() {
+		if err := runPortForward(nil, []string{"sess-1", "8000", "18888:8888"}); err != nil {
+			t.Errorf("runPortForward returned error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Forwarding localhost:18888 -> sess-1:8888") {
+		t.Errorf("expected forward listed, got: %s", output)
+	}
+
+	args, _ := os.ReadFile(argsFile)
+	for _, want := range []string{"-p\n2222\n-N\n", "-L\n8000:localhost:8000\n", "-L\n18888:localhost:8888\n", "ExitOnForwardFailure=yes", "root@10.0.0.5\n"} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("expected %q in ssh args, got:\n%s", want, args)
+		}
+	}
+
+	for _, spec := range []string{"0", "8000:", "abc", "8000:70000"} {
+		if err := runPortForward(nil, []string{"sess-1", spec}); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestCpCommand(t *testing.T) {
+	setupTestWithCleanup(t)
+	keyPath := setupRunningSession(t)
+	argsFile, keyFile := stubSSH(t, &scpBinary)
+
+	cpRecursive = true
+	if err := runCp(nil, []string{"./models/llama", "sess-1:/workspace/models/"}); err != nil {
+		t.Fatalf("runCp returned error: %v", err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if want := "-P\n2222\n-r\n./models/llama\nroot@10.0.0.5:/workspace/models/\n"; !strings.HasSuffix(string(args), want) {
+		t.Errorf("expected upload args ending %q, got:\n%s", want, args)
+	}
+	if key, _ := os.ReadFile(keyFile); string(key) != "KEY" {
+		t.Errorf("expected scp to get the saved key %s, got %q", keyPath, key)
+	}
+
+	cpRecursive = false
+	if err := runCp(nil, []string{"sess-1:/workspace/out.json", "./a:b/out.json"}); err != nil {
+		t.Fatalf("runCp returned error: %v", err)
+	}
+	args, _ = os.ReadFile(argsFile)
+	if want := "-P\n2222\nroot@10.0.0.5:/workspace/out.json\n./a:b/out.json\n"; !strings.HasSuffix(string(args), want) {
+		t.Errorf("expected download args ending %q, got:\n%s", want, args)
+	}
+
+	if err := runCp(nil, []string{"./a", "./b"}); err == nil {
+		t.Error("expected an error copying between local paths")
+	}
+	if err := runCp(nil, []string{"sess-1:/a", "sess-2:/b"}); err == nil {
+		t.Error("expected an error copying between sessions")
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	t.Run("rejects unknown format", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		outputFormat = "yaml"
+		if err := validateOutputFormat(); err == nil {
+			t.Error("expected error for --output yaml")
+		}
+		outputFormat = "json"
+		if err := validateOutputFormat(); err != nil {
+			t.Errorf("unexpected error for --output json: %v", err)
+		}
+	})
+
+	t.Run("sessions delete passes through the server response", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"status": "destroyed", "session_id": "sess-123"})
+		})
+		outputFormat = "json"
+
+		output := captureOutput(func() {
+			if err := runSessionsDelete(nil, []string{"sess-123"}); err != nil {
+				t.Errorf("runSessionsDelete returned error: %v", err)
+			}
+		})
+		var result map[string]string
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("expected JSON output, got %q: %v", output, err)
+		}
+		if result["status"] != "destroyed" {
+			t.Errorf("expected status destroyed, got %q", result["status"])
+		}
+	})
+
+	t.Run("sessions extend prints only JSON", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"new_expires_at": "2026-01-01T12:00:00Z", "projected_cost": 1.5})
+		})
+		outputFormat = "json"
+		extendHours = 1
+
+		output := captureOutput(func() {
+			if err := runSessionsExtend(nil, []string{"sess-123"}); err != nil {
+				t.Errorf("runSessionsExtend returned error: %v", err)
+			}
+		})
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("expected JSON output, got %q: %v", output, err)
+		}
+		if result["new_expires_at"] != "2026-01-01T12:00:00Z" {
+			t.Errorf("unexpected new_expires_at: %v", result["new_expires_at"])
+		}
+	})
+
+	t.Run("config show", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		serverURL = "http://shopper:8080"
+		outputFormat = "json"
+
+		output := captureOutput(func() {
+			if err := runConfigShow(nil, nil); err != nil {
+				t.Errorf("runConfigShow returned error: %v", err)
+			}
+		})
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("expected JSON output, got %q: %v", output, err)
+		}
+		if result["server_url"] != "http://shopper:8080" {
+			t.Errorf("unexpected server_url: %v", result["server_url"])
+		}
+	})
+
+	t.Run("top refuses JSON", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		outputFormat = "json"
+		if err := runTop(nil, nil); err == nil {
+			t.Error("expected error running top with --output json")
+		}
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Run("session IDs", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var sessions []interface{}
+			if r.URL.Query().Get("status") == "running" {
+				sessions = []interface{}{
+					map[string]interface{}{"id": "sess-abc", "status": "running", "gpu_type": "RTX4090"},
+					map[string]interface{}{"id": "sess-xyz", "status": "running", "gpu_type": "A100"},
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"sessions": sessions})
+		})
+
+		ids, directive := completeSessionIDs(nil, nil, "sess-a")
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("unexpected directive %v", directive)
+		}
+		if len(ids) != 1 || ids[0] != "sess-abc\trunning RTX4090" {
+			t.Errorf("expected sess-abc completion, got %v", ids)
+		}
+
+		if ids, _ := completeSessionIDs(nil, []string{"sess-abc"}, ""); len(ids) != 0 {
+			t.Errorf("expected no completions after the session ID, got %v", ids)
+		}
+	})
+
+	t.Run("shell scripts", func(t *testing.T) {
+		setupTestWithCleanup(t)
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"completion", "bash"})
+		err := rootCmd.Execute()
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		if err != nil {
+			t.Fatalf("completion bash returned error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "__start_gpu-shopper") {
+			t.Error("bash completion script does not complete gpu-shopper")
+		}
+
+		buf.Reset()
+		if err := rootCmd.GenZshCompletion(&buf); err != nil || !strings.Contains(buf.String(), "#compdef gpu-shopper") {
+			t.Errorf("zsh completion not generated: %v", err)
+		}
+		buf.Reset()
+		if err := rootCmd.GenFishCompletion(&buf, true); err != nil || !strings.Contains(buf.String(), "complete -c gpu-shopper") {
+			t.Errorf("fish completion not generated: %v", err)
+		}
+	})
+}
+
+func TestSecretsCommands(t *testing.T) {
+	setupTestWithCleanup(t)
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "secrets.json")
+	sealed := filepath.Join(dir, "secrets.enc")
+	if err := os.WriteFile(plain, []byte(`{"vastai_api_key": "vast-secret", "admin_api_key": "admin-secret"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SECRETS_PASSPHRASE", "")
+	if err := runSecretsEncrypt(nil, []string{plain, sealed}); err == nil {
+		t.Error("expected error without SECRETS_PASSPHRASE")
+	}
+
+	t.Setenv("SECRETS_PASSPHRASE", "passphrase")
+	captureOutput(func() {
+		if err := runSecretsEncrypt(nil, []string{plain, sealed}); err != nil {
+			t.Errorf("runSecretsEncrypt returned error: %v", err)
+		}
+	})
+	raw, err := os.ReadFile(sealed)
+	if err != nil {
+		t.Fatalf("secrets file not written: %v", err)
+	}
+	if strings.Contains(string(raw), "vast-secret") {
+		t.Error("secrets file contains a plaintext secret")
+	}
+	if info, _ := os.Stat(sealed); info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	output := captureOutput(func() {
+		if err := runSecretsList(nil, []string{sealed}); err != nil {
+			t.Errorf("runSecretsList returned error: %v", err)
+		}
+	})
+	if output != "admin_api_key\nvastai_api_key\n" {
+		t.Errorf("unexpected secret names: %q", output)
+	}
+
+	t.Setenv("SECRETS_PASSPHRASE", "wrong")
+	if err := runSecretsList(nil, []string{sealed}); err == nil {
+		t.Error("expected error with the wrong passphrase")
+	}
+}
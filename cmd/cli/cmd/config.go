@@ -35,6 +35,14 @@ func init() {
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
+	if outputFormat == "json" {
+		return printJSON(map[string]interface{}{
+			"server_url":    serverURL,
+			"output_format": outputFormat,
+			"api_key_set":   apiKey != "",
+		})
+	}
+
 	fmt.Println("GPU Shopper CLI Configuration")
 	fmt.Println("==============================")
 	fmt.Println()
@@ -58,6 +66,9 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 
 	switch key {
 	case "server":
+		if outputFormat == "json" {
+			return printJSON(map[string]string{"GPU_SHOPPER_URL": value})
+		}
 		fmt.Printf("To set the server URL, use the environment variable:\n")
 		fmt.Printf("  export GPU_SHOPPER_URL=%s\n", value)
 		fmt.Println()
@@ -18,6 +18,10 @@ var (
 	costsPeriod     string
 	costsStartDate  string
 	costsEndDate    string
+
+	costsProvider     string
+	costsExportFormat string
+	costsExportFile   string
 )
 
 var costsCmd = &cobra.Command{
@@ -33,9 +37,22 @@ var costsSummaryCmd = &cobra.Command{
 	RunE:  runCostsSummary,
 }
 
+var costsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export hourly cost records as CSV or JSON",
+	Long: `Export hourly cost records for spreadsheets and BI tools.
+
+Examples:
+  gpu-shopper costs export --file costs.csv
+  gpu-shopper costs export --consumer team-a --start 2026-01-01 --end 2026-01-31
+  gpu-shopper costs export --format json`,
+	RunE: runCostsExport,
+}
+
 func init() {
 	rootCmd.AddCommand(costsCmd)
 	costsCmd.AddCommand(costsSummaryCmd)
+	costsCmd.AddCommand(costsExportCmd)
 
 	costsCmd.Flags().StringVarP(&costsConsumerID, "consumer", "c", "", "Filter by consumer ID")
 	costsCmd.Flags().StringVarP(&costsSessionID, "session", "s", "", "Get cost for specific session")
@@ -44,6 +61,14 @@ func init() {
 	costsCmd.Flags().StringVar(&costsEndDate, "end", "", "End date (YYYY-MM-DD)")
 
 	costsSummaryCmd.Flags().StringVarP(&costsConsumerID, "consumer", "c", "", "Filter by consumer ID")
+
+	costsExportCmd.Flags().StringVarP(&costsConsumerID, "consumer", "c", "", "Filter by consumer ID")
+	costsExportCmd.Flags().StringVarP(&costsSessionID, "session", "s", "", "Filter by session ID")
+	costsExportCmd.Flags().StringVar(&costsProvider, "provider", "", "Filter by provider")
+	costsExportCmd.Flags().StringVar(&costsStartDate, "start", "", "Start date (YYYY-MM-DD)")
+	costsExportCmd.Flags().StringVar(&costsEndDate, "end", "", "End date, inclusive (YYYY-MM-DD)")
+	costsExportCmd.Flags().StringVarP(&costsExportFormat, "format", "f", "csv", "Export format (csv, json)")
+	costsExportCmd.Flags().StringVar(&costsExportFile, "file", "", "Write the export to this file instead of stdout")
 }
 
 func runCosts(cmd *cobra.Command, args []string) error {
@@ -132,6 +157,52 @@ func runCostsSummary(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runCostsExport(cmd *cobra.Command, args []string) error {
+	params := url.Values{}
+	params.Set("format", costsExportFormat)
+	if costsConsumerID != "" {
+		params.Set("consumer_id", costsConsumerID)
+	}
+	if costsSessionID != "" {
+		params.Set("session_id", costsSessionID)
+	}
+	if costsProvider != "" {
+		params.Set("provider", costsProvider)
+	}
+	if costsStartDate != "" {
+		params.Set("start_date", costsStartDate)
+	}
+	if costsEndDate != "" {
+		params.Set("end_date", costsEndDate)
+	}
+
+	reqURL := fmt.Sprintf("%s/api/v1/costs/export?%s", serverURL, params.Encode())
+
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server error: %s", string(body))
+	}
+
+	if costsExportFile == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	if err := os.WriteFile(costsExportFile, body, 0644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	fmt.Fprintf(progressOut(), "Costs exported to %s\n", costsExportFile)
+	return nil
+}
+
 func printCostSummary(summary CostSummary) {
 	fmt.Println("Cost Summary")
 	fmt.Println("============")
@@ -168,6 +239,15 @@ func printCostSummary(summary CostSummary) {
 		}
 		w.Flush()
 	}
+
+	if len(summary.ByCategory) > 0 {
+		fmt.Println("\nBy Line Item:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for category, cost := range summary.ByCategory {
+			fmt.Fprintf(w, "  %s\t$%.2f\n", category, cost)
+		}
+		w.Flush()
+	}
 }
 
 // CostSummary represents cost summary from the API
@@ -178,6 +258,7 @@ type CostSummary struct {
 	HoursUsed    float64            `json:"hours_used"`
 	ByProvider   map[string]float64 `json:"by_provider,omitempty"`
 	ByGPUType    map[string]float64 `json:"by_gpu_type,omitempty"`
+	ByCategory   map[string]float64 `json:"by_category,omitempty"`
 	PeriodStart  Time               `json:"period_start,omitempty"`
 	PeriodEnd    Time               `json:"period_end,omitempty"`
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var cpRecursive bool
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <source> <destination>",
+	Short: "Copy files to or from a session with scp",
+	Long: `Copy files between this machine and a session over scp. Exactly one of
+source and destination is a session path, written <session-id>:<path>.
+Use -r to copy directories such as model weights.
+
+The session's key is found as for 'gpu-shopper ssh'. For single files without
+an ssh client installed, see 'gpu-shopper transfer'.
+
+Examples:
+  gpu-shopper cp ./script.py sess-abc123:/workspace/
+  gpu-shopper cp -r ./models/llama sess-abc123:/workspace/models/
+  gpu-shopper cp sess-abc123:/workspace/results.json ./`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCp,
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+	addSessionKeyFlags(cpCmd)
+
+	cpCmd.Flags().BoolVarP(&cpRecursive, "recursive", "r", false, "Copy directories recursively")
+}
+
+// splitSessionPath splits "<session-id>:<path>". Arguments with a slash
+// before the first colon are local paths, so ./a:b is not mistaken for a
+// session.
+func splitSessionPath(arg string) (sessionID, path string, ok bool) {
+	i := strings.Index(arg, ":")
+	if i <= 0 || strings.Contains(arg[:i], "/") {
+		return "", "", false
+	}
+	sessionID, path, err := parseSessionPath(arg)
+	if err != nil {
+		return "", "", false
+	}
+	return sessionID, path, true
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	src, dst := args[0], args[1]
+	srcSession, srcPath, srcRemote := splitSessionPath(src)
+	dstSession, dstPath, dstRemote := splitSessionPath(dst)
+
+	var sessionID string
+	switch {
+	case srcRemote && dstRemote:
+		return fmt.Errorf("copying between sessions is not supported; copy through this machine")
+	case !srcRemote && !dstRemote:
+		return fmt.Errorf("one of source and destination must be a session path (<session-id>:<path>)")
+	case srcRemote:
+		sessionID = srcSession
+	default:
+		sessionID = dstSession
+	}
+
+	conn, err := openSSHConnection(sessionID)
+	if err != nil {
+		return err
+	}
+	defer conn.cleanup()
+
+	remote := func(path string) string { return conn.target() + ":" + path }
+	if srcRemote {
+		src = remote(srcPath)
+	} else {
+		dst = remote(dstPath)
+	}
+
+	scpArgs := append(conn.options(), "-P", strconv.Itoa(conn.session.SSHPort))
+	if cpRecursive {
+		scpArgs = append(scpArgs, "-r")
+	}
+	return runSSHClient(scpBinary, append(scpArgs, src, dst))
+}
@@ -18,6 +18,11 @@ var (
 	inventoryMaxPrice    float64
 	inventoryMinVRAM     int
 	inventoryMinGPUCount int
+	inventoryRegion      string
+	inventoryMinCUDA     float64
+	inventoryMinDriver   string
+	inventoryMinInetDown float64
+	inventoryMinDiskBW   float64
 )
 
 var inventoryCmd = &cobra.Command{
@@ -35,6 +40,11 @@ func init() {
 	inventoryCmd.Flags().Float64Var(&inventoryMaxPrice, "max-price", 0, "Maximum price per hour (USD)")
 	inventoryCmd.Flags().IntVar(&inventoryMinVRAM, "min-vram", 0, "Minimum VRAM in GB")
 	inventoryCmd.Flags().IntVar(&inventoryMinGPUCount, "min-gpus", 0, "Minimum GPU count")
+	inventoryCmd.Flags().Float64Var(&inventoryMinCUDA, "min-cuda", 0, "Minimum CUDA version (e.g., 12.1)")
+	inventoryCmd.Flags().StringVar(&inventoryMinDriver, "min-driver", "", "Minimum NVIDIA driver version (e.g., 535.104)")
+	inventoryCmd.Flags().Float64Var(&inventoryMinInetDown, "min-inet-down", 0, "Minimum download bandwidth in Mbps")
+	inventoryCmd.Flags().Float64Var(&inventoryMinDiskBW, "min-disk-bw", 0, "Minimum disk bandwidth in MB/s")
+	inventoryCmd.Flags().StringVar(&inventoryRegion, "region", "", "Only offers in these regions or countries, comma-separated (e.g., EU, DE,FR)")
 }
 
 func runInventory(cmd *cobra.Command, args []string) error {
@@ -55,6 +65,21 @@ func runInventory(cmd *cobra.Command, args []string) error {
 	if inventoryMinGPUCount > 0 {
 		params.Set("min_gpu_count", fmt.Sprintf("%d", inventoryMinGPUCount))
 	}
+	if inventoryRegion != "" {
+		params.Set("region", inventoryRegion)
+	}
+	if inventoryMinCUDA > 0 {
+		params.Set("min_cuda", fmt.Sprintf("%g", inventoryMinCUDA))
+	}
+	if inventoryMinDriver != "" {
+		params.Set("min_driver", inventoryMinDriver)
+	}
+	if inventoryMinInetDown > 0 {
+		params.Set("min_inet_down", fmt.Sprintf("%g", inventoryMinInetDown))
+	}
+	if inventoryMinDiskBW > 0 {
+		params.Set("min_disk_bw", fmt.Sprintf("%g", inventoryMinDiskBW))
+	}
 
 	// Make request
 	reqURL := fmt.Sprintf("%s/api/v1/inventory", serverURL)
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward <session-id> <local-port>[:<remote-port>]...",
+	Short: "Forward local ports to a session",
+	Long: `Forward local ports to ports on a session over SSH, e.g. to reach a vLLM
+endpoint that is not exposed publicly. A single port forwards to the same port
+on the session. Runs until interrupted with Ctrl+C.
+
+The session's key is found as for 'gpu-shopper ssh'.
+
+Examples:
+  gpu-shopper port-forward sess-abc123 8000
+  gpu-shopper port-forward sess-abc123 18000:8000 8888`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runPortForward,
+
+	ValidArgsFunction: completeSessionIDs,
+}
+
+func init() {
+	rootCmd.AddCommand(portForwardCmd)
+	addSessionKeyFlags(portForwardCmd)
+}
+
+// portForward is a local port forwarded to a port on the session
+type portForward struct {
+	Local  int
+	Remote int
+}
+
+// parsePortForward parses "local:remote", or "port" for the same port on both
+// ends
+func parsePortForward(spec string) (portForward, error) {
+	local, remote, found := strings.Cut(spec, ":")
+	if !found {
+		remote = local
+	}
+	var fwd portForward
+	var err error
+	if fwd.Local, err = parsePort(local); err != nil {
+		return fwd, fmt.Errorf("invalid port forward %q: %w", spec, err)
+	}
+	if fwd.Remote, err = parsePort(remote); err != nil {
+		return fwd, fmt.Errorf("invalid port forward %q: %w", spec, err)
+	}
+	return fwd, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port must be 1-65535, got %q", s)
+	}
+	return port, nil
+}
+
+func runPortForward(cmd *cobra.Command, args []string) error {
+	forwards := make([]portForward, 0, len(args)-1)
+	for _, spec := range args[1:] {
+		fwd, err := parsePortForward(spec)
+		if err != nil {
+			return err
+		}
+		forwards = append(forwards, fwd)
+	}
+
+	conn, err := openSSHConnection(args[0])
+	if err != nil {
+		return err
+	}
+	defer conn.cleanup()
+
+	// -N runs no remote command; ExitOnForwardFailure fails fast when a local
+	// port is already taken instead of leaving a forward-less connection up
+	sshArgs := append(conn.options(),
+		"-p", strconv.Itoa(conn.session.SSHPort),
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+	)
+	for _, fwd := range forwards {
+		sshArgs = append(sshArgs, "-L", fmt.Sprintf("%d:localhost:%d", fwd.Local, fwd.Remote))
+		fmt.Printf("Forwarding localhost:%d -> %s:%d\n", fwd.Local, conn.session.ID, fwd.Remote)
+	}
+	sshArgs = append(sshArgs, conn.target())
+	fmt.Println("Press Ctrl+C to stop.")
+
+	return runSSHClient(sshBinary, sshArgs)
+}
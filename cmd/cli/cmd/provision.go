@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -18,9 +17,13 @@ var (
 	provisionWorkload    string
 	provisionHours       int
 	provisionIdleTimeout int
+	provisionIdleGPUUtil int
 	provisionStorage     string
 	provisionSaveKey     string
 	provisionGPUType     string
+	provisionMinVRAM     int
+	provisionMaxPrice    float64
+	provisionRegion      string
 )
 
 var provisionCmd = &cobra.Command{
@@ -36,9 +39,13 @@ func init() {
 	provisionCmd.Flags().StringVarP(&provisionConsumerID, "consumer", "c", "", "Consumer ID (required)")
 	provisionCmd.Flags().StringVarP(&provisionOfferID, "offer", "i", "", "Offer ID to provision")
 	provisionCmd.Flags().StringVarP(&provisionGPUType, "gpu", "g", "", "GPU type to auto-select cheapest offer (e.g., RTX4090, A100)")
-	provisionCmd.Flags().StringVarP(&provisionWorkload, "workload", "w", "llm", "Workload type (llm, llm_vllm, llm_tgi, training, batch, interactive)")
+	provisionCmd.Flags().IntVar(&provisionMinVRAM, "min-vram", 0, "Minimum VRAM in GB when auto-selecting")
+	provisionCmd.Flags().Float64Var(&provisionMaxPrice, "max-price", 0, "Maximum price per hour when auto-selecting")
+	provisionCmd.Flags().StringVar(&provisionRegion, "region", "", "Region when auto-selecting")
+	provisionCmd.Flags().StringVarP(&provisionWorkload, "workload", "w", "llm", "Workload type (llm, llm_vllm, llm_tgi, llm_sglang, llm_llamacpp, training, batch, interactive)")
 	provisionCmd.Flags().IntVarP(&provisionHours, "hours", "t", 2, "Reservation hours (1-12)")
-	provisionCmd.Flags().IntVar(&provisionIdleTimeout, "idle-timeout", 0, "Idle timeout in minutes (0 = disabled)")
+	provisionCmd.Flags().IntVar(&provisionIdleTimeout, "idle-timeout", 0, "Destroy the session after this many minutes of GPU idleness (0 = disabled)")
+	provisionCmd.Flags().IntVar(&provisionIdleGPUUtil, "idle-gpu-util", 0, "GPU utilization percent below which the session counts as idle (default 5)")
 	provisionCmd.Flags().StringVar(&provisionStorage, "storage", "destroy", "Storage policy (destroy, preserve)")
 	provisionCmd.Flags().StringVar(&provisionSaveKey, "save-key", "", "Save SSH private key to file")
 
@@ -48,37 +55,52 @@ func init() {
 func runProvision(cmd *cobra.Command, args []string) error {
 	// Validate workload type
 	validWorkloads := map[string]bool{
-		"llm": true, "llm_vllm": true, "llm_tgi": true,
+		"llm": true, "llm_vllm": true, "llm_tgi": true, "llm_sglang": true, "llm_llamacpp": true,
 		"training": true, "batch": true, "interactive": true,
 	}
 	if !validWorkloads[provisionWorkload] {
-		return fmt.Errorf("invalid workload type %q, valid types: llm, llm_vllm, llm_tgi, training, batch, interactive", provisionWorkload)
+		return fmt.Errorf("invalid workload type %q, valid types: llm, llm_vllm, llm_tgi, llm_sglang, llm_llamacpp, training, batch, interactive", provisionWorkload)
 	}
 
-	// If --gpu provided but not --offer, auto-select cheapest matching offer
-	if provisionOfferID == "" && provisionGPUType != "" {
-		offer, err := selectCheapestOffer(provisionGPUType)
-		if err != nil {
-			return fmt.Errorf("failed to auto-select offer: %w", err)
-		}
-		provisionOfferID = offer.ID
-		fmt.Printf("Auto-selected offer %s (%s, $%.2f/hr)\n", offer.ID, offer.GPUType, offer.PricePerHour)
-	}
-
-	if provisionOfferID == "" {
+	autoSelect := provisionOfferID == "" && (provisionGPUType != "" || provisionMinVRAM > 0)
+	if provisionOfferID == "" && !autoSelect {
 		return fmt.Errorf("either --offer or --gpu must be provided")
 	}
 
 	reqBody := map[string]interface{}{
 		"consumer_id":       provisionConsumerID,
-		"offer_id":          provisionOfferID,
 		"workload_type":     provisionWorkload,
 		"reservation_hours": provisionHours,
 		"storage_policy":    provisionStorage,
 	}
 
+	// Without --offer the server picks the cheapest reliable offer matching the spec
+	if autoSelect {
+		spec := map[string]interface{}{}
+		if provisionGPUType != "" {
+			spec["gpu_type"] = provisionGPUType
+		}
+		if provisionMinVRAM > 0 {
+			spec["min_vram"] = provisionMinVRAM
+		}
+		if provisionMaxPrice > 0 {
+			spec["max_price"] = provisionMaxPrice
+		}
+		if provisionRegion != "" {
+			spec["region"] = provisionRegion
+		}
+		reqBody["offer"] = spec
+	} else {
+		reqBody["offer_id"] = provisionOfferID
+	}
+
 	if provisionIdleTimeout > 0 {
 		reqBody["idle_threshold_minutes"] = provisionIdleTimeout
+		if provisionIdleGPUUtil > 0 {
+			reqBody["idle_gpu_util_pct"] = provisionIdleGPUUtil
+		}
+	} else if provisionIdleGPUUtil > 0 {
+		return fmt.Errorf("--idle-gpu-util requires --idle-timeout")
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -103,6 +125,11 @@ func runProvision(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	if result.SelectedOfferID != "" && outputFormat != "json" {
+		fmt.Printf("Auto-selected offer %s (%s, $%.2f/hr)\n",
+			result.SelectedOfferID, result.Session.GPUType, result.Session.PricePerHour)
+	}
+
 	if outputFormat == "json" {
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -149,35 +176,3 @@ func runProvision(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
-
-// selectCheapestOffer queries the inventory API for the cheapest available offer matching the GPU type
-func selectCheapestOffer(gpuType string) (*GPUOffer, error) {
-	params := url.Values{}
-	params.Set("gpu_type", gpuType)
-
-	reqURL := fmt.Sprintf("%s/api/v1/inventory?%s", serverURL, params.Encode())
-	resp, err := http.Get(reqURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("inventory request failed: %s", string(body))
-	}
-
-	var result struct {
-		Offers []GPUOffer `json:"offers"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	if len(result.Offers) == 0 {
-		return nil, fmt.Errorf("no offers found for GPU type %s", gpuType)
-	}
-
-	// Offers are already sorted by price from API
-	return &result.Offers[0], nil
-}
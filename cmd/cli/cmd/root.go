@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -9,6 +15,7 @@ import (
 var (
 	serverURL    string
 	outputFormat string
+	apiKey       string
 )
 
 // rootCmd represents the base command
@@ -23,8 +30,14 @@ This CLI tool allows you to:
 - Provision GPU sessions
 - Monitor session status and costs
 - Manage session lifecycle`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateOutputFormat()
+	},
 }
 
+// outputFormats are the values accepted by --output
+var outputFormats = []string{"table", "json"}
+
 // Execute runs the root command
 func Execute() error {
 	return rootCmd.Execute()
@@ -33,6 +46,25 @@ func Execute() error {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&serverURL, "server", getEnvOrDefault("GPU_SHOPPER_URL", "http://localhost:8080"), "GPU Shopper server URL")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json)")
+	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", os.Getenv("GPU_SHOPPER_API_KEY"), "API key sent as a bearer token")
+	rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	http.DefaultClient.Transport = &apiKeyTransport{base: http.DefaultTransport}
+}
+
+// apiKeyTransport adds the --api-key bearer token to requests that do not
+// already carry an Authorization header (admin commands send their own).
+type apiKeyTransport struct {
+	base http.RoundTripper
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if apiKey == "" || req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	return t.base.RoundTrip(req)
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -41,3 +73,58 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func validateOutputFormat() error {
+	for _, f := range outputFormats {
+		if outputFormat == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --output %q: must be one of %s", outputFormat, strings.Join(outputFormats, ", "))
+}
+
+// progressOut is where commands write progress messages: stdout normally,
+// stderr under --output json so stdout holds only the JSON result
+func progressOut() io.Writer {
+	if outputFormat == "json" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// completeSessionIDs completes a session ID as the first argument of a
+// command, offering the sessions that have not finished yet
+func completeSessionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, status := range topActiveStatuses {
+		resp, err := http.Get(serverURL + "/api/v1/sessions?" + url.Values{"status": {status}}.Encode())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+		}
+		var result struct {
+			Sessions []Session `json:"sessions"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+		}
+		for _, s := range result.Sessions {
+			if strings.HasPrefix(s.ID, toComplete) {
+				ids = append(ids, s.ID+"\t"+s.Status+" "+s.GPUType)
+			}
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+// secretsPassphraseEnv holds the passphrase for encrypted secrets files, read
+// from the environment so it stays out of shell history
+const secretsPassphraseEnv = "SECRETS_PASSPHRASE"
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage the server's encrypted secrets file",
+	Long: `Manage the encrypted secrets file the server reads through SECRETS_FILE.
+
+Server credentials written as file:<key> are looked up in this file; see
+docs/CONFIGURATION.md. The passphrase is read from $SECRETS_PASSPHRASE.`,
+}
+
+var secretsEncryptCmd = &cobra.Command{
+	Use:   "encrypt <plaintext.json> <secrets-file>",
+	Short: "Encrypt a JSON object of secrets into a secrets file",
+	Long: `Encrypt a flat JSON object of secret names to values into a secrets file.
+Delete the plaintext afterwards.
+
+Example:
+  echo '{"vastai_api_key": "..."}' > secrets.json
+  SECRETS_PASSPHRASE=... gpu-shopper secrets encrypt secrets.json secrets.enc
+  shred -u secrets.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSecretsEncrypt,
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list <secrets-file>",
+	Short: "List the secret names in a secrets file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretsList,
+}
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.AddCommand(secretsEncryptCmd)
+	secretsCmd.AddCommand(secretsListCmd)
+}
+
+func runSecretsEncrypt(cmd *cobra.Command, args []string) error {
+	passphrase := os.Getenv(secretsPassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set", secretsPassphraseEnv)
+	}
+
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read secrets: %w", err)
+	}
+	var values map[string]string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return fmt.Errorf("secrets must be a JSON object of strings: %w", err)
+	}
+
+	sealed, err := secrets.Encrypt(values, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(args[1], sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+
+	if outputFormat == "json" {
+		return printJSON(map[string]interface{}{"file": args[1], "count": len(values)})
+	}
+	fmt.Printf("Encrypted %d secrets to %s\n", len(values), args[1])
+	return nil
+}
+
+func runSecretsList(cmd *cobra.Command, args []string) error {
+	passphrase := os.Getenv(secretsPassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set", secretsPassphraseEnv)
+	}
+
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	values, err := secrets.Decrypt(raw, passphrase)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if outputFormat == "json" {
+		return printJSON(names)
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
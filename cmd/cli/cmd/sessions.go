@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -16,6 +17,8 @@ import (
 var (
 	sessionsConsumerID string
 	sessionsStatus     string
+	sessionsHealth     string
+	sessionsAll        bool
 )
 
 var sessionsCmd = &cobra.Command{
@@ -35,6 +38,8 @@ var sessionsGetCmd = &cobra.Command{
 	Short: "Get session details",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runSessionsGet,
+
+	ValidArgsFunction: completeSessionIDs,
 }
 
 var sessionsDoneCmd = &cobra.Command{
@@ -42,6 +47,8 @@ var sessionsDoneCmd = &cobra.Command{
 	Short: "Signal session completion",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runSessionsDone,
+
+	ValidArgsFunction: completeSessionIDs,
 }
 
 var sessionsExtendCmd = &cobra.Command{
@@ -49,6 +56,8 @@ var sessionsExtendCmd = &cobra.Command{
 	Short: "Extend session reservation",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runSessionsExtend,
+
+	ValidArgsFunction: completeSessionIDs,
 }
 
 var sessionsDeleteCmd = &cobra.Command{
@@ -56,9 +65,26 @@ var sessionsDeleteCmd = &cobra.Command{
 	Short: "Force delete a session",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runSessionsDelete,
+
+	ValidArgsFunction: completeSessionIDs,
 }
 
-var extendHours int
+var sessionsLogsCmd = &cobra.Command{
+	Use:   "logs [session-id]",
+	Short: "Show a session's container logs",
+	Long: `Show the tail of a session's container output (stdout and stderr), as
+kept by its provider. Logs remain available after the workload crashes,
+until the instance is destroyed. Vast.ai only.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionsLogs,
+
+	ValidArgsFunction: completeSessionIDs,
+}
+
+var (
+	extendHours     int
+	sessionsLogTail int
+)
 
 func init() {
 	rootCmd.AddCommand(sessionsCmd)
@@ -68,11 +94,20 @@ func init() {
 	sessionsCmd.AddCommand(sessionsDoneCmd)
 	sessionsCmd.AddCommand(sessionsExtendCmd)
 	sessionsCmd.AddCommand(sessionsDeleteCmd)
+	sessionsCmd.AddCommand(sessionsLogsCmd)
 
 	sessionsListCmd.Flags().StringVarP(&sessionsConsumerID, "consumer", "c", "", "Filter by consumer ID")
 	sessionsListCmd.Flags().StringVarP(&sessionsStatus, "status", "s", "", "Filter by status")
+	sessionsListCmd.Flags().StringVar(&sessionsHealth, "health", "", "Filter by health (healthy, degraded)")
+	sessionsListCmd.Flags().BoolVar(&sessionsAll, "all", false, "Include stopped, failed and preempted sessions")
+	sessionsListCmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(
+		[]string{"pending", "provisioning", "running", "paused", "stopping", "stopped", "failed"}, cobra.ShellCompDirectiveNoFileComp))
+	sessionsListCmd.RegisterFlagCompletionFunc("health", cobra.FixedCompletions(
+		[]string{"healthy", "degraded"}, cobra.ShellCompDirectiveNoFileComp))
 
 	sessionsExtendCmd.Flags().IntVarP(&extendHours, "hours", "t", 1, "Additional hours (1-12)")
+
+	sessionsLogsCmd.Flags().IntVarP(&sessionsLogTail, "tail", "n", 200, "Lines from the end of the logs (max 5000)")
 }
 
 func runSessionsList(cmd *cobra.Command, args []string) error {
@@ -83,6 +118,12 @@ func runSessionsList(cmd *cobra.Command, args []string) error {
 	if sessionsStatus != "" {
 		params.Set("status", sessionsStatus)
 	}
+	if sessionsHealth != "" {
+		params.Set("health", sessionsHealth)
+	}
+	if sessionsAll {
+		params.Set("include_terminal", "true")
+	}
 
 	reqURL := fmt.Sprintf("%s/api/v1/sessions", serverURL)
 	if len(params) > 0 {
@@ -181,10 +222,26 @@ func runSessionsGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Price/Hour:     $%.2f\n", session.PricePerHour)
 	fmt.Printf("Created At:     %s\n", session.CreatedAt)
 	fmt.Printf("Expires At:     %s\n", session.ExpiresAt)
+	if h := session.Health; h != nil {
+		fmt.Printf("Health:         %s", h.Status)
+		if h.LastHeartbeatAt != "" {
+			fmt.Printf(" (last heartbeat %s)", h.LastHeartbeatAt)
+		}
+		fmt.Println()
+		if h.GPUUtilization != nil {
+			fmt.Printf("GPU Util:       %.0f%%\n", *h.GPUUtilization)
+		}
+		if h.IdleSeconds > 0 {
+			fmt.Printf("Idle For:       %s\n", time.Duration(h.IdleSeconds)*time.Second)
+		}
+	}
 
 	if session.SSHHost != "" {
 		fmt.Println("\nSSH Connection:")
 		fmt.Printf("  ssh -p %d %s@%s\n", session.SSHPort, session.SSHUser, session.SSHHost)
+		if session.SSHHostKey != "" {
+			fmt.Printf("  Host key: %s\n", session.SSHHostKey)
+		}
 	}
 
 	if session.Error != "" {
@@ -211,6 +268,11 @@ func runSessionsDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to signal done: %s", string(body))
 	}
 
+	if outputFormat == "json" {
+		_, err = io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+
 	fmt.Printf("Session %s shutdown initiated.\n", sessionID)
 	return nil
 }
@@ -227,7 +289,13 @@ func runSessionsExtend(cmd *cobra.Command, args []string) error {
 	}
 
 	reqURL := fmt.Sprintf("%s/api/v1/sessions/%s/extend", serverURL, sessionID)
-	resp, err := http.Post(reqURL, "application/json", bytes.NewReader(jsonBody))
+	req, err := http.NewRequest(http.MethodPatch, reqURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to connect to server: %w", err)
 	}
@@ -238,13 +306,23 @@ func runSessionsExtend(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to extend session: %s", string(body))
 	}
 
-	fmt.Printf("Session %s extended by %d hours.\n", sessionID, extendHours)
-
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
+	decodeErr := json.NewDecoder(resp.Body).Decode(&result)
+	if outputFormat == "json" {
+		if decodeErr != nil {
+			return fmt.Errorf("failed to parse response: %w", decodeErr)
+		}
+		return printJSON(result)
+	}
+
+	fmt.Printf("Session %s extended by %d hours.\n", sessionID, extendHours)
+	if decodeErr == nil {
 		if expiresAt, ok := result["new_expires_at"]; ok {
 			fmt.Printf("New expiration: %s\n", expiresAt)
 		}
+		if projected, ok := result["projected_cost"].(float64); ok {
+			fmt.Printf("Projected cost: $%.2f\n", projected)
+		}
 	}
 	return nil
 }
@@ -261,11 +339,59 @@ func runSessionsDelete(cmd *cobra.Command, args []string) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("failed to delete session: %s", string(body))
 	}
 
+	if outputFormat == "json" {
+		_, err = io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+
+	// The server saves a preserved workspace before destroying the session
+	if resp.StatusCode == http.StatusAccepted {
+		fmt.Printf("Saving workspace of session %s; it is destroyed once the workspace is saved.\n", sessionID)
+		return nil
+	}
 	fmt.Printf("Session %s destroyed.\n", sessionID)
 	return nil
 }
+
+func runSessionsLogs(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+
+	reqURL := fmt.Sprintf("%s/api/v1/sessions/%s/logs?tail=%d", serverURL, sessionID, sessionsLogTail)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error: %s", string(body))
+	}
+
+	var result struct {
+		SessionID string `json:"session_id"`
+		Tail      int    `json:"tail"`
+		Logs      string `json:"logs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Print(result.Logs)
+	return nil
+}
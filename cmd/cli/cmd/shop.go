@@ -0,0 +1,467 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shopConsumerID string
+	shopGPUType    string
+	shopMaxPrice   float64
+	shopRegion     string
+	shopWorkload   string
+	shopHours      int
+	shopSSHDir     string
+	shopWait       time.Duration
+)
+
+var shopCmd = &cobra.Command{
+	Use:   "shop",
+	Short: "Pick an offer interactively and provision it",
+	Long: `Browse available offers, narrow them down by GPU, price and region, and
+provision the one you pick. Once the session is running, its SSH key and a
+host entry are written so you can connect with "ssh gpu-<session-id>".
+
+The key is saved under ~/.ssh/gpu-shopper and the host entry to
+~/.ssh/gpu-shopper/config, which is included from ~/.ssh/config.
+
+Keys:
+  up/down, j/k   select an offer
+  g / p / r      filter by GPU, max price or region (enter to apply)
+  enter          provision the selected offer
+  q, esc         quit without provisioning
+
+Examples:
+  gpu-shopper shop -c my-app
+  gpu-shopper shop -c my-app --gpu A100 --max-price 2 --hours 4`,
+	RunE: runShop,
+}
+
+func init() {
+	rootCmd.AddCommand(shopCmd)
+
+	shopCmd.Flags().StringVarP(&shopConsumerID, "consumer", "c", "", "Consumer ID (required)")
+	shopCmd.Flags().StringVarP(&shopGPUType, "gpu", "g", "", "Initial GPU type filter")
+	shopCmd.Flags().Float64Var(&shopMaxPrice, "max-price", 0, "Initial maximum price per hour (USD)")
+	shopCmd.Flags().StringVar(&shopRegion, "region", "", "Initial region filter")
+	shopCmd.Flags().StringVarP(&shopWorkload, "workload", "w", "interactive", "Workload type (llm, llm_vllm, llm_tgi, llm_sglang, llm_llamacpp, training, batch, interactive)")
+	shopCmd.Flags().IntVarP(&shopHours, "hours", "t", 2, "Reservation hours (1-12)")
+	shopCmd.Flags().StringVar(&shopSSHDir, "ssh-dir", "", "SSH directory for the key and host entry (default ~/.ssh)")
+	shopCmd.Flags().DurationVar(&shopWait, "wait", 5*time.Minute, "How long to wait for SSH details before giving up on the host entry")
+
+	shopCmd.MarkFlagRequired("consumer")
+}
+
+// pickOffer runs the interactive picker and returns the chosen offer, or nil
+// if the user quit. Tests replace it to pick without a terminal.
+var pickOffer = func(offers []GPUOffer, filter offerFilter) (*GPUOffer, error) {
+	final, err := tea.NewProgram(newOfferPicker(offers, filter), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
+	}
+	return final.(*offerPicker).chosen, nil
+}
+
+// shopResult is printed by shop under --output json
+type shopResult struct {
+	Session    Session `json:"session"`
+	SSHKeyPath string  `json:"ssh_key_path,omitempty"`
+	SSHHost    string  `json:"ssh_host_alias,omitempty"`
+}
+
+func runShop(cmd *cobra.Command, args []string) error {
+	offers, err := shopFetchOffers()
+	if err != nil {
+		return err
+	}
+	if len(offers) == 0 {
+		return fmt.Errorf("no offers available")
+	}
+
+	offer, err := pickOffer(offers, offerFilter{GPU: shopGPUType, MaxPrice: shopMaxPrice, Region: shopRegion})
+	if err != nil {
+		return err
+	}
+	if offer == nil {
+		fmt.Fprintln(progressOut(), "No offer selected.")
+		return nil
+	}
+
+	fmt.Fprintf(progressOut(), "Provisioning %s (%dx %s, %s, $%.2f/hr)...\n",
+		offer.ID, offer.GPUCount, offer.GPUType, offer.Location, offer.PricePerHour)
+	result, err := shopCreateSession(offer.ID)
+	if err != nil {
+		return err
+	}
+	session := result.Session
+	fmt.Fprintf(progressOut(), "Session %s created (%s).\n", session.ID, session.Status)
+
+	if result.SSHPrivateKey == "" {
+		fmt.Fprintln(progressOut(), "No SSH key was returned; skipping SSH config.")
+		if outputFormat == "json" {
+			return printJSON(shopResult{Session: session})
+		}
+		return nil
+	}
+
+	sshDir := shopSSHDir
+	if sshDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to find home directory: %w", err)
+		}
+		sshDir = filepath.Join(home, ".ssh")
+	}
+	keyPath, err := saveSessionKey(sshDir, session.ID, result.SSHPrivateKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(progressOut(), "SSH private key saved to: %s\n", keyPath)
+
+	if session.SSHHost == "" {
+		fmt.Fprintln(progressOut(), "Waiting for SSH details...")
+		ctx, cancel := context.WithTimeout(context.Background(), shopWait)
+		running, err := smokeWaitRunning(ctx, session.ID, func(phase string) {
+			fmt.Fprintf(progressOut(), "  %s\n", phase)
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("session %s not ready, check it with 'gpu-shopper sessions get %s': %w", session.ID, session.ID, err)
+		}
+		session = *running
+	}
+
+	alias, err := writeSSHHostEntry(sshDir, &session, keyPath)
+	if err != nil {
+		return err
+	}
+	if outputFormat == "json" {
+		return printJSON(shopResult{Session: session, SSHKeyPath: keyPath, SSHHost: alias})
+	}
+	fmt.Println()
+	fmt.Println("Connect with:")
+	fmt.Printf("  ssh %s\n", alias)
+	return nil
+}
+
+// shopFetchOffers lists available offers, narrowed server-side by the
+// initial filters
+func shopFetchOffers() ([]GPUOffer, error) {
+	params := url.Values{}
+	if shopGPUType != "" {
+		params.Set("gpu_type", shopGPUType)
+	}
+	if shopMaxPrice > 0 {
+		params.Set("max_price", fmt.Sprintf("%.2f", shopMaxPrice))
+	}
+	if shopRegion != "" {
+		params.Set("location", shopRegion)
+	}
+
+	reqURL := fmt.Sprintf("%s/api/v1/inventory", serverURL)
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("server error: %s", string(body))
+	}
+
+	var result struct {
+		Offers []GPUOffer `json:"offers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Offers, nil
+}
+
+func shopCreateSession(offerID string) (*SessionResponse, error) {
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"consumer_id":       shopConsumerID,
+		"offer_id":          offerID,
+		"workload_type":     shopWorkload,
+		"reservation_hours": shopHours,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/sessions", serverURL), "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("provisioning failed: %s", string(body))
+	}
+
+	var result SessionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// shopConfigDir holds the session keys and the generated SSH config
+const shopConfigDir = "gpu-shopper"
+
+// saveSessionKey writes a session's private key to sshDir/gpu-shopper
+func saveSessionKey(sshDir, sessionID, privateKey string) (string, error) {
+	dir := filepath.Join(sshDir, shopConfigDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	keyPath := filepath.Join(dir, sessionID+".key")
+	if err := os.WriteFile(keyPath, []byte(privateKey), 0600); err != nil {
+		return "", fmt.Errorf("failed to save SSH key: %w", err)
+	}
+	return keyPath, nil
+}
+
+// writeSSHHostEntry appends a host entry for the session to
+// sshDir/gpu-shopper/config and makes sure sshDir/config includes that file.
+// It returns the host alias to connect with.
+func writeSSHHostEntry(sshDir string, session *Session, keyPath string) (string, error) {
+	alias := "gpu-" + session.ID
+	entry := fmt.Sprintf("\nHost %s\n  HostName %s\n  Port %d\n  User %s\n  IdentityFile \"%s\"\n  IdentitiesOnly yes\n  StrictHostKeyChecking accept-new\n  UserKnownHostsFile \"%s\"\n",
+		alias, session.SSHHost, session.SSHPort, session.SSHUser, keyPath,
+		filepath.Join(sshDir, shopConfigDir, "known_hosts"))
+
+	configPath := filepath.Join(sshDir, shopConfigDir, "config")
+	f, err := os.OpenFile(configPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", configPath, err)
+	}
+	if _, err := f.WriteString(entry); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write %s: %w", configPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", configPath, err)
+	}
+
+	// Include must come before any Host block to apply globally, so it is
+	// prepended rather than appended
+	mainConfig := filepath.Join(sshDir, "config")
+	existing, err := os.ReadFile(mainConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", mainConfig, err)
+	}
+	include := "Include " + configPath
+	if !strings.Contains(string(existing), include) {
+		updated := include + "\n\n" + string(existing)
+		if err := os.WriteFile(mainConfig, []byte(updated), 0600); err != nil {
+			return "", fmt.Errorf("failed to update %s: %w", mainConfig, err)
+		}
+	}
+	return alias, nil
+}
+
+// offerFilter narrows the picker's offer list
+type offerFilter struct {
+	GPU      string  // Case-insensitive substring of the GPU type
+	MaxPrice float64 // Zero means no limit
+	Region   string  // Case-insensitive substring of the location
+}
+
+func (f offerFilter) matches(o *GPUOffer) bool {
+	if f.GPU != "" && !containsFold(o.GPUType, f.GPU) {
+		return false
+	}
+	if f.MaxPrice > 0 && o.PricePerHour > f.MaxPrice {
+		return false
+	}
+	if f.Region != "" && !containsFold(o.Location, f.Region) {
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// offerPicker is the bubbletea model behind gpu-shopper shop
+type offerPicker struct {
+	offers  []GPUOffer
+	filter  offerFilter
+	visible []int // Indexes into offers that match the filter
+	cursor  int
+
+	editing string // Filter being edited: "gpu", "price" or "region"
+	input   string
+	status  string
+
+	chosen *GPUOffer
+}
+
+func newOfferPicker(offers []GPUOffer, filter offerFilter) *offerPicker {
+	p := &offerPicker{offers: offers, filter: filter}
+	p.applyFilter()
+	return p
+}
+
+func (p *offerPicker) applyFilter() {
+	p.visible = p.visible[:0]
+	for i := range p.offers {
+		if p.filter.matches(&p.offers[i]) {
+			p.visible = append(p.visible, i)
+		}
+	}
+	p.cursor = 0
+}
+
+func (p *offerPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (p *offerPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	if p.editing != "" {
+		p.handleInput(key)
+		return p, nil
+	}
+
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		return p, tea.Quit
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.visible)-1 {
+			p.cursor++
+		}
+	case "g":
+		p.startEditing("gpu", p.filter.GPU)
+	case "p":
+		price := ""
+		if p.filter.MaxPrice > 0 {
+			price = strconv.FormatFloat(p.filter.MaxPrice, 'f', -1, 64)
+		}
+		p.startEditing("price", price)
+	case "r":
+		p.startEditing("region", p.filter.Region)
+	case "enter":
+		if len(p.visible) > 0 {
+			p.chosen = &p.offers[p.visible[p.cursor]]
+			return p, tea.Quit
+		}
+	}
+	return p, nil
+}
+
+func (p *offerPicker) startEditing(field, value string) {
+	p.editing = field
+	p.input = value
+	p.status = ""
+}
+
+func (p *offerPicker) handleInput(key tea.KeyMsg) {
+	switch key.Type {
+	case tea.KeyEsc:
+		p.editing = ""
+		p.status = ""
+	case tea.KeyEnter:
+		switch p.editing {
+		case "gpu":
+			p.filter.GPU = strings.TrimSpace(p.input)
+		case "region":
+			p.filter.Region = strings.TrimSpace(p.input)
+		case "price":
+			price := 0.0
+			if s := strings.TrimSpace(p.input); s != "" {
+				v, err := strconv.ParseFloat(s, 64)
+				if err != nil || v < 0 {
+					p.status = fmt.Sprintf("invalid price %q", s)
+					return
+				}
+				price = v
+			}
+			p.filter.MaxPrice = price
+		}
+		p.editing = ""
+		p.status = ""
+		p.applyFilter()
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			runes := []rune(p.input)
+			p.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		p.input += string(key.Runes)
+	}
+}
+
+func (p *offerPicker) View() string {
+	var b strings.Builder
+
+	b.WriteString("gpu-shopper shop\n")
+	fmt.Fprintf(&b, "%d of %d offers", len(p.visible), len(p.offers))
+	if p.filter.GPU != "" {
+		fmt.Fprintf(&b, "  gpu %q", p.filter.GPU)
+	}
+	if p.filter.MaxPrice > 0 {
+		fmt.Fprintf(&b, "  max $%.2f/hr", p.filter.MaxPrice)
+	}
+	if p.filter.Region != "" {
+		fmt.Fprintf(&b, "  region %q", p.filter.Region)
+	}
+	b.WriteString("\n\n")
+
+	if len(p.visible) == 0 {
+		b.WriteString("No offers match the filters.\n")
+	} else {
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  PROVIDER\tGPU\tCOUNT\tVRAM\tLOCATION\tPRICE/HR\tCONFIDENCE")
+		for i, idx := range p.visible {
+			o := p.offers[idx]
+			marker := " "
+			if i == p.cursor {
+				marker = ">"
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%d\t%dGB\t%s\t$%.2f\t%.0f%%\n",
+				marker, o.Provider, o.GPUType, o.GPUCount, o.VRAM, o.Location, o.PricePerHour,
+				o.GetEffectiveAvailabilityConfidence()*100)
+		}
+		w.Flush()
+	}
+
+	b.WriteString("\n")
+	if p.status != "" {
+		b.WriteString(p.status + "\n")
+	}
+	if p.editing != "" {
+		fmt.Fprintf(&b, "Filter %s: %s_  (enter to apply, esc to cancel)\n", p.editing, p.input)
+	}
+	b.WriteString("up/down select  g gpu  p max price  r region  enter provision  q quit\n")
+	return b.String()
+}
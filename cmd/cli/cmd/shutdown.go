@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,8 @@ By default, this signals the session to shut down gracefully.
 Use --force to immediately destroy the session.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runShutdown,
+
+	ValidArgsFunction: completeSessionIDs,
 }
 
 func init() {
@@ -55,6 +58,11 @@ func runShutdown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("shutdown failed: %s", string(body))
 	}
 
+	if outputFormat == "json" {
+		_, err = io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+
 	if shutdownForce {
 		fmt.Printf("Session %s forcefully destroyed.\n", sessionID)
 	} else {
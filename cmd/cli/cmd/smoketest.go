@@ -0,0 +1,473 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/spf13/cobra"
+)
+
+var (
+	smokeMaxCost      float64
+	smokeConsumerID   string
+	smokeGPUType      string
+	smokeProvider     string
+	smokeTimeout      time.Duration
+	smokePollInterval = 10 * time.Second
+)
+
+// smokeCUDACheck initialises the CUDA driver API and counts devices. It uses
+// python3 ctypes against libcuda so no toolkit is needed on the image, and
+// falls back to a driver-level nvidia-smi query when python3 is missing.
+const smokeCUDACheck = `if command -v python3 >/dev/null 2>&1; then ` +
+	`python3 -c "import ctypes; c = ctypes.CDLL('libcuda.so.1'); n = ctypes.c_int(); ` +
+	`assert c.cuInit(0) == 0, 'cuInit failed'; ` +
+	`assert c.cuDeviceGetCount(ctypes.byref(n)) == 0, 'cuDeviceGetCount failed'; ` +
+	`assert n.value > 0, 'no CUDA devices'; print('cuda devices: %d' % n.value)"; ` +
+	`else nvidia-smi --query-gpu=name --format=csv,noheader >/dev/null && echo 'cuda devices: driver check only (python3 unavailable)'; fi`
+
+var smokeTestCmd = &cobra.Command{
+	Use:   "smoke-test",
+	Short: "Run an end-to-end smoke test against a real provider",
+	Long: `Provision the cheapest available offer, verify SSH and GPU access, run a
+trivial CUDA check, then destroy the instance and report timing and cost.
+
+The run is bounded by --max-cost: an offer is only selected if its price for
+every hour the run could be billed (at least one, since billing is hourly)
+fits within the cap, and the whole run is aborted at --timeout. The session
+is always destroyed, including on failure or interrupt.
+
+Examples:
+  gpu-shopper smoke-test --max-cost=0.50
+  gpu-shopper smoke-test --max-cost=1.00 --gpu RTX4090 --provider vastai`,
+	RunE: runSmokeTest,
+}
+
+func init() {
+	rootCmd.AddCommand(smokeTestCmd)
+
+	smokeTestCmd.Flags().Float64Var(&smokeMaxCost, "max-cost", 0.50, "Maximum total spend in USD for the run")
+	smokeTestCmd.Flags().StringVarP(&smokeConsumerID, "consumer", "c", "smoke-test", "Consumer ID to provision under")
+	smokeTestCmd.Flags().StringVarP(&smokeGPUType, "gpu", "g", "", "Restrict to a GPU type (e.g., RTX4090)")
+	smokeTestCmd.Flags().StringVarP(&smokeProvider, "provider", "p", "", "Restrict to a provider (vastai, tensordock, bluelobster)")
+	smokeTestCmd.Flags().DurationVar(&smokeTimeout, "timeout", 20*time.Minute, "Abort and destroy if the run takes longer than this")
+}
+
+// SmokeStep is the outcome of a single smoke test stage
+type SmokeStep struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration_ns"`
+	Detail   string        `json:"detail,omitempty"`
+}
+
+// SmokeReport summarises a smoke test run
+type SmokeReport struct {
+	OfferID       string      `json:"offer_id,omitempty"`
+	Provider      string      `json:"provider,omitempty"`
+	GPUType       string      `json:"gpu_type,omitempty"`
+	PricePerHour  float64     `json:"price_per_hour,omitempty"`
+	MaxCost       float64     `json:"max_cost"`
+	SessionID     string      `json:"session_id,omitempty"`
+	Steps         []SmokeStep `json:"steps"`
+	TotalDuration string      `json:"total_duration"`
+	EstimatedCost float64     `json:"estimated_cost"`
+	RecordedCost  *float64    `json:"recorded_cost,omitempty"`
+	Passed        bool        `json:"passed"`
+}
+
+// step runs fn, records its timing and outcome, and returns its error
+func (r *SmokeReport) step(name string, fn func() (string, error)) error {
+	start := time.Now()
+	detail, err := fn()
+	s := SmokeStep{Name: name, Passed: err == nil, Duration: time.Since(start), Detail: detail}
+	if err != nil {
+		s.Detail = err.Error()
+	}
+	r.Steps = append(r.Steps, s)
+	if outputFormat != "json" {
+		status := "ok"
+		if err != nil {
+			status = "FAILED"
+		}
+		fmt.Fprintf(os.Stderr, "[%s] %s (%s)\n", status, name, s.Duration.Round(time.Millisecond))
+	}
+	return err
+}
+
+func runSmokeTest(cmd *cobra.Command, args []string) error {
+	if smokeMaxCost <= 0 {
+		return fmt.Errorf("--max-cost must be positive")
+	}
+	if smokeTimeout <= 0 {
+		return fmt.Errorf("--timeout must be positive")
+	}
+
+	// Billing is per started hour, so the worst case is the price of every
+	// hour the run could touch before the timeout fires.
+	billedHours := math.Max(1, math.Ceil(smokeTimeout.Hours()))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, smokeTimeout)
+	defer cancelTimeout()
+
+	report := &SmokeReport{MaxCost: smokeMaxCost}
+	started := time.Now()
+
+	runErr := smokeRun(ctx, report, billedHours)
+
+	if report.SessionID != "" {
+		// Destroy with a fresh context so cleanup still runs after a timeout
+		// or interrupt has cancelled the main one.
+		destroyCtx, cancelDestroy := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancelDestroy()
+		if err := report.step("destroy", func() (string, error) {
+			return smokeDestroy(destroyCtx, report.SessionID)
+		}); err != nil && runErr == nil {
+			runErr = err
+		}
+
+		if cost, err := smokeFetchCost(report.SessionID); err == nil {
+			report.RecordedCost = &cost
+		}
+	}
+
+	elapsed := time.Since(started)
+	report.TotalDuration = elapsed.Round(time.Second).String()
+	if report.SessionID != "" {
+		report.EstimatedCost = report.PricePerHour * elapsed.Hours()
+	}
+	report.Passed = runErr == nil
+
+	if err := printSmokeReport(report); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("smoke test failed: %w", runErr)
+	}
+	return nil
+}
+
+// smokeRun executes every stage up to (but not including) destroy. The caller
+// is responsible for tearing down report.SessionID once it is set.
+func smokeRun(ctx context.Context, report *SmokeReport, billedHours float64) error {
+	var offer *GPUOffer
+	if err := report.step("select offer", func() (string, error) {
+		o, err := smokeSelectOffer(smokeMaxCost / billedHours)
+		if err != nil {
+			return "", err
+		}
+		offer = o
+		return fmt.Sprintf("%s %s $%.3f/hr", o.Provider, o.GPUType, o.PricePerHour), nil
+	}); err != nil {
+		return err
+	}
+	report.OfferID = offer.ID
+	report.Provider = offer.Provider
+	report.GPUType = offer.GPUType
+	report.PricePerHour = offer.PricePerHour
+
+	var created *SessionResponse
+	if err := report.step("provision", func() (string, error) {
+		resp, err := smokeCreateSession(offer.ID)
+		if err != nil {
+			return "", err
+		}
+		created = resp
+		return created.Session.ID, nil
+	}); err != nil {
+		return err
+	}
+	report.SessionID = created.Session.ID
+
+	var session *Session
+	if err := report.step("wait running", func() (string, error) {
+		s, err := smokeWaitRunning(ctx, report.SessionID, nil)
+		if err != nil {
+			return "", err
+		}
+		session = s
+		return fmt.Sprintf("%s@%s:%d", s.SSHUser, s.SSHHost, s.SSHPort), nil
+	}); err != nil {
+		return err
+	}
+
+	executor := ssh.NewExecutor(
+		ssh.WithExecutorConnectTimeout(30*time.Second),
+		ssh.WithExecutorCommandTimeout(60*time.Second),
+	)
+
+	var conn *ssh.Connection
+	if err := report.step("ssh", func() (string, error) {
+		c, err := executor.Connect(ctx, session.SSHHost, session.SSHPort, session.SSHUser, created.SSHPrivateKey)
+		if err != nil {
+			return "", err
+		}
+		conn = c
+		return "", executor.CheckHealth(ctx, conn)
+	}); err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := report.step("gpu", func() (string, error) {
+		status, err := executor.GetGPUStatus(ctx, conn)
+		if err != nil {
+			return "", err
+		}
+		return status.String(), nil
+	}); err != nil {
+		return err
+	}
+
+	return report.step("cuda", func() (string, error) {
+		info, err := executor.GetCUDAVersion(ctx, conn)
+		if err != nil {
+			return "", err
+		}
+		out, err := executor.RunCommandWithCombinedOutput(ctx, conn, smokeCUDACheck)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(out))
+		}
+		return fmt.Sprintf("CUDA %s, driver %s, %s", info.CUDAVersion, info.DriverVersion, strings.TrimSpace(out)), nil
+	})
+}
+
+// smokeSelectOffer returns the cheapest offer priced at or below maxPrice
+func smokeSelectOffer(maxPrice float64) (*GPUOffer, error) {
+	params := url.Values{}
+	params.Set("max_price", fmt.Sprintf("%.4f", maxPrice))
+	if smokeGPUType != "" {
+		params.Set("gpu_type", smokeGPUType)
+	}
+	if smokeProvider != "" {
+		params.Set("provider", smokeProvider)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/inventory?%s", serverURL, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("inventory request failed: %s", string(body))
+	}
+
+	var result struct {
+		Offers []GPUOffer `json:"offers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// The API orders by confidence first, so pick the cheapest explicitly and
+	// re-check the cap in case the server ignored the filter.
+	var cheapest *GPUOffer
+	for i := range result.Offers {
+		o := &result.Offers[i]
+		if !o.Available || o.PricePerHour > maxPrice {
+			continue
+		}
+		if cheapest == nil || o.PricePerHour < cheapest.PricePerHour {
+			cheapest = o
+		}
+	}
+	if cheapest == nil {
+		return nil, fmt.Errorf("no available offer within $%.2f/hr (max cost $%.2f)", maxPrice, smokeMaxCost)
+	}
+	return cheapest, nil
+}
+
+func smokeCreateSession(offerID string) (*SessionResponse, error) {
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"consumer_id":       smokeConsumerID,
+		"offer_id":          offerID,
+		"workload_type":     "interactive",
+		"reservation_hours": 1,
+		"storage_policy":    "destroy",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/sessions", serverURL), "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("provisioning failed: %s", string(body))
+	}
+
+	var result SessionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.Session.ID == "" {
+		return nil, fmt.Errorf("provisioning response missing session id")
+	}
+	return &result, nil
+}
+
+func smokeGetSession(sessionID string) (*Session, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/sessions/%s", serverURL, sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("session lookup failed: %s", string(body))
+	}
+
+	var session Session
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &session, nil
+}
+
+// smokeWaitRunning polls the session until it is running with SSH details.
+// onPhase, if set, is called each time the provisioning phase changes.
+func smokeWaitRunning(ctx context.Context, sessionID string, onPhase func(phase string)) (*Session, error) {
+	ticker := time.NewTicker(smokePollInterval)
+	defer ticker.Stop()
+
+	lastPhase := ""
+	for {
+		session, err := smokeGetSession(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if onPhase != nil && session.Phase != "" && session.Phase != lastPhase {
+			lastPhase = session.Phase
+			onPhase(session.Phase)
+		}
+		switch session.Status {
+		case "running":
+			if session.SSHHost == "" {
+				return nil, fmt.Errorf("session running without SSH host")
+			}
+			return session, nil
+		case "failed", "preempted", "stopped", "stopping":
+			return nil, fmt.Errorf("session %s: %s", session.Status, session.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting for session (last status %s): %w", session.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// smokeDestroy deletes the session and confirms the server reports it stopped
+func smokeDestroy(ctx context.Context, sessionID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/sessions/%s", serverURL, sessionID), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("destroy failed, check for orphans with 'gpu-shopper cleanup-orphans': %s", string(body))
+	}
+
+	session, err := smokeGetSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if session.Status != "stopped" {
+		return "", fmt.Errorf("session status %q after destroy, check for orphans with 'gpu-shopper cleanup-orphans'", session.Status)
+	}
+	return "verified stopped", nil
+}
+
+func smokeFetchCost(sessionID string) (float64, error) {
+	params := url.Values{}
+	params.Set("session_id", sessionID)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/costs?%s", serverURL, params.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("cost request failed: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		TotalCost float64 `json:"total_cost"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.TotalCost, nil
+}
+
+func printSmokeReport(report *SmokeReport) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Println()
+	if report.OfferID != "" {
+		fmt.Printf("Offer:          %s (%s %s, $%.3f/hr)\n", report.OfferID, report.Provider, report.GPUType, report.PricePerHour)
+	}
+	if report.SessionID != "" {
+		fmt.Printf("Session:        %s\n", report.SessionID)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tRESULT\tDURATION\tDETAIL")
+	fmt.Fprintln(w, "----\t------\t--------\t------")
+	for _, s := range report.Steps {
+		result := "PASS"
+		if !s.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, result, s.Duration.Round(time.Millisecond), truncateString(s.Detail, 80))
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Printf("Total time:     %s\n", report.TotalDuration)
+	fmt.Printf("Estimated cost: $%.4f (cap $%.2f)\n", report.EstimatedCost, report.MaxCost)
+	if report.RecordedCost != nil {
+		fmt.Printf("Recorded cost:  $%.4f\n", *report.RecordedCost)
+	}
+	if report.Passed {
+		fmt.Println("Result:         PASS")
+	} else {
+		fmt.Println("Result:         FAIL")
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshKeyFile       string
+	sshRegenerateKey bool
+)
+
+// sshBinary and scpBinary are the clients run by the ssh, port-forward and
+// cp commands. Tests point them at stubs.
+var (
+	sshBinary = "ssh"
+	scpBinary = "scp"
+)
+
+var sshCmd = &cobra.Command{
+	Use:   "ssh <session-id> [-- ssh-args...]",
+	Short: "Open an SSH shell on a session",
+	Long: `Look up a session's host, port and user and run ssh against it.
+
+The server never keeps session private keys, so the key comes from, in order:
+  --key                           a key saved at provisioning (--save-key)
+  ~/.ssh/gpu-shopper/<id>.key     the key saved by 'gpu-shopper shop'
+  --regenerate-key                a fresh key issued through the admin API
+
+--regenerate-key replaces the session's key (previous keys stop working) and is
+audited; it needs --admin-key and --actor. The new key is written to a private
+temporary file that is removed when ssh exits.
+
+Arguments after -- are passed to ssh, e.g. a remote command.
+
+Examples:
+  gpu-shopper ssh sess-abc123
+  gpu-shopper ssh sess-abc123 -k ~/.ssh/session_key
+  gpu-shopper ssh sess-abc123 -- nvidia-smi
+  gpu-shopper ssh sess-abc123 --regenerate-key --admin-key $KEY --actor alice`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSSH,
+
+	ValidArgsFunction: completeSessionIDs,
+}
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+	addSessionKeyFlags(sshCmd)
+}
+
+// addSessionKeyFlags adds the flags that choose a session's private key to
+// the ssh, port-forward and cp commands
+func addSessionKeyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&sshKeyFile, "key", "k", "", "Path to the session's SSH private key")
+	cmd.Flags().BoolVar(&sshRegenerateKey, "regenerate-key", false, "Issue a new session key through the admin API")
+	cmd.Flags().StringVar(&adminAPIKey, "admin-key", getEnvOrDefault("GPU_SHOPPER_ADMIN_KEY", ""), "Admin API key (with --regenerate-key)")
+	cmd.Flags().StringVar(&adminActor, "actor", getEnvOrDefault("USER", ""), "Operator name recorded in the audit log (with --regenerate-key)")
+	cmd.Flags().StringVar(&adminReason, "reason", "", "Reason recorded in the audit log (with --regenerate-key)")
+}
+
+func runSSH(cmd *cobra.Command, args []string) error {
+	conn, err := openSSHConnection(args[0])
+	if err != nil {
+		return err
+	}
+	defer conn.cleanup()
+
+	sshArgs := append(conn.options(), "-p", strconv.Itoa(conn.session.SSHPort), conn.target())
+	return runSSHClient(sshBinary, append(sshArgs, args[1:]...))
+}
+
+// sshConnection is what the ssh, port-forward and cp commands need to reach
+// a running session with the system ssh client
+type sshConnection struct {
+	session    *Session
+	keyPath    string
+	knownHosts string
+	cleanup    func() // Removes a temporary key
+}
+
+func openSSHConnection(sessionID string) (*sshConnection, error) {
+	session, err := getSessionDetails(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != "running" || session.SSHHost == "" {
+		return nil, fmt.Errorf("session %s is %s, not running with SSH available", sessionID, session.Status)
+	}
+
+	sshDir, err := sshConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	keyPath, cleanup, err := sshSessionKey(session)
+	if err != nil {
+		return nil, err
+	}
+	return &sshConnection{
+		session:    session,
+		keyPath:    keyPath,
+		knownHosts: filepath.Join(sshDir, "known_hosts"),
+		cleanup:    cleanup,
+	}, nil
+}
+
+// options returns the identity and host key options shared by ssh and scp.
+// The port is left out since ssh takes -p and scp -P.
+func (c *sshConnection) options() []string {
+	return []string{
+		"-i", c.keyPath,
+		"-o", "IdentitiesOnly=yes",
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-o", "UserKnownHostsFile=" + c.knownHosts,
+	}
+}
+
+func (c *sshConnection) target() string {
+	return c.session.SSHUser + "@" + c.session.SSHHost
+}
+
+// runSSHClient runs ssh or scp attached to the terminal. It runs rather than
+// execs so a temporary key is removed afterwards, and treats Ctrl+C as the
+// normal way to end a session or forward.
+func runSSHClient(binary string, args []string) error {
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	defer signal.Stop(interrupted)
+
+	c := exec.Command(binary, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		select {
+		case <-interrupted:
+			return nil
+		default:
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%s exited with status %d", filepath.Base(binary), exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to run %s: %w", binary, err)
+	}
+	return nil
+}
+
+// sshConfigDir returns ~/.ssh/gpu-shopper, where session keys and their
+// known hosts are kept, creating it if needed
+func sshConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".ssh", shopConfigDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// sshSessionKey finds the private key for a session. The returned cleanup
+// removes the key if it was written to a temporary file.
+func sshSessionKey(session *Session) (string, func(), error) {
+	noop := func() {}
+
+	if sshKeyFile != "" {
+		if _, err := os.Stat(sshKeyFile); err != nil {
+			return "", noop, fmt.Errorf("SSH key: %w", err)
+		}
+		return sshKeyFile, noop, nil
+	}
+
+	if !sshRegenerateKey {
+		dir, err := sshConfigDir()
+		if err != nil {
+			return "", noop, err
+		}
+		saved := filepath.Join(dir, session.ID+".key")
+		if _, err := os.Stat(saved); err == nil {
+			return saved, noop, nil
+		}
+		return "", noop, fmt.Errorf("no saved key for session %s: pass --key, or --regenerate-key to issue a new one", session.ID)
+	}
+
+	body, err := adminPost(fmt.Sprintf("/api/v1/admin/consumers/%s/sessions/%s/ssh-key", session.ConsumerID, session.ID), map[string]interface{}{})
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to regenerate SSH key: %w", err)
+	}
+	var result struct {
+		PrivateKey string `json:"ssh_private_key"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", noop, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// CreateTemp opens the file 0600, as ssh requires for identity files
+	f, err := os.CreateTemp("", "gpu-shopper-"+session.ID+"-*.key")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create key file: %w", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := f.WriteString(result.PrivateKey); err != nil {
+		f.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("failed to write key file: %w", err)
+	}
+	return f.Name(), cleanup, nil
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topConsumerID string
+	topInterval   time.Duration
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live terminal view of active sessions",
+	Long: `Show active sessions with their status, GPU utilization and cost so far,
+refreshing from the server until you quit.
+
+Keys:
+  up/down, j/k   select a session
+  d              destroy the selected session (asks to confirm with y)
+  e              extend the selected session by one hour
+  r              refresh now
+  q              quit
+
+Examples:
+  gpu-shopper top
+  gpu-shopper top --consumer my-app --interval 10s`,
+	RunE: runTop,
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+
+	topCmd.Flags().StringVarP(&topConsumerID, "consumer", "c", "", "Only show this consumer's sessions")
+	topCmd.Flags().DurationVar(&topInterval, "interval", 5*time.Second, "How often to refresh")
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	if outputFormat == "json" {
+		return fmt.Errorf("top is interactive; use 'gpu-shopper sessions list -o json' for scripting")
+	}
+	if topInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	_, err := tea.NewProgram(newTopModel(serverURL, topConsumerID, topInterval), tea.WithAltScreen()).Run()
+	return err
+}
+
+// topActiveStatuses are the statuses shown by top, fetched one list each so
+// long-running sessions are never cut off by the list limit
+var topActiveStatuses = []string{"pending", "provisioning", "running", "paused", "stopping"}
+
+// topSession is an active session with the cost it has accrued so far
+type topSession struct {
+	Session
+	Accrued float64
+}
+
+// topSnapshotMsg carries one refresh of sessions and fleet spend
+type topSnapshotMsg struct {
+	sessions   []topSession
+	burnRate   float64
+	spentToday float64
+	err        error
+}
+
+// topActionMsg reports the result of a destroy or extend
+type topActionMsg struct {
+	text string
+	err  error
+}
+
+type topTickMsg time.Time
+
+// topModel is the bubbletea model behind gpu-shopper top
+type topModel struct {
+	server     string
+	consumerID string
+	interval   time.Duration
+	now        func() time.Time
+
+	sessions   []topSession
+	burnRate   float64
+	spentToday float64
+	updated    time.Time
+	err        error
+
+	cursor     int
+	confirming string // Session awaiting destroy confirmation
+	status     string // Result of the last action
+}
+
+func newTopModel(server, consumerID string, interval time.Duration) *topModel {
+	return &topModel{
+		server:     server,
+		consumerID: consumerID,
+		interval:   interval,
+		now:        time.Now,
+	}
+}
+
+func (m *topModel) Init() tea.Cmd {
+	return tea.Batch(m.fetch(), m.tick())
+}
+
+func (m *topModel) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg { return topTickMsg(t) })
+}
+
+func (m *topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case topTickMsg:
+		return m, tea.Batch(m.fetch(), m.tick())
+
+	case topSnapshotMsg:
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil // Keep showing the last good snapshot
+		}
+		selected := m.selected()
+		m.sessions = msg.sessions
+		m.burnRate = msg.burnRate
+		m.spentToday = msg.spentToday
+		m.updated = m.now()
+		m.cursor = 0
+		for i, s := range m.sessions {
+			if selected != nil && s.ID == selected.ID {
+				m.cursor = i
+			}
+		}
+		return m, nil
+
+	case topActionMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		} else {
+			m.status = msg.text
+		}
+		return m, m.fetch()
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *topModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Any key other than y cancels a pending destroy
+	if m.confirming != "" {
+		id := m.confirming
+		m.confirming = ""
+		if key == "y" {
+			m.status = fmt.Sprintf("destroying %s...", id)
+			return m, m.destroy(id)
+		}
+		m.status = "destroy cancelled"
+		return m, nil
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.sessions)-1 {
+			m.cursor++
+		}
+	case "r":
+		return m, m.fetch()
+	case "d":
+		if s := m.selected(); s != nil && s.Status != "stopping" {
+			m.confirming = s.ID
+		}
+	case "e":
+		if s := m.selected(); s != nil {
+			m.status = fmt.Sprintf("extending %s...", s.ID)
+			return m, m.extend(s.ID)
+		}
+	}
+	return m, nil
+}
+
+func (m *topModel) selected() *topSession {
+	if m.cursor < 0 || m.cursor >= len(m.sessions) {
+		return nil
+	}
+	return &m.sessions[m.cursor]
+}
+
+func (m *topModel) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "gpu-shopper top  %s", m.server)
+	if m.consumerID != "" {
+		fmt.Fprintf(&b, "  consumer %s", m.consumerID)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%d active  burn $%.2f/h  spent today $%.2f", len(m.sessions), m.burnRate, m.spentToday)
+	if !m.updated.IsZero() {
+		fmt.Fprintf(&b, "  updated %s", m.updated.Format("15:04:05"))
+	}
+	b.WriteString("\n")
+	if m.err != nil {
+		fmt.Fprintf(&b, "refresh failed: %v\n", m.err)
+	}
+	b.WriteString("\n")
+
+	if len(m.sessions) == 0 {
+		b.WriteString("No active sessions.\n")
+	} else {
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  ID\tCONSUMER\tPROVIDER\tGPU\tSTATUS\tHEALTH\tGPU UTIL\tACCRUED\tPRICE/HR\tEXPIRES IN")
+		for i, s := range m.sessions {
+			marker := " "
+			if i == m.cursor {
+				marker = ">"
+			}
+			health, util := "", ""
+			if s.Health != nil {
+				health = s.Health.Status
+				if s.Health.GPUUtilization != nil {
+					util = fmt.Sprintf("%.0f%%", *s.Health.GPUUtilization)
+				}
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\t%dx %s\t%s\t%s\t%s\t$%.2f\t$%.2f\t%s\n",
+				marker, s.ID, s.ConsumerID, s.Provider, s.GPUCount, s.GPUType, s.Status,
+				health, util, s.Accrued, s.PricePerHour, m.expiresIn(s.ExpiresAt))
+		}
+		w.Flush()
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.confirming != "":
+		fmt.Fprintf(&b, "Destroy %s? (y/N)\n", m.confirming)
+	case m.status != "":
+		b.WriteString(m.status + "\n")
+	}
+	b.WriteString("up/down select  d destroy  e extend 1h  r refresh  q quit\n")
+	return b.String()
+}
+
+// expiresIn formats the time left until an RFC3339 expiry
+func (m *topModel) expiresIn(expiresAt string) string {
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return expiresAt
+	}
+	left := t.Sub(m.now())
+	if left <= 0 {
+		return "expired"
+	}
+	return left.Truncate(time.Minute).String()
+}
+
+// fetch loads the active sessions and merges in their accrued cost from the
+// fleet forecast
+func (m *topModel) fetch() tea.Cmd {
+	return func() tea.Msg {
+		var msg topSnapshotMsg
+		for _, status := range topActiveStatuses {
+			params := url.Values{"status": {status}}
+			if m.consumerID != "" {
+				params.Set("consumer_id", m.consumerID)
+			}
+			var result struct {
+				Sessions []Session `json:"sessions"`
+			}
+			if err := m.do(http.MethodGet, "/api/v1/sessions?"+params.Encode(), nil, &result); err != nil {
+				return topSnapshotMsg{err: err}
+			}
+			for _, s := range result.Sessions {
+				msg.sessions = append(msg.sessions, topSession{Session: s})
+			}
+		}
+
+		params := url.Values{}
+		if m.consumerID != "" {
+			params.Set("consumer_id", m.consumerID)
+		}
+		var forecast struct {
+			BurnRate   float64 `json:"burn_rate_per_hour"`
+			SpentToday float64 `json:"spent_today"`
+			Sessions   []struct {
+				SessionID   string  `json:"session_id"`
+				AccruedCost float64 `json:"accrued_cost"`
+			} `json:"sessions"`
+		}
+		if err := m.do(http.MethodGet, "/api/v1/costs/forecast?"+params.Encode(), nil, &forecast); err != nil {
+			return topSnapshotMsg{err: err}
+		}
+		accrued := make(map[string]float64, len(forecast.Sessions))
+		for _, f := range forecast.Sessions {
+			accrued[f.SessionID] = f.AccruedCost
+		}
+		for i := range msg.sessions {
+			msg.sessions[i].Accrued = accrued[msg.sessions[i].ID]
+		}
+		msg.burnRate = forecast.BurnRate
+		msg.spentToday = forecast.SpentToday
+
+		sort.Slice(msg.sessions, func(i, j int) bool {
+			return msg.sessions[i].CreatedAt > msg.sessions[j].CreatedAt
+		})
+		return msg
+	}
+}
+
+func (m *topModel) destroy(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.do(http.MethodDelete, "/api/v1/sessions/"+url.PathEscape(sessionID), nil, nil); err != nil {
+			return topActionMsg{err: fmt.Errorf("failed to destroy %s: %w", sessionID, err)}
+		}
+		return topActionMsg{text: fmt.Sprintf("session %s destroyed", sessionID)}
+	}
+}
+
+func (m *topModel) extend(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		body, _ := json.Marshal(map[string]int{"additional_hours": 1})
+		var result struct {
+			NewExpiresAt string `json:"new_expires_at"`
+		}
+		if err := m.do(http.MethodPatch, "/api/v1/sessions/"+url.PathEscape(sessionID)+"/extend", body, &result); err != nil {
+			return topActionMsg{err: fmt.Errorf("failed to extend %s: %w", sessionID, err)}
+		}
+		return topActionMsg{text: fmt.Sprintf("session %s extended to %s", sessionID, result.NewExpiresAt)}
+	}
+}
+
+// do sends a request to the server and decodes a JSON response into out
+func (m *topModel) do(method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequest(method, m.server+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error: %s", strings.TrimSpace(string(respBody)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -142,11 +142,21 @@ func createTransfer(session *Session, keyData []byte) (*filetransfer.Transfer, e
 		Port:       session.SSHPort,
 		User:       session.SSHUser,
 		PrivateKey: keyData,
+
+		HostKeyFingerprint: session.SSHHostKey,
 	}
 
 	return filetransfer.New(creds, filetransfer.WithConnectTimeout(30*time.Second)), nil
 }
 
+// transferResult is printed by upload and download under --output json
+type transferResult struct {
+	SessionID  string `json:"session_id"`
+	Direction  string `json:"direction"`
+	LocalPath  string `json:"local_path"`
+	RemotePath string `json:"remote_path"`
+}
+
 func runUpload(cmd *cobra.Command, args []string) error {
 	localPath := args[0]
 	sessionPath := args[1]
@@ -169,7 +179,7 @@ func runUpload(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get session details
-	fmt.Printf("Fetching session %s...\n", sessionID)
+	fmt.Fprintf(progressOut(), "Fetching session %s...\n", sessionID)
 	session, err := getSessionDetails(sessionID)
 	if err != nil {
 		return err
@@ -185,13 +195,16 @@ func runUpload(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
 	defer cancel()
 
-	fmt.Printf("Uploading %s to %s@%s:%d:%s...\n",
+	fmt.Fprintf(progressOut(), "Uploading %s to %s@%s:%d:%s...\n",
 		localPath, session.SSHUser, session.SSHHost, session.SSHPort, remotePath)
 
 	if err := transfer.Upload(ctx, localPath, remotePath); err != nil {
 		return fmt.Errorf("upload failed: %w", err)
 	}
 
+	if outputFormat == "json" {
+		return printJSON(transferResult{SessionID: sessionID, Direction: "upload", LocalPath: localPath, RemotePath: remotePath})
+	}
 	fmt.Println("Upload complete.")
 	return nil
 }
@@ -213,7 +226,7 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get session details
-	fmt.Printf("Fetching session %s...\n", sessionID)
+	fmt.Fprintf(progressOut(), "Fetching session %s...\n", sessionID)
 	session, err := getSessionDetails(sessionID)
 	if err != nil {
 		return err
@@ -229,13 +242,16 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
 	defer cancel()
 
-	fmt.Printf("Downloading %s@%s:%d:%s to %s...\n",
+	fmt.Fprintf(progressOut(), "Downloading %s@%s:%d:%s to %s...\n",
 		session.SSHUser, session.SSHHost, session.SSHPort, remotePath, localPath)
 
 	if err := transfer.Download(ctx, remotePath, localPath); err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
 
+	if outputFormat == "json" {
+		return printJSON(transferResult{SessionID: sessionID, Direction: "download", LocalPath: localPath, RemotePath: remotePath})
+	}
 	fmt.Println("Download complete.")
 	return nil
 }
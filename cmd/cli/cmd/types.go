@@ -15,18 +15,31 @@ type Session struct {
 	GPUType      string  `json:"gpu_type"`
 	GPUCount     int     `json:"gpu_count"`
 	Status       string  `json:"status"`
+	Phase        string  `json:"provision_phase,omitempty"`
 	Error        string  `json:"error,omitempty"`
 	SSHHost      string  `json:"ssh_host,omitempty"`
 	SSHPort      int     `json:"ssh_port,omitempty"`
 	SSHUser      string  `json:"ssh_user,omitempty"`
+	SSHHostKey   string  `json:"ssh_host_key_fingerprint,omitempty"`
 	WorkloadType string  `json:"workload_type"`
 	PricePerHour float64 `json:"price_per_hour"`
 	CreatedAt    string  `json:"created_at"`
 	ExpiresAt    string  `json:"expires_at"`
+
+	Health *SessionHealth `json:"health,omitempty"`
+}
+
+// SessionHealth is a running session's heartbeat state
+type SessionHealth struct {
+	Status          string   `json:"status"`
+	LastHeartbeatAt string   `json:"last_heartbeat_at,omitempty"`
+	GPUUtilization  *float64 `json:"gpu_utilization,omitempty"`
+	IdleSeconds     int      `json:"idle_seconds,omitempty"`
 }
 
 // SessionResponse is the response from session creation
 type SessionResponse struct {
-	Session       Session `json:"session"`
-	SSHPrivateKey string  `json:"ssh_private_key,omitempty"`
+	Session         Session `json:"session"`
+	SSHPrivateKey   string  `json:"ssh_private_key,omitempty"`
+	SelectedOfferID string  `json:"selected_offer_id,omitempty"`
 }
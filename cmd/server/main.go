@@ -13,15 +13,26 @@ import (
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/config"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/logging"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/notify"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/objectstore"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/bluelobster"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/middleware"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/static"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/tensordock"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/vastai"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
 	benchsvc "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/cost"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/featureflags"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/leader"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/lifecycle"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/modelcache"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/ranking"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/sessionexport"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
 )
 
@@ -51,6 +62,16 @@ func main() {
 	}
 	defer db.Close()
 
+	if cfg.Database.EncryptionKey != "" {
+		envelope, err := newEnvelope(cfg.Database.EncryptionKey)
+		if err != nil {
+			logger.Error("invalid database encryption key", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		db.SetEncryption(envelope)
+		logger.Info("database encryption enabled")
+	}
+
 	ctx := context.Background()
 	if err := db.Migrate(ctx); err != nil {
 		logger.Error("failed to run migrations", slog.String("error", err.Error()))
@@ -61,6 +82,9 @@ func main() {
 	sessionStore := storage.NewSessionStore(db)
 	costStore := storage.NewCostStore(db)
 
+	// Initialize offer failure store for persistent failure tracking
+	offerFailureStore := storage.NewOfferFailureStore(db)
+
 	// Initialize benchmark store
 	benchmarkStore, err := benchmark.NewStore(db.DB)
 	if err != nil {
@@ -72,40 +96,83 @@ func main() {
 
 	// Initialize providers
 	var providers []provider.Provider
+	providerRetry := middleware.WithRetry(middleware.RetryPolicy{
+		MaxAttempts: cfg.Providers.Retry.MaxAttempts,
+		BaseDelay:   cfg.Providers.Retry.BaseDelay,
+		MaxDelay:    cfg.Providers.Retry.MaxDelay,
+		Jitter:      cfg.Providers.Retry.Jitter,
+	})
 
-	if cfg.Providers.VastAI.APIKey != "" {
-		vastaiClient := vastai.NewClient(cfg.Providers.VastAI.APIKey)
-		providers = append(providers, vastaiClient)
-		logger.Info("initialized Vast.ai provider")
-	}
+	// Offline mode never calls live provider APIs
+	if cfg.Providers.Offline {
+		logger.Info("offline mode: live providers disabled")
+	} else {
+		if cfg.Providers.VastAI.APIKey != "" {
+			vastaiClient := vastai.NewClient(cfg.Providers.VastAI.APIKey)
+			providers = append(providers, middleware.Wrap(vastaiClient, providerRetry))
+			logger.Info("initialized Vast.ai provider")
+		}
+
+		if cfg.Providers.BlueLobster.Enabled && cfg.Providers.BlueLobster.APIKey != "" {
+			bluelobsterClient := bluelobster.NewClient(
+				cfg.Providers.BlueLobster.APIKey,
+				bluelobster.WithDefaultTemplate(cfg.Providers.BlueLobster.DefaultTemplate),
+			)
+			providers = append(providers, middleware.Wrap(bluelobsterClient, providerRetry))
+			logger.Info("initialized Blue Lobster provider",
+				slog.String("default_template", cfg.Providers.BlueLobster.DefaultTemplate))
+		}
 
-	if cfg.Providers.BlueLobster.Enabled && cfg.Providers.BlueLobster.APIKey != "" {
-		bluelobsterClient := bluelobster.NewClient(
-			cfg.Providers.BlueLobster.APIKey,
-			bluelobster.WithDefaultTemplate(cfg.Providers.BlueLobster.DefaultTemplate),
-		)
-		providers = append(providers, bluelobsterClient)
-		logger.Info("initialized Blue Lobster provider",
-			slog.String("default_template", cfg.Providers.BlueLobster.DefaultTemplate))
+		if cfg.Providers.TensorDock.AuthID != "" && cfg.Providers.TensorDock.APIToken != "" {
+			tensordockClient := tensordock.NewClient(
+				cfg.Providers.TensorDock.AuthID,
+				cfg.Providers.TensorDock.APIToken,
+				tensordock.WithDefaultImage(cfg.Providers.TensorDock.DefaultImage),
+				tensordock.WithNativeSSHKeys(cfg.Providers.TensorDock.NativeSSHKeys),
+				tensordock.WithLocationStatsStore(offerFailureStore),
+			)
+			if err := tensordockClient.LoadLocationStats(ctx); err != nil {
+				logger.Warn("failed to load TensorDock location stats", slog.String("error", err.Error()))
+			}
+			providers = append(providers, middleware.Wrap(tensordockClient, providerRetry))
+			logger.Info("initialized TensorDock provider",
+				slog.String("default_image", cfg.Providers.TensorDock.DefaultImage))
+		}
 	}
 
-	if cfg.Providers.TensorDock.AuthID != "" && cfg.Providers.TensorDock.APIToken != "" {
-		tensordockClient := tensordock.NewClient(
-			cfg.Providers.TensorDock.AuthID,
-			cfg.Providers.TensorDock.APIToken,
-			tensordock.WithDefaultImage(cfg.Providers.TensorDock.DefaultImage),
-		)
-		providers = append(providers, tensordockClient)
-		logger.Info("initialized TensorDock provider",
-			slog.String("default_image", cfg.Providers.TensorDock.DefaultImage))
+	var staticProvider *static.Client
+	if cfg.Providers.Static.CatalogPath != "" {
+		catalog, err := static.LoadCatalog(cfg.Providers.Static.CatalogPath)
+		if err != nil {
+			logger.Error("failed to load static catalog", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		staticOpts := []static.Option{static.WithLogger(logger)}
+		if cfg.Providers.Static.SSHKeyPath != "" {
+			adminKey, err := os.ReadFile(cfg.Providers.Static.SSHKeyPath)
+			if err != nil {
+				logger.Error("failed to read static provider SSH key", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			staticOpts = append(staticOpts, static.WithKeyInstaller(static.NewSSHKeyInstaller(string(adminKey))))
+		}
+		staticProvider = static.NewClient(catalog, staticOpts...)
+		providers = append(providers, staticProvider)
+		logger.Info("initialized static catalog provider",
+			slog.String("catalog", cfg.Providers.Static.CatalogPath),
+			slog.Int("nodes", len(catalog.Nodes)))
 	}
 
 	if len(providers) == 0 {
 		logger.Warn("no providers configured, running in demo mode")
 	}
 
-	// Initialize offer failure store for persistent failure tracking
-	offerFailureStore := storage.NewOfferFailureStore(db)
+	// Webhook notifier, shared by inventory price watches, budgets,
+	// provisioning and lifecycle events
+	notifier := notify.New(storage.NewWebhookStore(db),
+		notify.WithLogger(logger),
+		notify.WithMaxAttempts(cfg.Webhooks.MaxAttempts),
+		notify.WithRetryBackoff(cfg.Webhooks.RetryBackoff))
 
 	// Initialize services with provider-specific cache TTLs
 	invOpts := []inventory.Option{
@@ -113,18 +180,21 @@ func main() {
 		inventory.WithCacheTTL(cfg.Inventory.DefaultCacheTTL),
 		inventory.WithBackoffTTL(cfg.Inventory.BackoffCacheTTL),
 		inventory.WithFailureStore(offerFailureStore),
+		inventory.WithPriceHistory(storage.NewPriceHistoryStore(db)),
+		inventory.WithPriceWatches(storage.NewPriceWatchStore(db), notifier),
 	}
-	// TensorDock has volatile inventory, use shorter cache TTL
-	if cfg.Inventory.TensorDockCacheTTL > 0 {
-		invOpts = append(invOpts, inventory.WithProviderCacheTTL("tensordock", cfg.Inventory.TensorDockCacheTTL))
-		logger.Info("using shorter cache TTL for TensorDock",
-			slog.Duration("ttl", cfg.Inventory.TensorDockCacheTTL))
+	for name, ttl := range providerCacheTTLs(cfg, staticProvider != nil) {
+		invOpts = append(invOpts, inventory.WithProviderCacheTTL(name, ttl))
+		logger.Info("using provider-specific cache TTL",
+			slog.String("provider", name),
+			slog.Duration("ttl", ttl))
 	}
 	invService := inventory.New(providers, invOpts...)
 
 	// Load persisted failure tracking data from DB
 	{
-		since := time.Now().Add(-inventory.FailureDecayPeriod)
+		// Host reputation outlasts offer failure history
+		since := time.Now().Add(-inventory.HostFailureDecayPeriod)
 		dbFailures, err := offerFailureStore.LoadRecentFailures(ctx, since)
 		if err != nil {
 			logger.Warn("failed to load persisted failure data", slog.String("error", err.Error()))
@@ -136,6 +206,7 @@ func main() {
 					OfferID:     f.OfferID,
 					Provider:    f.Provider,
 					GPUType:     f.GPUType,
+					MachineID:   f.MachineID,
 					FailureType: f.FailureType,
 					Reason:      f.Reason,
 					CreatedAt:   f.CreatedAt,
@@ -161,35 +232,145 @@ func main() {
 
 			invService.LoadFailureData(ctx, failures, suppressions)
 		}
+
+		rules, err := offerFailureStore.LoadRules(ctx, time.Now())
+		if err != nil {
+			logger.Warn("failed to load offer rules", slog.String("error", err.Error()))
+		} else {
+			invService.LoadOfferRules(rules)
+		}
 	}
 
 	registry := provisioner.NewSimpleProviderRegistry(providers)
 	costTracker := cost.New(costStore, sessionStore, nil,
-		cost.WithLogger(logger))
+		cost.WithLogger(logger),
+		cost.WithProviders(registry),
+		cost.WithUsageStore(storage.NewUsageStore(db)))
+
+	budgetOpts := []budget.Option{
+		budget.WithLogger(logger),
+		budget.WithCheckInterval(cfg.Budget.CheckInterval),
+		budget.WithWarningThreshold(cfg.Budget.WarningThreshold),
+	}
+	if cfg.Lifecycle.DeploymentID != "" {
+		budgetOpts = append(budgetOpts, budget.WithDeploymentID(cfg.Lifecycle.DeploymentID))
+	}
+	var budgetAlerts budget.AlertSender = notifier
+	if cfg.Budget.WebhookURL != "" {
+		budgetAlerts = budget.MultiAlertSender{
+			budget.NewWebhookAlertSender(cfg.Budget.WebhookURL),
+			notifier,
+		}
+	}
+	budgetOpts = append(budgetOpts, budget.WithAlertSender(budgetAlerts))
+	budgetService := budget.New(storage.NewBudgetStore(db), costStore, sessionStore, budgetOpts...)
+
+	featureFlags := featureflags.New(storage.NewFeatureFlagStore(db),
+		featureflags.WithLogger(logger),
+		featureflags.WithDefault(tensordock.FeatureDedicatedIP, true),
+		featureflags.WithDefault(tensordock.FeatureNvidiaAutoInstall, true),
+		featureflags.WithDefault(provisioner.FeatureEntrypointWarmup, true))
 
+	sessionEvents := storage.NewSessionEventStore(db)
+	quarantineStore := storage.NewQuarantineStore(db)
 	provOpts := []provisioner.Option{
 		provisioner.WithLogger(logger),
 		provisioner.WithSSHVerifyTimeout(cfg.SSH.VerifyTimeout),
 		provisioner.WithSSHCheckInterval(cfg.SSH.CheckInterval),
+		provisioner.WithBandwidthTestURL(cfg.SSH.BandwidthTestURL),
+		provisioner.WithKernelWatchInterval(cfg.SSH.KernelWatchInterval),
 		provisioner.WithInventory(invService),
 		provisioner.WithCostRecorder(costTracker),
+		provisioner.WithBudgetChecker(budgetService),
+		provisioner.WithNotifier(notifier),
+		provisioner.WithEventRecorder(sessionEvents),
+		provisioner.WithQuarantine(quarantineStore),
+		provisioner.WithFeatureFlags(featureFlags),
+		provisioner.WithAllowedRegions(cfg.Policy.AllowedRegions),
+		provisioner.WithCreateConcurrency(cfg.Providers.MaxConcurrentCreates, createLimits(cfg.Providers), cfg.Providers.CreateQueueTimeout),
+		provisioner.WithReadinessProbes(readinessProbes(cfg.Workloads)),
 	}
 	if cfg.Lifecycle.DeploymentID != "" {
 		provOpts = append(provOpts, provisioner.WithDeploymentID(cfg.Lifecycle.DeploymentID))
 	}
+	if ws := cfg.Workspaces; ws.Bucket != "" {
+		bucket, err := objectstore.New(ws.Endpoint, ws.Bucket, ws.AccessKeyID, ws.SecretAccessKey,
+			objectstore.WithRegion(ws.Region))
+		if err != nil {
+			logger.Error("failed to configure workspace storage", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		provOpts = append(provOpts,
+			provisioner.WithWorkspaceSnapshotter(provisioner.NewWorkspaceSnapshotter(bucket, ws.Prefix, ws.Path)),
+			provisioner.WithWorkspaceSaveTimeout(ws.SaveTimeout))
+		logger.Info("preserving workspaces", slog.String("bucket", ws.Bucket), slog.String("path", ws.Path))
+	}
+	if mc := cfg.ModelCache; mc.Bucket != "" {
+		bucket, err := objectstore.New(mc.Endpoint, mc.Bucket, mc.AccessKeyID, mc.SecretAccessKey,
+			objectstore.WithRegion(mc.Region))
+		if err != nil {
+			logger.Error("failed to configure model cache", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		provOpts = append(provOpts,
+			provisioner.WithModelCache(modelcache.New(bucket, modelcache.WithPrefix(mc.Prefix), modelcache.WithDir(mc.Path))),
+			provisioner.WithModelPullTimeout(mc.PullTimeout))
+		logger.Info("pulling models from the model cache", slog.String("bucket", mc.Bucket), slog.String("path", mc.Path))
+	}
 	provService := provisioner.New(sessionStore, registry, provOpts...)
 
+	// Hard kill switch on provider-reported spend, independent of our estimates
+	guardOpts := []budget.SpendGuardOption{
+		budget.WithGuardLogger(logger),
+		budget.WithGuardCheckInterval(cfg.Budget.CheckInterval),
+		budget.WithSpendCeiling(cfg.Budget.SpendCeiling),
+		budget.WithGuardAlertSender(budgetAlerts),
+	}
+	if cfg.Lifecycle.DeploymentID != "" {
+		guardOpts = append(guardOpts, budget.WithGuardDeploymentID(cfg.Lifecycle.DeploymentID))
+	}
+	spendGuard := budget.NewSpendGuard(registry, costStore, sessionStore, provService, guardOpts...)
+
+	// Session requests that find no offer can wait for inventory; every
+	// provider refresh wakes the queue
+	reservationQueue := provisioner.NewReservationQueue(provService, invService, storage.NewReservationStore(db),
+		provisioner.WithQueueLogger(logger),
+		provisioner.WithQueueRegionPolicy(provService))
+	invService.OnRefresh(reservationQueue.Kick)
+
+	// Static leases live in memory; rebuild them from active sessions so
+	// leased nodes are not offered again or reported as ghosts
+	if staticProvider != nil {
+		active, err := sessionStore.GetActiveSessionsByProvider(ctx, static.ProviderName)
+		if err != nil {
+			logger.Error("failed to load sessions for static leases", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		restored := staticProvider.RestoreLeases(active, provService.GetDeploymentID())
+		logger.Info("restored static node leases", slog.Int("leases", restored))
+	}
+
 	lifecycleManager := lifecycle.New(sessionStore, provService,
 		lifecycle.WithLogger(logger),
 		lifecycle.WithCheckInterval(cfg.Lifecycle.CheckInterval),
 		lifecycle.WithHardMaxHours(cfg.Lifecycle.HardMaxHours),
-		lifecycle.WithOrphanGracePeriod(cfg.Lifecycle.OrphanGracePeriod))
+		lifecycle.WithOrphanGracePeriod(cfg.Lifecycle.OrphanGracePeriod),
+		lifecycle.WithMaxPausedDuration(cfg.Lifecycle.MaxPausedDuration),
+		lifecycle.WithEventHandler(notifier),
+		lifecycle.WithPreemptionHandler(provService),
+		lifecycle.WithHeartbeatReader(provService))
 
 	// Create reconciler with auto-destroy orphans enabled
 	reconcileOpts := []lifecycle.ReconcilerOption{
 		lifecycle.WithReconcileLogger(logger),
 		lifecycle.WithReconcileInterval(cfg.Lifecycle.ReconciliationInterval),
 		lifecycle.WithAutoDestroyOrphans(true),
+		lifecycle.WithFailoverHandler(provService),
+		lifecycle.WithLostSessionHandler(provService),
+		lifecycle.WithVerificationResumer(provService),
+		lifecycle.WithInstanceClaims(sessionStore, storage.NewInstanceReleaseStore(db)),
+		lifecycle.WithQuarantine(quarantineStore),
+		lifecycle.WithDestroyFailureNotifier(notifier),
 	}
 	if cfg.Lifecycle.DeploymentID != "" {
 		reconcileOpts = append(reconcileOpts, lifecycle.WithDeploymentID(cfg.Lifecycle.DeploymentID))
@@ -208,54 +389,150 @@ func main() {
 		lifecycle.WithStartupSweepTimeout(cfg.Lifecycle.StartupSweepTimeout),
 		lifecycle.WithShutdownTimeout(cfg.Lifecycle.ShutdownTimeout))
 
+	apiKeys, err := api.ParseAPIKeys(cfg.Server.APIKeys)
+	if err != nil {
+		logger.Error("invalid API_KEYS", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	trustedProxies, err := api.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.Error("invalid TRUSTED_PROXIES", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	// Initialize API server (not ready yet)
 	apiOpts := []api.Option{
 		api.WithLogger(logger),
 		api.WithPort(cfg.Server.Port),
+		api.WithBudgetService(budgetService),
+		api.WithDatabaseEncryption(cfg.Database.EncryptionKey != ""),
+		api.WithSpendGuard(spendGuard),
+		api.WithAdmin(cfg.Server.AdminAPIKey, storage.NewAuditStore(db)),
+		api.WithAPIKeys(apiKeys),
+		api.WithReconciler(reconciler),
+		api.WithInstanceClaimer(reconciler),
+		api.WithQuarantineStore(quarantineStore),
+		api.WithTrustedProxies(trustedProxies),
+		api.WithRateLimit(cfg.Server.RateLimitRPS, cfg.Server.RateLimitBurst),
+		api.WithCreateSessionRateLimit(cfg.Server.CreateSessionRatePerMinute, cfg.Server.CreateSessionBurst),
+		api.WithNotifier(notifier),
+		api.WithSessionEventStore(sessionEvents),
+		api.WithFailureStatsStore(sessionStore),
+		api.WithFeatureFlags(featureFlags),
+		api.WithReservationQueue(reservationQueue),
+		api.WithSessionExport(sessionexport.New(sessionStore, sessionEvents, costStore,
+			sessionexport.WithLogger(logger),
+			sessionexport.WithDeploymentID(provService.GetDeploymentID()))),
+	}
+	// Offer ranking draws on provisioning history and, when available, benchmarks
+	rankingOpts := []ranking.Option{
+		ranking.WithLogger(logger),
+		ranking.WithProvisioningTimeSource(sessionStore),
 	}
+	var benchScheduler *benchsvc.Scheduler
 	if benchmarkStore != nil {
 		apiOpts = append(apiOpts, api.WithBenchmarkStore(benchmarkStore))
+		rankingOpts = append(rankingOpts, ranking.WithThroughputSource(benchmarkStore))
+
+		// Model catalog: built-in entries, then the catalog file, then
+		// custom entries added through the admin API
+		benchCatalog := benchmark.NewCatalog()
+		if cfg.Benchmark.CatalogPath != "" {
+			if err := benchCatalog.LoadFile(cfg.Benchmark.CatalogPath); err != nil {
+				logger.Error("failed to load benchmark catalog", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			logger.Info("loaded benchmark catalog", slog.String("catalog", cfg.Benchmark.CatalogPath))
+		}
+		if catalogStore, err := benchmark.NewCatalogStore(db.DB); err != nil {
+			logger.Warn("failed to initialize benchmark catalog store", slog.String("error", err.Error()))
+		} else if err := benchCatalog.LoadStore(ctx, catalogStore); err != nil {
+			logger.Warn("failed to load custom benchmark catalog entries", slog.String("error", err.Error()))
+		}
+		apiOpts = append(apiOpts, api.WithBenchmarkCatalog(benchCatalog))
 
 		// Initialize benchmark runner with manifest store
 		manifestStore, err := benchmark.NewManifestStore(db.DB)
 		if err != nil {
 			logger.Warn("failed to initialize benchmark manifest store", slog.String("error", err.Error()))
 		} else {
-			benchRunner := benchsvc.NewRunner(provService, invService, benchmarkStore, manifestStore, logger, "scripts/gpu-benchmark.sh")
+			benchRunner := benchsvc.NewRunner(provService, invService, benchmarkStore, manifestStore, logger, "scripts/gpu-benchmark.sh",
+				benchsvc.WithCatalog(benchCatalog))
 			apiOpts = append(apiOpts, api.WithBenchmarkRunner(benchRunner))
 			logger.Info("initialized benchmark runner")
+
+			// Recurring benchmark runs keep price/performance data fresh
+			scheduleStore, err := benchsvc.NewScheduleStore(db.DB)
+			if err != nil {
+				logger.Warn("failed to initialize benchmark schedule store", slog.String("error", err.Error()))
+			} else {
+				benchScheduler = benchsvc.NewScheduler(benchRunner, scheduleStore, logger)
+				apiOpts = append(apiOpts, api.WithBenchmarkScheduler(benchScheduler))
+			}
 		}
 	}
+	apiOpts = append(apiOpts, api.WithRanker(ranking.New(rankingOpts...)))
 	server := api.New(invService, provService, lifecycleManager, costTracker, apiOpts...)
 
-	// Initialize metrics from database state BEFORE startup sweep
-	// This ensures gauges reflect reality before any reconciliation runs
-	storageCounts, err := sessionStore.CountSessionsByProviderAndStatus(ctx)
-	if err != nil {
-		logger.Error("failed to query session counts for metrics", slog.String("error", err.Error()))
-		// Continue startup - metrics will be incorrect but service can run
-	} else {
-		metricsCounts := make([]metrics.SessionCount, len(storageCounts))
+	// Derive session gauges from database state BEFORE startup sweep, then
+	// keep re-deriving them so they cannot drift from the sessions table
+	sessionProjector := metrics.NewSessionProjector(func(ctx context.Context) ([]metrics.SessionCount, error) {
+		storageCounts, err := sessionStore.CountSessionsByProviderAndStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		counts := make([]metrics.SessionCount, len(storageCounts))
 		for i, c := range storageCounts {
-			metricsCounts[i] = metrics.SessionCount{
+			counts[i] = metrics.SessionCount{
 				Provider: c.Provider,
 				Status:   c.Status,
 				Count:    c.Count,
 			}
 		}
-		if err := metrics.InitializeSessionMetrics(ctx, metricsCounts); err != nil {
-			logger.Error("failed to initialize metrics", slog.String("error", err.Error()))
+		return counts, nil
+	}, metrics.WithSessionCosts(func(ctx context.Context) ([]metrics.SessionCost, error) {
+		sessions, err := sessionStore.GetActiveSessions(ctx)
+		if err != nil {
+			return nil, err
 		}
+		costs := make([]metrics.SessionCost, 0, len(sessions))
+		for _, sess := range sessions {
+			accrued, err := costTracker.GetSessionCost(ctx, sess.ID)
+			if err != nil {
+				return nil, err
+			}
+			costs = append(costs, metrics.SessionCost{
+				SessionID:  sess.ID,
+				ConsumerID: sess.ConsumerID,
+				Provider:   sess.Provider,
+				Accrued:    accrued,
+				BurnRate:   sess.PricePerHour,
+				Runtime:    time.Since(sess.CreatedAt),
+			})
+		}
+		return costs, nil
+	}), metrics.WithProjectorLogger(logger))
+	if err := sessionProjector.Start(ctx); err != nil {
+		logger.Error("failed to start session metrics projector", slog.String("error", err.Error()))
+	}
+
+	// Every replica keeps its own inventory cache warm, leader or not
+	if cfg.Inventory.BackgroundRefresh {
+		invService.StartRefresher(ctx)
 	}
 
-	// Run startup sweep before accepting traffic (if enabled)
-	if cfg.Lifecycle.StartupSweepEnabled {
+	// Run startup sweep before accepting traffic (if enabled). Replicas
+	// sharing a database skip it: sessions that look stuck or orphaned may
+	// be in flight on another replica.
+	if cfg.Lifecycle.StartupSweepEnabled && !cfg.Lifecycle.LeaderElection {
 		logger.Info("running startup sweep to clean up orphaned instances")
 		if err := startupManager.RunStartupSweep(ctx); err != nil {
 			logger.Error("startup sweep failed", slog.String("error", err.Error()))
 			// Continue startup even if sweep fails - we don't want to prevent
 			// the server from starting due to sweep issues
 		}
+	} else if cfg.Lifecycle.LeaderElection {
+		logger.Info("leader election enabled, leaving orphan cleanup and stuck session recovery to the leader")
 	} else {
 		logger.Info("startup sweep disabled, skipping")
 	}
@@ -263,23 +540,109 @@ func main() {
 	// Mark server as ready
 	server.SetReady(true)
 
-	// Start background services
-	if err := lifecycleManager.Start(ctx); err != nil {
-		logger.Error("failed to start lifecycle manager", slog.String("error", err.Error()))
-		os.Exit(1)
-	}
+	// Background services act on state shared by every replica, so with
+	// leader election only the elected replica runs them
+	startBackgroundServices := func(ctx context.Context) {
+		// With leader election the startup sweep is skipped, so the leader
+		// resumes verifications a restart interrupted once elected. In the
+		// background, as the elector waits for this to return.
+		if cfg.Lifecycle.StartupSweepEnabled && cfg.Lifecycle.LeaderElection {
+			go func() {
+				if err := reconciler.RecoverStuckSessions(ctx); err != nil {
+					logger.Error("failed to recover stuck sessions", slog.String("error", err.Error()))
+				}
+			}()
+		}
 
-	if err := costTracker.Start(ctx); err != nil {
-		logger.Error("failed to start cost tracker", slog.String("error", err.Error()))
-		os.Exit(1)
+		if err := lifecycleManager.Start(ctx); err != nil {
+			logger.Error("failed to start lifecycle manager", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if err := costTracker.Start(ctx); err != nil {
+			logger.Error("failed to start cost tracker", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if err := budgetService.Start(ctx); err != nil {
+			logger.Error("failed to start budget service", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if err := spendGuard.Start(ctx); err != nil {
+			logger.Error("failed to start spend guard", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if err := notifier.Start(ctx); err != nil {
+			logger.Error("failed to start webhook notifier", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		// Start reconciler for ongoing checks
+		if err := reconciler.Start(ctx); err != nil {
+			logger.Error("failed to start reconciler", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if err := reservationQueue.Start(ctx); err != nil {
+			logger.Error("failed to start reservation queue", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if benchScheduler != nil {
+			benchScheduler.Start(ctx)
+		}
+	}
+	stopBackgroundServices := func() {
+		if benchScheduler != nil {
+			benchScheduler.Stop()
+		}
+		reservationQueue.Stop()
+		reconciler.Stop()
+		lifecycleManager.Stop()
+		costTracker.Stop()
+		budgetService.Stop()
+		spendGuard.Stop()
+		notifier.Stop()
 	}
 
-	// Start reconciler for ongoing checks
-	if err := reconciler.Start(ctx); err != nil {
-		logger.Error("failed to start reconciler", slog.String("error", err.Error()))
-		os.Exit(1)
+	var elector *leader.Elector
+	if cfg.Lifecycle.LeaderElection {
+		elector = leader.New(storage.NewLeaseStore(db), startBackgroundServices, stopBackgroundServices,
+			leader.WithLeaseTTL(cfg.Lifecycle.LeaderLeaseTTL),
+			leader.WithLogger(logger))
+		if err := elector.Start(ctx); err != nil {
+			logger.Error("failed to start leader election", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	} else {
+		startBackgroundServices(ctx)
 	}
 
+	// Reload the log level and cache TTLs on SIGHUP; everything else is
+	// only read at startup
+	go func() {
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		for range hupCh {
+			next, err := config.LoadFromEnv()
+			if err != nil {
+				logger.Error("config reload failed, keeping current settings", slog.String("error", err.Error()))
+				continue
+			}
+			logging.SetLevel(next.Logging.Level)
+			invService.SetCacheTTLs(next.Inventory.DefaultCacheTTL, next.Inventory.BackoffCacheTTL,
+				providerCacheTTLs(next, staticProvider != nil))
+			logger.Info("reloaded configuration",
+				slog.String("log_level", next.Logging.Level),
+				slog.Duration("default_cache_ttl", next.Inventory.DefaultCacheTTL))
+			if cfg.RequiresRestart(next) {
+				logger.Warn("config changes other than the log level and cache TTLs take effect after a restart")
+			}
+		}
+	}()
+
 	// Handle shutdown
 	go func() {
 		sigCh := make(chan os.Signal, 1)
@@ -291,18 +654,36 @@ func main() {
 		// Mark server as not ready to stop accepting new requests
 		server.SetReady(false)
 
+		// No new scheduled benchmarks or queued sessions while sessions are
+		// being torn down
+		if benchScheduler != nil {
+			benchScheduler.Stop()
+		}
+		reservationQueue.Stop()
+
 		// Run graceful shutdown to destroy active sessions BEFORE stopping server
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Lifecycle.ShutdownTimeout+10*time.Second)
 		defer cancel()
 
-		if err := startupManager.GracefulShutdown(shutdownCtx); err != nil {
-			logger.Error("graceful shutdown error", slog.String("error", err.Error()))
+		if elector == nil {
+			if err := startupManager.GracefulShutdown(shutdownCtx); err != nil {
+				logger.Error("graceful shutdown error", slog.String("error", err.Error()))
+			}
+		} else {
+			// Other replicas keep serving the sessions, and one of them
+			// takes over background services once the lease is released
+			logger.Info("leader election enabled, leaving active sessions running")
 		}
 
 		// Stop background services
-		reconciler.Stop()
-		lifecycleManager.Stop()
-		costTracker.Stop()
+		if elector != nil {
+			elector.Stop()
+		} else {
+			stopBackgroundServices()
+		}
+		sessionProjector.Stop()
+		provService.Stop()
+		invService.Shutdown()
 
 		// Shutdown HTTP server
 		if err := server.Shutdown(shutdownCtx); err != nil {
@@ -316,3 +697,50 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// providerCacheTTLs returns the inventory cache TTLs that override the
+// default for individual providers
+func providerCacheTTLs(cfg *config.Config, withStatic bool) map[string]time.Duration {
+	ttls := make(map[string]time.Duration)
+	// TensorDock has volatile inventory, use shorter cache TTL
+	if cfg.Inventory.TensorDockCacheTTL > 0 {
+		ttls["tensordock"] = cfg.Inventory.TensorDockCacheTTL
+	}
+	// Static offers change only when nodes are leased, and listing is free
+	if withStatic {
+		ttls[static.ProviderName] = 5 * time.Second
+	}
+	for name, ttl := range cfg.Inventory.ProviderCacheTTLs {
+		ttls[name] = ttl
+	}
+	return ttls
+}
+
+// createLimits returns the instance creation limits that override the
+// shared limit for individual providers
+func createLimits(cfg config.ProvidersConfig) map[string]int {
+	limits := make(map[string]int)
+	// TensorDock rate limits instance creation aggressively
+	if cfg.TensorDock.MaxConcurrentCreates > 0 {
+		limits["tensordock"] = cfg.TensorDock.MaxConcurrentCreates
+	}
+	return limits
+}
+
+// readinessProbes converts the configured readiness probe of each workload type
+func readinessProbes(cfg config.WorkloadsConfig) map[provider.WorkloadType]provisioner.ReadinessProbe {
+	probes := make(map[provider.WorkloadType]provisioner.ReadinessProbe, len(cfg.ReadinessProbes))
+	for workload, probe := range cfg.ReadinessProbes {
+		probes[provider.WorkloadType(workload)] = provisioner.ReadinessProbe(probe)
+	}
+	return probes
+}
+
+// newEnvelope builds the database encryption envelope from a base64 master key
+func newEnvelope(encodedKey string) (*secrets.Envelope, error) {
+	key, err := secrets.ParseMasterKey(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.NewEnvelope(key)
+}
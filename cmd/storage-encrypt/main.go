@@ -0,0 +1,58 @@
+// Command storage-encrypt seals secret columns that were written before
+// database encryption was enabled, using the DATABASE_ENCRYPTION_KEY master key.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/config"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "Path to SQLite database (default: DATABASE_PATH from configuration)")
+	flag.Parse()
+
+	// Load configuration so the key may come from a secrets backend
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Database.EncryptionKey == "" {
+		log.Fatal("DATABASE_ENCRYPTION_KEY is required")
+	}
+	if *dbPath == "" {
+		*dbPath = cfg.Database.Path
+	}
+
+	key, err := secrets.ParseMasterKey(cfg.Database.EncryptionKey)
+	if err != nil {
+		log.Fatalf("Invalid encryption key: %v", err)
+	}
+	envelope, err := secrets.NewEnvelope(key)
+	if err != nil {
+		log.Fatalf("Invalid encryption key: %v", err)
+	}
+
+	db, err := storage.New(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Migrate(ctx); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+	db.SetEncryption(envelope)
+
+	sealed, err := db.EncryptExisting(ctx)
+	if err != nil {
+		log.Fatalf("Failed to encrypt existing values (%d sealed before the error): %v", sealed, err)
+	}
+	fmt.Printf("Encrypted %d value(s) in %s\n", sealed, *dbPath)
+}
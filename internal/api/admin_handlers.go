@@ -0,0 +1,1095 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/featureflags"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/lifecycle"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/sessionexport"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// AuditStore persists the admin audit trail
+type AuditStore interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error)
+}
+
+// AdminAuditQuery holds query parameters for listing audit entries
+type AdminAuditQuery struct {
+	ConsumerID string `form:"consumer_id"`
+	SessionID  string `form:"session_id"`
+	Actor      string `form:"actor"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// SetFeatureFlagRequest is the request body for setting a feature flag
+type SetFeatureFlagRequest struct {
+	Enabled        *bool  `json:"enabled" binding:"required"`
+	RolloutPercent *int   `json:"rollout_percent" binding:"omitempty,min=0,max=100"` // Defaults to 100
+	Description    string `json:"description"`
+}
+
+// CatalogModelRequest is the request body for adding a model to the
+// benchmark catalog
+type CatalogModelRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Workload    string `json:"workload"`                    // Defaults to chat
+	MinVRAMGB   int    `json:"min_vram_gb" binding:"min=0"` // Per GPU; 0 = unknown
+	Description string `json:"description"`
+}
+
+// CatalogGPURequest is the request body for adding a GPU type to the
+// benchmark catalog
+type CatalogGPURequest struct {
+	Name   string `json:"name" binding:"required"`
+	VRAMGB int    `json:"vram_gb" binding:"required,min=1"`
+}
+
+// OfferRuleRequest is the request body for suppressing or allowlisting
+// offers by offer ID, host (machine ID) or location
+type OfferRuleRequest struct {
+	Action          string `json:"action" binding:"required,oneof=suppress allow"`
+	Scope           string `json:"scope" binding:"required,oneof=offer machine location"`
+	Value           string `json:"value" binding:"required"`
+	Provider        string `json:"provider"`                                    // Empty = any provider
+	DurationMinutes int    `json:"duration_minutes" binding:"min=0,max=525600"` // 0 = until removed
+	Reason          string `json:"reason"`                                      // Defaults to X-Admin-Reason
+}
+
+// AdoptInstanceRequest is the request body for adopting an unclaimed instance
+type AdoptInstanceRequest struct {
+	ConsumerID       string `json:"consumer_id" binding:"required"`
+	ReservationHours int    `json:"reservation_hours" binding:"min=0,max=12"` // Defaults to 1
+}
+
+// ExportSessionsRequest is the request body for exporting sessions. Explicit
+// session IDs take precedence over a consumer ID.
+type ExportSessionsRequest struct {
+	SessionIDs []string `json:"session_ids"`
+	ConsumerID string   `json:"consumer_id"`
+	Passphrase string   `json:"passphrase" binding:"required"`
+}
+
+// ImportSessionsRequest is the request body for importing an export bundle
+type ImportSessionsRequest struct {
+	Export     *models.SessionExport `json:"export" binding:"required"`
+	Passphrase string                `json:"passphrase" binding:"required"`
+	TakeOver   bool                  `json:"take_over"` // Keep active sessions active and manage their instances
+}
+
+// adminAuthMiddleware requires the admin API key (or, with access control
+// enabled, any admin-role key) as a bearer token and an X-Admin-Actor header
+// naming the operator, so every action is attributable.
+func (s *Server) adminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if (s.adminAPIKey == "" && !s.rbacEnabled()) || s.auditStore == nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse{
+				Error:     "admin API not configured",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+
+		// Admin-role keys were already checked by requireRole
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !s.rbacEnabled() && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminAPIKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:     "invalid admin credentials",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+
+		actor := sanitizeInput(c.GetHeader("X-Admin-Actor"), 128)
+		if actor == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "X-Admin-Actor header is required",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+
+		c.Set("admin_actor", actor)
+		c.Next()
+	}
+}
+
+// audit records an admin action before it is performed. Actions are refused
+// if the audit entry cannot be written.
+func (s *Server) audit(c *gin.Context, action models.AuditAction, consumerID, sessionID, details string) bool {
+	entry := &models.AuditEntry{
+		Actor:      c.GetString("admin_actor"),
+		Action:     action,
+		ConsumerID: consumerID,
+		SessionID:  sessionID,
+		Reason:     sanitizeInput(c.GetHeader("X-Admin-Reason"), 512),
+		Details:    details,
+		RequestID:  c.GetString("request_id"),
+	}
+
+	if err := s.auditStore.Record(c.Request.Context(), entry); err != nil {
+		s.logger.Error("failed to record admin audit entry",
+			slog.String("actor", entry.Actor),
+			slog.String("action", string(action)),
+			slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to record audit entry",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+
+	s.logger.Info("admin action",
+		slog.String("actor", entry.Actor),
+		slog.String("action", string(action)),
+		slog.String("consumer_id", consumerID),
+		slog.String("session_id", sessionID),
+		slog.String("reason", entry.Reason))
+
+	return true
+}
+
+// consumerSession loads a session and checks it belongs to the consumer in
+// the path. A mismatch is reported as not found so admins cannot act on a
+// session through the wrong consumer.
+func (s *Server) consumerSession(c *gin.Context) (*models.Session, bool) {
+	consumerID := c.Param("consumer_id")
+	sessionID := c.Param("id")
+
+	session, err := s.provisioner.GetSession(c.Request.Context(), sessionID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get session",
+			RequestID: c.GetString("request_id"),
+		})
+		return nil, false
+	}
+	if session == nil || session.ConsumerID != consumerID {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: fmt.Sprintf("session %s not found for consumer %s",
+				sanitizeInput(sessionID, 128), sanitizeInput(consumerID, 128)),
+			RequestID: c.GetString("request_id"),
+		})
+		return nil, false
+	}
+
+	return session, true
+}
+
+// handleAdminListSessions lists a consumer's sessions.
+func (s *Server) handleAdminListSessions(c *gin.Context) {
+	consumerID := c.Param("consumer_id")
+
+	if !s.audit(c, models.AuditActionListSessions, consumerID, "", "") {
+		return
+	}
+
+	sessions, err := s.provisioner.ListSessions(c.Request.Context(), models.SessionListFilter{
+		ConsumerID: consumerID,
+		Status:     models.SessionStatus(c.Query("status")),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list sessions",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	responses := make([]models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"consumer_id": consumerID,
+		"sessions":    responses,
+		"count":       len(responses),
+	})
+}
+
+// handleAdminRegenerateSSHKey issues a fresh SSH key for a consumer's running session.
+func (s *Server) handleAdminRegenerateSSHKey(c *gin.Context) {
+	session, ok := s.consumerSession(c)
+	if !ok {
+		return
+	}
+
+	if !s.audit(c, models.AuditActionRegenerateSSHKey, session.ConsumerID, session.ID, "") {
+		return
+	}
+
+	privateKey, err := s.provisioner.RegenerateSSHKey(c.Request.Context(), session.ID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var notRunning *provisioner.SessionNotRunningError
+		var notSupported *provisioner.SSHKeyRotationNotSupportedError
+		switch {
+		case errors.As(err, &notRunning):
+			status = http.StatusConflict
+		case errors.As(err, &notSupported):
+			status = http.StatusNotImplemented
+		}
+		c.JSON(status, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id":      session.ID,
+		"ssh_host":        session.SSHHost,
+		"ssh_port":        session.SSHPort,
+		"ssh_user":        session.SSHUser,
+		"ssh_private_key": privateKey,
+	})
+}
+
+// handleAdminExtendSession extends a consumer's session.
+func (s *Server) handleAdminExtendSession(c *gin.Context) {
+	session, ok := s.consumerSession(c)
+	if !ok {
+		return
+	}
+
+	var req ExtendSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	details := fmt.Sprintf("additional_hours=%d", req.AdditionalHours)
+	if !s.audit(c, models.AuditActionExtendSession, session.ConsumerID, session.ID, details) {
+		return
+	}
+
+	if err := s.lifecycle.ExtendSession(c.Request.Context(), session.ID, req.AdditionalHours); err != nil {
+		c.JSON(extendErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	response := gin.H{
+		"message":    "session extended",
+		"session_id": session.ID,
+	}
+	if updated, err := s.provisioner.GetSession(c.Request.Context(), session.ID); err == nil {
+		response["new_expires_at"] = updated.ExpiresAt
+		response["reservation_hours"] = updated.ReservationHrs
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleAdminDestroySession destroys a consumer's session.
+func (s *Server) handleAdminDestroySession(c *gin.Context) {
+	session, ok := s.consumerSession(c)
+	if !ok {
+		return
+	}
+
+	if !s.audit(c, models.AuditActionDestroySession, session.ConsumerID, session.ID, "") {
+		return
+	}
+
+	if err := s.provisioner.DestroySession(c.Request.Context(), session.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	if s.provisioner.SavingWorkspace(session.ID) {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":    "saving workspace, the session is destroyed once it is saved",
+			"session_id": session.ID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "session destroyed",
+		"session_id": session.ID,
+	})
+}
+
+// handleAdminListAudit lists the admin audit trail.
+func (s *Server) handleAdminListAudit(c *gin.Context) {
+	var query AdminAuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	entries, err := s.auditStore.List(c.Request.Context(), models.AuditFilter{
+		Actor:      query.Actor,
+		ConsumerID: query.ConsumerID,
+		SessionID:  query.SessionID,
+		Limit:      query.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list audit entries",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if entries == nil {
+		entries = []*models.AuditEntry{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// handleAdminListFeatureFlags lists stored feature flags and built-in defaults.
+func (s *Server) handleAdminListFeatureFlags(c *gin.Context) {
+	if !s.requireFeatureFlags(c) {
+		return
+	}
+
+	flags, err := s.featureFlags.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list feature flags",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if flags == nil {
+		flags = []*models.FeatureFlag{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flags": flags,
+		"count": len(flags),
+	})
+}
+
+// handleAdminSetFeatureFlag creates or updates a feature flag. Changes take
+// effect on the next provisioning request.
+func (s *Server) handleAdminSetFeatureFlag(c *gin.Context) {
+	if !s.requireFeatureFlags(c) {
+		return
+	}
+
+	var req SetFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	flag := &models.FeatureFlag{
+		Name:           c.Param("name"),
+		Enabled:        *req.Enabled,
+		RolloutPercent: 100,
+		Description:    sanitizeInput(req.Description, 512),
+		UpdatedBy:      c.GetString("admin_actor"),
+	}
+	if req.RolloutPercent != nil {
+		flag.RolloutPercent = *req.RolloutPercent
+	}
+
+	details := fmt.Sprintf("flag=%s enabled=%t rollout_percent=%d",
+		sanitizeInput(flag.Name, 128), flag.Enabled, flag.RolloutPercent)
+	if !s.audit(c, models.AuditActionSetFeatureFlag, "", "", details) {
+		return
+	}
+
+	if err := s.featureFlags.Set(c.Request.Context(), flag); err != nil {
+		var invalidErr *featureflags.InvalidFlagError
+		if errors.As(err, &invalidErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to set feature flag",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// handleAdminResetFeatureFlag deletes a stored flag so it reverts to its default.
+func (s *Server) handleAdminResetFeatureFlag(c *gin.Context) {
+	if !s.requireFeatureFlags(c) {
+		return
+	}
+
+	name := c.Param("name")
+	details := "flag=" + sanitizeInput(name, 128)
+	if !s.audit(c, models.AuditActionResetFeatureFlag, "", "", details) {
+		return
+	}
+
+	if err := s.featureFlags.Delete(c.Request.Context(), name); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "feature flag not found: " + sanitizeInput(name, 128),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to reset feature flag",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "feature flag reset to default",
+		"name":    name,
+	})
+}
+
+// requireFeatureFlags writes a 503 and returns false when feature flags are not configured.
+func (s *Server) requireFeatureFlags(c *gin.Context) bool {
+	if s.featureFlags == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "feature flags not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+	return true
+}
+
+// handleAdminExportSessions exports sessions with their status history, cost
+// records and encrypted SSH keys.
+func (s *Server) handleAdminExportSessions(c *gin.Context) {
+	if !s.requireSessionExport(c) {
+		return
+	}
+
+	var req ExportSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	details := fmt.Sprintf("session_ids=%d", len(req.SessionIDs))
+	if !s.audit(c, models.AuditActionExportSessions, sanitizeInput(req.ConsumerID, 128), "", details) {
+		return
+	}
+
+	sel := sessionexport.Selection{SessionIDs: req.SessionIDs, ConsumerID: req.ConsumerID}
+	bundle, err := s.sessionExport.Export(c.Request.Context(), sel, req.Passphrase)
+	if err != nil {
+		var invalidErr *sessionexport.InvalidRequestError
+		if errors.As(err, &invalidErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to export sessions",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// handleAdminImportSessions imports an export bundle. Existing sessions are
+// skipped; active sessions are imported as stopped unless take_over is set.
+func (s *Server) handleAdminImportSessions(c *gin.Context) {
+	if !s.requireSessionExport(c) {
+		return
+	}
+
+	var req ImportSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	details := fmt.Sprintf("source_deployment_id=%s sessions=%d take_over=%t",
+		sanitizeInput(req.Export.DeploymentID, 128), len(req.Export.Sessions), req.TakeOver)
+	if !s.audit(c, models.AuditActionImportSessions, "", "", details) {
+		return
+	}
+
+	result, err := s.sessionExport.Import(c.Request.Context(), req.Export, req.Passphrase, req.TakeOver)
+	if err != nil {
+		var invalidErr *sessionexport.InvalidRequestError
+		switch {
+		case errors.Is(err, sessionexport.ErrWrongPassphrase), errors.As(err, &invalidErr):
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+		default:
+			// Sessions imported before the failure stay imported; re-running
+			// the import skips them
+			imported := 0
+			if result != nil {
+				imported = len(result.Imported)
+			}
+			s.logger.Error("session import failed",
+				slog.String("error", err.Error()),
+				slog.Int("imported", imported))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:     "failed to import sessions",
+				RequestID: c.GetString("request_id"),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// requireSessionExport writes a 503 and returns false when session export is not configured.
+func (s *Server) requireSessionExport(c *gin.Context) bool {
+	if s.sessionExport == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "session export not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+	return true
+}
+
+// handleAdminReconcile runs a reconciler sweep immediately instead of waiting
+// for the next scheduled pass.
+func (s *Server) handleAdminReconcile(c *gin.Context) {
+	if s.reconciler == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "reconciler not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	if !s.audit(c, models.AuditActionReconcile, "", "", "") {
+		return
+	}
+
+	s.reconciler.RunReconciliation(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "reconciliation complete",
+		"request_id": c.GetString("request_id"),
+	})
+}
+
+// handleAdminAuditTerminations lists every provider instance with the
+// reasons it might never be terminated. It is read-only.
+func (s *Server) handleAdminAuditTerminations(c *gin.Context) {
+	if s.reconciler == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "reconciler not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.reconciler.AuditTerminations(c.Request.Context()))
+}
+
+// requireInstanceClaimer reports 503 when instance claims are unavailable
+func (s *Server) requireInstanceClaimer(c *gin.Context) bool {
+	if s.instanceClaimer == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "instance claims not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+	return true
+}
+
+// claimErrorStatus maps an instance claim error to an HTTP status
+func claimErrorStatus(err error) int {
+	var notFound *lifecycle.InstanceNotFoundError
+	if errors.As(err, &notFound) {
+		return http.StatusNotFound
+	}
+	var claimed *lifecycle.InstanceClaimedError
+	if errors.As(err, &claimed) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// handleAdminListUnclaimed lists shopper-labeled provider instances that no
+// session owns, whichever deployment made them. It is read-only.
+func (s *Server) handleAdminListUnclaimed(c *gin.Context) {
+	if !s.requireInstanceClaimer(c) {
+		return
+	}
+
+	c.JSON(http.StatusOK, s.instanceClaimer.ListUnclaimed(c.Request.Context()))
+}
+
+// handleAdminAdoptInstance adopts an unclaimed instance into this deployment
+// by creating a running session for it on behalf of a consumer.
+func (s *Server) handleAdminAdoptInstance(c *gin.Context) {
+	if !s.requireInstanceClaimer(c) {
+		return
+	}
+
+	var req AdoptInstanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	providerName, instanceID := c.Param("provider"), c.Param("instance_id")
+	details := fmt.Sprintf("provider=%s instance=%s", sanitizeInput(providerName, 64), sanitizeInput(instanceID, 128))
+	if !s.audit(c, models.AuditActionAdoptInstance, req.ConsumerID, "", details) {
+		return
+	}
+
+	session, err := s.instanceClaimer.AdoptInstance(c.Request.Context(), providerName, instanceID, req.ConsumerID, req.ReservationHours)
+	if err != nil {
+		c.JSON(claimErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// handleAdminReleaseInstance releases an unclaimed instance, so the
+// reconciler never destroys it as an orphan.
+func (s *Server) handleAdminReleaseInstance(c *gin.Context) {
+	if !s.requireInstanceClaimer(c) {
+		return
+	}
+
+	providerName, instanceID := c.Param("provider"), c.Param("instance_id")
+	details := fmt.Sprintf("provider=%s instance=%s", sanitizeInput(providerName, 64), sanitizeInput(instanceID, 128))
+	if !s.audit(c, models.AuditActionReleaseInstance, "", "", details) {
+		return
+	}
+
+	release, err := s.instanceClaimer.ReleaseInstance(c.Request.Context(), providerName, instanceID, c.GetString("admin_actor"))
+	if err != nil {
+		c.JSON(claimErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+// handleAdminUnreleaseInstance drops the release of an instance, so the
+// reconciler treats it as any other again.
+func (s *Server) handleAdminUnreleaseInstance(c *gin.Context) {
+	if !s.requireInstanceClaimer(c) {
+		return
+	}
+
+	providerName, instanceID := c.Param("provider"), c.Param("instance_id")
+	details := fmt.Sprintf("provider=%s instance=%s", sanitizeInput(providerName, 64), sanitizeInput(instanceID, 128))
+	if !s.audit(c, models.AuditActionUnreleaseInstance, "", "", details) {
+		return
+	}
+
+	if err := s.instanceClaimer.UnreleaseInstance(c.Request.Context(), providerName, instanceID); err != nil {
+		c.JSON(claimErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "instance release dropped",
+		"provider":    providerName,
+		"instance_id": instanceID,
+	})
+}
+
+// handleAdminListQuarantine lists instances that could not be destroyed and
+// await an operator. It is read-only.
+func (s *Server) handleAdminListQuarantine(c *gin.Context) {
+	if s.quarantine == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "quarantine not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	instances, err := s.quarantine.ListQuarantined(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list quarantined instances",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if instances == nil {
+		instances = []*models.QuarantinedInstance{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"instances": instances,
+		"count":     len(instances),
+	})
+}
+
+// handleAdminResolveQuarantine takes an instance out of quarantine once an
+// operator has dealt with it. Nothing is done to the instance itself.
+func (s *Server) handleAdminResolveQuarantine(c *gin.Context) {
+	if s.quarantine == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "quarantine not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	providerName, instanceID := c.Param("provider"), c.Param("instance_id")
+	details := fmt.Sprintf("provider=%s instance=%s", sanitizeInput(providerName, 64), sanitizeInput(instanceID, 128))
+	if !s.audit(c, models.AuditActionResolveQuarantine, "", "", details) {
+		return
+	}
+
+	if err := s.quarantine.Resolve(c.Request.Context(), providerName, instanceID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "instance not quarantined",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to resolve quarantined instance",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "instance resolved",
+		"provider":    providerName,
+		"instance_id": instanceID,
+	})
+}
+
+// handleAdminSpendStatus reports estimated against provider-reported spend
+// for the month and whether the spend ceiling has tripped. It is read-only.
+func (s *Server) handleAdminSpendStatus(c *gin.Context) {
+	if s.spendGuard == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "spend guard not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.spendGuard.Status())
+}
+
+// handleAdminAddCatalogModel adds or replaces a custom model in the benchmark
+// catalog. It applies to runs started afterwards.
+func (s *Server) handleAdminAddCatalogModel(c *gin.Context) {
+	if !s.requireBenchmarkCatalog(c) {
+		return
+	}
+
+	var req CatalogModelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	details := fmt.Sprintf("model=%s workload=%s min_vram_gb=%d",
+		sanitizeInput(req.Name, 128), sanitizeInput(req.Workload, 32), req.MinVRAMGB)
+	if !s.audit(c, models.AuditActionAddCatalogModel, "", "", details) {
+		return
+	}
+
+	model, err := s.benchmarkCatalog.AddModel(c.Request.Context(), benchmark.CatalogModel{
+		Name:        req.Name,
+		Workload:    benchmark.Workload(req.Workload),
+		MinVRAMGB:   req.MinVRAMGB,
+		Description: sanitizeInput(req.Description, 512),
+	})
+	if err != nil {
+		s.writeCatalogError(c, err, "failed to add catalog model")
+		return
+	}
+
+	c.JSON(http.StatusOK, model)
+}
+
+// handleAdminRemoveCatalogModel deletes a custom model. A built-in or file
+// entry of the same name applies again.
+func (s *Server) handleAdminRemoveCatalogModel(c *gin.Context) {
+	if !s.requireBenchmarkCatalog(c) {
+		return
+	}
+
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	if !s.audit(c, models.AuditActionRemoveCatalogModel, "", "", "model="+sanitizeInput(name, 128)) {
+		return
+	}
+
+	if err := s.benchmarkCatalog.RemoveModel(c.Request.Context(), name); err != nil {
+		s.writeCatalogError(c, err, "failed to remove catalog model")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "catalog model removed",
+		"name":    name,
+	})
+}
+
+// handleAdminAddCatalogGPU adds or replaces a custom GPU type in the
+// benchmark catalog
+func (s *Server) handleAdminAddCatalogGPU(c *gin.Context) {
+	if !s.requireBenchmarkCatalog(c) {
+		return
+	}
+
+	var req CatalogGPURequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	details := fmt.Sprintf("gpu=%s vram_gb=%d", sanitizeInput(req.Name, 64), req.VRAMGB)
+	if !s.audit(c, models.AuditActionAddCatalogGPU, "", "", details) {
+		return
+	}
+
+	gpu, err := s.benchmarkCatalog.AddGPU(c.Request.Context(), benchmark.CatalogGPU{
+		Name:   req.Name,
+		VRAMGB: req.VRAMGB,
+	})
+	if err != nil {
+		s.writeCatalogError(c, err, "failed to add catalog GPU")
+		return
+	}
+
+	c.JSON(http.StatusOK, gpu)
+}
+
+// handleAdminRemoveCatalogGPU deletes a custom GPU type
+func (s *Server) handleAdminRemoveCatalogGPU(c *gin.Context) {
+	if !s.requireBenchmarkCatalog(c) {
+		return
+	}
+
+	name := c.Param("name")
+	if !s.audit(c, models.AuditActionRemoveCatalogGPU, "", "", "gpu="+sanitizeInput(name, 64)) {
+		return
+	}
+
+	if err := s.benchmarkCatalog.RemoveGPU(c.Request.Context(), name); err != nil {
+		s.writeCatalogError(c, err, "failed to remove catalog GPU")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "catalog GPU removed",
+		"name":    name,
+	})
+}
+
+// writeCatalogError maps catalog errors to 400 and 404, and anything else
+// to a 500 with msg
+func (s *Server) writeCatalogError(c *gin.Context, err error, msg string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, benchmark.ErrInvalidCatalogEntry):
+		status, msg = http.StatusBadRequest, err.Error()
+	case errors.Is(err, benchmark.ErrCatalogEntryNotFound):
+		status, msg = http.StatusNotFound, err.Error()
+	}
+	c.JSON(status, ErrorResponse{
+		Error:     msg,
+		RequestID: c.GetString("request_id"),
+	})
+}
+
+// handleAdminListOfferSuppressions lists offers and hosts suppressed by
+// failure tracking together with the operator suppress/allow rules
+func (s *Server) handleAdminListOfferSuppressions(c *gin.Context) {
+	suppressed := s.inventory.ListSuppressedOffers()
+	hosts := s.inventory.ListSuppressedHosts()
+	rules := s.inventory.ListOfferRules()
+
+	c.JSON(http.StatusOK, gin.H{
+		"suppressed": suppressed,
+		"hosts":      hosts,
+		"rules":      rules,
+		"count":      len(suppressed) + len(hosts) + len(rules),
+	})
+}
+
+// handleAdminClearOfferSuppression lifts an automatic suppression and resets
+// the offer's failure history
+func (s *Server) handleAdminClearOfferSuppression(c *gin.Context) {
+	offerID := c.Param("offer_id")
+	if !s.audit(c, models.AuditActionClearSuppression, "", "", "offer="+sanitizeInput(offerID, 128)) {
+		return
+	}
+
+	if err := s.inventory.ClearOfferSuppression(c.Request.Context(), offerID); err != nil {
+		var notFound *inventory.OfferNotFoundError
+		if errors.As(err, &notFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "no failures tracked for offer: " + sanitizeInput(offerID, 128),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to clear offer suppression",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "offer suppression cleared",
+		"offer_id": offerID,
+	})
+}
+
+// handleAdminSetOfferRule suppresses or allowlists offers by offer ID, host
+// or location, replacing any rule for the same target. Changes take effect
+// on the next inventory query.
+func (s *Server) handleAdminSetOfferRule(c *gin.Context) {
+	var req OfferRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	reason := sanitizeInput(req.Reason, 512)
+	if reason == "" {
+		reason = sanitizeInput(c.GetHeader("X-Admin-Reason"), 512)
+	}
+	rule := &models.OfferRule{
+		Action:    models.OfferRuleAction(req.Action),
+		Scope:     models.OfferRuleScope(req.Scope),
+		Value:     sanitizeInput(req.Value, 256),
+		Provider:  sanitizeInput(req.Provider, 64),
+		Reason:    reason,
+		CreatedBy: c.GetString("admin_actor"),
+	}
+
+	details := fmt.Sprintf("action=%s scope=%s value=%s provider=%s duration_minutes=%d",
+		rule.Action, rule.Scope, rule.Value, rule.Provider, req.DurationMinutes)
+	if !s.audit(c, models.AuditActionSetOfferRule, "", "", details) {
+		return
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	if err := s.inventory.SetOfferRule(c.Request.Context(), rule, duration); err != nil {
+		var invalidErr *inventory.InvalidOfferRuleError
+		if errors.As(err, &invalidErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to set offer rule",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// handleAdminDeleteOfferRule removes an operator suppress/allow rule
+func (s *Server) handleAdminDeleteOfferRule(c *gin.Context) {
+	id := c.Param("id")
+	if !s.audit(c, models.AuditActionDeleteOfferRule, "", "", "rule="+sanitizeInput(id, 64)) {
+		return
+	}
+
+	if err := s.inventory.DeleteOfferRule(c.Request.Context(), id); err != nil {
+		if errors.Is(err, inventory.ErrOfferRuleNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "offer rule not found: " + sanitizeInput(id, 64),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to delete offer rule",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "offer rule deleted",
+		"id":      id,
+	})
+}
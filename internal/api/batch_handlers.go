@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// maxBatchAttemptsFactor bounds how many offers a batch may try per session
+// requested before giving up on the rest
+const maxBatchAttemptsFactor = 3
+
+// BatchCreateSessionsRequest provisions Count sessions on the cheapest offers
+// matching Filter, each configured from the embedded session spec.
+type BatchCreateSessionsRequest struct {
+	Count  int                `json:"count" binding:"required,min=1,max=16"`
+	Filter models.OfferFilter `json:"filter"`
+	SessionSpec
+}
+
+// BatchSessionResult is the outcome of provisioning one offer in a batch
+type BatchSessionResult struct {
+	OfferID       string                  `json:"offer_id"`
+	Provider      string                  `json:"provider"`
+	PricePerHour  float64                 `json:"price_per_hour"`
+	Session       *models.SessionResponse `json:"session,omitempty"`
+	SSHPrivateKey string                  `json:"ssh_private_key,omitempty"`
+	Error         string                  `json:"error,omitempty"`
+	ErrorType     string                  `json:"error_type,omitempty"`
+}
+
+// BatchCreateSessionsResponse reports every offer tried. Sessions that were
+// created share GroupID so they can be managed together.
+type BatchCreateSessionsResponse struct {
+	GroupID     string               `json:"group_id"`
+	Requested   int                  `json:"requested"`
+	Provisioned int                  `json:"provisioned"`
+	Results     []BatchSessionResult `json:"results"`
+}
+
+// handleBatchCreateSessions provisions several sessions from one filter.
+// Offers are tried cheapest first; an offer that fails is recorded and the
+// next one is tried. Sessions already created are kept if the batch falls
+// short, so the response may be a partial success (207).
+func (s *Server) handleBatchCreateSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req BatchCreateSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	if msg := validateSessionSpec(req.SessionSpec); msg != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     msg,
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if !s.allowSessionSecrets(c, req.SessionSpec) {
+		return
+	}
+
+	// A batch counts as one creation per session against the rate limit
+	if !s.allowRequest(c, s.createSessionLimiter, "create_session", req.Count) {
+		return
+	}
+
+	req.Filter.AllowedRegions = s.provisioner.AllowedRegions(req.ConsumerID)
+	offers, err := s.inventory.ListOffers(ctx, req.Filter)
+	if err != nil {
+		s.logger.Error("failed to list offers for batch",
+			slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list offers",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	candidates := make([]models.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if offer.Available {
+			candidates = append(candidates, offer)
+		}
+	}
+	if len(candidates) == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:     "no available offers match the filter",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].PricePerHour < candidates[j].PricePerHour
+	})
+
+	// Join the caller's group if given, otherwise start a new one
+	groupID := req.GroupID
+	if groupID == "" {
+		groupID = uuid.New().String()
+	}
+	resp := BatchCreateSessionsResponse{
+		GroupID:   groupID,
+		Requested: req.Count,
+	}
+
+	maxAttempts := req.Count * maxBatchAttemptsFactor
+	for i := range candidates {
+		if resp.Provisioned == req.Count || len(resp.Results) == maxAttempts {
+			break
+		}
+		offer := &candidates[i]
+
+		createReq := s.buildCreateRequest(ctx, req.SessionSpec, offer.ID)
+		createReq.GroupID = groupID
+
+		result := BatchSessionResult{
+			OfferID:      offer.ID,
+			Provider:     offer.Provider,
+			PricePerHour: offer.PricePerHour,
+		}
+
+		session, err := s.provisioner.CreateSession(ctx, createReq, offer)
+		if err != nil {
+			_, body := provisionErrorResponse(err, "")
+			result.Error = err.Error()
+			result.ErrorType, _ = body["error_type"].(string)
+			resp.Results = append(resp.Results, result)
+
+			// Budget and balance limits apply to every offer, so stop early
+			if stopsBatch(err) {
+				break
+			}
+			continue
+		}
+
+		sessionResp := session.ToResponse()
+		result.Session = &sessionResp
+		result.SSHPrivateKey = session.SSHPrivateKey
+		resp.Results = append(resp.Results, result)
+		resp.Provisioned++
+	}
+
+	s.logger.Info("batch provisioning complete",
+		slog.String("group_id", resp.GroupID),
+		slog.String("consumer_id", req.ConsumerID),
+		slog.Int("requested", resp.Requested),
+		slog.Int("provisioned", resp.Provisioned),
+		slog.Int("attempted", len(resp.Results)))
+
+	status := http.StatusCreated
+	if resp.Provisioned < resp.Requested {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
+}
+
+// stopsBatch reports whether a provisioning error would repeat for every
+// remaining offer in a batch
+func stopsBatch(err error) bool {
+	var budgetErr *budget.ExceededError
+	var balanceErr *provisioner.InsufficientBalanceError
+	return errors.As(err, &budgetErr) || errors.As(err, &balanceErr)
+}
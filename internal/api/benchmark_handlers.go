@@ -1,8 +1,12 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -218,6 +222,39 @@ func (s *Server) handleGetCheapestBenchmark(c *gin.Context) {
 	})
 }
 
+// handleListBenchmarkWorkloads returns the workloads endpoint runs can
+// measure, with the unit each reports throughput in
+func (s *Server) handleListBenchmarkWorkloads(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"workloads": benchmark.Workloads,
+		"count":     len(benchmark.Workloads),
+	})
+}
+
+// handleGetBenchmarkCatalog returns the models and GPUs benchmark runs are
+// sized against
+func (s *Server) handleGetBenchmarkCatalog(c *gin.Context) {
+	if !s.requireBenchmarkCatalog(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"models": s.benchmarkCatalog.Models(),
+		"gpus":   s.benchmarkCatalog.GPUs(),
+	})
+}
+
+// requireBenchmarkCatalog writes a 503 and returns false when the model catalog is not configured.
+func (s *Server) requireBenchmarkCatalog(c *gin.Context) bool {
+	if s.benchmarkCatalog == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "benchmark catalog not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+	return true
+}
+
 // handleGetHardwareRecommendations returns hardware recommendations for a model
 func (s *Server) handleGetHardwareRecommendations(c *gin.Context) {
 	if s.benchmarkStore == nil {
@@ -372,6 +409,148 @@ func (s *Server) handleCompareBenchmarks(c *gin.Context) {
 	c.JSON(http.StatusOK, comparison)
 }
 
+// handleBenchmarkHistory lists past benchmark runs with per-run throughput,
+// latency and cost, and each run's throughput trend against the previous run
+// of the same model on the same GPU
+func (s *Server) handleBenchmarkHistory(c *gin.Context) {
+	if s.benchmarkStore == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "benchmark service not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	filter, ok := s.bindHistoryFilter(c, 50)
+	if !ok {
+		return
+	}
+
+	history, err := s.benchmarkStore.ListHistory(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to fetch benchmark history: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+		"count":   len(history),
+	})
+}
+
+// handleBenchmarkReport renders a report over the runs matching the same
+// filters as the history endpoint, as markdown (default), JSON or HTML
+func (s *Server) handleBenchmarkReport(c *gin.Context) {
+	if s.benchmarkStore == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "benchmark service not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	format, err := benchmark.ParseReportFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	// Reports cover every matching run unless limited explicitly
+	filter, ok := s.bindHistoryFilter(c, 0)
+	if !ok {
+		return
+	}
+
+	report, err := s.benchmarkStore.GenerateReport(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to generate benchmark report: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, format); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to render benchmark report: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// bindHistoryFilter reads the model, gpu, provider, since, until and limit
+// query parameters, writing a 400 response if any is invalid. A zero
+// defaultLimit leaves the result unlimited when no limit is given.
+func (s *Server) bindHistoryFilter(c *gin.Context, defaultLimit int) (benchmark.HistoryFilter, bool) {
+	var query BenchmarkQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid query parameters: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return benchmark.HistoryFilter{}, false
+	}
+
+	filter := benchmark.HistoryFilter{
+		Model:    query.Model,
+		GPU:      query.GPU,
+		Provider: query.Provider,
+		Limit:    query.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+	if defaultLimit > 0 && filter.Limit > 200 {
+		filter.Limit = 200
+	}
+
+	var err error
+	if filter.Since, err = parseHistoryTime(c.Query("since"), false); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid since, expected YYYY-MM-DD or RFC3339: " + sanitizeInput(c.Query("since"), 64),
+			RequestID: c.GetString("request_id"),
+		})
+		return benchmark.HistoryFilter{}, false
+	}
+	if filter.Until, err = parseHistoryTime(c.Query("until"), true); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid until, expected YYYY-MM-DD or RFC3339: " + sanitizeInput(c.Query("until"), 64),
+			RequestID: c.GetString("request_id"),
+		})
+		return benchmark.HistoryFilter{}, false
+	}
+	return filter, true
+}
+
+// parseHistoryTime accepts RFC3339 or YYYY-MM-DD. A bare date used as an
+// upper bound includes the whole day.
+func parseHistoryTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
 // ── Benchmark Runs ──────────────────────────────────────────────────────────
 
 // handleStartBenchmarkRun starts a new benchmark run.
@@ -393,15 +572,22 @@ func (s *Server) handleStartBenchmarkRun(c *gin.Context) {
 		return
 	}
 
-	if len(req.Models) == 0 {
+	if err := req.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:     "at least one model is required",
+			Error:     err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
 	run, err := s.benchmarkRunner.StartRun(c.Request.Context(), req)
+	if errors.Is(err, benchsvc.ErrEndpointUnavailable) || errors.Is(err, benchsvc.ErrModelTooLarge) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:     "failed to start benchmark run: " + err.Error(),
@@ -443,6 +629,80 @@ func (s *Server) handleGetBenchmarkRun(c *gin.Context) {
 	})
 }
 
+// benchmarkStreamKeepalive is how long a run stream may stay silent before
+// a comment line keeps proxies from closing it
+const benchmarkStreamKeepalive = 15 * time.Second
+
+// handleStreamBenchmarkRun streams a run's progress as server-sent events:
+// a "progress" event with the run and its entries whenever they change, and
+// a final "done" event once the run has finished, failed or been cancelled.
+func (s *Server) handleStreamBenchmarkRun(c *gin.Context) {
+	if s.benchmarkRunner == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "benchmark runner not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	runID := c.Param("id")
+	ctx := c.Request.Context()
+	if _, err := s.benchmarkRunner.GetRun(ctx, runID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:     "run not found: " + sanitizeInput(runID, 128),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	// Runs outlive the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(s.benchmarkStreamInterval)
+	defer ticker.Stop()
+	var last []byte
+	lastWrite := time.Now()
+	for {
+		run, err := s.benchmarkRunner.GetRun(ctx, runID)
+		if err != nil {
+			return
+		}
+		entries, _ := s.benchmarkRunner.GetRunEntries(ctx, runID)
+		progress := gin.H{"run": run, "entries": entries}
+
+		// UpdatedAt alone does not count as progress
+		updatedAt := run.UpdatedAt
+		run.UpdatedAt = time.Time{}
+		current, _ := json.Marshal(progress)
+		run.UpdatedAt = updatedAt
+		if !bytes.Equal(current, last) {
+			last = current
+			c.SSEvent("progress", progress)
+			lastWrite = time.Now()
+		} else if time.Since(lastWrite) >= benchmarkStreamKeepalive {
+			c.Writer.WriteString(": keepalive\n\n")
+			lastWrite = time.Now()
+		}
+
+		switch run.Status {
+		case benchsvc.RunStatusCompleted, benchsvc.RunStatusFailed, benchsvc.RunStatusCancelled:
+			c.SSEvent("done", gin.H{"run_id": run.ID, "status": run.Status})
+			c.Writer.Flush()
+			return
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // handleCancelBenchmarkRun cancels a running benchmark.
 func (s *Server) handleCancelBenchmarkRun(c *gin.Context) {
 	if s.benchmarkRunner == nil {
@@ -488,9 +748,9 @@ func (s *Server) handleCreateBenchmarkSchedule(c *gin.Context) {
 		return
 	}
 
-	if sched.Name == "" || sched.CronExpr == "" {
+	if err := sched.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:     "name and cron expression are required",
+			Error:     "invalid schedule: " + err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
@@ -582,6 +842,13 @@ func (s *Server) handleUpdateBenchmarkSchedule(c *gin.Context) {
 	if update.Enabled != nil {
 		existing.Enabled = *update.Enabled
 	}
+	if err := existing.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid schedule: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 
 	if err := store.Update(c.Request.Context(), existing); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
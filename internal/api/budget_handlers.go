@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// SetBudgetRequest is the request body for creating or updating a budget
+type SetBudgetRequest struct {
+	Scope    models.BudgetScope  `json:"scope" binding:"required"`
+	ScopeID  string              `json:"scope_id"` // Optional for deployment budgets
+	Period   models.BudgetPeriod `json:"period" binding:"required"`
+	LimitUSD float64             `json:"limit_usd" binding:"required"`
+}
+
+// handleSetBudget creates a budget, or updates the limit of the existing
+// budget with the same scope, scope ID and period.
+func (s *Server) handleSetBudget(c *gin.Context) {
+	if s.budgetService == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "budget service not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	var req SetBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid request: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	b := &models.Budget{
+		Scope:    req.Scope,
+		ScopeID:  req.ScopeID,
+		Period:   req.Period,
+		LimitUSD: req.LimitUSD,
+	}
+	if err := s.budgetService.SetBudget(c.Request.Context(), b); err != nil {
+		var invalidErr *budget.InvalidBudgetError
+		if errors.As(err, &invalidErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to set budget: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"budget": b})
+}
+
+// handleListBudgets lists all budgets.
+func (s *Server) handleListBudgets(c *gin.Context) {
+	if s.budgetService == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "budget service not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	budgets, err := s.budgetService.ListBudgets(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list budgets: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if budgets == nil {
+		budgets = []*models.Budget{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budgets": budgets,
+		"count":   len(budgets),
+	})
+}
+
+// handleGetBudgetStatus reports spend against every budget for the current period.
+func (s *Server) handleGetBudgetStatus(c *gin.Context) {
+	if s.budgetService == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "budget service not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	statuses, err := s.budgetService.GetStatus(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get budget status: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budgets": statuses,
+		"count":   len(statuses),
+	})
+}
+
+// handleDeleteBudget deletes a budget.
+func (s *Server) handleDeleteBudget(c *gin.Context) {
+	if s.budgetService == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "budget service not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	id := c.Param("id")
+	if err := s.budgetService.DeleteBudget(c.Request.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "budget not found: " + sanitizeInput(id, 128),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to delete budget: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
@@ -0,0 +1,161 @@
+// Operator dashboard. Everything shown comes from /api/v1 with the API key
+// entered in the page, so the dashboard can see and do exactly what the key
+// is allowed to.
+"use strict";
+
+const refreshInterval = 15000;
+const activeStatuses = ["pending", "provisioning", "running", "paused", "stopping"];
+
+const keyInput = document.getElementById("api-key");
+keyInput.value = sessionStorage.getItem("apiKey") || "";
+
+document.getElementById("auth").addEventListener("submit", (event) => {
+  event.preventDefault();
+  sessionStorage.setItem("apiKey", keyInput.value.trim());
+  refresh();
+});
+
+async function api(method, path) {
+  const headers = {};
+  const key = sessionStorage.getItem("apiKey");
+  if (key) {
+    headers["Authorization"] = "Bearer " + key;
+  }
+  const resp = await fetch("/api/v1" + path, { method, headers });
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    throw new Error(body.error || method + " " + path + ": " + resp.status);
+  }
+  return body;
+}
+
+function money(value) {
+  return "$" + (value || 0).toFixed(2);
+}
+
+function time(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+// row builds a table row. Cells are set as text, never HTML, since session
+// and offer fields come from consumers and providers.
+function row(cells) {
+  const tr = document.createElement("tr");
+  for (const cell of cells) {
+    const td = document.createElement("td");
+    if (cell instanceof Node) {
+      td.appendChild(cell);
+    } else {
+      td.textContent = cell === undefined || cell === null ? "" : String(cell);
+    }
+    tr.appendChild(td);
+  }
+  return tr;
+}
+
+function fill(id, rows, columns, emptyText) {
+  const tbody = document.getElementById(id);
+  tbody.replaceChildren(...rows);
+  if (rows.length === 0) {
+    const td = document.createElement("td");
+    td.colSpan = columns;
+    td.className = "empty";
+    td.textContent = emptyText;
+    tbody.appendChild(document.createElement("tr")).appendChild(td);
+  }
+}
+
+function setText(id, text) {
+  document.getElementById(id).textContent = text;
+}
+
+function destroyButton(session) {
+  const button = document.createElement("button");
+  button.className = "destroy";
+  button.textContent = "Destroy";
+  button.addEventListener("click", async () => {
+    if (!confirm("Destroy session " + session.id + " (" + session.gpu_type + " on " + session.provider + ")?")) {
+      return;
+    }
+    button.disabled = true;
+    try {
+      await api("DELETE", "/sessions/" + encodeURIComponent(session.id));
+      refresh();
+    } catch (err) {
+      showError(err);
+      button.disabled = false;
+    }
+  });
+  return button;
+}
+
+async function loadActiveSessions() {
+  const lists = await Promise.all(activeStatuses.map((status) => api("GET", "/sessions?status=" + status)));
+  renderSessions(lists.flatMap((data) => data.sessions || []));
+}
+
+function renderSessions(active) {
+  setText("active-count", active.length);
+  fill("sessions", active.map((s) => {
+    const tr = row([
+      s.id, s.consumer_id, s.provider, s.gpu_count + "x " + s.gpu_type, s.status,
+      s.health ? s.health.status : "", money(s.price_per_hour), time(s.expires_at),
+      s.status === "stopping" ? "" : destroyButton(s),
+    ]);
+    if (s.health && s.health.status === "degraded") {
+      tr.className = "degraded";
+    }
+    return tr;
+  }), 9, "No active sessions");
+}
+
+function renderFailures(data) {
+  fill("failures", (data.sessions || []).map((s) => row([
+    s.id, s.provider, s.gpu_type, s.failure_category || "unknown", s.error, time(s.created_at),
+  ])), 6, "No failed sessions");
+}
+
+function renderForecast(data) {
+  setText("burn-rate", money(data.burn_rate_per_hour) + "/h");
+  setText("spent-today", money(data.spent_today));
+  setText("projected-today", money(data.projected_today));
+}
+
+function renderFailureRate(data) {
+  setText("failure-rate", (data.failure_rate * 100).toFixed(1) + "% of " + data.sessions);
+}
+
+function renderInventory(data) {
+  fill("inventory", (data.offers || []).map((o) => row([
+    o.provider, o.gpu_type, o.gpu_count, o.vram_gb + " GB", o.location,
+    money(o.price_per_hour), Math.round((o.availability_confidence || 0) * 100) + "%",
+  ])), 7, "No offers");
+}
+
+function showError(err) {
+  const el = document.getElementById("error");
+  el.textContent = err.message;
+  el.hidden = false;
+}
+
+async function refresh() {
+  document.getElementById("error").hidden = true;
+  // Panels load independently: a missing optional feature (analytics, cost
+  // tracking) leaves its panel empty rather than blanking the page.
+  const panels = [
+    loadActiveSessions(),
+    api("GET", "/sessions?status=failed&limit=10").then(renderFailures),
+    api("GET", "/costs/forecast").then(renderForecast),
+    api("GET", "/analytics/failures").then(renderFailureRate),
+    api("GET", "/inventory?limit=15").then(renderInventory),
+  ];
+  const results = await Promise.allSettled(panels);
+  const failed = results.find((r) => r.status === "rejected");
+  if (failed) {
+    showError(failed.reason);
+  }
+  setText("updated", "Updated " + new Date().toLocaleTimeString());
+}
+
+refresh();
+setInterval(refresh, refreshInterval);
@@ -1,11 +1,17 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"net/http"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,6 +21,7 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/lifecycle"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
@@ -32,18 +39,104 @@ type ErrorResponse struct {
 
 // HealthResponse is the health check response
 type HealthResponse struct {
-	Status    string            `json:"status"`
-	Timestamp time.Time         `json:"timestamp"`
-	Services  map[string]string `json:"services,omitempty"`
+	Status    string                         `json:"status"`
+	Timestamp time.Time                      `json:"timestamp"`
+	Services  map[string]string              `json:"services,omitempty"`
+	Inventory map[string]ProviderCacheHealth `json:"inventory,omitempty"` // By provider name
 }
 
-// CreateSessionRequest is the request to create a new session
+// ProviderCacheHealth is the state of a provider's cached offer catalogue
+type ProviderCacheHealth struct {
+	Cached       bool       `json:"cached"`
+	FetchedAt    *time.Time `json:"fetched_at,omitempty"`
+	AgeSeconds   float64    `json:"age_seconds"`
+	OfferCount   int        `json:"offer_count"`
+	Stale        bool       `json:"stale"`         // Older than the staleness threshold
+	ServingStale bool       `json:"serving_stale"` // Provider is failing; its last good offers are served
+	Error        bool       `json:"error"`         // Provider is failing and has no offers to serve
+}
+
+// StaleInventoryHeader lists the providers whose offers in an inventory
+// response are the last good offers served while the provider is failing
+const StaleInventoryHeader = "X-Inventory-Stale"
+
+// CreateSessionRequest is the request to create a new session, either on
+// a concrete offer or on the best offer matching Offer
 type CreateSessionRequest struct {
+	OfferID string     `json:"offer_id" binding:"required_without=Offer"`
+	Offer   *OfferSpec `json:"offer,omitempty"`
+	SessionSpec
+
+	// Queue the request instead of failing if the offer is gone
+	Queue *QueueOptions `json:"queue,omitempty"`
+}
+
+// QueueOptions describes the offers a queued request will accept and how
+// long it may wait for one
+type QueueOptions struct {
+	Filter         models.OfferFilter `json:"filter"`
+	MaxWaitMinutes int                `json:"max_wait_minutes,omitempty" binding:"omitempty,min=1,max=1440"`
+}
+
+// validDriverVersionRegex matches NVIDIA driver versions such as 535.104.05
+var validDriverVersionRegex = regexp.MustCompile(`^\d+(\.\d+){0,3}$`)
+
+// Cloud-init file permissions and owners, e.g. 0644 and ubuntu:ubuntu
+var (
+	validFileModeRegex  = regexp.MustCompile(`^0?[0-7]{3}$`)
+	validFileOwnerRegex = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}(:[a-z_][a-z0-9_-]{0,31})?$`)
+)
+
+// validHFTokenRegex keeps HuggingFace tokens to printable ASCII, so a token
+// cannot break out of the shell and environment it is passed through
+var validHFTokenRegex = regexp.MustCompile(`^[\x21-\x7e]{1,256}$`)
+
+// OfferSpec describes the GPU a session needs when the caller leaves offer
+// selection to the server
+type OfferSpec struct {
+	GPUType                   string  `json:"gpu_type,omitempty"`
+	MinVRAM                   int     `json:"min_vram,omitempty" binding:"omitempty,min=1"`
+	MaxPrice                  float64 `json:"max_price,omitempty" binding:"omitempty,gt=0"`
+	Region                    string  `json:"region,omitempty"` // Substring of the offer location
+	Provider                  string  `json:"provider,omitempty"`
+	MinGPUCount               int     `json:"min_gpu_count,omitempty" binding:"omitempty,min=1"`
+	MinAvailabilityConfidence float64 `json:"min_availability_confidence,omitempty" binding:"omitempty,gt=0,lte=1"`
+	Interruptible             bool    `json:"interruptible,omitempty"`
+	MinCUDAVersion            float64 `json:"min_cuda_version,omitempty" binding:"omitempty,gt=0"`
+	MinDriverVersion          string  `json:"min_driver_version,omitempty"`
+	MinInetDownMbps           float64 `json:"min_inet_down_mbps,omitempty" binding:"omitempty,gt=0"`
+	MinDiskBandwidthMBps      float64 `json:"min_disk_bw_mbps,omitempty" binding:"omitempty,gt=0"`
+
+	// Regions (e.g. "EU") or ISO country codes the offer must be in
+	Regions []string `json:"regions,omitempty"`
+}
+
+// Filter converts the spec into an inventory filter
+func (o OfferSpec) Filter() models.OfferFilter {
+	return models.OfferFilter{
+		Provider:                  o.Provider,
+		GPUType:                   o.GPUType,
+		MinVRAM:                   o.MinVRAM,
+		MaxPrice:                  o.MaxPrice,
+		Location:                  o.Region,
+		MinGPUCount:               o.MinGPUCount,
+		MinAvailabilityConfidence: o.MinAvailabilityConfidence,
+		Interruptible:             o.Interruptible,
+		MinCUDAVersion:            o.MinCUDAVersion,
+		MinDriverVersion:          o.MinDriverVersion,
+		MinInetDownMbps:           o.MinInetDownMbps,
+		MinDiskBandwidthMBps:      o.MinDiskBandwidthMBps,
+		Regions:                   o.Regions,
+	}
+}
+
+// SessionSpec is the session configuration shared by single and batch creation
+type SessionSpec struct {
 	ConsumerID     string `json:"consumer_id" binding:"required"`
-	OfferID        string `json:"offer_id" binding:"required"`
 	WorkloadType   string `json:"workload_type" binding:"required"`
 	ReservationHrs int    `json:"reservation_hours" binding:"required,min=1,max=12"`
-	IdleThreshold  int    `json:"idle_threshold_minutes,omitempty"`
+	IdleThreshold  int    `json:"idle_threshold_minutes,omitempty" binding:"min=0"`
+	IdleGPUUtilPct int    `json:"idle_gpu_util_pct,omitempty" binding:"min=0,max=100"` // Idle below this GPU utilization (default 5)
 	StoragePolicy  string `json:"storage_policy,omitempty"`
 
 	// Entrypoint mode configuration
@@ -69,6 +162,24 @@ type CreateSessionRequest struct {
 
 	// SSH timeout override
 	SSHTimeoutMinutes int `json:"ssh_timeout_minutes,omitempty"` // SSH verify timeout (1-30 min)
+
+	// Bid for interruptible offers in USD per hour (defaults to the offer's min_bid)
+	BidPrice float64 `json:"bid_price,omitempty" binding:"gte=0"`
+
+	// Session group to join; members are listed, destroyed and expired together
+	GroupID string `json:"group_id,omitempty"`
+
+	// Custom cloud-init run after the shopper's SSH key setup
+	CloudInit *models.CloudInitConfig `json:"cloud_init,omitempty"`
+
+	// Script run over SSH once the session is verified; output is recorded in the session events
+	BootstrapScript string `json:"bootstrap_script,omitempty"`
+
+	// Load-test the GPUs before the session turns running; unhealthy GPUs fail it
+	GPUBurnIn bool `json:"gpu_burn_in,omitempty"`
+
+	// HuggingFace token for gated and private models; never returned, and rejected without database encryption
+	HFToken string `json:"hf_token,omitempty"`
 }
 
 // ListTemplatesQuery defines query parameters for listing templates
@@ -84,6 +195,7 @@ type CreateSessionResponse struct {
 	Session          models.SessionResponse `json:"session"`
 	SSHPrivateKey    string                 `json:"ssh_private_key,omitempty"`
 	RetriesAttempted int                    `json:"retries_attempted,omitempty"` // Number of retries before success
+	SelectedOfferID  string                 `json:"selected_offer_id,omitempty"` // Offer chosen for an offer spec
 }
 
 // ExtendSessionRequest is the request to extend a session
@@ -91,12 +203,35 @@ type ExtendSessionRequest struct {
 	AdditionalHours int `json:"additional_hours" binding:"required,min=1,max=12"`
 }
 
+// RebootSessionRequest is the optional body of a session reboot
+type RebootSessionRequest struct {
+	// SSHPrivateKey is the session's private key, needed to reboot over SSH
+	// on providers that cannot reboot instances. It is not stored.
+	SSHPrivateKey string `json:"ssh_private_key"`
+}
+
 // ListSessionsQuery defines query parameters for listing sessions
 type ListSessionsQuery struct {
 	ConsumerID string `form:"consumer_id"`
 	Status     string `form:"status"`
 	Provider   string `form:"provider"` // Bug #100 fix: Add provider filter
-	Limit      int    `form:"limit"`
+	GroupID    string `form:"group_id"`
+	Health     string `form:"health"` // "healthy" or "degraded"
+	GPUType    string `form:"gpu_type"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1"`
+	Offset     int    `form:"offset" binding:"omitempty,min=0"`
+	Sort       string `form:"sort"`  // "created_at" (default), "price" or "cost"
+	Order      string `form:"order"` // "desc" (default) or "asc"
+	Search     string `form:"q"`     // Error, failure detail, offer, instance or consumer ID text
+
+	// Stopped, failed and preempted sessions are only listed when asked
+	// for, either with include_terminal, by status or by searching
+	IncludeTerminal bool `form:"include_terminal"`
+}
+
+// SessionLogsQuery defines query parameters for session logs
+type SessionLogsQuery struct {
+	Tail int `form:"tail" binding:"omitempty,min=1,max=5000"` // Lines from the end (default 200)
 }
 
 // CostQuery defines query parameters for cost endpoints
@@ -105,7 +240,42 @@ type CostQueryParams struct {
 	SessionID  string `form:"session_id"`
 	StartDate  string `form:"start_date"`
 	EndDate    string `form:"end_date"`
-	Period     string `form:"period"` // "daily", "monthly"
+	Period     string `form:"period"`   // "daily", "monthly"
+	GroupBy    string `form:"group_by"` // Comma-separated: consumer, deployment, gpu_type, provider
+	Format     string `form:"format"`   // With group_by: "json" (default) or "csv"
+}
+
+// CostExportParams defines query parameters for exporting cost records
+type CostExportParams struct {
+	ConsumerID string `form:"consumer_id"`
+	SessionID  string `form:"session_id"`
+	Provider   string `form:"provider"`
+	StartDate  string `form:"start_date"`
+	EndDate    string `form:"end_date"` // Inclusive
+	Format     string `form:"format"`   // "csv" (default) or "json"
+}
+
+// CostReconciliationParams defines query parameters for the cost
+// reconciliation report
+type CostReconciliationParams struct {
+	StartDate string `form:"start_date"`
+	EndDate   string `form:"end_date"` // Inclusive
+}
+
+// FailureAnalyticsParams defines query parameters for failure analytics
+type FailureAnalyticsParams struct {
+	StartDate string `form:"start_date"`
+	EndDate   string `form:"end_date"` // Inclusive
+}
+
+// SessionCostParams defines query parameters for a session's cost forecast
+type SessionCostParams struct {
+	ExtendHours int `form:"extend_hours" binding:"omitempty,min=1,max=12"` // Default 1
+}
+
+// CostForecastParams defines query parameters for the fleet cost forecast
+type CostForecastParams struct {
+	ConsumerID string `form:"consumer_id"`
 }
 
 // SessionDiagnosticsResponse contains diagnostic information for a session
@@ -146,6 +316,12 @@ func (s *Server) handleHealth(c *gin.Context) {
 
 	if s.inventory != nil {
 		response.Services["inventory"] = "ok"
+		response.Inventory = s.providerCacheHealth()
+		for _, h := range response.Inventory {
+			if h.ServingStale || h.Error {
+				response.Services["inventory"] = "degraded"
+			}
+		}
 	}
 
 	// Return 503 if not ready (e.g., during startup sweep)
@@ -160,6 +336,38 @@ func (s *Server) handleHealth(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// providerCacheHealth reports the age and state of every provider's cache
+func (s *Server) providerCacheHealth() map[string]ProviderCacheHealth {
+	status := s.inventory.GetProviderCacheStatus()
+	health := make(map[string]ProviderCacheHealth, len(status))
+	for _, name := range s.inventory.ProviderNames() {
+		st, ok := status[name]
+		if !ok {
+			health[name] = ProviderCacheHealth{}
+			continue
+		}
+		fetchedAt := st.FetchedAt
+		health[name] = ProviderCacheHealth{
+			Cached:       true,
+			FetchedAt:    &fetchedAt,
+			AgeSeconds:   st.AgeSeconds,
+			OfferCount:   st.OfferCount,
+			Stale:        st.IsStale,
+			ServingStale: st.InBackoff && !st.HasError,
+			Error:        st.HasError,
+		}
+	}
+	return health
+}
+
+// setStaleInventoryHeader flags a response that includes offers from
+// providers that are currently failing
+func (s *Server) setStaleInventoryHeader(c *gin.Context, filter models.OfferFilter) {
+	if stale := s.inventory.StaleProviders(filter); len(stale) > 0 {
+		c.Header(StaleInventoryHeader, strings.Join(stale, ","))
+	}
+}
+
 // ReadyResponse is the readiness check response
 type ReadyResponse struct {
 	Ready     bool      `json:"ready"`
@@ -183,12 +391,165 @@ func (s *Server) handleReady(c *gin.Context) {
 func (s *Server) handleListInventory(c *gin.Context) {
 	ctx := c.Request.Context()
 
+	filter, ok := s.offerFilterFromQuery(c)
+	if !ok {
+		return
+	}
+
+	// Bug #11, #72: Parse and validate pagination params
+	var limit, offset int
+	if limitStr := c.Query("limit"); limitStr != "" {
+		v, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid limit: must be a valid integer, got %q", limitStr),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		// Bug #72: Reject negative or zero limit
+		if v <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid limit: must be positive, got %d", v),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		limit = v
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		v, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid offset: must be a valid integer, got %q", offsetStr),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		if v < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid offset: must be non-negative, got %d", v),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		offset = v
+	}
+
+	var rank bool
+	if rankStr := c.Query("rank"); rankStr != "" {
+		v, err := strconv.ParseBool(rankStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid rank: must be true or false, got %q", rankStr),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		rank = v
+	}
+
+	offers, err := s.inventory.ListOffers(ctx, filter)
+	if err != nil {
+		// Bug #2 fix: Return 400 for invalid provider, not 500
+		status := http.StatusInternalServerError
+		var providerNotFound *inventory.ProviderNotFoundError
+		if errors.As(err, &providerNotFound) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	s.setStaleInventoryHeader(c, filter)
+
+	// Ranked listings are ordered by score and explain each offer's place
+	if rank {
+		ranked := s.ranker.Rank(ctx, offers, c.Query("model"))
+		totalCount := len(ranked)
+		ranked = paginate(ranked, offset, limit)
+		c.JSON(http.StatusOK, gin.H{
+			"offers": ranked,
+			"count":  len(ranked),
+			"total":  totalCount,
+		})
+		return
+	}
+
+	// Bug #11: Apply pagination
+	totalCount := len(offers)
+	offers = paginate(offers, offset, limit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"offers": offers,
+		"count":  len(offers),
+		"total":  totalCount,
+	})
+}
+
+// handleGetInventorySummary aggregates the offers matching the inventory
+// filters per GPU type, cheapest type first
+func (s *Server) handleGetInventorySummary(c *gin.Context) {
+	filter, ok := s.offerFilterFromQuery(c)
+	if !ok {
+		return
+	}
+
+	offers, err := s.inventory.ListOffers(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var providerNotFound *inventory.ProviderNotFoundError
+		if errors.As(err, &providerNotFound) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	s.setStaleInventoryHeader(c, filter)
+
+	summaries := inventory.SummarizeOffers(offers)
+	c.JSON(http.StatusOK, gin.H{
+		"gpu_types": summaries,
+		"count":     len(summaries),
+	})
+}
+
+// offerFilterFromQuery builds the offer filter shared by the inventory
+// listing and summary, writing a 400 response when a parameter is invalid
+func (s *Server) offerFilterFromQuery(c *gin.Context) (models.OfferFilter, bool) {
+	ctx := c.Request.Context()
+
 	filter := models.OfferFilter{
 		Provider: c.Query("provider"),
 		GPUType:  c.Query("gpu_type"),
 		Location: c.Query("location"),
 	}
 
+	// Offers in any listed region or country match
+	for _, param := range []string{"region", "country"} {
+		for _, region := range strings.Split(c.Query(param), ",") {
+			region = strings.TrimSpace(region)
+			if region == "" {
+				continue
+			}
+			if !models.IsValidRegion(region) {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:     fmt.Sprintf("invalid %s: expected an ISO country code or one of EU, EEA, NA, APAC, got %q", param, region),
+					RequestID: c.GetString("request_id"),
+				})
+				return models.OfferFilter{}, false
+			}
+			filter.Regions = append(filter.Regions, region)
+		}
+	}
+
 	// Bug #12-14: Validate numeric params - return 400 for invalid values
 	if minVRAM := c.Query("min_vram"); minVRAM != "" {
 		v, err := strconv.Atoi(minVRAM)
@@ -197,14 +558,14 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid min_vram: must be a valid integer, got %q", minVRAM),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid min_vram: must be non-negative, got %d", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MinVRAM = v
 	}
@@ -217,14 +578,14 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid max_price: must be a valid number, got %q", maxPrice),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid max_price: must be non-negative, got %v", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MaxPrice = v
 	}
@@ -237,14 +598,14 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid gpu_count: must be a valid integer, got %q", gpuCount),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid gpu_count: must be non-negative, got %d", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MinGPUCount = v
 	}
@@ -257,14 +618,14 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid min_gpu_count: must be a valid integer, got %q", minGPUCount),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid min_gpu_count: must be non-negative, got %d", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MinGPUCount = v
 	}
@@ -277,14 +638,14 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid min_reliability: must be a valid number, got %q", minReliability),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 || v > 1 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid min_reliability: must be between 0 and 1, got %v", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MinReliability = v
 	}
@@ -296,14 +657,14 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid min_availability_confidence: must be a valid number, got %q", minConfidence),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 || v > 1 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid min_availability_confidence: must be between 0 and 1, got %v", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MinAvailabilityConfidence = v
 	}
@@ -315,18 +676,66 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     fmt.Sprintf("invalid min_cuda: must be a valid number, got %q", minCUDA),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		if v < 0 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:     fmt.Sprintf("invalid min_cuda: must be non-negative, got %v", v),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 		filter.MinCUDAVersion = v
 	}
 
+	if minDriver := c.Query("min_driver"); minDriver != "" {
+		if !validDriverVersionRegex.MatchString(minDriver) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid min_driver: expected a version such as 535.104, got %q", minDriver),
+				RequestID: c.GetString("request_id"),
+			})
+			return models.OfferFilter{}, false
+		}
+		filter.MinDriverVersion = minDriver
+	}
+
+	if minInetDown := c.Query("min_inet_down"); minInetDown != "" {
+		v, err := strconv.ParseFloat(minInetDown, 64)
+		if err != nil || v < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid min_inet_down: must be a non-negative number, got %q", minInetDown),
+				RequestID: c.GetString("request_id"),
+			})
+			return models.OfferFilter{}, false
+		}
+		filter.MinInetDownMbps = v
+	}
+
+	if minDiskBW := c.Query("min_disk_bw"); minDiskBW != "" {
+		v, err := strconv.ParseFloat(minDiskBW, 64)
+		if err != nil || v < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid min_disk_bw: must be a non-negative number, got %q", minDiskBW),
+				RequestID: c.GetString("request_id"),
+			})
+			return models.OfferFilter{}, false
+		}
+		filter.MinDiskBandwidthMBps = v
+	}
+
+	// Interruptible (bid) offers are listed instead of on-demand ones when requested
+	if interruptible := c.Query("interruptible"); interruptible != "" {
+		v, err := strconv.ParseBool(interruptible)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid interruptible: must be true or false, got %q", interruptible),
+				RequestID: c.GetString("request_id"),
+			})
+			return models.OfferFilter{}, false
+		}
+		filter.Interruptible = v
+	}
+
 	// Template-aware filtering: apply template's extra_filters as offer constraints
 	if templateHashID := c.Query("template_hash_id"); templateHashID != "" {
 		templateProvider, err := s.inventory.GetTemplateProvider("vastai")
@@ -335,7 +744,7 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     "template filtering requires Vast.ai provider: " + err.Error(),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 
 		tmpl, err := templateProvider.GetTemplate(ctx, templateHashID)
@@ -344,7 +753,7 @@ func (s *Server) handleListInventory(c *gin.Context) {
 				Error:     "template not found: " + sanitizeInput(templateHashID, 128),
 				RequestID: c.GetString("request_id"),
 			})
-			return
+			return models.OfferFilter{}, false
 		}
 
 		// Parse template extra_filters and apply as offer constraints
@@ -380,79 +789,84 @@ func (s *Server) handleListInventory(c *gin.Context) {
 		}
 	}
 
-	// Bug #11, #72: Parse and validate pagination params
-	var limit, offset int
-	if limitStr := c.Query("limit"); limitStr != "" {
-		v, err := strconv.Atoi(limitStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:     fmt.Sprintf("invalid limit: must be a valid integer, got %q", limitStr),
-				RequestID: c.GetString("request_id"),
-			})
-			return
-		}
-		// Bug #72: Reject negative or zero limit
-		if v <= 0 {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:     fmt.Sprintf("invalid limit: must be positive, got %d", v),
-				RequestID: c.GetString("request_id"),
-			})
-			return
+	return filter, true
+}
+
+// paginate returns the page of items starting at offset, at most limit long
+// (0 = no limit)
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return []T{}
 		}
-		limit = v
+		items = items[offset:]
 	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		v, err := strconv.Atoi(offsetStr)
-		if err != nil {
+// maxPriceHistoryHours bounds history queries to the retention window
+const maxPriceHistoryHours = 90 * 24
+
+// handleGetPriceHistory returns recorded per-GPU-hour prices for a GPU type
+func (s *Server) handleGetPriceHistory(c *gin.Context) {
+	gpuType := c.Query("gpu")
+	if gpuType == "" {
+		gpuType = c.Query("gpu_type")
+	}
+	if gpuType == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "gpu query parameter is required",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	hours := 24
+	if v := c.Query("hours"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPriceHistoryHours {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:     fmt.Sprintf("invalid offset: must be a valid integer, got %q", offsetStr),
+				Error:     fmt.Sprintf("hours must be between 1 and %d", maxPriceHistoryHours),
 				RequestID: c.GetString("request_id"),
 			})
 			return
 		}
-		if v < 0 {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:     fmt.Sprintf("invalid offset: must be non-negative, got %d", v),
+		hours = n
+	}
+
+	filter := models.PriceHistoryFilter{
+		GPUType:  gpuType,
+		Provider: c.Query("provider"),
+		Since:    time.Now().Add(-time.Duration(hours) * time.Hour),
+	}
+
+	history, err := s.inventory.PriceHistory(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, inventory.ErrPriceHistoryDisabled) {
+			c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+				Error:     err.Error(),
 				RequestID: c.GetString("request_id"),
 			})
 			return
 		}
-		offset = v
-	}
-
-	offers, err := s.inventory.ListOffers(ctx, filter)
-	if err != nil {
-		// Bug #2 fix: Return 400 for invalid provider, not 500
-		status := http.StatusInternalServerError
-		var providerNotFound *inventory.ProviderNotFoundError
-		if errors.As(err, &providerNotFound) {
-			status = http.StatusBadRequest
-		}
-		c.JSON(status, ErrorResponse{
-			Error:     err.Error(),
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get price history",
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
-
-	// Bug #11: Apply pagination
-	totalCount := len(offers)
-	if offset > 0 {
-		if offset >= len(offers) {
-			offers = []models.GPUOffer{}
-		} else {
-			offers = offers[offset:]
-		}
-	}
-	if limit > 0 && limit < len(offers) {
-		offers = offers[:limit]
+	if history == nil {
+		history = []models.PriceSnapshot{}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"offers": offers,
-		"count":  len(offers),
-		"total":  totalCount,
+		"gpu_type": gpuType,
+		"since":    filter.Since.UTC(),
+		"history":  history,
+		"count":    len(history),
 	})
 }
 
@@ -489,27 +903,83 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 		return
 	}
 
-	// Validate workload_type
-	if wt := models.WorkloadType(req.WorkloadType); !wt.IsValid() {
+	if msg := validateSessionSpec(req.SessionSpec); msg != "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:     "invalid workload_type: must be one of: llm, llm_vllm, llm_tgi, training, batch, interactive, inference, ssh, benchmark",
+			Error:     msg,
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
+	if !s.allowSessionSecrets(c, req.SessionSpec) {
+		return
+	}
 
-	// Validate retry_scope if auto_retry is enabled
-	if req.AutoRetry && req.RetryScope != "" && !models.IsValidRetryScope(req.RetryScope) {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:     "invalid retry_scope: must be one of: same_gpu, same_vram, any",
-			RequestID: c.GetString("request_id"),
-		})
+	if req.Offer != nil {
+		if req.OfferID != "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "set either offer_id or offer, not both",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		if req.Offer.GPUType == "" && req.Offer.MinVRAM == 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "offer must set gpu_type or min_vram",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		for _, region := range req.Offer.Regions {
+			if !models.IsValidRegion(region) {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:     fmt.Sprintf("invalid offer region %q: expected an ISO country code or one of EU, EEA, NA, APAC", region),
+					RequestID: c.GetString("request_id"),
+				})
+				return
+			}
+		}
+		if req.Offer.MinDriverVersion != "" && !validDriverVersionRegex.MatchString(req.Offer.MinDriverVersion) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid offer min_driver_version %q: expected a version such as 535.104", req.Offer.MinDriverVersion),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+	}
+
+	if req.Queue != nil {
+		if s.reservations == nil {
+			c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+				Error:     "session queue not available",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		// An offer spec doubles as the queue filter unless one is given
+		if req.Queue.Filter.GPUType == "" && req.Offer != nil {
+			req.Queue.Filter = req.Offer.Filter()
+		}
+		if req.Queue.Filter.GPUType == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "queue.filter.gpu_type is required",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+	}
+
+	if req.Offer != nil {
+		s.createSessionForSpec(c, req)
 		return
 	}
 
 	// Get the offer from cache (spot market is fast - don't invalidate)
 	offer, err := s.inventory.GetOffer(ctx, req.OfferID)
 	if err != nil {
+		if req.Queue != nil {
+			s.enqueueSession(c, s.buildCreateRequest(ctx, req.SessionSpec, req.OfferID), req.Queue)
+			return
+		}
 		c.JSON(http.StatusNotFound, ErrorResponse{
 			Error:     "offer not found: " + sanitizeInput(req.OfferID, 128),
 			RequestID: c.GetString("request_id"),
@@ -517,9 +987,159 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 		return
 	}
 
+	createReq := s.buildCreateRequest(ctx, req.SessionSpec, req.OfferID)
+
+	session, err := s.provisioner.CreateSession(ctx, createReq, offer)
+	if err != nil {
+		var staleErr *provisioner.StaleInventoryError
+		if req.Queue != nil && errors.As(err, &staleErr) {
+			s.enqueueSession(c, createReq, req.Queue)
+			return
+		}
+		c.JSON(provisionErrorResponse(err, c.GetString("request_id")))
+		return
+	}
+
+	// Return session with secrets (only shown once)
+	c.JSON(http.StatusCreated, CreateSessionResponse{
+		Session:          session.ToResponse(),
+		SSHPrivateKey:    session.SSHPrivateKey,
+		RetriesAttempted: session.RetryCount,
+	})
+}
+
+// createSessionForSpec lets the provisioner pick the offer for req.Offer
+func (s *Server) createSessionForSpec(c *gin.Context, req CreateSessionRequest) {
+	ctx := c.Request.Context()
+	createReq := s.buildCreateRequest(ctx, req.SessionSpec, "")
+
+	session, err := s.provisioner.CreateSessionForSpec(ctx, createReq, req.Offer.Filter())
+	if err != nil {
+		var noMatchErr *provisioner.NoMatchingOfferError
+		if req.Queue != nil && (errors.As(err, &noMatchErr) || provisioner.IsRetryableWithDifferentOffer(err)) {
+			s.enqueueSession(c, createReq, req.Queue)
+			return
+		}
+		c.JSON(provisionErrorResponse(err, c.GetString("request_id")))
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateSessionResponse{
+		Session:          session.ToResponse(),
+		SSHPrivateKey:    session.SSHPrivateKey,
+		RetriesAttempted: session.RetryCount,
+		SelectedOfferID:  session.OfferID,
+	})
+}
+
+// validateSessionSpec checks the fields binding cannot, returning an error
+// message or "" if the spec is valid.
+func validateSessionSpec(spec SessionSpec) string {
+	if wt := models.WorkloadType(spec.WorkloadType); !wt.IsValid() {
+		return "invalid workload_type: must be one of: llm, llm_vllm, llm_tgi, llm_sglang, llm_llamacpp, training, batch, interactive, inference, ssh, benchmark"
+	}
+	if spec.AutoRetry && spec.RetryScope != "" && !models.IsValidRetryScope(spec.RetryScope) {
+		return "invalid retry_scope: must be one of: same_gpu, same_vram, any"
+	}
+	if spec.GroupID != "" && !validGroupIDRegex.MatchString(spec.GroupID) {
+		return "invalid group_id: must be 1-64 characters of letters, digits, '.', '_' or '-'"
+	}
+	if msg := validateCloudInit(spec.CloudInit); msg != "" {
+		return "invalid cloud_init: " + msg
+	}
+	if spec.BootstrapScript != "" {
+		if spec.LaunchMode == "entrypoint" {
+			return "invalid bootstrap_script: requires launch_mode ssh"
+		}
+		if len(spec.BootstrapScript) > models.MaxBootstrapScriptBytes {
+			return fmt.Sprintf("invalid bootstrap_script: exceeds the %d byte limit", models.MaxBootstrapScriptBytes)
+		}
+		if strings.ContainsRune(spec.BootstrapScript, 0) {
+			return "invalid bootstrap_script: contains a NUL byte"
+		}
+	}
+	if spec.GPUBurnIn && spec.LaunchMode == "entrypoint" {
+		return "invalid gpu_burn_in: requires launch_mode ssh"
+	}
+	if spec.HFToken != "" && !validHFTokenRegex.MatchString(spec.HFToken) {
+		return "invalid hf_token: must be 1-256 printable characters without spaces"
+	}
+	return ""
+}
+
+// allowSessionSecrets rejects a session spec carrying a HuggingFace token
+// unless the database encrypts it at rest, writing the 400 response
+func (s *Server) allowSessionSecrets(c *gin.Context, spec SessionSpec) bool {
+	if spec.HFToken == "" || s.databaseEncryption {
+		return true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":      "hf_token requires database encryption (DATABASE_ENCRYPTION_KEY), so the token is not stored in plaintext",
+		"error_type": "encryption_required",
+		"request_id": c.GetString("request_id"),
+	})
+	return false
+}
+
+// validateCloudInit checks consumer cloud-init against the size limits and
+// keeps it away from the files the shopper relies on to reach the instance.
+func validateCloudInit(ci *models.CloudInitConfig) string {
+	if ci == nil {
+		return ""
+	}
+	if len(ci.RunCmd) > models.MaxCloudInitRunCmds {
+		return fmt.Sprintf("at most %d runcmd entries allowed", models.MaxCloudInitRunCmds)
+	}
+	if len(ci.WriteFiles) > models.MaxCloudInitFiles {
+		return fmt.Sprintf("at most %d write_files entries allowed", models.MaxCloudInitFiles)
+	}
+	if size := ci.Size(); size > models.MaxCloudInitBytes {
+		return fmt.Sprintf("%d bytes exceeds the %d byte limit", size, models.MaxCloudInitBytes)
+	}
+	for i, cmd := range ci.RunCmd {
+		if strings.TrimSpace(cmd) == "" {
+			return fmt.Sprintf("runcmd[%d] is empty", i)
+		}
+		if strings.ContainsRune(cmd, 0) {
+			return fmt.Sprintf("runcmd[%d] contains a NUL byte", i)
+		}
+	}
+	for i, f := range ci.WriteFiles {
+		if !path.IsAbs(f.Path) || path.Clean(f.Path) != f.Path || f.Path == "/" {
+			return fmt.Sprintf("write_files[%d].path must be a clean absolute file path", i)
+		}
+		// Overwriting SSH configuration could lock the shopper out of the instance
+		if strings.Contains(f.Path, "/.ssh/") || strings.HasPrefix(f.Path, "/etc/ssh/") {
+			return fmt.Sprintf("write_files[%d].path may not be in an SSH configuration directory", i)
+		}
+		switch f.Encoding {
+		case "":
+			if strings.ContainsRune(f.Content, 0) {
+				return fmt.Sprintf("write_files[%d].content contains a NUL byte; use encoding b64 for binary files", i)
+			}
+		case "b64":
+			if _, err := base64.StdEncoding.DecodeString(f.Content); err != nil {
+				return fmt.Sprintf("write_files[%d].content is not valid base64", i)
+			}
+		default:
+			return fmt.Sprintf("write_files[%d].encoding must be empty or b64", i)
+		}
+		if f.Permissions != "" && !validFileModeRegex.MatchString(f.Permissions) {
+			return fmt.Sprintf("write_files[%d].permissions must be an octal mode such as 0644", i)
+		}
+		if f.Owner != "" && !validFileOwnerRegex.MatchString(f.Owner) {
+			return fmt.Sprintf("write_files[%d].owner must be user or user:group", i)
+		}
+	}
+	return ""
+}
+
+// buildCreateRequest converts an API session spec for offerID into a
+// provisioner request, applying template and SSH timeout defaults.
+func (s *Server) buildCreateRequest(ctx context.Context, spec SessionSpec, offerID string) models.CreateSessionRequest {
 	// Convert storage policy
 	var storagePolicy models.StoragePolicy
-	switch req.StoragePolicy {
+	switch spec.StoragePolicy {
 	case "preserve":
 		storagePolicy = models.StoragePreserve
 	default:
@@ -528,7 +1148,7 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 
 	// Convert launch mode
 	var launchMode models.LaunchMode
-	switch req.LaunchMode {
+	switch spec.LaunchMode {
 	case "entrypoint":
 		launchMode = models.LaunchModeEntrypoint
 	default:
@@ -537,30 +1157,37 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 
 	// Create session
 	createReq := models.CreateSessionRequest{
-		ConsumerID:        req.ConsumerID,
-		OfferID:           req.OfferID,
-		WorkloadType:      models.WorkloadType(req.WorkloadType),
-		ReservationHrs:    req.ReservationHrs,
-		IdleThreshold:     req.IdleThreshold,
+		ConsumerID:        spec.ConsumerID,
+		OfferID:           offerID,
+		WorkloadType:      models.WorkloadType(spec.WorkloadType),
+		ReservationHrs:    spec.ReservationHrs,
+		IdleThreshold:     spec.IdleThreshold,
+		IdleGPUUtilPct:    spec.IdleGPUUtilPct,
 		StoragePolicy:     storagePolicy,
 		LaunchMode:        launchMode,
-		DockerImage:       req.DockerImage,
-		ModelID:           req.ModelID,
-		ExposedPorts:      req.ExposedPorts,
-		Quantization:      req.Quantization,
-		TemplateHashID:    req.TemplateHashID,
-		DiskGB:            req.DiskGB,
-		AutoRetry:         req.AutoRetry,
-		MaxRetries:        req.MaxRetries,
-		RetryScope:        req.RetryScope,
-		SSHTimeoutMinutes: req.SSHTimeoutMinutes,
-		OnStartCmd:        req.OnStartCmd,
+		DockerImage:       spec.DockerImage,
+		ModelID:           spec.ModelID,
+		ExposedPorts:      spec.ExposedPorts,
+		Quantization:      spec.Quantization,
+		TemplateHashID:    spec.TemplateHashID,
+		DiskGB:            spec.DiskGB,
+		AutoRetry:         spec.AutoRetry,
+		MaxRetries:        spec.MaxRetries,
+		RetryScope:        spec.RetryScope,
+		SSHTimeoutMinutes: spec.SSHTimeoutMinutes,
+		OnStartCmd:        spec.OnStartCmd,
+		BidPrice:          spec.BidPrice,
+		GroupID:           spec.GroupID,
+		CloudInit:         spec.CloudInit,
+		BootstrapScript:   spec.BootstrapScript,
+		GPUBurnIn:         spec.GPUBurnIn,
+		HFToken:           spec.HFToken,
 	}
 
 	// Look up template's recommended disk space and SSH timeout (non-fatal if lookup fails)
-	if req.TemplateHashID != "" {
+	if spec.TemplateHashID != "" {
 		if templateProvider, err := s.inventory.GetTemplateProvider("vastai"); err == nil {
-			if tmpl, err := templateProvider.GetTemplate(ctx, req.TemplateHashID); err == nil && tmpl != nil {
+			if tmpl, err := templateProvider.GetTemplate(ctx, spec.TemplateHashID); err == nil && tmpl != nil {
 				createReq.TemplateRecommendedDiskGB = tmpl.RecommendedDiskSpace
 				// BUG-005: Use template's recommended SSH timeout for heavy images
 				createReq.TemplateRecommendedSSHTimeout = tmpl.GetRecommendedSSHTimeout()
@@ -569,8 +1196,8 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 	}
 
 	// BUG-005: Client SSH timeout override takes priority over template timeout
-	if req.SSHTimeoutMinutes > 0 {
-		mins := req.SSHTimeoutMinutes
+	if spec.SSHTimeoutMinutes > 0 {
+		mins := spec.SSHTimeoutMinutes
 		if mins > 30 {
 			mins = 30
 		}
@@ -580,64 +1207,169 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 		createReq.TemplateRecommendedSSHTimeout = time.Duration(mins) * time.Minute
 	}
 
-	session, err := s.provisioner.CreateSession(ctx, createReq, offer)
-	if err != nil {
-		var dupErr *provisioner.DuplicateSessionError
-		if errors.As(err, &dupErr) {
-			c.JSON(http.StatusConflict, ErrorResponse{
-				Error:     err.Error(),
-				RequestID: c.GetString("request_id"),
-			})
-			return
+	return createReq
+}
+
+// provisionErrorResponse maps a provisioning error to a status code and a
+// response body with an error_type clients can act on.
+func provisionErrorResponse(err error, requestID string) (int, gin.H) {
+	var dupErr *provisioner.DuplicateSessionError
+	if errors.As(err, &dupErr) {
+		return http.StatusConflict, gin.H{
+			"error":      err.Error(),
+			"request_id": requestID,
 		}
+	}
 
-		// Check for insufficient disk space error
-		var diskErr *provisioner.InsufficientDiskError
-		if errors.As(err, &diskErr) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":          err.Error(),
-				"error_type":     "insufficient_disk",
-				"requested_gb":   diskErr.RequestedGB,
-				"minimum_gb":     diskErr.MinimumGB,
-				"recommended_gb": diskErr.RecommendedGB,
-				"breakdown":      diskErr.Estimation,
-				"request_id":     c.GetString("request_id"),
-			})
-			return
+	// Check for budget cap - the session would push spend over a limit
+	var budgetErr *budget.ExceededError
+	if errors.As(err, &budgetErr) {
+		return http.StatusPaymentRequired, gin.H{
+			"error":          err.Error(),
+			"error_type":     "budget_exceeded",
+			"budget_id":      budgetErr.BudgetID,
+			"scope":          budgetErr.Scope,
+			"scope_id":       budgetErr.ScopeID,
+			"period":         budgetErr.Period,
+			"limit_usd":      budgetErr.Limit,
+			"spend":          budgetErr.Spend,
+			"projected_cost": budgetErr.ProjectedCost,
+			"request_id":     requestID,
 		}
+	}
 
-		// Check for stale inventory error - this means the offer appeared available
-		// but provisioning failed, likely due to stale inventory data
-		var staleErr *provisioner.StaleInventoryError
-		if errors.As(err, &staleErr) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error":           err.Error(),
-				"error_type":      "stale_inventory",
-				"offer_id":        staleErr.OfferID,
-				"provider":        staleErr.Provider,
-				"retry_suggested": true,
-				"message":         "The selected offer is no longer available. Please refresh inventory and select a different offer.",
-				"request_id":      c.GetString("request_id"),
-			})
-			return
+	// Check for provider balance too low to cover the reservation
+	var balanceErr *provisioner.InsufficientBalanceError
+	if errors.As(err, &balanceErr) {
+		return http.StatusPaymentRequired, gin.H{
+			"error":          err.Error(),
+			"error_type":     "insufficient_balance",
+			"provider":       balanceErr.Provider,
+			"balance":        balanceErr.Balance,
+			"projected_cost": balanceErr.ProjectedCost,
+			"request_id":     requestID,
+		}
+	}
+
+	// Check for a provider switched off by feature flag
+	var disabledErr *provisioner.ProviderDisabledError
+	if errors.As(err, &disabledErr) {
+		return http.StatusForbidden, gin.H{
+			"error":      err.Error(),
+			"error_type": "provider_disabled",
+			"provider":   disabledErr.Provider,
+			"request_id": requestID,
+		}
+	}
+
+	// Check for custom cloud-init the offer's provider cannot apply
+	var cloudInitErr *provisioner.CloudInitNotSupportedError
+	if errors.As(err, &cloudInitErr) {
+		return http.StatusBadRequest, gin.H{
+			"error":      err.Error(),
+			"error_type": "cloud_init_not_supported",
+			"provider":   cloudInitErr.Provider,
+			"request_id": requestID,
+		}
+	}
+
+	// Check for an offer outside the consumer's data-residency policy
+	var regionErr *provisioner.RegionNotAllowedError
+	if errors.As(err, &regionErr) {
+		return http.StatusForbidden, gin.H{
+			"error":      err.Error(),
+			"error_type": "region_not_allowed",
+			"offer_id":   regionErr.OfferID,
+			"location":   regionErr.Location,
+			"request_id": requestID,
+		}
+	}
+
+	// Check for a bid the offer cannot accept
+	var bidErr *provisioner.InvalidBidError
+	if errors.As(err, &bidErr) {
+		return http.StatusBadRequest, gin.H{
+			"error":      err.Error(),
+			"error_type": "invalid_bid",
+			"offer_id":   bidErr.OfferID,
+			"bid_price":  bidErr.BidPrice,
+			"min_bid":    bidErr.MinBid,
+			"request_id": requestID,
+		}
+	}
+
+	// Check for insufficient GPU memory error
+	var vramErr *provisioner.InsufficientVRAMError
+	if errors.As(err, &vramErr) {
+		return http.StatusBadRequest, gin.H{
+			"error":        err.Error(),
+			"error_type":   "insufficient_vram",
+			"required_gb":  vramErr.RequiredGB,
+			"available_gb": vramErr.AvailableGB,
+			"breakdown":    vramErr.Estimation,
+			"request_id":   requestID,
+		}
+	}
+
+	// Check for insufficient disk space error
+	var diskErr *provisioner.InsufficientDiskError
+	if errors.As(err, &diskErr) {
+		return http.StatusBadRequest, gin.H{
+			"error":          err.Error(),
+			"error_type":     "insufficient_disk",
+			"requested_gb":   diskErr.RequestedGB,
+			"minimum_gb":     diskErr.MinimumGB,
+			"recommended_gb": diskErr.RecommendedGB,
+			"breakdown":      diskErr.Estimation,
+			"request_id":     requestID,
+		}
+	}
+
+	// Check for an offer spec that no available offer satisfies
+	var noMatchErr *provisioner.NoMatchingOfferError
+	if errors.As(err, &noMatchErr) {
+		return http.StatusNotFound, gin.H{
+			"error":          err.Error(),
+			"error_type":     "no_matching_offer",
+			"min_confidence": noMatchErr.MinConfidence,
+			"request_id":     requestID,
 		}
+	}
 
-		errorType, retrySuggested := classifyProvisionError(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
+	// Check for a provider with too many instance creations in flight
+	var busyErr *provisioner.ProviderBusyError
+	if errors.As(err, &busyErr) {
+		return http.StatusServiceUnavailable, gin.H{
 			"error":           err.Error(),
-			"error_type":      errorType,
-			"retry_suggested": retrySuggested,
-			"request_id":      c.GetString("request_id"),
-		})
-		return
+			"error_type":      "provider_busy",
+			"provider":        busyErr.Provider,
+			"retry_suggested": true,
+			"request_id":      requestID,
+		}
 	}
 
-	// Return session with secrets (only shown once)
-	c.JSON(http.StatusCreated, CreateSessionResponse{
-		Session:          session.ToResponse(),
-		SSHPrivateKey:    session.SSHPrivateKey,
-		RetriesAttempted: session.RetryCount,
-	})
+	// Check for stale inventory error - this means the offer appeared available
+	// but provisioning failed, likely due to stale inventory data
+	var staleErr *provisioner.StaleInventoryError
+	if errors.As(err, &staleErr) {
+		return http.StatusServiceUnavailable, gin.H{
+			"error":           err.Error(),
+			"error_type":      "stale_inventory",
+			"offer_id":        staleErr.OfferID,
+			"provider":        staleErr.Provider,
+			"retry_suggested": true,
+			"message":         "The selected offer is no longer available. Please refresh inventory and select a different offer.",
+			"request_id":      requestID,
+		}
+	}
+
+	errorType, retrySuggested := classifyProvisionError(err)
+	return http.StatusInternalServerError, gin.H{
+		"error":           err.Error(),
+		"error_type":      errorType,
+		"retry_suggested": retrySuggested,
+		"request_id":      requestID,
+	}
 }
 
 func (s *Server) handleListSessions(c *gin.Context) {
@@ -652,165 +1384,871 @@ func (s *Server) handleListSessions(c *gin.Context) {
 		return
 	}
 
+	query.Search = strings.TrimSpace(query.Search)
+
 	// Build filter from query parameters
 	// Bug #100 fix: Parse provider query param and add to filter
 	filter := models.SessionListFilter{
 		ConsumerID: query.ConsumerID,
 		Provider:   query.Provider,
-		Limit:      query.Limit,
+		GroupID:    query.GroupID,
+		GPUType:    query.GPUType,
+		Offset:     query.Offset,
+		Search:     query.Search,
+
+		ExcludeTerminal: !query.IncludeTerminal && query.Status == "" && query.Search == "",
+		SortBy:          models.SessionSortField(query.Sort),
+	}
+	if query.Sort != "" && !filter.SortBy.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid sort: must be one of: created_at, price, cost",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	switch query.Order {
+	case "", "desc":
+	case "asc":
+		filter.SortAscending = true
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid order: must be one of: asc, desc",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	var err error
+	if filter.CreatedAfter, err = parseHistoryTime(c.Query("created_after"), false); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid created_after, expected YYYY-MM-DD or RFC3339: " + sanitizeInput(c.Query("created_after"), 64),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if filter.CreatedBefore, err = parseHistoryTime(c.Query("created_before"), false); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid created_before, expected YYYY-MM-DD or RFC3339: " + sanitizeInput(c.Query("created_before"), 64),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	// Fetch one extra session to tell whether there is another page
+	if query.Limit > 0 {
+		filter.Limit = query.Limit + 1
 	}
 	if query.Status != "" {
 		filter.Status = models.SessionStatus(query.Status)
 	}
+	if query.Health != "" {
+		filter.Health = models.HealthStatus(query.Health)
+		if !filter.Health.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "invalid health: must be one of: healthy, degraded",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+	}
+
+	// Query sessions from the provisioner service
+	sessions, err := s.provisioner.ListSessions(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list sessions",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	result := gin.H{}
+	if query.Limit > 0 && len(sessions) > query.Limit {
+		sessions = sessions[:query.Limit]
+		result["next_offset"] = query.Offset + query.Limit
+	}
+
+	// Convert to response format
+	responses := make([]models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToResponse()
+	}
+
+	result["sessions"] = responses
+	result["count"] = len(responses)
+	c.JSON(http.StatusOK, result)
+}
+
+// handleSearchSessions lists sessions matching the required q parameter,
+// taking the same filters as handleListSessions
+func (s *Server) handleSearchSessions(c *gin.Context) {
+	if strings.TrimSpace(c.Query("q")) == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "q is required",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	s.handleListSessions(c)
+}
+
+func (s *Server) handleGetSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	session, err := s.provisioner.GetSession(ctx, sessionID)
+	if err != nil {
+		// Check if the error is a not-found error (return 404) vs other errors (return 500)
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		// Internal error (DB issues, etc.)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get session",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, session.ToResponse())
+}
+
+// handleGetSessionEvents returns the status history of a session, oldest first,
+// along with bootstrap script results, so callers can see why a session ended
+// up in its current state.
+func (s *Server) handleGetSessionEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	if s.sessionEvents == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "session history not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	if _, err := s.provisioner.GetSession(ctx, sessionID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get session",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	events, err := s.sessionEvents.ListBySession(ctx, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list session events",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if events == nil {
+		events = []*models.SessionEvent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"events":     events,
+		"count":      len(events),
+	})
+}
+
+// handleGetSessionLogs returns the tail of a session's container output
+// from its provider
+func (s *Server) handleGetSessionLogs(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	var query SessionLogsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if query.Tail == 0 {
+		query.Tail = provisioner.DefaultLogTail
+	}
+
+	logs, err := s.provisioner.GetSessionLogs(ctx, sessionID, query.Tail)
+	if err != nil {
+		status := http.StatusBadGateway
+		var notRunning *provisioner.SessionNotRunningError
+		var notSupported *provisioner.LogsNotSupportedError
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.As(err, &notRunning):
+			status = http.StatusConflict
+		case errors.As(err, &notSupported):
+			status = http.StatusNotImplemented
+		}
+		c.JSON(status, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"tail":       query.Tail,
+		"logs":       logs,
+	})
+}
+
+// handleTakeReplacementKey returns the SSH private key of a session that
+// replaced a preempted one. The key is handed out once.
+func (s *Server) handleTakeReplacementKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	session, err := s.provisioner.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get session",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if !s.authorizeConsumer(c, session.ConsumerID, RoleOperator) {
+		return
+	}
+
+	key := s.provisioner.TakeReplacementKey(sessionID)
+	if key == "" {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:     "no SSH key to fetch: the session is not a failover replacement, or its key was already fetched",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id":      sessionID,
+		"ssh_private_key": key,
+	})
+}
+
+func (s *Server) handleSessionDone(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	if err := s.lifecycle.SignalDone(ctx, sessionID); err != nil {
+		// Bug #1/#70 fix: Return proper HTTP status codes based on error type
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		} else {
+			// Bug #70 fix: Return 409 Conflict for terminal sessions
+			var terminalErr *lifecycle.SessionTerminalError
+			if errors.As(err, &terminalErr) {
+				status = http.StatusConflict
+			}
+		}
+		c.JSON(status, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "session shutdown initiated",
+		"session_id": sessionID,
+	})
+}
+
+func (s *Server) handleExtendSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	var req ExtendSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// Bug #9: Sanitize validation errors to use JSON field names
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	if err := s.lifecycle.ExtendSession(ctx, sessionID, req.AdditionalHours); err != nil {
+		c.JSON(extendErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	// Get updated session
+	session, err := s.provisioner.GetSession(ctx, sessionID)
+	if err != nil || session == nil {
+		// Extension succeeded but couldn't retrieve updated session
+		// Return success without the new expiry time
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "session extended",
+			"session_id": sessionID,
+		})
+		return
+	}
+
+	// The lifecycle manager reads expires_at from the database on every check,
+	// so persisting the new expiry is what moves the self-destruct deadline.
+	// Return the updated cost projection so callers can see what the extra
+	// hours will cost before the next aggregation run.
+	response := gin.H{
+		"message":           "session extended",
+		"session_id":        sessionID,
+		"new_expires_at":    session.ExpiresAt,
+		"reservation_hours": session.ReservationHrs,
+		"additional_cost":   session.PricePerHour * float64(req.AdditionalHours),
+		"projected_cost":    session.ProjectedCost(),
+		"currency":          "USD",
+	}
+	if accrued, err := s.costTracker.GetSessionCost(ctx, sessionID); err == nil {
+		response["accrued_cost"] = accrued
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleGetSessionCost returns what a session has cost so far and what it
+// will cost by expiry, as is and if extended, at its current burn rate
+func (s *Server) handleGetSessionCost(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	var params SessionCostParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if params.ExtendHours == 0 {
+		params.ExtendHours = 1
+	}
+
+	session, err := s.provisioner.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get session",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	forecast, err := s.costTracker.ForecastSession(ctx, session, params.ExtendHours)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// extendErrorStatus maps a session extension error to an HTTP status code.
+// Bug #16/#18 fix: Use typed error checking instead of string matching for reliability
+func extendErrorStatus(err error) int {
+	if errors.Is(err, storage.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	// Check for terminal/stopping session error (409 Conflict)
+	var terminalErr *lifecycle.SessionTerminalError
+	if errors.As(err, &terminalErr) {
+		return http.StatusConflict
+	}
+	// Check for hard max exceeded (400 Bad Request)
+	var hardMaxErr *lifecycle.HardMaxExceededError
+	if errors.As(err, &hardMaxErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (s *Server) handleDeleteSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+
+	if err := s.provisioner.DestroySession(ctx, sessionID); err != nil {
+		// Check for not-found errors and return 404
+		var sessionNotFound *provisioner.SessionNotFoundError
+		if errors.As(err, &sessionNotFound) || errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     fmt.Sprintf("session not found: %s", sanitizeInput(sessionID, 128)),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	if s.provisioner.SavingWorkspace(sessionID) {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":    "saving workspace, the session is destroyed once it is saved",
+			"session_id": sessionID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "session destroyed",
+		"session_id": sessionID,
+	})
+}
+
+// handlePauseSession stops a running session's instance without destroying
+// it, so its GPUs are not billed until it is resumed
+func (s *Server) handlePauseSession(c *gin.Context) {
+	session, err := s.provisioner.PauseSession(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(pauseErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, session.ToResponse())
+}
+
+// handleResumeSession starts a paused session's instance again, returning
+// once it is reachable with its new SSH details
+func (s *Server) handleResumeSession(c *gin.Context) {
+	session, err := s.provisioner.ResumeSession(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(pauseErrorStatus(err), ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, session.ToResponse())
+}
+
+// handleRebootSession reboots a running session's instance in place,
+// returning once it answers SSH again
+func (s *Server) handleRebootSession(c *gin.Context) {
+	var req RebootSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     sanitizeValidationError(err),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	session, err := s.provisioner.RebootSession(c.Request.Context(), c.Param("id"), req.SSHPrivateKey)
+	if err != nil {
+		status := pauseErrorStatus(err)
+		var (
+			notSupported *provisioner.RebootNotSupportedError
+			timeout      *provisioner.RebootTimeoutError
+		)
+		switch {
+		case errors.As(err, &notSupported):
+			status = http.StatusBadRequest
+		case errors.As(err, &timeout):
+			status = http.StatusGatewayTimeout
+		}
+		c.JSON(status, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, session.ToResponse())
+}
+
+// pauseErrorStatus maps a session pause or resume error to an HTTP status code
+func pauseErrorStatus(err error) int {
+	var (
+		notRunning   *provisioner.SessionNotRunningError
+		notPaused    *provisioner.SessionNotPausedError
+		notSupported *provisioner.PauseNotSupportedError
+		timeout      *provisioner.ResumeTimeoutError
+	)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &notRunning), errors.As(err, &notPaused):
+		return http.StatusConflict
+	case errors.As(err, &notSupported):
+		return http.StatusNotImplemented
+	case errors.As(err, &timeout):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+func (s *Server) handleGetCosts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var params CostQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	// If session ID provided, get session cost
+	if params.SessionID != "" {
+		// Bug #49/#75 fix: Check if session exists before returning cost
+		// Return 404 if session_id provided but not found
+		_, err := s.provisioner.GetSession(ctx, params.SessionID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				c.JSON(http.StatusNotFound, ErrorResponse{
+					Error:     fmt.Sprintf("session not found: %s", sanitizeInput(params.SessionID, 128)),
+					RequestID: c.GetString("request_id"),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+
+		cost, err := s.costTracker.GetSessionCost(ctx, params.SessionID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"session_id": params.SessionID,
+			"total_cost": cost,
+			"currency":   "USD",
+		})
+		return
+	}
+
+	if params.GroupBy != "" {
+		s.handleCostBreakdown(c, params)
+		return
+	}
+
+	// Handle period-based queries
+	var summary *models.CostSummary
+	var err error
+
+	switch params.Period {
+	case "daily":
+		summary, err = s.costTracker.GetDailySummary(ctx, params.ConsumerID)
+	case "monthly":
+		summary, err = s.costTracker.GetMonthlySummary(ctx, params.ConsumerID)
+	default:
+		startTime, endTime, ok := parseCostDateRange(c, params.StartDate, params.EndDate)
+		if !ok {
+			return
+		}
+
+		summary, err = s.costTracker.GetSummary(ctx, models.CostQuery{
+			ConsumerID: params.ConsumerID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// parseCostDateRange parses start_date and end_date (YYYY-MM-DD, end
+// exclusive). A missing bound defaults to the month of the other one, or to
+// the current month when both are missing. It writes a 400 and returns false
+// on a malformed date.
+func parseCostDateRange(c *gin.Context, startDate, endDate string) (time.Time, time.Time, bool) {
+	var startTime, endTime time.Time
+	if startDate != "" {
+		var parseErr error
+		startTime, parseErr = time.Parse("2006-01-02", startDate)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid start_date format, expected YYYY-MM-DD: %s", startDate),
+				RequestID: c.GetString("request_id"),
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if endDate != "" {
+		var parseErr error
+		endTime, parseErr = time.Parse("2006-01-02", endDate)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid end_date format, expected YYYY-MM-DD: %s", endDate),
+				RequestID: c.GetString("request_id"),
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	// Bug #10: If no dates specified, default to current month to avoid zero dates
+	if startDate == "" && endDate == "" {
+		now := time.Now()
+		startTime = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		endTime = startTime.AddDate(0, 1, 0)
+	} else if startDate == "" {
+		// If only end date specified, default start to beginning of that month
+		startTime = time.Date(endTime.Year(), endTime.Month(), 1, 0, 0, 0, 0, endTime.Location())
+	} else if endDate == "" {
+		// If only start date specified, default end to end of that month
+		endTime = time.Date(startTime.Year(), startTime.Month()+1, 1, 0, 0, 0, 0, startTime.Location())
+	}
+
+	return startTime, endTime, true
+}
+
+// handleCostBreakdown serves GET /costs with group_by: spend per consumer,
+// deployment, GPU type or provider for chargeback, as JSON or CSV
+func (s *Server) handleCostBreakdown(c *gin.Context, params CostQueryParams) {
+	var groupBy []models.CostGroupBy
+	seen := make(map[models.CostGroupBy]bool)
+	for _, part := range strings.Split(params.GroupBy, ",") {
+		g := models.CostGroupBy(strings.TrimSpace(part))
+		if !g.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid group_by %q: must be one of: consumer, deployment, gpu_type, provider", sanitizeInput(string(g), 32)),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		if !seen[g] {
+			seen[g] = true
+			groupBy = append(groupBy, g)
+		}
+	}
+
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = "json"
+	}
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     fmt.Sprintf("invalid format %q, expected json or csv", sanitizeInput(params.Format, 32)),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	var start, end time.Time
+	switch params.Period {
+	case "daily", "day":
+		now := time.Now()
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		end = start.AddDate(0, 0, 1)
+	case "monthly", "month":
+		now := time.Now()
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		end = start.AddDate(0, 1, 0)
+	case "":
+		var ok bool
+		if start, end, ok = parseCostDateRange(c, params.StartDate, params.EndDate); !ok {
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     fmt.Sprintf("invalid period %q: must be one of: day, month", sanitizeInput(params.Period, 32)),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 
-	// Query sessions from the provisioner service
-	sessions, err := s.provisioner.ListSessions(ctx, filter)
+	breakdown, err := s.costTracker.GetBreakdown(c.Request.Context(), groupBy, models.CostQuery{
+		ConsumerID: params.ConsumerID,
+		StartTime:  start,
+		EndTime:    end,
+	}, s.provisioner.GetDeploymentID())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:     "failed to list sessions",
+			Error:     err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	// Convert to response format
-	responses := make([]models.SessionResponse, len(sessions))
-	for i, session := range sessions {
-		responses[i] = session.ToResponse()
+	if format == "json" {
+		c.JSON(http.StatusOK, breakdown)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"sessions": responses,
-		"count":    len(responses),
-	})
-}
-
-func (s *Server) handleGetSession(c *gin.Context) {
-	ctx := c.Request.Context()
-	sessionID := c.Param("id")
-
-	session, err := s.provisioner.GetSession(ctx, sessionID)
-	if err != nil {
-		// Check if the error is a not-found error (return 404) vs other errors (return 500)
-		if errors.Is(err, storage.ErrNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:     err.Error(),
-				RequestID: c.GetString("request_id"),
-			})
-			return
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"period_start", "period_end"}
+	for _, g := range groupBy {
+		header = append(header, string(g))
+	}
+	w.Write(append(header, "total_cost", "session_count", "hours_used"))
+	for _, group := range breakdown.Groups {
+		row := []string{
+			breakdown.PeriodStart.UTC().Format(time.RFC3339),
+			breakdown.PeriodEnd.UTC().Format(time.RFC3339),
 		}
-		// Internal error (DB issues, etc.)
+		for _, g := range groupBy {
+			switch g {
+			case models.CostGroupByConsumer:
+				row = append(row, group.ConsumerID)
+			case models.CostGroupByDeployment:
+				row = append(row, group.Deployment)
+			case models.CostGroupByGPUType:
+				row = append(row, group.GPUType)
+			case models.CostGroupByProvider:
+				row = append(row, group.Provider)
+			}
+		}
+		w.Write(append(row,
+			strconv.FormatFloat(group.TotalCost, 'f', 4, 64),
+			strconv.Itoa(group.SessionCount),
+			strconv.FormatFloat(group.HoursUsed, 'f', -1, 64)))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:     "failed to get session",
+			Error:     "failed to write CSV: " + err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, session.ToResponse())
+	c.Header("Content-Disposition", `attachment; filename="chargeback.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
 }
 
-func (s *Server) handleSessionDone(c *gin.Context) {
+func (s *Server) handleGetCostSummary(c *gin.Context) {
 	ctx := c.Request.Context()
-	sessionID := c.Param("id")
+	consumerID := c.Query("consumer_id")
 
-	if err := s.lifecycle.SignalDone(ctx, sessionID); err != nil {
-		// Bug #1/#70 fix: Return proper HTTP status codes based on error type
-		status := http.StatusInternalServerError
-		if errors.Is(err, storage.ErrNotFound) {
-			status = http.StatusNotFound
-		} else {
-			// Bug #70 fix: Return 409 Conflict for terminal sessions
-			var terminalErr *lifecycle.SessionTerminalError
-			if errors.As(err, &terminalErr) {
-				status = http.StatusConflict
-			}
-		}
-		c.JSON(status, ErrorResponse{
+	summary, err := s.costTracker.GetMonthlySummary(ctx, consumerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:     err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "session shutdown initiated",
-		"session_id": sessionID,
-	})
+	c.JSON(http.StatusOK, summary)
 }
 
-func (s *Server) handleExtendSession(c *gin.Context) {
-	ctx := c.Request.Context()
-	sessionID := c.Param("id")
-
-	var req ExtendSessionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		// Bug #9: Sanitize validation errors to use JSON field names
+// handleCostReconciliation compares recorded session costs with what
+// providers report billing, for sessions billed in the requested dates. It
+// defaults to the current month.
+func (s *Server) handleCostReconciliation(c *gin.Context) {
+	var params CostReconciliationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:     sanitizeValidationError(err),
+			Error:     err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	if err := s.lifecycle.ExtendSession(ctx, sessionID, req.AdditionalHours); err != nil {
-		// Bug #16/#18 fix: Return proper HTTP status codes based on error type
-		// Use typed error checking instead of string matching for reliability
-		status := http.StatusInternalServerError
-		if errors.Is(err, storage.ErrNotFound) {
-			status = http.StatusNotFound
-		} else {
-			// Check for terminal/stopping session error (409 Conflict)
-			var terminalErr *lifecycle.SessionTerminalError
-			if errors.As(err, &terminalErr) {
-				status = http.StatusConflict
-			}
-			// Check for hard max exceeded (400 Bad Request)
-			var hardMaxErr *lifecycle.HardMaxExceededError
-			if errors.As(err, &hardMaxErr) {
-				status = http.StatusBadRequest
-			}
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	if params.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", params.StartDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid start_date format, expected YYYY-MM-DD: %s", sanitizeInput(params.StartDate, 32)),
+				RequestID: c.GetString("request_id"),
+			})
+			return
 		}
-		c.JSON(status, ErrorResponse{
-			Error:     err.Error(),
+		start = parsed
+	}
+	if params.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", params.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid end_date format, expected YYYY-MM-DD: %s", sanitizeInput(params.EndDate, 32)),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "end_date must not be before start_date",
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	// Get updated session
-	session, err := s.provisioner.GetSession(ctx, sessionID)
-	if err != nil || session == nil {
-		// Extension succeeded but couldn't retrieve updated session
-		// Return success without the new expiry time
-		c.JSON(http.StatusOK, gin.H{
-			"message":    "session extended",
-			"session_id": sessionID,
+	report, err := s.costTracker.Reconcile(c.Request.Context(), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":        "session extended",
-		"session_id":     sessionID,
-		"new_expires_at": session.ExpiresAt,
-	})
+	c.JSON(http.StatusOK, report)
 }
 
-func (s *Server) handleDeleteSession(c *gin.Context) {
-	ctx := c.Request.Context()
-	sessionID := c.Param("id")
+// handleCostForecast projects today's and remaining spend across active
+// sessions at their current burn rate
+func (s *Server) handleCostForecast(c *gin.Context) {
+	var params CostForecastParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 
-	if err := s.provisioner.DestroySession(ctx, sessionID); err != nil {
-		// Check for not-found errors and return 404
-		var sessionNotFound *provisioner.SessionNotFoundError
-		if errors.As(err, &sessionNotFound) || errors.Is(err, storage.ErrNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:     fmt.Sprintf("session not found: %s", sanitizeInput(sessionID, 128)),
-				RequestID: c.GetString("request_id"),
-			})
-			return
-		}
+	forecast, err := s.costTracker.ForecastFleet(c.Request.Context(), params.ConsumerID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:     err.Error(),
 			RequestID: c.GetString("request_id"),
@@ -818,16 +2256,21 @@ func (s *Server) handleDeleteSession(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "session destroyed",
-		"session_id": sessionID,
-	})
+	c.JSON(http.StatusOK, forecast)
 }
 
-func (s *Server) handleGetCosts(c *gin.Context) {
-	ctx := c.Request.Context()
+// handleFailureAnalytics breaks down failures of sessions created in a period
+// (default: the last 7 days) by category, provider, GPU type and location
+func (s *Server) handleFailureAnalytics(c *gin.Context) {
+	if s.failureStats == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "failure analytics not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 
-	var params CostQueryParams
+	var params FailureAnalyticsParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:     err.Error(),
@@ -836,97 +2279,104 @@ func (s *Server) handleGetCosts(c *gin.Context) {
 		return
 	}
 
-	// If session ID provided, get session cost
-	if params.SessionID != "" {
-		// Bug #49/#75 fix: Check if session exists before returning cost
-		// Return 404 if session_id provided but not found
-		_, err := s.provisioner.GetSession(ctx, params.SessionID)
+	end := time.Now()
+	start := end.AddDate(0, 0, -7)
+	if params.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", params.EndDate)
 		if err != nil {
-			if errors.Is(err, storage.ErrNotFound) {
-				c.JSON(http.StatusNotFound, ErrorResponse{
-					Error:     fmt.Sprintf("session not found: %s", sanitizeInput(params.SessionID, 128)),
-					RequestID: c.GetString("request_id"),
-				})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:     err.Error(),
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid end_date format, expected YYYY-MM-DD: %s", sanitizeInput(params.EndDate, 32)),
 				RequestID: c.GetString("request_id"),
 			})
 			return
 		}
-
-		cost, err := s.costTracker.GetSessionCost(ctx, params.SessionID)
+		end = parsed.AddDate(0, 0, 1)
+		start = end.AddDate(0, 0, -7)
+	}
+	if params.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", params.StartDate)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:     err.Error(),
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid start_date format, expected YYYY-MM-DD: %s", sanitizeInput(params.StartDate, 32)),
 				RequestID: c.GetString("request_id"),
 			})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{
-			"session_id": params.SessionID,
-			"total_cost": cost,
-			"currency":   "USD",
+		start = parsed
+	}
+	if !end.After(start) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "end_date must not be before start_date",
+			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	// Handle period-based queries
-	var summary *models.CostSummary
-	var err error
+	counts, err := s.failureStats.GetFailureCounts(c.Request.Context(), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 
-	switch params.Period {
-	case "daily":
-		summary, err = s.costTracker.GetDailySummary(ctx, params.ConsumerID)
-	case "monthly":
-		summary, err = s.costTracker.GetMonthlySummary(ctx, params.ConsumerID)
-	default:
-		// Parse custom date range
-		var startTime, endTime time.Time
-		if params.StartDate != "" {
-			var parseErr error
-			startTime, parseErr = time.Parse("2006-01-02", params.StartDate)
-			if parseErr != nil {
-				c.JSON(http.StatusBadRequest, ErrorResponse{
-					Error:     fmt.Sprintf("invalid start_date format, expected YYYY-MM-DD: %s", params.StartDate),
-					RequestID: c.GetString("request_id"),
-				})
-				return
-			}
-		}
-		if params.EndDate != "" {
-			var parseErr error
-			endTime, parseErr = time.Parse("2006-01-02", params.EndDate)
-			if parseErr != nil {
-				c.JSON(http.StatusBadRequest, ErrorResponse{
-					Error:     fmt.Sprintf("invalid end_date format, expected YYYY-MM-DD: %s", params.EndDate),
-					RequestID: c.GetString("request_id"),
-				})
-				return
-			}
-		}
+	c.JSON(http.StatusOK, models.NewFailureAnalytics(start, end, counts))
+}
 
-		// Bug #10: If no dates specified, default to current month to avoid zero dates
-		if params.StartDate == "" && params.EndDate == "" {
-			now := time.Now()
-			startTime = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-			endTime = startTime.AddDate(0, 1, 0)
-		} else if params.StartDate == "" {
-			// If only end date specified, default start to beginning of that month
-			startTime = time.Date(endTime.Year(), endTime.Month(), 1, 0, 0, 0, 0, endTime.Location())
-		} else if params.EndDate == "" {
-			// If only start date specified, default end to end of that month
-			endTime = time.Date(startTime.Year(), startTime.Month()+1, 1, 0, 0, 0, 0, startTime.Location())
-		}
+// handleExportCosts returns hourly cost records as CSV (default) or JSON
+// for spreadsheets and BI tools
+func (s *Server) handleExportCosts(c *gin.Context) {
+	var params CostExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
 
-		summary, err = s.costTracker.GetSummary(ctx, models.CostQuery{
-			ConsumerID: params.ConsumerID,
-			StartTime:  startTime,
-			EndTime:    endTime,
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     fmt.Sprintf("invalid format %q, expected csv or json", sanitizeInput(params.Format, 32)),
+			RequestID: c.GetString("request_id"),
 		})
+		return
+	}
+
+	query := models.CostQuery{
+		ConsumerID: params.ConsumerID,
+		SessionID:  params.SessionID,
+		Provider:   params.Provider,
+	}
+	if params.StartDate != "" {
+		start, err := time.Parse("2006-01-02", params.StartDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid start_date format, expected YYYY-MM-DD: %s", sanitizeInput(params.StartDate, 32)),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		query.StartTime = start
+	}
+	if params.EndDate != "" {
+		end, err := time.Parse("2006-01-02", params.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     fmt.Sprintf("invalid end_date format, expected YYYY-MM-DD: %s", sanitizeInput(params.EndDate, 32)),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		query.EndTime = end.AddDate(0, 0, 1)
 	}
 
+	records, err := s.costTracker.ListRecords(c.Request.Context(), query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:     err.Error(),
@@ -934,24 +2384,44 @@ func (s *Server) handleGetCosts(c *gin.Context) {
 		})
 		return
 	}
+	if records == nil {
+		records = []models.CostRecord{}
+	}
 
-	c.JSON(http.StatusOK, summary)
-}
-
-func (s *Server) handleGetCostSummary(c *gin.Context) {
-	ctx := c.Request.Context()
-	consumerID := c.Query("consumer_id")
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"records": records,
+			"count":   len(records),
+		})
+		return
+	}
 
-	summary, err := s.costTracker.GetMonthlySummary(ctx, consumerID)
-	if err != nil {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"hour", "session_id", "consumer_id", "provider", "gpu_type", "category", "amount", "currency"})
+	for _, r := range records {
+		w.Write([]string{
+			r.Hour.UTC().Format(time.RFC3339),
+			r.SessionID,
+			r.ConsumerID,
+			r.Provider,
+			r.GPUType,
+			string(r.Category),
+			strconv.FormatFloat(r.Amount, 'f', 4, 64),
+			r.Currency,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:     err.Error(),
+			Error:     "failed to write CSV: " + err.Error(),
 			RequestID: c.GetString("request_id"),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, summary)
+	c.Header("Content-Disposition", `attachment; filename="costs.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
 }
 
 func (s *Server) handleGetSessionDiagnostics(c *gin.Context) {
@@ -1094,6 +2564,7 @@ func toSnakeCase(s string) string {
 		"WorkloadType":    "workload_type",
 		"ReservationHrs":  "reservation_hours",
 		"IdleThreshold":   "idle_threshold_minutes",
+		"IdleGPUUtilPct":  "idle_gpu_util_pct",
 		"StoragePolicy":   "storage_policy",
 		"LaunchMode":      "launch_mode",
 		"DockerImage":     "docker_image",
@@ -1133,13 +2604,35 @@ func (s *Server) handleOfferHealth(c *gin.Context) {
 		offers = filtered
 	}
 
+	hosts := s.inventory.GetHostHealth()
+	if providerFilter != "" {
+		var filtered []inventory.HostHealthInfo
+		for _, h := range hosts {
+			if h.Provider == providerFilter {
+				filtered = append(filtered, h)
+			}
+		}
+		hosts = filtered
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"offers":    offers,
 		"gpu_types": gpuTypes,
+		"hosts":     hosts,
 		"count":     len(offers),
 	})
 }
 
+// handleListProviders reports each provider's health, so callers can avoid
+// providers that are degraded or down
+func (s *Server) handleListProviders(c *gin.Context) {
+	providers := s.inventory.GetProviderHealth(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{
+		"providers": providers,
+		"count":     len(providers),
+	})
+}
+
 // Template handlers
 
 func (s *Server) handleListTemplates(c *gin.Context) {
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// CreatePriceWatchRequest is the request body for registering a price watch
+type CreatePriceWatchRequest struct {
+	ConsumerID string  `json:"consumer_id" binding:"required"`
+	GPUType    string  `json:"gpu_type" binding:"required"`
+	MaxPrice   float64 `json:"max_price" binding:"required,gt=0"` // USD per GPU-hour
+	Region     string  `json:"region,omitempty"`
+	Provider   string  `json:"provider,omitempty"`
+}
+
+// handleCreatePriceWatch registers a price watch for a consumer.
+func (s *Server) handleCreatePriceWatch(c *gin.Context) {
+	var req CreatePriceWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid request: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	watch := &models.PriceWatch{
+		ConsumerID: req.ConsumerID,
+		GPUType:    req.GPUType,
+		MaxPrice:   req.MaxPrice,
+		Region:     req.Region,
+		Provider:   req.Provider,
+	}
+	if err := s.inventory.CreatePriceWatch(c.Request.Context(), watch); err != nil {
+		s.writePriceWatchError(c, err, "failed to create price watch")
+		return
+	}
+
+	c.JSON(http.StatusCreated, watch)
+}
+
+// handleListPriceWatches lists price watches, optionally filtered by consumer.
+func (s *Server) handleListPriceWatches(c *gin.Context) {
+	watches, err := s.inventory.ListPriceWatches(c.Request.Context(), c.Query("consumer_id"))
+	if err != nil {
+		s.writePriceWatchError(c, err, "failed to list price watches")
+		return
+	}
+	if watches == nil {
+		watches = []*models.PriceWatch{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watches": watches,
+		"count":   len(watches),
+	})
+}
+
+// handleDeletePriceWatch removes a price watch.
+func (s *Server) handleDeletePriceWatch(c *gin.Context) {
+	id := c.Param("id")
+	if err := s.inventory.DeletePriceWatch(c.Request.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "price watch not found: " + sanitizeInput(id, 128),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		s.writePriceWatchError(c, err, "failed to delete price watch")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// writePriceWatchError maps inventory price watch errors to HTTP responses.
+func (s *Server) writePriceWatchError(c *gin.Context, err error, msg string) {
+	var invalidErr *inventory.InvalidPriceWatchError
+	var providerErr *inventory.ProviderNotFoundError
+	switch {
+	case errors.Is(err, inventory.ErrPriceWatchesDisabled):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+	case errors.As(err, &invalidErr), errors.As(err, &providerErr):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     msg + ": " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+)
+
+// limiterIdleTTL is how long a client's bucket is kept after its last request
+const limiterIdleTTL = 10 * time.Minute
+
+// maxLimiterClients triggers an early sweep of full buckets, which are no
+// different from new ones, when that many clients are tracked
+const maxLimiterClients = 10000
+
+// clientLimiter is one client's token bucket
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps a token bucket per client, keyed by API key when the
+// request is authenticated and by IP address otherwise.
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+func newRateLimiter(limit rate.Limit, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		limit:     limit,
+		burst:     burst,
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// reserve takes n tokens for client, capped at the burst size. It returns
+// zero if the request may proceed, or how long the client must wait before
+// retrying.
+func (l *rateLimiter) reserve(client string, n int) time.Duration {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop idle buckets so the map does not grow with every address seen
+	if now.Sub(l.lastSweep) > limiterIdleTTL || len(l.clients) >= maxLimiterClients {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > limiterIdleTTL || c.limiter.TokensAt(now) >= float64(l.burst) {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[client]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[client] = c
+	}
+	c.lastSeen = now
+
+	r := c.limiter.ReserveN(now, min(n, l.burst))
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// WithRateLimit limits every /api/v1 request to rps requests per second per
+// API key or client IP, allowing bursts of up to burst requests. Zero rps
+// disables the limit.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(s *Server) {
+		if rps > 0 {
+			s.rateLimiter = newRateLimiter(rate.Limit(rps), burst)
+		}
+	}
+}
+
+// WithCreateSessionRateLimit additionally limits session creation to
+// perMinute sessions per minute per API key or client IP, so a runaway
+// client cannot exhaust provider quotas. Zero disables the limit.
+func WithCreateSessionRateLimit(perMinute float64, burst int) Option {
+	return func(s *Server) {
+		if perMinute > 0 {
+			s.createSessionLimiter = newRateLimiter(rate.Limit(perMinute/60), burst)
+		}
+	}
+}
+
+// ParseTrustedProxies parses a comma-separated list of proxy IP addresses
+// and CIDR ranges, e.g. "10.0.0.0/8,192.168.1.10"
+func ParseTrustedProxies(spec string) ([]string, error) {
+	var proxies []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, expected an IP address or CIDR range", entry)
+		}
+		proxies = append(proxies, entry)
+	}
+	return proxies, nil
+}
+
+// WithTrustedProxies sets the reverse proxies whose X-Forwarded-For and
+// X-Real-IP headers give the client IP, for rate limits and logs. By default
+// no proxy is trusted and the client IP is the connection's peer address, so
+// clients cannot pick their own rate limit bucket with a forged header.
+func WithTrustedProxies(proxies []string) Option {
+	return func(s *Server) {
+		s.trustedProxies = proxies
+	}
+}
+
+// rateLimitMiddleware rejects requests over limiter's rate with 429 and a
+// Retry-After header. It must run after authMiddleware so that requests are
+// only keyed by API keys that were actually accepted.
+func (s *Server) rateLimitMiddleware(limiter *rateLimiter, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.allowRequest(c, limiter, scope, 1) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// allowRequest takes n tokens from the caller's bucket in limiter. If the
+// bucket is empty it aborts with 429 and returns false.
+func (s *Server) allowRequest(c *gin.Context, limiter *rateLimiter, scope string, n int) bool {
+	if limiter == nil {
+		return true
+	}
+
+	client := "ip:" + c.ClientIP()
+	if _, ok := c.Get("api_role"); ok {
+		client = "key:" + strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+
+	delay := limiter.reserve(client, n)
+	if delay == 0 {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(delay.Seconds()))
+	metrics.RecordRateLimited(scope)
+	s.logger.Warn("rate limit exceeded",
+		slog.String("scope", scope),
+		slog.String("client_ip", c.ClientIP()),
+		slog.String("path", c.Request.URL.Path),
+		slog.Int("retry_after_seconds", retryAfter))
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error":               "rate limit exceeded",
+		"error_type":          "rate_limited",
+		"retry_after_seconds": retryAfter,
+		"request_id":          c.GetString("request_id"),
+	})
+	return false
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Role is the access level granted to an API key. Roles are ordered: each
+// role may do everything the roles below it can.
+type Role int
+
+const (
+	RoleViewer   Role = iota + 1 // Read-only: inventory, sessions, costs
+	RoleOperator                 // Provision, extend and destroy sessions
+	RoleAdmin                    // Admin API and reconciler sweeps
+)
+
+// String returns the role name used in configuration and error responses
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseRole parses a role name
+func ParseRole(name string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "viewer":
+		return RoleViewer, nil
+	case "operator":
+		return RoleOperator, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("unknown role %q (valid roles: viewer, operator, admin)", name)
+	}
+}
+
+// APIKey is a bearer token and the role it grants. A key bound to a
+// consumer manages only that consumer's webhooks and SSH keys.
+type APIKey struct {
+	Key        string
+	Role       Role
+	ConsumerID string // Empty = not bound to a consumer
+}
+
+// ParseAPIKeys parses a comma-separated list of key:role pairs, e.g.
+// "dash-key:viewer,ci-key:operator". A role may be followed by @consumer to
+// bind the key to a consumer, e.g. "team-a-key:operator@team-a".
+func ParseAPIKeys(spec string) ([]APIKey, error) {
+	var keys []APIKey
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid API key entry %q, expected key:role", entry)
+		}
+		roleName, consumerID, bound := strings.Cut(entry[i+1:], "@")
+		role, err := ParseRole(roleName)
+		if err != nil {
+			return nil, err
+		}
+		if bound && consumerID == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected key:role@consumer", entry)
+		}
+		key := entry[:i]
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate API key in entry %q", entry)
+		}
+		seen[key] = true
+		keys = append(keys, APIKey{Key: key, Role: role, ConsumerID: consumerID})
+	}
+	return keys, nil
+}
+
+// WithAPIKeys enables role-based access control on /api/v1. Every request
+// must then present one of the keys as a bearer token. The admin API key, if
+// set, is accepted with RoleAdmin.
+func WithAPIKeys(keys []APIKey) Option {
+	return func(s *Server) {
+		s.apiKeys = keys
+	}
+}
+
+// rbacEnabled reports whether API keys are configured
+func (s *Server) rbacEnabled() bool {
+	return len(s.apiKeys) > 0
+}
+
+// lookupKey returns the API key matching a bearer token. Every key is
+// compared so the lookup time does not reveal which key matched.
+func (s *Server) lookupKey(token string) (APIKey, bool) {
+	var match APIKey
+	for _, k := range s.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(k.Key)) == 1 {
+			match = k
+		}
+	}
+	if s.adminAPIKey != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminAPIKey)) == 1 {
+		match = APIKey{Key: s.adminAPIKey, Role: RoleAdmin}
+	}
+	return match, match.Role != 0
+}
+
+// defaultRole is the policy for routes without a declaration: reads need a
+// viewer key, anything that changes state needs an operator.
+func defaultRole(method string) Role {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RoleViewer
+	default:
+		return RoleOperator
+	}
+}
+
+// authMiddleware authenticates the API key and enforces the default policy
+// for the request method. Routes that need more declare it with requireRole.
+func (s *Server) authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.rbacEnabled() {
+			c.Next()
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		key, ok := s.lookupKey(token)
+		if token == "" || !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:     "missing or invalid API key",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+
+		c.Set("api_role", key.Role)
+		c.Set("api_consumer", key.ConsumerID)
+		if !s.authorize(c, defaultRole(c.Request.Method)) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireRole declares the minimum role for a route or group. It is a no-op
+// when access control is disabled.
+func (s *Server) requireRole(required Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.rbacEnabled() {
+			c.Next()
+			return
+		}
+		if !s.authorize(c, required) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// authorize aborts with 403 unless the authenticated role meets required
+func (s *Server) authorize(c *gin.Context, required Role) bool {
+	role, _ := c.Get("api_role")
+	granted, _ := role.(Role)
+	if granted >= required {
+		return true
+	}
+
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":         fmt.Sprintf("%s role cannot perform this action", granted),
+		"error_type":    "insufficient_role",
+		"role":          granted.String(),
+		"required_role": required.String(),
+		"request_id":    c.GetString("request_id"),
+	})
+	return false
+}
+
+// authorizeConsumer aborts with 403 unless the API key may act for
+// consumerID. Keys bound to a consumer act only for it, admin keys for
+// anyone, and other keys need the unbound role. It is a no-op when access
+// control is disabled.
+func (s *Server) authorizeConsumer(c *gin.Context, consumerID string, unbound Role) bool {
+	if !s.rbacEnabled() {
+		return true
+	}
+	bound := c.GetString("api_consumer")
+	role, _ := c.Get("api_role")
+	if granted, _ := role.(Role); bound == "" || granted >= RoleAdmin {
+		return s.authorize(c, unbound)
+	}
+	if bound == consumerID {
+		return true
+	}
+
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":      fmt.Sprintf("API key cannot act for consumer %s", sanitizeInput(consumerID, 128)),
+		"error_type": "consumer_scope",
+		"request_id": c.GetString("request_id"),
+	})
+	return false
+}
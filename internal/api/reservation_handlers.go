@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// QueuedSessionResponse is a queued session request and, once fulfilled,
+// the session that was created for it
+type QueuedSessionResponse struct {
+	Reservation   *models.QueuedReservation `json:"reservation"`
+	Session       *models.SessionResponse   `json:"session,omitempty"`
+	SSHPrivateKey string                    `json:"ssh_private_key,omitempty"` // Only returned once
+}
+
+// enqueueSession queues a create request whose offer is gone and responds 202
+func (s *Server) enqueueSession(c *gin.Context, createReq models.CreateSessionRequest, opts *QueueOptions) {
+	maxWait := time.Duration(opts.MaxWaitMinutes) * time.Minute
+	r, err := s.reservations.Enqueue(c.Request.Context(), createReq, opts.Filter, maxWait)
+	if err != nil {
+		s.writeReservationError(c, err, "failed to queue session request")
+		return
+	}
+
+	s.logger.Info("session request queued for inventory",
+		slog.String("reservation_id", r.ID),
+		slog.String("consumer_id", r.ConsumerID),
+		slog.String("offer_id", sanitizeInput(createReq.OfferID, 128)),
+		slog.Int("position", r.Position))
+
+	c.JSON(http.StatusAccepted, QueuedSessionResponse{Reservation: r})
+}
+
+// handleListQueuedSessions lists queued session requests, optionally
+// filtered by consumer_id and status
+func (s *Server) handleListQueuedSessions(c *gin.Context) {
+	if !s.requireReservations(c) {
+		return
+	}
+
+	status := models.ReservationStatus(c.Query("status"))
+	reservations, err := s.reservations.List(c.Request.Context(), c.Query("consumer_id"), status)
+	if err != nil {
+		s.writeReservationError(c, err, "failed to list queued session requests")
+		return
+	}
+	if reservations == nil {
+		reservations = []*models.QueuedReservation{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reservations": reservations,
+		"count":        len(reservations),
+	})
+}
+
+// handleGetQueuedSession returns a queued request with its queue position,
+// or the session that fulfilled it
+func (s *Server) handleGetQueuedSession(c *gin.Context) {
+	if !s.requireReservations(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	r, err := s.reservations.Get(ctx, c.Param("id"))
+	if err != nil {
+		s.writeReservationError(c, err, "failed to get queued session request")
+		return
+	}
+
+	resp := QueuedSessionResponse{Reservation: r}
+	if r.SessionID != "" {
+		if session, err := s.provisioner.GetSession(ctx, r.SessionID); err == nil {
+			sessionResp := session.ToResponse()
+			resp.Session = &sessionResp
+		}
+		resp.SSHPrivateKey = s.reservations.TakePrivateKey(r.ID)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleCancelQueuedSession withdraws a waiting request
+func (s *Server) handleCancelQueuedSession(c *gin.Context) {
+	if !s.requireReservations(c) {
+		return
+	}
+
+	r, err := s.reservations.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.writeReservationError(c, err, "failed to cancel queued session request")
+		return
+	}
+
+	c.JSON(http.StatusOK, QueuedSessionResponse{Reservation: r})
+}
+
+func (s *Server) requireReservations(c *gin.Context) bool {
+	if s.reservations == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "session queue not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+	return true
+}
+
+// writeReservationError maps reservation queue errors to HTTP responses
+func (s *Server) writeReservationError(c *gin.Context, err error, msg string) {
+	var invalidErr *provisioner.InvalidReservationError
+	var notWaitingErr *provisioner.ReservationNotWaitingError
+	switch {
+	case errors.As(err, &invalidErr):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+	case errors.As(err, &notWaitingErr):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:     err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:     "queued session request not found: " + sanitizeInput(c.Param("id"), 128),
+			RequestID: c.GetString("request_id"),
+		})
+	default:
+		s.logger.Error(msg, slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     msg,
+			RequestID: c.GetString("request_id"),
+		})
+	}
+}
@@ -17,11 +17,17 @@ import (
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/notify"
 	benchsvc "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/cost"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/featureflags"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/lifecycle"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/ranking"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/sessionexport"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
 // Server is the HTTP API server
@@ -38,11 +44,51 @@ type Server struct {
 	benchmarkStore     *benchmark.Store
 	benchmarkRunner    *benchsvc.Runner
 	benchmarkScheduler *benchsvc.Scheduler
+	benchmarkCatalog   *benchmark.Catalog
+	budgetService      *budget.Service
+	spendGuard         *budget.SpendGuard
+	auditStore         AuditStore
+	notifier           *notify.Notifier
+	sessionEvents      SessionEventStore
+	failureStats       FailureStatsStore
+	featureFlags       *featureflags.Service
+	sessionExport      *sessionexport.Service
+	reservations       *provisioner.ReservationQueue
+	ranker             *ranking.Service
+
+	// Admin API key; admin routes are disabled when empty
+	adminAPIKey string
+
+	// API keys with roles; access control is disabled when empty
+	apiKeys []APIKey
+
+	// Reconciler swept on demand through the admin API
+	reconciler Reconciler
+
+	// Adopts and releases unclaimed provider instances through the admin API
+	instanceClaimer InstanceClaimer
+
+	// Instances that could not be destroyed, resolved through the admin API
+	quarantine QuarantineStore
+
+	// Whether secret columns are encrypted at rest, which accepting
+	// hf_token requires
+	databaseEncryption bool
+
+	// Reverse proxies trusted to report the client IP; none by default
+	trustedProxies []string
+
+	// Per-client rate limits; nil when disabled
+	rateLimiter          *rateLimiter
+	createSessionLimiter *rateLimiter
 
 	// Configuration
 	host string
 	port int
 
+	// How often benchmark run streams check for progress
+	benchmarkStreamInterval time.Duration
+
 	// Readiness state (atomic for thread-safe access)
 	ready atomic.Bool
 }
@@ -85,6 +131,14 @@ func WithBenchmarkRunner(runner *benchsvc.Runner) Option {
 	}
 }
 
+// WithBenchmarkCatalog serves the model catalog and enables managing its
+// custom entries in the admin API
+func WithBenchmarkCatalog(catalog *benchmark.Catalog) Option {
+	return func(s *Server) {
+		s.benchmarkCatalog = catalog
+	}
+}
+
 // WithBenchmarkScheduler sets the benchmark scheduler
 func WithBenchmarkScheduler(scheduler *benchsvc.Scheduler) Option {
 	return func(s *Server) {
@@ -92,6 +146,138 @@ func WithBenchmarkScheduler(scheduler *benchsvc.Scheduler) Option {
 	}
 }
 
+// WithBudgetService sets the budget service
+func WithBudgetService(svc *budget.Service) Option {
+	return func(s *Server) {
+		s.budgetService = svc
+	}
+}
+
+// WithDatabaseEncryption tells the server whether the database encrypts
+// secret columns. Without it, requests carrying hf_token are rejected rather
+// than storing the token in plaintext.
+func WithDatabaseEncryption(enabled bool) Option {
+	return func(s *Server) {
+		s.databaseEncryption = enabled
+	}
+}
+
+// WithSpendGuard exposes the provider-reported spend kill switch in the admin API
+func WithSpendGuard(g *budget.SpendGuard) Option {
+	return func(s *Server) {
+		s.spendGuard = g
+	}
+}
+
+// SessionEventStore provides session status history
+type SessionEventStore interface {
+	ListBySession(ctx context.Context, sessionID string) ([]*models.SessionEvent, error)
+}
+
+// FailureStatsStore provides session failure counts for failure analytics
+type FailureStatsStore interface {
+	GetFailureCounts(ctx context.Context, start, end time.Time) ([]models.FailureCount, error)
+}
+
+// WithFailureStatsStore enables the failure analytics endpoint
+func WithFailureStatsStore(store FailureStatsStore) Option {
+	return func(s *Server) {
+		s.failureStats = store
+	}
+}
+
+// WithSessionEventStore enables the session status history endpoint
+func WithSessionEventStore(store SessionEventStore) Option {
+	return func(s *Server) {
+		s.sessionEvents = store
+	}
+}
+
+// WithNotifier enables webhook subscription management
+func WithNotifier(n *notify.Notifier) Option {
+	return func(s *Server) {
+		s.notifier = n
+	}
+}
+
+// WithFeatureFlags enables feature flag management in the admin API
+func WithFeatureFlags(svc *featureflags.Service) Option {
+	return func(s *Server) {
+		s.featureFlags = svc
+	}
+}
+
+// WithSessionExport enables session export and import in the admin API
+func WithSessionExport(svc *sessionexport.Service) Option {
+	return func(s *Server) {
+		s.sessionExport = svc
+	}
+}
+
+// WithReservationQueue lets session requests wait for inventory instead of failing
+func WithReservationQueue(q *provisioner.ReservationQueue) Option {
+	return func(s *Server) {
+		s.reservations = q
+	}
+}
+
+// WithRanker sets the offer ranking service used by the inventory API
+func WithRanker(r *ranking.Service) Option {
+	return func(s *Server) {
+		s.ranker = r
+	}
+}
+
+// Reconciler runs a provider/database reconciliation pass
+type Reconciler interface {
+	RunReconciliation(ctx context.Context)
+	AuditTerminations(ctx context.Context) *models.TerminationAudit
+}
+
+// WithReconciler enables on-demand reconciler sweeps in the admin API
+func WithReconciler(r Reconciler) Option {
+	return func(s *Server) {
+		s.reconciler = r
+	}
+}
+
+// InstanceClaimer lists, adopts and releases shopper-labeled provider
+// instances no session owns
+type InstanceClaimer interface {
+	ListUnclaimed(ctx context.Context) *models.UnclaimedInstances
+	AdoptInstance(ctx context.Context, provider, instanceID, consumerID string, reservationHrs int) (*models.Session, error)
+	ReleaseInstance(ctx context.Context, provider, instanceID, releasedBy string) (*models.InstanceRelease, error)
+	UnreleaseInstance(ctx context.Context, provider, instanceID string) error
+}
+
+// WithInstanceClaimer enables claiming unclaimed instances in the admin API
+func WithInstanceClaimer(c InstanceClaimer) Option {
+	return func(s *Server) {
+		s.instanceClaimer = c
+	}
+}
+
+// QuarantineStore lists and resolves instances that could not be destroyed
+type QuarantineStore interface {
+	ListQuarantined(ctx context.Context) ([]*models.QuarantinedInstance, error)
+	Resolve(ctx context.Context, provider, instanceID string) error
+}
+
+// WithQuarantineStore enables the quarantine endpoints of the admin API
+func WithQuarantineStore(store QuarantineStore) Option {
+	return func(s *Server) {
+		s.quarantine = store
+	}
+}
+
+// WithAdmin enables the admin API, authenticated by apiKey and audited to store
+func WithAdmin(apiKey string, store AuditStore) Option {
+	return func(s *Server) {
+		s.adminAPIKey = apiKey
+		s.auditStore = store
+	}
+}
+
 // New creates a new API server
 func New(
 	inv *inventory.Service,
@@ -108,12 +294,19 @@ func New(
 		costTracker: ct,
 		host:        "0.0.0.0",
 		port:        8080,
+
+		benchmarkStreamInterval: 2 * time.Second,
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
 
+	// Without historical data, rank on what the offers themselves report
+	if s.ranker == nil {
+		s.ranker = ranking.New(ranking.WithLogger(s.logger))
+	}
+
 	s.setupRouter()
 	return s
 }
@@ -133,6 +326,10 @@ func (s *Server) IsReady() bool {
 func (s *Server) setupRouter() {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	// Parsed by ParseTrustedProxies, so this only fails on a programming error
+	if err := router.SetTrustedProxies(s.trustedProxies); err != nil {
+		s.logger.Error("invalid trusted proxies", slog.String("error", err.Error()))
+	}
 
 	// Add middleware
 	router.Use(s.requestIDMiddleware())
@@ -143,38 +340,118 @@ func (s *Server) setupRouter() {
 
 	// Health and readiness endpoints
 	router.GET("/health", s.handleHealth)
+	router.GET("/healthz", s.handleHealth)
 	router.GET("/ready", s.handleReady)
 
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	// API v1 routes
-	v1 := router.Group("/api/v1")
+	// Operator dashboard (static; its data comes from /api/v1)
+	router.Group("/dashboard", dashboardHeadersMiddleware()).StaticFS("/", dashboardFS())
+
+	// API v1 routes. Reads need a viewer key and writes an operator key
+	// when access control is enabled; stricter routes declare requireRole.
+	v1 := router.Group("/api/v1", s.authMiddleware(), s.rateLimitMiddleware(s.rateLimiter, "api"))
 	{
 		// Inventory
 		v1.GET("/inventory", s.handleListInventory)
+		v1.GET("/inventory/history", s.handleGetPriceHistory)
+		v1.GET("/inventory/summary", s.handleGetInventorySummary)
 		v1.GET("/inventory/:id", s.handleGetOffer)
 		v1.GET("/inventory/:id/compatible-templates", s.handleGetCompatibleTemplates)
 
+		// Price watches
+		v1.POST("/watches", s.handleCreatePriceWatch)
+		v1.GET("/watches", s.handleListPriceWatches)
+		v1.DELETE("/watches/:id", s.handleDeletePriceWatch)
+
 		// Templates (Vast.ai only)
 		v1.GET("/templates", s.handleListTemplates)
 		v1.GET("/templates/:hash_id", s.handleGetTemplate)
 
 		// Sessions
-		v1.POST("/sessions", s.handleCreateSession)
+		v1.POST("/sessions", s.rateLimitMiddleware(s.createSessionLimiter, "create_session"), s.handleCreateSession)
+		v1.POST("/sessions/batch", s.handleBatchCreateSessions)
 		v1.GET("/sessions", s.handleListSessions)
+		v1.GET("/sessions/search", s.handleSearchSessions)
 		v1.GET("/sessions/:id", s.handleGetSession)
 		v1.GET("/sessions/:id/diagnostics", s.handleGetSessionDiagnostics)
-		v1.POST("/sessions/:id/done", s.handleSessionDone)
+		v1.GET("/sessions/:id/events", s.handleGetSessionEvents)
+		v1.GET("/sessions/:id/logs", s.handleGetSessionLogs)
+		v1.GET("/sessions/:id/cost", s.handleGetSessionCost)
+		v1.GET("/sessions/:id/ssh-key", s.requireRole(RoleOperator), s.handleTakeReplacementKey)
+		v1.POST("/sessions/:id/done", s.requireRole(RoleOperator), s.handleSessionDone)
 		v1.POST("/sessions/:id/extend", s.handleExtendSession)
-		v1.DELETE("/sessions/:id", s.handleDeleteSession)
+		v1.PATCH("/sessions/:id/extend", s.handleExtendSession)
+		v1.PATCH("/sessions/:id/pause", s.requireRole(RoleOperator), s.handlePauseSession)
+		v1.PATCH("/sessions/:id/resume", s.requireRole(RoleOperator), s.handleResumeSession)
+		v1.POST("/sessions/:id/reboot", s.requireRole(RoleOperator), s.handleRebootSession)
+		v1.DELETE("/sessions/:id", s.requireRole(RoleOperator), s.handleDeleteSession)
+
+		// Session requests waiting for inventory
+		v1.GET("/session-queue", s.handleListQueuedSessions)
+		v1.GET("/session-queue/:id", s.handleGetQueuedSession)
+		v1.DELETE("/session-queue/:id", s.requireRole(RoleOperator), s.handleCancelQueuedSession)
+
+		// Session groups
+		v1.GET("/session-groups/:id", s.handleGetSessionGroup)
+		v1.DELETE("/session-groups/:id", s.requireRole(RoleOperator), s.handleDeleteSessionGroup)
 
 		// Costs
 		v1.GET("/costs", s.handleGetCosts)
 		v1.GET("/costs/summary", s.handleGetCostSummary)
+		v1.GET("/costs/export", s.handleExportCosts)
+		v1.GET("/costs/reconciliation", s.handleCostReconciliation)
+		v1.GET("/costs/forecast", s.handleCostForecast)
+
+		// Analytics
+		v1.GET("/analytics/failures", s.handleFailureAnalytics)
+
+		// Budgets (spend caps)
+		v1.POST("/budgets", s.requireRole(RoleAdmin), s.handleSetBudget)
+		v1.GET("/budgets", s.handleListBudgets)
+		v1.GET("/budgets/status", s.handleGetBudgetStatus)
+		v1.DELETE("/budgets/:id", s.requireRole(RoleAdmin), s.handleDeleteBudget)
+
+		// Webhook subscriptions
+		v1.POST("/webhooks", s.handleCreateWebhook)
+		v1.GET("/webhooks", s.handleListWebhooks)
+		v1.DELETE("/webhooks/:id", s.handleDeleteWebhook)
+		v1.GET("/webhooks/:id/deliveries", s.handleListWebhookDeliveries)
+
+		// Admin support tooling (acts on behalf of consumers, audited)
+		admin := v1.Group("/admin", s.requireRole(RoleAdmin), s.adminAuthMiddleware())
+		admin.GET("/audit", s.handleAdminListAudit)
+		admin.GET("/consumers/:consumer_id/sessions", s.handleAdminListSessions)
+		admin.POST("/consumers/:consumer_id/sessions/:id/ssh-key", s.handleAdminRegenerateSSHKey)
+		admin.POST("/consumers/:consumer_id/sessions/:id/extend", s.handleAdminExtendSession)
+		admin.DELETE("/consumers/:consumer_id/sessions/:id", s.handleAdminDestroySession)
+		admin.GET("/feature-flags", s.handleAdminListFeatureFlags)
+		admin.PUT("/feature-flags/:name", s.handleAdminSetFeatureFlag)
+		admin.DELETE("/feature-flags/:name", s.handleAdminResetFeatureFlag)
+		admin.POST("/sessions/export", s.handleAdminExportSessions)
+		admin.POST("/sessions/import", s.handleAdminImportSessions)
+		admin.POST("/reconcile", s.handleAdminReconcile)
+		admin.GET("/terminations", s.handleAdminAuditTerminations)
+		admin.GET("/instances/unclaimed", s.handleAdminListUnclaimed)
+		admin.POST("/instances/:provider/:instance_id/adopt", s.handleAdminAdoptInstance)
+		admin.POST("/instances/:provider/:instance_id/release", s.handleAdminReleaseInstance)
+		admin.DELETE("/instances/:provider/:instance_id/release", s.handleAdminUnreleaseInstance)
+		admin.GET("/quarantine", s.handleAdminListQuarantine)
+		admin.DELETE("/quarantine/:provider/:instance_id", s.handleAdminResolveQuarantine)
+		admin.GET("/spend", s.handleAdminSpendStatus)
+		admin.POST("/benchmark-catalog/models", s.handleAdminAddCatalogModel)
+		admin.DELETE("/benchmark-catalog/models/*name", s.handleAdminRemoveCatalogModel) // Hugging Face IDs contain '/'
+		admin.POST("/benchmark-catalog/gpus", s.handleAdminAddCatalogGPU)
+		admin.DELETE("/benchmark-catalog/gpus/:name", s.handleAdminRemoveCatalogGPU)
+		admin.GET("/offer-suppressions", s.handleAdminListOfferSuppressions)
+		admin.DELETE("/offer-suppressions/:offer_id", s.handleAdminClearOfferSuppression)
+		admin.POST("/offer-rules", s.handleAdminSetOfferRule)
+		admin.DELETE("/offer-rules/:id", s.handleAdminDeleteOfferRule)
 
 		// Offer health (global failure tracking)
 		v1.GET("/offer-health", s.handleOfferHealth)
+		v1.GET("/providers", s.handleListProviders)
 
 		// Benchmarks
 		v1.GET("/benchmarks", s.handleListBenchmarks)
@@ -183,11 +460,16 @@ func (s *Server) setupRouter() {
 		v1.GET("/benchmarks/best", s.handleGetBestBenchmark)
 		v1.GET("/benchmarks/cheapest", s.handleGetCheapestBenchmark)
 		v1.GET("/benchmarks/compare", s.handleCompareBenchmarks)
+		v1.GET("/benchmarks/history", s.handleBenchmarkHistory)
+		v1.GET("/benchmarks/report", s.handleBenchmarkReport)
 		v1.GET("/benchmarks/recommendations", s.handleGetHardwareRecommendations)
+		v1.GET("/benchmarks/workloads", s.handleListBenchmarkWorkloads)
+		v1.GET("/benchmarks/catalog", s.handleGetBenchmarkCatalog)
 
 		// Benchmark Runs (automated orchestration)
 		v1.POST("/benchmark-runs", s.handleStartBenchmarkRun)
 		v1.GET("/benchmark-runs/:id", s.handleGetBenchmarkRun)
+		v1.GET("/benchmark-runs/:id/events", s.handleStreamBenchmarkRun)
 		v1.DELETE("/benchmark-runs/:id", s.handleCancelBenchmarkRun)
 
 		// Benchmark Schedules (recurring automation)
@@ -3,17 +3,31 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/notify"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	benchsvc "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/cost"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/featureflags"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/lifecycle"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/ranking"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/sessionexport"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -93,7 +107,12 @@ func (m *mockTemplateProvider) GetCompatibleTemplates(ctx context.Context, offer
 	return result, nil
 }
 
+func (m *mockTemplateProvider) GetInstanceLogs(ctx context.Context, instanceID string, tail int) (string, error) {
+	return fmt.Sprintf("%d lines from %s\n", tail, instanceID), nil
+}
+
 type mockSessionStore struct {
+	mu       sync.RWMutex
 	sessions map[string]*models.Session
 }
 
@@ -102,11 +121,15 @@ func newMockSessionStore() *mockSessionStore {
 }
 
 func (m *mockSessionStore) Create(ctx context.Context, session *models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.sessions[session.ID] = session
 	return nil
 }
 
 func (m *mockSessionStore) Get(ctx context.Context, id string) (*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	s, ok := m.sessions[id]
 	if !ok {
 		return nil, storage.ErrNotFound
@@ -115,11 +138,34 @@ func (m *mockSessionStore) Get(ctx context.Context, id string) (*models.Session,
 }
 
 func (m *mockSessionStore) Update(ctx context.Context, session *models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.sessions[session.ID] = session
 	return nil
 }
 
+func (m *mockSessionStore) UpdateHealth(ctx context.Context, sessionID string, health models.SessionHealth) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	s.Health = health
+	return nil
+}
+
+func (m *mockSessionStore) UpdateThroughput(ctx context.Context, sessionID string, inetDownMbps, diskBandwidthMBps float64) error {
+	return nil
+}
+
+func (m *mockSessionStore) SetRebootingUntil(ctx context.Context, sessionID string, until time.Time) error {
+	return nil
+}
+
 func (m *mockSessionStore) GetActiveSessionByConsumerAndOffer(ctx context.Context, consumerID, offerID string) (*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	for _, session := range m.sessions {
 		if session.ConsumerID == consumerID && session.OfferID == offerID {
 			if session.Status == models.StatusPending ||
@@ -133,6 +179,8 @@ func (m *mockSessionStore) GetActiveSessionByConsumerAndOffer(ctx context.Contex
 }
 
 func (m *mockSessionStore) GetActiveSessions(ctx context.Context) ([]*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	var result []*models.Session
 	for _, s := range m.sessions {
 		if s.IsActive() {
@@ -146,6 +194,18 @@ func (m *mockSessionStore) GetExpiredSessions(ctx context.Context) ([]*models.Se
 	return nil, nil
 }
 
+func (m *mockSessionStore) GetActiveSessionsByGroup(ctx context.Context, groupID string) ([]*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*models.Session
+	for _, s := range m.sessions {
+		if s.GroupID == groupID && s.IsActive() {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockSessionStore) GetFailedSessionsWithInstances(ctx context.Context) ([]*models.Session, error) {
 	return nil, nil
 }
@@ -155,6 +215,8 @@ func (m *mockSessionStore) GetSessionsByStatus(ctx context.Context, statuses ...
 }
 
 func (m *mockSessionStore) List(ctx context.Context, filter models.SessionListFilter) ([]*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	var result []*models.Session
 	for _, session := range m.sessions {
 		if filter.ConsumerID != "" && session.ConsumerID != filter.ConsumerID {
@@ -163,14 +225,64 @@ func (m *mockSessionStore) List(ctx context.Context, filter models.SessionListFi
 		if filter.Status != "" && session.Status != filter.Status {
 			continue
 		}
+		if filter.GroupID != "" && session.GroupID != filter.GroupID {
+			continue
+		}
+		if filter.Health != "" && session.Health.Status != filter.Health {
+			continue
+		}
+		if filter.ExcludeTerminal && session.IsTerminal() {
+			continue
+		}
+		if filter.GPUType != "" && !strings.EqualFold(session.GPUType, filter.GPUType) {
+			continue
+		}
+		if !filter.CreatedAfter.IsZero() && session.CreatedAt.Before(filter.CreatedAfter) {
+			continue
+		}
+		if !filter.CreatedBefore.IsZero() && !session.CreatedAt.Before(filter.CreatedBefore) {
+			continue
+		}
+		if filter.Search != "" && !mockSearchMatches(session, filter.Search) {
+			continue
+		}
 		result = append(result, session)
-		if filter.Limit > 0 && len(result) >= filter.Limit {
-			break
+	}
+	// Sorts like the store, except that cost is not known here
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if filter.SortAscending {
+			a, b = b, a
 		}
+		if filter.SortBy == models.SessionSortPrice && a.PricePerHour != b.PricePerHour {
+			return a.PricePerHour > b.PricePerHour
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	if filter.Offset >= len(result) {
+		return nil, nil
+	}
+	result = result[filter.Offset:]
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
 	}
 	return result, nil
 }
 
+// mockSearchMatches matches like the store's LIKE fallback
+func mockSearchMatches(session *models.Session, search string) bool {
+	search = strings.ToLower(search)
+	for _, field := range []string{session.Error, session.FailureDetail, session.OfferID, session.ProviderID, session.ConsumerID} {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
 type mockCostStore struct {
 	records []*models.CostRecord
 }
@@ -198,6 +310,10 @@ func (m *mockCostStore) GetSessionCost(ctx context.Context, sessionID string) (f
 	return total, nil
 }
 
+func (m *mockCostStore) GetSessionCategoryCost(ctx context.Context, sessionID string, category models.CostCategory, before time.Time) (float64, error) {
+	return 0, nil
+}
+
 func (m *mockCostStore) GetConsumerCost(ctx context.Context, consumerID string, start, end time.Time) (float64, error) {
 	return 0, nil
 }
@@ -212,6 +328,65 @@ func (m *mockCostStore) GetSummary(ctx context.Context, query models.CostQuery)
 	}, nil
 }
 
+func (m *mockCostStore) GetBreakdown(ctx context.Context, groupBy []models.CostGroupBy, query models.CostQuery) ([]models.CostGroup, error) {
+	var groups []models.CostGroup
+	sessions := make(map[int]map[string]bool)
+	index := make(map[models.CostGroup]int)
+	for _, r := range m.records {
+		if query.ConsumerID != "" && r.ConsumerID != query.ConsumerID {
+			continue
+		}
+		if !query.StartTime.IsZero() && r.Hour.Before(query.StartTime) {
+			continue
+		}
+		if !query.EndTime.IsZero() && !r.Hour.Before(query.EndTime) {
+			continue
+		}
+		var key models.CostGroup
+		for _, g := range groupBy {
+			switch g {
+			case models.CostGroupByConsumer:
+				key.ConsumerID = r.ConsumerID
+			case models.CostGroupByGPUType:
+				key.GPUType = r.GPUType
+			case models.CostGroupByProvider:
+				key.Provider = r.Provider
+			}
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, key)
+			sessions[i] = make(map[string]bool)
+		}
+		groups[i].TotalCost += r.Amount
+		if r.Category == models.CostCategoryGPU || r.Category == "" {
+			groups[i].HoursUsed++
+		}
+		sessions[i][r.SessionID] = true
+		groups[i].SessionCount = len(sessions[i])
+	}
+	return groups, nil
+}
+
+func (m *mockCostStore) List(ctx context.Context, query models.CostQuery) ([]models.CostRecord, error) {
+	var result []models.CostRecord
+	for _, r := range m.records {
+		if query.ConsumerID != "" && r.ConsumerID != query.ConsumerID {
+			continue
+		}
+		if !query.StartTime.IsZero() && r.Hour.Before(query.StartTime) {
+			continue
+		}
+		if !query.EndTime.IsZero() && !r.Hour.Before(query.EndTime) {
+			continue
+		}
+		result = append(result, *r)
+	}
+	return result, nil
+}
+
 type mockDestroyer struct{}
 
 func (m *mockDestroyer) DestroySession(ctx context.Context, sessionID string) error {
@@ -219,6 +394,17 @@ func (m *mockDestroyer) DestroySession(ctx context.Context, sessionID string) er
 }
 
 func setupTestServer() *Server {
+	return setupTestServerWithBudget(nil)
+}
+
+// setupTestServerWithBudget builds the test server with an optional budget
+// service wired into both the provisioner and the API.
+func setupTestServerWithBudget(budgetSvc *budget.Service) *Server {
+	return newTestServer(budgetSvc, newMockSessionStore())
+}
+
+// newTestServer builds a test server backed by sessionStore with extra options applied
+func newTestServer(budgetSvc *budget.Service, sessionStore *mockSessionStore, opts ...Option) *Server {
 	// Create mock provider with template support
 	mockProv := &mockTemplateProvider{
 		mockProvider: mockProvider{
@@ -277,9 +463,14 @@ func setupTestServer() *Server {
 	// Create services
 	inv := inventory.New([]provider.Provider{mockProv})
 
-	sessionStore := newMockSessionStore()
 	registry := provisioner.NewSimpleProviderRegistry([]provider.Provider{mockProv})
-	prov := provisioner.New(sessionStore, registry)
+	provOpts := []provisioner.Option{provisioner.WithInventory(inv)}
+	apiOpts := opts
+	if budgetSvc != nil {
+		provOpts = append(provOpts, provisioner.WithBudgetChecker(budgetSvc))
+		apiOpts = append(apiOpts, WithBudgetService(budgetSvc))
+	}
+	prov := provisioner.New(sessionStore, registry, provOpts...)
 
 	destroyer := &mockDestroyer{}
 	lm := lifecycle.New(sessionStore, destroyer)
@@ -287,7 +478,7 @@ func setupTestServer() *Server {
 	costStore := newMockCostStore()
 	ct := cost.New(costStore, sessionStore, nil)
 
-	server := New(inv, prov, lm, ct)
+	server := New(inv, prov, lm, ct, apiOpts...)
 	// Set server as ready by default in tests
 	server.SetReady(true)
 	return server
@@ -310,6 +501,93 @@ func TestHealth(t *testing.T) {
 	assert.Equal(t, "true", response.Services["ready"])
 }
 
+// flakyProvider is a mockProvider whose listing can be made to fail
+type flakyProvider struct {
+	mockProvider
+	failing atomic.Bool
+}
+
+func (f *flakyProvider) ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error) {
+	if f.failing.Load() {
+		return nil, errors.New("502 bad gateway")
+	}
+	return f.offers, nil
+}
+
+func TestHealthInventoryCacheAndStaleHeader(t *testing.T) {
+	vast := &flakyProvider{mockProvider: mockProvider{name: "vastai", offers: []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.5, Available: true, FetchedAt: time.Now()},
+	}}}
+	td := &mockProvider{name: "tensordock"}
+	inv := inventory.New([]provider.Provider{vast, td},
+		inventory.WithCacheTTL(50*time.Millisecond),
+		inventory.WithBackoffTTL(time.Minute))
+	defer inv.Shutdown()
+	sessionStore := newMockSessionStore()
+	registry := provisioner.NewSimpleProviderRegistry([]provider.Provider{vast, td})
+	prov := provisioner.New(sessionStore, registry, provisioner.WithInventory(inv))
+	server := New(inv, prov, lifecycle.New(sessionStore, &mockDestroyer{}), cost.New(newMockCostStore(), sessionStore, nil))
+	server.SetReady(true)
+
+	health := func() HealthResponse {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Nothing is cached before the first fetch
+	response := health()
+	assert.False(t, response.Inventory["vastai"].Cached)
+	assert.False(t, response.Inventory["tensordock"].Cached)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/inventory", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(StaleInventoryHeader))
+
+	response = health()
+	assert.Equal(t, "ok", response.Services["inventory"])
+	assert.True(t, response.Inventory["vastai"].Cached)
+	assert.Equal(t, 1, response.Inventory["vastai"].OfferCount)
+	assert.NotNil(t, response.Inventory["vastai"].FetchedAt)
+
+	// Vast.ai goes down: its last good offers are served and flagged
+	time.Sleep(70 * time.Millisecond)
+	vast.failing.Store(true)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/inventory", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "vastai", w.Header().Get(StaleInventoryHeader))
+	assert.Contains(t, w.Body.String(), "offer-1")
+
+	response = health()
+	assert.Equal(t, "degraded", response.Services["inventory"])
+	assert.True(t, response.Inventory["vastai"].ServingStale)
+	assert.Greater(t, response.Inventory["vastai"].AgeSeconds, 0.05)
+	assert.False(t, response.Inventory["tensordock"].ServingStale)
+}
+
+func TestListProviders(t *testing.T) {
+	server := setupTestServer()
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/providers", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Providers []inventory.ProviderHealth `json:"providers"`
+		Count     int                        `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "vastai", response.Providers[0].Provider)
+	assert.Equal(t, inventory.ProviderHealthy, response.Providers[0].Status)
+	assert.Equal(t, 15, response.Providers[0].ErrorRateWindowMinutes)
+}
+
 func TestHealthNotReady(t *testing.T) {
 	server := setupTestServer()
 	server.SetReady(false)
@@ -328,6 +606,35 @@ func TestHealthNotReady(t *testing.T) {
 	assert.Equal(t, "false", response.Services["ready"])
 }
 
+func TestDashboard(t *testing.T) {
+	// The page is static, so it loads without a key even when access control
+	// is enabled; its API calls are what require one.
+	server := newTestServer(nil, newMockSessionStore(), WithAPIKeys([]APIKey{{Key: "viewer-key", Role: RoleViewer}}))
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/dashboard/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'self'")
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Contains(t, w.Body.String(), `<script src="app.js"></script>`)
+
+	for _, asset := range []string{"/dashboard/app.js", "/dashboard/style.css"} {
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", asset, nil))
+		assert.Equal(t, http.StatusOK, w.Code, asset)
+	}
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/dashboard", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/dashboard/", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 func TestReadyEndpoint(t *testing.T) {
 	server := setupTestServer()
 
@@ -416,6 +723,128 @@ func TestListInventoryWithMaxPrice(t *testing.T) {
 	assert.Equal(t, 1, count) // Only RTX4090 at $0.50
 }
 
+func TestListInventoryInterruptible(t *testing.T) {
+	server := setupTestServer()
+
+	// The test inventory has no bid offers
+	req := httptest.NewRequest("GET", "/api/v1/inventory?interruptible=true", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, int(response["count"].(float64)))
+
+	req = httptest.NewRequest("GET", "/api/v1/inventory?interruptible=maybe", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid interruptible")
+}
+
+func TestListInventoryRegionFilter(t *testing.T) {
+	server := setupTestServer()
+
+	// The test offers have no location, so they are in no region
+	req := httptest.NewRequest("GET", "/api/v1/inventory?region=EU&country=US", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, int(response["count"].(float64)))
+
+	req = httptest.NewRequest("GET", "/api/v1/inventory?region=Europe", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid region")
+}
+
+func TestListInventoryDriverFilter(t *testing.T) {
+	server := setupTestServer()
+
+	// The test offers advertise no driver version, so none meet a minimum
+	req := httptest.NewRequest("GET", "/api/v1/inventory?min_driver=535.104", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, int(response["count"].(float64)))
+
+	req = httptest.NewRequest("GET", "/api/v1/inventory?min_driver=latest", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid min_driver")
+}
+
+func TestListInventoryThroughputFilter(t *testing.T) {
+	server := setupTestServer()
+
+	// The test offers report no throughput, so none meet a minimum
+	req := httptest.NewRequest("GET", "/api/v1/inventory?min_inet_down=500&min_disk_bw=1000", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, int(response["count"].(float64)))
+
+	for _, query := range []string{"min_inet_down=fast", "min_disk_bw=-1"} {
+		req = httptest.NewRequest("GET", "/api/v1/inventory?"+query, nil)
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), "invalid min_", query)
+	}
+}
+
+func TestGetInventorySummary(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory/summary", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		GPUTypes []models.InventorySummary `json:"gpu_types"`
+		Count    int                       `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 2, response.Count)
+	assert.Equal(t, "RTX4090", response.GPUTypes[0].GPUType, "cheapest GPU type first")
+	assert.Equal(t, 1, response.GPUTypes[0].OfferCount)
+	assert.InDelta(t, 0.50, response.GPUTypes[0].MedianPrice, 1e-9)
+	assert.Equal(t, []string{"vastai"}, response.GPUTypes[0].Providers)
+	assert.Equal(t, "A100", response.GPUTypes[1].GPUType)
+
+	// Takes the inventory filters
+	req = httptest.NewRequest("GET", "/api/v1/inventory/summary?min_vram=40", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "A100", response.GPUTypes[0].GPUType)
+
+	req = httptest.NewRequest("GET", "/api/v1/inventory/summary?min_vram=lots", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid min_vram")
+}
+
 func TestListInventoryInvalidProvider(t *testing.T) {
 	// Bug #2: Invalid provider should return 400, not 500
 	server := setupTestServer()
@@ -657,6 +1086,117 @@ func TestExtendSession(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestExtendSessionPatchReturnsProjection(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	body := `{
+		"consumer_id": "consumer-001",
+		"offer_id": "offer-1",
+		"workload_type": "llm",
+		"reservation_hours": 2
+	}`
+	req = httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	var createResp CreateSessionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createResp))
+	sessionID := createResp.Session.ID
+
+	req = httptest.NewRequest("PATCH", "/api/v1/sessions/"+sessionID+"/extend", strings.NewReader(`{"additional_hours": 3}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(5), resp["reservation_hours"])
+	assert.InDelta(t, 1.50, resp["additional_cost"], 0.001) // 3h * $0.50
+	assert.InDelta(t, 2.50, resp["projected_cost"], 0.001)  // 5h * $0.50
+	assert.NotEmpty(t, resp["new_expires_at"])
+}
+
+func TestExtendSessionPatchNotFound(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("PATCH", "/api/v1/sessions/does-not-exist/extend", strings.NewReader(`{"additional_hours": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPauseAndResumeSessionErrors(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+	ctx := context.Background()
+	require.NoError(t, sessionStore.Create(ctx, &models.Session{
+		ID: "sess-running", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning,
+	}))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/sessions/does-not-exist/pause", http.StatusNotFound},
+		{"/api/v1/sessions/sess-running/pause", http.StatusNotImplemented}, // The mock provider cannot stop instances
+		{"/api/v1/sessions/sess-running/resume", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("PATCH", tt.path, nil))
+		assert.Equal(t, tt.want, w.Code, tt.path)
+	}
+}
+
+func TestRebootSessionErrors(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+	require.NoError(t, sessionStore.Create(context.Background(), &models.Session{
+		ID: "sess-running", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning,
+	}))
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/sessions/does-not-exist/reboot", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// The mock provider cannot reboot or stop instances, and no key was given to reboot over SSH
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/sessions/sess-running/reboot", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "private key")
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/sessions/sess-running/reboot", strings.NewReader(`{"ssh_private_key": 1}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTakeReplacementKey(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+	require.NoError(t, sessionStore.Create(context.Background(), &models.Session{
+		ID: "sess-running", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning,
+	}))
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/does-not-exist/ssh-key", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Only failover replacements have a key to fetch
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/sess-running/ssh-key", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "not a failover replacement")
+}
+
 // Template endpoint tests
 
 func TestListTemplates(t *testing.T) {
@@ -820,3 +1360,2434 @@ func TestCreateSessionWithTemplateHashID(t *testing.T) {
 	assert.NotEmpty(t, response.Session.ID)
 	assert.Equal(t, "template-hash-1", response.Session.TemplateHashID)
 }
+
+// newTestBudgetService creates a budget service backed by a temporary database
+func newTestBudgetService(t *testing.T) *budget.Service {
+	t.Helper()
+	db, err := storage.New(filepath.Join(t.TempDir(), "budget.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	return budget.New(storage.NewBudgetStore(db), storage.NewCostStore(db), newMockSessionStore())
+}
+
+func TestBudgetEndpoints(t *testing.T) {
+	server := setupTestServerWithBudget(newTestBudgetService(t))
+
+	// Create
+	body := `{"scope": "consumer", "scope_id": "consumer-001", "period": "daily", "limit_usd": 10}`
+	req := httptest.NewRequest("POST", "/api/v1/budgets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var created struct {
+		Budget models.Budget `json:"budget"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Budget.ID)
+	assert.Equal(t, 10.0, created.Budget.LimitUSD)
+
+	// Invalid period
+	body = `{"scope": "consumer", "scope_id": "consumer-001", "period": "yearly", "limit_usd": 10}`
+	req = httptest.NewRequest("POST", "/api/v1/budgets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Status
+	req = httptest.NewRequest("GET", "/api/v1/budgets/status", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status struct {
+		Budgets []models.BudgetStatus `json:"budgets"`
+		Count   int                   `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.Equal(t, 1, status.Count)
+	assert.Equal(t, 10.0, status.Budgets[0].Remaining)
+
+	// Delete, then delete again
+	req = httptest.NewRequest("DELETE", "/api/v1/budgets/"+created.Budget.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/budgets/"+created.Budget.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBudgetEndpointsUnavailable(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/budgets", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestCreateSessionRejectedByBudget(t *testing.T) {
+	budgetSvc := newTestBudgetService(t)
+	server := setupTestServerWithBudget(budgetSvc)
+
+	// $0.50/hr offer for 4 hours projects $2.00, over the $1.50 cap
+	require.NoError(t, budgetSvc.SetBudget(context.Background(), &models.Budget{
+		Scope: models.BudgetScopeConsumer, ScopeID: "consumer-001", Period: models.BudgetPeriodDaily, LimitUSD: 1.50,
+	}))
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	body := `{
+		"consumer_id": "consumer-001",
+		"offer_id": "offer-1",
+		"workload_type": "llm",
+		"reservation_hours": 4
+	}`
+	req = httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "budget_exceeded", resp["error_type"])
+	assert.Equal(t, 2.0, resp["projected_cost"])
+	assert.Equal(t, 1.5, resp["limit_usd"])
+}
+
+func batchRequest(body string) *http.Request {
+	req := httptest.NewRequest("POST", "/api/v1/sessions/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestBatchCreateSessions(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, batchRequest(`{
+		"count": 2,
+		"consumer_id": "consumer-001",
+		"workload_type": "training",
+		"reservation_hours": 1
+	}`))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp BatchCreateSessionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.GroupID)
+	assert.Equal(t, 2, resp.Provisioned)
+	require.Len(t, resp.Results, 2)
+
+	// Cheapest offer first
+	assert.Equal(t, "offer-1", resp.Results[0].OfferID)
+	assert.Equal(t, "offer-2", resp.Results[1].OfferID)
+	for _, r := range resp.Results {
+		require.NotNil(t, r.Session)
+		assert.Equal(t, resp.GroupID, r.Session.GroupID)
+		assert.NotEmpty(t, r.SSHPrivateKey)
+		assert.Equal(t, resp.GroupID, sessionStore.sessions[r.Session.ID].GroupID)
+	}
+}
+
+func TestBatchCreateSessionsPartial(t *testing.T) {
+	server := setupTestServer()
+
+	// Only two offers exist
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, batchRequest(`{
+		"count": 3,
+		"consumer_id": "consumer-001",
+		"workload_type": "training",
+		"reservation_hours": 1
+	}`))
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+
+	var resp BatchCreateSessionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Requested)
+	assert.Equal(t, 2, resp.Provisioned)
+}
+
+func TestBatchCreateSessionsStopsOnBudget(t *testing.T) {
+	budgetSvc := newTestBudgetService(t)
+	server := setupTestServerWithBudget(budgetSvc)
+	require.NoError(t, budgetSvc.SetBudget(context.Background(), &models.Budget{
+		Scope: models.BudgetScopeConsumer, ScopeID: "consumer-001", Period: models.BudgetPeriodDaily, LimitUSD: 1.50,
+	}))
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, batchRequest(`{
+		"count": 2,
+		"consumer_id": "consumer-001",
+		"workload_type": "training",
+		"reservation_hours": 4
+	}`))
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+
+	var resp BatchCreateSessionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 0, resp.Provisioned)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "budget_exceeded", resp.Results[0].ErrorType)
+	assert.Nil(t, resp.Results[0].Session)
+}
+
+func TestBatchCreateSessionsValidation(t *testing.T) {
+	server := setupTestServer()
+
+	// No offer matches the filter
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, batchRequest(`{
+		"count": 1,
+		"filter": {"gpu_type": "H100"},
+		"consumer_id": "consumer-001",
+		"workload_type": "training",
+		"reservation_hours": 1
+	}`))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Count is bounded
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, batchRequest(`{
+		"count": 17,
+		"consumer_id": "consumer-001",
+		"workload_type": "training",
+		"reservation_hours": 1
+	}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "count must be at most 16")
+}
+
+func TestListSessions_HealthFilter(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+
+	heartbeat := time.Now().Add(-10 * time.Minute)
+	util := 0.0
+	sessionStore.sessions["sess-ok"] = &models.Session{
+		ID: "sess-ok", ConsumerID: "consumer-001", Status: models.StatusRunning,
+		Health: models.SessionHealth{Status: models.HealthHealthy, LastHeartbeatAt: time.Now(), GPUUtilPct: &util},
+	}
+	sessionStore.sessions["sess-silent"] = &models.Session{
+		ID: "sess-silent", ConsumerID: "consumer-001", Status: models.StatusRunning,
+		Health: models.SessionHealth{Status: models.HealthDegraded, LastHeartbeatAt: heartbeat},
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions?health=degraded", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Sessions []models.SessionResponse `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Sessions, 1)
+	assert.Equal(t, "sess-silent", list.Sessions[0].ID)
+	require.NotNil(t, list.Sessions[0].Health)
+	assert.Equal(t, models.HealthDegraded, list.Sessions[0].Health.Status)
+	require.NotNil(t, list.Sessions[0].Health.LastHeartbeatAt)
+	assert.WithinDuration(t, heartbeat, *list.Sessions[0].Health.LastHeartbeatAt, time.Second)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions?health=sick", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid health")
+}
+
+func TestListSessions_IncludeTerminal(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+
+	now := time.Now()
+	for i, status := range []models.SessionStatus{
+		models.StatusRunning, models.StatusStopped, models.StatusFailed, models.StatusProvisioning, models.StatusPreempted,
+	} {
+		id := fmt.Sprintf("sess-%d", i)
+		sessionStore.sessions[id] = &models.Session{
+			ID: id, ConsumerID: "consumer-001", Status: status, CreatedAt: now.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+
+	type page struct {
+		Sessions   []models.SessionResponse `json:"sessions"`
+		Count      int                      `json:"count"`
+		NextOffset *int                     `json:"next_offset"`
+	}
+	list := func(query string) page {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var p page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		return p
+	}
+	ids := func(p page) []string {
+		var ids []string
+		for _, s := range p.Sessions {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	// Terminal sessions are left out by default
+	p := list("")
+	assert.Equal(t, []string{"sess-0", "sess-3"}, ids(p))
+	assert.Nil(t, p.NextOffset)
+
+	// ...but kept, newest first, when asked for
+	p = list("?include_terminal=true")
+	assert.Equal(t, []string{"sess-0", "sess-1", "sess-2", "sess-3", "sess-4"}, ids(p))
+
+	// A status filter selects terminal sessions directly
+	p = list("?status=stopped")
+	assert.Equal(t, []string{"sess-1"}, ids(p))
+
+	// Pages follow next_offset until it is absent
+	p = list("?include_terminal=true&limit=2")
+	assert.Equal(t, []string{"sess-0", "sess-1"}, ids(p))
+	require.NotNil(t, p.NextOffset)
+	assert.Equal(t, 2, *p.NextOffset)
+	p = list("?include_terminal=true&limit=2&offset=2")
+	assert.Equal(t, []string{"sess-2", "sess-3"}, ids(p))
+	require.NotNil(t, p.NextOffset)
+	p = list(fmt.Sprintf("?include_terminal=true&limit=2&offset=%d", *p.NextOffset))
+	assert.Equal(t, []string{"sess-4"}, ids(p))
+	assert.Equal(t, 1, p.Count)
+	assert.Nil(t, p.NextOffset)
+
+	for _, query := range []string{"?offset=-1", "?limit=-5", "?include_terminal=maybe"} {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestListSessions_SortAndFilter(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	sessionStore.sessions["sess-a"] = &models.Session{
+		ID: "sess-a", Status: models.StatusRunning, GPUType: "RTX4090", PricePerHour: 0.40, CreatedAt: day.AddDate(0, 0, -2),
+	}
+	sessionStore.sessions["sess-b"] = &models.Session{
+		ID: "sess-b", Status: models.StatusRunning, GPUType: "A100", PricePerHour: 1.20, CreatedAt: day.AddDate(0, 0, -1),
+	}
+	sessionStore.sessions["sess-c"] = &models.Session{
+		ID: "sess-c", Status: models.StatusRunning, GPUType: "RTX4090", PricePerHour: 0.30, CreatedAt: day,
+	}
+
+	list := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Sessions []models.SessionResponse `json:"sessions"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var ids []string
+		for _, s := range resp.Sessions {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"sess-c", "sess-b", "sess-a"}, list(""))
+	assert.Equal(t, []string{"sess-a", "sess-b", "sess-c"}, list("?order=asc"))
+	assert.Equal(t, []string{"sess-b", "sess-a", "sess-c"}, list("?sort=price"))
+	assert.Equal(t, []string{"sess-c", "sess-a", "sess-b"}, list("?sort=price&order=asc"))
+	assert.Equal(t, []string{"sess-c", "sess-a"}, list("?gpu_type=rtx4090"))
+	assert.Equal(t, []string{"sess-c", "sess-b"}, list("?created_after=2026-03-09"))
+	assert.Equal(t, []string{"sess-a"}, list("?created_before=2026-03-09T00:00:00Z"))
+	assert.Equal(t, []string{"sess-b"}, list("?created_after=2026-03-09&created_before=2026-03-10"))
+
+	for query, message := range map[string]string{
+		"?sort=gpu":              "invalid sort",
+		"?order=up":              "invalid order",
+		"?created_after=monday":  "invalid created_after",
+		"?created_before=2026-3": "invalid created_before",
+	} {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), message, query)
+	}
+}
+
+func TestSearchSessions(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+
+	now := time.Now()
+	sessionStore.sessions["sess-1"] = &models.Session{
+		ID: "sess-1", ConsumerID: "team-a", OfferID: "vastai-111", Status: models.StatusFailed,
+		Error: "instance failed: no available public IPs", CreatedAt: now.Add(-2 * time.Hour),
+	}
+	sessionStore.sessions["sess-2"] = &models.Session{
+		ID: "sess-2", ConsumerID: "team-b", OfferID: "vastai-222", ProviderID: "inst-9", Status: models.StatusRunning,
+		CreatedAt: now.Add(-time.Hour),
+	}
+	sessionStore.sessions["sess-3"] = &models.Session{
+		ID: "sess-3", ConsumerID: "team-a", OfferID: "tensordock-333", Status: models.StatusStopped,
+		Error: "No available public IPs", CreatedAt: now,
+	}
+
+	search := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/search"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Sessions []models.SessionResponse `json:"sessions"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var ids []string
+		for _, s := range resp.Sessions {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	// Terminal sessions are searched without asking for them
+	assert.Equal(t, []string{"sess-3", "sess-1"}, search("?q=no+available+public+IPs"))
+	assert.Equal(t, []string{"sess-1"}, search("?q=public+IPs&created_before="+now.Add(-time.Minute).UTC().Format(time.RFC3339)))
+	assert.Equal(t, []string{"sess-2"}, search("?q=inst-9"))
+	assert.Equal(t, []string{"sess-3", "sess-1"}, search("?q=team-a"))
+	assert.Empty(t, search("?q=out+of+memory"))
+
+	// The list endpoint takes the same parameter
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions?q=vastai-222", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/search?q=+", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "q is required")
+}
+
+func TestGetSessionLogs(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+	sessionStore.sessions["sess-1"] = &models.Session{
+		ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning,
+	}
+	sessionStore.sessions["sess-gone"] = &models.Session{
+		ID: "sess-gone", Provider: "vastai", ProviderID: "inst-2", Status: models.StatusStopped,
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/sess-1/logs", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "200 lines from inst-1\n", resp["logs"])
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/sess-1/logs?tail=20", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "20 lines from inst-1\n", resp["logs"])
+
+	for path, want := range map[string]int{
+		"/api/v1/sessions/sess-1/logs?tail=99999": http.StatusBadRequest,
+		"/api/v1/sessions/sess-gone/logs":         http.StatusConflict,
+		"/api/v1/sessions/missing/logs":           http.StatusNotFound,
+	} {
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, want, w.Code, path)
+	}
+}
+
+func TestSessionGroups(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, batchRequest(`{
+		"count": 2,
+		"group_id": "sweep-42",
+		"consumer_id": "consumer-001",
+		"workload_type": "training",
+		"reservation_hours": 1
+	}`))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// An unrelated session stays out of the group
+	sessionStore.sessions["sess-solo"] = &models.Session{
+		ID: "sess-solo", ConsumerID: "consumer-001", Status: models.StatusRunning,
+	}
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/session-groups/sweep-42", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var group map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &group))
+	assert.Equal(t, 2.0, group["count"])
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions?group_id=sweep-42", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 2.0, list["count"])
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/session-groups/sweep-42", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var destroyed DestroySessionGroupResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &destroyed))
+	assert.Len(t, destroyed.Destroyed, 2)
+	assert.Empty(t, destroyed.Failed)
+	for _, id := range destroyed.Destroyed {
+		assert.False(t, sessionStore.sessions[id].IsActive())
+	}
+	assert.Equal(t, models.StatusRunning, sessionStore.sessions["sess-solo"].Status)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/session-groups/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateSessionInvalidGroupID(t *testing.T) {
+	server := setupTestServer()
+
+	body := `{
+		"consumer_id": "consumer-001",
+		"offer_id": "offer-1",
+		"workload_type": "llm",
+		"reservation_hours": 2,
+		"group_id": "bad group/id"
+	}`
+	req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid group_id")
+}
+
+func TestValidateCloudInit(t *testing.T) {
+	tests := []struct {
+		name      string
+		cloudInit *models.CloudInitConfig
+		wantErr   string
+	}{
+		{"none", nil, ""},
+		{"valid", &models.CloudInitConfig{
+			RunCmd: []string{"apt-get install -y htop"},
+			WriteFiles: []models.CloudInitFile{
+				{Path: "/opt/setup.sh", Content: "echo hi", Permissions: "0755", Owner: "root:root"},
+				{Path: "/opt/blob", Content: "aGk=", Encoding: "b64"},
+			},
+		}, ""},
+		{"empty command", &models.CloudInitConfig{RunCmd: []string{" "}}, "runcmd[0] is empty"},
+		{"too many commands", &models.CloudInitConfig{RunCmd: make([]string, models.MaxCloudInitRunCmds+1)}, "runcmd entries"},
+		{"too large", &models.CloudInitConfig{RunCmd: []string{strings.Repeat("x", models.MaxCloudInitBytes+1)}}, "byte limit"},
+		{"relative path", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "opt/x"}}}, "absolute"},
+		{"path traversal", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/opt/../root/x"}}}, "absolute"},
+		{"authorized keys", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/root/.ssh/authorized_keys"}}}, "SSH"},
+		{"sshd config", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/etc/ssh/sshd_config"}}}, "SSH"},
+		{"bad base64", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/opt/x", Content: "!!", Encoding: "b64"}}}, "base64"},
+		{"unknown encoding", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/opt/x", Encoding: "gzip"}}}, "encoding"},
+		{"bad permissions", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/opt/x", Permissions: "rwx"}}}, "permissions"},
+		{"bad owner", &models.CloudInitConfig{WriteFiles: []models.CloudInitFile{{Path: "/opt/x", Owner: "root; rm"}}}, "owner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validateCloudInit(tt.cloudInit)
+			if tt.wantErr == "" {
+				assert.Empty(t, msg)
+			} else {
+				assert.Contains(t, msg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateSessionInvalidCloudInit(t *testing.T) {
+	server := setupTestServer()
+
+	body := `{
+		"consumer_id": "consumer-001",
+		"offer_id": "offer-1",
+		"workload_type": "llm",
+		"reservation_hours": 2,
+		"cloud_init": {"write_files": [{"path": "/root/.ssh/authorized_keys", "content": "ssh-rsa AAAA"}]}
+	}`
+	req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid cloud_init")
+}
+
+func TestValidateSessionSpec_BootstrapScript(t *testing.T) {
+	spec := SessionSpec{ConsumerID: "consumer-001", WorkloadType: "interactive", ReservationHrs: 1}
+
+	spec.BootstrapScript = "git clone https://example.com/repo.git"
+	assert.Empty(t, validateSessionSpec(spec))
+
+	entrypoint := spec
+	entrypoint.LaunchMode = "entrypoint"
+	assert.Contains(t, validateSessionSpec(entrypoint), "requires launch_mode ssh")
+
+	tooLarge := spec
+	tooLarge.BootstrapScript = strings.Repeat("x", models.MaxBootstrapScriptBytes+1)
+	assert.Contains(t, validateSessionSpec(tooLarge), "byte limit")
+}
+
+func TestValidateSessionSpec_GPUBurnIn(t *testing.T) {
+	spec := SessionSpec{ConsumerID: "consumer-001", WorkloadType: "interactive", ReservationHrs: 1, GPUBurnIn: true}
+	assert.Empty(t, validateSessionSpec(spec))
+
+	spec.LaunchMode = "entrypoint"
+	assert.Contains(t, validateSessionSpec(spec), "invalid gpu_burn_in")
+}
+
+func TestValidateSessionSpec_HFToken(t *testing.T) {
+	spec := SessionSpec{ConsumerID: "consumer-001", WorkloadType: "llm_vllm", ReservationHrs: 1, LaunchMode: "entrypoint"}
+
+	spec.HFToken = "hf_AbCdEfGhIjKlMnOpQrStUvWxYz0123456789"
+	assert.Empty(t, validateSessionSpec(spec))
+
+	for _, token := range []string{"hf_abc def", "hf_abc\nexport X=1", strings.Repeat("x", 257)} {
+		spec.HFToken = token
+		assert.Contains(t, validateSessionSpec(spec), "invalid hf_token", token)
+	}
+}
+
+func TestCreateSession_HFTokenRequiresEncryption(t *testing.T) {
+	body := `{
+		"consumer_id": "consumer-001",
+		"offer_id": "offer-1",
+		"workload_type": "llm_vllm",
+		"reservation_hours": 1,
+		"hf_token": "hf_AbCdEfGhIjKlMnOpQrStUvWxYz0123456789"
+	}`
+	create := func(server *Server) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Without database encryption the token would be stored in plaintext
+	w := create(setupTestServer())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"error_type":"encryption_required"`)
+
+	w = create(newTestServer(nil, newMockSessionStore(), WithDatabaseEncryption(true)))
+	assert.NotContains(t, w.Body.String(), "encryption_required")
+}
+
+func TestCreateSessionRejectedForInsufficientVRAM(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	// offer-1 has a single 24GB GPU; a 70B FP16 model needs far more
+	body := `{
+		"consumer_id": "consumer-001",
+		"offer_id": "offer-1",
+		"workload_type": "llm",
+		"reservation_hours": 1,
+		"model_id": "meta-llama/Meta-Llama-3.1-70B-Instruct"
+	}`
+	req = httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "insufficient_vram", resp["error_type"])
+	assert.Equal(t, 24.0, resp["available_gb"])
+}
+
+func setupAdminTestServer(t *testing.T) (*Server, *mockSessionStore, *storage.AuditStore) {
+	t.Helper()
+	db, err := storage.New(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	auditStore := storage.NewAuditStore(db)
+	sessionStore := newMockSessionStore()
+	sessionStore.sessions["sess-1"] = &models.Session{
+		ID:             "sess-1",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		OfferID:        "offer-1",
+		Status:         models.StatusRunning,
+		PricePerHour:   0.50,
+		ReservationHrs: 2,
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(2 * time.Hour),
+	}
+
+	server := newTestServer(nil, sessionStore, WithAdmin("admin-secret", auditStore))
+	return server, sessionStore, auditStore
+}
+
+func adminRequest(method, path, body string) *http.Request {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	req.Header.Set("X-Admin-Actor", "support-alice")
+	req.Header.Set("X-Admin-Reason", "ticket-123")
+	return req
+}
+
+func TestAdminAuth(t *testing.T) {
+	server, _, _ := setupAdminTestServer(t)
+
+	// Missing key
+	req := httptest.NewRequest("GET", "/api/v1/admin/consumers/consumer-001/sessions", nil)
+	req.Header.Set("X-Admin-Actor", "support-alice")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Wrong key
+	req.Header.Set("Authorization", "Bearer wrong")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Missing actor
+	req = adminRequest("GET", "/api/v1/admin/consumers/consumer-001/sessions", "")
+	req.Header.Del("X-Admin-Actor")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminDisabledWithoutKey(t *testing.T) {
+	server := setupTestServer()
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/audit", ""))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestAdminSessionActionsAreAudited(t *testing.T) {
+	server, sessionStore, auditStore := setupAdminTestServer(t)
+
+	// List the consumer's sessions
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/consumers/consumer-001/sessions", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	assert.Equal(t, 1.0, listResp["count"])
+
+	// Extend
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/consumers/consumer-001/sessions/sess-1/extend", `{"additional_hours": 2}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 4, sessionStore.sessions["sess-1"].ReservationHrs)
+
+	// Regenerating SSH access is not supported by the mock provider
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/consumers/consumer-001/sessions/sess-1/ssh-key", ""))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	// Destroy
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/consumers/consumer-001/sessions/sess-1", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{ConsumerID: "consumer-001"})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	actions := make(map[models.AuditAction]*models.AuditEntry)
+	for _, e := range entries {
+		assert.Equal(t, "support-alice", e.Actor)
+		assert.Equal(t, "ticket-123", e.Reason)
+		assert.NotEmpty(t, e.RequestID)
+		actions[e.Action] = e
+	}
+	require.Contains(t, actions, models.AuditActionExtendSession)
+	assert.Equal(t, "sess-1", actions[models.AuditActionExtendSession].SessionID)
+	assert.Equal(t, "additional_hours=2", actions[models.AuditActionExtendSession].Details)
+	assert.Contains(t, actions, models.AuditActionListSessions)
+	assert.Contains(t, actions, models.AuditActionRegenerateSSHKey)
+	assert.Contains(t, actions, models.AuditActionDestroySession)
+
+	// The audit trail is queryable through the admin API
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/audit?consumer_id=consumer-001", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	var auditResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &auditResp))
+	assert.Equal(t, 4.0, auditResp["count"])
+}
+
+func TestAdminRejectsSessionOfOtherConsumer(t *testing.T) {
+	server, sessionStore, auditStore := setupAdminTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/consumers/consumer-002/sessions/sess-1", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.StatusRunning, sessionStore.sessions["sess-1"].Status)
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+type mockReconciler struct {
+	runs int
+}
+
+func (m *mockReconciler) RunReconciliation(ctx context.Context) {
+	m.runs++
+}
+
+func (m *mockReconciler) AuditTerminations(ctx context.Context) *models.TerminationAudit {
+	return &models.TerminationAudit{
+		Providers: []models.TerminationAuditProvider{{Provider: "vastai", Instances: 1, Flagged: 1}},
+		Instances: []models.TerminationAuditInstance{{
+			Provider: "vastai", InstanceID: "inst-1", Status: "running",
+			Issues: []models.TerminationIssue{models.TerminationIssueNoSession},
+		}},
+		Flagged: 1,
+	}
+}
+
+func setupRBACTestServer(t *testing.T) (*Server, *mockSessionStore, *mockReconciler) {
+	t.Helper()
+	server, sessionStore, _ := setupAdminTestServer(t)
+	keys, err := ParseAPIKeys("dash-key:viewer, ci-key:operator")
+	require.NoError(t, err)
+	reconciler := &mockReconciler{}
+	WithAPIKeys(keys)(server)
+	WithReconciler(reconciler)(server)
+	return server, sessionStore, reconciler
+}
+
+func rbacRequest(method, path, key string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	req.Header.Set("X-Admin-Actor", "support-alice")
+	return req
+}
+
+func TestRBAC_RequiresAPIKey(t *testing.T) {
+	server, _, _ := setupRBACTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/sessions/sess-1", ""))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/sessions/sess-1", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Probes stay open
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/health", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRBAC_ViewerCannotDestroy(t *testing.T) {
+	server, sessionStore, _ := setupRBACTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/sessions/sess-1", "dash-key"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("DELETE", "/api/v1/sessions/sess-1", "dash-key"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, models.StatusRunning, sessionStore.sessions["sess-1"].Status)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "insufficient_role", resp["error_type"])
+	assert.Equal(t, "viewer", resp["role"])
+	assert.Equal(t, "operator", resp["required_role"])
+
+	// Any other write is refused by the default policy
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("POST", "/api/v1/sessions/sess-1/done", "dash-key"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// So is fetching SSH keys, although it is a read
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/sessions/sess-1/ssh-key", "dash-key"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRBAC_ViewerCannotEndOrControlSessions(t *testing.T) {
+	server, sessionStore, _ := setupRBACTestServer(t)
+
+	for _, req := range []*http.Request{
+		rbacRequest("DELETE", "/api/v1/sessions/sess-1", "dash-key"),
+		rbacRequest("POST", "/api/v1/sessions/sess-1/done", "dash-key"),
+		rbacRequest("PATCH", "/api/v1/sessions/sess-1/pause", "dash-key"),
+		rbacRequest("PATCH", "/api/v1/sessions/sess-1/resume", "dash-key"),
+		rbacRequest("POST", "/api/v1/sessions/sess-1/reboot", "dash-key"),
+		rbacRequest("DELETE", "/api/v1/session-queue/queued-1", "dash-key"),
+		rbacRequest("DELETE", "/api/v1/session-groups/group-1", "dash-key"),
+	} {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.Method+" "+req.URL.Path)
+		assert.Contains(t, w.Body.String(), `"required_role":"operator"`, req.Method+" "+req.URL.Path)
+	}
+	assert.Equal(t, models.StatusRunning, sessionStore.sessions["sess-1"].Status)
+}
+
+func TestRBAC_BudgetsRequireAdmin(t *testing.T) {
+	server, _, _ := setupRBACTestServer(t)
+
+	for _, req := range []*http.Request{
+		rbacRequest("POST", "/api/v1/budgets", "ci-key"),
+		rbacRequest("DELETE", "/api/v1/budgets/budget-1", "ci-key"),
+	} {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.URL.Path)
+		assert.Contains(t, w.Body.String(), `"required_role":"admin"`)
+	}
+}
+
+func TestRBAC_OperatorCanDestroy(t *testing.T) {
+	server, _, _ := setupRBACTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("DELETE", "/api/v1/sessions/sess-1", "ci-key"))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestRBAC_ReconcileRequiresAdmin(t *testing.T) {
+	server, _, reconciler := setupRBACTestServer(t)
+
+	for _, key := range []string{"dash-key", "ci-key"} {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, rbacRequest("POST", "/api/v1/admin/reconcile", key))
+		assert.Equal(t, http.StatusForbidden, w.Code, key)
+	}
+	assert.Equal(t, 0, reconciler.runs)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("POST", "/api/v1/admin/reconcile", "admin-secret"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 1, reconciler.runs)
+}
+
+func TestAdminAuditTerminations(t *testing.T) {
+	server, _, _ := setupRBACTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/admin/terminations", "ci-key"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/admin/terminations", "admin-secret"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var audit models.TerminationAudit
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &audit))
+	assert.Equal(t, 1, audit.Flagged)
+	require.Len(t, audit.Instances, 1)
+	assert.Equal(t, []models.TerminationIssue{models.TerminationIssueNoSession}, audit.Instances[0].Issues)
+}
+
+// mockInstanceClaimer implements InstanceClaimer for testing
+type mockInstanceClaimer struct {
+	adopted  []string
+	released []string
+}
+
+func (m *mockInstanceClaimer) ListUnclaimed(ctx context.Context) *models.UnclaimedInstances {
+	return &models.UnclaimedInstances{
+		Providers: []models.UnclaimedProvider{{Provider: "vastai", Unclaimed: 1}},
+		Instances: []models.UnclaimedInstance{{Provider: "vastai", InstanceID: "stray", Status: "running", Orphan: true}},
+	}
+}
+
+func (m *mockInstanceClaimer) AdoptInstance(ctx context.Context, providerName, instanceID, consumerID string, reservationHrs int) (*models.Session, error) {
+	switch instanceID {
+	case "stray":
+		m.adopted = append(m.adopted, instanceID)
+		return &models.Session{ID: "sess-adopted", ConsumerID: consumerID, Provider: providerName, ProviderID: instanceID,
+			Status: models.StatusRunning, ReservationHrs: reservationHrs}, nil
+	case "inst-1":
+		return nil, &lifecycle.InstanceClaimedError{Provider: providerName, InstanceID: instanceID, SessionID: "sess-1"}
+	}
+	return nil, &lifecycle.InstanceNotFoundError{Provider: providerName, InstanceID: instanceID}
+}
+
+func (m *mockInstanceClaimer) ReleaseInstance(ctx context.Context, providerName, instanceID, releasedBy string) (*models.InstanceRelease, error) {
+	if instanceID != "stray" {
+		return nil, &lifecycle.InstanceNotFoundError{Provider: providerName, InstanceID: instanceID}
+	}
+	m.released = append(m.released, instanceID)
+	return &models.InstanceRelease{Provider: providerName, InstanceID: instanceID, ReleasedBy: releasedBy}, nil
+}
+
+func (m *mockInstanceClaimer) UnreleaseInstance(ctx context.Context, providerName, instanceID string) error {
+	for i, id := range m.released {
+		if id == instanceID {
+			m.released = append(m.released[:i], m.released[i+1:]...)
+			return nil
+		}
+	}
+	return &lifecycle.InstanceNotFoundError{Provider: providerName, InstanceID: instanceID}
+}
+
+func TestAdminInstanceClaims(t *testing.T) {
+	server, _, auditStore := setupAdminTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/instances/unclaimed", ""))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	claimer := &mockInstanceClaimer{}
+	WithInstanceClaimer(claimer)(server)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/instances/unclaimed", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var unclaimed models.UnclaimedInstances
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &unclaimed))
+	require.Len(t, unclaimed.Instances, 1)
+	assert.True(t, unclaimed.Instances[0].Orphan)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/instances/vastai/stray/adopt", `{}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/instances/vastai/stray/adopt",
+		`{"consumer_id":"consumer-002","reservation_hours":3}`))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var session models.Session
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(t, "consumer-002", session.ConsumerID)
+	assert.Equal(t, 3, session.ReservationHrs)
+	assert.Equal(t, []string{"stray"}, claimer.adopted)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/instances/vastai/inst-1/adopt", `{"consumer_id":"consumer-002"}`))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/instances/vastai/missing/release", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/instances/vastai/stray/release", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var release models.InstanceRelease
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &release))
+	assert.Equal(t, "support-alice", release.ReleasedBy)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/instances/vastai/stray/release", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/instances/vastai/stray/release", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{Actor: "support-alice"})
+	require.NoError(t, err)
+	actions := make(map[models.AuditAction]int)
+	for _, e := range entries {
+		actions[e.Action]++
+	}
+	assert.Equal(t, 2, actions[models.AuditActionAdoptInstance])
+	assert.Equal(t, 2, actions[models.AuditActionReleaseInstance])
+	assert.Equal(t, 2, actions[models.AuditActionUnreleaseInstance])
+}
+
+func TestAdminQuarantine(t *testing.T) {
+	server, _, auditStore := setupAdminTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/quarantine", ""))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	db, err := storage.New(filepath.Join(t.TempDir(), "quarantine.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+	quarantine := storage.NewQuarantineStore(db)
+	require.NoError(t, quarantine.Quarantine(context.Background(), &models.QuarantinedInstance{
+		Provider: "vastai", InstanceID: "12345", Source: models.QuarantineOrphan, Reason: "instance is locked",
+	}))
+	WithQuarantineStore(quarantine)(server)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/quarantine", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Instances []models.QuarantinedInstance `json:"instances"`
+		Count     int                          `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Count)
+	assert.Equal(t, "instance is locked", resp.Instances[0].Reason)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/quarantine/vastai/12345", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/quarantine/vastai/12345", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/quarantine", ""))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 0, resp.Count)
+	assert.Empty(t, resp.Instances)
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{Actor: "support-alice"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.AuditActionResolveQuarantine, entries[0].Action)
+}
+
+func TestAdminSpendStatus(t *testing.T) {
+	server, sessionStore, _ := setupRBACTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/admin/spend", "admin-secret"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	registry := provisioner.NewSimpleProviderRegistry([]provider.Provider{&mockProvider{name: "vastai"}})
+	guard := budget.NewSpendGuard(registry, &mockCostStore{}, sessionStore, &mockDestroyer{}, budget.WithSpendCeiling(500))
+	guard.Check(context.Background())
+	WithSpendGuard(guard)(server)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/admin/spend", "ci-key"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/admin/spend", "admin-secret"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var status models.SpendCeilingStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, 500.0, status.CeilingUSD)
+	assert.Equal(t, 100.0, status.EstimatedSpend)
+	assert.False(t, status.Tripped)
+	require.Len(t, status.Providers, 1)
+	assert.Equal(t, "vastai", status.Providers[0].Provider)
+	assert.False(t, status.Providers[0].BillingSupport)
+}
+
+func TestRBAC_DisabledWithoutKeys(t *testing.T) {
+	server, _, _ := setupAdminTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/sessions/sess-1", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_CreateSession(t *testing.T) {
+	server := newTestServer(nil, newMockSessionStore(), WithCreateSessionRateLimit(1, 1))
+
+	createSession := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// The first request reaches the handler (and fails validation)
+	w := createSession("10.0.0.1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = createSession("10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "rate_limited", resp["error_type"])
+
+	// Other clients have their own bucket
+	w = createSession("10.0.0.2")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Reads are not subject to the session creation limit
+	req := httptest.NewRequest("GET", "/api/v1/inventory", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_ForwardedForOnlyFromTrustedProxies(t *testing.T) {
+	createSession := func(server *Server, forwardedFor string) int {
+		req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// A forged header does not give a client a fresh bucket
+	server := newTestServer(nil, newMockSessionStore(), WithCreateSessionRateLimit(1, 1))
+	assert.Equal(t, http.StatusBadRequest, createSession(server, "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, createSession(server, "203.0.113.2"))
+
+	// Behind a trusted proxy, each forwarded client has its own bucket
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.10")
+	require.NoError(t, err)
+	server = newTestServer(nil, newMockSessionStore(), WithTrustedProxies(proxies), WithCreateSessionRateLimit(1, 1))
+	assert.Equal(t, http.StatusBadRequest, createSession(server, "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, createSession(server, "203.0.113.1"))
+	assert.Equal(t, http.StatusBadRequest, createSession(server, "203.0.113.2"))
+
+	_, err = ParseTrustedProxies("10.0.0.0/8,proxy.internal")
+	assert.Error(t, err)
+}
+
+func TestRateLimiter_EvictsFullBuckets(t *testing.T) {
+	l := newRateLimiter(1000, 1)
+	for i := range maxLimiterClients {
+		l.reserve(fmt.Sprintf("ip:%d", i), 1)
+	}
+	// Every earlier bucket has refilled by now and is dropped on the next sweep
+	time.Sleep(5 * time.Millisecond)
+	l.reserve("ip:new", 1)
+	assert.Len(t, l.clients, 1)
+}
+
+func TestRateLimit_PerAPIKey(t *testing.T) {
+	keys, err := ParseAPIKeys("bench-a:operator,bench-b:operator")
+	require.NoError(t, err)
+	server := newTestServer(nil, newMockSessionStore(), WithAPIKeys(keys), WithRateLimit(0.5, 2))
+
+	get := func(key string) int {
+		req := httptest.NewRequest("GET", "/api/v1/inventory", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Both keys share an IP but are limited separately
+	assert.Equal(t, http.StatusOK, get("bench-a"))
+	assert.Equal(t, http.StatusOK, get("bench-a"))
+	assert.Equal(t, http.StatusTooManyRequests, get("bench-a"))
+	assert.Equal(t, http.StatusOK, get("bench-b"))
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("a:viewer,b:c:admin,")
+	require.NoError(t, err)
+	assert.Equal(t, []APIKey{{Key: "a", Role: RoleViewer}, {Key: "b:c", Role: RoleAdmin}}, keys)
+
+	keys, err = ParseAPIKeys("team-a-key:operator@team-a")
+	require.NoError(t, err)
+	assert.Equal(t, []APIKey{{Key: "team-a-key", Role: RoleOperator, ConsumerID: "team-a"}}, keys)
+
+	keys, err = ParseAPIKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, spec := range []string{"nokey", ":viewer", "a:root", "a:viewer,a:admin", "a:operator@"} {
+		_, err := ParseAPIKeys(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestAdminFeatureFlags(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "flags.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	auditStore := storage.NewAuditStore(db)
+	flags := featureflags.New(storage.NewFeatureFlagStore(db),
+		featureflags.WithDefault("tensordock.dedicated_ip", true))
+	server := newTestServer(nil, newMockSessionStore(),
+		WithAdmin("admin-secret", auditStore), WithFeatureFlags(flags))
+
+	// Defaults are listed before anything is stored
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/feature-flags", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listResp struct {
+		Flags []models.FeatureFlag `json:"flags"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	require.Len(t, listResp.Flags, 1)
+	assert.True(t, listResp.Flags[0].Enabled)
+
+	// Canary the rollback of dedicated IPs
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("PUT", "/api/v1/admin/feature-flags/tensordock.dedicated_ip",
+		`{"enabled": true, "rollout_percent": 10}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var flag models.FeatureFlag
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &flag))
+	assert.Equal(t, 10, flag.RolloutPercent)
+	assert.Equal(t, "support-alice", flag.UpdatedBy)
+
+	// Validation
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("PUT", "/api/v1/admin/feature-flags/tensordock.dedicated_ip",
+		`{"enabled": true, "rollout_percent": 150}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("PUT", "/api/v1/admin/feature-flags/Bad%20Name", `{"enabled": true}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Reset to default, then 404 once gone
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/feature-flags/tensordock.dedicated_ip", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/feature-flags/tensordock.dedicated_ip", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{Actor: "support-alice"})
+	require.NoError(t, err)
+	actions := make(map[models.AuditAction]int)
+	for _, e := range entries {
+		actions[e.Action]++
+	}
+	assert.Equal(t, 2, actions[models.AuditActionSetFeatureFlag])
+	assert.Equal(t, 2, actions[models.AuditActionResetFeatureFlag])
+}
+
+func TestAdminFeatureFlagsNotConfigured(t *testing.T) {
+	server, _, _ := setupAdminTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/feature-flags", ""))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestAdminOfferSuppressions(t *testing.T) {
+	server, _, auditStore := setupAdminTestServer(t)
+
+	inventoryIDs := func() []string {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/inventory", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Offers []models.GPUOffer `json:"offers"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var ids []string
+		for _, o := range resp.Offers {
+			ids = append(ids, o.ID)
+		}
+		return ids
+	}
+	require.ElementsMatch(t, []string{"offer-1", "offer-2"}, inventoryIDs())
+
+	// Manually suppress an offer for an hour
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/offer-rules",
+		`{"action": "suppress", "scope": "offer", "value": "offer-1", "duration_minutes": 60}`))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var rule models.OfferRule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rule))
+	assert.NotEmpty(t, rule.ID)
+	assert.Equal(t, "support-alice", rule.CreatedBy)
+	assert.Equal(t, "ticket-123", rule.Reason, "reason defaults to the audit reason")
+	require.NotNil(t, rule.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *rule.ExpiresAt, time.Minute)
+	assert.Equal(t, []string{"offer-2"}, inventoryIDs())
+
+	// Automatic suppression after repeated failures
+	for i := 0; i < 3; i++ {
+		server.inventory.RecordOfferFailure("offer-2", "vastai", "A100", "", "ssh_timeout", "timed out")
+	}
+	assert.Empty(t, inventoryIDs())
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("GET", "/api/v1/admin/offer-suppressions", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listResp struct {
+		Suppressed []inventory.OfferHealthInfo `json:"suppressed"`
+		Rules      []models.OfferRule          `json:"rules"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	require.Len(t, listResp.Suppressed, 1)
+	assert.Equal(t, "offer-2", listResp.Suppressed[0].OfferID)
+	require.Len(t, listResp.Rules, 1)
+	assert.Equal(t, rule.ID, listResp.Rules[0].ID)
+
+	// Lift the automatic suppression, then 404 once nothing is tracked
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/offer-suppressions/offer-2", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"offer-2"}, inventoryIDs())
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/offer-suppressions/offer-2", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Remove the manual rule, then 404 once gone
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/offer-rules/"+rule.ID, ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.ElementsMatch(t, []string{"offer-1", "offer-2"}, inventoryIDs())
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/offer-rules/"+rule.ID, ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Validation
+	for _, body := range []string{
+		`{"action": "block", "scope": "offer", "value": "offer-1"}`,
+		`{"action": "suppress", "scope": "region", "value": "EU"}`,
+		`{"action": "suppress", "scope": "machine", "value": "   "}`,
+		`{"action": "allow", "scope": "offer", "value": "offer-1", "duration_minutes": -5}`,
+	} {
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/offer-rules", body))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{Actor: "support-alice"})
+	require.NoError(t, err)
+	actions := make(map[models.AuditAction]int)
+	for _, e := range entries {
+		actions[e.Action]++
+	}
+	assert.Equal(t, 2, actions[models.AuditActionClearSuppression])
+	assert.Equal(t, 2, actions[models.AuditActionDeleteOfferRule])
+	assert.GreaterOrEqual(t, actions[models.AuditActionSetOfferRule], 1)
+}
+
+// newSessionExportTestServer builds an admin server whose session export
+// service is backed by its own database
+func newSessionExportTestServer(t *testing.T, name string) (*Server, *storage.DB) {
+	t.Helper()
+	db, err := storage.New(filepath.Join(t.TempDir(), name))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	exporter := sessionexport.New(storage.NewSessionStore(db), storage.NewSessionEventStore(db), storage.NewCostStore(db))
+	server := newTestServer(nil, newMockSessionStore(),
+		WithAdmin("admin-secret", storage.NewAuditStore(db)), WithSessionExport(exporter))
+	return server, db
+}
+
+func TestAdminSessionExportImport(t *testing.T) {
+	source, sourceDB := newSessionExportTestServer(t, "source.db")
+	target, targetDB := newSessionExportTestServer(t, "target.db")
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, storage.NewSessionStore(sourceDB).Create(ctx, &models.Session{
+		ID: "sess-dr", ConsumerID: "consumer-1", Provider: "vastai", OfferID: "offer-1",
+		GPUType: "RTX4090", GPUCount: 1, Status: models.StatusRunning, SSHPublicKey: "ssh-ed25519 AAAA dr",
+		WorkloadType: models.WorkloadInteractive, ReservationHrs: 2, StoragePolicy: models.StorageDestroy,
+		PricePerHour: 0.5, CreatedAt: now, ExpiresAt: now.Add(2 * time.Hour),
+	}))
+
+	w := httptest.NewRecorder()
+	source.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/sessions/export",
+		`{"consumer_id": "consumer-1", "passphrase": "short"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	source.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/sessions/export",
+		`{"consumer_id": "consumer-1", "passphrase": "drill passphrase 2026"}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "ssh-ed25519")
+	exported := w.Body.String()
+
+	w = httptest.NewRecorder()
+	target.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/sessions/import",
+		`{"export": `+exported+`, "passphrase": "wrong passphrase here"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	target.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/sessions/import",
+		`{"export": `+exported+`, "passphrase": "drill passphrase 2026"}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result models.SessionImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"sess-dr"}, result.Imported)
+	assert.Equal(t, []string{"sess-dr"}, result.Deactivated)
+
+	imported, err := storage.NewSessionStore(targetDB).Get(ctx, "sess-dr")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusStopped, imported.Status)
+	assert.Equal(t, "ssh-ed25519 AAAA dr", imported.SSHPublicKey)
+
+	entries, err := storage.NewAuditStore(targetDB).List(ctx, models.AuditFilter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, models.AuditActionImportSessions, entries[0].Action)
+}
+
+func TestAdminSessionExportNotConfigured(t *testing.T) {
+	server, _, _ := setupAdminTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/sessions/export", `{"passphrase": "x"}`))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func setupWebhookTestServer(t *testing.T) *Server {
+	t.Helper()
+	db, err := storage.New(filepath.Join(t.TempDir(), "webhooks.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	notifier := notify.New(storage.NewWebhookStore(db))
+	return newTestServer(nil, newMockSessionStore(), WithNotifier(notifier))
+}
+
+func TestWebhookSubscriptionLifecycle(t *testing.T) {
+	server := setupWebhookTestServer(t)
+
+	// Create
+	body := `{"consumer_id":"consumer-001","url":"https://example.com/hook","events":["session.running","session.failed"]}`
+	req := httptest.NewRequest("POST", "/api/v1/webhooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created CreateWebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Webhook)
+	assert.NotEmpty(t, created.Webhook.ID)
+	assert.NotEmpty(t, created.Secret, "generated secret is returned once")
+	assert.Len(t, created.Webhook.Events, 2)
+
+	// List never exposes the secret
+	req = httptest.NewRequest("GET", "/api/v1/webhooks?consumer_id=consumer-001", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Secret)
+	var listResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	assert.Equal(t, 1.0, listResp["count"])
+
+	// Deliveries (none yet)
+	req = httptest.NewRequest("GET", "/api/v1/webhooks/"+created.Webhook.ID+"/deliveries", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":0`)
+
+	// Delete
+	req = httptest.NewRequest("DELETE", "/api/v1/webhooks/"+created.Webhook.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/webhooks/"+created.Webhook.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhooksScopedToConsumer(t *testing.T) {
+	server := setupWebhookTestServer(t)
+	keys, err := ParseAPIKeys("ci-key:operator,team-a-key:operator@consumer-001,root-key:admin")
+	require.NoError(t, err)
+	WithAPIKeys(keys)(server)
+
+	create := func(key, consumerID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/webhooks",
+			strings.NewReader(`{"consumer_id":"`+consumerID+`","url":"https://example.com/hook"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Keys not bound to a consumer need admin to subscribe on its behalf
+	assert.Equal(t, http.StatusForbidden, create("ci-key", "consumer-001").Code)
+	w := create("team-a-key", "consumer-002")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "consumer_scope")
+	require.Equal(t, http.StatusCreated, create("root-key", "consumer-002").Code)
+
+	w = create("team-a-key", "consumer-001")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created CreateWebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	// A bound key only lists its consumer's subscriptions
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("GET", "/api/v1/webhooks", "team-a-key"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("DELETE", "/api/v1/webhooks/"+created.Webhook.ID, "ci-key"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, rbacRequest("DELETE", "/api/v1/webhooks/"+created.Webhook.ID, "team-a-key"))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	server := setupWebhookTestServer(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing url", `{"consumer_id":"consumer-001"}`},
+		{"bad scheme", `{"consumer_id":"consumer-001","url":"ftp://example.com"}`},
+		{"unknown event", `{"consumer_id":"consumer-001","url":"https://example.com","events":["session.exploded"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/webhooks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.Router().ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestWebhooksUnavailableWithoutNotifier(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/webhooks", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+type mockSessionEventStore struct {
+	events map[string][]*models.SessionEvent
+}
+
+func (m *mockSessionEventStore) ListBySession(ctx context.Context, sessionID string) ([]*models.SessionEvent, error) {
+	return m.events[sessionID], nil
+}
+
+func TestGetSessionEvents(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	sessionStore.sessions["sess-1"] = &models.Session{
+		ID:         "sess-1",
+		ConsumerID: "consumer-001",
+		Provider:   "vastai",
+		Status:     models.StatusFailed,
+		Error:      "SSH verification timeout",
+	}
+	eventStore := &mockSessionEventStore{events: map[string][]*models.SessionEvent{
+		"sess-1": {
+			{ID: 1, SessionID: "sess-1", ToStatus: models.StatusPending},
+			{ID: 2, SessionID: "sess-1", FromStatus: models.StatusPending, ToStatus: models.StatusProvisioning},
+			{ID: 3, SessionID: "sess-1", FromStatus: models.StatusProvisioning, ToStatus: models.StatusFailed,
+				Reason: "SSH verification timeout"},
+		},
+	}}
+	server := newTestServer(nil, sessionStore, WithSessionEventStore(eventStore))
+
+	req := httptest.NewRequest("GET", "/api/v1/sessions/sess-1/events", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		SessionID string                 `json:"session_id"`
+		Events    []*models.SessionEvent `json:"events"`
+		Count     int                    `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "sess-1", resp.SessionID)
+	require.Equal(t, 3, resp.Count)
+	assert.Equal(t, models.StatusFailed, resp.Events[2].ToStatus)
+	assert.Equal(t, "SSH verification timeout", resp.Events[2].Reason)
+
+	// Unknown session
+	req = httptest.NewRequest("GET", "/api/v1/sessions/missing/events", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetPriceHistory(t *testing.T) {
+	server := setupTestServer()
+
+	// gpu is required
+	req := httptest.NewRequest("GET", "/api/v1/inventory/history", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/inventory/history?gpu=RTX4090&hours=0", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The test inventory has no price history store
+	req = httptest.NewRequest("GET", "/api/v1/inventory/history?gpu=RTX4090", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	// Offer lookups are unaffected by the history route
+	req = httptest.NewRequest("GET", "/api/v1/inventory/offer-1", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPriceWatches(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "watches.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Migrate(context.Background()))
+
+	server := setupTestServer()
+	server.inventory = inventory.New(
+		[]provider.Provider{&mockProvider{name: "vastai"}},
+		inventory.WithPriceWatches(storage.NewPriceWatchStore(db), nil))
+
+	body := `{"consumer_id":"consumer-1","gpu_type":"RTX4090","max_price":0.40,"region":"US"}`
+	req := httptest.NewRequest("POST", "/api/v1/watches", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var watch models.PriceWatch
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &watch))
+	assert.NotEmpty(t, watch.ID)
+	assert.Equal(t, 0.40, watch.MaxPrice)
+
+	// Unknown providers and non-positive prices are rejected
+	for _, bad := range []string{
+		`{"consumer_id":"consumer-1","gpu_type":"RTX4090","max_price":0.40,"provider":"nope"}`,
+		`{"consumer_id":"consumer-1","gpu_type":"RTX4090","max_price":-1}`,
+	} {
+		req = httptest.NewRequest("POST", "/api/v1/watches", strings.NewReader(bad))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/watches?consumer_id=consumer-1", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Watches []models.PriceWatch `json:"watches"`
+		Count   int                 `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Count)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/watches/"+watch.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/watches/"+watch.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPriceWatchesNotConfigured(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/watches", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestBenchmarkSchedules(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "schedules.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	scheduleStore, err := benchsvc.NewScheduleStore(db.DB)
+	require.NoError(t, err)
+
+	server := newTestServer(nil, newMockSessionStore(),
+		WithBenchmarkScheduler(benchsvc.NewScheduler(nil, scheduleStore, slog.Default())))
+
+	for _, bad := range []string{
+		`{"name":"weekly","cron":"every sunday","run_request":{"models":["llama3.1:8b"]}}`,
+		`{"name":"weekly","cron":"0 0 * * 8","run_request":{"models":["llama3.1:8b"]}}`,
+		`{"name":"weekly","cron":"0 0 * * 0","run_request":{}}`,
+	} {
+		req := httptest.NewRequest("POST", "/api/v1/benchmark-schedules", strings.NewReader(bad))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/benchmark-schedules",
+		strings.NewReader(`{"name":"weekly","cron":"0 0 * * 0","run_request":{"models":["llama3.1:8b"]}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Schedule benchsvc.Schedule `json:"schedule"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Schedule.NextRunAt)
+	assert.Equal(t, time.Sunday, created.Schedule.NextRunAt.Weekday())
+
+	req = httptest.NewRequest("PUT", "/api/v1/benchmark-schedules/"+created.Schedule.ID,
+		strings.NewReader(`{"cron":"61 * * * *"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateSessionQueuedWhenOfferGone(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "queue.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	server := setupTestServer()
+	server.reservations = provisioner.NewReservationQueue(server.provisioner, server.inventory,
+		storage.NewReservationStore(db), provisioner.WithQueueLogger(slog.Default()))
+
+	// Queueing needs a GPU type to match future offers against
+	body := `{"consumer_id":"consumer-001","offer_id":"nonexistent","workload_type":"llm","reservation_hours":2,
+		"queue":{"filter":{"max_price":0.40}}}`
+	req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Nothing matches (the only RTX4090 costs 0.50), so the request waits
+	body = `{"consumer_id":"consumer-001","offer_id":"nonexistent","workload_type":"llm","reservation_hours":2,
+		"queue":{"filter":{"gpu_type":"RTX4090","max_price":0.40},"max_wait_minutes":30}}`
+	req = httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	var queued QueuedSessionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &queued))
+	require.NotNil(t, queued.Reservation)
+	assert.Equal(t, models.ReservationWaiting, queued.Reservation.Status)
+	assert.Equal(t, 1, queued.Reservation.Position)
+	assert.Nil(t, queued.Session)
+
+	req = httptest.NewRequest("GET", "/api/v1/session-queue/"+queued.Reservation.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/session-queue?consumer_id=consumer-001&status=waiting", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Count)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/session-queue/"+queued.Reservation.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &queued))
+	assert.Equal(t, models.ReservationCancelled, queued.Reservation.Status)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/session-queue/"+queued.Reservation.ID, nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/session-queue/missing", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateSessionAutoSelectsOffer(t *testing.T) {
+	server := setupTestServer()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/sessions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"consumer_id":"consumer-001","workload_type":"llm","reservation_hours":1,
+		"offer":{"min_vram":40,"max_price":2.00}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response CreateSessionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "offer-2", response.SelectedOfferID)
+	assert.Equal(t, "A100", response.Session.GPUType)
+	assert.NotEmpty(t, response.SSHPrivateKey)
+
+	w = post(`{"consumer_id":"consumer-002","workload_type":"llm","reservation_hours":1,
+		"offer":{"gpu_type":"H100"}}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "no_matching_offer")
+
+	// Either an offer ID or a spec, and the spec must narrow the GPU
+	w = post(`{"consumer_id":"consumer-003","offer_id":"offer-1","workload_type":"llm","reservation_hours":1,
+		"offer":{"gpu_type":"RTX4090"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post(`{"consumer_id":"consumer-003","workload_type":"llm","reservation_hours":1,"offer":{"max_price":1.0}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post(`{"consumer_id":"consumer-003","workload_type":"llm","reservation_hours":1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The test offers have no location, so no region can hold them
+	w = post(`{"consumer_id":"consumer-004","workload_type":"llm","reservation_hours":1,
+		"offer":{"gpu_type":"A100","regions":["EU"]}}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = post(`{"consumer_id":"consumer-004","workload_type":"llm","reservation_hours":1,
+		"offer":{"gpu_type":"A100","regions":["Europe"]}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid offer region")
+}
+
+func TestListInventoryRanked(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory?rank=true&limit=1", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Offers []ranking.RankedOffer `json:"offers"`
+		Count  int                   `json:"count"`
+		Total  int                   `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, 2, response.Total)
+	require.Len(t, response.Offers, 1)
+
+	// Same reliability and confidence, so the cheaper offer wins
+	top := response.Offers[0]
+	assert.Equal(t, "offer-1", top.ID)
+	assert.Equal(t, 1, top.Ranking.Rank)
+	require.NotNil(t, top.Ranking.Price)
+	assert.Equal(t, 1.0, top.Ranking.Price.Score)
+	assert.Greater(t, top.Ranking.Score, 0.0)
+
+	req = httptest.NewRequest("GET", "/api/v1/inventory?rank=maybe", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStreamBenchmarkRun(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "runs.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := benchmark.NewStore(db.DB)
+	require.NoError(t, err)
+	manifest, err := benchmark.NewManifestStore(db.DB)
+	require.NoError(t, err)
+
+	// An endpoint that fails every request ends the run quickly
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer endpoint.Close()
+
+	runner := benchsvc.NewRunner(nil, nil, store, manifest, slog.Default(), "")
+	server := newTestServer(nil, newMockSessionStore(), WithBenchmarkRunner(runner))
+	server.benchmarkStreamInterval = 10 * time.Millisecond
+
+	req := httptest.NewRequest("POST", "/api/v1/benchmark-runs",
+		strings.NewReader(`{"endpoint":"`+endpoint.URL+`","models":["llama3.1:8b"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var started struct {
+		Run benchsvc.BenchmarkRun `json:"run"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+
+	// The stream ends by itself once the run is over
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/benchmark-runs/"+started.Run.ID+"/events", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+	body := w.Body.String()
+	assert.Contains(t, body, "event:progress")
+	assert.Contains(t, body, `"entries":[`)
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(body), `data:{"run_id":"`+started.Run.ID+`","status":"failed"}`), body)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/benchmark-runs/missing/events", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBenchmarkHistory(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "bench.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := benchmark.NewStore(db.DB)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, tps := range []float64{100, 120} {
+		r := &benchmark.BenchmarkResult{Timestamp: base.AddDate(0, 0, i), Provider: "vastai", PricePerHour: 0.40}
+		r.Model.Name = "llama3.1:8b"
+		r.Hardware.GPUName = "RTX 4090"
+		r.Results.AvgTokensPerSecond = tps
+		require.NoError(t, store.Save(ctx, r))
+	}
+
+	server := setupTestServer()
+	server.benchmarkStore = store
+
+	req := httptest.NewRequest("GET", "/api/v1/benchmarks/history?model=llama&until=2026-03-02", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		History []benchmark.HistoryEntry `json:"history"`
+		Count   int                      `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 2, response.Count, "a bare until date includes that day")
+	assert.Equal(t, benchmark.TrendUp, response.History[0].Trend)
+	assert.InDelta(t, 20, response.History[0].ChangePct, 0.001)
+
+	req = httptest.NewRequest("GET", "/api/v1/benchmarks/history?since=yesterday", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBenchmarkReport(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "bench.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := benchmark.NewStore(db.DB)
+	require.NoError(t, err)
+
+	r := &benchmark.BenchmarkResult{Provider: "vastai", PricePerHour: 0.40}
+	r.Model.Name = "llama3.1:8b"
+	r.Hardware.GPUName = "RTX 4090"
+	r.Results.AvgTokensPerSecond = 100
+	require.NoError(t, store.Save(context.Background(), r))
+
+	server := setupTestServer()
+	server.benchmarkStore = store
+
+	for format, contentType := range map[string]string{
+		"":         "text/markdown",
+		"json":     "application/json",
+		"html":     "text/html",
+		"markdown": "text/markdown",
+	} {
+		req := httptest.NewRequest("GET", "/api/v1/benchmarks/report?model=llama&format="+format, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Type"), contentType, format)
+		assert.Contains(t, w.Body.String(), "RTX 4090", format)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/benchmarks/report?format=pdf", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListBenchmarkWorkloads(t *testing.T) {
+	server := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/benchmarks/workloads", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Workloads []benchmark.WorkloadSpec `json:"workloads"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Workloads, 4)
+	assert.Equal(t, benchmark.WorkloadChat, response.Workloads[0].Workload)
+	assert.Equal(t, "images", response.Workloads[3].Unit)
+}
+
+func TestBenchmarkCatalog(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "catalog.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	catalogStore, err := benchmark.NewCatalogStore(db.DB)
+	require.NoError(t, err)
+	catalog := benchmark.NewCatalog()
+	require.NoError(t, catalog.LoadStore(context.Background(), catalogStore))
+	auditStore := storage.NewAuditStore(db)
+	server := newTestServer(nil, newMockSessionStore(),
+		WithAdmin("admin-secret", auditStore), WithBenchmarkCatalog(catalog))
+
+	// Add a fine-tuned model; Hugging Face IDs contain a slash
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/benchmark-catalog/models",
+		`{"name": "acme/support-llama-8b", "min_vram_gb": 20}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var model benchmark.CatalogModel
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+	assert.Equal(t, benchmark.WorkloadChat, model.Workload)
+	assert.Equal(t, benchmark.CatalogSourceCustom, model.Source)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/benchmark-catalog/gpus",
+		`{"name": "RTX 6000 Ada", "vram_gb": 48}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/benchmarks/catalog", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listResp struct {
+		Models []benchmark.CatalogModel `json:"models"`
+		GPUs   []benchmark.CatalogGPU   `json:"gpus"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	sources := make(map[string]string)
+	for _, m := range listResp.Models {
+		sources[m.Name] = m.Source
+	}
+	assert.Equal(t, benchmark.CatalogSourceCustom, sources["acme/support-llama-8b"])
+	assert.Equal(t, benchmark.CatalogSourceBuiltin, sources["llama3.1:8b"])
+	assert.Len(t, listResp.GPUs, len(benchmark.NewCatalog().GPUs())+1)
+
+	// Validation
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/benchmark-catalog/models",
+		`{"name": "acme/x", "workload": "video"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("POST", "/api/v1/admin/benchmark-catalog/gpus", `{"name": "X"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Remove, then 404 once gone; built-in entries cannot be removed
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/benchmark-catalog/models/acme/support-llama-8b", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	_, ok := catalog.Model("acme/support-llama-8b")
+	assert.False(t, ok)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/benchmark-catalog/models/acme/support-llama-8b", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/benchmark-catalog/gpus/RTX%204090", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, adminRequest("DELETE", "/api/v1/admin/benchmark-catalog/gpus/RTX%206000%20Ada", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	entries, err := auditStore.List(context.Background(), models.AuditFilter{Actor: "support-alice"})
+	require.NoError(t, err)
+	actions := make(map[models.AuditAction]int)
+	for _, e := range entries {
+		actions[e.Action]++
+	}
+	assert.Equal(t, 2, actions[models.AuditActionAddCatalogModel])
+	assert.Equal(t, 2, actions[models.AuditActionRemoveCatalogModel])
+	assert.Equal(t, 1, actions[models.AuditActionAddCatalogGPU], "invalid bodies are rejected before auditing")
+	assert.Equal(t, 2, actions[models.AuditActionRemoveCatalogGPU])
+
+	// Without a catalog the routes are unavailable
+	w = httptest.NewRecorder()
+	setupTestServer().Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/benchmarks/catalog", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGetCosts_GroupBy(t *testing.T) {
+	server := setupTestServer()
+	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, r := range []*models.CostRecord{
+		{SessionID: "sess-1", ConsumerID: "team-a", Provider: "vastai", GPUType: "RTX4090", Category: models.CostCategoryGPU, Hour: hour, Amount: 0.5},
+		{SessionID: "sess-2", ConsumerID: "team-a", Provider: "vastai", GPUType: "A100", Category: models.CostCategoryGPU, Hour: hour, Amount: 1.5},
+		{SessionID: "sess-3", ConsumerID: "team-b", Provider: "vastai", GPUType: "RTX4090", Category: models.CostCategoryGPU, Hour: hour, Amount: 0.5},
+	} {
+		server.costTracker.RecordCost(context.Background(), r)
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs?group_by=consumer,deployment&start_date=2026-01-01&end_date=2026-02-01", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var breakdown models.CostBreakdown
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &breakdown))
+	assert.Equal(t, []models.CostGroupBy{models.CostGroupByConsumer, models.CostGroupByDeployment}, breakdown.GroupBy)
+	assert.Equal(t, 2.5, breakdown.TotalCost)
+	require.Len(t, breakdown.Groups, 2)
+	assert.Equal(t, "team-a", breakdown.Groups[0].ConsumerID)
+	assert.Equal(t, 2.0, breakdown.Groups[0].TotalCost)
+	assert.Equal(t, server.provisioner.GetDeploymentID(), breakdown.Groups[0].Deployment)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs?group_by=gpu_type&consumer_id=team-a&start_date=2026-01-01&format=csv", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "chargeback.csv")
+	assert.Equal(t,
+		"period_start,period_end,gpu_type,total_cost,session_count,hours_used\n"+
+			"2026-01-01T00:00:00Z,2026-02-01T00:00:00Z,RTX4090,0.5000,1,1\n"+
+			"2026-01-01T00:00:00Z,2026-02-01T00:00:00Z,A100,1.5000,1,1\n",
+		w.Body.String())
+
+	for _, query := range []string{"group_by=team", "group_by=consumer&period=year", "group_by=consumer&format=xlsx"} {
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestCostReconciliation(t *testing.T) {
+	server := setupTestServer()
+	server.costTracker.RecordCost(context.Background(), &models.CostRecord{
+		SessionID: "sess-1", ConsumerID: "consumer-001", Provider: "vastai", GPUType: "RTX4090",
+		Category: models.CostCategoryGPU, Hour: time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), Amount: 0.5, Currency: "USD",
+	})
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs/reconciliation?start_date=2026-01-01&end_date=2026-01-31", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report models.CostReconciliation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), report.PeriodEnd, "end_date is inclusive")
+	assert.Equal(t, 10.0, report.ThresholdPct)
+	require.Len(t, report.Sessions, 1)
+	assert.Equal(t, "sess-1", report.Sessions[0].SessionID)
+	assert.Nil(t, report.Sessions[0].ReportedCost, "no provider usage recorded")
+	assert.Equal(t, 1, report.Unverified)
+
+	for _, query := range []string{"start_date=01-01-2026", "end_date=2026-13-01", "start_date=2026-02-01&end_date=2026-01-01"} {
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs/reconciliation?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetSessionCost(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+	sessionStore.sessions["sess-run"] = &models.Session{
+		ID: "sess-run", ConsumerID: "consumer-001", Status: models.StatusRunning, PricePerHour: 2.00,
+		ExpiresAt: time.Now().Add(10 * time.Hour),
+	}
+	sessionStore.sessions["sess-done"] = &models.Session{
+		ID: "sess-done", ConsumerID: "consumer-001", Status: models.StatusStopped, PricePerHour: 2.00,
+	}
+	server.costTracker.RecordCost(context.Background(), &models.CostRecord{
+		SessionID: "sess-done", ConsumerID: "consumer-001", Hour: time.Now().Truncate(time.Hour), Amount: 2.00,
+	})
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/sess-run/cost?extend_hours=4", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var forecast models.SessionCostForecast
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &forecast))
+	assert.Equal(t, 2.00, forecast.BurnRate)
+	assert.Zero(t, forecast.AccruedCost)
+	// 9-10 unbilled hours remain, depending on the time within the hour
+	assert.InDelta(t, 19.00, forecast.ProjectedAtExpiry, 1.0)
+	assert.Equal(t, 4, forecast.ExtendHours)
+	assert.InDelta(t, forecast.ProjectedAtExpiry+8.00, forecast.ProjectedIfExtended, 0.0001)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/sess-done/cost", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &forecast))
+	assert.Equal(t, 1, forecast.ExtendHours, "defaults to one hour")
+	assert.Equal(t, 2.00, forecast.AccruedCost)
+	assert.Equal(t, 2.00, forecast.ProjectedAtExpiry)
+	assert.Equal(t, 2.00, forecast.ProjectedIfExtended, "ended sessions cannot be extended")
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/sess-run/cost?extend_hours=13", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/nonexistent/cost", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCostForecast(t *testing.T) {
+	sessionStore := newMockSessionStore()
+	server := newTestServer(nil, sessionStore)
+	sessionStore.sessions["sess-a"] = &models.Session{
+		ID: "sess-a", ConsumerID: "consumer-001", Status: models.StatusRunning, PricePerHour: 2.00,
+		ExpiresAt: time.Now().Add(10 * time.Hour),
+	}
+	sessionStore.sessions["sess-b"] = &models.Session{
+		ID: "sess-b", ConsumerID: "consumer-002", Status: models.StatusRunning, PricePerHour: 1.00,
+		ExpiresAt: time.Now().Add(10 * time.Hour),
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs/forecast", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var fleet models.FleetCostForecast
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fleet))
+	assert.Equal(t, 2, fleet.ActiveSessions)
+	assert.Equal(t, 3.00, fleet.BurnRate)
+	assert.GreaterOrEqual(t, fleet.ProjectedToday, fleet.SpentToday)
+	require.Len(t, fleet.Sessions, 2)
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/costs/forecast?consumer_id=consumer-002", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fleet))
+	assert.Equal(t, "consumer-002", fleet.ConsumerID)
+	assert.Equal(t, 1, fleet.ActiveSessions)
+	assert.Equal(t, 1.00, fleet.BurnRate)
+}
+
+// mockFailureStatsStore returns fixed failure counts and records the range asked for
+type mockFailureStatsStore struct {
+	counts     []models.FailureCount
+	start, end time.Time
+}
+
+func (m *mockFailureStatsStore) GetFailureCounts(ctx context.Context, start, end time.Time) ([]models.FailureCount, error) {
+	m.start, m.end = start, end
+	return m.counts, nil
+}
+
+func TestFailureAnalytics(t *testing.T) {
+	w := httptest.NewRecorder()
+	setupTestServer().Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/analytics/failures", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	stats := &mockFailureStatsStore{counts: []models.FailureCount{
+		{Provider: "vastai", GPUType: "RTX4090", Location: "US", Count: 6},
+		{Provider: "vastai", GPUType: "RTX4090", Location: "US", Category: models.FailureSSHTimeout, Count: 3},
+		{Provider: "vastai", GPUType: "A100", Location: "EU", Category: models.FailureStaleInventory, Count: 1},
+		{Provider: "tensordock", GPUType: "A100", Category: models.FailureInstanceStopped, Count: 2},
+		{Provider: "tensordock", GPUType: "A100", Count: 8},
+	}}
+	server := newTestServer(nil, newMockSessionStore(), WithFailureStatsStore(stats))
+
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/analytics/failures?start_date=2026-03-01&end_date=2026-03-31", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), stats.start)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), stats.end, "end_date is inclusive")
+
+	var analytics models.FailureAnalytics
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &analytics))
+	assert.Equal(t, 20, analytics.Sessions)
+	assert.Equal(t, 6, analytics.Failures)
+	assert.InDelta(t, 0.3, analytics.FailureRate, 0.0001)
+	assert.Equal(t, 3, analytics.ByCategory[models.FailureSSHTimeout])
+
+	require.Len(t, analytics.ByProvider, 2)
+	assert.Equal(t, "vastai", analytics.ByProvider[0].Key, "most failures first")
+	assert.Equal(t, 10, analytics.ByProvider[0].Sessions)
+	assert.Equal(t, 4, analytics.ByProvider[0].Failures)
+	assert.InDelta(t, 0.4, analytics.ByProvider[0].FailureRate, 0.0001)
+
+	require.Len(t, analytics.ByGPUType, 2)
+	assert.Equal(t, "A100", analytics.ByGPUType[0].Key, "ties ordered by key")
+	assert.Equal(t, 3, analytics.ByGPUType[0].Failures)
+	assert.Equal(t, 1, analytics.ByGPUType[0].ByCategory[models.FailureStaleInventory])
+	assert.Equal(t, "RTX4090", analytics.ByGPUType[1].Key)
+
+	require.Len(t, analytics.ByLocation, 3)
+	assert.Equal(t, "US", analytics.ByLocation[0].Key)
+	assert.Equal(t, "unknown", analytics.ByLocation[1].Key)
+	assert.Equal(t, 10, analytics.ByLocation[1].Sessions)
+
+	for _, query := range []string{"start_date=03-01-2026", "end_date=2026-13-01", "start_date=2026-04-01&end_date=2026-03-01"} {
+		w = httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/analytics/failures?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestExportCosts(t *testing.T) {
+	server := setupTestServer()
+	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	server.costTracker.RecordCost(context.Background(), &models.CostRecord{
+		SessionID: "sess-1", ConsumerID: "consumer-001", Provider: "vastai", GPUType: "RTX4090",
+		Category: models.CostCategoryGPU, Hour: hour, Amount: 0.5, Currency: "USD",
+	})
+	server.costTracker.RecordCost(context.Background(), &models.CostRecord{
+		SessionID: "sess-2", ConsumerID: "consumer-001", Provider: "vastai", GPUType: "RTX4090",
+		Category: models.CostCategoryGPU, Hour: hour.AddDate(0, 0, 2), Amount: 0.5, Currency: "USD",
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/costs/export?consumer_id=consumer-001&end_date=2026-01-15", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "costs.csv")
+	assert.Equal(t,
+		"hour,session_id,consumer_id,provider,gpu_type,category,amount,currency\n"+
+			"2026-01-15T10:00:00Z,sess-1,consumer-001,vastai,RTX4090,gpu,0.5000,USD\n",
+		w.Body.String(), "end_date includes that whole day only")
+
+	req = httptest.NewRequest("GET", "/api/v1/costs/export?format=json", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+
+	req = httptest.NewRequest("GET", "/api/v1/costs/export?format=xlsx", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// validGroupIDRegex restricts client-supplied group IDs to URL-safe names
+var validGroupIDRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// GroupDestroyFailure reports a group member that could not be destroyed
+type GroupDestroyFailure struct {
+	SessionID string `json:"session_id"`
+	Error     string `json:"error"`
+}
+
+// DestroySessionGroupResponse is the result of destroying a session group
+type DestroySessionGroupResponse struct {
+	GroupID   string                `json:"group_id"`
+	Destroyed []string              `json:"destroyed"`
+	Failed    []GroupDestroyFailure `json:"failed,omitempty"`
+}
+
+// listGroupSessions returns every session in a group, writing a 404 and
+// returning false if the group has none.
+func (s *Server) listGroupSessions(c *gin.Context, groupID string) ([]*models.Session, bool) {
+	sessions, err := s.provisioner.ListSessions(c.Request.Context(), models.SessionListFilter{GroupID: groupID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list sessions",
+			RequestID: c.GetString("request_id"),
+		})
+		return nil, false
+	}
+	if len(sessions) == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:     "session group not found: " + sanitizeInput(groupID, 128),
+			RequestID: c.GetString("request_id"),
+		})
+		return nil, false
+	}
+	return sessions, true
+}
+
+// handleGetSessionGroup lists the sessions in a group
+func (s *Server) handleGetSessionGroup(c *gin.Context) {
+	groupID := c.Param("id")
+
+	sessions, ok := s.listGroupSessions(c, groupID)
+	if !ok {
+		return
+	}
+
+	responses := make([]models.SessionResponse, len(sessions))
+	active := 0
+	for i, session := range sessions {
+		responses[i] = session.ToResponse()
+		if session.IsActive() {
+			active++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group_id": groupID,
+		"sessions": responses,
+		"count":    len(responses),
+		"active":   active,
+	})
+}
+
+// handleDeleteSessionGroup destroys every active session in a group in
+// parallel. Members that fail to destroy are reported and the response is
+// 207 so the caller can retry them.
+func (s *Server) handleDeleteSessionGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	groupID := c.Param("id")
+
+	sessions, ok := s.listGroupSessions(c, groupID)
+	if !ok {
+		return
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		resp = DestroySessionGroupResponse{GroupID: groupID, Destroyed: []string{}}
+	)
+	for _, session := range sessions {
+		if !session.IsActive() {
+			continue
+		}
+		wg.Add(1)
+		go func(sessionID string) {
+			defer wg.Done()
+			err := s.provisioner.DestroySession(ctx, sessionID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				s.logger.Error("failed to destroy session group member",
+					slog.String("group_id", groupID),
+					slog.String("session_id", sessionID),
+					slog.String("error", err.Error()))
+				resp.Failed = append(resp.Failed, GroupDestroyFailure{SessionID: sessionID, Error: err.Error()})
+				return
+			}
+			resp.Destroyed = append(resp.Destroyed, sessionID)
+		}(session.ID)
+	}
+	wg.Wait()
+
+	s.logger.Info("session group destroyed",
+		slog.String("group_id", groupID),
+		slog.Int("destroyed", len(resp.Destroyed)),
+		slog.Int("failed", len(resp.Failed)))
+
+	status := http.StatusOK
+	if len(resp.Failed) > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/notify"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// CreateWebhookRequest is the request body for registering a webhook
+type CreateWebhookRequest struct {
+	ConsumerID string                    `json:"consumer_id" binding:"required"`
+	URL        string                    `json:"url" binding:"required"`
+	Events     []models.WebhookEventType `json:"events"`           // Empty subscribes to all events
+	Secret     string                    `json:"secret,omitempty"` // Generated when omitted
+}
+
+// CreateWebhookResponse returns the new subscription along with its signing
+// secret, which is not shown again.
+type CreateWebhookResponse struct {
+	Webhook *models.WebhookSubscription `json:"webhook"`
+	Secret  string                      `json:"secret"`
+}
+
+// handleCreateWebhook registers a webhook subscription for a consumer. Only
+// the consumer's own API key or an admin may do so.
+func (s *Server) handleCreateWebhook(c *gin.Context) {
+	if !s.requireNotifier(c) {
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "invalid request: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if !s.authorizeConsumer(c, req.ConsumerID, RoleAdmin) {
+		return
+	}
+
+	sub := &models.WebhookSubscription{
+		ConsumerID: req.ConsumerID,
+		URL:        req.URL,
+		Events:     req.Events,
+		Secret:     req.Secret,
+	}
+	if err := s.notifier.Subscribe(c.Request.Context(), sub); err != nil {
+		var invalidErr *notify.InvalidSubscriptionError
+		if errors.As(err, &invalidErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     err.Error(),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to create webhook: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateWebhookResponse{Webhook: sub, Secret: sub.Secret})
+}
+
+// handleListWebhooks lists webhook subscriptions, optionally filtered by
+// consumer. Keys bound to a consumer see only that consumer's.
+func (s *Server) handleListWebhooks(c *gin.Context) {
+	if !s.requireNotifier(c) {
+		return
+	}
+
+	consumerID := c.Query("consumer_id")
+	if bound := c.GetString("api_consumer"); bound != "" {
+		if consumerID != "" && !s.authorizeConsumer(c, consumerID, RoleViewer) {
+			return
+		}
+		consumerID = bound
+	}
+	subs, err := s.notifier.ListSubscriptions(c.Request.Context(), consumerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list webhooks: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if subs == nil {
+		subs = []*models.WebhookSubscription{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+
+// handleDeleteWebhook removes a webhook subscription. Only the consumer's
+// own API key or an admin may do so.
+func (s *Server) handleDeleteWebhook(c *gin.Context) {
+	if !s.requireNotifier(c) {
+		return
+	}
+
+	id := c.Param("id")
+	sub, ok := s.getWebhook(c, id)
+	if !ok || !s.authorizeConsumer(c, sub.ConsumerID, RoleAdmin) {
+		return
+	}
+	if err := s.notifier.Unsubscribe(c.Request.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "webhook not found: " + sanitizeInput(id, 128),
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to delete webhook: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// handleListWebhookDeliveries lists recent delivery attempts for a subscription.
+func (s *Server) handleListWebhookDeliveries(c *gin.Context) {
+	if !s.requireNotifier(c) {
+		return
+	}
+
+	id := c.Param("id")
+	sub, ok := s.getWebhook(c, id)
+	if !ok || !s.authorizeConsumer(c, sub.ConsumerID, RoleViewer) {
+		return
+	}
+
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 500 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "limit must be between 1 and 500",
+				RequestID: c.GetString("request_id"),
+			})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := s.notifier.ListDeliveries(c.Request.Context(), id, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to list deliveries: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// requireNotifier writes a 503 and returns false when webhooks are not configured.
+func (s *Server) requireNotifier(c *gin.Context) bool {
+	if s.notifier == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:     "webhook notifications not available",
+			RequestID: c.GetString("request_id"),
+		})
+		return false
+	}
+	return true
+}
+
+// getWebhook looks up a subscription, writing the error response if it
+// cannot be found
+func (s *Server) getWebhook(c *gin.Context, id string) (*models.WebhookSubscription, bool) {
+	sub, err := s.notifier.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:     "webhook not found: " + sanitizeInput(id, 128),
+				RequestID: c.GetString("request_id"),
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:     "failed to get webhook: " + err.Error(),
+			RequestID: c.GetString("request_id"),
+		})
+		return nil, false
+	}
+	return sub, true
+}
@@ -0,0 +1,491 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog errors
+var (
+	ErrInvalidCatalogEntry  = errors.New("invalid catalog entry")
+	ErrCatalogEntryNotFound = errors.New("catalog entry not found")
+)
+
+// Where a catalog entry was defined. Later sources override earlier ones.
+const (
+	CatalogSourceBuiltin = "builtin"
+	CatalogSourceFile    = "file"
+	CatalogSourceCustom  = "custom" // Added through the admin API and stored in the database
+)
+
+// CatalogModel is a model the benchmark runner knows how to size
+type CatalogModel struct {
+	Name        string   `json:"name" yaml:"name"`                         // Ollama tag or Hugging Face ID
+	Workload    Workload `json:"workload" yaml:"workload"`                 // Default chat
+	MinVRAMGB   int      `json:"min_vram_gb,omitempty" yaml:"min_vram_gb"` // Per-GPU memory needed; 0 = unknown
+	Description string   `json:"description,omitempty" yaml:"description"`
+	Source      string   `json:"source" yaml:"-"` // builtin, file or custom
+}
+
+// CatalogGPU is a GPU type with its memory, used to skip GPUs a model
+// cannot fit on
+type CatalogGPU struct {
+	Name   string `json:"name" yaml:"name"`       // As offers report it, e.g. "RTX 4090"
+	VRAMGB int    `json:"vram_gb" yaml:"vram_gb"` // Per GPU
+	Source string `json:"source" yaml:"-"`
+}
+
+// CatalogFile is the YAML (or JSON) file layout loaded by LoadFile
+type CatalogFile struct {
+	Models []CatalogModel `yaml:"models"`
+	GPUs   []CatalogGPU   `yaml:"gpus"`
+}
+
+var builtinModels = []CatalogModel{
+	{Name: "phi3:mini", MinVRAMGB: 4},
+	{Name: "mistral:7b", MinVRAMGB: 8},
+	{Name: "llama3:8b", MinVRAMGB: 8},
+	{Name: "llama3.1:8b", MinVRAMGB: 8},
+	{Name: "qwen2.5:14b", MinVRAMGB: 16},
+	{Name: "deepseek-r1:14b", MinVRAMGB: 16},
+	{Name: "codellama:34b", MinVRAMGB: 24},
+	{Name: "llama3:70b", MinVRAMGB: 48},
+	{Name: "meta-llama/Llama-3.1-8B-Instruct", MinVRAMGB: 24},
+	{Name: "Qwen/Qwen2.5-7B-Instruct", MinVRAMGB: 24},
+	{Name: "BAAI/bge-large-en-v1.5", Workload: WorkloadEmbeddings, MinVRAMGB: 4},
+	{Name: "intfloat/e5-mistral-7b-instruct", Workload: WorkloadEmbeddings, MinVRAMGB: 24},
+	{Name: "openai/whisper-large-v3", Workload: WorkloadTranscription, MinVRAMGB: 10},
+	{Name: "openai/whisper-large-v3-turbo", Workload: WorkloadTranscription, MinVRAMGB: 6},
+	{Name: "stabilityai/stable-diffusion-xl-base-1.0", Workload: WorkloadImage, MinVRAMGB: 12},
+	{Name: "stabilityai/sdxl-turbo", Workload: WorkloadImage, MinVRAMGB: 12},
+}
+
+var builtinGPUs = []CatalogGPU{
+	{Name: "RTX 3090", VRAMGB: 24},
+	{Name: "RTX 4090", VRAMGB: 24},
+	{Name: "RTX 5070 Ti", VRAMGB: 16},
+	{Name: "RTX 5080", VRAMGB: 16},
+	{Name: "RTX 5090", VRAMGB: 32},
+	{Name: "RTX A6000", VRAMGB: 48},
+	{Name: "L40S", VRAMGB: 48},
+	{Name: "A100", VRAMGB: 80},
+	{Name: "H100 SXM", VRAMGB: 80},
+	{Name: "H100 PCIE", VRAMGB: 80},
+	{Name: "H200 NVL", VRAMGB: 141},
+}
+
+// modelNamePattern accepts Ollama tags and Hugging Face IDs
+var modelNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]*$`)
+
+const maxCatalogVRAMGB = 1024
+
+// Catalog is the set of models and GPUs benchmarks are planned against.
+// Built-in entries are overridden by a catalog file, which is overridden by
+// custom entries stored in the database. Models outside the catalog can
+// still be benchmarked; they are just never skipped for lack of VRAM.
+type Catalog struct {
+	mu           sync.RWMutex
+	models       map[string]CatalogModel // Built-in and file entries, by lowercased name
+	gpus         map[string]CatalogGPU
+	customModels map[string]CatalogModel
+	customGPUs   map[string]CatalogGPU
+	store        *CatalogStore // Persists custom entries; nil keeps them in memory
+}
+
+// NewCatalog returns a catalog of the built-in models and GPUs
+func NewCatalog() *Catalog {
+	c := &Catalog{
+		models:       make(map[string]CatalogModel),
+		gpus:         make(map[string]CatalogGPU),
+		customModels: make(map[string]CatalogModel),
+		customGPUs:   make(map[string]CatalogGPU),
+	}
+	for _, m := range builtinModels {
+		m, _ = normalizeCatalogModel(m)
+		m.Source = CatalogSourceBuiltin
+		c.models[catalogKey(m.Name)] = m
+	}
+	for _, g := range builtinGPUs {
+		g.Source = CatalogSourceBuiltin
+		c.gpus[catalogKey(g.Name)] = g
+	}
+	return c
+}
+
+// LoadFile adds the models and GPUs of a YAML catalog file, replacing
+// built-in entries of the same name. JSON files are valid YAML.
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read model catalog: %w", err)
+	}
+	var file CatalogFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse model catalog %s: %w", path, err)
+	}
+
+	models := make([]CatalogModel, len(file.Models))
+	for i, m := range file.Models {
+		if models[i], err = normalizeCatalogModel(m); err != nil {
+			return fmt.Errorf("model catalog %s: model %d: %w", path, i, err)
+		}
+	}
+	gpus := make([]CatalogGPU, len(file.GPUs))
+	for i, g := range file.GPUs {
+		if gpus[i], err = normalizeCatalogGPU(g); err != nil {
+			return fmt.Errorf("model catalog %s: gpu %d: %w", path, i, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range models {
+		m.Source = CatalogSourceFile
+		c.models[catalogKey(m.Name)] = m
+	}
+	for _, g := range gpus {
+		g.Source = CatalogSourceFile
+		c.gpus[catalogKey(g.Name)] = g
+	}
+	return nil
+}
+
+// LoadStore adds the custom entries saved in store, and saves entries added
+// from now on to it
+func (c *Catalog) LoadStore(ctx context.Context, store *CatalogStore) error {
+	models, err := store.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	gpus, err := store.ListGPUs(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = store
+	for _, m := range models {
+		c.customModels[catalogKey(m.Name)] = m
+	}
+	for _, g := range gpus {
+		c.customGPUs[catalogKey(g.Name)] = g
+	}
+	return nil
+}
+
+// Models returns every model in the catalog, sorted by name
+func (c *Catalog) Models() []CatalogModel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	merged := make(map[string]CatalogModel, len(c.models)+len(c.customModels))
+	for k, m := range c.models {
+		merged[k] = m
+	}
+	for k, m := range c.customModels {
+		merged[k] = m
+	}
+	out := make([]CatalogModel, 0, len(merged))
+	for _, m := range merged {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return catalogKey(out[i].Name) < catalogKey(out[j].Name) })
+	return out
+}
+
+// GPUs returns every GPU in the catalog, sorted by VRAM then name
+func (c *Catalog) GPUs() []CatalogGPU {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	merged := make(map[string]CatalogGPU, len(c.gpus)+len(c.customGPUs))
+	for k, g := range c.gpus {
+		merged[k] = g
+	}
+	for k, g := range c.customGPUs {
+		merged[k] = g
+	}
+	out := make([]CatalogGPU, 0, len(merged))
+	for _, g := range merged {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].VRAMGB != out[j].VRAMGB {
+			return out[i].VRAMGB < out[j].VRAMGB
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Model looks up a model by name, ignoring case
+func (c *Catalog) Model(name string) (CatalogModel, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if m, ok := c.customModels[catalogKey(name)]; ok {
+		return m, true
+	}
+	m, ok := c.models[catalogKey(name)]
+	return m, ok
+}
+
+// GPU looks up a GPU type by name, ignoring case
+func (c *Catalog) GPU(name string) (CatalogGPU, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if g, ok := c.customGPUs[catalogKey(name)]; ok {
+		return g, true
+	}
+	g, ok := c.gpus[catalogKey(name)]
+	return g, ok
+}
+
+// MinVRAM returns the per-GPU memory a model needs, or 0 when unknown
+func (c *Catalog) MinVRAM(model string) int {
+	m, _ := c.Model(model)
+	return m.MinVRAMGB
+}
+
+// FitsGPU reports whether model can run on a GPU type. It is true unless
+// both are in the catalog and the GPU has too little memory.
+func (c *Catalog) FitsGPU(model, gpu string) bool {
+	m, ok := c.Model(model)
+	if !ok || m.MinVRAMGB == 0 {
+		return true
+	}
+	g, ok := c.GPU(gpu)
+	return !ok || g.VRAMGB >= m.MinVRAMGB
+}
+
+// AddModel adds or replaces a custom model
+func (c *Catalog) AddModel(ctx context.Context, m CatalogModel) (CatalogModel, error) {
+	m, err := normalizeCatalogModel(m)
+	if err != nil {
+		return CatalogModel{}, err
+	}
+	m.Source = CatalogSourceCustom
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.store != nil {
+		if err := c.store.SaveModel(ctx, m); err != nil {
+			return CatalogModel{}, err
+		}
+	}
+	c.customModels[catalogKey(m.Name)] = m
+	return m, nil
+}
+
+// RemoveModel deletes a custom model. Built-in and file entries it replaced
+// take effect again; they cannot be removed themselves.
+func (c *Catalog) RemoveModel(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := catalogKey(name)
+	if _, ok := c.customModels[key]; !ok {
+		return fmt.Errorf("%w: no custom model %q", ErrCatalogEntryNotFound, name)
+	}
+	if c.store != nil {
+		if err := c.store.delete(ctx, catalogKindModel, key); err != nil {
+			return err
+		}
+	}
+	delete(c.customModels, key)
+	return nil
+}
+
+// AddGPU adds or replaces a custom GPU type
+func (c *Catalog) AddGPU(ctx context.Context, g CatalogGPU) (CatalogGPU, error) {
+	g, err := normalizeCatalogGPU(g)
+	if err != nil {
+		return CatalogGPU{}, err
+	}
+	g.Source = CatalogSourceCustom
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.store != nil {
+		if err := c.store.SaveGPU(ctx, g); err != nil {
+			return CatalogGPU{}, err
+		}
+	}
+	c.customGPUs[catalogKey(g.Name)] = g
+	return g, nil
+}
+
+// RemoveGPU deletes a custom GPU type
+func (c *Catalog) RemoveGPU(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := catalogKey(name)
+	if _, ok := c.customGPUs[key]; !ok {
+		return fmt.Errorf("%w: no custom GPU %q", ErrCatalogEntryNotFound, name)
+	}
+	if c.store != nil {
+		if err := c.store.delete(ctx, catalogKindGPU, key); err != nil {
+			return err
+		}
+	}
+	delete(c.customGPUs, key)
+	return nil
+}
+
+func catalogKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func normalizeCatalogModel(m CatalogModel) (CatalogModel, error) {
+	m.Name = strings.TrimSpace(m.Name)
+	if len(m.Name) > 128 || !modelNamePattern.MatchString(m.Name) {
+		return m, fmt.Errorf("%w: model name %q must be an Ollama tag or Hugging Face ID", ErrInvalidCatalogEntry, m.Name)
+	}
+	spec, err := LookupWorkload(string(m.Workload))
+	if err != nil {
+		return m, fmt.Errorf("%w: %v", ErrInvalidCatalogEntry, err)
+	}
+	m.Workload = spec.Workload
+	if m.MinVRAMGB < 0 || m.MinVRAMGB > maxCatalogVRAMGB {
+		return m, fmt.Errorf("%w: min_vram_gb must be between 0 and %d", ErrInvalidCatalogEntry, maxCatalogVRAMGB)
+	}
+	if len(m.Description) > 512 {
+		return m, fmt.Errorf("%w: description is longer than 512 characters", ErrInvalidCatalogEntry)
+	}
+	return m, nil
+}
+
+func normalizeCatalogGPU(g CatalogGPU) (CatalogGPU, error) {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" || len(g.Name) > 64 {
+		return g, fmt.Errorf("%w: gpu name must be 1 to 64 characters", ErrInvalidCatalogEntry)
+	}
+	if g.VRAMGB < 1 || g.VRAMGB > maxCatalogVRAMGB {
+		return g, fmt.Errorf("%w: vram_gb must be between 1 and %d", ErrInvalidCatalogEntry, maxCatalogVRAMGB)
+	}
+	return g, nil
+}
+
+// Kinds of stored catalog entries
+const (
+	catalogKindModel = "model"
+	catalogKindGPU   = "gpu"
+)
+
+// CatalogStore persists custom catalog entries
+type CatalogStore struct {
+	db *sql.DB
+}
+
+// NewCatalogStore creates a new catalog store
+func NewCatalogStore(db *sql.DB) (*CatalogStore, error) {
+	s := &CatalogStore{db: db}
+	if err := s.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate catalog tables: %w", err)
+	}
+	return s, nil
+}
+
+func (s *CatalogStore) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS benchmark_catalog (
+			kind TEXT NOT NULL,
+			name_key TEXT NOT NULL,
+			entry_json TEXT NOT NULL,
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (kind, name_key)
+		)
+	`)
+	return err
+}
+
+// SaveModel inserts or replaces a custom model
+func (s *CatalogStore) SaveModel(ctx context.Context, m CatalogModel) error {
+	return s.save(ctx, catalogKindModel, m.Name, m)
+}
+
+// SaveGPU inserts or replaces a custom GPU type
+func (s *CatalogStore) SaveGPU(ctx context.Context, g CatalogGPU) error {
+	return s.save(ctx, catalogKindGPU, g.Name, g)
+}
+
+// ListModels returns all custom models
+func (s *CatalogStore) ListModels(ctx context.Context) ([]CatalogModel, error) {
+	var out []CatalogModel
+	err := s.list(ctx, catalogKindModel, func(data []byte) error {
+		var m CatalogModel
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		out = append(out, m)
+		return nil
+	})
+	return out, err
+}
+
+// ListGPUs returns all custom GPU types
+func (s *CatalogStore) ListGPUs(ctx context.Context) ([]CatalogGPU, error) {
+	var out []CatalogGPU
+	err := s.list(ctx, catalogKindGPU, func(data []byte) error {
+		var g CatalogGPU
+		if err := json.Unmarshal(data, &g); err != nil {
+			return err
+		}
+		out = append(out, g)
+		return nil
+	})
+	return out, err
+}
+
+func (s *CatalogStore) save(ctx context.Context, kind, name string, entry interface{}) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog entry: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO benchmark_catalog (kind, name_key, entry_json, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(kind, name_key) DO UPDATE SET entry_json = excluded.entry_json, updated_at = excluded.updated_at
+	`, kind, catalogKey(name), string(data), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save catalog %s: %w", kind, err)
+	}
+	return nil
+}
+
+func (s *CatalogStore) delete(ctx context.Context, kind, key string) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM benchmark_catalog WHERE kind = ? AND name_key = ?", kind, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete catalog %s: %w", kind, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s %q", ErrCatalogEntryNotFound, kind, key)
+	}
+	return nil
+}
+
+func (s *CatalogStore) list(ctx context.Context, kind string, scan func([]byte) error) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT entry_json FROM benchmark_catalog WHERE kind = ? ORDER BY name_key", kind)
+	if err != nil {
+		return fmt.Errorf("failed to list catalog %ss: %w", kind, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := scan([]byte(data)); err != nil {
+			return fmt.Errorf("invalid stored catalog %s: %w", kind, err)
+		}
+	}
+	return rows.Err()
+}
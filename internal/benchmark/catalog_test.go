@@ -0,0 +1,114 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCatalog_Builtin(t *testing.T) {
+	c := NewCatalog()
+
+	m, ok := c.Model("LLAMA3:70B")
+	require.True(t, ok, "lookups ignore case")
+	assert.Equal(t, WorkloadChat, m.Workload, "workload defaults to chat")
+	assert.Equal(t, CatalogSourceBuiltin, m.Source)
+
+	m, ok = c.Model("openai/whisper-large-v3")
+	require.True(t, ok)
+	assert.Equal(t, WorkloadTranscription, m.Workload)
+
+	assert.False(t, c.FitsGPU("llama3:70b", "RTX 4090"))
+	assert.True(t, c.FitsGPU("llama3:70b", "H100 SXM"))
+	assert.True(t, c.FitsGPU("llama3:70b", "Unknown GPU"), "unknown GPUs are never skipped")
+	assert.True(t, c.FitsGPU("acme/custom", "RTX 3090"), "unknown models are never skipped")
+
+	gpus := c.GPUs()
+	require.NotEmpty(t, gpus)
+	assert.LessOrEqual(t, gpus[0].VRAMGB, gpus[len(gpus)-1].VRAMGB)
+}
+
+func TestCatalog_LoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+models:
+  - name: acme/support-llama-8b
+    min_vram_gb: 20
+    description: Fine-tuned support assistant
+  - name: llama3:70b
+    min_vram_gb: 40
+gpus:
+  - name: RTX 6000 Ada
+    vram_gb: 48
+`), 0o644))
+
+	c := NewCatalog()
+	require.NoError(t, c.LoadFile(path))
+
+	m, ok := c.Model("acme/support-llama-8b")
+	require.True(t, ok)
+	assert.Equal(t, CatalogSourceFile, m.Source)
+	assert.Equal(t, WorkloadChat, m.Workload)
+	assert.Equal(t, 20, c.MinVRAM("acme/support-llama-8b"))
+	assert.Equal(t, 40, c.MinVRAM("llama3:70b"), "file entries replace built-in ones")
+	assert.True(t, c.FitsGPU("llama3:70b", "RTX 6000 Ada"))
+
+	// Invalid files are rejected whole
+	bad := filepath.Join(t.TempDir(), "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("models:\n  - name: ok-model\n  - name: x\n    workload: video\n"), 0o644))
+	c = NewCatalog()
+	err := c.LoadFile(bad)
+	assert.ErrorIs(t, err, ErrInvalidCatalogEntry)
+	_, ok = c.Model("ok-model")
+	assert.False(t, ok)
+
+	assert.Error(t, c.LoadFile(filepath.Join(t.TempDir(), "missing.yaml")))
+}
+
+func TestCatalog_CustomEntriesPersist(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewCatalogStore(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c := NewCatalog()
+	require.NoError(t, c.LoadStore(ctx, store))
+
+	m, err := c.AddModel(ctx, CatalogModel{Name: "llama3:70b", MinVRAMGB: 80})
+	require.NoError(t, err)
+	assert.Equal(t, CatalogSourceCustom, m.Source)
+	_, err = c.AddGPU(ctx, CatalogGPU{Name: "MI300X", VRAMGB: 192})
+	require.NoError(t, err)
+
+	_, err = c.AddModel(ctx, CatalogModel{Name: "bad name"})
+	assert.ErrorIs(t, err, ErrInvalidCatalogEntry)
+	_, err = c.AddGPU(ctx, CatalogGPU{Name: "RTX 4090"})
+	assert.ErrorIs(t, err, ErrInvalidCatalogEntry)
+
+	// A restarted server sees the custom entries
+	reloaded := NewCatalog()
+	require.NoError(t, reloaded.LoadStore(ctx, store))
+	assert.Equal(t, 80, reloaded.MinVRAM("llama3:70b"))
+	assert.False(t, reloaded.FitsGPU("llama3:70b", "L40S"))
+	g, ok := reloaded.GPU("mi300x")
+	require.True(t, ok)
+	assert.Equal(t, CatalogSourceCustom, g.Source)
+
+	// Removing a custom model restores the built-in entry
+	require.NoError(t, reloaded.RemoveModel(ctx, "llama3:70b"))
+	assert.Equal(t, 48, reloaded.MinVRAM("llama3:70b"))
+	assert.ErrorIs(t, reloaded.RemoveModel(ctx, "llama3:70b"), ErrCatalogEntryNotFound, "built-in entries cannot be removed")
+	require.NoError(t, reloaded.RemoveGPU(ctx, "MI300X"))
+
+	stored, err := store.ListModels(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
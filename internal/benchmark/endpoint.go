@@ -0,0 +1,420 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for endpoint benchmarks
+const (
+	DefaultEndpointRequests    = 20
+	DefaultEndpointConcurrency = 2
+	DefaultEndpointMaxTokens   = 256
+	defaultEndpointTimeout     = 3 * time.Minute
+)
+
+// endpointPrompts are cycled through by endpoint benchmarks. They mix short
+// answers, explanation and code so throughput is not measured on one shape
+// of output only.
+var endpointPrompts = []struct {
+	kind   string
+	prompt string
+}{
+	{"short", "In one sentence, what is a GPU?"},
+	{"explain", "Explain how attention works in a transformer model, step by step."},
+	{"code", "Write a Python function that returns the n-th Fibonacci number iteratively, with a docstring."},
+	{"reasoning", "A train leaves at 3pm travelling 80 km/h; a second leaves the same station at 4pm at 100 km/h. When does the second catch up? Show your work."},
+}
+
+// EndpointConfig configures a benchmark against an already-running
+// OpenAI-compatible server such as vLLM
+type EndpointConfig struct {
+	BaseURL     string // e.g. http://host:8000; a trailing /v1 is accepted
+	Model       string // Model to request; empty uses the first model the server lists
+	Requests    int
+	Concurrency int
+	MaxTokens   int
+	Client      *http.Client
+	Workload    Workload // Empty means chat
+
+	// ConcurrencyLevels, if set, sweeps the endpoint at each level in
+	// ascending order with Requests requests per level; Concurrency is ignored
+	ConcurrencyLevels []int
+}
+
+// EndpointRun is the outcome of an endpoint benchmark
+type EndpointRun struct {
+	Model             string
+	Workload          Workload
+	TestConfig        TestConfig
+	Results           PerformanceResults
+	ConcurrencyLevels []ConcurrencyLevel // Only for sweeps
+}
+
+// requestTiming holds the streaming latencies of one request in
+// milliseconds, zero when not measured, and the work it completed in the
+// workload's unit
+type requestTiming struct {
+	ttftMs float64
+	itlMs  float64
+	units  float64
+}
+
+// BenchmarkEndpoint sends cfg.Requests streaming chat completions to the
+// endpoint, cfg.Concurrency at a time, and measures throughput, latency and
+// time to first token. Failed requests count toward the error rate; an error
+// is returned only if the endpoint cannot be used at all. Other workloads
+// send their own request and report Throughput in the workload's unit.
+//
+// For a sweep, each level is recorded in ConcurrencyLevels and Results come
+// from the highest level at which any request succeeded. The sweep stops at
+// the first level where every request fails.
+func BenchmarkEndpoint(ctx context.Context, cfg EndpointConfig) (*EndpointRun, error) {
+	if cfg.Requests <= 0 {
+		cfg.Requests = DefaultEndpointRequests
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultEndpointConcurrency
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = DefaultEndpointMaxTokens
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultEndpointTimeout}
+	}
+	spec, err := LookupWorkload(string(cfg.Workload))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Workload = spec.Workload
+	base := endpointBase(cfg.BaseURL)
+	if base == "" {
+		return nil, fmt.Errorf("endpoint URL is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		served, err := EndpointModel(ctx, cfg.Client, base)
+		if err != nil {
+			return nil, err
+		}
+		model = served
+	}
+
+	testConfig := TestConfig{PromptTypes: []string{string(cfg.Workload)}}
+	if cfg.Workload == WorkloadChat {
+		testConfig = TestConfig{MaxTokens: cfg.MaxTokens}
+		for _, p := range endpointPrompts {
+			testConfig.PromptTypes = append(testConfig.PromptTypes, p.kind)
+		}
+	}
+
+	levels := sweepLevels(cfg.ConcurrencyLevels)
+	sweep := len(levels) > 0
+	if !sweep {
+		levels = []int{cfg.Concurrency}
+	}
+
+	run := &EndpointRun{Model: model, Workload: cfg.Workload, TestConfig: testConfig}
+	var lastErr error
+	for _, concurrency := range levels {
+		results, timings, elapsed := runEndpointLevel(ctx, cfg, base, model, concurrency)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rate := workRate(timings, elapsed)
+		if cfg.Workload == WorkloadChat {
+			rate = 0 // Chat throughput is the token rate
+		}
+		if sweep {
+			level := summarizeLevel(concurrency, results, timings, elapsed)
+			level.Throughput = rate
+			run.ConcurrencyLevels = append(run.ConcurrencyLevels, level)
+		}
+
+		perf := AnalyzeResults(results)
+		perf.DurationSeconds = elapsed
+		if elapsed > 0 {
+			perf.RequestsPerMinute = float64(len(results)) * 60 / elapsed
+		}
+		if perf.TotalErrors == perf.TotalRequests {
+			lastErr = fmt.Errorf("all %d requests to %s failed at concurrency %d: %s",
+				perf.TotalRequests, base, concurrency, results[0].ErrorMsg)
+			break
+		}
+		setTTFT(&perf, timings)
+		if rate > 0 {
+			perf.Throughput, perf.ThroughputUnit = rate, spec.RateUnit()
+		}
+		run.Results = perf
+		run.TestConfig.ConcurrentReqs = concurrency
+	}
+	if run.TestConfig.ConcurrentReqs == 0 {
+		return nil, lastErr
+	}
+	return run, nil
+}
+
+// sweepLevels sorts and de-duplicates positive concurrency levels
+func sweepLevels(levels []int) []int {
+	var out []int
+	for _, l := range levels {
+		if l > 0 {
+			out = append(out, l)
+		}
+	}
+	sort.Ints(out)
+	for i := len(out) - 1; i > 0; i-- {
+		if out[i] == out[i-1] {
+			out = append(out[:i], out[i+1:]...)
+		}
+	}
+	return out
+}
+
+// runEndpointLevel sends cfg.Requests requests, concurrency at a time, and
+// returns their results, timings and the wall-clock seconds taken
+func runEndpointLevel(ctx context.Context, cfg EndpointConfig, base, model string, concurrency int) ([]RequestResult, []requestTiming, float64) {
+	results := make([]RequestResult, cfg.Requests)
+	timings := make([]requestTiming, cfg.Requests)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], timings[i] = cfg.sendRequest(ctx, base, model, i)
+				results[i].RequestNum = i + 1
+			}
+		}()
+	}
+	for i := 0; i < cfg.Requests; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, timings, time.Since(start).Seconds()
+}
+
+// summarizeLevel computes the per-level breakdown of a concurrency sweep.
+// Throughput is total tokens over wall-clock time, so it reflects what the
+// server delivers to all clients together.
+func summarizeLevel(concurrency int, results []RequestResult, timings []requestTiming, elapsed float64) ConcurrencyLevel {
+	level := ConcurrencyLevel{
+		Concurrency:     concurrency,
+		Requests:        len(results),
+		DurationSeconds: elapsed,
+	}
+	var tokens int
+	var latencies, ttfts, itls []float64
+	for i, r := range results {
+		if r.Error {
+			level.Errors++
+			continue
+		}
+		tokens += r.Tokens
+		latencies = append(latencies, r.DurationSec*1000)
+		if timings[i].ttftMs > 0 {
+			ttfts = append(ttfts, timings[i].ttftMs)
+		}
+		if timings[i].itlMs > 0 {
+			itls = append(itls, timings[i].itlMs)
+		}
+	}
+	if level.Requests > 0 {
+		level.ErrorRate = float64(level.Errors) / float64(level.Requests)
+	}
+	if elapsed > 0 {
+		level.TokensPerSecond = float64(tokens) / elapsed
+		level.RequestsPerMinute = float64(level.Requests) * 60 / elapsed
+	}
+	level.P50LatencyMs, level.P90LatencyMs, level.P99LatencyMs = percentiles(latencies)
+	level.P50TTFTMs, level.P90TTFTMs, level.P99TTFTMs = percentiles(ttfts)
+	level.P50ITLMs, level.P90ITLMs, level.P99ITLMs = percentiles(itls)
+	return level
+}
+
+// percentiles returns the p50, p90 and p99 of values, sorting them in place
+func percentiles(values []float64) (p50, p90, p99 float64) {
+	sort.Float64s(values)
+	return percentile(values, 50), percentile(values, 90), percentile(values, 99)
+}
+
+// EndpointModel returns the first model an OpenAI-compatible server lists
+// at GET /v1/models
+func EndpointModel(ctx context.Context, client *http.Client, baseURL string) (string, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	base := endpointBase(baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/v1/models", nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("listing models failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", fmt.Errorf("failed to parse model list: %w", err)
+	}
+	if len(list.Data) == 0 || list.Data[0].ID == "" {
+		return "", fmt.Errorf("endpoint serves no models")
+	}
+	return list.Data[0].ID, nil
+}
+
+// endpointBase strips a trailing slash and /v1 so paths can be appended
+func endpointBase(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/v1")
+}
+
+// streamCompletion sends one streaming chat completion and returns its
+// result with its time to first token and inter-token latency
+func streamCompletion(ctx context.Context, client *http.Client, base, model, prompt string, maxTokens int) (RequestResult, requestTiming) {
+	start := time.Now()
+	result := RequestResult{Timestamp: start.Unix()}
+	fail := func(msg string) (RequestResult, requestTiming) {
+		result.Error = true
+		result.ErrorMsg = msg
+		result.DurationSec = time.Since(start).Seconds()
+		return result, requestTiming{}
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"model":          model,
+		"messages":       []map[string]string{{"role": "user", "content": prompt}},
+		"max_tokens":     maxTokens,
+		"stream":         true,
+		"stream_options": map[string]bool{"include_usage": true},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return fail(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fail(err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fail(fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg))))
+	}
+
+	var firstChunk, lastChunk time.Time
+	var chunks, usageTokens int
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *struct {
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		for _, c := range chunk.Choices {
+			if c.Delta.Content == "" {
+				continue
+			}
+			lastChunk = time.Now()
+			if chunks == 0 {
+				firstChunk = lastChunk
+			}
+			chunks++
+		}
+		if chunk.Usage != nil && chunk.Usage.CompletionTokens > 0 {
+			usageTokens = chunk.Usage.CompletionTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fail(err.Error())
+	}
+
+	result.DurationSec = time.Since(start).Seconds()
+	// Servers that do not report usage stream roughly one token per chunk
+	result.Tokens = usageTokens
+	if result.Tokens == 0 {
+		result.Tokens = chunks
+	}
+	if result.Tokens == 0 {
+		return fail("no tokens generated")
+	}
+	if result.DurationSec > 0 {
+		result.TokensPerSec = float64(result.Tokens) / result.DurationSec
+	}
+
+	var timing requestTiming
+	if chunks > 0 {
+		timing.ttftMs = milliseconds(firstChunk.Sub(start))
+	}
+	if result.Tokens > 1 && lastChunk.After(firstChunk) {
+		timing.itlMs = milliseconds(lastChunk.Sub(firstChunk)) / float64(result.Tokens-1)
+	}
+	return result, timing
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// setTTFT fills the time-to-first-token figures from per-request timings
+func setTTFT(perf *PerformanceResults, timings []requestTiming) {
+	var values []float64
+	var sum float64
+	for _, t := range timings {
+		if v := t.ttftMs; v > 0 {
+			values = append(values, v)
+			sum += v
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	sort.Float64s(values)
+	perf.AvgTTFTMs = sum / float64(len(values))
+	perf.P50TTFTMs = percentile(values, 50)
+	perf.P95TTFTMs = percentile(values, 95)
+}
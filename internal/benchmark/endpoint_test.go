@@ -0,0 +1,149 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVLLM serves /v1/models and streams three content chunks per chat
+// completion; every failEvery-th request fails when failEvery > 0
+func fakeVLLM(t *testing.T, withUsage bool, failEvery int32) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"meta-llama/Llama-3.1-8B-Instruct"}]}`)
+		case "/v1/chat/completions":
+			n := atomic.AddInt32(&calls, 1)
+			var req struct {
+				Model  string `json:"model"`
+				Stream bool   `json:"stream"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "meta-llama/Llama-3.1-8B-Instruct", req.Model)
+			assert.True(t, req.Stream)
+			if failEvery > 0 && n%failEvery == 0 {
+				http.Error(w, "overloaded", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+			for _, word := range []string{"Hello", " there", "!"} {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", word)
+			}
+			if withUsage {
+				fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":12,\"completion_tokens\":5}}\n\n")
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestBenchmarkEndpoint(t *testing.T) {
+	srv, calls := fakeVLLM(t, true, 4)
+
+	run, err := BenchmarkEndpoint(context.Background(), EndpointConfig{
+		BaseURL:     srv.URL + "/v1",
+		Requests:    8,
+		Concurrency: 3,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "meta-llama/Llama-3.1-8B-Instruct", run.Model)
+	assert.Equal(t, int32(8), atomic.LoadInt32(calls))
+	assert.Equal(t, 3, run.TestConfig.ConcurrentReqs)
+	assert.Equal(t, DefaultEndpointMaxTokens, run.TestConfig.MaxTokens)
+
+	r := run.Results
+	assert.Equal(t, 8, r.TotalRequests)
+	assert.Equal(t, 2, r.TotalErrors)
+	assert.InDelta(t, 0.25, r.ErrorRate, 0.001)
+	assert.Equal(t, 30, r.TotalTokens, "usage is preferred over chunk counts")
+	assert.Greater(t, r.AvgTokensPerSecond, 0.0)
+	assert.Greater(t, r.AvgTTFTMs, 0.0)
+	assert.Greater(t, r.DurationSeconds, 0.0)
+}
+
+func TestBenchmarkEndpoint_CountsChunksWithoutUsage(t *testing.T) {
+	srv, _ := fakeVLLM(t, false, 0)
+
+	run, err := BenchmarkEndpoint(context.Background(), EndpointConfig{
+		BaseURL:  srv.URL,
+		Model:    "meta-llama/Llama-3.1-8B-Instruct",
+		Requests: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 6, run.Results.TotalTokens)
+	assert.Zero(t, run.Results.TotalErrors)
+}
+
+func TestBenchmarkEndpoint_ConcurrencySweep(t *testing.T) {
+	srv, calls := fakeVLLM(t, true, 0)
+
+	run, err := BenchmarkEndpoint(context.Background(), EndpointConfig{
+		BaseURL:           srv.URL,
+		Requests:          4,
+		ConcurrencyLevels: []int{4, 1, 2, 2},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(12), atomic.LoadInt32(calls), "levels are de-duplicated")
+	require.Len(t, run.ConcurrencyLevels, 3)
+	for i, want := range []int{1, 2, 4} {
+		level := run.ConcurrencyLevels[i]
+		assert.Equal(t, want, level.Concurrency)
+		assert.Equal(t, 4, level.Requests)
+		assert.Zero(t, level.Errors)
+		assert.Greater(t, level.TokensPerSecond, 0.0)
+		assert.Greater(t, level.P99TTFTMs, 0.0)
+	}
+	assert.Equal(t, 4, run.TestConfig.ConcurrentReqs, "headline results come from the highest level")
+	assert.Equal(t, 4, run.Results.TotalRequests)
+}
+
+func TestSummarizeLevel(t *testing.T) {
+	var results []RequestResult
+	var timings []requestTiming
+	for i := 1; i <= 10; i++ {
+		results = append(results, RequestResult{Tokens: 100, DurationSec: float64(i)})
+		timings = append(timings, requestTiming{ttftMs: float64(i * 10), itlMs: float64(i)})
+	}
+	results = append(results, RequestResult{Error: true, ErrorMsg: "timeout"})
+	timings = append(timings, requestTiming{})
+
+	level := summarizeLevel(8, results, timings, 20)
+	assert.Equal(t, 8, level.Concurrency)
+	assert.Equal(t, 11, level.Requests)
+	assert.Equal(t, 1, level.Errors)
+	assert.InDelta(t, 1.0/11, level.ErrorRate, 0.0001)
+	assert.InDelta(t, 50, level.TokensPerSecond, 0.0001, "1000 tokens over 20s")
+	assert.InDelta(t, 33, level.RequestsPerMinute, 0.0001)
+	assert.Equal(t, 50.0, level.P50TTFTMs)
+	assert.Equal(t, 90.0, level.P90TTFTMs)
+	assert.Equal(t, 90.0, level.P99TTFTMs)
+	assert.Equal(t, 5.0, level.P50ITLMs)
+	assert.Equal(t, 9.0, level.P90ITLMs)
+	assert.Equal(t, 5000.0, level.P50LatencyMs)
+}
+
+func TestBenchmarkEndpoint_Unusable(t *testing.T) {
+	srv, _ := fakeVLLM(t, true, 1)
+	_, err := BenchmarkEndpoint(context.Background(), EndpointConfig{BaseURL: srv.URL, Requests: 2})
+	assert.ErrorContains(t, err, "all 2 requests")
+
+	_, err = EndpointModel(context.Background(), nil, "http://127.0.0.1:1")
+	assert.ErrorContains(t, err, "unreachable")
+}
@@ -0,0 +1,167 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// TrendThreshold is the relative throughput change below which a run is
+// considered flat compared to the previous one
+const TrendThreshold = 0.05
+
+// Trend describes how a run's throughput compares to the previous run of
+// the same model on the same GPU
+type Trend string
+
+const (
+	TrendUp   Trend = "up"
+	TrendDown Trend = "down"
+	TrendFlat Trend = "flat"
+)
+
+// HistoryFilter selects benchmark runs for a history listing. Model and GPU
+// match substrings; zero times leave the range open.
+type HistoryFilter struct {
+	Model    string
+	GPU      string
+	Provider string
+	Since    time.Time
+	Until    time.Time
+	Limit    int // 0 = no limit
+}
+
+// HistoryEntry summarizes one benchmark run
+type HistoryEntry struct {
+	ID                   string    `json:"id"`
+	Timestamp            time.Time `json:"timestamp"`
+	Model                string    `json:"model"`
+	Quantization         string    `json:"quantization,omitempty"`
+	Runtime              string    `json:"runtime,omitempty"` // Inference server, e.g. "ollama", "vllm", "sglang"
+	GPUName              string    `json:"gpu_name"`
+	GPUCount             int       `json:"gpu_count"`
+	Provider             string    `json:"provider"`
+	Location             string    `json:"location,omitempty"`
+	AvgTokensPerSecond   float64   `json:"avg_tokens_per_second"`
+	AvgLatencyMs         float64   `json:"avg_latency_ms"`
+	P95LatencyMs         float64   `json:"p95_latency_ms"`
+	ErrorRate            float64   `json:"error_rate"`
+	PricePerHour         float64   `json:"price_per_hour"`
+	RunCost              float64   `json:"run_cost"` // Price of the benchmark's own duration
+	CostPerMillionTokens float64   `json:"cost_per_million_tokens,omitempty"`
+	AvgPowerW            float64   `json:"avg_power_w,omitempty"` // All GPUs together
+	TokensPerWatt        float64   `json:"tokens_per_watt,omitempty"`
+
+	// Headline throughput in the workload's unit: AvgTokensPerSecond in
+	// "tok/s" for chat
+	Workload       Workload `json:"workload"`
+	Throughput     float64  `json:"throughput"`
+	ThroughputUnit string   `json:"throughput_unit"`
+
+	// Per-level breakdown when the run was a concurrency sweep
+	ConcurrencyLevels []ConcurrencyLevel `json:"concurrency_levels,omitempty"`
+
+	// Trend against the previous run of the same model and quantization on
+	// the same GPU; empty for the first run
+	Trend     Trend   `json:"trend,omitempty"`
+	ChangePct float64 `json:"change_pct,omitempty"`
+}
+
+// ListHistory returns benchmark runs matching filter, newest first, each
+// with its throughput trend. Trends compare against earlier runs even when
+// those fall outside the date range.
+func (s *Store) ListHistory(ctx context.Context, filter HistoryFilter) ([]HistoryEntry, error) {
+	query := `SELECT full_result_json FROM benchmarks WHERE 1=1`
+	var args []interface{}
+	if filter.Model != "" {
+		query += " AND model_name LIKE ?"
+		args = append(args, "%"+filter.Model+"%")
+	}
+	if filter.GPU != "" {
+		query += " AND gpu_name LIKE ?"
+		args = append(args, "%"+filter.GPU+"%")
+	}
+	if filter.Provider != "" {
+		query += " AND provider = ?"
+		args = append(args, filter.Provider)
+	}
+	query += " ORDER BY timestamp ASC"
+
+	results, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := BuildHistory(results)
+
+	// Newest first, within the date range
+	history := make([]HistoryEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if !filter.Since.IsZero() && e.Timestamp.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !e.Timestamp.Before(filter.Until) {
+			continue
+		}
+		history = append(history, e)
+		if filter.Limit > 0 && len(history) == filter.Limit {
+			break
+		}
+	}
+	return history, nil
+}
+
+// BuildHistory summarizes results, which must be oldest first, and sets
+// each run's trend against the previous run of the same model, runtime,
+// quantization and GPU
+func BuildHistory(results []*BenchmarkResult) []HistoryEntry {
+	entries := make([]HistoryEntry, 0, len(results))
+	previous := make(map[string]float64)
+	for _, r := range results {
+		e := HistoryEntry{
+			ID:                 r.ID,
+			Timestamp:          r.Timestamp,
+			Model:              r.Model.Name,
+			Quantization:       r.Model.Quantization,
+			Runtime:            r.Model.Runtime,
+			GPUName:            r.Hardware.GPUName,
+			GPUCount:           r.Hardware.GPUCount,
+			Provider:           r.Provider,
+			Location:           r.Location,
+			AvgTokensPerSecond: r.Results.AvgTokensPerSecond,
+			AvgLatencyMs:       r.Results.AvgLatencyMs,
+			P95LatencyMs:       r.Results.P95LatencyMs,
+			ErrorRate:          r.Results.ErrorRate,
+			PricePerHour:       r.PricePerHour,
+			RunCost:            r.PricePerHour * r.Results.DurationSeconds / 3600,
+			AvgPowerW:          r.GPUStats.AvgTotalPowerW,
+			TokensPerWatt:      r.Results.TokensPerWatt,
+			ConcurrencyLevels:  r.ConcurrencyLevels,
+			Workload:           WorkloadOf(r),
+			Throughput:         r.Results.Throughput,
+			ThroughputUnit:     r.Results.ThroughputUnit,
+		}
+		if e.Workload == WorkloadChat {
+			e.Throughput, e.ThroughputUnit = e.AvgTokensPerSecond, "tok/s"
+		}
+		e.CostPerMillionTokens = CalculateCostAnalysis(r).CostPerMillionTokens
+
+		key := r.Model.Name + "|" + r.Model.Runtime + "|" + r.Model.Quantization + "|" + r.Hardware.GPUName
+		if prev, ok := previous[key]; ok && prev > 0 {
+			e.ChangePct = (e.Throughput - prev) / prev * 100
+			switch {
+			case e.ChangePct >= TrendThreshold*100:
+				e.Trend = TrendUp
+			case e.ChangePct <= -TrendThreshold*100:
+				e.Trend = TrendDown
+			default:
+				e.Trend = TrendFlat
+			}
+		}
+		if e.Throughput > 0 {
+			previous[key] = e.Throughput
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
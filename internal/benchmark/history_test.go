@@ -0,0 +1,106 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func historyResult(id, model, gpu, provider string, ts time.Time, tps float64) *BenchmarkResult {
+	r := &BenchmarkResult{
+		ID:           id,
+		Timestamp:    ts,
+		Provider:     provider,
+		PricePerHour: 0.36,
+	}
+	r.Model.Name = model
+	r.Hardware.GPUName = gpu
+	r.Hardware.GPUCount = 1
+	r.Results.AvgTokensPerSecond = tps
+	r.Results.AvgLatencyMs = 250
+	r.Results.DurationSeconds = 600
+	return r
+}
+
+func TestStore_ListHistory(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, r := range []*BenchmarkResult{
+		historyResult("r1", "llama3.1:8b", "RTX 4090", "vastai", base, 100),
+		historyResult("r2", "llama3.1:8b", "RTX 4090", "vastai", base.AddDate(0, 0, 1), 102),
+		historyResult("r3", "llama3.1:8b", "RTX 4090", "vastai", base.AddDate(0, 0, 2), 90),
+		historyResult("r4", "llama3.1:8b", "RTX 3090", "tensordock", base.AddDate(0, 0, 3), 70),
+		historyResult("r5", "qwen2.5:7b", "RTX 4090", "vastai", base.AddDate(0, 0, 4), 110),
+	} {
+		require.NoError(t, store.Save(ctx, r))
+	}
+
+	history, err := store.ListHistory(ctx, HistoryFilter{Model: "llama", GPU: "4090"})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	// Newest first, each compared with the run before it
+	assert.Equal(t, "r3", history[0].ID)
+	assert.Equal(t, TrendDown, history[0].Trend)
+	assert.InDelta(t, -11.76, history[0].ChangePct, 0.01)
+	assert.Equal(t, TrendFlat, history[1].Trend)
+	assert.Empty(t, history[2].Trend, "first run has nothing to compare against")
+	assert.InDelta(t, 0.06, history[0].RunCost, 0.0001)
+
+	// The date range keeps the trend against runs outside it
+	history, err = store.ListHistory(ctx, HistoryFilter{
+		Model: "llama",
+		Since: base.AddDate(0, 0, 2),
+		Until: base.AddDate(0, 0, 3),
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "r3", history[0].ID)
+	assert.Equal(t, TrendDown, history[0].Trend)
+
+	history, err = store.ListHistory(ctx, HistoryFilter{Provider: "tensordock"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "r4", history[0].ID)
+
+	history, err = store.ListHistory(ctx, HistoryFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "r5", history[0].ID)
+}
+
+func TestStore_GetModelRecommendations_ByQuantization(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, quant := range []string{"fp16", "awq", "awq"} {
+		r := historyResult(fmt.Sprintf("r%d", i), "mistral-7b", "RTX 4090", "vastai", base.AddDate(0, 0, i), 50+float64(i)*20)
+		r.Model.Quantization = quant
+		r.Results.TotalRequests = 10
+		require.NoError(t, store.Save(ctx, r))
+	}
+
+	recs, err := store.GetModelRecommendations(ctx, "mistral-7b")
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	assert.Equal(t, "awq", recs[0].Quantization)
+	assert.InDelta(t, 80, recs[0].ExpectedTPS, 0.001)
+	assert.Equal(t, "Based on 2 awq benchmark(s)", recs[0].Notes)
+	assert.Equal(t, "fp16", recs[1].Quantization)
+}
@@ -32,6 +32,13 @@ type ManifestEntry struct {
 	Status   ManifestStatus `json:"status"`
 	Priority int            `json:"priority"` // P0=highest, P2=lowest
 
+	// Inference server the model is deployed with (e.g. "vllm", "sglang");
+	// empty for Ollama entries
+	Backend string `json:"backend,omitempty"`
+
+	// Server quantization (e.g. "awq", "fp16"); empty for Ollama entries
+	Quantization string `json:"quantization,omitempty"`
+
 	// Worker tracking
 	WorkerID   string `json:"worker_id,omitempty"`
 	OutputFile string `json:"output_file,omitempty"`
@@ -78,6 +85,8 @@ func (s *ManifestStore) migrate() error {
 			gpu_type TEXT NOT NULL,
 			provider TEXT NOT NULL,
 			model TEXT NOT NULL,
+			quantization TEXT,
+			backend TEXT,
 			status TEXT NOT NULL DEFAULT 'pending',
 			priority INTEGER NOT NULL DEFAULT 1,
 
@@ -120,6 +129,8 @@ func (s *ManifestStore) migrate() error {
 		"ALTER TABLE benchmark_manifest ADD COLUMN offer_id TEXT",
 		"ALTER TABLE benchmark_manifest ADD COLUMN price_per_hour REAL",
 		"ALTER TABLE benchmark_manifest ADD COLUMN total_cost REAL",
+		"ALTER TABLE benchmark_manifest ADD COLUMN quantization TEXT",
+		"ALTER TABLE benchmark_manifest ADD COLUMN backend TEXT",
 	}
 	for _, stmt := range alters {
 		_, _ = s.db.Exec(stmt) // Ignore "duplicate column" errors
@@ -144,13 +155,13 @@ func (s *ManifestStore) Create(ctx context.Context, entry *ManifestEntry) error
 
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO benchmark_manifest (
-			id, run_id, gpu_type, provider, model, status, priority,
+			id, run_id, gpu_type, provider, model, quantization, backend, status, priority,
 			worker_id, output_file, session_id, offer_id, price_per_hour,
 			benchmark_id, tokens_per_second, total_cost,
 			failure_reason, failure_stage, created_at, started_at, completed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
-		entry.ID, entry.RunID, entry.GPUType, entry.Provider, entry.Model,
+		entry.ID, entry.RunID, entry.GPUType, entry.Provider, entry.Model, entry.Quantization, entry.Backend,
 		entry.Status, entry.Priority, entry.WorkerID, entry.OutputFile,
 		entry.SessionID, entry.OfferID, entry.PriceHour,
 		entry.BenchmarkID, entry.TokensPerSecond, entry.TotalCost,
@@ -183,7 +194,7 @@ func (s *ManifestStore) Update(ctx context.Context, entry *ManifestEntry) error
 // Get retrieves a manifest entry by ID
 func (s *ManifestStore) Get(ctx context.Context, id string) (*ManifestEntry, error) {
 	row := s.db.QueryRowContext(ctx, `
-		SELECT id, run_id, gpu_type, provider, model, status, priority,
+		SELECT id, run_id, gpu_type, provider, model, quantization, backend, status, priority,
 			worker_id, output_file, session_id, offer_id, price_per_hour,
 			benchmark_id, tokens_per_second, total_cost,
 			failure_reason, failure_stage, created_at, started_at, completed_at
@@ -195,7 +206,7 @@ func (s *ManifestStore) Get(ctx context.Context, id string) (*ManifestEntry, err
 // ListByRun returns all entries for a specific run
 func (s *ManifestStore) ListByRun(ctx context.Context, runID string) ([]*ManifestEntry, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, run_id, gpu_type, provider, model, status, priority,
+		SELECT id, run_id, gpu_type, provider, model, quantization, backend, status, priority,
 			worker_id, output_file, session_id, offer_id, price_per_hour,
 			benchmark_id, tokens_per_second, total_cost,
 			failure_reason, failure_stage, created_at, started_at, completed_at
@@ -213,7 +224,7 @@ func (s *ManifestStore) ListByRun(ctx context.Context, runID string) ([]*Manifes
 // GetPendingByPriority returns pending entries ordered by priority
 func (s *ManifestStore) GetPendingByPriority(ctx context.Context, runID string, limit int) ([]*ManifestEntry, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, run_id, gpu_type, provider, model, status, priority,
+		SELECT id, run_id, gpu_type, provider, model, quantization, backend, status, priority,
 			worker_id, output_file, session_id, offer_id, price_per_hour,
 			benchmark_id, tokens_per_second, total_cost,
 			failure_reason, failure_stage, created_at, started_at, completed_at
@@ -232,7 +243,7 @@ func (s *ManifestStore) GetPendingByPriority(ctx context.Context, runID string,
 // GetRunning returns all running entries for a run
 func (s *ManifestStore) GetRunning(ctx context.Context, runID string) ([]*ManifestEntry, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, run_id, gpu_type, provider, model, status, priority,
+		SELECT id, run_id, gpu_type, provider, model, quantization, backend, status, priority,
 			worker_id, output_file, session_id, offer_id, price_per_hour,
 			benchmark_id, tokens_per_second, total_cost,
 			failure_reason, failure_stage, created_at, started_at, completed_at
@@ -331,6 +342,25 @@ func (s *ManifestStore) MarkFailed(ctx context.Context, id, reason, stage string
 	return err
 }
 
+// SkipPending marks every entry of a run that has not started as skipped,
+// e.g. when the run's budget is exhausted. Returns the number skipped.
+func (s *ManifestStore) SkipPending(ctx context.Context, runID, reason string) (int, error) {
+	now := time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE benchmark_manifest SET
+			status = 'skipped', failure_reason = ?, completed_at = ?
+		WHERE run_id = ? AND status = 'pending'
+	`, reason, now, runID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
 // MarkTimeout marks an entry as timed out
 func (s *ManifestStore) MarkTimeout(ctx context.Context, id, stage string) error {
 	now := time.Now()
@@ -344,13 +374,13 @@ func (s *ManifestStore) MarkTimeout(ctx context.Context, id, stage string) error
 
 func (s *ManifestStore) scanEntry(row *sql.Row) (*ManifestEntry, error) {
 	var e ManifestEntry
-	var workerID, outputFile, sessionID, offerID sql.NullString
+	var quantization, backend, workerID, outputFile, sessionID, offerID sql.NullString
 	var priceHour, tps, cost sql.NullFloat64
 	var benchmarkID, failureReason, failureStage sql.NullString
 	var startedAt, completedAt sql.NullTime
 
 	err := row.Scan(
-		&e.ID, &e.RunID, &e.GPUType, &e.Provider, &e.Model, &e.Status, &e.Priority,
+		&e.ID, &e.RunID, &e.GPUType, &e.Provider, &e.Model, &quantization, &backend, &e.Status, &e.Priority,
 		&workerID, &outputFile, &sessionID, &offerID, &priceHour,
 		&benchmarkID, &tps, &cost,
 		&failureReason, &failureStage, &e.CreatedAt, &startedAt, &completedAt,
@@ -362,6 +392,8 @@ func (s *ManifestStore) scanEntry(row *sql.Row) (*ManifestEntry, error) {
 		return nil, err
 	}
 
+	e.Quantization = quantization.String
+	e.Backend = backend.String
 	e.WorkerID = workerID.String
 	e.OutputFile = outputFile.String
 	e.SessionID = sessionID.String
@@ -386,13 +418,13 @@ func (s *ManifestStore) scanEntries(rows *sql.Rows) ([]*ManifestEntry, error) {
 	var entries []*ManifestEntry
 	for rows.Next() {
 		var e ManifestEntry
-		var workerID, outputFile, sessionID, offerID sql.NullString
+		var quantization, backend, workerID, outputFile, sessionID, offerID sql.NullString
 		var priceHour, tps, cost sql.NullFloat64
 		var benchmarkID, failureReason, failureStage sql.NullString
 		var startedAt, completedAt sql.NullTime
 
 		err := rows.Scan(
-			&e.ID, &e.RunID, &e.GPUType, &e.Provider, &e.Model, &e.Status, &e.Priority,
+			&e.ID, &e.RunID, &e.GPUType, &e.Provider, &e.Model, &quantization, &backend, &e.Status, &e.Priority,
 			&workerID, &outputFile, &sessionID, &offerID, &priceHour,
 			&benchmarkID, &tps, &cost,
 			&failureReason, &failureStage, &e.CreatedAt, &startedAt, &completedAt,
@@ -401,6 +433,8 @@ func (s *ManifestStore) scanEntries(rows *sql.Rows) ([]*ManifestEntry, error) {
 			return nil, err
 		}
 
+		e.Quantization = quantization.String
+		e.Backend = backend.String
 		e.WorkerID = workerID.String
 		e.OutputFile = outputFile.String
 		e.SessionID = sessionID.String
@@ -48,3 +48,53 @@ func TestMarkRunning_OnlyClaimsPendingEntries(t *testing.T) {
 	err = store.MarkRunning(ctx, entry.ID, "worker-3", "")
 	assert.Error(t, err, "should not be able to claim a completed entry")
 }
+
+func TestSkipPending(t *testing.T) {
+	store := setupTestManifest(t)
+	ctx := context.Background()
+
+	var ids []string
+	for _, model := range []string{"a", "b", "c"} {
+		entry := &ManifestEntry{RunID: "run-test", GPUType: "RTX 4090", Provider: "vastai", Model: model}
+		require.NoError(t, store.Create(ctx, entry))
+		ids = append(ids, entry.ID)
+	}
+	other := &ManifestEntry{RunID: "run-other", GPUType: "RTX 4090", Provider: "vastai", Model: "a"}
+	require.NoError(t, store.Create(ctx, other))
+	require.NoError(t, store.MarkRunning(ctx, ids[0], "worker-1", ""))
+
+	skipped, err := store.SkipPending(ctx, "run-test", "budget exhausted")
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped)
+
+	summary, err := store.GetSummary(ctx, "run-test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary[ManifestStatusRunning])
+	assert.Equal(t, 2, summary[ManifestStatusSkipped])
+
+	entry, err := store.Get(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, "budget exhausted", entry.FailureReason)
+
+	// Other runs are untouched
+	summary, err = store.GetSummary(ctx, "run-other")
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary[ManifestStatusPending])
+}
+
+func TestManifestEntry_Quantization(t *testing.T) {
+	store := setupTestManifest(t)
+	ctx := context.Background()
+
+	entry := &ManifestEntry{RunID: "run-test", GPUType: "RTX 4090", Provider: "vastai", Model: "mistral-7b", Quantization: "awq"}
+	require.NoError(t, store.Create(ctx, entry))
+
+	got, err := store.Get(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "awq", got.Quantization)
+
+	pending, err := store.GetPendingByPriority(ctx, "run-test", 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "awq", pending[0].Quantization)
+}
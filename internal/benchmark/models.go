@@ -27,6 +27,13 @@ type BenchmarkResult struct {
 	// GPU statistics during the test
 	GPUStats GPUStats `json:"gpu_stats"`
 
+	// Per-level breakdown of a concurrency sweep, lowest level first
+	ConcurrencyLevels []ConcurrencyLevel `json:"concurrency_levels,omitempty"`
+
+	// What was measured; empty for chat results stored before workloads
+	// were recorded (see WorkloadOf)
+	Workload Workload `json:"workload,omitempty"`
+
 	// Provider information
 	Provider     string  `json:"provider"`
 	Location     string  `json:"location"`
@@ -103,6 +110,15 @@ type PerformanceResults struct {
 	MatchRate           float64 `json:"match_rate,omitempty"`            // 0.0-1.0, quality score
 	PromptsWithExpected int     `json:"prompts_with_expected,omitempty"` // Count of prompts with validation
 	PromptsMatching     int     `json:"prompts_matching,omitempty"`      // Count that matched
+
+	// Energy efficiency: average tokens/s per watt of total GPU power, which
+	// is the same as tokens per joule. Zero when power was not measured.
+	TokensPerWatt float64 `json:"tokens_per_watt,omitempty"`
+
+	// Throughput of non-chat workloads in ThroughputUnit, e.g. 3.2
+	// "images/s"; zero for chat, whose throughput is AvgTokensPerSecond
+	Throughput     float64 `json:"throughput,omitempty"`
+	ThroughputUnit string  `json:"throughput_unit,omitempty"`
 }
 
 // GPUStats contains GPU utilization statistics during the benchmark.
@@ -113,8 +129,40 @@ type GPUStats struct {
 	MaxMemoryUsedMiB  int     `json:"max_memory_used_mib"`
 	AvgTemperatureC   float64 `json:"avg_temperature_c"`
 	MaxTemperatureC   float64 `json:"max_temperature_c"`
-	AvgPowerDrawW     float64 `json:"avg_power_draw_w"`
-	MaxPowerDrawW     float64 `json:"max_power_draw_w"`
+	AvgPowerDrawW     float64 `json:"avg_power_draw_w"`            // Per GPU
+	MaxPowerDrawW     float64 `json:"max_power_draw_w"`            // Per GPU
+	AvgTotalPowerW    float64 `json:"avg_total_power_w,omitempty"` // All GPUs together
+	EnergyWh          float64 `json:"energy_wh,omitempty"`         // GPU energy used during the throughput test
+}
+
+// ConcurrencyLevel holds the measurements for one step of a concurrency
+// sweep. Plotted against Concurrency, throughput flattens and latency climbs
+// once the server saturates.
+type ConcurrencyLevel struct {
+	Concurrency       int     `json:"concurrency"`
+	Requests          int     `json:"requests"`
+	Errors            int     `json:"errors"`
+	ErrorRate         float64 `json:"error_rate"`
+	DurationSeconds   float64 `json:"duration_seconds"`
+	TokensPerSecond   float64 `json:"tokens_per_second"`    // Aggregate across in-flight requests
+	Throughput        float64 `json:"throughput,omitempty"` // Non-chat workloads, in the result's ThroughputUnit
+	RequestsPerMinute float64 `json:"requests_per_minute"`
+
+	// Request latency (in milliseconds)
+	P50LatencyMs float64 `json:"p50_latency_ms"`
+	P90LatencyMs float64 `json:"p90_latency_ms"`
+	P99LatencyMs float64 `json:"p99_latency_ms"`
+
+	// Time to first token (in milliseconds)
+	P50TTFTMs float64 `json:"p50_ttft_ms"`
+	P90TTFTMs float64 `json:"p90_ttft_ms"`
+	P99TTFTMs float64 `json:"p99_ttft_ms"`
+
+	// Inter-token latency: mean gap between tokens after the first, per
+	// request (in milliseconds)
+	P50ITLMs float64 `json:"p50_itl_ms"`
+	P90ITLMs float64 `json:"p90_itl_ms"`
+	P99ITLMs float64 `json:"p99_itl_ms"`
 }
 
 // CostAnalysis provides cost-efficiency metrics.
@@ -144,7 +192,14 @@ type HardwareRecommendation struct {
 	Model           string   `json:"model"`
 	MinVRAMGiB      int      `json:"min_vram_gib"`
 	RecommendedGPUs []string `json:"recommended_gpus"`
+	Quantization    string   `json:"quantization,omitempty"` // e.g. "awq"; runs are grouped by GPU, runtime and quantization
+	Runtime         string   `json:"runtime,omitempty"`      // e.g. "ollama", "sglang"
 	ExpectedTPS     float64  `json:"expected_tps"`
 	EstimatedCost   float64  `json:"estimated_cost_per_hour"`
 	Notes           string   `json:"notes"`
+
+	// Non-chat workloads rank by throughput in their own unit
+	Workload           Workload `json:"workload"`
+	ExpectedThroughput float64  `json:"expected_throughput,omitempty"`
+	ThroughputUnit     string   `json:"throughput_unit,omitempty"`
 }
@@ -181,7 +181,10 @@ func AnalyzeResults(results []RequestResult) PerformanceResults {
 	return pr
 }
 
-// AnalyzeGPUStats computes GPU statistics from samples.
+// AnalyzeGPUStats computes GPU statistics from samples. Multi-GPU hosts
+// produce one sample per GPU with the same timestamp; those are summed for
+// the total power draw. Samples without a power reading (nvidia-smi reports
+// N/A on some GPUs) are left out of the power figures.
 func AnalyzeGPUStats(samples []GPUSample) GPUStats {
 	if len(samples) == 0 {
 		return GPUStats{}
@@ -189,12 +192,12 @@ func AnalyzeGPUStats(samples []GPUSample) GPUStats {
 
 	var sumUtil, sumTemp, sumPower float64
 	var maxUtil, maxTemp, maxPower float64
-	var maxMem int
+	var maxMem, powerSamples int
+	totalPower := make(map[int64]float64)
 
 	for _, s := range samples {
 		sumUtil += s.Utilization
 		sumTemp += s.Temperature
-		sumPower += s.PowerDraw
 
 		if s.Utilization > maxUtil {
 			maxUtil = s.Utilization
@@ -202,24 +205,37 @@ func AnalyzeGPUStats(samples []GPUSample) GPUStats {
 		if s.Temperature > maxTemp {
 			maxTemp = s.Temperature
 		}
-		if s.PowerDraw > maxPower {
-			maxPower = s.PowerDraw
-		}
 		if s.MemoryUsed > maxMem {
 			maxMem = s.MemoryUsed
 		}
+		if s.PowerDraw > 0 {
+			sumPower += s.PowerDraw
+			powerSamples++
+			totalPower[s.Timestamp] += s.PowerDraw
+			if s.PowerDraw > maxPower {
+				maxPower = s.PowerDraw
+			}
+		}
 	}
 
 	n := float64(len(samples))
-	return GPUStats{
+	stats := GPUStats{
 		AvgUtilizationPct: sumUtil / n,
 		MaxUtilizationPct: maxUtil,
 		AvgTemperatureC:   sumTemp / n,
 		MaxTemperatureC:   maxTemp,
-		AvgPowerDrawW:     sumPower / n,
 		MaxPowerDrawW:     maxPower,
 		MaxMemoryUsedMiB:  maxMem,
 	}
+	if powerSamples > 0 {
+		stats.AvgPowerDrawW = sumPower / float64(powerSamples)
+		var sumTotal float64
+		for _, w := range totalPower {
+			sumTotal += w
+		}
+		stats.AvgTotalPowerW = sumTotal / float64(len(totalPower))
+	}
+	return stats
 }
 
 // percentile calculates the p-th percentile of a sorted slice.
@@ -241,7 +257,7 @@ func BuildBenchmarkResult(
 	provider, location string,
 	pricePerHour float64,
 ) *BenchmarkResult {
-	return &BenchmarkResult{
+	result := &BenchmarkResult{
 		Timestamp:    time.Now(),
 		Hardware:     hardware,
 		Model:        model,
@@ -252,6 +268,32 @@ func BuildBenchmarkResult(
 		Location:     location,
 		PricePerHour: pricePerHour,
 	}
+	ApplyEnergyMetrics(result)
+	return result
+}
+
+// ApplyEnergyMetrics fills in total GPU power, energy and tokens per watt
+// from the power readings where the result does not already carry them.
+// Older results only have the per-GPU average, which is scaled by the GPU
+// count. Results without power readings are left unchanged.
+func ApplyEnergyMetrics(result *BenchmarkResult) {
+	g := &result.GPUStats
+	if g.AvgTotalPowerW <= 0 && g.AvgPowerDrawW > 0 {
+		count := result.Hardware.GPUCount
+		if count < 1 {
+			count = 1
+		}
+		g.AvgTotalPowerW = g.AvgPowerDrawW * float64(count)
+	}
+	if g.AvgTotalPowerW <= 0 {
+		return
+	}
+	if g.EnergyWh <= 0 && result.Results.DurationSeconds > 0 {
+		g.EnergyWh = g.AvgTotalPowerW * result.Results.DurationSeconds / 3600
+	}
+	if result.Results.TokensPerWatt <= 0 && result.Results.AvgTokensPerSecond > 0 {
+		result.Results.TokensPerWatt = result.Results.AvgTokensPerSecond / g.AvgTotalPowerW
+	}
 }
 
 // CalculateCostAnalysis computes cost metrics from benchmark results.
@@ -425,6 +467,11 @@ GPU Utilization
   Avg Power:        %.1f W
   Peak VRAM:        %d MiB
 
+Energy
+  Total GPU Power:  %.1f W
+  Energy Used:      %.2f Wh
+  Tokens/Watt:      %.3f
+
 Cost Analysis
   Price/Hour:       $%.3f
   Tokens/Dollar:    %.0f
@@ -442,6 +489,7 @@ Cost Analysis
 		result.GPUStats.AvgUtilizationPct, result.GPUStats.AvgTemperatureC,
 		result.GPUStats.MaxTemperatureC, result.GPUStats.AvgPowerDrawW,
 		result.GPUStats.MaxMemoryUsedMiB,
+		result.GPUStats.AvgTotalPowerW, result.GPUStats.EnergyWh, result.Results.TokensPerWatt,
 		cost.CostPerHour, cost.TokensPerDollar, cost.CostPerMillionTokens, cost.EstimatedMonthly,
 	)
 }
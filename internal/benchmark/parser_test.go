@@ -154,6 +154,57 @@ func TestAnalyzeGPUStats(t *testing.T) {
 	}
 }
 
+func TestAnalyzeGPUStats_MultiGPUPower(t *testing.T) {
+	samples := []GPUSample{
+		{Timestamp: 100, PowerDraw: 200},
+		{Timestamp: 100, PowerDraw: 220},
+		{Timestamp: 105, PowerDraw: 300},
+		{Timestamp: 105, PowerDraw: 0}, // N/A reading
+	}
+
+	stats := AnalyzeGPUStats(samples)
+
+	// Per GPU: (200+220+300)/3; total: (420+300)/2
+	if stats.AvgPowerDrawW != 240 {
+		t.Errorf("expected 240 W avg per GPU, got %f", stats.AvgPowerDrawW)
+	}
+	if stats.AvgTotalPowerW != 360 {
+		t.Errorf("expected 360 W avg total, got %f", stats.AvgTotalPowerW)
+	}
+	if stats.MaxPowerDrawW != 300 {
+		t.Errorf("expected 300 W max, got %f", stats.MaxPowerDrawW)
+	}
+}
+
+func TestApplyEnergyMetrics(t *testing.T) {
+	result := &BenchmarkResult{
+		Hardware: HardwareInfo{GPUCount: 2},
+		Results:  PerformanceResults{AvgTokensPerSecond: 100, DurationSeconds: 1800},
+		GPUStats: GPUStats{AvgPowerDrawW: 125},
+	}
+
+	ApplyEnergyMetrics(result)
+
+	// Older results only carry the per-GPU average: 2 x 125 W
+	if result.GPUStats.AvgTotalPowerW != 250 {
+		t.Errorf("expected 250 W total power, got %f", result.GPUStats.AvgTotalPowerW)
+	}
+	// 250 W for half an hour
+	if result.GPUStats.EnergyWh != 125 {
+		t.Errorf("expected 125 Wh, got %f", result.GPUStats.EnergyWh)
+	}
+	if result.Results.TokensPerWatt != 0.4 {
+		t.Errorf("expected 0.4 tokens/W, got %f", result.Results.TokensPerWatt)
+	}
+
+	// No power readings: nothing to derive
+	noPower := &BenchmarkResult{Results: PerformanceResults{AvgTokensPerSecond: 100}}
+	ApplyEnergyMetrics(noPower)
+	if noPower.Results.TokensPerWatt != 0 || noPower.GPUStats.EnergyWh != 0 {
+		t.Errorf("expected no energy metrics without power readings, got %+v", noPower.GPUStats)
+	}
+}
+
 func TestCalculateCostAnalysis(t *testing.T) {
 	result := &BenchmarkResult{
 		Results: PerformanceResults{
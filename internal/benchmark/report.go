@@ -0,0 +1,435 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportFormat is an output format for benchmark reports
+type ReportFormat string
+
+const (
+	ReportMarkdown ReportFormat = "markdown"
+	ReportJSON     ReportFormat = "json"
+	ReportHTML     ReportFormat = "html"
+	ReportCSV      ReportFormat = "csv"
+)
+
+// ParseReportFormat accepts "markdown" (or "md"), "json", "html" and "csv"
+func ParseReportFormat(s string) (ReportFormat, error) {
+	switch strings.ToLower(s) {
+	case "", "markdown", "md":
+		return ReportMarkdown, nil
+	case "json":
+		return ReportJSON, nil
+	case "html":
+		return ReportHTML, nil
+	case "csv":
+		return ReportCSV, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q (expected markdown, json, html or csv)", s)
+	}
+}
+
+// ContentType returns the HTTP content type for the format
+func (f ReportFormat) ContentType() string {
+	switch f {
+	case ReportJSON:
+		return "application/json; charset=utf-8"
+	case ReportHTML:
+		return "text/html; charset=utf-8"
+	case ReportCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/markdown; charset=utf-8"
+	}
+}
+
+// maxRecommendErrorRate excludes unreliable runs from recommendations,
+// matching GetModelRecommendations
+const maxRecommendErrorRate = 0.1
+
+// Report summarizes a set of benchmark runs
+type Report struct {
+	GeneratedAt     time.Time             `json:"generated_at"`
+	Scope           ReportScope           `json:"scope"`
+	Summary         ReportSummary         `json:"summary"`
+	Recommendations []ModelRecommendation `json:"recommendations"`
+	Hardware        []HardwareSummary     `json:"hardware"`
+	Cost            ReportCostSummary     `json:"cost"`
+	Saturation      []SaturationCurve     `json:"saturation"`
+	Results         []HistoryEntry        `json:"results"`
+}
+
+// ReportScope records the filters a report was generated with
+type ReportScope struct {
+	Model    string     `json:"model,omitempty"`
+	GPU      string     `json:"gpu,omitempty"`
+	Provider string     `json:"provider,omitempty"`
+	Since    *time.Time `json:"since,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+}
+
+// ReportSummary holds headline figures across all runs in the report
+type ReportSummary struct {
+	Runs                    int       `json:"runs"`
+	Models                  int       `json:"models"`
+	GPUs                    int       `json:"gpus"`
+	Providers               int       `json:"providers"`
+	FirstRun                time.Time `json:"first_run,omitempty"`
+	LastRun                 time.Time `json:"last_run,omitempty"`
+	AvgTokensPerSecond      float64   `json:"avg_tokens_per_second"`
+	MaxTokensPerSecond      float64   `json:"max_tokens_per_second"`
+	AvgLatencyMs            float64   `json:"avg_latency_ms"`
+	AvgErrorRate            float64   `json:"avg_error_rate"`
+	AvgCostPerMillionTokens float64   `json:"avg_cost_per_million_tokens,omitempty"`
+	AvgTokensPerWatt        float64   `json:"avg_tokens_per_watt,omitempty"` // Over runs with power readings
+}
+
+// ModelRecommendation picks the fastest, the cheapest-per-token (per unit
+// for non-chat workloads) and the most energy-efficient GPU for a model among runs with an error rate under 10%
+type ModelRecommendation struct {
+	Model         string           `json:"model"`
+	Fastest       *HardwareSummary `json:"fastest,omitempty"`
+	BestValue     *HardwareSummary `json:"best_value,omitempty"`
+	MostEfficient *HardwareSummary `json:"most_efficient,omitempty"`
+}
+
+// HardwareSummary averages the runs of one model on one GPU. Quantized
+// variants of a model, and runs on different inference servers, are
+// summarized separately.
+type HardwareSummary struct {
+	Model                string  `json:"model"`
+	Quantization         string  `json:"quantization,omitempty"`
+	Runtime              string  `json:"runtime,omitempty"`
+	GPUName              string  `json:"gpu_name"`
+	Runs                 int     `json:"runs"`
+	AvgTokensPerSecond   float64 `json:"avg_tokens_per_second"`
+	AvgLatencyMs         float64 `json:"avg_latency_ms"`
+	P95LatencyMs         float64 `json:"p95_latency_ms"`
+	AvgErrorRate         float64 `json:"avg_error_rate"`
+	AvgPricePerHour      float64 `json:"avg_price_per_hour"`
+	CostPerMillionTokens float64 `json:"cost_per_million_tokens,omitempty"`
+	AvgPowerW            float64 `json:"avg_power_w,omitempty"` // Over runs with power readings
+	TokensPerWatt        float64 `json:"tokens_per_watt,omitempty"`
+
+	// Throughput in the workload's unit, and for non-chat workloads the
+	// price of 1,000 of those units (images, inputs, audio seconds)
+	Workload        Workload `json:"workload"`
+	AvgThroughput   float64  `json:"avg_throughput"`
+	ThroughputUnit  string   `json:"throughput_unit"`
+	CostPerThousand float64  `json:"cost_per_thousand,omitempty"`
+}
+
+// unitCost is what "best value" minimizes: cost per million tokens for
+// chat, per thousand units otherwise
+func (h *HardwareSummary) unitCost() float64 {
+	if h.Workload == WorkloadChat {
+		return h.CostPerMillionTokens
+	}
+	return h.CostPerThousand
+}
+
+// ReportCostSummary totals what the benchmark runs themselves cost
+type ReportCostSummary struct {
+	TotalRunCost float64        `json:"total_run_cost"`
+	ByProvider   []ProviderCost `json:"by_provider"`
+}
+
+// ProviderCost is the benchmark spend on one provider
+type ProviderCost struct {
+	Provider string  `json:"provider"`
+	Runs     int     `json:"runs"`
+	RunCost  float64 `json:"run_cost"`
+}
+
+// SaturationCurve is the concurrency sweep of the most recent run of a
+// model on a GPU that has one
+type SaturationCurve struct {
+	Model        string             `json:"model"`
+	Quantization string             `json:"quantization,omitempty"`
+	Runtime      string             `json:"runtime,omitempty"`
+	GPUName      string             `json:"gpu_name"`
+	BenchmarkID  string             `json:"benchmark_id"`
+	Timestamp    time.Time          `json:"timestamp"`
+	Levels       []ConcurrencyLevel `json:"levels"`
+
+	ThroughputUnit string `json:"throughput_unit"` // Of each level's throughput
+
+	// Lowest level reaching 95% of the best throughput; beyond it, extra
+	// concurrency mostly adds latency
+	SaturationConcurrency int `json:"saturation_concurrency"`
+}
+
+// saturationShare is the fraction of peak throughput at which a sweep is
+// considered saturated
+const saturationShare = 0.95
+
+// GenerateReport builds a report over the runs matching filter. The limit,
+// if set, caps the number of most recent runs included.
+func (s *Store) GenerateReport(ctx context.Context, filter HistoryFilter) (*Report, error) {
+	entries, err := s.ListHistory(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	report := BuildReport(entries, time.Now())
+
+	report.Scope = ReportScope{Model: filter.Model, GPU: filter.GPU, Provider: filter.Provider}
+	if !filter.Since.IsZero() {
+		since := filter.Since
+		report.Scope.Since = &since
+	}
+	if !filter.Until.IsZero() {
+		until := filter.Until
+		report.Scope.Until = &until
+	}
+	return report, nil
+}
+
+// BuildReport summarizes history entries, as returned by ListHistory
+func BuildReport(entries []HistoryEntry, now time.Time) *Report {
+	report := &Report{
+		GeneratedAt:     now,
+		Recommendations: []ModelRecommendation{},
+		Hardware:        []HardwareSummary{},
+		Cost:            ReportCostSummary{ByProvider: []ProviderCost{}},
+		Saturation:      []SaturationCurve{},
+		Results:         entries,
+	}
+	if report.Results == nil {
+		report.Results = []HistoryEntry{}
+	}
+	if len(entries) == 0 {
+		return report
+	}
+
+	models := make(map[string]bool)
+	gpus := make(map[string]bool)
+	providers := make(map[string]*ProviderCost)
+	hardware := make(map[string]*HardwareSummary)
+	powerRuns := make(map[string]int)
+	var hardwareOrder []string
+
+	sum := &report.Summary
+	var costSum, tpwSum float64
+	var costRuns, tpwRuns int
+	for _, e := range entries {
+		sum.Runs++
+		models[e.Model] = true
+		gpus[e.GPUName] = true
+		if sum.FirstRun.IsZero() || e.Timestamp.Before(sum.FirstRun) {
+			sum.FirstRun = e.Timestamp
+		}
+		if e.Timestamp.After(sum.LastRun) {
+			sum.LastRun = e.Timestamp
+		}
+		sum.AvgTokensPerSecond += e.AvgTokensPerSecond
+		sum.AvgLatencyMs += e.AvgLatencyMs
+		sum.AvgErrorRate += e.ErrorRate
+		if e.AvgTokensPerSecond > sum.MaxTokensPerSecond {
+			sum.MaxTokensPerSecond = e.AvgTokensPerSecond
+		}
+		if e.CostPerMillionTokens > 0 {
+			costSum += e.CostPerMillionTokens
+			costRuns++
+		}
+		if e.TokensPerWatt > 0 {
+			tpwSum += e.TokensPerWatt
+			tpwRuns++
+		}
+
+		p, ok := providers[e.Provider]
+		if !ok {
+			p = &ProviderCost{Provider: e.Provider}
+			providers[e.Provider] = p
+		}
+		p.Runs++
+		p.RunCost += e.RunCost
+		report.Cost.TotalRunCost += e.RunCost
+
+		if e.ErrorRate >= maxRecommendErrorRate {
+			continue
+		}
+		key := e.Model + "|" + e.Runtime + "|" + e.Quantization + "|" + e.GPUName
+		h, ok := hardware[key]
+		if !ok {
+			h = &HardwareSummary{Model: e.Model, Quantization: e.Quantization, Runtime: e.Runtime, GPUName: e.GPUName,
+				Workload: e.Workload, ThroughputUnit: e.ThroughputUnit}
+			hardware[key] = h
+			hardwareOrder = append(hardwareOrder, key)
+		}
+		h.Runs++
+		h.AvgTokensPerSecond += e.AvgTokensPerSecond
+		h.AvgThroughput += e.Throughput
+		h.AvgLatencyMs += e.AvgLatencyMs
+		h.P95LatencyMs += e.P95LatencyMs
+		h.AvgErrorRate += e.ErrorRate
+		h.AvgPricePerHour += e.PricePerHour
+		if e.AvgPowerW > 0 && e.TokensPerWatt > 0 {
+			h.AvgPowerW += e.AvgPowerW
+			h.TokensPerWatt += e.TokensPerWatt
+			powerRuns[key]++
+		}
+	}
+
+	n := float64(sum.Runs)
+	sum.Models = len(models)
+	sum.GPUs = len(gpus)
+	sum.Providers = len(providers)
+	sum.AvgTokensPerSecond /= n
+	sum.AvgLatencyMs /= n
+	sum.AvgErrorRate /= n
+	if costRuns > 0 {
+		sum.AvgCostPerMillionTokens = costSum / float64(costRuns)
+	}
+	if tpwRuns > 0 {
+		sum.AvgTokensPerWatt = tpwSum / float64(tpwRuns)
+	}
+
+	for _, p := range providers {
+		report.Cost.ByProvider = append(report.Cost.ByProvider, *p)
+	}
+	sort.Slice(report.Cost.ByProvider, func(i, j int) bool {
+		return report.Cost.ByProvider[i].RunCost > report.Cost.ByProvider[j].RunCost
+	})
+
+	for _, key := range hardwareOrder {
+		h := hardware[key]
+		runs := float64(h.Runs)
+		h.AvgTokensPerSecond /= runs
+		h.AvgThroughput /= runs
+		h.AvgLatencyMs /= runs
+		h.P95LatencyMs /= runs
+		h.AvgErrorRate /= runs
+		h.AvgPricePerHour /= runs
+		if h.AvgTokensPerSecond > 0 && h.AvgPricePerHour > 0 {
+			h.CostPerMillionTokens = h.AvgPricePerHour / (h.AvgTokensPerSecond * 3600) * 1e6
+		}
+		if h.Workload != WorkloadChat && h.AvgThroughput > 0 && h.AvgPricePerHour > 0 {
+			h.CostPerThousand = h.AvgPricePerHour / (h.AvgThroughput * 3600) * 1e3
+		}
+		if n := powerRuns[key]; n > 0 {
+			h.AvgPowerW /= float64(n)
+			h.TokensPerWatt /= float64(n)
+		}
+		report.Hardware = append(report.Hardware, *h)
+	}
+	sort.SliceStable(report.Hardware, func(i, j int) bool {
+		a, b := report.Hardware[i], report.Hardware[j]
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.AvgThroughput > b.AvgThroughput
+	})
+
+	report.Recommendations = recommend(report.Hardware)
+	report.Saturation = saturationCurves(entries)
+	return report
+}
+
+// saturationCurves picks the latest sweep for each model/GPU, ordered like
+// the hardware table
+func saturationCurves(entries []HistoryEntry) []SaturationCurve {
+	latest := make(map[string]*HistoryEntry)
+	for i := range entries {
+		e := &entries[i]
+		if len(e.ConcurrencyLevels) == 0 {
+			continue
+		}
+		key := e.Model + "|" + e.Runtime + "|" + e.Quantization + "|" + e.GPUName
+		if prev, ok := latest[key]; !ok || e.Timestamp.After(prev.Timestamp) {
+			latest[key] = e
+		}
+	}
+
+	curves := []SaturationCurve{}
+	for _, e := range latest {
+		curve := SaturationCurve{
+			Model:        e.Model,
+			Quantization: e.Quantization,
+			Runtime:      e.Runtime,
+			GPUName:      e.GPUName,
+			BenchmarkID:  e.ID,
+			Timestamp:    e.Timestamp,
+			Levels:       e.ConcurrencyLevels,
+
+			ThroughputUnit: e.ThroughputUnit,
+		}
+		var peak float64
+		for _, l := range curve.Levels {
+			peak = max(peak, levelRate(l))
+		}
+		for _, l := range curve.Levels {
+			if peak > 0 && levelRate(l) >= peak*saturationShare {
+				curve.SaturationConcurrency = l.Concurrency
+				break
+			}
+		}
+		curves = append(curves, curve)
+	}
+	sort.Slice(curves, func(i, j int) bool {
+		if curves[i].Model != curves[j].Model {
+			return curves[i].Model < curves[j].Model
+		}
+		if curves[i].GPUName != curves[j].GPUName {
+			return curves[i].GPUName < curves[j].GPUName
+		}
+		if curves[i].Runtime != curves[j].Runtime {
+			return curves[i].Runtime < curves[j].Runtime
+		}
+		return curves[i].Quantization < curves[j].Quantization
+	})
+	return curves
+}
+
+// levelRate is a sweep level's throughput in the workload's unit
+func levelRate(l ConcurrencyLevel) float64 {
+	if l.Throughput > 0 {
+		return l.Throughput
+	}
+	return l.TokensPerSecond
+}
+
+// recommend picks the fastest, best-value and most efficient GPU per model
+// from hardware, which must be grouped by model
+func recommend(hardware []HardwareSummary) []ModelRecommendation {
+	recs := []ModelRecommendation{}
+	for i := range hardware {
+		h := &hardware[i]
+		if len(recs) == 0 || recs[len(recs)-1].Model != h.Model {
+			recs = append(recs, ModelRecommendation{Model: h.Model})
+		}
+		rec := &recs[len(recs)-1]
+		if h.AvgThroughput > 0 && (rec.Fastest == nil || h.AvgThroughput > rec.Fastest.AvgThroughput) {
+			rec.Fastest = h
+		}
+		if h.unitCost() > 0 && (rec.BestValue == nil || h.unitCost() < rec.BestValue.unitCost()) {
+			rec.BestValue = h
+		}
+		if h.TokensPerWatt > 0 && (rec.MostEfficient == nil || h.TokensPerWatt > rec.MostEfficient.TokensPerWatt) {
+			rec.MostEfficient = h
+		}
+	}
+	return recs
+}
+
+// Render writes the report in the given format
+func (r *Report) Render(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case ReportHTML:
+		return r.WriteHTML(w)
+	case ReportCSV:
+		return r.WriteCSV(w)
+	default:
+		return r.WriteMarkdown(w)
+	}
+}
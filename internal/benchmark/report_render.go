@@ -0,0 +1,509 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteMarkdown writes the report as a markdown document
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Benchmark Report\n\n")
+	fmt.Fprintf(&b, "Generated %s", r.GeneratedAt.UTC().Format(time.RFC3339))
+	if scope := r.Scope.describe(); scope != "" {
+		fmt.Fprintf(&b, " for %s", scope)
+	}
+	b.WriteString(".\n\n")
+
+	if r.Summary.Runs == 0 {
+		b.WriteString("No benchmark runs found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	s := r.Summary
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Metric | Value |\n|--------|-------|\n")
+	fmt.Fprintf(&b, "| Runs | %d |\n", s.Runs)
+	fmt.Fprintf(&b, "| Period | %s to %s |\n", s.FirstRun.UTC().Format("2006-01-02"), s.LastRun.UTC().Format("2006-01-02"))
+	fmt.Fprintf(&b, "| Models / GPUs / Providers | %d / %d / %d |\n", s.Models, s.GPUs, s.Providers)
+	fmt.Fprintf(&b, "| Avg throughput | %.1f tok/s |\n", s.AvgTokensPerSecond)
+	fmt.Fprintf(&b, "| Best throughput | %.1f tok/s |\n", s.MaxTokensPerSecond)
+	fmt.Fprintf(&b, "| Avg latency | %.0f ms |\n", s.AvgLatencyMs)
+	fmt.Fprintf(&b, "| Avg error rate | %.1f%% |\n", s.AvgErrorRate*100)
+	if s.AvgCostPerMillionTokens > 0 {
+		fmt.Fprintf(&b, "| Avg cost per 1M tokens | $%.2f |\n", s.AvgCostPerMillionTokens)
+	}
+	if s.AvgTokensPerWatt > 0 {
+		fmt.Fprintf(&b, "| Avg energy efficiency | %.3f tok/W |\n", s.AvgTokensPerWatt)
+	}
+
+	b.WriteString("\n## Recommendations\n\n")
+	b.WriteString("| Model | Fastest GPU | Throughput | Best Value GPU | Cost | Most Efficient GPU | Tok/W |\n")
+	b.WriteString("|-------|-------------|------------|----------------|------|--------------------|-------|\n")
+	for _, rec := range r.Recommendations {
+		fastest, fastestTPS, value, valueCost, efficient, efficientTPW := "-", "-", "-", "-", "-", "-"
+		if rec.Fastest != nil {
+			fastest = gpuLabel(rec.Fastest.GPUName, rec.Fastest.Runtime, rec.Fastest.Quantization)
+			fastestTPS = throughputLabel(rec.Fastest.AvgThroughput, rec.Fastest.ThroughputUnit)
+		}
+		if rec.BestValue != nil {
+			value = gpuLabel(rec.BestValue.GPUName, rec.BestValue.Runtime, rec.BestValue.Quantization)
+			valueCost = unitCostLabel(*rec.BestValue)
+		}
+		if rec.MostEfficient != nil {
+			efficient = gpuLabel(rec.MostEfficient.GPUName, rec.MostEfficient.Runtime, rec.MostEfficient.Quantization)
+			efficientTPW = fmt.Sprintf("%.3f", rec.MostEfficient.TokensPerWatt)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n", mdEscape(rec.Model), mdEscape(fastest), fastestTPS,
+			mdEscape(value), valueCost, mdEscape(efficient), efficientTPW)
+	}
+
+	b.WriteString("\n## Hardware\n\n")
+	b.WriteString("| Model | GPU | Runs | Avg Throughput | Avg Latency | P95 Latency | $/hr | Cost | Avg Power | Tok/W |\n")
+	b.WriteString("|-------|-----|------|----------------|-------------|-------------|------|------|-----------|-------|\n")
+	for _, h := range r.Hardware {
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %.0f ms | %.0f ms | $%.2f | %s | %s | %s |\n",
+			mdEscape(h.Model), mdEscape(gpuLabel(h.GPUName, h.Runtime, h.Quantization)), h.Runs,
+			throughputLabel(h.AvgThroughput, h.ThroughputUnit),
+			h.AvgLatencyMs, h.P95LatencyMs, h.AvgPricePerHour, unitCostLabel(h),
+			watts(h.AvgPowerW), tokensPerWatt(h.TokensPerWatt))
+	}
+
+	if len(r.Saturation) > 0 {
+		b.WriteString("\n## Saturation\n\n")
+		b.WriteString("Latest concurrency sweep per model and GPU. Throughput is the total across concurrent requests, in the unit given per sweep; latencies are in ms.\n")
+		for _, c := range r.Saturation {
+			fmt.Fprintf(&b, "\n### %s on %s\n\n", c.Model, gpuLabel(c.GPUName, c.Runtime, c.Quantization))
+			fmt.Fprintf(&b, "Run %s on %s, throughput in %s", c.BenchmarkID, c.Timestamp.UTC().Format("2006-01-02"), c.ThroughputUnit)
+			if c.SaturationConcurrency > 0 {
+				fmt.Fprintf(&b, "; saturates at concurrency %d", c.SaturationConcurrency)
+			}
+			b.WriteString(".\n\n")
+			b.WriteString("| Concurrency | Throughput | Req/min | TTFT p50 / p90 / p99 | ITL p50 / p90 / p99 | Latency p50 / p90 / p99 | Errors |\n")
+			b.WriteString("|-------------|------------|---------|----------------------|---------------------|-------------------------|--------|\n")
+			for _, l := range c.Levels {
+				fmt.Fprintf(&b, "| %d | %.1f | %.1f | %.0f / %.0f / %.0f | %.1f / %.1f / %.1f | %.0f / %.0f / %.0f | %.1f%% |\n",
+					l.Concurrency, levelRate(l), l.RequestsPerMinute,
+					l.P50TTFTMs, l.P90TTFTMs, l.P99TTFTMs, l.P50ITLMs, l.P90ITLMs, l.P99ITLMs,
+					l.P50LatencyMs, l.P90LatencyMs, l.P99LatencyMs, l.ErrorRate*100)
+			}
+		}
+	}
+
+	b.WriteString("\n## Cost\n\n")
+	fmt.Fprintf(&b, "Benchmark runs cost $%.2f in total.\n\n", r.Cost.TotalRunCost)
+	b.WriteString("| Provider | Runs | Cost |\n|----------|------|------|\n")
+	for _, p := range r.Cost.ByProvider {
+		fmt.Fprintf(&b, "| %s | %d | $%.2f |\n", mdEscape(p.Provider), p.Runs, p.RunCost)
+	}
+
+	b.WriteString("\n## Results\n\n")
+	b.WriteString("| Date | Model | GPU | Provider | Throughput | Trend | Avg Latency | P95 Latency | Errors | $/hr | Run Cost | Tok/W |\n")
+	b.WriteString("|------|-------|-----|----------|------------|-------|-------------|-------------|--------|------|----------|-------|\n")
+	for _, e := range r.Results {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %.0f ms | %.0f ms | %.1f%% | $%.2f | $%.4f | %s |\n",
+			e.Timestamp.UTC().Format("2006-01-02 15:04"), mdEscape(e.Model), mdEscape(gpuLabel(e.GPUName, e.Runtime, e.Quantization)),
+			mdEscape(e.Provider), throughputLabel(e.Throughput, e.ThroughputUnit), trendLabel(e), e.AvgLatencyMs, e.P95LatencyMs,
+			e.ErrorRate*100, e.PricePerHour, e.RunCost, tokensPerWatt(e.TokensPerWatt))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCSV writes one row per run, for spreadsheets and BI tools
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"timestamp", "id", "model", "gpu_name", "gpu_count", "provider", "location",
+		"avg_tokens_per_second", "avg_latency_ms", "p95_latency_ms", "error_rate",
+		"price_per_hour", "run_cost", "cost_per_million_tokens", "avg_power_w", "tokens_per_watt",
+		"trend", "change_pct", "quantization", "workload", "throughput", "throughput_unit", "runtime",
+	})
+	for _, e := range r.Results {
+		cw.Write([]string{
+			e.Timestamp.UTC().Format(time.RFC3339),
+			e.ID,
+			e.Model,
+			e.GPUName,
+			strconv.Itoa(e.GPUCount),
+			e.Provider,
+			e.Location,
+			csvFloat(e.AvgTokensPerSecond, 2),
+			csvFloat(e.AvgLatencyMs, 1),
+			csvFloat(e.P95LatencyMs, 1),
+			csvFloat(e.ErrorRate, 4),
+			csvFloat(e.PricePerHour, 4),
+			csvFloat(e.RunCost, 4),
+			csvFloat(e.CostPerMillionTokens, 4),
+			csvFloat(e.AvgPowerW, 1),
+			csvFloat(e.TokensPerWatt, 4),
+			string(e.Trend),
+			csvFloat(e.ChangePct, 2),
+			e.Quantization,
+			string(e.Workload),
+			csvFloat(e.Throughput, 2),
+			e.ThroughputUnit,
+			e.Runtime,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvFloat(v float64, prec int) string {
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// throughputLabel shows a throughput with its unit, e.g. "2.50 images/s"
+func throughputLabel(v float64, unit string) string {
+	if v <= 0 {
+		return "-"
+	}
+	if unit == "tok/s" {
+		return fmt.Sprintf("%.1f %s", v, unit)
+	}
+	return fmt.Sprintf("%.2f %s", v, unit)
+}
+
+// unitCostLabel shows what best value is ranked by: cost per million
+// tokens for chat, per thousand units otherwise
+func unitCostLabel(h HardwareSummary) string {
+	if h.unitCost() <= 0 {
+		return "-"
+	}
+	if h.Workload == WorkloadChat {
+		return fmt.Sprintf("$%.2f/1M tok", h.CostPerMillionTokens)
+	}
+	return fmt.Sprintf("$%.3f/1K %s", h.CostPerThousand, strings.TrimSuffix(h.ThroughputUnit, "/s"))
+}
+
+// gpuLabel names a GPU with the inference server and quantization it ran,
+// e.g. "RTX 4090 (sglang) + AWQ". Ollama, the default, is left out.
+func gpuLabel(gpu, runtime, quantization string) string {
+	label := gpu
+	if runtime != "" && runtime != "ollama" {
+		label += " (" + runtime + ")"
+	}
+	if quantization != "" {
+		label += " + " + strings.ToUpper(quantization)
+	}
+	return label
+}
+
+// watts and tokensPerWatt show "-" for runs without power readings
+func watts(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f W", v)
+}
+
+func tokensPerWatt(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", v)
+}
+
+// WriteHTML writes the report as a standalone HTML page. Charts are inline
+// SVG so the file can be shared without external assets.
+func (r *Report) WriteHTML(w io.Writer) error {
+	data := struct {
+		*Report
+		ScopeText  string
+		Charts     []reportChart
+		Plots      []saturationPlot
+		TrendLabel func(HistoryEntry) string
+	}{
+		Report:     r,
+		ScopeText:  r.Scope.describe(),
+		Charts:     r.charts(),
+		Plots:      r.saturationPlots(),
+		TrendLabel: trendLabel,
+	}
+	return reportTemplate.Execute(w, data)
+}
+
+// reportChart is a horizontal bar chart
+type reportChart struct {
+	Title  string
+	Unit   string
+	Height int
+	Bars   []reportBar
+}
+
+type reportBar struct {
+	Label string
+	Value float64
+	Width float64 // Bar length in chart units, 0-400
+	Y     int
+}
+
+const (
+	chartBarHeight = 22
+	chartBarWidth  = 400
+)
+
+// charts plots throughput, cost per million tokens and, where power was
+// measured, tokens per watt for each chat model/GPU. Other workloads are
+// left out since their units differ.
+func (r *Report) charts() []reportChart {
+	if len(r.Hardware) == 0 {
+		return nil
+	}
+	throughput := reportChart{Title: "Throughput by GPU", Unit: "tok/s"}
+	cost := reportChart{Title: "Cost per 1M tokens", Unit: "$"}
+	efficiency := reportChart{Title: "Energy efficiency", Unit: "tok/W"}
+	for _, h := range r.Hardware {
+		if h.Workload != WorkloadChat {
+			continue
+		}
+		label := gpuLabel(h.GPUName, h.Runtime, h.Quantization)
+		if r.Summary.Models > 1 {
+			label = h.Model + " · " + label
+		}
+		throughput.Bars = append(throughput.Bars, reportBar{Label: label, Value: h.AvgTokensPerSecond})
+		if h.CostPerMillionTokens > 0 {
+			cost.Bars = append(cost.Bars, reportBar{Label: label, Value: h.CostPerMillionTokens})
+		}
+		if h.TokensPerWatt > 0 {
+			efficiency.Bars = append(efficiency.Bars, reportBar{Label: label, Value: h.TokensPerWatt})
+		}
+	}
+
+	charts := []reportChart{throughput}
+	if len(cost.Bars) > 0 {
+		charts = append(charts, cost)
+	}
+	if len(efficiency.Bars) > 0 {
+		charts = append(charts, efficiency)
+	}
+	for i := range charts {
+		c := &charts[i]
+		var max float64
+		for _, bar := range c.Bars {
+			if bar.Value > max {
+				max = bar.Value
+			}
+		}
+		for j := range c.Bars {
+			if max > 0 {
+				c.Bars[j].Width = c.Bars[j].Value / max * chartBarWidth
+			}
+			c.Bars[j].Y = j * (chartBarHeight + 6)
+		}
+		c.Height = len(c.Bars) * (chartBarHeight + 6)
+	}
+	return charts
+}
+
+// saturationPlot is a line chart of throughput against concurrency. Levels
+// are spaced evenly since sweeps usually double at each step.
+type saturationPlot struct {
+	SaturationCurve
+	Line   string // SVG polyline points
+	Points []plotPoint
+	MaxTPS float64
+}
+
+type plotPoint struct {
+	X, Y  float64
+	Level ConcurrencyLevel
+}
+
+const (
+	plotLeft   = 60
+	plotTop    = 20
+	plotWidth  = 560
+	plotHeight = 180
+)
+
+func (r *Report) saturationPlots() []saturationPlot {
+	var plots []saturationPlot
+	for _, c := range r.Saturation {
+		p := saturationPlot{SaturationCurve: c}
+		for _, l := range c.Levels {
+			p.MaxTPS = max(p.MaxTPS, levelRate(l))
+		}
+		var line []string
+		for i, l := range c.Levels {
+			x := float64(plotLeft)
+			if len(c.Levels) > 1 {
+				x += float64(i) * plotWidth / float64(len(c.Levels)-1)
+			}
+			y := float64(plotTop + plotHeight)
+			if p.MaxTPS > 0 {
+				y -= levelRate(l) / p.MaxTPS * plotHeight
+			}
+			p.Points = append(p.Points, plotPoint{X: x, Y: y, Level: l})
+			line = append(line, fmt.Sprintf("%.1f,%.1f", x, y))
+		}
+		p.Line = strings.Join(line, " ")
+		plots = append(plots, p)
+	}
+	return plots
+}
+
+func (s ReportScope) describe() string {
+	var parts []string
+	if s.Model != "" {
+		parts = append(parts, "model "+s.Model)
+	}
+	if s.GPU != "" {
+		parts = append(parts, "GPU "+s.GPU)
+	}
+	if s.Provider != "" {
+		parts = append(parts, "provider "+s.Provider)
+	}
+	if s.Since != nil {
+		parts = append(parts, "since "+s.Since.UTC().Format("2006-01-02"))
+	}
+	if s.Until != nil {
+		parts = append(parts, "before "+s.Until.UTC().Format("2006-01-02"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func trendLabel(e HistoryEntry) string {
+	switch e.Trend {
+	case TrendUp:
+		return fmt.Sprintf("↑ %+.1f%%", e.ChangePct)
+	case TrendDown:
+		return fmt.Sprintf("↓ %+.1f%%", e.ChangePct)
+	case TrendFlat:
+		return fmt.Sprintf("→ %+.1f%%", e.ChangePct)
+	default:
+		return "-"
+	}
+}
+
+// mdEscape keeps values from breaking markdown table cells
+func mdEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct":   func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+	"f1":    func(v float64) string { return fmt.Sprintf("%.1f", v) },
+	"f0":    func(v float64) string { return fmt.Sprintf("%.0f", v) },
+	"usd":   func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	"usd4":  func(v float64) string { return fmt.Sprintf("$%.4f", v) },
+	"f3":    func(v float64) string { return fmt.Sprintf("%.3f", v) },
+	"gpu":   gpuLabel,
+	"rate":  throughputLabel,
+	"cost":  unitCostLabel,
+	"level": levelRate,
+	"watts": watts,
+	"tpw":   tokensPerWatt,
+	"date":  func(t time.Time) string { return t.UTC().Format("2006-01-02") },
+	"when":  func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Benchmark Report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem auto; max-width: 1100px; color: #1f2933; padding: 0 1rem; }
+h1 { margin-bottom: 0.2rem; }
+.meta { color: #616e7c; margin-top: 0; }
+.cards { display: flex; flex-wrap: wrap; gap: 1rem; margin: 1.5rem 0; }
+.card { border: 1px solid #e4e7eb; border-radius: 6px; padding: 0.8rem 1rem; min-width: 150px; }
+.card .label { color: #616e7c; font-size: 0.8rem; text-transform: uppercase; }
+.card .value { font-size: 1.4rem; font-weight: 600; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; font-size: 0.9rem; }
+th, td { border-bottom: 1px solid #e4e7eb; padding: 0.4rem 0.6rem; text-align: left; }
+th { background: #f5f7fa; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.up { color: #2f8132; } .down { color: #ba2525; }
+svg text { font-size: 12px; fill: #1f2933; }
+</style>
+</head>
+<body>
+<h1>Benchmark Report</h1>
+<p class="meta">Generated {{when .GeneratedAt}} UTC{{if .ScopeText}} for {{.ScopeText}}{{end}}</p>
+{{if eq .Summary.Runs 0}}
+<p>No benchmark runs found.</p>
+{{else}}
+<div class="cards">
+<div class="card"><div class="label">Runs</div><div class="value">{{.Summary.Runs}}</div></div>
+<div class="card"><div class="label">Period</div><div class="value">{{date .Summary.FirstRun}} – {{date .Summary.LastRun}}</div></div>
+<div class="card"><div class="label">Avg throughput</div><div class="value">{{f1 .Summary.AvgTokensPerSecond}} tok/s</div></div>
+<div class="card"><div class="label">Best throughput</div><div class="value">{{f1 .Summary.MaxTokensPerSecond}} tok/s</div></div>
+<div class="card"><div class="label">Avg latency</div><div class="value">{{f0 .Summary.AvgLatencyMs}} ms</div></div>
+<div class="card"><div class="label">Benchmark spend</div><div class="value">{{usd .Cost.TotalRunCost}}</div></div>
+{{if gt .Summary.AvgTokensPerWatt 0.0}}<div class="card"><div class="label">Avg efficiency</div><div class="value">{{f3 .Summary.AvgTokensPerWatt}} tok/W</div></div>{{end}}
+</div>
+
+{{range .Charts}}
+<h2>{{.Title}}</h2>
+<svg width="760" height="{{.Height}}" role="img" aria-label="{{.Title}}">
+{{$unit := .Unit}}{{range .Bars}}<g transform="translate(0,{{.Y}})">
+<text x="0" y="15">{{.Label}}</text>
+<rect x="240" y="0" width="{{f1 .Width}}" height="22" fill="#3e7bfa" rx="2"></rect>
+<text x="{{f1 .Width}}" dx="248" y="15">{{if eq $unit "$"}}{{usd .Value}}{{else if eq $unit "tok/W"}}{{f3 .Value}} {{$unit}}{{else}}{{f1 .Value}} {{$unit}}{{end}}</text>
+</g>
+{{end}}</svg>
+{{end}}
+
+<h2>Recommendations</h2>
+<table>
+<tr><th>Model</th><th>Fastest GPU</th><th>Throughput</th><th>Best value GPU</th><th>Cost</th><th>Most efficient GPU</th><th>Tok/W</th></tr>
+{{range .Recommendations}}<tr><td>{{.Model}}</td>
+{{if .Fastest}}<td>{{gpu .Fastest.GPUName .Fastest.Runtime .Fastest.Quantization}}</td><td class="num">{{rate .Fastest.AvgThroughput .Fastest.ThroughputUnit}}</td>{{else}}<td>-</td><td class="num">-</td>{{end}}
+{{if .BestValue}}<td>{{gpu .BestValue.GPUName .BestValue.Runtime .BestValue.Quantization}}</td><td class="num">{{cost .BestValue}}</td>{{else}}<td>-</td><td class="num">-</td>{{end}}
+{{if .MostEfficient}}<td>{{gpu .MostEfficient.GPUName .MostEfficient.Runtime .MostEfficient.Quantization}}</td><td class="num">{{f3 .MostEfficient.TokensPerWatt}}</td>{{else}}<td>-</td><td class="num">-</td>{{end}}</tr>
+{{end}}</table>
+
+<h2>Hardware</h2>
+<table>
+<tr><th>Model</th><th>GPU</th><th>Runs</th><th>Avg throughput</th><th>Avg latency</th><th>P95 latency</th><th>$/hr</th><th>Cost</th><th>Avg power</th><th>Tok/W</th></tr>
+{{range .Hardware}}<tr><td>{{.Model}}</td><td>{{gpu .GPUName .Runtime .Quantization}}</td><td class="num">{{.Runs}}</td><td class="num">{{rate .AvgThroughput .ThroughputUnit}}</td><td class="num">{{f0 .AvgLatencyMs}} ms</td><td class="num">{{f0 .P95LatencyMs}} ms</td><td class="num">{{usd .AvgPricePerHour}}</td><td class="num">{{cost .}}</td><td class="num">{{watts .AvgPowerW}}</td><td class="num">{{tpw .TokensPerWatt}}</td></tr>
+{{end}}</table>
+
+{{if .Plots}}
+<h2>Saturation</h2>
+<p>Latest concurrency sweep per model and GPU. Throughput is the total across concurrent requests.</p>
+{{range .Plots}}
+<h3>{{.Model}} on {{gpu .GPUName .Runtime .Quantization}}</h3>
+<p class="meta">Run {{.BenchmarkID}} on {{date .Timestamp}}{{if .SaturationConcurrency}}; saturates at concurrency {{.SaturationConcurrency}}{{end}}</p>
+<svg width="680" height="240" role="img" aria-label="Throughput by concurrency for {{.Model}} on {{gpu .GPUName .Runtime .Quantization}}">
+<line x1="60" y1="200" x2="620" y2="200" stroke="#9aa5b1"></line>
+<line x1="60" y1="20" x2="60" y2="200" stroke="#9aa5b1"></line>
+<text x="54" y="24" text-anchor="end">{{f0 .MaxTPS}}</text>
+<text x="54" y="204" text-anchor="end">0</text>
+<text x="10" y="115">{{.ThroughputUnit}}</text>
+<polyline points="{{.Line}}" fill="none" stroke="#3e7bfa" stroke-width="2"></polyline>
+{{range .Points}}<circle cx="{{f1 .X}}" cy="{{f1 .Y}}" r="3" fill="#3e7bfa"></circle>
+<text x="{{f1 .X}}" y="220" text-anchor="middle">{{.Level.Concurrency}}</text>
+{{end}}<text x="340" y="236" text-anchor="middle">concurrent requests</text>
+</svg>
+<table>
+<tr><th>Concurrency</th><th>Throughput</th><th>Req/min</th><th>TTFT p50</th><th>TTFT p90</th><th>TTFT p99</th><th>ITL p50</th><th>ITL p90</th><th>ITL p99</th><th>Latency p99</th><th>Errors</th></tr>
+{{range .Levels}}<tr><td class="num">{{.Concurrency}}</td><td class="num">{{f1 (level .)}}</td><td class="num">{{f1 .RequestsPerMinute}}</td><td class="num">{{f0 .P50TTFTMs}} ms</td><td class="num">{{f0 .P90TTFTMs}} ms</td><td class="num">{{f0 .P99TTFTMs}} ms</td><td class="num">{{f1 .P50ITLMs}} ms</td><td class="num">{{f1 .P90ITLMs}} ms</td><td class="num">{{f1 .P99ITLMs}} ms</td><td class="num">{{f0 .P99LatencyMs}} ms</td><td class="num">{{pct .ErrorRate}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+
+<h2>Cost</h2>
+<table>
+<tr><th>Provider</th><th>Runs</th><th>Cost</th></tr>
+{{range .Cost.ByProvider}}<tr><td>{{.Provider}}</td><td class="num">{{.Runs}}</td><td class="num">{{usd .RunCost}}</td></tr>
+{{end}}</table>
+
+<h2>Results</h2>
+<table>
+<tr><th>Date</th><th>Model</th><th>GPU</th><th>Provider</th><th>Throughput</th><th>Trend</th><th>Avg latency</th><th>P95 latency</th><th>Errors</th><th>$/hr</th><th>Run cost</th><th>Tok/W</th></tr>
+{{range .Results}}<tr><td>{{when .Timestamp}}</td><td>{{.Model}}</td><td>{{gpu .GPUName .Runtime .Quantization}}</td><td>{{.Provider}}</td><td class="num">{{rate .Throughput .ThroughputUnit}}</td><td class="{{.Trend}}">{{call $.TrendLabel .}}</td><td class="num">{{f0 .AvgLatencyMs}} ms</td><td class="num">{{f0 .P95LatencyMs}} ms</td><td class="num">{{pct .ErrorRate}}</td><td class="num">{{usd .PricePerHour}}</td><td class="num">{{usd4 .RunCost}}</td><td class="num">{{tpw .TokensPerWatt}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,177 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportEntries() []HistoryEntry {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []*BenchmarkResult{
+		historyResult("r1", "llama3.1:8b", "RTX 4090", "vastai", base, 100),
+		historyResult("r2", "llama3.1:8b", "RTX 4090", "vastai", base.AddDate(0, 0, 1), 120),
+		historyResult("r3", "llama3.1:8b", "RTX 3090", "tensordock", base.AddDate(0, 0, 2), 80),
+		historyResult("r4", "llama3.1:8b", "A100", "tensordock", base.AddDate(0, 0, 3), 300),
+	}
+	results[2].PricePerHour = 0.10
+	results[3].Results.ErrorRate = 0.5
+	results[1].ConcurrencyLevels = []ConcurrencyLevel{
+		{Concurrency: 1, TokensPerSecond: 40, P99TTFTMs: 80},
+		{Concurrency: 4, TokensPerSecond: 150, P99TTFTMs: 120},
+		{Concurrency: 8, TokensPerSecond: 155, P99TTFTMs: 400},
+	}
+	for i, watts := range []float64{400, 400, 350} {
+		results[i].GPUStats.AvgPowerDrawW = watts
+		ApplyEnergyMetrics(results[i])
+	}
+	return BuildHistory(results)
+}
+
+func TestBuildReport(t *testing.T) {
+	report := BuildReport(reportEntries(), time.Now())
+
+	s := report.Summary
+	assert.Equal(t, 4, s.Runs)
+	assert.Equal(t, 1, s.Models)
+	assert.Equal(t, 3, s.GPUs)
+	assert.Equal(t, 2, s.Providers)
+	assert.Equal(t, 300.0, s.MaxTokensPerSecond)
+	assert.InDelta(t, 150, s.AvgTokensPerSecond, 0.001)
+
+	// The failing A100 run counts toward totals but not recommendations
+	require.Len(t, report.Hardware, 2)
+	assert.Equal(t, "RTX 4090", report.Hardware[0].GPUName)
+	assert.Equal(t, 2, report.Hardware[0].Runs)
+	assert.InDelta(t, 110, report.Hardware[0].AvgTokensPerSecond, 0.001)
+
+	require.Len(t, report.Recommendations, 1)
+	rec := report.Recommendations[0]
+	assert.Equal(t, "RTX 4090", rec.Fastest.GPUName)
+	assert.Equal(t, "RTX 3090", rec.BestValue.GPUName)
+	// 4090 averages 0.275 tok/W against the 3090's 80/350
+	require.NotNil(t, rec.MostEfficient)
+	assert.Equal(t, "RTX 4090", rec.MostEfficient.GPUName)
+	assert.InDelta(t, 400, report.Hardware[0].AvgPowerW, 0.001)
+	assert.InDelta(t, 0.275, report.Hardware[0].TokensPerWatt, 0.0001)
+	assert.InDelta(t, (0.25+0.3+80.0/350)/3, s.AvgTokensPerWatt, 0.0001, "runs without power are left out")
+
+	// 0.36/hr for three 10-minute runs plus 0.10/hr for one
+	assert.InDelta(t, 0.18+0.10/6, report.Cost.TotalRunCost, 0.0001)
+	require.Len(t, report.Cost.ByProvider, 2)
+	assert.Equal(t, "vastai", report.Cost.ByProvider[0].Provider)
+
+	require.Len(t, report.Saturation, 1)
+	curve := report.Saturation[0]
+	assert.Equal(t, "r2", curve.BenchmarkID)
+	assert.Len(t, curve.Levels, 3)
+	assert.Equal(t, 4, curve.SaturationConcurrency, "150 tok/s is within 95% of the 155 peak")
+}
+
+func TestBuildReport_Quantization(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []*BenchmarkResult{
+		historyResult("q1", "mistral-7b", "A6000", "vastai", base, 60),
+		historyResult("q2", "mistral-7b", "RTX 4090", "vastai", base.AddDate(0, 0, 1), 90),
+		historyResult("q3", "mistral-7b", "RTX 4090", "vastai", base.AddDate(0, 0, 2), 50),
+	}
+	results[0].Model.Quantization = "fp16"
+	results[1].Model.Quantization = "awq"
+	results[2].Model.Quantization = "fp16"
+	entries := BuildHistory(results)
+	assert.Empty(t, entries[2].Trend, "fp16 is not compared with the awq run")
+
+	report := BuildReport(entries, time.Now())
+	require.Len(t, report.Hardware, 3)
+	require.Len(t, report.Recommendations, 1)
+	assert.Equal(t, "RTX 4090", report.Recommendations[0].Fastest.GPUName)
+	assert.Equal(t, "awq", report.Recommendations[0].Fastest.Quantization)
+
+	var md bytes.Buffer
+	require.NoError(t, report.Render(&md, ReportMarkdown))
+	assert.Contains(t, md.String(), "| mistral-7b | RTX 4090 + AWQ | 90.0 tok/s |")
+	assert.Contains(t, md.String(), "| A6000 + FP16 |")
+}
+
+func TestBuildReport_Runtimes(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []*BenchmarkResult{
+		historyResult("b1", "llama3.1:8b", "RTX 4090", "vastai", base, 80),
+		historyResult("b2", "meta-llama/Llama-3.1-8B-Instruct", "RTX 4090", "vastai", base.AddDate(0, 0, 1), 100),
+		historyResult("b3", "meta-llama/Llama-3.1-8B-Instruct", "RTX 4090", "vastai", base.AddDate(0, 0, 2), 130),
+	}
+	results[0].Model.Runtime = "ollama"
+	results[1].Model.Runtime = "vllm"
+	results[2].Model.Runtime = "sglang"
+	entries := BuildHistory(results)
+	assert.Equal(t, "sglang", entries[2].Runtime)
+	assert.Empty(t, entries[2].Trend, "sglang is not compared with the vllm run")
+
+	report := BuildReport(entries, time.Now())
+	require.Len(t, report.Hardware, 3)
+
+	var md bytes.Buffer
+	require.NoError(t, report.Render(&md, ReportMarkdown))
+	assert.Contains(t, md.String(), "| meta-llama/Llama-3.1-8B-Instruct | RTX 4090 (sglang) | 130.0 tok/s |")
+	assert.Contains(t, md.String(), "| RTX 4090 (vllm) |")
+	assert.Contains(t, md.String(), "| llama3.1:8b | RTX 4090 | 80.0 tok/s |", "ollama is the default and left out")
+}
+
+func TestReportRender(t *testing.T) {
+	report := BuildReport(reportEntries(), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	report.Scope.Model = "llama"
+
+	var md bytes.Buffer
+	require.NoError(t, report.Render(&md, ReportMarkdown))
+	assert.Contains(t, md.String(), "# Benchmark Report")
+	assert.Contains(t, md.String(), "for model llama")
+	assert.Contains(t, md.String(), "| llama3.1:8b | RTX 4090 | 110.0 tok/s | RTX 3090 |")
+	assert.Contains(t, md.String(), "↑ +20.0%")
+	assert.Contains(t, md.String(), "| 400 W | 0.275 |")
+	assert.Contains(t, md.String(), "### llama3.1:8b on RTX 4090")
+	assert.Contains(t, md.String(), "saturates at concurrency 4")
+	assert.Contains(t, md.String(), "| 8 | 155.0 |")
+
+	var js bytes.Buffer
+	require.NoError(t, report.Render(&js, ReportJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, 4, decoded.Summary.Runs)
+	assert.Len(t, decoded.Results, 4)
+
+	var html bytes.Buffer
+	require.NoError(t, report.Render(&html, ReportHTML))
+	assert.Contains(t, html.String(), "<!DOCTYPE html>")
+	assert.Contains(t, html.String(), "<svg")
+	assert.Contains(t, html.String(), "Throughput by GPU")
+	assert.Contains(t, html.String(), "Energy efficiency")
+	assert.Contains(t, html.String(), "<polyline")
+	assert.NotContains(t, html.String(), "<script", "report is static")
+
+	var csv bytes.Buffer
+	require.NoError(t, report.Render(&csv, ReportCSV))
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	require.Len(t, lines, 5, "header plus one row per run")
+	assert.True(t, strings.HasPrefix(lines[0], "timestamp,id,model,gpu_name"))
+	assert.Contains(t, lines[2], "2026-03-02T12:00:00Z,r2,llama3.1:8b,RTX 4090,1,vastai,,120.00")
+	assert.True(t, strings.HasSuffix(lines[2], ",400.0,0.3000,up,20.00,,chat,120.00,tok/s,"))
+
+	var empty bytes.Buffer
+	require.NoError(t, BuildReport(nil, time.Now()).Render(&empty, ReportHTML))
+	assert.Contains(t, empty.String(), "No benchmark runs found")
+}
+
+func TestParseReportFormat(t *testing.T) {
+	for in, want := range map[string]ReportFormat{"": ReportMarkdown, "md": ReportMarkdown, "JSON": ReportJSON, "html": ReportHTML, "csv": ReportCSV} {
+		got, err := ParseReportFormat(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseReportFormat("pdf")
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -93,7 +94,20 @@ func (s *Store) migrate() error {
 		CREATE INDEX IF NOT EXISTS idx_benchmarks_gpu ON benchmarks(gpu_name);
 		CREATE INDEX IF NOT EXISTS idx_benchmarks_timestamp ON benchmarks(timestamp);
 	`)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Idempotent column additions for tables created by older schema
+	alters := []string{
+		"ALTER TABLE benchmarks ADD COLUMN tokens_per_watt REAL",
+		"ALTER TABLE benchmarks ADD COLUMN workload TEXT",
+		"ALTER TABLE benchmarks ADD COLUMN throughput REAL",
+	}
+	for _, stmt := range alters {
+		_, _ = s.db.Exec(stmt) // Ignore "duplicate column" errors
+	}
+	return nil
 }
 
 // Save stores a benchmark result.
@@ -104,6 +118,7 @@ func (s *Store) Save(ctx context.Context, result *BenchmarkResult) error {
 	if result.Timestamp.IsZero() {
 		result.Timestamp = time.Now()
 	}
+	ApplyEnergyMetrics(result)
 
 	fullJSON, err := json.Marshal(result)
 	if err != nil {
@@ -123,8 +138,9 @@ func (s *Store) Save(ctx context.Context, result *BenchmarkResult) error {
 			p50_tokens_per_second, p95_tokens_per_second, p99_tokens_per_second,
 			avg_latency_ms, p95_latency_ms, requests_per_minute,
 			avg_gpu_util, max_gpu_util, avg_gpu_temp, max_gpu_temp,
-			avg_power_draw, max_memory_used_mib,
+			avg_power_draw, max_memory_used_mib, tokens_per_watt,
 			provider, location, price_per_hour,
+			workload, throughput,
 			full_result_json
 		) VALUES (
 			?, ?,
@@ -138,8 +154,9 @@ func (s *Store) Save(ctx context.Context, result *BenchmarkResult) error {
 			?, ?, ?,
 			?, ?, ?,
 			?, ?, ?, ?,
-			?, ?,
 			?, ?, ?,
+			?, ?, ?,
+			?, ?,
 			?
 		)
 	`,
@@ -158,8 +175,9 @@ func (s *Store) Save(ctx context.Context, result *BenchmarkResult) error {
 		result.Results.AvgLatencyMs, result.Results.P95LatencyMs, result.Results.RequestsPerMinute,
 		result.GPUStats.AvgUtilizationPct, result.GPUStats.MaxUtilizationPct,
 		result.GPUStats.AvgTemperatureC, result.GPUStats.MaxTemperatureC,
-		result.GPUStats.AvgPowerDrawW, result.GPUStats.MaxMemoryUsedMiB,
+		result.GPUStats.AvgPowerDrawW, result.GPUStats.MaxMemoryUsedMiB, result.Results.TokensPerWatt,
 		result.Provider, result.Location, result.PricePerHour,
+		string(WorkloadOf(result)), result.Results.Throughput,
 		string(fullJSON),
 	)
 	return err
@@ -182,6 +200,7 @@ func (s *Store) Get(ctx context.Context, id string) (*BenchmarkResult, error) {
 	if err := json.Unmarshal([]byte(fullJSON), &result); err != nil {
 		return nil, err
 	}
+	ApplyEnergyMetrics(&result)
 	return &result, nil
 }
 
@@ -240,6 +259,39 @@ func (s *Store) GetCheapestForModel(ctx context.Context, modelName string, minTP
 	return results[0], nil
 }
 
+// ThroughputByGPU returns the average tokens per second of each benchmarked
+// GPU, keyed by GPU name. Runs with 10% or more errors are ignored. If
+// modelName is set, only benchmarks of that model are included.
+func (s *Store) ThroughputByGPU(ctx context.Context, modelName string) (map[string]float64, error) {
+	query := `
+		SELECT gpu_name, AVG(avg_tokens_per_second)
+		FROM benchmarks
+		WHERE total_errors < total_requests * 0.1 AND avg_tokens_per_second > 0`
+	var args []interface{}
+	if modelName != "" {
+		query += " AND model_name = ?"
+		args = append(args, modelName)
+	}
+	query += " GROUP BY gpu_name"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	throughput := make(map[string]float64)
+	for rows.Next() {
+		var gpuName string
+		var avgTPS float64
+		if err := rows.Scan(&gpuName, &avgTPS); err != nil {
+			return nil, err
+		}
+		throughput[gpuName] = avgTPS
+	}
+	return throughput, rows.Err()
+}
+
 // query is a helper to run a query and parse results.
 func (s *Store) query(ctx context.Context, query string, args ...interface{}) ([]*BenchmarkResult, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -258,6 +310,7 @@ func (s *Store) query(ctx context.Context, query string, args ...interface{}) ([
 		if err := json.Unmarshal([]byte(fullJSON), &result); err != nil {
 			return nil, err
 		}
+		ApplyEnergyMetrics(&result) // Results stored before power metrics existed
 		results = append(results, &result)
 	}
 	return results, rows.Err()
@@ -268,14 +321,18 @@ func (s *Store) GetModelRecommendations(ctx context.Context, modelName string) (
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT
 			gpu_name,
+			COALESCE(quantization, '') as quant,
+			runtime,
+			COALESCE(workload, 'chat') as kind,
 			gpu_memory_mib,
 			AVG(avg_tokens_per_second) as avg_tps,
+			AVG(COALESCE(throughput, 0)) as avg_throughput,
 			AVG(price_per_hour) as avg_price,
 			COUNT(*) as sample_count
 		FROM benchmarks
 		WHERE model_name = ? AND total_errors < total_requests * 0.1
-		GROUP BY gpu_name
-		ORDER BY avg_tps DESC
+		GROUP BY gpu_name, runtime, quant, kind
+		ORDER BY CASE WHEN avg_throughput > 0 THEN avg_throughput ELSE avg_tps END DESC
 	`, modelName)
 	if err != nil {
 		return nil, err
@@ -284,21 +341,33 @@ func (s *Store) GetModelRecommendations(ctx context.Context, modelName string) (
 
 	var recs []HardwareRecommendation
 	for rows.Next() {
-		var gpuName string
+		var gpuName, quant, runtime, kind string
 		var gpuMemory int
-		var avgTPS, avgPrice float64
+		var avgTPS, avgThroughput, avgPrice float64
 		var sampleCount int
-		if err := rows.Scan(&gpuName, &gpuMemory, &avgTPS, &avgPrice, &sampleCount); err != nil {
+		if err := rows.Scan(&gpuName, &quant, &runtime, &kind, &gpuMemory, &avgTPS, &avgThroughput, &avgPrice, &sampleCount); err != nil {
 			return nil, err
 		}
-		recs = append(recs, HardwareRecommendation{
+		notes := fmt.Sprintf("Based on %d benchmark(s)", sampleCount)
+		if variant := strings.TrimSpace(runtime + " " + quant); variant != "" {
+			notes = fmt.Sprintf("Based on %d %s benchmark(s)", sampleCount, variant)
+		}
+		rec := HardwareRecommendation{
 			Model:           modelName,
+			Workload:        Workload(kind),
 			MinVRAMGiB:      gpuMemory / 1024,
 			RecommendedGPUs: []string{gpuName},
+			Quantization:    quant,
+			Runtime:         runtime,
 			ExpectedTPS:     avgTPS,
 			EstimatedCost:   avgPrice,
-			Notes:           fmt.Sprintf("Based on %d benchmark(s)", sampleCount),
-		})
+			Notes:           notes,
+		}
+		if spec, err := LookupWorkload(kind); err == nil && spec.Workload != WorkloadChat {
+			rec.ExpectedThroughput = avgThroughput
+			rec.ThroughputUnit = spec.RateUnit()
+		}
+		recs = append(recs, rec)
 	}
 	return recs, rows.Err()
 }
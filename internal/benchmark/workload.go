@@ -0,0 +1,282 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Workload is the kind of inference a benchmark measures
+type Workload string
+
+const (
+	WorkloadChat          Workload = "chat"          // Streaming chat completions
+	WorkloadEmbeddings    Workload = "embeddings"    // Text embeddings
+	WorkloadTranscription Workload = "transcription" // Speech to text (Whisper)
+	WorkloadImage         Workload = "image"         // Image generation (Stable Diffusion)
+)
+
+// WorkloadSpec describes how a workload is benchmarked against an
+// OpenAI-compatible server and what its throughput counts
+type WorkloadSpec struct {
+	Workload      Workload `json:"workload"`
+	Path          string   `json:"path"`           // Route requests are sent to
+	Unit          string   `json:"unit"`           // What throughput counts, e.g. "images"
+	ExampleModels []string `json:"example_models"` // Commonly served models
+}
+
+// RateUnit is the throughput unit, e.g. "images/s"
+func (s WorkloadSpec) RateUnit() string {
+	return s.Unit + "/s"
+}
+
+// Workloads is the catalog of benchmarkable workloads. Chat throughput is
+// AvgTokensPerSecond; the others report PerformanceResults.Throughput.
+var Workloads = []WorkloadSpec{
+	{
+		Workload:      WorkloadChat,
+		Path:          "/v1/chat/completions",
+		Unit:          "tok",
+		ExampleModels: []string{"meta-llama/Llama-3.1-8B-Instruct", "Qwen/Qwen2.5-7B-Instruct"},
+	},
+	{
+		Workload:      WorkloadEmbeddings,
+		Path:          "/v1/embeddings",
+		Unit:          "inputs",
+		ExampleModels: []string{"BAAI/bge-large-en-v1.5", "intfloat/e5-mistral-7b-instruct"},
+	},
+	{
+		Workload:      WorkloadTranscription,
+		Path:          "/v1/audio/transcriptions",
+		Unit:          "audio s",
+		ExampleModels: []string{"openai/whisper-large-v3", "openai/whisper-large-v3-turbo"},
+	},
+	{
+		Workload:      WorkloadImage,
+		Path:          "/v1/images/generations",
+		Unit:          "images",
+		ExampleModels: []string{"stabilityai/stable-diffusion-xl-base-1.0", "stabilityai/sdxl-turbo"},
+	},
+}
+
+// LookupWorkload returns the catalog entry for name; empty means chat
+func LookupWorkload(name string) (WorkloadSpec, error) {
+	if name == "" {
+		name = string(WorkloadChat)
+	}
+	for _, s := range Workloads {
+		if string(s.Workload) == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+	return WorkloadSpec{}, fmt.Errorf("unknown workload %q (expected chat, embeddings, transcription or image)", name)
+}
+
+// WorkloadOf returns the workload a result measured. Results stored before
+// workloads were recorded are chat.
+func WorkloadOf(r *BenchmarkResult) Workload {
+	if r.Workload == "" {
+		return WorkloadChat
+	}
+	return r.Workload
+}
+
+// Request shapes for the non-chat workloads
+const (
+	embeddingBatchSize       = 16
+	transcriptionClipSeconds = 30
+	imageSize                = "512x512"
+)
+
+// embeddingInputs are batched into each embeddings request
+var embeddingInputs = []string{
+	"GPUs run thousands of threads in parallel, which suits matrix multiplication.",
+	"The quarterly report shows revenue growth of 12% driven by cloud services.",
+	"To reset your password, open settings and choose 'Forgot password'.",
+	"Transformers replace recurrence with attention over the whole sequence.",
+	"The hiking trail climbs 800 metres through pine forest to a glacial lake.",
+	"Add the flour gradually and knead the dough for ten minutes until smooth.",
+	"Kubernetes schedules pods onto nodes according to resource requests.",
+	"The defendant's appeal was dismissed on procedural grounds.",
+}
+
+const imagePrompt = "A lighthouse on a rocky coast at sunset, oil painting, detailed"
+
+// sendRequest performs request i of a benchmark. The timing's units are the
+// work done, counted in the workload's unit.
+func (cfg EndpointConfig) sendRequest(ctx context.Context, base, model string, i int) (RequestResult, requestTiming) {
+	switch cfg.Workload {
+	case WorkloadEmbeddings:
+		return embedTexts(ctx, cfg.Client, base, model, i)
+	case WorkloadTranscription:
+		return transcribeClip(ctx, cfg.Client, base, model)
+	case WorkloadImage:
+		return generateImage(ctx, cfg.Client, base, model)
+	default:
+		prompt := endpointPrompts[i%len(endpointPrompts)].prompt
+		result, timing := streamCompletion(ctx, cfg.Client, base, model, prompt, cfg.MaxTokens)
+		timing.units = float64(result.Tokens)
+		return result, timing
+	}
+}
+
+// workRate is the units completed per wall-clock second
+func workRate(timings []requestTiming, elapsed float64) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	var units float64
+	for _, t := range timings {
+		units += t.units
+	}
+	return units / elapsed
+}
+
+// postJSON sends body to base+path and decodes a 200 response into out.
+// The returned result has its duration set and is marked failed on error.
+func postJSON(ctx context.Context, client *http.Client, url, contentType string, body io.Reader, out interface{}) RequestResult {
+	start := time.Now()
+	result := RequestResult{Timestamp: start.Unix()}
+	err := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", contentType)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		return nil
+	}()
+	result.DurationSec = time.Since(start).Seconds()
+	if err != nil {
+		result.Error = true
+		result.ErrorMsg = err.Error()
+	}
+	return result
+}
+
+// embedTexts embeds a batch of embeddingBatchSize inputs
+func embedTexts(ctx context.Context, client *http.Client, base, model string, i int) (RequestResult, requestTiming) {
+	inputs := make([]string, embeddingBatchSize)
+	for j := range inputs {
+		inputs[j] = embeddingInputs[(i+j)%len(embeddingInputs)]
+	}
+	body, _ := json.Marshal(map[string]interface{}{"model": model, "input": inputs})
+
+	var resp struct {
+		Data []struct {
+			Embedding json.RawMessage `json:"embedding"`
+		} `json:"data"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+		} `json:"usage"`
+	}
+	result := postJSON(ctx, client, base+"/v1/embeddings", "application/json", bytes.NewReader(body), &resp)
+	if result.Error {
+		return result, requestTiming{}
+	}
+	if len(resp.Data) == 0 {
+		result.Error, result.ErrorMsg = true, "no embeddings returned"
+		return result, requestTiming{}
+	}
+	result.Tokens = resp.Usage.PromptTokens
+	if result.DurationSec > 0 {
+		result.TokensPerSec = float64(result.Tokens) / result.DurationSec
+	}
+	return result, requestTiming{units: float64(len(resp.Data))}
+}
+
+// transcribeClip uploads the synthetic clip for transcription
+func transcribeClip(ctx context.Context, client *http.Client, base, model string) (RequestResult, requestTiming) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("model", model)
+	form.WriteField("response_format", "json")
+	part, _ := form.CreateFormFile("file", "benchmark.wav")
+	part.Write(benchmarkClip())
+	form.Close()
+
+	var resp struct {
+		Text string `json:"text"`
+	}
+	result := postJSON(ctx, client, base+"/v1/audio/transcriptions", form.FormDataContentType(), &body, &resp)
+	if result.Error {
+		return result, requestTiming{}
+	}
+	return result, requestTiming{units: transcriptionClipSeconds}
+}
+
+// generateImage requests one image
+func generateImage(ctx context.Context, client *http.Client, base, model string) (RequestResult, requestTiming) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"model":           model,
+		"prompt":          imagePrompt,
+		"n":               1,
+		"size":            imageSize,
+		"response_format": "b64_json",
+	})
+	var resp struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	result := postJSON(ctx, client, base+"/v1/images/generations", "application/json", bytes.NewReader(body), &resp)
+	if result.Error {
+		return result, requestTiming{}
+	}
+	if len(resp.Data) == 0 {
+		result.Error, result.ErrorMsg = true, "no images returned"
+		return result, requestTiming{}
+	}
+	return result, requestTiming{units: float64(len(resp.Data))}
+}
+
+var (
+	clipOnce sync.Once
+	clip     []byte
+)
+
+// benchmarkClip returns a transcriptionClipSeconds 16 kHz mono WAV of
+// tones that rise and fall like speech. The content is synthetic, so
+// transcription figures compare GPUs rather than predict real-world speed.
+func benchmarkClip() []byte {
+	clipOnce.Do(func() {
+		const rate = 16000
+		samples := rate * transcriptionClipSeconds
+		var buf bytes.Buffer
+		buf.WriteString("RIFF")
+		binary.Write(&buf, binary.LittleEndian, uint32(36+samples*2))
+		buf.WriteString("WAVEfmt ")
+		binary.Write(&buf, binary.LittleEndian, []uint32{16})
+		binary.Write(&buf, binary.LittleEndian, []uint16{1, 1}) // PCM, mono
+		binary.Write(&buf, binary.LittleEndian, []uint32{rate, rate * 2})
+		binary.Write(&buf, binary.LittleEndian, []uint16{2, 16})
+		buf.WriteString("data")
+		binary.Write(&buf, binary.LittleEndian, uint32(samples*2))
+		for n := 0; n < samples; n++ {
+			t := float64(n) / rate
+			pitch := 160 + 60*math.Sin(2*math.Pi*0.7*t)
+			envelope := math.Abs(math.Sin(2 * math.Pi * 2.5 * t)) // ~5 syllables a second
+			binary.Write(&buf, binary.LittleEndian, int16(8000*envelope*math.Sin(2*math.Pi*pitch*t)))
+		}
+		clip = buf.Bytes()
+	})
+	return clip
+}
@@ -0,0 +1,155 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInferenceServer answers the embeddings, transcription and image
+// generation routes of an OpenAI-compatible server
+func fakeInferenceServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/embeddings":
+			var req struct {
+				Input []string `json:"input"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			data := strings.TrimSuffix(strings.Repeat(`{"embedding":[0.1,0.2]},`, len(req.Input)), ",")
+			fmt.Fprintf(w, `{"data":[%s],"usage":{"prompt_tokens":%d}}`, data, len(req.Input)*12)
+		case "/v1/audio/transcriptions":
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			file.Close()
+			assert.Equal(t, "openai/whisper-large-v3", r.FormValue("model"))
+			assert.Greater(t, header.Size, int64(transcriptionClipSeconds*16000*2))
+			fmt.Fprint(w, `{"text":"hello"}`)
+		case "/v1/images/generations":
+			fmt.Fprint(w, `{"data":[{"b64_json":"aGVsbG8="}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBenchmarkEndpoint_Workloads(t *testing.T) {
+	srv := fakeInferenceServer(t)
+
+	tests := []struct {
+		workload Workload
+		model    string
+		unit     string
+	}{
+		{WorkloadEmbeddings, "BAAI/bge-large-en-v1.5", "inputs/s"},
+		{WorkloadTranscription, "openai/whisper-large-v3", "audio s/s"},
+		{WorkloadImage, "stabilityai/sdxl-turbo", "images/s"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.workload), func(t *testing.T) {
+			run, err := BenchmarkEndpoint(context.Background(), EndpointConfig{
+				BaseURL:  srv.URL,
+				Model:    tt.model,
+				Workload: tt.workload,
+				Requests: 4,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.workload, run.Workload)
+			assert.Zero(t, run.Results.TotalErrors)
+			assert.Greater(t, run.Results.Throughput, 0.0)
+			assert.Equal(t, tt.unit, run.Results.ThroughputUnit)
+			assert.Zero(t, run.Results.AvgTTFTMs, "only chat streams")
+		})
+	}
+
+	// Embeddings also count the prompt tokens they processed
+	run, err := BenchmarkEndpoint(context.Background(), EndpointConfig{
+		BaseURL:           srv.URL,
+		Model:             "BAAI/bge-large-en-v1.5",
+		Workload:          WorkloadEmbeddings,
+		Requests:          2,
+		ConcurrencyLevels: []int{1, 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*embeddingBatchSize*12, run.Results.TotalTokens)
+	require.Len(t, run.ConcurrencyLevels, 2)
+	assert.Greater(t, run.ConcurrencyLevels[1].Throughput, 0.0)
+
+	_, err = BenchmarkEndpoint(context.Background(), EndpointConfig{BaseURL: srv.URL, Model: "m", Workload: "video"})
+	assert.ErrorContains(t, err, "unknown workload")
+}
+
+func TestLookupWorkload(t *testing.T) {
+	spec, err := LookupWorkload("")
+	require.NoError(t, err)
+	assert.Equal(t, WorkloadChat, spec.Workload)
+	assert.Equal(t, "tok/s", spec.RateUnit())
+
+	spec, err = LookupWorkload("Image")
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/images/generations", spec.Path)
+
+	_, err = LookupWorkload("video")
+	assert.Error(t, err)
+}
+
+func TestBuildReport_NonChatWorkload(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var results []*BenchmarkResult
+	for i, gpu := range []string{"RTX 4090", "L40S"} {
+		r := historyResult(fmt.Sprintf("sd%d", i), "sdxl-turbo", gpu, "vastai", base, 0)
+		r.Workload = WorkloadImage
+		r.Results.Throughput = float64(2 + i) // images/s
+		r.Results.ThroughputUnit = "images/s"
+		results = append(results, r)
+	}
+	results[1].PricePerHour = 1.08
+
+	report := BuildReport(BuildHistory(results), base)
+	require.Len(t, report.Recommendations, 1)
+	rec := report.Recommendations[0]
+	assert.Equal(t, "L40S", rec.Fastest.GPUName)
+	assert.Equal(t, "RTX 4090", rec.BestValue.GPUName, "$0.36/hr at 2 images/s beats $1.08/hr at 3")
+	assert.InDelta(t, 0.05, rec.BestValue.CostPerThousand, 0.0001)
+
+	var md strings.Builder
+	require.NoError(t, report.Render(&md, ReportMarkdown))
+	assert.Contains(t, md.String(), "| sdxl-turbo | L40S | 3.00 images/s | RTX 4090 | $0.050/1K images |")
+}
+
+func TestStore_GetModelRecommendations_Workload(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i, gpu := range []string{"RTX 4090", "L40S"} {
+		r := historyResult(fmt.Sprintf("w%d", i), "openai/whisper-large-v3", gpu, "vastai", time.Now(), 0)
+		r.Workload = WorkloadTranscription
+		r.Results.TotalRequests = 20
+		r.Results.Throughput = float64(40 + i*30)
+		require.NoError(t, store.Save(ctx, r))
+	}
+
+	recs, err := store.GetModelRecommendations(ctx, "openai/whisper-large-v3")
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	assert.Equal(t, "L40S", recs[0].RecommendedGPUs[0], "ranked by throughput, not tokens")
+	assert.Equal(t, WorkloadTranscription, recs[0].Workload)
+	assert.InDelta(t, 70, recs[0].ExpectedThroughput, 0.001)
+	assert.Equal(t, "audio s/s", recs[0].ThroughputUnit)
+}
@@ -1,12 +1,18 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
 // Config holds all application configuration
@@ -17,18 +23,40 @@ type Config struct {
 	Inventory InventoryConfig `mapstructure:"inventory"`
 	Lifecycle LifecycleConfig `mapstructure:"lifecycle"`
 	SSH       SSHConfig       `mapstructure:"ssh"`
+	Budget    BudgetConfig    `mapstructure:"budget"`
+	Webhooks  WebhooksConfig  `mapstructure:"webhooks"`
+	Benchmark BenchmarkConfig `mapstructure:"benchmark"`
 	Logging   LoggingConfig   `mapstructure:"logging"`
+	Secrets   SecretsConfig   `mapstructure:"secrets"`
+	Policy    PolicyConfig    `mapstructure:"policy"`
+	Workloads WorkloadsConfig `mapstructure:"workloads"`
+
+	Workspaces WorkspacesConfig `mapstructure:"workspaces"`
+	ModelCache ModelCacheConfig `mapstructure:"model_cache"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Host        string `mapstructure:"host"`
+	Port        int    `mapstructure:"port"`
+	AdminAPIKey string `mapstructure:"admin_api_key"` // Enables /api/v1/admin when set
+	APIKeys     string `mapstructure:"api_keys"`      // "key:role,..." enables role-based access control
+
+	// Per API key (or client IP) rate limits; zero disables
+	RateLimitRPS               float64 `mapstructure:"rate_limit_rps"`
+	RateLimitBurst             int     `mapstructure:"rate_limit_burst"`
+	CreateSessionRatePerMinute float64 `mapstructure:"create_session_rate_per_minute"`
+	CreateSessionBurst         int     `mapstructure:"create_session_burst"`
+
+	// Comma-separated reverse proxy IPs or CIDRs trusted to report the
+	// client IP in X-Forwarded-For; none by default
+	TrustedProxies string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Path string `mapstructure:"path"`
+	Path          string `mapstructure:"path"`
+	EncryptionKey string `mapstructure:"encryption_key"` // Base64 32-byte master key; enables encryption of secret columns
 }
 
 // ProvidersConfig holds configuration for GPU providers
@@ -36,6 +64,23 @@ type ProvidersConfig struct {
 	VastAI      VastAIConfig      `mapstructure:"vastai"`
 	BlueLobster BlueLobsterConfig `mapstructure:"bluelobster"`
 	TensorDock  TensorDockConfig  `mapstructure:"tensordock"`
+	Static      StaticConfig      `mapstructure:"static"`
+	Offline     bool              `mapstructure:"offline"` // Use only the static catalog; live provider APIs are never called
+	Retry       ProviderRetry     `mapstructure:"retry"`
+
+	// MaxConcurrentCreates caps instance creations in flight per provider
+	// (0 = unlimited); requests over it queue for up to CreateQueueTimeout
+	MaxConcurrentCreates int           `mapstructure:"max_concurrent_creates"`
+	CreateQueueTimeout   time.Duration `mapstructure:"create_queue_timeout"`
+}
+
+// ProviderRetry holds the retry policy for provider API calls that fail with
+// a server error, rate limit or timeout
+type ProviderRetry struct {
+	MaxAttempts int           `mapstructure:"max_attempts"` // Tries per call; 1 disables retries
+	BaseDelay   time.Duration `mapstructure:"base_delay"`   // Doubles after each failed attempt
+	MaxDelay    time.Duration `mapstructure:"max_delay"`
+	Jitter      float64       `mapstructure:"jitter"` // Share of each delay that is randomized, 0 to 1
 }
 
 // VastAIConfig holds Vast.ai specific configuration
@@ -57,6 +102,21 @@ type TensorDockConfig struct {
 	APIToken     string `mapstructure:"api_token"`
 	Enabled      bool   `mapstructure:"enabled"`
 	DefaultImage string `mapstructure:"default_image"` // Default OS image (e.g., "ubuntu2404")
+
+	// NativeSSHKeys relies on the create request's ssh_key field instead of
+	// installing the key with cloud-init runcmd
+	NativeSSHKeys bool `mapstructure:"native_ssh_keys"`
+
+	// MaxConcurrentCreates overrides providers.max_concurrent_creates, as
+	// TensorDock rate limits instance creation aggressively (0 = use the
+	// shared limit)
+	MaxConcurrentCreates int `mapstructure:"max_concurrent_creates"`
+}
+
+// StaticConfig holds configuration for the file-based static offer catalog
+type StaticConfig struct {
+	CatalogPath string `mapstructure:"catalog_path"` // JSON catalog of nodes; enables the provider when set
+	SSHKeyPath  string `mapstructure:"ssh_key_path"` // Admin private key used to grant session SSH keys on nodes
 }
 
 // InventoryConfig holds inventory cache configuration
@@ -64,6 +124,11 @@ type InventoryConfig struct {
 	DefaultCacheTTL    time.Duration `mapstructure:"default_cache_ttl"`
 	BackoffCacheTTL    time.Duration `mapstructure:"backoff_cache_ttl"`
 	TensorDockCacheTTL time.Duration `mapstructure:"tensordock_cache_ttl"` // Shorter TTL for volatile TensorDock inventory
+	BackgroundRefresh  bool          `mapstructure:"background_refresh"`   // Keep each provider's catalogue cached in the background
+
+	// Per-provider TTLs by provider name (config file only); these override
+	// DefaultCacheTTL and TensorDockCacheTTL
+	ProviderCacheTTLs map[string]time.Duration `mapstructure:"provider_cache_ttls"`
 }
 
 // LifecycleConfig holds lifecycle management configuration
@@ -76,12 +141,48 @@ type LifecycleConfig struct {
 	StartupSweepTimeout    time.Duration `mapstructure:"startup_sweep_timeout"`
 	ShutdownTimeout        time.Duration `mapstructure:"shutdown_timeout"`
 	DeploymentID           string        `mapstructure:"deployment_id"`
+
+	// MaxPausedDuration is how long a session can stay paused before it is
+	// destroyed; 0 keeps paused sessions until they are resumed or deleted
+	MaxPausedDuration time.Duration `mapstructure:"max_paused_duration"`
+
+	// LeaderElection makes replicas sharing a database elect one of
+	// themselves to run background services; all replicas serve the API
+	LeaderElection bool          `mapstructure:"leader_election"`
+	LeaderLeaseTTL time.Duration `mapstructure:"leader_lease_ttl"`
 }
 
 // SSHConfig holds SSH verification configuration
 type SSHConfig struct {
 	VerifyTimeout time.Duration `mapstructure:"verify_timeout"`
 	CheckInterval time.Duration `mapstructure:"check_interval"`
+
+	// BandwidthTestURL is downloaded by new instances to measure their
+	// download speed; empty skips the measurement
+	BandwidthTestURL string `mapstructure:"bandwidth_test_url"`
+
+	// KernelWatchInterval is how often running sessions' kernel logs are
+	// read for OOM kills and GPU errors; 0 disables it
+	KernelWatchInterval time.Duration `mapstructure:"kernel_watch_interval"`
+}
+
+// BudgetConfig holds budget enforcement configuration
+type BudgetConfig struct {
+	CheckInterval    time.Duration `mapstructure:"check_interval"`
+	WarningThreshold float64       `mapstructure:"warning_threshold"` // Fraction of limit (0.8 = 80%)
+	WebhookURL       string        `mapstructure:"webhook_url"`       // Optional: receives threshold alerts
+	SpendCeiling     float64       `mapstructure:"spend_ceiling"`     // Monthly provider-reported spend that terminates every session, 0 = off
+}
+
+// WebhooksConfig holds webhook notification delivery configuration
+type WebhooksConfig struct {
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // Doubles after each failed attempt
+}
+
+// BenchmarkConfig holds benchmark configuration
+type BenchmarkConfig struct {
+	CatalogPath string `mapstructure:"catalog_path"` // YAML model/GPU catalog merged over the built-in one
 }
 
 // LoggingConfig holds logging configuration
@@ -90,6 +191,73 @@ type LoggingConfig struct {
 	Format string `mapstructure:"format"` // "json" or "text"
 }
 
+// SecretsConfig holds the backends that secret references resolve against.
+// Credentials written as "vault:<path>#<field>" or "file:<key>" are replaced
+// by the secret when configuration is loaded.
+type SecretsConfig struct {
+	VaultAddr      string `mapstructure:"vault_addr"`
+	VaultToken     string `mapstructure:"vault_token"`
+	VaultMount     string `mapstructure:"vault_mount"` // KV version 2 engine mount
+	VaultNamespace string `mapstructure:"vault_namespace"`
+	File           string `mapstructure:"file"`            // Encrypted secrets file
+	FilePassphrase string `mapstructure:"file_passphrase"` // Decrypts File
+}
+
+// PolicyConfig holds per-consumer provisioning policy (config file only)
+type PolicyConfig struct {
+	// AllowedRegions limits where each consumer's sessions may run, keyed by
+	// consumer ID with "*" for consumers not listed. Values are region names
+	// ("EU", "EEA", "NA", "APAC") or ISO country codes.
+	AllowedRegions map[string][]string `mapstructure:"allowed_regions"`
+}
+
+// WorkloadsConfig holds settings for entrypoint-mode workloads (config file only)
+type WorkloadsConfig struct {
+	// ReadinessProbes sets how each workload type's API is judged ready once
+	// its health route answers, keyed by workload type ("vllm", "tgi",
+	// "sglang", "llamacpp", "custom"). Values are "health", "models" (the
+	// model is listed by /v1/models) or "completion" (a one-token completion
+	// succeeds).
+	ReadinessProbes map[string]string `mapstructure:"readiness_probes"`
+}
+
+// WorkspacesConfig holds the S3-compatible bucket that workspaces of
+// sessions with storage_policy "preserve" are saved to. Preserving
+// workspaces is off while Bucket is empty.
+type WorkspacesConfig struct {
+	Endpoint        string        `mapstructure:"endpoint"` // e.g. "https://s3.us-east-1.amazonaws.com"
+	Region          string        `mapstructure:"region"`
+	Bucket          string        `mapstructure:"bucket"`
+	Prefix          string        `mapstructure:"prefix"` // Key prefix of saved workspaces
+	AccessKeyID     string        `mapstructure:"access_key_id"`
+	SecretAccessKey string        `mapstructure:"secret_access_key"`
+	Path            string        `mapstructure:"path"`         // Directory saved on the instance
+	SaveTimeout     time.Duration `mapstructure:"save_timeout"` // Bounds saving a workspace before its instance is destroyed
+}
+
+// ModelCacheConfig holds the S3-compatible bucket that model weights of
+// SSH-mode sessions are pulled from, instead of Hugging Face. The model
+// cache is off while Bucket is empty.
+type ModelCacheConfig struct {
+	Endpoint        string        `mapstructure:"endpoint"` // e.g. "https://<account>.r2.cloudflarestorage.com"
+	Region          string        `mapstructure:"region"`
+	Bucket          string        `mapstructure:"bucket"`
+	Prefix          string        `mapstructure:"prefix"` // Key prefix of cached models, one directory per model ID
+	AccessKeyID     string        `mapstructure:"access_key_id"`
+	SecretAccessKey string        `mapstructure:"secret_access_key"`
+	Path            string        `mapstructure:"path"`         // Directory models are pulled into on the instance
+	PullTimeout     time.Duration `mapstructure:"pull_timeout"` // Bounds pulling a model's weights
+}
+
+// Valid workload types and readiness probes of workloads.readiness_probes
+var (
+	readinessWorkloadTypes = map[string]bool{"vllm": true, "tgi": true, "sglang": true, "llamacpp": true, "custom": true}
+	readinessProbes        = map[string]bool{"health": true, "models": true, "completion": true}
+)
+
+// secretResolveTimeout bounds resolving every secret reference at load
+const secretResolveTimeout = 30 * time.Second
+
 // Load loads configuration from file and environment
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
@@ -119,21 +287,38 @@ func Load(configPath string) (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
 
-// LoadFromEnv loads configuration primarily from environment variables
+// ConfigFileEnv names the environment variable holding the path of an
+// optional YAML or TOML config file
+const ConfigFileEnv = "CONFIG_FILE"
+
+// LoadFromEnv loads configuration primarily from environment variables.
+// Settings are layered, later ones winning: defaults, the config file named
+// by CONFIG_FILE, a .env file, then the environment.
 func LoadFromEnv() (*Config, error) {
 	v := viper.New()
 
 	// Set defaults
 	setDefaults(v)
 
+	// Read the config file if one is named; unlike .env it must exist
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
 	// Read from .env file if it exists
 	v.SetConfigFile(".env")
 	v.SetConfigType("env")
-	_ = v.ReadInConfig() // Ignore error if .env doesn't exist
+	_ = v.MergeInConfig() // Ignore error if .env doesn't exist
 
 	// Map flat .env keys (e.g., "vastai_api_key") to nested config paths
 	// (e.g., "providers.vastai.api_key"). BindEnv only reads os.Getenv(),
@@ -151,6 +336,9 @@ func LoadFromEnv() (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -159,6 +347,10 @@ func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.host", "0.0.0.0")
 	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.rate_limit_rps", 20)
+	v.SetDefault("server.rate_limit_burst", 40)
+	v.SetDefault("server.create_session_rate_per_minute", 10)
+	v.SetDefault("server.create_session_burst", 5)
 
 	// Database defaults
 	v.SetDefault("database.path", "./data/gpu-shopper.db")
@@ -169,11 +361,19 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("providers.bluelobster.default_template", "UBUNTU-22-04-NV")
 	v.SetDefault("providers.tensordock.enabled", true)
 	v.SetDefault("providers.tensordock.default_image", "ubuntu2204") // BUG-009: ubuntu2204 has better NVIDIA driver support
+	v.SetDefault("providers.tensordock.max_concurrent_creates", 2)
+	v.SetDefault("providers.retry.max_attempts", 3)
+	v.SetDefault("providers.retry.base_delay", 500*time.Millisecond)
+	v.SetDefault("providers.retry.max_delay", 5*time.Second)
+	v.SetDefault("providers.retry.jitter", 0.5)
+	v.SetDefault("providers.max_concurrent_creates", 10)
+	v.SetDefault("providers.create_queue_timeout", 30*time.Second)
 
 	// Inventory defaults
 	v.SetDefault("inventory.default_cache_ttl", time.Minute)
 	v.SetDefault("inventory.backoff_cache_ttl", 5*time.Minute)
 	v.SetDefault("inventory.tensordock_cache_ttl", 30*time.Second) // Shorter TTL for volatile TensorDock inventory
+	v.SetDefault("inventory.background_refresh", true)
 
 	// Lifecycle defaults
 	v.SetDefault("lifecycle.check_interval", time.Minute)
@@ -183,10 +383,36 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("lifecycle.startup_sweep_enabled", true)
 	v.SetDefault("lifecycle.startup_sweep_timeout", 2*time.Minute)
 	v.SetDefault("lifecycle.shutdown_timeout", 60*time.Second)
+	v.SetDefault("lifecycle.max_paused_duration", 24*time.Hour)
+	v.SetDefault("lifecycle.leader_election", false)
+	v.SetDefault("lifecycle.leader_lease_ttl", 30*time.Second)
 
 	// SSH verification defaults
 	v.SetDefault("ssh.verify_timeout", 10*time.Minute)
 	v.SetDefault("ssh.check_interval", 15*time.Second)
+	v.SetDefault("ssh.bandwidth_test_url", "")
+	v.SetDefault("ssh.kernel_watch_interval", time.Minute)
+
+	// Budget defaults
+	v.SetDefault("budget.check_interval", 5*time.Minute)
+	v.SetDefault("budget.warning_threshold", 0.80)
+
+	// Webhook defaults
+	v.SetDefault("webhooks.max_attempts", 6)
+	v.SetDefault("webhooks.retry_backoff", 30*time.Second)
+
+	// Secrets defaults
+	v.SetDefault("secrets.vault_mount", secrets.DefaultVaultMount)
+
+	// Workspace defaults
+	v.SetDefault("workspaces.prefix", "workspaces")
+	v.SetDefault("workspaces.path", "/workspace")
+	v.SetDefault("workspaces.save_timeout", 30*time.Minute)
+
+	// Model cache defaults
+	v.SetDefault("model_cache.prefix", "models")
+	v.SetDefault("model_cache.path", "/models")
+	v.SetDefault("model_cache.pull_timeout", 30*time.Minute)
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
@@ -200,17 +426,48 @@ func setDefaults(v *viper.Viper) {
 // but only if the nested key hasn't already been set (preserving explicit config).
 func mapEnvFileKeys(v *viper.Viper) {
 	mappings := map[string]string{
-		"vastai_api_key":           "providers.vastai.api_key",
-		"bluelobster_api_key":      "providers.bluelobster.api_key",
-		"tensordock_auth_id":       "providers.tensordock.auth_id",
-		"tensordock_api_token":     "providers.tensordock.api_token",
-		"tensordock_default_image": "providers.tensordock.default_image",
-		"database_path":            "database.path",
-		"server_host":              "server.host",
-		"server_port":              "server.port",
-		"log_level":                "logging.level",
-		"log_format":               "logging.format",
-		"deployment_id":            "lifecycle.deployment_id",
+		"vastai_api_key":                   "providers.vastai.api_key",
+		"bluelobster_api_key":              "providers.bluelobster.api_key",
+		"tensordock_auth_id":               "providers.tensordock.auth_id",
+		"tensordock_api_token":             "providers.tensordock.api_token",
+		"tensordock_default_image":         "providers.tensordock.default_image",
+		"tensordock_native_ssh_keys":       "providers.tensordock.native_ssh_keys",
+		"static_catalog_path":              "providers.static.catalog_path",
+		"static_ssh_key_path":              "providers.static.ssh_key_path",
+		"offline":                          "providers.offline",
+		"database_path":                    "database.path",
+		"database_encryption_key":          "database.encryption_key",
+		"server_host":                      "server.host",
+		"server_port":                      "server.port",
+		"admin_api_key":                    "server.admin_api_key",
+		"api_keys":                         "server.api_keys",
+		"log_level":                        "logging.level",
+		"log_format":                       "logging.format",
+		"deployment_id":                    "lifecycle.deployment_id",
+		"leader_election":                  "lifecycle.leader_election",
+		"leader_lease_ttl":                 "lifecycle.leader_lease_ttl",
+		"max_paused_duration":              "lifecycle.max_paused_duration",
+		"budget_webhook_url":               "budget.webhook_url",
+		"budget_spend_ceiling":             "budget.spend_ceiling",
+		"benchmark_catalog_path":           "benchmark.catalog_path",
+		"vault_addr":                       "secrets.vault_addr",
+		"vault_token":                      "secrets.vault_token",
+		"vault_mount":                      "secrets.vault_mount",
+		"vault_namespace":                  "secrets.vault_namespace",
+		"secrets_file":                     "secrets.file",
+		"secrets_passphrase":               "secrets.file_passphrase",
+		"workspace_s3_endpoint":            "workspaces.endpoint",
+		"workspace_s3_region":              "workspaces.region",
+		"workspace_s3_bucket":              "workspaces.bucket",
+		"workspace_s3_access_key_id":       "workspaces.access_key_id",
+		"workspace_s3_secret_access_key":   "workspaces.secret_access_key",
+		"workspace_path":                   "workspaces.path",
+		"model_cache_s3_endpoint":          "model_cache.endpoint",
+		"model_cache_s3_region":            "model_cache.region",
+		"model_cache_s3_bucket":            "model_cache.bucket",
+		"model_cache_s3_access_key_id":     "model_cache.access_key_id",
+		"model_cache_s3_secret_access_key": "model_cache.secret_access_key",
+		"model_cache_path":                 "model_cache.path",
 	}
 
 	for flatKey, nestedKey := range mappings {
@@ -237,24 +494,225 @@ func bindEnvVars(v *viper.Viper) {
 	bindEnv("providers.tensordock.auth_id", "TENSORDOCK_AUTH_ID")
 	bindEnv("providers.tensordock.api_token", "TENSORDOCK_API_TOKEN")
 	bindEnv("providers.tensordock.default_image", "TENSORDOCK_DEFAULT_IMAGE")
+	bindEnv("providers.tensordock.native_ssh_keys", "TENSORDOCK_NATIVE_SSH_KEYS")
+	bindEnv("providers.tensordock.max_concurrent_creates", "TENSORDOCK_MAX_CONCURRENT_CREATES")
+	bindEnv("providers.static.catalog_path", "STATIC_CATALOG_PATH")
+	bindEnv("providers.static.ssh_key_path", "STATIC_SSH_KEY_PATH")
+	bindEnv("providers.offline", "OFFLINE")
+	bindEnv("providers.retry.max_attempts", "PROVIDER_RETRY_MAX_ATTEMPTS")
+	bindEnv("providers.retry.base_delay", "PROVIDER_RETRY_BASE_DELAY")
+	bindEnv("providers.retry.max_delay", "PROVIDER_RETRY_MAX_DELAY")
+	bindEnv("providers.retry.jitter", "PROVIDER_RETRY_JITTER")
+	bindEnv("providers.max_concurrent_creates", "PROVIDER_MAX_CONCURRENT_CREATES")
+	bindEnv("providers.create_queue_timeout", "PROVIDER_CREATE_QUEUE_TIMEOUT")
 
 	// Database path
 	bindEnv("database.path", "DATABASE_PATH")
+	bindEnv("database.encryption_key", "DATABASE_ENCRYPTION_KEY")
 
 	// Server config
 	bindEnv("server.host", "SERVER_HOST")
 	bindEnv("server.port", "SERVER_PORT")
+	bindEnv("server.admin_api_key", "ADMIN_API_KEY")
+	bindEnv("server.api_keys", "API_KEYS")
+	bindEnv("server.rate_limit_rps", "RATE_LIMIT_RPS")
+	bindEnv("server.rate_limit_burst", "RATE_LIMIT_BURST")
+	bindEnv("server.create_session_rate_per_minute", "CREATE_SESSION_RATE_PER_MINUTE")
+	bindEnv("server.create_session_burst", "CREATE_SESSION_BURST")
+	bindEnv("server.trusted_proxies", "TRUSTED_PROXIES")
 
 	// Logging
 	bindEnv("logging.level", "LOG_LEVEL")
 	bindEnv("logging.format", "LOG_FORMAT")
 
+	// SSH checks
+	bindEnv("ssh.bandwidth_test_url", "BANDWIDTH_TEST_URL")
+	bindEnv("ssh.kernel_watch_interval", "KERNEL_WATCH_INTERVAL")
+
 	// Lifecycle
 	bindEnv("lifecycle.deployment_id", "DEPLOYMENT_ID")
+	bindEnv("lifecycle.leader_election", "LEADER_ELECTION")
+	bindEnv("lifecycle.leader_lease_ttl", "LEADER_LEASE_TTL")
+	bindEnv("lifecycle.max_paused_duration", "MAX_PAUSED_DURATION")
+
+	// Budget alerts
+	bindEnv("budget.webhook_url", "BUDGET_WEBHOOK_URL")
+	bindEnv("budget.spend_ceiling", "BUDGET_SPEND_CEILING")
+
+	// Benchmarks
+	bindEnv("benchmark.catalog_path", "BENCHMARK_CATALOG_PATH")
+
+	// Secrets backends
+	bindEnv("secrets.vault_addr", "VAULT_ADDR")
+	bindEnv("secrets.vault_token", "VAULT_TOKEN")
+	bindEnv("secrets.vault_mount", "VAULT_MOUNT")
+	bindEnv("secrets.vault_namespace", "VAULT_NAMESPACE")
+	bindEnv("secrets.file", "SECRETS_FILE")
+	bindEnv("secrets.file_passphrase", "SECRETS_PASSPHRASE")
+
+	// Workspace storage
+	bindEnv("workspaces.endpoint", "WORKSPACE_S3_ENDPOINT")
+	bindEnv("workspaces.region", "WORKSPACE_S3_REGION")
+	bindEnv("workspaces.bucket", "WORKSPACE_S3_BUCKET")
+	bindEnv("workspaces.access_key_id", "WORKSPACE_S3_ACCESS_KEY_ID")
+	bindEnv("workspaces.secret_access_key", "WORKSPACE_S3_SECRET_ACCESS_KEY")
+	bindEnv("workspaces.path", "WORKSPACE_PATH")
+	bindEnv("workspaces.save_timeout", "WORKSPACE_SAVE_TIMEOUT")
+
+	// Model cache
+	bindEnv("model_cache.endpoint", "MODEL_CACHE_S3_ENDPOINT")
+	bindEnv("model_cache.region", "MODEL_CACHE_S3_REGION")
+	bindEnv("model_cache.bucket", "MODEL_CACHE_S3_BUCKET")
+	bindEnv("model_cache.access_key_id", "MODEL_CACHE_S3_ACCESS_KEY_ID")
+	bindEnv("model_cache.secret_access_key", "MODEL_CACHE_S3_SECRET_ACCESS_KEY")
+	bindEnv("model_cache.path", "MODEL_CACHE_PATH")
+	bindEnv("model_cache.pull_timeout", "MODEL_CACHE_PULL_TIMEOUT")
+}
+
+// secretFields are the settings that may be given as secret references
+func (c *Config) secretFields() map[string]*string {
+	return map[string]*string{
+		"providers.vastai.api_key":       &c.Providers.VastAI.APIKey,
+		"providers.bluelobster.api_key":  &c.Providers.BlueLobster.APIKey,
+		"providers.tensordock.auth_id":   &c.Providers.TensorDock.AuthID,
+		"providers.tensordock.api_token": &c.Providers.TensorDock.APIToken,
+		"server.admin_api_key":           &c.Server.AdminAPIKey,
+		"server.api_keys":                &c.Server.APIKeys,
+		"budget.webhook_url":             &c.Budget.WebhookURL,
+		"database.encryption_key":        &c.Database.EncryptionKey,
+		"workspaces.access_key_id":       &c.Workspaces.AccessKeyID,
+		"workspaces.secret_access_key":   &c.Workspaces.SecretAccessKey,
+		"model_cache.access_key_id":      &c.ModelCache.AccessKeyID,
+		"model_cache.secret_access_key":  &c.ModelCache.SecretAccessKey,
+	}
+}
+
+// resolveSecrets replaces secret references with the secrets they name.
+// Backends are only contacted when a setting references them.
+func (c *Config) resolveSecrets() error {
+	used := make(map[string]bool)
+	for _, field := range c.secretFields() {
+		if secrets.IsReference(*field) {
+			backend, _, _ := strings.Cut(*field, ":")
+			used[backend] = true
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+
+	var backends []secrets.Backend
+	if used["vault"] && c.Secrets.VaultAddr != "" {
+		backends = append(backends, secrets.NewVault(c.Secrets.VaultAddr, c.Secrets.VaultToken,
+			secrets.WithVaultMount(c.Secrets.VaultMount),
+			secrets.WithVaultNamespace(c.Secrets.VaultNamespace)))
+	}
+	if used["file"] && c.Secrets.File != "" {
+		file, err := secrets.NewFile(c.Secrets.File, c.Secrets.FilePassphrase)
+		if err != nil {
+			return fmt.Errorf("failed to open secrets file: %w", err)
+		}
+		backends = append(backends, file)
+	}
+	resolver := secrets.NewResolver(backends...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+	for name, field := range c.secretFields() {
+		value, err := resolver.Resolve(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		*field = value
+	}
+	return nil
+}
+
+// RequiresRestart reports whether next differs from c in anything other than
+// the settings a running server reloads: the log level and inventory cache
+// TTLs
+func (c *Config) RequiresRestart(next *Config) bool {
+	a, b := *c, *next
+	a.Logging.Level, b.Logging.Level = "", ""
+	a.Inventory = InventoryConfig{BackgroundRefresh: a.Inventory.BackgroundRefresh}
+	b.Inventory = InventoryConfig{BackgroundRefresh: b.Inventory.BackgroundRefresh}
+	return !reflect.DeepEqual(a, b)
 }
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
+	for consumer, regions := range c.Policy.AllowedRegions {
+		for _, region := range regions {
+			if !models.IsValidRegion(region) {
+				return fmt.Errorf("policy.allowed_regions: invalid region %q for consumer %q", region, consumer)
+			}
+		}
+	}
+
+	for workload, probe := range c.Workloads.ReadinessProbes {
+		if !readinessWorkloadTypes[workload] {
+			return fmt.Errorf("workloads.readiness_probes: unknown workload type %q", workload)
+		}
+		if !readinessProbes[probe] {
+			return fmt.Errorf("workloads.readiness_probes: invalid probe %q for %s, want health, models or completion", probe, workload)
+		}
+	}
+
+	retry := c.Providers.Retry
+	if retry.MaxAttempts < 0 || retry.BaseDelay < 0 || retry.MaxDelay < 0 {
+		return fmt.Errorf("providers.retry: attempts and delays must not be negative")
+	}
+	if retry.Jitter < 0 || retry.Jitter > 1 {
+		return fmt.Errorf("providers.retry.jitter must be between 0 and 1, got %v", retry.Jitter)
+	}
+	if c.Providers.MaxConcurrentCreates < 0 || c.Providers.TensorDock.MaxConcurrentCreates < 0 {
+		return fmt.Errorf("providers: max_concurrent_creates must not be negative")
+	}
+	if c.Providers.CreateQueueTimeout < 0 {
+		return fmt.Errorf("providers.create_queue_timeout must not be negative")
+	}
+	if c.SSH.KernelWatchInterval < 0 {
+		return fmt.Errorf("ssh.kernel_watch_interval must not be negative")
+	}
+	if c.Lifecycle.MaxPausedDuration < 0 {
+		return fmt.Errorf("lifecycle.max_paused_duration must not be negative")
+	}
+
+	if ws := c.Workspaces; ws.Bucket != "" {
+		if ws.Endpoint == "" || ws.AccessKeyID == "" || ws.SecretAccessKey == "" {
+			return fmt.Errorf("WORKSPACE_S3_ENDPOINT, WORKSPACE_S3_ACCESS_KEY_ID and WORKSPACE_S3_SECRET_ACCESS_KEY are required when WORKSPACE_S3_BUCKET is set")
+		}
+		if !strings.HasPrefix(ws.Path, "/") {
+			return fmt.Errorf("workspaces.path must be absolute, got %q", ws.Path)
+		}
+		if ws.SaveTimeout <= 0 {
+			return fmt.Errorf("workspaces.save_timeout must be positive")
+		}
+	}
+
+	if mc := c.ModelCache; mc.Bucket != "" {
+		if mc.Endpoint == "" || mc.AccessKeyID == "" || mc.SecretAccessKey == "" {
+			return fmt.Errorf("MODEL_CACHE_S3_ENDPOINT, MODEL_CACHE_S3_ACCESS_KEY_ID and MODEL_CACHE_S3_SECRET_ACCESS_KEY are required when MODEL_CACHE_S3_BUCKET is set")
+		}
+		if !strings.HasPrefix(mc.Path, "/") {
+			return fmt.Errorf("model_cache.path must be absolute, got %q", mc.Path)
+		}
+		if mc.PullTimeout <= 0 {
+			return fmt.Errorf("model_cache.pull_timeout must be positive")
+		}
+	}
+
+	// Offline mode needs only the static catalog
+	if c.Providers.Offline {
+		if c.Providers.Static.CatalogPath == "" {
+			return fmt.Errorf("STATIC_CATALOG_PATH is required in offline mode")
+		}
+		if c.Providers.Static.SSHKeyPath == "" {
+			return fmt.Errorf("STATIC_SSH_KEY_PATH is required in offline mode")
+		}
+		return nil
+	}
+
 	// Check that at least one provider is configured
 	if !c.Providers.VastAI.Enabled && !c.Providers.BlueLobster.Enabled && !c.Providers.TensorDock.Enabled {
 		return fmt.Errorf("at least one provider must be enabled")
@@ -2,11 +2,14 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
 )
 
 func TestLoadFromEnv_Defaults(t *testing.T) {
@@ -21,10 +24,37 @@ func TestLoadFromEnv_Defaults(t *testing.T) {
 	// Check defaults
 	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
 	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, 20.0, cfg.Server.RateLimitRPS)
+	assert.Equal(t, 10.0, cfg.Server.CreateSessionRatePerMinute)
 	assert.Equal(t, "./data/gpu-shopper.db", cfg.Database.Path)
 	assert.Equal(t, time.Minute, cfg.Inventory.DefaultCacheTTL)
 	assert.Equal(t, 5*time.Minute, cfg.Inventory.BackoffCacheTTL)
+	assert.True(t, cfg.Inventory.BackgroundRefresh)
+	assert.Equal(t, ProviderRetry{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.5,
+	}, cfg.Providers.Retry)
+	assert.Equal(t, 10, cfg.Providers.MaxConcurrentCreates)
+	assert.Equal(t, 2, cfg.Providers.TensorDock.MaxConcurrentCreates)
+	assert.Equal(t, 30*time.Second, cfg.Providers.CreateQueueTimeout)
 	assert.Equal(t, 12, cfg.Lifecycle.HardMaxHours)
+	assert.False(t, cfg.Lifecycle.LeaderElection)
+	assert.Equal(t, 30*time.Second, cfg.Lifecycle.LeaderLeaseTTL)
+	assert.Equal(t, 24*time.Hour, cfg.Lifecycle.MaxPausedDuration)
+	assert.Equal(t, 5*time.Minute, cfg.Budget.CheckInterval)
+	assert.Equal(t, 0.80, cfg.Budget.WarningThreshold)
+	assert.Zero(t, cfg.Budget.SpendCeiling)
+	assert.Empty(t, cfg.Workspaces.Bucket)
+	assert.Equal(t, "workspaces", cfg.Workspaces.Prefix)
+	assert.Equal(t, "/workspace", cfg.Workspaces.Path)
+	assert.Equal(t, 30*time.Minute, cfg.Workspaces.SaveTimeout)
+	assert.Empty(t, cfg.ModelCache.Bucket)
+	assert.Equal(t, "models", cfg.ModelCache.Prefix)
+	assert.Equal(t, "/models", cfg.ModelCache.Path)
+	assert.Equal(t, 30*time.Minute, cfg.ModelCache.PullTimeout)
+	assert.Equal(t, time.Minute, cfg.SSH.KernelWatchInterval)
 	assert.Equal(t, "info", cfg.Logging.Level)
 }
 
@@ -34,11 +64,27 @@ func TestLoadFromEnv_WithEnvVars(t *testing.T) {
 	os.Setenv("TENSORDOCK_AUTH_ID", "test-auth-id")
 	os.Setenv("TENSORDOCK_API_TOKEN", "test-api-token")
 	os.Setenv("SERVER_PORT", "9090")
+	os.Setenv("BUDGET_SPEND_CEILING", "750")
+	os.Setenv("LEADER_ELECTION", "true")
+	os.Setenv("LEADER_LEASE_TTL", "45s")
+	os.Setenv("PROVIDER_RETRY_MAX_ATTEMPTS", "5")
+	os.Setenv("PROVIDER_RETRY_BASE_DELAY", "1s")
+	os.Setenv("TENSORDOCK_MAX_CONCURRENT_CREATES", "1")
+	os.Setenv("PROVIDER_CREATE_QUEUE_TIMEOUT", "1m")
+	os.Setenv("KERNEL_WATCH_INTERVAL", "0")
 	defer func() {
 		os.Unsetenv("VASTAI_API_KEY")
 		os.Unsetenv("TENSORDOCK_AUTH_ID")
 		os.Unsetenv("TENSORDOCK_API_TOKEN")
 		os.Unsetenv("SERVER_PORT")
+		os.Unsetenv("BUDGET_SPEND_CEILING")
+		os.Unsetenv("LEADER_ELECTION")
+		os.Unsetenv("LEADER_LEASE_TTL")
+		os.Unsetenv("PROVIDER_RETRY_MAX_ATTEMPTS")
+		os.Unsetenv("PROVIDER_RETRY_BASE_DELAY")
+		os.Unsetenv("TENSORDOCK_MAX_CONCURRENT_CREATES")
+		os.Unsetenv("PROVIDER_CREATE_QUEUE_TIMEOUT")
+		os.Unsetenv("KERNEL_WATCH_INTERVAL")
 	}()
 
 	cfg, err := LoadFromEnv()
@@ -48,6 +94,14 @@ func TestLoadFromEnv_WithEnvVars(t *testing.T) {
 	assert.Equal(t, "test-auth-id", cfg.Providers.TensorDock.AuthID)
 	assert.Equal(t, "test-api-token", cfg.Providers.TensorDock.APIToken)
 	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, 750.0, cfg.Budget.SpendCeiling)
+	assert.True(t, cfg.Lifecycle.LeaderElection)
+	assert.Equal(t, 45*time.Second, cfg.Lifecycle.LeaderLeaseTTL)
+	assert.Equal(t, 5, cfg.Providers.Retry.MaxAttempts)
+	assert.Equal(t, time.Second, cfg.Providers.Retry.BaseDelay)
+	assert.Equal(t, 1, cfg.Providers.TensorDock.MaxConcurrentCreates)
+	assert.Equal(t, time.Minute, cfg.Providers.CreateQueueTimeout)
+	assert.Zero(t, cfg.SSH.KernelWatchInterval)
 }
 
 func TestConfig_Validate_NoProviders(t *testing.T) {
@@ -100,3 +154,285 @@ func TestConfig_Validate_Success(t *testing.T) {
 	err := cfg.Validate()
 	assert.NoError(t, err)
 }
+
+func TestConfig_Validate_AllowedRegions(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI: VastAIConfig{Enabled: true, APIKey: "test-key"},
+		},
+		Policy: PolicyConfig{AllowedRegions: map[string][]string{"team-eu": {"EU", "CH"}, "*": {"Europe"}}},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid region "Europe"`)
+
+	cfg.Policy.AllowedRegions["*"] = []string{"EEA"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_ReadinessProbes(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI: VastAIConfig{Enabled: true, APIKey: "test-key"},
+		},
+		Workloads: WorkloadsConfig{ReadinessProbes: map[string]string{"vllm": "completion", "tgi": "ready"}},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid probe "ready" for tgi`)
+
+	cfg.Workloads.ReadinessProbes = map[string]string{"ollama": "health"}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Workloads.ReadinessProbes = map[string]string{"vllm": "completion", "tgi": "models"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_Offline(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI:  VastAIConfig{Enabled: true}, // Live provider credentials are not needed offline
+			Offline: true,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "STATIC_CATALOG_PATH")
+
+	cfg.Providers.Static = StaticConfig{CatalogPath: "catalog.json", SSHKeyPath: "admin_key"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_ProviderRetry(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI: VastAIConfig{Enabled: true, APIKey: "test-key"},
+			Retry:  ProviderRetry{MaxAttempts: 3, Jitter: 1.5},
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "providers.retry.jitter")
+
+	cfg.Providers.Retry = ProviderRetry{MaxAttempts: -1}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Providers.Retry = ProviderRetry{MaxAttempts: 1, Jitter: 0.2}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_ProviderConcurrency(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI:     VastAIConfig{Enabled: true, APIKey: "test-key"},
+			TensorDock: TensorDockConfig{MaxConcurrentCreates: -1},
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "max_concurrent_creates")
+
+	cfg.Providers.TensorDock.MaxConcurrentCreates = 2
+	cfg.Providers.CreateQueueTimeout = -time.Second
+	assert.Error(t, cfg.Validate())
+
+	cfg.Providers.CreateQueueTimeout = 0
+	assert.NoError(t, cfg.Validate())
+
+	cfg.SSH.KernelWatchInterval = -time.Minute
+	assert.ErrorContains(t, cfg.Validate(), "kernel_watch_interval")
+
+	cfg.SSH.KernelWatchInterval = 0
+	cfg.Lifecycle.MaxPausedDuration = -time.Hour
+	assert.ErrorContains(t, cfg.Validate(), "max_paused_duration")
+}
+
+func TestConfig_Validate_Workspaces(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI: VastAIConfig{Enabled: true, APIKey: "test-key"},
+		},
+		Workspaces: WorkspacesConfig{Bucket: "workspaces", Path: "/workspace", SaveTimeout: 30 * time.Minute},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "WORKSPACE_S3_ENDPOINT")
+
+	cfg.Workspaces.Endpoint = "https://s3.us-east-1.amazonaws.com"
+	cfg.Workspaces.AccessKeyID = "AKIAEXAMPLE"
+	cfg.Workspaces.SecretAccessKey = "secret"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Workspaces.Path = "workspace"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfig_Validate_ModelCache(t *testing.T) {
+	cfg := &Config{
+		Providers: ProvidersConfig{
+			VastAI: VastAIConfig{Enabled: true, APIKey: "test-key"},
+		},
+		ModelCache: ModelCacheConfig{Bucket: "models", Path: "/models", PullTimeout: 30 * time.Minute},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "MODEL_CACHE_S3_ENDPOINT")
+
+	cfg.ModelCache.Endpoint = "https://account.r2.cloudflarestorage.com"
+	cfg.ModelCache.AccessKeyID = "key"
+	cfg.ModelCache.SecretAccessKey = "secret"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ModelCache.PullTimeout = 0
+	assert.Error(t, cfg.Validate())
+}
+
+func TestLoadFromEnv_ConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("yaml", func(t *testing.T) {
+		path := filepath.Join(dir, "gpu-shopper.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  port: 9191
+providers:
+  vastai:
+    api_key: file-vast-key
+inventory:
+  default_cache_ttl: 2m
+  provider_cache_ttls:
+    vastai: 45s
+lifecycle:
+  hard_max_hours: 6
+policy:
+  allowed_regions:
+    team-eu: [EU, CH]
+workloads:
+  readiness_probes:
+    vllm: completion
+logging:
+  level: debug
+`), 0600))
+		t.Setenv(ConfigFileEnv, path)
+
+		cfg, err := LoadFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, 9191, cfg.Server.Port)
+		assert.Equal(t, "file-vast-key", cfg.Providers.VastAI.APIKey)
+		assert.Equal(t, 2*time.Minute, cfg.Inventory.DefaultCacheTTL)
+		assert.Equal(t, map[string]time.Duration{"vastai": 45 * time.Second}, cfg.Inventory.ProviderCacheTTLs)
+		assert.Equal(t, 6, cfg.Lifecycle.HardMaxHours)
+		assert.Equal(t, map[string][]string{"team-eu": {"EU", "CH"}}, cfg.Policy.AllowedRegions)
+		assert.Equal(t, map[string]string{"vllm": "completion"}, cfg.Workloads.ReadinessProbes)
+		assert.Equal(t, "debug", cfg.Logging.Level)
+		assert.Equal(t, "0.0.0.0", cfg.Server.Host, "unset keys keep their defaults")
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		path := filepath.Join(dir, "gpu-shopper.toml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+[server]
+port = 9292
+
+[providers.tensordock]
+auth_id = "file-auth-id"
+`), 0600))
+		t.Setenv(ConfigFileEnv, path)
+
+		cfg, err := LoadFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, 9292, cfg.Server.Port)
+		assert.Equal(t, "file-auth-id", cfg.Providers.TensorDock.AuthID)
+	})
+
+	t.Run("environment overrides file", func(t *testing.T) {
+		path := filepath.Join(dir, "override.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 9393\nlogging:\n  level: debug\n"), 0600))
+		t.Setenv(ConfigFileEnv, path)
+		t.Setenv("SERVER_PORT", "9494")
+
+		cfg, err := LoadFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, 9494, cfg.Server.Port)
+		assert.Equal(t, "debug", cfg.Logging.Level)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv(ConfigFileEnv, filepath.Join(dir, "missing.yaml"))
+
+		_, err := LoadFromEnv()
+		assert.Error(t, err)
+	})
+}
+
+func TestConfig_RequiresRestart(t *testing.T) {
+	base := &Config{
+		Server:    ServerConfig{Port: 8080},
+		Inventory: InventoryConfig{DefaultCacheTTL: time.Minute},
+		Logging:   LoggingConfig{Level: "info", Format: "json"},
+	}
+
+	reloadable := *base
+	reloadable.Logging.Level = "debug"
+	reloadable.Inventory = InventoryConfig{
+		DefaultCacheTTL:   2 * time.Minute,
+		ProviderCacheTTLs: map[string]time.Duration{"vastai": 30 * time.Second},
+	}
+	assert.False(t, base.RequiresRestart(&reloadable))
+
+	structural := *base
+	structural.Server.Port = 9090
+	assert.True(t, base.RequiresRestart(&structural))
+
+	format := *base
+	format.Logging.Format = "text"
+	assert.True(t, base.RequiresRestart(&format))
+
+	refresh := *base
+	refresh.Inventory.BackgroundRefresh = true
+	assert.True(t, base.RequiresRestart(&refresh))
+}
+
+func TestLoadFromEnv_SecretReferences(t *testing.T) {
+	raw, err := secrets.Encrypt(map[string]string{
+		"vastai_api_key": "vast-from-file",
+		"admin_api_key":  "admin-from-file",
+		"db_key":         "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=",
+	}, "passphrase")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	require.NoError(t, os.WriteFile(path, raw, 0600))
+
+	t.Setenv("SECRETS_FILE", path)
+	t.Setenv("SECRETS_PASSPHRASE", "passphrase")
+	t.Setenv("VASTAI_API_KEY", "file:vastai_api_key")
+	t.Setenv("ADMIN_API_KEY", "file:admin_api_key")
+	t.Setenv("TENSORDOCK_AUTH_ID", "plain-auth-id")
+	t.Setenv("DATABASE_ENCRYPTION_KEY", "file:db_key")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "vast-from-file", cfg.Providers.VastAI.APIKey)
+	assert.Equal(t, "admin-from-file", cfg.Server.AdminAPIKey)
+	assert.Equal(t, "plain-auth-id", cfg.Providers.TensorDock.AuthID)
+	assert.Equal(t, "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", cfg.Database.EncryptionKey)
+
+	t.Run("unconfigured backend", func(t *testing.T) {
+		t.Setenv("BLUELOBSTER_API_KEY", "vault:gpu-shopper#bluelobster_api_key")
+		_, err := LoadFromEnv()
+		assert.ErrorContains(t, err, "providers.bluelobster.api_key")
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		t.Setenv("SECRETS_PASSPHRASE", "wrong")
+		_, err := LoadFromEnv()
+		assert.ErrorIs(t, err, secrets.ErrWrongPassphrase)
+	})
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
+
+	sshpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
 )
 
 const (
@@ -23,6 +25,10 @@ type Credentials struct {
 	Port       int
 	User       string
 	PrivateKey []byte // PEM-encoded private key
+
+	// HostKeyFingerprint is the SHA256 fingerprint the host key must match,
+	// as recorded by the server. Empty accepts any host key.
+	HostKeyFingerprint string
 }
 
 // Validate checks that the credentials have all required fields
@@ -223,7 +229,7 @@ func (t *Transfer) connect(ctx context.Context) (*ssh.Client, error) {
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(signer),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // Commodity GPUs have unknown/dynamic host keys
+		HostKeyCallback: sshpkg.TrustOnFirstUse(t.creds.HostKeyFingerprint, nil),
 		Timeout:         t.connectTimeout,
 	}
 
@@ -27,21 +27,13 @@ type Config struct {
 	Output io.Writer
 }
 
+// level is shared by every handler built by Setup so SetLevel can change it
+// on a running server
+var level slog.LevelVar
+
 // Setup configures the global logger
 func Setup(cfg Config) *slog.Logger {
-	var level slog.Level
-	switch strings.ToLower(cfg.Level) {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn", "warning":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
-	}
+	level.Set(parseLevel(cfg.Level))
 
 	output := cfg.Output
 	if output == nil {
@@ -50,8 +42,8 @@ func Setup(cfg Config) *slog.Logger {
 
 	var handler slog.Handler
 	opts := &slog.HandlerOptions{
-		Level:     level,
-		AddSource: level == slog.LevelDebug,
+		Level:     &level,
+		AddSource: level.Level() == slog.LevelDebug,
 	}
 
 	if strings.ToLower(cfg.Format) == "text" {
@@ -69,6 +61,26 @@ func Setup(cfg Config) *slog.Logger {
 	return logger
 }
 
+// SetLevel changes the level of the logger built by Setup. Source locations
+// are only added if the server started at debug level.
+func SetLevel(name string) {
+	level.Set(parseLevel(name))
+}
+
+// parseLevel maps a level name to its slog level, defaulting to info
+func parseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
 // ContextHandler adds context values to log records
 type ContextHandler struct {
 	slog.Handler
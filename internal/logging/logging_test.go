@@ -78,6 +78,29 @@ func TestSetup_LogLevels(t *testing.T) {
 	}
 }
 
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := Setup(Config{
+		Level:  "warn",
+		Format: "json",
+		Output: &buf,
+	})
+
+	logger.Info("hidden")
+	assert.Empty(t, buf.String())
+
+	SetLevel("debug")
+	logger.Debug("shown")
+	assert.Contains(t, buf.String(), "shown")
+
+	SetLevel("bogus")
+	buf.Reset()
+	logger.Debug("hidden again")
+	logger.Info("info")
+	assert.NotContains(t, buf.String(), "hidden again")
+	assert.Contains(t, buf.String(), `"msg":"info"`)
+}
+
 func TestWithRequestID(t *testing.T) {
 	ctx := context.Background()
 	ctx = WithRequestID(ctx, "req-123")
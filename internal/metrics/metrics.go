@@ -1,8 +1,6 @@
 package metrics
 
 import (
-	"context"
-	"log/slog"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -103,6 +101,16 @@ var (
 		[]string{"provider", "error_type"},
 	)
 
+	// SSHHostKeyMismatches counts connections refused because an instance
+	// presented a different host key than the one recorded on first use
+	SSHHostKeyMismatches = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_ssh_host_key_mismatches_total",
+			Help: "SSH connections refused because the host key changed since first use, by provider",
+		},
+		[]string{"provider"},
+	)
+
 	// APIVerifyDuration tracks how long API verification takes (entrypoint mode)
 	APIVerifyDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -166,6 +174,14 @@ var (
 		},
 	)
 
+	// ZombiesDetected counts zombie sessions (running in DB, instance stopped)
+	ZombiesDetected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gpu_zombies_detected_total",
+			Help: "Total number of zombie sessions detected (running in DB, instance stopped on provider)",
+		},
+	)
+
 	// ProvisioningDuration tracks how long provisioning takes
 	ProvisioningDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -185,6 +201,49 @@ var (
 		[]string{"provider"},
 	)
 
+	// SessionCostAccrued tracks the recorded cost of each active session
+	SessionCostAccrued = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gpu_session_cost_accrued_usd",
+			Help: "Cost recorded so far in USD by active session, consumer and provider",
+		},
+		[]string{"session", "consumer", "provider"},
+	)
+
+	// SessionRuntime tracks how long each active session has existed
+	SessionRuntime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gpu_session_runtime_seconds",
+			Help: "Time since creation in seconds by active session, consumer and provider",
+		},
+		[]string{"session", "consumer", "provider"},
+	)
+
+	// FleetHourlyBurn tracks the combined hourly price of all active sessions
+	FleetHourlyBurn = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gpu_fleet_hourly_burn_usd",
+			Help: "Combined hourly price in USD of all active sessions",
+		},
+	)
+
+	// QuarantinedInstances tracks instances that could not be destroyed and
+	// await an operator
+	QuarantinedInstances = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gpu_quarantined_instances",
+			Help: "Number of provider instances that could not be destroyed and await an operator",
+		},
+	)
+
+	// LeaderElected is 1 while this replica holds the leader lease
+	LeaderElected = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gpu_leader_elected",
+			Help: "1 while this replica holds the leader lease and runs background services, else 0",
+		},
+	)
+
 	// BudgetAlerts counts budget alert events
 	BudgetAlerts = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -194,6 +253,42 @@ var (
 		[]string{"alert_type"},
 	)
 
+	// BudgetUtilization tracks spend (recorded plus committed) as a fraction of each budget
+	BudgetUtilization = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gpu_budget_utilization_ratio",
+			Help: "Current period spend as a fraction of budget limit by scope, scope_id and period",
+		},
+		[]string{"scope", "scope_id", "period"},
+	)
+
+	// BudgetRejections counts session requests rejected by a budget cap
+	BudgetRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_budget_rejections_total",
+			Help: "Total number of session requests rejected because a budget would be exceeded",
+		},
+		[]string{"scope"},
+	)
+
+	// WebhookDeliveries counts webhook delivery attempts by event type and outcome
+	WebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_webhook_deliveries_total",
+			Help: "Total number of webhook delivery attempts by event type and outcome (delivered, retry, failed)",
+		},
+		[]string{"event_type", "outcome"},
+	)
+
+	// InventoryCacheLookups counts inventory cache lookups by provider and result
+	InventoryCacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_inventory_cache_lookups_total",
+			Help: "Total number of inventory cache lookups by provider and result (fresh, stale, miss, coalesced)",
+		},
+		[]string{"provider", "result"},
+	)
+
 	// ProviderAPIResponseTime tracks API response times by provider and operation
 	// This helps identify slow operations and potential performance issues
 	ProviderAPIResponseTime = promauto.NewHistogramVec(
@@ -215,6 +310,16 @@ var (
 		[]string{"provider", "operation", "status"},
 	)
 
+	// ProviderAPIRetriesTotal counts retries of provider API calls after
+	// transient errors
+	ProviderAPIRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_provider_api_retries_total",
+			Help: "Total number of provider API call retries by provider and operation",
+		},
+		[]string{"provider", "operation"},
+	)
+
 	// ProviderCircuitBreakerState tracks circuit breaker state by provider
 	// Values: 0 = closed, 1 = open, 2 = half-open
 	ProviderCircuitBreakerState = promauto.NewGaugeVec(
@@ -252,6 +357,15 @@ var (
 		[]string{"provider", "scope"},
 	)
 
+	// SessionsPreempted counts interruptible sessions reclaimed by their provider
+	SessionsPreempted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_sessions_preempted_total",
+			Help: "Total number of interruptible sessions preempted (e.g. outbid) by provider",
+		},
+		[]string{"provider"},
+	)
+
 	// SessionDiskAvailableGB tracks available disk space observed post-provision
 	SessionDiskAvailableGB = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -262,6 +376,13 @@ var (
 	)
 
 	// OfferFailuresRecorded counts offer provisioning failures by provider, GPU type, and failure type
+	RateLimitedRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gpu_api_rate_limited_total",
+			Help: "Total number of API requests rejected by the rate limiter",
+		},
+		[]string{"scope"},
+	)
 	OfferFailuresRecorded = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "gpu_offer_failures_total",
@@ -295,6 +416,12 @@ func RecordGhostDetected() {
 	ReconciliationMismatches.Inc()
 }
 
+// RecordZombieDetected increments the zombie counter and reconciliation mismatches
+func RecordZombieDetected() {
+	ZombiesDetected.Inc()
+	ReconciliationMismatches.Inc()
+}
+
 // RecordDestroyFailure increments the destroy failure counter
 func RecordDestroyFailure() {
 	DestroyFailures.Inc()
@@ -305,16 +432,6 @@ func RecordProviderError(provider, operation string) {
 	ProviderAPIErrors.WithLabelValues(provider, operation).Inc()
 }
 
-// UpdateSessionStatus updates the active sessions gauge
-func UpdateSessionStatus(provider, oldStatus, newStatus string) {
-	if oldStatus != "" {
-		SessionsActive.WithLabelValues(provider, oldStatus).Dec()
-	}
-	if newStatus != "" {
-		SessionsActive.WithLabelValues(provider, newStatus).Inc()
-	}
-}
-
 // RecordSSHVerifyDuration records how long SSH verification took
 func RecordSSHVerifyDuration(provider string, duration time.Duration) {
 	SSHVerifyDuration.WithLabelValues(provider).Observe(duration.Seconds())
@@ -335,6 +452,11 @@ func RecordSSHVerifyError(provider, errorType string) {
 	SSHVerifyErrorTypes.WithLabelValues(provider, errorType).Inc()
 }
 
+// RecordSSHHostKeyMismatch records a connection refused for a changed host key
+func RecordSSHHostKeyMismatch(provider string) {
+	SSHHostKeyMismatches.WithLabelValues(provider).Inc()
+}
+
 // RecordHardMaxEnforced increments the hard max enforcement counter
 func RecordHardMaxEnforced() {
 	HardMaxEnforced.Inc()
@@ -350,6 +472,31 @@ func RecordBudgetAlert(alertType string) {
 	BudgetAlerts.WithLabelValues(alertType).Inc()
 }
 
+// UpdateBudgetUtilization sets the utilization gauge for a budget
+func UpdateBudgetUtilization(scope, scopeID, period string, ratio float64) {
+	BudgetUtilization.WithLabelValues(scope, scopeID, period).Set(ratio)
+}
+
+// RecordBudgetRejection increments the budget rejection counter
+func RecordBudgetRejection(scope string) {
+	BudgetRejections.WithLabelValues(scope).Inc()
+}
+
+// RecordRateLimited increments the rate-limited request counter
+func RecordRateLimited(scope string) {
+	RateLimitedRequests.WithLabelValues(scope).Inc()
+}
+
+// RecordWebhookDelivery increments the webhook delivery counter
+func RecordWebhookDelivery(eventType, outcome string) {
+	WebhookDeliveries.WithLabelValues(eventType, outcome).Inc()
+}
+
+// RecordInventoryCacheLookup increments the inventory cache lookup counter
+func RecordInventoryCacheLookup(provider, result string) {
+	InventoryCacheLookups.WithLabelValues(provider, result).Inc()
+}
+
 // RecordAPIVerifyDuration records how long API verification took
 func RecordAPIVerifyDuration(provider string, duration time.Duration) {
 	APIVerifyDuration.WithLabelValues(provider).Observe(duration.Seconds())
@@ -377,12 +524,26 @@ func RecordProviderAPICall(provider, operation, status string) {
 	ProviderAPICallsTotal.WithLabelValues(provider, operation, status).Inc()
 }
 
+// RecordProviderAPIRetry records a provider API call being retried
+func RecordProviderAPIRetry(provider, operation string) {
+	ProviderAPIRetriesTotal.WithLabelValues(provider, operation).Inc()
+}
+
 // UpdateProviderCircuitBreakerState updates the circuit breaker state metric
 // state should be 0 (closed), 1 (open), or 2 (half-open)
 func UpdateProviderCircuitBreakerState(provider string, state int) {
 	ProviderCircuitBreakerState.WithLabelValues(provider).Set(float64(state))
 }
 
+// UpdateLeaderElected records whether this replica is the leader
+func UpdateLeaderElected(leader bool) {
+	if leader {
+		LeaderElected.Set(1)
+	} else {
+		LeaderElected.Set(0)
+	}
+}
+
 // RecordHTTPRequest records the duration and increments the counter for an HTTP request
 func RecordHTTPRequest(method, path, status string, duration time.Duration) {
 	HTTPRequestDuration.WithLabelValues(method, path, status).Observe(duration.Seconds())
@@ -394,6 +555,11 @@ func RecordRetryAttempt(provider, scope, reason string) {
 	SessionRetryAttempts.WithLabelValues(provider, scope, reason).Inc()
 }
 
+// RecordSessionPreempted increments the preempted session counter
+func RecordSessionPreempted(provider string) {
+	SessionsPreempted.WithLabelValues(provider).Inc()
+}
+
 // RecordRetrySuccess increments the retry success counter
 func RecordRetrySuccess(provider, scope string) {
 	SessionRetrySuccesses.WithLabelValues(provider, scope).Inc()
@@ -421,14 +587,12 @@ type SessionCount struct {
 	Count    int
 }
 
-// InitializeSessionMetrics populates gauges from database state on startup.
-// This ensures metrics reflect reality before any reconciliation runs,
-// preventing negative gauge values from state transitions.
-func InitializeSessionMetrics(ctx context.Context, counts []SessionCount) error {
-	for _, c := range counts {
-		SessionsActive.WithLabelValues(c.Provider, c.Status).Set(float64(c.Count))
-	}
-	slog.Info("initialized session metrics from database",
-		slog.Int("label_combinations", len(counts)))
-	return nil
+// SessionCost holds the cost figures of one active session
+type SessionCost struct {
+	SessionID  string
+	ConsumerID string
+	Provider   string
+	Accrued    float64       // USD recorded so far
+	BurnRate   float64       // USD per hour
+	Runtime    time.Duration // Since the session was created
 }
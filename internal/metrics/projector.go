@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultProjectionInterval is how often the session gauges are re-derived
+const DefaultProjectionInterval = 15 * time.Second
+
+// SessionCountFunc returns the current number of active sessions per
+// provider/status combination from the authoritative session state
+type SessionCountFunc func(ctx context.Context) ([]SessionCount, error)
+
+// SessionCostFunc returns the cost figures of every active session
+type SessionCostFunc func(ctx context.Context) ([]SessionCost, error)
+
+// SessionProjector derives the gpu_sessions_active gauge from persisted
+// session state instead of incrementing and decrementing it at every status
+// transition. Each projection sets every series to its current count, so a
+// missed or duplicated transition can no longer make the gauge drift or go
+// negative (#46, #57, #94).
+type SessionProjector struct {
+	counts   SessionCountFunc
+	costs    SessionCostFunc // Optional: enables the per-session cost gauges
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu         sync.Mutex
+	known      map[[2]string]bool // provider/status series set by earlier projections
+	knownCosts map[[3]string]bool // session/consumer/provider series set by earlier projections
+	running    bool
+	stopCh     chan struct{}
+	doneCh     chan struct{}
+}
+
+// ProjectorOption configures the session projector
+type ProjectorOption func(*SessionProjector)
+
+// WithProjectionInterval sets how often gauges are re-derived
+func WithProjectionInterval(d time.Duration) ProjectorOption {
+	return func(p *SessionProjector) {
+		p.interval = d
+	}
+}
+
+// WithSessionCosts also projects the per-session cost and runtime gauges and
+// the fleet burn rate from costs
+func WithSessionCosts(costs SessionCostFunc) ProjectorOption {
+	return func(p *SessionProjector) {
+		p.costs = costs
+	}
+}
+
+// WithProjectorLogger sets a custom logger
+func WithProjectorLogger(logger *slog.Logger) ProjectorOption {
+	return func(p *SessionProjector) {
+		p.logger = logger
+	}
+}
+
+// NewSessionProjector creates a projector that reads session counts from counts
+func NewSessionProjector(counts SessionCountFunc, opts ...ProjectorOption) *SessionProjector {
+	p := &SessionProjector{
+		counts:     counts,
+		interval:   DefaultProjectionInterval,
+		logger:     slog.Default(),
+		known:      make(map[[2]string]bool),
+		knownCosts: make(map[[3]string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Project re-derives the session gauges from current state. Series that were
+// set by an earlier projection but no longer have sessions are set to zero.
+func (p *SessionProjector) Project(ctx context.Context) error {
+	counts, err := p.counts(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := make(map[[2]string]bool, len(counts))
+	for _, c := range counts {
+		key := [2]string{c.Provider, c.Status}
+		current[key] = true
+		SessionsActive.WithLabelValues(c.Provider, c.Status).Set(float64(c.Count))
+	}
+	for key := range p.known {
+		if !current[key] {
+			SessionsActive.WithLabelValues(key[0], key[1]).Set(0)
+		}
+	}
+	p.known = current
+
+	if p.costs == nil {
+		return nil
+	}
+	return p.projectCosts(ctx)
+}
+
+// projectCosts re-derives the cost gauges. Series of sessions that are no
+// longer active are removed rather than zeroed, so ended sessions do not
+// accumulate. Must be called with mu held.
+func (p *SessionProjector) projectCosts(ctx context.Context) error {
+	costs, err := p.costs(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[[3]string]bool, len(costs))
+	burn := 0.0
+	for _, c := range costs {
+		key := [3]string{c.SessionID, c.ConsumerID, c.Provider}
+		current[key] = true
+		SessionCostAccrued.WithLabelValues(key[:]...).Set(c.Accrued)
+		SessionRuntime.WithLabelValues(key[:]...).Set(c.Runtime.Seconds())
+		burn += c.BurnRate
+	}
+	for key := range p.knownCosts {
+		if !current[key] {
+			SessionCostAccrued.DeleteLabelValues(key[:]...)
+			SessionRuntime.DeleteLabelValues(key[:]...)
+		}
+	}
+	p.knownCosts = current
+	FleetHourlyBurn.Set(burn)
+
+	return nil
+}
+
+// Start projects once and then keeps the gauges up to date in the background
+func (p *SessionProjector) Start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = true
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	p.mu.Unlock()
+
+	if err := p.Project(ctx); err != nil {
+		p.logger.Error("failed to project session metrics", slog.String("error", err.Error()))
+	}
+
+	go p.run(ctx)
+	return nil
+}
+
+// Stop stops background projection
+func (p *SessionProjector) Stop() {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return
+	}
+	stopCh := p.stopCh
+	doneCh := p.doneCh
+	p.mu.Unlock()
+
+	close(stopCh)
+	<-doneCh
+
+	p.mu.Lock()
+	p.running = false
+	p.mu.Unlock()
+}
+
+// run is the projection loop
+func (p *SessionProjector) run(ctx context.Context) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.Project(ctx); err != nil {
+				p.logger.Error("failed to project session metrics", slog.String("error", err.Error()))
+			}
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionProjector_Project(t *testing.T) {
+	counts := []SessionCount{
+		{Provider: "projtest", Status: "running", Count: 3},
+		{Provider: "projtest", Status: "pending", Count: 1},
+	}
+	p := NewSessionProjector(func(ctx context.Context) ([]SessionCount, error) {
+		return counts, nil
+	})
+
+	// Drifted value left behind by an earlier bug is overwritten
+	SessionsActive.WithLabelValues("projtest", "running").Set(-2)
+
+	require.NoError(t, p.Project(context.Background()))
+	assert.Equal(t, 3.0, testutil.ToFloat64(SessionsActive.WithLabelValues("projtest", "running")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(SessionsActive.WithLabelValues("projtest", "pending")))
+
+	// Pending session started running; no pending sessions remain
+	counts = []SessionCount{
+		{Provider: "projtest", Status: "running", Count: 4},
+	}
+	require.NoError(t, p.Project(context.Background()))
+	assert.Equal(t, 4.0, testutil.ToFloat64(SessionsActive.WithLabelValues("projtest", "running")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(SessionsActive.WithLabelValues("projtest", "pending")))
+}
+
+func TestSessionProjector_ProjectError(t *testing.T) {
+	p := NewSessionProjector(func(ctx context.Context) ([]SessionCount, error) {
+		return nil, errors.New("database is locked")
+	})
+
+	SessionsActive.WithLabelValues("projtest-err", "running").Set(2)
+
+	assert.Error(t, p.Project(context.Background()))
+	// Gauges keep their last projected values when state can't be read
+	assert.Equal(t, 2.0, testutil.ToFloat64(SessionsActive.WithLabelValues("projtest-err", "running")))
+}
+
+func TestSessionProjector_ProjectCosts(t *testing.T) {
+	costs := []SessionCost{
+		{SessionID: "sess-1", ConsumerID: "team-a", Provider: "projtest", Accrued: 6, BurnRate: 2, Runtime: 3 * time.Hour},
+		{SessionID: "sess-2", ConsumerID: "team-b", Provider: "projtest", Accrued: 1, BurnRate: 0.5, Runtime: 90 * time.Minute},
+	}
+	p := NewSessionProjector(func(ctx context.Context) ([]SessionCount, error) {
+		return nil, nil
+	}, WithSessionCosts(func(ctx context.Context) ([]SessionCost, error) {
+		return costs, nil
+	}))
+
+	require.NoError(t, p.Project(context.Background()))
+	assert.Equal(t, 6.0, testutil.ToFloat64(SessionCostAccrued.WithLabelValues("sess-1", "team-a", "projtest")))
+	assert.Equal(t, 5400.0, testutil.ToFloat64(SessionRuntime.WithLabelValues("sess-2", "team-b", "projtest")))
+	assert.Equal(t, 2.5, testutil.ToFloat64(FleetHourlyBurn))
+
+	// sess-1 ended: its series are removed, not left at their last value
+	costs = costs[1:]
+	require.NoError(t, p.Project(context.Background()))
+	assert.False(t, SessionCostAccrued.DeleteLabelValues("sess-1", "team-a", "projtest"), "series already removed")
+	assert.False(t, SessionRuntime.DeleteLabelValues("sess-1", "team-a", "projtest"), "series already removed")
+	assert.Equal(t, 0.5, testutil.ToFloat64(FleetHourlyBurn))
+}
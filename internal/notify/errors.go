@@ -0,0 +1,21 @@
+package notify
+
+import "fmt"
+
+// InvalidSubscriptionError indicates a webhook subscription failed validation
+type InvalidSubscriptionError struct {
+	Reason string
+}
+
+func (e *InvalidSubscriptionError) Error() string {
+	return fmt.Sprintf("invalid webhook subscription: %s", e.Reason)
+}
+
+// InvalidSignatureError indicates a webhook signature did not verify
+type InvalidSignatureError struct {
+	Reason string
+}
+
+func (e *InvalidSignatureError) Error() string {
+	return fmt.Sprintf("invalid webhook signature: %s", e.Reason)
+}
@@ -0,0 +1,571 @@
+// Package notify delivers signed webhook notifications for session lifecycle
+// events to consumer-configured URLs. Events are persisted as deliveries in
+// SQLite before sending, and failed deliveries are retried with exponential
+// backoff by a background worker.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a delivery is tried before it is marked failed
+	DefaultMaxAttempts = 6
+
+	// DefaultRetryBackoff is the delay before the first retry; it doubles on each attempt
+	DefaultRetryBackoff = 30 * time.Second
+
+	// DefaultMaxRetryBackoff caps the delay between retries
+	DefaultMaxRetryBackoff = 1 * time.Hour
+
+	// DefaultPollInterval is how often the worker looks for due deliveries
+	DefaultPollInterval = 15 * time.Second
+
+	// DefaultDeliveryTimeout bounds a single webhook request
+	DefaultDeliveryTimeout = 10 * time.Second
+
+	// deliveryBatchSize limits how many due deliveries are sent per pass
+	deliveryBatchSize = 50
+
+	// maxErrorLength truncates stored delivery errors
+	maxErrorLength = 512
+)
+
+// Store defines the interface for subscription and delivery persistence
+type Store interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, consumerID string) ([]*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// Notifier queues and delivers webhook events
+type Notifier struct {
+	store  Store
+	client *http.Client
+	logger *slog.Logger
+
+	// Configuration
+	maxAttempts     int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+	pollInterval    time.Duration
+
+	// For time mocking in tests
+	now func() time.Time
+
+	// kickCh wakes the worker when new deliveries are queued
+	kickCh chan struct{}
+
+	// Shutdown coordination
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// Option configures the notifier
+type Option func(*Notifier)
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(n *Notifier) {
+		n.logger = logger
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for deliveries
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// WithMaxAttempts sets how many times a delivery is tried
+func WithMaxAttempts(attempts int) Option {
+	return func(n *Notifier) {
+		n.maxAttempts = attempts
+	}
+}
+
+// WithRetryBackoff sets the initial retry delay
+func WithRetryBackoff(d time.Duration) Option {
+	return func(n *Notifier) {
+		n.retryBackoff = d
+	}
+}
+
+// WithPollInterval sets how often the worker looks for due deliveries
+func WithPollInterval(d time.Duration) Option {
+	return func(n *Notifier) {
+		n.pollInterval = d
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(n *Notifier) {
+		n.now = fn
+	}
+}
+
+// New creates a new notifier
+func New(store Store, opts ...Option) *Notifier {
+	n := &Notifier{
+		store:           store,
+		client:          &http.Client{Timeout: DefaultDeliveryTimeout},
+		logger:          slog.Default(),
+		maxAttempts:     DefaultMaxAttempts,
+		retryBackoff:    DefaultRetryBackoff,
+		maxRetryBackoff: DefaultMaxRetryBackoff,
+		pollInterval:    DefaultPollInterval,
+		now:             time.Now,
+		kickCh:          make(chan struct{}, 1),
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+// Subscribe validates and stores a subscription. A signing secret is
+// generated when none is provided.
+func (n *Notifier) Subscribe(ctx context.Context, sub *models.WebhookSubscription) error {
+	if sub.ConsumerID == "" {
+		return &InvalidSubscriptionError{Reason: "consumer_id is required"}
+	}
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &InvalidSubscriptionError{Reason: "url must be an absolute http or https URL"}
+	}
+	for _, e := range sub.Events {
+		if !e.IsValid() {
+			return &InvalidSubscriptionError{Reason: fmt.Sprintf("unknown event type %q", e)}
+		}
+	}
+
+	if sub.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+		sub.Secret = secret
+	}
+
+	return n.store.CreateSubscription(ctx, sub)
+}
+
+// GetSubscription retrieves a subscription by ID
+func (n *Notifier) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	return n.store.GetSubscription(ctx, id)
+}
+
+// ListSubscriptions lists subscriptions, optionally for a single consumer
+func (n *Notifier) ListSubscriptions(ctx context.Context, consumerID string) ([]*models.WebhookSubscription, error) {
+	return n.store.ListSubscriptions(ctx, consumerID)
+}
+
+// Unsubscribe deletes a subscription. Pending deliveries for it are dropped by the worker.
+func (n *Notifier) Unsubscribe(ctx context.Context, id string) error {
+	return n.store.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns the delivery history of a subscription, newest first
+func (n *Notifier) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	return n.store.ListDeliveries(ctx, subscriptionID, limit)
+}
+
+// Notify queues an event for every subscription of the event's consumer that
+// wants it. Delivery happens asynchronously in the worker.
+func (n *Notifier) Notify(ctx context.Context, event models.WebhookEvent) error {
+	if event.ConsumerID == "" {
+		return nil
+	}
+
+	subs, err := n.store.ListSubscriptions(ctx, event.ConsumerID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	var targets []*models.WebhookSubscription
+	for _, sub := range subs {
+		if sub.Wants(event.Type) {
+			targets = append(targets, sub)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	now := n.now()
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = now.UTC()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	for _, sub := range targets {
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		}
+		if err := n.store.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	n.kick()
+	return nil
+}
+
+// NotifySession queues a session event. Errors are logged rather than
+// returned so callers on the provisioning path are never blocked by webhooks.
+func (n *Notifier) NotifySession(ctx context.Context, eventType models.WebhookEventType, session *models.Session) {
+	event := models.WebhookEvent{
+		Type:       eventType,
+		ConsumerID: session.ConsumerID,
+		SessionID:  session.ID,
+		Data:       session.ToResponse(),
+	}
+	if err := n.Notify(ctx, event); err != nil {
+		n.logger.Error("failed to queue webhook event",
+			slog.String("event_type", string(eventType)),
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// NotifyFailover implements provisioner.FailoverNotifier. The event is sent
+// for the replacement session, which is where the consumer should reconnect.
+func (n *Notifier) NotifyFailover(ctx context.Context, failover models.SessionFailover) {
+	event := models.WebhookEvent{
+		Type:       models.WebhookEventSessionFailedOver,
+		ConsumerID: failover.Session.ConsumerID,
+		SessionID:  failover.Session.ID,
+		Data:       failover,
+	}
+	if err := n.Notify(ctx, event); err != nil {
+		n.logger.Error("failed to queue webhook event",
+			slog.String("event_type", string(event.Type)),
+			slog.String("session_id", failover.Session.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// NotifyHostKeyChanged implements provisioner.HostKeyNotifier
+func (n *Notifier) NotifyHostKeyChanged(ctx context.Context, change models.SessionHostKeyChange) {
+	event := models.WebhookEvent{
+		Type:       models.WebhookEventHostKeyChanged,
+		ConsumerID: change.Session.ConsumerID,
+		SessionID:  change.Session.ID,
+		Data:       change,
+	}
+	if err := n.Notify(ctx, event); err != nil {
+		n.logger.Error("failed to queue webhook event",
+			slog.String("event_type", string(event.Type)),
+			slog.String("session_id", change.Session.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// NotifyDestroyFailed implements lifecycle.DestroyFailureNotifier
+func (n *Notifier) NotifyDestroyFailed(ctx context.Context, failure models.SessionDestroyFailure) {
+	event := models.WebhookEvent{
+		Type:       models.WebhookEventDestroyFailed,
+		ConsumerID: failure.Session.ConsumerID,
+		SessionID:  failure.Session.ID,
+		Data:       failure,
+	}
+	if err := n.Notify(ctx, event); err != nil {
+		n.logger.Error("failed to queue webhook event",
+			slog.String("event_type", string(event.Type)),
+			slog.String("session_id", failure.Session.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// OnSessionExpired implements lifecycle.EventHandler. The destroy that follows
+// emits session.destroyed, so nothing is sent here.
+func (n *Notifier) OnSessionExpired(session *models.Session) {}
+
+// OnHardMaxReached implements lifecycle.EventHandler. The destroy that follows
+// emits session.destroyed, so nothing is sent here.
+func (n *Notifier) OnHardMaxReached(session *models.Session) {}
+
+// OnOrphanDetected implements lifecycle.EventHandler
+func (n *Notifier) OnOrphanDetected(session *models.Session) {
+	n.NotifySession(context.Background(), models.WebhookEventOrphanDetected, session)
+}
+
+// OnSessionExpiringSoon implements lifecycle.ExpiryWarningHandler
+func (n *Notifier) OnSessionExpiringSoon(session *models.Session) {
+	n.NotifySession(context.Background(), models.WebhookEventSessionExpiringSoon, session)
+}
+
+// OnSessionIdle implements lifecycle.IdleWarningHandler
+func (n *Notifier) OnSessionIdle(warning models.SessionIdleWarning) {
+	event := models.WebhookEvent{
+		Type:       models.WebhookEventSessionIdle,
+		ConsumerID: warning.Session.ConsumerID,
+		SessionID:  warning.Session.ID,
+		Data:       warning,
+	}
+	if err := n.Notify(context.Background(), event); err != nil {
+		n.logger.Error("failed to queue webhook event",
+			slog.String("event_type", string(event.Type)),
+			slog.String("session_id", warning.Session.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// SendBudgetAlert implements budget.AlertSender. Only consumer budgets are
+// delivered, since deployment budgets do not belong to a single consumer.
+func (n *Notifier) SendBudgetAlert(ctx context.Context, alert models.BudgetAlert) error {
+	if alert.Scope != models.BudgetScopeConsumer {
+		return nil
+	}
+	return n.Notify(ctx, models.WebhookEvent{
+		Type:       models.WebhookEventBudgetAlert,
+		ConsumerID: alert.ConsumerID,
+		Data:       alert,
+	})
+}
+
+// NotifyPriceWatch implements inventory.PriceWatchNotifier
+func (n *Notifier) NotifyPriceWatch(ctx context.Context, alert models.PriceWatchAlert) error {
+	return n.Notify(ctx, models.WebhookEvent{
+		Type:       models.WebhookEventPriceWatchMatched,
+		ConsumerID: alert.Watch.ConsumerID,
+		Data:       alert,
+	})
+}
+
+// Start begins the delivery worker
+func (n *Notifier) Start(ctx context.Context) error {
+	n.mu.Lock()
+	if n.running {
+		n.mu.Unlock()
+		return nil
+	}
+	n.running = true
+	n.stopCh = make(chan struct{})
+	n.doneCh = make(chan struct{})
+	n.mu.Unlock()
+
+	n.logger.Info("webhook notifier starting",
+		slog.Duration("poll_interval", n.pollInterval),
+		slog.Int("max_attempts", n.maxAttempts))
+
+	go n.run(ctx)
+	return nil
+}
+
+// Stop gracefully stops the delivery worker
+func (n *Notifier) Stop() {
+	n.mu.Lock()
+	if !n.running {
+		n.mu.Unlock()
+		return
+	}
+	stopCh := n.stopCh
+	doneCh := n.doneCh
+	n.mu.Unlock()
+
+	n.logger.Info("webhook notifier stopping")
+	close(stopCh)
+	<-doneCh
+
+	n.mu.Lock()
+	n.running = false
+	n.mu.Unlock()
+
+	n.logger.Info("webhook notifier stopped")
+}
+
+// run is the main delivery loop
+func (n *Notifier) run(ctx context.Context) {
+	defer close(n.doneCh)
+
+	ticker := time.NewTicker(n.pollInterval)
+	defer ticker.Stop()
+
+	// Deliver anything left pending from before a restart
+	n.ProcessDue(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			n.ProcessDue(ctx)
+		case <-n.kickCh:
+			n.ProcessDue(ctx)
+		case <-n.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// kick wakes the worker without blocking
+func (n *Notifier) kick() {
+	select {
+	case n.kickCh <- struct{}{}:
+	default:
+	}
+}
+
+// ProcessDue attempts every delivery whose next attempt time has passed
+func (n *Notifier) ProcessDue(ctx context.Context) {
+	deliveries, err := n.store.ListDueDeliveries(ctx, n.now(), deliveryBatchSize)
+	if err != nil {
+		n.logger.Error("failed to list due webhook deliveries",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, d := range deliveries {
+		n.attempt(ctx, d)
+	}
+}
+
+// attempt sends one delivery and records the outcome
+func (n *Notifier) attempt(ctx context.Context, d *models.WebhookDelivery) {
+	sub, err := n.store.GetSubscription(ctx, d.SubscriptionID)
+	if err != nil {
+		// Subscription was deleted (or cannot be read); stop retrying
+		d.Status = models.WebhookDeliveryFailed
+		d.LastError = truncate("subscription unavailable: "+err.Error(), maxErrorLength)
+		n.saveDelivery(ctx, d)
+		metrics.RecordWebhookDelivery(string(d.EventType), "failed")
+		return
+	}
+
+	d.Attempts++
+	statusCode, err := n.send(ctx, sub, d)
+	d.LastStatusCode = statusCode
+
+	if err == nil {
+		d.Status = models.WebhookDeliveryDelivered
+		d.LastError = ""
+		d.DeliveredAt = n.now()
+		n.saveDelivery(ctx, d)
+		metrics.RecordWebhookDelivery(string(d.EventType), "delivered")
+		return
+	}
+
+	d.LastError = truncate(err.Error(), maxErrorLength)
+	if d.Attempts >= n.maxAttempts {
+		d.Status = models.WebhookDeliveryFailed
+		n.logger.Warn("webhook delivery failed permanently",
+			slog.String("delivery_id", d.ID),
+			slog.String("subscription_id", sub.ID),
+			slog.String("event_type", string(d.EventType)),
+			slog.Int("attempts", d.Attempts),
+			slog.String("error", d.LastError))
+		metrics.RecordWebhookDelivery(string(d.EventType), "failed")
+	} else {
+		d.NextAttemptAt = n.now().Add(n.backoff(d.Attempts))
+		n.logger.Debug("webhook delivery will be retried",
+			slog.String("delivery_id", d.ID),
+			slog.Int("attempts", d.Attempts),
+			slog.Time("next_attempt_at", d.NextAttemptAt),
+			slog.String("error", d.LastError))
+		metrics.RecordWebhookDelivery(string(d.EventType), "retry")
+	}
+	n.saveDelivery(ctx, d)
+}
+
+// send posts the signed payload and returns the response status code
+func (n *Notifier) send(ctx context.Context, sub *models.WebhookSubscription, d *models.WebhookDelivery) (int, error) {
+	body := []byte(d.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, n.now(), body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt after the given number of attempts
+func (n *Notifier) backoff(attempts int) time.Duration {
+	delay := n.retryBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= n.maxRetryBackoff {
+			return n.maxRetryBackoff
+		}
+	}
+	return delay
+}
+
+func (n *Notifier) saveDelivery(ctx context.Context, d *models.WebhookDelivery) {
+	if err := n.store.UpdateDelivery(ctx, d); err != nil {
+		n.logger.Error("failed to update webhook delivery",
+			slog.String("delivery_id", d.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// generateSecret returns a random hex signing secret
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen]
+}
@@ -0,0 +1,445 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("not found")
+
+// mockStore implements Store for testing
+type mockStore struct {
+	mu            sync.Mutex
+	subscriptions map[string]*models.WebhookSubscription
+	deliveries    map[string]*models.WebhookDelivery
+	nextID        int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		subscriptions: make(map[string]*models.WebhookSubscription),
+		deliveries:    make(map[string]*models.WebhookDelivery),
+	}
+}
+
+func (m *mockStore) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	sub.ID = fmt.Sprintf("sub-%d", m.nextID)
+	stored := *sub
+	m.subscriptions[sub.ID] = &stored
+	return nil
+}
+
+func (m *mockStore) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.subscriptions[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	copy := *sub
+	return &copy, nil
+}
+
+func (m *mockStore) ListSubscriptions(ctx context.Context, consumerID string) ([]*models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.WebhookSubscription
+	for _, sub := range m.subscriptions {
+		if consumerID == "" || sub.ConsumerID == consumerID {
+			copy := *sub
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStore) DeleteSubscription(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subscriptions[id]; !ok {
+		return errNotFound
+	}
+	delete(m.subscriptions, id)
+	return nil
+}
+
+func (m *mockStore) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	d.ID = fmt.Sprintf("del-%d", m.nextID)
+	stored := *d
+	m.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (m *mockStore) UpdateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *d
+	m.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (m *mockStore) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			copy := *d
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (m *mockStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.SubscriptionID == subscriptionID {
+			copy := *d
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStore) onlyDelivery(t *testing.T) *models.WebhookDelivery {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Len(t, m.deliveries, 1)
+	for _, d := range m.deliveries {
+		copy := *d
+		return &copy
+	}
+	return nil
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+// recordingServer captures webhook requests and replies with the configured status codes in turn
+type recordingServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []recordedRequest
+}
+
+type recordedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func (r *recordingServer) handler(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, recordedRequest{header: req.Header.Clone(), body: body})
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *recordingServer) getRequests() []recordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]recordedRequest(nil), r.requests...)
+}
+
+func TestSubscribe_Validation(t *testing.T) {
+	n := New(newMockStore(), WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		sub  models.WebhookSubscription
+	}{
+		{"missing consumer", models.WebhookSubscription{URL: "https://example.com/hook"}},
+		{"relative url", models.WebhookSubscription{ConsumerID: "c", URL: "/hook"}},
+		{"bad scheme", models.WebhookSubscription{ConsumerID: "c", URL: "ftp://example.com/hook"}},
+		{"unknown event", models.WebhookSubscription{ConsumerID: "c", URL: "https://example.com/hook",
+			Events: []models.WebhookEventType{"session.exploded"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := tt.sub
+			var invalidErr *InvalidSubscriptionError
+			assert.ErrorAs(t, n.Subscribe(ctx, &sub), &invalidErr)
+		})
+	}
+
+	sub := &models.WebhookSubscription{ConsumerID: "c", URL: "https://example.com/hook"}
+	require.NoError(t, n.Subscribe(ctx, sub))
+	assert.Len(t, sub.Secret, 64, "secret should be generated")
+}
+
+func TestNotify_DeliversSignedEvent(t *testing.T) {
+	rec := &recordingServer{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	store := newMockStore()
+	n := New(store, WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	sub := &models.WebhookSubscription{
+		ConsumerID: "consumer-001",
+		URL:        srv.URL,
+		Secret:     "s3cret",
+		Events:     []models.WebhookEventType{models.WebhookEventSessionRunning},
+	}
+	require.NoError(t, n.Subscribe(ctx, sub))
+
+	session := &models.Session{ID: "sess-1", ConsumerID: "consumer-001", Status: models.StatusRunning}
+
+	// Not subscribed to this event type
+	n.NotifySession(ctx, models.WebhookEventSessionCreated, session)
+	// Different consumer
+	n.NotifySession(ctx, models.WebhookEventSessionRunning, &models.Session{ID: "sess-2", ConsumerID: "consumer-002"})
+	// Wanted
+	n.NotifySession(ctx, models.WebhookEventSessionRunning, session)
+
+	n.ProcessDue(ctx)
+
+	requests := rec.getRequests()
+	require.Len(t, requests, 1)
+	req := requests[0]
+
+	assert.Equal(t, "session.running", req.header.Get(HeaderEvent))
+	assert.NotEmpty(t, req.header.Get(HeaderDelivery))
+	assert.NoError(t, Verify("s3cret", req.header.Get(HeaderSignature), req.body, time.Minute, time.Now()))
+	assert.Error(t, Verify("wrong", req.header.Get(HeaderSignature), req.body, time.Minute, time.Now()))
+
+	var event models.WebhookEvent
+	require.NoError(t, json.Unmarshal(req.body, &event))
+	assert.Equal(t, models.WebhookEventSessionRunning, event.Type)
+	assert.Equal(t, "consumer-001", event.ConsumerID)
+	assert.Equal(t, "sess-1", event.SessionID)
+	assert.NotEmpty(t, event.ID)
+
+	d := store.onlyDelivery(t)
+	assert.Equal(t, models.WebhookDeliveryDelivered, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusOK, d.LastStatusCode)
+	assert.False(t, d.DeliveredAt.IsZero())
+}
+
+func TestNotifyFailover(t *testing.T) {
+	rec := &recordingServer{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	store := newMockStore()
+	n := New(store, WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	require.NoError(t, n.Subscribe(ctx, &models.WebhookSubscription{
+		ConsumerID: "consumer-001",
+		URL:        srv.URL,
+		Events:     []models.WebhookEventType{models.WebhookEventSessionFailedOver},
+	}))
+
+	replacement := &models.Session{ID: "sess-2", ConsumerID: "consumer-001", Status: models.StatusRunning, SSHHost: "10.0.0.2", SSHPort: 22}
+	n.NotifyFailover(ctx, models.SessionFailover{
+		PreemptedSessionID: "sess-1",
+		Session:            replacement.ToResponse(),
+	})
+	n.ProcessDue(ctx)
+
+	requests := rec.getRequests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "session.failed_over", requests[0].header.Get(HeaderEvent))
+
+	var event struct {
+		SessionID string                 `json:"session_id"`
+		Data      models.SessionFailover `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(requests[0].body, &event))
+	assert.Equal(t, "sess-2", event.SessionID)
+	assert.Equal(t, "sess-1", event.Data.PreemptedSessionID)
+	assert.Equal(t, "10.0.0.2", event.Data.Session.SSHHost)
+	assert.NotContains(t, string(requests[0].body), "ssh_private_key")
+}
+
+func TestNotify_RetriesWithBackoff(t *testing.T) {
+	rec := &recordingServer{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	n := New(store,
+		WithLogger(newTestLogger()),
+		WithRetryBackoff(time.Minute),
+		WithTimeFunc(func() time.Time { return now }))
+	ctx := context.Background()
+
+	require.NoError(t, n.Subscribe(ctx, &models.WebhookSubscription{ConsumerID: "consumer-001", URL: srv.URL}))
+	n.NotifySession(ctx, models.WebhookEventSessionFailed, &models.Session{ID: "sess-1", ConsumerID: "consumer-001"})
+
+	// First attempt fails; retry in 1 minute
+	n.ProcessDue(ctx)
+	d := store.onlyDelivery(t)
+	assert.Equal(t, models.WebhookDeliveryPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, d.LastStatusCode)
+	assert.Equal(t, now.Add(time.Minute), d.NextAttemptAt)
+
+	// Not yet due
+	n.ProcessDue(ctx)
+	assert.Len(t, rec.getRequests(), 1)
+
+	// Second attempt fails; backoff doubles
+	now = now.Add(time.Minute)
+	n.ProcessDue(ctx)
+	d = store.onlyDelivery(t)
+	assert.Equal(t, 2, d.Attempts)
+	assert.Equal(t, now.Add(2*time.Minute), d.NextAttemptAt)
+
+	// Third attempt succeeds
+	now = now.Add(2 * time.Minute)
+	n.ProcessDue(ctx)
+	d = store.onlyDelivery(t)
+	assert.Equal(t, models.WebhookDeliveryDelivered, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+	assert.Empty(t, d.LastError)
+	assert.Len(t, rec.getRequests(), 3)
+}
+
+func TestNotify_GivesUpAfterMaxAttempts(t *testing.T) {
+	rec := &recordingServer{statuses: []int{500, 500, 500}}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	now := time.Now()
+	store := newMockStore()
+	n := New(store,
+		WithLogger(newTestLogger()),
+		WithMaxAttempts(2),
+		WithRetryBackoff(time.Second),
+		WithTimeFunc(func() time.Time { return now }))
+	ctx := context.Background()
+
+	require.NoError(t, n.Subscribe(ctx, &models.WebhookSubscription{ConsumerID: "consumer-001", URL: srv.URL}))
+	n.NotifySession(ctx, models.WebhookEventSessionCreated, &models.Session{ID: "sess-1", ConsumerID: "consumer-001"})
+
+	n.ProcessDue(ctx)
+	now = now.Add(time.Second)
+	n.ProcessDue(ctx)
+
+	d := store.onlyDelivery(t)
+	assert.Equal(t, models.WebhookDeliveryFailed, d.Status)
+	assert.Equal(t, 2, d.Attempts)
+	assert.Contains(t, d.LastError, "status 500")
+
+	// Failed deliveries are not retried
+	now = now.Add(time.Hour)
+	n.ProcessDue(ctx)
+	assert.Len(t, rec.getRequests(), 2)
+}
+
+func TestNotify_DropsDeliveriesForDeletedSubscription(t *testing.T) {
+	store := newMockStore()
+	n := New(store, WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	sub := &models.WebhookSubscription{ConsumerID: "consumer-001", URL: "https://example.invalid/hook"}
+	require.NoError(t, n.Subscribe(ctx, sub))
+	n.NotifySession(ctx, models.WebhookEventSessionCreated, &models.Session{ID: "sess-1", ConsumerID: "consumer-001"})
+	require.NoError(t, n.Unsubscribe(ctx, sub.ID))
+
+	n.ProcessDue(ctx)
+
+	d := store.onlyDelivery(t)
+	assert.Equal(t, models.WebhookDeliveryFailed, d.Status)
+	assert.Equal(t, 0, d.Attempts)
+}
+
+func TestBackoff_Capped(t *testing.T) {
+	n := New(newMockStore(), WithRetryBackoff(30*time.Second))
+
+	assert.Equal(t, 30*time.Second, n.backoff(1))
+	assert.Equal(t, time.Minute, n.backoff(2))
+	assert.Equal(t, 2*time.Minute, n.backoff(3))
+	assert.Equal(t, DefaultMaxRetryBackoff, n.backoff(20))
+}
+
+func TestSendBudgetAlert_OnlyConsumerScope(t *testing.T) {
+	store := newMockStore()
+	n := New(store, WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	require.NoError(t, n.Subscribe(ctx, &models.WebhookSubscription{ConsumerID: "consumer-001", URL: "https://example.com/hook"}))
+
+	require.NoError(t, n.SendBudgetAlert(ctx, models.BudgetAlert{
+		ConsumerID: "default", Scope: models.BudgetScopeDeployment, AlertType: "warning",
+	}))
+	assert.Empty(t, store.deliveries)
+
+	require.NoError(t, n.SendBudgetAlert(ctx, models.BudgetAlert{
+		ConsumerID: "consumer-001", Scope: models.BudgetScopeConsumer, AlertType: "exceeded",
+	}))
+	d := store.onlyDelivery(t)
+	assert.Equal(t, models.WebhookEventBudgetAlert, d.EventType)
+	assert.Contains(t, d.Payload, `"alert_type":"exceeded"`)
+}
+
+func TestVerify_Tolerance(t *testing.T) {
+	body := []byte(`{"id":"evt-1"}`)
+	sent := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	header := Sign("secret", sent, body)
+
+	assert.NoError(t, Verify("secret", header, body, 5*time.Minute, sent.Add(time.Minute)))
+	assert.Error(t, Verify("secret", header, body, 5*time.Minute, sent.Add(10*time.Minute)))
+	assert.NoError(t, Verify("secret", header, body, 0, sent.Add(24*time.Hour)))
+	assert.Error(t, Verify("secret", header, []byte(`{"id":"evt-2"}`), 0, sent))
+	assert.Error(t, Verify("secret", "garbage", body, 0, sent))
+}
+
+func TestStartStop_DeliversQueuedEvents(t *testing.T) {
+	rec := &recordingServer{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	n := New(newMockStore(), WithLogger(newTestLogger()), WithPollInterval(time.Hour))
+	ctx := context.Background()
+	require.NoError(t, n.Subscribe(ctx, &models.WebhookSubscription{ConsumerID: "consumer-001", URL: srv.URL}))
+
+	require.NoError(t, n.Start(ctx))
+	defer n.Stop()
+
+	// Queuing wakes the worker without waiting for the poll interval
+	n.NotifySession(ctx, models.WebhookEventSessionCreated, &models.Session{ID: "sess-1", ConsumerID: "consumer-001"})
+
+	require.Eventually(t, func() bool {
+		return len(rec.getRequests()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every webhook request
+const (
+	HeaderSignature = "X-Shopper-Signature"
+	HeaderEvent     = "X-Shopper-Event"
+	HeaderDelivery  = "X-Shopper-Delivery"
+)
+
+// Sign returns the signature header value for a payload sent at timestamp.
+// The format is "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, computeSignature(secret, ts, body))
+}
+
+// Verify checks a signature header against the body. Signatures older than
+// tolerance are rejected to limit replay; a zero tolerance skips the age check.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	if ts == "" || sig == "" {
+		return &InvalidSignatureError{Reason: "malformed header"}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return &InvalidSignatureError{Reason: "malformed timestamp"}
+	}
+	if tolerance > 0 && now.Sub(time.Unix(unix, 0)) > tolerance {
+		return &InvalidSignatureError{Reason: "timestamp outside tolerance"}
+	}
+
+	expected := computeSignature(secret, ts, body)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return &InvalidSignatureError{Reason: "signature mismatch"}
+	}
+
+	return nil
+}
+
+func computeSignature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
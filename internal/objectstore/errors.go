@@ -0,0 +1,23 @@
+package objectstore
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// APIError is an error response from the object store
+type APIError struct {
+	StatusCode int
+	Code       string // S3 error code, e.g. "AccessDenied"; empty if the body had none
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("object store returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("object store returned status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
@@ -0,0 +1,424 @@
+// Package objectstore is a minimal client for S3-compatible object storage:
+// AWS S3, Cloudflare R2, Backblaze B2, MinIO and the like. It covers what
+// workspace snapshots and the model cache need - multipart uploads whose
+// parts are sent by another host through presigned URLs, presigned
+// downloads, listing, and stat.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRegion is signed into requests when no region is configured.
+	// Stores without regions, such as MinIO, accept it.
+	DefaultRegion = "us-east-1"
+
+	// DefaultTimeout bounds each request the client makes itself
+	DefaultTimeout = 60 * time.Second
+
+	amzDateFormat   = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Client signs requests to one bucket with AWS Signature Version 4. The
+// bucket is addressed by path (endpoint/bucket/key), which every
+// S3-compatible store accepts.
+type Client struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithRegion sets the region requests are signed for
+func WithRegion(region string) Option {
+	return func(c *Client) {
+		if region != "" {
+			c.region = region
+		}
+	}
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// New creates a client for bucket at endpoint, e.g.
+// "https://s3.us-east-1.amazonaws.com"
+func New(endpoint, bucket, accessKey, secretKey string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid object store endpoint %q", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket cannot be empty")
+	}
+	c := &Client{
+		endpoint:   u,
+		region:     DefaultRegion,
+		bucket:     bucket,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// objectURL returns the URL of key, with query parameters
+func (c *Client) objectURL(key string, query url.Values) *url.URL {
+	u := *c.endpoint
+	u.Path = u.Path + "/" + c.bucket + "/" + key
+	u.RawPath = encodePath(u.Path)
+	u.RawQuery = encodeQuery(query)
+	return &u
+}
+
+// PresignGet returns a URL that downloads key until it expires
+func (c *Client) PresignGet(key string, expires time.Duration) string {
+	return c.presign(http.MethodGet, c.objectURL(key, nil), c.now(), expires)
+}
+
+// PresignUploadPart returns a URL that uploads part number part (from 1) of
+// a multipart upload with a PUT, until it expires. The response's ETag
+// header is needed to complete the upload.
+func (c *Client) PresignUploadPart(key, uploadID string, part int, expires time.Duration) string {
+	query := url.Values{"partNumber": {strconv.Itoa(part)}, "uploadId": {uploadID}}
+	return c.presign(http.MethodPut, c.objectURL(key, query), c.now(), expires)
+}
+
+// CreateMultipartUpload starts a multipart upload of key and returns its ID
+func (c *Client) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	body, err := c.do(ctx, http.MethodPost, c.objectURL(key, url.Values{"uploads": {""}}), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("failed to create multipart upload: unexpected response %q", truncate(body))
+	}
+	return result.UploadID, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts, given their ETags in
+// part order, into key. key is replaced only once this succeeds.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, key, uploadID string, etags []string) error {
+	type part struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	var request struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}
+	for i, etag := range etags {
+		request.Parts = append(request.Parts, part{PartNumber: i + 1, ETag: etag})
+	}
+	payload, err := xml.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode parts: %w", err)
+	}
+
+	body, err := c.do(ctx, http.MethodPost, c.objectURL(key, url.Values{"uploadId": {uploadID}}), payload)
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	// S3 can report a failure in the body of a 200 response
+	if apiErr := parseAPIError(http.StatusOK, body); apiErr.Code != "" {
+		return fmt.Errorf("failed to complete multipart upload: %w", apiErr)
+	}
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its parts
+func (c *Client) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	if _, err := c.do(ctx, http.MethodDelete, c.objectURL(key, url.Values{"uploadId": {uploadID}}), nil); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+// Stat returns the size of key in bytes, or ErrNotFound
+func (c *Client) Stat(ctx context.Context, key string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.objectURL(key, nil).String(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.sign(req, emptyPayloadHash, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("stat request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, &APIError{StatusCode: resp.StatusCode}
+	}
+	return resp.ContentLength, nil
+}
+
+// Object is an entry of a bucket listing
+type Object struct {
+	Key  string
+	Size int64
+}
+
+// List returns every object whose key starts with prefix, in key order
+func (c *Client) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := c.do(ctx, http.MethodGet, c.objectURL("", query), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		var result struct {
+			Contents []struct {
+				Key  string `xml:"Key"`
+				Size int64  `xml:"Size"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to list objects: unexpected response %q", truncate(body))
+		}
+		for _, o := range result.Contents {
+			objects = append(objects, Object{Key: o.Key, Size: o.Size})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request and returns the response body, or an APIError
+// for a non-2xx status
+func (c *Client) do(ctx context.Context, method string, u *url.URL, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	c.sign(req, hashHex(payload), c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound && method != http.MethodDelete {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, parseAPIError(resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// parseAPIError reads an S3 XML error body
+func parseAPIError(status int, body []byte) *APIError {
+	var e struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}
+	apiErr := &APIError{StatusCode: status}
+	if xml.Unmarshal(body, &e) == nil {
+		apiErr.Code, apiErr.Message = e.Code, e.Message
+	}
+	return apiErr
+}
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+var emptyPayloadHash = hashHex(nil)
+
+// sign adds Signature Version 4 authorization headers to req. Every header
+// already set is signed, along with the host.
+func (c *Client) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		encodeQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, c.scope(t), signedHeaders, c.signature(t, canonicalRequest)))
+}
+
+// presign returns u with Signature Version 4 query authorization, valid
+// for expires
+func (c *Client) presign(method string, u *url.URL, t time.Time, expires time.Duration) string {
+	t = t.UTC()
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", c.accessKey+"/"+c.scope(t))
+	query.Set("X-Amz-Date", t.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		encodePath(u.Path),
+		encodeQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", c.signature(t, canonicalRequest))
+	signed := *u
+	signed.RawQuery = encodeQuery(query)
+	return signed.String()
+}
+
+// scope is the credential scope of requests signed at t
+func (c *Client) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + c.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request made at t
+func (c *Client) signature(t time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		t.Format(amzDateFormat),
+		c.scope(t),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// encodeQuery encodes query parameters sorted by name, escaping everything
+// but unreserved characters as Signature Version 4 requires
+func encodeQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// encodePath escapes a URL path for signing, keeping its slashes
+func encodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return uriEncode(path, false)
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+// truncate shortens a response body for error messages
+func truncate(body []byte) string {
+	if len(body) > 200 {
+		return string(body[:200]) + "..."
+	}
+	return string(body)
+}
@@ -10,12 +10,10 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
 
-	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
@@ -32,160 +30,12 @@ const (
 // taskPollTimeout is a var (not const) so tests can override it for fast unit tests.
 var taskPollTimeout = 5 * time.Minute
 
-// CircuitBreakerState represents the current state of the circuit breaker
-type CircuitBreakerState int
-
-const (
-	// CircuitClosed is the normal operating state - requests are allowed
-	CircuitClosed CircuitBreakerState = iota
-	// CircuitOpen means too many failures occurred - requests are blocked
-	CircuitOpen
-	// CircuitHalfOpen allows a test request through to check if service recovered
-	CircuitHalfOpen
-)
-
-// CircuitBreakerConfig configures the circuit breaker behavior
-type CircuitBreakerConfig struct {
-	// FailureThreshold is the number of consecutive failures before opening the circuit
-	FailureThreshold int
-	// ResetTimeout is how long to wait before transitioning from Open to HalfOpen
-	ResetTimeout time.Duration
-	// MaxBackoff is the maximum backoff duration for exponential backoff
-	MaxBackoff time.Duration
-	// BaseBackoff is the initial backoff duration
-	BaseBackoff time.Duration
-}
-
-// DefaultCircuitBreakerConfig returns sensible defaults for the circuit breaker
-func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
-	return CircuitBreakerConfig{
-		FailureThreshold: 5,
-		ResetTimeout:     30 * time.Second,
-		MaxBackoff:       2 * time.Minute,
-		BaseBackoff:      1 * time.Second,
-	}
-}
-
-// circuitBreaker implements a simple circuit breaker pattern with exponential backoff
-type circuitBreaker struct {
-	mu               sync.Mutex
-	state            CircuitBreakerState
-	failures         int
-	lastFailure      time.Time
-	lastStateChange  time.Time
-	config           CircuitBreakerConfig
-	consecutiveWaits int // For exponential backoff
-}
-
-// newCircuitBreaker creates a new circuit breaker with the given configuration
-func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
-	return &circuitBreaker{
-		state:  CircuitClosed,
-		config: config,
-	}
-}
-
-// allow returns true if a request should be allowed, false if circuit is open
-func (cb *circuitBreaker) allow() bool {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	switch cb.state {
-	case CircuitClosed:
-		return true
-	case CircuitOpen:
-		// Check if we should transition to half-open
-		if time.Since(cb.lastStateChange) > cb.config.ResetTimeout {
-			cb.state = CircuitHalfOpen
-			cb.lastStateChange = time.Now()
-			return true
-		}
-		return false
-	case CircuitHalfOpen:
-		// Allow one test request
-		return true
-	default:
-		return true
-	}
-}
-
-// recordSuccess records a successful request
-func (cb *circuitBreaker) recordSuccess() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.failures = 0
-	cb.consecutiveWaits = 0
-	if cb.state == CircuitHalfOpen {
-		cb.state = CircuitClosed
-		cb.lastStateChange = time.Now()
-	}
-}
-
-// recordFailure records a failed request and potentially opens the circuit
-func (cb *circuitBreaker) recordFailure() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.failures++
-	cb.lastFailure = time.Now()
-
-	if cb.state == CircuitHalfOpen {
-		// Failed while testing - go back to open
-		cb.state = CircuitOpen
-		cb.lastStateChange = time.Now()
-		cb.consecutiveWaits++
-		return
-	}
-
-	if cb.failures >= cb.config.FailureThreshold {
-		cb.state = CircuitOpen
-		cb.lastStateChange = time.Now()
-		cb.consecutiveWaits++
-	}
-}
-
-// getBackoff returns the current backoff duration using exponential backoff
-func (cb *circuitBreaker) getBackoff() time.Duration {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	if cb.consecutiveWaits == 0 {
-		return cb.config.BaseBackoff
-	}
-
-	// Cap consecutiveWaits to prevent integer overflow in bit shift
-	waits := cb.consecutiveWaits
-	const maxShift = 10
-	if waits > maxShift {
-		waits = maxShift
-	}
-
-	// Exponential backoff: base * 2^(waits-1), capped at maxBackoff
-	backoff := cb.config.BaseBackoff * time.Duration(1<<uint(waits-1))
-	if backoff > cb.config.MaxBackoff {
-		backoff = cb.config.MaxBackoff
-	}
-	return backoff
-}
-
-// State returns the current circuit breaker state (for monitoring/testing)
-func (cb *circuitBreaker) State() CircuitBreakerState {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	return cb.state
-}
-
-// ErrCircuitOpen is returned when the circuit breaker is open
-var ErrCircuitOpen = errors.New("circuit breaker is open")
-
 // Client implements the provider.Provider interface for Blue Lobster
 type Client struct {
 	apiKey          string
 	baseURL         string
 	httpClient      *http.Client
 	limiter         *rate.Limiter
-	circuitBreaker  *circuitBreaker
 	logger          *slog.Logger
 	defaultTemplate string
 }
@@ -214,13 +64,6 @@ func WithRateLimit(r rate.Limit, burst int) ClientOption {
 	}
 }
 
-// WithCircuitBreaker configures the circuit breaker for API calls
-func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
-	return func(c *Client) {
-		c.circuitBreaker = newCircuitBreaker(config)
-	}
-}
-
 // WithLogger sets a custom structured logger
 func WithLogger(logger *slog.Logger) ClientOption {
 	return func(c *Client) {
@@ -242,7 +85,6 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 		baseURL:         defaultBaseURL,
 		httpClient:      &http.Client{Timeout: defaultTimeout},
 		limiter:         rate.NewLimiter(2, 3), // 2 req/s, burst 3
-		circuitBreaker:  newCircuitBreaker(DefaultCircuitBreakerConfig()),
 		logger:          slog.Default(),
 		defaultTemplate: defaultTemplate,
 	}
@@ -264,6 +106,8 @@ func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
 	switch feature {
 	case provider.FeatureInstanceTags:
 		return false // BL-007: metadata not persisted by API
+	case provider.FeatureSSHKeyRegistration:
+		return true // The launch request's ssh_key is installed on the VM
 	default:
 		return false
 	}
@@ -271,12 +115,6 @@ func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
 
 // ListOffers returns available GPU offers from Blue Lobster
 func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (offers []models.GPUOffer, err error) {
-	startTime := time.Now()
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListOffers", startTime, err)
-	}()
-
 	var resp AvailableResponse
 	if err = c.doRequest(ctx, http.MethodGet, "/instances/available", nil, &resp); err != nil {
 		return nil, fmt.Errorf("bluelobster: ListOffers: %w", err)
@@ -307,12 +145,6 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 
 // ListAllInstances returns all instances with our tags (for reconciliation)
 func (c *Client) ListAllInstances(ctx context.Context) (instances []provider.ProviderInstance, err error) {
-	startTime := time.Now()
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListAllInstances", startTime, err)
-	}()
-
 	// The /instances endpoint returns a plain array of VMInstance
 	var vms []VMInstance
 	if err = c.doRequest(ctx, http.MethodGet, "/instances", nil, &vms); err != nil {
@@ -404,12 +236,6 @@ func (c *Client) ListAllInstances(ctx context.Context) (instances []provider.Pro
 
 // CreateInstance provisions a new GPU instance
 func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstanceRequest) (info *provider.InstanceInfo, err error) {
-	startTime := time.Now()
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("CreateInstance", startTime, err)
-	}()
-
 	// Parse the offer ID to extract instance type and region
 	instanceType, region, err := parseOfferID(req.OfferID)
 	if err != nil {
@@ -551,12 +377,6 @@ func (c *Client) getInstanceInfo(ctx context.Context, instanceID string) (*provi
 
 // DestroyInstance tears down a GPU instance
 func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err error) {
-	startTime := time.Now()
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("DestroyInstance", startTime, err)
-	}()
-
 	if err := validateInstanceID(instanceID); err != nil {
 		return fmt.Errorf("bluelobster: DestroyInstance: %w", err)
 	}
@@ -583,12 +403,6 @@ func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err er
 
 // GetInstanceStatus returns current status of an instance
 func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (status *provider.InstanceStatus, err error) {
-	startTime := time.Now()
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("GetInstanceStatus", startTime, err)
-	}()
-
 	if err := validateInstanceID(instanceID); err != nil {
 		return nil, fmt.Errorf("bluelobster: GetInstanceStatus: %w", err)
 	}
@@ -639,75 +453,9 @@ func (c *Client) rateLimit(ctx context.Context) error {
 	return c.limiter.Wait(ctx)
 }
 
-// checkCircuitBreaker returns an error if the circuit breaker is open
-func (c *Client) checkCircuitBreaker() error {
-	if !c.circuitBreaker.allow() {
-		backoff := c.circuitBreaker.getBackoff()
-		c.logger.Warn("circuit breaker is open", "provider", "bluelobster", "backoff", backoff)
-		return fmt.Errorf("%w: retry after %v", ErrCircuitOpen, backoff)
-	}
-	return nil
-}
-
-// recordAPIResult records the result of an API call to the circuit breaker
-func (c *Client) recordAPIResult(err error) {
-	if err == nil {
-		c.circuitBreaker.recordSuccess()
-		return
-	}
-
-	// Only count certain errors as failures for the circuit breaker
-	// Don't count validation errors, not found errors, etc.
-	var providerErr *provider.ProviderError
-	if errors.As(err, &providerErr) {
-		// Rate limits and server errors should trigger circuit breaker
-		if providerErr.StatusCode >= 500 || providerErr.StatusCode == 429 {
-			c.circuitBreaker.recordFailure()
-			return
-		}
-	}
-
-	// Network errors should trigger circuit breaker
-	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-		// Don't trigger for context cancellation by caller
-		return
-	}
-
-	// Other network-level errors
-	if strings.Contains(err.Error(), "connection refused") ||
-		strings.Contains(err.Error(), "no such host") ||
-		strings.Contains(err.Error(), "network is unreachable") {
-		c.circuitBreaker.recordFailure()
-	}
-}
-
-// recordAPIMetrics records API call metrics including response time and call count
-func (c *Client) recordAPIMetrics(operation string, startTime time.Time, err error) {
-	duration := time.Since(startTime)
-	metrics.RecordProviderAPIResponseTime("bluelobster", operation, duration)
-
-	status := "success"
-	if err != nil {
-		if errors.Is(err, ErrCircuitOpen) {
-			status = "circuit_open"
-		} else {
-			status = "error"
-		}
-	}
-	metrics.RecordProviderAPICall("bluelobster", operation, status)
-
-	// Update circuit breaker state metric
-	metrics.UpdateProviderCircuitBreakerState("bluelobster", int(c.circuitBreaker.State()))
-}
-
-// doRequest performs a full HTTP request lifecycle: check circuit breaker, rate limit,
-// build request with X-API-Key header, execute, read body, handle errors, unmarshal JSON.
+// doRequest performs a full HTTP request lifecycle: rate limit, build request
+// with X-API-Key header, execute, read body, handle errors, unmarshal JSON.
 func (c *Client) doRequest(ctx context.Context, method, path string, body io.Reader, result interface{}) error {
-	// Check circuit breaker
-	if err := c.checkCircuitBreaker(); err != nil {
-		return err
-	}
-
 	// Rate limit
 	if err := c.rateLimit(ctx); err != nil {
 		return fmt.Errorf("rate limit wait: %w", err)
@@ -732,8 +480,6 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		// Note: Do NOT call recordAPIResult here — callers record via defer
-		// to avoid double-counting against the circuit breaker threshold.
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// APIHealthWindow is how far back APIStats counts calls for error rates
+const APIHealthWindow = 15 * time.Minute
+
+// Circuit breaker states reported in APIHealth
+const (
+	CircuitStateClosed   = "closed"
+	CircuitStateOpen     = "open"
+	CircuitStateHalfOpen = "half-open"
+)
+
+// CircuitStateName names a circuit breaker state in the numbering the
+// clients share with the circuit breaker metric (0 closed, 1 open, 2 half-open)
+func CircuitStateName(state int) string {
+	switch state {
+	case 1:
+		return CircuitStateOpen
+	case 2:
+		return CircuitStateHalfOpen
+	}
+	return CircuitStateClosed
+}
+
+// HealthReporter is an optional interface for providers that track the
+// health of their API.
+type HealthReporter interface {
+	APIHealth() APIHealth
+}
+
+// APIHealth summarizes a provider API's recent behaviour.
+type APIHealth struct {
+	CircuitState   string    // CircuitStateClosed, CircuitStateOpen or CircuitStateHalfOpen
+	LastSuccessAt  time.Time // Zero if no call has succeeded since startup
+	LastErrorAt    time.Time // Zero if no call has failed since startup
+	LastError      string
+	RecentCalls    int // Calls within APIHealthWindow
+	RecentFailures int // Failed calls within APIHealthWindow
+}
+
+// ErrorRate returns the share of recent calls that failed, or 0 without
+// recent calls.
+func (h APIHealth) ErrorRate() float64 {
+	if h.RecentCalls == 0 {
+		return 0
+	}
+	return float64(h.RecentFailures) / float64(h.RecentCalls)
+}
+
+// APIStats records the outcome of a provider client's API calls in
+// one-minute buckets covering APIHealthWindow. It is safe for concurrent use.
+type APIStats struct {
+	mu            sync.Mutex
+	buckets       [int(APIHealthWindow / time.Minute)]apiStatsBucket
+	lastSuccessAt time.Time
+	lastErrorAt   time.Time
+	lastError     string
+
+	// For time mocking in tests
+	now func() time.Time
+}
+
+type apiStatsBucket struct {
+	minute   int64 // Unix minute the counts belong to
+	calls    int
+	failures int
+}
+
+// Record counts an API call that returned err. Errors meaning the provider
+// is unavailable (server errors, rate limits, rejected credentials, timeouts
+// and network failures) count as failures; errors about the request itself,
+// such as an instance not being found, mean the API is working. Calls
+// cancelled by the caller are not counted.
+func (s *APIStats) Record(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	failed := err != nil && isAvailabilityError(err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timeNow()
+	minute := now.Unix() / 60
+	b := &s.buckets[minute%int64(len(s.buckets))]
+	if b.minute != minute {
+		*b = apiStatsBucket{minute: minute}
+	}
+	b.calls++
+	if failed {
+		b.failures++
+		s.lastErrorAt = now
+		s.lastError = err.Error()
+	} else {
+		s.lastSuccessAt = now
+	}
+}
+
+// Health returns the recorded stats with the given circuit breaker state.
+func (s *APIStats) Health(circuitState string) APIHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := APIHealth{
+		CircuitState:  circuitState,
+		LastSuccessAt: s.lastSuccessAt,
+		LastErrorAt:   s.lastErrorAt,
+		LastError:     s.lastError,
+	}
+	oldest := s.timeNow().Unix()/60 - int64(len(s.buckets)) + 1
+	for _, b := range s.buckets {
+		if b.minute >= oldest {
+			health.RecentCalls += b.calls
+			health.RecentFailures += b.failures
+		}
+	}
+	return health
+}
+
+func (s *APIStats) timeNow() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// isAvailabilityError reports whether err means the provider API could not
+// serve the request, rather than that the request itself was rejected
+func isAvailabilityError(err error) bool {
+	if IsRateLimitError(err) || IsAuthError(err) {
+		return true
+	}
+	if errors.Is(err, ErrInstanceNotFound) || errors.Is(err, ErrTemplateNotFound) ||
+		errors.Is(err, ErrOfferUnavailable) || errors.Is(err, ErrOfferStaleInventory) {
+		return false
+	}
+	var pe *ProviderError
+	if errors.As(err, &pe) && pe.StatusCode > 0 {
+		return pe.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIStats_Record(t *testing.T) {
+	now := time.Date(2026, 1, 29, 12, 0, 30, 0, time.UTC)
+	stats := &APIStats{now: func() time.Time { return now }}
+
+	stats.Record(nil)
+	stats.Record(NewProviderError("vastai", "list_offers", http.StatusBadGateway, "bad gateway", nil))
+	stats.Record(NewProviderError("vastai", "list_offers", http.StatusTooManyRequests, "slow down", nil))
+	stats.Record(fmt.Errorf("get status: %w", ErrInstanceNotFound))                          // API answered
+	stats.Record(NewProviderError("vastai", "get_status", http.StatusNotFound, "gone", nil)) // API answered
+	stats.Record(context.Canceled)                                                           // Not counted
+
+	health := stats.Health(CircuitStateClosed)
+	assert.Equal(t, CircuitStateClosed, health.CircuitState)
+	assert.Equal(t, 5, health.RecentCalls)
+	assert.Equal(t, 2, health.RecentFailures)
+	assert.InDelta(t, 0.4, health.ErrorRate(), 0.001)
+	assert.Equal(t, now, health.LastSuccessAt)
+	assert.Equal(t, now, health.LastErrorAt)
+	assert.Contains(t, health.LastError, "HTTP 429")
+
+	// Calls age out of the window; the last success and error are kept
+	now = now.Add(APIHealthWindow)
+	stats.Record(errors.New("connection refused"))
+	health = stats.Health(CircuitStateOpen)
+	assert.Equal(t, 1, health.RecentCalls)
+	assert.Equal(t, 1, health.RecentFailures)
+	assert.Equal(t, "connection refused", health.LastError)
+	assert.False(t, health.LastSuccessAt.IsZero())
+
+	now = now.Add(APIHealthWindow)
+	assert.Zero(t, stats.Health(CircuitStateClosed).RecentCalls)
+	assert.Zero(t, APIHealth{}.ErrorRate())
+}
+
+func TestCircuitStateName(t *testing.T) {
+	assert.Equal(t, CircuitStateClosed, CircuitStateName(0))
+	assert.Equal(t, CircuitStateOpen, CircuitStateName(1))
+	assert.Equal(t, CircuitStateHalfOpen, CircuitStateName(2))
+}
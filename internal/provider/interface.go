@@ -29,6 +29,21 @@ const (
 	FeatureInstanceTags  ProviderFeature = "instance_tags"
 	FeatureSpotPricing   ProviderFeature = "spot_pricing"
 	FeatureCustomImages  ProviderFeature = "custom_images"
+
+	// FeatureSSHKeyRegistration means the provider installs
+	// CreateInstanceRequest.SSHPublicKey through its own API, so the instance
+	// accepts the key as soon as sshd is up, without a cloud-init workaround
+	FeatureSSHKeyRegistration ProviderFeature = "ssh_key_registration"
+
+	// FeatureOfferQueryFilters means ListOffers passes the GPU type,
+	// location and interruptible filters to the provider API, which can
+	// return offers its unfiltered listing leaves out. Providers without it
+	// fetch their whole catalogue and filter it locally.
+	FeatureOfferQueryFilters ProviderFeature = "offer_query_filters"
+
+	// FeatureCloudInit means CreateInstance applies
+	// CreateInstanceRequest.CloudInit to the instance's cloud-init user data
+	FeatureCloudInit ProviderFeature = "cloud_init"
 )
 
 // LaunchMode determines how the instance is configured
@@ -45,14 +60,25 @@ const (
 type WorkloadType string
 
 const (
-	WorkloadTypeVLLM   WorkloadType = "vllm"
-	WorkloadTypeTGI    WorkloadType = "tgi"
-	WorkloadTypeCustom WorkloadType = "custom"
+	WorkloadTypeVLLM     WorkloadType = "vllm"
+	WorkloadTypeTGI      WorkloadType = "tgi"
+	WorkloadTypeSGLang   WorkloadType = "sglang"
+	WorkloadTypeLlamaCpp WorkloadType = "llamacpp" // llama.cpp server, serving GGUF models
+	WorkloadTypeCustom   WorkloadType = "custom"
 )
 
+// HealthPath is the route polled to verify a workload's API. SGLang's
+// /health answers before the model can generate, so its deeper check is used.
+func (t WorkloadType) HealthPath() string {
+	if t == WorkloadTypeSGLang {
+		return "/health_generate"
+	}
+	return "/health"
+}
+
 // WorkloadConfig contains configuration for entrypoint-mode workloads
 type WorkloadConfig struct {
-	Type           WorkloadType // "vllm", "tgi", "custom"
+	Type           WorkloadType // "vllm", "tgi", "sglang", "llamacpp", "custom"
 	ModelID        string       // HuggingFace model ID (e.g., "TinyLlama/TinyLlama-1.1B-Chat-v1.0")
 	GPUMemoryUtil  float64      // GPU memory utilization (0.0-1.0, default 0.9)
 	Quantization   string       // Quantization method (e.g., "awq", "gptq", "")
@@ -96,6 +122,83 @@ type AccountBalance struct {
 	Currency string  // Currency code (e.g., "USD")
 }
 
+// ChargesProvider is an optional interface for providers that bill storage,
+// bandwidth or IP addresses separately from the GPU hourly rate.
+type ChargesProvider interface {
+	GetInstanceCharges(ctx context.Context, instanceID string) (*InstanceCharges, error)
+}
+
+// InstanceCharges represents non-GPU charges reported by a provider for an instance.
+type InstanceCharges struct {
+	StoragePerHour float64 // Hourly storage charge in USD
+	IPPerHour      float64 // Hourly public IP charge in USD
+	BandwidthTotal float64 // Cumulative bandwidth charge since instance start in USD
+}
+
+// UsageProvider is an optional interface for providers that report what an
+// instance has been billed, so recorded costs can be reconciled against it.
+type UsageProvider interface {
+	GetInstanceUsage(ctx context.Context, instanceID string) (*InstanceUsage, error)
+}
+
+// InstanceUsage represents provider-reported billing for an instance.
+type InstanceUsage struct {
+	StartedAt  time.Time // When the provider started billing the instance
+	HourlyRate float64   // Current all-in hourly rate in USD (GPU and storage)
+	Total      float64   // Billed since StartedAt in USD, bandwidth included
+}
+
+// SSHKeyProvider is an optional interface for providers that can attach an
+// additional SSH public key to a running instance.
+type SSHKeyProvider interface {
+	AttachSSHKey(ctx context.Context, instanceID string, sshPublicKey string) error
+}
+
+// LogsProvider is an optional interface for providers that can return an
+// instance's container output (stdout and stderr).
+type LogsProvider interface {
+	// GetInstanceLogs returns up to the last tail lines of the instance's logs
+	GetInstanceLogs(ctx context.Context, instanceID string, tail int) (string, error)
+}
+
+// PowerController is an optional interface for providers that can stop an
+// instance without destroying it, keeping its disk, and start it again. A
+// stopped instance is not billed for compute, but its storage still is.
+type PowerController interface {
+	StopInstance(ctx context.Context, instanceID string) error
+	StartInstance(ctx context.Context, instanceID string) error
+}
+
+// RebootProvider is an optional interface for providers that can reboot an
+// instance in place, keeping its disk and GPUs.
+type RebootProvider interface {
+	RebootInstance(ctx context.Context, instanceID string) error
+}
+
+// Wrapper is implemented by providers that wrap another provider, such as
+// the middleware adding circuit breaking and retries.
+type Wrapper interface {
+	Unwrap() Provider
+}
+
+// As returns p as a T, checking p and then every provider it wraps. Use it
+// instead of a type assertion to find the optional interfaces above on a
+// wrapped provider.
+func As[T any](p Provider) (T, bool) {
+	for p != nil {
+		if t, ok := p.(T); ok {
+			return t, true
+		}
+		w, ok := p.(Wrapper)
+		if !ok {
+			break
+		}
+		p = w.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
 // ErrBalanceNotSupported indicates a provider doesn't support balance checking.
 var ErrBalanceNotSupported = errors.New("balance checking not supported by this provider")
 
@@ -125,6 +228,10 @@ type CreateInstanceRequest struct {
 	OnStartCmd   string            // Command to run on startup
 	Tags         models.InstanceTags
 
+	// Consumer cloud-init to run after the provider's own SSH key setup.
+	// Only honoured by providers supporting FeatureCloudInit.
+	CloudInit *models.CloudInitConfig
+
 	// Dual launch mode support
 	LaunchMode     LaunchMode      // "ssh" or "entrypoint" (default: ssh)
 	Entrypoint     []string        // Container entrypoint args (for entrypoint mode)
@@ -136,10 +243,22 @@ type CreateInstanceRequest struct {
 	TemplateHashID string // Vast.ai template hash_id (e.g., "4e17788f74f075dd9aab7d0d4427968f")
 
 	// Pricing for interruptible/spot instances
-	BidPrice float64 // Bid per GPU/hr for interruptible instances (0 = on-demand, omit price)
+	BidPrice float64 // Bid in $/hr for interruptible instances (0 = on-demand, omit price)
 
 	// Storage configuration
 	DiskGB int // Disk space in GB (cannot be changed after creation)
+
+	// Runtime feature flags evaluated for this session (flag name -> enabled)
+	Features map[string]bool
+}
+
+// FeatureEnabled reports whether the named feature flag is on for this
+// request, or def if the flag was not evaluated
+func (r CreateInstanceRequest) FeatureEnabled(name string, def bool) bool {
+	if v, ok := r.Features[name]; ok {
+		return v
+	}
+	return def
 }
 
 // InstanceInfo contains details about a provisioned instance
@@ -170,6 +289,14 @@ type InstanceStatus struct {
 	// Port mappings for HTTP API access (entrypoint mode workloads)
 	PublicIP string      // Public IP address of the instance
 	Ports    map[int]int // Container port -> external port mapping (e.g., 8000 -> 33526)
+
+	// Preempted is true when an interruptible instance was reclaimed by the
+	// provider (e.g. outbid) rather than stopped by us
+	Preempted bool
+
+	// GPUUtilPct is the instance's GPU utilization in percent, averaged over
+	// its GPUs. Nil unless the provider supports FeatureIdleDetection.
+	GPUUtilPct *float64
 }
 
 // ProviderInstance represents an instance discovered during reconciliation
@@ -180,6 +307,7 @@ type ProviderInstance struct {
 	StartedAt    time.Time
 	Tags         models.InstanceTags // Parsed from instance metadata
 	PricePerHour float64
+	Preempted    bool // Reclaimed by the provider (see InstanceStatus.Preempted)
 }
 
 // IsOurs checks if this instance belongs to our shopper deployment.
@@ -197,6 +325,16 @@ func (p ProviderInstance) IsOurs(deploymentID string) bool {
 	return strings.HasPrefix(tag, deploymentID)
 }
 
+// IsStopped reports whether the provider lists this instance as stopped or
+// gone, as opposed to running or still starting
+func (p ProviderInstance) IsStopped() bool {
+	switch strings.ToLower(p.Status) {
+	case "stopped", "exited", "terminated", "deleted", "destroyed":
+		return true
+	}
+	return false
+}
+
 // IsExpired checks if this instance is past its expiration time
 func (p ProviderInstance) IsExpired() bool {
 	return !p.Tags.ShopperExpiresAt.IsZero() && time.Now().After(p.Tags.ShopperExpiresAt)
@@ -0,0 +1,197 @@
+// Package middleware wraps providers with the protection every provider API
+// needs: a circuit breaker, retries of idempotent calls, call metrics and the
+// call history behind provider health reporting.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+)
+
+// ErrCircuitOpen is returned when the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerState represents the current state of the circuit breaker
+type CircuitBreakerState int
+
+const (
+	// CircuitClosed is the normal operating state - requests are allowed
+	CircuitClosed CircuitBreakerState = iota
+	// CircuitOpen means too many failures occurred - requests are blocked
+	CircuitOpen
+	// CircuitHalfOpen allows a test request through to check if service recovered
+	CircuitHalfOpen
+)
+
+// CircuitBreakerConfig configures the circuit breaker behavior
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures before opening the circuit
+	FailureThreshold int
+	// ResetTimeout is how long to wait before transitioning from Open to HalfOpen
+	ResetTimeout time.Duration
+	// MaxBackoff is the maximum backoff duration for exponential backoff
+	MaxBackoff time.Duration
+	// BaseBackoff is the initial backoff duration
+	BaseBackoff time.Duration
+}
+
+// DefaultCircuitBreakerConfig returns sensible defaults for the circuit breaker
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 5,
+		ResetTimeout:     30 * time.Second,
+		MaxBackoff:       2 * time.Minute,
+		BaseBackoff:      1 * time.Second,
+	}
+}
+
+// CircuitBreaker implements a simple circuit breaker pattern with exponential backoff
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            CircuitBreakerState
+	failures         int
+	lastFailure      time.Time
+	lastStateChange  time.Time
+	config           CircuitBreakerConfig
+	consecutiveWaits int // For exponential backoff
+}
+
+// NewCircuitBreaker creates a new circuit breaker with the given configuration
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		state:  CircuitClosed,
+		config: config,
+	}
+}
+
+// Allow returns true if a request should be allowed, false if circuit is open
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitClosed:
+		return true
+	case CircuitOpen:
+		// Check if we should transition to half-open
+		if time.Since(cb.lastStateChange) > cb.config.ResetTimeout {
+			cb.state = CircuitHalfOpen
+			cb.lastStateChange = time.Now()
+			return true
+		}
+		return false
+	case CircuitHalfOpen:
+		// Allow one test request
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess records a successful request
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+	cb.consecutiveWaits = 0
+	if cb.state == CircuitHalfOpen {
+		cb.state = CircuitClosed
+		cb.lastStateChange = time.Now()
+	}
+}
+
+// RecordFailure records a failed request and potentially opens the circuit
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.lastFailure = time.Now()
+
+	if cb.state == CircuitHalfOpen {
+		// Failed while testing - go back to open
+		cb.state = CircuitOpen
+		cb.lastStateChange = time.Now()
+		cb.consecutiveWaits++
+		return
+	}
+
+	if cb.failures >= cb.config.FailureThreshold {
+		cb.state = CircuitOpen
+		cb.lastStateChange = time.Now()
+		cb.consecutiveWaits++
+	}
+}
+
+// RecordResult records the outcome of a request. Only errors that mean the
+// provider is struggling (server errors, rate limits, network failures)
+// count as failures; validation and not-found errors do not, and neither do
+// calls cancelled by the caller.
+func (cb *CircuitBreaker) RecordResult(err error) {
+	if err == nil {
+		cb.RecordSuccess()
+		return
+	}
+	if isBreakerFailure(err) {
+		cb.RecordFailure()
+	}
+}
+
+// Backoff returns the current backoff duration using exponential backoff
+func (cb *CircuitBreaker) Backoff() time.Duration {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.consecutiveWaits == 0 {
+		return cb.config.BaseBackoff
+	}
+
+	// Cap consecutiveWaits to prevent integer overflow in bit shift
+	// With max shift of 10, we can shift up to 2^10 = 1024x the base backoff
+	waits := cb.consecutiveWaits
+	const maxShift = 10
+	if waits > maxShift {
+		waits = maxShift
+	}
+
+	// Exponential backoff: base * 2^(waits-1), capped at maxBackoff
+	backoff := cb.config.BaseBackoff * time.Duration(1<<uint(waits-1))
+	if backoff > cb.config.MaxBackoff {
+		backoff = cb.config.MaxBackoff
+	}
+	return backoff
+}
+
+// State returns the current circuit breaker state (for monitoring/testing)
+func (cb *CircuitBreaker) State() CircuitBreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// isBreakerFailure reports whether err should count against the circuit
+func isBreakerFailure(err error) bool {
+	// Rate limits and server errors should trigger circuit breaker
+	var providerErr *provider.ProviderError
+	if errors.As(err, &providerErr) {
+		if providerErr.StatusCode >= 500 || providerErr.StatusCode == 429 {
+			return true
+		}
+	}
+
+	// Don't trigger for context cancellation by caller
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Other network-level errors
+	return strings.Contains(err.Error(), "connection refused") ||
+		strings.Contains(err.Error(), "no such host") ||
+		strings.Contains(err.Error(), "network is unreachable")
+}
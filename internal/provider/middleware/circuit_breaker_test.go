@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("starts in closed state", func(t *testing.T) {
+		cb := NewCircuitBreaker(DefaultCircuitBreakerConfig())
+		assert.Equal(t, CircuitClosed, cb.State())
+		assert.True(t, cb.Allow())
+	})
+
+	t.Run("opens after threshold failures", func(t *testing.T) {
+		config := CircuitBreakerConfig{
+			FailureThreshold: 3,
+			ResetTimeout:     1 * time.Second,
+			MaxBackoff:       1 * time.Minute,
+			BaseBackoff:      100 * time.Millisecond,
+		}
+		cb := NewCircuitBreaker(config)
+
+		// Record failures
+		for i := 0; i < 3; i++ {
+			cb.RecordFailure()
+		}
+
+		assert.Equal(t, CircuitOpen, cb.State())
+		assert.False(t, cb.Allow())
+	})
+
+	t.Run("resets to half-open after timeout", func(t *testing.T) {
+		config := CircuitBreakerConfig{
+			FailureThreshold: 2,
+			ResetTimeout:     50 * time.Millisecond,
+			MaxBackoff:       1 * time.Minute,
+			BaseBackoff:      100 * time.Millisecond,
+		}
+		cb := NewCircuitBreaker(config)
+
+		// Open the circuit
+		cb.RecordFailure()
+		cb.RecordFailure()
+		assert.Equal(t, CircuitOpen, cb.State())
+
+		// Wait for reset timeout
+		time.Sleep(60 * time.Millisecond)
+
+		// Should transition to half-open and allow request
+		assert.True(t, cb.Allow())
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+	})
+
+	t.Run("closes on success in half-open state", func(t *testing.T) {
+		config := CircuitBreakerConfig{
+			FailureThreshold: 2,
+			ResetTimeout:     10 * time.Millisecond,
+			MaxBackoff:       1 * time.Minute,
+			BaseBackoff:      100 * time.Millisecond,
+		}
+		cb := NewCircuitBreaker(config)
+
+		// Open the circuit
+		cb.RecordFailure()
+		cb.RecordFailure()
+
+		// Wait and transition to half-open
+		time.Sleep(15 * time.Millisecond)
+		cb.Allow()
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+
+		// Success closes the circuit
+		cb.RecordSuccess()
+		assert.Equal(t, CircuitClosed, cb.State())
+	})
+
+	t.Run("reopens on failure in half-open state", func(t *testing.T) {
+		config := CircuitBreakerConfig{
+			FailureThreshold: 2,
+			ResetTimeout:     10 * time.Millisecond,
+			MaxBackoff:       1 * time.Minute,
+			BaseBackoff:      100 * time.Millisecond,
+		}
+		cb := NewCircuitBreaker(config)
+
+		// Open the circuit
+		cb.RecordFailure()
+		cb.RecordFailure()
+
+		// Wait and transition to half-open
+		time.Sleep(15 * time.Millisecond)
+		cb.Allow()
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+
+		// Failure reopens
+		cb.RecordFailure()
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("exponential backoff increases", func(t *testing.T) {
+		config := CircuitBreakerConfig{
+			FailureThreshold: 1,
+			ResetTimeout:     10 * time.Millisecond,
+			MaxBackoff:       10 * time.Second,
+			BaseBackoff:      100 * time.Millisecond,
+		}
+		cb := NewCircuitBreaker(config)
+
+		// First open
+		cb.RecordFailure()
+		backoff1 := cb.Backoff()
+
+		// Wait and let it try again, fail again
+		time.Sleep(15 * time.Millisecond)
+		cb.Allow() // transitions to half-open
+		cb.RecordFailure()
+		backoff2 := cb.Backoff()
+
+		assert.True(t, backoff2 > backoff1, "backoff should increase: %v > %v", backoff2, backoff1)
+	})
+
+	t.Run("backoff caps at max", func(t *testing.T) {
+		config := CircuitBreakerConfig{
+			FailureThreshold: 1,
+			ResetTimeout:     1 * time.Millisecond,
+			MaxBackoff:       500 * time.Millisecond,
+			BaseBackoff:      100 * time.Millisecond,
+		}
+		cb := NewCircuitBreaker(config)
+
+		// Cause many failures to increase backoff
+		for i := 0; i < 20; i++ {
+			cb.RecordFailure()
+			time.Sleep(2 * time.Millisecond)
+			cb.Allow()
+		}
+
+		backoff := cb.Backoff()
+		assert.LessOrEqual(t, backoff, config.MaxBackoff)
+	})
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Provider wraps a provider.Provider with a circuit breaker, retries of
+// idempotent calls (listing offers and instances, instance status) under a
+// RetryPolicy, call metrics and health reporting. Creating and
+// destroying instances are never retried here: a request that timed out may
+// still have taken effect.
+//
+// Optional interfaces of the wrapped provider, such as
+// provider.BalanceProvider, are reached through provider.As.
+type Provider struct {
+	next    provider.Provider
+	breaker *CircuitBreaker
+	stats   provider.APIStats
+	logger  *slog.Logger
+	retry   RetryPolicy
+}
+
+// Option configures the provider middleware
+type Option func(*Provider)
+
+// WithCircuitBreaker configures the circuit breaker
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(p *Provider) {
+		p.breaker = NewCircuitBreaker(config)
+	}
+}
+
+// WithRetry sets the retry policy for idempotent calls
+func WithRetry(policy RetryPolicy) Option {
+	return func(p *Provider) {
+		p.retry = policy
+	}
+}
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Provider) {
+		p.logger = logger
+	}
+}
+
+// Wrap returns next protected by the middleware
+func Wrap(next provider.Provider, opts ...Option) *Provider {
+	p := &Provider{
+		next:    next,
+		breaker: NewCircuitBreaker(DefaultCircuitBreakerConfig()),
+		logger:  slog.Default(),
+		retry:   DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.retry.MaxAttempts < 1 {
+		p.retry.MaxAttempts = 1
+	}
+	return p
+}
+
+var (
+	_ provider.Provider       = (*Provider)(nil)
+	_ provider.Wrapper        = (*Provider)(nil)
+	_ provider.HealthReporter = (*Provider)(nil)
+)
+
+// Unwrap returns the wrapped provider
+func (p *Provider) Unwrap() provider.Provider {
+	return p.next
+}
+
+// Name returns the wrapped provider's name
+func (p *Provider) Name() string {
+	return p.next.Name()
+}
+
+// SupportsFeature reports the wrapped provider's features
+func (p *Provider) SupportsFeature(feature provider.ProviderFeature) bool {
+	return p.next.SupportsFeature(feature)
+}
+
+// ListOffers returns the wrapped provider's offers
+func (p *Provider) ListOffers(ctx context.Context, filter models.OfferFilter) (offers []models.GPUOffer, err error) {
+	err = p.call(ctx, "ListOffers", true, func() error {
+		offers, err = p.next.ListOffers(ctx, filter)
+		return err
+	})
+	return offers, err
+}
+
+// ListAllInstances returns the wrapped provider's instances
+func (p *Provider) ListAllInstances(ctx context.Context) (instances []provider.ProviderInstance, err error) {
+	err = p.call(ctx, "ListAllInstances", true, func() error {
+		instances, err = p.next.ListAllInstances(ctx)
+		return err
+	})
+	return instances, err
+}
+
+// CreateInstance provisions an instance through the wrapped provider
+func (p *Provider) CreateInstance(ctx context.Context, req provider.CreateInstanceRequest) (info *provider.InstanceInfo, err error) {
+	err = p.call(ctx, "CreateInstance", false, func() error {
+		info, err = p.next.CreateInstance(ctx, req)
+		return err
+	})
+	return info, err
+}
+
+// DestroyInstance tears down an instance through the wrapped provider
+func (p *Provider) DestroyInstance(ctx context.Context, instanceID string) error {
+	return p.call(ctx, "DestroyInstance", false, func() error {
+		return p.next.DestroyInstance(ctx, instanceID)
+	})
+}
+
+// GetInstanceStatus returns an instance's status from the wrapped provider
+func (p *Provider) GetInstanceStatus(ctx context.Context, instanceID string) (status *provider.InstanceStatus, err error) {
+	err = p.call(ctx, "GetInstanceStatus", true, func() error {
+		status, err = p.next.GetInstanceStatus(ctx, instanceID)
+		return err
+	})
+	return status, err
+}
+
+// APIHealth returns the circuit breaker state and recent call outcomes
+func (p *Provider) APIHealth() provider.APIHealth {
+	return p.stats.Health(provider.CircuitStateName(int(p.breaker.State())))
+}
+
+// call runs fn behind the circuit breaker, retrying transient errors when
+// idempotent, and records each attempt
+func (p *Provider) call(ctx context.Context, operation string, idempotent bool, fn func() error) error {
+	attempts := 1
+	if idempotent {
+		attempts = p.retry.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if !p.breaker.Allow() {
+			backoff := p.breaker.Backoff()
+			p.logger.Debug("circuit breaker is open",
+				slog.String("provider", p.Name()),
+				slog.String("operation", operation),
+				slog.Duration("backoff", backoff))
+			err = fmt.Errorf("%w: retry after %v", ErrCircuitOpen, backoff)
+			p.record(operation, time.Now(), err)
+			return err
+		}
+
+		start := time.Now()
+		err = fn()
+		p.breaker.RecordResult(err)
+		p.record(operation, start, err)
+
+		if err == nil || attempt >= attempts || !shouldRetry(ctx, err) {
+			return err
+		}
+
+		delay := p.retry.Delay(attempt)
+		metrics.RecordProviderAPIRetry(p.Name(), operation)
+		p.logger.Debug("retrying provider call",
+			slog.String("provider", p.Name()),
+			slog.String("operation", operation),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// record updates the call metrics and health stats for one attempt
+func (p *Provider) record(operation string, start time.Time, err error) {
+	name := p.Name()
+	metrics.RecordProviderAPIResponseTime(name, operation, time.Since(start))
+
+	status := "success"
+	if err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			status = "circuit_open"
+		} else {
+			status = "error"
+		}
+	}
+	metrics.RecordProviderAPICall(name, operation, status)
+	if status != "circuit_open" {
+		p.stats.Record(err)
+	}
+
+	// Update circuit breaker state metric
+	metrics.UpdateProviderCircuitBreakerState(name, int(p.breaker.State()))
+}
+
+// ObserveCall records metrics for a provider API call made outside the
+// middleware, such as a call through one of the optional provider
+// interfaces
+func ObserveCall(providerName, operation string, start time.Time, err error) {
+	metrics.RecordProviderAPIResponseTime(providerName, operation, time.Since(start))
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	metrics.RecordProviderAPICall(providerName, operation, status)
+}
@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider returns the queued errors in order, then succeeds
+type fakeProvider struct {
+	mu     sync.Mutex
+	errs   []error
+	calls  map[string]int
+	offers []models.GPUOffer
+}
+
+func (f *fakeProvider) next(operation string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[operation]++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeProvider) callCount(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[operation]
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error) {
+	if err := f.next("ListOffers"); err != nil {
+		return nil, err
+	}
+	return f.offers, nil
+}
+
+func (f *fakeProvider) ListAllInstances(ctx context.Context) ([]provider.ProviderInstance, error) {
+	return nil, f.next("ListAllInstances")
+}
+
+func (f *fakeProvider) CreateInstance(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+	if err := f.next("CreateInstance"); err != nil {
+		return nil, err
+	}
+	return &provider.InstanceInfo{ProviderInstanceID: "inst-1"}, nil
+}
+
+func (f *fakeProvider) DestroyInstance(ctx context.Context, instanceID string) error {
+	return f.next("DestroyInstance")
+}
+
+func (f *fakeProvider) GetInstanceStatus(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+	if err := f.next("GetInstanceStatus"); err != nil {
+		return nil, err
+	}
+	return &provider.InstanceStatus{Status: "running", Running: true}, nil
+}
+
+func (f *fakeProvider) SupportsFeature(feature provider.ProviderFeature) bool { return false }
+
+// balanceProvider adds an optional interface to fakeProvider
+type balanceProvider struct {
+	fakeProvider
+}
+
+func (b *balanceProvider) GetAccountBalance(ctx context.Context) (*provider.AccountBalance, error) {
+	return &provider.AccountBalance{Balance: 42, Currency: "USD"}, nil
+}
+
+func serverError() error {
+	return provider.NewProviderError("fake", "call", http.StatusServiceUnavailable, "unavailable", nil)
+}
+
+func TestProvider_RetriesIdempotentCalls(t *testing.T) {
+	fake := &fakeProvider{
+		errs:   []error{serverError()},
+		offers: []models.GPUOffer{{ID: "offer-1"}},
+	}
+	p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	offers, err := p.ListOffers(context.Background(), models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Len(t, offers, 1)
+	assert.Equal(t, 2, fake.callCount("ListOffers"))
+
+	// Each attempt counts towards the API health
+	health := p.APIHealth()
+	assert.Equal(t, 2, health.RecentCalls)
+	assert.Equal(t, 1, health.RecentFailures)
+	assert.Equal(t, provider.CircuitStateClosed, health.CircuitState)
+}
+
+func TestProvider_GivesUpAfterMaxAttempts(t *testing.T) {
+	fake := &fakeProvider{errs: []error{serverError(), serverError(), serverError()}}
+	p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	_, err := p.GetInstanceStatus(context.Background(), "inst-1")
+	require.Error(t, err)
+	assert.Equal(t, 2, fake.callCount("GetInstanceStatus"))
+}
+
+func TestProvider_RetriesTimeouts(t *testing.T) {
+	fake := &fakeProvider{errs: []error{
+		fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+		provider.ErrProviderRateLimit,
+	}}
+	p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	_, err := p.ListAllInstances(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.callCount("ListAllInstances"))
+}
+
+func TestProvider_DoesNotRetry(t *testing.T) {
+	t.Run("instance creation", func(t *testing.T) {
+		fake := &fakeProvider{errs: []error{serverError()}}
+		p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		_, err := p.CreateInstance(context.Background(), provider.CreateInstanceRequest{OfferID: "offer-1"})
+		require.Error(t, err)
+		assert.Equal(t, 1, fake.callCount("CreateInstance"))
+	})
+
+	t.Run("instance destruction", func(t *testing.T) {
+		fake := &fakeProvider{errs: []error{serverError()}}
+		p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		require.Error(t, p.DestroyInstance(context.Background(), "inst-1"))
+		assert.Equal(t, 1, fake.callCount("DestroyInstance"))
+	})
+
+	t.Run("errors that are not retryable", func(t *testing.T) {
+		fake := &fakeProvider{errs: []error{provider.ErrInstanceNotFound}}
+		p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		_, err := p.GetInstanceStatus(context.Background(), "inst-1")
+		assert.ErrorIs(t, err, provider.ErrInstanceNotFound)
+		assert.Equal(t, 1, fake.callCount("GetInstanceStatus"))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		fake := &fakeProvider{errs: []error{serverError()}}
+		p := Wrap(fake, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := p.ListAllInstances(ctx)
+		require.Error(t, err)
+		assert.Equal(t, 1, fake.callCount("ListAllInstances"))
+	})
+}
+
+func TestProvider_CircuitOpens(t *testing.T) {
+	fake := &fakeProvider{errs: []error{serverError(), serverError()}}
+	p := Wrap(fake,
+		WithRetry(RetryPolicy{MaxAttempts: 1}),
+		WithCircuitBreaker(CircuitBreakerConfig{
+			FailureThreshold: 2,
+			ResetTimeout:     time.Minute,
+			MaxBackoff:       time.Minute,
+			BaseBackoff:      time.Second,
+		}))
+
+	ctx := context.Background()
+	_, _ = p.ListOffers(ctx, models.OfferFilter{})
+	_, _ = p.ListOffers(ctx, models.OfferFilter{})
+
+	_, err := p.ListOffers(ctx, models.OfferFilter{})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, fake.callCount("ListOffers"), "open circuit should not reach the provider")
+
+	health := p.APIHealth()
+	assert.Equal(t, provider.CircuitStateOpen, health.CircuitState)
+	assert.Equal(t, 2, health.RecentCalls, "rejected calls are not counted")
+}
+
+func TestProvider_OptionalInterfaces(t *testing.T) {
+	inner := &balanceProvider{}
+	p := Wrap(inner)
+
+	assert.Same(t, inner, p.Unwrap())
+	assert.Equal(t, "fake", p.Name())
+
+	bp, ok := provider.As[provider.BalanceProvider](p)
+	require.True(t, ok, "optional interfaces of the wrapped provider are found")
+	balance, err := bp.GetAccountBalance(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, balance.Balance)
+
+	_, ok = provider.As[provider.HealthReporter](p)
+	assert.True(t, ok)
+
+	_, ok = provider.As[provider.LogsProvider](p)
+	assert.False(t, ok)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+)
+
+// RetryPolicy controls how idempotent provider calls are retried after
+// transient errors: server errors, rate limits and timeouts.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a call is tried; 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles with every
+	// further attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts
+	MaxDelay time.Duration
+	// Jitter is the share of each wait that is randomized, from 0 (none) to
+	// 1 (anywhere between zero and the full wait). It keeps many callers
+	// that failed together from retrying together.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+// Delay returns the wait before the given retry (1 for the first retry)
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	// Cap the shift the same way the circuit breaker backoff does
+	const maxShift = 10
+	shift := retry - 1
+	if shift > maxShift {
+		shift = maxShift
+	}
+	if shift < 0 {
+		shift = 0
+	}
+
+	delay := p.BaseDelay << uint(shift)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	if jitter == 0 || delay <= 0 {
+		return delay
+	}
+	return delay - time.Duration(rand.Float64()*jitter*float64(delay))
+}
+
+// shouldRetry reports whether a call that failed with err is worth
+// repeating. A call timing out counts only while the caller's own context
+// is still live.
+func shouldRetry(ctx context.Context, err error) bool {
+	if provider.IsRetryable(err) {
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
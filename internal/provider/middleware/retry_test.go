@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Run("doubles up to the maximum", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+
+		assert.Equal(t, 100*time.Millisecond, policy.Delay(1))
+		assert.Equal(t, 200*time.Millisecond, policy.Delay(2))
+		assert.Equal(t, 400*time.Millisecond, policy.Delay(3))
+		assert.Equal(t, 500*time.Millisecond, policy.Delay(4))
+		assert.Equal(t, 500*time.Millisecond, policy.Delay(100))
+	})
+
+	t.Run("jitter shortens delays within bounds", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.5}
+
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 50; i++ {
+			d := policy.Delay(2)
+			assert.GreaterOrEqual(t, d, time.Second)
+			assert.LessOrEqual(t, d, 2*time.Second)
+			seen[d] = true
+		}
+		assert.Greater(t, len(seen), 1, "delays should vary")
+	})
+
+	t.Run("jitter is clamped", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: time.Second, Jitter: 3}
+		for i := 0; i < 20; i++ {
+			d := policy.Delay(1)
+			assert.GreaterOrEqual(t, d, time.Duration(0))
+			assert.LessOrEqual(t, d, time.Second)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package static
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+const (
+	defaultSSHPort = 22
+	defaultSSHUser = "root"
+)
+
+// nodeIDPattern keeps node IDs safe to embed in offer and instance IDs
+var nodeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Catalog is a file-based list of pre-existing GPU nodes offered instead of
+// live provider inventory
+type Catalog struct {
+	Nodes []Node `json:"nodes"`
+}
+
+// Node is a GPU machine in the catalog. Each node is offered to one session
+// at a time.
+type Node struct {
+	ID            string  `json:"id"`
+	GPUType       string  `json:"gpu_type"`
+	GPUCount      int     `json:"gpu_count"`
+	VRAM          int     `json:"vram_gb"`
+	PricePerHour  float64 `json:"price_per_hour"` // Internal chargeback rate, USD
+	Location      string  `json:"location"`
+	CUDAVersion   float64 `json:"cuda_version,omitempty"`
+	DriverVersion string  `json:"driver_version,omitempty"`
+
+	// SSH access for the admin key and for sessions
+	SSHHost string `json:"ssh_host"`
+	SSHPort int    `json:"ssh_port"` // Default 22
+	SSHUser string `json:"ssh_user"` // Default root
+	// SHA256 host key fingerprint as printed by ssh-keygen -l. Without
+	// it, the first key seen is trusted until restart.
+	SSHHostKey string `json:"ssh_host_key,omitempty"`
+}
+
+// LoadCatalog reads and validates a catalog file
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog %s: %w", path, err)
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", path, err)
+	}
+
+	return &catalog, nil
+}
+
+// Validate checks required fields and applies defaults
+func (c *Catalog) Validate() error {
+	seen := make(map[string]bool, len(c.Nodes))
+	for i := range c.Nodes {
+		n := &c.Nodes[i]
+		if !nodeIDPattern.MatchString(n.ID) {
+			return fmt.Errorf("node %d: id %q must be non-empty and contain only letters, digits, '.', '_' or '-'", i, n.ID)
+		}
+		if seen[n.ID] {
+			return fmt.Errorf("node %d: duplicate id %q", i, n.ID)
+		}
+		seen[n.ID] = true
+
+		if n.GPUType == "" {
+			return fmt.Errorf("node %s: gpu_type is required", n.ID)
+		}
+		if n.SSHHost == "" {
+			return fmt.Errorf("node %s: ssh_host is required", n.ID)
+		}
+		if n.PricePerHour < 0 {
+			return fmt.Errorf("node %s: price_per_hour cannot be negative", n.ID)
+		}
+		if n.GPUCount <= 0 {
+			n.GPUCount = 1
+		}
+		if n.SSHPort == 0 {
+			n.SSHPort = defaultSSHPort
+		}
+		if n.SSHUser == "" {
+			n.SSHUser = defaultSSHUser
+		}
+	}
+	return nil
+}
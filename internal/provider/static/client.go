@@ -0,0 +1,314 @@
+// Package static implements a provider backed by a file-based catalog of
+// pre-existing GPU nodes, for private clusters, demos and air-gapped
+// deployments where live provider APIs are unreachable. Provisioning leases
+// a node and grants the session's SSH key; destroying revokes the key and
+// returns the node to the catalog.
+package static
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ProviderName identifies the static catalog provider
+const ProviderName = "static"
+
+// offerIDPrefix prefixes node IDs to form offer IDs ("static:<node>")
+const offerIDPrefix = ProviderName + ":"
+
+// sessionIDPattern keeps session IDs safe to use as an authorized_keys label
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// lease is a node assigned to a session
+type lease struct {
+	instanceID string
+	node       Node
+	tags       models.InstanceTags
+	startedAt  time.Time
+}
+
+// Client serves offers from a static catalog and leases its nodes to sessions
+type Client struct {
+	nodes     map[string]Node
+	installer KeyInstaller
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu         sync.Mutex
+	leases     map[string]*lease // instance ID -> lease
+	nodeLeases map[string]string // node ID -> instance ID
+}
+
+// Option configures the static provider
+type Option func(*Client)
+
+// WithKeyInstaller sets how session SSH keys are granted on nodes
+func WithKeyInstaller(installer KeyInstaller) Option {
+	return func(c *Client) {
+		c.installer = installer
+	}
+}
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(c *Client) {
+		c.now = fn
+	}
+}
+
+// NewClient creates a static provider for the catalog's nodes
+func NewClient(catalog *Catalog, opts ...Option) *Client {
+	c := &Client{
+		nodes:      make(map[string]Node, len(catalog.Nodes)),
+		logger:     slog.Default(),
+		now:        time.Now,
+		leases:     make(map[string]*lease),
+		nodeLeases: make(map[string]string),
+	}
+	for _, n := range catalog.Nodes {
+		c.nodes[n.ID] = n
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name returns the provider identifier
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+// SupportsFeature checks if the provider supports a specific feature
+func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
+	return feature == provider.FeatureInstanceTags
+}
+
+// ListOffers returns one offer per catalog node that is not leased
+func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	var offers []models.GPUOffer
+	for id, n := range c.nodes {
+		if _, leased := c.nodeLeases[id]; leased {
+			continue
+		}
+		offer := models.GPUOffer{
+			ID:                     offerIDPrefix + n.ID,
+			Provider:               ProviderName,
+			ProviderID:             n.ID,
+			GPUType:                n.GPUType,
+			GPUCount:               n.GPUCount,
+			VRAM:                   n.VRAM,
+			PricePerHour:           n.PricePerHour,
+			Location:               n.Location,
+			Reliability:            1.0,
+			Available:              true,
+			FetchedAt:              now,
+			AvailabilityConfidence: 1.0,
+			CUDAVersion:            n.CUDAVersion,
+			DriverVersion:          n.DriverVersion,
+			MachineID:              n.ID,
+		}
+		if offer.MatchesFilter(filter) {
+			offers = append(offers, offer)
+		}
+	}
+
+	sort.Slice(offers, func(i, j int) bool { return offers[i].ID < offers[j].ID })
+	return offers, nil
+}
+
+// CreateInstance leases the offer's node to the session and grants its SSH key
+func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+	if req.LaunchMode == provider.LaunchModeEntrypoint {
+		return nil, fmt.Errorf("static: CreateInstance: entrypoint launch mode is not supported")
+	}
+	if !sessionIDPattern.MatchString(req.SessionID) {
+		return nil, fmt.Errorf("static: CreateInstance: invalid session ID %q", req.SessionID)
+	}
+	if req.SSHPublicKey == "" {
+		return nil, fmt.Errorf("static: CreateInstance: SSH public key is required")
+	}
+	if c.installer == nil {
+		return nil, fmt.Errorf("static: CreateInstance: no admin SSH key configured")
+	}
+
+	nodeID := strings.TrimPrefix(req.OfferID, offerIDPrefix)
+	instanceID := nodeID + ":" + req.SessionID
+
+	// Reserve the node before the slow SSH call so concurrent requests
+	// cannot lease it twice
+	c.mu.Lock()
+	node, ok := c.nodes[nodeID]
+	if !ok {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("static: CreateInstance: unknown node %q: %w", nodeID, provider.ErrOfferUnavailable)
+	}
+	if _, leased := c.nodeLeases[nodeID]; leased {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("static: CreateInstance: node %s is leased: %w", nodeID, provider.ErrOfferUnavailable)
+	}
+	l := &lease{instanceID: instanceID, node: node, tags: req.Tags, startedAt: c.now()}
+	c.leases[instanceID] = l
+	c.nodeLeases[nodeID] = instanceID
+	c.mu.Unlock()
+
+	if err := c.installer.InstallKey(ctx, node, req.SSHPublicKey, req.Tags.ToLabel()); err != nil {
+		c.release(instanceID)
+		return nil, fmt.Errorf("static: CreateInstance: failed to install SSH key: %w", err)
+	}
+
+	if req.OnStartCmd != "" {
+		c.logger.Warn("static provider does not run on-start commands",
+			slog.String("session_id", req.SessionID),
+			slog.String("node_id", nodeID))
+	}
+
+	c.logger.Info("node leased",
+		slog.String("provider", ProviderName),
+		slog.String("node_id", nodeID),
+		slog.String("session_id", req.SessionID))
+
+	return &provider.InstanceInfo{
+		ProviderInstanceID: instanceID,
+		SSHHost:            node.SSHHost,
+		SSHPort:            node.SSHPort,
+		SSHUser:            node.SSHUser,
+		Status:             "running",
+		ActualPricePerHour: node.PricePerHour,
+	}, nil
+}
+
+// DestroyInstance revokes the session's SSH key and returns the node to the
+// catalog. Unknown instances are treated as already destroyed.
+func (c *Client) DestroyInstance(ctx context.Context, instanceID string) error {
+	c.mu.Lock()
+	l, ok := c.leases[instanceID]
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if c.installer != nil {
+		if err := c.installer.RemoveKey(ctx, l.node, l.tags.ToLabel()); err != nil {
+			return fmt.Errorf("static: DestroyInstance: failed to revoke SSH key: %w", err)
+		}
+	}
+
+	c.release(instanceID)
+
+	c.logger.Info("node released",
+		slog.String("provider", ProviderName),
+		slog.String("node_id", l.node.ID),
+		slog.String("instance_id", instanceID))
+
+	return nil
+}
+
+// GetInstanceStatus reports a leased node as running
+func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+	c.mu.Lock()
+	l, ok := c.leases[instanceID]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("static: GetInstanceStatus: %s: %w", instanceID, provider.ErrInstanceNotFound)
+	}
+
+	return &provider.InstanceStatus{
+		Status:    "running",
+		Running:   true,
+		StartedAt: l.startedAt,
+		SSHHost:   l.node.SSHHost,
+		SSHPort:   l.node.SSHPort,
+		SSHUser:   l.node.SSHUser,
+		PublicIP:  l.node.SSHHost,
+	}, nil
+}
+
+// ListAllInstances returns all leased nodes
+func (c *Client) ListAllInstances(ctx context.Context) ([]provider.ProviderInstance, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	instances := make([]provider.ProviderInstance, 0, len(c.leases))
+	for _, l := range c.leases {
+		instances = append(instances, provider.ProviderInstance{
+			ID:           l.instanceID,
+			Name:         l.tags.ToLabel(),
+			Status:       "running",
+			StartedAt:    l.startedAt,
+			Tags:         l.tags,
+			PricePerHour: l.node.PricePerHour,
+		})
+	}
+	return instances, nil
+}
+
+// RestoreLeases rebuilds leases from active sessions after a restart, since
+// leases are only held in memory. Sessions for other providers or nodes no
+// longer in the catalog are skipped.
+func (c *Client) RestoreLeases(sessions []*models.Session, deploymentID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	restored := 0
+	for _, s := range sessions {
+		if s.Provider != ProviderName || s.ProviderID == "" {
+			continue
+		}
+		nodeID, _, ok := strings.Cut(s.ProviderID, ":")
+		node, known := c.nodes[nodeID]
+		if !ok || !known {
+			c.logger.Warn("cannot restore lease for node missing from catalog",
+				slog.String("session_id", s.ID),
+				slog.String("instance_id", s.ProviderID))
+			continue
+		}
+		c.leases[s.ProviderID] = &lease{
+			instanceID: s.ProviderID,
+			node:       node,
+			tags: models.InstanceTags{
+				ShopperSessionID:    s.ID,
+				ShopperDeploymentID: deploymentID,
+				ShopperExpiresAt:    s.ExpiresAt,
+				ShopperConsumerID:   s.ConsumerID,
+			},
+			startedAt: s.CreatedAt,
+		}
+		c.nodeLeases[nodeID] = s.ProviderID
+		restored++
+	}
+	return restored
+}
+
+// release frees a lease
+func (c *Client) release(instanceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l, ok := c.leases[instanceID]; ok {
+		delete(c.nodeLeases, l.node.ID)
+		delete(c.leases, instanceID)
+	}
+}
@@ -0,0 +1,216 @@
+package static
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const testPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl test@example"
+
+type fakeInstaller struct {
+	mu        sync.Mutex
+	installed map[string]string // node ID -> comment
+	err       error
+}
+
+func newFakeInstaller() *fakeInstaller {
+	return &fakeInstaller{installed: make(map[string]string)}
+}
+
+func (f *fakeInstaller) InstallKey(ctx context.Context, node Node, publicKey, comment string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.installed[node.ID] = comment
+	return nil
+}
+
+func (f *fakeInstaller) RemoveKey(ctx context.Context, node Node, comment string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	if f.installed[node.ID] == comment {
+		delete(f.installed, node.ID)
+	}
+	return nil
+}
+
+func testCatalog() *Catalog {
+	catalog := &Catalog{Nodes: []Node{
+		{ID: "rack1-a", GPUType: "RTX 4090", VRAM: 24, PricePerHour: 0.40, Location: "lab", SSHHost: "10.0.0.5"},
+		{ID: "rack1-b", GPUType: "A100", VRAM: 80, GPUCount: 2, PricePerHour: 2.10, Location: "lab", SSHHost: "10.0.0.6", SSHPort: 2222, SSHUser: "ubuntu"},
+	}}
+	_ = catalog.Validate()
+	return catalog
+}
+
+func createRequest(offerID, sessionID string) provider.CreateInstanceRequest {
+	return provider.CreateInstanceRequest{
+		OfferID:      offerID,
+		SessionID:    sessionID,
+		SSHPublicKey: testPublicKey,
+		Tags: models.InstanceTags{
+			ShopperSessionID:    sessionID,
+			ShopperDeploymentID: "deploy-1",
+			ShopperConsumerID:   "consumer-001",
+		},
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"nodes":[{"id":"n1","gpu_type":"RTX 4090","vram_gb":24,"price_per_hour":0.3,"ssh_host":"10.0.0.5"}]}`), 0o600))
+
+	catalog, err := LoadCatalog(path)
+	require.NoError(t, err)
+	require.Len(t, catalog.Nodes, 1)
+	assert.Equal(t, 22, catalog.Nodes[0].SSHPort)
+	assert.Equal(t, "root", catalog.Nodes[0].SSHUser)
+	assert.Equal(t, 1, catalog.Nodes[0].GPUCount)
+}
+
+func TestCatalogValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+	}{
+		{"missing id", Node{GPUType: "A100", SSHHost: "h"}},
+		{"unsafe id", Node{ID: "a:b", GPUType: "A100", SSHHost: "h"}},
+		{"missing gpu type", Node{ID: "n1", SSHHost: "h"}},
+		{"missing host", Node{ID: "n1", GPUType: "A100"}},
+		{"negative price", Node{ID: "n1", GPUType: "A100", SSHHost: "h", PricePerHour: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, (&Catalog{Nodes: []Node{tt.node}}).Validate())
+		})
+	}
+
+	dup := &Catalog{Nodes: []Node{
+		{ID: "n1", GPUType: "A100", SSHHost: "h"},
+		{ID: "n1", GPUType: "A100", SSHHost: "h"},
+	}}
+	assert.Error(t, dup.Validate())
+}
+
+func TestClient_LeaseLifecycle(t *testing.T) {
+	installer := newFakeInstaller()
+	c := NewClient(testCatalog(), WithKeyInstaller(installer))
+	ctx := context.Background()
+
+	offers, err := c.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	require.Len(t, offers, 2)
+	assert.Equal(t, "static:rack1-a", offers[0].ID)
+	assert.Equal(t, ProviderName, offers[0].Provider)
+	assert.True(t, offers[0].Available)
+
+	filtered, err := c.ListOffers(ctx, models.OfferFilter{GPUType: "A100"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 2, filtered[0].GPUCount)
+
+	info, err := c.CreateInstance(ctx, createRequest("static:rack1-b", "sess-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "rack1-b:sess-1", info.ProviderInstanceID)
+	assert.Equal(t, "10.0.0.6", info.SSHHost)
+	assert.Equal(t, 2222, info.SSHPort)
+	assert.Equal(t, "ubuntu", info.SSHUser)
+	assert.Equal(t, 2.10, info.ActualPricePerHour)
+	assert.Equal(t, "shopper-sess-1", installer.installed["rack1-b"])
+
+	// Leased node is no longer offered and cannot be leased twice
+	offers, err = c.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	_, err = c.CreateInstance(ctx, createRequest("static:rack1-b", "sess-2"))
+	assert.ErrorIs(t, err, provider.ErrOfferUnavailable)
+
+	status, err := c.GetInstanceStatus(ctx, info.ProviderInstanceID)
+	require.NoError(t, err)
+	assert.True(t, status.Running)
+
+	instances, err := c.ListAllInstances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.True(t, instances[0].IsOurs("deploy-1"))
+
+	// Destroy revokes the key and frees the node
+	require.NoError(t, c.DestroyInstance(ctx, info.ProviderInstanceID))
+	assert.Empty(t, installer.installed)
+	_, err = c.GetInstanceStatus(ctx, info.ProviderInstanceID)
+	assert.True(t, provider.IsNotFoundError(err))
+	assert.NoError(t, c.DestroyInstance(ctx, info.ProviderInstanceID), "destroy is idempotent")
+
+	offers, err = c.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Len(t, offers, 2)
+}
+
+func TestClient_CreateInstanceErrors(t *testing.T) {
+	installer := newFakeInstaller()
+	c := NewClient(testCatalog(), WithKeyInstaller(installer))
+	ctx := context.Background()
+
+	_, err := c.CreateInstance(ctx, createRequest("static:missing", "sess-1"))
+	assert.ErrorIs(t, err, provider.ErrOfferUnavailable)
+
+	_, err = c.CreateInstance(ctx, createRequest("static:rack1-a", "sess/1"))
+	assert.Error(t, err)
+
+	req := createRequest("static:rack1-a", "sess-1")
+	req.LaunchMode = provider.LaunchModeEntrypoint
+	_, err = c.CreateInstance(ctx, req)
+	assert.Error(t, err)
+
+	// A failed key install releases the node
+	installer.err = errors.New("connection refused")
+	_, err = c.CreateInstance(ctx, createRequest("static:rack1-a", "sess-1"))
+	assert.Error(t, err)
+	offers, err := c.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Len(t, offers, 2)
+
+	// Without an admin key nothing can be provisioned
+	_, err = NewClient(testCatalog()).CreateInstance(ctx, createRequest("static:rack1-a", "sess-1"))
+	assert.Error(t, err)
+}
+
+func TestClient_RestoreLeases(t *testing.T) {
+	c := NewClient(testCatalog(), WithKeyInstaller(newFakeInstaller()))
+	ctx := context.Background()
+
+	expires := time.Now().Add(time.Hour)
+	restored := c.RestoreLeases([]*models.Session{
+		{ID: "sess-1", ConsumerID: "consumer-001", Provider: ProviderName, ProviderID: "rack1-a:sess-1", ExpiresAt: expires},
+		{ID: "sess-2", Provider: ProviderName, ProviderID: "gone:sess-2"},
+		{ID: "sess-3", Provider: "vastai", ProviderID: "12345"},
+	}, "deploy-1")
+	assert.Equal(t, 1, restored)
+
+	offers, err := c.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, "static:rack1-b", offers[0].ID)
+
+	instances, err := c.ListAllInstances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "sess-1", instances[0].Tags.ShopperSessionID)
+	assert.True(t, instances[0].IsOurs("deploy-1"))
+}
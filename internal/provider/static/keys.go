@@ -0,0 +1,90 @@
+package static
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+
+	sshexec "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+)
+
+// KeyInstaller grants and revokes session SSH access on a node
+type KeyInstaller interface {
+	// InstallKey appends publicKey, labelled with comment, to the node's authorized keys
+	InstallKey(ctx context.Context, node Node, publicKey, comment string) error
+	// RemoveKey removes every authorized key labelled with comment
+	RemoveKey(ctx context.Context, node Node, comment string) error
+}
+
+// sshKeyInstaller manages authorized_keys on nodes over SSH using an
+// operator-provided admin key
+type sshKeyInstaller struct {
+	executor   *sshexec.Executor
+	privateKey string
+
+	// Host key fingerprints first seen on nodes without a configured one
+	hostKeys   map[string]string
+	hostKeysMu sync.Mutex
+}
+
+// NewSSHKeyInstaller creates a KeyInstaller that connects to nodes with the
+// admin private key (PEM) and edits the SSH user's authorized_keys
+func NewSSHKeyInstaller(privateKey string) KeyInstaller {
+	return &sshKeyInstaller{
+		executor:   sshexec.NewExecutor(),
+		privateKey: privateKey,
+		hostKeys:   make(map[string]string),
+	}
+}
+
+func (k *sshKeyInstaller) InstallKey(ctx context.Context, node Node, publicKey, comment string) error {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return fmt.Errorf("invalid SSH public key: %w", err)
+	}
+	// Re-marshal so only the key type and base64 body reach the shell
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))) + " " + comment
+
+	cmd := "mkdir -p ~/.ssh && chmod 700 ~/.ssh && " +
+		fmt.Sprintf("echo '%s' >> ~/.ssh/authorized_keys && ", line) +
+		"chmod 600 ~/.ssh/authorized_keys"
+	return k.run(ctx, node, cmd)
+}
+
+func (k *sshKeyInstaller) RemoveKey(ctx context.Context, node Node, comment string) error {
+	cmd := fmt.Sprintf("if [ -f ~/.ssh/authorized_keys ]; then sed -i '/ %s$/d' ~/.ssh/authorized_keys; fi", comment)
+	return k.run(ctx, node, cmd)
+}
+
+func (k *sshKeyInstaller) run(ctx context.Context, node Node, cmd string) error {
+	ctx = sshexec.WithHostKeyCallback(ctx, k.hostKeyCallback(node))
+	conn, err := k.executor.Connect(ctx, node.SSHHost, node.SSHPort, node.SSHUser, k.privateKey)
+	if err != nil {
+		return fmt.Errorf("node %s: %w", node.ID, err)
+	}
+	defer conn.Close()
+
+	if _, stderr, err := k.executor.RunCommand(ctx, conn, cmd); err != nil {
+		return fmt.Errorf("node %s: %w (stderr: %s)", node.ID, err, stderr)
+	}
+	return nil
+}
+
+// hostKeyCallback checks the node's host key against its configured
+// fingerprint, or else against the first key it presented
+func (k *sshKeyInstaller) hostKeyCallback(node Node) ssh.HostKeyCallback {
+	fingerprint := node.SSHHostKey
+	if fingerprint == "" {
+		k.hostKeysMu.Lock()
+		fingerprint = k.hostKeys[node.ID]
+		k.hostKeysMu.Unlock()
+	}
+	return sshexec.TrustOnFirstUse(fingerprint, func(fingerprint string) {
+		k.hostKeysMu.Lock()
+		defer k.hostKeysMu.Unlock()
+		k.hostKeys[node.ID] = fingerprint
+	})
+}
@@ -0,0 +1,41 @@
+package static
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	sshexec "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+)
+
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return key
+}
+
+func TestSSHKeyInstaller_PinsHostKeys(t *testing.T) {
+	installer := NewSSHKeyInstaller("admin-key").(*sshKeyInstaller)
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 22}
+	first, other := newTestHostKey(t), newTestHostKey(t)
+	node := Node{ID: "rack1-a", SSHHost: "10.0.0.5"}
+
+	// The first key a node presents is trusted and kept for later connections
+	require.NoError(t, installer.hostKeyCallback(node)("10.0.0.5:22", addr, first))
+	require.NoError(t, installer.hostKeyCallback(node)("10.0.0.5:22", addr, first))
+	var mismatch *sshexec.HostKeyMismatchError
+	assert.ErrorAs(t, installer.hostKeyCallback(node)("10.0.0.5:22", addr, other), &mismatch)
+
+	// A configured fingerprint is enforced from the first connection
+	pinned := Node{ID: "rack1-b", SSHHost: "10.0.0.6", SSHHostKey: sshexec.Fingerprint(first)}
+	assert.ErrorAs(t, installer.hostKeyCallback(pinned)("10.0.0.6:22", addr, other), &mismatch)
+	assert.NoError(t, installer.hostKeyCallback(pinned)("10.0.0.6:22", addr, first))
+}
@@ -351,6 +351,71 @@ func TestAPIContract_CreateInstance_RequestFormat_CloudInit(t *testing.T) {
 	assert.Contains(t, runcmdStr, "authorized_keys")
 }
 
+func TestAPIContract_CreateInstance_RequestFormat_CustomCloudInit(t *testing.T) {
+	var receivedRequest CreateInstanceRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&receivedRequest)
+		json.NewEncoder(w).Encode(CreateInstanceResponse{
+			Data: CreateInstanceResponseData{ID: "test-123", Status: "creating"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-token", WithBaseURL(server.URL))
+	_, err := client.CreateInstance(context.Background(), provider.CreateInstanceRequest{
+		OfferID:      "tensordock-11111111-1111-1111-1111-111111111111-rtx4090",
+		SSHPublicKey: TestSSHKey,
+		Tags:         models.InstanceTags{ShopperSessionID: "test"},
+		OnStartCmd:   "echo started",
+		CloudInit: &models.CloudInitConfig{
+			RunCmd:     []string{"pip install my-tool"},
+			WriteFiles: []models.CloudInitFile{{Path: "/opt/setup.sh", Content: "echo hi", Permissions: "0755"}},
+		},
+	})
+	require.NoError(t, err)
+
+	ci := receivedRequest.Data.Attributes.CloudInit
+	require.NotNil(t, ci)
+	require.Len(t, ci.WriteFiles, 1)
+	assert.Equal(t, WriteFile{Path: "/opt/setup.sh", Content: "echo hi", Permissions: "0755"}, ci.WriteFiles[0])
+
+	// The SSH key is installed first, then the consumer's commands, then on-start
+	require.Len(t, ci.RunCmd, 18)
+	assert.Contains(t, ci.RunCmd[0], "/root/.ssh")
+	assert.Equal(t, "pip install my-tool", ci.RunCmd[16])
+	assert.Equal(t, "echo started", ci.RunCmd[17])
+}
+
+func TestAPIContract_CreateInstance_CustomCloudInitWithoutSSHKey(t *testing.T) {
+	var receivedRequest CreateInstanceRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&receivedRequest)
+		json.NewEncoder(w).Encode(CreateInstanceResponse{
+			Data: CreateInstanceResponseData{ID: "test-123", Status: "creating"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-token", WithBaseURL(server.URL))
+	_, err := client.CreateInstance(context.Background(), provider.CreateInstanceRequest{
+		OfferID: "tensordock-11111111-1111-1111-1111-111111111111-rtx4090",
+		Tags:    models.InstanceTags{ShopperSessionID: "test"},
+		CloudInit: &models.CloudInitConfig{
+			WriteFiles: []models.CloudInitFile{{Path: "/etc/motd", Content: "aGk=", Encoding: "b64"}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Files alone are enough to keep cloud-init
+	ci := receivedRequest.Data.Attributes.CloudInit
+	require.NotNil(t, ci)
+	assert.Empty(t, ci.RunCmd)
+	require.Len(t, ci.WriteFiles, 1)
+	assert.Equal(t, "b64", ci.WriteFiles[0].Encoding)
+}
+
 func TestAPIContract_CreateInstance_RequestFormat_Resources(t *testing.T) {
 	var receivedRequest CreateInstanceRequest
 
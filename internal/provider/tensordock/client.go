@@ -54,7 +54,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 	"golang.org/x/crypto/ssh"
@@ -87,6 +86,12 @@ const (
 	// This helps users understand these offers may not actually be available.
 	TensorDockAvailabilityConfidence = 0.3
 
+	// TensorDock does not report host throughput. Its hosts are in
+	// datacenters, so offers carry these conservative estimates of gigabit
+	// networking and SSD storage, flagged as estimated.
+	estimatedInetMbps          = 1000
+	estimatedDiskBandwidthMBps = 500
+
 	// defaultVCPUs is the default number of vCPUs for new instances
 	defaultVCPUs = 8
 
@@ -97,6 +102,17 @@ const (
 	defaultStorageGB = 100
 )
 
+// Feature flags gating TensorDock behaviors. Both default to enabled, which
+// is the behavior before the flags existed.
+const (
+	// FeatureDedicatedIP requests a dedicated public IP. When off, SSH and
+	// exposed ports are port-forwarded instead.
+	FeatureDedicatedIP = "tensordock.dedicated_ip"
+
+	// FeatureNvidiaAutoInstall repairs or installs NVIDIA drivers via cloud-init
+	FeatureNvidiaAutoInstall = "tensordock.nvidia_auto_install"
+)
+
 // OperationTimeouts holds configurable timeouts for different API operations.
 // Each operation can have its own timeout to account for different latency
 // characteristics (e.g., create operations may need more time than status checks).
@@ -119,154 +135,6 @@ func DefaultTimeouts() OperationTimeouts {
 	}
 }
 
-// CircuitBreakerState represents the current state of the circuit breaker
-type CircuitBreakerState int
-
-const (
-	// CircuitClosed is the normal operating state - requests are allowed
-	CircuitClosed CircuitBreakerState = iota
-	// CircuitOpen means too many failures occurred - requests are blocked
-	CircuitOpen
-	// CircuitHalfOpen allows a test request through to check if service recovered
-	CircuitHalfOpen
-)
-
-// CircuitBreakerConfig configures the circuit breaker behavior
-type CircuitBreakerConfig struct {
-	// FailureThreshold is the number of consecutive failures before opening the circuit
-	FailureThreshold int
-	// ResetTimeout is how long to wait before transitioning from Open to HalfOpen
-	ResetTimeout time.Duration
-	// MaxBackoff is the maximum backoff duration for exponential backoff
-	MaxBackoff time.Duration
-	// BaseBackoff is the initial backoff duration
-	BaseBackoff time.Duration
-}
-
-// DefaultCircuitBreakerConfig returns sensible defaults for the circuit breaker
-func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
-	return CircuitBreakerConfig{
-		FailureThreshold: 5,
-		ResetTimeout:     30 * time.Second,
-		MaxBackoff:       2 * time.Minute,
-		BaseBackoff:      1 * time.Second,
-	}
-}
-
-// circuitBreaker implements a simple circuit breaker pattern with exponential backoff
-type circuitBreaker struct {
-	mu               sync.Mutex
-	state            CircuitBreakerState
-	failures         int
-	lastFailure      time.Time
-	lastStateChange  time.Time
-	config           CircuitBreakerConfig
-	consecutiveWaits int // For exponential backoff
-}
-
-// newCircuitBreaker creates a new circuit breaker with the given configuration
-func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
-	return &circuitBreaker{
-		state:  CircuitClosed,
-		config: config,
-	}
-}
-
-// allow returns true if a request should be allowed, false if circuit is open
-func (cb *circuitBreaker) allow() bool {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	switch cb.state {
-	case CircuitClosed:
-		return true
-	case CircuitOpen:
-		// Check if we should transition to half-open
-		if time.Since(cb.lastStateChange) > cb.config.ResetTimeout {
-			cb.state = CircuitHalfOpen
-			cb.lastStateChange = time.Now()
-			return true
-		}
-		return false
-	case CircuitHalfOpen:
-		// Allow one test request
-		return true
-	default:
-		return true
-	}
-}
-
-// recordSuccess records a successful request
-func (cb *circuitBreaker) recordSuccess() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.failures = 0
-	cb.consecutiveWaits = 0
-	if cb.state == CircuitHalfOpen {
-		cb.state = CircuitClosed
-		cb.lastStateChange = time.Now()
-	}
-}
-
-// recordFailure records a failed request and potentially opens the circuit
-func (cb *circuitBreaker) recordFailure() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.failures++
-	cb.lastFailure = time.Now()
-
-	if cb.state == CircuitHalfOpen {
-		// Failed while testing - go back to open
-		cb.state = CircuitOpen
-		cb.lastStateChange = time.Now()
-		cb.consecutiveWaits++
-		return
-	}
-
-	if cb.failures >= cb.config.FailureThreshold {
-		cb.state = CircuitOpen
-		cb.lastStateChange = time.Now()
-		cb.consecutiveWaits++
-	}
-}
-
-// getBackoff returns the current backoff duration using exponential backoff
-func (cb *circuitBreaker) getBackoff() time.Duration {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	if cb.consecutiveWaits == 0 {
-		return cb.config.BaseBackoff
-	}
-
-	// Cap consecutiveWaits to prevent integer overflow in bit shift
-	// With max shift of 10, we can shift up to 2^10 = 1024x the base backoff
-	waits := cb.consecutiveWaits
-	const maxShift = 10
-	if waits > maxShift {
-		waits = maxShift
-	}
-
-	// Exponential backoff: base * 2^(waits-1), capped at maxBackoff
-	backoff := cb.config.BaseBackoff * time.Duration(1<<uint(waits-1))
-	if backoff > cb.config.MaxBackoff {
-		backoff = cb.config.MaxBackoff
-	}
-	return backoff
-}
-
-// State returns the current circuit breaker state (for monitoring/testing)
-func (cb *circuitBreaker) State() CircuitBreakerState {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	return cb.state
-}
-
-// ErrCircuitOpen is returned when the circuit breaker is open
-var ErrCircuitOpen = errors.New("circuit breaker is open")
-
 // Client implements the provider.Provider interface for TensorDock.
 // It handles authentication, rate limiting, and API communication.
 // locationStats tracks provisioning success/failure rates per location.
@@ -355,6 +223,33 @@ func (ls *locationStats) getConfidenceLocked(locationID string) float64 {
 	return rate
 }
 
+// snapshot returns the persistable stats for a location
+func (ls *locationStats) snapshot(locationID string) models.LocationStats {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	return models.LocationStats{
+		Provider:      "tensordock",
+		LocationID:    locationID,
+		Attempts:      ls.attempts[locationID],
+		Successes:     ls.successes[locationID],
+		LastAttemptAt: ls.lastAttempt[locationID],
+		LastSucceeded: ls.lastWasSuccess[locationID],
+	}
+}
+
+// restore replaces a location's stats with previously persisted ones. Decay
+// still applies on the next attempt, based on the restored last attempt time.
+func (ls *locationStats) restore(stats models.LocationStats) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.attempts[stats.LocationID] = stats.Attempts
+	ls.successes[stats.LocationID] = stats.Successes
+	ls.lastAttempt[stats.LocationID] = stats.LastAttemptAt
+	ls.lastWasSuccess[stats.LocationID] = stats.LastSucceeded
+}
+
 // getStats returns human-readable stats for a location
 func (ls *locationStats) getStats(locationID string) (attempts, successes int, confidence float64) {
 	ls.mu.RLock()
@@ -372,11 +267,10 @@ type Client struct {
 	apiToken string // API Token from TensorDock dashboard
 
 	// Configuration
-	baseURL        string
-	httpClient     *http.Client
-	defaultImage   string
-	timeouts       OperationTimeouts
-	circuitBreaker *circuitBreaker
+	baseURL      string
+	httpClient   *http.Client
+	defaultImage string
+	timeouts     OperationTimeouts
 
 	// Rate limiting to avoid 429 errors (token bucket)
 	limiter *rate.Limiter
@@ -384,11 +278,23 @@ type Client struct {
 	// Debug mode for troubleshooting API issues
 	debugEnabled bool
 
+	// Trust the ssh_key field to install keys instead of cloud-init runcmd
+	nativeSSHKeys bool
+
 	// Structured logging
 	logger *slog.Logger
 
 	// Dynamic availability tracking (stale inventory fix)
 	locationStats *locationStats
+
+	// Optional persistence so location stats survive restarts
+	statsStore LocationStatsStore
+}
+
+// LocationStatsStore persists per-location provisioning stats
+type LocationStatsStore interface {
+	SetLocationStats(ctx context.Context, stats *models.LocationStats) error
+	LoadLocationStats(ctx context.Context, provider string) ([]models.LocationStats, error)
 }
 
 // ClientOption configures the TensorDock client
@@ -439,6 +345,17 @@ func WithDefaultImage(image string) ClientOption {
 	}
 }
 
+// WithNativeSSHKeys makes the client rely on the ssh_key field of the
+// create request to install the session key, for accounts where TensorDock
+// honors it. The cloud-init runcmd workaround is skipped and the client
+// reports provider.FeatureSSHKeyRegistration, which lets the provisioner
+// skip its cloud-init wait.
+func WithNativeSSHKeys(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.nativeSSHKeys = enabled
+	}
+}
+
 // WithDebug enables debug logging for API requests and responses.
 // Logs are prefixed with "[TensorDock DEBUG]" and include:
 // - Request URLs and bodies
@@ -464,21 +381,6 @@ func WithTimeouts(timeouts OperationTimeouts) ClientOption {
 	}
 }
 
-// WithCircuitBreaker configures the circuit breaker for API calls.
-// The circuit breaker helps prevent cascading failures by temporarily
-// blocking requests when the API is experiencing issues.
-//
-// Example:
-//
-//	config := tensordock.DefaultCircuitBreakerConfig()
-//	config.FailureThreshold = 3
-//	client := tensordock.NewClient(key, token, tensordock.WithCircuitBreaker(config))
-func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
-	return func(c *Client) {
-		c.circuitBreaker = newCircuitBreaker(config)
-	}
-}
-
 // WithLogger sets a custom logger for the client.
 // If not provided, slog.Default() is used.
 //
@@ -494,6 +396,50 @@ func WithLogger(logger *slog.Logger) ClientOption {
 	}
 }
 
+// WithLocationStatsStore persists per-location provisioning stats so that
+// availability confidence survives restarts. Call LoadLocationStats at
+// startup to restore them.
+func WithLocationStatsStore(store LocationStatsStore) ClientOption {
+	return func(c *Client) {
+		c.statsStore = store
+	}
+}
+
+// LoadLocationStats restores persisted per-location provisioning stats. It is
+// a no-op when no store is configured.
+func (c *Client) LoadLocationStats(ctx context.Context) error {
+	if c.statsStore == nil {
+		return nil
+	}
+	stats, err := c.statsStore.LoadLocationStats(ctx, c.Name())
+	if err != nil {
+		return err
+	}
+	for _, st := range stats {
+		c.locationStats.restore(st)
+	}
+	c.logger.Info("loaded location stats from store", slog.Int("locations", len(stats)))
+	return nil
+}
+
+// persistLocationStats saves a location's stats in the background. Failures
+// are logged and otherwise ignored; the in-memory stats stay authoritative.
+func (c *Client) persistLocationStats(locationID string) {
+	if c.statsStore == nil {
+		return
+	}
+	stats := c.locationStats.snapshot(locationID)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := c.statsStore.SetLocationStats(ctx, &stats); err != nil {
+			c.logger.Warn("failed to persist location stats",
+				slog.String("location_id", locationID),
+				slog.String("error", err.Error()))
+		}
+	}()
+}
+
 // debugLog logs a message if debug mode is enabled.
 // SECURITY: This function redacts sensitive credentials before logging.
 func (c *Client) debugLog(format string, args ...interface{}) {
@@ -685,16 +631,15 @@ func redactQueryParam(s, param string) string {
 //	)
 func NewClient(apiKey, apiToken string, opts ...ClientOption) *Client {
 	c := &Client{
-		apiKey:         apiKey,
-		apiToken:       apiToken,
-		baseURL:        defaultBaseURL,
-		httpClient:     &http.Client{}, // Timeout set per-operation
-		defaultImage:   defaultImageName,
-		timeouts:       DefaultTimeouts(),
-		circuitBreaker: newCircuitBreaker(DefaultCircuitBreakerConfig()),
-		limiter:        rate.NewLimiter(rate.Limit(2), 3), // 2 req/s, burst 3
-		logger:         slog.Default(),
-		locationStats:  newLocationStats(), // Dynamic availability tracking
+		apiKey:        apiKey,
+		apiToken:      apiToken,
+		baseURL:       defaultBaseURL,
+		httpClient:    &http.Client{}, // Timeout set per-operation
+		defaultImage:  defaultImageName,
+		timeouts:      DefaultTimeouts(),
+		limiter:       rate.NewLimiter(rate.Limit(2), 3), // 2 req/s, burst 3
+		logger:        slog.Default(),
+		locationStats: newLocationStats(), // Dynamic availability tracking
 	}
 
 	for _, opt := range opts {
@@ -714,6 +659,10 @@ func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
 	switch feature {
 	case provider.FeatureCustomImages:
 		return true // TensorDock supports selecting from predefined OS images
+	case provider.FeatureSSHKeyRegistration:
+		return c.nativeSSHKeys // The ssh_key field is ignored unless enabled
+	case provider.FeatureCloudInit:
+		return true
 	default:
 		return false
 	}
@@ -728,20 +677,6 @@ func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
 // fail to provision with "No available nodes found". We set AvailabilityConfidence
 // to 50% to reflect this uncertainty.
 func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (offers []models.GPUOffer, err error) {
-	startTime := time.Now()
-
-	// Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("ListOffers", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListOffers", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -804,20 +739,6 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 // Note: TensorDock's /instances endpoint returns an array directly in the "data"
 // field, not wrapped in {"instances": [...]}.
 func (c *Client) ListAllInstances(ctx context.Context) (instances []provider.ProviderInstance, err error) {
-	startTime := time.Now()
-
-	// Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("ListAllInstances", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListAllInstances", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -958,20 +879,6 @@ func (c *Client) instancesToProviderInstances(instances []Instance) []provider.P
 // The create response does NOT include the IP address. You must poll
 // GetInstanceStatus until SSHHost is populated (typically 5-30 seconds).
 func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstanceRequest) (info *provider.InstanceInfo, err error) {
-	startTime := time.Now()
-
-	// Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("CreateInstance", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("CreateInstance", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -991,6 +898,7 @@ func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstance
 	defer func() {
 		success := err == nil && info != nil && info.ProviderInstanceID != ""
 		c.locationStats.recordAttempt(locationID, success)
+		c.persistLocationStats(locationID)
 		if !success && err != nil {
 			c.logger.Debug("provisioning attempt recorded",
 				slog.String("location_id", locationID),
@@ -1020,20 +928,53 @@ func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstance
 				},
 				// Request dedicated IP for direct port access (all ports exposed)
 				// This is more reliable than port forwarding which was being ignored
-				UseDedicatedIP: true,
+				UseDedicatedIP: req.FeatureEnabled(FeatureDedicatedIP, true),
 			},
 		},
 	}
 
+	// Without a dedicated IP, TensorDock requires SSH to be port-forwarded
+	if !createReq.Data.Attributes.UseDedicatedIP {
+		createReq.Data.Attributes.PortForwards = buildPortForwards(req.ExposedPorts)
+	}
+
 	// Configure SSH key installation via cloud-init
 	// The ssh_key API field is required but doesn't work, so we use runcmd
+	// unless native key registration is enabled
 	if req.SSHPublicKey != "" {
 		// Validate SSH public key before using it
 		if err := ValidateSSHPublicKey(req.SSHPublicKey); err != nil {
 			return nil, fmt.Errorf("SSH key validation failed: %w", err)
 		}
 		createReq.Data.Attributes.SSHKey = req.SSHPublicKey
-		createReq.Data.Attributes.CloudInit = buildSSHKeyCloudInit(req.SSHPublicKey)
+		installDrivers := req.FeatureEnabled(FeatureNvidiaAutoInstall, true)
+		if c.nativeSSHKeys {
+			createReq.Data.Attributes.CloudInit = &CloudInit{}
+			if installDrivers {
+				createReq.Data.Attributes.CloudInit.RunCmd = nvidiaDriverRunCmds()
+			}
+		} else {
+			createReq.Data.Attributes.CloudInit = buildCloudInit(req.SSHPublicKey, installDrivers)
+		}
+	}
+
+	// Merge the consumer's cloud-init after ours, so the SSH key is
+	// installed even if their commands fail
+	if !req.CloudInit.IsEmpty() {
+		if createReq.Data.Attributes.CloudInit == nil {
+			createReq.Data.Attributes.CloudInit = &CloudInit{}
+		}
+		ci := createReq.Data.Attributes.CloudInit
+		for _, f := range req.CloudInit.WriteFiles {
+			ci.WriteFiles = append(ci.WriteFiles, WriteFile{
+				Path:        f.Path,
+				Content:     f.Content,
+				Encoding:    f.Encoding,
+				Permissions: f.Permissions,
+				Owner:       f.Owner,
+			})
+		}
+		ci.RunCmd = append(ci.RunCmd, req.CloudInit.RunCmd...)
 	}
 
 	// Append OnStartCmd to cloud-init runcmd if specified
@@ -1044,6 +985,11 @@ func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstance
 		)
 	}
 
+	// Nothing left for cloud-init to do
+	if ci := createReq.Data.Attributes.CloudInit; ci != nil && len(ci.RunCmd) == 0 && len(ci.WriteFiles) == 0 {
+		createReq.Data.Attributes.CloudInit = nil
+	}
+
 	reqURL := c.buildURL("/instances")
 
 	body, err := json.Marshal(createReq)
@@ -1121,25 +1067,11 @@ func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstance
 // This method is idempotent - calling it on an already-deleted instance returns
 // success (HTTP 404 is not treated as an error).
 func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err error) {
-	startTime := time.Now()
-
 	// Validate instance ID to prevent path traversal and other attacks
 	if err := ValidateInstanceID(instanceID); err != nil {
 		return err
 	}
 
-	// Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("DestroyInstance", startTime, err)
-		return err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("DestroyInstance", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -1183,6 +1115,66 @@ func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err er
 	return nil
 }
 
+// StopInstance powers off an instance, keeping its disk. TensorDock stops
+// billing its GPUs but keeps billing its storage. The GPUs are released, so
+// starting it again fails if the host has none free by then.
+func (c *Client) StopInstance(ctx context.Context, instanceID string) error {
+	return c.powerAction(ctx, instanceID, "stop", "StopInstance")
+}
+
+// StartInstance powers on an instance stopped by StopInstance. Its SSH port
+// may change; read it from GetInstanceStatus once the instance runs.
+func (c *Client) StartInstance(ctx context.Context, instanceID string) error {
+	return c.powerAction(ctx, instanceID, "start", "StartInstance")
+}
+
+// powerAction posts a power action ("stop" or "start") for an instance
+func (c *Client) powerAction(ctx context.Context, instanceID, action, operation string) error {
+	// Validate instance ID to prevent path traversal and other attacks
+	if err := ValidateInstanceID(instanceID); err != nil {
+		return err
+	}
+
+	if err := c.rateLimit(ctx); err != nil {
+		return fmt.Errorf("rate limit wait: %w", err)
+	}
+
+	// Power actions are as quick to accept as destroys
+	ctx, cancel := c.contextWithTimeout(ctx, c.timeouts.Destroy)
+	defer cancel()
+
+	reqURL := c.buildURL(fmt.Sprintf("/instances/%s/%s", instanceID, action))
+
+	c.debugLog("%s request URL: %s", operation, reqURL)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setAuthHeader(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.debugLog("%s response status: %d", operation, resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		return c.handleError(resp, operation)
+	}
+
+	c.logger.Info("instance power action sent",
+		slog.String("provider", "tensordock"),
+		slog.String("instance_id", instanceID),
+		slog.String("action", action),
+	)
+	return nil
+}
+
 // GetInstanceStatus returns the current status of an instance.
 //
 // This is the primary method for:
@@ -1207,25 +1199,11 @@ func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err er
 //	    time.Sleep(5 * time.Second)
 //	}
 func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (status *provider.InstanceStatus, err error) {
-	startTime := time.Now()
-
 	// Validate instance ID to prevent path traversal and other attacks
 	if err := ValidateInstanceID(instanceID); err != nil {
 		return nil, err
 	}
 
-	// Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("GetInstanceStatus", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("GetInstanceStatus", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -1338,70 +1316,6 @@ func (c *Client) contextWithTimeout(parent context.Context, timeout time.Duratio
 	return context.WithTimeout(parent, timeout)
 }
 
-// checkCircuitBreaker returns an error if the circuit breaker is open.
-// If the circuit is open, it also logs the current backoff duration.
-func (c *Client) checkCircuitBreaker() error {
-	if !c.circuitBreaker.allow() {
-		backoff := c.circuitBreaker.getBackoff()
-		c.debugLog("Circuit breaker is open, backoff: %v", backoff)
-		return fmt.Errorf("%w: retry after %v", ErrCircuitOpen, backoff)
-	}
-	return nil
-}
-
-// recordAPIResult records the result of an API call to the circuit breaker.
-// It should be called after each API request completes.
-func (c *Client) recordAPIResult(err error) {
-	if err == nil {
-		c.circuitBreaker.recordSuccess()
-		return
-	}
-
-	// Only count certain errors as failures for the circuit breaker
-	// Don't count validation errors, not found errors, etc.
-	var providerErr *provider.ProviderError
-	if errors.As(err, &providerErr) {
-		// Rate limits and server errors should trigger circuit breaker
-		if providerErr.StatusCode >= 500 || providerErr.StatusCode == 429 {
-			c.circuitBreaker.recordFailure()
-			return
-		}
-	}
-
-	// Network errors should trigger circuit breaker
-	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-		// Don't trigger for context cancellation by caller
-		return
-	}
-
-	// Other network-level errors
-	if strings.Contains(err.Error(), "connection refused") ||
-		strings.Contains(err.Error(), "no such host") ||
-		strings.Contains(err.Error(), "network is unreachable") {
-		c.circuitBreaker.recordFailure()
-	}
-}
-
-// recordAPIMetrics records API call metrics including response time and call count.
-// This should be called after each API request completes.
-func (c *Client) recordAPIMetrics(operation string, startTime time.Time, err error) {
-	duration := time.Since(startTime)
-	metrics.RecordProviderAPIResponseTime("tensordock", operation, duration)
-
-	status := "success"
-	if err != nil {
-		if errors.Is(err, ErrCircuitOpen) {
-			status = "circuit_open"
-		} else {
-			status = "error"
-		}
-	}
-	metrics.RecordProviderAPICall("tensordock", operation, status)
-
-	// Update circuit breaker state metric
-	metrics.UpdateProviderCircuitBreakerState("tensordock", int(c.circuitBreaker.State()))
-}
-
 // maxErrorMessageLength is the maximum length for error messages.
 // Longer messages are truncated to prevent memory issues and log bloat.
 const maxErrorMessageLength = 1000
@@ -1549,6 +1463,19 @@ func ValidateSSHPublicKey(publicKey string) error {
 	return nil
 }
 
+// buildPortForwards forwards SSH plus any exposed ports. TensorDock may
+// assign different external ports; GetInstanceStatus reports the real ones.
+func buildPortForwards(exposedPorts []int) []PortForward {
+	forwards := []PortForward{{Protocol: "tcp", InternalPort: 22, ExternalPort: 22}}
+	for _, port := range exposedPorts {
+		if port == 22 {
+			continue
+		}
+		forwards = append(forwards, PortForward{Protocol: "tcp", InternalPort: port, ExternalPort: port})
+	}
+	return forwards
+}
+
 // buildSSHKeyCloudInit creates cloud-init configuration for SSH key installation.
 //
 // TensorDock's ssh_key API field doesn't actually install the key, and the
@@ -1574,17 +1501,61 @@ func buildSSHKeyCloudInit(publicKey string) *CloudInit {
 // 2-5 min after boot. When installNvidiaDrivers is true, we kill the lock holder, fix
 // dpkg state, attempt DKMS build, and fall back to full driver install if needed.
 func buildCloudInit(publicKey string, installNvidiaDrivers bool) *CloudInit {
-	// TensorDock's cloud-init write_files may not support encoding field,
-	// and runs before runcmd (which creates directories).
-	// Use runcmd only for reliable SSH key installation with proper ordering.
-	//
-	// We install keys for both root and the default 'user' account that
-	// TensorDock creates, since the SSH user may vary.
-	//
+	runCmds := sshKeyRunCmds(publicKey)
+	if installNvidiaDrivers {
+		runCmds = append(runCmds, nvidiaDriverRunCmds()...)
+	}
+
+	return &CloudInit{
+		RunCmd: runCmds,
+	}
+}
+
+// nvidiaDriverRunCmds fixes NVIDIA drivers on TensorDock VMs.
+//
+// BUG-009/013/014: Root cause chain: base images have partially-installed nvidia
+// packages (dpkg iU state), DKMS kernel module never built, and unattended-upgrades
+// holds dpkg lock for 2-5 min.
+func nvidiaDriverRunCmds() []string {
+	return []string{
+		// BUG-014: Kill unattended-upgrades and wait for dpkg lock
+		"systemctl stop unattended-upgrades 2>/dev/null || true",
+		"killall -9 unattended-upgrades 2>/dev/null || true",
+		"while fuser /var/lib/dpkg/lock-frontend >/dev/null 2>&1; do sleep 2; done",
+		// BUG-013: Fix partially-installed packages first
+		"DEBIAN_FRONTEND=noninteractive dpkg --configure -a",
+		// BUG-009: Install/fix nvidia driver if nvidia-smi doesn't work
+		"if ! nvidia-smi > /dev/null 2>&1; then " +
+			"echo 'nvidia-smi failed, attempting driver fix...' >> /var/log/cloud-init-nvidia.log && " +
+			// Try DKMS build first (handles iU state where package is installed but module isn't)
+			"if dpkg -l nvidia-dkms-* 2>/dev/null | grep -q '^.i'; then " +
+			"NVIDIA_VER=$(dpkg -l nvidia-dkms-* 2>/dev/null | grep '^.i' | awk '{print $3}' | head -1 | cut -d- -f1) && " +
+			"dkms build nvidia/$NVIDIA_VER >> /var/log/cloud-init-nvidia.log 2>&1 && " +
+			"dkms install nvidia/$NVIDIA_VER >> /var/log/cloud-init-nvidia.log 2>&1 && " +
+			"modprobe nvidia >> /var/log/cloud-init-nvidia.log 2>&1; " +
+			"else " +
+			// No nvidia-dkms package at all — full install
+			"apt-get update -o DPkg::Lock::Timeout=120 >> /var/log/cloud-init-nvidia.log 2>&1 && " +
+			"DEBIAN_FRONTEND=noninteractive apt-get install -y -o DPkg::Lock::Timeout=120 nvidia-driver-550 >> /var/log/cloud-init-nvidia.log 2>&1 && " +
+			"modprobe nvidia >> /var/log/cloud-init-nvidia.log 2>&1; " +
+			"fi && " +
+			"echo 'Driver fix complete.' >> /var/log/cloud-init-nvidia.log; " +
+			"fi",
+	}
+}
+
+// sshKeyRunCmds installs publicKey for root and the default 'user' account.
+//
+// TensorDock's cloud-init write_files may not support encoding field,
+// and runs before runcmd (which creates directories).
+// Use runcmd only for reliable SSH key installation with proper ordering.
+//
+// We install keys for both root and the default 'user' account that
+// TensorDock creates, since the SSH user may vary.
+func sshKeyRunCmds(publicKey string) []string {
 	// Shell-escape the key to handle any special characters.
 	escapedKey := shellEscapeSingleQuote(publicKey)
-
-	runCmds := []string{
+	return []string{
 		// Create directories with proper permissions first
 		"mkdir -p /root/.ssh",
 		"chmod 700 /root/.ssh",
@@ -1601,41 +1572,6 @@ func buildCloudInit(publicKey string, installNvidiaDrivers bool) *CloudInit {
 		"chmod 600 /home/user/.ssh/authorized_keys",
 		"chown user:user /home/user/.ssh/authorized_keys",
 	}
-
-	// BUG-009/013/014: Fix NVIDIA drivers on TensorDock VMs.
-	// Root cause chain: base images have partially-installed nvidia packages (dpkg iU state),
-	// DKMS kernel module never built, and unattended-upgrades holds dpkg lock for 2-5 min.
-	if installNvidiaDrivers {
-		runCmds = append(runCmds,
-			// BUG-014: Kill unattended-upgrades and wait for dpkg lock
-			"systemctl stop unattended-upgrades 2>/dev/null || true",
-			"killall -9 unattended-upgrades 2>/dev/null || true",
-			"while fuser /var/lib/dpkg/lock-frontend >/dev/null 2>&1; do sleep 2; done",
-			// BUG-013: Fix partially-installed packages first
-			"DEBIAN_FRONTEND=noninteractive dpkg --configure -a",
-			// BUG-009: Install/fix nvidia driver if nvidia-smi doesn't work
-			"if ! nvidia-smi > /dev/null 2>&1; then "+
-				"echo 'nvidia-smi failed, attempting driver fix...' >> /var/log/cloud-init-nvidia.log && "+
-				// Try DKMS build first (handles iU state where package is installed but module isn't)
-				"if dpkg -l nvidia-dkms-* 2>/dev/null | grep -q '^.i'; then "+
-				"NVIDIA_VER=$(dpkg -l nvidia-dkms-* 2>/dev/null | grep '^.i' | awk '{print $3}' | head -1 | cut -d- -f1) && "+
-				"dkms build nvidia/$NVIDIA_VER >> /var/log/cloud-init-nvidia.log 2>&1 && "+
-				"dkms install nvidia/$NVIDIA_VER >> /var/log/cloud-init-nvidia.log 2>&1 && "+
-				"modprobe nvidia >> /var/log/cloud-init-nvidia.log 2>&1; "+
-				"else "+
-				// No nvidia-dkms package at all — full install
-				"apt-get update -o DPkg::Lock::Timeout=120 >> /var/log/cloud-init-nvidia.log 2>&1 && "+
-				"DEBIAN_FRONTEND=noninteractive apt-get install -y -o DPkg::Lock::Timeout=120 nvidia-driver-550 >> /var/log/cloud-init-nvidia.log 2>&1 && "+
-				"modprobe nvidia >> /var/log/cloud-init-nvidia.log 2>&1; "+
-				"fi && "+
-				"echo 'Driver fix complete.' >> /var/log/cloud-init-nvidia.log; "+
-				"fi",
-		)
-	}
-
-	return &CloudInit{
-		RunCmd: runCmds,
-	}
 }
 
 // shellEscapeSingleQuote escapes a string for use inside single quotes in shell.
@@ -1709,6 +1645,10 @@ func locationGPUToOffer(loc Location, gpu LocationGPU) models.GPUOffer {
 		MaxDuration:            0, // No maximum duration
 		FetchedAt:              time.Now(),
 		AvailabilityConfidence: TensorDockAvailabilityConfidence,
+		InetDownMbps:           estimatedInetMbps,
+		InetUpMbps:             estimatedInetMbps,
+		DiskBandwidthMBps:      estimatedDiskBandwidthMBps,
+		BandwidthEstimated:     true,
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/middleware"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,6 +33,7 @@ func TestClient_SupportsFeature(t *testing.T) {
 		{provider.FeatureInstanceTags, false},
 		{provider.FeatureSpotPricing, false},
 		{provider.FeatureIdleDetection, false},
+		{provider.FeatureSSHKeyRegistration, false},
 	}
 
 	for _, tt := range tests {
@@ -39,6 +41,9 @@ func TestClient_SupportsFeature(t *testing.T) {
 			assert.Equal(t, tt.expected, c.SupportsFeature(tt.feature))
 		})
 	}
+
+	native := NewClient("test-key", "test-token", WithNativeSSHKeys(true))
+	assert.True(t, native.SupportsFeature(provider.FeatureSSHKeyRegistration))
 }
 
 func TestClient_ListOffers(t *testing.T) {
@@ -566,6 +571,35 @@ func TestClient_DestroyInstance_InvalidID(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidInstanceID)
 }
 
+func TestClient_StopAndStartInstance(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-token", WithBaseURL(server.URL))
+	require.NoError(t, client.StopInstance(context.Background(), "inst-123"))
+	require.NoError(t, client.StartInstance(context.Background(), "inst-123"))
+	assert.Equal(t, []string{"/instances/inst-123/stop", "/instances/inst-123/start"}, paths)
+
+	var _ provider.PowerController = client
+}
+
+func TestClient_StopInstance_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-token", WithBaseURL(server.URL))
+	assert.ErrorIs(t, client.StopInstance(context.Background(), "inst-123"), provider.ErrInstanceNotFound)
+	assert.ErrorIs(t, client.StartInstance(context.Background(), "inst/../../etc"), ErrInvalidInstanceID)
+}
+
 func TestDefaultTimeouts(t *testing.T) {
 	timeouts := DefaultTimeouts()
 
@@ -621,142 +655,6 @@ func TestContextWithTimeout(t *testing.T) {
 	})
 }
 
-func TestCircuitBreaker(t *testing.T) {
-	t.Run("starts in closed state", func(t *testing.T) {
-		cb := newCircuitBreaker(DefaultCircuitBreakerConfig())
-		assert.Equal(t, CircuitClosed, cb.State())
-		assert.True(t, cb.allow())
-	})
-
-	t.Run("opens after threshold failures", func(t *testing.T) {
-		config := CircuitBreakerConfig{
-			FailureThreshold: 3,
-			ResetTimeout:     1 * time.Second,
-			MaxBackoff:       1 * time.Minute,
-			BaseBackoff:      100 * time.Millisecond,
-		}
-		cb := newCircuitBreaker(config)
-
-		// Record failures
-		for i := 0; i < 3; i++ {
-			cb.recordFailure()
-		}
-
-		assert.Equal(t, CircuitOpen, cb.State())
-		assert.False(t, cb.allow())
-	})
-
-	t.Run("resets to half-open after timeout", func(t *testing.T) {
-		config := CircuitBreakerConfig{
-			FailureThreshold: 2,
-			ResetTimeout:     50 * time.Millisecond,
-			MaxBackoff:       1 * time.Minute,
-			BaseBackoff:      100 * time.Millisecond,
-		}
-		cb := newCircuitBreaker(config)
-
-		// Open the circuit
-		cb.recordFailure()
-		cb.recordFailure()
-		assert.Equal(t, CircuitOpen, cb.State())
-
-		// Wait for reset timeout
-		time.Sleep(60 * time.Millisecond)
-
-		// Should transition to half-open and allow request
-		assert.True(t, cb.allow())
-		assert.Equal(t, CircuitHalfOpen, cb.State())
-	})
-
-	t.Run("closes on success in half-open state", func(t *testing.T) {
-		config := CircuitBreakerConfig{
-			FailureThreshold: 2,
-			ResetTimeout:     10 * time.Millisecond,
-			MaxBackoff:       1 * time.Minute,
-			BaseBackoff:      100 * time.Millisecond,
-		}
-		cb := newCircuitBreaker(config)
-
-		// Open the circuit
-		cb.recordFailure()
-		cb.recordFailure()
-
-		// Wait and transition to half-open
-		time.Sleep(15 * time.Millisecond)
-		cb.allow()
-		assert.Equal(t, CircuitHalfOpen, cb.State())
-
-		// Success closes the circuit
-		cb.recordSuccess()
-		assert.Equal(t, CircuitClosed, cb.State())
-	})
-
-	t.Run("reopens on failure in half-open state", func(t *testing.T) {
-		config := CircuitBreakerConfig{
-			FailureThreshold: 2,
-			ResetTimeout:     10 * time.Millisecond,
-			MaxBackoff:       1 * time.Minute,
-			BaseBackoff:      100 * time.Millisecond,
-		}
-		cb := newCircuitBreaker(config)
-
-		// Open the circuit
-		cb.recordFailure()
-		cb.recordFailure()
-
-		// Wait and transition to half-open
-		time.Sleep(15 * time.Millisecond)
-		cb.allow()
-		assert.Equal(t, CircuitHalfOpen, cb.State())
-
-		// Failure reopens
-		cb.recordFailure()
-		assert.Equal(t, CircuitOpen, cb.State())
-	})
-
-	t.Run("exponential backoff increases", func(t *testing.T) {
-		config := CircuitBreakerConfig{
-			FailureThreshold: 1,
-			ResetTimeout:     10 * time.Millisecond,
-			MaxBackoff:       10 * time.Second,
-			BaseBackoff:      100 * time.Millisecond,
-		}
-		cb := newCircuitBreaker(config)
-
-		// First open
-		cb.recordFailure()
-		backoff1 := cb.getBackoff()
-
-		// Wait and let it try again, fail again
-		time.Sleep(15 * time.Millisecond)
-		cb.allow() // transitions to half-open
-		cb.recordFailure()
-		backoff2 := cb.getBackoff()
-
-		assert.True(t, backoff2 > backoff1, "backoff should increase: %v > %v", backoff2, backoff1)
-	})
-
-	t.Run("backoff caps at max", func(t *testing.T) {
-		config := CircuitBreakerConfig{
-			FailureThreshold: 1,
-			ResetTimeout:     1 * time.Millisecond,
-			MaxBackoff:       500 * time.Millisecond,
-			BaseBackoff:      100 * time.Millisecond,
-		}
-		cb := newCircuitBreaker(config)
-
-		// Cause many failures to increase backoff
-		for i := 0; i < 20; i++ {
-			cb.recordFailure()
-			time.Sleep(2 * time.Millisecond)
-			cb.allow()
-		}
-
-		backoff := cb.getBackoff()
-		assert.LessOrEqual(t, backoff, config.MaxBackoff)
-	})
-}
-
 func TestClient_CircuitBreakerIntegration(t *testing.T) {
 	failCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -766,17 +664,20 @@ func TestClient_CircuitBreakerIntegration(t *testing.T) {
 	}))
 	defer server.Close()
 
-	config := CircuitBreakerConfig{
+	config := middleware.CircuitBreakerConfig{
 		FailureThreshold: 2,
 		ResetTimeout:     100 * time.Millisecond,
 		MaxBackoff:       1 * time.Second,
 		BaseBackoff:      50 * time.Millisecond,
 	}
 
-	client := NewClient("test-key", "test-token",
-		WithBaseURL(server.URL),
-		WithMinInterval(0),
-		WithCircuitBreaker(config),
+	client := middleware.Wrap(
+		NewClient("test-key", "test-token",
+			WithBaseURL(server.URL),
+			WithMinInterval(0),
+		),
+		middleware.WithCircuitBreaker(config),
+		middleware.WithRetry(middleware.RetryPolicy{MaxAttempts: 1}),
 	)
 
 	// First two calls should hit the server and fail
@@ -790,7 +691,7 @@ func TestClient_CircuitBreakerIntegration(t *testing.T) {
 	serverCallsBefore := failCount
 	_, err3 := client.ListOffers(context.Background(), models.OfferFilter{})
 	require.Error(t, err3)
-	assert.ErrorIs(t, err3, ErrCircuitOpen)
+	assert.ErrorIs(t, err3, middleware.ErrCircuitOpen)
 	assert.Equal(t, serverCallsBefore, failCount, "should not have called server when circuit is open")
 }
 
@@ -857,6 +758,9 @@ func TestLocationGPUToOffer(t *testing.T) {
 	assert.Equal(t, 0.40, offer.PricePerHour)
 	assert.Contains(t, offer.Location, "TestCity")
 	assert.InDelta(t, 0.67, offer.Reliability, 0.01) // Tier 2/3
+	assert.True(t, offer.BandwidthEstimated, "TensorDock does not report throughput")
+	assert.Positive(t, offer.InetDownMbps)
+	assert.Positive(t, offer.DiskBandwidthMBps)
 }
 
 func TestInstancesToProviderInstances_LogsUnknownInstances(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1123,6 +1124,65 @@ func TestLocationStats_GetStats(t *testing.T) {
 	assert.InDelta(t, 0.333, confidence, 0.01)
 }
 
+// fakeLocationStatsStore keeps location stats in memory like storage.OfferFailureStore
+type fakeLocationStatsStore struct {
+	mu    sync.Mutex
+	stats map[string]models.LocationStats
+}
+
+func (s *fakeLocationStatsStore) SetLocationStats(ctx context.Context, stats *models.LocationStats) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats[stats.Provider+":"+stats.LocationID] = *stats
+	return nil
+}
+
+func (s *fakeLocationStatsStore) LoadLocationStats(ctx context.Context, provider string) ([]models.LocationStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stats []models.LocationStats
+	for _, st := range s.stats {
+		if st.Provider == provider {
+			stats = append(stats, st)
+		}
+	}
+	return stats, nil
+}
+
+func (s *fakeLocationStatsStore) get(locationID string) (models.LocationStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats["tensordock:"+locationID]
+	return st, ok
+}
+
+func TestLocationStats_PersistAndReload(t *testing.T) {
+	store := &fakeLocationStatsStore{stats: make(map[string]models.LocationStats)}
+	client := NewClient("key", "token", WithLocationStatsStore(store))
+
+	client.locationStats.recordAttempt("loc-bad", false)
+	client.persistLocationStats("loc-bad")
+	client.locationStats.recordAttempt("loc-bad", false)
+	client.persistLocationStats("loc-bad")
+
+	require.Eventually(t, func() bool {
+		st, ok := store.get("loc-bad")
+		return ok && st.Attempts == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// A restarted client starts from the persisted stats instead of the default
+	restarted := NewClient("key", "token", WithLocationStatsStore(store))
+	assert.Equal(t, TensorDockAvailabilityConfidence, restarted.locationStats.getConfidence("loc-bad"))
+	require.NoError(t, restarted.LoadLocationStats(context.Background()))
+	attempts, successes, confidence := restarted.locationStats.getStats("loc-bad")
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 0, successes)
+	assert.Equal(t, 0.05, confidence)
+
+	// Without a store, loading is a no-op
+	assert.NoError(t, NewClient("key", "token").LoadLocationStats(context.Background()))
+}
+
 // =============================================================================
 // GPU Name Parsing Edge Cases
 // =============================================================================
@@ -333,6 +333,118 @@ func TestCreateInstance_DedicatedIP(t *testing.T) {
 	assert.True(t, capturedRequest.Data.Attributes.UseDedicatedIP, "useDedicatedIp should be true")
 }
 
+// TestCreateInstance_FeatureFlagsOff verifies the port-forward and no-driver
+// fallbacks used when the dedicated IP and NVIDIA install flags are rolled back
+func TestCreateInstance_FeatureFlagsOff(t *testing.T) {
+	var capturedRequest CreateInstanceRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.URL.Path == "/instances" {
+			err := json.NewDecoder(r.Body).Decode(&capturedRequest)
+			require.NoError(t, err)
+
+			resp := CreateInstanceResponse{
+				Data: CreateInstanceResponseData{
+					Type:   "virtualmachine",
+					ID:     "inst-123",
+					Name:   "shopper-session-abc",
+					Status: "creating",
+				},
+			}
+			json.NewEncoder(w).Encode(resp)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-token",
+		WithBaseURL(server.URL),
+		WithMinInterval(0))
+
+	req := provider.CreateInstanceRequest{
+		OfferID:      "tensordock-1a779525-4c04-4f2c-aa45-58b47d54bb38-geforcertx4090-pcie-24gb",
+		SessionID:    "session-abc",
+		SSHPublicKey: TestSSHKey,
+		ExposedPorts: []int{8000},
+		Tags: models.InstanceTags{
+			ShopperSessionID: "session-abc",
+		},
+		Features: map[string]bool{
+			FeatureDedicatedIP:       false,
+			FeatureNvidiaAutoInstall: false,
+		},
+	}
+
+	_, err := client.CreateInstance(context.Background(), req)
+	require.NoError(t, err)
+
+	attrs := capturedRequest.Data.Attributes
+	assert.False(t, attrs.UseDedicatedIP)
+	assert.Equal(t, []PortForward{
+		{Protocol: "tcp", InternalPort: 22, ExternalPort: 22},
+		{Protocol: "tcp", InternalPort: 8000, ExternalPort: 8000},
+	}, attrs.PortForwards)
+
+	require.NotNil(t, attrs.CloudInit)
+	for _, cmd := range attrs.CloudInit.RunCmd {
+		assert.NotContains(t, cmd, "nvidia-smi", "driver install should be skipped")
+	}
+}
+
+// TestCreateInstance_NativeSSHKeys verifies the key is sent only in the ssh_key
+// field when native key registration is enabled
+func TestCreateInstance_NativeSSHKeys(t *testing.T) {
+	var capturedRequest CreateInstanceRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.URL.Path == "/instances" {
+			err := json.NewDecoder(r.Body).Decode(&capturedRequest)
+			require.NoError(t, err)
+
+			resp := CreateInstanceResponse{
+				Data: CreateInstanceResponseData{
+					Type:   "virtualmachine",
+					ID:     "inst-123",
+					Name:   "shopper-session-abc",
+					Status: "creating",
+				},
+			}
+			json.NewEncoder(w).Encode(resp)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-token",
+		WithBaseURL(server.URL),
+		WithMinInterval(0),
+		WithNativeSSHKeys(true))
+
+	req := provider.CreateInstanceRequest{
+		OfferID:      "tensordock-1a779525-4c04-4f2c-aa45-58b47d54bb38-geforcertx4090-pcie-24gb",
+		SessionID:    "session-abc",
+		SSHPublicKey: TestSSHKey,
+		Tags: models.InstanceTags{
+			ShopperSessionID: "session-abc",
+		},
+	}
+
+	_, err := client.CreateInstance(context.Background(), req)
+	require.NoError(t, err)
+
+	attrs := capturedRequest.Data.Attributes
+	assert.Equal(t, TestSSHKey, attrs.SSHKey)
+	require.NotNil(t, attrs.CloudInit, "driver fix still runs via cloud-init")
+	for _, cmd := range attrs.CloudInit.RunCmd {
+		assert.NotContains(t, cmd, "authorized_keys", "key should not be installed via runcmd")
+	}
+
+	// With nothing else to run, cloud-init is omitted entirely
+	req.Features = map[string]bool{FeatureNvidiaAutoInstall: false}
+	capturedRequest = CreateInstanceRequest{}
+	_, err = client.CreateInstance(context.Background(), req)
+	require.NoError(t, err)
+	assert.Nil(t, capturedRequest.Data.Attributes.CloudInit)
+}
+
 // TestGetInstanceStatus_DynamicPort verifies dynamic port assignment handling
 func TestGetInstanceStatus_DynamicPort(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
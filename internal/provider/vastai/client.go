@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -17,8 +16,8 @@ import (
 
 	"golang.org/x/time/rate"
 
-	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/middleware"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
@@ -27,154 +26,6 @@ const (
 	defaultTimeout = 30 * time.Second
 )
 
-// Bug #48: Circuit breaker configuration for Vast.ai
-// CircuitBreakerState represents the current state of the circuit breaker
-type CircuitBreakerState int
-
-const (
-	// CircuitClosed is the normal operating state - requests are allowed
-	CircuitClosed CircuitBreakerState = iota
-	// CircuitOpen means too many failures occurred - requests are blocked
-	CircuitOpen
-	// CircuitHalfOpen allows a test request through to check if service recovered
-	CircuitHalfOpen
-)
-
-// CircuitBreakerConfig configures the circuit breaker behavior
-type CircuitBreakerConfig struct {
-	// FailureThreshold is the number of consecutive failures before opening the circuit
-	FailureThreshold int
-	// ResetTimeout is how long to wait before transitioning from Open to HalfOpen
-	ResetTimeout time.Duration
-	// MaxBackoff is the maximum backoff duration for exponential backoff
-	MaxBackoff time.Duration
-	// BaseBackoff is the initial backoff duration
-	BaseBackoff time.Duration
-}
-
-// DefaultCircuitBreakerConfig returns sensible defaults for the circuit breaker
-func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
-	return CircuitBreakerConfig{
-		FailureThreshold: 5,
-		ResetTimeout:     30 * time.Second,
-		MaxBackoff:       2 * time.Minute,
-		BaseBackoff:      1 * time.Second,
-	}
-}
-
-// circuitBreaker implements a simple circuit breaker pattern with exponential backoff
-type circuitBreaker struct {
-	mu               sync.Mutex
-	state            CircuitBreakerState
-	failures         int
-	lastFailure      time.Time
-	lastStateChange  time.Time
-	config           CircuitBreakerConfig
-	consecutiveWaits int // For exponential backoff
-}
-
-// newCircuitBreaker creates a new circuit breaker with the given configuration
-func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
-	return &circuitBreaker{
-		state:  CircuitClosed,
-		config: config,
-	}
-}
-
-// allow returns true if a request should be allowed, false if circuit is open
-func (cb *circuitBreaker) allow() bool {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	switch cb.state {
-	case CircuitClosed:
-		return true
-	case CircuitOpen:
-		// Check if we should transition to half-open
-		if time.Since(cb.lastStateChange) > cb.config.ResetTimeout {
-			cb.state = CircuitHalfOpen
-			cb.lastStateChange = time.Now()
-			return true
-		}
-		return false
-	case CircuitHalfOpen:
-		// Allow one test request
-		return true
-	default:
-		return true
-	}
-}
-
-// recordSuccess records a successful request
-func (cb *circuitBreaker) recordSuccess() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.failures = 0
-	cb.consecutiveWaits = 0
-	if cb.state == CircuitHalfOpen {
-		cb.state = CircuitClosed
-		cb.lastStateChange = time.Now()
-	}
-}
-
-// recordFailure records a failed request and potentially opens the circuit
-func (cb *circuitBreaker) recordFailure() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.failures++
-	cb.lastFailure = time.Now()
-
-	if cb.state == CircuitHalfOpen {
-		// Failed while testing - go back to open
-		cb.state = CircuitOpen
-		cb.lastStateChange = time.Now()
-		cb.consecutiveWaits++
-		return
-	}
-
-	if cb.failures >= cb.config.FailureThreshold {
-		cb.state = CircuitOpen
-		cb.lastStateChange = time.Now()
-		cb.consecutiveWaits++
-	}
-}
-
-// getBackoff returns the current backoff duration using exponential backoff
-func (cb *circuitBreaker) getBackoff() time.Duration {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	if cb.consecutiveWaits == 0 {
-		return cb.config.BaseBackoff
-	}
-
-	// Cap consecutiveWaits to prevent integer overflow in bit shift
-	waits := cb.consecutiveWaits
-	const maxShift = 10
-	if waits > maxShift {
-		waits = maxShift
-	}
-
-	// Exponential backoff: base * 2^(waits-1), capped at maxBackoff
-	backoff := cb.config.BaseBackoff * time.Duration(1<<uint(waits-1))
-	if backoff > cb.config.MaxBackoff {
-		backoff = cb.config.MaxBackoff
-	}
-	return backoff
-}
-
-// State returns the current circuit breaker state (for monitoring/testing)
-func (cb *circuitBreaker) State() CircuitBreakerState {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	return cb.state
-}
-
-// ErrCircuitOpen is returned when the circuit breaker is open
-var ErrCircuitOpen = errors.New("circuit breaker is open")
-
 // templateCacheTTL is how long templates are cached before refetching.
 // Templates change infrequently, so use a longer TTL than inventory.
 const templateCacheTTL = 1 * time.Hour
@@ -195,6 +46,10 @@ type bundleCache struct {
 
 // Compile-time interface checks
 var _ provider.BalanceProvider = (*Client)(nil)
+var _ provider.ChargesProvider = (*Client)(nil)
+var _ provider.UsageProvider = (*Client)(nil)
+var _ provider.SSHKeyProvider = (*Client)(nil)
+var _ provider.LogsProvider = (*Client)(nil)
 
 // Client implements the provider.Provider interface for Vast.ai
 type Client struct {
@@ -205,9 +60,6 @@ type Client struct {
 	// Rate limiting (token bucket)
 	limiter *rate.Limiter
 
-	// Bug #48: Circuit breaker for API calls
-	circuitBreaker *circuitBreaker
-
 	// Template cache
 	templates *templateCache
 
@@ -253,23 +105,15 @@ func WithMinInterval(d time.Duration) ClientOption {
 	}
 }
 
-// WithCircuitBreaker configures the circuit breaker for API calls (Bug #48)
-func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
-	return func(c *Client) {
-		c.circuitBreaker = newCircuitBreaker(config)
-	}
-}
-
 // NewClient creates a new Vast.ai client
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
-		apiKey:         apiKey,
-		baseURL:        defaultBaseURL,
-		httpClient:     &http.Client{Timeout: defaultTimeout},
-		limiter:        rate.NewLimiter(rate.Limit(1), 2),                // 1 req/s, burst 2 (Vast.ai 429 threshold is ~2 req/s)
-		circuitBreaker: newCircuitBreaker(DefaultCircuitBreakerConfig()), // Bug #48
-		templates:      &templateCache{},
-		bundles:        &bundleCache{bundles: make(map[int]Bundle)},
+		apiKey:     apiKey,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		limiter:    rate.NewLimiter(rate.Limit(1), 2), // 1 req/s, burst 2 (Vast.ai 429 threshold is ~2 req/s)
+		templates:  &templateCache{},
+		bundles:    &bundleCache{bundles: make(map[int]Bundle)},
 	}
 
 	for _, opt := range opts {
@@ -293,6 +137,12 @@ func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
 		return true // Vast.ai has spot/interruptible pricing
 	case provider.FeatureCustomImages:
 		return true // Vast.ai supports custom Docker images
+	case provider.FeatureIdleDetection:
+		return true // Vast.ai reports GPU utilization per instance
+	case provider.FeatureSSHKeyRegistration:
+		return true // Keys are attached to the instance via the API
+	case provider.FeatureOfferQueryFilters:
+		return true // GPU, location and bid filters are part of the search query
 	default:
 		return false
 	}
@@ -300,20 +150,6 @@ func (c *Client) SupportsFeature(feature provider.ProviderFeature) bool {
 
 // ListOffers returns available GPU offers from Vast.ai
 func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (offers []models.GPUOffer, err error) {
-	startTime := time.Now()
-
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("ListOffers", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListOffers", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -321,9 +157,14 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 	// Build query - Vast.ai uses JSON query syntax
 	// Use type=on-demand to ensure we only get fixed-price offers that can't be interrupted.
 	// Without this, we may get interruptible-eligible hosts that are unreliable.
+	// Bid mode (filter.Interruptible) asks for the interruptible market instead.
+	offerType := "on-demand"
+	if filter.Interruptible {
+		offerType = "bid"
+	}
 	query := map[string]interface{}{
 		"rentable": map[string]bool{"eq": true},
-		"type":     "on-demand",
+		"type":     offerType,
 	}
 
 	if filter.GPUType != "" {
@@ -332,7 +173,8 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 	if filter.MinVRAM > 0 {
 		query["gpu_ram"] = map[string]int{"gte": filter.MinVRAM * 1024} // Convert GB to MB
 	}
-	if filter.MaxPrice > 0 {
+	// Bid offers are priced at min_bid, so MaxPrice is applied after conversion
+	if filter.MaxPrice > 0 && !filter.Interruptible {
 		query["dph_total"] = map[string]float64{"lte": filter.MaxPrice}
 	}
 	// Enforce reliability floor: use filter value if set, otherwise default to 0.95
@@ -345,6 +187,12 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 	if filter.MinCUDAVersion > 0 {
 		query["cuda_max_good"] = map[string]float64{"gte": filter.MinCUDAVersion}
 	}
+	if filter.MinInetDownMbps > 0 {
+		query["inet_down"] = map[string]float64{"gte": filter.MinInetDownMbps}
+	}
+	if filter.MinDiskBandwidthMBps > 0 {
+		query["disk_bw"] = map[string]float64{"gte": filter.MinDiskBandwidthMBps}
+	}
 	if filter.Location != "" {
 		// Location filter uses Vast.ai's geolocation "in" syntax with country codes (e.g., "US")
 		query["geolocation"] = map[string][]string{"in": {filter.Location}}
@@ -394,6 +242,9 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 	offers = make([]models.GPUOffer, 0, len(result.Offers))
 	for _, bundle := range result.Offers {
 		offer := bundle.ToGPUOffer()
+		if filter.Interruptible {
+			offer = bundle.ToBidOffer()
+		}
 		if offer.MatchesFilter(filter) {
 			offers = append(offers, offer)
 		}
@@ -404,20 +255,6 @@ func (c *Client) ListOffers(ctx context.Context, filter models.OfferFilter) (off
 
 // ListAllInstances returns all instances with our tags (for reconciliation)
 func (c *Client) ListAllInstances(ctx context.Context) (instances []provider.ProviderInstance, err error) {
-	startTime := time.Now()
-
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("ListAllInstances", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListAllInstances", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -460,6 +297,7 @@ func (c *Client) ListAllInstances(ctx context.Context) (instances []provider.Pro
 					ShopperSessionID: sessionID,
 				},
 				PricePerHour: inst.DphTotal,
+				Preempted:    inst.Preempted(),
 			})
 		}
 	}
@@ -469,20 +307,6 @@ func (c *Client) ListAllInstances(ctx context.Context) (instances []provider.Pro
 
 // CreateInstance provisions a new GPU instance
 func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstanceRequest) (info *provider.InstanceInfo, err error) {
-	startTime := time.Now()
-
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("CreateInstance", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("CreateInstance", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -491,13 +315,8 @@ func (c *Client) CreateInstance(ctx context.Context, req provider.CreateInstance
 	createReq := c.buildCreateRequest(req)
 
 	// Parse offer ID as bundle ID
-	// Offer IDs are in format "vastai-{id}" or just "{id}"
 	// Bug #60: Make prefix check case-insensitive and trim whitespace
-	offerID := strings.TrimSpace(req.OfferID)
-	if strings.HasPrefix(strings.ToLower(offerID), "vastai-") {
-		offerID = offerID[7:] // Remove "vastai-" prefix (7 chars)
-	}
-	bundleID, err := strconv.Atoi(offerID)
+	bundleID, err := c.parseOfferID(req.OfferID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid offer ID: %w", err)
 	}
@@ -598,9 +417,12 @@ func (c *Client) buildCreateRequest(req provider.CreateInstanceRequest) CreateIn
 		ClientID:  "me",
 		DiskSpace: diskSpace,
 		Label:     req.Tags.ToLabel(),
-		// NOTE: Do NOT set Price here. Omitting price creates an on-demand instance
+		// NOTE: Only set Price for bids. Omitting price creates an on-demand instance
 		// that cannot be interrupted. Sending price creates a spot/bid instance that
-		// Vast.ai can reclaim at any time. On-demand is what we want for production.
+		// Vast.ai can reclaim at any time, so it is only sent when BidPrice is set.
+	}
+	if req.BidPrice > 0 {
+		createReq.Price = req.BidPrice
 	}
 
 	// Template-based provisioning: if template_hash_id is provided, use it
@@ -705,6 +527,12 @@ func (c *Client) buildEntrypointRequest(createReq CreateInstanceRequest, req pro
 			if req.WorkloadConfig.Quantization != "" {
 				createReq.Env["QUANTIZE"] = req.WorkloadConfig.Quantization
 			}
+		case provider.WorkloadTypeSGLang:
+			createReq.OnStart = BuildSGLangOnStart(req.WorkloadConfig)
+			log.Printf("[Vast.ai] SGLang config: model=%s", req.WorkloadConfig.ModelID)
+		case provider.WorkloadTypeLlamaCpp:
+			createReq.OnStart = BuildLlamaCppOnStart(req.WorkloadConfig)
+			log.Printf("[Vast.ai] llama.cpp config: model=%s", req.WorkloadConfig.ModelID)
 		}
 	}
 
@@ -733,16 +561,8 @@ func (c *Client) buildEntrypointRequest(createReq CreateInstanceRequest, req pro
 func (c *Client) AttachSSHKey(ctx context.Context, instanceID string, sshPublicKey string) (err error) {
 	startTime := time.Now()
 
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("AttachSSHKey", startTime, err)
-		return err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
 	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("AttachSSHKey", startTime, err)
+		middleware.ObserveCall("vastai", "AttachSSHKey", startTime, err)
 	}()
 
 	if err := c.rateLimit(ctx); err != nil {
@@ -780,20 +600,6 @@ func (c *Client) AttachSSHKey(ctx context.Context, instanceID string, sshPublicK
 
 // DestroyInstance tears down a GPU instance
 func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err error) {
-	startTime := time.Now()
-
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("DestroyInstance", startTime, err)
-		return err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
-	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("DestroyInstance", startTime, err)
-	}()
-
 	if err := c.rateLimit(ctx); err != nil {
 		return fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -820,22 +626,245 @@ func (c *Client) DestroyInstance(ctx context.Context, instanceID string) (err er
 	return nil
 }
 
+// StopInstance stops an instance, keeping its disk. Vast.ai stops billing
+// its GPUs but keeps billing its storage, and may rent the GPUs to someone
+// else meanwhile, in which case starting it again waits until they are free.
+func (c *Client) StopInstance(ctx context.Context, instanceID string) (err error) {
+	startTime := time.Now()
+
+	defer func() {
+		middleware.ObserveCall("vastai", "StopInstance", startTime, err)
+	}()
+
+	return c.setInstanceState(ctx, instanceID, "stopped", "StopInstance")
+}
+
+// StartInstance starts an instance stopped by StopInstance
+func (c *Client) StartInstance(ctx context.Context, instanceID string) (err error) {
+	startTime := time.Now()
+
+	defer func() {
+		middleware.ObserveCall("vastai", "StartInstance", startTime, err)
+	}()
+
+	return c.setInstanceState(ctx, instanceID, "running", "StartInstance")
+}
+
+// RebootInstance restarts an instance's container, keeping its disk and
+// its GPUs. Its SSH port may change.
+func (c *Client) RebootInstance(ctx context.Context, instanceID string) (err error) {
+	startTime := time.Now()
+
+	defer func() {
+		middleware.ObserveCall("vastai", "RebootInstance", startTime, err)
+	}()
+
+	return c.putInstance(ctx, fmt.Sprintf("/instances/reboot/%s/", instanceID), nil, "RebootInstance")
+}
+
+// setInstanceState asks Vast.ai to move an instance to the given state
+func (c *Client) setInstanceState(ctx context.Context, instanceID, state, operation string) error {
+	return c.putInstance(ctx, fmt.Sprintf("/instances/%s/", instanceID), map[string]string{"state": state}, operation)
+}
+
+// putInstance sends an instance action to Vast.ai, with payload as its JSON
+// body if not nil
+func (c *Client) putInstance(ctx context.Context, path string, payload any, operation string) error {
+	if err := c.rateLimit(ctx); err != nil {
+		return fmt.Errorf("rate limit wait: %w", err)
+	}
+
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := c.doWithRetry(httpReq, body)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.handleError(resp, operation)
+	}
+
+	return nil
+}
+
 // GetInstanceStatus returns current status of an instance
 func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (status *provider.InstanceStatus, err error) {
+	result, err := c.getInstance(ctx, instanceID, "GetInstanceStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	preempted := result.Preempted()
+	status = &provider.InstanceStatus{
+		Status:    result.ActualStatus,
+		Running:   result.ActualStatus == "running" && !preempted,
+		StartedAt: time.Unix(int64(result.StartDate), 0),
+		SSHHost:   result.SSHHost,
+		SSHPort:   result.SSHPort,
+		SSHUser:   "root",
+		// Port mappings for HTTP API access (vLLM, TGI, etc.)
+		PublicIP:   result.PublicIP,
+		Ports:      result.ParsePortMappings(),
+		Preempted:  preempted,
+		GPUUtilPct: result.GPUUtil,
+	}
+	if preempted {
+		status.Error = "outbid"
+		if result.StatusMsg != "" {
+			status.Error = result.StatusMsg
+		}
+	}
+	return status, nil
+}
+
+// GetInstanceCharges returns the storage and bandwidth charges Vast.ai reports
+// for an instance. Vast.ai does not bill public IPs separately.
+func (c *Client) GetInstanceCharges(ctx context.Context, instanceID string) (charges *provider.InstanceCharges, err error) {
 	startTime := time.Now()
 
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("GetInstanceStatus", startTime, err)
+	defer func() {
+		middleware.ObserveCall("vastai", "GetInstanceCharges", startTime, err)
+	}()
+
+	inst, err := c.getInstance(ctx, instanceID, "GetInstanceCharges")
+	if err != nil {
 		return nil, err
 	}
 
-	// Record result to circuit breaker and metrics when function returns
+	return inst.Charges(), nil
+}
+
+// GetInstanceUsage returns what Vast.ai has billed an instance so far
+func (c *Client) GetInstanceUsage(ctx context.Context, instanceID string) (usage *provider.InstanceUsage, err error) {
+	startTime := time.Now()
+
+	defer func() {
+		middleware.ObserveCall("vastai", "GetInstanceUsage", startTime, err)
+	}()
+
+	inst, err := c.getInstance(ctx, instanceID, "GetInstanceUsage")
+	if err != nil {
+		return nil, err
+	}
+
+	return inst.Usage(time.Now()), nil
+}
+
+// Vast.ai writes requested logs to a file that appears at the returned URL
+// after a short delay
+const (
+	logsPollAttempts = 10
+	logsPollInterval = 1 * time.Second
+)
+
+// GetInstanceLogs returns the last tail lines of an instance's container
+// output. Vast.ai uploads the logs on request and returns a URL to fetch them
+// from, which is polled until the upload completes.
+func (c *Client) GetInstanceLogs(ctx context.Context, instanceID string, tail int) (logs string, err error) {
+	startTime := time.Now()
+
 	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("GetInstanceStatus", startTime, err)
+		middleware.ObserveCall("vastai", "GetInstanceLogs", startTime, err)
 	}()
 
+	if err := c.rateLimit(ctx); err != nil {
+		return "", fmt.Errorf("rate limit wait: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/instances/request_logs/%s/", c.baseURL, instanceID)
+
+	body, err := json.Marshal(map[string]string{"tail": strconv.Itoa(tail)})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "PUT", reqURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := c.doWithRetry(httpReq, body)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", c.handleError(resp, "GetInstanceLogs")
+	}
+
+	var result struct {
+		Success   bool   `json:"success"`
+		ResultURL string `json:"result_url"`
+		Msg       string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.Success || result.ResultURL == "" {
+		return "", fmt.Errorf("%w: logs not available: %s", provider.ErrInvalidResponse, result.Msg)
+	}
+
+	return c.fetchLogs(ctx, result.ResultURL)
+}
+
+// fetchLogs polls a log upload URL until the file is available
+func (c *Client) fetchLogs(ctx context.Context, resultURL string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", resultURL, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// The URL is pre-signed, so the API key is not sent
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("request failed: %w", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read logs: %w", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			return string(data), nil
+		}
+		if attempt == logsPollAttempts {
+			return "", fmt.Errorf("%w: logs not uploaded after %d attempts (status %d)",
+				provider.ErrProviderError, attempt, resp.StatusCode)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(logsPollInterval):
+		}
+	}
+}
+
+// getInstance fetches a single instance. operation names the caller for error messages.
+func (c *Client) getInstance(ctx context.Context, instanceID, operation string) (*Instance, error) {
 	if err := c.rateLimit(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait: %w", err)
 	}
@@ -861,7 +890,7 @@ func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (stat
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleError(resp, "GetInstanceStatus")
+		return nil, c.handleError(resp, operation)
 	}
 
 	// The individual instance endpoint wraps the response in {"instances": {...}}
@@ -877,18 +906,7 @@ func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (stat
 		return nil, provider.ErrInstanceNotFound
 	}
 
-	result := *wrapper.Instances
-	return &provider.InstanceStatus{
-		Status:    result.ActualStatus,
-		Running:   result.ActualStatus == "running",
-		StartedAt: time.Unix(int64(result.StartDate), 0),
-		SSHHost:   result.SSHHost,
-		SSHPort:   result.SSHPort,
-		SSHUser:   "root",
-		// Port mappings for HTTP API access (vLLM, TGI, etc.)
-		PublicIP: result.PublicIP,
-		Ports:    result.ParsePortMappings(),
-	}, nil
+	return wrapper.Instances, nil
 }
 
 // ListTemplates returns available templates from Vast.ai
@@ -898,16 +916,8 @@ func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (stat
 func (c *Client) ListTemplates(ctx context.Context, filter models.TemplateFilter) (templates []models.VastTemplate, err error) {
 	startTime := time.Now()
 
-	// Bug #48: Check circuit breaker before making request
-	if err := c.checkCircuitBreaker(); err != nil {
-		c.recordAPIMetrics("ListTemplates", startTime, err)
-		return nil, err
-	}
-
-	// Record result to circuit breaker and metrics when function returns
 	defer func() {
-		c.recordAPIResult(err)
-		c.recordAPIMetrics("ListTemplates", startTime, err)
+		middleware.ObserveCall("vastai", "ListTemplates", startTime, err)
 	}()
 
 	// Check cache first
@@ -1124,69 +1134,6 @@ func (c *Client) handleError(resp *http.Response, operation string) error {
 	return provider.NewProviderError("vastai", operation, resp.StatusCode, message, baseErr)
 }
 
-// Bug #48: Circuit breaker helper methods
-
-// checkCircuitBreaker returns an error if the circuit breaker is open
-func (c *Client) checkCircuitBreaker() error {
-	if !c.circuitBreaker.allow() {
-		backoff := c.circuitBreaker.getBackoff()
-		log.Printf("[Vast.ai] Circuit breaker is open, backoff: %v", backoff)
-		return fmt.Errorf("%w: retry after %v", ErrCircuitOpen, backoff)
-	}
-	return nil
-}
-
-// recordAPIResult records the result of an API call to the circuit breaker
-func (c *Client) recordAPIResult(err error) {
-	if err == nil {
-		c.circuitBreaker.recordSuccess()
-		return
-	}
-
-	// Only count certain errors as failures for the circuit breaker
-	// Don't count validation errors, not found errors, etc.
-	var providerErr *provider.ProviderError
-	if errors.As(err, &providerErr) {
-		// Rate limits and server errors should trigger circuit breaker
-		if providerErr.StatusCode >= 500 || providerErr.StatusCode == 429 {
-			c.circuitBreaker.recordFailure()
-			return
-		}
-	}
-
-	// Network errors should trigger circuit breaker
-	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-		// Don't trigger for context cancellation by caller
-		return
-	}
-
-	// Other network-level errors
-	if strings.Contains(err.Error(), "connection refused") ||
-		strings.Contains(err.Error(), "no such host") ||
-		strings.Contains(err.Error(), "network is unreachable") {
-		c.circuitBreaker.recordFailure()
-	}
-}
-
-// recordAPIMetrics records API call metrics including response time and call count
-func (c *Client) recordAPIMetrics(operation string, startTime time.Time, err error) {
-	duration := time.Since(startTime)
-	metrics.RecordProviderAPIResponseTime("vastai", operation, duration)
-
-	status := "success"
-	if err != nil {
-		if errors.Is(err, ErrCircuitOpen) {
-			status = "circuit_open"
-		} else {
-			status = "error"
-		}
-	}
-	metrics.RecordProviderAPICall("vastai", operation, status)
-
-	// Update circuit breaker state metric
-	metrics.UpdateProviderCircuitBreakerState("vastai", int(c.circuitBreaker.State()))
-}
-
 // GetCompatibleTemplates returns templates compatible with the given offer ID.
 // Compatibility is determined by matching template extra_filters against host properties.
 func (c *Client) GetCompatibleTemplates(ctx context.Context, offerID string) ([]models.CompatibleTemplate, error) {
@@ -1251,11 +1198,14 @@ func (c *Client) GetCompatibleTemplates(ctx context.Context, offerID string) ([]
 }
 
 // parseOfferID extracts the bundle ID from an offer ID string
-// Offer IDs are in format "vastai-{id}" or just "{id}"
+// Offer IDs are in format "vastai-{id}", "vastai-bid-{id}" or just "{id}"
 func (c *Client) parseOfferID(offerID string) (int, error) {
 	offerID = strings.TrimSpace(offerID)
 	if strings.HasPrefix(strings.ToLower(offerID), "vastai-") {
 		offerID = offerID[7:] // Remove "vastai-" prefix (7 chars)
 	}
+	if strings.HasPrefix(strings.ToLower(offerID), "bid-") {
+		offerID = offerID[4:] // Remove "bid-" prefix (4 chars)
+	}
 	return strconv.Atoi(offerID)
 }
@@ -32,7 +32,9 @@ func TestClient_SupportsFeature(t *testing.T) {
 		{provider.FeatureInstanceTags, true},
 		{provider.FeatureSpotPricing, true},
 		{provider.FeatureCustomImages, true},
-		{provider.FeatureIdleDetection, false},
+		{provider.FeatureIdleDetection, true},
+		{provider.FeatureSSHKeyRegistration, true},
+		{provider.FeatureOfferQueryFilters, true},
 	}
 
 	for _, tt := range tests {
@@ -267,6 +269,104 @@ func TestClient_AttachSSHKey(t *testing.T) {
 	assert.Equal(t, "ssh-rsa AAAAB3NzaC1yc2E... test@host", capturedSSHKey)
 }
 
+func TestClient_GetInstanceLogs(t *testing.T) {
+	var logFetches int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/logs/") {
+			// The upload lands after the first poll
+			assert.Empty(t, r.Header.Get("Authorization"), "the API key is not sent to the log URL")
+			logFetches++
+			if logFetches == 1 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("loading model\nCUDA out of memory\n"))
+			return
+		}
+
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/instances/request_logs/12345/", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "50", req["tail"])
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":    true,
+			"result_url": server.URL + "/logs/12345.log",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	logs, err := client.GetInstanceLogs(context.Background(), "12345", 50)
+	require.NoError(t, err)
+	assert.Equal(t, "loading model\nCUDA out of memory\n", logs)
+	assert.Equal(t, 2, logFetches)
+}
+
+func TestClient_GetInstanceLogs_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.GetInstanceLogs(context.Background(), "12345", 50)
+	assert.ErrorIs(t, err, provider.ErrInstanceNotFound)
+}
+
+func TestClient_StopAndStartInstance(t *testing.T) {
+	var states []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/instances/12345/", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		states = append(states, req["state"])
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, client.StopInstance(context.Background(), "12345"))
+	require.NoError(t, client.StartInstance(context.Background(), "12345"))
+	assert.Equal(t, []string{"stopped", "running"}, states)
+
+	var _ provider.PowerController = client
+}
+
+func TestClient_StopInstance_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	err := client.StopInstance(context.Background(), "12345")
+	assert.ErrorIs(t, err, provider.ErrInstanceNotFound)
+}
+
+func TestClient_RebootInstance(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/instances/reboot/12345/", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, client.RebootInstance(context.Background(), "12345"))
+	assert.True(t, called)
+
+	var _ provider.RebootProvider = client
+}
+
 // TestClient_CreateInstance_CallsAttachSSHKey verifies that CreateInstance
 // calls AttachSSHKey after the instance is created.
 // LEARNING: SSH key attachment is a two-step process:
@@ -368,15 +468,20 @@ func TestClient_CreateInstance_AttachSSHKeyFailureNonFatal(t *testing.T) {
 
 func TestBundle_ToGPUOffer(t *testing.T) {
 	bundle := Bundle{
-		ID:          12345,
-		GPUName:     "GeForce RTX 4090",
-		GPURam:      24576,
-		NumGPUs:     2,
-		DphTotal:    0.90,
-		Geolocation: "California, US",
-		Reliability: 0.95,
-		Rentable:    true,
-		Rented:      false,
+		ID:            12345,
+		GPUName:       "GeForce RTX 4090",
+		GPURam:        24576,
+		NumGPUs:       2,
+		DphTotal:      0.90,
+		Geolocation:   "California, US",
+		Reliability:   0.95,
+		Rentable:      true,
+		Rented:        false,
+		CudaMaxGood:   12.4,
+		DriverVersion: "550.54.14",
+		InetDown:      940.2,
+		InetUp:        512.7,
+		DiskBw:        2150,
 	}
 
 	offer := bundle.ToGPUOffer()
@@ -389,6 +494,151 @@ func TestBundle_ToGPUOffer(t *testing.T) {
 	assert.Equal(t, 24, offer.VRAM) // Converted from MB to GB
 	assert.Equal(t, 0.90, offer.PricePerHour)
 	assert.True(t, offer.Available)
+	assert.Equal(t, 12.4, offer.CUDAVersion)
+	assert.Equal(t, "550.54.14", offer.DriverVersion)
+	assert.Equal(t, 940.2, offer.InetDownMbps)
+	assert.Equal(t, 512.7, offer.InetUpMbps)
+	assert.Equal(t, 2150.0, offer.DiskBandwidthMBps)
+	assert.False(t, offer.BandwidthEstimated)
+}
+
+func TestBundle_ToGPUOffer_OnDemandIgnoresMinBid(t *testing.T) {
+	bundle := Bundle{ID: 12345, GPUName: "RTX 4090", NumGPUs: 1, DphTotal: 0.50, MinBid: 0.20, Reliability: 0.95, Rentable: true}
+
+	offer := bundle.ToGPUOffer()
+
+	assert.False(t, offer.Interruptible)
+	assert.Zero(t, offer.MinBid)
+	assert.Equal(t, 0.50, offer.PricePerHour)
+	assert.InDelta(t, 0.6*0.95+0.4, offer.AvailabilityConfidence, 1e-9)
+}
+
+func TestBundle_ToBidOffer(t *testing.T) {
+	bundle := Bundle{ID: 12345, GPUName: "RTX 4090", NumGPUs: 1, DphTotal: 0.50, MinBid: 0.20, Reliability: 0.95, Rentable: true}
+
+	offer := bundle.ToBidOffer()
+
+	assert.Equal(t, "vastai-bid-12345", offer.ID)
+	assert.Equal(t, "12345", offer.ProviderID)
+	assert.True(t, offer.Interruptible)
+	assert.Equal(t, 0.20, offer.MinBid)
+	assert.Equal(t, 0.20, offer.PricePerHour)
+	assert.InDelta(t, 0.6*0.95+0.4*0.4, offer.AvailabilityConfidence, 1e-9)
+
+	// Bid offer IDs resolve to the same ask
+	c := NewClient("test-key")
+	id, err := c.parseOfferID(offer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 12345, id)
+}
+
+func TestClient_ListOffers_Interruptible(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var q map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(r.URL.Query().Get("q")), &q))
+		assert.Equal(t, "bid", q["type"])
+		assert.NotContains(t, q, "dph_total") // bid offers are priced at min_bid
+
+		json.NewEncoder(w).Encode(BundlesResponse{Offers: []Bundle{
+			{ID: 1, GPUName: "RTX 4090", GPURam: 24576, NumGPUs: 1, DphTotal: 0.50, MinBid: 0.15, Reliability: 0.97, Rentable: true},
+			{ID: 2, GPUName: "RTX 4090", GPURam: 24576, NumGPUs: 1, DphTotal: 0.50, MinBid: 0.40, Reliability: 0.97, Rentable: true},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	offers, err := client.ListOffers(context.Background(), models.OfferFilter{Interruptible: true, MaxPrice: 0.30})
+
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, "vastai-bid-1", offers[0].ID)
+	assert.True(t, offers[0].Interruptible)
+}
+
+func TestClient_CreateInstance_SendsBidPrice(t *testing.T) {
+	var captured map[string]interface{}
+	var askPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			askPath = r.URL.Path
+			captured = nil
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+			json.NewEncoder(w).Encode(CreateInstanceResponse{Success: true, NewContract: 67890})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := client.CreateInstance(context.Background(), provider.CreateInstanceRequest{
+		OfferID:   "vastai-bid-12345",
+		SessionID: "sess-001",
+		BidPrice:  0.25,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/asks/12345/", askPath)
+	assert.Equal(t, 0.25, captured["price"])
+
+	// On-demand requests never carry a price
+	_, err = client.CreateInstance(context.Background(), provider.CreateInstanceRequest{
+		OfferID:   "vastai-12345",
+		SessionID: "sess-002",
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, captured, "price")
+}
+
+func TestClient_GetInstanceStatus_Preempted(t *testing.T) {
+	tests := []struct {
+		name      string
+		instance  map[string]interface{}
+		preempted bool
+	}{
+		{
+			name:      "outbid bid instance",
+			instance:  map[string]interface{}{"id": 1, "is_bid": true, "intended_status": "running", "actual_status": "exited", "status_msg": "Instance outbid"},
+			preempted: true,
+		},
+		{
+			name:      "stopped bid instance still intended running",
+			instance:  map[string]interface{}{"id": 1, "is_bid": true, "intended_status": "running", "actual_status": "stopped"},
+			preempted: true,
+		},
+		{
+			name:     "running bid instance",
+			instance: map[string]interface{}{"id": 1, "is_bid": true, "intended_status": "running", "actual_status": "running"},
+		},
+		{
+			name:     "on-demand instance stopped",
+			instance: map[string]interface{}{"id": 1, "intended_status": "running", "actual_status": "exited"},
+		},
+		{
+			name:     "bid instance stopped by us",
+			instance: map[string]interface{}{"id": 1, "is_bid": true, "intended_status": "stopped", "actual_status": "stopped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]interface{}{"instances": tt.instance})
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", WithBaseURL(server.URL), WithMinInterval(0))
+			status, err := client.GetInstanceStatus(context.Background(), "1")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.preempted, status.Preempted)
+			if tt.preempted {
+				assert.False(t, status.Running)
+				assert.NotEmpty(t, status.Error)
+			}
+		})
+	}
 }
 
 func TestNormalizeGPUName(t *testing.T) {
@@ -543,9 +793,42 @@ func TestBuildTGIArgs(t *testing.T) {
 	}
 }
 
+func TestBuildSGLangOnStart(t *testing.T) {
+	assert.Empty(t, BuildSGLangOnStart(nil))
+
+	cmd := BuildSGLangOnStart(&provider.WorkloadConfig{
+		Type:           provider.WorkloadTypeSGLang,
+		ModelID:        "Qwen/Qwen2.5-7B-Instruct-AWQ",
+		Quantization:   "awq",
+		TensorParallel: 2,
+	})
+	assert.Contains(t, cmd, "python3 -m sglang.launch_server --model-path 'Qwen/Qwen2.5-7B-Instruct-AWQ' --host 0.0.0.0 --port 30000")
+	assert.Contains(t, cmd, "--quantization 'awq'")
+	assert.Contains(t, cmd, "--tp-size 2")
+	assert.True(t, strings.HasSuffix(cmd, "&"), "runs in the background")
+}
+
+func TestBuildLlamaCppOnStart(t *testing.T) {
+	assert.Empty(t, BuildLlamaCppOnStart(&provider.WorkloadConfig{}))
+
+	cmd := BuildLlamaCppOnStart(&provider.WorkloadConfig{
+		Type:         provider.WorkloadTypeLlamaCpp,
+		ModelID:      "bartowski/Llama-3.2-3B-Instruct-GGUF",
+		Quantization: "Q4_K_M",
+		MaxModelLen:  8192,
+	})
+	assert.Contains(t, cmd, "/app/llama-server -hf 'bartowski/Llama-3.2-3B-Instruct-GGUF:Q4_K_M' --host 0.0.0.0 --port 8080 -ngl 999")
+	assert.Contains(t, cmd, "-c 8192")
+
+	cmd = BuildLlamaCppOnStart(&provider.WorkloadConfig{ModelID: "it's/model"})
+	assert.Contains(t, cmd, `-hf 'it'\''s/model'`)
+}
+
 func TestGetImageForWorkload(t *testing.T) {
 	assert.Equal(t, ImageVLLM, GetImageForWorkload(provider.WorkloadTypeVLLM))
 	assert.Equal(t, ImageTGI, GetImageForWorkload(provider.WorkloadTypeTGI))
+	assert.Equal(t, ImageSGLang, GetImageForWorkload(provider.WorkloadTypeSGLang))
+	assert.Equal(t, ImageLlamaCpp, GetImageForWorkload(provider.WorkloadTypeLlamaCpp))
 	assert.Equal(t, ImageSSHBase, GetImageForWorkload(provider.WorkloadTypeCustom))
 	assert.Equal(t, ImageSSHBase, GetImageForWorkload("unknown"))
 }
@@ -553,6 +836,8 @@ func TestGetImageForWorkload(t *testing.T) {
 func TestGetPortForWorkload(t *testing.T) {
 	assert.Equal(t, DefaultVLLMPort, GetPortForWorkload(provider.WorkloadTypeVLLM))
 	assert.Equal(t, DefaultTGIPort, GetPortForWorkload(provider.WorkloadTypeTGI))
+	assert.Equal(t, DefaultSGLangPort, GetPortForWorkload(provider.WorkloadTypeSGLang))
+	assert.Equal(t, DefaultLlamaCppPort, GetPortForWorkload(provider.WorkloadTypeLlamaCpp))
 	assert.Equal(t, 0, GetPortForWorkload(provider.WorkloadTypeCustom))
 }
 
@@ -644,6 +929,7 @@ func TestClient_GetInstanceStatus_WithPortMappings(t *testing.T) {
 				"ssh_port":      20544,
 				"public_ipaddr": "65.130.162.74",
 				"start_date":    1706745600.0,
+				"gpu_util":      3.5,
 				"ports": map[string][]map[string]string{
 					"8000/tcp": {{"HostIp": "0.0.0.0", "HostPort": "33526"}},
 					"8080/tcp": {{"HostIp": "0.0.0.0", "HostPort": "44100"}},
@@ -670,6 +956,9 @@ func TestClient_GetInstanceStatus_WithPortMappings(t *testing.T) {
 	require.NotNil(t, status.Ports)
 	assert.Equal(t, 33526, status.Ports[8000])
 	assert.Equal(t, 44100, status.Ports[8080])
+
+	require.NotNil(t, status.GPUUtilPct)
+	assert.Equal(t, 3.5, *status.GPUUtilPct)
 }
 
 // Template-related tests
@@ -1112,3 +1401,95 @@ func TestClient_GetAccountBalance_Error(t *testing.T) {
 	assert.Nil(t, balance)
 	assert.Contains(t, err.Error(), "balance check failed: status 401")
 }
+
+func TestClient_GetInstanceCharges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Contains(t, r.URL.Path, "/instances/12345/")
+
+		response := map[string]interface{}{
+			"instances": map[string]interface{}{
+				"id":                 12345,
+				"actual_status":      "running",
+				"disk_space":         50.0,
+				"storage_total_cost": 0.0137,
+				"inet_up_cost":       0.02,
+				"inet_down_cost":     0.01,
+				"inet_up_billed":     5.0,
+				"inet_down_billed":   20.0,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	charges, err := client.GetInstanceCharges(context.Background(), "12345")
+	require.NoError(t, err)
+	assert.Zero(t, charges.StoragePerHour, "dph_total already includes storage")
+	assert.InDelta(t, 0.30, charges.BandwidthTotal, 0.00001) // 5GB*$0.02 + 20GB*$0.01
+	assert.Zero(t, charges.IPPerHour)
+}
+
+func TestClient_GetInstanceCharges_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"instances": null}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := client.GetInstanceCharges(context.Background(), "99999")
+	assert.ErrorIs(t, err, provider.ErrInstanceNotFound)
+}
+
+func TestClient_GetInstanceUsage(t *testing.T) {
+	started := time.Now().Add(-2 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Contains(t, r.URL.Path, "/instances/12345/")
+
+		response := map[string]interface{}{
+			"instances": map[string]interface{}{
+				"id":               12345,
+				"actual_status":    "running",
+				"dph_total":        0.40,
+				"start_date":       float64(started.Unix()),
+				"inet_up_cost":     0.02,
+				"inet_down_cost":   0.01,
+				"inet_up_billed":   5.0,
+				"inet_down_billed": 20.0,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	usage, err := client.GetInstanceUsage(context.Background(), "12345")
+	require.NoError(t, err)
+	assert.Equal(t, started.Unix(), usage.StartedAt.Unix())
+	assert.Equal(t, 0.40, usage.HourlyRate)
+	assert.InDelta(t, 1.10, usage.Total, 0.001) // 2h*$0.40 + $0.30 bandwidth
+}
+
+func TestInstance_Usage_NotStarted(t *testing.T) {
+	inst := &Instance{DphTotal: 0.40}
+
+	usage := inst.Usage(time.Now())
+	assert.True(t, usage.StartedAt.IsZero())
+	assert.Zero(t, usage.Total)
+}
+
+func TestInstance_Charges_NoStorage(t *testing.T) {
+	inst := &Instance{DiskSpace: 73, StorageCost: 0.10, StorageTotalCost: 0.01, DphTotal: 0.41}
+
+	charges := inst.Charges()
+	assert.Zero(t, charges.StoragePerHour)
+	assert.Zero(t, charges.BandwidthTotal)
+}
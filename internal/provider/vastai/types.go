@@ -24,14 +24,22 @@ const (
 	// ImageTGI is the Text Generation Inference server image
 	ImageTGI = "ghcr.io/huggingface/text-generation-inference:latest"
 
+	// ImageSGLang is the SGLang inference server image
+	ImageSGLang = "lmsysorg/sglang:latest"
+
+	// ImageLlamaCpp is the llama.cpp server image built with CUDA
+	ImageLlamaCpp = "ghcr.io/ggml-org/llama.cpp:server-cuda"
+
 	// ImageOllama is the Ollama server image
 	ImageOllama = "ollama/ollama:latest"
 )
 
 // Default ports for inference servers
 const (
-	DefaultVLLMPort = 8000
-	DefaultTGIPort  = 80
+	DefaultVLLMPort     = 8000
+	DefaultTGIPort      = 80
+	DefaultSGLangPort   = 30000
+	DefaultLlamaCppPort = 8080
 )
 
 // BundlesResponse is the response from GET /bundles/
@@ -67,11 +75,11 @@ type Bundle struct {
 	// Storage
 	DiskSpace float64 `json:"disk_space"` // GB
 	DiskName  string  `json:"disk_name"`
-	DiskBw    float64 `json:"disk_bw"`
+	DiskBw    float64 `json:"disk_bw"` // MB/s
 
 	// Network
-	InetDown     float64 `json:"inet_down"`
-	InetUp       float64 `json:"inet_up"`
+	InetDown     float64 `json:"inet_down"` // Mbps
+	InetUp       float64 `json:"inet_up"`   // Mbps
 	InetDownCost float64 `json:"inet_down_cost"`
 	InetUpCost   float64 `json:"inet_up_cost"`
 
@@ -133,24 +141,9 @@ func (b Bundle) ToHostProperties() map[string]interface{} {
 	}
 }
 
-// ToGPUOffer converts a Vast.ai Bundle to a unified GPUOffer
+// ToGPUOffer converts a Vast.ai Bundle listed as on-demand to a unified GPUOffer.
+// On-demand instances cannot be reclaimed, so min_bid is ignored.
 func (b Bundle) ToGPUOffer() models.GPUOffer {
-	interruptible := b.MinBid > 0
-
-	// Score by completion probability: reliability + bid safety.
-	// On-demand (min_bid=0) gets bidSafety=1.0; spot instances get a score
-	// based on how close min_bid is to dph_total (higher = harder to outbid = safer).
-	var bidSafety float64
-	if !interruptible {
-		bidSafety = 1.0 // on-demand, cannot be reclaimed
-	} else if b.DphTotal > 0 {
-		bidSafety = b.MinBid / b.DphTotal
-		if bidSafety > 1.0 {
-			bidSafety = 1.0
-		}
-	}
-	confidence := 0.6*b.Reliability + 0.4*bidSafety
-
 	return models.GPUOffer{
 		ID:                     fmt.Sprintf("vastai-%d", b.ID),
 		Provider:               "vastai",
@@ -164,14 +157,39 @@ func (b Bundle) ToGPUOffer() models.GPUOffer {
 		Available:              b.Rentable && !b.Rented,
 		MaxDuration:            0, // Vast.ai doesn't have max duration
 		FetchedAt:              time.Now(),
-		AvailabilityConfidence: confidence,
+		AvailabilityConfidence: 0.6*b.Reliability + 0.4, // bid safety 1.0: cannot be reclaimed
 		CUDAVersion:            b.CudaMaxGood,
+		DriverVersion:          b.DriverVersion,
+		InetDownMbps:           b.InetDown,
+		InetUpMbps:             b.InetUp,
+		DiskBandwidthMBps:      b.DiskBw,
 		MachineID:              fmt.Sprintf("vastai-machine-%d", b.MachineID),
-		Interruptible:          interruptible,
-		MinBid:                 b.MinBid,
 	}
 }
 
+// ToBidOffer converts a Vast.ai Bundle listed as interruptible to a unified GPUOffer.
+// The offer is priced at min_bid and gets its own ID so it never collides with
+// the on-demand listing of the same machine.
+func (b Bundle) ToBidOffer() models.GPUOffer {
+	offer := b.ToGPUOffer()
+	offer.ID = fmt.Sprintf("vastai-bid-%d", b.ID)
+	offer.PricePerHour = b.MinBid
+	offer.Interruptible = true
+	offer.MinBid = b.MinBid
+
+	// Score by completion probability: reliability + bid safety.
+	// Bid safety is how close min_bid is to dph_total (higher = harder to outbid).
+	var bidSafety float64
+	if b.DphTotal > 0 {
+		bidSafety = b.MinBid / b.DphTotal
+		if bidSafety > 1.0 {
+			bidSafety = 1.0
+		}
+	}
+	offer.AvailabilityConfidence = 0.6*b.Reliability + 0.4*bidSafety
+	return offer
+}
+
 // InstancesResponse is the response from GET /instances/
 type InstancesResponse struct {
 	Instances []Instance `json:"instances"`
@@ -193,6 +211,8 @@ type Instance struct {
 	ActualStatus   string `json:"actual_status"`
 	IntendedStatus string `json:"intended_status"`
 	CurState       string `json:"cur_state"`
+	StatusMsg      string `json:"status_msg"`
+	IsBid          bool   `json:"is_bid"`
 
 	// Connection info
 	SSHHost  string `json:"ssh_host"`
@@ -209,12 +229,20 @@ type Instance struct {
 	DirectPortEnd   int `json:"direct_port_end"`
 
 	// GPU info
-	GPUName string  `json:"gpu_name"`
-	NumGPUs int     `json:"num_gpus"`
-	GPURam  float64 `json:"gpu_ram"`
+	GPUName string   `json:"gpu_name"`
+	NumGPUs int      `json:"num_gpus"`
+	GPURam  float64  `json:"gpu_ram"`
+	GPUUtil *float64 `json:"gpu_util"` // Percent, averaged over the GPUs; null until the host reports it
 
 	// Pricing
-	DphTotal float64 `json:"dph_total"`
+	DphTotal         float64 `json:"dph_total"`
+	DiskSpace        float64 `json:"disk_space"`         // GB allocated
+	StorageCost      float64 `json:"storage_cost"`       // $/GB/month
+	StorageTotalCost float64 `json:"storage_total_cost"` // $/hour for allocated disk
+	InetUpCost       float64 `json:"inet_up_cost"`       // $/GB
+	InetDownCost     float64 `json:"inet_down_cost"`     // $/GB
+	InetUpBilled     float64 `json:"inet_up_billed"`     // GB billed so far
+	InetDownBilled   float64 `json:"inet_down_billed"`   // GB billed so far
 
 	// Timing
 	StartDate float64 `json:"start_date"`
@@ -249,8 +277,8 @@ type CreateInstanceRequest struct {
 	// Request params override template defaults. Env vars are merged (request wins conflicts).
 	TemplateHashID string `json:"template_hash_id,omitempty"`
 
-	// Pricing: for interruptible instances, this is the bid per GPU per hour.
-	// If omitted, Vast.ai uses the minimum bid which may be immediately outbid.
+	// Pricing: setting a price creates an interruptible instance bidding this
+	// many $/hour. Omitting it creates an on-demand instance.
 	Price float64 `json:"price,omitempty"`
 }
 
@@ -444,6 +472,67 @@ func BuildTGIArgs(config *provider.WorkloadConfig) string {
 	return strings.Join(args, " ")
 }
 
+// BuildSGLangOnStart builds the on-start command that launches the SGLang
+// server in the background. In ssh_proxy mode the image's own entrypoint
+// does not run.
+func BuildSGLangOnStart(config *provider.WorkloadConfig) string {
+	if config == nil || config.ModelID == "" {
+		return ""
+	}
+
+	args := []string{
+		"python3", "-m", "sglang.launch_server",
+		"--model-path", shellQuote(config.ModelID),
+		"--host", "0.0.0.0",
+		"--port", fmt.Sprintf("%d", DefaultSGLangPort),
+	}
+	if config.GPUMemoryUtil > 0 {
+		args = append(args, "--mem-fraction-static", fmt.Sprintf("%.2f", config.GPUMemoryUtil))
+	}
+	if config.Quantization != "" {
+		args = append(args, "--quantization", shellQuote(config.Quantization))
+	}
+	if config.MaxModelLen > 0 {
+		args = append(args, "--context-length", fmt.Sprintf("%d", config.MaxModelLen))
+	}
+	if config.TensorParallel > 1 {
+		args = append(args, "--tp-size", fmt.Sprintf("%d", config.TensorParallel))
+	}
+
+	return strings.Join(args, " ") + " > /var/log/sglang.log 2>&1 &"
+}
+
+// BuildLlamaCppOnStart builds the on-start command that launches llama.cpp's
+// server in the background. ModelID is a Hugging Face GGUF repository;
+// Quantization picks a file in it, e.g. "Q4_K_M".
+func BuildLlamaCppOnStart(config *provider.WorkloadConfig) string {
+	if config == nil || config.ModelID == "" {
+		return ""
+	}
+
+	model := config.ModelID
+	if config.Quantization != "" {
+		model += ":" + config.Quantization
+	}
+	args := []string{
+		"/app/llama-server",
+		"-hf", shellQuote(model),
+		"--host", "0.0.0.0",
+		"--port", fmt.Sprintf("%d", DefaultLlamaCppPort),
+		"-ngl", "999", // Offload every layer to the GPU
+	}
+	if config.MaxModelLen > 0 {
+		args = append(args, "-c", fmt.Sprintf("%d", config.MaxModelLen))
+	}
+
+	return strings.Join(args, " ") + " > /var/log/llama-server.log 2>&1 &"
+}
+
+// shellQuote wraps a string in single quotes for safe shell interpolation
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
 // GetImageForWorkload returns the appropriate Docker image for a workload type
 func GetImageForWorkload(workloadType provider.WorkloadType) string {
 	switch workloadType {
@@ -451,6 +540,10 @@ func GetImageForWorkload(workloadType provider.WorkloadType) string {
 		return ImageVLLM
 	case provider.WorkloadTypeTGI:
 		return ImageTGI
+	case provider.WorkloadTypeSGLang:
+		return ImageSGLang
+	case provider.WorkloadTypeLlamaCpp:
+		return ImageLlamaCpp
 	default:
 		return ImageSSHBase
 	}
@@ -463,6 +556,10 @@ func GetPortForWorkload(workloadType provider.WorkloadType) int {
 		return DefaultVLLMPort
 	case provider.WorkloadTypeTGI:
 		return DefaultTGIPort
+	case provider.WorkloadTypeSGLang:
+		return DefaultSGLangPort
+	case provider.WorkloadTypeLlamaCpp:
+		return DefaultLlamaCppPort
 	default:
 		return 0
 	}
@@ -481,6 +578,21 @@ func FormatPortsString(ports []int) string {
 	return strings.Join(portStrs, ",")
 }
 
+// Preempted reports whether an interruptible instance was reclaimed by Vast.ai.
+// Outbid instances are stopped by the host while intended_status stays "running".
+func (inst *Instance) Preempted() bool {
+	if !inst.IsBid {
+		return false
+	}
+	if strings.Contains(strings.ToLower(inst.StatusMsg), "outbid") {
+		return true
+	}
+	if inst.IntendedStatus != "running" {
+		return false
+	}
+	return inst.ActualStatus == "stopped" || inst.ActualStatus == "exited" || inst.CurState == "stopped"
+}
+
 // ParsePortMappings converts Docker-style port bindings to a simple container->external port map
 // Input format: {"8000/tcp": [{"HostIp": "0.0.0.0", "HostPort": "33526"}]}
 // Output format: map[8000]33526
@@ -513,6 +625,32 @@ func (inst *Instance) ParsePortMappings() map[int]int {
 	return result
 }
 
+// Charges converts the instance's bandwidth pricing into
+// provider.InstanceCharges. Storage is not reported: dph_total, which
+// sessions are priced at, already includes the allocated disk.
+func (inst *Instance) Charges() *provider.InstanceCharges {
+	return &provider.InstanceCharges{
+		BandwidthTotal: inst.InetUpBilled*inst.InetUpCost + inst.InetDownBilled*inst.InetDownCost,
+	}
+}
+
+// Usage converts Vast.ai's billing fields into provider.InstanceUsage as of
+// now. dph_total already includes the allocated disk, so storage is not added
+// again; bandwidth is billed per GB transferred.
+func (inst *Instance) Usage(now time.Time) *provider.InstanceUsage {
+	usage := &provider.InstanceUsage{
+		HourlyRate: inst.DphTotal,
+		Total:      inst.InetUpBilled*inst.InetUpCost + inst.InetDownBilled*inst.InetDownCost,
+	}
+	if inst.StartDate > 0 {
+		usage.StartedAt = time.Unix(int64(inst.StartDate), 0)
+		if now.After(usage.StartedAt) {
+			usage.Total += inst.DphTotal * now.Sub(usage.StartedAt).Hours()
+		}
+	}
+	return usage
+}
+
 // parsePortFromSpec extracts the port number from a Docker port spec like "8000/tcp"
 func parsePortFromSpec(spec string) int {
 	// Split on "/" to separate port from protocol
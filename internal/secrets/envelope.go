@@ -0,0 +1,146 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sealedPrefix marks a value sealed by Envelope
+const sealedPrefix = "enc:v1:"
+
+// Envelope encrypts values for storage with envelope encryption: each value
+// is sealed with its own AES-256-GCM data key, which is itself sealed with
+// the master key and stored alongside it. Sealed values have the form
+// enc:v1:<key id>:<wrapped data key>:<ciphertext>.
+type Envelope struct {
+	master cipher.AEAD
+	keyID  string
+}
+
+// NewEnvelope creates an envelope over a 32-byte master key
+func NewEnvelope(masterKey []byte) (*Envelope, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(masterKey))
+	}
+	master, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(masterKey)
+	return &Envelope{master: master, keyID: hex.EncodeToString(sum[:4])}, nil
+}
+
+// ParseMasterKey decodes a base64-encoded 32-byte master key, as produced by
+// `openssl rand -base64 32`
+func ParseMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("master key must decode to 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// IsSealed reports whether a stored value was sealed by an Envelope
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// Seal encrypts plaintext under a fresh data key
+func (e *Envelope) Seal(plaintext string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := sealWith(e.master, dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := sealWith(data, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + e.keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a sealed value. Values that were never sealed are returned
+// unchanged, so rows written before encryption was enabled stay readable.
+func (e *Envelope) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, sealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed sealed value")
+	}
+	if parts[0] != e.keyID {
+		return "", &WrongKeyError{KeyID: parts[0]}
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed sealed value: %w", err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed sealed value: %w", err)
+	}
+
+	dataKey, err := openWith(e.master, wrapped)
+	if err != nil {
+		return "", err
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := openWith(data, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealWith encrypts plaintext and returns nonce || ciphertext
+func sealWith(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openWith reverses sealWith
+func openWith(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed sealed value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt sealed value: %w", err)
+	}
+	return plaintext, nil
+}
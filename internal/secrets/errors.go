@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWrongPassphrase is returned when an encrypted secrets file cannot be
+// decrypted with the given passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted secrets file")
+
+// NotFoundError indicates a backend has no secret under a key
+type NotFoundError struct {
+	Backend string
+	Key     string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("secret %q not found in %s backend", e.Key, e.Backend)
+}
+
+// BackendNotConfiguredError indicates a reference names a backend that has
+// not been set up
+type BackendNotConfiguredError struct {
+	Backend string
+}
+
+func (e *BackendNotConfiguredError) Error() string {
+	return fmt.Sprintf("secret references the %s backend, which is not configured", e.Backend)
+}
+
+// WrongKeyError indicates a value was sealed under a different master key
+type WrongKeyError struct {
+	KeyID string
+}
+
+func (e *WrongKeyError) Error() string {
+	return fmt.Sprintf("value was sealed with another master key (key id %s)", e.KeyID)
+}
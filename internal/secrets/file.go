@@ -0,0 +1,114 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const fileFormatVersion = 1
+
+// encryptedFile is the on-disk form of a secrets file. Data is the
+// AES-256-GCM sealed JSON object of secrets, prefixed with its nonce.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Data    []byte `json:"data"`
+}
+
+// File serves secrets from a passphrase-encrypted file, decrypted once when
+// the backend is created
+type File struct {
+	secrets map[string]string
+}
+
+// NewFile decrypts the secrets file at path
+func NewFile(path, passphrase string) (*File, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	secrets, err := Decrypt(raw, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &File{secrets: secrets}, nil
+}
+
+// Name returns "file"
+func (f *File) Name() string {
+	return "file"
+}
+
+// Get returns the secret stored under key
+func (f *File) Get(ctx context.Context, key string) (string, error) {
+	value, ok := f.secrets[key]
+	if !ok {
+		return "", &NotFoundError{Backend: f.Name(), Key: key}
+	}
+	return value, nil
+}
+
+// Encrypt seals secrets with a key derived from passphrase, producing the
+// contents of a secrets file
+func Encrypt(secrets map[string]string, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase is required")
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode secrets: %w", err)
+	}
+
+	salt, err := NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := PassphraseAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return json.MarshalIndent(encryptedFile{
+		Version: fileFormatVersion,
+		Salt:    salt,
+		Data:    aead.Seal(nonce, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+// Decrypt reverses Encrypt. A wrong passphrase returns ErrWrongPassphrase.
+func Decrypt(raw []byte, passphrase string) (map[string]string, error) {
+	var file encryptedFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+	if file.Version != fileFormatVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", file.Version)
+	}
+
+	aead, err := PassphraseAEAD(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Data) < aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	nonce, ciphertext := file.Data[:aead.NonceSize()], file.Data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets: %w", err)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,63 @@
+// Package secrets resolves configuration values that name a secret in an
+// external backend instead of holding it in plaintext. A reference has the
+// form "<backend>:<key>", e.g. "vault:gpu-shopper/providers#vastai_api_key"
+// or "file:vastai_api_key"; any other value is returned unchanged.
+package secrets
+
+import (
+	"context"
+	"strings"
+)
+
+// Backend looks up secrets by key
+type Backend interface {
+	// Name is the reference prefix that selects this backend
+	Name() string
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// Resolver resolves secret references against a set of backends
+type Resolver struct {
+	backends map[string]Backend
+}
+
+// NewResolver creates a resolver over the given backends
+func NewResolver(backends ...Backend) *Resolver {
+	r := &Resolver{backends: make(map[string]Backend, len(backends))}
+	for _, b := range backends {
+		r.backends[b.Name()] = b
+	}
+	return r
+}
+
+// prefixes are the reference prefixes recognised by IsReference, whether or
+// not a backend for them is configured
+var prefixes = []string{"vault:", "file:"}
+
+// IsReference reports whether value names a secret rather than holding one
+func IsReference(value string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(value, p) && len(value) > len(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the secret a reference names, or value itself if it is not
+// a reference
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	name, key, _ := strings.Cut(value, ":")
+	backend, ok := r.backends[name]
+	if !ok {
+		return "", &BackendNotConfiguredError{Backend: name}
+	}
+	secret, err := backend.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReference(t *testing.T) {
+	assert.True(t, IsReference("vault:gpu-shopper#key"))
+	assert.True(t, IsReference("file:vastai_api_key"))
+	assert.False(t, IsReference("plain-api-key"))
+	assert.False(t, IsReference("vault:"))
+	assert.False(t, IsReference("https://hooks.example.com/x"))
+}
+
+func TestResolver(t *testing.T) {
+	raw, err := Encrypt(map[string]string{"vastai_api_key": "vast-secret"}, "passphrase")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	require.NoError(t, os.WriteFile(path, raw, 0600))
+	file, err := NewFile(path, "passphrase")
+	require.NoError(t, err)
+
+	r := NewResolver(file)
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "file:vastai_api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "vast-secret", value)
+
+	value, err = r.Resolve(ctx, "plain-value")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-value", value)
+
+	_, err = r.Resolve(ctx, "file:missing")
+	var notFound *NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = r.Resolve(ctx, "vault:gpu-shopper#key")
+	var notConfigured *BackendNotConfiguredError
+	assert.ErrorAs(t, err, &notConfigured)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	raw, err := Encrypt(map[string]string{"a": "1", "b": "2"}, "correct horse")
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), `"a"`, "secrets must not appear in plaintext")
+
+	secrets, err := Decrypt(raw, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, secrets)
+
+	_, err = Decrypt(raw, "wrong")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	_, err = Encrypt(map[string]string{"a": "1"}, "")
+	assert.Error(t, err)
+}
+
+func TestPassphraseAEAD(t *testing.T) {
+	salt, err := NewSalt()
+	require.NoError(t, err)
+	other, err := NewSalt()
+	require.NoError(t, err)
+	assert.NotEqual(t, salt, other)
+
+	aead, err := PassphraseAEAD("correct horse", salt)
+	require.NoError(t, err)
+	nonce := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nil, nonce, []byte("secret"), nil)
+
+	// The same passphrase and salt derive the same key
+	again, err := PassphraseAEAD("correct horse", salt)
+	require.NoError(t, err)
+	plaintext, err := again.Open(nil, nonce, sealed, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	for _, wrong := range []struct {
+		passphrase string
+		salt       []byte
+	}{{"wrong", salt}, {"correct horse", other}} {
+		aead, err := PassphraseAEAD(wrong.passphrase, wrong.salt)
+		require.NoError(t, err)
+		_, err = aead.Open(nil, nonce, sealed, nil)
+		assert.Error(t, err)
+	}
+}
+
+func TestVault(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		assert.Equal(t, "team-a", r.Header.Get("X-Vault-Namespace"))
+		if r.URL.Path != "/v1/kv/data/gpu-shopper/providers" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"vastai_api_key": "vast-secret", "count": 3},
+				"metadata": map[string]interface{}{"version": 2},
+			},
+		})
+	}))
+	defer server.Close()
+
+	v := NewVault(server.URL+"/", "vault-token", WithVaultMount("kv"), WithVaultNamespace("team-a"))
+	ctx := context.Background()
+
+	value, err := v.Get(ctx, "gpu-shopper/providers#vastai_api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "vast-secret", value)
+
+	// A second field at the same path is served from the cache
+	_, err = v.Get(ctx, "gpu-shopper/providers#missing")
+	var notFound *NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, 1, requests)
+
+	_, err = v.Get(ctx, "gpu-shopper/providers#count")
+	assert.Error(t, err, "non-string fields are rejected")
+
+	_, err = v.Get(ctx, "gpu-shopper/other#key")
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = v.Get(ctx, "gpu-shopper/providers")
+	assert.Error(t, err, "a field is required")
+}
+
+func TestEnvelope(t *testing.T) {
+	key, err := ParseMasterKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+	require.NoError(t, err)
+	e, err := NewEnvelope(key)
+	require.NoError(t, err)
+
+	sealed, err := e.Seal("private key")
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, sealed, "private key")
+
+	again, err := e.Seal("private key")
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "each value gets its own data key and nonce")
+
+	plaintext, err := e.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "private key", plaintext)
+
+	plaintext, err = e.Open("legacy plaintext")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy plaintext", plaintext)
+
+	other, err := NewEnvelope(make([]byte, 32))
+	require.NoError(t, err)
+	_, err = other.Open(sealed)
+	var wrongKey *WrongKeyError
+	assert.ErrorAs(t, err, &wrongKey)
+
+	_, err = e.Open(sealed[:len(sealed)-4])
+	assert.Error(t, err, "tampered values fail authentication")
+
+	_, err = NewEnvelope([]byte("short"))
+	assert.Error(t, err)
+	_, err = ParseMasterKey("c2hvcnQ=")
+	assert.Error(t, err)
+	_, err = ParseMasterKey("not base64!")
+	assert.Error(t, err)
+}
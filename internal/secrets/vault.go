@@ -0,0 +1,144 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultVaultMount is the KV version 2 secrets engine mount
+	DefaultVaultMount = "secret"
+
+	// DefaultVaultTimeout bounds a single Vault request
+	DefaultVaultTimeout = 10 * time.Second
+)
+
+// Vault reads secrets from a HashiCorp Vault KV version 2 engine. Keys have
+// the form "<path>#<field>"; each path is read once and cached.
+type Vault struct {
+	addr      string
+	token     string
+	mount     string
+	namespace string
+	client    *http.Client
+
+	mu    sync.Mutex
+	cache map[string]map[string]interface{}
+}
+
+// VaultOption configures the Vault backend
+type VaultOption func(*Vault)
+
+// WithVaultMount sets the KV engine mount (default "secret")
+func WithVaultMount(mount string) VaultOption {
+	return func(v *Vault) {
+		v.mount = strings.Trim(mount, "/")
+	}
+}
+
+// WithVaultNamespace sets the Vault Enterprise namespace
+func WithVaultNamespace(namespace string) VaultOption {
+	return func(v *Vault) {
+		v.namespace = namespace
+	}
+}
+
+// WithVaultHTTPClient sets a custom HTTP client
+func WithVaultHTTPClient(client *http.Client) VaultOption {
+	return func(v *Vault) {
+		v.client = client
+	}
+}
+
+// NewVault creates a Vault backend for the server at addr, authenticating
+// with token
+func NewVault(addr, token string, opts ...VaultOption) *Vault {
+	v := &Vault{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		mount:  DefaultVaultMount,
+		client: &http.Client{Timeout: DefaultVaultTimeout},
+		cache:  make(map[string]map[string]interface{}),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Name returns "vault"
+func (v *Vault) Name() string {
+	return "vault"
+}
+
+// Get returns the field of the secret at a path, keyed "<path>#<field>"
+func (v *Vault) Get(ctx context.Context, key string) (string, error) {
+	path, field, ok := strings.Cut(key, "#")
+	path = strings.Trim(path, "/")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("invalid vault secret reference %q: expected <path>#<field>", key)
+	}
+
+	data, err := v.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", &NotFoundError{Backend: v.Name(), Key: key}
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %q is not a string", key)
+	}
+	return s, nil
+}
+
+// read fetches the latest version of the secret at path
+func (v *Vault) read(ctx context.Context, path string) (map[string]interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if data, ok := v.cache[path]; ok {
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/data/%s", v.addr, v.mount, path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &NotFoundError{Backend: v.Name(), Key: path}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault returned %d reading %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse vault response: %w", err)
+	}
+	v.cache[path] = result.Data.Data
+	return result.Data.Data, nil
+}
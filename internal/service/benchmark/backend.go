@@ -0,0 +1,303 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/provisioner"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Inference servers a run can deploy models with instead of running the
+// Ollama script
+const (
+	BackendVLLM     = "vllm"
+	BackendTGI      = "tgi"
+	BackendSGLang   = "sglang"
+	BackendLlamaCpp = "llamacpp" // llama.cpp server; models are GGUF repos, e.g. "org/model-GGUF:Q4_K_M"
+)
+
+// backend describes how an inference server is launched and reached
+type backend struct {
+	workloadType models.WorkloadType
+	port         int
+	// quantizes reports whether the server takes the run's quantizations.
+	// llama.cpp loads pre-quantized GGUF files instead.
+	quantizes bool
+
+	// For deployments over SSH: the container image, the arguments it is
+	// started with and the route that answers once the model is loaded
+	image      string
+	args       func(model, quantization string, port int) []string
+	healthPath string
+}
+
+var backends = map[string]backend{
+	BackendVLLM: {
+		workloadType: models.WorkloadLLMVLLM, port: 8000, quantizes: true,
+		image: "vllm/vllm-openai:latest", healthPath: "/health",
+		args: func(model, quantization string, port int) []string {
+			return withFlag([]string{"--model", model, "--host", "0.0.0.0", "--port", strconv.Itoa(port)}, "--quantization", quantization)
+		},
+	},
+	BackendTGI: {
+		workloadType: models.WorkloadLLMTGI, port: 80, quantizes: true,
+		image: "ghcr.io/huggingface/text-generation-inference:latest", healthPath: "/health",
+		args: func(model, quantization string, port int) []string {
+			return withFlag([]string{"--model-id", model, "--hostname", "0.0.0.0", "--port", strconv.Itoa(port)}, "--quantize", quantization)
+		},
+	},
+	BackendSGLang: {
+		workloadType: models.WorkloadLLMSGLang, port: 30000, quantizes: true,
+		image: "lmsysorg/sglang:latest", healthPath: "/health_generate",
+		args: func(model, quantization string, port int) []string {
+			return withFlag([]string{"python3", "-m", "sglang.launch_server", "--model-path", model, "--host", "0.0.0.0", "--port", strconv.Itoa(port)}, "--quantization", quantization)
+		},
+	},
+	BackendLlamaCpp: {
+		workloadType: models.WorkloadLLMLlamaCpp, port: 8080,
+		image: "ghcr.io/ggml-org/llama.cpp:server-cuda", healthPath: "/health",
+		args: func(model, _ string, port int) []string {
+			return []string{"-hf", model, "--host", "0.0.0.0", "--port", strconv.Itoa(port), "-ngl", "999"}
+		},
+	},
+}
+
+// withFlag appends flag and value to args unless value is empty
+func withFlag(args []string, flag, value string) []string {
+	if value == "" {
+		return args
+	}
+	return append(args, flag, value)
+}
+
+// Quantization methods a run can compare. Any of them deploys the model
+// with an inference server (vLLM unless backends say otherwise) instead of
+// running the Ollama script.
+const (
+	QuantizationFP16 = "fp16" // Unquantized baseline
+	QuantizationAWQ  = "awq"
+	QuantizationGPTQ = "gptq"
+	QuantizationFP8  = "fp8"
+)
+
+var validQuantizations = map[string]bool{
+	QuantizationFP16: true,
+	QuantizationAWQ:  true,
+	QuantizationGPTQ: true,
+	QuantizationFP8:  true,
+}
+
+// entrypointProviders launch inference servers in entrypoint mode. On the
+// others the runner deploys the server's container over SSH.
+var entrypointProviders = []string{"vastai"}
+
+// serverReadyTimeout covers the image pull, weight download and model load
+// before the API answers
+const serverReadyTimeout = 25 * time.Minute
+
+// serverQuantization maps a benchmark quantization to the server's
+// quantization flag. fp16 is the baseline, so no flag is passed.
+func serverQuantization(q string) string {
+	if q == QuantizationFP16 {
+		return ""
+	}
+	return q
+}
+
+func supportsEntrypoint(provider string) bool {
+	for _, p := range entrypointProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// entryBackend is the inference server an entry deploys, or "" for the
+// Ollama script. Quantized entries recorded before backends were stored
+// ran vLLM.
+func entryBackend(entry *benchmarkpkg.ManifestEntry) string {
+	if entry.Backend != "" {
+		return entry.Backend
+	}
+	if entry.Quantization != "" {
+		return BackendVLLM
+	}
+	return ""
+}
+
+// sessionBackend is the backend a session was launched with, or ""
+func sessionBackend(w models.WorkloadType) string {
+	for name, b := range backends {
+		if b.workloadType == w {
+			return name
+		}
+	}
+	return ""
+}
+
+// processServerEntryOnce provisions an instance running the entry's backend
+// with its quantization, benchmarks the API and destroys it. Providers
+// without entrypoint mode get a plain instance that the server is deployed
+// to over SSH. Returns the same
+// (success, shouldRetry, machineID) as processEntryOnce.
+func (r *Runner) processServerEntryOnce(ctx context.Context, run *BenchmarkRun, entry *benchmarkpkg.ManifestEntry, attempt int, offers []models.GPUOffer, releaseGate func()) (bool, bool, string) {
+	offer := pickBenchmarkOffer(offers)
+	entry.OfferID = offer.ID
+	entry.PriceHour = offer.PricePerHour
+	backendName := entryBackend(entry)
+	b := backends[backendName]
+	entrypoint := supportsEntrypoint(entry.Provider)
+
+	createReq := models.CreateSessionRequest{
+		ConsumerID:     fmt.Sprintf("bench-%s-%d", entry.ID, attempt),
+		OfferID:        offer.ID,
+		WorkloadType:   b.workloadType,
+		ReservationHrs: 1,
+		LaunchMode:     models.LaunchModeSSH,
+		ModelID:        entry.Model,
+		Quantization:   serverQuantization(entry.Quantization),
+		AutoRetry:      true,
+		MaxRetries:     2,
+		RetryScope:     "same_gpu",
+	}
+	if entrypoint {
+		createReq.LaunchMode = models.LaunchModeEntrypoint
+		createReq.ExposedPorts = []int{b.port}
+	}
+	session, err := r.provisioner.CreateSession(ctx, createReq, offer)
+	if err != nil {
+		var dupErr *provisioner.DuplicateSessionError
+		if errors.As(err, &dupErr) {
+			r.cleanupSession(ctx, dupErr.SessionID)
+		}
+		r.failServerEntry(entry, err.Error(), "provision")
+		return false, true, offer.MachineID
+	}
+
+	entry.SessionID = session.ID
+	if run.spend != nil {
+		run.spend.start(entry.ID, offer.PricePerHour, session.CreatedAt)
+		defer run.spend.finish(entry.ID)
+	}
+	if err := r.manifest.Update(ctx, entry); err != nil {
+		r.logger.Error("failed to update manifest entry",
+			slog.String("entry_id", entry.ID),
+			slog.String("error", err.Error()))
+	}
+	defer r.cleanupSession(ctx, session.ID)
+
+	r.logger.Info("inference server benchmark session provisioned",
+		slog.String("session_id", session.ID),
+		slog.String("entry_id", entry.ID),
+		slog.String("backend", backendName),
+		slog.String("quantization", entry.Quantization),
+		slog.Bool("entrypoint", entrypoint))
+	releaseGate()
+
+	var endpoint string
+	var ok bool
+	if entrypoint {
+		endpoint, ok = r.waitForServer(ctx, entry, session.ID, offer)
+	} else {
+		var closeDeployment func()
+		endpoint, closeDeployment, ok = r.deployServerOverSSH(ctx, entry, session, offer, b)
+		defer closeDeployment()
+	}
+	if !ok {
+		return false, true, offer.MachineID
+	}
+
+	out, err := benchmarkpkg.BenchmarkEndpoint(ctx, benchmarkpkg.EndpointConfig{
+		BaseURL: endpoint,
+		Model:   entry.Model,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, false, offer.MachineID
+		}
+		r.failServerEntry(entry, err.Error(), "benchmark")
+		return false, true, offer.MachineID
+	}
+
+	result := endpointResult(entry, out, offer.GPUCount, offer.PricePerHour)
+	result.Hardware.GPUMemoryMiB = offer.VRAM * 1024
+	result.Location = offer.Location
+	if err := r.store.Save(ctx, result); err != nil {
+		r.failServerEntry(entry, "save failed: "+err.Error(), "save")
+		return false, true, offer.MachineID
+	}
+
+	totalCost := time.Since(session.CreatedAt).Hours() * offer.PricePerHour
+	if err := r.manifest.MarkSuccess(ctx, entry.ID, result.ID, result.Results.AvgTokensPerSecond, totalCost); err != nil {
+		r.logger.Error("CRITICAL: failed to mark entry as success",
+			slog.String("entry_id", entry.ID),
+			slog.String("error", err.Error()))
+	}
+
+	r.logger.Info("inference server benchmark entry completed",
+		slog.String("entry_id", entry.ID),
+		slog.String("benchmark_id", result.ID),
+		slog.String("backend", backendName),
+		slog.String("quantization", entry.Quantization),
+		slog.Float64("avg_tps", result.Results.AvgTokensPerSecond),
+		slog.Float64("cost", totalCost))
+	return true, true, offer.MachineID
+}
+
+// waitForServer polls the session until the provisioner has verified the
+// server's API, and returns its endpoint. On failure the entry is already marked.
+func (r *Runner) waitForServer(ctx context.Context, entry *benchmarkpkg.ManifestEntry, sessionID string, offer *models.GPUOffer) (string, bool) {
+	pollCtx, cancel := context.WithTimeout(ctx, serverReadyTimeout)
+	defer cancel()
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pollCtx.Done():
+			if ctx.Err() != nil {
+				return "", false
+			}
+			r.logger.Warn("timeout waiting for inference server API", slog.String("session_id", sessionID))
+			if err := r.manifest.MarkTimeout(ctx, entry.ID, "api_wait"); err != nil {
+				r.logger.Error("failed to mark entry as timeout",
+					slog.String("entry_id", entry.ID),
+					slog.String("error", err.Error()))
+			}
+			r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "api_timeout", "inference server API not ready")
+			return "", false
+		case <-ticker.C:
+			s, err := r.provisioner.GetSession(ctx, sessionID)
+			if err != nil {
+				continue
+			}
+			if s.Status == models.StatusFailed {
+				r.failServerEntry(entry, s.Error, "provision")
+				r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "session_failed", s.Error)
+				return "", false
+			}
+			if endpoint, err := sessionEndpoint(s); err == nil {
+				return endpoint, true
+			}
+		}
+	}
+}
+
+func (r *Runner) failServerEntry(entry *benchmarkpkg.ManifestEntry, reason, stage string) {
+	r.logger.Warn("inference server benchmark failed",
+		slog.String("entry_id", entry.ID),
+		slog.String("stage", stage),
+		slog.String("reason", reason))
+	if err := r.manifest.MarkFailed(context.Background(), entry.ID, reason, stage); err != nil {
+		r.logger.Error("failed to mark entry as failed",
+			slog.String("entry_id", entry.ID),
+			slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,131 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+)
+
+func TestBenchmarkRunRequest_ValidateQuantizations(t *testing.T) {
+	models := []string{"mistral-7b"}
+	assert.NoError(t, BenchmarkRunRequest{Models: models, Quantizations: []string{"fp16", "awq", "gptq", "fp8"}}.Validate())
+	assert.NoError(t, BenchmarkRunRequest{Models: models, Quantizations: []string{"awq"}, Providers: []string{"vastai"}}.Validate())
+
+	assert.Error(t, BenchmarkRunRequest{Models: models, Quantizations: []string{"int4"}}.Validate())
+	assert.NoError(t, BenchmarkRunRequest{Models: models, Quantizations: []string{"awq"}, Providers: []string{"tensordock"}}.Validate(),
+		"providers without entrypoint mode get vLLM deployed over SSH")
+	assert.Error(t, BenchmarkRunRequest{SessionID: "sess-1", Quantizations: []string{"awq"}}.Validate())
+}
+
+func TestBenchmarkRunRequest_ValidateBackends(t *testing.T) {
+	models := []string{"meta-llama/Llama-3.1-8B-Instruct"}
+	assert.NoError(t, BenchmarkRunRequest{Models: models, Backends: []string{"vllm", "tgi", "sglang"}, Quantizations: []string{"fp16"}}.Validate())
+	assert.NoError(t, BenchmarkRunRequest{Models: []string{"org/model-GGUF:Q4_K_M"}, Backends: []string{"llamacpp"}}.Validate())
+	assert.NoError(t, BenchmarkRunRequest{SessionID: "sess-1", Backends: []string{"sglang"}}.Validate(), "labels the session's results")
+
+	assert.ErrorContains(t, BenchmarkRunRequest{Models: models, Backends: []string{"trt-llm"}}.Validate(), "unknown backend")
+	assert.Error(t, BenchmarkRunRequest{Models: models, Backends: []string{"llamacpp"}, Quantizations: []string{"awq"}}.Validate(),
+		"llama.cpp quantizations come from the GGUF file")
+	assert.Error(t, BenchmarkRunRequest{Endpoint: "http://host:8000", Backends: []string{"vllm", "sglang"}}.Validate())
+}
+
+func TestServerQuantization(t *testing.T) {
+	assert.Empty(t, serverQuantization(QuantizationFP16), "fp16 runs unquantized")
+	assert.Equal(t, "awq", serverQuantization(QuantizationAWQ))
+}
+
+func TestEntryBackend(t *testing.T) {
+	assert.Empty(t, entryBackend(&benchmarkpkg.ManifestEntry{}), "Ollama script")
+	assert.Equal(t, BackendVLLM, entryBackend(&benchmarkpkg.ManifestEntry{Quantization: "awq"}), "quantized entries predate backends")
+	assert.Equal(t, BackendSGLang, entryBackend(&benchmarkpkg.ManifestEntry{Backend: BackendSGLang, Quantization: "awq"}))
+
+	assert.Equal(t, BackendLlamaCpp, sessionBackend(backends[BackendLlamaCpp].workloadType))
+	assert.Empty(t, sessionBackend("llm"))
+}
+
+func TestRunner_CreateMatrixEntries_Quantizations(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	manifest, err := benchmarkpkg.NewManifestStore(db)
+	require.NoError(t, err)
+
+	r := &Runner{manifest: manifest, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ctx := context.Background()
+	count, err := r.createMatrixEntries(ctx, "run-quant", BenchmarkRunRequest{
+		Models:        []string{"mistral-7b"},
+		GPUTypes:      []string{"RTX 4090", "RTX A6000"},
+		Providers:     []string{"vastai"},
+		Quantizations: []string{"fp16", "awq"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	entries, err := manifest.ListByRun(ctx, "run-quant")
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	quants := make(map[string]int)
+	for _, e := range entries {
+		assert.Equal(t, BackendVLLM, e.Backend, "quantizations alone deploy vLLM")
+		quants[e.Quantization]++
+	}
+	assert.Equal(t, map[string]int{"fp16": 2, "awq": 2}, quants)
+}
+
+func TestRunner_CreateMatrixEntries_Backends(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	manifest, err := benchmarkpkg.NewManifestStore(db)
+	require.NoError(t, err)
+
+	r := &Runner{manifest: manifest, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ctx := context.Background()
+	count, err := r.createMatrixEntries(ctx, "run-backends", BenchmarkRunRequest{
+		Models:        []string{"meta-llama/Llama-3.1-8B-Instruct"},
+		GPUTypes:      []string{"RTX 4090"},
+		Backends:      []string{"vllm", "sglang", "tgi"},
+		Quantizations: []string{"fp16", "awq"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 18, count, "every provider can run a backend")
+
+	entries, err := manifest.ListByRun(ctx, "run-backends")
+	require.NoError(t, err)
+	perBackend := make(map[string]int)
+	for _, e := range entries {
+		perBackend[e.Backend]++
+	}
+	assert.Equal(t, map[string]int{"vllm": 6, "sglang": 6, "tgi": 6}, perBackend)
+}
+
+func TestDockerDeploySteps(t *testing.T) {
+	steps := dockerDeploySteps(backends[BackendVLLM], "TheBloke/Mistral-7B-Instruct-v0.2-AWQ", "awq")
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = s.name
+	}
+	assert.Equal(t, []string{"install_docker", "install_nvidia_toolkit", "pull_image", "start_server"}, names)
+	assert.Contains(t, steps[2].cmd, "docker pull 'vllm/vllm-openai:latest'")
+
+	start := steps[3].cmd
+	assert.Contains(t, start, "--gpus all")
+	assert.Contains(t, start, "-p 127.0.0.1:8000:8000", "only reachable through the SSH tunnel")
+	assert.Contains(t, start, "'--model' 'TheBloke/Mistral-7B-Instruct-v0.2-AWQ'")
+	assert.Contains(t, start, "'--quantization' 'awq'")
+
+	start = dockerDeploySteps(backends[BackendSGLang], "Qwen/Qwen2.5-7B-Instruct", "")[3].cmd
+	assert.Contains(t, start, "'lmsysorg/sglang:latest' 'python3' '-m' 'sglang.launch_server'")
+	assert.NotContains(t, start, "--quantization", "fp16 passes no flag")
+
+	start = dockerDeploySteps(backends[BackendLlamaCpp], "it's/model-GGUF:Q4_K_M", "")[3].cmd
+	assert.Contains(t, start, `'-hf' 'it'\''s/model-GGUF:Q4_K_M'`)
+}
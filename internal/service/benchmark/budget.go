@@ -0,0 +1,81 @@
+package benchmark
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultParallel runs one entry at a time unless a run asks for more
+	DefaultParallel = 1
+
+	// MaxParallel caps how many entries of a run hold instances at once
+	MaxParallel = 8
+
+	// budgetCheckInterval is how often a run's accrued cost is compared
+	// against its budget while entries are in flight
+	budgetCheckInterval = 30 * time.Second
+)
+
+// spendLedger tracks what a run has spent, including instances that are
+// still billing, so that entries running in parallel share one cost ceiling.
+// Cost is recorded in the manifest only when an entry succeeds; the ledger
+// also counts failed attempts, which are billed all the same.
+type spendLedger struct {
+	mu      sync.Mutex
+	settled float64
+	active  map[string]activeSpend
+	now     func() time.Time
+}
+
+type activeSpend struct {
+	pricePerHour float64
+	since        time.Time
+}
+
+func newSpendLedger() *spendLedger {
+	return &spendLedger{
+		active: make(map[string]activeSpend),
+		now:    time.Now,
+	}
+}
+
+// start begins accruing cost for an entry's instance
+func (l *spendLedger) start(entryID string, pricePerHour float64, since time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if since.IsZero() {
+		since = l.now()
+	}
+	l.active[entryID] = activeSpend{pricePerHour: pricePerHour, since: since}
+}
+
+// finish stops accruing cost for an entry and settles what it spent
+func (l *spendLedger) finish(entryID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if a, ok := l.active[entryID]; ok {
+		l.settled += a.cost(l.now())
+		delete(l.active, entryID)
+	}
+}
+
+// total returns settled spend plus what in-flight instances have accrued
+func (l *spendLedger) total() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	total := l.settled
+	now := l.now()
+	for _, a := range l.active {
+		total += a.cost(now)
+	}
+	return total
+}
+
+func (a activeSpend) cost(now time.Time) float64 {
+	elapsed := now.Sub(a.since)
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed.Hours() * a.pricePerHour
+}
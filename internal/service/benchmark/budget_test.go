@@ -0,0 +1,95 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+)
+
+func TestSpendLedger(t *testing.T) {
+	now := time.Now()
+	ledger := newSpendLedger()
+	ledger.now = func() time.Time { return now }
+
+	ledger.start("a", 2.0, now.Add(-30*time.Minute))
+	ledger.start("b", 1.0, now.Add(-time.Hour))
+	assert.InDelta(t, 2.0, ledger.total(), 0.0001, "in-flight instances accrue")
+
+	ledger.finish("a")
+	now = now.Add(30 * time.Minute)
+	// a settled at $1; b has now run 1.5h
+	assert.InDelta(t, 2.5, ledger.total(), 0.0001)
+
+	ledger.finish("b")
+	ledger.finish("b")
+	now = now.Add(time.Hour)
+	assert.InDelta(t, 2.5, ledger.total(), 0.0001, "settled spend stops accruing")
+}
+
+func TestBenchmarkRunRequest_Validate(t *testing.T) {
+	assert.NoError(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}}.Validate())
+	assert.NoError(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, Parallel: MaxParallel}.Validate())
+
+	assert.Error(t, BenchmarkRunRequest{}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, Parallel: MaxParallel + 1}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, Parallel: -1}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, MaxBudget: -5}.Validate())
+}
+
+func TestRunner_BudgetExhausted(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	manifest, err := benchmarkpkg.NewManifestStore(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var entries []*benchmarkpkg.ManifestEntry
+	for _, gpu := range []string{"RTX 4090", "RTX 3090", "A100"} {
+		entry := &benchmarkpkg.ManifestEntry{RunID: "run-budget", GPUType: gpu, Provider: "vastai", Model: "llama3.1:8b"}
+		require.NoError(t, manifest.Create(ctx, entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, manifest.MarkRunning(ctx, entries[0].ID, "worker-1", ""))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r := &Runner{
+		manifest: manifest,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		runs:     make(map[string]*BenchmarkRun),
+		cancels:  map[string]context.CancelFunc{"run-budget": cancel},
+	}
+	run := &BenchmarkRun{
+		ID:      "run-budget",
+		Request: BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, MaxBudget: 1.0, Parallel: 2},
+		spend:   newSpendLedger(),
+	}
+
+	run.spend.start(entries[0].ID, 1.0, time.Now().Add(-30*time.Minute))
+	assert.False(t, r.budgetExhausted(run))
+	assert.NoError(t, runCtx.Err())
+
+	// A second instance pushes the shared total over the ceiling
+	run.spend.start("other", 1.0, time.Now().Add(-45*time.Minute))
+	assert.True(t, r.budgetExhausted(run))
+	assert.True(t, run.BudgetExceeded)
+	assert.ErrorIs(t, runCtx.Err(), context.Canceled, "in-flight entries are aborted")
+
+	summary, err := manifest.GetSummary(ctx, "run-budget")
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary[benchmarkpkg.ManifestStatusSkipped])
+	assert.Equal(t, 1, summary[benchmarkpkg.ManifestStatusRunning])
+
+	// Later checks report the same without skipping again
+	assert.True(t, r.budgetExhausted(run))
+}
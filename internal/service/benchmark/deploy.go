@@ -0,0 +1,232 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+	sshpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// serverContainer names the inference server container on hosts deployed
+// over SSH
+const serverContainer = "gpu-shopper-bench"
+
+// deployStep is one remote command of an SSH deployment
+type deployStep struct {
+	name    string
+	cmd     string
+	timeout time.Duration
+}
+
+// sudoPrefix lets each step run as root whether or not the SSH user is
+const sudoPrefix = `SUDO=; [ "$(id -u)" -eq 0 ] || SUDO="sudo -n"; `
+
+// dockerDeploySteps installs Docker and the NVIDIA container toolkit where
+// missing, pulls the backend's image and starts its container. The server
+// is bound to the host's loopback and reached through an SSH tunnel, so no
+// provider port has to be opened.
+func dockerDeploySteps(b backend, model, quantization string) []deployStep {
+	args := b.args(model, quantization, b.port)
+	for i, a := range args {
+		args[i] = shellQuote(a)
+	}
+	run := fmt.Sprintf("$SUDO docker rm -f %s >/dev/null 2>&1; $SUDO docker run -d --name %s --gpus all --ipc=host -p 127.0.0.1:%d:%d %s %s",
+		serverContainer, serverContainer, b.port, b.port, shellQuote(b.image), strings.Join(args, " "))
+
+	return []deployStep{
+		{
+			name:    "install_docker",
+			cmd:     "command -v docker >/dev/null 2>&1 || curl -fsSL https://get.docker.com | $SUDO sh",
+			timeout: 15 * time.Minute,
+		},
+		{
+			name: "install_nvidia_toolkit",
+			cmd: "$SUDO docker info 2>/dev/null | grep -qi nvidia || { " +
+				"curl -fsSL https://nvidia.github.io/libnvidia-container/gpgkey | $SUDO gpg --batch --yes --dearmor -o /usr/share/keyrings/nvidia-container-toolkit-keyring.gpg && " +
+				"curl -fsSL https://nvidia.github.io/libnvidia-container/stable/deb/nvidia-container-toolkit.list | " +
+				"sed 's#deb https://#deb [signed-by=/usr/share/keyrings/nvidia-container-toolkit-keyring.gpg] https://#g' | " +
+				"$SUDO tee /etc/apt/sources.list.d/nvidia-container-toolkit.list >/dev/null && " +
+				"$SUDO apt-get update -qq && $SUDO DEBIAN_FRONTEND=noninteractive apt-get install -y -qq nvidia-container-toolkit && " +
+				"$SUDO nvidia-ctk runtime configure --runtime=docker && $SUDO systemctl restart docker; }",
+			timeout: 15 * time.Minute,
+		},
+		{
+			name:    "pull_image",
+			cmd:     "$SUDO docker pull " + shellQuote(b.image),
+			timeout: 20 * time.Minute,
+		},
+		{
+			name:    "start_server",
+			cmd:     run,
+			timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// deployServerOverSSH waits for the session's SSH access, deploys the
+// backend's container with dockerDeploySteps and waits for its API. It
+// returns an endpoint tunnelled to the server and a func that closes the
+// tunnel, which is never nil. On failure the entry is already marked.
+func (r *Runner) deployServerOverSSH(ctx context.Context, entry *benchmarkpkg.ManifestEntry, session *models.Session, offer *models.GPUOffer, b backend) (string, func(), bool) {
+	noop := func() {}
+
+	running, ok := r.waitForSSH(ctx, entry, session.ID, offer)
+	if !ok {
+		return "", noop, false
+	}
+	host, port, user := running.SSHHost, running.SSHPort, running.SSHUser
+	key := session.SSHPrivateKey // From creation response
+	ctx = r.pinHostKey(ctx, session.ID, entry.Provider, running.SSHHostKeyFingerprint)
+	if err := r.waitForSystemReady(ctx, host, port, user, key, entry.Provider); err != nil {
+		r.failServerEntry(entry, "system not ready: "+err.Error(), "readiness")
+		r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "readiness_timeout", "system not ready: "+err.Error())
+		return "", noop, false
+	}
+
+	executor := sshpkg.NewExecutor()
+	var conn *sshpkg.Connection
+	var err error
+	for attempt := 1; attempt <= 3; attempt++ {
+		if conn, err = executor.Connect(ctx, host, port, user, key); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", noop, false
+		case <-time.After(10 * time.Second):
+		}
+	}
+	if err != nil {
+		r.failServerEntry(entry, err.Error(), "deploy")
+		return "", noop, false
+	}
+
+	for _, step := range dockerDeploySteps(b, entry.Model, serverQuantization(entry.Quantization)) {
+		r.logger.Info("deploying inference server over SSH",
+			slog.String("entry_id", entry.ID),
+			slog.String("step", step.name))
+		stepCtx, cancel := context.WithTimeout(ctx, step.timeout)
+		_, stderr, err := executor.RunCommand(stepCtx, conn, sudoPrefix+step.cmd)
+		cancel()
+		if err != nil {
+			conn.Close()
+			if ctx.Err() == nil {
+				r.failServerEntry(entry, fmt.Sprintf("%s: %v: %s", step.name, err, lastLine(stderr)), "deploy")
+			}
+			return "", noop, false
+		}
+	}
+
+	tunnel, err := executor.Forward(conn, fmt.Sprintf("127.0.0.1:%d", b.port))
+	if err != nil {
+		conn.Close()
+		r.failServerEntry(entry, err.Error(), "deploy")
+		return "", noop, false
+	}
+	closeDeployment := func() {
+		tunnel.Close()
+		conn.Close()
+	}
+
+	endpoint := "http://" + tunnel.Addr()
+	if !r.waitForHealth(ctx, endpoint+b.healthPath) {
+		if ctx.Err() == nil {
+			logs, _, _ := executor.RunCommand(ctx, conn, sudoPrefix+"$SUDO docker logs --tail 20 "+serverContainer+" 2>&1")
+			r.logger.Warn("timeout waiting for inference server API",
+				slog.String("session_id", session.ID),
+				slog.String("logs", logs))
+			if err := r.manifest.MarkTimeout(ctx, entry.ID, "api_wait"); err != nil {
+				r.logger.Error("failed to mark entry as timeout",
+					slog.String("entry_id", entry.ID),
+					slog.String("error", err.Error()))
+			}
+			r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "api_timeout", "inference server API not ready")
+		}
+		closeDeployment()
+		return "", noop, false
+	}
+	return endpoint, closeDeployment, true
+}
+
+// waitForSSH polls the session until it is running with SSH access and
+// returns it. On failure the entry is already marked.
+func (r *Runner) waitForSSH(ctx context.Context, entry *benchmarkpkg.ManifestEntry, sessionID string, offer *models.GPUOffer) (*models.Session, bool) {
+	pollCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	defer cancel()
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pollCtx.Done():
+			if ctx.Err() != nil {
+				return nil, false
+			}
+			r.logger.Warn("timeout waiting for benchmark session", slog.String("session_id", sessionID))
+			if err := r.manifest.MarkTimeout(ctx, entry.ID, "ssh_wait"); err != nil {
+				r.logger.Error("failed to mark entry as timeout",
+					slog.String("entry_id", entry.ID),
+					slog.String("error", err.Error()))
+			}
+			r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "ssh_timeout", "benchmark SSH wait timeout")
+			return nil, false
+		case <-ticker.C:
+			s, err := r.provisioner.GetSession(ctx, sessionID)
+			if err != nil {
+				continue
+			}
+			if s.Status == models.StatusFailed {
+				r.failServerEntry(entry, s.Error, "provision")
+				r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "session_failed", s.Error)
+				return nil, false
+			}
+			if s.Status == models.StatusRunning && s.SSHHost != "" {
+				return s, true
+			}
+		}
+	}
+}
+
+// waitForHealth polls url until it answers 200, for up to serverReadyTimeout
+func (r *Runner) waitForHealth(ctx context.Context, url string) bool {
+	pollCtx, cancel := context.WithTimeout(ctx, serverReadyTimeout)
+	defer cancel()
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	for {
+		select {
+		case <-pollCtx.Done():
+			return false
+		case <-ticker.C:
+			req, err := http.NewRequestWithContext(pollCtx, http.MethodGet, url, nil)
+			if err != nil {
+				return false
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				continue
+			}
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return true
+			}
+		}
+	}
+}
+
+// lastLine is the final non-empty line of command output
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
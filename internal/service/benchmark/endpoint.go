@@ -0,0 +1,212 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ErrEndpointUnavailable is returned by StartRun when the endpoint or session
+// to benchmark cannot be used
+var ErrEndpointUnavailable = errors.New("benchmark endpoint unavailable")
+
+// externalProvider labels results from endpoints not managed by the shopper
+const externalProvider = "external"
+
+// Limits for concurrency sweeps, which send DefaultEndpointRequests
+// requests per level
+const (
+	MaxConcurrency       = 128
+	MaxConcurrencyLevels = 10
+)
+
+// skipsProvisioning reports whether the run benchmarks an already-running
+// endpoint rather than provisioning instances
+func (req BenchmarkRunRequest) skipsProvisioning() bool {
+	return req.Endpoint != "" || req.SessionID != ""
+}
+
+// sessionEndpoint returns the API endpoint of a running session
+func sessionEndpoint(session *models.Session) (string, error) {
+	if session.Status != models.StatusRunning {
+		return "", fmt.Errorf("%w: session %s is %s, not running", ErrEndpointUnavailable, session.ID, session.Status)
+	}
+	if session.APIEndpoint == "" {
+		return "", fmt.Errorf("%w: session %s has no API endpoint (launch it in entrypoint mode)", ErrEndpointUnavailable, session.ID)
+	}
+	return session.APIEndpoint, nil
+}
+
+// createEndpointEntries adds one manifest entry per model for a run against
+// an existing endpoint. GPU and provider come from the session, or from the
+// request for an external endpoint, and only label the results. Without
+// models, the session's model or the first model the endpoint serves is used.
+func (r *Runner) createEndpointEntries(ctx context.Context, runID string, req BenchmarkRunRequest) (int, error) {
+	gpu, provider := "unknown", externalProvider
+	endpoint := req.Endpoint
+	modelNames := req.Models
+	var quantization, backendName string
+
+	if req.SessionID != "" {
+		session, err := r.provisioner.GetSession(ctx, req.SessionID)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrEndpointUnavailable, err)
+		}
+		if endpoint, err = sessionEndpoint(session); err != nil {
+			return 0, err
+		}
+		gpu, provider, quantization = session.GPUType, session.Provider, session.Quantization
+		if len(modelNames) == 0 && session.ModelID != "" {
+			modelNames = []string{session.ModelID}
+		}
+	}
+	if len(req.GPUTypes) > 0 {
+		gpu = req.GPUTypes[0]
+	}
+	if len(req.Providers) > 0 {
+		provider = req.Providers[0]
+	}
+	if len(req.Backends) > 0 {
+		backendName = req.Backends[0]
+	}
+	if len(modelNames) == 0 {
+		served, err := benchmarkpkg.EndpointModel(ctx, nil, endpoint)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrEndpointUnavailable, err)
+		}
+		modelNames = []string{served}
+	}
+
+	for _, model := range modelNames {
+		entry := &benchmarkpkg.ManifestEntry{
+			RunID:        runID,
+			GPUType:      gpu,
+			Provider:     provider,
+			Model:        model,
+			Priority:     req.Priority,
+			Quantization: quantization,
+			Backend:      backendName,
+		}
+		if err := r.manifest.Create(ctx, entry); err != nil {
+			return 0, fmt.Errorf("failed to create manifest entry: %w", err)
+		}
+	}
+	return len(modelNames), nil
+}
+
+// processEndpointEntry benchmarks an already-running endpoint. Nothing is
+// provisioned or destroyed, so the entry never records a session ID and the
+// end-of-run sweep leaves the target session alone.
+func (r *Runner) processEndpointEntry(ctx context.Context, run *BenchmarkRun, entry *benchmarkpkg.ManifestEntry) {
+	endpoint := run.Request.Endpoint
+	gpuCount := 1
+	var price float64
+	if run.Request.SessionID != "" {
+		session, err := r.provisioner.GetSession(ctx, run.Request.SessionID)
+		if err == nil {
+			endpoint, err = sessionEndpoint(session)
+		}
+		if err != nil {
+			r.failEndpointEntry(entry, err.Error(), "find_endpoint")
+			return
+		}
+		price = session.PricePerHour
+		if session.GPUCount > 0 {
+			gpuCount = session.GPUCount
+		}
+	}
+
+	// Without an explicit workload, catalog models are benchmarked as what
+	// they are (e.g. a Whisper model as transcription)
+	workload := benchmarkpkg.Workload(run.Request.Workload)
+	if workload == "" && r.catalog != nil {
+		if m, ok := r.catalog.Model(entry.Model); ok {
+			workload = m.Workload
+		}
+	}
+
+	r.logger.Info("benchmarking existing endpoint",
+		slog.String("entry_id", entry.ID),
+		slog.String("model", entry.Model),
+		slog.String("workload", string(workload)),
+		slog.String("endpoint", endpoint))
+
+	start := time.Now()
+	out, err := benchmarkpkg.BenchmarkEndpoint(ctx, benchmarkpkg.EndpointConfig{
+		BaseURL:           endpoint,
+		Model:             entry.Model,
+		ConcurrencyLevels: run.Request.ConcurrencyLevels,
+		Workload:          workload,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			r.failEndpointEntry(entry, "run cancelled", "cancelled")
+			return
+		}
+		r.failEndpointEntry(entry, err.Error(), "benchmark")
+		return
+	}
+
+	result := endpointResult(entry, out, gpuCount, price)
+	if err := r.store.Save(ctx, result); err != nil {
+		r.failEndpointEntry(entry, "failed to store result: "+err.Error(), "store")
+		return
+	}
+
+	// The instance is paid for either way; attribute its time to the run so
+	// cost per token can be compared with provisioned runs
+	cost := time.Since(start).Hours() * price
+	if err := r.manifest.MarkSuccess(ctx, entry.ID, result.ID, result.Results.AvgTokensPerSecond, cost); err != nil {
+		r.logger.Error("failed to mark entry as success",
+			slog.String("entry_id", entry.ID),
+			slog.String("error", err.Error()))
+	}
+
+	r.logger.Info("endpoint benchmark completed",
+		slog.String("entry_id", entry.ID),
+		slog.String("benchmark_id", result.ID),
+		slog.Float64("avg_tps", result.Results.AvgTokensPerSecond),
+		slog.Float64("error_rate", result.Results.ErrorRate))
+}
+
+// endpointResult builds the stored result of a benchmark against an
+// OpenAI-compatible endpoint. Endpoints of unknown backend are assumed to be
+// vLLM.
+func endpointResult(entry *benchmarkpkg.ManifestEntry, out *benchmarkpkg.EndpointRun, gpuCount int, price float64) *benchmarkpkg.BenchmarkResult {
+	runtime := entryBackend(entry)
+	if runtime == "" {
+		runtime = BackendVLLM
+	}
+	return &benchmarkpkg.BenchmarkResult{
+		Timestamp: time.Now(),
+		Hardware:  benchmarkpkg.HardwareInfo{GPUName: entry.GPUType, GPUCount: gpuCount},
+		Model: benchmarkpkg.ModelInfo{
+			Name:         out.Model,
+			Quantization: entry.Quantization,
+			Runtime:      runtime,
+		},
+		TestConfig:        out.TestConfig,
+		Results:           out.Results,
+		ConcurrencyLevels: out.ConcurrencyLevels,
+		Workload:          out.Workload,
+		Provider:          entry.Provider,
+		PricePerHour:      price,
+	}
+}
+
+func (r *Runner) failEndpointEntry(entry *benchmarkpkg.ManifestEntry, reason, stage string) {
+	r.logger.Warn("endpoint benchmark failed",
+		slog.String("entry_id", entry.ID),
+		slog.String("reason", reason))
+	// Background: the run context may already be cancelled
+	if err := r.manifest.MarkFailed(context.Background(), entry.ID, reason, stage); err != nil {
+		r.logger.Error("failed to mark entry as failed",
+			slog.String("entry_id", entry.ID),
+			slog.String("error", err.Error()))
+	}
+}
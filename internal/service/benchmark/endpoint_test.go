@@ -0,0 +1,107 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+)
+
+func TestBenchmarkRunRequest_ValidateEndpoint(t *testing.T) {
+	assert.NoError(t, BenchmarkRunRequest{Endpoint: "http://10.0.0.5:8000"}.Validate(), "models default to the served one")
+	assert.NoError(t, BenchmarkRunRequest{SessionID: "sess-1"}.Validate())
+
+	assert.Error(t, BenchmarkRunRequest{Endpoint: "10.0.0.5:8000"}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Endpoint: "ftp://host"}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Endpoint: "http://host:8000", SessionID: "sess-1"}.Validate())
+
+	assert.NoError(t, BenchmarkRunRequest{SessionID: "sess-1", ConcurrencyLevels: []int{1, 2, 4, 8}}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, ConcurrencyLevels: []int{1, 2}}.Validate(),
+		"sweeps need an endpoint to target")
+	assert.Error(t, BenchmarkRunRequest{SessionID: "sess-1", ConcurrencyLevels: []int{0}}.Validate())
+	assert.Error(t, BenchmarkRunRequest{SessionID: "sess-1", ConcurrencyLevels: []int{MaxConcurrency + 1}}.Validate())
+
+	assert.NoError(t, BenchmarkRunRequest{Endpoint: "http://10.0.0.5:8000", Workload: "image"}.Validate())
+	assert.NoError(t, BenchmarkRunRequest{Models: []string{"llama3.1:8b"}, Workload: "chat"}.Validate())
+	assert.Error(t, BenchmarkRunRequest{Models: []string{"bge-large"}, Workload: "embeddings"}.Validate(),
+		"non-chat workloads need a running server")
+	assert.Error(t, BenchmarkRunRequest{SessionID: "sess-1", Workload: "video"}.Validate())
+}
+
+func TestRunner_StartRunAgainstEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			fmt.Fprint(w, `{"data":[{"id":"Qwen/Qwen2.5-7B-Instruct"}]}`)
+		case "/v1/chat/completions":
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"completion_tokens\":4}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	store, err := benchmarkpkg.NewStore(db)
+	require.NoError(t, err)
+	manifest, err := benchmarkpkg.NewManifestStore(db)
+	require.NoError(t, err)
+
+	// No provisioner or inventory: endpoint runs must not need them
+	r := NewRunner(nil, nil, store, manifest, slog.New(slog.NewTextHandler(io.Discard, nil)), "")
+	run, err := r.StartRun(context.Background(), BenchmarkRunRequest{
+		Endpoint: srv.URL,
+		GPUTypes: []string{"H100"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.TotalEntries)
+
+	require.Eventually(t, func() bool {
+		got, err := r.GetRun(context.Background(), run.ID)
+		return err == nil && got.Status == RunStatusCompleted
+	}, 5*time.Second, 20*time.Millisecond)
+
+	entries, err := r.GetRunEntries(context.Background(), run.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, benchmarkpkg.ManifestStatusSuccess, entry.Status)
+	assert.Equal(t, "Qwen/Qwen2.5-7B-Instruct", entry.Model)
+	assert.Equal(t, "H100", entry.GPUType)
+	assert.Equal(t, externalProvider, entry.Provider)
+	assert.Empty(t, entry.SessionID)
+
+	result, err := store.Get(context.Background(), entry.BenchmarkID)
+	require.NoError(t, err)
+	assert.Equal(t, "vllm", result.Model.Runtime)
+	assert.Equal(t, benchmarkpkg.DefaultEndpointRequests, result.Results.TotalRequests)
+	assert.Greater(t, result.Results.AvgTokensPerSecond, 0.0)
+}
+
+func TestRunner_StartRunAgainstUnreachableEndpoint(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	manifest, err := benchmarkpkg.NewManifestStore(db)
+	require.NoError(t, err)
+
+	r := NewRunner(nil, nil, nil, manifest, slog.New(slog.NewTextHandler(io.Discard, nil)), "")
+	_, err = r.StartRun(context.Background(), BenchmarkRunRequest{Endpoint: "http://127.0.0.1:1"})
+	assert.ErrorIs(t, err, ErrEndpointUnavailable)
+}
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
@@ -16,8 +18,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
 
 	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
 	sshpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/inventory"
@@ -33,6 +37,99 @@ type BenchmarkRunRequest struct {
 	MaxBudget float64  `json:"max_budget,omitempty"` // Total $ budget for the run
 	Priority  int      `json:"priority,omitempty"`   // Manifest priority (lower = higher)
 	Location  string   `json:"location,omitempty"`   // Country code filter (e.g., "US")
+	Parallel  int      `json:"parallel,omitempty"`   // Entries run at once (default 1, max MaxParallel)
+
+	// Benchmark an already-running OpenAI-compatible server (e.g. vLLM)
+	// instead of provisioning instances. At most one may be set; models
+	// default to the one the endpoint serves.
+	Endpoint  string `json:"endpoint,omitempty"`   // e.g. http://host:8000
+	SessionID string `json:"session_id,omitempty"` // Session launched in entrypoint mode
+
+	// Deploy each model with vLLM once per quantization (fp16, awq, gptq,
+	// fp8) instead of running the Ollama script. awq and gptq need a
+	// checkpoint quantized with that method.
+	Quantizations []string `json:"quantizations,omitempty"`
+
+	// Inference servers to deploy each model with (vllm, tgi, sglang,
+	// llamacpp), for comparing engines on the same hardware. Endpoint and
+	// session runs take at most one, which only labels their results.
+	Backends []string `json:"backends,omitempty"`
+
+	// Concurrency sweep for endpoint and session runs, e.g. [1, 2, 4, 8, 16].
+	// Each level's latency percentiles are stored with the result.
+	ConcurrencyLevels []int `json:"concurrency_levels,omitempty"`
+
+	// What endpoint and session runs measure: chat, embeddings,
+	// transcription or image. Defaults to the model's catalog workload, or
+	// chat for models outside the catalog.
+	Workload string `json:"workload,omitempty"`
+}
+
+// Validate checks a run request before any manifest entries are created.
+func (req BenchmarkRunRequest) Validate() error {
+	if req.Endpoint != "" && req.SessionID != "" {
+		return fmt.Errorf("endpoint and session_id are mutually exclusive")
+	}
+	if req.Endpoint != "" {
+		u, err := url.Parse(req.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint must be an http(s) URL")
+		}
+	}
+	if len(req.Models) == 0 && !req.skipsProvisioning() {
+		return fmt.Errorf("at least one model is required")
+	}
+	if req.Parallel < 0 || req.Parallel > MaxParallel {
+		return fmt.Errorf("parallel must be between 1 and %d", MaxParallel)
+	}
+	if req.MaxBudget < 0 {
+		return fmt.Errorf("max_budget must not be negative")
+	}
+	if len(req.Quantizations) > 0 {
+		if req.skipsProvisioning() {
+			return fmt.Errorf("quantizations only apply to provisioned runs")
+		}
+		for _, q := range req.Quantizations {
+			if !validQuantizations[q] {
+				return fmt.Errorf("unknown quantization %q (expected fp16, awq, gptq or fp8)", q)
+			}
+		}
+	}
+	for _, name := range req.Backends {
+		b, ok := backends[name]
+		if !ok {
+			return fmt.Errorf("unknown backend %q (expected vllm, tgi, sglang or llamacpp)", name)
+		}
+		if !b.quantizes && len(req.Quantizations) > 0 {
+			return fmt.Errorf("backend %s loads pre-quantized GGUF files; name the quantization in the model (e.g. org/model-GGUF:Q4_K_M)", name)
+		}
+	}
+	if len(req.Backends) > 1 && req.skipsProvisioning() {
+		return fmt.Errorf("endpoint and session runs take at most one backend")
+	}
+	if req.Workload != "" {
+		spec, err := benchmarkpkg.LookupWorkload(req.Workload)
+		if err != nil {
+			return err
+		}
+		if spec.Workload != benchmarkpkg.WorkloadChat && !req.skipsProvisioning() {
+			return fmt.Errorf("workload %s requires endpoint or session_id", spec.Workload)
+		}
+	}
+	if len(req.ConcurrencyLevels) > 0 {
+		if !req.skipsProvisioning() {
+			return fmt.Errorf("concurrency_levels requires endpoint or session_id")
+		}
+		if len(req.ConcurrencyLevels) > MaxConcurrencyLevels {
+			return fmt.Errorf("at most %d concurrency levels are allowed", MaxConcurrencyLevels)
+		}
+		for _, level := range req.ConcurrencyLevels {
+			if level < 1 || level > MaxConcurrency {
+				return fmt.Errorf("concurrency levels must be between 1 and %d", MaxConcurrency)
+			}
+		}
+	}
+	return nil
 }
 
 // BenchmarkRunStatus represents the current state of a benchmark run.
@@ -60,9 +157,19 @@ type BenchmarkRun struct {
 	Failed       int     `json:"failed"`
 	Running      int     `json:"running"`
 	Pending      int     `json:"pending"`
+	Skipped      int     `json:"skipped"`
 	TotalCost    float64 `json:"total_cost"`
+
+	// BudgetExceeded is set when the run was stopped by its max_budget
+	BudgetExceeded bool `json:"budget_exceeded,omitempty"`
+
+	spend *spendLedger
 }
 
+// ErrModelTooLarge is returned by StartRun when the model catalog lists
+// every requested model as too large for every GPU type of the run
+var ErrModelTooLarge = errors.New("no GPU type has enough VRAM for the model")
+
 // Runner orchestrates benchmark runs across GPU instances.
 type Runner struct {
 	provisioner *provisioner.Service
@@ -74,6 +181,9 @@ type Runner struct {
 	// Benchmark script content, loaded at construction time
 	scriptContent string
 
+	// Model and GPU sizes used to skip GPUs a model cannot fit on; optional
+	catalog *benchmarkpkg.Catalog
+
 	// Active runs tracking
 	mu      sync.Mutex
 	runs    map[string]*BenchmarkRun
@@ -84,6 +194,17 @@ type Runner struct {
 	provisionSem chan struct{}
 }
 
+// RunnerOption configures a Runner
+type RunnerOption func(*Runner)
+
+// WithCatalog sizes runs with the model catalog: GPU types too small for a
+// model are left out of the matrix and offers are filtered by its VRAM.
+func WithCatalog(catalog *benchmarkpkg.Catalog) RunnerOption {
+	return func(r *Runner) {
+		r.catalog = catalog
+	}
+}
+
 // NewRunner creates a new benchmark runner.
 // scriptPath is the path to the gpu-benchmark.sh script (e.g. "scripts/gpu-benchmark.sh").
 func NewRunner(
@@ -93,6 +214,7 @@ func NewRunner(
 	manifest *benchmarkpkg.ManifestStore,
 	logger *slog.Logger,
 	scriptPath string,
+	opts ...RunnerOption,
 ) *Runner {
 	var scriptContent string
 	if data, err := os.ReadFile(scriptPath); err != nil {
@@ -102,7 +224,7 @@ func NewRunner(
 		logger.Info("loaded benchmark script", slog.String("path", scriptPath), slog.Int("bytes", len(scriptContent)))
 	}
 
-	return &Runner{
+	r := &Runner{
 		provisioner:   prov,
 		inventory:     inv,
 		store:         store,
@@ -113,12 +235,19 @@ func NewRunner(
 		cancels:       make(map[string]context.CancelFunc),
 		provisionSem:  make(chan struct{}, 1),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // StartRun begins a new benchmark run.
 func (r *Runner) StartRun(ctx context.Context, req BenchmarkRunRequest) (*BenchmarkRun, error) {
-	if len(req.Models) == 0 {
-		return nil, fmt.Errorf("at least one model is required")
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Parallel == 0 {
+		req.Parallel = DefaultParallel
 	}
 
 	runID := "run-" + uuid.New().String()[:8]
@@ -130,15 +259,54 @@ func (r *Runner) StartRun(ctx context.Context, req BenchmarkRunRequest) (*Benchm
 		Request:   req,
 		CreatedAt: now,
 		UpdatedAt: now,
+		spend:     newSpendLedger(),
+	}
+
+	var entryCount int
+	var err error
+	if req.skipsProvisioning() {
+		entryCount, err = r.createEndpointEntries(ctx, runID, req)
+	} else {
+		entryCount, err = r.createMatrixEntries(ctx, runID, req)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	run.TotalEntries = entryCount
+	run.Pending = entryCount
+
+	r.mu.Lock()
+	r.runs[runID] = run
+	r.mu.Unlock()
+
+	// Start background processing
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.cancels[runID] = cancel
+	r.mu.Unlock()
+
+	go r.processRun(runCtx, run)
+
+	r.logger.Info("benchmark run started",
+		slog.String("run_id", runID),
+		slog.Int("entries", entryCount),
+		slog.Int("parallel", req.Parallel),
+		slog.Float64("max_budget", req.MaxBudget))
+
+	return run, nil
+}
 
+// createMatrixEntries adds a manifest entry for every model, GPU type,
+// provider, backend and quantization combination of the request.
+func (r *Runner) createMatrixEntries(ctx context.Context, runID string, req BenchmarkRunRequest) (int, error) {
 	// Determine GPU types to benchmark
 	gpuTypes := req.GPUTypes
 	if len(gpuTypes) == 0 {
 		// Use what's currently available
 		offers, err := r.inventory.ListOffers(ctx, models.OfferFilter{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list offers: %w", err)
+			return 0, fmt.Errorf("failed to list offers: %w", err)
 		}
 		seen := make(map[string]bool)
 		for _, o := range offers {
@@ -155,47 +323,68 @@ func (r *Runner) StartRun(ctx context.Context, req BenchmarkRunRequest) (*Benchm
 		providers = []string{"vastai", "bluelobster", "tensordock"}
 	}
 
-	// Create manifest entries: models x GPU types x providers
+	// Without backends or quantizations, one pass runs the Ollama script.
+	// Quantizations alone deploy vLLM.
+	backendNames := req.Backends
+	if len(backendNames) == 0 {
+		backendNames = []string{""}
+		if len(req.Quantizations) > 0 {
+			backendNames = []string{BackendVLLM}
+		}
+	}
+	quantizations := req.Quantizations
+	if len(quantizations) == 0 {
+		quantizations = []string{""}
+	}
+
+	// Create manifest entries: models x GPU types x providers x backends x
+	// quantizations
 	entryCount := 0
+	var tooSmall []string
 	for _, model := range req.Models {
 		for _, gpu := range gpuTypes {
+			if r.catalog != nil && !r.catalog.FitsGPU(model, gpu) {
+				r.logger.Info("skipping GPU too small for model",
+					slog.String("run_id", runID),
+					slog.String("model", model),
+					slog.String("gpu_type", gpu),
+					slog.Int("min_vram_gb", r.catalog.MinVRAM(model)))
+				tooSmall = append(tooSmall, model+" on "+gpu)
+				continue
+			}
 			for _, prov := range providers {
-				entry := &benchmarkpkg.ManifestEntry{
-					RunID:    runID,
-					GPUType:  gpu,
-					Provider: prov,
-					Model:    model,
-					Priority: req.Priority,
-				}
-				if err := r.manifest.Create(ctx, entry); err != nil {
-					return nil, fmt.Errorf("failed to create manifest entry: %w", err)
+				for _, backendName := range backendNames {
+					for _, quant := range quantizations {
+						entry := &benchmarkpkg.ManifestEntry{
+							RunID:        runID,
+							GPUType:      gpu,
+							Provider:     prov,
+							Model:        model,
+							Priority:     req.Priority,
+							Quantization: quant,
+							Backend:      backendName,
+						}
+						if err := r.manifest.Create(ctx, entry); err != nil {
+							return 0, fmt.Errorf("failed to create manifest entry: %w", err)
+						}
+						entryCount++
+					}
 				}
-				entryCount++
 			}
 		}
 	}
+	if entryCount == 0 && len(tooSmall) > 0 {
+		return 0, fmt.Errorf("%w: %s", ErrModelTooLarge, strings.Join(tooSmall, ", "))
+	}
+	return entryCount, nil
+}
 
-	run.TotalEntries = entryCount
-	run.Pending = entryCount
-
-	r.mu.Lock()
-	r.runs[runID] = run
-	r.mu.Unlock()
-
-	// Start background processing
-	runCtx, cancel := context.WithCancel(context.Background())
-	r.mu.Lock()
-	r.cancels[runID] = cancel
-	r.mu.Unlock()
-
-	go r.processRun(runCtx, run)
-
-	r.logger.Info("benchmark run started",
-		slog.String("run_id", runID),
-		slog.Int("entries", entryCount),
-		slog.Float64("max_budget", req.MaxBudget))
-
-	return run, nil
+// minVRAM is the per-GPU memory the catalog lists for model, or 0
+func (r *Runner) minVRAM(model string) int {
+	if r.catalog == nil {
+		return 0
+	}
+	return r.catalog.MinVRAM(model)
 }
 
 // GetRun returns the current state of a benchmark run.
@@ -217,12 +406,19 @@ func (r *Runner) GetRun(ctx context.Context, runID string) (*BenchmarkRun, error
 		snapshot.Running = summary[benchmarkpkg.ManifestStatusRunning]
 		snapshot.Completed = summary[benchmarkpkg.ManifestStatusSuccess]
 		snapshot.Failed = summary[benchmarkpkg.ManifestStatusFailed] + summary[benchmarkpkg.ManifestStatusTimeout]
+		snapshot.Skipped = summary[benchmarkpkg.ManifestStatusSkipped]
 	}
 
 	cost, err := r.manifest.GetTotalCost(ctx, runID)
 	if err == nil {
 		snapshot.TotalCost = cost
 	}
+	// The ledger also counts failed attempts and instances still running
+	if snapshot.spend != nil {
+		if spent := snapshot.spend.total(); spent > snapshot.TotalCost {
+			snapshot.TotalCost = spent
+		}
+	}
 
 	return &snapshot, nil
 }
@@ -277,84 +473,139 @@ func (r *Runner) processRun(ctx context.Context, run *BenchmarkRun) {
 	run.UpdatedAt = time.Now()
 	r.mu.Unlock()
 
+	if run.Request.MaxBudget > 0 {
+		go r.watchBudget(ctx, run)
+	}
+
+	parallel := run.Request.Parallel
+	if parallel <= 0 {
+		parallel = DefaultParallel
+	}
+	// Each slot is one entry holding (or provisioning) an instance
+	slots := make(chan struct{}, parallel)
+
 	var wg sync.WaitGroup
+	dispatched := 0
 
+dispatch:
 	for {
 		select {
+		case slots <- struct{}{}:
 		case <-ctx.Done():
-			wg.Wait()
-			return
-		default:
+			break dispatch
 		}
 
-		// Get next pending entries
-		entries, err := r.manifest.GetPendingByPriority(ctx, run.ID, 1000) // fetch all pending
-		if err != nil {
-			r.logger.Error("failed to get pending entries", slog.String("error", err.Error()))
+		if r.budgetExhausted(run) {
+			<-slots
 			break
 		}
-		if len(entries) == 0 {
-			// All entries are either running or completed. Wait for current
-			// workers to finish, then re-check — there may be more pending
-			// entries beyond the initial batch (e.g., 5 total with batch size 3).
-			wg.Wait()
-
-			// Re-check after workers finish: if more entries became pending
-			// (or were never fetched), continue; otherwise we're done.
-			remaining, _ := r.manifest.GetPendingByPriority(ctx, run.ID, 1)
-			if len(remaining) == 0 {
-				break
+
+		// Brief stagger to avoid API rate limiting (provisioning gate handles sequencing)
+		if dispatched > 0 {
+			select {
+			case <-ctx.Done():
+				<-slots
+				break dispatch
+			case <-time.After(500 * time.Millisecond):
 			}
-			continue
 		}
 
-		// Check budget
-		if run.Request.MaxBudget > 0 {
-			totalCost, _ := r.manifest.GetTotalCost(ctx, run.ID)
-			if totalCost >= run.Request.MaxBudget {
-				r.logger.Warn("budget exhausted", slog.Float64("total_cost", totalCost), slog.Float64("budget", run.Request.MaxBudget))
-				break
+		entries, err := r.manifest.GetPendingByPriority(ctx, run.ID, 1)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error("failed to get pending entries", slog.String("error", err.Error()))
 			}
+			<-slots
+			break
+		}
+		if len(entries) == 0 {
+			<-slots
+			break
 		}
+		entry := entries[0]
 
-		for i, entry := range entries {
-			// Mark running BEFORE dispatching to prevent double-dispatch
-			// when the outer loop re-fetches pending entries.
-			workerID := "worker-" + uuid.New().String()[:8]
-			if err := r.manifest.MarkRunning(ctx, entry.ID, workerID, ""); err != nil {
-				r.logger.Error("failed to mark entry running", slog.String("error", err.Error()))
+		// Mark running BEFORE dispatching to prevent double-dispatch
+		workerID := "worker-" + uuid.New().String()[:8]
+		if err := r.manifest.MarkRunning(ctx, entry.ID, workerID, ""); err != nil {
+			<-slots
+			if errors.Is(err, benchmarkpkg.ErrEntryNotPending) {
 				continue
 			}
-			entry.Status = benchmarkpkg.ManifestStatusRunning // sync in-memory status
+			r.logger.Error("failed to mark entry running", slog.String("error", err.Error()))
+			break
+		}
+		entry.Status = benchmarkpkg.ManifestStatusRunning // sync in-memory status
+
+		dispatched++
+		wg.Add(1)
+		go func(e *benchmarkpkg.ManifestEntry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.processEntry(ctx, run, e)
+		}(entry)
+	}
 
-			select {
-			case <-ctx.Done():
-				wg.Wait()
-				r.updateRunStatus(run)
-				return
-			default:
-				wg.Add(1)
-				go func(e *benchmarkpkg.ManifestEntry) {
-					defer wg.Done()
-					r.processEntry(ctx, run, e)
-				}(entry)
-			}
+	wg.Wait()
+	r.updateRunStatus(run)
+}
 
-			// Brief stagger to avoid API rate limiting (provisioning gate handles sequencing)
-			if i < len(entries)-1 {
-				select {
-				case <-ctx.Done():
-					wg.Wait()
-					r.updateRunStatus(run)
-					return
-				case <-time.After(500 * time.Millisecond):
-				}
+// watchBudget aborts the run once its accrued cost reaches max_budget. Cost
+// keeps accruing while entries run, so it is checked periodically rather
+// than only between dispatches.
+func (r *Runner) watchBudget(ctx context.Context, run *BenchmarkRun) {
+	ticker := time.NewTicker(budgetCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.budgetExhausted(run) {
+				return
 			}
 		}
 	}
+}
 
-	wg.Wait()
-	r.updateRunStatus(run)
+// budgetExhausted reports whether the run has spent its max_budget. The
+// first time it has, pending entries are skipped and the run context is
+// cancelled so in-flight entries stop and release their instances.
+func (r *Runner) budgetExhausted(run *BenchmarkRun) bool {
+	budget := run.Request.MaxBudget
+	if budget <= 0 || run.spend == nil {
+		return false
+	}
+	spent := run.spend.total()
+	if spent < budget {
+		return false
+	}
+
+	r.mu.Lock()
+	if run.BudgetExceeded {
+		r.mu.Unlock()
+		return true
+	}
+	run.BudgetExceeded = true
+	cancel := r.cancels[run.ID]
+	r.mu.Unlock()
+
+	skipped, err := r.manifest.SkipPending(context.Background(), run.ID, "budget exhausted")
+	if err != nil {
+		r.logger.Error("failed to skip pending entries",
+			slog.String("run_id", run.ID),
+			slog.String("error", err.Error()))
+	}
+	r.logger.Warn("budget exhausted, aborting benchmark run",
+		slog.String("run_id", run.ID),
+		slog.Float64("total_cost", spent),
+		slog.Float64("budget", budget),
+		slog.Int("skipped", skipped))
+
+	if cancel != nil {
+		cancel()
+	}
+	return true
 }
 
 // updateRunStatus updates the run's final status.
@@ -371,6 +622,7 @@ func (r *Runner) updateRunStatus(run *BenchmarkRun) {
 
 	run.Pending = summary[benchmarkpkg.ManifestStatusPending]
 	run.Running = summary[benchmarkpkg.ManifestStatusRunning]
+	run.Skipped = summary[benchmarkpkg.ManifestStatusSkipped]
 
 	// Completed and failed counts should never decrease (monotonic)
 	newCompleted := summary[benchmarkpkg.ManifestStatusSuccess]
@@ -381,6 +633,11 @@ func (r *Runner) updateRunStatus(run *BenchmarkRun) {
 	if newFailed > run.Failed {
 		run.Failed = newFailed
 	}
+	if run.spend != nil {
+		if spent := run.spend.total(); spent > totalCost {
+			totalCost = spent
+		}
+	}
 	if totalCost > run.TotalCost {
 		run.TotalCost = totalCost
 	}
@@ -398,6 +655,11 @@ func (r *Runner) updateRunStatus(run *BenchmarkRun) {
 // processEntry handles a single manifest entry with 1 retry (2 total attempts).
 // The entry is already marked as running by processRun before dispatch.
 func (r *Runner) processEntry(ctx context.Context, run *BenchmarkRun, entry *benchmarkpkg.ManifestEntry) {
+	if run.Request.skipsProvisioning() {
+		r.processEndpointEntry(ctx, run, entry)
+		return
+	}
+
 	const maxAttempts = 2
 	var failedOfferIDs []string
 	var failedMachineIDs []string
@@ -429,18 +691,24 @@ func (r *Runner) processEntry(ctx context.Context, run *BenchmarkRun, entry *ben
 		if lastMachineID != "" {
 			failedMachineIDs = append(failedMachineIDs, lastMachineID)
 		}
-		if !shouldRetry {
-			r.logger.Info("skipping retry — no offers available",
-				slog.String("entry_id", entry.ID))
-			return
-		}
 		if ctx.Err() != nil {
-			// Context cancelled — mark with reason if not already terminal (use Background since ctx is cancelled)
-			if mfErr := r.manifest.MarkFailed(context.Background(), entry.ID, "run cancelled", "cancelled"); mfErr != nil {
+			// Context cancelled — mark with reason (use Background since ctx is cancelled)
+			reason := "run cancelled"
+			r.mu.Lock()
+			if run.BudgetExceeded {
+				reason = "budget exhausted"
+			}
+			r.mu.Unlock()
+			if mfErr := r.manifest.MarkFailed(context.Background(), entry.ID, reason, "cancelled"); mfErr != nil {
 				r.logger.Error("failed to mark cancelled entry", slog.String("error", mfErr.Error()))
 			}
 			return
 		}
+		if !shouldRetry {
+			r.logger.Info("skipping retry — no offers available",
+				slog.String("entry_id", entry.ID))
+			return
+		}
 	}
 	// All retries exhausted — ensure entry has a failure reason
 	r.logger.Warn("all retry attempts exhausted",
@@ -460,7 +728,8 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 		slog.String("entry_id", entry.ID),
 		slog.String("model", entry.Model),
 		slog.String("gpu_type", entry.GPUType),
-		slog.String("provider", entry.Provider))
+		slog.String("provider", entry.Provider),
+		slog.String("quantization", entry.Quantization))
 
 	// Acquire provisioning gate — only one entry provisions at a time so that
 	// cache evictions from failed offers benefit the next entry.
@@ -483,6 +752,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 		Provider: entry.Provider,
 		GPUType:  entry.GPUType,
 		Location: run.Request.Location,
+		MinVRAM:  r.minVRAM(entry.Model),
 	})
 	if err != nil || len(offers) == 0 {
 		reason := fmt.Sprintf("no offers available for %s on %s", entry.GPUType, entry.Provider)
@@ -531,6 +801,10 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 		}
 	}
 
+	if entryBackend(entry) != "" {
+		return r.processServerEntryOnce(ctx, run, entry, attempt, offers, releaseGate)
+	}
+
 	// Vast.ai: filter offers to those compatible with the Ollama template
 	ollamaTemplateHash := "38a9dab633743d43107eb9a80d4ada9e"
 	if entry.Provider == "vastai" {
@@ -562,23 +836,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 		}
 	}
 
-	// Prefer single-GPU offers for benchmarks (cheaper, more available)
-	singleGPU := make([]models.GPUOffer, 0)
-	for _, o := range offers {
-		if o.GPUCount == 1 {
-			singleGPU = append(singleGPU, o)
-		}
-	}
-	if len(singleGPU) > 0 {
-		offers = singleGPU
-	}
-
-	// Sort by price ascending for SelectFromTopN
-	sort.Slice(offers, func(i, j int) bool {
-		return offers[i].PricePerHour < offers[j].PricePerHour
-	})
-
-	offer := models.SelectFromTopN(offers, 5, 1.5)
+	offer := pickBenchmarkOffer(offers)
 	entry.OfferID = offer.ID
 	entry.PriceHour = offer.PricePerHour
 
@@ -624,6 +882,11 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 	}
 
 	entry.SessionID = session.ID
+	// The instance bills from here on, whether or not the attempt succeeds
+	if run.spend != nil {
+		run.spend.start(entry.ID, offer.PricePerHour, session.CreatedAt)
+		defer run.spend.finish(entry.ID)
+	}
 	if err := r.manifest.Update(ctx, entry); err != nil {
 		r.logger.Error("failed to update manifest entry",
 			slog.String("entry_id", entry.ID),
@@ -639,7 +902,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 	releaseGate()
 
 	// Step 3: Wait for session to be running with SSH access
-	var sshHost, sshUser, sshKey string
+	var sshHost, sshUser, sshKey, sshHostKey string
 	var sshPort int
 
 	pollCtx, pollCancel := context.WithTimeout(ctx, 15*time.Minute)
@@ -649,6 +912,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 	defer ticker.Stop()
 
 	sessionReady := false
+	var lastPhase models.ProvisionPhase
 	for !sessionReady {
 		select {
 		case <-pollCtx.Done():
@@ -659,7 +923,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 					slog.String("entry_id", entry.ID),
 					slog.String("error", err.Error()))
 			}
-			r.reportOfferFailure(offer.ID, entry.Provider, entry.GPUType, "ssh_timeout", "benchmark SSH wait timeout")
+			r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "ssh_timeout", "benchmark SSH wait timeout")
 			r.cleanupSession(ctx, session.ID)
 			return false, true, offer.MachineID
 		case <-ticker.C:
@@ -667,13 +931,20 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 			if err != nil {
 				continue
 			}
+			if s.ProvisionPhase != lastPhase {
+				lastPhase = s.ProvisionPhase
+				r.logger.Info("benchmark session provisioning progress",
+					slog.String("session_id", session.ID),
+					slog.String("phase", string(s.ProvisionPhase)),
+					slog.Duration("elapsed", time.Since(session.CreatedAt)))
+			}
 			if s.Status == models.StatusFailed {
 				if err := r.manifest.MarkFailed(ctx, entry.ID, s.Error, "provision"); err != nil {
 					r.logger.Error("failed to mark entry as failed",
 						slog.String("entry_id", entry.ID),
 						slog.String("error", err.Error()))
 				}
-				r.reportOfferFailure(offer.ID, entry.Provider, entry.GPUType, "session_failed", s.Error)
+				r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "session_failed", s.Error)
 				r.cleanupSession(ctx, session.ID)
 				return false, true, offer.MachineID
 			}
@@ -682,11 +953,14 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 				sshPort = s.SSHPort
 				sshUser = s.SSHUser
 				sshKey = session.SSHPrivateKey // From creation response
+				sshHostKey = s.SSHHostKeyFingerprint
 				sessionReady = true
 			}
 		}
 	}
 
+	ctx = r.pinHostKey(ctx, session.ID, entry.Provider, sshHostKey)
+
 	// Step 3.5: Wait for system readiness (Blue Lobster post-boot dist-upgrade)
 	if err := r.waitForSystemReady(ctx, sshHost, sshPort, sshUser, sshKey, entry.Provider); err != nil {
 		r.logger.Error("system readiness failed", slog.String("error", err.Error()))
@@ -695,7 +969,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 				slog.String("entry_id", entry.ID),
 				slog.String("error", markErr.Error()))
 		}
-		r.reportOfferFailure(offer.ID, entry.Provider, entry.GPUType, "readiness_timeout", "system not ready: "+err.Error())
+		r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "readiness_timeout", "system not ready: "+err.Error())
 		r.cleanupSession(ctx, session.ID)
 		return false, true, offer.MachineID
 	}
@@ -750,7 +1024,7 @@ func (r *Runner) processEntryOnce(ctx context.Context, run *BenchmarkRun, entry
 				slog.String("entry_id", entry.ID),
 				slog.String("error", markErr.Error()))
 		}
-		r.reportOfferFailure(offer.ID, entry.Provider, entry.GPUType, "deploy_failed", "script upload failed: "+uploadErr.Error())
+		r.reportOfferFailure(offer, entry.Provider, entry.GPUType, "deploy_failed", "script upload failed: "+uploadErr.Error())
 		r.cleanupSession(ctx, session.ID)
 		return false, true, offer.MachineID
 	}
@@ -886,14 +1160,35 @@ resultsCollected:
 	return true, true, offer.MachineID
 }
 
-// reportOfferFailure records a post-provisioning failure to the global tracker
-// and evicts the offer from cache so other concurrent entries avoid it.
-func (r *Runner) reportOfferFailure(offerID, provider, gpuType, failureType, reason string) {
-	if offerID == "" {
+// pickBenchmarkOffer prefers single-GPU offers (cheaper, more available) and
+// picks among the cheapest few so retries spread across hosts
+func pickBenchmarkOffer(offers []models.GPUOffer) *models.GPUOffer {
+	singleGPU := make([]models.GPUOffer, 0)
+	for _, o := range offers {
+		if o.GPUCount == 1 {
+			singleGPU = append(singleGPU, o)
+		}
+	}
+	if len(singleGPU) > 0 {
+		offers = singleGPU
+	}
+
+	// Sort by price ascending for SelectFromTopN
+	sort.Slice(offers, func(i, j int) bool {
+		return offers[i].PricePerHour < offers[j].PricePerHour
+	})
+	return models.SelectFromTopN(offers, 5, 1.5)
+}
+
+// reportOfferFailure records a post-provisioning failure to the global tracker,
+// against the offer and its host, and evicts the offer from cache so other
+// concurrent entries avoid it.
+func (r *Runner) reportOfferFailure(offer *models.GPUOffer, provider, gpuType, failureType, reason string) {
+	if offer == nil || offer.ID == "" {
 		return
 	}
-	r.inventory.RecordOfferFailure(offerID, provider, gpuType, failureType, reason)
-	r.inventory.EvictOffer(offerID)
+	r.inventory.RecordOfferFailure(offer.ID, provider, gpuType, offer.MachineID, failureType, reason)
+	r.inventory.EvictOffer(offer.ID)
 }
 
 // cleanupSession destroys a benchmark session.
@@ -940,6 +1235,26 @@ func (r *Runner) cleanupRunSessions(runID string) {
 	}
 }
 
+// pinHostKey returns a context whose SSH connections require the host key
+// the provisioner recorded for the session on first use. A changed key is
+// refused and logged.
+func (r *Runner) pinHostKey(ctx context.Context, sessionID, provider, fingerprint string) context.Context {
+	verify := sshpkg.TrustOnFirstUse(fingerprint, nil)
+	return sshpkg.WithHostKeyCallback(ctx, func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := verify(hostname, remote, key)
+		var mismatch *sshpkg.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			r.logger.Error("SSH host key changed, refusing connection",
+				slog.String("session_id", sessionID),
+				slog.String("addr", mismatch.Addr),
+				slog.String("expected", mismatch.Expected),
+				slog.String("actual", mismatch.Actual))
+			metrics.RecordSSHHostKeyMismatch(provider)
+		}
+		return err
+	})
+}
+
 // waitForSystemReady waits for dpkg locks to release and nvidia-smi to return
 // stable GPU names before allowing the benchmark to proceed. Only runs for
 // Blue Lobster provider, which has a post-boot dist-upgrade cycle.
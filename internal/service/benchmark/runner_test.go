@@ -0,0 +1,49 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	benchmarkpkg "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/benchmark"
+)
+
+func TestRunner_CreateMatrixEntries_SkipsGPUsTooSmall(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	manifest, err := benchmarkpkg.NewManifestStore(db)
+	require.NoError(t, err)
+
+	r := NewRunner(nil, nil, nil, manifest, slog.New(slog.NewTextHandler(io.Discard, nil)), "",
+		WithCatalog(benchmarkpkg.NewCatalog()))
+	ctx := context.Background()
+
+	count, err := r.createMatrixEntries(ctx, "run-fit", BenchmarkRunRequest{
+		Models:    []string{"llama3:70b", "llama3.1:8b", "acme/custom-model"},
+		GPUTypes:  []string{"RTX 4090", "H100 SXM"},
+		Providers: []string{"vastai"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, count, "llama3:70b is left off the RTX 4090")
+
+	entries, err := manifest.ListByRun(ctx, "run-fit")
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, e.Model == "llama3:70b" && e.GPUType == "RTX 4090")
+	}
+	assert.Equal(t, 48, r.minVRAM("llama3:70b"))
+	assert.Zero(t, r.minVRAM("acme/custom-model"))
+
+	_, err = r.createMatrixEntries(ctx, "run-none", BenchmarkRunRequest{
+		Models:    []string{"llama3:70b"},
+		GPUTypes:  []string{"RTX 3090", "RTX 4090"},
+		Providers: []string{"vastai"},
+	})
+	assert.ErrorIs(t, err, ErrModelTooLarge)
+}
@@ -6,8 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -18,15 +16,48 @@ import (
 type Schedule struct {
 	ID        string              `json:"id"`
 	Name      string              `json:"name"` // e.g. "weekly-value-check"
-	CronExpr  string              `json:"cron"` // e.g. "0 0 * * 0" (weekly)
+	CronExpr  string              `json:"cron"` // e.g. "0 0 * * 0" (weekly, UTC)
 	Request   BenchmarkRunRequest `json:"run_request"`
 	Enabled   bool                `json:"enabled"`
 	LastRunID string              `json:"last_run_id,omitempty"`
 	LastRunAt *time.Time          `json:"last_run_at,omitempty"`
+	NextRunAt *time.Time          `json:"next_run_at,omitempty"` // Computed from CronExpr, not stored
 	CreatedAt time.Time           `json:"created_at"`
 	UpdatedAt time.Time           `json:"updated_at"`
 }
 
+// Validate checks that a schedule can be run.
+func (s *Schedule) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := ParseCron(s.CronExpr); err != nil {
+		return err
+	}
+	if len(s.Request.Models) == 0 && !s.Request.skipsProvisioning() {
+		return fmt.Errorf("run_request must include at least one model")
+	}
+	if err := s.Request.Validate(); err != nil {
+		return fmt.Errorf("run_request: %w", err)
+	}
+	return nil
+}
+
+// setNextRun fills NextRunAt for enabled schedules with a valid expression.
+func (s *Schedule) setNextRun(now time.Time) {
+	s.NextRunAt = nil
+	if !s.Enabled {
+		return
+	}
+	cron, err := ParseCron(s.CronExpr)
+	if err != nil {
+		return
+	}
+	if next := cron.Next(now); !next.IsZero() {
+		s.NextRunAt = &next
+	}
+}
+
 // ScheduleStore provides persistence for benchmark schedules.
 type ScheduleStore struct {
 	db *sql.DB
@@ -77,7 +108,11 @@ func (s *ScheduleStore) Create(ctx context.Context, sched *Schedule) error {
 		INSERT INTO benchmark_schedules (id, name, cron_expr, run_request_json, enabled, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`, sched.ID, sched.Name, sched.CronExpr, string(reqJSON), sched.Enabled, sched.CreatedAt, sched.UpdatedAt)
-	return err
+	if err != nil {
+		return err
+	}
+	sched.setNextRun(time.Now())
+	return nil
 }
 
 // Update modifies an existing schedule.
@@ -96,7 +131,11 @@ func (s *ScheduleStore) Update(ctx context.Context, sched *Schedule) error {
 		WHERE id = ?
 	`, sched.Name, sched.CronExpr, string(reqJSON), sched.Enabled,
 		sched.LastRunID, sched.LastRunAt, sched.UpdatedAt, sched.ID)
-	return err
+	if err != nil {
+		return err
+	}
+	sched.setNextRun(time.Now())
+	return nil
 }
 
 // Get retrieves a schedule by ID.
@@ -125,6 +164,7 @@ func (s *ScheduleStore) Get(ctx context.Context, id string) (*Schedule, error) {
 		sched.LastRunAt = &lastRunAt.Time
 	}
 	sched.LastRunID = lastRunID.String
+	sched.setNextRun(time.Now())
 	return &sched, nil
 }
 
@@ -157,6 +197,7 @@ func (s *ScheduleStore) List(ctx context.Context) ([]*Schedule, error) {
 			sched.LastRunAt = &lastRunAt.Time
 		}
 		sched.LastRunID = lastRunID.String
+		sched.setNextRun(time.Now())
 		schedules = append(schedules, &sched)
 	}
 	return schedules, rows.Err()
@@ -204,27 +245,39 @@ func (s *ScheduleStore) ListEnabled(ctx context.Context) ([]*Schedule, error) {
 			sched.LastRunAt = &lastRunAt.Time
 		}
 		sched.LastRunID = lastRunID.String
+		sched.setNextRun(time.Now())
 		schedules = append(schedules, &sched)
 	}
 	return schedules, rows.Err()
 }
 
+// RunStarter starts benchmark runs and reports their progress. *Runner
+// implements it.
+type RunStarter interface {
+	StartRun(ctx context.Context, req BenchmarkRunRequest) (*BenchmarkRun, error)
+	GetRun(ctx context.Context, runID string) (*BenchmarkRun, error)
+}
+
 // Scheduler checks cron schedules and triggers benchmark runs.
 type Scheduler struct {
-	runner *Runner
+	runner RunStarter
 	store  *ScheduleStore
 	logger *slog.Logger
 
 	mu     sync.Mutex
 	cancel context.CancelFunc
+
+	// For testing
+	now func() time.Time
 }
 
 // NewScheduler creates a new benchmark scheduler.
-func NewScheduler(runner *Runner, store *ScheduleStore, logger *slog.Logger) *Scheduler {
+func NewScheduler(runner RunStarter, store *ScheduleStore, logger *slog.Logger) *Scheduler {
 	return &Scheduler{
 		runner: runner,
 		store:  store,
 		logger: logger,
+		now:    time.Now,
 	}
 }
 
@@ -255,7 +308,8 @@ func (s *Scheduler) GetStore() *ScheduleStore {
 }
 
 func (s *Scheduler) run(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Minute)
+	// Check twice a minute so a late tick cannot skip a minute
+	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
 	for {
@@ -275,72 +329,67 @@ func (s *Scheduler) checkSchedules(ctx context.Context) {
 		return
 	}
 
-	now := time.Now()
+	now := s.now()
 	for _, sched := range schedules {
-		if shouldRun(sched, now) {
-			s.logger.Info("triggering scheduled benchmark",
+		if !shouldRun(sched, now) {
+			continue
+		}
+
+		// A slow matrix must not pile up runs on a frequent schedule
+		if s.lastRunActive(ctx, sched) {
+			s.logger.Warn("skipping scheduled benchmark, previous run still active",
 				slog.String("schedule_id", sched.ID),
-				slog.String("name", sched.Name))
-
-			run, err := s.runner.StartRun(ctx, sched.Request)
-			if err != nil {
-				s.logger.Error("failed to start scheduled benchmark",
-					slog.String("schedule_id", sched.ID),
-					slog.String("error", err.Error()))
-				continue
-			}
-
-			sched.LastRunID = run.ID
-			nowT := time.Now()
-			sched.LastRunAt = &nowT
-			if err := s.store.Update(ctx, sched); err != nil {
-				s.logger.Error("failed to update schedule after run",
-					slog.String("schedule_id", sched.ID),
-					slog.String("error", err.Error()))
-			}
+				slog.String("name", sched.Name),
+				slog.String("last_run_id", sched.LastRunID))
+			continue
 		}
-	}
-}
 
-// shouldRun checks if a schedule should trigger based on its cron expression.
-// Simplified cron: "minute hour day-of-month month day-of-week"
-// Supports: *, specific values, */N (step values).
-func shouldRun(sched *Schedule, now time.Time) bool {
-	// Don't re-run within the same minute
-	if sched.LastRunAt != nil {
-		if now.Sub(*sched.LastRunAt) < 2*time.Minute {
-			return false
+		s.logger.Info("triggering scheduled benchmark",
+			slog.String("schedule_id", sched.ID),
+			slog.String("name", sched.Name))
+
+		run, err := s.runner.StartRun(ctx, sched.Request)
+		if err != nil {
+			s.logger.Error("failed to start scheduled benchmark",
+				slog.String("schedule_id", sched.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		sched.LastRunID = run.ID
+		sched.LastRunAt = &now
+		if err := s.store.Update(ctx, sched); err != nil {
+			s.logger.Error("failed to update schedule after run",
+				slog.String("schedule_id", sched.ID),
+				slog.String("error", err.Error()))
 		}
 	}
+}
 
-	parts := strings.Fields(sched.CronExpr)
-	if len(parts) != 5 {
+// lastRunActive reports whether the schedule's previous run is still
+// pending or running. Runs from before a restart are not tracked and
+// count as finished.
+func (s *Scheduler) lastRunActive(ctx context.Context, sched *Schedule) bool {
+	if sched.LastRunID == "" {
 		return false
 	}
-
-	return matchCronField(parts[0], now.Minute()) &&
-		matchCronField(parts[1], now.Hour()) &&
-		matchCronField(parts[2], now.Day()) &&
-		matchCronField(parts[3], int(now.Month())) &&
-		matchCronField(parts[4], int(now.Weekday()))
+	run, err := s.runner.GetRun(ctx, sched.LastRunID)
+	if err != nil {
+		return false
+	}
+	return run.Status == RunStatusPending || run.Status == RunStatusRunning
 }
 
-// matchCronField checks if a value matches a cron field.
-// Supports: "*" (any), "N" (exact), "*/N" (divisible by N).
-func matchCronField(field string, value int) bool {
-	if field == "*" {
-		return true
-	}
-	if strings.HasPrefix(field, "*/") {
-		divisor, err := strconv.Atoi(strings.TrimPrefix(field, "*/"))
-		if err != nil || divisor <= 0 {
-			return false
-		}
-		return value%divisor == 0
+// shouldRun checks if a schedule should trigger in the minute containing now.
+func shouldRun(sched *Schedule, now time.Time) bool {
+	// Don't re-run within the same minute
+	if sched.LastRunAt != nil && !now.Truncate(time.Minute).After(sched.LastRunAt.Truncate(time.Minute)) {
+		return false
 	}
-	expected, err := strconv.Atoi(field)
+
+	cron, err := ParseCron(sched.CronExpr)
 	if err != nil {
 		return false
 	}
-	return value == expected
+	return cron.Matches(now)
 }
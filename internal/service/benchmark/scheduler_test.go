@@ -0,0 +1,169 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRunStarter struct {
+	mu     sync.Mutex
+	starts []BenchmarkRunRequest
+	status BenchmarkRunStatus
+}
+
+func (m *mockRunStarter) StartRun(ctx context.Context, req BenchmarkRunRequest) (*BenchmarkRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.starts = append(m.starts, req)
+	return &BenchmarkRun{ID: "run-test", Status: RunStatusPending, Request: req}, nil
+}
+
+func (m *mockRunStarter) GetRun(ctx context.Context, runID string) (*BenchmarkRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &BenchmarkRun{ID: runID, Status: m.status}, nil
+}
+
+func setupTestScheduleStore(t *testing.T) *ScheduleStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := NewScheduleStore(db)
+	require.NoError(t, err)
+	return store
+}
+
+func TestParseCron(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", s)
+		require.NoError(t, err)
+		return tm
+	}
+
+	tests := []struct {
+		expr  string
+		time  string
+		match bool
+	}{
+		{"* * * * *", "2026-03-04 05:06", true},
+		{"0 0 * * 0", "2026-03-08 00:00", true}, // Sunday
+		{"0 0 * * 7", "2026-03-08 00:00", true}, // Sunday as 7
+		{"0 0 * * 0", "2026-03-09 00:00", false},
+		{"*/15 * * * *", "2026-03-04 05:45", true},
+		{"*/15 * * * *", "2026-03-04 05:46", false},
+		{"0 9-17 * * 1-5", "2026-03-04 12:00", true},  // Wednesday
+		{"0 9-17 * * 1-5", "2026-03-07 12:00", false}, // Saturday
+		{"0,30 6 * * *", "2026-03-04 06:30", true},
+		{"0-30/10 * * * *", "2026-03-04 06:20", true},
+		{"0-30/10 * * * *", "2026-03-04 06:40", false},
+		{"5/20 * * * *", "2026-03-04 06:45", true},
+		// Both day fields restricted: either may match
+		{"0 0 1 * 1", "2026-03-01 00:00", true},
+		{"0 0 1 * 1", "2026-03-02 00:00", true},
+		{"0 0 1 * 1", "2026-03-03 00:00", false},
+	}
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.match, cron.Matches(at(tt.time)), "%s at %s", tt.expr, tt.time)
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2026, 3, 4, 5, 6, 30, 0, time.UTC) // Wednesday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 5, 7, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"30 2 1 * *", time.Date(2026, 4, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, cron.Next(from), tt.expr)
+	}
+
+	never, err := ParseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.Next(from).IsZero())
+}
+
+func TestScheduler_CheckSchedules(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestScheduleStore(t)
+	runner := &mockRunStarter{status: RunStatusRunning}
+
+	sched := &Schedule{
+		Name:     "hourly",
+		CronExpr: "0 * * * *",
+		Request:  BenchmarkRunRequest{Models: []string{"llama3.1:8b"}},
+		Enabled:  true,
+	}
+	require.NoError(t, store.Create(ctx, sched))
+
+	s := NewScheduler(runner, store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Date(2026, 3, 4, 5, 0, 10, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	// Fires once in a matching minute, even when checked twice
+	s.checkSchedules(ctx)
+	now = now.Add(30 * time.Second)
+	s.checkSchedules(ctx)
+	require.Len(t, runner.starts, 1)
+	assert.Equal(t, []string{"llama3.1:8b"}, runner.starts[0].Models)
+
+	got, err := store.Get(ctx, sched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "run-test", got.LastRunID)
+	require.NotNil(t, got.NextRunAt)
+
+	// The previous run is still going, so the next slot is skipped
+	now = now.Add(time.Hour)
+	s.checkSchedules(ctx)
+	assert.Len(t, runner.starts, 1)
+
+	runner.status = RunStatusCompleted
+	now = now.Add(time.Hour)
+	s.checkSchedules(ctx)
+	assert.Len(t, runner.starts, 2)
+
+	// Disabled schedules never fire
+	got.Enabled = false
+	require.NoError(t, store.Update(ctx, got))
+	now = now.Add(time.Hour)
+	s.checkSchedules(ctx)
+	assert.Len(t, runner.starts, 2)
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	valid := Schedule{Name: "weekly", CronExpr: "0 0 * * 0", Request: BenchmarkRunRequest{Models: []string{"llama3.1:8b"}}}
+	assert.NoError(t, valid.Validate())
+
+	noModels := valid
+	noModels.Request = BenchmarkRunRequest{}
+	assert.Error(t, noModels.Validate())
+
+	badCron := valid
+	badCron.CronExpr = "weekly"
+	assert.Error(t, badCron.Validate())
+}
@@ -0,0 +1,32 @@
+package budget
+
+import (
+	"fmt"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ExceededError indicates a new session would push spend over a budget cap
+type ExceededError struct {
+	BudgetID      string
+	Scope         models.BudgetScope
+	ScopeID       string
+	Period        models.BudgetPeriod
+	Limit         float64
+	Spend         float64 // Recorded spend plus committed cost of active sessions
+	ProjectedCost float64 // Cost of the rejected request
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s budget for %s %s would be exceeded: $%.2f spent or committed + $%.2f requested > $%.2f limit",
+		e.Period, e.Scope, e.ScopeID, e.Spend, e.ProjectedCost, e.Limit)
+}
+
+// InvalidBudgetError indicates a budget definition failed validation
+type InvalidBudgetError struct {
+	Reason string
+}
+
+func (e *InvalidBudgetError) Error() string {
+	return fmt.Sprintf("invalid budget: %s", e.Reason)
+}
@@ -0,0 +1,552 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// DefaultCheckInterval is how often budget thresholds are evaluated
+	DefaultCheckInterval = 5 * time.Minute
+
+	// DefaultWarningThreshold is the utilization at which a warning alert is sent (80%)
+	DefaultWarningThreshold = 0.80
+
+	// DefaultDeploymentScopeID is the scope ID used for deployment budgets
+	// when no deployment ID is configured
+	DefaultDeploymentScopeID = "default"
+)
+
+// Store defines the interface for budget persistence
+type Store interface {
+	Upsert(ctx context.Context, budget *models.Budget) error
+	Get(ctx context.Context, id string) (*models.Budget, error)
+	List(ctx context.Context) ([]*models.Budget, error)
+	ListForScope(ctx context.Context, scope models.BudgetScope, scopeID string) ([]*models.Budget, error)
+	Delete(ctx context.Context, id string) error
+	MarkAlerted(ctx context.Context, id, alertType string, at time.Time) error
+}
+
+// CostStore defines the interface for recorded spend queries
+type CostStore interface {
+	GetConsumerCost(ctx context.Context, consumerID string, start, end time.Time) (float64, error)
+	GetTotalCost(ctx context.Context, start, end time.Time) (float64, error)
+}
+
+// SessionStore defines the interface for active session queries
+type SessionStore interface {
+	GetActiveSessions(ctx context.Context) ([]*models.Session, error)
+}
+
+// AlertSender sends budget alerts
+type AlertSender interface {
+	SendBudgetAlert(ctx context.Context, alert models.BudgetAlert) error
+}
+
+// MultiAlertSender sends each alert to every sender. An error from any sender
+// leaves the alert unmarked, so senders that succeeded may receive it again.
+type MultiAlertSender []AlertSender
+
+// SendBudgetAlert sends the alert to every sender and joins their errors
+func (m MultiAlertSender) SendBudgetAlert(ctx context.Context, alert models.BudgetAlert) error {
+	var errs []error
+	for _, sender := range m {
+		if err := sender.SendBudgetAlert(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Service enforces spend caps and raises threshold alerts
+type Service struct {
+	store        Store
+	costStore    CostStore
+	sessionStore SessionStore
+	alertSender  AlertSender // Optional: alerts are still logged and counted without one
+	logger       *slog.Logger
+
+	// Configuration
+	deploymentID     string
+	checkInterval    time.Duration
+	warningThreshold float64
+
+	// For time mocking in tests
+	now func() time.Time
+
+	// Scopes held by ReserveBudget, keyed by scope and scope ID
+	scopeMu    sync.Mutex
+	scopeLocks map[string]*scopeLock
+
+	// Shutdown coordination
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// Option configures the budget service
+type Option func(*Service)
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithAlertSender sets the alert sender
+func WithAlertSender(sender AlertSender) Option {
+	return func(s *Service) {
+		s.alertSender = sender
+	}
+}
+
+// WithDeploymentID sets the scope ID that deployment budgets are matched against
+func WithDeploymentID(id string) Option {
+	return func(s *Service) {
+		s.deploymentID = id
+	}
+}
+
+// WithCheckInterval sets how often thresholds are evaluated
+func WithCheckInterval(d time.Duration) Option {
+	return func(s *Service) {
+		s.checkInterval = d
+	}
+}
+
+// WithWarningThreshold sets the utilization at which a warning alert is sent
+func WithWarningThreshold(threshold float64) Option {
+	return func(s *Service) {
+		s.warningThreshold = threshold
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(s *Service) {
+		s.now = fn
+	}
+}
+
+// New creates a new budget service
+func New(store Store, costStore CostStore, sessionStore SessionStore, opts ...Option) *Service {
+	s := &Service{
+		store:            store,
+		costStore:        costStore,
+		sessionStore:     sessionStore,
+		logger:           slog.Default(),
+		deploymentID:     DefaultDeploymentScopeID,
+		checkInterval:    DefaultCheckInterval,
+		warningThreshold: DefaultWarningThreshold,
+		now:              time.Now,
+		scopeLocks:       make(map[string]*scopeLock),
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// DeploymentID returns the scope ID used for deployment budgets
+func (s *Service) DeploymentID() string {
+	return s.deploymentID
+}
+
+// SetBudget validates and creates or updates a budget
+func (s *Service) SetBudget(ctx context.Context, budget *models.Budget) error {
+	if !budget.Scope.IsValid() {
+		return &InvalidBudgetError{Reason: fmt.Sprintf("unknown scope %q", budget.Scope)}
+	}
+	if !budget.Period.IsValid() {
+		return &InvalidBudgetError{Reason: fmt.Sprintf("unknown period %q", budget.Period)}
+	}
+	if budget.LimitUSD <= 0 {
+		return &InvalidBudgetError{Reason: "limit_usd must be positive"}
+	}
+	if budget.Scope == models.BudgetScopeDeployment && budget.ScopeID == "" {
+		budget.ScopeID = s.deploymentID
+	}
+	if budget.ScopeID == "" {
+		return &InvalidBudgetError{Reason: "scope_id is required for consumer budgets"}
+	}
+
+	if err := s.store.Upsert(ctx, budget); err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+
+	s.logger.Info("budget set",
+		slog.String("budget_id", budget.ID),
+		slog.String("scope", string(budget.Scope)),
+		slog.String("scope_id", budget.ScopeID),
+		slog.String("period", string(budget.Period)),
+		slog.Float64("limit_usd", budget.LimitUSD))
+
+	return nil
+}
+
+// GetBudget returns a budget by ID
+func (s *Service) GetBudget(ctx context.Context, id string) (*models.Budget, error) {
+	return s.store.Get(ctx, id)
+}
+
+// ListBudgets returns all budgets
+func (s *Service) ListBudgets(ctx context.Context) ([]*models.Budget, error) {
+	return s.store.List(ctx)
+}
+
+// DeleteBudget removes a budget
+func (s *Service) DeleteBudget(ctx context.Context, id string) error {
+	return s.store.Delete(ctx, id)
+}
+
+// CheckBudget returns an *ExceededError if starting work with the given
+// projected cost would push the consumer, or the deployment as a whole, over
+// any of its budgets. Spend counts recorded costs in the current period plus
+// the remaining reserved hours of active sessions. Two checks running at once
+// see the same spend; use ReserveBudget to start work.
+func (s *Service) CheckBudget(ctx context.Context, consumerID string, projectedCost float64) error {
+	budgets, err := s.applicableBudgets(ctx, consumerID)
+	if err != nil {
+		return err
+	}
+	return s.checkBudgets(ctx, budgets, consumerID, projectedCost)
+}
+
+// ReserveBudget is CheckBudget for work about to start. On success it holds
+// the consumer's budgets, and the deployment's if it has any, until release
+// is called, which the caller does once the session is stored and counts
+// towards spend. Concurrent sessions against the same budgets are thus
+// checked one at a time and cannot jointly overrun a cap.
+func (s *Service) ReserveBudget(ctx context.Context, consumerID string, projectedCost float64) (release func(), err error) {
+	// The consumer's scope is always taken before the deployment's
+	unlockConsumer := s.lockScope(string(models.BudgetScopeConsumer) + ":" + consumerID)
+	budgets, err := s.applicableBudgets(ctx, consumerID)
+	if err != nil {
+		unlockConsumer()
+		return nil, err
+	}
+	unlockDeployment := func() {}
+	for _, b := range budgets {
+		if b.Scope == models.BudgetScopeDeployment {
+			unlockDeployment = s.lockScope(string(models.BudgetScopeDeployment) + ":" + s.deploymentID)
+			break
+		}
+	}
+	release = func() {
+		unlockDeployment()
+		unlockConsumer()
+	}
+
+	if err := s.checkBudgets(ctx, budgets, consumerID, projectedCost); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// scopeLock serializes ReserveBudget calls on one budget scope
+type scopeLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockScope locks the scope under key, returning its unlock func. Locks are
+// dropped once nothing holds or waits for them.
+func (s *Service) lockScope(key string) func() {
+	s.scopeMu.Lock()
+	l, ok := s.scopeLocks[key]
+	if !ok {
+		l = &scopeLock{}
+		s.scopeLocks[key] = l
+	}
+	l.refs++
+	s.scopeMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		s.scopeMu.Lock()
+		defer s.scopeMu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(s.scopeLocks, key)
+		}
+	}
+}
+
+// checkBudgets returns an *ExceededError if projectedCost would push spend
+// over any of budgets
+func (s *Service) checkBudgets(ctx context.Context, budgets []*models.Budget, consumerID string, projectedCost float64) error {
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	sessions, err := s.sessionStore.GetActiveSessions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active sessions: %w", err)
+	}
+
+	for _, b := range budgets {
+		status, err := s.status(ctx, b, sessions)
+		if err != nil {
+			return err
+		}
+		if status.Spend+status.Committed+projectedCost > b.LimitUSD {
+			s.logger.Warn("session rejected by budget",
+				slog.String("budget_id", b.ID),
+				slog.String("scope", string(b.Scope)),
+				slog.String("scope_id", b.ScopeID),
+				slog.String("consumer_id", consumerID),
+				slog.Float64("limit_usd", b.LimitUSD),
+				slog.Float64("spend", status.Spend+status.Committed),
+				slog.Float64("projected_cost", projectedCost))
+			metrics.RecordBudgetRejection(string(b.Scope))
+			return &ExceededError{
+				BudgetID:      b.ID,
+				Scope:         b.Scope,
+				ScopeID:       b.ScopeID,
+				Period:        b.Period,
+				Limit:         b.LimitUSD,
+				Spend:         status.Spend + status.Committed,
+				ProjectedCost: projectedCost,
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetStatus returns spend against every budget for the current period
+func (s *Service) GetStatus(ctx context.Context) ([]*models.BudgetStatus, error) {
+	budgets, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	if len(budgets) == 0 {
+		return []*models.BudgetStatus{}, nil
+	}
+
+	sessions, err := s.sessionStore.GetActiveSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sessions: %w", err)
+	}
+
+	statuses := make([]*models.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status, err := s.status(ctx, b, sessions)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// applicableBudgets returns the consumer's budgets and the deployment's budgets
+func (s *Service) applicableBudgets(ctx context.Context, consumerID string) ([]*models.Budget, error) {
+	consumerBudgets, err := s.store.ListForScope(ctx, models.BudgetScopeConsumer, consumerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer budgets: %w", err)
+	}
+	deploymentBudgets, err := s.store.ListForScope(ctx, models.BudgetScopeDeployment, s.deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment budgets: %w", err)
+	}
+	return append(consumerBudgets, deploymentBudgets...), nil
+}
+
+// status computes spend against a budget for the period containing now
+func (s *Service) status(ctx context.Context, b *models.Budget, activeSessions []*models.Session) (*models.BudgetStatus, error) {
+	now := s.now()
+	start, end := b.Period.Bounds(now)
+
+	var spend float64
+	var err error
+	if b.Scope == models.BudgetScopeDeployment {
+		spend, err = s.costStore.GetTotalCost(ctx, start, end)
+	} else {
+		spend, err = s.costStore.GetConsumerCost(ctx, b.ScopeID, start, end)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spend for budget %s: %w", b.ID, err)
+	}
+
+	var committed float64
+	for _, session := range activeSessions {
+		if b.Scope == models.BudgetScopeConsumer && session.ConsumerID != b.ScopeID {
+			continue
+		}
+		committed += committedCost(session, now)
+	}
+
+	status := &models.BudgetStatus{
+		Budget:      *b,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Spend:       spend,
+		Committed:   committed,
+		Remaining:   b.LimitUSD - spend - committed,
+	}
+	if b.LimitUSD > 0 {
+		status.Utilization = (spend + committed) / b.LimitUSD
+	}
+
+	return status, nil
+}
+
+// committedCost is the cost of the reserved time a session has not yet been billed for.
+// Cost records are written per started hour, so the current hour is already counted.
+func committedCost(session *models.Session, now time.Time) float64 {
+	if session.ExpiresAt.IsZero() || !session.ExpiresAt.After(now) {
+		return 0
+	}
+	billedThrough := now.Truncate(time.Hour).Add(time.Hour)
+	if !session.ExpiresAt.After(billedThrough) {
+		return 0
+	}
+	return session.ExpiresAt.Sub(billedThrough).Hours() * session.PricePerHour
+}
+
+// Start begins the threshold alert loop
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = true
+	s.stopCh = make(chan struct{})
+	s.doneCh = make(chan struct{})
+	s.mu.Unlock()
+
+	s.logger.Info("budget service starting",
+		slog.Duration("check_interval", s.checkInterval),
+		slog.Float64("warning_threshold", s.warningThreshold))
+
+	go s.run(ctx)
+	return nil
+}
+
+// Stop gracefully stops the budget service
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	stopCh := s.stopCh
+	doneCh := s.doneCh
+	s.mu.Unlock()
+
+	s.logger.Info("budget service stopping")
+	close(stopCh)
+	<-doneCh
+
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+
+	s.logger.Info("budget service stopped")
+}
+
+// run is the main threshold check loop
+func (s *Service) run(ctx context.Context) {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.CheckThresholds(ctx)
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckThresholds updates utilization metrics and sends warning and exceeded
+// alerts. Each alert type is sent at most once per budget period.
+func (s *Service) CheckThresholds(ctx context.Context) {
+	statuses, err := s.GetStatus(ctx)
+	if err != nil {
+		s.logger.Error("failed to compute budget status",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, status := range statuses {
+		b := status.Budget
+		metrics.UpdateBudgetUtilization(string(b.Scope), b.ScopeID, string(b.Period), status.Utilization)
+
+		switch {
+		case status.Utilization >= 1.0:
+			if b.ExceededSentAt.Before(status.PeriodStart) {
+				s.sendAlert(ctx, status, "exceeded")
+			}
+		case status.Utilization >= s.warningThreshold:
+			if b.WarningSentAt.Before(status.PeriodStart) {
+				s.sendAlert(ctx, status, "warning")
+			}
+		}
+	}
+}
+
+// sendAlert logs, counts, dispatches and records a threshold alert
+func (s *Service) sendAlert(ctx context.Context, status *models.BudgetStatus, alertType string) {
+	b := status.Budget
+	now := s.now()
+
+	s.logger.Warn("budget threshold reached",
+		slog.String("budget_id", b.ID),
+		slog.String("scope", string(b.Scope)),
+		slog.String("scope_id", b.ScopeID),
+		slog.String("period", string(b.Period)),
+		slog.String("alert_type", alertType),
+		slog.Float64("limit_usd", b.LimitUSD),
+		slog.Float64("utilization", status.Utilization))
+
+	metrics.RecordBudgetAlert(alertType)
+
+	if s.alertSender != nil {
+		alert := models.BudgetAlert{
+			ConsumerID:   b.ScopeID,
+			BudgetLimit:  b.LimitUSD,
+			CurrentSpend: status.Spend + status.Committed,
+			Percentage:   status.Utilization * 100,
+			AlertType:    alertType,
+			Timestamp:    now,
+			Scope:        b.Scope,
+			Period:       b.Period,
+		}
+		if err := s.alertSender.SendBudgetAlert(ctx, alert); err != nil {
+			// Leave the alert unmarked so it is retried on the next check
+			s.logger.Error("failed to send budget alert",
+				slog.String("budget_id", b.ID),
+				slog.String("error", err.Error()))
+			return
+		}
+	}
+
+	if err := s.store.MarkAlerted(ctx, b.ID, alertType, now); err != nil {
+		s.logger.Error("failed to record budget alert",
+			slog.String("budget_id", b.ID),
+			slog.String("error", err.Error()))
+	}
+}
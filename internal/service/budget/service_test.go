@@ -0,0 +1,383 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("not found")
+
+// mockStore implements Store for testing
+type mockStore struct {
+	mu      sync.Mutex
+	budgets map[string]*models.Budget
+	nextID  int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{budgets: make(map[string]*models.Budget)}
+}
+
+func (m *mockStore) Upsert(ctx context.Context, budget *models.Budget) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, b := range m.budgets {
+		if b.Scope == budget.Scope && b.ScopeID == budget.ScopeID && b.Period == budget.Period {
+			b.LimitUSD = budget.LimitUSD
+			*budget = *b
+			return nil
+		}
+	}
+	m.nextID++
+	budget.ID = fmt.Sprintf("budget-%d", m.nextID)
+	stored := *budget
+	m.budgets[budget.ID] = &stored
+	return nil
+}
+
+func (m *mockStore) Get(ctx context.Context, id string) (*models.Budget, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.budgets[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	copied := *b
+	return &copied, nil
+}
+
+func (m *mockStore) List(ctx context.Context) ([]*models.Budget, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Budget
+	for _, b := range m.budgets {
+		copied := *b
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (m *mockStore) ListForScope(ctx context.Context, scope models.BudgetScope, scopeID string) ([]*models.Budget, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Budget
+	for _, b := range m.budgets {
+		if b.Scope == scope && b.ScopeID == scopeID {
+			copied := *b
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.budgets[id]; !ok {
+		return errNotFound
+	}
+	delete(m.budgets, id)
+	return nil
+}
+
+func (m *mockStore) MarkAlerted(ctx context.Context, id, alertType string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.budgets[id]
+	if !ok {
+		return errNotFound
+	}
+	if alertType == "warning" {
+		b.WarningSentAt = at
+	} else {
+		b.ExceededSentAt = at
+	}
+	return nil
+}
+
+// mockCostStore returns fixed spend figures
+type mockCostStore struct {
+	byConsumer map[string]float64
+	total      float64
+}
+
+func (m *mockCostStore) GetConsumerCost(ctx context.Context, consumerID string, start, end time.Time) (float64, error) {
+	return m.byConsumer[consumerID], nil
+}
+
+func (m *mockCostStore) GetTotalCost(ctx context.Context, start, end time.Time) (float64, error) {
+	return m.total, nil
+}
+
+// mockSessionStore returns a fixed set of active sessions
+type mockSessionStore struct {
+	sessions []*models.Session
+}
+
+func (m *mockSessionStore) GetActiveSessions(ctx context.Context) ([]*models.Session, error) {
+	return m.sessions, nil
+}
+
+// mockAlertSender records sent alerts
+type mockAlertSender struct {
+	mu     sync.Mutex
+	alerts []models.BudgetAlert
+	err    error
+}
+
+func (m *mockAlertSender) SendBudgetAlert(ctx context.Context, alert models.BudgetAlert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.alerts = append(m.alerts, alert)
+	return nil
+}
+
+// fixedNow is mid-hour so committed cost math is easy to follow
+var fixedNow = time.Date(2026, 3, 18, 10, 30, 0, 0, time.UTC)
+
+func newTestService(costs *mockCostStore, sessions *mockSessionStore, opts ...Option) (*Service, *mockStore) {
+	store := newMockStore()
+	opts = append([]Option{WithTimeFunc(func() time.Time { return fixedNow })}, opts...)
+	return New(store, costs, sessions, opts...), store
+}
+
+func TestSetBudget_Validation(t *testing.T) {
+	svc, _ := newTestService(&mockCostStore{}, &mockSessionStore{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		budget models.Budget
+	}{
+		{"bad scope", models.Budget{Scope: "team", ScopeID: "x", Period: models.BudgetPeriodDaily, LimitUSD: 1}},
+		{"bad period", models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "x", Period: "yearly", LimitUSD: 1}},
+		{"zero limit", models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "x", Period: models.BudgetPeriodDaily}},
+		{"missing consumer", models.Budget{Scope: models.BudgetScopeConsumer, Period: models.BudgetPeriodDaily, LimitUSD: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.budget
+			err := svc.SetBudget(ctx, &b)
+			var invalid *InvalidBudgetError
+			assert.ErrorAs(t, err, &invalid)
+		})
+	}
+}
+
+func TestSetBudget_DeploymentDefaultsScopeID(t *testing.T) {
+	svc, _ := newTestService(&mockCostStore{}, &mockSessionStore{}, WithDeploymentID("deploy-1"))
+
+	b := &models.Budget{Scope: models.BudgetScopeDeployment, Period: models.BudgetPeriodMonthly, LimitUSD: 500}
+	require.NoError(t, svc.SetBudget(context.Background(), b))
+	assert.Equal(t, "deploy-1", b.ScopeID)
+}
+
+func TestCheckBudget_NoBudgets(t *testing.T) {
+	svc, _ := newTestService(&mockCostStore{}, &mockSessionStore{})
+	assert.NoError(t, svc.CheckBudget(context.Background(), "consumer-1", 1000))
+}
+
+func TestCheckBudget_ConsumerCap(t *testing.T) {
+	costs := &mockCostStore{byConsumer: map[string]float64{"consumer-1": 8}}
+	svc, _ := newTestService(costs, &mockSessionStore{})
+	ctx := context.Background()
+
+	require.NoError(t, svc.SetBudget(ctx, &models.Budget{
+		Scope: models.BudgetScopeConsumer, ScopeID: "consumer-1", Period: models.BudgetPeriodDaily, LimitUSD: 10,
+	}))
+
+	// $8 spent + $2 requested == limit: allowed
+	assert.NoError(t, svc.CheckBudget(ctx, "consumer-1", 2))
+
+	// $8 spent + $3 requested > limit: rejected
+	err := svc.CheckBudget(ctx, "consumer-1", 3)
+	var exceeded *ExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, models.BudgetScopeConsumer, exceeded.Scope)
+	assert.Equal(t, 10.0, exceeded.Limit)
+	assert.Equal(t, 8.0, exceeded.Spend)
+	assert.Equal(t, 3.0, exceeded.ProjectedCost)
+
+	// Other consumers are unaffected
+	assert.NoError(t, svc.CheckBudget(ctx, "consumer-2", 3))
+}
+
+func TestCheckBudget_CountsCommittedSessions(t *testing.T) {
+	// Session at $1/hr expiring 4.5h from now; the current hour is already billed,
+	// leaving 4h of committed cost.
+	sessions := &mockSessionStore{sessions: []*models.Session{{
+		ID: "sess-1", ConsumerID: "consumer-1", PricePerHour: 1.0,
+		Status: models.StatusRunning, ExpiresAt: fixedNow.Add(4*time.Hour + 30*time.Minute),
+	}}}
+	svc, _ := newTestService(&mockCostStore{}, sessions)
+	ctx := context.Background()
+
+	require.NoError(t, svc.SetBudget(ctx, &models.Budget{
+		Scope: models.BudgetScopeConsumer, ScopeID: "consumer-1", Period: models.BudgetPeriodWeekly, LimitUSD: 5,
+	}))
+
+	assert.NoError(t, svc.CheckBudget(ctx, "consumer-1", 1))
+
+	var exceeded *ExceededError
+	assert.ErrorAs(t, svc.CheckBudget(ctx, "consumer-1", 1.5), &exceeded)
+}
+
+func TestCheckBudget_DeploymentCap(t *testing.T) {
+	costs := &mockCostStore{total: 95}
+	svc, _ := newTestService(costs, &mockSessionStore{})
+	ctx := context.Background()
+
+	require.NoError(t, svc.SetBudget(ctx, &models.Budget{
+		Scope: models.BudgetScopeDeployment, Period: models.BudgetPeriodMonthly, LimitUSD: 100,
+	}))
+
+	var exceeded *ExceededError
+	require.ErrorAs(t, svc.CheckBudget(ctx, "anyone", 10), &exceeded)
+	assert.Equal(t, models.BudgetScopeDeployment, exceeded.Scope)
+	assert.Equal(t, DefaultDeploymentScopeID, exceeded.ScopeID)
+}
+
+func TestReserveBudget_HoldsScopeUntilReleased(t *testing.T) {
+	svc, _ := newTestService(&mockCostStore{total: 10}, &mockSessionStore{})
+	ctx := context.Background()
+	require.NoError(t, svc.SetBudget(ctx, &models.Budget{
+		Scope: models.BudgetScopeDeployment, Period: models.BudgetPeriodMonthly, LimitUSD: 100,
+	}))
+
+	release, err := svc.ReserveBudget(ctx, "consumer-1", 10)
+	require.NoError(t, err)
+
+	// Another consumer waits on the deployment budget
+	reserved := make(chan struct{})
+	go func() {
+		release, err := svc.ReserveBudget(ctx, "consumer-2", 10)
+		if assert.NoError(t, err) {
+			release()
+		}
+		close(reserved)
+	}()
+	select {
+	case <-reserved:
+		t.Fatal("second reservation did not wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case <-reserved:
+	case <-time.After(time.Second):
+		t.Fatal("second reservation did not proceed after release")
+	}
+
+	// A rejected reservation holds nothing
+	_, err = svc.ReserveBudget(ctx, "consumer-1", 95)
+	var exceeded *ExceededError
+	require.ErrorAs(t, err, &exceeded)
+	release, err = svc.ReserveBudget(ctx, "consumer-1", 1)
+	require.NoError(t, err)
+	release()
+	assert.Empty(t, svc.scopeLocks)
+}
+
+func TestGetStatus(t *testing.T) {
+	costs := &mockCostStore{byConsumer: map[string]float64{"consumer-1": 4}}
+	svc, _ := newTestService(costs, &mockSessionStore{})
+	ctx := context.Background()
+
+	require.NoError(t, svc.SetBudget(ctx, &models.Budget{
+		Scope: models.BudgetScopeConsumer, ScopeID: "consumer-1", Period: models.BudgetPeriodDaily, LimitUSD: 10,
+	}))
+
+	statuses, err := svc.GetStatus(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, 4.0, statuses[0].Spend)
+	assert.Equal(t, 6.0, statuses[0].Remaining)
+	assert.InDelta(t, 0.4, statuses[0].Utilization, 0.001)
+	assert.Equal(t, time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC), statuses[0].PeriodStart)
+}
+
+func TestCheckThresholds_SendsEachAlertOncePerPeriod(t *testing.T) {
+	costs := &mockCostStore{byConsumer: map[string]float64{"consumer-1": 8.5}}
+	sender := &mockAlertSender{}
+	svc, store := newTestService(costs, &mockSessionStore{}, WithAlertSender(sender))
+	ctx := context.Background()
+
+	b := &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "consumer-1", Period: models.BudgetPeriodDaily, LimitUSD: 10}
+	require.NoError(t, svc.SetBudget(ctx, b))
+
+	svc.CheckThresholds(ctx)
+	svc.CheckThresholds(ctx)
+
+	require.Len(t, sender.alerts, 1)
+	assert.Equal(t, "warning", sender.alerts[0].AlertType)
+	assert.Equal(t, models.BudgetScopeConsumer, sender.alerts[0].Scope)
+	assert.InDelta(t, 85.0, sender.alerts[0].Percentage, 0.001)
+
+	stored, err := store.Get(ctx, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fixedNow, stored.WarningSentAt)
+
+	// Crossing 100% raises a separate exceeded alert
+	costs.byConsumer["consumer-1"] = 12
+	svc.CheckThresholds(ctx)
+	require.Len(t, sender.alerts, 2)
+	assert.Equal(t, "exceeded", sender.alerts[1].AlertType)
+}
+
+func TestCheckThresholds_RetriesFailedSend(t *testing.T) {
+	costs := &mockCostStore{byConsumer: map[string]float64{"consumer-1": 9}}
+	sender := &mockAlertSender{err: errors.New("webhook down")}
+	svc, store := newTestService(costs, &mockSessionStore{}, WithAlertSender(sender))
+	ctx := context.Background()
+
+	b := &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "consumer-1", Period: models.BudgetPeriodDaily, LimitUSD: 10}
+	require.NoError(t, svc.SetBudget(ctx, b))
+
+	svc.CheckThresholds(ctx)
+	stored, err := store.Get(ctx, b.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.WarningSentAt.IsZero())
+
+	sender.err = nil
+	svc.CheckThresholds(ctx)
+	assert.Len(t, sender.alerts, 1)
+}
+
+func TestWebhookAlertSender(t *testing.T) {
+	var received models.BudgetAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookAlertSender(server.URL)
+	err := sender.SendBudgetAlert(context.Background(), models.BudgetAlert{ConsumerID: "consumer-1", AlertType: "warning"})
+	require.NoError(t, err)
+	assert.Equal(t, "consumer-1", received.ConsumerID)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	assert.Error(t, NewWebhookAlertSender(failing.URL).SendBudgetAlert(context.Background(), models.BudgetAlert{}))
+}
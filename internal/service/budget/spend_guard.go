@@ -0,0 +1,391 @@
+package budget
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// AlertTypeSpendCeiling is the alert type sent when the spend guard trips
+const AlertTypeSpendCeiling = "spend_ceiling"
+
+// ProviderLookup resolves and enumerates the configured providers
+type ProviderLookup interface {
+	Get(name string) (provider.Provider, error)
+	List() []string
+}
+
+// SummaryStore defines the interface for recorded spend by provider
+type SummaryStore interface {
+	GetSummary(ctx context.Context, query models.CostQuery) (*models.CostSummary, error)
+}
+
+// SessionDestroyer terminates sessions when the spend ceiling is exceeded
+type SessionDestroyer interface {
+	DestroySession(ctx context.Context, sessionID string) error
+}
+
+// SpendGuard is a kill switch driven by provider-reported spend rather than
+// our own estimates. Each check polls the balance of every provider that
+// implements provider.BalanceProvider; drops in balance since the previous
+// check count as spend for the month, rises (deposits) are ignored. When the
+// reported spend across providers reaches the ceiling, every active session
+// is destroyed and an alert is sent. The guard stays tripped until the next
+// month, destroying any session that is still active on each check.
+//
+// Balances are only observed while the guard runs, so spend during downtime
+// is not counted.
+type SpendGuard struct {
+	providers    ProviderLookup
+	costStore    SummaryStore
+	sessionStore SessionStore
+	destroyer    SessionDestroyer
+	alertSender  AlertSender // Optional: the trip is still logged without one
+	logger       *slog.Logger
+
+	// Configuration
+	deploymentID  string
+	ceiling       float64
+	checkInterval time.Duration
+
+	// For time mocking in tests
+	now func() time.Time
+
+	// Spend state for the current month
+	checkMu      sync.Mutex // Serializes checks
+	stateMu      sync.RWMutex
+	lastBalance  map[string]float64
+	reported     map[string]float64
+	status       models.SpendCeilingStatus
+	alertPending bool
+
+	// Shutdown coordination
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// SpendGuardOption configures the spend guard
+type SpendGuardOption func(*SpendGuard)
+
+// WithGuardLogger sets a custom logger
+func WithGuardLogger(logger *slog.Logger) SpendGuardOption {
+	return func(g *SpendGuard) {
+		g.logger = logger
+	}
+}
+
+// WithGuardAlertSender sets the sender notified when the guard trips
+func WithGuardAlertSender(sender AlertSender) SpendGuardOption {
+	return func(g *SpendGuard) {
+		g.alertSender = sender
+	}
+}
+
+// WithGuardDeploymentID sets the scope ID reported in spend ceiling alerts
+func WithGuardDeploymentID(id string) SpendGuardOption {
+	return func(g *SpendGuard) {
+		g.deploymentID = id
+	}
+}
+
+// WithSpendCeiling sets the monthly provider-reported spend, in USD, at which
+// every session is terminated. Zero disables the kill switch; spend is still
+// reconciled.
+func WithSpendCeiling(ceiling float64) SpendGuardOption {
+	return func(g *SpendGuard) {
+		g.ceiling = ceiling
+	}
+}
+
+// WithGuardCheckInterval sets how often provider balances are polled
+func WithGuardCheckInterval(d time.Duration) SpendGuardOption {
+	return func(g *SpendGuard) {
+		g.checkInterval = d
+	}
+}
+
+// WithGuardTimeFunc sets a custom time function (for testing)
+func WithGuardTimeFunc(fn func() time.Time) SpendGuardOption {
+	return func(g *SpendGuard) {
+		g.now = fn
+	}
+}
+
+// NewSpendGuard creates a new spend guard
+func NewSpendGuard(providers ProviderLookup, costStore SummaryStore, sessionStore SessionStore, destroyer SessionDestroyer, opts ...SpendGuardOption) *SpendGuard {
+	g := &SpendGuard{
+		providers:     providers,
+		costStore:     costStore,
+		sessionStore:  sessionStore,
+		destroyer:     destroyer,
+		logger:        slog.Default(),
+		deploymentID:  DefaultDeploymentScopeID,
+		checkInterval: DefaultCheckInterval,
+		now:           time.Now,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.status.CeilingUSD = g.ceiling
+	return g
+}
+
+// Start takes an initial balance reading and begins the check loop
+func (g *SpendGuard) Start(ctx context.Context) error {
+	g.mu.Lock()
+	if g.running {
+		g.mu.Unlock()
+		return nil
+	}
+	g.running = true
+	g.stopCh = make(chan struct{})
+	g.doneCh = make(chan struct{})
+	g.mu.Unlock()
+
+	g.logger.Info("spend guard starting",
+		slog.Duration("check_interval", g.checkInterval),
+		slog.Float64("ceiling_usd", g.ceiling))
+
+	go g.run(ctx)
+	return nil
+}
+
+// Stop gracefully stops the spend guard
+func (g *SpendGuard) Stop() {
+	g.mu.Lock()
+	if !g.running {
+		g.mu.Unlock()
+		return
+	}
+	stopCh := g.stopCh
+	doneCh := g.doneCh
+	g.mu.Unlock()
+
+	g.logger.Info("spend guard stopping")
+	close(stopCh)
+	<-doneCh
+
+	g.mu.Lock()
+	g.running = false
+	g.mu.Unlock()
+
+	g.logger.Info("spend guard stopped")
+}
+
+// run is the main check loop
+func (g *SpendGuard) run(ctx context.Context) {
+	defer close(g.doneCh)
+
+	// The first check records the baseline balances
+	g.Check(ctx)
+
+	ticker := time.NewTicker(g.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.Check(ctx)
+		case <-g.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Status returns the result of the last check
+func (g *SpendGuard) Status() *models.SpendCeilingStatus {
+	g.stateMu.RLock()
+	defer g.stateMu.RUnlock()
+
+	status := g.status
+	status.Providers = append([]models.ProviderSpend(nil), g.status.Providers...)
+	if g.status.TrippedAt != nil {
+		at := *g.status.TrippedAt
+		status.TrippedAt = &at
+	}
+	return &status
+}
+
+// Check reconciles estimated against provider-reported spend, trips the kill
+// switch when reported spend reaches the ceiling and, while tripped, destroys
+// every active session
+func (g *SpendGuard) Check(ctx context.Context) {
+	g.checkMu.Lock()
+	defer g.checkMu.Unlock()
+
+	now := g.now()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	g.stateMu.Lock()
+	if !g.status.PeriodStart.Equal(periodStart) {
+		// New month: balances already seen stay as the baseline
+		g.reported = make(map[string]float64)
+		if g.lastBalance == nil {
+			g.lastBalance = make(map[string]float64)
+		}
+		g.status = models.SpendCeilingStatus{CeilingUSD: g.ceiling, PeriodStart: periodStart}
+		g.alertPending = false
+	}
+	g.stateMu.Unlock()
+
+	estimated := map[string]float64{}
+	summary, err := g.costStore.GetSummary(ctx, models.CostQuery{
+		StartTime: periodStart,
+		EndTime:   periodStart.AddDate(0, 1, 0),
+	})
+	if err != nil {
+		g.logger.Error("failed to get estimated spend",
+			slog.String("error", err.Error()))
+	} else if summary.ByProvider != nil {
+		estimated = summary.ByProvider
+	}
+
+	names := g.providers.List()
+	sort.Strings(names)
+
+	providers := make([]models.ProviderSpend, 0, len(names))
+	var totalEstimated, totalReported float64
+	for _, name := range names {
+		ps := g.checkProvider(ctx, name)
+		ps.EstimatedSpend = estimated[name]
+		if ps.BillingSupport {
+			ps.Discrepancy = ps.ReportedSpend - ps.EstimatedSpend
+		}
+		totalEstimated += ps.EstimatedSpend
+		totalReported += ps.ReportedSpend
+		providers = append(providers, ps)
+	}
+
+	g.stateMu.Lock()
+	g.status.CheckedAt = now
+	g.status.EstimatedSpend = totalEstimated
+	g.status.ReportedSpend = totalReported
+	g.status.Providers = providers
+	if g.ceiling > 0 && totalReported >= g.ceiling && !g.status.Tripped {
+		g.status.Tripped = true
+		g.status.TrippedAt = &now
+		g.alertPending = true
+		g.logger.Error("provider-reported spend reached the spend ceiling; terminating all sessions",
+			slog.Float64("ceiling_usd", g.ceiling),
+			slog.Float64("reported_spend", totalReported),
+			slog.Float64("estimated_spend", totalEstimated))
+	}
+	tripped := g.status.Tripped
+	g.stateMu.Unlock()
+
+	if !tripped {
+		return
+	}
+
+	terminated := g.terminateAll(ctx)
+	g.stateMu.Lock()
+	g.status.SessionsTerminated += terminated
+	sendAlert := g.alertPending
+	g.stateMu.Unlock()
+
+	if sendAlert {
+		g.sendAlert(ctx, totalReported, now)
+	}
+}
+
+// checkProvider polls one provider's balance and accumulates any drop
+func (g *SpendGuard) checkProvider(ctx context.Context, name string) models.ProviderSpend {
+	ps := models.ProviderSpend{Provider: name}
+
+	prov, err := g.providers.Get(name)
+	if err != nil {
+		ps.Error = err.Error()
+		return ps
+	}
+	bp, ok := provider.As[provider.BalanceProvider](prov)
+	if !ok {
+		return ps
+	}
+	ps.BillingSupport = true
+
+	balance, err := bp.GetAccountBalance(ctx)
+
+	g.stateMu.Lock()
+	defer g.stateMu.Unlock()
+
+	if err != nil {
+		g.logger.Warn("failed to get provider balance for spend guard",
+			slog.String("provider", name),
+			slog.String("error", err.Error()))
+		ps.Error = err.Error()
+	} else {
+		if last, seen := g.lastBalance[name]; seen && balance.Balance < last {
+			g.reported[name] += last - balance.Balance
+		}
+		g.lastBalance[name] = balance.Balance
+		b := balance.Balance
+		ps.Balance = &b
+	}
+	ps.ReportedSpend = g.reported[name]
+	return ps
+}
+
+// terminateAll destroys every active session and returns how many were
+// destroyed. Failures are retried on the next check.
+func (g *SpendGuard) terminateAll(ctx context.Context) int {
+	sessions, err := g.sessionStore.GetActiveSessions(ctx)
+	if err != nil {
+		g.logger.Error("spend guard failed to list active sessions",
+			slog.String("error", err.Error()))
+		return 0
+	}
+
+	terminated := 0
+	for _, session := range sessions {
+		if err := g.destroyer.DestroySession(ctx, session.ID); err != nil {
+			g.logger.Error("spend guard failed to destroy session",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		g.logger.Warn("spend guard destroyed session",
+			slog.String("session_id", session.ID),
+			slog.String("provider", session.Provider))
+		terminated++
+	}
+	return terminated
+}
+
+// sendAlert notifies the alert sender that the guard tripped. A failed send
+// is retried on the next check.
+func (g *SpendGuard) sendAlert(ctx context.Context, reported float64, now time.Time) {
+	if g.alertSender != nil {
+		alert := models.BudgetAlert{
+			ConsumerID:   g.deploymentID,
+			BudgetLimit:  g.ceiling,
+			CurrentSpend: reported,
+			Percentage:   reported / g.ceiling * 100,
+			AlertType:    AlertTypeSpendCeiling,
+			Timestamp:    now,
+			Scope:        models.BudgetScopeDeployment,
+			Period:       models.BudgetPeriodMonthly,
+		}
+		if err := g.alertSender.SendBudgetAlert(ctx, alert); err != nil {
+			g.logger.Error("failed to send spend ceiling alert",
+				slog.String("error", err.Error()))
+			return
+		}
+	}
+
+	g.stateMu.Lock()
+	g.alertPending = false
+	g.stateMu.Unlock()
+}
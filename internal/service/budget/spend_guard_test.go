@@ -0,0 +1,182 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBalanceProvider reports a settable account balance
+type mockBalanceProvider struct {
+	provider.Provider
+	balance float64
+	err     error
+}
+
+func (m *mockBalanceProvider) GetAccountBalance(ctx context.Context) (*provider.AccountBalance, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &provider.AccountBalance{Balance: m.balance, Currency: "USD"}, nil
+}
+
+// mockProviderLookup resolves providers from a map
+type mockProviderLookup map[string]provider.Provider
+
+func (m mockProviderLookup) Get(name string) (provider.Provider, error) {
+	p, ok := m[name]
+	if !ok {
+		return nil, errNotFound
+	}
+	return p, nil
+}
+
+func (m mockProviderLookup) List() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
+// mockSummaryStore returns fixed recorded spend by provider
+type mockSummaryStore struct {
+	byProvider map[string]float64
+}
+
+func (m *mockSummaryStore) GetSummary(ctx context.Context, query models.CostQuery) (*models.CostSummary, error) {
+	return &models.CostSummary{ByProvider: m.byProvider}, nil
+}
+
+// mockDestroyer records destroyed sessions
+type mockDestroyer struct {
+	mu        sync.Mutex
+	destroyed []string
+}
+
+func (m *mockDestroyer) DestroySession(ctx context.Context, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.destroyed = append(m.destroyed, sessionID)
+	return nil
+}
+
+func TestSpendGuard_ReconcilesReportedSpend(t *testing.T) {
+	vast := &mockBalanceProvider{balance: 100}
+	providers := mockProviderLookup{
+		"vastai":     vast,
+		"tensordock": &mockBalanceProvider{err: errors.New("unavailable")},
+		"static":     struct{ provider.Provider }{},
+	}
+	costs := &mockSummaryStore{byProvider: map[string]float64{"vastai": 12, "static": 3}}
+	destroyer := &mockDestroyer{}
+	guard := NewSpendGuard(providers, costs, &mockSessionStore{}, destroyer,
+		WithGuardTimeFunc(func() time.Time { return fixedNow }))
+	ctx := context.Background()
+
+	guard.Check(ctx) // baseline
+	vast.balance = 85
+	guard.Check(ctx)
+	vast.balance = 135 // deposit is not spend
+	guard.Check(ctx)
+	vast.balance = 130
+	guard.Check(ctx)
+
+	status := guard.Status()
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), status.PeriodStart)
+	assert.InDelta(t, 20.0, status.ReportedSpend, 0.001)
+	assert.InDelta(t, 15.0, status.EstimatedSpend, 0.001)
+	assert.False(t, status.Tripped, "no ceiling configured")
+	require.Len(t, status.Providers, 3)
+
+	static, tensordock, vastai := status.Providers[0], status.Providers[1], status.Providers[2]
+	assert.Equal(t, "static", static.Provider)
+	assert.False(t, static.BillingSupport)
+	assert.Zero(t, static.Discrepancy)
+
+	assert.Equal(t, "tensordock", tensordock.Provider)
+	assert.True(t, tensordock.BillingSupport)
+	assert.Equal(t, "unavailable", tensordock.Error)
+
+	assert.Equal(t, "vastai", vastai.Provider)
+	assert.InDelta(t, 20.0, vastai.ReportedSpend, 0.001)
+	assert.InDelta(t, 8.0, vastai.Discrepancy, 0.001)
+	require.NotNil(t, vastai.Balance)
+	assert.Equal(t, 130.0, *vastai.Balance)
+	assert.Empty(t, destroyer.destroyed)
+}
+
+func TestSpendGuard_TripsAtCeiling(t *testing.T) {
+	vast := &mockBalanceProvider{balance: 100}
+	sessions := &mockSessionStore{sessions: []*models.Session{{ID: "sess-1"}, {ID: "sess-2"}}}
+	destroyer := &mockDestroyer{}
+	alerts := &mockAlertSender{}
+	now := fixedNow
+	guard := NewSpendGuard(mockProviderLookup{"vastai": vast}, &mockSummaryStore{}, sessions, destroyer,
+		WithSpendCeiling(50),
+		WithGuardAlertSender(alerts),
+		WithGuardDeploymentID("deploy-1"),
+		WithGuardTimeFunc(func() time.Time { return now }))
+	ctx := context.Background()
+
+	guard.Check(ctx)
+	vast.balance = 60
+	guard.Check(ctx)
+	assert.False(t, guard.Status().Tripped, "$40 is under the ceiling")
+	assert.Empty(t, destroyer.destroyed)
+
+	vast.balance = 45
+	guard.Check(ctx)
+
+	status := guard.Status()
+	assert.True(t, status.Tripped)
+	require.NotNil(t, status.TrippedAt)
+	assert.Equal(t, 2, status.SessionsTerminated)
+	assert.Equal(t, []string{"sess-1", "sess-2"}, destroyer.destroyed)
+	require.Len(t, alerts.alerts, 1)
+	assert.Equal(t, AlertTypeSpendCeiling, alerts.alerts[0].AlertType)
+	assert.Equal(t, "deploy-1", alerts.alerts[0].ConsumerID)
+	assert.InDelta(t, 55.0, alerts.alerts[0].CurrentSpend, 0.001)
+
+	// Still tripped: sessions started since are destroyed, the alert is not repeated
+	sessions.sessions = []*models.Session{{ID: "sess-3"}}
+	guard.Check(ctx)
+	assert.Equal(t, []string{"sess-1", "sess-2", "sess-3"}, destroyer.destroyed)
+	assert.Len(t, alerts.alerts, 1)
+
+	// A new month resets the reported spend and the switch
+	now = time.Date(2026, 4, 1, 0, 5, 0, 0, time.UTC)
+	guard.Check(ctx)
+	status = guard.Status()
+	assert.False(t, status.Tripped)
+	assert.Zero(t, status.ReportedSpend)
+	assert.Len(t, destroyer.destroyed, 3)
+}
+
+func TestSpendGuard_RetriesFailedAlert(t *testing.T) {
+	vast := &mockBalanceProvider{balance: 100}
+	alerts := &mockAlertSender{err: errors.New("webhook down")}
+	guard := NewSpendGuard(mockProviderLookup{"vastai": vast}, &mockSummaryStore{}, &mockSessionStore{}, &mockDestroyer{},
+		WithSpendCeiling(10),
+		WithGuardAlertSender(alerts),
+		WithGuardTimeFunc(func() time.Time { return fixedNow }))
+	ctx := context.Background()
+
+	guard.Check(ctx)
+	vast.balance = 80
+	guard.Check(ctx)
+	assert.Empty(t, alerts.alerts)
+
+	alerts.err = nil
+	guard.Check(ctx)
+	assert.Len(t, alerts.alerts, 1)
+	guard.Check(ctx)
+	assert.Len(t, alerts.alerts, 1)
+}
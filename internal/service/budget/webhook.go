@@ -0,0 +1,55 @@
+package budget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// DefaultWebhookTimeout bounds a single webhook delivery
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookAlertSender posts budget alerts as JSON to a fixed URL
+type WebhookAlertSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookAlertSender creates an alert sender that posts to url
+func NewWebhookAlertSender(url string) *WebhookAlertSender {
+	return &WebhookAlertSender{
+		url:    url,
+		client: &http.Client{Timeout: DefaultWebhookTimeout},
+	}
+}
+
+// SendBudgetAlert posts the alert and fails on any non-2xx response
+func (w *WebhookAlertSender) SendBudgetAlert(ctx context.Context, alert models.BudgetAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode budget alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send budget webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("budget webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ForecastSession returns what the session has cost so far and what it will
+// cost at its current hourly price by expiry, and by expiry after extending
+// it by extendHours. Hours are billed whole as they start, so the current hour
+// counts as accrued and projection begins at the next hour boundary. An ended
+// session projects no further cost.
+func (t *Tracker) ForecastSession(ctx context.Context, session *models.Session, extendHours int) (*models.SessionCostForecast, error) {
+	accrued, err := t.costStore.GetSessionCost(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost for session %s: %w", session.ID, err)
+	}
+
+	forecast := &models.SessionCostForecast{
+		SessionID:           session.ID,
+		ConsumerID:          session.ConsumerID,
+		Status:              session.Status,
+		ExpiresAt:           session.ExpiresAt,
+		AccruedCost:         accrued,
+		ProjectedAtExpiry:   accrued,
+		ExtendHours:         extendHours,
+		ProjectedIfExtended: accrued,
+		Currency:            "USD",
+	}
+	if !session.IsActive() {
+		return forecast, nil
+	}
+
+	forecast.BurnRate = session.PricePerHour
+	forecast.ProjectedAtExpiry += session.PricePerHour * unbilledHours(t.now(), session.ExpiresAt)
+	forecast.ProjectedIfExtended = forecast.ProjectedAtExpiry + session.PricePerHour*float64(extendHours)
+	return forecast, nil
+}
+
+// ForecastFleet projects spend across every active session, or only those of
+// consumerID when set. Today's projection adds what active sessions will be
+// billed until midnight (or their expiry, if sooner) to what has already been
+// recorded today.
+func (t *Tracker) ForecastFleet(ctx context.Context, consumerID string) (*models.FleetCostForecast, error) {
+	sessions, err := t.sessionStore.GetActiveSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sessions: %w", err)
+	}
+	today, err := t.GetDailySummary(ctx, consumerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's cost: %w", err)
+	}
+
+	now := t.now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	fleet := &models.FleetCostForecast{
+		ConsumerID:     consumerID,
+		GeneratedAt:    now,
+		SpentToday:     today.TotalCost,
+		ProjectedToday: today.TotalCost,
+		Sessions:       []models.SessionCostForecast{},
+		Currency:       "USD",
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	for _, session := range sessions {
+		if consumerID != "" && session.ConsumerID != consumerID {
+			continue
+		}
+		forecast, err := t.ForecastSession(ctx, session, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		until := midnight
+		if session.ExpiresAt.Before(until) {
+			until = session.ExpiresAt
+		}
+
+		fleet.ActiveSessions++
+		fleet.BurnRate += forecast.BurnRate
+		fleet.ProjectedToday += forecast.BurnRate * unbilledHours(now, until)
+		fleet.ProjectedAtExpiry += forecast.ProjectedAtExpiry
+		fleet.Sessions = append(fleet.Sessions, *forecast)
+	}
+
+	return fleet, nil
+}
+
+// unbilledHours returns the hours from the next hour boundary after now until
+// end, or zero when end comes first
+func unbilledHours(now, end time.Time) float64 {
+	from := now.Truncate(time.Hour).Add(time.Hour)
+	if !end.After(from) {
+		return 0
+	}
+	return end.Sub(from).Hours()
+}
@@ -0,0 +1,105 @@
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newForecastTracker returns a tracker at 20:30 with two running sessions and
+// one that stopped earlier today:
+//   - sess-a (team-a): $2/h, billed 18:00-20:00, expires 23:30
+//   - sess-b (team-b): $1/h, billed 20:00, expires 02:00 tomorrow
+//   - sess-c (team-a): stopped, billed $4 at 10:00
+func newForecastTracker(t *testing.T) (*Tracker, *mockSessionStore, time.Time) {
+	now := time.Date(2026, 3, 18, 20, 30, 0, 0, time.UTC)
+	costStore := newMockCostStoreWithTime(func() time.Time { return now })
+	sessionStore := newMockSessionStore()
+	ctx := context.Background()
+
+	record := func(sessionID, consumerID string, hour int, amount float64) {
+		require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+			SessionID: sessionID, ConsumerID: consumerID, Provider: "vastai",
+			Hour: time.Date(2026, 3, 18, hour, 0, 0, 0, time.UTC), Amount: amount,
+		}))
+	}
+
+	sessionStore.add(&models.Session{
+		ID: "sess-a", ConsumerID: "team-a", Status: models.StatusRunning, PricePerHour: 2.00,
+		ExpiresAt: time.Date(2026, 3, 18, 23, 30, 0, 0, time.UTC),
+	})
+	record("sess-a", "team-a", 18, 2.00)
+	record("sess-a", "team-a", 19, 2.00)
+	record("sess-a", "team-a", 20, 2.00)
+
+	sessionStore.add(&models.Session{
+		ID: "sess-b", ConsumerID: "team-b", Status: models.StatusRunning, PricePerHour: 1.00,
+		ExpiresAt: time.Date(2026, 3, 19, 2, 0, 0, 0, time.UTC),
+	})
+	record("sess-b", "team-b", 20, 1.00)
+
+	sessionStore.add(&models.Session{
+		ID: "sess-c", ConsumerID: "team-a", Status: models.StatusStopped, PricePerHour: 4.00,
+		ExpiresAt: time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC),
+	})
+	record("sess-c", "team-a", 10, 4.00)
+
+	tracker := New(costStore, sessionStore, nil, WithTimeFunc(func() time.Time { return now }))
+	return tracker, sessionStore, now
+}
+
+func TestTracker_ForecastSession(t *testing.T) {
+	tracker, sessionStore, _ := newForecastTracker(t)
+	ctx := context.Background()
+
+	running, err := sessionStore.Get(ctx, "sess-a")
+	require.NoError(t, err)
+	forecast, err := tracker.ForecastSession(ctx, running, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 2.00, forecast.BurnRate)
+	assert.InDelta(t, 6.00, forecast.AccruedCost, 0.0001)
+	assert.InDelta(t, 11.00, forecast.ProjectedAtExpiry, 0.0001, "21:00-23:30 at $2/h")
+	assert.Equal(t, 3, forecast.ExtendHours)
+	assert.InDelta(t, 17.00, forecast.ProjectedIfExtended, 0.0001)
+	assert.Equal(t, "USD", forecast.Currency)
+
+	stopped, err := sessionStore.Get(ctx, "sess-c")
+	require.NoError(t, err)
+	forecast, err = tracker.ForecastSession(ctx, stopped, 3)
+	require.NoError(t, err)
+	assert.Zero(t, forecast.BurnRate)
+	assert.InDelta(t, 4.00, forecast.AccruedCost, 0.0001)
+	assert.InDelta(t, 4.00, forecast.ProjectedAtExpiry, 0.0001)
+	assert.InDelta(t, 4.00, forecast.ProjectedIfExtended, 0.0001)
+}
+
+func TestTracker_ForecastFleet(t *testing.T) {
+	tracker, _, now := newForecastTracker(t)
+	ctx := context.Background()
+
+	fleet, err := tracker.ForecastFleet(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, now, fleet.GeneratedAt)
+	assert.Equal(t, 2, fleet.ActiveSessions)
+	assert.InDelta(t, 3.00, fleet.BurnRate, 0.0001)
+	assert.InDelta(t, 11.00, fleet.SpentToday, 0.0001)
+	// sess-a until its 23:30 expiry, sess-b until midnight
+	assert.InDelta(t, 19.00, fleet.ProjectedToday, 0.0001)
+	assert.InDelta(t, 17.00, fleet.ProjectedAtExpiry, 0.0001)
+	require.Len(t, fleet.Sessions, 2)
+	assert.Equal(t, "sess-a", fleet.Sessions[0].SessionID)
+	assert.Equal(t, "sess-b", fleet.Sessions[1].SessionID)
+
+	fleet, err = tracker.ForecastFleet(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", fleet.ConsumerID)
+	assert.Equal(t, 1, fleet.ActiveSessions)
+	assert.InDelta(t, 2.00, fleet.BurnRate, 0.0001)
+	assert.InDelta(t, 10.00, fleet.SpentToday, 0.0001)
+	assert.InDelta(t, 15.00, fleet.ProjectedToday, 0.0001)
+	assert.InDelta(t, 11.00, fleet.ProjectedAtExpiry, 0.0001)
+}
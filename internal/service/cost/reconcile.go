@@ -0,0 +1,203 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// recordProviderUsage snapshots what the session's provider reports it has
+// billed, when the provider supports it. The last snapshot outlives the
+// instance, so ended sessions can still be reconciled.
+func (t *Tracker) recordProviderUsage(ctx context.Context, session *models.Session) {
+	if t.usageStore == nil || t.providers == nil || session.ProviderID == "" {
+		return
+	}
+	prov, err := t.providers.Get(session.Provider)
+	if err != nil {
+		return
+	}
+	up, ok := provider.As[provider.UsageProvider](prov)
+	if !ok {
+		return
+	}
+
+	usage, err := up.GetInstanceUsage(ctx, session.ProviderID)
+	if err != nil {
+		t.logger.Debug("could not get provider usage for session",
+			slog.String("session_id", session.ID),
+			slog.String("provider", session.Provider),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	if err := t.usageStore.UpsertProviderUsage(ctx, &models.ProviderUsage{
+		SessionID:  session.ID,
+		Provider:   session.Provider,
+		HourlyRate: usage.HourlyRate,
+		Total:      usage.Total,
+		ObservedAt: t.now(),
+	}); err != nil {
+		t.logger.Error("failed to record provider usage",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()))
+		t.metrics.mu.Lock()
+		t.metrics.Errors++
+		t.metrics.mu.Unlock()
+	}
+}
+
+// Reconcile compares the recorded cost of every session billed in
+// [start, end) with the cost its provider reports. Sessions are compared over
+// their whole lifetime; a session is flagged when the two differ by more than
+// the discrepancy threshold. Sessions whose provider does not report usage
+// are listed as unverified.
+func (t *Tracker) Reconcile(ctx context.Context, start, end time.Time) (*models.CostReconciliation, error) {
+	records, err := t.costStore.List(ctx, models.CostQuery{StartTime: start, EndTime: end})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cost records: %w", err)
+	}
+
+	// One entry per session, described by its first record
+	entries := make(map[string]*models.CostReconciliationEntry)
+	for _, r := range records {
+		if _, ok := entries[r.SessionID]; ok {
+			continue
+		}
+		entries[r.SessionID] = &models.CostReconciliationEntry{
+			SessionID:  r.SessionID,
+			ConsumerID: r.ConsumerID,
+			Provider:   r.Provider,
+			GPUType:    r.GPUType,
+		}
+	}
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	now := t.now()
+	report := &models.CostReconciliation{
+		GeneratedAt:  now,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		ThresholdPct: t.discrepancyThreshold * 100,
+		Sessions:     make([]models.CostReconciliationEntry, 0, len(ids)),
+	}
+
+	for _, id := range ids {
+		entry := entries[id]
+
+		recorded, err := t.costStore.GetSessionCost(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cost for session %s: %w", id, err)
+		}
+		entry.RecordedCost = recorded
+		report.Recorded += recorded
+
+		reported, observedAt, err := t.reportedCost(ctx, id, now)
+		if err != nil {
+			return nil, err
+		}
+		if observedAt.IsZero() {
+			report.Unverified++
+			report.Sessions = append(report.Sessions, *entry)
+			continue
+		}
+
+		entry.ReportedCost = &reported
+		entry.ObservedAt = &observedAt
+		entry.Discrepancy = recorded - reported
+		switch {
+		case reported > 0:
+			entry.DiscrepancyPct = math.Abs(entry.Discrepancy) / reported * 100
+		case recorded > 0:
+			entry.DiscrepancyPct = 100
+		}
+		entry.Flagged = entry.DiscrepancyPct > report.ThresholdPct
+
+		report.Verified++
+		report.Reported += reported
+		if entry.Flagged {
+			report.Flagged++
+		}
+		report.Sessions = append(report.Sessions, *entry)
+	}
+
+	return report, nil
+}
+
+// reportedCost returns the session's provider-reported cost through when it
+// stopped (or now) and when usage was last observed, which is zero when it
+// never was. Cost after the observation is extended at the reported rate.
+func (t *Tracker) reportedCost(ctx context.Context, sessionID string, now time.Time) (float64, time.Time, error) {
+	if t.usageStore == nil {
+		return 0, time.Time{}, nil
+	}
+	usage, err := t.usageStore.GetProviderUsage(ctx, sessionID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get provider usage for session %s: %w", sessionID, err)
+	}
+
+	through := now
+	if session, err := t.sessionStore.Get(ctx, sessionID); err == nil && !session.StoppedAt.IsZero() {
+		through = session.StoppedAt
+	}
+
+	reported := usage.Total
+	if through.After(usage.ObservedAt) {
+		reported += usage.HourlyRate * through.Sub(usage.ObservedAt).Hours()
+	}
+	return reported, usage.ObservedAt, nil
+}
+
+// runReconciliation reconciles the sessions billed since the previous run and
+// logs every flagged session
+func (t *Tracker) runReconciliation(ctx context.Context) {
+	end := t.now()
+	report, err := t.Reconcile(ctx, end.Add(-t.reconciliationInterval), end)
+	if err != nil {
+		t.logger.Error("cost reconciliation failed",
+			slog.String("error", err.Error()))
+		t.metrics.mu.Lock()
+		t.metrics.Errors++
+		t.metrics.mu.Unlock()
+		return
+	}
+
+	t.metrics.mu.Lock()
+	t.metrics.Reconciliations++
+	t.metrics.Discrepancies += int64(report.Flagged)
+	t.metrics.mu.Unlock()
+
+	for _, entry := range report.Sessions {
+		if !entry.Flagged {
+			continue
+		}
+		t.logger.Warn("recorded cost differs from provider-reported cost",
+			slog.String("session_id", entry.SessionID),
+			slog.String("provider", entry.Provider),
+			slog.Float64("recorded_cost", entry.RecordedCost),
+			slog.Float64("reported_cost", *entry.ReportedCost),
+			slog.Float64("discrepancy_pct", entry.DiscrepancyPct))
+	}
+
+	t.logger.Info("cost reconciliation complete",
+		slog.Int("sessions", len(report.Sessions)),
+		slog.Int("verified", report.Verified),
+		slog.Int("flagged", report.Flagged),
+		slog.Float64("recorded_total", report.Recorded),
+		slog.Float64("reported_total", report.Reported))
+}
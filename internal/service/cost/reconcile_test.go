@@ -0,0 +1,169 @@
+package cost
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usageProvider reports settable usage; other Provider methods are unused
+type usageProvider struct {
+	provider.Provider
+	usage *provider.InstanceUsage
+}
+
+func (u *usageProvider) GetInstanceUsage(ctx context.Context, instanceID string) (*provider.InstanceUsage, error) {
+	copied := *u.usage
+	return &copied, nil
+}
+
+// mockUsageStore implements UsageStore for testing
+type mockUsageStore struct {
+	mu    sync.Mutex
+	usage map[string]models.ProviderUsage
+}
+
+func newMockUsageStore() *mockUsageStore {
+	return &mockUsageStore{usage: make(map[string]models.ProviderUsage)}
+}
+
+func (m *mockUsageStore) UpsertProviderUsage(ctx context.Context, usage *models.ProviderUsage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage[usage.SessionID] = *usage
+	return nil
+}
+
+func (m *mockUsageStore) GetProviderUsage(ctx context.Context, sessionID string) (*models.ProviderUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage, ok := m.usage[sessionID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &usage, nil
+}
+
+func TestTracker_RecordsProviderUsage(t *testing.T) {
+	now := time.Date(2026, 3, 18, 10, 15, 0, 0, time.UTC)
+	sessionStore := newMockSessionStore()
+	sessionStore.add(&models.Session{
+		ID:           "sess-1",
+		Provider:     "vastai",
+		ProviderID:   "12345",
+		Status:       models.StatusRunning,
+		PricePerHour: 0.40,
+	})
+	usageStore := newMockUsageStore()
+	prov := &usageProvider{usage: &provider.InstanceUsage{HourlyRate: 0.40, Total: 0.10}}
+
+	tracker := New(newMockCostStoreWithTime(func() time.Time { return now }), sessionStore, nil,
+		WithProviders(mockProviderLookup{"vastai": prov}),
+		WithUsageStore(usageStore),
+		WithTimeFunc(func() time.Time { return now }))
+
+	tracker.RunAggregationNow(context.Background())
+
+	usage, err := usageStore.GetProviderUsage(context.Background(), "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, "vastai", usage.Provider)
+	assert.Equal(t, 0.40, usage.HourlyRate)
+	assert.Equal(t, 0.10, usage.Total)
+	assert.Equal(t, now, usage.ObservedAt)
+}
+
+func TestTracker_Reconcile(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-24 * time.Hour)
+	costStore := newMockCostStoreWithTime(func() time.Time { return now })
+	sessionStore := newMockSessionStore()
+	usageStore := newMockUsageStore()
+	ctx := context.Background()
+
+	record := func(sessionID, provider string, hour time.Time, amount float64) {
+		require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+			SessionID: sessionID, ConsumerID: "consumer-001", Provider: provider,
+			GPUType: "RTX4090", Hour: hour, Amount: amount,
+		}))
+	}
+
+	// Matches: 2h at $0.50 recorded, provider billed $0.90 an hour before
+	// the session stopped and $0.10 more at its rate
+	sessionStore.add(&models.Session{ID: "sess-match", Status: models.StatusStopped, StoppedAt: now.Add(-2 * time.Hour)})
+	record("sess-match", "vastai", now.Add(-4*time.Hour), 0.50)
+	record("sess-match", "vastai", now.Add(-3*time.Hour), 0.50)
+	usageStore.usage["sess-match"] = models.ProviderUsage{
+		SessionID: "sess-match", HourlyRate: 0.10, Total: 0.90, ObservedAt: now.Add(-3 * time.Hour),
+	}
+
+	// Over-recorded: $3 recorded against $2 billed
+	sessionStore.add(&models.Session{ID: "sess-drift", Status: models.StatusStopped, StoppedAt: now.Add(-time.Hour)})
+	record("sess-drift", "vastai", now.Add(-2*time.Hour), 3.00)
+	usageStore.usage["sess-drift"] = models.ProviderUsage{
+		SessionID: "sess-drift", HourlyRate: 1.00, Total: 2.00, ObservedAt: now.Add(-time.Hour),
+	}
+
+	// No provider usage
+	record("sess-td", "tensordock", now.Add(-time.Hour), 0.75)
+
+	tracker := New(costStore, sessionStore, nil,
+		WithUsageStore(usageStore),
+		WithTimeFunc(func() time.Time { return now }))
+
+	report, err := tracker.Reconcile(ctx, start, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10.0, report.ThresholdPct)
+	assert.Equal(t, 2, report.Verified)
+	assert.Equal(t, 1, report.Unverified)
+	assert.Equal(t, 1, report.Flagged)
+	assert.InDelta(t, 4.75, report.Recorded, 0.0001)
+	assert.InDelta(t, 3.00, report.Reported, 0.0001)
+	require.Len(t, report.Sessions, 3)
+
+	drift, match, td := report.Sessions[0], report.Sessions[1], report.Sessions[2]
+	assert.Equal(t, "sess-drift", drift.SessionID)
+	require.NotNil(t, drift.ReportedCost)
+	assert.InDelta(t, 2.00, *drift.ReportedCost, 0.0001)
+	assert.InDelta(t, 1.00, drift.Discrepancy, 0.0001)
+	assert.InDelta(t, 50.0, drift.DiscrepancyPct, 0.0001)
+	assert.True(t, drift.Flagged)
+
+	assert.Equal(t, "sess-match", match.SessionID)
+	require.NotNil(t, match.ReportedCost)
+	assert.InDelta(t, 1.00, *match.ReportedCost, 0.0001)
+	assert.False(t, match.Flagged)
+
+	assert.Equal(t, "sess-td", td.SessionID)
+	assert.Equal(t, "tensordock", td.Provider)
+	assert.Nil(t, td.ReportedCost)
+	assert.False(t, td.Flagged)
+}
+
+func TestTracker_RunReconciliation_CountsDiscrepancies(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	costStore := newMockCostStoreWithTime(func() time.Time { return now })
+	usageStore := newMockUsageStore()
+	ctx := context.Background()
+
+	require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+		SessionID: "sess-1", Provider: "vastai", Hour: now.Add(-time.Hour), Amount: 1.00,
+	}))
+	usageStore.usage["sess-1"] = models.ProviderUsage{SessionID: "sess-1", Total: 0.50, ObservedAt: now}
+
+	tracker := New(costStore, newMockSessionStore(), nil,
+		WithUsageStore(usageStore),
+		WithTimeFunc(func() time.Time { return now }))
+	tracker.runReconciliation(ctx)
+
+	metrics := tracker.GetMetrics()
+	assert.Equal(t, int64(1), metrics.Reconciliations)
+	assert.Equal(t, int64(1), metrics.Discrepancies)
+}
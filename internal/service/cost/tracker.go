@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
@@ -20,6 +21,14 @@ const (
 
 	// DefaultBudgetExceededThreshold is the percentage at which to send exceeded alert (100%)
 	DefaultBudgetExceededThreshold = 1.0
+
+	// DefaultReconciliationInterval is how often recorded costs are reconciled
+	// against provider-reported usage
+	DefaultReconciliationInterval = 24 * time.Hour
+
+	// DefaultDiscrepancyThreshold is the relative difference between recorded
+	// and provider-reported cost above which a session is flagged (10%)
+	DefaultDiscrepancyThreshold = 0.10
 )
 
 // CostStore defines the interface for cost persistence
@@ -27,8 +36,11 @@ type CostStore interface {
 	Record(ctx context.Context, record *models.CostRecord) error
 	RecordHourlyForSession(ctx context.Context, session *models.Session) error
 	GetSessionCost(ctx context.Context, sessionID string) (float64, error)
+	GetSessionCategoryCost(ctx context.Context, sessionID string, category models.CostCategory, before time.Time) (float64, error)
 	GetConsumerCost(ctx context.Context, consumerID string, start, end time.Time) (float64, error)
 	GetSummary(ctx context.Context, query models.CostQuery) (*models.CostSummary, error)
+	GetBreakdown(ctx context.Context, groupBy []models.CostGroupBy, query models.CostQuery) ([]models.CostGroup, error)
+	List(ctx context.Context, query models.CostQuery) ([]models.CostRecord, error)
 }
 
 // SessionStore defines the interface for session queries
@@ -44,6 +56,17 @@ type ConsumerStore interface {
 	Update(ctx context.Context, consumer *models.Consumer) error
 }
 
+// UsageStore defines the interface for provider-reported usage persistence
+type UsageStore interface {
+	UpsertProviderUsage(ctx context.Context, usage *models.ProviderUsage) error
+	GetProviderUsage(ctx context.Context, sessionID string) (*models.ProviderUsage, error)
+}
+
+// ProviderLookup resolves a provider by name
+type ProviderLookup interface {
+	Get(name string) (provider.Provider, error)
+}
+
 // AlertSender sends budget alerts
 type AlertSender interface {
 	SendBudgetAlert(ctx context.Context, alert models.BudgetAlert) error
@@ -61,6 +84,8 @@ type Tracker struct {
 	costStore     CostStore
 	sessionStore  SessionStore
 	consumerStore ConsumerStore
+	providers     ProviderLookup // Optional: enables storage/bandwidth/IP line items
+	usageStore    UsageStore     // Optional: enables reconciliation against provider usage
 	alertSender   AlertSender
 	logger        *slog.Logger
 
@@ -68,6 +93,8 @@ type Tracker struct {
 	aggregationInterval     time.Duration
 	budgetWarningThreshold  float64
 	budgetExceededThreshold float64
+	reconciliationInterval  time.Duration
+	discrepancyThreshold    float64
 
 	// For time mocking in tests
 	now func() time.Time
@@ -89,6 +116,8 @@ type Metrics struct {
 	CostsRecorded   int64
 	BudgetWarnings  int64
 	BudgetExceeded  int64
+	Reconciliations int64
+	Discrepancies   int64
 	Errors          int64
 }
 
@@ -130,6 +159,37 @@ func WithAlertSender(sender AlertSender) Option {
 	}
 }
 
+// WithProviders enables recording provider-reported storage, bandwidth and IP
+// charges for providers that implement provider.ChargesProvider
+func WithProviders(providers ProviderLookup) Option {
+	return func(t *Tracker) {
+		t.providers = providers
+	}
+}
+
+// WithUsageStore enables snapshotting provider-reported usage for providers
+// that implement provider.UsageProvider, and the nightly reconciliation
+func WithUsageStore(store UsageStore) Option {
+	return func(t *Tracker) {
+		t.usageStore = store
+	}
+}
+
+// WithReconciliationInterval sets how often recorded costs are reconciled
+func WithReconciliationInterval(d time.Duration) Option {
+	return func(t *Tracker) {
+		t.reconciliationInterval = d
+	}
+}
+
+// WithDiscrepancyThreshold sets the relative difference at which a session is
+// flagged by reconciliation
+func WithDiscrepancyThreshold(threshold float64) Option {
+	return func(t *Tracker) {
+		t.discrepancyThreshold = threshold
+	}
+}
+
 // WithTimeFunc sets a custom time function (for testing)
 func WithTimeFunc(fn func() time.Time) Option {
 	return func(t *Tracker) {
@@ -148,6 +208,8 @@ func New(costStore CostStore, sessionStore SessionStore, consumerStore ConsumerS
 		aggregationInterval:     DefaultAggregationInterval,
 		budgetWarningThreshold:  DefaultBudgetWarningThreshold,
 		budgetExceededThreshold: DefaultBudgetExceededThreshold,
+		reconciliationInterval:  DefaultReconciliationInterval,
+		discrepancyThreshold:    DefaultDiscrepancyThreshold,
 		now:                     time.Now,
 		stopCh:                  make(chan struct{}),
 		doneCh:                  make(chan struct{}),
@@ -210,10 +272,15 @@ func (t *Tracker) run(ctx context.Context) {
 	ticker := time.NewTicker(t.aggregationInterval)
 	defer ticker.Stop()
 
+	reconcileTicker := time.NewTicker(t.reconciliationInterval)
+	defer reconcileTicker.Stop()
+
 	for {
 		select {
 		case <-ticker.C:
 			t.runAggregation(ctx)
+		case <-reconcileTicker.C:
+			t.runReconciliation(ctx)
 		case <-t.stopCh:
 			return
 		case <-ctx.Done():
@@ -275,6 +342,89 @@ func (t *Tracker) recordCostsForRunningSessions(ctx context.Context) {
 		t.metrics.mu.Lock()
 		t.metrics.CostsRecorded++
 		t.metrics.mu.Unlock()
+
+		t.recordProviderCharges(ctx, session)
+		t.recordProviderUsage(ctx, session)
+	}
+}
+
+// recordProviderCharges records storage, bandwidth and IP line items for the
+// current hour when the session's provider reports them. Hourly charges are
+// upserted like GPU-hours; bandwidth is reported cumulatively, so the current
+// hour is billed the difference from what earlier hours already recorded.
+func (t *Tracker) recordProviderCharges(ctx context.Context, session *models.Session) {
+	if t.providers == nil || session.ProviderID == "" {
+		return
+	}
+	prov, err := t.providers.Get(session.Provider)
+	if err != nil {
+		return
+	}
+	cp, ok := provider.As[provider.ChargesProvider](prov)
+	if !ok {
+		return
+	}
+
+	charges, err := cp.GetInstanceCharges(ctx, session.ProviderID)
+	if err != nil {
+		t.logger.Debug("could not get provider charges for session",
+			slog.String("session_id", session.ID),
+			slog.String("provider", session.Provider),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	hour := t.now().Truncate(time.Hour)
+
+	bandwidth := 0.0
+	if charges.BandwidthTotal > 0 {
+		billed, err := t.costStore.GetSessionCategoryCost(ctx, session.ID, models.CostCategoryBandwidth, hour)
+		if err != nil {
+			t.logger.Error("failed to get recorded bandwidth cost",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+			t.metrics.mu.Lock()
+			t.metrics.Errors++
+			t.metrics.mu.Unlock()
+		} else if charges.BandwidthTotal > billed {
+			bandwidth = charges.BandwidthTotal - billed
+		}
+	}
+
+	items := []struct {
+		category models.CostCategory
+		amount   float64
+	}{
+		{models.CostCategoryStorage, charges.StoragePerHour},
+		{models.CostCategoryBandwidth, bandwidth},
+		{models.CostCategoryIP, charges.IPPerHour},
+	}
+
+	for _, item := range items {
+		if item.amount <= 0 {
+			continue
+		}
+		record := &models.CostRecord{
+			SessionID:  session.ID,
+			ConsumerID: session.ConsumerID,
+			Provider:   session.Provider,
+			GPUType:    session.GPUType,
+			Category:   item.category,
+			Hour:       hour,
+			Amount:     item.amount,
+			Currency:   "USD",
+		}
+		if err := t.costStore.Record(ctx, record); err != nil {
+			t.logger.Error("failed to record provider charge",
+				slog.String("session_id", session.ID),
+				slog.String("category", string(item.category)),
+				slog.String("error", err.Error()))
+			t.metrics.mu.Lock()
+			t.metrics.Errors++
+			t.metrics.mu.Unlock()
+			continue
+		}
+		metrics.RecordCost(session.Provider, item.amount)
 	}
 }
 
@@ -422,6 +572,39 @@ func (t *Tracker) GetSummary(ctx context.Context, query models.CostQuery) (*mode
 	return t.costStore.GetSummary(ctx, query)
 }
 
+// GetBreakdown returns spend matching query grouped by the given dimensions
+// for chargeback. Deployment groups are labelled with deploymentID.
+func (t *Tracker) GetBreakdown(ctx context.Context, groupBy []models.CostGroupBy, query models.CostQuery, deploymentID string) (*models.CostBreakdown, error) {
+	groups, err := t.costStore.GetBreakdown(ctx, groupBy, query)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &models.CostBreakdown{
+		GroupBy:     groupBy,
+		PeriodStart: query.StartTime,
+		PeriodEnd:   query.EndTime,
+		Groups:      make([]models.CostGroup, 0, len(groups)),
+	}
+	byDeployment := false
+	for _, g := range groupBy {
+		byDeployment = byDeployment || g == models.CostGroupByDeployment
+	}
+	for _, group := range groups {
+		if byDeployment {
+			group.Deployment = deploymentID
+		}
+		breakdown.TotalCost += group.TotalCost
+		breakdown.Groups = append(breakdown.Groups, group)
+	}
+	return breakdown, nil
+}
+
+// ListRecords returns the hourly cost records matching query
+func (t *Tracker) ListRecords(ctx context.Context, query models.CostQuery) ([]models.CostRecord, error) {
+	return t.costStore.List(ctx, query)
+}
+
 // GetDailySummary returns cost summary for today
 func (t *Tracker) GetDailySummary(ctx context.Context, consumerID string) (*models.CostSummary, error) {
 	now := t.now()
@@ -472,6 +655,8 @@ func (t *Tracker) GetMetrics() Metrics {
 		CostsRecorded:   t.metrics.CostsRecorded,
 		BudgetWarnings:  t.metrics.BudgetWarnings,
 		BudgetExceeded:  t.metrics.BudgetExceeded,
+		Reconciliations: t.metrics.Reconciliations,
+		Discrepancies:   t.metrics.Discrepancies,
 		Errors:          t.metrics.Errors,
 	}
 }
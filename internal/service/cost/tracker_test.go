@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,6 +57,7 @@ func (m *mockCostStore) RecordHourlyForSession(ctx context.Context, session *mod
 		ConsumerID: session.ConsumerID,
 		Provider:   session.Provider,
 		GPUType:    session.GPUType,
+		Category:   models.CostCategoryGPU,
 		Hour:       m.now().Truncate(time.Hour),
 		Amount:     session.PricePerHour,
 		Currency:   "USD",
@@ -76,6 +78,18 @@ func (m *mockCostStore) GetSessionCost(ctx context.Context, sessionID string) (f
 	return total, nil
 }
 
+func (m *mockCostStore) GetSessionCategoryCost(ctx context.Context, sessionID string, category models.CostCategory, before time.Time) (float64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var total float64
+	for _, r := range m.records {
+		if r.SessionID == sessionID && r.Category == category && r.Hour.Before(before) {
+			total += r.Amount
+		}
+	}
+	return total, nil
+}
+
 func (m *mockCostStore) GetConsumerCost(ctx context.Context, consumerID string, start, end time.Time) (float64, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -124,6 +138,64 @@ func (m *mockCostStore) GetSummary(ctx context.Context, query models.CostQuery)
 	return summary, nil
 }
 
+func (m *mockCostStore) GetBreakdown(ctx context.Context, groupBy []models.CostGroupBy, query models.CostQuery) ([]models.CostGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var groups []models.CostGroup
+	sessions := make(map[int]map[string]bool)
+	index := make(map[models.CostGroup]int)
+	for _, r := range m.records {
+		if query.ConsumerID != "" && r.ConsumerID != query.ConsumerID {
+			continue
+		}
+		if !query.StartTime.IsZero() && r.Hour.Before(query.StartTime) {
+			continue
+		}
+		if !query.EndTime.IsZero() && !r.Hour.Before(query.EndTime) {
+			continue
+		}
+		var key models.CostGroup
+		for _, g := range groupBy {
+			switch g {
+			case models.CostGroupByConsumer:
+				key.ConsumerID = r.ConsumerID
+			case models.CostGroupByGPUType:
+				key.GPUType = r.GPUType
+			case models.CostGroupByProvider:
+				key.Provider = r.Provider
+			}
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, key)
+			sessions[i] = make(map[string]bool)
+		}
+		groups[i].TotalCost += r.Amount
+		if r.Category == models.CostCategoryGPU || r.Category == "" {
+			groups[i].HoursUsed++
+		}
+		sessions[i][r.SessionID] = true
+		groups[i].SessionCount = len(sessions[i])
+	}
+	return groups, nil
+}
+
+func (m *mockCostStore) List(ctx context.Context, query models.CostQuery) ([]models.CostRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []models.CostRecord
+	for _, r := range m.records {
+		if query.ConsumerID != "" && r.ConsumerID != query.ConsumerID {
+			continue
+		}
+		result = append(result, *r)
+	}
+	return result, nil
+}
+
 func (m *mockCostStore) getRecords() []*models.CostRecord {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -325,6 +397,102 @@ func TestTracker_RecordsCostsForRunningSessions(t *testing.T) {
 	assert.Equal(t, int64(1), metrics.CostsRecorded)
 }
 
+// chargesProvider reports fixed non-GPU charges; other Provider methods are unused
+type chargesProvider struct {
+	provider.Provider
+	charges *provider.InstanceCharges
+}
+
+func (c *chargesProvider) GetInstanceCharges(ctx context.Context, instanceID string) (*provider.InstanceCharges, error) {
+	return c.charges, nil
+}
+
+// mockProviderLookup resolves providers from a map
+type mockProviderLookup map[string]provider.Provider
+
+func (m mockProviderLookup) Get(name string) (provider.Provider, error) {
+	p, ok := m[name]
+	if !ok {
+		return nil, errors.New("provider not found")
+	}
+	return p, nil
+}
+
+func TestTracker_RecordsProviderCharges(t *testing.T) {
+	now := time.Date(2026, 3, 18, 10, 15, 0, 0, time.UTC)
+	costStore := newMockCostStoreWithTime(func() time.Time { return now })
+	sessionStore := newMockSessionStore()
+	sessionStore.add(&models.Session{
+		ID:           "sess-1",
+		ConsumerID:   "consumer-001",
+		Provider:     "vastai",
+		ProviderID:   "12345",
+		GPUType:      "RTX4090",
+		Status:       models.StatusRunning,
+		PricePerHour: 0.50,
+	})
+
+	prov := &chargesProvider{charges: &provider.InstanceCharges{
+		StoragePerHour: 0.02,
+		BandwidthTotal: 0.30,
+	}}
+
+	tracker := New(costStore, sessionStore, nil,
+		WithProviders(mockProviderLookup{"vastai": prov}),
+		WithTimeFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	tracker.RunAggregationNow(ctx)
+
+	byCategory := func() map[models.CostCategory]float64 {
+		result := make(map[models.CostCategory]float64)
+		for _, r := range costStore.getRecords() {
+			result[r.Category] += r.Amount
+		}
+		return result
+	}
+
+	totals := byCategory()
+	assert.Equal(t, 0.50, totals[models.CostCategoryGPU])
+	assert.Equal(t, 0.02, totals[models.CostCategoryStorage])
+	assert.InDelta(t, 0.30, totals[models.CostCategoryBandwidth], 0.0001)
+	assert.Zero(t, totals[models.CostCategoryIP])
+
+	// Next hour: cumulative bandwidth grows to $0.45, so only $0.15 is new
+	now = now.Add(time.Hour)
+	prov.charges.BandwidthTotal = 0.45
+	tracker.RunAggregationNow(ctx)
+
+	totals = byCategory()
+	assert.Equal(t, 1.00, totals[models.CostCategoryGPU])
+	assert.Equal(t, 0.04, totals[models.CostCategoryStorage])
+	assert.InDelta(t, 0.45, totals[models.CostCategoryBandwidth], 0.0001)
+}
+
+func TestTracker_SkipsChargesForProvidersWithoutSupport(t *testing.T) {
+	costStore := newMockCostStore()
+	sessionStore := newMockSessionStore()
+	sessionStore.add(&models.Session{
+		ID:           "sess-1",
+		ConsumerID:   "consumer-001",
+		Provider:     "tensordock",
+		ProviderID:   "abc",
+		Status:       models.StatusRunning,
+		PricePerHour: 0.50,
+	})
+
+	// A provider without GetInstanceCharges only gets GPU-hours
+	var plain provider.Provider
+	tracker := New(costStore, sessionStore, nil,
+		WithProviders(mockProviderLookup{"tensordock": plain}))
+
+	tracker.RunAggregationNow(context.Background())
+
+	records := costStore.getRecords()
+	require.Len(t, records, 1)
+	assert.Equal(t, models.CostCategoryGPU, records[0].Category)
+}
+
 func TestTracker_BudgetWarning(t *testing.T) {
 	costStore := newMockCostStore()
 	sessionStore := newMockSessionStore()
@@ -810,3 +978,33 @@ func TestTracker_TimeInjection_DailySummary(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 0.00, summary.TotalCost, "daily summary on new day should be empty")
 }
+
+func TestTracker_GetBreakdown(t *testing.T) {
+	costStore := newMockCostStore()
+	tracker := New(costStore, newMockSessionStore(), nil)
+	ctx := context.Background()
+	hour := time.Date(2026, 3, 18, 10, 0, 0, 0, time.UTC)
+
+	for _, r := range []*models.CostRecord{
+		{SessionID: "sess-1", ConsumerID: "team-a", GPUType: "RTX4090", Category: models.CostCategoryGPU, Hour: hour, Amount: 0.50},
+		{SessionID: "sess-2", ConsumerID: "team-a", GPUType: "A100", Category: models.CostCategoryGPU, Hour: hour, Amount: 1.50},
+		{SessionID: "sess-3", ConsumerID: "team-b", GPUType: "RTX4090", Category: models.CostCategoryGPU, Hour: hour, Amount: 0.50},
+	} {
+		require.NoError(t, tracker.RecordCost(ctx, r))
+	}
+
+	query := models.CostQuery{StartTime: hour.Truncate(24 * time.Hour), EndTime: hour.Add(24 * time.Hour)}
+	breakdown, err := tracker.GetBreakdown(ctx, []models.CostGroupBy{models.CostGroupByDeployment, models.CostGroupByConsumer}, query, "deploy-1")
+	require.NoError(t, err)
+	assert.Equal(t, query.StartTime, breakdown.PeriodStart)
+	assert.Equal(t, 2.50, breakdown.TotalCost)
+	require.Len(t, breakdown.Groups, 2)
+	for _, g := range breakdown.Groups {
+		assert.Equal(t, "deploy-1", g.Deployment)
+	}
+
+	breakdown, err = tracker.GetBreakdown(ctx, []models.CostGroupBy{models.CostGroupByGPUType}, query, "deploy-1")
+	require.NoError(t, err)
+	require.Len(t, breakdown.Groups, 2)
+	assert.Empty(t, breakdown.Groups[0].Deployment, "not grouped by deployment")
+}
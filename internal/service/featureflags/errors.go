@@ -0,0 +1,13 @@
+package featureflags
+
+import "fmt"
+
+// InvalidFlagError indicates a feature flag failed validation
+type InvalidFlagError struct {
+	Name   string
+	Reason string
+}
+
+func (e *InvalidFlagError) Error() string {
+	return fmt.Sprintf("invalid feature flag %q: %s", e.Name, e.Reason)
+}
@@ -0,0 +1,203 @@
+// Package featureflags evaluates runtime feature flags that gate risky
+// provider behaviors, so fixes can be canaried and rolled back without a
+// redeploy.
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ProviderFlagPrefix prefixes flags gating provisioning on a whole provider,
+// e.g. "provider.bluelobster". Provider flags default to enabled.
+const ProviderFlagPrefix = "provider."
+
+// flagNamePattern restricts flag names to dotted lowercase identifiers
+var flagNamePattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// Store persists feature flags
+type Store interface {
+	SetFlag(ctx context.Context, flag *models.FeatureFlag) error
+	GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error)
+	ListFlags(ctx context.Context) ([]*models.FeatureFlag, error)
+	DeleteFlag(ctx context.Context, name string) error
+}
+
+// Service evaluates feature flags. Flags that are not stored fall back to a
+// registered default, which should match the behavior before the flag
+// existed so that an empty table changes nothing.
+type Service struct {
+	store    Store
+	defaults map[string]bool
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// Option configures the feature flag service
+type Option func(*Service)
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithDefault registers a known flag and the value used when it is not stored
+func WithDefault(name string, enabled bool) Option {
+	return func(s *Service) {
+		s.defaults[name] = enabled
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(s *Service) {
+		s.now = fn
+	}
+}
+
+// New creates a new feature flag service
+func New(store Store, opts ...Option) *Service {
+	s := &Service{
+		store:    store,
+		defaults: make(map[string]bool),
+		logger:   slog.Default(),
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Enabled reports whether flag name is on for key (typically a session or
+// consumer ID). def is used when the flag is neither stored nor registered.
+// Lookup errors fall back to the default rather than failing provisioning.
+func (s *Service) Enabled(ctx context.Context, name, key string, def bool) bool {
+	if d, ok := s.defaults[name]; ok {
+		def = d
+	}
+
+	flag, err := s.store.GetFlag(ctx, name)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			s.logger.Error("failed to get feature flag, using default",
+				slog.String("flag", name),
+				slog.Bool("default", def),
+				slog.String("error", err.Error()))
+		}
+		return def
+	}
+
+	return evaluate(flag, key)
+}
+
+// Evaluate returns the value of every registered and stored flag for key
+func (s *Service) Evaluate(ctx context.Context, key string) map[string]bool {
+	result := make(map[string]bool, len(s.defaults))
+	for name, def := range s.defaults {
+		result[name] = def
+	}
+
+	flags, err := s.store.ListFlags(ctx)
+	if err != nil {
+		s.logger.Error("failed to list feature flags, using defaults",
+			slog.String("error", err.Error()))
+		return result
+	}
+	for _, flag := range flags {
+		result[flag.Name] = evaluate(flag, key)
+	}
+
+	return result
+}
+
+// List returns all stored flags plus registered flags still at their
+// default. Defaults have a zero UpdatedAt.
+func (s *Service) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	flags, err := s.store.ListFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		stored[flag.Name] = true
+	}
+	for name, def := range s.defaults {
+		if !stored[name] {
+			flags = append(flags, &models.FeatureFlag{
+				Name:           name,
+				Enabled:        def,
+				RolloutPercent: 100,
+				Description:    "built-in default",
+			})
+		}
+	}
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags, nil
+}
+
+// Set validates and stores a flag, overriding any default
+func (s *Service) Set(ctx context.Context, flag *models.FeatureFlag) error {
+	if !flagNamePattern.MatchString(flag.Name) || len(flag.Name) > 128 {
+		return &InvalidFlagError{Name: flag.Name, Reason: "name must be dotted lowercase identifiers"}
+	}
+	if flag.RolloutPercent < 0 || flag.RolloutPercent > 100 {
+		return &InvalidFlagError{Name: flag.Name, Reason: "rollout_percent must be between 0 and 100"}
+	}
+
+	flag.UpdatedAt = s.now().UTC()
+	if err := s.store.SetFlag(ctx, flag); err != nil {
+		return err
+	}
+
+	s.logger.Info("feature flag updated",
+		slog.String("flag", flag.Name),
+		slog.Bool("enabled", flag.Enabled),
+		slog.Int("rollout_percent", flag.RolloutPercent),
+		slog.String("updated_by", flag.UpdatedBy))
+
+	return nil
+}
+
+// Delete removes a stored flag, reverting it to its default
+func (s *Service) Delete(ctx context.Context, name string) error {
+	if err := s.store.DeleteFlag(ctx, name); err != nil {
+		return err
+	}
+
+	s.logger.Info("feature flag reset to default", slog.String("flag", name))
+	return nil
+}
+
+// evaluate applies the flag's rollout to key. A disabled flag is off for
+// everyone; an enabled flag is on for RolloutPercent of keys.
+func evaluate(flag *models.FeatureFlag, key string) bool {
+	if !flag.Enabled {
+		return false
+	}
+	return bucket(flag.Name, key) < flag.RolloutPercent
+}
+
+// bucket deterministically maps key to [0, 100) for a flag. Hashing the flag
+// name with the key keeps canary cohorts independent across flags.
+func bucket(name, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{':'})
+	h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
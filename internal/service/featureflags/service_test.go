@@ -0,0 +1,150 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+type mockStore struct {
+	mu    sync.Mutex
+	flags map[string]*models.FeatureFlag
+	err   error
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{flags: make(map[string]*models.FeatureFlag)}
+}
+
+func (m *mockStore) SetFlag(ctx context.Context, flag *models.FeatureFlag) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *flag
+	m.flags[flag.Name] = &copied
+	return nil
+}
+
+func (m *mockStore) GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	flag, ok := m.flags[name]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return flag, nil
+}
+
+func (m *mockStore) ListFlags(ctx context.Context) ([]*models.FeatureFlag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	var flags []*models.FeatureFlag
+	for _, flag := range m.flags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+func (m *mockStore) DeleteFlag(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.flags[name]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(m.flags, name)
+	return nil
+}
+
+func TestService_DefaultsWhenUnset(t *testing.T) {
+	svc := New(newMockStore(), WithDefault("tensordock.dedicated_ip", true))
+	ctx := context.Background()
+
+	assert.True(t, svc.Enabled(ctx, "tensordock.dedicated_ip", "sess-1", false), "registered default wins")
+	assert.True(t, svc.Enabled(ctx, "provider.newcloud", "sess-1", true))
+	assert.False(t, svc.Enabled(ctx, "unknown.flag", "sess-1", false))
+
+	assert.Equal(t, map[string]bool{"tensordock.dedicated_ip": true}, svc.Evaluate(ctx, "sess-1"))
+}
+
+func TestService_StoreErrorFallsBackToDefault(t *testing.T) {
+	store := newMockStore()
+	require.NoError(t, store.SetFlag(context.Background(), &models.FeatureFlag{Name: "tensordock.dedicated_ip"}))
+	store.err = errors.New("database is locked")
+	svc := New(store, WithDefault("tensordock.dedicated_ip", true))
+
+	assert.True(t, svc.Enabled(context.Background(), "tensordock.dedicated_ip", "sess-1", false))
+	assert.True(t, svc.Evaluate(context.Background(), "sess-1")["tensordock.dedicated_ip"])
+}
+
+func TestService_RolloutPercent(t *testing.T) {
+	store := newMockStore()
+	svc := New(store, WithDefault("tensordock.dedicated_ip", true))
+	ctx := context.Background()
+
+	require.NoError(t, svc.Set(ctx, &models.FeatureFlag{Name: "tensordock.dedicated_ip", Enabled: true, RolloutPercent: 25}))
+
+	on := 0
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("sess-%d", i)
+		v := svc.Enabled(ctx, "tensordock.dedicated_ip", key, false)
+		assert.Equal(t, v, svc.Enabled(ctx, "tensordock.dedicated_ip", key, false), "evaluation must be stable")
+		assert.Equal(t, v, svc.Evaluate(ctx, key)["tensordock.dedicated_ip"])
+		if v {
+			on++
+		}
+	}
+	assert.InDelta(t, 500, on, 100, "about 25%% of keys should be in the canary")
+
+	// Rolling back turns it off for everyone
+	require.NoError(t, svc.Set(ctx, &models.FeatureFlag{Name: "tensordock.dedicated_ip", Enabled: false, RolloutPercent: 100}))
+	for i := 0; i < 100; i++ {
+		assert.False(t, svc.Enabled(ctx, "tensordock.dedicated_ip", fmt.Sprintf("sess-%d", i), true))
+	}
+
+	// Deleting reverts to the default
+	require.NoError(t, svc.Delete(ctx, "tensordock.dedicated_ip"))
+	assert.True(t, svc.Enabled(ctx, "tensordock.dedicated_ip", "sess-1", false))
+}
+
+func TestService_SetValidation(t *testing.T) {
+	svc := New(newMockStore())
+	ctx := context.Background()
+
+	var invalid *InvalidFlagError
+	assert.ErrorAs(t, svc.Set(ctx, &models.FeatureFlag{Name: "Bad Name", RolloutPercent: 100}), &invalid)
+	assert.ErrorAs(t, svc.Set(ctx, &models.FeatureFlag{Name: "tensordock.dedicated_ip", RolloutPercent: 101}), &invalid)
+	assert.ErrorAs(t, svc.Set(ctx, &models.FeatureFlag{Name: "tensordock.dedicated_ip", RolloutPercent: -1}), &invalid)
+	assert.NoError(t, svc.Set(ctx, &models.FeatureFlag{Name: "provider.bluelobster", RolloutPercent: 0}))
+}
+
+func TestService_ListIncludesDefaults(t *testing.T) {
+	svc := New(newMockStore(),
+		WithDefault("tensordock.dedicated_ip", true),
+		WithDefault("tensordock.nvidia_auto_install", true))
+	ctx := context.Background()
+
+	require.NoError(t, svc.Set(ctx, &models.FeatureFlag{Name: "tensordock.nvidia_auto_install", Enabled: false, UpdatedBy: "ops-bob"}))
+
+	flags, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "tensordock.dedicated_ip", flags[0].Name)
+	assert.True(t, flags[0].Enabled)
+	assert.True(t, flags[0].UpdatedAt.IsZero())
+	assert.Equal(t, "tensordock.nvidia_auto_install", flags[1].Name)
+	assert.False(t, flags[1].Enabled)
+	assert.Equal(t, "ops-bob", flags[1].UpdatedBy)
+}
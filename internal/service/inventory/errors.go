@@ -1,10 +1,38 @@
 package inventory
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrPriceHistoryDisabled is returned when no price history store is configured
+var ErrPriceHistoryDisabled = errors.New("price history is not enabled")
+
+// ErrPriceWatchesDisabled is returned when no price watch store is configured
+var ErrPriceWatchesDisabled = errors.New("price watches are not enabled")
+
+// InvalidPriceWatchError indicates a price watch failed validation
+type InvalidPriceWatchError struct {
+	Reason string
+}
+
+func (e *InvalidPriceWatchError) Error() string {
+	return fmt.Sprintf("invalid price watch: %s", e.Reason)
+}
+
+// ErrOfferRuleNotFound is returned when an offer rule does not exist
+var ErrOfferRuleNotFound = errors.New("offer rule not found")
+
+// InvalidOfferRuleError indicates an offer rule failed validation
+type InvalidOfferRuleError struct {
+	Reason string
+}
+
+func (e *InvalidOfferRuleError) Error() string {
+	return fmt.Sprintf("invalid offer rule: %s", e.Reason)
+}
+
 // ProviderNotFoundError indicates the requested provider doesn't exist
 type ProviderNotFoundError struct {
 	Name string
@@ -6,16 +6,19 @@ import (
 	"math"
 	"sync"
 	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
-// FailureType categorizes provisioning failures for offer tracking
+// FailureType categorizes provisioning failures for offer tracking. Values
+// are the session failure categories (models.FailureCategory).
 type FailureType string
 
 const (
-	FailureStaleInventory  FailureType = "stale_inventory"
-	FailureInstanceStopped FailureType = "instance_stopped"
-	FailureSSHTimeout      FailureType = "ssh_timeout"
-	FailureUnknown         FailureType = "unknown"
+	FailureStaleInventory  = FailureType(models.FailureStaleInventory)
+	FailureInstanceStopped = FailureType(models.FailureInstanceStopped)
+	FailureSSHTimeout      = FailureType(models.FailureSSHTimeout)
+	FailureUnknown         = FailureType(models.FailureUnknown)
 )
 
 const (
@@ -35,16 +38,24 @@ const (
 	// GPUTypeFailureThreshold is how many distinct offers of the same GPU type
 	// must fail before all offers of that type are degraded
 	GPUTypeFailureThreshold = 3
+
+	// HostFailureDecayPeriod is how long failures count against a host. Hosts
+	// (e.g. Vast.ai machines) outlive their offers and relist under new offer
+	// IDs, so their reputation is kept longer than an offer's.
+	HostFailureDecayPeriod = 6 * time.Hour
 )
 
 // FailureStore is the interface for persisting offer failure data.
 // Implemented by storage.OfferFailureStore.
 type FailureStore interface {
-	RecordFailure(ctx context.Context, offerID, provider, gpuType, failureType, reason string) error
+	RecordFailure(ctx context.Context, offerID, provider, gpuType, machineID, failureType, reason string) error
 	SetSuppression(ctx context.Context, offerID, provider, gpuType string, suppressedAt time.Time) error
 	ClearSuppression(ctx context.Context, offerID string) error
 	CleanupOldFailures(ctx context.Context, before time.Time) (int64, error)
 	CleanupExpiredSuppressions(ctx context.Context, before time.Time) (int64, error)
+	ClearFailures(ctx context.Context, offerID string) error
+	SetRule(ctx context.Context, rule *models.OfferRule) error
+	DeleteRule(ctx context.Context, id string) error
 }
 
 // OfferFailureTracker tracks provisioning failures across sessions to degrade
@@ -54,6 +65,10 @@ type OfferFailureTracker struct {
 	mu       sync.RWMutex
 	offers   map[string]*offerFailureRecord // keyed by offer ID
 	gpuTypes map[string]*gpuTypeRecord      // keyed by "provider:GPUType"
+	hosts    map[string]*hostRecord         // keyed by "provider:MachineID"
+
+	// Operator-managed suppress/allow rules, keyed by rule ID
+	rules map[string]*models.OfferRule
 
 	// Optional persistent storage (nil = in-memory only)
 	store  FailureStore
@@ -63,6 +78,7 @@ type OfferFailureTracker struct {
 type offerFailureRecord struct {
 	Provider     string
 	GPUType      string
+	MachineID    string // empty if the offer's host is unknown
 	Failures     []failureEvent
 	SuppressedAt time.Time // zero if not suppressed
 }
@@ -77,11 +93,24 @@ type gpuTypeRecord struct {
 	FailedOfferIDs map[string]time.Time // offer ID → last failure time
 }
 
+type hostRecord struct {
+	Provider  string
+	MachineID string
+	Failures  []hostFailureEvent
+}
+
+type hostFailureEvent struct {
+	OfferID   string
+	Type      FailureType
+	Timestamp time.Time
+}
+
 // OfferHealthInfo exposes health data for the /api/v1/offer-health endpoint
 type OfferHealthInfo struct {
 	OfferID              string      `json:"offer_id"`
 	Provider             string      `json:"provider"`
 	GPUType              string      `json:"gpu_type"`
+	MachineID            string      `json:"machine_id,omitempty"`
 	RecentFailures       int         `json:"recent_failures"`
 	IsSuppressed         bool        `json:"is_suppressed"`
 	SuppressedAt         *time.Time  `json:"suppressed_at,omitempty"`
@@ -100,11 +129,26 @@ type GPUTypeHealthInfo struct {
 	DegradationApplied float64 `json:"degradation_applied,omitempty"`
 }
 
+// HostHealthInfo exposes host-level health data. Failures count against a
+// host across every offer ID it has been listed under.
+type HostHealthInfo struct {
+	Provider             string      `json:"provider"`
+	MachineID            string      `json:"machine_id"`
+	RecentFailures       int         `json:"recent_failures"` // Within HostFailureDecayPeriod
+	FailedOffers         int         `json:"failed_offers"`   // Distinct offer IDs that failed
+	IsSuppressed         bool        `json:"is_suppressed"`
+	ConfidenceMultiplier float64     `json:"confidence_multiplier"` // Applied to the host's other offers
+	LastFailure          *time.Time  `json:"last_failure,omitempty"`
+	LastFailureType      FailureType `json:"last_failure_type,omitempty"`
+}
+
 // NewOfferFailureTracker creates a new failure tracker
 func NewOfferFailureTracker() *OfferFailureTracker {
 	return &OfferFailureTracker{
 		offers:   make(map[string]*offerFailureRecord),
 		gpuTypes: make(map[string]*gpuTypeRecord),
+		hosts:    make(map[string]*hostRecord),
+		rules:    make(map[string]*models.OfferRule),
 		logger:   slog.Default(),
 	}
 }
@@ -156,6 +200,16 @@ func (t *OfferFailureTracker) LoadFromStore(ctx context.Context, failures []Stor
 		if existing, ok := gpuRec.FailedOfferIDs[f.OfferID]; !ok || f.CreatedAt.After(existing) {
 			gpuRec.FailedOfferIDs[f.OfferID] = f.CreatedAt
 		}
+
+		if f.MachineID != "" {
+			record.MachineID = f.MachineID
+			host := t.hostRecordLocked(f.Provider, f.MachineID)
+			host.Failures = append(host.Failures, hostFailureEvent{
+				OfferID:   f.OfferID,
+				Type:      FailureType(f.FailureType),
+				Timestamp: f.CreatedAt,
+			})
+		}
 		loaded++
 	}
 
@@ -179,7 +233,8 @@ func (t *OfferFailureTracker) LoadFromStore(ctx context.Context, failures []Stor
 		slog.Int("failures", loaded),
 		slog.Int("suppressions", len(suppressions)),
 		slog.Int("tracked_offers", len(t.offers)),
-		slog.Int("tracked_gpu_types", len(t.gpuTypes)))
+		slog.Int("tracked_gpu_types", len(t.gpuTypes)),
+		slog.Int("tracked_hosts", len(t.hosts)))
 }
 
 // StoredFailure represents a failure event loaded from the DB
@@ -187,6 +242,7 @@ type StoredFailure struct {
 	OfferID     string
 	Provider    string
 	GPUType     string
+	MachineID   string
 	FailureType string
 	Reason      string
 	CreatedAt   time.Time
@@ -200,8 +256,14 @@ type StoredSuppression struct {
 	SuppressedAt time.Time
 }
 
-// RecordFailure records a provisioning failure for an offer
+// RecordFailure records a provisioning failure for an offer whose host is unknown
 func (t *OfferFailureTracker) RecordFailure(offerID, providerName, gpuType string, failureType FailureType, reason string) {
+	t.RecordMachineFailure(offerID, providerName, gpuType, "", failureType, reason)
+}
+
+// RecordMachineFailure records a provisioning failure for an offer and, when
+// machineID is set, against the host it was listed on
+func (t *OfferFailureTracker) RecordMachineFailure(offerID, providerName, gpuType, machineID string, failureType FailureType, reason string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -223,6 +285,15 @@ func (t *OfferFailureTracker) RecordFailure(offerID, providerName, gpuType strin
 		Timestamp: now,
 		Reason:    reason,
 	})
+	if machineID != "" {
+		record.MachineID = machineID
+		host := t.hostRecordLocked(providerName, machineID)
+		host.Failures = append(host.Failures, hostFailureEvent{
+			OfferID:   offerID,
+			Type:      failureType,
+			Timestamp: now,
+		})
+	}
 
 	// Check suppression threshold: count failures within SuppressionWindow
 	recentCount := 0
@@ -257,7 +328,7 @@ func (t *OfferFailureTracker) RecordFailure(offerID, providerName, gpuType strin
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			if err := t.store.RecordFailure(ctx, offerID, providerName, gpuType, string(failureType), reason); err != nil {
+			if err := t.store.RecordFailure(ctx, offerID, providerName, gpuType, machineID, string(failureType), reason); err != nil {
 				t.logger.Warn("failed to persist offer failure",
 					slog.String("offer_id", offerID),
 					slog.String("error", err.Error()))
@@ -341,6 +412,132 @@ func (t *OfferFailureTracker) IsSuppressed(offerID string) bool {
 	return now.Before(record.SuppressedAt.Add(SuppressionCooldown))
 }
 
+// IsHostSuppressed returns true if the host has failed SuppressionThreshold
+// times within SuppressionWindow, across any of its offers. Every offer on a
+// suppressed host is hidden, including offers it relists under new IDs.
+func (t *OfferFailureTracker) IsHostSuppressed(providerName, machineID string) bool {
+	if machineID == "" {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.isHostSuppressedLocked(providerName+":"+machineID, time.Now())
+}
+
+// OfferConfidenceMultiplier returns GetConfidenceMultiplier for the offer,
+// further degraded by failures of other offers on the same host: 0.7 per
+// failure within HostFailureDecayPeriod. Returns 0.0 if the offer or its host
+// is suppressed. Minimum 0.05 otherwise.
+func (t *OfferFailureTracker) OfferConfidenceMultiplier(offer *models.GPUOffer) float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+	result := t.getConfidenceMultiplierLocked(offer.ID, offer.GPUType, offer.Provider, now)
+	if result == 0.0 || offer.MachineID == "" {
+		return result
+	}
+
+	hostKey := offer.Provider + ":" + offer.MachineID
+	if t.isHostSuppressedLocked(hostKey, now) {
+		return 0.0
+	}
+	result *= t.hostMultiplierLocked(hostKey, offer.ID, now)
+	if result < 0.05 {
+		result = 0.05
+	}
+	return result
+}
+
+// GetHostHealth returns health data for hosts with failures within
+// HostFailureDecayPeriod
+func (t *OfferFailureTracker) GetHostHealth() []HostHealthInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+	cutoff := now.Add(-HostFailureDecayPeriod)
+	hosts := make([]HostHealthInfo, 0, len(t.hosts))
+	for hostKey, host := range t.hosts {
+		info := HostHealthInfo{
+			Provider:  host.Provider,
+			MachineID: host.MachineID,
+		}
+		offerIDs := make(map[string]bool)
+		for _, f := range host.Failures {
+			if f.Timestamp.After(cutoff) {
+				info.RecentFailures++
+				offerIDs[f.OfferID] = true
+			}
+		}
+		if info.RecentFailures == 0 {
+			continue
+		}
+		info.FailedOffers = len(offerIDs)
+		info.IsSuppressed = t.isHostSuppressedLocked(hostKey, now)
+		if !info.IsSuppressed {
+			info.ConfidenceMultiplier = t.hostMultiplierLocked(hostKey, "", now)
+			if info.ConfidenceMultiplier < 0.05 {
+				info.ConfidenceMultiplier = 0.05
+			}
+		}
+		last := host.Failures[len(host.Failures)-1]
+		info.LastFailure = &last.Timestamp
+		info.LastFailureType = last.Type
+		hosts = append(hosts, info)
+	}
+	return hosts
+}
+
+// hostRecordLocked returns the host's record, creating it if needed.
+// Must be called with the write lock held.
+func (t *OfferFailureTracker) hostRecordLocked(providerName, machineID string) *hostRecord {
+	hostKey := providerName + ":" + machineID
+	host, exists := t.hosts[hostKey]
+	if !exists {
+		host = &hostRecord{Provider: providerName, MachineID: machineID}
+		t.hosts[hostKey] = host
+	}
+	return host
+}
+
+// isHostSuppressedLocked reports whether the host reached the suppression
+// threshold within SuppressionWindow.
+// Must be called with at least a read lock held.
+func (t *OfferFailureTracker) isHostSuppressedLocked(hostKey string, now time.Time) bool {
+	host, exists := t.hosts[hostKey]
+	if !exists {
+		return false
+	}
+	count := 0
+	cutoff := now.Add(-SuppressionWindow)
+	for _, f := range host.Failures {
+		if f.Timestamp.After(cutoff) {
+			count++
+		}
+	}
+	return count >= SuppressionThreshold
+}
+
+// hostMultiplierLocked returns 0.7^n for the n host failures within
+// HostFailureDecayPeriod not recorded against offerID, which the per-offer
+// multiplier already counts.
+// Must be called with at least a read lock held.
+func (t *OfferFailureTracker) hostMultiplierLocked(hostKey, offerID string, now time.Time) float64 {
+	host, exists := t.hosts[hostKey]
+	if !exists {
+		return 1.0
+	}
+	count := 0
+	cutoff := now.Add(-HostFailureDecayPeriod)
+	for _, f := range host.Failures {
+		if f.OfferID != offerID && f.Timestamp.After(cutoff) {
+			count++
+		}
+	}
+	return math.Pow(0.7, float64(count))
+}
+
 // GetAllHealth returns structured health data for all tracked offers
 func (t *OfferFailureTracker) GetAllHealth() ([]OfferHealthInfo, []GPUTypeHealthInfo) {
 	t.mu.RLock()
@@ -359,6 +556,7 @@ func (t *OfferFailureTracker) GetAllHealth() ([]OfferHealthInfo, []GPUTypeHealth
 			OfferID:              offerID,
 			Provider:             record.Provider,
 			GPUType:              record.GPUType,
+			MachineID:            record.MachineID,
 			RecentFailures:       recentCount,
 			IsSuppressed:         !record.SuppressedAt.IsZero() && now.Before(record.SuppressedAt.Add(SuppressionCooldown)),
 			ConfidenceMultiplier: t.getConfidenceMultiplierLocked(offerID, record.GPUType, record.Provider, now),
@@ -492,6 +690,28 @@ func (t *OfferFailureTracker) cleanupLocked(now time.Time) {
 		}
 	}
 
+	// Prune host failures past the longer host decay period
+	hostCutoff := now.Add(-HostFailureDecayPeriod)
+	for hostKey, host := range t.hosts {
+		var kept []hostFailureEvent
+		for _, f := range host.Failures {
+			if f.Timestamp.After(hostCutoff) {
+				kept = append(kept, f)
+			}
+		}
+		host.Failures = kept
+		if len(kept) == 0 {
+			delete(t.hosts, hostKey)
+		}
+	}
+
+	// Drop expired rules; the store skips them on load
+	for id, rule := range t.rules {
+		if !rule.IsActive(now) {
+			delete(t.rules, id)
+		}
+	}
+
 	// Clean up GPU-type records
 	for gpuKey, gpuRec := range t.gpuTypes {
 		for offerID, lastFail := range gpuRec.FailedOfferIDs {
@@ -517,8 +737,8 @@ func (t *OfferFailureTracker) cleanupLocked(now time.Time) {
 						slog.String("error", err.Error()))
 				}
 			}
-			// Cleanup old failures from DB
-			if deleted, err := store.CleanupOldFailures(ctx, decayCutoff); err != nil {
+			// Cleanup old failures from DB, keeping those that still count against hosts
+			if deleted, err := store.CleanupOldFailures(ctx, hostCutoff); err != nil {
 				t.logger.Warn("failed to cleanup old failures from store",
 					slog.String("error", err.Error()))
 			} else if deleted > 0 {
@@ -5,6 +5,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
 func TestNoFailures_MultiplierIsOne(t *testing.T) {
@@ -449,3 +451,207 @@ func TestLoadFromStore_ExpiredSuppressionIgnored(t *testing.T) {
 		t.Error("expected expired suppression to be cleared after loading from store")
 	}
 }
+
+func TestOfferRules_SuppressAndAllow(t *testing.T) {
+	tracker := NewOfferFailureTracker()
+	ctx := context.Background()
+	onHost := &models.GPUOffer{ID: "offer-1", Provider: "vastai", GPUType: "RTX 4090", MachineID: "99", Location: "Oslo, Norway"}
+	elsewhere := &models.GPUOffer{ID: "offer-2", Provider: "vastai", GPUType: "RTX 4090", MachineID: "100", Location: "Texas, US"}
+
+	// Suppress an entire host
+	hostRule := &models.OfferRule{ID: "rule-1", Action: models.OfferRuleSuppress, Scope: models.OfferRuleScopeMachine, Value: "99"}
+	if err := tracker.SetRule(ctx, hostRule); err != nil {
+		t.Fatal(err)
+	}
+	if !tracker.IsOfferSuppressed(onHost) {
+		t.Error("expected offer on suppressed host to be suppressed")
+	}
+	if tracker.IsOfferSuppressed(elsewhere) {
+		t.Error("expected offer on other host not to be suppressed")
+	}
+
+	// An allow rule exempts an offer from automatic suppression and degradation
+	for i := 0; i < SuppressionThreshold; i++ {
+		tracker.RecordFailure("offer-2", "vastai", "RTX 4090", FailureSSHTimeout, "timeout")
+	}
+	if !tracker.IsOfferSuppressed(elsewhere) {
+		t.Fatal("expected offer to be automatically suppressed")
+	}
+	allowRule := &models.OfferRule{ID: "rule-2", Action: models.OfferRuleAllow, Scope: models.OfferRuleScopeLocation, Value: "US"}
+	if err := tracker.SetRule(ctx, allowRule); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.IsOfferSuppressed(elsewhere) || !tracker.IsOfferAllowed(elsewhere) {
+		t.Error("expected allowlisted offer to be exempt from automatic suppression")
+	}
+
+	// Suppress rules win over allow rules
+	if err := tracker.SetRule(ctx, &models.OfferRule{ID: "rule-3", Action: models.OfferRuleSuppress, Scope: models.OfferRuleScopeOffer, Value: "offer-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if !tracker.IsOfferSuppressed(elsewhere) || tracker.IsOfferAllowed(elsewhere) {
+		t.Error("expected suppress rule to take precedence over allow rule")
+	}
+
+	// A rule for the same target replaces the previous one
+	if err := tracker.SetRule(ctx, &models.OfferRule{ID: "rule-4", Action: models.OfferRuleAllow, Scope: models.OfferRuleScopeMachine, Value: "99"}); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.IsOfferSuppressed(onHost) {
+		t.Error("expected replaced host rule to stop suppressing")
+	}
+	if len(tracker.Rules()) != 3 {
+		t.Errorf("expected 3 rules, got %d", len(tracker.Rules()))
+	}
+
+	if err := tracker.DeleteRule(ctx, "rule-1"); err != ErrOfferRuleNotFound {
+		t.Errorf("expected ErrOfferRuleNotFound for replaced rule, got %v", err)
+	}
+	if err := tracker.DeleteRule(ctx, "rule-3"); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.IsOfferSuppressed(elsewhere) {
+		t.Error("expected deleting the suppress rule to fall back to the allow rule")
+	}
+}
+
+func TestOfferRules_Expire(t *testing.T) {
+	tracker := NewOfferFailureTracker()
+	offer := &models.GPUOffer{ID: "offer-1", Provider: "vastai"}
+
+	expired := time.Now().Add(-time.Second)
+	tracker.LoadRules([]*models.OfferRule{
+		{ID: "rule-1", Action: models.OfferRuleSuppress, Scope: models.OfferRuleScopeOffer, Value: "offer-1", ExpiresAt: &expired},
+	})
+	if tracker.IsOfferSuppressed(offer) {
+		t.Error("expected expired rule to be ignored")
+	}
+	if len(tracker.Rules()) != 0 {
+		t.Errorf("expected no active rules, got %d", len(tracker.Rules()))
+	}
+}
+
+func TestClearSuppression(t *testing.T) {
+	tracker := NewOfferFailureTracker()
+	for i := 0; i < SuppressionThreshold; i++ {
+		tracker.RecordFailure("offer-1", "vastai", "RTX 4090", FailureStaleInventory, "gone")
+	}
+	if !tracker.IsSuppressed("offer-1") {
+		t.Fatal("expected offer to be suppressed")
+	}
+
+	if !tracker.ClearSuppression(context.Background(), "offer-1") {
+		t.Fatal("expected tracked offer to be cleared")
+	}
+	if tracker.IsSuppressed("offer-1") {
+		t.Error("expected suppression to be lifted")
+	}
+	if m := tracker.GetConfidenceMultiplier("offer-1", "RTX 4090", "vastai"); m != 1.0 {
+		t.Errorf("expected failure history to be reset, got multiplier %f", m)
+	}
+
+	// The next failure starts a fresh count
+	tracker.RecordFailure("offer-1", "vastai", "RTX 4090", FailureStaleInventory, "gone")
+	if tracker.IsSuppressed("offer-1") {
+		t.Error("expected a single new failure not to re-suppress")
+	}
+
+	if tracker.ClearSuppression(context.Background(), "offer-unknown") {
+		t.Error("expected untracked offer not to be cleared")
+	}
+}
+
+func TestHostFailures_NewOffersInheritReputation(t *testing.T) {
+	tracker := NewOfferFailureTracker()
+	tracker.RecordMachineFailure("offer-1", "vastai", "RTX 4090", "vastai-machine-7", FailureSSHTimeout, "timeout")
+
+	// The host relists under a new offer ID
+	relisted := &models.GPUOffer{ID: "offer-2", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-7"}
+	if m := tracker.OfferConfidenceMultiplier(relisted); m < 0.69 || m > 0.71 {
+		t.Errorf("expected relisted offer to inherit host multiplier ~0.7, got %f", m)
+	}
+
+	// The failing offer is not counted twice
+	failed := &models.GPUOffer{ID: "offer-1", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-7"}
+	if m := tracker.OfferConfidenceMultiplier(failed); m < 0.69 || m > 0.71 {
+		t.Errorf("expected failed offer multiplier ~0.7, got %f", m)
+	}
+
+	// Other hosts, and the same machine ID on another provider, are unaffected
+	for _, offer := range []*models.GPUOffer{
+		{ID: "offer-3", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-8"},
+		{ID: "offer-4", Provider: "static", GPUType: "RTX 4090", MachineID: "vastai-machine-7"},
+		{ID: "offer-5", Provider: "vastai", GPUType: "RTX 4090"},
+	} {
+		if m := tracker.OfferConfidenceMultiplier(offer); m != 1.0 {
+			t.Errorf("expected multiplier 1.0 for %s, got %f", offer.ID, m)
+		}
+	}
+}
+
+func TestHostFailures_SuppressHost(t *testing.T) {
+	tracker := NewOfferFailureTracker()
+	tracker.RecordMachineFailure("offer-1", "vastai", "RTX 4090", "vastai-machine-7", FailureSSHTimeout, "timeout")
+	tracker.RecordMachineFailure("offer-2", "vastai", "RTX 4090", "vastai-machine-7", FailureInstanceStopped, "stopped")
+	if tracker.IsHostSuppressed("vastai", "vastai-machine-7") {
+		t.Fatal("expected host below threshold not to be suppressed")
+	}
+	tracker.RecordMachineFailure("offer-3", "vastai", "RTX 4090", "vastai-machine-7", FailureStaleInventory, "gone")
+
+	if !tracker.IsHostSuppressed("vastai", "vastai-machine-7") {
+		t.Fatal("expected host to be suppressed after failures across its offers")
+	}
+	fresh := &models.GPUOffer{ID: "offer-4", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-7"}
+	if !tracker.IsOfferSuppressed(fresh) {
+		t.Error("expected new offer on suppressed host to be suppressed")
+	}
+	if m := tracker.OfferConfidenceMultiplier(fresh); m != 0.0 {
+		t.Errorf("expected multiplier 0.0 on suppressed host, got %f", m)
+	}
+	for _, id := range []string{"offer-1", "offer-2", "offer-3"} {
+		if tracker.IsSuppressed(id) {
+			t.Errorf("expected %s not to be suppressed at offer level after a single failure", id)
+		}
+	}
+
+	// An allow rule exempts the host
+	if err := tracker.SetRule(context.Background(), &models.OfferRule{ID: "rule-1", Action: models.OfferRuleAllow, Scope: models.OfferRuleScopeMachine, Value: "vastai-machine-7"}); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.IsOfferSuppressed(fresh) {
+		t.Error("expected allowlisted host not to be suppressed")
+	}
+
+	hosts := tracker.GetHostHealth()
+	if len(hosts) != 1 {
+		t.Fatalf("expected 1 host, got %d", len(hosts))
+	}
+	if hosts[0].MachineID != "vastai-machine-7" || hosts[0].RecentFailures != 3 || hosts[0].FailedOffers != 3 || !hosts[0].IsSuppressed {
+		t.Errorf("unexpected host health: %+v", hosts[0])
+	}
+}
+
+func TestHostFailures_OutlastOfferFailures(t *testing.T) {
+	tracker := NewOfferFailureTracker()
+	now := time.Now()
+
+	tracker.LoadFromStore(context.Background(), []StoredFailure{
+		{OfferID: "offer-1", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-7", FailureType: "ssh_timeout", CreatedAt: now.Add(-2 * time.Hour)},
+		{OfferID: "offer-2", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-7", FailureType: "ssh_timeout", CreatedAt: now.Add(-7 * time.Hour)},
+	}, nil)
+
+	// Past FailureDecayPeriod the offer itself is forgotten, but within
+	// HostFailureDecayPeriod the host still carries the failure
+	if m := tracker.GetConfidenceMultiplier("offer-1", "RTX 4090", "vastai"); m != 1.0 {
+		t.Errorf("expected offer failure to have decayed, got multiplier %f", m)
+	}
+	relisted := &models.GPUOffer{ID: "offer-3", Provider: "vastai", GPUType: "RTX 4090", MachineID: "vastai-machine-7"}
+	if m := tracker.OfferConfidenceMultiplier(relisted); m < 0.69 || m > 0.71 {
+		t.Errorf("expected one host failure to count (~0.7), got %f", m)
+	}
+
+	hosts := tracker.GetHostHealth()
+	if len(hosts) != 1 || hosts[0].RecentFailures != 1 {
+		t.Errorf("expected failures past HostFailureDecayPeriod to be pruned, got %+v", hosts)
+	}
+}
@@ -0,0 +1,74 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// fetchGroup collapses concurrent provider fetches for the same cache key
+// into a single upstream request (singleflight). Without it, a cold or
+// expired cache under load sends one request per client to the provider,
+// which is what triggers their rate limiting.
+type fetchGroup struct {
+	mu    sync.Mutex
+	calls map[string]*fetchCall
+	wg    sync.WaitGroup // Tracks running fetches for graceful shutdown
+}
+
+// fetchCall is an in-flight or completed fetch
+type fetchCall struct {
+	done   chan struct{}
+	offers []models.GPUOffer
+	err    error
+}
+
+// start launches fn for key unless a fetch for key is already in flight, in
+// which case the existing call is returned and shared is true. fn runs on its
+// own goroutine so that a caller giving up does not cancel the fetch for
+// every other caller waiting on it.
+func (g *fetchGroup) start(key string, fn func() ([]models.GPUOffer, error)) (call *fetchCall, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*fetchCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return c, true
+	}
+	c := &fetchCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.wg.Add(1)
+	g.mu.Unlock()
+
+	go func() {
+		defer g.wg.Done()
+		c.offers, c.err = fn()
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	return c, false
+}
+
+// do runs fn for key, or joins the fetch already in flight for key, and waits
+// for the result or for ctx to be done.
+func (g *fetchGroup) do(ctx context.Context, key string, fn func() ([]models.GPUOffer, error)) (offers []models.GPUOffer, shared bool, err error) {
+	c, shared := g.start(key, fn)
+
+	select {
+	case <-c.done:
+		return c.offers, shared, c.err
+	case <-ctx.Done():
+		return nil, shared, ctx.Err()
+	}
+}
+
+// wait blocks until all in-flight fetches have finished
+func (g *fetchGroup) wait() {
+	g.wg.Wait()
+}
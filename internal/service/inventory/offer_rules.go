@@ -0,0 +1,278 @@
+package inventory
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// LoadRules replaces the tracker's offer rules with rules loaded from the
+// store. Call this once at startup after SetStore.
+func (t *OfferFailureTracker) LoadRules(rules []*models.OfferRule) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rules = make(map[string]*models.OfferRule, len(rules))
+	now := time.Now()
+	for _, rule := range rules {
+		if rule.IsActive(now) {
+			t.rules[rule.ID] = rule
+		}
+	}
+	t.logger.Info("loaded offer rules from store", slog.Int("rules", len(t.rules)))
+}
+
+// SetRule adds a rule, replacing any rule for the same scope, value and
+// provider. The rule is persisted before it takes effect.
+func (t *OfferFailureTracker) SetRule(ctx context.Context, rule *models.OfferRule) error {
+	t.mu.RLock()
+	store := t.store
+	t.mu.RUnlock()
+
+	if store != nil {
+		if err := store.SetRule(ctx, rule); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, existing := range t.rules {
+		if existing.Scope == rule.Scope && existing.Value == rule.Value && existing.Provider == rule.Provider {
+			delete(t.rules, id)
+		}
+	}
+	stored := *rule
+	t.rules[rule.ID] = &stored
+	return nil
+}
+
+// DeleteRule removes a rule by ID
+func (t *OfferFailureTracker) DeleteRule(ctx context.Context, id string) error {
+	t.mu.RLock()
+	_, exists := t.rules[id]
+	store := t.store
+	t.mu.RUnlock()
+	if !exists {
+		return ErrOfferRuleNotFound
+	}
+
+	if store != nil {
+		if err := store.DeleteRule(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	delete(t.rules, id)
+	t.mu.Unlock()
+	return nil
+}
+
+// Rules returns the active rules, oldest first
+func (t *OfferFailureTracker) Rules() []models.OfferRule {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+	rules := make([]models.OfferRule, 0, len(t.rules))
+	for _, rule := range t.rules {
+		if rule.IsActive(now) {
+			rules = append(rules, *rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules
+}
+
+// IsOfferSuppressed returns true if a suppress rule matches the offer or,
+// unless an allow rule matches it, the offer or its host is automatically
+// suppressed
+func (t *OfferFailureTracker) IsOfferSuppressed(offer *models.GPUOffer) bool {
+	switch t.matchingAction(offer) {
+	case models.OfferRuleSuppress:
+		return true
+	case models.OfferRuleAllow:
+		return false
+	}
+	return t.IsSuppressed(offer.ID) || t.IsHostSuppressed(offer.Provider, offer.MachineID)
+}
+
+// IsOfferAllowed returns true if an allow rule and no suppress rule matches
+// the offer, exempting it from failure-based degradation
+func (t *OfferFailureTracker) IsOfferAllowed(offer *models.GPUOffer) bool {
+	return t.matchingAction(offer) == models.OfferRuleAllow
+}
+
+// matchingAction returns the action of the active rules matching the offer,
+// suppress taking precedence, or "" when none match
+func (t *OfferFailureTracker) matchingAction(offer *models.GPUOffer) models.OfferRuleAction {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+	var action models.OfferRuleAction
+	for _, rule := range t.rules {
+		if !rule.IsActive(now) || !rule.Matches(offer) {
+			continue
+		}
+		if rule.Action == models.OfferRuleSuppress {
+			return models.OfferRuleSuppress
+		}
+		action = rule.Action
+	}
+	return action
+}
+
+// ClearSuppression lifts an offer's automatic suppression and forgets its
+// failure history so it is not suppressed again by the next failure. It
+// returns false if the offer has no tracked failures.
+func (t *OfferFailureTracker) ClearSuppression(ctx context.Context, offerID string) bool {
+	t.mu.Lock()
+	record, exists := t.offers[offerID]
+	if !exists {
+		t.mu.Unlock()
+		return false
+	}
+	delete(t.offers, offerID)
+	hostKey := record.Provider + ":" + record.MachineID
+	if host, ok := t.hosts[hostKey]; ok && record.MachineID != "" {
+		var kept []hostFailureEvent
+		for _, f := range host.Failures {
+			if f.OfferID != offerID {
+				kept = append(kept, f)
+			}
+		}
+		host.Failures = kept
+		if len(kept) == 0 {
+			delete(t.hosts, hostKey)
+		}
+	}
+	gpuKey := record.Provider + ":" + record.GPUType
+	if gpuRec, ok := t.gpuTypes[gpuKey]; ok {
+		delete(gpuRec.FailedOfferIDs, offerID)
+		if len(gpuRec.FailedOfferIDs) == 0 {
+			delete(t.gpuTypes, gpuKey)
+		}
+	}
+	store := t.store
+	t.mu.Unlock()
+
+	if store != nil {
+		if err := store.ClearSuppression(ctx, offerID); err != nil {
+			t.logger.Warn("failed to clear suppression from store",
+				slog.String("offer_id", offerID),
+				slog.String("error", err.Error()))
+		}
+		if err := store.ClearFailures(ctx, offerID); err != nil {
+			t.logger.Warn("failed to clear offer failures from store",
+				slog.String("offer_id", offerID),
+				slog.String("error", err.Error()))
+		}
+	}
+	return true
+}
+
+// SetOfferRule validates and stores an operator suppress/allow rule. A
+// positive duration makes the rule expire; zero keeps it until removed.
+func (s *Service) SetOfferRule(ctx context.Context, rule *models.OfferRule, duration time.Duration) error {
+	rule.Value = strings.TrimSpace(rule.Value)
+	rule.Action = models.OfferRuleAction(strings.ToLower(string(rule.Action)))
+	rule.Scope = models.OfferRuleScope(strings.ToLower(string(rule.Scope)))
+	rule.Provider = strings.ToLower(strings.TrimSpace(rule.Provider))
+	if !rule.IsValid() {
+		return &InvalidOfferRuleError{Reason: "action must be suppress or allow, scope must be offer, machine or location, and value is required"}
+	}
+	if duration < 0 {
+		return &InvalidOfferRuleError{Reason: "duration must not be negative"}
+	}
+
+	rule.ID = uuid.New().String()
+	rule.CreatedAt = time.Now().UTC()
+	rule.ExpiresAt = nil
+	if duration > 0 {
+		expiresAt := rule.CreatedAt.Add(duration)
+		rule.ExpiresAt = &expiresAt
+	}
+
+	if err := s.failureTracker.SetRule(ctx, rule); err != nil {
+		return err
+	}
+	s.logger.Info("offer rule set",
+		slog.String("rule_id", rule.ID),
+		slog.String("action", string(rule.Action)),
+		slog.String("scope", string(rule.Scope)),
+		slog.String("value", rule.Value),
+		slog.Duration("duration", duration))
+	return nil
+}
+
+// DeleteOfferRule removes an operator rule
+func (s *Service) DeleteOfferRule(ctx context.Context, id string) error {
+	if err := s.failureTracker.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("offer rule deleted", slog.String("rule_id", id))
+	return nil
+}
+
+// ListOfferRules returns the active operator rules, oldest first
+func (s *Service) ListOfferRules() []models.OfferRule {
+	return s.failureTracker.Rules()
+}
+
+// ListSuppressedOffers returns health data for offers currently suppressed
+// by failure tracking
+func (s *Service) ListSuppressedOffers() []OfferHealthInfo {
+	offers, _ := s.failureTracker.GetAllHealth()
+	suppressed := make([]OfferHealthInfo, 0, len(offers))
+	for _, offer := range offers {
+		if offer.IsSuppressed {
+			suppressed = append(suppressed, offer)
+		}
+	}
+	sort.Slice(suppressed, func(i, j int) bool {
+		return suppressed[i].OfferID < suppressed[j].OfferID
+	})
+	return suppressed
+}
+
+// ListSuppressedHosts returns health data for hosts currently suppressed by
+// failure tracking
+func (s *Service) ListSuppressedHosts() []HostHealthInfo {
+	hosts := s.failureTracker.GetHostHealth()
+	suppressed := make([]HostHealthInfo, 0, len(hosts))
+	for _, host := range hosts {
+		if host.IsSuppressed {
+			suppressed = append(suppressed, host)
+		}
+	}
+	sort.Slice(suppressed, func(i, j int) bool {
+		return suppressed[i].Provider+":"+suppressed[i].MachineID < suppressed[j].Provider+":"+suppressed[j].MachineID
+	})
+	return suppressed
+}
+
+// ClearOfferSuppression lifts an offer's automatic suppression and resets
+// its failure history
+func (s *Service) ClearOfferSuppression(ctx context.Context, offerID string) error {
+	if !s.failureTracker.ClearSuppression(ctx, offerID) {
+		return &OfferNotFoundError{ID: offerID}
+	}
+	s.logger.Info("offer suppression cleared", slog.String("offer_id", offerID))
+	return nil
+}
+
+// LoadOfferRules loads persisted operator rules into the failure tracker.
+// Call this once at startup after the service is created.
+func (s *Service) LoadOfferRules(rules []*models.OfferRule) {
+	s.failureTracker.LoadRules(rules)
+}
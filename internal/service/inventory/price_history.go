@@ -0,0 +1,108 @@
+package inventory
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// PriceHistoryCompactInterval is the minimum time between price history compactions
+const PriceHistoryCompactInterval = time.Hour
+
+// PriceHistoryStore persists offer price snapshots
+type PriceHistoryStore interface {
+	RecordPriceSnapshots(ctx context.Context, snapshots []models.PriceSnapshot) error
+	ListPriceHistory(ctx context.Context, filter models.PriceHistoryFilter) ([]models.PriceSnapshot, error)
+	CompactPriceHistory(ctx context.Context, now time.Time) error
+}
+
+// WithPriceHistory records a price snapshot per GPU type on every successful
+// provider fetch
+func WithPriceHistory(store PriceHistoryStore) Option {
+	return func(s *Service) {
+		s.priceHistory = store
+	}
+}
+
+// PriceHistory returns recorded price snapshots, oldest first
+func (s *Service) PriceHistory(ctx context.Context, filter models.PriceHistoryFilter) ([]models.PriceSnapshot, error) {
+	if s.priceHistory == nil {
+		return nil, ErrPriceHistoryDisabled
+	}
+	return s.priceHistory.ListPriceHistory(ctx, filter)
+}
+
+// recordPriceSnapshots stores min/avg/max per-GPU prices of the available
+// offers of each GPU type, and compacts old history at most once per
+// PriceHistoryCompactInterval. Failures are logged; they never fail a fetch.
+func (s *Service) recordPriceSnapshots(ctx context.Context, providerName string, offers []models.GPUOffer, now time.Time) {
+	if s.priceHistory == nil {
+		return
+	}
+
+	snapshots := summarizePrices(providerName, offers, now)
+	if err := s.priceHistory.RecordPriceSnapshots(ctx, snapshots); err != nil {
+		s.logger.Warn("failed to record price history",
+			slog.String("provider", providerName),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	due := now.Sub(s.lastPriceCompaction) >= PriceHistoryCompactInterval
+	if due {
+		s.lastPriceCompaction = now
+	}
+	s.mu.Unlock()
+
+	if due {
+		if err := s.priceHistory.CompactPriceHistory(ctx, now); err != nil {
+			s.logger.Warn("failed to compact price history", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// summarizePrices aggregates available offers into one snapshot per GPU type
+func summarizePrices(providerName string, offers []models.GPUOffer, now time.Time) []models.PriceSnapshot {
+	byType := make(map[string]*models.PriceSnapshot)
+	var order []string
+
+	for _, offer := range offers {
+		if !offer.Available || offer.GPUType == "" || offer.PricePerHour <= 0 {
+			continue
+		}
+		price := offer.PricePerGPUHour()
+
+		snap, ok := byType[offer.GPUType]
+		if !ok {
+			snap = &models.PriceSnapshot{
+				Provider:  providerName,
+				GPUType:   offer.GPUType,
+				MinPrice:  price,
+				MaxPrice:  price,
+				SampledAt: now,
+			}
+			byType[offer.GPUType] = snap
+			order = append(order, offer.GPUType)
+		}
+		if price < snap.MinPrice {
+			snap.MinPrice = price
+		}
+		if price > snap.MaxPrice {
+			snap.MaxPrice = price
+		}
+		// AvgPrice holds the sum until all offers are counted
+		snap.AvgPrice += price
+		snap.OfferCount++
+	}
+
+	snapshots := make([]models.PriceSnapshot, 0, len(order))
+	for _, gpuType := range order {
+		snap := byType[gpuType]
+		snap.AvgPrice /= float64(snap.OfferCount)
+		snapshots = append(snapshots, *snap)
+	}
+	return snapshots
+}
@@ -0,0 +1,109 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPriceHistoryStore implements PriceHistoryStore for testing
+type mockPriceHistoryStore struct {
+	mu          sync.Mutex
+	snapshots   []models.PriceSnapshot
+	compactions int
+}
+
+func (m *mockPriceHistoryStore) RecordPriceSnapshots(ctx context.Context, snapshots []models.PriceSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots = append(m.snapshots, snapshots...)
+	return nil
+}
+
+func (m *mockPriceHistoryStore) ListPriceHistory(ctx context.Context, filter models.PriceHistoryFilter) ([]models.PriceSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []models.PriceSnapshot
+	for _, snap := range m.snapshots {
+		if snap.GPUType == filter.GPUType {
+			result = append(result, snap)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPriceHistoryStore) CompactPriceHistory(ctx context.Context, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.compactions++
+	return nil
+}
+
+func TestSummarizePrices(t *testing.T) {
+	now := time.Now()
+	offers := []models.GPUOffer{
+		{ID: "1", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.40, Available: true},
+		{ID: "2", GPUType: "RTX4090", GPUCount: 2, PricePerHour: 1.20, Available: true}, // 0.60 per GPU
+		{ID: "3", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.10, Available: false},
+		{ID: "4", GPUType: "A100", GPUCount: 1, PricePerHour: 1.50, Available: true},
+		{ID: "5", GPUType: "", GPUCount: 1, PricePerHour: 0.20, Available: true},
+	}
+
+	snapshots := summarizePrices("vastai", offers, now)
+	require.Len(t, snapshots, 2)
+
+	rtx := snapshots[0]
+	assert.Equal(t, "RTX4090", rtx.GPUType)
+	assert.Equal(t, "vastai", rtx.Provider)
+	assert.Equal(t, 0.40, rtx.MinPrice)
+	assert.Equal(t, 0.60, rtx.MaxPrice)
+	assert.InDelta(t, 0.50, rtx.AvgPrice, 1e-9)
+	assert.Equal(t, 2, rtx.OfferCount)
+	assert.Equal(t, now, rtx.SampledAt)
+
+	assert.Equal(t, "A100", snapshots[1].GPUType)
+	assert.Equal(t, 1, snapshots[1].OfferCount)
+}
+
+func TestService_RecordsPriceHistoryOnFetch(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.50, Available: true},
+	}
+	p := &mockProvider{name: "vastai", offers: offers}
+	store := &mockPriceHistoryStore{}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(time.Hour),
+		WithPriceHistory(store),
+		WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	_, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+
+	// Cached reads don't record again
+	_, err = svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+
+	// Location-filtered fetches are not representative and are skipped
+	_, err = svc.ListOffers(ctx, models.OfferFilter{Location: "US"})
+	require.NoError(t, err)
+
+	history, err := svc.PriceHistory(ctx, models.PriceHistoryFilter{GPUType: "RTX4090"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 0.50, history[0].MinPrice)
+	assert.Equal(t, 1, store.compactions)
+}
+
+func TestService_PriceHistoryDisabled(t *testing.T) {
+	svc := New(nil, WithLogger(newTestLogger()))
+
+	_, err := svc.PriceHistory(context.Background(), models.PriceHistoryFilter{GPUType: "RTX4090"})
+	assert.ErrorIs(t, err, ErrPriceHistoryDisabled)
+}
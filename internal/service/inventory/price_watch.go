@@ -0,0 +1,176 @@
+package inventory
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// MaxPriceWatchesPerConsumer caps how many watches a single consumer can hold
+const MaxPriceWatchesPerConsumer = 50
+
+// PriceWatchStore persists consumer price watches
+type PriceWatchStore interface {
+	CreatePriceWatch(ctx context.Context, watch *models.PriceWatch) error
+	ListPriceWatches(ctx context.Context, consumerID string) ([]*models.PriceWatch, error)
+	SetPriceWatchMatching(ctx context.Context, id string, matching bool, notifiedAt *time.Time) error
+	DeletePriceWatch(ctx context.Context, id string) error
+}
+
+// PriceWatchNotifier delivers price watch alerts
+type PriceWatchNotifier interface {
+	NotifyPriceWatch(ctx context.Context, alert models.PriceWatchAlert) error
+}
+
+// WithPriceWatches evaluates consumer price watches on every successful
+// unfiltered provider fetch and notifies when a watch starts matching
+func WithPriceWatches(store PriceWatchStore, notifier PriceWatchNotifier) Option {
+	return func(s *Service) {
+		s.priceWatches = store
+		s.watchNotifier = notifier
+	}
+}
+
+// CreatePriceWatch validates and stores a new price watch
+func (s *Service) CreatePriceWatch(ctx context.Context, watch *models.PriceWatch) error {
+	if s.priceWatches == nil {
+		return ErrPriceWatchesDisabled
+	}
+	if watch.ConsumerID == "" {
+		return &InvalidPriceWatchError{Reason: "consumer_id is required"}
+	}
+	if watch.GPUType == "" {
+		return &InvalidPriceWatchError{Reason: "gpu_type is required"}
+	}
+	if watch.MaxPrice <= 0 {
+		return &InvalidPriceWatchError{Reason: "max_price must be positive"}
+	}
+	if watch.Provider != "" && !s.hasProvider(watch.Provider) {
+		return &ProviderNotFoundError{Name: watch.Provider}
+	}
+
+	existing, err := s.priceWatches.ListPriceWatches(ctx, watch.ConsumerID)
+	if err != nil {
+		return err
+	}
+	if len(existing) >= MaxPriceWatchesPerConsumer {
+		return &InvalidPriceWatchError{Reason: "too many price watches for consumer"}
+	}
+
+	watch.Matching = false
+	watch.NotifiedAt = nil
+	return s.priceWatches.CreatePriceWatch(ctx, watch)
+}
+
+// ListPriceWatches returns the price watches of a consumer, or all watches
+// when consumerID is empty
+func (s *Service) ListPriceWatches(ctx context.Context, consumerID string) ([]*models.PriceWatch, error) {
+	if s.priceWatches == nil {
+		return nil, ErrPriceWatchesDisabled
+	}
+	return s.priceWatches.ListPriceWatches(ctx, consumerID)
+}
+
+// DeletePriceWatch removes a price watch
+func (s *Service) DeletePriceWatch(ctx context.Context, id string) error {
+	if s.priceWatches == nil {
+		return ErrPriceWatchesDisabled
+	}
+	return s.priceWatches.DeletePriceWatch(ctx, id)
+}
+
+func (s *Service) hasProvider(name string) bool {
+	for _, p := range s.providers {
+		if p.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluatePriceWatches checks every watch against the freshly fetched offers
+// of one provider plus the cached unfiltered offers of the others. Alerts are
+// edge-triggered: a watch notifies when it goes from no match to a match, and
+// re-arms once no offer matches. Failures are logged; they never fail a fetch.
+func (s *Service) evaluatePriceWatches(ctx context.Context, providerName string, offers []models.GPUOffer, now time.Time) {
+	if s.priceWatches == nil {
+		return
+	}
+
+	// Concurrent fetches from different providers would otherwise race on
+	// the matching flag and notify twice
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	watches, err := s.priceWatches.ListPriceWatches(ctx, "")
+	if err != nil {
+		s.logger.Warn("failed to list price watches", slog.String("error", err.Error()))
+		return
+	}
+	if len(watches) == 0 {
+		return
+	}
+
+	all := append([]models.GPUOffer(nil), offers...)
+	s.mu.RLock()
+	for _, p := range s.providers {
+		name := p.Name()
+		if name == providerName {
+			continue
+		}
+		if cached, ok := s.cache[cacheKey(name, models.OfferFilter{})]; ok && cached.err == nil {
+			all = append(all, cached.offers...)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, watch := range watches {
+		var best *models.GPUOffer
+		count := 0
+		for i := range all {
+			if !watch.Matches(all[i]) {
+				continue
+			}
+			count++
+			if best == nil || all[i].PricePerGPUHour() < best.PricePerGPUHour() {
+				best = &all[i]
+			}
+		}
+
+		switch {
+		case best != nil && !watch.Matching:
+			alert := models.PriceWatchAlert{Watch: watch, Offer: *best, MatchCount: count}
+			if s.watchNotifier != nil {
+				if err := s.watchNotifier.NotifyPriceWatch(ctx, alert); err != nil {
+					// Stay armed so the next refresh retries
+					s.logger.Warn("failed to send price watch alert",
+						slog.String("watch_id", watch.ID),
+						slog.String("error", err.Error()))
+					continue
+				}
+			}
+			notifiedAt := now
+			watch.Matching = true
+			watch.NotifiedAt = &notifiedAt
+			if err := s.priceWatches.SetPriceWatchMatching(ctx, watch.ID, true, &notifiedAt); err != nil {
+				s.logger.Warn("failed to update price watch",
+					slog.String("watch_id", watch.ID),
+					slog.String("error", err.Error()))
+			}
+			s.logger.Info("price watch matched",
+				slog.String("watch_id", watch.ID),
+				slog.String("consumer_id", watch.ConsumerID),
+				slog.String("offer_id", best.ID),
+				slog.Float64("price_per_gpu_hour", best.PricePerGPUHour()))
+		case best == nil && watch.Matching:
+			watch.Matching = false
+			if err := s.priceWatches.SetPriceWatchMatching(ctx, watch.ID, false, nil); err != nil {
+				s.logger.Warn("failed to update price watch",
+					slog.String("watch_id", watch.ID),
+					slog.String("error", err.Error()))
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPriceWatchStore implements PriceWatchStore for testing
+type mockPriceWatchStore struct {
+	mu      sync.Mutex
+	watches []*models.PriceWatch
+}
+
+func (m *mockPriceWatchStore) CreatePriceWatch(ctx context.Context, watch *models.PriceWatch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if watch.ID == "" {
+		watch.ID = "watch-" + watch.GPUType
+	}
+	m.watches = append(m.watches, watch)
+	return nil
+}
+
+func (m *mockPriceWatchStore) ListPriceWatches(ctx context.Context, consumerID string) ([]*models.PriceWatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.PriceWatch
+	for _, w := range m.watches {
+		if consumerID == "" || w.ConsumerID == consumerID {
+			copied := *w
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPriceWatchStore) SetPriceWatchMatching(ctx context.Context, id string, matching bool, notifiedAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, w := range m.watches {
+		if w.ID == id {
+			w.Matching = matching
+			if notifiedAt != nil {
+				w.NotifiedAt = notifiedAt
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockPriceWatchStore) DeletePriceWatch(ctx context.Context, id string) error {
+	return nil
+}
+
+// mockPriceWatchNotifier records alerts
+type mockPriceWatchNotifier struct {
+	mu     sync.Mutex
+	alerts []models.PriceWatchAlert
+}
+
+func (m *mockPriceWatchNotifier) NotifyPriceWatch(ctx context.Context, alert models.PriceWatchAlert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alerts = append(m.alerts, alert)
+	return nil
+}
+
+func TestPriceWatch_Matches(t *testing.T) {
+	watch := &models.PriceWatch{GPUType: "RTX 4090", MaxPrice: 0.40, Region: "us"}
+
+	assert.True(t, watch.Matches(models.GPUOffer{GPUType: "rtx 4090", GPUCount: 2, PricePerHour: 0.70, Location: "US-East", Available: true}))
+	assert.False(t, watch.Matches(models.GPUOffer{GPUType: "RTX 4090", GPUCount: 1, PricePerHour: 0.50, Location: "US", Available: true}))
+	assert.False(t, watch.Matches(models.GPUOffer{GPUType: "RTX 4090", GPUCount: 1, PricePerHour: 0.30, Location: "EU", Available: true}))
+	assert.False(t, watch.Matches(models.GPUOffer{GPUType: "RTX 4090", GPUCount: 1, PricePerHour: 0.30, Location: "US", Available: false}))
+	assert.False(t, watch.Matches(models.GPUOffer{GPUType: "A100", GPUCount: 1, PricePerHour: 0.30, Location: "US", Available: true}))
+
+	watch.Provider = "vastai"
+	assert.False(t, watch.Matches(models.GPUOffer{Provider: "tensordock", GPUType: "RTX 4090", GPUCount: 1, PricePerHour: 0.30, Location: "US", Available: true}))
+}
+
+func TestService_PriceWatchNotifiesOnMatch(t *testing.T) {
+	p := &mockProvider{name: "vastai", offers: []models.GPUOffer{
+		{ID: "expensive", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.80, Location: "US", Available: true},
+	}}
+	store := &mockPriceWatchStore{}
+	notifier := &mockPriceWatchNotifier{}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(time.Hour),
+		WithPriceWatches(store, notifier),
+		WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	require.NoError(t, svc.CreatePriceWatch(ctx, &models.PriceWatch{ConsumerID: "consumer-1", GPUType: "RTX4090", MaxPrice: 0.50}))
+
+	refresh := func() {
+		svc.InvalidateCache("vastai")
+		_, err := svc.ListOffers(ctx, models.OfferFilter{})
+		require.NoError(t, err)
+	}
+
+	refresh()
+	assert.Empty(t, notifier.alerts, "no offer under the limit yet")
+
+	p.offers = append(p.offers,
+		models.GPUOffer{ID: "cheap", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.45, Location: "US", Available: true},
+		models.GPUOffer{ID: "cheaper", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.40, Location: "US", Available: true},
+	)
+	refresh()
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, "cheaper", notifier.alerts[0].Offer.ID)
+	assert.Equal(t, 2, notifier.alerts[0].MatchCount)
+	assert.Equal(t, "consumer-1", notifier.alerts[0].Watch.ConsumerID)
+
+	// Still matching: no repeat alert
+	refresh()
+	assert.Len(t, notifier.alerts, 1)
+
+	// Filtered fetches don't re-arm the watch
+	_, err := svc.ListOffers(ctx, models.OfferFilter{GPUType: "A100"})
+	require.NoError(t, err)
+	assert.True(t, store.watches[0].Matching)
+
+	// Offers disappear, then return: the watch re-arms and fires again
+	p.offers = p.offers[:1]
+	refresh()
+	assert.False(t, store.watches[0].Matching)
+	p.offers = append(p.offers, models.GPUOffer{ID: "back", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.30, Location: "US", Available: true})
+	refresh()
+	require.Len(t, notifier.alerts, 2)
+	assert.Equal(t, "back", notifier.alerts[1].Offer.ID)
+}
+
+func TestService_CreatePriceWatchValidation(t *testing.T) {
+	p := &mockProvider{name: "vastai"}
+	svc := New([]provider.Provider{p}, WithPriceWatches(&mockPriceWatchStore{}, nil), WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	var invalid *InvalidPriceWatchError
+	assert.ErrorAs(t, svc.CreatePriceWatch(ctx, &models.PriceWatch{GPUType: "RTX4090", MaxPrice: 1}), &invalid)
+	assert.ErrorAs(t, svc.CreatePriceWatch(ctx, &models.PriceWatch{ConsumerID: "c", MaxPrice: 1}), &invalid)
+	assert.ErrorAs(t, svc.CreatePriceWatch(ctx, &models.PriceWatch{ConsumerID: "c", GPUType: "RTX4090"}), &invalid)
+
+	var notFound *ProviderNotFoundError
+	assert.ErrorAs(t, svc.CreatePriceWatch(ctx, &models.PriceWatch{ConsumerID: "c", GPUType: "RTX4090", MaxPrice: 1, Provider: "nope"}), &notFound)
+
+	disabled := New(nil, WithLogger(newTestLogger()))
+	assert.ErrorIs(t, disabled.CreatePriceWatch(ctx, &models.PriceWatch{}), ErrPriceWatchesDisabled)
+}
@@ -0,0 +1,155 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+)
+
+// Provider health statuses
+const (
+	ProviderHealthy  = "healthy"
+	ProviderDegraded = "degraded"
+	ProviderDown     = "down"
+)
+
+const (
+	// DegradedErrorRate is the share of failed API calls within
+	// provider.APIHealthWindow at which a provider is reported degraded
+	DegradedErrorRate = 0.25
+
+	// minCallsForErrorRate keeps a single failed call from marking a
+	// quiet provider degraded
+	minCallsForErrorRate = 4
+
+	// balanceTimeout bounds each provider's balance lookup
+	balanceTimeout = 5 * time.Second
+)
+
+// ProviderHealth is the current health of one provider, combining its API
+// client's circuit breaker and call history with the inventory cache
+type ProviderHealth struct {
+	Provider string   `json:"provider"`
+	Status   string   `json:"status"`            // healthy, degraded or down
+	Reasons  []string `json:"reasons,omitempty"` // Why the provider is not healthy
+
+	// API client state; omitted for providers that do not report it
+	CircuitState           string     `json:"circuit_state,omitempty"`
+	LastSuccessAt          *time.Time `json:"last_success_at,omitempty"`
+	LastErrorAt            *time.Time `json:"last_error_at,omitempty"`
+	LastError              string     `json:"last_error,omitempty"`
+	RecentCalls            int        `json:"recent_calls"`
+	RecentFailures         int        `json:"recent_failures"`
+	RecentErrorRate        float64    `json:"recent_error_rate"`
+	ErrorRateWindowMinutes int        `json:"error_rate_window_minutes"`
+
+	// Inventory cache of the provider's unfiltered catalogue
+	CacheFetchedAt    *time.Time `json:"cache_fetched_at,omitempty"`
+	CacheAgeSeconds   float64    `json:"cache_age_seconds"`
+	CacheOfferCount   int        `json:"cache_offer_count"`
+	CacheServingStale bool       `json:"cache_serving_stale"`
+
+	// Account balance, for providers that report it
+	Balance      *float64 `json:"balance,omitempty"`
+	Currency     string   `json:"currency,omitempty"`
+	BalanceError string   `json:"balance_error,omitempty"`
+}
+
+// GetProviderHealth returns the health of every provider, in registration
+// order. Balances are fetched live from providers that support them.
+func (s *Service) GetProviderHealth(ctx context.Context) []ProviderHealth {
+	cacheStatus := s.GetProviderCacheStatus()
+	health := make([]ProviderHealth, len(s.providers))
+
+	var wg sync.WaitGroup
+	for i, p := range s.providers {
+		wg.Add(1)
+		go func(i int, p provider.Provider) {
+			defer wg.Done()
+			health[i] = s.providerHealth(ctx, p, cacheStatus)
+		}(i, p)
+	}
+	wg.Wait()
+	return health
+}
+
+func (s *Service) providerHealth(ctx context.Context, p provider.Provider, cacheStatus map[string]CacheStatus) ProviderHealth {
+	h := ProviderHealth{
+		Provider:               p.Name(),
+		Status:                 ProviderHealthy,
+		ErrorRateWindowMinutes: int(provider.APIHealthWindow / time.Minute),
+	}
+
+	if reporter, ok := provider.As[provider.HealthReporter](p); ok {
+		api := reporter.APIHealth()
+		h.CircuitState = api.CircuitState
+		h.LastSuccessAt = timePtr(api.LastSuccessAt)
+		h.LastErrorAt = timePtr(api.LastErrorAt)
+		h.LastError = api.LastError
+		h.RecentCalls = api.RecentCalls
+		h.RecentFailures = api.RecentFailures
+		h.RecentErrorRate = api.ErrorRate()
+
+		switch api.CircuitState {
+		case provider.CircuitStateOpen:
+			h.markDown("circuit breaker is open")
+		case provider.CircuitStateHalfOpen:
+			h.markDegraded("circuit breaker is half-open")
+		}
+		if api.RecentCalls >= minCallsForErrorRate && api.ErrorRate() >= DegradedErrorRate {
+			h.markDegraded(fmt.Sprintf("%d of %d API calls failed in the last %d minutes",
+				api.RecentFailures, api.RecentCalls, h.ErrorRateWindowMinutes))
+		}
+	}
+
+	if cached, ok := cacheStatus[p.Name()]; ok {
+		fetchedAt := cached.FetchedAt
+		h.CacheFetchedAt = &fetchedAt
+		h.CacheAgeSeconds = cached.AgeSeconds
+		h.CacheOfferCount = cached.OfferCount
+		h.CacheServingStale = cached.InBackoff && !cached.HasError
+		switch {
+		case cached.HasError:
+			h.markDown("inventory fetch is failing")
+		case h.CacheServingStale:
+			h.markDegraded("inventory fetch is failing, serving last good offers")
+		}
+	}
+
+	if bp, ok := provider.As[provider.BalanceProvider](p); ok {
+		balanceCtx, cancel := context.WithTimeout(ctx, balanceTimeout)
+		defer cancel()
+		if balance, err := bp.GetAccountBalance(balanceCtx); err != nil {
+			h.BalanceError = err.Error()
+		} else {
+			b := balance.Balance
+			h.Balance = &b
+			h.Currency = balance.Currency
+		}
+	}
+
+	return h
+}
+
+func (h *ProviderHealth) markDown(reason string) {
+	h.Status = ProviderDown
+	h.Reasons = append(h.Reasons, reason)
+}
+
+func (h *ProviderHealth) markDegraded(reason string) {
+	if h.Status == ProviderHealthy {
+		h.Status = ProviderDegraded
+	}
+	h.Reasons = append(h.Reasons, reason)
+}
+
+// timePtr returns nil for the zero time
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportingProvider is a mockProvider that reports API health and a balance
+type reportingProvider struct {
+	mockProvider
+	api        provider.APIHealth
+	balance    float64
+	balanceErr error
+}
+
+func (r *reportingProvider) APIHealth() provider.APIHealth { return r.api }
+
+func (r *reportingProvider) GetAccountBalance(ctx context.Context) (*provider.AccountBalance, error) {
+	if r.balanceErr != nil {
+		return nil, r.balanceErr
+	}
+	return &provider.AccountBalance{Balance: r.balance, Currency: "USD"}, nil
+}
+
+func TestService_GetProviderHealth(t *testing.T) {
+	lastSuccess := time.Now().Add(-time.Minute)
+	healthy := &reportingProvider{
+		mockProvider: mockProvider{name: "vastai", offers: []models.GPUOffer{
+			{ID: "offer-1", Provider: "vastai", Available: true, FetchedAt: time.Now()},
+		}},
+		api:     provider.APIHealth{CircuitState: provider.CircuitStateClosed, LastSuccessAt: lastSuccess, RecentCalls: 10, RecentFailures: 1},
+		balance: 42.5,
+	}
+	flaky := &reportingProvider{
+		mockProvider: mockProvider{name: "tensordock"},
+		api:          provider.APIHealth{CircuitState: provider.CircuitStateClosed, RecentCalls: 8, RecentFailures: 4},
+		balanceErr:   errors.New("balance check failed: status 500"),
+	}
+	broken := &reportingProvider{
+		mockProvider: mockProvider{name: "bluelobster", err: errors.New("503 service unavailable")},
+		api:          provider.APIHealth{CircuitState: provider.CircuitStateOpen},
+	}
+	plain := &mockProvider{name: "static"}
+
+	svc := New([]provider.Provider{healthy, flaky, broken, plain}, WithLogger(newTestLogger()))
+	defer svc.Shutdown()
+	_, err := svc.ListOffers(context.Background(), models.OfferFilter{})
+	require.NoError(t, err)
+
+	health := svc.GetProviderHealth(context.Background())
+	require.Len(t, health, 4)
+
+	vast := health[0]
+	assert.Equal(t, "vastai", vast.Provider)
+	assert.Equal(t, ProviderHealthy, vast.Status)
+	assert.Empty(t, vast.Reasons)
+	assert.Equal(t, provider.CircuitStateClosed, vast.CircuitState)
+	require.NotNil(t, vast.LastSuccessAt)
+	assert.WithinDuration(t, lastSuccess, *vast.LastSuccessAt, 0)
+	assert.Nil(t, vast.LastErrorAt)
+	assert.InDelta(t, 0.1, vast.RecentErrorRate, 0.001)
+	assert.Equal(t, 1, vast.CacheOfferCount)
+	assert.NotNil(t, vast.CacheFetchedAt)
+	require.NotNil(t, vast.Balance)
+	assert.Equal(t, 42.5, *vast.Balance)
+	assert.Equal(t, "USD", vast.Currency)
+
+	td := health[1]
+	assert.Equal(t, ProviderDegraded, td.Status)
+	assert.Equal(t, []string{"4 of 8 API calls failed in the last 15 minutes"}, td.Reasons)
+	assert.Nil(t, td.Balance)
+	assert.Contains(t, td.BalanceError, "status 500")
+
+	bl := health[2]
+	assert.Equal(t, ProviderDown, bl.Status)
+	assert.Equal(t, []string{"circuit breaker is open", "inventory fetch is failing"}, bl.Reasons)
+
+	static := health[3]
+	assert.Equal(t, ProviderHealthy, static.Status)
+	assert.Empty(t, static.CircuitState)
+	assert.Nil(t, static.Balance)
+}
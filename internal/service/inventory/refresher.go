@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// MinRefreshInterval bounds how often the background refresher polls a
+// single provider, however short its cache TTL
+const MinRefreshInterval = 5 * time.Second
+
+// StartRefresher keeps every provider's unfiltered catalogue cached by
+// refreshing it in the background as it reaches soft expiry, so inventory
+// requests are served from a warm cache instead of waiting on provider APIs.
+// Each provider runs on its own schedule: its cache TTL while healthy, the
+// backoff TTL after a failed fetch. Filtered queries still cache lazily.
+// The refreshers stop, abandoning any fetch in flight, when ctx is done or
+// the service shuts down.
+func (s *Service) StartRefresher(ctx context.Context) {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+
+	// Stop with the service as well as with ctx
+	ctx, cancel := context.WithCancel(ctx)
+	context.AfterFunc(s.ctx, cancel)
+
+	for _, p := range s.providers {
+		s.refreshers.Add(1)
+		go s.runRefresher(ctx, p)
+	}
+	s.logger.Info("inventory background refresh started", slog.Int("providers", len(s.providers)))
+}
+
+func (s *Service) runRefresher(ctx context.Context, p provider.Provider) {
+	defer s.refreshers.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+
+		s.refreshProvider(ctx, p)
+		timer.Reset(s.nextRefresh(p.Name()))
+	}
+}
+
+// refreshProvider fetches a provider's unfiltered offers into the cache,
+// joining any request-driven fetch already in flight. Errors are logged and
+// cached by fetchOffers.
+func (s *Service) refreshProvider(ctx context.Context, p provider.Provider) {
+	filter := models.OfferFilter{}
+	key := cacheKey(p.Name(), filter)
+	_, _, _ = s.fetches.do(ctx, key, func() ([]models.GPUOffer, error) {
+		return s.fetchOffers(ctx, p, filter)
+	})
+}
+
+// nextRefresh returns how long to wait before refreshing a provider again:
+// until its cached catalogue reaches soft expiry
+func (s *Service) nextRefresh(providerName string) time.Duration {
+	s.mu.RLock()
+	cached, exists := s.cache[cacheKey(providerName, models.OfferFilter{})]
+	s.mu.RUnlock()
+
+	if !exists {
+		return MinRefreshInterval
+	}
+	if wait := time.Until(cached.softExpiry); wait > MinRefreshInterval {
+		return wait
+	}
+	return MinRefreshInterval
+}
+
+// GetProviderCacheStatus returns the cache status of each provider's
+// unfiltered catalogue, keyed by provider name. Providers that have not been
+// fetched yet are omitted.
+func (s *Service) GetProviderCacheStatus() map[string]CacheStatus {
+	all := s.GetCacheStatus()
+	status := make(map[string]CacheStatus, len(s.providers))
+	for _, p := range s.providers {
+		if st, ok := all[cacheKey(p.Name(), models.OfferFilter{})]; ok {
+			status[p.Name()] = st
+		}
+	}
+	return status
+}
+
+// StaleProviders returns the providers, sorted by name, whose offers for
+// filter are the last good offers served while the provider is failing
+func (s *Service) StaleProviders(filter models.OfferFilter) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stale []string
+	for _, p := range s.providers {
+		if filter.Provider != "" && p.Name() != filter.Provider {
+			continue
+		}
+		cached, ok := s.cache[cacheKey(p.Name(), upstreamFilter(p, filter))]
+		if ok && cached.servingStale() {
+			stale = append(stale, p.Name())
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// servingStale reports whether the entry holds last good offers kept after
+// the provider started failing
+func (c *providerCache) servingStale() bool {
+	return c.inBackoff && c.err == nil && len(c.offers) > 0
+}
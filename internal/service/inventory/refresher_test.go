@@ -0,0 +1,102 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RefresherWarmsCache(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", Available: true, FetchedAt: time.Now()},
+	}
+	vast := &mockProvider{name: "vastai", offers: offers}
+	td := &mockProvider{name: "tensordock", offers: offers}
+	svc := New([]provider.Provider{vast, td},
+		WithCacheTTL(time.Minute),
+		WithProviderCacheTTL("tensordock", 20*time.Second),
+		WithLogger(newTestLogger()))
+
+	svc.StartRefresher(context.Background())
+	svc.StartRefresher(context.Background()) // Second start is a no-op
+
+	require.Eventually(t, func() bool {
+		return len(svc.GetProviderCacheStatus()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), vast.callCount.Load())
+	assert.Equal(t, int32(1), td.callCount.Load())
+
+	// Requests are served from the warm cache
+	_, err := svc.ListOffers(context.Background(), models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), vast.callCount.Load())
+
+	// Each provider is refreshed at its own soft expiry (75% of its TTL)
+	assert.InDelta(t, 45*time.Second, svc.nextRefresh("vastai"), float64(time.Second))
+	assert.InDelta(t, 15*time.Second, svc.nextRefresh("tensordock"), float64(time.Second))
+	assert.Equal(t, MinRefreshInterval, svc.nextRefresh("unknown"))
+
+	// Shutdown stops the refreshers
+	svc.Shutdown()
+	assert.NotPanics(t, svc.Shutdown)
+}
+
+func TestService_RefresherBacksOffOnError(t *testing.T) {
+	p := &mockProvider{name: "vastai", err: errors.New("503 service unavailable")}
+	svc := New([]provider.Provider{p},
+		WithBackoffTTL(2*time.Minute),
+		WithLogger(newTestLogger()))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	svc.StartRefresher(ctx)
+	require.Eventually(t, func() bool {
+		return svc.GetProviderCacheStatus()["vastai"].HasError
+	}, time.Second, 10*time.Millisecond)
+
+	// A failing provider is retried after the backoff TTL
+	assert.InDelta(t, 2*time.Minute, svc.nextRefresh("vastai"), float64(time.Second))
+
+	cancel()
+	svc.Shutdown()
+}
+
+func TestService_StaleProviders(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", Available: true, FetchedAt: time.Now()},
+	}
+	vast := &mockProvider{name: "vastai", offers: offers, queryFilters: true}
+	td := &mockProvider{name: "tensordock", offers: []models.GPUOffer{
+		{ID: "offer-2", Provider: "tensordock", GPUType: "RTX4090", Available: true, FetchedAt: time.Now()},
+	}}
+	svc := New([]provider.Provider{vast, td},
+		WithCacheTTL(50*time.Millisecond),
+		WithBackoffTTL(time.Second),
+		WithLogger(newTestLogger()))
+	defer svc.Shutdown()
+
+	ctx := context.Background()
+	_, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, svc.StaleProviders(models.OfferFilter{}))
+
+	// Vast.ai goes down after its cache expires; its last good offers are served
+	time.Sleep(70 * time.Millisecond)
+	vast.err = errors.New("502 bad gateway")
+	got, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	assert.Equal(t, []string{"vastai"}, svc.StaleProviders(models.OfferFilter{}))
+	assert.Empty(t, svc.StaleProviders(models.OfferFilter{Provider: "tensordock"}))
+	assert.Empty(t, svc.StaleProviders(models.OfferFilter{GPUType: "RTX4090"}), "other cache keys are unaffected")
+
+	status := svc.GetProviderCacheStatus()["vastai"]
+	assert.True(t, status.InBackoff)
+	assert.False(t, status.HasError)
+}
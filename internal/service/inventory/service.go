@@ -55,10 +55,31 @@ type Service struct {
 	// Global offer failure tracking (BUG-010, BUG-011, BUG-012)
 	failureTracker *OfferFailureTracker
 
-	// Bug #19 fix: Track background refresh goroutines for graceful shutdown
-	refreshWg    sync.WaitGroup
-	shutdownCh   chan struct{}
+	// Optional offer price history
+	priceHistory        PriceHistoryStore
+	lastPriceCompaction time.Time
+
+	// Optional consumer price watches
+	priceWatches  PriceWatchStore
+	watchNotifier PriceWatchNotifier
+	watchMu       sync.Mutex
+
+	// Called after every successful provider fetch (see OnRefresh)
+	refreshHooks []func()
+
+	// Collapses concurrent fetches per cache key; also tracks fetch
+	// goroutines for graceful shutdown (Bug #19)
+	fetches fetchGroup
+
+	// Lifetime of the service, cancelled by Shutdown so background fetches
+	// stop with it
+	ctx          context.Context
+	cancel       context.CancelFunc
 	shutdownOnce sync.Once
+
+	// Background refresh goroutines, one per provider (see StartRefresher)
+	refreshing bool
+	refreshers sync.WaitGroup
 }
 
 // providerCache holds cached offers for a single provider
@@ -69,7 +90,6 @@ type providerCache struct {
 	softExpiry time.Time // When to start background refresh (before hard expiry)
 	err        error
 	inBackoff  bool
-	refreshing bool // True if a background refresh is in progress
 }
 
 // Option configures the inventory service
@@ -132,8 +152,8 @@ func New(providers []provider.Provider, opts ...Option) *Service {
 		backoffTTL:      BackoffCacheTTL,
 		providerTimeout: DefaultProviderTimeout,
 		failureTracker:  NewOfferFailureTracker(),
-		shutdownCh:      make(chan struct{}), // Bug #19 fix: Initialize shutdown channel
 	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	for _, opt := range opts {
 		opt(s)
@@ -142,6 +162,23 @@ func New(providers []provider.Provider, opts ...Option) *Service {
 	return s
 }
 
+// OnRefresh registers fn to be called after every successful provider
+// fetch. fn must not block.
+func (s *Service) OnRefresh(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshHooks = append(s.refreshHooks, fn)
+}
+
+func (s *Service) notifyRefresh() {
+	s.mu.RLock()
+	hooks := s.refreshHooks
+	s.mu.RUnlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
 // ListOffers returns aggregated GPU offers from all providers
 func (s *Service) ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error) {
 	// If filtering by specific provider, only fetch from that one
@@ -240,13 +277,28 @@ func cacheKey(providerName string, filter models.OfferFilter) string {
 	if filter.Location != "" {
 		key += ":loc=" + filter.Location
 	}
+	if filter.Interruptible {
+		key += ":bid"
+	}
 	return key
 }
 
+// upstreamFilter returns the filter to fetch p's offers with. Providers
+// that filter their catalogue locally are always fetched unfiltered, so
+// queries for different GPU types or locations share one cache entry and
+// one upstream request per provider; filterAndSort narrows the result down.
+func upstreamFilter(p provider.Provider, filter models.OfferFilter) models.OfferFilter {
+	if p.SupportsFeature(provider.FeatureOfferQueryFilters) {
+		return filter
+	}
+	return models.OfferFilter{}
+}
+
 // getOffersWithCache returns cached offers or fetches fresh ones
 // Implements stale-while-revalidate pattern: returns stale data immediately
 // while refreshing in the background to avoid blocking requests
 func (s *Service) getOffersWithCache(ctx context.Context, p provider.Provider, filter models.OfferFilter) ([]models.GPUOffer, error) {
+	filter = upstreamFilter(p, filter)
 	providerName := p.Name()
 	key := cacheKey(providerName, filter)
 	now := time.Now()
@@ -263,6 +315,7 @@ func (s *Service) getOffersWithCache(ctx context.Context, p provider.Provider, f
 				slog.String("provider", providerName),
 				slog.Int("count", len(cached.offers)),
 				slog.Bool("in_backoff", cached.inBackoff))
+			metrics.RecordInventoryCacheLookup(providerName, "fresh")
 
 			if cached.err != nil {
 				return nil, cached.err
@@ -275,107 +328,66 @@ func (s *Service) getOffersWithCache(ctx context.Context, p provider.Provider, f
 			s.logger.Debug("using stale cached offers, triggering background refresh",
 				slog.String("provider", providerName),
 				slog.Int("count", len(cached.offers)))
+			metrics.RecordInventoryCacheLookup(providerName, "stale")
 
-			// Trigger background refresh if not already refreshing
 			s.triggerBackgroundRefresh(p, filter)
 
 			return cached.offers, nil
 		}
 	}
 
-	// Case 3: No cache or cache expired - must fetch synchronously
-	return s.fetchOffersSync(ctx, p, filter)
+	// Case 3: No cache or cache expired - must fetch synchronously. Concurrent
+	// callers for the same key share a single provider request. The fetch is
+	// detached from ctx so one caller timing out doesn't fail the others.
+	fetchCtx := context.WithoutCancel(ctx)
+	offers, shared, err := s.fetches.do(ctx, key, func() ([]models.GPUOffer, error) {
+		return s.fetchOffers(fetchCtx, p, filter)
+	})
+	if shared {
+		metrics.RecordInventoryCacheLookup(providerName, "coalesced")
+	} else {
+		metrics.RecordInventoryCacheLookup(providerName, "miss")
+	}
+	return offers, err
 }
 
-// triggerBackgroundRefresh starts a background goroutine to refresh the cache
-// Bug #19 fix: Track goroutines with WaitGroup and respect shutdown signal
+// triggerBackgroundRefresh starts a background fetch to refresh the cache,
+// unless a fetch for the same key is already in flight
+// Bug #19 fix: Fetch goroutines are tracked and not started during shutdown
 func (s *Service) triggerBackgroundRefresh(p provider.Provider, filter models.OfferFilter) {
 	providerName := p.Name()
 	key := cacheKey(providerName, filter)
 
-	// Check if shutdown is in progress
-	select {
-	case <-s.shutdownCh:
-		return // Don't start new goroutines during shutdown
-	default:
-	}
-
-	s.mu.Lock()
-	cached, exists := s.cache[key]
-	if exists && cached.refreshing {
-		s.mu.Unlock()
-		return // Already refreshing
+	// Don't start new goroutines during shutdown
+	if s.ctx.Err() != nil {
+		return
 	}
-	if exists {
-		cached.refreshing = true
-	}
-	s.mu.Unlock()
-
-	// Bug #19 fix: Track goroutine with WaitGroup
-	s.refreshWg.Add(1)
-	go func() {
-		defer s.refreshWg.Done()
-
-		ctx, cancel := context.WithTimeout(context.Background(), s.providerTimeout)
-		defer cancel()
 
+	_, shared := s.fetches.start(key, func() ([]models.GPUOffer, error) {
 		s.logger.Debug("background refresh started", slog.String("provider", providerName), slog.String("cache_key", key))
-
-		// Bug #19 fix: Check for shutdown during the refresh
-		select {
-		case <-s.shutdownCh:
-			s.mu.Lock()
-			if cached, exists := s.cache[key]; exists {
-				cached.refreshing = false
-			}
-			s.mu.Unlock()
-			return
-		default:
-		}
-
-		offers, err := p.ListOffers(ctx, filter)
-		now := time.Now()
-
-		s.mu.Lock()
-		defer s.mu.Unlock()
-
-		if err != nil {
-			s.logger.Warn("background refresh failed",
-				slog.String("provider", providerName),
-				slog.String("error", err.Error()))
-			// On error, keep the old cache but mark as no longer refreshing
-			if cached, exists := s.cache[key]; exists {
-				cached.refreshing = false
-			}
-			return
-		}
-
-		ttl := s.getCacheTTL(providerName)
-		softExpiry := now.Add(ttl * 3 / 4) // Soft expiry at 75% of TTL
-		s.cache[key] = &providerCache{
-			offers:     offers,
-			fetchedAt:  now,
-			expiresAt:  now.Add(ttl),
-			softExpiry: softExpiry,
-			err:        nil,
-			inBackoff:  false,
-			refreshing: false,
-		}
-
-		s.logger.Debug("background refresh completed",
-			slog.String("provider", providerName),
-			slog.String("cache_key", key),
-			slog.Int("count", len(offers)),
-			slog.Duration("ttl", ttl))
-	}()
+		return s.fetchOffers(s.ctx, p, filter)
+	})
+	if shared {
+		s.logger.Debug("background refresh already in flight", slog.String("cache_key", key))
+	}
 }
 
-// fetchOffersSync fetches offers synchronously and updates cache
-func (s *Service) fetchOffersSync(ctx context.Context, p provider.Provider, filter models.OfferFilter) ([]models.GPUOffer, error) {
+// fetchOffers fetches offers from the provider and updates the cache.
+// Only called through s.fetches, so at most one fetch per cache key runs at a time.
+func (s *Service) fetchOffers(ctx context.Context, p provider.Provider, filter models.OfferFilter) ([]models.GPUOffer, error) {
 	providerName := p.Name()
 	key := cacheKey(providerName, filter)
 
-	s.logger.Debug("fetching offers from provider (sync)", slog.String("provider", providerName), slog.String("cache_key", key))
+	// Another fetch may have refreshed the cache between our cache check
+	// and becoming the leader for this key
+	s.mu.RLock()
+	cached, exists := s.cache[key]
+	s.mu.RUnlock()
+	if exists && time.Now().Before(cached.softExpiry) {
+		return cached.offers, cached.err
+	}
+
+	s.logger.Debug("fetching offers from provider", slog.String("provider", providerName), slog.String("cache_key", key))
 
 	fetchCtx, cancel := context.WithTimeout(ctx, s.providerTimeout)
 	defer cancel()
@@ -383,11 +395,50 @@ func (s *Service) fetchOffersSync(ctx context.Context, p provider.Provider, filt
 	offers, err := p.ListOffers(fetchCtx, filter)
 	now := time.Now()
 
+	// Location-filtered results are a regional subset, so they would skew
+	// the price history
+	if err == nil && filter.Location == "" {
+		s.recordPriceSnapshots(ctx, providerName, offers, now)
+	}
+	// Watches are evaluated against the full catalogue only; GPU- or
+	// location-filtered fetches would look like offers disappearing
+	if err == nil && filter.GPUType == "" && filter.Location == "" && !filter.Interruptible {
+		s.evaluatePriceWatches(ctx, providerName, offers, now)
+	}
+	// Wake anything waiting for new offers, such as queued session requests
+	if err == nil {
+		s.notifyRefresh()
+	}
+
 	// Update cache
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if err != nil {
+		// Serve the last good offers rather than an error while they are
+		// within their TTL or younger than MaxStaleAge; staleness degradation
+		// lowers their confidence. Either way, back off so we don't hammer
+		// the provider.
+		if prev, ok := s.cache[key]; ok && prev.err == nil && len(prev.offers) > 0 && now.Before(staleLimit(prev)) {
+			expiry := now.Add(s.backoffTTL)
+			if limit := staleLimit(prev); limit.Before(expiry) {
+				expiry = limit
+			}
+			s.logger.Warn("provider fetch error, serving stale offers during backoff",
+				slog.String("provider", providerName),
+				slog.String("error", err.Error()),
+				slog.Duration("age", now.Sub(prev.fetchedAt)))
+
+			s.cache[key] = &providerCache{
+				offers:     prev.offers,
+				fetchedAt:  prev.fetchedAt,
+				expiresAt:  expiry,
+				softExpiry: expiry,
+				inBackoff:  true,
+			}
+			return prev.offers, nil
+		}
+
 		s.logger.Warn("provider fetch error, entering backoff",
 			slog.String("provider", providerName),
 			slog.String("error", err.Error()))
@@ -399,7 +450,6 @@ func (s *Service) fetchOffersSync(ctx context.Context, p provider.Provider, filt
 			softExpiry: now.Add(s.backoffTTL),
 			err:        err,
 			inBackoff:  true,
-			refreshing: false,
 		}
 		return nil, err
 	}
@@ -413,7 +463,6 @@ func (s *Service) fetchOffersSync(ctx context.Context, p provider.Provider, filt
 		softExpiry: softExpiry,
 		err:        nil,
 		inBackoff:  false,
-		refreshing: false,
 	}
 
 	s.logger.Debug("cached offers from provider",
@@ -425,6 +474,16 @@ func (s *Service) fetchOffersSync(ctx context.Context, p provider.Provider, filt
 	return offers, nil
 }
 
+// staleLimit returns how long a successful cache entry may be served when
+// refreshing it fails
+func staleLimit(c *providerCache) time.Time {
+	limit := c.fetchedAt.Add(MaxStaleAge)
+	if c.expiresAt.After(limit) {
+		return c.expiresAt
+	}
+	return limit
+}
+
 // filterAndSort applies filters and sorts offers by price
 func (s *Service) filterAndSort(offers []models.GPUOffer, filter models.OfferFilter) []models.GPUOffer {
 	filtered := make([]models.GPUOffer, 0, len(offers))
@@ -433,16 +492,17 @@ func (s *Service) filterAndSort(offers []models.GPUOffer, filter models.OfferFil
 		// Apply staleness degradation to availability confidence
 		adjustedOffer := s.applyStalenessDegradation(offer)
 
-		// Skip suppressed offers (global failure tracking — BUG-010, BUG-011, BUG-012)
-		if s.failureTracker.IsSuppressed(adjustedOffer.ID) {
+		// Skip suppressed offers (operator rules and global failure tracking — BUG-010, BUG-011, BUG-012)
+		if s.failureTracker.IsOfferSuppressed(&adjustedOffer) {
 			continue
 		}
 
-		// Apply failure-based confidence degradation
-		multiplier := s.failureTracker.GetConfidenceMultiplier(
-			adjustedOffer.ID, adjustedOffer.GPUType, adjustedOffer.Provider)
-		if multiplier < 1.0 {
-			adjustedOffer.AvailabilityConfidence *= multiplier
+		// Apply failure-based confidence degradation unless allowlisted
+		if !s.failureTracker.IsOfferAllowed(&adjustedOffer) {
+			multiplier := s.failureTracker.OfferConfidenceMultiplier(&adjustedOffer)
+			if multiplier < 1.0 {
+				adjustedOffer.AvailabilityConfidence *= multiplier
+			}
 		}
 
 		if adjustedOffer.MatchesFilter(filter) && adjustedOffer.Available {
@@ -523,6 +583,18 @@ func (s *Service) GetOffer(ctx context.Context, offerID string) (*models.GPUOffe
 		}
 	}
 
+	// Interruptible (bid) offers are listed separately from on-demand ones
+	bidOffers, err := s.ListOffers(ctx, models.OfferFilter{Interruptible: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, offer := range bidOffers {
+		if offer.ID == offerID {
+			adjusted := s.applyStalenessDegradation(offer)
+			return &adjusted, nil
+		}
+	}
+
 	return nil, &OfferNotFoundError{ID: offerID}
 }
 
@@ -596,6 +668,20 @@ func (s *Service) ProviderNames() []string {
 	return names
 }
 
+// SetCacheTTLs replaces the default, backoff and provider-specific cache TTLs
+// of a running service. Cached entries keep their current expiry; the new
+// TTLs apply from each provider's next fetch.
+func (s *Service) SetCacheTTLs(cacheTTL, backoffTTL time.Duration, providerTTLs map[string]time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheTTL = cacheTTL
+	s.backoffTTL = backoffTTL
+	s.providerCacheTTL = make(map[string]time.Duration, len(providerTTLs))
+	for name, ttl := range providerTTLs {
+		s.providerCacheTTL[name] = ttl
+	}
+}
+
 // getCacheTTL returns the cache TTL for a specific provider
 // Uses provider-specific TTL if configured, otherwise falls back to default
 func (s *Service) getCacheTTL(providerName string) time.Duration {
@@ -608,12 +694,13 @@ func (s *Service) getCacheTTL(providerName string) time.Duration {
 }
 
 // Shutdown gracefully shuts down the inventory service
-// Bug #19 fix: Signals background refresh goroutines to stop and waits for completion
+// Bug #19 fix: Cancels background fetches and waits for them to return
 func (s *Service) Shutdown() {
 	s.shutdownOnce.Do(func() {
-		s.logger.Info("inventory service shutting down, waiting for background refreshes")
-		close(s.shutdownCh)
-		s.refreshWg.Wait()
+		s.logger.Info("inventory service shutting down, waiting for in-flight fetches")
+		s.cancel()
+		s.refreshers.Wait()
+		s.fetches.wait()
 		s.logger.Info("inventory service shutdown complete")
 	})
 }
@@ -639,13 +726,16 @@ func (s *Service) EvictOffer(offerID string) {
 }
 
 // RecordOfferFailure records a provisioning failure for global offer health tracking.
-// Called by the provisioner when an offer fails at any stage.
-func (s *Service) RecordOfferFailure(offerID, providerName, gpuType, failureType, reason string) {
-	s.failureTracker.RecordFailure(offerID, providerName, gpuType, FailureType(failureType), reason)
+// Called by the provisioner when an offer fails at any stage. machineID is the
+// offer's host, if known, so the failure also counts against the host's other
+// and future offers.
+func (s *Service) RecordOfferFailure(offerID, providerName, gpuType, machineID, failureType, reason string) {
+	s.failureTracker.RecordMachineFailure(offerID, providerName, gpuType, machineID, FailureType(failureType), reason)
 	s.logger.Warn("offer failure recorded",
 		slog.String("offer_id", offerID),
 		slog.String("provider", providerName),
 		slog.String("gpu_type", gpuType),
+		slog.String("machine_id", machineID),
 		slog.String("failure_type", failureType),
 		slog.String("reason", reason))
 	metrics.RecordOfferFailure(providerName, gpuType, failureType)
@@ -656,6 +746,11 @@ func (s *Service) GetAllOfferHealth() ([]OfferHealthInfo, []GPUTypeHealthInfo) {
 	return s.failureTracker.GetAllHealth()
 }
 
+// GetHostHealth returns health data for hosts with recent failures
+func (s *Service) GetHostHealth() []HostHealthInfo {
+	return s.failureTracker.GetHostHealth()
+}
+
 // LoadFailureData loads persisted failure data into the in-memory tracker.
 // Call this once at startup after the service is created.
 func (s *Service) LoadFailureData(ctx context.Context, failures []StoredFailure, suppressions []StoredSuppression) {
@@ -671,8 +766,8 @@ func (s *Service) FindComparableOffers(ctx context.Context, original *models.GPU
 		return nil, fmt.Errorf("original offer is nil")
 	}
 
-	// Build filter based on scope
-	filter := models.OfferFilter{}
+	// Build filter based on scope; interruptible sessions retry on the bid market
+	filter := models.OfferFilter{Interruptible: original.Interruptible}
 
 	switch scope {
 	case "same_gpu":
@@ -722,8 +817,8 @@ func (s *Service) FindComparableOffers(ctx context.Context, original *models.GPU
 		if !offer.Available {
 			continue
 		}
-		// Skip suppressed offers (global failure tracking)
-		if s.failureTracker.IsSuppressed(offer.ID) {
+		// Skip suppressed offers (operator rules and global failure tracking)
+		if s.failureTracker.IsOfferSuppressed(&offer) {
 			continue
 		}
 		candidates = append(candidates, offer)
@@ -758,7 +853,7 @@ func (s *Service) FindComparableOffers(ctx context.Context, original *models.GPU
 func (s *Service) GetTemplateProvider(providerName string) (provider.TemplateProvider, error) {
 	for _, p := range s.providers {
 		if p.Name() == providerName {
-			templateProvider, ok := p.(provider.TemplateProvider)
+			templateProvider, ok := provider.As[provider.TemplateProvider](p)
 			if !ok {
 				return nil, &ProviderNotFoundError{Name: providerName + " (does not support templates)"}
 			}
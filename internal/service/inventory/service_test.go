@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -22,6 +23,9 @@ type mockProvider struct {
 	err       error
 	callCount atomic.Int32
 	delay     time.Duration
+
+	// queryFilters reports provider.FeatureOfferQueryFilters, as Vast.ai does
+	queryFilters bool
 }
 
 func (m *mockProvider) Name() string {
@@ -62,7 +66,7 @@ func (m *mockProvider) GetInstanceStatus(ctx context.Context, instanceID string)
 }
 
 func (m *mockProvider) SupportsFeature(feature provider.ProviderFeature) bool {
-	return false
+	return feature == provider.FeatureOfferQueryFilters && m.queryFilters
 }
 
 func newTestLogger() *slog.Logger {
@@ -249,6 +253,19 @@ func TestService_ListOffers_Caching(t *testing.T) {
 	assert.Equal(t, int32(2), p.callCount.Load())
 }
 
+func TestService_SetCacheTTLs(t *testing.T) {
+	svc := New(nil,
+		WithCacheTTL(time.Minute),
+		WithProviderCacheTTL("tensordock", 30*time.Second),
+		WithLogger(newTestLogger()))
+
+	svc.SetCacheTTLs(2*time.Minute, 10*time.Minute, map[string]time.Duration{"vastai": 45 * time.Second})
+
+	assert.Equal(t, 2*time.Minute, svc.getCacheTTL("tensordock"), "provider TTLs are replaced, not merged")
+	assert.Equal(t, 45*time.Second, svc.getCacheTTL("vastai"))
+	assert.Equal(t, 10*time.Minute, svc.backoffTTL)
+}
+
 func TestService_ListOffers_BackoffOnError(t *testing.T) {
 	providerErr := errors.New("provider unavailable")
 	p := &mockProvider{name: "vastai", err: providerErr}
@@ -363,6 +380,40 @@ func TestService_GetOffer_NotFound(t *testing.T) {
 	assert.True(t, errors.As(err, &notFound))
 }
 
+func TestService_InterruptibleOffers(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "vastai-1", Provider: "vastai", GPUType: "RTX4090", PricePerHour: 0.50, Available: true},
+		{ID: "vastai-bid-1", Provider: "vastai", GPUType: "RTX4090", PricePerHour: 0.20, Available: true, Interruptible: true, MinBid: 0.20},
+		{ID: "vastai-bid-2", Provider: "vastai", GPUType: "RTX4090", PricePerHour: 0.22, Available: true, Interruptible: true, MinBid: 0.22},
+	}
+
+	p := &mockProvider{name: "vastai", offers: offers, queryFilters: true}
+	svc := New([]provider.Provider{p}, WithLogger(newTestLogger()))
+	ctx := context.Background()
+
+	onDemand, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	require.Len(t, onDemand, 1)
+	assert.Equal(t, "vastai-1", onDemand[0].ID)
+
+	bids, err := svc.ListOffers(ctx, models.OfferFilter{Interruptible: true})
+	require.NoError(t, err)
+	assert.Len(t, bids, 2)
+
+	// Bid offers are cached separately from on-demand offers
+	assert.Equal(t, int32(2), p.callCount.Load())
+
+	offer, err := svc.GetOffer(ctx, "vastai-bid-1")
+	require.NoError(t, err)
+	assert.True(t, offer.Interruptible)
+
+	// Interruptible sessions retry on the bid market only
+	alternatives, err := svc.FindComparableOffers(ctx, offer, "same_gpu", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, alternatives, 1)
+	assert.Equal(t, "vastai-bid-2", alternatives[0].ID)
+}
+
 func TestService_InvalidateCache(t *testing.T) {
 	offers := []models.GPUOffer{
 		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", PricePerHour: 0.50, Available: true},
@@ -505,6 +556,187 @@ func TestService_ConcurrentAccess(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_ConcurrentColdCacheCoalesced(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", Available: true},
+	}
+
+	p := &mockProvider{name: "vastai", offers: offers, delay: 50 * time.Millisecond}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(time.Hour),
+		WithLogger(newTestLogger()))
+	defer svc.Shutdown()
+
+	ctx := context.Background()
+	const numGoroutines = 20
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := svc.ListOffers(ctx, models.OfferFilter{})
+			if err == nil && len(got) != 1 {
+				err = errors.New("unexpected offer count")
+			}
+			errCh <- err
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), p.callCount.Load(), "concurrent cold-cache requests should share one provider fetch")
+}
+
+func TestService_ConcurrentFilteredQueriesShareCatalogueFetch(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "td-1", Provider: "tensordock", GPUType: "RTX4090", Location: "Dallas, US", Available: true},
+		{ID: "td-2", Provider: "tensordock", GPUType: "A100", Location: "Frankfurt, DE", Available: true},
+		{ID: "td-3", Provider: "tensordock", GPUType: "H100", Location: "Dallas, US", Available: true},
+	}
+
+	// TensorDock filters its catalogue locally, so every query can be served
+	// from one unfiltered fetch
+	p := &mockProvider{name: "tensordock", offers: offers, delay: 50 * time.Millisecond}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(time.Hour),
+		WithLogger(newTestLogger()))
+	defer svc.Shutdown()
+
+	filters := []models.OfferFilter{
+		{},
+		{GPUType: "RTX4090"},
+		{GPUType: "A100"},
+		{Location: "Dallas"},
+		{Provider: "tensordock", GPUType: "H100"},
+		{MinVRAM: 80},
+	}
+	want := []int{3, 1, 1, 2, 1, 0}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	got := make([]int, len(filters))
+	errs := make([]error, len(filters))
+	for i, filter := range filters {
+		wg.Add(1)
+		go func(i int, filter models.OfferFilter) {
+			defer wg.Done()
+			result, err := svc.ListOffers(ctx, filter)
+			got[i], errs[i] = len(result), err
+		}(i, filter)
+	}
+	wg.Wait()
+
+	for i := range filters {
+		require.NoError(t, errs[i])
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, int32(1), p.callCount.Load(), "queries with different filters should share one provider fetch")
+
+	// Later filtered queries are served from the same cache entry
+	_, err := svc.ListOffers(ctx, models.OfferFilter{GPUType: "A100", Location: "Frankfurt"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), p.callCount.Load())
+}
+
+func TestService_CancelledCallerDoesNotAbortSharedFetch(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", Available: true},
+	}
+
+	p := &mockProvider{name: "vastai", offers: offers, delay: 100 * time.Millisecond}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(time.Hour),
+		WithLogger(newTestLogger()))
+	defer svc.Shutdown()
+
+	shortCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	resultCh := make(chan error, 1)
+	go func() {
+		// Give the short-lived caller time to start the fetch
+		time.Sleep(5 * time.Millisecond)
+		_, err := svc.ListOffers(context.Background(), models.OfferFilter{})
+		resultCh <- err
+	}()
+
+	_, err := svc.ListOffers(shortCtx, models.OfferFilter{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, <-resultCh)
+	assert.Equal(t, int32(1), p.callCount.Load())
+
+	// The shared fetch populated the cache
+	status := svc.GetCacheStatus()
+	assert.Equal(t, 1, status["vastai"].OfferCount)
+}
+
+func TestService_ShutdownCancelsBackgroundRefresh(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", Available: true},
+	}
+
+	p := &mockProvider{name: "vastai", offers: offers}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(40*time.Millisecond),
+		WithLogger(newTestLogger()))
+
+	ctx := context.Background()
+	_, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+
+	// Past soft expiry, a stale read starts a background refresh that hangs
+	time.Sleep(35 * time.Millisecond)
+	p.delay = time.Minute
+	_, err = svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return p.callCount.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	// Shutdown aborts it rather than waiting for the provider timeout
+	start := time.Now()
+	svc.Shutdown()
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestService_ServesStaleOffersOnError(t *testing.T) {
+	offers := []models.GPUOffer{
+		{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", Available: true, FetchedAt: time.Now()},
+	}
+
+	p := &mockProvider{name: "vastai", offers: offers}
+	svc := New([]provider.Provider{p},
+		WithCacheTTL(50*time.Millisecond),
+		WithBackoffTTL(time.Second),
+		WithLogger(newTestLogger()))
+	defer svc.Shutdown()
+
+	ctx := context.Background()
+
+	_, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+
+	// Let the cache expire, then make the provider fail (e.g. rate limited)
+	time.Sleep(70 * time.Millisecond)
+	p.err = errors.New("429 too many requests")
+
+	got, err := svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "offer-1", got[0].ID)
+	assert.Equal(t, int32(2), p.callCount.Load())
+
+	// Backoff applies: the failing provider is not called again
+	_, err = svc.ListOffers(ctx, models.OfferFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), p.callCount.Load())
+	assert.True(t, svc.GetCacheStatus()["vastai"].InBackoff)
+}
+
 func TestService_ContextCancellation(t *testing.T) {
 	p := &mockProvider{name: "vastai", delay: time.Second}
 	svc := New([]provider.Provider{p}, WithLogger(newTestLogger()))
@@ -0,0 +1,68 @@
+package inventory
+
+import (
+	"sort"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// SummarizeOffers aggregates offers into one summary per GPU type, cheapest
+// type first
+func SummarizeOffers(offers []models.GPUOffer) []models.InventorySummary {
+	type group struct {
+		prices     []float64
+		providers  map[string]bool
+		confidence float64
+	}
+	groups := make(map[string]*group)
+
+	for _, offer := range offers {
+		if offer.GPUType == "" {
+			continue
+		}
+		g, ok := groups[offer.GPUType]
+		if !ok {
+			g = &group{providers: make(map[string]bool)}
+			groups[offer.GPUType] = g
+		}
+		g.prices = append(g.prices, offer.PricePerGPUHour())
+		g.providers[offer.Provider] = true
+		g.confidence += offer.GetEffectiveAvailabilityConfidence()
+	}
+
+	summaries := make([]models.InventorySummary, 0, len(groups))
+	for gpuType, g := range groups {
+		sort.Float64s(g.prices)
+		providers := make([]string, 0, len(g.providers))
+		for name := range g.providers {
+			providers = append(providers, name)
+		}
+		sort.Strings(providers)
+
+		summaries = append(summaries, models.InventorySummary{
+			GPUType:                   gpuType,
+			OfferCount:                len(g.prices),
+			MinPrice:                  g.prices[0],
+			MedianPrice:               median(g.prices),
+			Providers:                 providers,
+			AvgAvailabilityConfidence: g.confidence / float64(len(g.prices)),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].MinPrice != summaries[j].MinPrice {
+			return summaries[i].MinPrice < summaries[j].MinPrice
+		}
+		return summaries[i].GPUType < summaries[j].GPUType
+	})
+	return summaries
+}
+
+// median returns the middle of sorted, non-empty values
+func median(sorted []float64) float64 {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,43 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeOffers(t *testing.T) {
+	offers := []models.GPUOffer{
+		{Provider: "vastai", GPUType: "RTX 4090", GPUCount: 1, PricePerHour: 0.50, AvailabilityConfidence: 0.8},
+		{Provider: "tensordock", GPUType: "RTX 4090", GPUCount: 1, PricePerHour: 0.40},
+		{Provider: "vastai", GPUType: "RTX 4090", GPUCount: 2, PricePerHour: 1.40, AvailabilityConfidence: 0.6},
+		{Provider: "vastai", GPUType: "RTX 3090", GPUCount: 1, PricePerHour: 0.20, AvailabilityConfidence: 0.9},
+		{Provider: "vastai", GPUType: "RTX 3090", GPUCount: 1, PricePerHour: 0.30, AvailabilityConfidence: 0.9},
+		{Provider: "vastai", GPUType: "", PricePerHour: 0.01},
+	}
+
+	summaries := SummarizeOffers(offers)
+	require.Len(t, summaries, 2)
+
+	// Cheapest GPU type first
+	cheap := summaries[0]
+	assert.Equal(t, "RTX 3090", cheap.GPUType)
+	assert.Equal(t, 2, cheap.OfferCount)
+	assert.InDelta(t, 0.20, cheap.MinPrice, 1e-9)
+	assert.InDelta(t, 0.25, cheap.MedianPrice, 1e-9, "even counts average the middle prices")
+	assert.Equal(t, []string{"vastai"}, cheap.Providers)
+	assert.InDelta(t, 0.9, cheap.AvgAvailabilityConfidence, 1e-9)
+
+	// Prices are per GPU-hour and unset confidence counts as 1.0
+	rtx4090 := summaries[1]
+	assert.Equal(t, "RTX 4090", rtx4090.GPUType)
+	assert.Equal(t, 3, rtx4090.OfferCount)
+	assert.InDelta(t, 0.40, rtx4090.MinPrice, 1e-9)
+	assert.InDelta(t, 0.50, rtx4090.MedianPrice, 1e-9)
+	assert.Equal(t, []string{"tensordock", "vastai"}, rtx4090.Providers)
+	assert.InDelta(t, 0.8, rtx4090.AvgAvailabilityConfidence, 1e-9)
+
+	assert.Empty(t, SummarizeOffers(nil))
+}
@@ -0,0 +1,307 @@
+// Package leader elects one server replica to run background services.
+//
+// Every replica serves API traffic, but sweeps such as the lifecycle
+// manager, reconciler and cost tracker act on shared state and must run in
+// only one place. Replicas compete for a named lease in the shared database;
+// the holder renews it periodically and runs the background services, and
+// another replica takes over once the lease expires.
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+)
+
+const (
+	// DefaultLeaseName is the lease replicas compete for
+	DefaultLeaseName = "background-services"
+
+	// DefaultLeaseTTL is how long a lease lasts without renewal, and so how
+	// long background work can stall after the leader dies
+	DefaultLeaseTTL = 30 * time.Second
+)
+
+// LeaseStore grants named, time-limited leases
+type LeaseStore interface {
+	TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, holder string) error
+}
+
+// Elector holds the leader lease for as long as it can, calling onElected
+// when it becomes leader and onDemoted when it stops being leader, including
+// when it is stopped. The callbacks run on the elector's goroutine and must
+// not block for long, or the lease may expire before it is renewed.
+type Elector struct {
+	store     LeaseStore
+	onElected func(ctx context.Context)
+	onDemoted func()
+	logger    *slog.Logger
+
+	// Configuration
+	name          string
+	holder        string
+	ttl           time.Duration
+	renewInterval time.Duration
+
+	// For time mocking in tests
+	now func() time.Time
+
+	// Leadership state
+	stateMu     sync.RWMutex
+	leader      bool
+	lastRenewed time.Time
+
+	// Shutdown coordination
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// Option configures the elector
+type Option func(*Elector)
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Elector) {
+		e.logger = logger
+	}
+}
+
+// WithLeaseName sets the name of the lease to compete for
+func WithLeaseName(name string) Option {
+	return func(e *Elector) {
+		e.name = name
+	}
+}
+
+// WithHolderID sets the ID this replica holds the lease under. It must be
+// unique among replicas; the default combines the hostname with a random
+// suffix.
+func WithHolderID(id string) Option {
+	return func(e *Elector) {
+		e.holder = id
+	}
+}
+
+// WithLeaseTTL sets how long the lease lasts without renewal
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(e *Elector) {
+		e.ttl = ttl
+	}
+}
+
+// WithRenewInterval sets how often the lease is renewed, or retried by
+// replicas that are not leader. It defaults to a third of the lease TTL.
+func WithRenewInterval(d time.Duration) Option {
+	return func(e *Elector) {
+		e.renewInterval = d
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(e *Elector) {
+		e.now = fn
+	}
+}
+
+// New creates a new elector
+func New(store LeaseStore, onElected func(ctx context.Context), onDemoted func(), opts ...Option) *Elector {
+	e := &Elector{
+		store:     store,
+		onElected: onElected,
+		onDemoted: onDemoted,
+		logger:    slog.Default(),
+		name:      DefaultLeaseName,
+		ttl:       DefaultLeaseTTL,
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.holder == "" {
+		e.holder = defaultHolderID()
+	}
+	if e.renewInterval <= 0 {
+		e.renewInterval = e.ttl / 3
+	}
+	return e
+}
+
+// defaultHolderID identifies this process among replicas
+func defaultHolderID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%s", hostname, hex.EncodeToString(suffix))
+}
+
+// HolderID returns the ID this replica holds the lease under
+func (e *Elector) HolderID() string {
+	return e.holder
+}
+
+// IsLeader reports whether this replica currently holds the lease
+func (e *Elector) IsLeader() bool {
+	e.stateMu.RLock()
+	defer e.stateMu.RUnlock()
+	return e.leader
+}
+
+// Start makes a first attempt at the lease and begins the renewal loop
+func (e *Elector) Start(ctx context.Context) error {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return nil
+	}
+	e.running = true
+	e.stopCh = make(chan struct{})
+	e.doneCh = make(chan struct{})
+	e.mu.Unlock()
+
+	e.logger.Info("leader election starting",
+		slog.String("lease", e.name),
+		slog.String("holder", e.holder),
+		slog.Duration("ttl", e.ttl),
+		slog.Duration("renew_interval", e.renewInterval))
+
+	e.tryLead(ctx)
+	go e.run(ctx)
+	return nil
+}
+
+// Stop ends the renewal loop, stepping down and releasing the lease if this
+// replica is the leader so another can take over immediately
+func (e *Elector) Stop() {
+	e.mu.Lock()
+	if !e.running {
+		e.mu.Unlock()
+		return
+	}
+	stopCh := e.stopCh
+	doneCh := e.doneCh
+	e.mu.Unlock()
+
+	e.logger.Info("leader election stopping")
+	close(stopCh)
+	<-doneCh
+
+	e.mu.Lock()
+	e.running = false
+	e.mu.Unlock()
+
+	e.logger.Info("leader election stopped")
+}
+
+func (e *Elector) run(ctx context.Context) {
+	defer close(e.doneCh)
+	defer e.stepDown()
+
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.tryLead(ctx)
+		case <-e.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// tryLead takes or renews the lease and updates leadership to match
+func (e *Elector) tryLead(ctx context.Context) {
+	acquired, err := e.store.TryAcquire(ctx, e.name, e.holder, e.ttl)
+	now := e.now()
+
+	switch {
+	case err != nil:
+		e.logger.Warn("failed to renew leader lease",
+			slog.String("lease", e.name),
+			slog.String("error", err.Error()))
+		// Keep leading while the lease we hold is still valid, but step down
+		// before it can expire and be taken by another replica
+		if e.IsLeader() && now.Add(e.renewInterval).Sub(e.lastRenewedAt()) >= e.ttl {
+			e.demote("lease could not be renewed")
+		}
+	case acquired:
+		e.stateMu.Lock()
+		e.lastRenewed = now
+		wasLeader := e.leader
+		e.leader = true
+		e.stateMu.Unlock()
+
+		if !wasLeader {
+			e.logger.Info("elected leader, starting background services",
+				slog.String("lease", e.name),
+				slog.String("holder", e.holder))
+			metrics.UpdateLeaderElected(true)
+			if e.onElected != nil {
+				e.onElected(ctx)
+			}
+		}
+	default:
+		if e.IsLeader() {
+			e.demote("lease taken by another replica")
+		}
+	}
+}
+
+func (e *Elector) lastRenewedAt() time.Time {
+	e.stateMu.RLock()
+	defer e.stateMu.RUnlock()
+	return e.lastRenewed
+}
+
+// demote stops the background services after losing the lease
+func (e *Elector) demote(reason string) {
+	e.stateMu.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.stateMu.Unlock()
+	if !wasLeader {
+		return
+	}
+
+	e.logger.Warn("no longer leader, stopping background services",
+		slog.String("lease", e.name),
+		slog.String("reason", reason))
+	metrics.UpdateLeaderElected(false)
+	if e.onDemoted != nil {
+		e.onDemoted()
+	}
+}
+
+// stepDown demotes and releases the lease when the elector stops
+func (e *Elector) stepDown() {
+	if !e.IsLeader() {
+		return
+	}
+	e.demote("shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.store.Release(ctx, e.name, e.holder); err != nil {
+		e.logger.Warn("failed to release leader lease",
+			slog.String("lease", e.name),
+			slog.String("error", err.Error()))
+	}
+}
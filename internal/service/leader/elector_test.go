@@ -0,0 +1,187 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLeaseStore grants leases like storage.LeaseStore, without expiry
+type fakeLeaseStore struct {
+	mu      sync.Mutex
+	holders map[string]string
+	err     error
+}
+
+func newFakeLeaseStore() *fakeLeaseStore {
+	return &fakeLeaseStore{holders: make(map[string]string)}
+}
+
+func (s *fakeLeaseStore) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, s.err
+	}
+	if current, ok := s.holders[name]; ok && current != holder {
+		return false, nil
+	}
+	s.holders[name] = holder
+	return true, nil
+}
+
+func (s *fakeLeaseStore) Release(ctx context.Context, name, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.holders[name] == holder {
+		delete(s.holders, name)
+	}
+	return nil
+}
+
+func (s *fakeLeaseStore) setHolder(name, holder string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holders[name] = holder
+}
+
+func (s *fakeLeaseStore) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *fakeLeaseStore) holder(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.holders[name]
+}
+
+// transitions records elected/demoted callbacks
+type transitions struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (tr *transitions) elected(ctx context.Context) { tr.add("elected") }
+func (tr *transitions) demoted()                    { tr.add("demoted") }
+
+func (tr *transitions) add(event string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.events = append(tr.events, event)
+}
+
+func (tr *transitions) get() []string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]string(nil), tr.events...)
+}
+
+func newTestElector(store LeaseStore, tr *transitions, holder string, opts ...Option) *Elector {
+	opts = append([]Option{
+		WithHolderID(holder),
+		WithLeaseTTL(300 * time.Millisecond),
+		WithRenewInterval(20 * time.Millisecond),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}, opts...)
+	return New(store, tr.elected, tr.demoted, opts...)
+}
+
+func TestElector_OnlyOneLeader(t *testing.T) {
+	store := newFakeLeaseStore()
+	var trA, trB transitions
+	a := newTestElector(store, &trA, "replica-a")
+	b := newTestElector(store, &trB, "replica-b")
+	ctx := context.Background()
+
+	require.NoError(t, a.Start(ctx))
+	require.NoError(t, b.Start(ctx))
+	defer b.Stop()
+
+	assert.True(t, a.IsLeader(), "the first replica takes the free lease on start")
+	assert.False(t, b.IsLeader())
+	assert.Equal(t, []string{"elected"}, trA.get())
+	assert.Empty(t, trB.get())
+
+	// Stopping the leader releases the lease and the other replica takes over
+	a.Stop()
+	assert.False(t, a.IsLeader())
+	assert.Equal(t, []string{"elected", "demoted"}, trA.get())
+	require.Eventually(t, b.IsLeader, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"elected"}, trB.get())
+	assert.Equal(t, "replica-b", store.holder(DefaultLeaseName))
+}
+
+func TestElector_DemotedWhenLeaseLost(t *testing.T) {
+	store := newFakeLeaseStore()
+	var tr transitions
+	e := newTestElector(store, &tr, "replica-a")
+
+	require.NoError(t, e.Start(context.Background()))
+	defer e.Stop()
+	require.True(t, e.IsLeader())
+
+	// Another replica took the lease after ours expired
+	store.setHolder(DefaultLeaseName, "replica-b")
+	require.Eventually(t, func() bool { return !e.IsLeader() }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"elected", "demoted"}, tr.get())
+
+	// Stopping a follower leaves the other replica's lease alone
+	e.Stop()
+	assert.Equal(t, "replica-b", store.holder(DefaultLeaseName))
+	assert.Equal(t, []string{"elected", "demoted"}, tr.get())
+}
+
+func TestElector_StoreErrors(t *testing.T) {
+	store := newFakeLeaseStore()
+	var tr transitions
+	now := time.Now()
+	var nowMu sync.Mutex
+	clock := func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		now = now.Add(d)
+	}
+
+	e := newTestElector(store, &tr, "replica-a", WithTimeFunc(clock))
+	require.NoError(t, e.Start(context.Background()))
+	defer e.Stop()
+	require.True(t, e.IsLeader())
+
+	// A failed renewal keeps leadership while the lease is still valid
+	store.setErr(errors.New("database is locked"))
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, e.IsLeader())
+
+	// Step down before the unrenewed lease can expire
+	advance(290 * time.Millisecond)
+	require.Eventually(t, func() bool { return !e.IsLeader() }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"elected", "demoted"}, tr.get())
+
+	// Leadership resumes once the store recovers
+	store.setErr(nil)
+	require.Eventually(t, e.IsLeader, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"elected", "demoted", "elected"}, tr.get())
+}
+
+func TestNew_Defaults(t *testing.T) {
+	e := New(newFakeLeaseStore(), nil, nil)
+	assert.Equal(t, DefaultLeaseName, e.name)
+	assert.Equal(t, DefaultLeaseTTL, e.ttl)
+	assert.Equal(t, DefaultLeaseTTL/3, e.renewInterval)
+	assert.NotEmpty(t, e.HolderID())
+	assert.NotEqual(t, e.HolderID(), New(newFakeLeaseStore(), nil, nil).HolderID(), "holder IDs are unique per process start")
+}
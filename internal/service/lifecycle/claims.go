@@ -0,0 +1,307 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/logging"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Instances are claimed explicitly rather than by label alone: a shopper
+// label says an instance was made by some deployment, not by this one, and
+// providers such as Vast.ai cannot tag instances with a deployment at all.
+// An unclaimed instance is one with a shopper label that no active session
+// owns. Adopting it creates a session for it, so it is tracked, billed and
+// expired like any other; releasing it records that it is someone else's, so
+// the reconciler never destroys it as an orphan.
+
+// DefaultAdoptReservationHours is the reservation of an adopted instance's
+// session when none is given
+const DefaultAdoptReservationHours = 1
+
+// SessionCreator creates the sessions of adopted instances
+type SessionCreator interface {
+	Create(ctx context.Context, session *models.Session) error
+}
+
+// InstanceReleaseStore persists the instances this deployment released
+type InstanceReleaseStore interface {
+	Release(ctx context.Context, release *models.InstanceRelease) error
+	ListReleases(ctx context.Context) ([]*models.InstanceRelease, error)
+	DeleteRelease(ctx context.Context, provider, instanceID string) error
+}
+
+// errClaimsDisabled is returned by the claim operations without WithInstanceClaims
+var errClaimsDisabled = errors.New("instance claims are not enabled")
+
+// WithInstanceClaims enables adopting and releasing unclaimed instances.
+// Released instances are never treated as orphans.
+func WithInstanceClaims(sessions SessionCreator, releases InstanceReleaseStore) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.sessions = sessions
+		r.releases = releases
+	}
+}
+
+// releasedInstances returns the IDs of a provider's released instances
+func (r *Reconciler) releasedInstances(ctx context.Context, providerName string) (map[string]bool, error) {
+	released := make(map[string]bool)
+	if r.releases == nil {
+		return released, nil
+	}
+	releases, err := r.releases.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.Provider == providerName {
+			released[release.InstanceID] = true
+		}
+	}
+	return released, nil
+}
+
+// isOurs reports whether the reconciler counts an instance no session owns
+// as this deployment's orphan. Without a deployment ID every instance is.
+func (r *Reconciler) isOurs(instance provider.ProviderInstance) bool {
+	return r.deploymentID == "" || instance.IsOurs(r.deploymentID)
+}
+
+// ListUnclaimed lists every shopper-labeled instance the providers report
+// that no active session owns, whichever deployment it is tagged for. A
+// provider that cannot be listed is reported with its error.
+func (r *Reconciler) ListUnclaimed(ctx context.Context) *models.UnclaimedInstances {
+	result := &models.UnclaimedInstances{
+		GeneratedAt: r.now(),
+		Providers:   []models.UnclaimedProvider{},
+		Instances:   []models.UnclaimedInstance{},
+	}
+
+	providerNames := r.providers.List()
+	sort.Strings(providerNames)
+	for _, providerName := range providerNames {
+		summary := models.UnclaimedProvider{Provider: providerName}
+		instances, err := r.unclaimedInstances(ctx, providerName)
+		if err != nil {
+			r.logger.Error("failed to list unclaimed instances",
+				slog.String("provider", providerName),
+				slog.String("error", err.Error()))
+			summary.Error = err.Error()
+		}
+		released, err := r.releasedInstances(ctx, providerName)
+		if err != nil && summary.Error == "" {
+			summary.Error = err.Error()
+		}
+		for _, instance := range instances {
+			entry := models.UnclaimedInstance{
+				Provider:     providerName,
+				InstanceID:   instance.ID,
+				Name:         instance.Name,
+				Status:       instance.Status,
+				PricePerHour: instance.PricePerHour,
+				SessionID:    instance.Tags.ShopperSessionID,
+				DeploymentID: instance.Tags.ShopperDeploymentID,
+				Released:     released[instance.ID],
+			}
+			entry.Orphan = !entry.Released && r.isOurs(instance)
+			if !instance.StartedAt.IsZero() {
+				startedAt := instance.StartedAt
+				entry.StartedAt = &startedAt
+			}
+			if expiresAt := instance.Tags.ShopperExpiresAt; !expiresAt.IsZero() {
+				entry.ExpiresAt = &expiresAt
+			}
+			result.Instances = append(result.Instances, entry)
+		}
+		summary.Unclaimed = len(instances)
+		result.Providers = append(result.Providers, summary)
+	}
+	return result
+}
+
+// unclaimedInstances returns a provider's instances that no active session owns
+func (r *Reconciler) unclaimedInstances(ctx context.Context, providerName string) ([]provider.ProviderInstance, error) {
+	prov, err := r.providers.Get(providerName)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := prov.ListAllInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := r.instanceOwners(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	var unclaimed []provider.ProviderInstance
+	for _, instance := range instances {
+		if _, owned := owners[instance.ID]; !owned {
+			unclaimed = append(unclaimed, instance)
+		}
+	}
+	return unclaimed, nil
+}
+
+// instanceOwners maps a provider's instance IDs to the active sessions on them
+func (r *Reconciler) instanceOwners(ctx context.Context, providerName string) (map[string]string, error) {
+	sessions, err := r.store.GetActiveSessionsByProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[string]string, len(sessions))
+	for _, s := range sessions {
+		if s.ProviderID != "" {
+			owners[s.ProviderID] = s.ID
+		}
+	}
+	return owners, nil
+}
+
+// findUnclaimed returns a provider's instance if it is unclaimed, an
+// InstanceClaimedError if an active session owns it, or an
+// InstanceNotFoundError if the provider does not list it
+func (r *Reconciler) findUnclaimed(ctx context.Context, providerName, instanceID string) (provider.Provider, provider.ProviderInstance, error) {
+	prov, err := r.providers.Get(providerName)
+	if err != nil {
+		return nil, provider.ProviderInstance{}, &InstanceNotFoundError{Provider: providerName, InstanceID: instanceID}
+	}
+	owners, err := r.instanceOwners(ctx, providerName)
+	if err != nil {
+		return nil, provider.ProviderInstance{}, err
+	}
+	if sessionID, owned := owners[instanceID]; owned {
+		return nil, provider.ProviderInstance{}, &InstanceClaimedError{Provider: providerName, InstanceID: instanceID, SessionID: sessionID}
+	}
+	instances, err := prov.ListAllInstances(ctx)
+	if err != nil {
+		return nil, provider.ProviderInstance{}, fmt.Errorf("failed to list instances: %w", err)
+	}
+	for _, instance := range instances {
+		if instance.ID == instanceID {
+			return prov, instance, nil
+		}
+	}
+	return nil, provider.ProviderInstance{}, &InstanceNotFoundError{Provider: providerName, InstanceID: instanceID}
+}
+
+// AdoptInstance creates a running session for an unclaimed instance, owned
+// by consumerID and expiring after reservationHrs (0 uses
+// DefaultAdoptReservationHours). Any release of the instance is dropped. The
+// session's SSH key is unknown, so a new one must be attached to log in.
+func (r *Reconciler) AdoptInstance(ctx context.Context, providerName, instanceID, consumerID string, reservationHrs int) (*models.Session, error) {
+	if r.sessions == nil {
+		return nil, errClaimsDisabled
+	}
+	prov, instance, err := r.findUnclaimed(ctx, providerName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if reservationHrs <= 0 {
+		reservationHrs = DefaultAdoptReservationHours
+	}
+
+	now := r.now()
+	session := &models.Session{
+		ID:             uuid.New().String(),
+		ConsumerID:     consumerID,
+		Provider:       providerName,
+		ProviderID:     instanceID,
+		Status:         models.StatusRunning,
+		ProvisionPhase: models.PhaseRunning,
+		WorkloadType:   models.WorkloadInteractive,
+		StoragePolicy:  models.StorageDestroy,
+		ReservationHrs: reservationHrs,
+		PricePerHour:   instance.PricePerHour,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(time.Duration(reservationHrs) * time.Hour),
+	}
+	if status, err := prov.GetInstanceStatus(ctx, instanceID); err != nil {
+		r.logger.Warn("failed to get status of adopted instance",
+			slog.String("provider", providerName),
+			slog.String("provider_id", instanceID),
+			slog.String("error", err.Error()))
+	} else {
+		session.SSHHost, session.SSHPort, session.SSHUser = status.SSHHost, status.SSHPort, status.SSHUser
+	}
+
+	if err := r.sessions.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := r.releases.DeleteRelease(ctx, providerName, instanceID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		r.logger.Warn("failed to drop release of adopted instance",
+			slog.String("provider_id", instanceID),
+			slog.String("error", err.Error()))
+	}
+
+	r.logger.Info("instance adopted",
+		slog.String("provider", providerName),
+		slog.String("provider_id", instanceID),
+		slog.String("session_id", session.ID),
+		slog.String("label_session_id", instance.Tags.ShopperSessionID))
+	logging.Audit(ctx, "instance_adopted",
+		"session_id", session.ID,
+		"consumer_id", consumerID,
+		"provider", providerName,
+		"provider_id", instanceID)
+	return session, nil
+}
+
+// ReleaseInstance records that this deployment disowns an unclaimed
+// instance, so the reconciler leaves it alone
+func (r *Reconciler) ReleaseInstance(ctx context.Context, providerName, instanceID, releasedBy string) (*models.InstanceRelease, error) {
+	if r.releases == nil {
+		return nil, errClaimsDisabled
+	}
+	if _, _, err := r.findUnclaimed(ctx, providerName, instanceID); err != nil {
+		return nil, err
+	}
+
+	release := &models.InstanceRelease{
+		Provider:     providerName,
+		InstanceID:   instanceID,
+		DeploymentID: r.deploymentID,
+		ReleasedBy:   releasedBy,
+		ReleasedAt:   r.now(),
+	}
+	if err := r.releases.Release(ctx, release); err != nil {
+		return nil, err
+	}
+
+	r.logger.Info("instance released",
+		slog.String("provider", providerName),
+		slog.String("provider_id", instanceID))
+	logging.Audit(ctx, "instance_released",
+		"provider", providerName,
+		"provider_id", instanceID,
+		"released_by", releasedBy)
+	return release, nil
+}
+
+// UnreleaseInstance drops the release of an instance, so the reconciler
+// treats it as any other again. It returns an InstanceNotFoundError if the
+// instance was not released.
+func (r *Reconciler) UnreleaseInstance(ctx context.Context, providerName, instanceID string) error {
+	if r.releases == nil {
+		return errClaimsDisabled
+	}
+	if err := r.releases.DeleteRelease(ctx, providerName, instanceID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return &InstanceNotFoundError{Provider: providerName, InstanceID: instanceID}
+		}
+		return err
+	}
+	logging.Audit(ctx, "instance_unreleased",
+		"provider", providerName,
+		"provider_id", instanceID)
+	return nil
+}
@@ -0,0 +1,201 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *mockReconcileStore) Create(ctx context.Context, session *models.Session) error {
+	m.add(session)
+	return nil
+}
+
+// mockReleaseStore implements InstanceReleaseStore for testing
+type mockReleaseStore struct {
+	mu       sync.Mutex
+	releases map[string]*models.InstanceRelease
+}
+
+func newMockReleaseStore() *mockReleaseStore {
+	return &mockReleaseStore{releases: make(map[string]*models.InstanceRelease)}
+}
+
+func (m *mockReleaseStore) Release(ctx context.Context, release *models.InstanceRelease) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releases[release.Provider+"/"+release.InstanceID] = release
+	return nil
+}
+
+func (m *mockReleaseStore) ListReleases(ctx context.Context) ([]*models.InstanceRelease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.InstanceRelease
+	for _, r := range m.releases {
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (m *mockReleaseStore) DeleteRelease(ctx context.Context, providerName, instanceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := providerName + "/" + instanceID
+	if _, ok := m.releases[key]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(m.releases, key)
+	return nil
+}
+
+// newClaimsFixture returns a reconciler for deployment "ours" over a vastai
+// provider with an owned instance, an untagged unclaimed instance and another
+// deployment's instance
+func newClaimsFixture(t *testing.T) (*Reconciler, *mockReconcileStore, *mockReleaseStore, *mockReconcileProvider) {
+	t.Helper()
+	store := newMockReconcileStore()
+	store.add(&models.Session{
+		ID:         "sess-owned",
+		Provider:   "vastai",
+		ProviderID: "owned",
+		Status:     models.StatusRunning,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	})
+
+	prov := newMockReconcileProvider("vastai")
+	prov.instances = []provider.ProviderInstance{
+		{ID: "owned", Name: "shopper-sess-owned", Status: "running",
+			Tags: models.InstanceTags{ShopperSessionID: "sess-owned"}},
+		{ID: "stray", Name: "shopper-sess-gone", Status: "running", PricePerHour: 0.5,
+			Tags: models.InstanceTags{ShopperSessionID: "sess-gone", ShopperDeploymentID: "ours"}},
+		{ID: "theirs", Name: "shopper-sess-theirs", Status: "running",
+			Tags: models.InstanceTags{ShopperSessionID: "sess-theirs", ShopperDeploymentID: "other"}},
+	}
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "running", Running: true, SSHHost: "10.0.0.1", SSHPort: 2222, SSHUser: "root"}, nil
+	}
+	registry := newMockProviderRegistry()
+	registry.Add(prov)
+
+	releases := newMockReleaseStore()
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithDeploymentID("ours"),
+		WithAutoDestroyOrphans(true),
+		WithInstanceClaims(store, releases))
+	return r, store, releases, prov
+}
+
+func TestReconciler_ListUnclaimed(t *testing.T) {
+	r, _, releases, _ := newClaimsFixture(t)
+	require.NoError(t, releases.Release(context.Background(), &models.InstanceRelease{Provider: "vastai", InstanceID: "theirs"}))
+
+	result := r.ListUnclaimed(context.Background())
+
+	require.Len(t, result.Providers, 1)
+	assert.Equal(t, 2, result.Providers[0].Unclaimed)
+	require.Len(t, result.Instances, 2)
+	assert.Equal(t, "stray", result.Instances[0].InstanceID)
+	assert.Equal(t, "sess-gone", result.Instances[0].SessionID)
+	assert.True(t, result.Instances[0].Orphan)
+	assert.False(t, result.Instances[0].Released)
+	assert.Equal(t, "theirs", result.Instances[1].InstanceID)
+	assert.False(t, result.Instances[1].Orphan)
+	assert.True(t, result.Instances[1].Released)
+}
+
+func TestReconciler_AdoptInstance(t *testing.T) {
+	r, store, releases, prov := newClaimsFixture(t)
+	ctx := context.Background()
+	require.NoError(t, releases.Release(ctx, &models.InstanceRelease{Provider: "vastai", InstanceID: "theirs"}))
+
+	session, err := r.AdoptInstance(ctx, "vastai", "theirs", "consumer-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "consumer-1", session.ConsumerID)
+	assert.Equal(t, "theirs", session.ProviderID)
+	assert.Equal(t, models.StatusRunning, session.Status)
+	assert.Equal(t, DefaultAdoptReservationHours, session.ReservationHrs)
+	assert.Equal(t, "10.0.0.1", session.SSHHost)
+	assert.Equal(t, 2222, session.SSHPort)
+
+	stored, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "theirs", stored.ProviderID)
+	remaining, err := releases.ListReleases(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	// The adopted instance is no longer unclaimed, and not an orphan
+	r.RunReconciliation(ctx)
+	assert.NotContains(t, prov.getDestroyCalls(), "theirs")
+
+	_, err = r.AdoptInstance(ctx, "vastai", "theirs", "consumer-2", 0)
+	var claimed *InstanceClaimedError
+	require.ErrorAs(t, err, &claimed)
+	assert.Equal(t, session.ID, claimed.SessionID)
+
+	_, err = r.AdoptInstance(ctx, "vastai", "missing", "consumer-1", 0)
+	var notFound *InstanceNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestReconciler_ReleasedInstanceNotDestroyed(t *testing.T) {
+	r, _, releases, prov := newClaimsFixture(t)
+	ctx := context.Background()
+
+	release, err := r.ReleaseInstance(ctx, "vastai", "stray", "ops")
+	require.NoError(t, err)
+	assert.Equal(t, "ours", release.DeploymentID)
+	assert.Equal(t, "ops", release.ReleasedBy)
+
+	r.RunReconciliation(ctx)
+	assert.Empty(t, prov.getDestroyCalls())
+	assert.Equal(t, int64(0), r.GetMetrics().OrphansFound)
+
+	_, err = r.ReleaseInstance(ctx, "vastai", "owned", "ops")
+	var claimed *InstanceClaimedError
+	assert.ErrorAs(t, err, &claimed)
+
+	require.NoError(t, r.UnreleaseInstance(ctx, "vastai", "stray"))
+	var notFound *InstanceNotFoundError
+	assert.ErrorAs(t, r.UnreleaseInstance(ctx, "vastai", "stray"), &notFound)
+	remaining, err := releases.ListReleases(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	r.RunReconciliation(ctx)
+	assert.Equal(t, []string{"stray"}, prov.getDestroyCalls())
+}
+
+func TestReconciler_UntaggedInstanceWithSessionIsNotGhost(t *testing.T) {
+	r, store, _, prov := newClaimsFixture(t)
+	ctx := context.Background()
+
+	r.RunReconciliation(ctx)
+
+	// "owned" has no deployment tag, as on Vast.ai, but a session runs on it
+	assert.Equal(t, int64(0), r.GetMetrics().GhostsFound)
+	session, err := store.Get(ctx, "sess-owned")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, session.Status)
+	assert.NotContains(t, prov.getDestroyCalls(), "owned")
+}
+
+func TestReconciler_ClaimsDisabled(t *testing.T) {
+	r := NewReconciler(newMockReconcileStore(), newMockProviderRegistry(), WithReconcileLogger(newTestLogger()))
+	ctx := context.Background()
+
+	_, err := r.AdoptInstance(ctx, "vastai", "stray", "consumer-1", 1)
+	assert.ErrorIs(t, err, errClaimsDisabled)
+	_, err = r.ReleaseInstance(ctx, "vastai", "stray", "ops")
+	assert.ErrorIs(t, err, errClaimsDisabled)
+	assert.ErrorIs(t, r.UnreleaseInstance(ctx, "vastai", "stray"), errClaimsDisabled)
+}
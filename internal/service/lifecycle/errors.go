@@ -40,3 +40,25 @@ func (e *HardMaxExceededError) Error() string {
 		e.HardMaxHours, e.SessionID, e.CurrentDuration.Hours(), e.RequestedHours,
 	)
 }
+
+// InstanceNotFoundError indicates a provider does not list the instance as a
+// shopper-labeled one
+type InstanceNotFoundError struct {
+	Provider   string
+	InstanceID string
+}
+
+func (e *InstanceNotFoundError) Error() string {
+	return fmt.Sprintf("no shopper instance %s on %s", e.InstanceID, e.Provider)
+}
+
+// InstanceClaimedError indicates an active session already owns the instance
+type InstanceClaimedError struct {
+	Provider   string
+	InstanceID string
+	SessionID  string
+}
+
+func (e *InstanceClaimedError) Error() string {
+	return fmt.Sprintf("instance %s on %s belongs to session %s", e.InstanceID, e.Provider, e.SessionID)
+}
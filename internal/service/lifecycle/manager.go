@@ -2,6 +2,7 @@ package lifecycle
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
@@ -25,6 +26,21 @@ const (
 	// DefaultSSHHealthCheckInterval is how often to run SSH health checks
 	DefaultSSHHealthCheckInterval = 2 * time.Minute
 
+	// DefaultExpiryWarning is how long before expiry a running session is reported as expiring soon
+	DefaultExpiryWarning = 15 * time.Minute
+
+	// DefaultIdleWarning is how long before its idle policy destroys a session
+	// the session is reported as idle
+	DefaultIdleWarning = 5 * time.Minute
+
+	// DefaultHeartbeatTimeout is how long a running session can go without a
+	// heartbeat before it is marked degraded
+	DefaultHeartbeatTimeout = 5 * time.Minute
+
+	// DefaultMaxPausedDuration is how long a session can stay paused before
+	// it is destroyed
+	DefaultMaxPausedDuration = 24 * time.Hour
+
 	// DefaultStuckSessionTimeout is how long a session can be in a transitional state
 	// (stopping, provisioning) before being marked as failed
 	// Bug #103 fix: Prevent sessions from getting stuck indefinitely
@@ -37,8 +53,10 @@ type SessionStore interface {
 	GetExpiredSessions(ctx context.Context) ([]*models.Session, error)
 	GetSessionsByStatus(ctx context.Context, statuses ...models.SessionStatus) ([]*models.Session, error)
 	GetFailedSessionsWithInstances(ctx context.Context) ([]*models.Session, error)
+	GetActiveSessionsByGroup(ctx context.Context, groupID string) ([]*models.Session, error)
 	Get(ctx context.Context, id string) (*models.Session, error)
 	Update(ctx context.Context, session *models.Session) error
+	UpdateHealth(ctx context.Context, sessionID string, health models.SessionHealth) error
 }
 
 // SessionDestroyer handles session destruction
@@ -53,6 +71,34 @@ type EventHandler interface {
 	OnOrphanDetected(session *models.Session)
 }
 
+// ExpiryWarningHandler is an optional EventHandler extension that is told
+// once per reservation when a running session is about to expire.
+type ExpiryWarningHandler interface {
+	OnSessionExpiringSoon(session *models.Session)
+}
+
+// PreemptionHandler checks running interruptible sessions for preemption.
+// The provisioner implements it since it owns the providers and auto-retry.
+type PreemptionHandler interface {
+	// HandlePreemption reports whether the session's instance was reclaimed by
+	// its provider, failing the session and retrying it if so.
+	HandlePreemption(ctx context.Context, session *models.Session) (bool, error)
+}
+
+// IdleWarningHandler is an optional EventHandler extension that is told
+// once per idle stretch when a session's idle policy is about to destroy it.
+type IdleWarningHandler interface {
+	OnSessionIdle(warning models.SessionIdleWarning)
+}
+
+// HeartbeatReader polls running sessions for health and idle policies. The
+// provisioner implements it since it owns the providers.
+type HeartbeatReader interface {
+	// Heartbeat reports whether the session's instance is running and its
+	// GPU utilization in percent, nil if the provider does not report one.
+	Heartbeat(ctx context.Context, session *models.Session) (alive bool, gpuUtilPct *float64, err error)
+}
+
 // noopEventHandler is a default handler that does nothing
 type noopEventHandler struct{}
 
@@ -67,11 +113,30 @@ type Manager struct {
 	handler   EventHandler
 	logger    *slog.Logger
 
+	// preemption is optional; without it interruptible sessions are not polled
+	preemption PreemptionHandler
+
+	// heartbeats is optional; without it session health is not tracked and
+	// idle policies are not enforced
+	heartbeats       HeartbeatReader
+	heartbeatTimeout time.Duration
+
 	// Configuration
 	checkInterval       time.Duration
 	hardMaxHours        int
 	orphanGracePeriod   time.Duration
 	stuckSessionTimeout time.Duration // Bug #103 fix: timeout for stuck sessions
+	expiryWarning       time.Duration
+	maxPausedDuration   time.Duration
+
+	// expiryWarned maps session ID to the expiry already warned about, so an
+	// extended session is warned again for its new expiry
+	expiryWarned map[string]time.Time
+
+	// idleWarned maps session ID to the start of the idle stretch already
+	// warned about, so a session that goes idle again is warned again
+	idleWarned  map[string]time.Time
+	idleWarning time.Duration
 
 	// SSH health check configuration (optional)
 	sshExecutor            *ssh.Executor
@@ -105,6 +170,10 @@ type Metrics struct {
 	SSHHealthChecksRun      int64
 	SSHHealthChecksFailed   int64
 	FailedDestroysRecovered int64
+	SessionsPreempted       int64
+	IdleSessionsDestroyed   int64
+	SessionsDegraded        int64
+	PausedSessionsDestroyed int64
 }
 
 // Option configures the lifecycle manager
@@ -145,6 +214,13 @@ func WithEventHandler(handler EventHandler) Option {
 	}
 }
 
+// WithExpiryWarning sets how long before expiry sessions are reported as expiring soon
+func WithExpiryWarning(d time.Duration) Option {
+	return func(m *Manager) {
+		m.expiryWarning = d
+	}
+}
+
 // WithTimeFunc sets a custom time function (for testing)
 func WithTimeFunc(fn func() time.Time) Option {
 	return func(m *Manager) {
@@ -180,6 +256,41 @@ func WithSSHHealthCheckInterval(d time.Duration) Option {
 	}
 }
 
+// WithPreemptionHandler enables preemption checks for interruptible sessions
+func WithPreemptionHandler(h PreemptionHandler) Option {
+	return func(m *Manager) {
+		m.preemption = h
+	}
+}
+
+// WithHeartbeatReader enables session health tracking and idle policies
+func WithHeartbeatReader(r HeartbeatReader) Option {
+	return func(m *Manager) {
+		m.heartbeats = r
+	}
+}
+
+// WithHeartbeatTimeout sets how long a session can go without a heartbeat before it is degraded
+func WithHeartbeatTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		m.heartbeatTimeout = d
+	}
+}
+
+// WithIdleWarning sets how long before an idle session is destroyed it is reported as idle
+func WithIdleWarning(d time.Duration) Option {
+	return func(m *Manager) {
+		m.idleWarning = d
+	}
+}
+
+// WithMaxPausedDuration sets how long a session can stay paused before it is destroyed
+func WithMaxPausedDuration(d time.Duration) Option {
+	return func(m *Manager) {
+		m.maxPausedDuration = d
+	}
+}
+
 // New creates a new lifecycle manager
 func New(store SessionStore, destroyer SessionDestroyer, opts ...Option) *Manager {
 	m := &Manager{
@@ -191,6 +302,12 @@ func New(store SessionStore, destroyer SessionDestroyer, opts ...Option) *Manage
 		hardMaxHours:           DefaultHardMaxHours,
 		orphanGracePeriod:      DefaultOrphanGracePeriod,
 		stuckSessionTimeout:    DefaultStuckSessionTimeout,
+		expiryWarning:          DefaultExpiryWarning,
+		maxPausedDuration:      DefaultMaxPausedDuration,
+		expiryWarned:           make(map[string]time.Time),
+		idleWarned:             make(map[string]time.Time),
+		idleWarning:            DefaultIdleWarning,
+		heartbeatTimeout:       DefaultHeartbeatTimeout,
 		sshHealthCheckInterval: DefaultSSHHealthCheckInterval,
 		now:                    time.Now,
 		stopCh:                 make(chan struct{}),
@@ -220,6 +337,7 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.logger.Info("lifecycle manager starting",
 		slog.Duration("check_interval", m.checkInterval),
 		slog.Int("hard_max_hours", m.hardMaxHours),
+		slog.Bool("heartbeats_enabled", m.heartbeats != nil),
 		slog.Bool("ssh_health_check_enabled", m.sshHealthCheckEnabled),
 		slog.Duration("ssh_health_check_interval", m.sshHealthCheckInterval))
 
@@ -285,10 +403,15 @@ func (m *Manager) runChecks(ctx context.Context) {
 
 	// Run checks in order of priority
 	m.checkHardMax(ctx)
+	m.checkExpiringSoon(ctx)
 	m.checkReservationExpiry(ctx)
 	m.checkOrphans(ctx)
 	m.checkStuckSessions(ctx) // Bug #103 fix: Check for stuck sessions
 	m.checkFailedDestroys(ctx)
+	m.checkPreemptions(ctx)
+	m.checkHeartbeats(ctx)
+	m.checkIdleSessions(ctx)
+	m.checkPausedSessions(ctx)
 
 	// Run SSH health check if enabled and interval has passed
 	// Bug #17 fix: Protect lastSSHHealthCheck with mutex
@@ -307,7 +430,8 @@ func (m *Manager) runChecks(ctx context.Context) {
 	}
 }
 
-// checkHardMax enforces the 12-hour maximum session duration
+// checkHardMax enforces the 12-hour maximum session duration. Time spent
+// paused does not count.
 func (m *Manager) checkHardMax(ctx context.Context) {
 	sessions, err := m.store.GetActiveSessions(ctx)
 	if err != nil {
@@ -318,8 +442,13 @@ func (m *Manager) checkHardMax(ctx context.Context) {
 
 	now := m.now()
 	hardMaxDuration := time.Duration(m.hardMaxHours) * time.Hour
+	handled := make(map[string]bool)
 
 	for _, session := range sessions {
+		if handled[session.ID] {
+			continue
+		}
+
 		// Skip sessions with override
 		if session.HardMaxOverride {
 			continue
@@ -331,10 +460,10 @@ func (m *Manager) checkHardMax(ctx context.Context) {
 		}
 
 		// Check if session has exceeded hard max
-		if now.Sub(session.CreatedAt) > hardMaxDuration {
+		if age := session.RunningFor(now); age > hardMaxDuration {
 			m.logger.Warn("session exceeded hard max duration",
 				slog.String("session_id", session.ID),
-				slog.Duration("age", now.Sub(session.CreatedAt)),
+				slog.Duration("age", age),
 				slog.Duration("hard_max", hardMaxDuration))
 
 			m.metrics.mu.Lock()
@@ -346,15 +475,60 @@ func (m *Manager) checkHardMax(ctx context.Context) {
 				"session_id", session.ID,
 				"consumer_id", session.ConsumerID,
 				"provider", session.Provider,
-				"age_hours", now.Sub(session.CreatedAt).Hours(),
+				"age_hours", age.Hours(),
 				"hard_max_hours", m.hardMaxHours)
 			metrics.RecordHardMaxEnforced()
 			metrics.RecordSessionDestroyed(session.Provider, "hard_max")
 
 			m.handler.OnHardMaxReached(session)
 			m.destroySession(ctx, session, "hard max duration exceeded")
+			handled[session.ID] = true
+			m.expireGroup(ctx, session, handled)
+		}
+	}
+}
+
+// checkExpiringSoon notifies the event handler about running sessions that
+// will expire within the warning window. Skipped unless the handler
+// implements ExpiryWarningHandler.
+func (m *Manager) checkExpiringSoon(ctx context.Context) {
+	warner, ok := m.handler.(ExpiryWarningHandler)
+	if !ok || m.expiryWarning <= 0 {
+		return
+	}
+
+	sessions, err := m.store.GetSessionsByStatus(ctx, models.StatusRunning)
+	if err != nil {
+		m.logger.Error("failed to get running sessions for expiry warning check",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	now := m.now()
+	warned := make(map[string]time.Time, len(sessions))
+
+	for _, session := range sessions {
+		if prev, ok := m.expiryWarned[session.ID]; ok && prev.Equal(session.ExpiresAt) {
+			warned[session.ID] = prev
+			continue
+		}
+
+		remaining := session.ExpiresAt.Sub(now)
+		if remaining <= 0 || remaining > m.expiryWarning {
+			continue
 		}
+
+		m.logger.Info("session expiring soon",
+			slog.String("session_id", session.ID),
+			slog.Time("expires_at", session.ExpiresAt),
+			slog.Duration("remaining", remaining))
+
+		warner.OnSessionExpiringSoon(session)
+		warned[session.ID] = session.ExpiresAt
 	}
+
+	// Only running sessions are kept, so stopped sessions drop out of the map
+	m.expiryWarned = warned
 }
 
 // checkReservationExpiry handles sessions past their reservation time
@@ -366,7 +540,12 @@ func (m *Manager) checkReservationExpiry(ctx context.Context) {
 		return
 	}
 
+	handled := make(map[string]bool)
 	for _, session := range sessions {
+		if handled[session.ID] {
+			continue
+		}
+
 		m.logger.Info("session reservation expired",
 			slog.String("session_id", session.ID),
 			slog.Time("expires_at", session.ExpiresAt))
@@ -385,6 +564,53 @@ func (m *Manager) checkReservationExpiry(ctx context.Context) {
 
 		m.handler.OnSessionExpired(session)
 		m.destroySession(ctx, session, "reservation expired")
+		handled[session.ID] = true
+		m.expireGroup(ctx, session, handled)
+	}
+}
+
+// expireGroup destroys the rest of an expired session's group. A group
+// expires as a unit: once any member's time is up the others go with it,
+// rather than leaving a partial training job or sweep running. Members
+// already destroyed this check are recorded in handled.
+func (m *Manager) expireGroup(ctx context.Context, expired *models.Session, handled map[string]bool) {
+	if expired.GroupID == "" {
+		return
+	}
+
+	members, err := m.store.GetActiveSessionsByGroup(ctx, expired.GroupID)
+	if err != nil {
+		m.logger.Error("failed to get session group members",
+			slog.String("group_id", expired.GroupID),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, member := range members {
+		if handled[member.ID] {
+			continue
+		}
+		handled[member.ID] = true
+
+		m.logger.Info("session group expired",
+			slog.String("session_id", member.ID),
+			slog.String("group_id", member.GroupID),
+			slog.String("expired_session_id", expired.ID))
+
+		m.metrics.mu.Lock()
+		m.metrics.SessionsExpired++
+		m.metrics.mu.Unlock()
+
+		logging.Audit(ctx, "session_group_expired",
+			"session_id", member.ID,
+			"consumer_id", member.ConsumerID,
+			"provider", member.Provider,
+			"group_id", member.GroupID,
+			"expired_session_id", expired.ID)
+		metrics.RecordSessionDestroyed(member.Provider, "group_expired")
+
+		m.handler.OnSessionExpired(member)
+		m.destroySession(ctx, member, "session group expired")
 	}
 }
 
@@ -448,6 +674,8 @@ func (m *Manager) checkStuckSessions(ctx context.Context) {
 			// Mark session as failed
 			oldStatus := session.Status
 			session.Status = models.StatusFailed
+			session.FailureCategory = models.FailureProvisioningTimeout
+			session.FailureDetail = string(oldStatus)
 			// Bug fix: Use oldStatus (not session.Status which is now "failed") for error message
 			if oldStatus == models.StatusStopping {
 				session.Error = "Session stuck in stopping state - manual cleanup may be required"
@@ -470,7 +698,6 @@ func (m *Manager) checkStuckSessions(ctx context.Context) {
 				"provider", session.Provider,
 				"old_status", string(oldStatus),
 				"stuck_duration_minutes", stuckDuration.Minutes())
-			metrics.UpdateSessionStatus(session.Provider, string(oldStatus), string(models.StatusFailed))
 		}
 	}
 }
@@ -601,6 +828,250 @@ func (m *Manager) checkFailedDestroys(ctx context.Context) {
 	}
 }
 
+// checkPreemptions asks the preemption handler about every running
+// interruptible session, so outbid instances are replaced promptly
+func (m *Manager) checkPreemptions(ctx context.Context) {
+	if m.preemption == nil {
+		return
+	}
+
+	sessions, err := m.store.GetSessionsByStatus(ctx, models.StatusRunning)
+	if err != nil {
+		m.logger.Error("failed to get running sessions for preemption check",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, session := range sessions {
+		if !session.Interruptible {
+			continue
+		}
+
+		preempted, err := m.preemption.HandlePreemption(ctx, session)
+		if err != nil {
+			m.logger.Warn("preemption check failed",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		if !preempted {
+			continue
+		}
+
+		m.metrics.mu.Lock()
+		m.metrics.SessionsPreempted++
+		m.metrics.mu.Unlock()
+
+		logging.Audit(ctx, "session_preempted",
+			"session_id", session.ID,
+			"consumer_id", session.ConsumerID,
+			"provider", session.Provider,
+			"provider_id", session.ProviderID)
+	}
+}
+
+// checkHeartbeats polls every running session and records its health. A
+// heartbeat marks the session healthy and tracks how long its GPUs have been
+// below its idle threshold; a session without one for longer than the
+// heartbeat timeout is marked degraded. A failed poll changes neither the
+// last heartbeat nor the idle stretch.
+func (m *Manager) checkHeartbeats(ctx context.Context) {
+	if m.heartbeats == nil {
+		return
+	}
+
+	sessions, err := m.store.GetSessionsByStatus(ctx, models.StatusRunning)
+	if err != nil {
+		m.logger.Error("failed to get running sessions for heartbeat check",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	now := m.now()
+	for _, session := range sessions {
+		health := session.Health
+
+		alive, gpuUtilPct, err := m.heartbeats.Heartbeat(ctx, session)
+		if err != nil {
+			m.logger.Warn("session heartbeat failed",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+		}
+
+		if err == nil && alive {
+			if health.Status == models.HealthDegraded {
+				m.logger.Info("session heartbeat resumed",
+					slog.String("session_id", session.ID))
+			}
+			health.Status = models.HealthHealthy
+			health.LastHeartbeatAt = now
+			health.GPUUtilPct = gpuUtilPct
+			if gpuUtilPct != nil && session.IdlePolicy().Idle(*gpuUtilPct) {
+				if health.IdleSince.IsZero() {
+					health.IdleSince = now
+				}
+			} else {
+				health.IdleSince = time.Time{}
+			}
+		} else {
+			// Sessions are polled from the start, so one that never had a
+			// heartbeat has been silent since it was created
+			silentSince := health.LastHeartbeatAt
+			if silentSince.IsZero() {
+				silentSince = session.CreatedAt
+			}
+			silent := now.Sub(silentSince)
+			if silent <= m.heartbeatTimeout || health.Status == models.HealthDegraded {
+				continue
+			}
+
+			m.logger.Warn("session degraded: no heartbeat",
+				slog.String("session_id", session.ID),
+				slog.Duration("silent", silent),
+				slog.Duration("heartbeat_timeout", m.heartbeatTimeout))
+
+			m.metrics.mu.Lock()
+			m.metrics.SessionsDegraded++
+			m.metrics.mu.Unlock()
+
+			logging.Audit(ctx, "session_degraded",
+				"session_id", session.ID,
+				"consumer_id", session.ConsumerID,
+				"provider", session.Provider,
+				"silent_minutes", silent.Minutes())
+			health.Status = models.HealthDegraded
+		}
+
+		if err := m.store.UpdateHealth(ctx, session.ID, health); err != nil {
+			m.logger.Error("failed to update session health",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// checkIdleSessions enforces idle policies from the recorded heartbeats: a
+// running session whose GPUs have been idle for its policy's duration is
+// destroyed. The event handler is warned first if it implements
+// IdleWarningHandler. Only heartbeats count, so a session whose provider
+// does not report utilization, or that has stopped sending heartbeats, is
+// never destroyed as idle.
+func (m *Manager) checkIdleSessions(ctx context.Context) {
+	if m.heartbeats == nil {
+		return
+	}
+
+	sessions, err := m.store.GetSessionsByStatus(ctx, models.StatusRunning)
+	if err != nil {
+		m.logger.Error("failed to get running sessions for idle check",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	warner, canWarn := m.handler.(IdleWarningHandler)
+	warned := make(map[string]time.Time)
+
+	for _, session := range sessions {
+		policy := session.IdlePolicy()
+		health := session.Health
+		if !policy.Enabled() || health.IdleSince.IsZero() || health.GPUUtilPct == nil {
+			continue
+		}
+		idle := health.IdleFor()
+		pct := *health.GPUUtilPct
+
+		if idle >= policy.After {
+			m.logger.Info("session idle past its idle policy",
+				slog.String("session_id", session.ID),
+				slog.Float64("gpu_util_pct", pct),
+				slog.Duration("idle", idle),
+				slog.Duration("idle_threshold", policy.After))
+
+			m.metrics.mu.Lock()
+			m.metrics.IdleSessionsDestroyed++
+			m.metrics.mu.Unlock()
+
+			logging.Audit(ctx, "idle_session_destroyed",
+				"session_id", session.ID,
+				"consumer_id", session.ConsumerID,
+				"provider", session.Provider,
+				"gpu_util_pct", pct,
+				"idle_minutes", idle.Minutes(),
+				"idle_threshold_minutes", session.IdleThreshold)
+			metrics.RecordSessionDestroyed(session.Provider, "idle")
+
+			m.destroySession(ctx, session, fmt.Sprintf("idle for %s below %d%% GPU utilization",
+				idle.Round(time.Minute), policy.MaxGPUUtilPct))
+			continue
+		}
+
+		if prev, ok := m.idleWarned[session.ID]; ok && prev.Equal(health.IdleSince) {
+			warned[session.ID] = prev
+			continue
+		}
+		if !canWarn || policy.After-idle > m.idleWarning {
+			continue
+		}
+
+		terminateAt := health.IdleSince.Add(policy.After)
+		m.logger.Info("session idle, warning before destroy",
+			slog.String("session_id", session.ID),
+			slog.Float64("gpu_util_pct", pct),
+			slog.Time("terminate_at", terminateAt))
+
+		warner.OnSessionIdle(models.SessionIdleWarning{
+			Session:     session.ToResponse(),
+			GPUUtilPct:  pct,
+			IdleSince:   health.IdleSince,
+			TerminateAt: terminateAt,
+		})
+		warned[session.ID] = health.IdleSince
+	}
+
+	// Only idle running sessions are kept, so stopped sessions drop out of the map
+	m.idleWarned = warned
+}
+
+// checkPausedSessions destroys sessions paused for longer than the max
+// paused duration, whose instances would otherwise keep billing for storage
+func (m *Manager) checkPausedSessions(ctx context.Context) {
+	if m.maxPausedDuration <= 0 {
+		return
+	}
+	sessions, err := m.store.GetSessionsByStatus(ctx, models.StatusPaused)
+	if err != nil {
+		m.logger.Error("failed to get paused sessions",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	now := m.now()
+	for _, session := range sessions {
+		paused := now.Sub(session.PausedAt)
+		if session.PausedAt.IsZero() || paused <= m.maxPausedDuration {
+			continue
+		}
+
+		m.logger.Info("session paused past max paused duration",
+			slog.String("session_id", session.ID),
+			slog.Duration("paused", paused),
+			slog.Duration("max_paused", m.maxPausedDuration))
+
+		m.metrics.mu.Lock()
+		m.metrics.PausedSessionsDestroyed++
+		m.metrics.mu.Unlock()
+
+		logging.Audit(ctx, "max_paused_enforced",
+			"session_id", session.ID,
+			"consumer_id", session.ConsumerID,
+			"provider", session.Provider,
+			"paused_hours", paused.Hours())
+		metrics.RecordSessionDestroyed(session.Provider, "max_paused")
+
+		m.destroySession(ctx, session, "paused past max paused duration")
+	}
+}
+
 // SignalDone signals that a session has completed its work
 func (m *Manager) SignalDone(ctx context.Context, sessionID string) error {
 	session, err := m.store.Get(ctx, sessionID)
@@ -635,13 +1106,13 @@ func (m *Manager) ExtendSession(ctx context.Context, sessionID string, additiona
 	// Bug #7 fix: Check cumulative duration doesn't exceed hard max
 	// Calculate total duration from creation to new expiration
 	now := m.now()
-	totalDuration := now.Sub(session.CreatedAt) + time.Duration(additionalHours)*time.Hour
+	totalDuration := session.RunningFor(now) + time.Duration(additionalHours)*time.Hour
 	hardMaxDuration := time.Duration(m.hardMaxHours) * time.Hour
 
 	if !session.HardMaxOverride && totalDuration > hardMaxDuration {
 		return &HardMaxExceededError{
 			SessionID:       sessionID,
-			CurrentDuration: now.Sub(session.CreatedAt),
+			CurrentDuration: session.RunningFor(now),
 			RequestedHours:  additionalHours,
 			HardMaxHours:    m.hardMaxHours,
 		}
@@ -694,6 +1165,10 @@ func (m *Manager) GetMetrics() Metrics {
 		SSHHealthChecksRun:      m.metrics.SSHHealthChecksRun,
 		SSHHealthChecksFailed:   m.metrics.SSHHealthChecksFailed,
 		FailedDestroysRecovered: m.metrics.FailedDestroysRecovered,
+		SessionsPreempted:       m.metrics.SessionsPreempted,
+		IdleSessionsDestroyed:   m.metrics.IdleSessionsDestroyed,
+		SessionsDegraded:        m.metrics.SessionsDegraded,
+		PausedSessionsDestroyed: m.metrics.PausedSessionsDestroyed,
 	}
 }
 
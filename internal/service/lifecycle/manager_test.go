@@ -79,6 +79,20 @@ func (m *mockSessionStore) GetFailedSessionsWithInstances(ctx context.Context) (
 	return result, nil
 }
 
+func (m *mockSessionStore) GetActiveSessionsByGroup(ctx context.Context, groupID string) ([]*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*models.Session
+	for _, s := range m.sessions {
+		if s.GroupID == groupID && s.IsActive() {
+			copy := *s
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockSessionStore) GetSessionsByStatus(ctx context.Context, statuses ...models.SessionStatus) ([]*models.Session, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -121,6 +135,18 @@ func (m *mockSessionStore) Update(ctx context.Context, session *models.Session)
 	return nil
 }
 
+func (m *mockSessionStore) UpdateHealth(ctx context.Context, sessionID string, health models.SessionHealth) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return &SessionNotFoundError{ID: sessionID}
+	}
+	s.Health = health
+	return nil
+}
+
 // mockDestroyer implements SessionDestroyer for testing
 type mockDestroyer struct {
 	mu           sync.Mutex
@@ -271,6 +297,47 @@ func TestManager_CheckHardMax(t *testing.T) {
 	assert.Equal(t, "sess-old", handler.hardMaxSessions[0].ID)
 }
 
+func TestManager_CheckPausedSessions(t *testing.T) {
+	store := newMockSessionStore()
+	destroyer := newMockDestroyer()
+	now := time.Now()
+
+	// Paused past the max, and running 13 hours of which 4 were paused
+	store.add(&models.Session{
+		ID:        "sess-stale",
+		Status:    models.StatusPaused,
+		CreatedAt: now.Add(-30 * time.Hour),
+		PausedAt:  now.Add(-25 * time.Hour),
+	})
+	store.add(&models.Session{
+		ID:        "sess-paused",
+		Status:    models.StatusPaused,
+		CreatedAt: now.Add(-2 * time.Hour),
+		PausedAt:  now.Add(-time.Hour),
+	})
+	store.add(&models.Session{
+		ID:          "sess-resumed",
+		Status:      models.StatusRunning,
+		CreatedAt:   now.Add(-13 * time.Hour),
+		ExpiresAt:   now.Add(time.Hour),
+		PausedTotal: 4 * time.Hour,
+	})
+
+	m := New(store, destroyer,
+		WithLogger(newTestLogger()),
+		WithHardMaxHours(12),
+		WithMaxPausedDuration(24*time.Hour),
+		WithTimeFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	m.checkPausedSessions(ctx)
+	m.checkHardMax(ctx)
+
+	// Time paused does not count toward the hard max
+	assert.Equal(t, []string{"sess-stale"}, destroyer.getDestroyCalls())
+	assert.Equal(t, int64(1), m.GetMetrics().PausedSessionsDestroyed)
+}
+
 func TestManager_CheckReservationExpiry(t *testing.T) {
 	now := time.Now()
 
@@ -310,6 +377,57 @@ func TestManager_CheckReservationExpiry(t *testing.T) {
 	assert.Len(t, handler.expiredSessions, 1)
 }
 
+func TestManager_CheckReservationExpiry_Group(t *testing.T) {
+	now := time.Now()
+
+	store := &mockSessionStoreWithExpiry{
+		sessions: make(map[string]*models.Session),
+		now:      now,
+	}
+	destroyer := newMockDestroyer()
+	handler := newMockEventHandler()
+
+	// Two members have expired, one has not; an unrelated session is valid
+	store.add(&models.Session{
+		ID:        "sess-a",
+		GroupID:   "job-1",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-3 * time.Hour),
+		ExpiresAt: now.Add(-1 * time.Hour),
+	})
+	store.add(&models.Session{
+		ID:        "sess-b",
+		GroupID:   "job-1",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-3 * time.Hour),
+		ExpiresAt: now.Add(-1 * time.Hour),
+	})
+	store.add(&models.Session{
+		ID:        "sess-c",
+		GroupID:   "job-1",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-3 * time.Hour),
+		ExpiresAt: now.Add(2 * time.Hour),
+	})
+	store.add(&models.Session{
+		ID:        "sess-other",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-1 * time.Hour),
+		ExpiresAt: now.Add(1 * time.Hour),
+	})
+
+	m := New(store, destroyer,
+		WithLogger(newTestLogger()),
+		WithEventHandler(handler))
+
+	m.checkReservationExpiry(context.Background())
+
+	// The whole group goes, each member exactly once
+	assert.ElementsMatch(t, []string{"sess-a", "sess-b", "sess-c"}, destroyer.getDestroyCalls())
+	assert.Len(t, handler.expiredSessions, 3)
+	assert.Equal(t, int64(3), m.GetMetrics().SessionsExpired)
+}
+
 // mockSessionStoreWithExpiry is like mockSessionStore but uses custom now for expiry check
 type mockSessionStoreWithExpiry struct {
 	mu       sync.RWMutex
@@ -323,6 +441,19 @@ func (m *mockSessionStoreWithExpiry) add(session *models.Session) {
 	m.sessions[session.ID] = session
 }
 
+func (m *mockSessionStoreWithExpiry) GetActiveSessionsByGroup(ctx context.Context, groupID string) ([]*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*models.Session
+	for _, s := range m.sessions {
+		if s.GroupID == groupID && s.IsActive() {
+			copy := *s
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockSessionStoreWithExpiry) GetActiveSessions(ctx context.Context) ([]*models.Session, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -397,6 +528,17 @@ func (m *mockSessionStoreWithExpiry) Update(ctx context.Context, session *models
 	return nil
 }
 
+func (m *mockSessionStoreWithExpiry) UpdateHealth(ctx context.Context, sessionID string, health models.SessionHealth) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return &SessionNotFoundError{ID: sessionID}
+	}
+	s.Health = health
+	return nil
+}
+
 func TestManager_CheckOrphans(t *testing.T) {
 	store := newMockSessionStore()
 	destroyer := newMockDestroyer()
@@ -893,3 +1035,317 @@ func TestManager_CheckFailedDestroys_DestroyFails(t *testing.T) {
 	assert.Equal(t, models.StatusFailed, session.Status)
 	assert.Equal(t, "td-instance-42", session.ProviderID)
 }
+
+// expiryWarningHandler adds expiry warnings to mockEventHandler
+type expiryWarningHandler struct {
+	*mockEventHandler
+	expiringSessions []string
+}
+
+func (h *expiryWarningHandler) OnSessionExpiringSoon(session *models.Session) {
+	h.expiringSessions = append(h.expiringSessions, session.ID)
+}
+
+func TestManager_CheckExpiringSoon(t *testing.T) {
+	store := newMockSessionStore()
+	destroyer := newMockDestroyer()
+	handler := &expiryWarningHandler{mockEventHandler: newMockEventHandler()}
+
+	now := time.Now()
+	soon := &models.Session{
+		ID:        "sess-soon",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-1 * time.Hour),
+		ExpiresAt: now.Add(10 * time.Minute),
+	}
+	store.add(soon)
+	store.add(&models.Session{
+		ID:        "sess-later",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-1 * time.Hour),
+		ExpiresAt: now.Add(2 * time.Hour),
+	})
+
+	m := New(store, destroyer,
+		WithLogger(newTestLogger()),
+		WithEventHandler(handler),
+		WithExpiryWarning(15*time.Minute),
+		WithTimeFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	m.checkExpiringSoon(ctx)
+	assert.Equal(t, []string{"sess-soon"}, handler.expiringSessions)
+
+	// Warned only once per expiry
+	m.checkExpiringSoon(ctx)
+	assert.Equal(t, []string{"sess-soon"}, handler.expiringSessions)
+
+	// Extending and approaching the new expiry warns again
+	soon.ExpiresAt = now.Add(1 * time.Hour)
+	m.checkExpiringSoon(ctx)
+	assert.Len(t, handler.expiringSessions, 1)
+
+	now = now.Add(50 * time.Minute)
+	m.checkExpiringSoon(ctx)
+	assert.Equal(t, []string{"sess-soon", "sess-soon"}, handler.expiringSessions)
+}
+
+func TestManager_CheckExpiringSoon_RequiresHandler(t *testing.T) {
+	store := newMockSessionStore()
+	now := time.Now()
+	store.add(&models.Session{
+		ID:        "sess-soon",
+		Status:    models.StatusRunning,
+		ExpiresAt: now.Add(5 * time.Minute),
+	})
+
+	// The default handler does not implement ExpiryWarningHandler; must not panic
+	m := New(store, newMockDestroyer(),
+		WithLogger(newTestLogger()),
+		WithTimeFunc(func() time.Time { return now }))
+	m.checkExpiringSoon(context.Background())
+	assert.Empty(t, m.expiryWarned)
+}
+
+// mockPreemptionHandler implements PreemptionHandler for testing
+type mockPreemptionHandler struct {
+	mu        sync.Mutex
+	preempted map[string]bool
+	checked   []string
+}
+
+func (m *mockPreemptionHandler) HandlePreemption(ctx context.Context, session *models.Session) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checked = append(m.checked, session.ID)
+	return m.preempted[session.ID], nil
+}
+
+func TestManager_CheckPreemptions(t *testing.T) {
+	store := newMockSessionStore()
+	destroyer := newMockDestroyer()
+	now := time.Now()
+
+	store.add(&models.Session{ID: "sess-bid-outbid", Status: models.StatusRunning, Interruptible: true, ProviderID: "inst-1", ExpiresAt: now.Add(time.Hour)})
+	store.add(&models.Session{ID: "sess-bid-ok", Status: models.StatusRunning, Interruptible: true, ProviderID: "inst-2", ExpiresAt: now.Add(time.Hour)})
+	store.add(&models.Session{ID: "sess-on-demand", Status: models.StatusRunning, ProviderID: "inst-3", ExpiresAt: now.Add(time.Hour)})
+	store.add(&models.Session{ID: "sess-bid-stopped", Status: models.StatusStopped, Interruptible: true, ExpiresAt: now.Add(time.Hour)})
+
+	handler := &mockPreemptionHandler{preempted: map[string]bool{"sess-bid-outbid": true}}
+	m := New(store, destroyer,
+		WithLogger(newTestLogger()),
+		WithPreemptionHandler(handler))
+
+	m.checkPreemptions(context.Background())
+
+	// Only running interruptible sessions are checked
+	assert.ElementsMatch(t, []string{"sess-bid-outbid", "sess-bid-ok"}, handler.checked)
+	assert.Equal(t, int64(1), m.GetMetrics().SessionsPreempted)
+
+	// The handler owns cleanup; the manager does not destroy anything itself
+	assert.Empty(t, destroyer.getDestroyCalls())
+}
+
+func TestManager_CheckPreemptions_NoHandler(t *testing.T) {
+	store := newMockSessionStore()
+	store.add(&models.Session{ID: "sess-bid", Status: models.StatusRunning, Interruptible: true, ExpiresAt: time.Now().Add(time.Hour)})
+
+	m := New(store, newMockDestroyer(), WithLogger(newTestLogger()))
+	m.checkPreemptions(context.Background())
+
+	assert.Equal(t, int64(0), m.GetMetrics().SessionsPreempted)
+}
+
+// mockHeartbeatReader implements HeartbeatReader for testing
+type mockHeartbeatReader struct {
+	mu   sync.Mutex
+	util map[string]float64 // Missing sessions are alive but unreported
+	down map[string]bool    // Sessions whose heartbeat fails
+}
+
+func newMockHeartbeatReader(util map[string]float64) *mockHeartbeatReader {
+	return &mockHeartbeatReader{util: util, down: make(map[string]bool)}
+}
+
+func (m *mockHeartbeatReader) Heartbeat(ctx context.Context, session *models.Session) (bool, *float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down[session.ID] {
+		return false, nil, errors.New("provider unreachable")
+	}
+	pct, ok := m.util[session.ID]
+	if !ok {
+		return true, nil, nil
+	}
+	return true, &pct, nil
+}
+
+func (m *mockHeartbeatReader) set(sessionID string, pct float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.util[sessionID] = pct
+}
+
+func (m *mockHeartbeatReader) setDown(sessionID string, down bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down[sessionID] = down
+}
+
+// idleWarningHandler adds idle warnings to mockEventHandler
+type idleWarningHandler struct {
+	*mockEventHandler
+	warnings []models.SessionIdleWarning
+}
+
+func (h *idleWarningHandler) OnSessionIdle(warning models.SessionIdleWarning) {
+	h.warnings = append(h.warnings, warning)
+}
+
+func TestManager_CheckHeartbeats(t *testing.T) {
+	store := newMockSessionStore()
+	now := time.Now()
+	store.add(&models.Session{
+		ID:        "sess-1",
+		Status:    models.StatusRunning,
+		CreatedAt: now.Add(-1 * time.Hour),
+		ExpiresAt: now.Add(2 * time.Hour),
+	})
+
+	reader := newMockHeartbeatReader(map[string]float64{"sess-1": 42})
+	m := New(store, newMockDestroyer(),
+		WithLogger(newTestLogger()),
+		WithHeartbeatReader(reader),
+		WithHeartbeatTimeout(5*time.Minute),
+		WithTimeFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	m.checkHeartbeats(ctx)
+	sess, err := store.Get(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.HealthHealthy, sess.Health.Status)
+	assert.Equal(t, now, sess.Health.LastHeartbeatAt)
+	require.NotNil(t, sess.Health.GPUUtilPct)
+	assert.Equal(t, 42.0, *sess.Health.GPUUtilPct)
+	assert.True(t, sess.Health.IdleSince.IsZero(), "sessions without an idle policy are never idle")
+
+	// A missed heartbeat within the timeout keeps the session healthy
+	reader.setDown("sess-1", true)
+	now = now.Add(3 * time.Minute)
+	m.checkHeartbeats(ctx)
+	sess, _ = store.Get(ctx, "sess-1")
+	assert.Equal(t, models.HealthHealthy, sess.Health.Status)
+
+	// Silence past the timeout degrades it, once
+	now = now.Add(3 * time.Minute)
+	m.checkHeartbeats(ctx)
+	m.checkHeartbeats(ctx)
+	sess, _ = store.Get(ctx, "sess-1")
+	assert.Equal(t, models.HealthDegraded, sess.Health.Status)
+	assert.Equal(t, now.Add(-6*time.Minute), sess.Health.LastHeartbeatAt)
+	assert.Equal(t, int64(1), m.GetMetrics().SessionsDegraded)
+
+	// The next heartbeat restores it
+	reader.setDown("sess-1", false)
+	now = now.Add(time.Minute)
+	m.checkHeartbeats(ctx)
+	sess, _ = store.Get(ctx, "sess-1")
+	assert.Equal(t, models.HealthHealthy, sess.Health.Status)
+	assert.Equal(t, now, sess.Health.LastHeartbeatAt)
+}
+
+func TestManager_CheckIdleSessions(t *testing.T) {
+	store := newMockSessionStore()
+	destroyer := newMockDestroyer()
+	handler := &idleWarningHandler{mockEventHandler: newMockEventHandler()}
+
+	now := time.Now()
+	running := func(id string, idleMinutes, utilPct int) *models.Session {
+		return &models.Session{
+			ID:             id,
+			Status:         models.StatusRunning,
+			IdleThreshold:  idleMinutes,
+			IdleGPUUtilPct: utilPct,
+			CreatedAt:      now.Add(-1 * time.Hour),
+			ExpiresAt:      now.Add(2 * time.Hour),
+		}
+	}
+	store.add(running("sess-idle", 30, 0))
+	store.add(running("sess-busy", 30, 0))
+	store.add(running("sess-custom", 30, 20))
+	store.add(running("sess-no-policy", 0, 0))
+	store.add(running("sess-unreported", 30, 0))
+
+	reader := newMockHeartbeatReader(map[string]float64{
+		"sess-idle":      1,
+		"sess-busy":      80,
+		"sess-custom":    15,
+		"sess-no-policy": 0,
+	})
+	m := New(store, destroyer,
+		WithLogger(newTestLogger()),
+		WithEventHandler(handler),
+		WithHeartbeatReader(reader),
+		WithTimeFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	tick := func() {
+		m.checkHeartbeats(ctx)
+		m.checkIdleSessions(ctx)
+	}
+	tick()
+	assert.Empty(t, destroyer.getDestroyCalls())
+	assert.Empty(t, handler.warnings)
+
+	// Warned once, DefaultIdleWarning before the policy's 30 minutes are up
+	now = now.Add(25 * time.Minute)
+	tick()
+	tick()
+	require.Len(t, handler.warnings, 2)
+	assert.ElementsMatch(t, []string{"sess-idle", "sess-custom"},
+		[]string{handler.warnings[0].Session.ID, handler.warnings[1].Session.ID})
+	assert.Equal(t, now.Add(5*time.Minute), handler.warnings[0].TerminateAt)
+
+	// Activity before the deadline cancels the termination
+	reader.set("sess-custom", 40)
+	now = now.Add(5 * time.Minute)
+	tick()
+	assert.Equal(t, []string{"sess-idle"}, destroyer.getDestroyCalls())
+	assert.Equal(t, int64(1), m.GetMetrics().IdleSessionsDestroyed)
+	custom, err := store.Get(ctx, "sess-custom")
+	require.NoError(t, err)
+	assert.True(t, custom.Health.IdleSince.IsZero())
+
+	// Going idle again starts a new stretch and a new warning
+	destroyed, err := store.Get(ctx, "sess-idle")
+	require.NoError(t, err)
+	destroyed.Status = models.StatusStopped
+	require.NoError(t, store.Update(ctx, destroyed))
+	reader.set("sess-custom", 2)
+	tick()
+	now = now.Add(29 * time.Minute)
+	tick()
+	require.Len(t, handler.warnings, 3)
+	assert.Equal(t, "sess-custom", handler.warnings[2].Session.ID)
+	assert.Len(t, destroyer.getDestroyCalls(), 1)
+
+	// A session without heartbeats is never destroyed as idle
+	reader.setDown("sess-custom", true)
+	now = now.Add(10 * time.Minute)
+	tick()
+	assert.Len(t, destroyer.getDestroyCalls(), 1)
+}
+
+func TestManager_CheckIdleSessions_NoReader(t *testing.T) {
+	store := newMockSessionStore()
+	store.add(&models.Session{ID: "sess-idle", Status: models.StatusRunning, IdleThreshold: 1, ExpiresAt: time.Now().Add(time.Hour)})
+
+	m := New(store, newMockDestroyer(), WithLogger(newTestLogger()))
+	m.checkHeartbeats(context.Background())
+	m.checkIdleSessions(context.Background())
+
+	sess, err := store.Get(context.Background(), "sess-idle")
+	require.NoError(t, err)
+	assert.Empty(t, sess.Health.Status)
+	assert.Equal(t, int64(0), m.GetMetrics().IdleSessionsDestroyed)
+}
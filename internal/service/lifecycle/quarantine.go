@@ -0,0 +1,237 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/logging"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Destroying a quarantined instance is re-attempted after
+// destroyRetryBaseDelay, doubling after each failure up to destroyRetryMaxDelay
+const (
+	destroyRetryBaseDelay = 15 * time.Minute
+	destroyRetryMaxDelay  = 24 * time.Hour
+)
+
+// QuarantineStore persists instances that could not be destroyed, so they
+// are surfaced until an operator resolves them
+type QuarantineStore interface {
+	Quarantine(ctx context.Context, q *models.QuarantinedInstance) error
+	ListQuarantined(ctx context.Context) ([]*models.QuarantinedInstance, error)
+	Resolve(ctx context.Context, provider, instanceID string) error
+}
+
+// DestroyFailureNotifier alerts a session's consumer that its instance still
+// could not be destroyed
+type DestroyFailureNotifier interface {
+	NotifyDestroyFailed(ctx context.Context, failure models.SessionDestroyFailure)
+}
+
+// WithQuarantine records orphans and leaked instances the reconciler fails to
+// destroy in q. Every pass re-attempts destroying the quarantined instances
+// that are due, backing off up to a day between attempts, and they leave
+// quarantine once the provider confirms they are gone.
+func WithQuarantine(q QuarantineStore) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.quarantine = q
+	}
+}
+
+// WithDestroyFailureNotifier alerts consumers through n each time a scheduled
+// destroy of their session's instance fails
+func WithDestroyFailureNotifier(n DestroyFailureNotifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.destroyAlerts = n
+	}
+}
+
+// quarantineInstance records a failed destroy of a provider instance
+func (r *Reconciler) quarantineInstance(ctx context.Context, providerName, instanceID, sessionID string, source models.QuarantineSource, destroyErr error) {
+	if r.quarantine == nil {
+		return
+	}
+	q := &models.QuarantinedInstance{
+		Provider:   providerName,
+		InstanceID: instanceID,
+		SessionID:  sessionID,
+		Source:     source,
+		Reason:     destroyErr.Error(),
+		LastSeenAt: r.now(),
+		// The instance is listed, so the next pass destroys it again anyway
+		NextAttemptAt: r.now().Add(destroyRetryBaseDelay),
+	}
+	if err := r.quarantine.Quarantine(ctx, q); err != nil {
+		r.logger.Error("failed to quarantine instance",
+			slog.String("provider_id", instanceID),
+			slog.String("error", err.Error()))
+		return
+	}
+	logging.Audit(ctx, "instance_quarantined",
+		"provider", providerName,
+		"provider_id", instanceID,
+		"session_id", sessionID,
+		"source", string(source),
+		"reason", q.Reason)
+}
+
+// quarantinedInstances returns the IDs of a provider's quarantined
+// instances, which retryDestroys destroys on their own schedule
+func (r *Reconciler) quarantinedInstances(ctx context.Context, providerName string) map[string]bool {
+	quarantined := make(map[string]bool)
+	if r.quarantine == nil {
+		return quarantined
+	}
+	instances, err := r.quarantine.ListQuarantined(ctx)
+	if err != nil {
+		r.logger.Error("failed to list quarantined instances",
+			slog.String("error", err.Error()))
+		return quarantined
+	}
+	for _, q := range instances {
+		if q.Provider == providerName {
+			quarantined[q.InstanceID] = true
+		}
+	}
+	return quarantined
+}
+
+// releaseQuarantine takes a destroyed instance out of quarantine, if it was in
+func (r *Reconciler) releaseQuarantine(ctx context.Context, providerName, instanceID string) {
+	if r.quarantine == nil {
+		return
+	}
+	if err := r.quarantine.Resolve(ctx, providerName, instanceID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		r.logger.Warn("failed to release destroyed instance from quarantine",
+			slog.String("provider_id", instanceID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// retryDestroys re-attempts destroying every quarantined instance that is
+// due. An instance the provider no longer runs leaves quarantine; one that
+// still runs is scheduled again, later each time, and its consumer alerted.
+func (r *Reconciler) retryDestroys(ctx context.Context) {
+	if r.quarantine == nil {
+		return
+	}
+	quarantined, err := r.quarantine.ListQuarantined(ctx)
+	if err != nil {
+		r.logger.Error("failed to list quarantined instances",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	now := r.now()
+	for _, q := range quarantined {
+		if q.NextAttemptAt.After(now) {
+			continue
+		}
+		prov, err := r.providers.Get(q.Provider)
+		if err != nil {
+			continue
+		}
+
+		err = destroyAndConfirm(ctx, prov, q.InstanceID)
+		if err == nil {
+			r.logger.Info("quarantined instance destroyed",
+				slog.String("provider", q.Provider),
+				slog.String("provider_id", q.InstanceID),
+				slog.Int("failures", q.Failures))
+			logging.Audit(ctx, "quarantined_instance_destroyed",
+				"provider", q.Provider,
+				"provider_id", q.InstanceID,
+				"session_id", q.SessionID)
+			r.releaseQuarantine(ctx, q.Provider, q.InstanceID)
+			continue
+		}
+
+		q.Reason = err.Error()
+		q.LastSeenAt = now
+		q.NextAttemptAt = now.Add(destroyRetryDelay(q.Failures + 1))
+		r.logger.Error("CRITICAL: scheduled destroy of quarantined instance failed",
+			slog.String("provider", q.Provider),
+			slog.String("provider_id", q.InstanceID),
+			slog.String("session_id", q.SessionID),
+			slog.Int("failures", q.Failures+1),
+			slog.Time("next_attempt_at", q.NextAttemptAt),
+			slog.String("error", err.Error()))
+		metrics.RecordDestroyFailure()
+		if err := r.quarantine.Quarantine(ctx, q); err != nil {
+			r.logger.Error("failed to reschedule quarantined instance",
+				slog.String("provider_id", q.InstanceID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		r.alertDestroyFailed(ctx, q)
+	}
+}
+
+// destroyAndConfirm destroys an instance and reports an error unless the
+// provider confirms it is gone. A stopped instance still bills storage, so
+// only its absence counts, whether or not the destroy call succeeded.
+func destroyAndConfirm(ctx context.Context, prov provider.Provider, instanceID string) error {
+	destroyErr := prov.DestroyInstance(ctx, instanceID)
+	if errors.Is(destroyErr, provider.ErrInstanceNotFound) {
+		return nil
+	}
+	status, err := prov.GetInstanceStatus(ctx, instanceID)
+	switch {
+	case errors.Is(err, provider.ErrInstanceNotFound):
+		return nil
+	case destroyErr != nil:
+		return destroyErr
+	case err != nil:
+		return fmt.Errorf("failed to confirm destruction: %w", err)
+	case status.Running:
+		return errors.New("instance still running after destroy")
+	}
+	return fmt.Errorf("instance still listed after destroy (status %q)", status.Status)
+}
+
+// destroyRetryDelay returns how long to wait before re-attempting a destroy
+// that has failed the given number of times
+func destroyRetryDelay(failures int) time.Duration {
+	delay := destroyRetryBaseDelay
+	for i := 1; i < failures && delay < destroyRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, destroyRetryMaxDelay)
+}
+
+// alertDestroyFailed tells the consumer of a quarantined instance's session
+// that it still could not be destroyed
+func (r *Reconciler) alertDestroyFailed(ctx context.Context, q *models.QuarantinedInstance) {
+	if r.destroyAlerts == nil || q.SessionID == "" {
+		return
+	}
+	session, err := r.store.Get(ctx, q.SessionID)
+	if err != nil {
+		return
+	}
+	r.destroyAlerts.NotifyDestroyFailed(ctx, models.SessionDestroyFailure{
+		Session:  session.ToResponse(),
+		Instance: *q,
+	})
+}
+
+// updateQuarantineGauge publishes how many instances are quarantined
+func (r *Reconciler) updateQuarantineGauge(ctx context.Context) {
+	if r.quarantine == nil {
+		return
+	}
+	quarantined, err := r.quarantine.ListQuarantined(ctx)
+	if err != nil {
+		r.logger.Error("failed to list quarantined instances",
+			slog.String("error", err.Error()))
+		return
+	}
+	metrics.QuarantinedInstances.Set(float64(len(quarantined)))
+}
@@ -0,0 +1,254 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockQuarantineStore implements QuarantineStore for testing
+type mockQuarantineStore struct {
+	mu        sync.Mutex
+	instances map[string]*models.QuarantinedInstance
+}
+
+func newMockQuarantineStore() *mockQuarantineStore {
+	return &mockQuarantineStore{instances: make(map[string]*models.QuarantinedInstance)}
+}
+
+func (m *mockQuarantineStore) Quarantine(ctx context.Context, q *models.QuarantinedInstance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := q.Provider + "/" + q.InstanceID
+	if existing, ok := m.instances[key]; ok {
+		q.Failures = existing.Failures + 1
+	} else {
+		q.Failures = 1
+	}
+	m.instances[key] = q
+	return nil
+}
+
+func (m *mockQuarantineStore) ListQuarantined(ctx context.Context) ([]*models.QuarantinedInstance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.QuarantinedInstance
+	for _, q := range m.instances {
+		result = append(result, q)
+	}
+	return result, nil
+}
+
+func (m *mockQuarantineStore) Resolve(ctx context.Context, providerName, instanceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := providerName + "/" + instanceID
+	if _, ok := m.instances[key]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(m.instances, key)
+	return nil
+}
+
+// mockDestroyFailureNotifier implements DestroyFailureNotifier for testing
+type mockDestroyFailureNotifier struct {
+	mu       sync.Mutex
+	failures []models.SessionDestroyFailure
+}
+
+func (m *mockDestroyFailureNotifier) NotifyDestroyFailed(ctx context.Context, failure models.SessionDestroyFailure) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures = append(m.failures, failure)
+}
+
+func (m *mockDestroyFailureNotifier) getFailures() []models.SessionDestroyFailure {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.SessionDestroyFailure(nil), m.failures...)
+}
+
+func TestDestroyRetryDelay(t *testing.T) {
+	assert.Equal(t, 15*time.Minute, destroyRetryDelay(1))
+	assert.Equal(t, 30*time.Minute, destroyRetryDelay(2))
+	assert.Equal(t, 4*time.Hour, destroyRetryDelay(5))
+	assert.Equal(t, 24*time.Hour, destroyRetryDelay(8))
+	assert.Equal(t, 24*time.Hour, destroyRetryDelay(100))
+}
+
+func TestReconciler_RetriesQuarantinedDestroys(t *testing.T) {
+	store := newMockReconcileStore()
+	store.add(&models.Session{
+		ID:         "sess-stuck",
+		ConsumerID: "consumer-1",
+		Provider:   "vastai",
+		Status:     models.StatusStopped,
+	})
+	registry := newMockProviderRegistry()
+
+	// The instance is no longer listed, so only the retry queue destroys it
+	prov := newMockReconcileProvider("vastai")
+	prov.destroyErr = errors.New("instance is locked")
+	status := &provider.InstanceStatus{Status: "running", Running: true}
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		prov.mu.Lock()
+		defer prov.mu.Unlock()
+		if status == nil {
+			return nil, provider.ErrInstanceNotFound
+		}
+		return status, nil
+	}
+	registry.Add(prov)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	quarantine := newMockQuarantineStore()
+	ctx := context.Background()
+	require.NoError(t, quarantine.Quarantine(ctx, &models.QuarantinedInstance{
+		Provider:      "vastai",
+		InstanceID:    "stuck-instance",
+		SessionID:     "sess-stuck",
+		Source:        models.QuarantineDestroy,
+		Reason:        "destroy verification failed",
+		LastSeenAt:    now,
+		NextAttemptAt: now,
+	}))
+
+	alerts := &mockDestroyFailureNotifier{}
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithReconcileTimeFunc(func() time.Time { return now }),
+		WithQuarantine(quarantine),
+		WithDestroyFailureNotifier(alerts))
+
+	r.RunReconciliation(ctx)
+
+	quarantined, err := quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, 2, quarantined[0].Failures)
+	assert.Equal(t, "instance is locked", quarantined[0].Reason)
+	assert.Equal(t, now.Add(30*time.Minute), quarantined[0].NextAttemptAt)
+	require.Len(t, alerts.getFailures(), 1)
+	assert.Equal(t, "consumer-1", alerts.getFailures()[0].Session.ConsumerID)
+	assert.Equal(t, "stuck-instance", alerts.getFailures()[0].Instance.InstanceID)
+
+	// Not due yet
+	now = now.Add(10 * time.Minute)
+	r.RunReconciliation(ctx)
+	assert.Len(t, prov.getDestroyCalls(), 1)
+
+	// The provider still reports the instance after an accepted destroy
+	prov.mu.Lock()
+	prov.destroyErr = nil
+	prov.mu.Unlock()
+	now = now.Add(20 * time.Minute)
+	r.RunReconciliation(ctx)
+	quarantined, err = quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, "instance still running after destroy", quarantined[0].Reason)
+	assert.Equal(t, now.Add(time.Hour), quarantined[0].NextAttemptAt)
+	assert.Len(t, alerts.getFailures(), 2)
+
+	// A stopped instance still bills storage, so an accepted destroy that
+	// leaves it listed is no confirmation
+	prov.mu.Lock()
+	status = &provider.InstanceStatus{Status: "stopped"}
+	prov.mu.Unlock()
+	now = now.Add(time.Hour)
+	r.RunReconciliation(ctx)
+	quarantined, err = quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, `instance still listed after destroy (status "stopped")`, quarantined[0].Reason)
+	assert.Len(t, alerts.getFailures(), 3)
+
+	// Once the provider confirms it is gone it leaves quarantine
+	prov.mu.Lock()
+	status = nil
+	prov.mu.Unlock()
+	now = now.Add(2 * time.Hour)
+	r.RunReconciliation(ctx)
+	quarantined, err = quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, quarantined)
+	assert.Len(t, prov.getDestroyCalls(), 4)
+	assert.Len(t, alerts.getFailures(), 3)
+}
+
+func TestReconciler_QuarantinesUndestroyableOrphan(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	prov := newMockReconcileProvider("vastai")
+	prov.instances = []provider.ProviderInstance{{
+		ID:     "stuck-instance",
+		Status: "running",
+		Tags:   models.InstanceTags{ShopperSessionID: "gone-session"},
+	}}
+	prov.destroyErr = errors.New("instance is locked")
+	registry.Add(prov)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	quarantine := newMockQuarantineStore()
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithReconcileTimeFunc(func() time.Time { return now }),
+		WithAutoDestroyOrphans(true),
+		WithQuarantine(quarantine))
+
+	ctx := context.Background()
+	r.RunReconciliation(ctx)
+
+	// Later passes leave it to its scheduled destroy
+	now = now.Add(5 * time.Minute)
+	r.RunReconciliation(ctx)
+
+	quarantined, err := quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, "stuck-instance", quarantined[0].InstanceID)
+	assert.Equal(t, "gone-session", quarantined[0].SessionID)
+	assert.Equal(t, models.QuarantineOrphan, quarantined[0].Source)
+	assert.Equal(t, "instance is locked", quarantined[0].Reason)
+	assert.Equal(t, 1, quarantined[0].Failures)
+	assert.Equal(t, now.Add(10*time.Minute), quarantined[0].NextAttemptAt)
+	assert.Len(t, prov.getDestroyCalls(), 1)
+
+	// A stopped instance whose destroy failed stays quarantined
+	prov.mu.Lock()
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "stopped"}, nil
+	}
+	prov.mu.Unlock()
+	now = now.Add(10 * time.Minute)
+	r.RunReconciliation(ctx)
+	quarantined, err = quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, 2, quarantined[0].Failures)
+
+	// A destroy that succeeds and leaves it unlisted takes it out of
+	// quarantine
+	prov.mu.Lock()
+	prov.destroyErr = nil
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		return nil, provider.ErrInstanceNotFound
+	}
+	prov.mu.Unlock()
+	now = now.Add(30 * time.Minute)
+	r.RunReconciliation(ctx)
+
+	quarantined, err = quarantine.ListQuarantined(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, quarantined)
+	assert.Len(t, prov.getDestroyCalls(), 3)
+}
@@ -2,6 +2,7 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 	"time"
@@ -38,6 +39,32 @@ type ReconcileEventHandler interface {
 	OnReconcileError(providerName string, err error)
 }
 
+// FailoverHandler takes over running sessions whose instance was preempted or
+// terminated outside our control
+type FailoverHandler interface {
+	// FailoverSession marks the session preempted and re-provisions it if the
+	// consumer enabled auto-retry. It reports false if the session was no
+	// longer running.
+	FailoverSession(ctx context.Context, sessionID, reason string) (bool, error)
+}
+
+// LostSessionHandler fails running sessions whose instance stopped or
+// vanished outside our control and was not failed over
+type LostSessionHandler interface {
+	// FailLostSession destroys what is left of the session's instance, marks
+	// the session failed, records its final cost and notifies the consumer.
+	// It reports false if the session was no longer running.
+	FailLostSession(ctx context.Context, sessionID string, category models.FailureCategory, detail, reason string) (bool, error)
+}
+
+// VerificationResumer continues the verification of sessions that a restart
+// interrupted while their instance was booting or being verified
+type VerificationResumer interface {
+	// ResumeVerification resumes the session's verification in the
+	// background. It reports false if the session cannot be resumed.
+	ResumeVerification(session *models.Session) bool
+}
+
 // noopReconcileHandler is a default handler that does nothing
 type noopReconcileHandler struct{}
 
@@ -50,9 +77,21 @@ type Reconciler struct {
 	store        ReconcileStore
 	providers    ProviderRegistry
 	handler      ReconcileEventHandler
+	failover     FailoverHandler
+	lost         LostSessionHandler
+	resumer      VerificationResumer
 	logger       *slog.Logger
 	deploymentID string
 
+	// Instance claims are optional; without them nothing can be adopted
+	// or released
+	sessions SessionCreator
+	releases InstanceReleaseStore
+
+	// Optional; without it failed destroys are only logged
+	quarantine    QuarantineStore
+	destroyAlerts DestroyFailureNotifier
+
 	// Configuration
 	reconcileInterval  time.Duration
 	autoDestroyOrphans bool
@@ -78,6 +117,9 @@ type ReconcileMetrics struct {
 	OrphansDestroyed   int64
 	GhostsFound        int64
 	GhostsFixed        int64
+	ZombiesFound       int64
+	ZombiesFixed       int64
+	Preemptions        int64
 	Errors             int64
 }
 
@@ -119,6 +161,33 @@ func WithReconcileEventHandler(handler ReconcileEventHandler) ReconcilerOption {
 	}
 }
 
+// WithFailoverHandler enables failover of sessions whose instance was
+// preempted or terminated outside our control. Without it, such sessions are
+// failed through the LostSessionHandler, or only marked stopped as ghosts.
+func WithFailoverHandler(h FailoverHandler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.failover = h
+	}
+}
+
+// WithLostSessionHandler fails running sessions whose instance stopped, or
+// vanished without being failed over, through h so the consumer is notified.
+// Without it, such sessions are only marked failed or stopped.
+func WithLostSessionHandler(h LostSessionHandler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.lost = h
+	}
+}
+
+// WithVerificationResumer hands provisioning sessions found at startup to v
+// while their instance still exists. Without it, or for sessions v cannot
+// resume, they are marked running if the instance runs and stopped otherwise.
+func WithVerificationResumer(v VerificationResumer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.resumer = v
+	}
+}
+
 // WithReconcileTimeFunc sets a custom time function (for testing)
 func WithReconcileTimeFunc(fn func() time.Time) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -239,6 +308,9 @@ func (r *Reconciler) RunReconciliation(ctx context.Context) {
 			r.handler.OnReconcileError(providerName, err)
 		}
 	}
+
+	r.retryDestroys(ctx)
+	r.updateQuarantineGauge(ctx)
 }
 
 // reconcileProvider reconciles state for a single provider
@@ -260,6 +332,11 @@ func (r *Reconciler) reconcileProvider(ctx context.Context, providerName string)
 		return err
 	}
 
+	released, err := r.releasedInstances(ctx, providerName)
+	if err != nil {
+		return err
+	}
+
 	// Build maps for comparison
 	localMap := make(map[string]*models.Session)
 	for _, s := range localSessions {
@@ -270,6 +347,16 @@ func (r *Reconciler) reconcileProvider(ctx context.Context, providerName string)
 
 	providerMap := make(map[string]provider.ProviderInstance)
 	for _, p := range providerInstances {
+		// An instance one of our sessions runs on is ours whatever its tag,
+		// since some providers cannot tag instances with a deployment
+		if _, ok := localMap[p.ID]; ok {
+			providerMap[p.ID] = p
+			continue
+		}
+		// Released instances belong to someone else
+		if released[p.ID] {
+			continue
+		}
 		// Only include instances from our deployment.
 		// If deploymentID is empty, we include ALL instances which may lead to
 		// false positive orphan detection for instances from other deployments.
@@ -284,17 +371,33 @@ func (r *Reconciler) reconcileProvider(ctx context.Context, providerName string)
 		providerMap[p.ID] = p
 	}
 
-	// Find orphans: exist on provider but not in DB
+	// Find orphans: exist on provider but not in DB. Quarantined orphans
+	// are left to their scheduled destroy.
+	quarantined := r.quarantinedInstances(ctx, providerName)
 	for providerID, instance := range providerMap {
-		if _, exists := localMap[providerID]; !exists {
+		if _, exists := localMap[providerID]; !exists && !quarantined[providerID] {
 			r.handleOrphan(ctx, prov, providerID, instance)
 		}
 	}
 
 	// Find ghosts: exist in DB but not on provider
 	for providerID, session := range localMap {
-		if _, exists := providerMap[providerID]; !exists {
+		// A rebooting instance may be listed stopped or missing until
+		// it is back
+		if session.Rebooting(r.now()) {
+			continue
+		}
+		instance, exists := providerMap[providerID]
+		if !exists {
 			r.handleGhost(ctx, session)
+			continue
+		}
+		if instance.Preempted && session.Status == models.StatusRunning && r.failover != nil {
+			r.handlePreempted(ctx, session, "instance preempted by provider")
+			continue
+		}
+		if instance.IsStopped() && session.Status == models.StatusRunning {
+			r.handleZombie(ctx, prov, session, instance)
 		}
 	}
 
@@ -332,7 +435,9 @@ func (r *Reconciler) handleOrphan(ctx context.Context, prov provider.Provider, p
 				slog.String("provider_id", providerID),
 				slog.String("error", err.Error()))
 			metrics.RecordDestroyFailure()
+			r.quarantineInstance(ctx, prov.Name(), providerID, instance.Tags.ShopperSessionID, models.QuarantineOrphan, err)
 		} else {
+			r.releaseQuarantine(ctx, prov.Name(), providerID)
 			r.logger.Info("orphan destroyed",
 				slog.String("provider_id", providerID))
 
@@ -386,8 +491,28 @@ func (r *Reconciler) handleGhost(ctx context.Context, session *models.Session) {
 
 	r.handler.OnGhostFound(session)
 
+	// A running instance that vanished was terminated outside our control;
+	// fail it over like a preemption when we can
+	if session.Status == models.StatusRunning && r.failover != nil {
+		if r.handlePreempted(ctx, session, "instance terminated outside our control") {
+			r.metrics.mu.Lock()
+			r.metrics.GhostsFixed++
+			r.metrics.mu.Unlock()
+		}
+		return
+	}
+
+	if session.Status == models.StatusRunning && r.lost != nil {
+		if r.failLost(ctx, session, models.FailureInstanceVanished, "",
+			"Instance not found on provider during reconciliation") {
+			r.metrics.mu.Lock()
+			r.metrics.GhostsFixed++
+			r.metrics.mu.Unlock()
+		}
+		return
+	}
+
 	// Update session to stopped
-	oldStatus := session.Status
 	session.Status = models.StatusStopped
 	session.Error = "Instance not found on provider during reconciliation"
 	session.StoppedAt = r.now()
@@ -403,14 +528,129 @@ func (r *Reconciler) handleGhost(ctx context.Context, session *models.Session) {
 			"consumer_id", session.ConsumerID,
 			"provider", session.Provider)
 
-		metrics.UpdateSessionStatus(session.Provider, string(oldStatus), string(models.StatusStopped))
-
 		r.metrics.mu.Lock()
 		r.metrics.GhostsFixed++
 		r.metrics.mu.Unlock()
 	}
 }
 
+// handleZombie handles a zombie session: running in the DB while the provider
+// lists its instance as stopped. The instance's status is checked again first,
+// so one stale listing cannot fail a healthy session.
+func (r *Reconciler) handleZombie(ctx context.Context, prov provider.Provider, session *models.Session, instance provider.ProviderInstance) {
+	const zombieGracePeriod = 10 * time.Minute
+	if r.now().Sub(session.CreatedAt) < zombieGracePeriod {
+		return
+	}
+
+	detail := instance.Status
+	status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+	switch {
+	case errors.Is(err, provider.ErrInstanceNotFound):
+	case err != nil:
+		r.logger.Warn("failed to confirm zombie session",
+			slog.String("session_id", session.ID),
+			slog.String("provider_id", session.ProviderID),
+			slog.String("error", err.Error()))
+		return
+	case status.Running:
+		return
+	default:
+		detail = status.Status
+	}
+
+	r.logger.Warn("ZOMBIE DETECTED: Session running in DB but instance stopped on provider",
+		slog.String("session_id", session.ID),
+		slog.String("provider_id", session.ProviderID),
+		slog.String("instance_status", detail))
+
+	r.metrics.mu.Lock()
+	r.metrics.ZombiesFound++
+	r.metrics.mu.Unlock()
+
+	logging.Audit(ctx, "zombie_detected",
+		"session_id", session.ID,
+		"consumer_id", session.ConsumerID,
+		"provider", session.Provider,
+		"provider_id", session.ProviderID,
+		"instance_status", detail)
+	metrics.RecordZombieDetected()
+
+	reason := "Instance stopped on provider: " + detail
+	fixed := false
+	if r.lost != nil {
+		fixed = r.failLost(ctx, session, models.FailureInstanceStopped, detail, reason)
+	} else {
+		session.Status = models.StatusFailed
+		session.FailureCategory = models.FailureInstanceStopped
+		session.FailureDetail = detail
+		session.Error = reason
+		session.StoppedAt = r.now()
+		if err := r.store.Update(ctx, session); err != nil {
+			r.logger.Error("failed to update zombie session",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+		} else {
+			fixed = true
+		}
+	}
+	if fixed {
+		logging.Audit(ctx, "zombie_fixed",
+			"session_id", session.ID,
+			"consumer_id", session.ConsumerID,
+			"provider", session.Provider)
+		r.metrics.mu.Lock()
+		r.metrics.ZombiesFixed++
+		r.metrics.mu.Unlock()
+	}
+}
+
+// failLost hands a lost running session to the lost session handler and
+// reports whether it was failed
+func (r *Reconciler) failLost(ctx context.Context, session *models.Session, category models.FailureCategory, detail, reason string) bool {
+	failed, err := r.lost.FailLostSession(ctx, session.ID, category, detail, reason)
+	if err != nil {
+		r.logger.Error("failed to fail lost session",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()))
+		return false
+	}
+	return failed
+}
+
+// handlePreempted hands a lost running session to the failover handler and
+// reports whether it was failed over
+func (r *Reconciler) handlePreempted(ctx context.Context, session *models.Session, reason string) bool {
+	failedOver, err := r.failover.FailoverSession(ctx, session.ID, reason)
+	if err != nil {
+		r.logger.Error("failed to fail over preempted session",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()))
+		return false
+	}
+	if !failedOver {
+		return false
+	}
+
+	r.logger.Warn("PREEMPTION DETECTED: session failed over",
+		slog.String("session_id", session.ID),
+		slog.String("provider", session.Provider),
+		slog.String("provider_id", session.ProviderID),
+		slog.String("reason", reason))
+
+	logging.Audit(ctx, "session_preempted",
+		"session_id", session.ID,
+		"consumer_id", session.ConsumerID,
+		"provider", session.Provider,
+		"provider_id", session.ProviderID,
+		"reason", reason)
+
+	r.metrics.mu.Lock()
+	r.metrics.Preemptions++
+	r.metrics.mu.Unlock()
+	return true
+}
+
 // RecoverStuckSessions recovers sessions stuck in transitional states
 func (r *Reconciler) RecoverStuckSessions(ctx context.Context) error {
 	r.logger.Info("recovering stuck sessions")
@@ -441,6 +681,7 @@ func (r *Reconciler) RecoverStuckSessions(ctx context.Context) error {
 			// Never got a provider ID - mark as failed
 			session.Status = models.StatusFailed
 			session.Error = "Provisioning failed - no provider instance ID"
+			session.FailureCategory = models.FailureProviderError
 			session.StoppedAt = r.now()
 			r.store.Update(ctx, session)
 			continue
@@ -452,6 +693,7 @@ func (r *Reconciler) RecoverStuckSessions(ctx context.Context) error {
 			if session.Status == models.StatusProvisioning {
 				session.Status = models.StatusFailed
 				session.Error = "Instance not found after restart"
+				session.FailureCategory = models.FailureInstanceVanished
 			} else {
 				session.Status = models.StatusStopped
 			}
@@ -460,10 +702,18 @@ func (r *Reconciler) RecoverStuckSessions(ctx context.Context) error {
 			continue
 		}
 
+		if session.Status == models.StatusProvisioning && r.resumer != nil && r.resumer.ResumeVerification(session) {
+			r.logger.Info("resumed verification of stuck session",
+				slog.String("session_id", session.ID),
+				slog.String("phase", string(session.ProvisionPhase)))
+			continue
+		}
+
 		if status.Running {
 			if session.Status == models.StatusProvisioning {
 				// Instance is running - update to running with SSH info
 				session.Status = models.StatusRunning
+				session.ProvisionPhase = models.PhaseRunning
 				if status.SSHHost != "" {
 					session.SSHHost = status.SSHHost
 				}
@@ -526,7 +776,9 @@ func (r *Reconciler) RecoverStuckSessions(ctx context.Context) error {
 					r.logger.Error("failed to destroy leaked instance",
 						slog.String("session_id", session.ID),
 						slog.String("error", err.Error()))
+					r.quarantineInstance(ctx, session.Provider, session.ProviderID, session.ID, models.QuarantineDestroy, err)
 				} else {
+					r.releaseQuarantine(ctx, session.Provider, session.ProviderID)
 					session.ProviderID = ""
 					session.Status = models.StatusStopped
 					session.StoppedAt = r.now()
@@ -553,6 +805,9 @@ func (r *Reconciler) GetMetrics() ReconcileMetrics {
 		OrphansDestroyed:   r.metrics.OrphansDestroyed,
 		GhostsFound:        r.metrics.GhostsFound,
 		GhostsFixed:        r.metrics.GhostsFixed,
+		ZombiesFound:       r.metrics.ZombiesFound,
+		ZombiesFixed:       r.metrics.ZombiesFixed,
+		Preemptions:        r.metrics.Preemptions,
 		Errors:             r.metrics.Errors,
 	}
 }
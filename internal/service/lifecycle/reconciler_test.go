@@ -21,6 +21,7 @@ type mockReconcileProvider struct {
 
 	mu           sync.Mutex
 	destroyCalls []string
+	destroyErr   error
 	statusFn     func(id string) (*provider.InstanceStatus, error)
 }
 
@@ -55,7 +56,7 @@ func (m *mockReconcileProvider) DestroyInstance(ctx context.Context, instanceID
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.destroyCalls = append(m.destroyCalls, instanceID)
-	return nil
+	return m.destroyErr
 }
 
 func (m *mockReconcileProvider) GetInstanceStatus(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
@@ -326,6 +327,293 @@ func TestReconciler_DetectsGhost(t *testing.T) {
 	assert.Equal(t, int64(1), metrics.GhostsFixed)
 }
 
+// mockFailoverHandler records failovers and marks sessions preempted
+type mockFailoverHandler struct {
+	store   *mockReconcileStore
+	reasons map[string]string
+}
+
+func (m *mockFailoverHandler) FailoverSession(ctx context.Context, sessionID, reason string) (bool, error) {
+	session, err := m.store.Get(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	if session.Status != models.StatusRunning {
+		return false, nil
+	}
+	session.Status = models.StatusPreempted
+	session.Error = reason
+	if m.reasons == nil {
+		m.reasons = make(map[string]string)
+	}
+	m.reasons[sessionID] = reason
+	return true, m.store.Update(ctx, session)
+}
+
+func TestReconciler_GhostFailsOver(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	store.add(&models.Session{
+		ID:         "lost-session",
+		Provider:   "vastai",
+		ProviderID: "missing-instance",
+		Status:     models.StatusRunning,
+	})
+	store.add(&models.Session{
+		ID:         "lost-provisioning",
+		Provider:   "vastai",
+		ProviderID: "missing-instance-2",
+		Status:     models.StatusProvisioning,
+	})
+	registry.Add(newMockReconcileProvider("vastai"))
+
+	failover := &mockFailoverHandler{store: store}
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithFailoverHandler(failover))
+
+	ctx := context.Background()
+	r.RunReconciliation(ctx)
+
+	// Running sessions are failed over, provisioning ones still just stop
+	updated, _ := store.Get(ctx, "lost-session")
+	assert.Equal(t, models.StatusPreempted, updated.Status)
+	assert.Equal(t, "instance terminated outside our control", failover.reasons["lost-session"])
+
+	provisioning, _ := store.Get(ctx, "lost-provisioning")
+	assert.Equal(t, models.StatusStopped, provisioning.Status)
+
+	metrics := r.GetMetrics()
+	assert.Equal(t, int64(2), metrics.GhostsFound)
+	assert.Equal(t, int64(2), metrics.GhostsFixed)
+	assert.Equal(t, int64(1), metrics.Preemptions)
+}
+
+func TestReconciler_DetectsPreemptedInstance(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	store.add(&models.Session{
+		ID:         "outbid-session",
+		Provider:   "vastai",
+		ProviderID: "outbid-instance",
+		Status:     models.StatusRunning,
+	})
+	store.add(&models.Session{
+		ID:         "healthy-session",
+		Provider:   "vastai",
+		ProviderID: "healthy-instance",
+		Status:     models.StatusRunning,
+	})
+
+	prov := newMockReconcileProvider("vastai")
+	prov.instances = []provider.ProviderInstance{
+		{ID: "outbid-instance", Status: "stopped", Preempted: true},
+		{ID: "healthy-instance", Status: "running"},
+	}
+	registry.Add(prov)
+
+	failover := &mockFailoverHandler{store: store}
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithFailoverHandler(failover))
+
+	ctx := context.Background()
+	r.RunReconciliation(ctx)
+
+	outbid, _ := store.Get(ctx, "outbid-session")
+	assert.Equal(t, models.StatusPreempted, outbid.Status)
+	assert.Equal(t, "instance preempted by provider", outbid.Error)
+
+	healthy, _ := store.Get(ctx, "healthy-session")
+	assert.Equal(t, models.StatusRunning, healthy.Status)
+
+	assert.Equal(t, int64(1), r.GetMetrics().Preemptions)
+	assert.Empty(t, prov.getDestroyCalls())
+
+	// Already handled sessions are not failed over twice
+	r.RunReconciliation(ctx)
+	assert.Equal(t, int64(1), r.GetMetrics().Preemptions)
+}
+
+// mockLostSessionHandler records lost sessions and marks them failed
+type mockLostSessionHandler struct {
+	store   *mockReconcileStore
+	reasons map[string]string
+}
+
+func (m *mockLostSessionHandler) FailLostSession(ctx context.Context, sessionID string, category models.FailureCategory, detail, reason string) (bool, error) {
+	session, err := m.store.Get(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	if session.Status != models.StatusRunning {
+		return false, nil
+	}
+	session.Status = models.StatusFailed
+	session.FailureCategory = category
+	session.FailureDetail = detail
+	session.Error = reason
+	if m.reasons == nil {
+		m.reasons = make(map[string]string)
+	}
+	m.reasons[sessionID] = reason
+	return true, m.store.Update(ctx, session)
+}
+
+func TestReconciler_DetectsZombie(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	store.add(&models.Session{
+		ID:         "zombie-session",
+		Provider:   "vastai",
+		ProviderID: "exited-instance",
+		Status:     models.StatusRunning,
+	})
+	store.add(&models.Session{
+		ID:         "restarted-session",
+		Provider:   "vastai",
+		ProviderID: "restarted-instance",
+		Status:     models.StatusRunning,
+	})
+	store.add(&models.Session{
+		ID:         "young-session",
+		Provider:   "vastai",
+		ProviderID: "young-instance",
+		Status:     models.StatusRunning,
+		CreatedAt:  time.Now(),
+	})
+
+	prov := newMockReconcileProvider("vastai")
+	prov.instances = []provider.ProviderInstance{
+		{ID: "exited-instance", Status: "exited"},
+		{ID: "restarted-instance", Status: "stopped"},
+		{ID: "young-instance", Status: "stopped"},
+	}
+	// The listing of restarted-instance is stale; it runs again
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		if id == "restarted-instance" {
+			return &provider.InstanceStatus{Status: "running", Running: true}, nil
+		}
+		return &provider.InstanceStatus{Status: "exited"}, nil
+	}
+	registry.Add(prov)
+
+	r := NewReconciler(store, registry, WithReconcileLogger(newTestLogger()))
+
+	ctx := context.Background()
+	r.RunReconciliation(ctx)
+
+	zombie, _ := store.Get(ctx, "zombie-session")
+	assert.Equal(t, models.StatusFailed, zombie.Status)
+	assert.Equal(t, models.FailureInstanceStopped, zombie.FailureCategory)
+	assert.Equal(t, "Instance stopped on provider: exited", zombie.Error)
+	assert.False(t, zombie.StoppedAt.IsZero())
+
+	restarted, _ := store.Get(ctx, "restarted-session")
+	assert.Equal(t, models.StatusRunning, restarted.Status)
+	young, _ := store.Get(ctx, "young-session")
+	assert.Equal(t, models.StatusRunning, young.Status)
+
+	metrics := r.GetMetrics()
+	assert.Equal(t, int64(1), metrics.ZombiesFound)
+	assert.Equal(t, int64(1), metrics.ZombiesFixed)
+
+	// Failed sessions are no longer active, so they are not found again
+	r.RunReconciliation(ctx)
+	assert.Equal(t, int64(1), r.GetMetrics().ZombiesFound)
+}
+
+func TestReconciler_SkipsRebootingSessions(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+	// Marked rebooting by whichever server is rebooting it
+	store.add(&models.Session{
+		ID:             "rebooting-session",
+		Provider:       "vastai",
+		ProviderID:     "stopped-instance",
+		Status:         models.StatusRunning,
+		RebootingUntil: time.Now().Add(time.Hour),
+	})
+	// A reboot that gave up long ago no longer shields its session
+	store.add(&models.Session{
+		ID:             "stale-reboot-session",
+		Provider:       "vastai",
+		ProviderID:     "exited-instance",
+		Status:         models.StatusRunning,
+		RebootingUntil: time.Now().Add(-time.Hour),
+	})
+
+	prov := newMockReconcileProvider("vastai")
+	prov.instances = []provider.ProviderInstance{
+		{ID: "stopped-instance", Status: "stopped"},
+		{ID: "exited-instance", Status: "exited"},
+	}
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "stopped"}, nil
+	}
+	registry.Add(prov)
+
+	r := NewReconciler(store, registry, WithReconcileLogger(newTestLogger()))
+	ctx := context.Background()
+	r.RunReconciliation(ctx)
+
+	session, _ := store.Get(ctx, "rebooting-session")
+	assert.Equal(t, models.StatusRunning, session.Status)
+	assert.Equal(t, int64(1), r.GetMetrics().ZombiesFound)
+	stale, _ := store.Get(ctx, "stale-reboot-session")
+	assert.NotEqual(t, models.StatusRunning, stale.Status)
+}
+
+func TestReconciler_LostSessionHandler(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	store.add(&models.Session{
+		ID:         "zombie-session",
+		Provider:   "vastai",
+		ProviderID: "exited-instance",
+		Status:     models.StatusRunning,
+	})
+	store.add(&models.Session{
+		ID:         "ghost-session",
+		Provider:   "vastai",
+		ProviderID: "missing-instance",
+		Status:     models.StatusRunning,
+	})
+
+	prov := newMockReconcileProvider("vastai")
+	prov.instances = []provider.ProviderInstance{{ID: "exited-instance", Status: "exited"}}
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "exited"}, nil
+	}
+	registry.Add(prov)
+
+	lost := &mockLostSessionHandler{store: store}
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithLostSessionHandler(lost))
+
+	ctx := context.Background()
+	r.RunReconciliation(ctx)
+
+	zombie, _ := store.Get(ctx, "zombie-session")
+	assert.Equal(t, models.StatusFailed, zombie.Status)
+	assert.Equal(t, "exited", zombie.FailureDetail)
+	assert.Equal(t, "Instance stopped on provider: exited", lost.reasons["zombie-session"])
+
+	// Without failover, vanished instances are failed through the handler too
+	ghost, _ := store.Get(ctx, "ghost-session")
+	assert.Equal(t, models.StatusFailed, ghost.Status)
+	assert.Equal(t, models.FailureInstanceVanished, ghost.FailureCategory)
+
+	metrics := r.GetMetrics()
+	assert.Equal(t, int64(1), metrics.ZombiesFixed)
+	assert.Equal(t, int64(1), metrics.GhostsFixed)
+}
+
 func TestReconciler_MatchingStateNoAction(t *testing.T) {
 	store := newMockReconcileStore()
 	registry := newMockProviderRegistry()
@@ -541,6 +829,62 @@ func TestReconciler_RecoverStuckProvisioningWithInstance(t *testing.T) {
 	assert.Equal(t, models.StatusRunning, updated.Status)
 }
 
+type mockVerificationResumer struct {
+	accept  bool
+	resumed []string
+}
+
+func (m *mockVerificationResumer) ResumeVerification(session *models.Session) bool {
+	m.resumed = append(m.resumed, session.ID)
+	return m.accept
+}
+
+func TestReconciler_RecoverStuckProvisioningResumesVerification(t *testing.T) {
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	// Session interrupted while its instance was booting
+	store.add(&models.Session{
+		ID:             "booting-session",
+		Provider:       "vastai",
+		ProviderID:     "booting-instance",
+		Status:         models.StatusProvisioning,
+		ProvisionPhase: models.PhaseBooting,
+	})
+	// Session that never got an instance is not offered for resumption
+	store.add(&models.Session{
+		ID:       "no-instance",
+		Provider: "vastai",
+		Status:   models.StatusProvisioning,
+	})
+
+	prov := newMockReconcileProvider("vastai")
+	prov.statusFn = func(id string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "loading"}, nil
+	}
+	registry.Add(prov)
+
+	resumer := &mockVerificationResumer{accept: true}
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithVerificationResumer(resumer))
+
+	ctx := context.Background()
+	require.NoError(t, r.RecoverStuckSessions(ctx))
+
+	assert.Equal(t, []string{"booting-session"}, resumer.resumed)
+	updated, _ := store.Get(ctx, "booting-session")
+	assert.Equal(t, models.StatusProvisioning, updated.Status, "a resumed session is left to its verification")
+	updated, _ = store.Get(ctx, "no-instance")
+	assert.Equal(t, models.StatusFailed, updated.Status)
+
+	// Sessions the resumer refuses are settled as before
+	resumer.accept = false
+	require.NoError(t, r.RecoverStuckSessions(ctx))
+	updated, _ = store.Get(ctx, "booting-session")
+	assert.Equal(t, models.StatusStopped, updated.Status)
+}
+
 func TestReconciler_RecoverStuckStopping(t *testing.T) {
 	store := newMockReconcileStore()
 	registry := newMockProviderRegistry()
@@ -763,3 +1107,80 @@ func TestReconciler_MultipleProviders(t *testing.T) {
 	assert.Equal(t, int64(0), metrics.OrphansFound)
 	assert.Equal(t, int64(0), metrics.GhostsFound)
 }
+
+func TestReconciler_AuditTerminations(t *testing.T) {
+	now := time.Now()
+	store := newMockReconcileStore()
+	registry := newMockProviderRegistry()
+
+	store.add(&models.Session{
+		ID: "sess-ok", Provider: "bluelobster", ProviderID: "inst-ok",
+		Status: models.StatusRunning, ExpiresAt: now.Add(time.Hour),
+	})
+	store.add(&models.Session{
+		ID: "sess-silent", Provider: "bluelobster", ProviderID: "inst-silent",
+		Status: models.StatusRunning, ExpiresAt: now.Add(-time.Minute),
+		Health: models.SessionHealth{Status: models.HealthDegraded},
+	})
+	store.add(&models.Session{
+		ID: "sess-stopped", Provider: "bluelobster", ProviderID: "inst-zombie",
+		Status: models.StatusStopped, ExpiresAt: now.Add(time.Hour),
+	})
+
+	tags := func(sessionID string, expiresAt time.Time) models.InstanceTags {
+		return models.InstanceTags{
+			ShopperSessionID:    sessionID,
+			ShopperDeploymentID: "test-deploy",
+			ShopperExpiresAt:    expiresAt,
+		}
+	}
+	bl := newMockReconcileProvider("bluelobster")
+	bl.instances = []provider.ProviderInstance{
+		{ID: "inst-ok", Status: "running", Tags: tags("sess-ok", now.Add(time.Hour))},
+		{ID: "inst-silent", Status: "running", Tags: tags("sess-silent", now.Add(time.Hour))},
+		{ID: "inst-zombie", Status: "running", Tags: tags("sess-stopped", now.Add(-time.Hour))},
+		{ID: "inst-other", Status: "running", Tags: models.InstanceTags{ShopperDeploymentID: "other-deploy"}},
+	}
+	registry.Add(bl)
+
+	// Vast.ai labels carry only the session ID
+	vast := newMockReconcileProvider("vastai")
+	vast.instances = []provider.ProviderInstance{
+		{ID: "inst-unlabelled", Status: "running", Tags: models.InstanceTags{ShopperSessionID: "sess-gone"}},
+	}
+	registry.Add(vast)
+
+	broken := newMockReconcileProvider("tensordock")
+	broken.err = errors.New("provider unavailable")
+	registry.Add(broken)
+
+	r := NewReconciler(store, registry,
+		WithReconcileLogger(newTestLogger()),
+		WithDeploymentID("test-deploy"),
+		WithReconcileTimeFunc(func() time.Time { return now }))
+
+	audit := r.AuditTerminations(context.Background())
+
+	issues := make(map[string][]models.TerminationIssue)
+	for _, inst := range audit.Instances {
+		issues[inst.InstanceID] = inst.Issues
+	}
+	assert.Equal(t, map[string][]models.TerminationIssue{
+		"inst-ok":         {},
+		"inst-silent":     {models.TerminationIssueNoHeartbeat, models.TerminationIssueExpired},
+		"inst-zombie":     {models.TerminationIssueNoSession, models.TerminationIssueExpired},
+		"inst-unlabelled": {models.TerminationIssueNoSession, models.TerminationIssueNoExpiryLabel},
+	}, issues)
+	assert.Equal(t, 3, audit.Flagged)
+
+	require.Len(t, audit.Providers, 3)
+	assert.Equal(t, models.TerminationAuditProvider{
+		Provider: "bluelobster", Instances: 3, OtherDeployments: 1, Flagged: 2,
+	}, audit.Providers[0])
+	assert.Equal(t, "tensordock", audit.Providers[1].Provider)
+	assert.Equal(t, "provider unavailable", audit.Providers[1].Error)
+
+	// The audit only reports
+	assert.Empty(t, bl.getDestroyCalls())
+	assert.Empty(t, vast.getDestroyCalls())
+}
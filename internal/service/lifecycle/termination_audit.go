@@ -0,0 +1,116 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// AuditTerminations lists every instance each provider reports and flags
+// those that may never be terminated: instances with no active session, no
+// expiry tag or an expiry in the past, and instances whose session has
+// stopped receiving heartbeats. Unlike RunReconciliation it changes nothing.
+// A provider that cannot be listed is reported with its error rather than
+// failing the audit.
+func (r *Reconciler) AuditTerminations(ctx context.Context) *models.TerminationAudit {
+	now := r.now()
+	audit := &models.TerminationAudit{
+		GeneratedAt: now,
+		Providers:   []models.TerminationAuditProvider{},
+		Instances:   []models.TerminationAuditInstance{},
+	}
+
+	providerNames := r.providers.List()
+	sort.Strings(providerNames)
+	for _, providerName := range providerNames {
+		summary := models.TerminationAuditProvider{Provider: providerName}
+		instances, err := r.auditProvider(ctx, providerName, &summary)
+		if err != nil {
+			r.logger.Error("termination audit failed for provider",
+				slog.String("provider", providerName),
+				slog.String("error", err.Error()))
+			summary.Error = err.Error()
+		}
+		audit.Providers = append(audit.Providers, summary)
+		audit.Instances = append(audit.Instances, instances...)
+		audit.Flagged += summary.Flagged
+	}
+
+	return audit
+}
+
+// auditProvider audits one provider's instances, filling in its summary
+func (r *Reconciler) auditProvider(ctx context.Context, providerName string, summary *models.TerminationAuditProvider) ([]models.TerminationAuditInstance, error) {
+	prov, err := r.providers.Get(providerName)
+	if err != nil {
+		return nil, err
+	}
+	providerInstances, err := prov.ListAllInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := r.store.GetActiveSessionsByProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	byProviderID := make(map[string]*models.Session, len(sessions))
+	for _, s := range sessions {
+		if s.ProviderID != "" {
+			byProviderID[s.ProviderID] = s
+		}
+	}
+
+	now := r.now()
+	var result []models.TerminationAuditInstance
+	for _, instance := range providerInstances {
+		// Some providers cannot tag instances with a deployment, so untagged
+		// instances are audited rather than assumed to be someone else's
+		tag := instance.Tags.ShopperDeploymentID
+		if r.deploymentID != "" && tag != "" && !instance.IsOurs(r.deploymentID) {
+			summary.OtherDeployments++
+			continue
+		}
+
+		entry := models.TerminationAuditInstance{
+			Provider:     providerName,
+			InstanceID:   instance.ID,
+			Name:         instance.Name,
+			Status:       instance.Status,
+			PricePerHour: instance.PricePerHour,
+			Issues:       []models.TerminationIssue{},
+		}
+
+		session, ok := byProviderID[instance.ID]
+		if ok {
+			entry.SessionID = session.ID
+			entry.SessionStatus = session.Status
+			if session.Health.Status == models.HealthDegraded {
+				entry.Issues = append(entry.Issues, models.TerminationIssueNoHeartbeat)
+			}
+		} else {
+			entry.SessionID = instance.Tags.ShopperSessionID
+			entry.Issues = append(entry.Issues, models.TerminationIssueNoSession)
+		}
+
+		expired := ok && !session.ExpiresAt.IsZero() && now.After(session.ExpiresAt)
+		if expiresAt := instance.Tags.ShopperExpiresAt; expiresAt.IsZero() {
+			entry.Issues = append(entry.Issues, models.TerminationIssueNoExpiryLabel)
+		} else {
+			entry.ExpiresAt = &expiresAt
+			expired = expired || now.After(expiresAt)
+		}
+		if expired {
+			entry.Issues = append(entry.Issues, models.TerminationIssueExpired)
+		}
+
+		summary.Instances++
+		if len(entry.Issues) > 0 {
+			summary.Flagged++
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
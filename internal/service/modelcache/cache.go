@@ -0,0 +1,171 @@
+// Package modelcache serves model weights from the operator's S3-compatible
+// bucket, so instances download them from storage the operator controls
+// instead of from Hugging Face on every launch. A model's files are stored
+// under <prefix>/<model_id>/, laid out as in its Hugging Face repository,
+// e.g. as uploaded with "aws s3 sync" from a "huggingface-cli download".
+package modelcache
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/objectstore"
+)
+
+const (
+	// DefaultPrefix is the key prefix models are stored under
+	DefaultPrefix = "models"
+
+	// DefaultDir is the directory models are downloaded into on instances,
+	// one subdirectory per model ID
+	DefaultDir = "/models"
+
+	// DefaultURLExpiry is how long the download URLs in a pull script stay
+	// valid
+	DefaultURLExpiry = 6 * time.Hour
+
+	// maxFiles caps the files of one model, as every file's URL is part of
+	// the pull script
+	maxFiles = 2000
+)
+
+// modelIDRe matches Hugging Face repository IDs, which are safe as both
+// object keys and paths
+var modelIDRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(/[A-Za-z0-9][A-Za-z0-9._-]*)?$`)
+
+// Bucket is the part of the object store client the cache uses
+type Bucket interface {
+	List(ctx context.Context, prefix string) ([]objectstore.Object, error)
+	PresignGet(key string, expires time.Duration) string
+}
+
+// Cache generates scripts that download cached models onto instances
+type Cache struct {
+	bucket    Bucket
+	prefix    string
+	dir       string
+	urlExpiry time.Duration
+}
+
+// Option configures a Cache
+type Option func(*Cache)
+
+// WithPrefix sets the key prefix models are stored under
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = strings.Trim(prefix, "/")
+	}
+}
+
+// WithDir sets the directory models are downloaded into on instances
+func WithDir(dir string) Option {
+	return func(c *Cache) {
+		if dir != "" {
+			c.dir = dir
+		}
+	}
+}
+
+// WithURLExpiry sets how long the download URLs in a pull script stay valid
+func WithURLExpiry(d time.Duration) Option {
+	return func(c *Cache) {
+		if d > 0 {
+			c.urlExpiry = d
+		}
+	}
+}
+
+// New creates a cache of the models in bucket
+func New(bucket Bucket, opts ...Option) *Cache {
+	c := &Cache{
+		bucket:    bucket,
+		prefix:    DefaultPrefix,
+		dir:       DefaultDir,
+		urlExpiry: DefaultURLExpiry,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Pull is the download of one model's weights onto an instance
+type Pull struct {
+	Dir    string // Directory the weights are downloaded into
+	Files  int
+	Size   int64
+	Script string // Shell script that downloads the weights
+}
+
+// Pull lists modelID's files and returns a script that downloads them
+// through presigned URLs, so the instance needs no credentials. Files
+// already downloaded with the right size are skipped, so a pull can be
+// rerun. It returns ErrNotCached if the bucket has no files for the model;
+// IDs that are not Hugging Face repository IDs, such as Ollama tags, are
+// never cached.
+func (c *Cache) Pull(ctx context.Context, modelID string) (*Pull, error) {
+	if !modelIDRe.MatchString(modelID) {
+		return nil, ErrNotCached
+	}
+	base := modelID + "/"
+	if c.prefix != "" {
+		base = c.prefix + "/" + base
+	}
+	objects, err := c.bucket.List(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+
+	pull := &Pull{Dir: path.Join(c.dir, modelID)}
+	var script strings.Builder
+	fmt.Fprintf(&script, `set -e
+D=%s
+mkdir -p "$D"
+fetch() {
+	[ "$(stat -c %%s "$D/$1" 2>/dev/null)" = "$2" ] && return 0
+	curl -sSfL --retry 3 --create-dirs -o "$D/$1" "$3"
+}
+`, quoteShell(pull.Dir))
+	for _, o := range objects {
+		name := strings.TrimPrefix(o.Key, base)
+		if !safeName(name) {
+			continue
+		}
+		pull.Files++
+		pull.Size += o.Size
+		fmt.Fprintf(&script, "fetch %s %d %s\n", quoteShell(name), o.Size, quoteShell(c.bucket.PresignGet(o.Key, c.urlExpiry)))
+	}
+	if pull.Files == 0 {
+		return nil, ErrNotCached
+	}
+	if pull.Files > maxFiles {
+		return nil, fmt.Errorf("model %s has %d files in the cache, more than the %d that can be pulled", modelID, pull.Files, maxFiles)
+	}
+	fmt.Fprintf(&script, "echo \"%d files, %d bytes in $D\"\n", pull.Files, pull.Size)
+	pull.Script = script.String()
+	return pull, nil
+}
+
+// safeName reports whether a file name relative to the model's directory
+// stays inside it. Directory markers, whose names end in a slash, are not
+// files.
+func safeName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteShell single-quotes s for a POSIX shell
+func quoteShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,81 @@
+package modelcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/objectstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBucket struct {
+	objects  []objectstore.Object
+	prefixes []string
+	expiry   time.Duration
+}
+
+func (b *fakeBucket) List(ctx context.Context, prefix string) ([]objectstore.Object, error) {
+	b.prefixes = append(b.prefixes, prefix)
+	var objects []objectstore.Object
+	for _, o := range b.objects {
+		if strings.HasPrefix(o.Key, prefix) {
+			objects = append(objects, o)
+		}
+	}
+	return objects, nil
+}
+
+func (b *fakeBucket) PresignGet(key string, expires time.Duration) string {
+	b.expiry = expires
+	return "https://bucket.example.com/" + key + "?X-Amz-Signature=sig"
+}
+
+func TestCache_Pull(t *testing.T) {
+	bucket := &fakeBucket{objects: []objectstore.Object{
+		{Key: "cache/org/model/", Size: 0},
+		{Key: "cache/org/model/config.json", Size: 700},
+		{Key: "cache/org/model/model-00001-of-00002.safetensors", Size: 4 << 30},
+		{Key: "cache/org/model/model-00002-of-00002.safetensors", Size: 1 << 30},
+		{Key: "cache/org/model/../escape", Size: 1},
+		{Key: "cache/org/model-large/config.json", Size: 800},
+	}}
+	cache := New(bucket, WithPrefix("/cache/"), WithDir("/data/models"), WithURLExpiry(time.Hour))
+
+	pull, err := cache.Pull(context.Background(), "org/model")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache/org/model/"}, bucket.prefixes)
+	assert.Equal(t, time.Hour, bucket.expiry)
+	assert.Equal(t, "/data/models/org/model", pull.Dir)
+	assert.Equal(t, 3, pull.Files)
+	assert.Equal(t, int64(5<<30+700), pull.Size)
+
+	assert.Contains(t, pull.Script, "D='/data/models/org/model'\n")
+	assert.Contains(t, pull.Script,
+		"fetch 'model-00001-of-00002.safetensors' 4294967296 'https://bucket.example.com/cache/org/model/model-00001-of-00002.safetensors?X-Amz-Signature=sig'\n")
+	assert.Contains(t, pull.Script, "fetch 'config.json' 700 ")
+	assert.NotContains(t, pull.Script, "escape")
+	assert.NotContains(t, pull.Script, "model-large")
+}
+
+func TestCache_Pull_NotCached(t *testing.T) {
+	cache := New(&fakeBucket{objects: []objectstore.Object{{Key: "models/org/model/", Size: 0}}})
+
+	_, err := cache.Pull(context.Background(), "org/model")
+	assert.ErrorIs(t, err, ErrNotCached)
+	_, err = cache.Pull(context.Background(), "org/other")
+	assert.ErrorIs(t, err, ErrNotCached)
+}
+
+func TestCache_Pull_NotARepositoryID(t *testing.T) {
+	bucket := &fakeBucket{}
+	cache := New(bucket)
+
+	for _, id := range []string{"", "../models", "org/../model", "org/model/extra", "llama3:8b", "/org/model"} {
+		_, err := cache.Pull(context.Background(), id)
+		assert.ErrorIs(t, err, ErrNotCached, id)
+	}
+	assert.Empty(t, bucket.prefixes)
+}
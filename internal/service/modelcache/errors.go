@@ -0,0 +1,6 @@
+package modelcache
+
+import "errors"
+
+// ErrNotCached is returned when a model has no weights in the cache
+var ErrNotCached = errors.New("model is not in the cache")
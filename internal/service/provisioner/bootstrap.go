@@ -0,0 +1,158 @@
+package provisioner
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// maxBootstrapOutputBytes caps the script output kept in the session
+// history; the tail is kept, as that is where failures show
+const maxBootstrapOutputBytes = 16 * 1024
+
+// sshBootstrapRunner runs bootstrap scripts with the SSH executor
+type sshBootstrapRunner struct {
+	timeout time.Duration
+}
+
+func (r *sshBootstrapRunner) RunScript(ctx context.Context, host string, port int, user, privateKey, script string) (string, error) {
+	// Scripts with their own deadline, such as model pulls, may outlast
+	// bootstrap scripts
+	timeout := r.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	executor := sshverify.NewExecutor(
+		sshverify.WithExecutorConnectTimeout(30*time.Second),
+		sshverify.WithExecutorCommandTimeout(timeout),
+	)
+	conn, err := executor.Connect(ctx, host, port, user, privateKey)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	// The script is passed encoded so no quoting can break out of the command
+	cmd := fmt.Sprintf("echo %s | base64 -d | bash", base64.StdEncoding.EncodeToString([]byte(script)))
+	stdout, stderr, err := executor.RunCommand(ctx, conn, cmd)
+	output := stdout
+	if stderr != "" {
+		if output != "" {
+			output += "\n"
+		}
+		output += stderr
+	}
+	return output, err
+}
+
+// runBootstrap runs the session's bootstrap script and records the result in
+// the session history. A failing script does not fail the session, which is
+// already running; the consumer decides what to do from the recorded output.
+// modelDir, if set, is where the session's model weights were pulled to and
+// is exported to the script as MODEL_DIR. The session's HuggingFace token is
+// exported too, and redacted from the recorded output.
+func (s *Service) runBootstrap(session *models.Session, privateKey, modelDir string, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.bootstrapTimeout)
+	defer cancel()
+
+	script := session.BootstrapScript
+	if modelDir != "" {
+		script = "export MODEL_DIR=" + shellQuote(modelDir) + "\n" + script
+	}
+	if session.HFToken != "" {
+		var exports strings.Builder
+		for _, name := range hfTokenEnvNames {
+			exports.WriteString("export " + name + "=" + shellQuote(session.HFToken) + "\n")
+		}
+		script = exports.String() + script
+	}
+	start := time.Now()
+	output, err := s.bootstrapRunner.RunScript(s.pinHostKey(ctx, session),
+		session.SSHHost, session.SSHPort, session.SSHUser, privateKey, script)
+
+	event := &models.SessionEvent{
+		SessionID:  session.ID,
+		Type:       models.SessionEventBootstrap,
+		FromStatus: session.Status,
+		ToStatus:   session.Status,
+		Output:     truncateOutput(redactSecret(output, session.HFToken), maxBootstrapOutputBytes),
+	}
+	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+		}
+		event.Reason = "bootstrap script failed: " + redactSecret(err.Error(), session.HFToken)
+		logger.Warn("bootstrap script failed",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(start)))
+	} else {
+		logger.Info("bootstrap script completed", slog.Duration("duration", time.Since(start)))
+	}
+	s.recordEvent(event, logger)
+}
+
+// skipBootstrap records that a session's bootstrap script could not be run
+func (s *Service) skipBootstrap(session *models.Session, reason string, logger *slog.Logger) {
+	logger.Warn("bootstrap script skipped", slog.String("reason", reason))
+	s.recordEvent(&models.SessionEvent{
+		SessionID:  session.ID,
+		Type:       models.SessionEventBootstrap,
+		FromStatus: session.Status,
+		ToStatus:   session.Status,
+		Reason:     "bootstrap script skipped: " + reason,
+	}, logger)
+}
+
+// recordEvent appends a non-status event to the session history, if an
+// event recorder is configured
+func (s *Service) recordEvent(event *models.SessionEvent, logger *slog.Logger) {
+	if s.events == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.events.Record(ctx, event); err != nil {
+		logger.Error("failed to record session event",
+			slog.String("type", string(event.Type)),
+			slog.String("error", err.Error()))
+	}
+}
+
+// hfTokenEnvNames are the environment variables a HuggingFace token is read
+// from: HF_TOKEN by current HuggingFace libraries, HUGGING_FACE_HUB_TOKEN by
+// older ones and TGI
+var hfTokenEnvNames = []string{"HF_TOKEN", "HUGGING_FACE_HUB_TOKEN"}
+
+// hfTokenEnv returns the environment that passes token to a container
+func hfTokenEnv(token string) map[string]string {
+	env := make(map[string]string, len(hfTokenEnvNames))
+	for _, name := range hfTokenEnvNames {
+		env[name] = token
+	}
+	return env
+}
+
+// redactSecret replaces every occurrence of secret in s with REDACTED, so
+// step output that echoes a secret can be recorded
+func redactSecret(s, secret string) string {
+	if secret == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, secret, "REDACTED")
+}
+
+// truncateOutput keeps the last max bytes of output, marking the cut
+func truncateOutput(output string, max int) string {
+	if len(output) <= max {
+		return output
+	}
+	return "[truncated]\n" + strings.ToValidUTF8(output[len(output)-max:], "")
+}
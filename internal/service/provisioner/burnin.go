@@ -0,0 +1,83 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// sshBurnInRunner runs GPU burn-ins with the SSH executor
+type sshBurnInRunner struct{}
+
+func (sshBurnInRunner) RunBurnIn(ctx context.Context, host string, port int, user, privateKey string, duration time.Duration) (*sshverify.GPUBurnIn, error) {
+	executor := sshverify.NewExecutor(
+		sshverify.WithExecutorConnectTimeout(30*time.Second),
+		sshverify.WithExecutorCommandTimeout(duration+time.Minute),
+	)
+	conn, err := executor.Connect(ctx, host, port, user, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return executor.RunGPUBurnIn(ctx, conn, duration)
+}
+
+// burnIn load-tests the session's GPUs before it is handed over, recording
+// the result in the session history. If the GPUs are unhealthy the session
+// is failed and its offer marked failed, so auto-retry moves on to another
+// offer, and burnIn returns false. A burn-in that cannot run does not hold
+// the session back.
+func (s *Service) burnIn(session *models.Session, privateKey string, logger *slog.Logger) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), s.burnInDuration+2*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	result, err := s.burnInRunner.RunBurnIn(s.pinHostKey(ctx, session),
+		session.SSHHost, session.SSHPort, session.SSHUser, privateKey, s.burnInDuration)
+
+	event := &models.SessionEvent{
+		SessionID:  session.ID,
+		Type:       models.SessionEventBurnIn,
+		FromStatus: session.Status,
+		ToStatus:   session.Status,
+	}
+	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+		}
+		event.Reason = "GPU burn-in could not run: " + err.Error()
+		logger.Warn("GPU burn-in could not run", slog.String("error", err.Error()))
+		s.recordEvent(event, logger)
+		return true
+	}
+
+	event.Output = truncateOutput(result.RawOutput, maxBootstrapOutputBytes)
+	problems := result.Problems(session.GPUCount)
+	if len(problems) == 0 {
+		event.Reason = "GPU burn-in passed: " + result.String()
+		logger.Info("GPU burn-in passed",
+			slog.String("result", result.String()),
+			slog.Duration("duration", time.Since(start)))
+		s.recordEvent(event, logger)
+		return true
+	}
+
+	reason := "GPU burn-in failed: " + strings.Join(problems, "; ")
+	event.Reason = reason
+	s.recordEvent(event, logger)
+	logger.Error("GPU burn-in failed, destroying instance",
+		slog.String("problems", strings.Join(problems, "; ")),
+		slog.String("offer_id", session.OfferID))
+
+	s.failSession(ctx, session, models.FailureGPUUnhealthy, problems[0], reason)
+	metrics.RecordSessionDestroyed(session.Provider, "gpu_unhealthy")
+	s.recordOfferFailure(session, models.FailureGPUUnhealthy, reason)
+	return false
+}
@@ -0,0 +1,94 @@
+package provisioner
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCreateQueueTimeout is how long a session waits for a free
+// instance creation slot on its provider before giving up
+const DefaultCreateQueueTimeout = 30 * time.Second
+
+// createLimiter caps how many CreateInstance calls run at once against each
+// provider. Requests over the limit queue until a slot frees up or the
+// queue timeout passes.
+type createLimiter struct {
+	defaultLimit int            // 0 = unlimited
+	limits       map[string]int // Provider name -> limit, overriding defaultLimit
+	timeout      time.Duration
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newCreateLimiter(defaultLimit int, limits map[string]int, timeout time.Duration) *createLimiter {
+	if timeout <= 0 {
+		timeout = DefaultCreateQueueTimeout
+	}
+	return &createLimiter{
+		defaultLimit: defaultLimit,
+		limits:       limits,
+		timeout:      timeout,
+		slots:        make(map[string]chan struct{}),
+	}
+}
+
+// limit returns the concurrency limit for the provider, 0 for unlimited
+func (l *createLimiter) limit(providerName string) int {
+	if n, ok := l.limits[providerName]; ok {
+		return n
+	}
+	return l.defaultLimit
+}
+
+// semaphore returns the provider's slot channel, or nil when it is unlimited
+func (l *createLimiter) semaphore(providerName string) chan struct{} {
+	n := l.limit(providerName)
+	if n <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.slots[providerName]
+	if !ok {
+		sem = make(chan struct{}, n)
+		l.slots[providerName] = sem
+	}
+	return sem
+}
+
+// acquire waits for a creation slot on the provider. The returned release
+// func frees the slot and is safe to call more than once.
+func (l *createLimiter) acquire(ctx context.Context, providerName string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	sem := l.semaphore(providerName)
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	release := sync.OnceFunc(func() { <-sem })
+
+	// Take a free slot without starting the timer
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, &ProviderBusyError{Provider: providerName, Limit: cap(sem), Waited: time.Since(start)}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
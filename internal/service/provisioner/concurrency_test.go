@@ -0,0 +1,152 @@
+package provisioner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateLimiter_Acquire(t *testing.T) {
+	l := newCreateLimiter(2, map[string]int{"tensordock": 1, "static": 0}, 20*time.Millisecond)
+	ctx := context.Background()
+
+	release, err := l.acquire(ctx, "tensordock")
+	require.NoError(t, err)
+
+	_, err = l.acquire(ctx, "tensordock")
+	var busyErr *ProviderBusyError
+	require.ErrorAs(t, err, &busyErr)
+	assert.Equal(t, "tensordock", busyErr.Provider)
+	assert.Equal(t, 1, busyErr.Limit)
+	assert.GreaterOrEqual(t, busyErr.Waited, 20*time.Millisecond)
+
+	// Releasing twice frees a single slot
+	release()
+	release()
+	release, err = l.acquire(ctx, "tensordock")
+	require.NoError(t, err)
+	defer release()
+
+	// Other providers have slots of their own
+	for i := 0; i < 2; i++ {
+		_, err = l.acquire(ctx, "vastai")
+		require.NoError(t, err)
+	}
+	_, err = l.acquire(ctx, "vastai")
+	assert.ErrorAs(t, err, &busyErr)
+
+	// A zero limit means unlimited
+	for i := 0; i < 10; i++ {
+		_, err = l.acquire(ctx, "static")
+		require.NoError(t, err)
+	}
+}
+
+func TestCreateLimiter_QueuesUntilReleased(t *testing.T) {
+	l := newCreateLimiter(1, nil, time.Minute)
+	ctx := context.Background()
+
+	release, err := l.acquire(ctx, "vastai")
+	require.NoError(t, err)
+
+	acquired := make(chan error, 1)
+	go func() {
+		next, err := l.acquire(ctx, "vastai")
+		if err == nil {
+			next()
+		}
+		acquired <- err
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second acquire should wait for a free slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case err := <-acquired:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("second acquire did not get the released slot")
+	}
+}
+
+func TestCreateLimiter_ContextCancelled(t *testing.T) {
+	l := newCreateLimiter(1, nil, time.Minute)
+
+	release, err := l.acquire(context.Background(), "vastai")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = l.acquire(ctx, "vastai")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestService_CreateSession_ProviderBusy(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	prov.createInstanceFn = func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+		started <- struct{}{}
+		<-unblock
+		return &provider.InstanceInfo{ProviderInstanceID: "mock-instance-" + req.OfferID, SSHHost: "192.168.1.100", SSHPort: 22}, nil
+	}
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+
+	svc := New(store, registry,
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithCreateConcurrency(0, map[string]int{"vastai": 1}, 50*time.Millisecond))
+
+	ctx := context.Background()
+	newRequest := func(offerID string) (models.CreateSessionRequest, *models.GPUOffer) {
+		return models.CreateSessionRequest{
+			ConsumerID:     "consumer-001",
+			OfferID:        offerID,
+			WorkloadType:   models.WorkloadLLM,
+			ReservationHrs: 1,
+		}, &models.GPUOffer{
+			ID:           offerID,
+			Provider:     "vastai",
+			PricePerHour: 0.50,
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		req, offer := newRequest("offer-1")
+		_, err := svc.CreateSession(ctx, req, offer)
+		done <- err
+	}()
+	<-started
+
+	// The only slot is taken, so the second session times out in the queue
+	// without leaving a session record behind
+	req, offer := newRequest("offer-2")
+	_, err := svc.CreateSession(ctx, req, offer)
+	var busyErr *ProviderBusyError
+	require.ErrorAs(t, err, &busyErr)
+	assert.Equal(t, "vastai", busyErr.Provider)
+	store.mu.Lock()
+	assert.Len(t, store.sessions, 1)
+	store.mu.Unlock()
+
+	close(unblock)
+	require.NoError(t, <-done)
+
+	// The slot is free again once the first creation returns
+	go func() { <-started }()
+	_, err = svc.CreateSession(ctx, req, offer)
+	require.NoError(t, err)
+	assert.Equal(t, 2, prov.createCalls)
+}
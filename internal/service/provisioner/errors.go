@@ -3,6 +3,7 @@ package provisioner
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
@@ -40,6 +41,92 @@ func (e *SessionNotFoundError) Error() string {
 	return fmt.Sprintf("session not found: %s", e.ID)
 }
 
+// SessionNotRunningError indicates an operation requires a running session
+type SessionNotRunningError struct {
+	ID     string
+	Status models.SessionStatus
+}
+
+func (e *SessionNotRunningError) Error() string {
+	return fmt.Sprintf("session %s is not running (status: %s)", e.ID, e.Status)
+}
+
+// SSHKeyRotationNotSupportedError indicates the provider cannot attach new SSH keys to a running instance
+type SSHKeyRotationNotSupportedError struct {
+	Provider string
+}
+
+func (e *SSHKeyRotationNotSupportedError) Error() string {
+	return fmt.Sprintf("provider %s does not support regenerating SSH access", e.Provider)
+}
+
+// PauseNotSupportedError indicates a session cannot be paused, because its
+// provider cannot stop instances or because it is interruptible, and a
+// stopped interruptible instance would lose its bid
+type PauseNotSupportedError struct {
+	Provider      string
+	Interruptible bool
+}
+
+func (e *PauseNotSupportedError) Error() string {
+	if e.Interruptible {
+		return "interruptible sessions cannot be paused"
+	}
+	return fmt.Sprintf("provider %s does not support pausing sessions", e.Provider)
+}
+
+// SessionNotPausedError indicates an operation requires a paused session
+type SessionNotPausedError struct {
+	ID     string
+	Status models.SessionStatus
+}
+
+func (e *SessionNotPausedError) Error() string {
+	return fmt.Sprintf("session %s is not paused (status: %s)", e.ID, e.Status)
+}
+
+// ResumeTimeoutError indicates a paused session's instance did not start in
+// time. The instance is stopped again and the session stays paused.
+type ResumeTimeoutError struct {
+	SessionID string
+	Timeout   time.Duration
+}
+
+func (e *ResumeTimeoutError) Error() string {
+	return fmt.Sprintf("instance of session %s did not start within %s; the session is still paused",
+		e.SessionID, e.Timeout)
+}
+
+// RebootNotSupportedError indicates a session cannot be rebooted, because
+// its provider can neither reboot nor stop and start instances, and no SSH
+// key was given to reboot it over SSH
+type RebootNotSupportedError struct {
+	Provider string
+}
+
+func (e *RebootNotSupportedError) Error() string {
+	return fmt.Sprintf("provider %s cannot reboot or stop instances; give the session's SSH private key to reboot it over SSH", e.Provider)
+}
+
+// RebootTimeoutError indicates a rebooted instance did not answer SSH again in time
+type RebootTimeoutError struct {
+	SessionID string
+	Timeout   time.Duration
+}
+
+func (e *RebootTimeoutError) Error() string {
+	return fmt.Sprintf("instance of session %s did not answer SSH within %s of rebooting", e.SessionID, e.Timeout)
+}
+
+// LogsNotSupportedError indicates the provider cannot return instance logs
+type LogsNotSupportedError struct {
+	Provider string
+}
+
+func (e *LogsNotSupportedError) Error() string {
+	return fmt.Sprintf("provider %s does not support instance logs", e.Provider)
+}
+
 // DuplicateSessionError indicates a consumer already has an active session for the given offer
 type DuplicateSessionError struct {
 	ConsumerID string
@@ -88,9 +175,145 @@ func (e *InsufficientDiskError) Error() string {
 	return msg
 }
 
+// InsufficientVRAMError indicates the offer's GPUs cannot hold the requested model
+type InsufficientVRAMError struct {
+	RequiredGB  int
+	AvailableGB int
+	VRAMPerGPU  int
+	GPUCount    int
+	Estimation  *VRAMEstimation
+}
+
+func (e *InsufficientVRAMError) Error() string {
+	msg := fmt.Sprintf("insufficient GPU memory: model needs ~%d GB VRAM, offer has %d GB (%d x %d GB)",
+		e.RequiredGB, e.AvailableGB, e.GPUCount, e.VRAMPerGPU)
+	if e.Estimation != nil {
+		msg += " — breakdown: " + e.Estimation.FormatBreakdown()
+	}
+	return msg
+}
+
 // IsRetryableWithDifferentOffer returns true if the error indicates we should
 // automatically try a different offer (e.g., stale inventory errors)
 func IsRetryableWithDifferentOffer(err error) bool {
 	var staleErr *StaleInventoryError
 	return errors.As(err, &staleErr)
 }
+
+// InsufficientBalanceError indicates the provider account balance cannot cover
+// the full reservation of the requested session
+type InsufficientBalanceError struct {
+	Provider      string
+	Balance       float64
+	ProjectedCost float64
+	Currency      string
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("%s account balance %.2f %s is below projected session cost %.2f",
+		e.Provider, e.Balance, e.Currency, e.ProjectedCost)
+}
+
+// ProviderBusyError indicates the provider already had as many instance
+// creations in flight as allowed, and none finished within the queue timeout
+type ProviderBusyError struct {
+	Provider string
+	Limit    int
+	Waited   time.Duration
+}
+
+func (e *ProviderBusyError) Error() string {
+	return fmt.Sprintf("provider %s is busy: %d instance creations already in progress, waited %s for a slot",
+		e.Provider, e.Limit, e.Waited.Round(time.Millisecond))
+}
+
+// ProviderDisabledError indicates provisioning on the provider is switched
+// off by a feature flag for this consumer
+type ProviderDisabledError struct {
+	Provider string
+}
+
+func (e *ProviderDisabledError) Error() string {
+	return fmt.Sprintf("provisioning on provider %s is disabled", e.Provider)
+}
+
+// CloudInitNotSupportedError indicates custom cloud-init requested on a
+// provider that cannot apply it
+type CloudInitNotSupportedError struct {
+	Provider string
+}
+
+func (e *CloudInitNotSupportedError) Error() string {
+	return fmt.Sprintf("provider %s does not support custom cloud_init", e.Provider)
+}
+
+// RegionNotAllowedError indicates an offer outside the regions the
+// consumer's sessions may run in
+type RegionNotAllowedError struct {
+	ConsumerID string
+	OfferID    string
+	Location   string
+}
+
+func (e *RegionNotAllowedError) Error() string {
+	return fmt.Sprintf("offer %s (%s) is outside the regions allowed for consumer %s", e.OfferID, e.Location, e.ConsumerID)
+}
+
+// InvalidBidError indicates a bid price the offer cannot accept
+type InvalidBidError struct {
+	OfferID  string
+	BidPrice float64
+	MinBid   float64
+}
+
+func (e *InvalidBidError) Error() string {
+	if e.MinBid == 0 {
+		return fmt.Sprintf("offer %s is not interruptible and does not accept a bid", e.OfferID)
+	}
+	return fmt.Sprintf("bid %.4f/hr for offer %s is below the minimum bid %.4f/hr",
+		e.BidPrice, e.OfferID, e.MinBid)
+}
+
+// InvalidReservationError indicates a session request that cannot be queued
+type InvalidReservationError struct {
+	Reason string
+}
+
+func (e *InvalidReservationError) Error() string {
+	return "invalid queued request: " + e.Reason
+}
+
+// ReservationNotWaitingError indicates a queued request that is no longer
+// waiting, so it cannot be cancelled
+type ReservationNotWaitingError struct {
+	ID     string
+	Status models.ReservationStatus
+}
+
+func (e *ReservationNotWaitingError) Error() string {
+	return fmt.Sprintf("queued request %s is no longer waiting (status: %s)", e.ID, e.Status)
+}
+
+// NoMatchingOfferError indicates no available offer satisfies an
+// auto-selection spec
+type NoMatchingOfferError struct {
+	Filter        models.OfferFilter
+	MinConfidence float64
+}
+
+func (e *NoMatchingOfferError) Error() string {
+	return fmt.Sprintf("no available offer matches the spec with availability confidence >= %.2f", e.MinConfidence)
+}
+
+// InvalidPhaseTransitionError indicates a session cannot move to a provisioning
+// phase, because it is terminal or has already reached or passed that phase
+type InvalidPhaseTransitionError struct {
+	SessionID string
+	Status    models.SessionStatus
+	From      models.ProvisionPhase
+	To        models.ProvisionPhase
+}
+
+func (e *InvalidPhaseTransitionError) Error() string {
+	return fmt.Sprintf("session %s cannot move from phase %q to %q (status: %s)", e.SessionID, e.From, e.To, e.Status)
+}
@@ -0,0 +1,135 @@
+package provisioner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Failure taxonomy: every way provisioning can fail maps to a
+// models.FailureCategory stored on the session, with the finer-grained
+// classification (SSH error type, instance status) kept as its detail. The
+// same category is recorded against the offer by the failure tracker.
+
+// classifyCreateError categorizes a failed provider create call. Offers that
+// were already gone are stale inventory; anything else is a provider error.
+func classifyCreateError(err error) models.FailureCategory {
+	if provider.ShouldRetryWithDifferentOffer(err) {
+		return models.FailureStaleInventory
+	}
+	return models.FailureProviderError
+}
+
+// isPermanentSSHError reports whether an SSH error type from
+// classifySSHError will not resolve by waiting
+func isPermanentSSHError(errorType string) bool {
+	return errorType == "auth_failed" || errorType == "key_parse_failed" || errorType == "host_key_changed"
+}
+
+// classifyInstanceStopReason provides a more descriptive failure reason based on
+// the instance status and error message from the provider. Errors found in
+// the instance's kernel log, if any, are given as the cause; otherwise the
+// likely cause is guessed from the status.
+func classifyInstanceStopReason(status, errorMsg, kernelDiagnosis string) string {
+	base := fmt.Sprintf("instance stopped unexpectedly: %s", status)
+
+	if errorMsg != "" {
+		base += fmt.Sprintf(" (%s)", errorMsg)
+	}
+
+	if kernelDiagnosis != "" {
+		return withKernelCause(base, kernelDiagnosis)
+	}
+
+	// Add likely cause hints based on known patterns
+	lower := strings.ToLower(status + " " + errorMsg)
+	switch {
+	case strings.Contains(lower, "loading"):
+		base += " — likely cause: image pull failed, disk full, or driver incompatibility"
+	case strings.Contains(lower, "error"):
+		base += " — likely cause: runtime crash, OOM, or configuration error"
+	case strings.Contains(lower, "exited"):
+		base += " — likely cause: entrypoint failed or OOM kill"
+	}
+
+	return base
+}
+
+// withKernelCause appends errors found in the instance's kernel log to a
+// failure reason as its cause
+func withKernelCause(reason, kernelDiagnosis string) string {
+	return reason + " — cause (kernel log): " + kernelDiagnosis
+}
+
+// classifySSHError categorizes SSH connection errors for logging
+// Returns: error_type (connection_refused, timeout, auth_failed, etc.)
+func classifySSHError(err error) string {
+	if err == nil {
+		return "none"
+	}
+
+	// Host key differs from the one pinned on first use
+	var mismatch *sshverify.HostKeyMismatchError
+	if errors.As(err, &mismatch) {
+		return "host_key_changed"
+	}
+
+	errStr := err.Error()
+
+	// Connection refused - instance not accepting connections yet
+	if strings.Contains(errStr, "connection refused") {
+		return "connection_refused"
+	}
+
+	// Connection timeout - network issues or firewall
+	if strings.Contains(errStr, "i/o timeout") ||
+		strings.Contains(errStr, "connection timed out") ||
+		strings.Contains(errStr, "deadline exceeded") {
+		return "timeout"
+	}
+
+	// No route to host - network unreachable
+	if strings.Contains(errStr, "no route to host") ||
+		strings.Contains(errStr, "network is unreachable") {
+		return "network_unreachable"
+	}
+
+	// DNS resolution failure
+	if strings.Contains(errStr, "no such host") ||
+		strings.Contains(errStr, "lookup") {
+		return "dns_failed"
+	}
+
+	// SSH handshake failures (auth)
+	if strings.Contains(errStr, "SSH handshake failed") ||
+		strings.Contains(errStr, "unable to authenticate") ||
+		strings.Contains(errStr, "permission denied") {
+		return "auth_failed"
+	}
+
+	// Private key issues
+	if strings.Contains(errStr, "failed to parse private key") {
+		return "key_parse_failed"
+	}
+
+	// Session/command failures
+	if strings.Contains(errStr, "failed to create session") ||
+		strings.Contains(errStr, "verify command failed") {
+		return "command_failed"
+	}
+
+	if strings.Contains(errStr, "unexpected packet") {
+		return "connection_closed"
+	}
+
+	// EOF typically means the connection was closed
+	if strings.Contains(errStr, "EOF") {
+		return "connection_closed"
+	}
+
+	return "unknown"
+}
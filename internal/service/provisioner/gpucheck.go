@@ -0,0 +1,145 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// FeatureGPUVerification gates failing SSH-mode sessions whose GPUs do not
+// match their offer, evaluated per session ID
+const FeatureGPUVerification = "provisioner.gpu_verification"
+
+// vramTolerance is the share of the advertised VRAM an instance may fall
+// short by. nvidia-smi reports MiB, less what the driver reserves, and
+// providers round.
+const vramTolerance = 0.1
+
+// GPULister is implemented by SSH verifiers that can list an instance's
+// GPUs. Sessions verified without one skip the GPU check.
+type GPULister interface {
+	// ListGPUs runs nvidia-smi over SSH and returns every GPU it lists
+	ListGPUs(ctx context.Context, host string, port int, user, privateKey string) ([]*sshverify.GPUStatus, error)
+}
+
+// checkGPUs compares the GPUs nvidia-smi reports with those the session's
+// offer advertised. If they do not match, the host misreports its hardware:
+// the session is failed and its offer marked failed, so auto-retry moves on
+// to another offer, and checkGPUs returns false. A check that cannot run
+// does not hold the session back.
+func (s *Service) checkGPUs(session *models.Session, privateKey string, logger *slog.Logger) bool {
+	lister, ok := s.sshVerifier.(GPULister)
+	if !ok {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if s.features != nil && !s.features.Enabled(ctx, FeatureGPUVerification, session.ID, true) {
+		return true
+	}
+
+	gpus, err := lister.ListGPUs(s.pinHostKey(ctx, session), session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+		}
+		logger.Warn("GPU check could not run", slog.String("error", err.Error()))
+		return true
+	}
+
+	problems := gpuMismatch(session, gpus)
+	if len(problems) == 0 {
+		logger.Info("GPU check passed",
+			slog.Int("gpus", len(gpus)),
+			slog.String("gpu_name", gpus[0].Name),
+			slog.Int64("memory_total_mb", gpus[0].MemoryTotalMB))
+		return true
+	}
+
+	reason := "GPUs do not match the offer: " + strings.Join(problems, "; ")
+	logger.Error("GPUs do not match the offer, destroying instance",
+		slog.String("problems", strings.Join(problems, "; ")),
+		slog.String("offer_id", session.OfferID))
+
+	s.failSession(ctx, session, models.FailureGPUMismatch, problems[0], reason)
+	metrics.RecordSessionDestroyed(session.Provider, "gpu_mismatch")
+	s.recordOfferFailure(session, models.FailureGPUMismatch, reason)
+	return false
+}
+
+// gpuMismatch compares the GPUs nvidia-smi reports on an instance with those
+// its offer advertised, returning each way they differ. More GPUs or more
+// VRAM than advertised are not mismatches.
+func gpuMismatch(session *models.Session, gpus []*sshverify.GPUStatus) []string {
+	var problems []string
+	if len(gpus) < session.GPUCount {
+		problems = append(problems, fmt.Sprintf("%d of %d GPUs visible", len(gpus), session.GPUCount))
+	}
+	for i, g := range gpus {
+		if !gpuModelMatches(session.GPUType, g.Name) {
+			problems = append(problems, fmt.Sprintf("GPU %d is %s, advertised %s", i, g.Name, session.GPUType))
+		}
+		if session.VRAM > 0 && g.MemoryTotalMB > 0 && float64(g.MemoryTotalMB) < float64(session.VRAM)*1024*(1-vramTolerance) {
+			problems = append(problems, fmt.Sprintf("GPU %d has %.0f GB VRAM, advertised %d GB",
+				i, math.Round(float64(g.MemoryTotalMB)/1024), session.VRAM))
+		}
+	}
+	return problems
+}
+
+// gpuMemorySize matches a memory size in a GPU name, e.g. "80gb"
+var gpuMemorySize = regexp.MustCompile(`^\d+gb$`)
+
+// gpuNameFields splits a lowercased GPU name into words
+func gpuNameFields(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_' || r == '/'
+	})
+}
+
+// gpuModelMatches reports whether a GPU name nvidia-smi reports is the model
+// an offer advertised. Providers and nvidia-smi name GPUs differently ("RTX
+// 4090" and "NVIDIA GeForce RTX 4090", "A100 SXM4" and "NVIDIA
+// A100-SXM4-80GB"), so only the words that identify the model must match:
+// those with a digit, and the Ti and Super variants. Memory sizes are left
+// to the VRAM check. A word may match adjacent reported words run together,
+// as in "RTX 6000Ada" and "NVIDIA RTX 6000 Ada Generation".
+func gpuModelMatches(advertised, reported string) bool {
+	runs := gpuNameRuns(gpuNameFields(reported))
+	for _, want := range gpuNameFields(advertised) {
+		if gpuMemorySize.MatchString(want) {
+			continue
+		}
+		if !strings.ContainsAny(want, "0123456789") && want != "ti" && want != "super" {
+			continue
+		}
+		if !slices.Contains(runs, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// gpuNameRuns returns each word of a GPU name, and each run of up to three
+// adjacent words joined together
+func gpuNameRuns(fields []string) []string {
+	var runs []string
+	for i := range fields {
+		for j := i + 1; j <= min(i+3, len(fields)); j++ {
+			runs = append(runs, strings.Join(fields[i:j], ""))
+		}
+	}
+	return runs
+}
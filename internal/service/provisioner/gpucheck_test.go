@@ -0,0 +1,90 @@
+package provisioner
+
+import (
+	"testing"
+
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGPUModelMatches(t *testing.T) {
+	tests := []struct {
+		advertised string
+		reported   string
+		want       bool
+	}{
+		{"RTX 4090", "NVIDIA GeForce RTX 4090", true},
+		{"RTX4090", "NVIDIA GeForce RTX 4090", true},
+		{"A100 SXM4", "NVIDIA A100-SXM4-80GB", true},
+		{"A100 80GB", "NVIDIA A100-SXM4-80GB", true},
+		{"H100 SXM", "NVIDIA H100 80GB HBM3", true},
+		{"RTX A6000", "NVIDIA RTX A6000", true},
+		{"RTX 6000Ada", "NVIDIA RTX 6000 Ada Generation", true},
+		{"Tesla V100", "Tesla V100-SXM2-16GB", true},
+		{"RTX 4070 Ti", "NVIDIA GeForce RTX 4070 Ti SUPER", true},
+		{"", "NVIDIA GeForce RTX 3090", true},
+		{"RTX 4090", "NVIDIA GeForce RTX 3090", false},
+		{"A10", "NVIDIA A100-PCIE-40GB", false},
+		{"A100 SXM4", "NVIDIA A100 80GB PCIe", false},
+		{"RTX 4060 Ti", "NVIDIA GeForce RTX 4060", false},
+		{"L40S", "NVIDIA L40", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.advertised+" vs "+tt.reported, func(t *testing.T) {
+			assert.Equal(t, tt.want, gpuModelMatches(tt.advertised, tt.reported))
+		})
+	}
+}
+
+func TestGPUMismatch(t *testing.T) {
+	rtx4090 := &sshverify.GPUStatus{Name: "NVIDIA GeForce RTX 4090", MemoryTotalMB: 24564}
+	rtx3090 := &sshverify.GPUStatus{Name: "NVIDIA GeForce RTX 3090", MemoryTotalMB: 24576}
+	a100 := &sshverify.GPUStatus{Name: "NVIDIA A100-SXM4-40GB", MemoryTotalMB: 40960}
+
+	tests := []struct {
+		name    string
+		session *models.Session
+		gpus    []*sshverify.GPUStatus
+		want    []string
+	}{
+		{
+			name:    "matches",
+			session: &models.Session{GPUType: "RTX 4090", GPUCount: 2, VRAM: 24},
+			gpus:    []*sshverify.GPUStatus{rtx4090, rtx4090},
+		},
+		{
+			name:    "more than advertised",
+			session: &models.Session{GPUType: "RTX 4090", GPUCount: 1, VRAM: 20},
+			gpus:    []*sshverify.GPUStatus{rtx4090, rtx4090},
+		},
+		{
+			name:    "nothing advertised",
+			session: &models.Session{},
+			gpus:    []*sshverify.GPUStatus{rtx3090},
+		},
+		{
+			name:    "missing GPU",
+			session: &models.Session{GPUType: "RTX 4090", GPUCount: 2, VRAM: 24},
+			gpus:    []*sshverify.GPUStatus{rtx4090},
+			want:    []string{"1 of 2 GPUs visible"},
+		},
+		{
+			name:    "wrong model",
+			session: &models.Session{GPUType: "RTX 4090", GPUCount: 2, VRAM: 24},
+			gpus:    []*sshverify.GPUStatus{rtx4090, rtx3090},
+			want:    []string{"GPU 1 is NVIDIA GeForce RTX 3090, advertised RTX 4090"},
+		},
+		{
+			name:    "less VRAM",
+			session: &models.Session{GPUType: "A100", GPUCount: 1, VRAM: 80},
+			gpus:    []*sshverify.GPUStatus{a100},
+			want:    []string{"GPU 0 has 40 GB VRAM, advertised 80 GB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gpuMismatch(tt.session, tt.gpus))
+		})
+	}
+}
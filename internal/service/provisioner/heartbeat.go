@@ -0,0 +1,28 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Heartbeat polls a running session's instance from its provider. alive is
+// true if the provider reports the instance running; gpuUtilPct is its GPU
+// utilization in percent, or nil if the provider does not report one.
+func (s *Service) Heartbeat(ctx context.Context, session *models.Session) (alive bool, gpuUtilPct *float64, err error) {
+	if session.ProviderID == "" || session.Status != models.StatusRunning {
+		return false, nil, nil
+	}
+
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return false, nil, err
+	}
+
+	status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get instance status: %w", err)
+	}
+	return status.Running, status.GPUUtilPct, nil
+}
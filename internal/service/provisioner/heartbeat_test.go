@@ -0,0 +1,47 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Heartbeat(t *testing.T) {
+	prov := newMockProvider("vastai")
+	svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+	session := &models.Session{ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning}
+	ctx := context.Background()
+
+	// Providers without idle detection report no utilization
+	alive, util, err := svc.Heartbeat(ctx, session)
+	require.NoError(t, err)
+	assert.True(t, alive)
+	assert.Nil(t, util)
+
+	pct := 12.5
+	prov.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Running: true, Status: "running", GPUUtilPct: &pct}, nil
+	}
+	alive, util, err = svc.Heartbeat(ctx, session)
+	require.NoError(t, err)
+	assert.True(t, alive)
+	require.NotNil(t, util)
+	assert.Equal(t, 12.5, *util)
+
+	prov.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		return nil, errors.New("provider unavailable")
+	}
+	_, _, err = svc.Heartbeat(ctx, session)
+	assert.Error(t, err)
+
+	// Sessions that are not running are never polled
+	session.Status = models.StatusStopping
+	alive, _, err = svc.Heartbeat(ctx, session)
+	require.NoError(t, err)
+	assert.False(t, alive)
+}
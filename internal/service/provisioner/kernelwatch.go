@@ -0,0 +1,168 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// sshKernelLogReader reads kernel logs with the SSH executor
+type sshKernelLogReader struct{}
+
+func (sshKernelLogReader) ReadKernelErrors(ctx context.Context, host string, port int, user, privateKey string) ([]sshverify.KernelEvent, error) {
+	executor := sshverify.NewExecutor(
+		sshverify.WithExecutorConnectTimeout(15*time.Second),
+		sshverify.WithExecutorCommandTimeout(15*time.Second),
+	)
+	conn, err := executor.Connect(ctx, host, port, user, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return executor.ReadKernelErrors(ctx, conn)
+}
+
+// kernelEventKinds orders the kinds of a poll's kernel events, most
+// telling first
+var kernelEventKinds = []sshverify.KernelEventKind{sshverify.KernelOOM, sshverify.KernelXid, sshverify.KernelError}
+
+// startKernelWatch watches the session's kernel log in the background until
+// the session ends or the service is stopped
+func (s *Service) startKernelWatch(session *models.Session, privateKey string, logger *slog.Logger) {
+	s.kernelWatchesMu.Lock()
+	defer s.kernelWatchesMu.Unlock()
+	if s.ctx.Err() != nil {
+		return
+	}
+	s.kernelWatches.Add(1)
+	go func() {
+		defer s.kernelWatches.Done()
+		s.watchKernelLog(s.ctx, session, privateKey, logger)
+	}()
+}
+
+// watchKernelLog reads the session's kernel log every kernelWatchInterval
+// for as long as it runs, recording errors logged since it was handed over
+// as kernel events. The session key is only held in memory, so watching
+// stops with the server and is not resumed after a restart.
+func (s *Service) watchKernelLog(stopCtx context.Context, session *models.Session, privateKey string, logger *slog.Logger) {
+	defer s.setKernelDiagnosis(session.ID, "")
+	ticker := time.NewTicker(s.kernelWatchInterval)
+	defer ticker.Stop()
+
+	// Errors logged before the first read predate the session
+	seen := -1.0 // Uptime of the newest line read, -1 before the first read
+	for {
+		ctx, cancel := context.WithTimeout(stopCtx, 30*time.Second)
+		events, err := s.kernelLogReader.ReadKernelErrors(s.pinHostKey(ctx, session),
+			session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+		if err != nil {
+			var mismatch *sshverify.HostKeyMismatchError
+			if errors.As(err, &mismatch) {
+				s.reportHostKeyMismatch(ctx, session, mismatch)
+				cancel()
+				return
+			}
+			logger.Debug("kernel log: failed to read", slog.String("error", err.Error()))
+		} else {
+			newer, newest := newKernelEvents(events, seen)
+			if seen >= 0 && len(newer) > 0 {
+				s.recordKernelEvents(session, newer, logger)
+			}
+			seen = newest
+		}
+		cancel()
+
+		select {
+		case <-ticker.C:
+		case <-stopCtx.Done():
+			return
+		}
+		ctx, cancel = context.WithTimeout(stopCtx, 10*time.Second)
+		current, err := s.store.Get(ctx, session.ID)
+		cancel()
+		if err != nil || current.Status != models.StatusRunning {
+			return
+		}
+	}
+}
+
+// newKernelEvents returns the events logged after uptime seen, and the
+// uptime of the newest event. A log whose newest event is older than seen
+// belongs to a rebooted instance, so all of it is new.
+func newKernelEvents(events []sshverify.KernelEvent, seen float64) ([]sshverify.KernelEvent, float64) {
+	if len(events) == 0 {
+		return nil, max(seen, 0)
+	}
+	newest := 0.0
+	for _, e := range events {
+		newest = max(newest, e.Uptime)
+	}
+	if newest < seen {
+		return events, newest
+	}
+	var newer []sshverify.KernelEvent
+	for _, e := range events {
+		if e.Uptime > seen {
+			newer = append(newer, e)
+		}
+	}
+	return newer, max(newest, seen)
+}
+
+// recordKernelEvents records one kernel event per kind of error found in a
+// poll, and keeps their summary as the session's diagnosis should it stop
+func (s *Service) recordKernelEvents(session *models.Session, events []sshverify.KernelEvent, logger *slog.Logger) {
+	var diagnoses []string
+	for _, kind := range kernelEventKinds {
+		reason := sshverify.DescribeKernelEvents(kind, events)
+		if reason == "" {
+			continue
+		}
+		var lines strings.Builder
+		for _, e := range events {
+			if e.Kind == kind {
+				fmt.Fprintf(&lines, "[%12.6f] %s\n", e.Uptime, e.Message)
+			}
+		}
+		logger.Warn("kernel log: errors on instance",
+			slog.String("kind", string(kind)),
+			slog.String("detail", reason))
+		s.recordEvent(&models.SessionEvent{
+			SessionID:  session.ID,
+			Type:       models.SessionEventKernel,
+			FromStatus: models.StatusRunning,
+			ToStatus:   models.StatusRunning,
+			Reason:     reason,
+			Output:     truncateOutput(lines.String(), maxBootstrapOutputBytes),
+		}, logger)
+		diagnoses = append(diagnoses, reason)
+	}
+	s.setKernelDiagnosis(session.ID, strings.Join(diagnoses, "; "))
+}
+
+// setKernelDiagnosis keeps the errors last found in a session's kernel log,
+// or forgets them if diagnosis is empty
+func (s *Service) setKernelDiagnosis(sessionID, diagnosis string) {
+	s.kernelDiagnosesMu.Lock()
+	defer s.kernelDiagnosesMu.Unlock()
+	if diagnosis == "" {
+		delete(s.kernelDiagnoses, sessionID)
+		return
+	}
+	s.kernelDiagnoses[sessionID] = diagnosis
+}
+
+// kernelDiagnosis returns the errors last found in a session's kernel log,
+// or "" if none were found or it is not watched
+func (s *Service) kernelDiagnosis(sessionID string) string {
+	s.kernelDiagnosesMu.Lock()
+	defer s.kernelDiagnosesMu.Unlock()
+	return s.kernelDiagnoses[sessionID]
+}
@@ -0,0 +1,153 @@
+package provisioner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// kernelLog is a fake KernelLogReader serving a settable kernel log
+type kernelLog struct {
+	mu     sync.Mutex
+	events []sshverify.KernelEvent
+	reads  int
+}
+
+func (k *kernelLog) ReadKernelErrors(ctx context.Context, host string, port int, user, privateKey string) ([]sshverify.KernelEvent, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.reads++
+	return append([]sshverify.KernelEvent(nil), k.events...), nil
+}
+
+func (k *kernelLog) set(events ...sshverify.KernelEvent) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.events = events
+}
+
+func (k *kernelLog) getReads() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.reads
+}
+
+func TestNewKernelEvents(t *testing.T) {
+	events := []sshverify.KernelEvent{{Uptime: 10}, {Uptime: 20}, {Uptime: 30}}
+
+	newer, newest := newKernelEvents(events, 20)
+	assert.Equal(t, events[2:], newer)
+	assert.Equal(t, 30.0, newest)
+
+	newer, newest = newKernelEvents(nil, 20)
+	assert.Empty(t, newer)
+	assert.Equal(t, 20.0, newest)
+
+	// An instance that rebooted starts its log over
+	newer, newest = newKernelEvents(events[:1], 20)
+	assert.Equal(t, events[:1], newer)
+	assert.Equal(t, 10.0, newest)
+}
+
+func TestClassifyInstanceStopReason_KernelDiagnosis(t *testing.T) {
+	assert.Equal(t, "instance stopped unexpectedly: exited — likely cause: entrypoint failed or OOM kill",
+		classifyInstanceStopReason("exited", "", ""))
+	assert.Equal(t, "instance stopped unexpectedly: exited (code 137) — cause (kernel log): OOM killer killed python3",
+		classifyInstanceStopReason("exited", "code 137", "OOM killer killed python3"))
+}
+
+func TestService_WatchKernelLog(t *testing.T) {
+	store := newMockSessionStore()
+	events := &eventLog{}
+	kernel := &kernelLog{}
+	// Errors already logged when the session is handed over are not its own
+	kernel.set(sshverify.KernelEvent{Uptime: 10, Kind: sshverify.KernelOOM, Message: "Out of memory: Killed process 7 (stale)"})
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithKernelLogReader(kernel),
+		WithKernelWatchInterval(10*time.Millisecond),
+		WithEventRecorder(events))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadInteractive,
+		ReservationHrs: 1,
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	require.Eventually(t, func() bool { return kernel.getReads() >= 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, events.getEvents())
+
+	kernel.set(
+		sshverify.KernelEvent{Uptime: 10, Kind: sshverify.KernelOOM, Message: "Out of memory: Killed process 7 (stale)"},
+		sshverify.KernelEvent{Uptime: 500.25, Kind: sshverify.KernelOOM, Message: "Out of memory: Killed process 4242 (python3)"},
+		sshverify.KernelEvent{Uptime: 501, Kind: sshverify.KernelXid, Message: "NVRM: Xid (PCI:0000:01:00): 79, pid=4300, GPU has fallen off the bus."},
+	)
+	require.Eventually(t, func() bool { return len(events.getEvents()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	got := events.getEvents()
+	assert.Equal(t, models.SessionEventKernel, got[0].Type)
+	assert.Equal(t, "OOM killer killed python3", got[0].Reason)
+	assert.Equal(t, "[  500.250000] Out of memory: Killed process 4242 (python3)\n", got[0].Output)
+	assert.Equal(t, "GPU Xid error 79", got[1].Reason)
+
+	// Each error is recorded once
+	reads := kernel.getReads()
+	require.Eventually(t, func() bool { return kernel.getReads() > reads+1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, events.getEvents(), 2)
+
+	// A session lost afterwards is failed with a confirmed cause
+	failedOver, err := svc.FailoverSession(ctx, session.ID, "instance terminated outside our control")
+	require.NoError(t, err)
+	require.True(t, failedOver)
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "instance terminated outside our control — cause (kernel log): OOM killer killed python3; GPU Xid error 79", s.Error)
+
+	// Watching stops with the session
+	require.Eventually(t, func() bool { return svc.kernelDiagnosis(session.ID) == "" }, 5*time.Second, 10*time.Millisecond)
+	reads = kernel.getReads()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, reads, kernel.getReads())
+}
+
+func TestService_StopEndsKernelWatches(t *testing.T) {
+	store := newMockSessionStore()
+	kernel := &kernelLog{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithKernelLogReader(kernel),
+		WithKernelWatchInterval(10*time.Millisecond))
+
+	session, err := svc.CreateSession(context.Background(), models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadInteractive,
+		ReservationHrs: 1,
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	require.Eventually(t, func() bool { return kernel.getReads() >= 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// The session is still running, but the watch ends with the service
+	svc.Stop()
+	reads := kernel.getReads()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, reads, kernel.getReads())
+	s, err := store.Get(context.Background(), session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+}
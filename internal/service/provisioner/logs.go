@@ -0,0 +1,52 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Bounds for GetSessionLogs' tail
+const (
+	DefaultLogTail = 200
+	MaxLogTail     = 5000
+)
+
+// GetSessionLogs returns the last tail lines of a session's container output,
+// as kept by its provider. Logs are read from the provider API, so they are
+// available for as long as the instance exists, including after a crash,
+// but not once it has been destroyed.
+func (s *Service) GetSessionLogs(ctx context.Context, sessionID string, tail int) (string, error) {
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("session not found: %w", err)
+	}
+
+	if session.ProviderID == "" || session.Status == models.StatusStopped {
+		return "", &SessionNotRunningError{ID: sessionID, Status: session.Status}
+	}
+
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return "", &ProviderNotFoundError{Name: session.Provider}
+	}
+	logsProvider, ok := provider.As[provider.LogsProvider](prov)
+	if !ok {
+		return "", &LogsNotSupportedError{Provider: session.Provider}
+	}
+
+	if tail <= 0 {
+		tail = DefaultLogTail
+	}
+	if tail > MaxLogTail {
+		tail = MaxLogTail
+	}
+
+	logs, err := logsProvider.GetInstanceLogs(ctx, session.ProviderID, tail)
+	if err != nil {
+		return "", fmt.Errorf("failed to get instance logs: %w", err)
+	}
+	return logs, nil
+}
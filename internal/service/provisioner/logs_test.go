@@ -0,0 +1,76 @@
+package provisioner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// logsProvider adds instance logs to mockProvider
+type logsProvider struct {
+	*mockProvider
+	instanceID string
+	tail       int
+}
+
+func (p *logsProvider) GetInstanceLogs(ctx context.Context, instanceID string, tail int) (string, error) {
+	p.instanceID = instanceID
+	p.tail = tail
+	return "server started\n", nil
+}
+
+func TestService_GetSessionLogs(t *testing.T) {
+	ctx := context.Background()
+	store := newMockSessionStore()
+	prov := &logsProvider{mockProvider: newMockProvider("vastai")}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+
+	require.NoError(t, store.Create(ctx, &models.Session{
+		ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusFailed,
+	}))
+
+	logs, err := svc.GetSessionLogs(ctx, "sess-1", 0)
+	require.NoError(t, err, "failed sessions keep their instance logs until destroyed")
+	assert.Equal(t, "server started\n", logs)
+	assert.Equal(t, "inst-1", prov.instanceID)
+	assert.Equal(t, DefaultLogTail, prov.tail)
+
+	_, err = svc.GetSessionLogs(ctx, "sess-1", 100000)
+	require.NoError(t, err)
+	assert.Equal(t, MaxLogTail, prov.tail)
+}
+
+func TestService_GetSessionLogs_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("destroyed", func(t *testing.T) {
+		store := newMockSessionStore()
+		prov := &logsProvider{mockProvider: newMockProvider("vastai")}
+		svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusStopped,
+		}))
+
+		_, err := svc.GetSessionLogs(ctx, "sess-1", 10)
+		var notRunning *SessionNotRunningError
+		require.ErrorAs(t, err, &notRunning)
+		assert.Empty(t, prov.instanceID)
+	})
+
+	t.Run("provider unsupported", func(t *testing.T) {
+		store := newMockSessionStore()
+		svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("tensordock")}), WithLogger(newTestLogger()))
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID: "sess-1", Provider: "tensordock", ProviderID: "inst-1", Status: models.StatusRunning,
+		}))
+
+		_, err := svc.GetSessionLogs(ctx, "sess-1", 10)
+		var notSupported *LogsNotSupportedError
+		require.ErrorAs(t, err, &notSupported)
+	})
+}
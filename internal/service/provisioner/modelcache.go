@@ -0,0 +1,75 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/modelcache"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// pullsModel reports whether the session's model weights are pulled from
+// the model cache once it is running. Entrypoint-mode workloads download
+// their model themselves as they start.
+func (s *Service) pullsModel(session *models.Session) bool {
+	return s.modelCache != nil &&
+		session.ModelID != "" &&
+		session.LaunchMode != models.LaunchModeEntrypoint
+}
+
+// pullModel downloads the session's model weights from the model cache onto
+// its instance, recording the result in the session history. It returns the
+// directory the weights are in, or "" if they were not pulled; the session
+// keeps running either way.
+func (s *Service) pullModel(session *models.Session, privateKey string, logger *slog.Logger) string {
+	ctx, cancel := context.WithTimeout(context.Background(), s.modelPullTimeout)
+	defer cancel()
+
+	event := &models.SessionEvent{
+		SessionID:  session.ID,
+		Type:       models.SessionEventModelCache,
+		FromStatus: session.Status,
+		ToStatus:   session.Status,
+	}
+	pull, err := s.modelCache.Pull(ctx, session.ModelID)
+	if err != nil {
+		if errors.Is(err, modelcache.ErrNotCached) {
+			event.Reason = fmt.Sprintf("model %s is not in the model cache", session.ModelID)
+			logger.Info("model is not in the model cache", slog.String("model_id", session.ModelID))
+		} else {
+			event.Reason = "model cache lookup failed: " + err.Error()
+			logger.Warn("model cache lookup failed", slog.String("error", err.Error()))
+		}
+		s.recordEvent(event, logger)
+		return ""
+	}
+
+	start := time.Now()
+	output, err := s.bootstrapRunner.RunScript(s.pinHostKey(ctx, session),
+		session.SSHHost, session.SSHPort, session.SSHUser, privateKey, pull.Script)
+	event.Output = truncateOutput(output, maxBootstrapOutputBytes)
+	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+		}
+		event.Reason = "model pull failed: " + err.Error()
+		logger.Warn("model pull failed",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(start)))
+		s.recordEvent(event, logger)
+		return ""
+	}
+
+	event.Reason = fmt.Sprintf("model pulled from cache: %d files, %s in %s", pull.Files, formatBytes(pull.Size), pull.Dir)
+	logger.Info("model pulled from cache",
+		slog.String("model_id", session.ModelID),
+		slog.Int64("bytes", pull.Size),
+		slog.Duration("duration", time.Since(start)))
+	s.recordEvent(event, logger)
+	return pull.Dir
+}
@@ -0,0 +1,83 @@
+package provisioner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/modelcache"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelStore is a fake ModelCache holding one model
+type modelStore struct {
+	modelID string
+}
+
+func (m *modelStore) Pull(ctx context.Context, modelID string) (*modelcache.Pull, error) {
+	if modelID != m.modelID {
+		return nil, modelcache.ErrNotCached
+	}
+	return &modelcache.Pull{Dir: "/models/" + modelID, Files: 4, Size: 15 << 29, Script: "fetch weights"}, nil
+}
+
+func TestService_CreateSession_PullsModel(t *testing.T) {
+	tests := []struct {
+		name        string
+		modelID     string
+		wantScripts []string
+		wantReason  string
+	}{
+		{
+			name:    "cached model",
+			modelID: "org/model",
+			wantScripts: []string{
+				"fetch weights",
+				"export MODEL_DIR='/models/org/model'\nvllm serve \"$MODEL_DIR\"",
+			},
+			wantReason: "model pulled from cache: 4 files, 7.5 GiB in /models/org/model",
+		},
+		{
+			name:        "model not cached",
+			modelID:     "org/other",
+			wantScripts: []string{"vllm serve \"$MODEL_DIR\""},
+			wantReason:  "model org/other is not in the model cache",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockSessionStore()
+			runner := &scriptRunner{}
+			events := &eventLog{}
+			svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+				WithLogger(newTestLogger()),
+				WithSSHVerifier(NewMockSSHVerifier()),
+				WithSSHCheckInterval(10*time.Millisecond),
+				WithBootstrapRunner(runner),
+				WithModelCache(&modelStore{modelID: "org/model"}),
+				WithEventRecorder(events))
+
+			ctx := context.Background()
+			_, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+				ConsumerID:      "consumer-001",
+				OfferID:         "offer-1",
+				WorkloadType:    models.WorkloadLLMVLLM,
+				ReservationHrs:  1,
+				ModelID:         tt.modelID,
+				BootstrapScript: "vllm serve \"$MODEL_DIR\"",
+			}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+			require.NoError(t, err)
+			require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+			assert.Equal(t, tt.wantScripts, runner.getScripts())
+			got := events.getEvents()
+			require.Len(t, got, 2)
+			assert.Equal(t, models.SessionEventModelCache, got[0].Type)
+			assert.Equal(t, tt.wantReason, got[0].Reason)
+			assert.Equal(t, models.SessionEventBootstrap, got[1].Type)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/logging"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// A paused session's instance is stopped with its disk kept, so its GPUs are
+// not billed until it is resumed. Its reservation clock stops too: resuming
+// moves the expiry back by the time spent paused, and paused time does not
+// count towards the hard max. The lifecycle manager destroys sessions left
+// paused too long.
+
+// PauseSession stops the instance of a running session without destroying
+// it. Only providers implementing provider.PowerController can pause, and
+// interruptible sessions cannot.
+func (s *Service) PauseSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	// Serialize with destroys, failovers and resumes of the session
+	lock := s.getDestroyLock(sessionID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if session.Status != models.StatusRunning || session.ProviderID == "" {
+		return nil, &SessionNotRunningError{ID: sessionID, Status: session.Status}
+	}
+	if session.Interruptible {
+		return nil, &PauseNotSupportedError{Provider: session.Provider, Interruptible: true}
+	}
+	if s.SavingWorkspace(sessionID) {
+		return nil, &SessionNotRunningError{ID: sessionID, Status: models.StatusStopping}
+	}
+	pauser, err := s.powerController(session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pauser.StopInstance(ctx, session.ProviderID); err != nil {
+		return nil, fmt.Errorf("failed to stop instance: %w", err)
+	}
+
+	session.Status = models.StatusPaused
+	session.PausedAt = s.now()
+	if err := s.store.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	s.logger.Info("session paused",
+		slog.String("session_id", sessionID),
+		slog.String("provider", session.Provider),
+		slog.String("provider_id", session.ProviderID))
+	logging.Audit(ctx, "session_paused",
+		"session_id", session.ID,
+		"consumer_id", session.ConsumerID,
+		"provider", session.Provider,
+		"provider_id", session.ProviderID)
+	return session, nil
+}
+
+// ResumeSession starts the instance of a paused session and waits for it to
+// run, refreshing its SSH details, which the provider may have changed. If
+// the instance does not start within the resume timeout, for example because
+// its GPUs were rented out meanwhile, it is stopped again and a
+// ResumeTimeoutError returned; the session stays paused.
+func (s *Service) ResumeSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	lock := s.getDestroyLock(sessionID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if session.Status != models.StatusPaused {
+		return nil, &SessionNotPausedError{ID: sessionID, Status: session.Status}
+	}
+	pauser, err := s.powerController(session)
+	if err != nil {
+		return nil, err
+	}
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return nil, &ProviderNotFoundError{Name: session.Provider}
+	}
+
+	if err := pauser.StartInstance(ctx, session.ProviderID); err != nil {
+		return nil, fmt.Errorf("failed to start instance: %w", err)
+	}
+
+	status, err := s.awaitInstanceRunning(ctx, prov, session)
+	if err != nil {
+		// Leave the instance as it was, rather than billing for GPUs no session uses
+		if stopErr := pauser.StopInstance(context.WithoutCancel(ctx), session.ProviderID); stopErr != nil {
+			s.logger.Error("failed to stop instance after failed resume",
+				slog.String("session_id", sessionID),
+				slog.String("provider_id", session.ProviderID),
+				slog.String("error", stopErr.Error()))
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	paused := now.Sub(session.PausedAt)
+	session.Status = models.StatusRunning
+	session.PausedTotal += paused
+	session.PausedAt = time.Time{}
+	session.ExpiresAt = session.ExpiresAt.Add(paused)
+	updateSSHEndpoint(session, status)
+	if err := s.store.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	s.logger.Info("session resumed",
+		slog.String("session_id", sessionID),
+		slog.Duration("paused", paused),
+		slog.Time("expires_at", session.ExpiresAt))
+	logging.Audit(ctx, "session_resumed",
+		"session_id", session.ID,
+		"consumer_id", session.ConsumerID,
+		"provider", session.Provider,
+		"provider_id", session.ProviderID,
+		"paused_minutes", paused.Minutes())
+	return session, nil
+}
+
+// powerController returns the session's provider if it can stop and start instances
+func (s *Service) powerController(session *models.Session) (provider.PowerController, error) {
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return nil, &ProviderNotFoundError{Name: session.Provider}
+	}
+	pauser, ok := provider.As[provider.PowerController](prov)
+	if !ok {
+		return nil, &PauseNotSupportedError{Provider: session.Provider}
+	}
+	return pauser, nil
+}
+
+// awaitInstanceRunning polls the status of a session's instance until it
+// runs with SSH details, or the resume timeout passes
+func (s *Service) awaitInstanceRunning(ctx context.Context, prov provider.Provider, session *models.Session) (*provider.InstanceStatus, error) {
+	timeout := time.NewTimer(s.resumeTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(s.sshCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+		if errors.Is(err, provider.ErrInstanceNotFound) {
+			return nil, err
+		}
+		if err != nil {
+			s.logger.Debug("status check of resuming instance failed",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+		} else if status.Running && status.SSHHost != "" {
+			return status, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			return nil, &ResumeTimeoutError{SessionID: session.ID, Timeout: s.resumeTimeout}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package provisioner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pausableProvider adds stopping and starting instances to mockProvider.
+// Started instances come back on a new SSH port unless stuck is set.
+type pausableProvider struct {
+	*mockProvider
+	mu      sync.Mutex
+	stopped bool
+	stuck   bool
+	calls   []string
+}
+
+func newPausableProvider() *pausableProvider {
+	p := &pausableProvider{mockProvider: newMockProvider("vastai")}
+	p.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.stopped || p.stuck {
+			return &provider.InstanceStatus{Status: "stopped"}, nil
+		}
+		return &provider.InstanceStatus{Status: "running", Running: true,
+			SSHHost: "192.168.1.100", SSHPort: 40022, SSHUser: "root"}, nil
+	}
+	return p
+}
+
+func (p *pausableProvider) StopInstance(ctx context.Context, instanceID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	p.calls = append(p.calls, "stop")
+	return nil
+}
+
+func (p *pausableProvider) StartInstance(ctx context.Context, instanceID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = false
+	p.calls = append(p.calls, "start")
+	return nil
+}
+
+func (p *pausableProvider) getCalls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+func newPauseFixture(t *testing.T, prov provider.Provider, now *time.Time) (*Service, *mockSessionStore) {
+	t.Helper()
+	store := newMockSessionStore()
+	require.NoError(t, store.Create(context.Background(), &models.Session{
+		ID:         "sess-1",
+		ConsumerID: "consumer-001",
+		Provider:   "vastai",
+		ProviderID: "inst-1",
+		Status:     models.StatusRunning,
+		SSHHost:    "192.168.1.100",
+		SSHPort:    22,
+		CreatedAt:  *now,
+		ExpiresAt:  now.Add(2 * time.Hour),
+	}))
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithResumeTimeout(100*time.Millisecond),
+		WithTimeFunc(func() time.Time { return *now }))
+	return svc, store
+}
+
+func TestService_PauseAndResumeSession(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	prov := newPausableProvider()
+	svc, store := newPauseFixture(t, prov, &now)
+	ctx := context.Background()
+
+	paused, err := svc.PauseSession(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPaused, paused.Status)
+	assert.Equal(t, now, paused.PausedAt)
+
+	_, err = svc.PauseSession(ctx, "sess-1")
+	var notRunning *SessionNotRunningError
+	assert.ErrorAs(t, err, &notRunning)
+
+	now = now.Add(3 * time.Hour)
+	resumed, err := svc.ResumeSession(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, resumed.Status)
+	assert.True(t, resumed.PausedAt.IsZero())
+	assert.Equal(t, 3*time.Hour, resumed.PausedTotal)
+	// The 2 hour reservation moves back by the 3 hours spent paused
+	assert.Equal(t, start.Add(5*time.Hour), resumed.ExpiresAt)
+	assert.Equal(t, 40022, resumed.SSHPort)
+	assert.Equal(t, time.Duration(0), resumed.RunningFor(now))
+
+	stored, err := store.Get(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, stored.Status)
+	assert.Equal(t, []string{"stop", "start"}, prov.getCalls())
+
+	_, err = svc.ResumeSession(ctx, "sess-1")
+	var notPaused *SessionNotPausedError
+	assert.ErrorAs(t, err, &notPaused)
+}
+
+func TestService_ResumeSession_Timeout(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	prov := newPausableProvider()
+	svc, store := newPauseFixture(t, prov, &now)
+	ctx := context.Background()
+
+	_, err := svc.PauseSession(ctx, "sess-1")
+	require.NoError(t, err)
+	prov.mu.Lock()
+	prov.stuck = true
+	prov.mu.Unlock()
+
+	_, err = svc.ResumeSession(ctx, "sess-1")
+	var timeoutErr *ResumeTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "sess-1", timeoutErr.SessionID)
+
+	// The instance is stopped again and the session stays paused
+	assert.Equal(t, []string{"stop", "start", "stop"}, prov.getCalls())
+	stored, err := store.Get(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPaused, stored.Status)
+}
+
+func TestService_PauseSession_NotSupported(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("provider", func(t *testing.T) {
+		svc, _ := newPauseFixture(t, newMockProvider("vastai"), &now)
+		_, err := svc.PauseSession(ctx, "sess-1")
+		var notSupported *PauseNotSupportedError
+		require.ErrorAs(t, err, &notSupported)
+		assert.False(t, notSupported.Interruptible)
+	})
+
+	t.Run("interruptible", func(t *testing.T) {
+		prov := newPausableProvider()
+		svc, store := newPauseFixture(t, prov, &now)
+		session, err := store.Get(ctx, "sess-1")
+		require.NoError(t, err)
+		session.Interruptible = true
+		require.NoError(t, store.Update(ctx, session))
+
+		_, err = svc.PauseSession(ctx, "sess-1")
+		var notSupported *PauseNotSupportedError
+		require.ErrorAs(t, err, &notSupported)
+		assert.True(t, notSupported.Interruptible)
+		assert.Empty(t, prov.getCalls())
+	})
+}
@@ -0,0 +1,667 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Provisioning runs as a state machine over models.ProvisionPhase:
+//
+//	pending -> provisioning -> booting -> verifying -> running
+//
+// Each transition is persisted with the session, so after a restart the
+// reconciler can tell a session whose instance is still booting or being
+// verified from one that never got an instance, and resume its verification
+// (see ResumeVerification). A failure moves the session to StatusFailed and
+// keeps the phase it failed in.
+
+// phaseOrder ranks the provisioning phases; sessions only move forward
+var phaseOrder = map[models.ProvisionPhase]int{
+	models.PhasePending:      1,
+	models.PhaseProvisioning: 2,
+	models.PhaseBooting:      3,
+	models.PhaseWarmingUp:    4,
+	models.PhaseVerifying:    5,
+	models.PhaseRunning:      6,
+}
+
+// phaseStatus is the session status that goes with each provisioning phase
+var phaseStatus = map[models.ProvisionPhase]models.SessionStatus{
+	models.PhasePending:      models.StatusPending,
+	models.PhaseProvisioning: models.StatusProvisioning,
+	models.PhaseBooting:      models.StatusProvisioning,
+	models.PhaseWarmingUp:    models.StatusProvisioning,
+	models.PhaseVerifying:    models.StatusProvisioning,
+	models.PhaseRunning:      models.StatusRunning,
+}
+
+// sshBannerTimeout bounds a resumed verification's check that sshd answers
+const sshBannerTimeout = 10 * time.Second
+
+// setPhase moves the session to a later provisioning phase and the status
+// that goes with it, stamping the progress milestone the phase marks,
+// without persisting the change. Sessions recorded before phases were
+// tracked have none and may enter any phase.
+func setPhase(session *models.Session, to models.ProvisionPhase) error {
+	if session.IsTerminal() || phaseOrder[to] <= phaseOrder[session.ProvisionPhase] {
+		return &InvalidPhaseTransitionError{
+			SessionID: session.ID,
+			Status:    session.Status,
+			From:      session.ProvisionPhase,
+			To:        to,
+		}
+	}
+	session.ProvisionPhase = to
+	session.Status = phaseStatus[to]
+
+	now := time.Now()
+	switch to {
+	case models.PhaseBooting:
+		session.Progress.InstanceCreatedAt = now
+	case models.PhaseWarmingUp, models.PhaseVerifying:
+		// Warm-up, when there is one, is where polling begins
+		if session.Progress.CloudInitDoneAt.IsZero() {
+			session.Progress.CloudInitDoneAt = now
+		}
+	case models.PhaseRunning:
+		if session.LaunchMode != models.LaunchModeEntrypoint {
+			session.Progress.SSHVerifiedAt = now
+		}
+	}
+	return nil
+}
+
+// advancePhase moves the session to a later provisioning phase and persists it
+func (s *Service) advancePhase(ctx context.Context, session *models.Session, to models.ProvisionPhase) error {
+	if err := setPhase(session, to); err != nil {
+		return err
+	}
+	if err := s.store.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to record %s phase: %w", to, err)
+	}
+	s.logger.Debug("provisioning phase changed",
+		slog.String("session_id", session.ID),
+		slog.String("phase", string(to)))
+	return nil
+}
+
+// bootDelay is how long to wait after creating an instance before polling
+// SSH. TensorDock VMs need cloud-init runcmd to install the key, unless the
+// provider registered it natively and SSH works as soon as the VM boots.
+// Blue Lobster instances restart SSH during a post-boot dist-upgrade.
+func bootDelay(session *models.Session, prov provider.Provider) time.Duration {
+	switch session.Provider {
+	case "tensordock":
+		if prov == nil || !prov.SupportsFeature(provider.FeatureSSHKeyRegistration) {
+			return TensorDockCloudInitDelay
+		}
+	case "bluelobster":
+		return BlueLobsterBootDelay
+	}
+	return 0
+}
+
+// sshVerification polls a booting instance until SSH works, the deadline
+// passes or the instance is found to be gone.
+type sshVerification struct {
+	svc        *Service
+	sessionID  string
+	privateKey string // Empty for resumed verifications; the key is never stored
+	prov       provider.Provider
+	logger     *slog.Logger
+
+	start    time.Time
+	backoff  *ProgressiveBackoff // Reduces provider API load when instances are slow to boot
+	attempts int
+
+	lastErrorType string
+	lastError     string
+	lastSSHErr    error // Non-nil triggers an instance status check on the next poll
+
+	consecutivePermanentErrors int
+	consecutiveOK              int
+	consecutiveNeeded          int
+	hostKeyAlerted             bool
+}
+
+// verifySSH verifies SSH access to a session's instance, moving the session
+// through the booting and verifying phases to running, or failing it.
+// privateKey is passed directly because it's not stored in the database.
+func (s *Service) verifySSH(ctx context.Context, sessionID, privateKey string, prov provider.Provider, deadline time.Time) {
+	v := &sshVerification{
+		svc:               s,
+		sessionID:         sessionID,
+		privateKey:        privateKey,
+		prov:              prov,
+		logger:            s.logger.With(slog.String("session_id", sessionID)),
+		start:             time.Now(),
+		backoff:           NewProgressiveBackoff(s.sshCheckInterval, s.sshMaxInterval, s.sshBackoffMultiplier),
+		lastErrorType:     "none",
+		consecutiveNeeded: 1,
+	}
+	v.run(ctx, deadline)
+}
+
+func (v *sshVerification) run(ctx context.Context, deadline time.Time) {
+	v.logger.Info("waiting for SSH verification", slog.Bool("resumed", v.resumed()))
+
+	timeout := time.NewTimer(time.Until(deadline))
+	defer timeout.Stop()
+
+	if session, err := v.svc.store.Get(ctx, v.sessionID); err == nil {
+		if session.Provider == "bluelobster" {
+			v.consecutiveNeeded = 2
+		}
+		// Host keys of commodity GPU instances are unknown in advance, so the
+		// first key seen is trusted and pinned on the session (trust on first use)
+		if session.SSHHostKeyFingerprint == "" {
+			v.logger.Info("host key will be trusted on first use")
+		}
+		if session.ProvisionPhase != models.PhaseVerifying {
+			if !v.awaitBoot(ctx, session, timeout.C) {
+				return
+			}
+		}
+	}
+
+	pollTimer := time.NewTimer(v.backoff.Next())
+	defer pollTimer.Stop()
+
+	for {
+		select {
+		case <-timeout.C:
+			v.timedOut(ctx)
+			return
+
+		case <-pollTimer.C:
+			next, done := v.poll(ctx)
+			if done {
+				return
+			}
+			pollTimer.Reset(next)
+
+		case <-ctx.Done():
+			v.logger.Warn("context cancelled while waiting for SSH verification")
+			return
+		}
+	}
+}
+
+// resumed reports whether this verification was picked up after a restart
+func (v *sshVerification) resumed() bool {
+	return v.privateKey == ""
+}
+
+// awaitBoot waits out the provider's boot delay and moves the session to
+// the verifying phase. It reports false if verification ended meanwhile.
+func (v *sshVerification) awaitBoot(ctx context.Context, session *models.Session, timeout <-chan time.Time) bool {
+	if delay := bootDelay(session, v.prov); delay > 0 {
+		v.logger.Info("waiting for instance to boot before SSH polling",
+			slog.String("provider", session.Provider),
+			slog.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-timeout:
+			v.timedOut(ctx)
+			return false
+		case <-ctx.Done():
+			return false
+		}
+
+		// The session may have been destroyed while the instance booted
+		current, err := v.svc.store.Get(ctx, v.sessionID)
+		if err != nil {
+			v.logger.Error("failed to get session", slog.String("error", err.Error()))
+			return true
+		}
+		if current.IsTerminal() {
+			v.logger.Info("session is terminal, stopping SSH verification")
+			return false
+		}
+		session = current
+	}
+
+	if err := v.svc.advancePhase(ctx, session, models.PhaseVerifying); err != nil {
+		v.logger.Warn("failed to move session to verifying", slog.String("error", err.Error()))
+	}
+	return true
+}
+
+// poll makes one verification attempt. It returns the wait before the next
+// attempt, or done once the session is running or has failed.
+func (v *sshVerification) poll(ctx context.Context) (next time.Duration, done bool) {
+	v.attempts++
+	v.logger.Debug("SSH poll attempt",
+		slog.Int("attempt", v.attempts),
+		slog.Duration("next_interval", v.backoff.Current()))
+
+	session, err := v.svc.store.Get(ctx, v.sessionID)
+	if err != nil {
+		v.logger.Error("failed to get session", slog.String("error", err.Error()))
+		return v.backoff.Next(), false
+	}
+	if session.IsTerminal() {
+		v.logger.Info("session is terminal, stopping SSH verification")
+		return 0, true
+	}
+
+	// Poll provider for SSH info if we don't have it yet, OR check
+	// instance health after an SSH failure (detect reclaimed instances).
+	if (session.SSHHost == "" || v.lastSSHErr != nil) && session.ProviderID != "" {
+		proceed, done := v.checkInstance(ctx, session)
+		if done {
+			return 0, true
+		}
+		if !proceed {
+			return v.backoff.Next(), false
+		}
+	}
+
+	if session.SSHHost == "" || session.SSHPort <= 0 {
+		return v.backoff.Next(), false
+	}
+	return v.trySSH(ctx, session)
+}
+
+// checkInstance asks the provider for the instance's state, failing the
+// session if it vanished or stopped, and fills in SSH details the instance
+// did not have when it was created. proceed is false if the provider could
+// not be asked.
+func (v *sshVerification) checkInstance(ctx context.Context, session *models.Session) (proceed, done bool) {
+	s := v.svc
+	status, err := v.prov.GetInstanceStatus(ctx, session.ProviderID)
+	if err != nil {
+		if errors.Is(err, provider.ErrInstanceNotFound) {
+			v.logger.Error("instance no longer exists, failing session",
+				slog.String("provider_id", session.ProviderID))
+			s.failSession(ctx, session, models.FailureInstanceVanished, "", "instance_vanished: no longer exists on provider")
+			metrics.RecordSessionDestroyed(session.Provider, "instance_vanished")
+			s.recordOfferFailure(session, models.FailureInstanceVanished, "instance not found during SSH verification")
+			return false, true
+		}
+		v.logger.Warn("failed to get instance status", slog.String("error", err.Error()))
+		return false, false
+	}
+
+	// BUG-011 fix: Fail fast if instance stopped unexpectedly
+	// Don't fail for transient states like "creating", "starting", or "loading"
+	if !status.Running && status.Status != "" &&
+		status.Status != "created" && status.Status != "creating" &&
+		status.Status != "starting" && status.Status != "provisioning" &&
+		status.Status != "booting" && status.Status != "loading" {
+		v.logger.Error("instance stopped unexpectedly",
+			slog.String("status", status.Status),
+			slog.String("error_detail", status.Error),
+			slog.String("provider_id", session.ProviderID))
+
+		// Attempt to destroy the failed instance
+		if err := v.prov.DestroyInstance(ctx, session.ProviderID); err != nil {
+			v.logger.Error("failed to destroy stopped instance",
+				slog.String("error", err.Error()))
+		}
+
+		failReason := classifyInstanceStopReason(status.Status, status.Error, s.kernelDiagnosis(session.ID))
+		s.failSession(ctx, session, models.FailureInstanceStopped, status.Status, failReason)
+		metrics.RecordSessionDestroyed(session.Provider, "instance_stopped")
+		s.recordOfferFailure(session, models.FailureInstanceStopped, failReason)
+		return false, true
+	}
+
+	// Populate SSH info from provider if we don't have it yet
+	if session.SSHHost == "" && status.SSHHost != "" {
+		session.SSHHost = status.SSHHost
+		session.Progress.IPAssignedAt = time.Now()
+		if status.SSHPort != 0 {
+			session.SSHPort = status.SSHPort
+		}
+		if status.SSHUser != "" {
+			session.SSHUser = status.SSHUser
+		}
+		if err := s.store.Update(ctx, session); err != nil {
+			v.logger.Error("failed to update SSH info", slog.String("error", err.Error()))
+		} else {
+			v.logger.Info("SSH info updated",
+				slog.String("ssh_host", session.SSHHost),
+				slog.Int("ssh_port", session.SSHPort))
+			// Reset backoff when we get new SSH info
+			v.backoff.Reset()
+		}
+	}
+	return true, false
+}
+
+// trySSH makes a single SSH connection attempt
+func (v *sshVerification) trySSH(ctx context.Context, session *models.Session) (next time.Duration, done bool) {
+	s := v.svc
+	v.logger.Debug("attempting SSH verification",
+		slog.String("host", session.SSHHost),
+		slog.Int("port", session.SSHPort))
+
+	var err error
+	if v.resumed() {
+		err = checkSSHBanner(ctx, session.SSHHost, session.SSHPort)
+	} else {
+		err = s.sshVerifier.VerifyOnce(s.pinHostKey(ctx, session), session.SSHHost, session.SSHPort, session.SSHUser, v.privateKey)
+	}
+	if err == nil {
+		v.lastSSHErr = nil
+		v.consecutiveOK++
+		if v.consecutiveOK < v.consecutiveNeeded {
+			v.logger.Info("SSH succeeded, verifying stability",
+				slog.Int("consecutive", v.consecutiveOK),
+				slog.Int("needed", v.consecutiveNeeded))
+			// Quick re-check after 5 seconds
+			return 5 * time.Second, false
+		}
+		v.succeed(ctx, session)
+		return 0, true
+	}
+
+	v.lastSSHErr = err
+	v.lastErrorType = classifySSHError(err)
+	var mismatch *sshverify.HostKeyMismatchError
+	if errors.As(err, &mismatch) && !v.hostKeyAlerted {
+		v.hostKeyAlerted = true
+		s.reportHostKeyMismatch(ctx, session, mismatch)
+	}
+	v.consecutiveOK = 0
+	v.lastError = err.Error()
+	v.logger.Info("SSH verification attempt failed",
+		slog.Int("attempt", v.attempts),
+		slog.String("error_type", v.lastErrorType),
+		slog.String("host", session.SSHHost),
+		slog.Int("port", session.SSHPort),
+		slog.String("error", v.lastError))
+	metrics.RecordSSHVerifyError(session.Provider, v.lastErrorType)
+
+	// Fail fast on permanent SSH errors (auth_failed, key_parse_failed)
+	if !isPermanentSSHError(v.lastErrorType) {
+		v.consecutivePermanentErrors = 0
+		return v.backoff.Next(), false
+	}
+	v.consecutivePermanentErrors++
+	if v.consecutivePermanentErrors < 3 {
+		return v.backoff.Next(), false
+	}
+
+	v.logger.Error("permanent SSH error detected, failing session early",
+		slog.String("error_type", v.lastErrorType),
+		slog.Int("consecutive_errors", v.consecutivePermanentErrors))
+
+	// Set FailedOffers BEFORE failSession so it's persisted atomically
+	if session.OfferID != "" {
+		if session.FailedOffers == "" {
+			session.FailedOffers = session.OfferID
+		} else if !strings.Contains(session.FailedOffers, session.OfferID) {
+			session.FailedOffers = session.FailedOffers + "," + session.OfferID
+		}
+	}
+
+	s.failSession(ctx, session, models.FailureSSHAuth, v.lastErrorType, "permanent SSH error: "+v.lastErrorType)
+	s.recordOfferFailure(session, models.FailureSSHAuth, "permanent SSH error: "+v.lastErrorType)
+
+	metrics.RecordSSHVerifyFailure()
+	metrics.RecordSessionDestroyed(session.Provider, "permanent_ssh_error")
+	return 0, true
+}
+
+// succeed moves the session to running and starts the post-provision checks
+func (v *sshVerification) succeed(ctx context.Context, session *models.Session) {
+	s := v.svc
+	duration := time.Since(v.start)
+	v.logger.Info("SSH verification successful",
+		slog.Duration("duration", duration),
+		slog.Int("attempts", v.attempts))
+
+	// Check the GPUs are those advertised, load-test them if asked, and
+	// restore the consumer's saved workspace before handing the session
+	// over. The burn-in and restore may outlast the verification deadline,
+	// which no longer applies.
+	if !v.resumed() && !s.checkGPUs(session, v.privateKey, v.logger) {
+		return
+	}
+	burnIn := session.GPUBurnIn && !v.resumed()
+	if burnIn && !s.burnIn(session, v.privateKey, v.logger) {
+		return
+	}
+	restore := s.preservesWorkspace(session) && !v.resumed()
+	if restore {
+		s.restoreWorkspace(session, v.privateKey, v.logger)
+	}
+	if burnIn || restore {
+		ctx = context.WithoutCancel(ctx)
+		// The session may have been destroyed meanwhile
+		current, err := s.store.Get(ctx, v.sessionID)
+		if err != nil {
+			v.logger.Error("failed to get session", slog.String("error", err.Error()))
+		} else if current.IsTerminal() {
+			v.logger.Info("session is terminal, not handing it over")
+			return
+		} else {
+			session = current
+		}
+	}
+
+	if err := s.advancePhase(ctx, session, models.PhaseRunning); err != nil {
+		v.logger.Error("failed to update session to running", slog.String("error", err.Error()))
+	}
+
+	s.notifyRunning(ctx, session, v.privateKey)
+
+	// Durations of a verification resumed partway are meaningless, and the
+	// checks below log in with the session key, which is no longer known
+	if v.resumed() {
+		v.logger.Info("resumed verification complete, skipping post-provision checks")
+		if session.GPUBurnIn {
+			s.recordEvent(&models.SessionEvent{
+				SessionID:  session.ID,
+				Type:       models.SessionEventBurnIn,
+				FromStatus: session.Status,
+				ToStatus:   session.Status,
+				Reason:     "GPU burn-in skipped: the session key is not kept across restarts",
+			}, v.logger)
+		}
+		if s.preservesWorkspace(session) {
+			s.recordEvent(&models.SessionEvent{
+				SessionID:  session.ID,
+				Type:       models.SessionEventWorkspace,
+				FromStatus: session.Status,
+				ToStatus:   session.Status,
+				Reason:     "workspace restore skipped: the session key is not kept across restarts",
+			}, v.logger)
+		}
+		if s.pullsModel(session) {
+			s.recordEvent(&models.SessionEvent{
+				SessionID:  session.ID,
+				Type:       models.SessionEventModelCache,
+				FromStatus: session.Status,
+				ToStatus:   session.Status,
+				Reason:     "model pull skipped: the session key is not kept across restarts",
+			}, v.logger)
+		}
+		if session.BootstrapScript != "" {
+			s.skipBootstrap(session, "the session key is not kept across restarts", v.logger)
+		}
+		return
+	}
+
+	metrics.RecordSSHVerifyDuration(session.Provider, duration)
+	metrics.RecordSSHVerifyAttempts(session.Provider, v.attempts)
+	// Bug #57 fix: Record provisioning duration when session becomes running
+	metrics.RecordProvisioningDuration(session.Provider, duration)
+
+	// BUG-004: Validate CUDA version after SSH success (async, non-blocking)
+	// This is informational - we don't fail the session on mismatch
+	go s.validateCUDAVersionAsync(session, v.privateKey, v.logger)
+
+	// Post-provision disk space check (async, non-blocking)
+	go s.validateDiskSpaceAsync(session, v.privateKey, v.logger)
+
+	// Post-provision throughput measurement (async, non-blocking)
+	go s.measureThroughputAsync(session, v.privateKey, v.logger)
+
+	// Watch the kernel log for OOM kills and GPU errors while the session runs
+	if s.kernelWatchInterval > 0 {
+		s.startKernelWatch(session, v.privateKey, v.logger)
+	}
+
+	// Pull the model weights, then run the consumer's bootstrap script, which
+	// may serve them. The session is already running, so neither holds up
+	// provisioning.
+	var modelDir string
+	if s.pullsModel(session) {
+		modelDir = s.pullModel(session, v.privateKey, v.logger)
+	}
+	if session.BootstrapScript != "" {
+		s.runBootstrap(session, v.privateKey, modelDir, v.logger)
+	}
+}
+
+// timedOut destroys the instance and fails the session
+func (v *sshVerification) timedOut(ctx context.Context) {
+	s := v.svc
+	v.logger.Error("SSH verification timeout, destroying instance",
+		slog.Int("attempts", v.attempts),
+		slog.String("last_error_type", v.lastErrorType),
+		slog.String("last_error", v.lastError),
+		slog.Duration("elapsed", time.Since(v.start)))
+	session, err := s.store.Get(ctx, v.sessionID)
+	if err != nil {
+		v.logger.Error("failed to get session", slog.String("error", err.Error()))
+		return
+	}
+	if session.IsTerminal() {
+		return
+	}
+
+	if session.ProviderID != "" {
+		if err := v.prov.DestroyInstance(ctx, session.ProviderID); err != nil {
+			v.logger.Error("failed to destroy instance after SSH timeout",
+				slog.String("error", err.Error()))
+		}
+	}
+
+	s.failSession(ctx, session, models.FailureSSHTimeout, v.lastErrorType, "SSH verification timeout")
+	metrics.RecordSSHVerifyFailure()
+	// Bug #94 fix: Record session destroyed when SSH verification times out
+	metrics.RecordSessionDestroyed(session.Provider, "ssh_verify_timeout")
+	s.recordOfferFailure(session, models.FailureSSHTimeout, "SSH verification timeout")
+}
+
+// recordOfferFailure records a global offer failure and evicts the offer
+// from cache for cross-session intelligence
+func (s *Service) recordOfferFailure(session *models.Session, category models.FailureCategory, reason string) {
+	if s.inventory == nil {
+		return
+	}
+	s.inventory.RecordOfferFailure(session.OfferID, session.Provider, session.GPUType, session.MachineID, string(category), reason)
+	s.inventory.EvictOffer(session.OfferID)
+}
+
+// ResumeVerification continues, in the background, the verification of a
+// session that a restart interrupted while its instance was booting or being
+// verified. The original deadline still applies. It reports false for
+// sessions it cannot resume: those without an instance, or recorded before
+// provisioning phases were tracked, whose launch mode is unknown.
+//
+// The session's private key is never stored, so a resumed SSH verification
+// only confirms that sshd answers, and skips the post-provision checks that
+// log in to the instance. Entrypoint-mode sessions resume polling their
+// workload API. Sessions that fail are not auto-retried, as the original
+// request is no longer known.
+func (s *Service) ResumeVerification(session *models.Session) bool {
+	if session.Status != models.StatusProvisioning || session.ProviderID == "" || session.VerifyDeadline.IsZero() {
+		return false
+	}
+	switch session.ProvisionPhase {
+	case models.PhaseBooting, models.PhaseWarmingUp, models.PhaseVerifying:
+	default:
+		return false
+	}
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return false
+	}
+	if !s.startVerifying(session.ID) {
+		// Not interrupted: this process is still verifying it
+		return true
+	}
+
+	s.logger.Info("resuming verification",
+		slog.String("session_id", session.ID),
+		slog.String("launch_mode", string(session.LaunchMode)),
+		slog.String("phase", string(session.ProvisionPhase)),
+		slog.Duration("remaining", time.Until(session.VerifyDeadline)))
+
+	// Leave room past the deadline for the timeout to fail the session
+	ctxDeadline := session.VerifyDeadline
+	if now := time.Now(); ctxDeadline.Before(now) {
+		ctxDeadline = now
+	}
+	verifyCtx, cancel := context.WithDeadline(context.Background(), ctxDeadline.Add(5*time.Second))
+	s.verifyWg.Add(1)
+	go func() {
+		defer s.verifyWg.Done()
+		defer s.stopVerifying(session.ID)
+		defer cancel()
+		if session.LaunchMode == models.LaunchModeEntrypoint {
+			s.waitForAPIVerifyAsync(verifyCtx, session.ID, prov, session.VerifyDeadline, true)
+			return
+		}
+		s.verifySSH(verifyCtx, session.ID, "", prov, session.VerifyDeadline)
+	}()
+	return true
+}
+
+// startVerifying marks a session as being verified by this process,
+// returning false if it already is
+func (s *Service) startVerifying(sessionID string) bool {
+	s.verifyingMu.Lock()
+	defer s.verifyingMu.Unlock()
+	if s.verifying[sessionID] {
+		return false
+	}
+	s.verifying[sessionID] = true
+	return true
+}
+
+func (s *Service) stopVerifying(sessionID string) {
+	s.verifyingMu.Lock()
+	defer s.verifyingMu.Unlock()
+	delete(s.verifying, sessionID)
+}
+
+// checkSSHBanner confirms sshd answers on host:port by reading the version
+// banner it sends before authentication
+func checkSSHBanner(ctx context.Context, host string, port int) error {
+	dialer := net.Dialer{Timeout: sshBannerTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetReadDeadline(time.Now().Add(sshBannerTimeout)); err != nil {
+		return err
+	}
+	banner := make([]byte, 4)
+	if _, err := io.ReadFull(conn, banner); err != nil {
+		return fmt.Errorf("failed to read SSH banner: %w", err)
+	}
+	if string(banner) != "SSH-" {
+		return fmt.Errorf("unexpected SSH banner %q", banner)
+	}
+	return nil
+}
@@ -0,0 +1,979 @@
+package provisioner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPhase(t *testing.T) {
+	session := &models.Session{ID: "sess-1", Status: models.StatusPending, ProvisionPhase: models.PhasePending}
+
+	require.NoError(t, setPhase(session, models.PhaseProvisioning))
+	assert.Equal(t, models.StatusProvisioning, session.Status)
+
+	// Phases may be skipped but never repeated or reversed
+	require.NoError(t, setPhase(session, models.PhaseVerifying))
+	var transitionErr *InvalidPhaseTransitionError
+	require.ErrorAs(t, setPhase(session, models.PhaseBooting), &transitionErr)
+	assert.Equal(t, models.PhaseVerifying, transitionErr.From)
+	assert.Equal(t, models.PhaseBooting, transitionErr.To)
+	assert.Error(t, setPhase(session, models.PhaseVerifying))
+
+	require.NoError(t, setPhase(session, models.PhaseRunning))
+	assert.Equal(t, models.StatusRunning, session.Status)
+	assert.True(t, session.Progress.InstanceCreatedAt.IsZero(), "the booting phase was skipped")
+	assert.False(t, session.Progress.CloudInitDoneAt.IsZero())
+	assert.False(t, session.Progress.SSHVerifiedAt.IsZero())
+
+	// Terminal sessions stay where they failed
+	failed := &models.Session{ID: "sess-2", Status: models.StatusFailed, ProvisionPhase: models.PhaseBooting}
+	assert.ErrorAs(t, setPhase(failed, models.PhaseVerifying), &transitionErr)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+
+	// Sessions recorded before phases were tracked may enter any phase
+	legacy := &models.Session{ID: "sess-3", Status: models.StatusProvisioning}
+	assert.NoError(t, setPhase(legacy, models.PhaseVerifying))
+}
+
+func TestService_CreateSession_RecordsPhases(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHVerifyTimeout(5*time.Second),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	before := time.Now()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	after := time.Now()
+
+	assert.Equal(t, models.PhaseBooting, session.ProvisionPhase)
+	assert.False(t, session.VerifyDeadline.Before(before.Add(5*time.Second)))
+	assert.False(t, session.VerifyDeadline.After(after.Add(5*time.Second)))
+
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, models.PhaseRunning, s.ProvisionPhase)
+
+	// Every milestone is stamped, in order
+	p := s.Progress
+	assert.False(t, p.InstanceCreatedAt.Before(before))
+	assert.False(t, p.IPAssignedAt.IsZero(), "the instance came with its SSH host")
+	assert.False(t, p.CloudInitDoneAt.Before(p.InstanceCreatedAt))
+	assert.False(t, p.SSHVerifiedAt.Before(p.CloudInitDoneAt))
+	resp := s.ToResponse()
+	require.NotNil(t, resp.Progress)
+	require.NotNil(t, resp.Progress.SSHVerifiedAt)
+	assert.True(t, p.SSHVerifiedAt.Equal(*resp.Progress.SSHVerifiedAt))
+}
+
+// sshBannerListener accepts connections and greets them like sshd
+func sshBannerListener(t *testing.T) (host string, port int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("SSH-2.0-test\r\n"))
+			conn.Close()
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestService_ResumeVerification(t *testing.T) {
+	host, port := sshBannerListener(t)
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	// Would fail the session if used; a resumed check has no key to log in with
+	verifier := NewMockSSHVerifier()
+	verifier.SetSucceed(false)
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(verifier),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-resume",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		Status:         models.StatusProvisioning,
+		ProvisionPhase: models.PhaseVerifying,
+		VerifyDeadline: now.Add(time.Minute),
+		SSHHost:        host,
+		SSHPort:        port,
+		SSHUser:        "root",
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(time.Hour),
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	require.True(t, svc.ResumeVerification(session))
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, "sess-resume")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, models.PhaseRunning, s.ProvisionPhase)
+	assert.Empty(t, verifier.GetVerifyCalls())
+}
+
+func TestService_ResumeVerification_TimesOutAtOriginalDeadline(t *testing.T) {
+	// Nothing listens on the port, so sshd never answers
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-late",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		Status:         models.StatusProvisioning,
+		ProvisionPhase: models.PhaseVerifying,
+		VerifyDeadline: now.Add(200 * time.Millisecond),
+		SSHHost:        "127.0.0.1",
+		SSHPort:        port,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(time.Hour),
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	require.True(t, svc.ResumeVerification(session))
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, "sess-late")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, s.Status)
+	assert.Equal(t, models.FailureSSHTimeout, s.FailureCategory)
+	assert.Equal(t, models.PhaseVerifying, s.ProvisionPhase, "the phase shows where provisioning failed")
+	assert.NotZero(t, prov.getDestroyCalls(), "the instance is destroyed")
+}
+
+// healthVerifier records the URLs it is asked to check and reports them healthy
+type healthVerifier struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (v *healthVerifier) CheckHealth(ctx context.Context, url string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.urls = append(v.urls, url)
+	return nil
+}
+
+func (v *healthVerifier) getURLs() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]string(nil), v.urls...)
+}
+
+func TestService_CreateSession_Entrypoint_RecordsAPIPort(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	prov.createInstanceFn = func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+		return &provider.InstanceInfo{ProviderInstanceID: "inst-1", SSHHost: "192.168.1.100", SSHPort: 22, APIPort: 8000}, nil
+	}
+	verifier := &healthVerifier{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithHTTPVerifier(verifier),
+		WithAPICheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+		LaunchMode:     models.LaunchModeEntrypoint,
+		DockerImage:    "vllm/vllm-openai:latest",
+		ModelID:        "TinyLlama/TinyLlama-1.1B-Chat-v1.0",
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	assert.Equal(t, 8000, session.APIPort)
+
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, models.PhaseRunning, s.ProvisionPhase)
+	assert.Equal(t, "http://192.168.1.100:8000", s.APIEndpoint)
+}
+
+func TestService_ResumeVerification_Entrypoint(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	verifier := &healthVerifier{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithHTTPVerifier(verifier),
+		WithAPICheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-api",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		Status:         models.StatusProvisioning,
+		ProvisionPhase: models.PhaseVerifying,
+		VerifyDeadline: now.Add(time.Minute),
+		LaunchMode:     models.LaunchModeEntrypoint,
+		WorkloadType:   models.WorkloadLLM,
+		SSHHost:        "192.168.1.100",
+		SSHPort:        22,
+		APIPort:        8000,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(time.Hour),
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	require.True(t, svc.ResumeVerification(session))
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, "sess-api")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, models.PhaseRunning, s.ProvisionPhase)
+	assert.Equal(t, "http://192.168.1.100:8000", s.APIEndpoint)
+	assert.True(t, s.Progress.SSHVerifiedAt.IsZero(), "entrypoint sessions verify their API, not SSH")
+	assert.Equal(t, []string{"http://192.168.1.100:8000/health"}, verifier.getURLs())
+}
+
+func TestService_ResumeVerification_Refuses(t *testing.T) {
+	svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()))
+	deadline := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		session *models.Session
+	}{
+		{"no instance", &models.Session{Provider: "vastai", Status: models.StatusProvisioning, ProvisionPhase: models.PhaseProvisioning, VerifyDeadline: deadline}},
+		{"recorded before phases", &models.Session{Provider: "vastai", ProviderID: "inst-1", Status: models.StatusProvisioning}},
+		{"unknown provider", &models.Session{Provider: "gone", ProviderID: "inst-1", Status: models.StatusProvisioning, ProvisionPhase: models.PhaseBooting, VerifyDeadline: deadline}},
+		{"not provisioning", &models.Session{Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning, ProvisionPhase: models.PhaseRunning, VerifyDeadline: deadline}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, svc.ResumeVerification(tt.session))
+		})
+	}
+}
+
+func TestService_ResumeVerification_SkipsSessionsVerifyingHere(t *testing.T) {
+	store := newMockSessionStore()
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()))
+	session := &models.Session{ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusProvisioning,
+		ProvisionPhase: models.PhaseVerifying, VerifyDeadline: time.Now().Add(time.Minute)}
+	require.NoError(t, store.Create(context.Background(), session))
+
+	// A recovery sweep on a leader still verifying the session leaves it be
+	require.True(t, svc.startVerifying("sess-1"))
+	assert.True(t, svc.ResumeVerification(session))
+	assert.True(t, svc.WaitForVerificationComplete(time.Second))
+	stored, err := store.Get(context.Background(), "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusProvisioning, stored.Status)
+}
+
+// scriptRunner records the bootstrap scripts it runs
+type scriptRunner struct {
+	mu      sync.Mutex
+	scripts []string
+	output  string
+	err     error
+}
+
+func (r *scriptRunner) RunScript(ctx context.Context, host string, port int, user, privateKey, script string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scripts = append(r.scripts, script)
+	return r.output, r.err
+}
+
+func (r *scriptRunner) getScripts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.scripts...)
+}
+
+// eventLog records session events in memory
+type eventLog struct {
+	mu     sync.Mutex
+	events []*models.SessionEvent
+}
+
+func (l *eventLog) Record(ctx context.Context, event *models.SessionEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *eventLog) getEvents() []*models.SessionEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*models.SessionEvent(nil), l.events...)
+}
+
+func TestService_CreateSession_RunsBootstrapScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		runErr     error
+		wantReason string
+	}{
+		{"succeeds", nil, ""},
+		{"fails", errors.New("exit status 1"), "bootstrap script failed: exit status 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockSessionStore()
+			runner := &scriptRunner{output: "Cloning into 'repo'...", err: tt.runErr}
+			events := &eventLog{}
+			svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+				WithLogger(newTestLogger()),
+				WithSSHVerifier(NewMockSSHVerifier()),
+				WithSSHCheckInterval(10*time.Millisecond),
+				WithBootstrapRunner(runner),
+				WithEventRecorder(events))
+
+			ctx := context.Background()
+			session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+				ConsumerID:      "consumer-001",
+				OfferID:         "offer-1",
+				WorkloadType:    models.WorkloadInteractive,
+				ReservationHrs:  1,
+				BootstrapScript: "git clone https://example.com/repo.git",
+			}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+			require.NoError(t, err)
+			require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+			assert.Equal(t, []string{"git clone https://example.com/repo.git"}, runner.getScripts())
+			got := events.getEvents()
+			require.Len(t, got, 1)
+			assert.Equal(t, models.SessionEventBootstrap, got[0].Type)
+			assert.Equal(t, session.ID, got[0].SessionID)
+			assert.Equal(t, "Cloning into 'repo'...", got[0].Output)
+			assert.Equal(t, tt.wantReason, got[0].Reason)
+
+			// A failing script leaves the session running
+			s, err := store.Get(ctx, session.ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusRunning, s.Status)
+		})
+	}
+}
+
+func TestService_CreateSession_HFTokenInEntrypointEnv(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	prov.createInstanceFn = func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+		return &provider.InstanceInfo{ProviderInstanceID: "inst-1", SSHHost: "192.168.1.100", SSHPort: 22, APIPort: 8000}, nil
+	}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithHTTPVerifier(&healthVerifier{}),
+		WithAPICheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadLLMVLLM,
+		ReservationHrs: 1,
+		LaunchMode:     models.LaunchModeEntrypoint,
+		ModelID:        "meta-llama/Llama-3.1-8B-Instruct",
+		HFToken:        "hf_secret",
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	assert.Equal(t, map[string]string{"HF_TOKEN": "hf_secret", "HUGGING_FACE_HUB_TOKEN": "hf_secret"},
+		prov.lastCreateRequest.EnvVars)
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hf_secret", s.HFToken)
+}
+
+func TestService_CreateSession_HFTokenInBootstrap(t *testing.T) {
+	store := newMockSessionStore()
+	runner := &scriptRunner{output: "token is hf_secret"}
+	events := &eventLog{}
+	prov := newMockProvider("vastai")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithBootstrapRunner(runner),
+		WithEventRecorder(events))
+
+	_, err := svc.CreateSession(context.Background(), models.CreateSessionRequest{
+		ConsumerID:      "consumer-001",
+		OfferID:         "offer-1",
+		WorkloadType:    models.WorkloadInteractive,
+		ReservationHrs:  1,
+		BootstrapScript: "echo token is $HF_TOKEN",
+		HFToken:         "hf_secret",
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	// SSH-mode instances get the token from the bootstrap script only
+	assert.Empty(t, prov.lastCreateRequest.EnvVars)
+	assert.Equal(t, []string{
+		"export HF_TOKEN='hf_secret'\nexport HUGGING_FACE_HUB_TOKEN='hf_secret'\necho token is $HF_TOKEN",
+	}, runner.getScripts())
+	got := events.getEvents()
+	require.Len(t, got, 1)
+	assert.Equal(t, "token is REDACTED", got[0].Output)
+}
+
+func TestService_ResumeVerification_SkipsBootstrap(t *testing.T) {
+	host, port := sshBannerListener(t)
+	store := newMockSessionStore()
+	runner := &scriptRunner{}
+	events := &eventLog{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithBootstrapRunner(runner),
+		WithEventRecorder(events))
+
+	ctx := context.Background()
+	now := time.Now()
+	session := &models.Session{
+		ID:              "sess-resume",
+		Provider:        "vastai",
+		ProviderID:      "inst-1",
+		Status:          models.StatusProvisioning,
+		ProvisionPhase:  models.PhaseVerifying,
+		VerifyDeadline:  now.Add(time.Minute),
+		SSHHost:         host,
+		SSHPort:         port,
+		SSHUser:         "root",
+		BootstrapScript: "docker compose up -d",
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(time.Hour),
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	require.True(t, svc.ResumeVerification(session))
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	// Without the session key the script cannot run, which is recorded
+	assert.Empty(t, runner.getScripts())
+	got := events.getEvents()
+	require.Len(t, got, 1)
+	assert.Equal(t, models.SessionEventBootstrap, got[0].Type)
+	assert.Contains(t, got[0].Reason, "skipped")
+}
+
+func TestTruncateOutput(t *testing.T) {
+	assert.Equal(t, "short", truncateOutput("short", 10))
+	assert.Equal(t, "[truncated]\n56789", truncateOutput("0123456789", 5))
+}
+
+// warmupProvider is a mock provider whose instance has fixed container logs
+type warmupProvider struct {
+	*mockProvider
+	logs string
+}
+
+func (p *warmupProvider) GetInstanceLogs(ctx context.Context, instanceID string, tail int) (string, error) {
+	return p.logs, nil
+}
+
+// downVerifier reports every API unhealthy
+type downVerifier struct{}
+
+func (downVerifier) CheckHealth(ctx context.Context, url string) error {
+	return errors.New("connection refused")
+}
+
+func entrypointRequest() models.CreateSessionRequest {
+	return models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadLLMVLLM,
+		ReservationHrs: 1,
+		LaunchMode:     models.LaunchModeEntrypoint,
+		DockerImage:    "vllm/vllm-openai:latest",
+		ModelID:        "TinyLlama/TinyLlama-1.1B-Chat-v1.0",
+	}
+}
+
+func newWarmupProvider(logs string) *warmupProvider {
+	prov := &warmupProvider{mockProvider: newMockProvider("vastai"), logs: logs}
+	prov.createInstanceFn = func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+		return &provider.InstanceInfo{ProviderInstanceID: "inst-1", SSHHost: "192.168.1.100", SSHPort: 22, APIPort: 8000}, nil
+	}
+	return prov
+}
+
+func TestService_CreateSession_Entrypoint_WarmsUp(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newWarmupProvider("INFO Loading weights took 12.31 GB\nINFO Application startup complete.")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithHTTPVerifier(&healthVerifier{}),
+		WithAPICheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, entrypointRequest(),
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, models.PhaseRunning, s.ProvisionPhase)
+	assert.False(t, s.Progress.ImagePulledAt.IsZero())
+	assert.False(t, s.Progress.ContainerStartedAt.IsZero())
+	assert.False(t, s.Progress.WeightsLoadedAt.IsZero())
+	assert.False(t, s.Progress.CloudInitDoneAt.After(s.Progress.ImagePulledAt), "polling began when warm-up did")
+}
+
+func TestService_CreateSession_Entrypoint_WarmupFailsFast(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newWarmupProvider("INFO Loading safetensors checkpoint shards\ntorch.OutOfMemoryError: CUDA out of memory. Tried to allocate 2.00 GiB")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithHTTPVerifier(downVerifier{}),
+		WithAPICheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, entrypointRequest(),
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, s.Status)
+	assert.Equal(t, models.FailureWarmup, s.FailureCategory)
+	assert.Equal(t, "out of GPU memory", s.FailureDetail)
+	assert.Equal(t, models.PhaseWarmingUp, s.ProvisionPhase)
+	assert.NotZero(t, prov.getDestroyCalls())
+}
+
+func TestService_CreateSession_Entrypoint_TimeoutNamesStage(t *testing.T) {
+	tests := []struct {
+		name      string
+		logs      string
+		wantStage string
+	}{
+		{"image never pulled", "", "image_pull"},
+		{"weights never loaded", "INFO Starting to load model", "weights_load"},
+		{"API never answered", "INFO Loading weights took 3.2 GB", "api_startup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockSessionStore()
+			prov := newWarmupProvider(tt.logs)
+			prov.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+				return &provider.InstanceStatus{Status: "loading", SSHHost: "192.168.1.100", SSHPort: 22}, nil
+			}
+			svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+				WithLogger(newTestLogger()),
+				WithHTTPVerifier(downVerifier{}),
+				WithAPIVerifyTimeout(200*time.Millisecond),
+				WithAPICheckInterval(10*time.Millisecond))
+
+			ctx := context.Background()
+			session, err := svc.CreateSession(ctx, entrypointRequest(),
+				&models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+			require.NoError(t, err)
+			require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+			s, err := store.Get(ctx, session.ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusFailed, s.Status)
+			assert.Equal(t, models.FailureAPITimeout, s.FailureCategory)
+			assert.Equal(t, tt.wantStage, s.FailureDetail)
+			assert.Contains(t, s.Error, warmupStageReasons[tt.wantStage])
+		})
+	}
+}
+
+func TestService_CreateSession_Entrypoint_WarmupDisabled(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newWarmupProvider("INFO Loading weights took 12.31 GB")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithHTTPVerifier(&healthVerifier{}),
+		WithAPICheckInterval(10*time.Millisecond),
+		WithFeatureFlags(&mockFeatureFlags{flags: map[string]bool{FeatureEntrypointWarmup: false}}))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, entrypointRequest(),
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.True(t, s.Progress.WeightsLoadedAt.IsZero(), "logs are not read without warm-up")
+}
+
+// fakeVLLM serves /health from the start and lists its model once loaded
+type fakeVLLM struct {
+	mu          sync.Mutex
+	loaded      bool
+	completions int
+}
+
+func (f *fakeVLLM) setLoaded() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loaded = true
+}
+
+func (f *fakeVLLM) getCompletions() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.completions
+}
+
+func (f *fakeVLLM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/health":
+		w.WriteHeader(http.StatusOK)
+	case "/v1/models":
+		data := []map[string]string{}
+		if f.loaded {
+			data = append(data, map[string]string{"id": "TinyLlama/TinyLlama-1.1B-Chat-v1.0"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data})
+	case "/v1/completions":
+		f.completions++
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]string{{"text": "!"}}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newFakeVLLMProvider creates instances whose API is served by a fake vLLM
+func newFakeVLLMProvider(t *testing.T) (*mockProvider, *fakeVLLM) {
+	t.Helper()
+	api := &fakeVLLM{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	prov := newMockProvider("vastai")
+	prov.createInstanceFn = func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+		return &provider.InstanceInfo{ProviderInstanceID: "inst-1", SSHHost: host, SSHPort: 22, APIPort: port}, nil
+	}
+	return prov, api
+}
+
+func TestService_CreateSession_Entrypoint_WaitsForModel(t *testing.T) {
+	store := newMockSessionStore()
+	prov, api := newFakeVLLMProvider(t)
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithAPICheckInterval(10*time.Millisecond),
+		WithFeatureFlags(&mockFeatureFlags{flags: map[string]bool{FeatureEntrypointWarmup: false}}))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, entrypointRequest(),
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+
+	// /health answers, but the model is still loading
+	time.Sleep(100 * time.Millisecond)
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusProvisioning, s.Status)
+
+	api.setLoaded()
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	s, err = store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Zero(t, api.getCompletions(), "vLLM is probed with the model list by default")
+}
+
+func TestService_CreateSession_Entrypoint_CompletionProbe(t *testing.T) {
+	store := newMockSessionStore()
+	prov, api := newFakeVLLMProvider(t)
+	api.setLoaded()
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithAPICheckInterval(10*time.Millisecond),
+		WithFeatureFlags(&mockFeatureFlags{flags: map[string]bool{FeatureEntrypointWarmup: false}}),
+		WithReadinessProbes(map[provider.WorkloadType]ReadinessProbe{provider.WorkloadTypeVLLM: ReadinessCompletion}))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, entrypointRequest(),
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, 1, api.getCompletions())
+}
+
+func TestDefaultHTTPVerifier_CheckModel(t *testing.T) {
+	api := &fakeVLLM{}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	v := NewDefaultHTTPVerifier()
+	ctx := context.Background()
+
+	assert.ErrorContains(t, v.CheckModel(ctx, srv.URL, ""), "no models served yet")
+	api.setLoaded()
+	assert.NoError(t, v.CheckModel(ctx, srv.URL, ""))
+	assert.NoError(t, v.CheckModel(ctx, srv.URL, "TinyLlama/TinyLlama-1.1B-Chat-v1.0"))
+	assert.ErrorContains(t, v.CheckModel(ctx, srv.URL, "meta-llama/Llama-3.1-8B"), "not served yet")
+}
+
+// burnInRunner returns its results in turn, repeating the last
+type burnInRunner struct {
+	mu      sync.Mutex
+	results []string
+	calls   int
+}
+
+func (r *burnInRunner) RunBurnIn(ctx context.Context, host string, port int, user, privateKey string, duration time.Duration) (*sshverify.GPUBurnIn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	output := r.results[min(r.calls, len(r.results)-1)]
+	r.calls++
+	return sshverify.ParseGPUBurnIn(output), nil
+}
+
+func (r *burnInRunner) getCalls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+const (
+	healthyBurnIn   = "== gpus\n0, NVIDIA GeForce RTX 4090\n== load\ngpu 0 tflops 160.2 ok 1\n== throttle\n0, 70, Not Active, Not Active\n== xid"
+	unhealthyBurnIn = "== gpus\n0, NVIDIA GeForce RTX 4090\n== load\ngpu 0 tflops 158.7 ok 1\n== throttle\n== xid\n[ 91.2] NVRM: Xid (PCI:0000:01:00): 48, pid=4411, DBE (double bit error)"
+)
+
+func burnInRequest() models.CreateSessionRequest {
+	return models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-1",
+		WorkloadType:   models.WorkloadInteractive,
+		ReservationHrs: 1,
+		GPUBurnIn:      true,
+	}
+}
+
+func TestService_CreateSession_GPUBurnInPasses(t *testing.T) {
+	store := newMockSessionStore()
+	runner := &burnInRunner{results: []string{healthyBurnIn}}
+	events := &eventLog{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithGPUBurnInRunner(runner),
+		WithEventRecorder(events))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, burnInRequest(),
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", GPUCount: 1, PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, 1, runner.getCalls())
+
+	got := events.getEvents()
+	require.Len(t, got, 1)
+	assert.Equal(t, models.SessionEventBurnIn, got[0].Type)
+	assert.Equal(t, "GPU burn-in passed: 1 GPUs, GPU 0 160.2 TFLOPS, max 70C", got[0].Reason)
+	assert.Contains(t, got[0].Output, "tflops 160.2")
+}
+
+func TestService_CreateSession_GPUBurnInFailsAndRetries(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	runner := &burnInRunner{results: []string{unhealthyBurnIn, healthyBurnIn}}
+	inv := &mockInventory{alternatives: []models.GPUOffer{{ID: "offer-2", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.55, Available: true}}}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithGPUBurnInRunner(runner),
+		WithInventory(inv))
+
+	ctx := context.Background()
+	req := burnInRequest()
+	req.AutoRetry = true
+	req.MaxRetries = 2
+	session, err := svc.CreateSession(ctx, req,
+		&models.GPUOffer{ID: "offer-1", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.50})
+	require.NoError(t, err)
+
+	var failed *models.Session
+	require.Eventually(t, func() bool {
+		failed, err = store.Get(ctx, session.ID)
+		return err == nil && failed.RetryChildID != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	assert.Equal(t, models.FailureGPUUnhealthy, failed.FailureCategory)
+	assert.Contains(t, failed.FailureDetail, "Xid errors")
+	assert.Contains(t, failed.Error, "GPU burn-in failed")
+	assert.NotZero(t, prov.getDestroyCalls(), "the unhealthy instance is destroyed")
+
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	child, err := store.Get(ctx, failed.RetryChildID)
+	require.NoError(t, err)
+	assert.Equal(t, "offer-2", child.OfferID)
+	assert.True(t, child.GPUBurnIn)
+	assert.Equal(t, models.StatusRunning, child.Status)
+}
+
+// gpuListingVerifier is a mock SSH verifier that lists the GPUs of each
+// instance it is asked about in turn, repeating the last
+type gpuListingVerifier struct {
+	*MockSSHVerifier
+	mu    sync.Mutex
+	gpus  [][]*sshverify.GPUStatus
+	calls int
+}
+
+func (v *gpuListingVerifier) ListGPUs(ctx context.Context, host string, port int, user, privateKey string) ([]*sshverify.GPUStatus, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	gpus := v.gpus[min(v.calls, len(v.gpus)-1)]
+	v.calls++
+	return gpus, nil
+}
+
+func (v *gpuListingVerifier) getCalls() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.calls
+}
+
+func TestService_CreateSession_GPUCheckPasses(t *testing.T) {
+	store := newMockSessionStore()
+	verifier := &gpuListingVerifier{
+		MockSSHVerifier: NewMockSSHVerifier(),
+		gpus:            [][]*sshverify.GPUStatus{{{Name: "NVIDIA GeForce RTX 4090", MemoryTotalMB: 24564}}},
+	}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(verifier),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID: "consumer-001", OfferID: "offer-1", WorkloadType: models.WorkloadInteractive, ReservationHrs: 1,
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", GPUType: "RTX 4090", GPUCount: 1, VRAM: 24, PricePerHour: 0.50})
+	require.NoError(t, err)
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	assert.Equal(t, 24, s.VRAM)
+	assert.Equal(t, 1, verifier.getCalls())
+}
+
+func TestService_CreateSession_GPUMismatchFailsAndRetries(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	verifier := &gpuListingVerifier{
+		MockSSHVerifier: NewMockSSHVerifier(),
+		gpus: [][]*sshverify.GPUStatus{
+			{{Name: "NVIDIA GeForce RTX 3090", MemoryTotalMB: 24576}},
+			{{Name: "NVIDIA GeForce RTX 4090", MemoryTotalMB: 24564}},
+		},
+	}
+	inv := &mockInventory{alternatives: []models.GPUOffer{{ID: "offer-2", Provider: "vastai", GPUType: "RTX 4090", GPUCount: 1, VRAM: 24, PricePerHour: 0.55, Available: true}}}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(verifier),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithInventory(inv))
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID: "consumer-001", OfferID: "offer-1", WorkloadType: models.WorkloadInteractive, ReservationHrs: 1,
+		AutoRetry: true, MaxRetries: 2,
+	}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", GPUType: "RTX 4090", GPUCount: 1, VRAM: 24, PricePerHour: 0.50})
+	require.NoError(t, err)
+
+	var failed *models.Session
+	require.Eventually(t, func() bool {
+		failed, err = store.Get(ctx, session.ID)
+		return err == nil && failed.RetryChildID != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	assert.Equal(t, models.FailureGPUMismatch, failed.FailureCategory)
+	assert.Equal(t, "GPU 0 is NVIDIA GeForce RTX 3090, advertised RTX 4090", failed.FailureDetail)
+	assert.Contains(t, failed.Error, "GPUs do not match the offer")
+	assert.NotZero(t, prov.getDestroyCalls(), "the misreported instance is destroyed")
+
+	require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+	child, err := store.Get(ctx, failed.RetryChildID)
+	require.NoError(t, err)
+	assert.Equal(t, "offer-2", child.OfferID)
+	assert.Equal(t, models.StatusRunning, child.Status)
+}
@@ -0,0 +1,184 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// HandlePreemption checks whether a running interruptible session was
+// reclaimed by its provider (e.g. outbid) and fails it over if so.
+func (s *Service) HandlePreemption(ctx context.Context, session *models.Session) (bool, error) {
+	if !session.Interruptible || session.ProviderID == "" || session.Status != models.StatusRunning || session.Rebooting(s.now()) {
+		return false, nil
+	}
+
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return false, err
+	}
+
+	status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get instance status: %w", err)
+	}
+	if !status.Preempted {
+		return false, nil
+	}
+
+	reason := "instance reclaimed by provider"
+	if status.Error != "" {
+		reason = status.Error
+	}
+	return s.FailoverSession(ctx, session.ID, reason)
+}
+
+// FailoverSession handles a running session whose instance was preempted or
+// terminated outside our control. The session is marked preempted, whatever
+// is left of its instance destroyed, and, if auto-retry is enabled, a
+// replacement is provisioned from the consumer's original request. Sessions
+// that are no longer running, or are being rebooted, are left alone, so the lifecycle manager and
+// reconciler can both report the same loss safely. Errors last found in the
+// instance's kernel log are added to reason as the cause.
+func (s *Service) FailoverSession(ctx context.Context, sessionID, reason string) (bool, error) {
+	// Serialize with destroys so a consumer's own teardown is never
+	// mistaken for a preemption
+	lock := s.getDestroyLock(sessionID)
+	lock.Lock()
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		lock.Unlock()
+		return false, err
+	}
+	// Another server may have started rebooting the instance since the
+	// caller looked
+	if session.Status != models.StatusRunning || session.Rebooting(s.now()) {
+		lock.Unlock()
+		return false, nil
+	}
+	if diagnosis := s.kernelDiagnosis(sessionID); diagnosis != "" {
+		reason = withKernelCause(reason, diagnosis)
+	}
+
+	s.logger.Warn("session preempted",
+		slog.String("session_id", session.ID),
+		slog.String("provider", session.Provider),
+		slog.String("provider_id", session.ProviderID),
+		slog.String("reason", reason))
+	metrics.RecordSessionPreempted(session.Provider)
+
+	session.FailureCategory = models.FailurePreempted
+	s.terminateSession(ctx, session, models.StatusPreempted, reason, models.WebhookEventSessionPreempted)
+	lock.Unlock()
+	s.cleanupDestroyLock(sessionID)
+
+	if session.AutoRetry && session.RetryCount < session.MaxRetries && s.inventory != nil {
+		s.triggerAsyncRetry(session, retryRequestFromSession(session))
+	}
+
+	return true, nil
+}
+
+// TakeReplacementKey returns the SSH private key of a running failover
+// replacement and forgets it, so it can be fetched only once. It returns ""
+// if the session is not a replacement, or its key was already taken or lost
+// in a restart.
+func (s *Service) TakeReplacementKey(sessionID string) string {
+	s.replacementKeysMu.Lock()
+	defer s.replacementKeysMu.Unlock()
+	key := s.replacementKeys[sessionID]
+	delete(s.replacementKeys, sessionID)
+	return key
+}
+
+// FailLostSession fails a running session whose instance stopped or vanished
+// outside our control without being failed over, such as a reconciler
+// zombie. Whatever is left of the instance is destroyed, the final cost
+// recorded and the consumer notified with session.failed. Sessions that are no
+// longer running or are being rebooted, or whose instance the provider
+// reports running again, are left alone. Errors last found in the instance's kernel
+// log are added to reason as the cause.
+func (s *Service) FailLostSession(ctx context.Context, sessionID string, category models.FailureCategory, detail, reason string) (bool, error) {
+	lock := s.getDestroyLock(sessionID)
+	lock.Lock()
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		lock.Unlock()
+		return false, err
+	}
+	// Another server may have started rebooting the instance since the
+	// caller looked
+	if session.Status != models.StatusRunning || session.Rebooting(s.now()) {
+		lock.Unlock()
+		return false, nil
+	}
+	// The instance may have come back while waiting for the lock, such as
+	// at the end of a reboot
+	if s.instanceRunning(ctx, session) {
+		lock.Unlock()
+		s.logger.Info("instance of lost session is running again, leaving it",
+			slog.String("session_id", session.ID),
+			slog.String("provider_id", session.ProviderID))
+		return false, nil
+	}
+	if diagnosis := s.kernelDiagnosis(sessionID); diagnosis != "" {
+		reason = withKernelCause(reason, diagnosis)
+	}
+
+	s.logger.Warn("session lost",
+		slog.String("session_id", session.ID),
+		slog.String("provider", session.Provider),
+		slog.String("provider_id", session.ProviderID),
+		slog.String("reason", reason))
+
+	s.failSession(ctx, session, category, detail, reason)
+	lock.Unlock()
+	s.cleanupDestroyLock(sessionID)
+
+	return true, nil
+}
+
+// instanceRunning reports whether the provider currently reports a session's
+// instance running
+func (s *Service) instanceRunning(ctx context.Context, session *models.Session) bool {
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return false
+	}
+	status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+	return err == nil && status.Running
+}
+
+// retryRequestFromSession rebuilds the consumer's create request from the
+// session it produced, since requests themselves are not stored. The bid is
+// left unset so the replacement bids the new offer's minimum.
+func retryRequestFromSession(session *models.Session) models.CreateSessionRequest {
+	return models.CreateSessionRequest{
+		ConsumerID:      session.ConsumerID,
+		OfferID:         session.OfferID,
+		WorkloadType:    session.WorkloadType,
+		ReservationHrs:  session.ReservationHrs,
+		IdleThreshold:   session.IdleThreshold,
+		IdleGPUUtilPct:  session.IdleGPUUtilPct,
+		StoragePolicy:   session.StoragePolicy,
+		LaunchMode:      session.LaunchMode,
+		DockerImage:     session.DockerImage,
+		ModelID:         session.ModelID,
+		ExposedPorts:    session.ExposedPorts,
+		Quantization:    session.Quantization,
+		TemplateHashID:  session.TemplateHashID,
+		DiskGB:          session.DiskGB,
+		AutoRetry:       session.AutoRetry,
+		MaxRetries:      session.MaxRetries,
+		RetryScope:      session.RetryScope,
+		GroupID:         session.GroupID,
+		BootstrapScript: session.BootstrapScript,
+		GPUBurnIn:       session.GPUBurnIn,
+		HFToken:         session.HFToken,
+		OnStartCmd:      session.OnStartCmd,
+		CloudInit:       session.CloudInit,
+	}
+}
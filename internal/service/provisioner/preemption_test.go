@@ -0,0 +1,359 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockInventory implements InventoryFinder for auto-retry tests
+type mockInventory struct {
+	mu           sync.Mutex
+	alternatives []models.GPUOffer
+	original     *models.GPUOffer
+}
+
+func (m *mockInventory) FindComparableOffers(ctx context.Context, original *models.GPUOffer, scope string, excludeIDs []string, excludeMachineIDs []string) ([]models.GPUOffer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.original = original
+	return m.alternatives, nil
+}
+
+func (m *mockInventory) GetOffer(ctx context.Context, offerID string) (*models.GPUOffer, error) {
+	return nil, errors.New("not cached")
+}
+
+func (m *mockInventory) RecordOfferFailure(offerID, provider, gpuType, machineID, failureType, reason string) {
+}
+
+func (m *mockInventory) EvictOffer(offerID string) {}
+
+func bidOffer(id string, minBid float64) *models.GPUOffer {
+	return &models.GPUOffer{
+		ID:            id,
+		Provider:      "vastai",
+		GPUType:       "RTX4090",
+		GPUCount:      1,
+		PricePerHour:  minBid,
+		Available:     true,
+		Interruptible: true,
+		MinBid:        minBid,
+	}
+}
+
+func TestService_CreateSession_Bid(t *testing.T) {
+	tests := []struct {
+		name      string
+		offer     *models.GPUOffer
+		bid       float64
+		wantBid   float64
+		wantError bool
+	}{
+		{name: "defaults to min bid", offer: bidOffer("vastai-bid-1", 0.20), wantBid: 0.20},
+		{name: "explicit bid", offer: bidOffer("vastai-bid-1", 0.20), bid: 0.25, wantBid: 0.25},
+		{name: "bid below minimum", offer: bidOffer("vastai-bid-1", 0.20), bid: 0.10, wantError: true},
+		{name: "bid on on-demand offer", offer: &models.GPUOffer{ID: "vastai-1", Provider: "vastai", GPUCount: 1, PricePerHour: 0.50}, bid: 0.30, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockSessionStore()
+			prov := newMockProvider("vastai")
+			svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+
+			req := models.CreateSessionRequest{
+				ConsumerID:     "consumer-001",
+				OfferID:        tt.offer.ID,
+				WorkloadType:   models.WorkloadLLM,
+				ReservationHrs: 1,
+				BidPrice:       tt.bid,
+			}
+			session, err := svc.CreateSession(context.Background(), req, tt.offer)
+
+			if tt.wantError {
+				var bidErr *InvalidBidError
+				require.ErrorAs(t, err, &bidErr)
+				assert.Equal(t, 0, prov.createCalls)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, session.Interruptible)
+			assert.Equal(t, tt.wantBid, session.BidPrice)
+			assert.Equal(t, tt.wantBid, session.PricePerHour)
+			assert.Equal(t, tt.wantBid, prov.lastCreateRequest.BidPrice)
+		})
+	}
+}
+
+func TestService_CreateSession_OnDemandSendsNoBid(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+
+	offer := &models.GPUOffer{ID: "vastai-1", Provider: "vastai", GPUCount: 1, PricePerHour: 0.50, MinBid: 0.20}
+	session, err := svc.CreateSession(context.Background(), models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        offer.ID,
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}, offer)
+
+	require.NoError(t, err)
+	assert.False(t, session.Interruptible)
+	assert.Zero(t, prov.lastCreateRequest.BidPrice)
+}
+
+func TestService_HandlePreemption(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	prov.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "stopped", Preempted: true, Error: "outbid"}, nil
+	}
+	inv := &mockInventory{alternatives: []models.GPUOffer{*bidOffer("vastai-bid-2", 0.22)}}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithInventory(inv))
+
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-bid",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		OfferID:        "vastai-bid-1",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusRunning,
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 2,
+		PricePerHour:   0.20,
+		Interruptible:  true,
+		BidPrice:       0.20,
+		AutoRetry:      true,
+		MaxRetries:     3,
+		RetryScope:     "same_gpu",
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(2 * time.Hour),
+	}
+	require.NoError(t, store.Create(context.Background(), session))
+
+	preempted, err := svc.HandlePreemption(context.Background(), session)
+	require.NoError(t, err)
+	assert.True(t, preempted)
+
+	failed, err := store.Get(context.Background(), "sess-bid")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPreempted, failed.Status)
+	assert.Equal(t, "outbid", failed.Error)
+	assert.NotEmpty(t, failed.RetryChildID)
+	assert.Equal(t, 1, prov.getDestroyCalls())
+
+	// Replacement stays on the bid market
+	require.NotNil(t, inv.original)
+	assert.True(t, inv.original.Interruptible)
+
+	child, err := store.Get(context.Background(), failed.RetryChildID)
+	require.NoError(t, err)
+	assert.Equal(t, "sess-bid", child.RetryParentID)
+	assert.Equal(t, "vastai-bid-2", child.OfferID)
+	assert.True(t, child.Interruptible)
+	assert.Equal(t, 0.22, child.BidPrice)
+}
+
+func TestService_HandlePreemption_NotPreempted(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+
+	session := &models.Session{
+		ID:            "sess-bid",
+		Provider:      "vastai",
+		ProviderID:    "inst-1",
+		Status:        models.StatusRunning,
+		Interruptible: true,
+	}
+	require.NoError(t, store.Create(context.Background(), session))
+
+	preempted, err := svc.HandlePreemption(context.Background(), session)
+	require.NoError(t, err)
+	assert.False(t, preempted)
+
+	got, err := store.Get(context.Background(), "sess-bid")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, got.Status)
+	assert.Equal(t, 0, prov.getDestroyCalls())
+
+	// On-demand sessions are never polled
+	session.Interruptible = false
+	preempted, err = svc.HandlePreemption(context.Background(), session)
+	require.NoError(t, err)
+	assert.False(t, preempted)
+	assert.Equal(t, 1, prov.statusCalls)
+}
+
+// failoverNotifier records session events and failover notices
+type failoverNotifier struct {
+	recordingNotifier
+	failovers []models.SessionFailover
+}
+
+func (f *failoverNotifier) NotifyFailover(ctx context.Context, failover models.SessionFailover) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failovers = append(f.failovers, failover)
+}
+
+func (f *failoverNotifier) getFailovers() []models.SessionFailover {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]models.SessionFailover(nil), f.failovers...)
+}
+
+func TestService_FailoverSession(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	prov.features = map[provider.ProviderFeature]bool{provider.FeatureCloudInit: true}
+	inv := &mockInventory{alternatives: []models.GPUOffer{
+		{ID: "vastai-2", Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: 0.45, Available: true},
+	}}
+	notifier := &failoverNotifier{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithInventory(inv),
+		WithNotifier(notifier),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHVerifyTimeout(time.Second),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-lost",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		OfferID:        "vastai-1",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusRunning,
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 2,
+		PricePerHour:   0.45,
+		AutoRetry:      true,
+		MaxRetries:     2,
+		RetryScope:     "same_gpu",
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(2 * time.Hour),
+		OnStartCmd:     "ollama serve",
+		CloudInit:      &models.CloudInitConfig{RunCmd: []string{"echo ready"}},
+	}
+	require.NoError(t, store.Create(context.Background(), session))
+
+	failedOver, err := svc.FailoverSession(context.Background(), "sess-lost", "instance terminated outside our control")
+	require.NoError(t, err)
+	assert.True(t, failedOver)
+
+	preempted, err := store.Get(context.Background(), "sess-lost")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPreempted, preempted.Status)
+	assert.Equal(t, "instance terminated outside our control", preempted.Error)
+	require.NotEmpty(t, preempted.RetryChildID)
+	assert.Contains(t, notifier.getEvents(), models.WebhookEventSessionPreempted)
+
+	// The consumer is told where to reconnect once the replacement is up
+	require.Eventually(t, func() bool {
+		return len(notifier.getFailovers()) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+	failover := notifier.getFailovers()[0]
+	assert.Equal(t, "sess-lost", failover.PreemptedSessionID)
+	assert.Equal(t, preempted.RetryChildID, failover.Session.ID)
+	assert.Equal(t, models.StatusRunning, failover.Session.Status)
+	assert.NotEmpty(t, failover.Session.SSHHost)
+
+	// The replacement starts up the way the consumer asked for
+	replacement, err := store.Get(context.Background(), failover.Session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ollama serve", replacement.OnStartCmd)
+	assert.Equal(t, session.CloudInit, replacement.CloudInit)
+	assert.Equal(t, "ollama serve", prov.lastCreateRequest.OnStartCmd)
+	assert.Equal(t, session.CloudInit, prov.lastCreateRequest.CloudInit)
+
+	// The replacement's key is handed out once, not sent in the event
+	assert.Contains(t, svc.TakeReplacementKey(failover.Session.ID), "PRIVATE KEY")
+	assert.Empty(t, svc.TakeReplacementKey(failover.Session.ID))
+
+	// A second report of the same loss is a no-op
+	failedOver, err = svc.FailoverSession(context.Background(), "sess-lost", "instance preempted by provider")
+	require.NoError(t, err)
+	assert.False(t, failedOver)
+}
+
+func TestService_FailLostSession(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	notifier := &failoverNotifier{}
+	stopped := false
+	prov.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		if stopped {
+			return &provider.InstanceStatus{Status: "exited"}, nil
+		}
+		return &provider.InstanceStatus{Status: "running", Running: true}, nil
+	}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithNotifier(notifier))
+
+	now := time.Now()
+	require.NoError(t, store.Create(context.Background(), &models.Session{
+		ID:             "sess-zombie",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		ProviderID:     "inst-1",
+		Status:         models.StatusRunning,
+		ReservationHrs: 2,
+		PricePerHour:   0.45,
+		AutoRetry:      true,
+		MaxRetries:     2,
+		CreatedAt:      now.Add(-time.Hour),
+		ExpiresAt:      now.Add(time.Hour),
+	}))
+	svc.setKernelDiagnosis("sess-zombie", "GPU Xid error 79")
+
+	// An instance running again by the time the loss is handled, such as
+	// after a reboot, is left alone
+	failed, err := svc.FailLostSession(context.Background(), "sess-zombie",
+		models.FailureInstanceStopped, "exited", "Instance stopped on provider: exited")
+	require.NoError(t, err)
+	assert.False(t, failed)
+	assert.Zero(t, prov.getDestroyCalls())
+
+	stopped = true
+	failed, err = svc.FailLostSession(context.Background(), "sess-zombie",
+		models.FailureInstanceStopped, "exited", "Instance stopped on provider: exited")
+	require.NoError(t, err)
+	assert.True(t, failed)
+
+	session, err := store.Get(context.Background(), "sess-zombie")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, session.Status)
+	assert.Equal(t, models.FailureInstanceStopped, session.FailureCategory)
+	assert.Equal(t, "exited", session.FailureDetail)
+	assert.Equal(t, "Instance stopped on provider: exited — cause (kernel log): GPU Xid error 79", session.Error)
+	assert.False(t, session.StoppedAt.IsZero())
+	assert.Empty(t, session.RetryChildID, "lost sessions are not retried")
+	assert.Equal(t, 1, prov.getDestroyCalls())
+	assert.Contains(t, notifier.getEvents(), models.WebhookEventSessionFailed)
+
+	// A second report of the same loss is a no-op
+	failed, err = svc.FailLostSession(context.Background(), "sess-zombie",
+		models.FailureInstanceVanished, "", "Instance not found on provider during reconciliation")
+	require.NoError(t, err)
+	assert.False(t, failed)
+}
@@ -0,0 +1,164 @@
+package provisioner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ReadinessProbe is how a workload's API is judged ready to serve once its
+// health route answers
+type ReadinessProbe string
+
+const (
+	// ReadinessHealth trusts the health route alone
+	ReadinessHealth ReadinessProbe = "health"
+	// ReadinessModels requires /v1/models to list the session's model
+	ReadinessModels ReadinessProbe = "models"
+	// ReadinessCompletion requires a one-token completion to succeed
+	ReadinessCompletion ReadinessProbe = "completion"
+)
+
+// defaultReadinessProbes apply to workload types not configured otherwise.
+// vLLM answers /health before the model is loaded, so the model list is
+// checked too; SGLang's /health_generate already runs a generation.
+var defaultReadinessProbes = map[provider.WorkloadType]ReadinessProbe{
+	provider.WorkloadTypeVLLM: ReadinessModels,
+}
+
+// ModelVerifier is implemented by HTTP verifiers that can check an
+// OpenAI-compatible server is serving a model, not just answering. Verifiers
+// without it are trusted on the health route alone.
+type ModelVerifier interface {
+	// CheckModel checks /v1/models lists modelID, or any model if modelID is empty
+	CheckModel(ctx context.Context, baseURL, modelID string) error
+	// CheckCompletion checks a one-token completion from modelID succeeds
+	CheckCompletion(ctx context.Context, baseURL, modelID string) error
+}
+
+// WithReadinessProbes sets the readiness probe of each workload type, over
+// the defaults
+func WithReadinessProbes(probes map[provider.WorkloadType]ReadinessProbe) Option {
+	return func(s *Service) {
+		s.readinessProbes = make(map[provider.WorkloadType]ReadinessProbe, len(defaultReadinessProbes)+len(probes))
+		for t, p := range defaultReadinessProbes {
+			s.readinessProbes[t] = p
+		}
+		for t, p := range probes {
+			s.readinessProbes[t] = p
+		}
+	}
+}
+
+// readinessProbe returns the probe for a session's workload
+func (s *Service) readinessProbe(session *models.Session) ReadinessProbe {
+	probes := s.readinessProbes
+	if probes == nil {
+		probes = defaultReadinessProbes
+	}
+	if p, ok := probes[providerWorkloadType(session.WorkloadType)]; ok {
+		return p
+	}
+	return ReadinessHealth
+}
+
+// checkReady runs the session's readiness probe against a workload API whose
+// health route has answered
+func (s *Service) checkReady(ctx context.Context, session *models.Session, baseURL string) error {
+	mv, ok := s.httpVerifier.(ModelVerifier)
+	if !ok {
+		return nil
+	}
+	switch s.readinessProbe(session) {
+	case ReadinessModels:
+		return mv.CheckModel(ctx, baseURL, session.ModelID)
+	case ReadinessCompletion:
+		return mv.CheckCompletion(ctx, baseURL, session.ModelID)
+	default:
+		return nil
+	}
+}
+
+// CheckModel checks /v1/models lists modelID, or any model if modelID is empty
+func (v *DefaultHTTPVerifier) CheckModel(ctx context.Context, baseURL, modelID string) error {
+	_, err := v.servedModel(ctx, baseURL, modelID)
+	return err
+}
+
+// CheckCompletion checks a one-token completion succeeds. Without a modelID
+// the first model served is asked.
+func (v *DefaultHTTPVerifier) CheckCompletion(ctx context.Context, baseURL, modelID string) error {
+	model, err := v.servedModel(ctx, baseURL, modelID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]any{"model": model, "prompt": "Hello", "max_tokens": 1})
+	if err != nil {
+		return fmt.Errorf("failed to encode completion request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(baseURL, "/")+"/v1/completions", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("completion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("completion failed with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// servedModel returns modelID if /v1/models lists it, or the first model
+// listed if modelID is empty
+func (v *DefaultHTTPVerifier) servedModel(ctx context.Context, baseURL, modelID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(baseURL, "/")+"/v1/models", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("model list request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("model list failed with status: %d", resp.StatusCode)
+	}
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&list); err != nil {
+		return "", fmt.Errorf("failed to decode model list: %w", err)
+	}
+	if len(list.Data) == 0 {
+		return "", fmt.Errorf("no models served yet")
+	}
+	if modelID == "" {
+		return list.Data[0].ID, nil
+	}
+	for _, m := range list.Data {
+		if m.ID == modelID {
+			return modelID, nil
+		}
+	}
+	return "", fmt.Errorf("model %s not served yet", modelID)
+}
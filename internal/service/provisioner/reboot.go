@@ -0,0 +1,249 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/logging"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// rebootSettleTime is how long after a provider reboot an instance that
+// answers SSH counts as rebooted, in case it restarted between two polls
+const rebootSettleTime = 30 * time.Second
+
+// rebootCommand reboots an instance a second after the SSH session that
+// ran it returns, so the command itself succeeds
+const rebootCommand = `nohup sh -c 'sleep 1; reboot || sudo -n reboot' >/dev/null 2>&1 &`
+
+// sshRebooter reboots instances with the SSH executor
+type sshRebooter struct{}
+
+func (sshRebooter) Reboot(ctx context.Context, host string, port int, user, privateKey string) error {
+	executor := sshverify.NewExecutor(
+		sshverify.WithExecutorConnectTimeout(15*time.Second),
+		sshverify.WithExecutorCommandTimeout(15*time.Second),
+	)
+	conn, err := executor.Connect(ctx, host, port, user, privateKey)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, stderr, err := executor.RunCommand(ctx, conn, rebootCommand)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, stderr)
+	}
+	return nil
+}
+
+// Ways of rebooting an instance, most preferred first
+const (
+	rebootViaProvider = "provider"    // provider.RebootProvider
+	rebootViaSSH      = "ssh"         // Running reboot, with the session's key
+	rebootPowerCycle  = "power_cycle" // provider.PowerController stop and start
+)
+
+// RebootSession reboots the instance of a running session in place and
+// waits until it answers SSH again. Providers implementing
+// provider.RebootProvider reboot it themselves. Otherwise it is rebooted
+// over SSH, which needs the session's private key as the server does not
+// keep it, or failing that stopped and started through
+// provider.PowerController. With a key, SSH is verified by logging in;
+// without, by the SSH banner.
+func (s *Service) RebootSession(ctx context.Context, sessionID, privateKey string) (*models.Session, error) {
+	// Serialize with destroys, failovers and pauses of the session
+	lock := s.getDestroyLock(sessionID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if session.Status != models.StatusRunning || session.ProviderID == "" {
+		return nil, &SessionNotRunningError{ID: sessionID, Status: session.Status}
+	}
+	if s.SavingWorkspace(sessionID) {
+		return nil, &SessionNotRunningError{ID: sessionID, Status: models.StatusStopping}
+	}
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return nil, &ProviderNotFoundError{Name: session.Provider}
+	}
+	rebootedAt := s.now()
+	deadline := time.Now().Add(s.rebootTimeout)
+	// Mark the session rebooting where every server's reconciler sees it
+	if err := s.store.SetRebootingUntil(ctx, sessionID, rebootedAt.Add(s.rebootTimeout)); err != nil {
+		return nil, fmt.Errorf("failed to mark session rebooting: %w", err)
+	}
+	defer func() {
+		if err := s.store.SetRebootingUntil(context.WithoutCancel(ctx), sessionID, time.Time{}); err != nil {
+			s.logger.Error("failed to clear session reboot mark",
+				slog.String("session_id", sessionID),
+				slog.String("error", err.Error()))
+		}
+	}()
+	rebooter, viaProvider := provider.As[provider.RebootProvider](prov)
+	power, canPowerCycle := provider.As[provider.PowerController](prov)
+	var method string
+	// settle is how soon an instance answering SSH counts as rebooted
+	// without having been seen down
+	var settle time.Duration
+	switch {
+	case viaProvider:
+		method, settle = rebootViaProvider, rebootSettleTime
+		err = rebooter.RebootInstance(ctx, session.ProviderID)
+	case privateKey != "":
+		// An SSH reboot is only known to have happened once SSH goes down
+		method, settle = rebootViaSSH, s.rebootTimeout
+		err = s.sshRebooter.Reboot(s.pinHostKey(ctx, session),
+			session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+	case canPowerCycle:
+		method = rebootPowerCycle
+		err = s.powerCycle(ctx, prov, power, session, deadline)
+	default:
+		return nil, &RebootNotSupportedError{Provider: session.Provider}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reboot instance: %w", err)
+	}
+	s.logger.Info("session rebooting",
+		slog.String("session_id", sessionID),
+		slog.String("provider", session.Provider),
+		slog.String("method", method))
+
+	if err := s.awaitRebooted(ctx, prov, session, privateKey, settle, deadline); err != nil {
+		return nil, err
+	}
+	// Don't revive a session something else ended meanwhile
+	if current, err := s.store.Get(ctx, sessionID); err == nil && current.Status != models.StatusRunning {
+		return nil, &SessionNotRunningError{ID: sessionID, Status: current.Status}
+	}
+	if err := s.store.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	s.logger.Info("session rebooted",
+		slog.String("session_id", sessionID),
+		slog.Duration("took", s.now().Sub(rebootedAt)))
+	logging.Audit(ctx, "session_rebooted",
+		"session_id", session.ID,
+		"consumer_id", session.ConsumerID,
+		"provider", session.Provider,
+		"provider_id", session.ProviderID,
+		"method", method)
+	return session, nil
+}
+
+// powerCycle stops a session's instance, waits until the provider no longer
+// reports it running or the deadline passes, and starts it again. The
+// instance is left stopped if it cannot be started, for the reconciler to
+// find.
+func (s *Service) powerCycle(ctx context.Context, prov provider.Provider, power provider.PowerController, session *models.Session, deadline time.Time) error {
+	if err := power.StopInstance(ctx, session.ProviderID); err != nil {
+		return fmt.Errorf("failed to stop instance: %w", err)
+	}
+
+	timeout := time.NewTimer(time.Until(deadline))
+	defer timeout.Stop()
+	ticker := time.NewTicker(s.sshCheckInterval)
+	defer ticker.Stop()
+	for stopped := false; !stopped; {
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			return &RebootTimeoutError{SessionID: session.ID, Timeout: s.rebootTimeout}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+		if errors.Is(err, provider.ErrInstanceNotFound) {
+			return err
+		}
+		stopped = err == nil && !status.Running
+	}
+
+	if err := power.StartInstance(ctx, session.ProviderID); err != nil {
+		s.logger.Error("failed to start instance after stopping it to reboot",
+			slog.String("session_id", session.ID),
+			slog.String("provider_id", session.ProviderID),
+			slog.String("error", err.Error()))
+		return fmt.Errorf("failed to start instance: %w", err)
+	}
+	return nil
+}
+
+// awaitRebooted polls a rebooting session's instance until it answers SSH
+// after having gone down, or after settle, updating the session's SSH
+// details from the provider. It gives up at the deadline.
+func (s *Service) awaitRebooted(ctx context.Context, prov provider.Provider, session *models.Session, privateKey string, settle time.Duration, deadline time.Time) error {
+	start := time.Now()
+	timeout := time.NewTimer(time.Until(deadline))
+	defer timeout.Stop()
+	ticker := time.NewTicker(s.sshCheckInterval)
+	defer ticker.Stop()
+
+	wentDown := false
+	for {
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			return &RebootTimeoutError{SessionID: session.ID, Timeout: s.rebootTimeout}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+		if errors.Is(err, provider.ErrInstanceNotFound) {
+			return err
+		}
+		if err != nil {
+			s.logger.Debug("status check of rebooting instance failed",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		if !status.Running || status.SSHHost == "" {
+			wentDown = true
+			continue
+		}
+		updateSSHEndpoint(session, status)
+
+		if err := s.checkSSH(ctx, session, privateKey); err != nil {
+			wentDown = true
+			continue
+		}
+		if wentDown || time.Since(start) >= settle {
+			return nil
+		}
+	}
+}
+
+// checkSSH checks a session's instance answers SSH: by logging in with
+// privateKey if given, or else by reading its SSH banner
+func (s *Service) checkSSH(ctx context.Context, session *models.Session, privateKey string) error {
+	if privateKey == "" {
+		return checkSSHBanner(ctx, session.SSHHost, session.SSHPort)
+	}
+	return s.sshVerifier.VerifyOnce(s.pinHostKey(ctx, session), session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+}
+
+// updateSSHEndpoint copies an instance's SSH details onto its session,
+// which providers may change when the instance restarts
+func updateSSHEndpoint(session *models.Session, status *provider.InstanceStatus) {
+	session.SSHHost = status.SSHHost
+	if status.SSHPort != 0 {
+		session.SSHPort = status.SSHPort
+	}
+	if status.SSHUser != "" {
+		session.SSHUser = status.SSHUser
+	}
+	if session.APIEndpoint != "" && session.APIPort > 0 {
+		session.APIEndpoint = fmt.Sprintf("http://%s:%d", session.SSHHost, session.APIPort)
+	}
+}
@@ -0,0 +1,201 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rebootingHost fakes an instance that refuses SSH for a few polls after
+// it is rebooted, whether by its provider or over SSH
+type rebootingHost struct {
+	*mockProvider
+	mu        sync.Mutex
+	downPolls int
+	reboots   []string
+}
+
+func newRebootingHost() *rebootingHost {
+	return &rebootingHost{mockProvider: newMockProvider("vastai")}
+}
+
+func (h *rebootingHost) reboot(via string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.downPolls = 2
+	h.reboots = append(h.reboots, via)
+}
+
+func (h *rebootingHost) Reboot(ctx context.Context, host string, port int, user, privateKey string) error {
+	h.reboot("ssh")
+	return nil
+}
+
+func (h *rebootingHost) VerifyOnce(ctx context.Context, host string, port int, user, privateKey string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.downPolls > 0 {
+		h.downPolls--
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (h *rebootingHost) getReboots() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.reboots...)
+}
+
+// providerRebootingHost is a rebootingHost whose provider reboots instances
+type providerRebootingHost struct {
+	*rebootingHost
+}
+
+func (h providerRebootingHost) RebootInstance(ctx context.Context, instanceID string) error {
+	h.reboot("provider")
+	return nil
+}
+
+func newRebootFixture(t *testing.T, prov provider.Provider, host *rebootingHost) (*Service, *mockSessionStore) {
+	t.Helper()
+	store := newMockSessionStore()
+	require.NoError(t, store.Create(context.Background(), &models.Session{
+		ID:         "sess-1",
+		ConsumerID: "consumer-001",
+		Provider:   "vastai",
+		ProviderID: "inst-1",
+		Status:     models.StatusRunning,
+		SSHHost:    "192.168.1.100",
+		SSHPort:    22,
+		SSHUser:    "root",
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}))
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHCheckInterval(10*time.Millisecond),
+		WithRebootTimeout(200*time.Millisecond),
+		WithSSHVerifier(host),
+		WithSSHRebooter(host))
+	return svc, store
+}
+
+func TestService_RebootSession_ViaProvider(t *testing.T) {
+	host := newRebootingHost()
+	var store *mockSessionStore
+	markedRebooting := false
+	host.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		// Other servers see the session rebooting meanwhile
+		if session, err := store.Get(ctx, "sess-1"); err == nil && session.Rebooting(time.Now()) {
+			markedRebooting = true
+		}
+		// The instance comes back on a new SSH port
+		return &provider.InstanceStatus{Status: "running", Running: true,
+			SSHHost: "192.168.1.100", SSHPort: 40022, SSHUser: "root"}, nil
+	}
+	svc, store := newRebootFixture(t, providerRebootingHost{host}, host)
+	ctx := context.Background()
+
+	session, err := svc.RebootSession(ctx, "sess-1", "private-key")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"provider"}, host.getReboots())
+	assert.Equal(t, models.StatusRunning, session.Status)
+	assert.Equal(t, 40022, session.SSHPort)
+	assert.True(t, markedRebooting)
+
+	stored, err := store.Get(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, 40022, stored.SSHPort)
+	assert.False(t, stored.Rebooting(time.Now()))
+}
+
+func TestService_RebootSession_OverSSH(t *testing.T) {
+	host := newRebootingHost()
+	svc, _ := newRebootFixture(t, host, host)
+	ctx := context.Background()
+
+	// Without a provider reboot or stop and start, the key is needed to reboot over SSH
+	_, err := svc.RebootSession(ctx, "sess-1", "")
+	var notSupported *RebootNotSupportedError
+	require.ErrorAs(t, err, &notSupported)
+	assert.Empty(t, host.getReboots())
+
+	session, err := svc.RebootSession(ctx, "sess-1", "private-key")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssh"}, host.getReboots())
+	assert.Equal(t, models.StatusRunning, session.Status)
+}
+
+func TestService_RebootSession_Timeout(t *testing.T) {
+	host := newRebootingHost()
+	host.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		return &provider.InstanceStatus{Status: "loading"}, nil
+	}
+	svc, _ := newRebootFixture(t, providerRebootingHost{host}, host)
+
+	_, err := svc.RebootSession(context.Background(), "sess-1", "")
+	var timeoutErr *RebootTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "sess-1", timeoutErr.SessionID)
+}
+
+func TestService_RebootSession_NotRunning(t *testing.T) {
+	host := newRebootingHost()
+	svc, store := newRebootFixture(t, providerRebootingHost{host}, host)
+	ctx := context.Background()
+	session, err := store.Get(ctx, "sess-1")
+	require.NoError(t, err)
+	session.Status = models.StatusPaused
+	require.NoError(t, store.Update(ctx, session))
+
+	_, err = svc.RebootSession(ctx, "sess-1", "")
+	var notRunning *SessionNotRunningError
+	assert.ErrorAs(t, err, &notRunning)
+	assert.Empty(t, host.getReboots())
+}
+
+func TestService_RebootSession_PowerCycle(t *testing.T) {
+	// The instance's sshd, answering with its banner
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+			conn.Close()
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+
+	prov := newPausableProvider()
+	prov.getStatusFn = func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error) {
+		prov.mu.Lock()
+		defer prov.mu.Unlock()
+		if prov.stopped {
+			return &provider.InstanceStatus{Status: "stopped"}, nil
+		}
+		return &provider.InstanceStatus{Status: "running", Running: true,
+			SSHHost: "127.0.0.1", SSHPort: addr.Port, SSHUser: "user"}, nil
+	}
+	host := newRebootingHost()
+	svc, _ := newRebootFixture(t, prov, host)
+
+	// Without a key or a provider reboot, the instance is stopped and started
+	session, err := svc.RebootSession(context.Background(), "sess-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stop", "start"}, prov.getCalls())
+	assert.Equal(t, addr.Port, session.SSHPort)
+	assert.Empty(t, host.getReboots())
+}
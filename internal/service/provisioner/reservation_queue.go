@@ -0,0 +1,463 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// DefaultReservationMaxWait is how long a queued request waits when the
+	// caller does not say
+	DefaultReservationMaxWait = time.Hour
+
+	// MaxReservationMaxWait caps how long a request may wait for inventory
+	MaxReservationMaxWait = 24 * time.Hour
+
+	// MaxWaitingReservationsPerConsumer caps a consumer's waiting requests
+	MaxWaitingReservationsPerConsumer = 10
+
+	// DefaultReservationPollInterval is how often waiting requests are
+	// retried when no inventory refresh has woken the queue
+	DefaultReservationPollInterval = time.Minute
+
+	// maxReservationAttemptsPerPass bounds how many offers one request may
+	// try per pass, so a single request cannot starve those behind it
+	maxReservationAttemptsPerPass = 3
+)
+
+// ReservationStore persists queued session requests
+type ReservationStore interface {
+	CreateReservation(ctx context.Context, r *models.QueuedReservation) error
+	GetReservation(ctx context.Context, id string) (*models.QueuedReservation, error)
+	ListReservations(ctx context.Context, consumerID string, status models.ReservationStatus) ([]*models.QueuedReservation, error)
+	UpdateReservation(ctx context.Context, r *models.QueuedReservation) error
+}
+
+// OfferLister lists current offers matching a filter
+type OfferLister interface {
+	ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error)
+}
+
+// SessionCreator provisions a session on a chosen offer. *Service implements it.
+type SessionCreator interface {
+	CreateSession(ctx context.Context, req models.CreateSessionRequest, offer *models.GPUOffer) (*models.Session, error)
+}
+
+// RegionPolicy reports where a consumer's sessions may run. *Service
+// implements it.
+type RegionPolicy interface {
+	AllowedRegions(consumerID string) []string
+}
+
+// ReservationQueue holds session requests that found no available offer and
+// fulfils them first-come first-served as matching offers appear. Passes run
+// when the inventory reports fresh offers (see Kick) and on a poll interval.
+type ReservationQueue struct {
+	creator SessionCreator
+	offers  OfferLister
+	store   ReservationStore
+	regions RegionPolicy // Optional: limits offers to the consumer's regions
+	logger  *slog.Logger
+
+	pollInterval time.Duration
+	now          func() time.Time
+
+	// Private keys of fulfilled sessions, handed out once by TakePrivateKey.
+	// Kept in memory only, like every other session private key.
+	keysMu sync.Mutex
+	keys   map[string]string
+
+	// Serializes passes so two never claim the same offer
+	processMu sync.Mutex
+
+	kickCh  chan struct{}
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// QueueOption configures the reservation queue
+type QueueOption func(*ReservationQueue)
+
+// WithQueueLogger sets the logger
+func WithQueueLogger(logger *slog.Logger) QueueOption {
+	return func(q *ReservationQueue) {
+		q.logger = logger
+	}
+}
+
+// WithQueuePollInterval sets how often waiting requests are retried
+func WithQueuePollInterval(d time.Duration) QueueOption {
+	return func(q *ReservationQueue) {
+		q.pollInterval = d
+	}
+}
+
+// WithQueueRegionPolicy only fulfils requests with offers in the regions the
+// consumer's sessions may run in
+func WithQueueRegionPolicy(p RegionPolicy) QueueOption {
+	return func(q *ReservationQueue) {
+		q.regions = p
+	}
+}
+
+// WithQueueTimeFunc sets a custom time function (for testing)
+func WithQueueTimeFunc(fn func() time.Time) QueueOption {
+	return func(q *ReservationQueue) {
+		q.now = fn
+	}
+}
+
+// NewReservationQueue creates a reservation queue
+func NewReservationQueue(creator SessionCreator, offers OfferLister, store ReservationStore, opts ...QueueOption) *ReservationQueue {
+	q := &ReservationQueue{
+		creator:      creator,
+		offers:       offers,
+		store:        store,
+		logger:       slog.Default(),
+		pollInterval: DefaultReservationPollInterval,
+		now:          time.Now,
+		keys:         make(map[string]string),
+		kickCh:       make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Enqueue queues a session request. maxWait defaults to
+// DefaultReservationMaxWait and may not exceed MaxReservationMaxWait.
+func (q *ReservationQueue) Enqueue(ctx context.Context, req models.CreateSessionRequest, filter models.OfferFilter, maxWait time.Duration) (*models.QueuedReservation, error) {
+	if filter.GPUType == "" {
+		return nil, &InvalidReservationError{Reason: "queue filter gpu_type is required"}
+	}
+	if maxWait == 0 {
+		maxWait = DefaultReservationMaxWait
+	}
+	if maxWait < 0 || maxWait > MaxReservationMaxWait {
+		return nil, &InvalidReservationError{Reason: fmt.Sprintf("max wait must be between 1 minute and %s", MaxReservationMaxWait)}
+	}
+
+	waiting, err := q.store.ListReservations(ctx, req.ConsumerID, models.ReservationWaiting)
+	if err != nil {
+		return nil, err
+	}
+	if len(waiting) >= MaxWaitingReservationsPerConsumer {
+		return nil, &InvalidReservationError{Reason: "too many queued requests for consumer"}
+	}
+
+	now := q.now().UTC()
+	req.OfferID = ""
+	r := &models.QueuedReservation{
+		ConsumerID: req.ConsumerID,
+		Filter:     filter,
+		Request:    req,
+		Status:     models.ReservationWaiting,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(maxWait),
+	}
+	if err := q.store.CreateReservation(ctx, r); err != nil {
+		return nil, err
+	}
+
+	q.logger.Info("session request queued",
+		slog.String("reservation_id", r.ID),
+		slog.String("consumer_id", r.ConsumerID),
+		slog.String("gpu_type", filter.GPUType),
+		slog.Time("expires_at", r.ExpiresAt))
+
+	// Offers other than the one the caller wanted may already match
+	q.Kick()
+	return q.withPosition(ctx, r)
+}
+
+// Get returns a queued request with its current queue position
+func (q *ReservationQueue) Get(ctx context.Context, id string) (*models.QueuedReservation, error) {
+	r, err := q.store.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return q.withPosition(ctx, r)
+}
+
+// List returns queued requests oldest first, optionally for one consumer and
+// status, with queue positions for those still waiting
+func (q *ReservationQueue) List(ctx context.Context, consumerID string, status models.ReservationStatus) ([]*models.QueuedReservation, error) {
+	reservations, err := q.store.ListReservations(ctx, consumerID, status)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := q.positions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reservations {
+		r.Position = positions[r.ID]
+	}
+	return reservations, nil
+}
+
+// Cancel withdraws a waiting request
+func (q *ReservationQueue) Cancel(ctx context.Context, id string) (*models.QueuedReservation, error) {
+	// A pass may be provisioning this request right now
+	q.processMu.Lock()
+	defer q.processMu.Unlock()
+
+	r, err := q.store.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != models.ReservationWaiting {
+		return nil, &ReservationNotWaitingError{ID: id, Status: r.Status}
+	}
+
+	r.Status = models.ReservationCancelled
+	if err := q.store.UpdateReservation(ctx, r); err != nil {
+		return nil, err
+	}
+	q.logger.Info("queued session request cancelled",
+		slog.String("reservation_id", r.ID),
+		slog.String("consumer_id", r.ConsumerID))
+	return r, nil
+}
+
+// TakePrivateKey returns the SSH private key of the session that fulfilled
+// a request. It is returned only once and is lost if the server restarts.
+func (q *ReservationQueue) TakePrivateKey(id string) string {
+	q.keysMu.Lock()
+	defer q.keysMu.Unlock()
+	key := q.keys[id]
+	delete(q.keys, id)
+	return key
+}
+
+// Kick asks for a fulfilment pass soon, e.g. after an inventory refresh.
+// It never blocks.
+func (q *ReservationQueue) Kick() {
+	select {
+	case q.kickCh <- struct{}{}:
+	default:
+	}
+}
+
+// Start begins processing the queue in the background
+func (q *ReservationQueue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	if q.running {
+		q.mu.Unlock()
+		return nil
+	}
+	q.running = true
+	q.stopCh = make(chan struct{})
+	q.doneCh = make(chan struct{})
+	q.mu.Unlock()
+
+	q.logger.Info("reservation queue starting",
+		slog.Duration("poll_interval", q.pollInterval))
+
+	go q.run(ctx)
+	return nil
+}
+
+// Stop stops background processing and waits for an in-progress pass
+func (q *ReservationQueue) Stop() {
+	q.mu.Lock()
+	if !q.running {
+		q.mu.Unlock()
+		return
+	}
+	stopCh := q.stopCh
+	doneCh := q.doneCh
+	q.mu.Unlock()
+
+	close(stopCh)
+	<-doneCh
+	q.logger.Info("reservation queue stopped")
+}
+
+func (q *ReservationQueue) run(ctx context.Context) {
+	defer func() {
+		q.mu.Lock()
+		q.running = false
+		q.mu.Unlock()
+		close(q.doneCh)
+	}()
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	// Requests queued before a restart are picked up straight away
+	q.Process(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopCh:
+			return
+		case <-ticker.C:
+			q.Process(ctx)
+		case <-q.kickCh:
+			q.Process(ctx)
+		}
+	}
+}
+
+// Process runs one fulfilment pass. Waiting requests are visited oldest
+// first; each expires, is fulfilled with the cheapest matching available
+// offer not already taken this pass, or stays waiting.
+func (q *ReservationQueue) Process(ctx context.Context) {
+	q.processMu.Lock()
+	defer q.processMu.Unlock()
+
+	waiting, err := q.store.ListReservations(ctx, "", models.ReservationWaiting)
+	if err != nil {
+		q.logger.Error("failed to list queued session requests", slog.String("error", err.Error()))
+		return
+	}
+
+	claimed := make(map[string]bool)
+	for _, r := range waiting {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !q.now().Before(r.ExpiresAt) {
+			r.Status = models.ReservationExpired
+			if r.LastError == "" {
+				r.LastError = "no matching offer became available before the request expired"
+			}
+			q.update(ctx, r)
+			q.logger.Info("queued session request expired",
+				slog.String("reservation_id", r.ID),
+				slog.String("consumer_id", r.ConsumerID),
+				slog.Int("attempts", r.Attempts))
+			continue
+		}
+
+		q.fulfil(ctx, r, claimed)
+	}
+}
+
+// fulfil tries the cheapest matching offers for one request
+func (q *ReservationQueue) fulfil(ctx context.Context, r *models.QueuedReservation, claimed map[string]bool) {
+	filter := r.Filter
+	if q.regions != nil {
+		filter.AllowedRegions = q.regions.AllowedRegions(r.ConsumerID)
+	}
+	offers, err := q.offers.ListOffers(ctx, filter)
+	if err != nil {
+		q.logger.Warn("failed to list offers for queued session request",
+			slog.String("reservation_id", r.ID),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	candidates := make([]models.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if offer.Available && !claimed[offer.ID] && offer.MatchesFilter(filter) {
+			candidates = append(candidates, offer)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].PricePerHour < candidates[j].PricePerHour
+	})
+
+	for i := range candidates {
+		if i == maxReservationAttemptsPerPass {
+			break
+		}
+		offer := &candidates[i]
+		claimed[offer.ID] = true
+
+		req := r.Request
+		req.OfferID = offer.ID
+		r.Attempts++
+
+		session, err := q.creator.CreateSession(ctx, req, offer)
+		if err != nil {
+			r.LastError = err.Error()
+			q.logger.Warn("failed to fulfil queued session request",
+				slog.String("reservation_id", r.ID),
+				slog.String("offer_id", offer.ID),
+				slog.String("error", err.Error()))
+			if isPermanentReservationError(err) {
+				r.Status = models.ReservationFailed
+				break
+			}
+			continue
+		}
+
+		fulfilledAt := q.now().UTC()
+		r.Status = models.ReservationFulfilled
+		r.SessionID = session.ID
+		r.LastError = ""
+		r.FulfilledAt = &fulfilledAt
+
+		q.keysMu.Lock()
+		q.keys[r.ID] = session.SSHPrivateKey
+		q.keysMu.Unlock()
+
+		q.logger.Info("queued session request fulfilled",
+			slog.String("reservation_id", r.ID),
+			slog.String("consumer_id", r.ConsumerID),
+			slog.String("session_id", session.ID),
+			slog.String("offer_id", offer.ID),
+			slog.Duration("waited", fulfilledAt.Sub(r.CreatedAt)))
+		break
+	}
+
+	q.update(ctx, r)
+}
+
+func (q *ReservationQueue) update(ctx context.Context, r *models.QueuedReservation) {
+	if err := q.store.UpdateReservation(ctx, r); err != nil {
+		q.logger.Error("failed to update queued session request",
+			slog.String("reservation_id", r.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// positions maps each waiting request to its 1-based place in the queue
+func (q *ReservationQueue) positions(ctx context.Context) (map[string]int, error) {
+	waiting, err := q.store.ListReservations(ctx, "", models.ReservationWaiting)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[string]int, len(waiting))
+	for i, r := range waiting {
+		positions[r.ID] = i + 1
+	}
+	return positions, nil
+}
+
+func (q *ReservationQueue) withPosition(ctx context.Context, r *models.QueuedReservation) (*models.QueuedReservation, error) {
+	if r.Status != models.ReservationWaiting {
+		return r, nil
+	}
+	positions, err := q.positions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.Position = positions[r.ID]
+	return r, nil
+}
+
+// isPermanentReservationError reports whether a provisioning error would
+// repeat for every offer, so waiting longer cannot help
+func isPermanentReservationError(err error) bool {
+	var diskErr *InsufficientDiskError
+	return errors.As(err, &diskErr)
+}
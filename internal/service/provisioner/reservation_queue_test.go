@@ -0,0 +1,279 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+type mockReservationStore struct {
+	mu           sync.Mutex
+	reservations map[string]*models.QueuedReservation
+	nextID       int
+}
+
+func newMockReservationStore() *mockReservationStore {
+	return &mockReservationStore{reservations: make(map[string]*models.QueuedReservation)}
+}
+
+func (m *mockReservationStore) CreateReservation(ctx context.Context, r *models.QueuedReservation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	r.ID = fmt.Sprintf("res-%d", m.nextID)
+	copy := *r
+	m.reservations[r.ID] = &copy
+	return nil
+}
+
+func (m *mockReservationStore) GetReservation(ctx context.Context, id string) (*models.QueuedReservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.reservations[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copy := *r
+	return &copy, nil
+}
+
+func (m *mockReservationStore) ListReservations(ctx context.Context, consumerID string, status models.ReservationStatus) ([]*models.QueuedReservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.QueuedReservation
+	for _, r := range m.reservations {
+		if (consumerID == "" || r.ConsumerID == consumerID) && (status == "" || r.Status == status) {
+			copy := *r
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (m *mockReservationStore) UpdateReservation(ctx context.Context, r *models.QueuedReservation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.reservations[r.ID]; !ok {
+		return ErrNotFound
+	}
+	copy := *r
+	m.reservations[r.ID] = &copy
+	return nil
+}
+
+type mockOfferLister struct {
+	mu     sync.Mutex
+	offers []models.GPUOffer
+}
+
+func (m *mockOfferLister) ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []models.GPUOffer
+	for _, o := range m.offers {
+		if o.MatchesFilter(filter) {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+type mockSessionCreator struct {
+	mu       sync.Mutex
+	offerIDs []string
+	failFor  map[string]error
+}
+
+func (m *mockSessionCreator) CreateSession(ctx context.Context, req models.CreateSessionRequest, offer *models.GPUOffer) (*models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.failFor[offer.ID]; err != nil {
+		return nil, err
+	}
+	m.offerIDs = append(m.offerIDs, offer.ID)
+	return &models.Session{
+		ID:            "sess-" + offer.ID,
+		ConsumerID:    req.ConsumerID,
+		OfferID:       req.OfferID,
+		SSHPrivateKey: "key-" + offer.ID,
+	}, nil
+}
+
+func rtxOffer(id string, price float64) models.GPUOffer {
+	return models.GPUOffer{ID: id, Provider: "vastai", GPUType: "RTX4090", GPUCount: 1, PricePerHour: price, Available: true}
+}
+
+func TestReservationQueue_FulfilsInOrder(t *testing.T) {
+	ctx := context.Background()
+	store := newMockReservationStore()
+	offers := &mockOfferLister{}
+	creator := &mockSessionCreator{}
+	q := NewReservationQueue(creator, offers, store, WithQueueLogger(newTestLogger()))
+
+	req := models.CreateSessionRequest{ConsumerID: "consumer-001", OfferID: "gone", WorkloadType: models.WorkloadLLM, ReservationHrs: 1}
+	filter := models.OfferFilter{GPUType: "RTX4090", MaxPrice: 0.60}
+
+	first, err := q.Enqueue(ctx, req, filter, 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationWaiting, first.Status)
+	assert.Equal(t, 1, first.Position)
+	assert.Equal(t, DefaultReservationMaxWait, first.ExpiresAt.Sub(first.CreatedAt))
+
+	second, err := q.Enqueue(ctx, req, filter, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Position)
+
+	// Nothing matches yet
+	q.Process(ctx)
+	got, err := q.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationWaiting, got.Status)
+
+	// One matching offer appears (plus one too expensive): oldest request wins
+	offers.offers = []models.GPUOffer{rtxOffer("cheap", 0.40), rtxOffer("pricey", 0.90)}
+	q.Process(ctx)
+
+	got, err = q.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationFulfilled, got.Status)
+	assert.Equal(t, "sess-cheap", got.SessionID)
+	assert.Zero(t, got.Position)
+	require.NotNil(t, got.FulfilledAt)
+	assert.Equal(t, "key-cheap", q.TakePrivateKey(first.ID))
+	assert.Empty(t, q.TakePrivateKey(first.ID), "private key is handed out once")
+
+	got, err = q.Get(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationWaiting, got.Status)
+	assert.Equal(t, 1, got.Position)
+	assert.Equal(t, []string{"cheap"}, creator.offerIDs)
+}
+
+// regionPolicy allows every consumer the same regions
+type regionPolicy []string
+
+func (p regionPolicy) AllowedRegions(consumerID string) []string { return p }
+
+func TestReservationQueue_RegionPolicy(t *testing.T) {
+	ctx := context.Background()
+	store := newMockReservationStore()
+	us := rtxOffer("us", 0.30)
+	us.Location = "Virginia, US"
+	eu := rtxOffer("eu", 0.50)
+	eu.Location = "Stockholm, SE"
+	offers := &mockOfferLister{offers: []models.GPUOffer{us, eu}}
+	creator := &mockSessionCreator{}
+	q := NewReservationQueue(creator, offers, store, WithQueueLogger(newTestLogger()), WithQueueRegionPolicy(regionPolicy{"EU"}))
+
+	req := models.CreateSessionRequest{ConsumerID: "consumer-001", WorkloadType: models.WorkloadLLM, ReservationHrs: 1}
+	r, err := q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 0)
+	require.NoError(t, err)
+
+	q.Process(ctx)
+	got, err := q.Get(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationFulfilled, got.Status)
+	assert.Equal(t, []string{"eu"}, creator.offerIDs)
+}
+
+func TestReservationQueue_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewReservationQueue(&mockSessionCreator{}, &mockOfferLister{}, newMockReservationStore(),
+		WithQueueLogger(newTestLogger()),
+		WithQueueTimeFunc(func() time.Time { return now }))
+
+	req := models.CreateSessionRequest{ConsumerID: "consumer-001", WorkloadType: models.WorkloadLLM, ReservationHrs: 1}
+	r, err := q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 5*time.Minute)
+	require.NoError(t, err)
+
+	now = now.Add(5 * time.Minute)
+	q.Process(ctx)
+
+	got, err := q.Get(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationExpired, got.Status)
+	assert.NotEmpty(t, got.LastError)
+}
+
+func TestReservationQueue_FailedOffersAreSkipped(t *testing.T) {
+	ctx := context.Background()
+	offers := &mockOfferLister{offers: []models.GPUOffer{rtxOffer("a", 0.30), rtxOffer("b", 0.40)}}
+	creator := &mockSessionCreator{failFor: map[string]error{
+		"a": &StaleInventoryError{OfferID: "a", Provider: "vastai", OriginalErr: errors.New("no such ask")},
+	}}
+	q := NewReservationQueue(creator, offers, newMockReservationStore(), WithQueueLogger(newTestLogger()))
+
+	req := models.CreateSessionRequest{ConsumerID: "consumer-001", WorkloadType: models.WorkloadLLM, ReservationHrs: 1}
+	r, err := q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 0)
+	require.NoError(t, err)
+
+	q.Process(ctx)
+
+	got, err := q.Get(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationFulfilled, got.Status)
+	assert.Equal(t, "sess-b", got.SessionID)
+	assert.Equal(t, 2, got.Attempts)
+}
+
+func TestReservationQueue_PermanentErrorFails(t *testing.T) {
+	ctx := context.Background()
+	offers := &mockOfferLister{offers: []models.GPUOffer{rtxOffer("a", 0.30), rtxOffer("b", 0.40)}}
+	creator := &mockSessionCreator{failFor: map[string]error{
+		"a": &InsufficientDiskError{RequestedGB: 10, MinimumGB: 40},
+	}}
+	q := NewReservationQueue(creator, offers, newMockReservationStore(), WithQueueLogger(newTestLogger()))
+
+	req := models.CreateSessionRequest{ConsumerID: "consumer-001", WorkloadType: models.WorkloadLLM, ReservationHrs: 1}
+	r, err := q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 0)
+	require.NoError(t, err)
+
+	q.Process(ctx)
+
+	got, err := q.Get(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationFailed, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Empty(t, creator.offerIDs)
+}
+
+func TestReservationQueue_EnqueueValidationAndCancel(t *testing.T) {
+	ctx := context.Background()
+	q := NewReservationQueue(&mockSessionCreator{}, &mockOfferLister{}, newMockReservationStore(), WithQueueLogger(newTestLogger()))
+	req := models.CreateSessionRequest{ConsumerID: "consumer-001", WorkloadType: models.WorkloadLLM, ReservationHrs: 1}
+
+	var invalid *InvalidReservationError
+	_, err := q.Enqueue(ctx, req, models.OfferFilter{}, 0)
+	assert.ErrorAs(t, err, &invalid)
+	_, err = q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 25*time.Hour)
+	assert.ErrorAs(t, err, &invalid)
+
+	r, err := q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 0)
+	require.NoError(t, err)
+
+	cancelled, err := q.Cancel(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationCancelled, cancelled.Status)
+
+	var notWaiting *ReservationNotWaitingError
+	_, err = q.Cancel(ctx, r.ID)
+	assert.ErrorAs(t, err, &notWaiting)
+
+	for i := 0; i < MaxWaitingReservationsPerConsumer; i++ {
+		_, err = q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 0)
+		require.NoError(t, err)
+	}
+	_, err = q.Enqueue(ctx, req, models.OfferFilter{GPUType: "RTX4090"}, 0)
+	assert.ErrorAs(t, err, &invalid)
+}
@@ -0,0 +1,109 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// DefaultAutoSelectMinConfidence is the availability confidence an offer
+	// needs to be auto-selected when the spec does not set one. Offers that
+	// recently failed or come from stale inventory fall below it.
+	DefaultAutoSelectMinConfidence = 0.5
+
+	// maxAutoSelectAttempts bounds how many offers one auto-selected create
+	// tries when offers turn out to be gone
+	maxAutoSelectAttempts = 3
+)
+
+// CreateSessionForSpec provisions a session on the cheapest available offer
+// matching filter instead of a caller-chosen offer. Offers are taken from
+// inventory, so suppressed offers are already excluded and confidence
+// reflects recent failures and staleness. Offers below the confidence floor
+// or too small for req.ModelID are skipped. If an offer turns out to be gone,
+// the next cheapest is tried.
+func (s *Service) CreateSessionForSpec(ctx context.Context, req models.CreateSessionRequest, filter models.OfferFilter) (*models.Session, error) {
+	lister, ok := s.inventory.(OfferLister)
+	if !ok {
+		return nil, fmt.Errorf("offer auto-selection requires an inventory")
+	}
+
+	minConfidence := filter.MinAvailabilityConfidence
+	if minConfidence <= 0 {
+		minConfidence = DefaultAutoSelectMinConfidence
+	}
+	// Confidence is checked on the effective value below, so offers without
+	// an explicit confidence are not filtered out by the inventory
+	listFilter := filter
+	listFilter.MinAvailabilityConfidence = 0
+	listFilter.AllowedRegions = s.AllowedRegions(req.ConsumerID)
+
+	offers, err := lister.ListOffers(ctx, listFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offers: %w", err)
+	}
+
+	candidates := rankAutoSelectCandidates(offers, minConfidence, req)
+	if len(candidates) == 0 {
+		return nil, &NoMatchingOfferError{Filter: filter, MinConfidence: minConfidence}
+	}
+
+	var lastErr error
+	for i := range candidates {
+		if i == maxAutoSelectAttempts {
+			break
+		}
+		offer := &candidates[i]
+		req.OfferID = offer.ID
+
+		s.logger.Info("auto-selected offer",
+			slog.String("consumer_id", req.ConsumerID),
+			slog.String("offer_id", offer.ID),
+			slog.String("provider", offer.Provider),
+			slog.String("gpu_type", offer.GPUType),
+			slog.Float64("price_per_hour", offer.PricePerHour),
+			slog.Float64("availability_confidence", offer.GetEffectiveAvailabilityConfidence()),
+			slog.Int("candidates", len(candidates)))
+
+		session, err := s.CreateSession(ctx, req, offer)
+		if err == nil {
+			return session, nil
+		}
+		if !IsRetryableWithDifferentOffer(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// rankAutoSelectCandidates keeps the available offers that meet the
+// confidence floor and can hold req's model, cheapest first. Equal prices
+// prefer the more reliable offer.
+func rankAutoSelectCandidates(offers []models.GPUOffer, minConfidence float64, req models.CreateSessionRequest) []models.GPUOffer {
+	candidates := make([]models.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if !offer.Available || offer.GetEffectiveAvailabilityConfidence() < minConfidence {
+			continue
+		}
+		if req.ModelID != "" {
+			estimation := EstimateVRAMRequirements(req.ModelID, req.Quantization, offer.GPUCount)
+			if _, err := ValidateVRAM(offer.VRAM, offer.GPUCount, estimation); err != nil {
+				continue
+			}
+		}
+		candidates = append(candidates, offer)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].PricePerHour != candidates[j].PricePerHour {
+			return candidates[i].PricePerHour < candidates[j].PricePerHour
+		}
+		return candidates[i].GetEffectiveAvailabilityConfidence() > candidates[j].GetEffectiveAvailabilityConfidence()
+	})
+	return candidates
+}
@@ -0,0 +1,165 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// mockListingInventory adds offer listing to mockInventory for auto-selection
+type mockListingInventory struct {
+	mockInventory
+	offers []models.GPUOffer
+}
+
+func (m *mockListingInventory) ListOffers(ctx context.Context, filter models.OfferFilter) ([]models.GPUOffer, error) {
+	var result []models.GPUOffer
+	for _, o := range m.offers {
+		if o.MatchesFilter(filter) {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+func specOffer(id string, price, confidence float64, vram int) models.GPUOffer {
+	return models.GPUOffer{
+		ID:                     id,
+		Provider:               "vastai",
+		ProviderID:             id,
+		GPUType:                "RTX4090",
+		GPUCount:               1,
+		VRAM:                   vram,
+		PricePerHour:           price,
+		Available:              true,
+		AvailabilityConfidence: confidence,
+	}
+}
+
+func TestRankAutoSelectCandidates(t *testing.T) {
+	offers := []models.GPUOffer{
+		specOffer("pricey", 0.60, 1.0, 24),
+		specOffer("unreliable", 0.20, 0.3, 24),
+		specOffer("small", 0.25, 1.0, 8),
+		specOffer("cheap-low", 0.40, 0.6, 24),
+		specOffer("cheap-high", 0.40, 0.9, 24),
+	}
+	offers = append(offers, specOffer("unavailable", 0.10, 1.0, 24))
+	offers[len(offers)-1].Available = false
+
+	ids := func(offers []models.GPUOffer) []string {
+		var out []string
+		for _, o := range offers {
+			out = append(out, o.ID)
+		}
+		return out
+	}
+
+	got := rankAutoSelectCandidates(offers, 0.5, models.CreateSessionRequest{})
+	assert.Equal(t, []string{"small", "cheap-high", "cheap-low", "pricey"}, ids(got))
+
+	// A 13B model does not fit in 8GB
+	got = rankAutoSelectCandidates(offers, 0.5, models.CreateSessionRequest{ModelID: "meta-llama/Llama-2-13b-hf", Quantization: "awq"})
+	assert.Equal(t, []string{"cheap-high", "cheap-low", "pricey"}, ids(got))
+}
+
+func TestService_CreateSessionForSpec(t *testing.T) {
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}
+
+	t.Run("picks cheapest reliable offer", func(t *testing.T) {
+		inv := &mockListingInventory{offers: []models.GPUOffer{
+			specOffer("a", 0.50, 1.0, 24),
+			specOffer("b", 0.30, 0.2, 24),
+			specOffer("c", 0.40, 0.8, 24),
+		}}
+		svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+			WithLogger(newTestLogger()), WithInventory(inv))
+
+		session, err := svc.CreateSessionForSpec(context.Background(), req, models.OfferFilter{GPUType: "RTX4090"})
+		require.NoError(t, err)
+		assert.Equal(t, "c", session.OfferID)
+		assert.Equal(t, 0.40, session.PricePerHour)
+	})
+
+	t.Run("skips offers that are gone", func(t *testing.T) {
+		inv := &mockListingInventory{offers: []models.GPUOffer{
+			specOffer("gone", 0.30, 1.0, 24),
+			specOffer("next", 0.40, 1.0, 24),
+		}}
+		prov := newMockProvider("vastai")
+		prov.createInstanceFn = func(ctx context.Context, r provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+			if r.OfferID == "gone" {
+				return nil, fmt.Errorf("create failed: %w", provider.ErrOfferUnavailable)
+			}
+			return &provider.InstanceInfo{ProviderInstanceID: "inst-1", Status: "running"}, nil
+		}
+		svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{prov}),
+			WithLogger(newTestLogger()), WithInventory(inv))
+
+		session, err := svc.CreateSessionForSpec(context.Background(), req, models.OfferFilter{GPUType: "RTX4090"})
+		require.NoError(t, err)
+		assert.Equal(t, "next", session.OfferID)
+	})
+
+	t.Run("no matching offer", func(t *testing.T) {
+		inv := &mockListingInventory{offers: []models.GPUOffer{specOffer("a", 0.50, 0.4, 24)}}
+		svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+			WithLogger(newTestLogger()), WithInventory(inv))
+
+		_, err := svc.CreateSessionForSpec(context.Background(), req, models.OfferFilter{GPUType: "A100"})
+		var noMatch *NoMatchingOfferError
+		require.ErrorAs(t, err, &noMatch)
+		assert.Equal(t, DefaultAutoSelectMinConfidence, noMatch.MinConfidence)
+
+		// A lower floor admits the less reliable offer
+		session, err := svc.CreateSessionForSpec(context.Background(), req,
+			models.OfferFilter{GPUType: "RTX4090", MinAvailabilityConfidence: 0.3})
+		require.NoError(t, err)
+		assert.Equal(t, "a", session.OfferID)
+	})
+
+	t.Run("keeps to the consumer's allowed regions", func(t *testing.T) {
+		us := specOffer("us", 0.30, 1.0, 24)
+		us.Location = "Virginia, US"
+		de := specOffer("de", 0.50, 1.0, 24)
+		de.Location = "Frankfurt, Hesse, Germany"
+		inv := &mockListingInventory{offers: []models.GPUOffer{us, de}}
+		svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+			WithLogger(newTestLogger()), WithInventory(inv),
+			WithAllowedRegions(map[string][]string{"Consumer-001": {"EU"}, "*": {"NA"}}))
+
+		session, err := svc.CreateSessionForSpec(context.Background(), req, models.OfferFilter{GPUType: "RTX4090"})
+		require.NoError(t, err)
+		assert.Equal(t, "de", session.OfferID, "the cheaper US offer is outside the EU")
+
+		// A chosen offer outside the policy is refused
+		_, err = svc.CreateSession(context.Background(), req, &us)
+		var regionErr *RegionNotAllowedError
+		require.ErrorAs(t, err, &regionErr)
+		assert.Equal(t, "us", regionErr.OfferID)
+
+		// Unlisted consumers get the "*" policy
+		other := req
+		other.ConsumerID = "consumer-002"
+		session, err = svc.CreateSessionForSpec(context.Background(), other, models.OfferFilter{GPUType: "RTX4090"})
+		require.NoError(t, err)
+		assert.Equal(t, "us", session.OfferID)
+	})
+
+	t.Run("requires inventory", func(t *testing.T) {
+		svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+			WithLogger(newTestLogger()))
+		_, err := svc.CreateSessionForSpec(context.Background(), req, models.OfferFilter{GPUType: "RTX4090"})
+		assert.Error(t, err)
+	})
+}
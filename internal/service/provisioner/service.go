@@ -20,6 +20,8 @@ import (
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/logging"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/featureflags"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/modelcache"
 	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
@@ -28,6 +30,9 @@ import (
 // Compile-time check that sshverify.Verifier satisfies SSHVerifier interface
 var _ SSHVerifier = (*sshverify.Verifier)(nil)
 
+// Compile-time check that sshverify.Verifier can list GPUs for the GPU check
+var _ GPULister = (*sshverify.Verifier)(nil)
+
 const (
 	// DefaultSSHVerifyTimeout is how long to wait for SSH verification
 	// Increased to 8 minutes to accommodate TensorDock cloud-init delays
@@ -49,6 +54,29 @@ const (
 	// DefaultAPICheckInterval is how often to retry API health check
 	DefaultAPICheckInterval = 15 * time.Second
 
+	// DefaultBootstrapTimeout is how long a session's bootstrap script may run
+	DefaultBootstrapTimeout = 10 * time.Minute
+
+	// DefaultGPUBurnInDuration is how long a GPU burn-in loads the GPUs
+	DefaultGPUBurnInDuration = 30 * time.Second
+
+	// DefaultWorkspaceSaveTimeout bounds saving a session's workspace before
+	// its instance is destroyed, and restoring it
+	DefaultWorkspaceSaveTimeout = 30 * time.Minute
+
+	// DefaultModelPullTimeout bounds downloading a session's model weights
+	// from the model cache
+	DefaultModelPullTimeout = 30 * time.Minute
+
+	// DefaultResumeTimeout is how long resuming a paused session waits for its
+	// instance to start. Resumes and reboots answer within the API server's
+	// 5 minute write timeout, so their timeouts stay well under it.
+	DefaultResumeTimeout = 3 * time.Minute
+
+	// DefaultRebootTimeout is how long rebooting a session may take in all,
+	// including stopping and starting its instance, until it answers SSH again
+	DefaultRebootTimeout = 3 * time.Minute
+
 	// DefaultDestroyTimeout is the max time to wait for destroy verification
 	DefaultDestroyTimeout = 5 * time.Minute
 
@@ -146,6 +174,8 @@ type SessionStore interface {
 	Update(ctx context.Context, session *models.Session) error
 	GetActiveSessionByConsumerAndOffer(ctx context.Context, consumerID, offerID string) (*models.Session, error)
 	List(ctx context.Context, filter models.SessionListFilter) ([]*models.Session, error)
+	UpdateThroughput(ctx context.Context, sessionID string, inetDownMbps, diskBandwidthMBps float64) error
+	SetRebootingUntil(ctx context.Context, sessionID string, until time.Time) error
 }
 
 // ProviderRegistry provides access to provider clients
@@ -158,7 +188,7 @@ type ProviderRegistry interface {
 type InventoryFinder interface {
 	FindComparableOffers(ctx context.Context, original *models.GPUOffer, scope string, excludeIDs []string, excludeMachineIDs []string) ([]models.GPUOffer, error)
 	GetOffer(ctx context.Context, offerID string) (*models.GPUOffer, error)
-	RecordOfferFailure(offerID, provider, gpuType, failureType, reason string)
+	RecordOfferFailure(offerID, provider, gpuType, machineID, failureType, reason string)
 	EvictOffer(offerID string)
 }
 
@@ -167,12 +197,100 @@ type CostRecorder interface {
 	RecordFinalCost(ctx context.Context, session *models.Session) error
 }
 
+// BudgetChecker rejects sessions that would exceed a spend cap. The budgets
+// checked are held until release is called, once the session is stored, so
+// concurrent creates cannot jointly overrun a cap.
+type BudgetChecker interface {
+	ReserveBudget(ctx context.Context, consumerID string, projectedCost float64) (release func(), err error)
+}
+
+// Notifier receives session lifecycle events (e.g., for webhook delivery).
+type Notifier interface {
+	NotifySession(ctx context.Context, eventType models.WebhookEventType, session *models.Session)
+}
+
+// FailoverNotifier is implemented by notifiers that can tell a consumer
+// where a preempted session was re-provisioned.
+type FailoverNotifier interface {
+	NotifyFailover(ctx context.Context, failover models.SessionFailover)
+}
+
+// HostKeyNotifier is implemented by notifiers that can tell a consumer a
+// session's SSH host key changed since it was first recorded.
+type HostKeyNotifier interface {
+	NotifyHostKeyChanged(ctx context.Context, change models.SessionHostKeyChange)
+}
+
+// FeatureFlags evaluates runtime feature flags for canary rollout
+type FeatureFlags interface {
+	Enabled(ctx context.Context, name, key string, def bool) bool
+	Evaluate(ctx context.Context, key string) map[string]bool
+}
+
 // SSHVerifier defines the interface for SSH verification
 type SSHVerifier interface {
 	// VerifyOnce attempts a single SSH connection verification (no retries)
 	VerifyOnce(ctx context.Context, host string, port int, user, privateKey string) error
 }
 
+// BootstrapRunner runs a session's bootstrap script on its instance
+type BootstrapRunner interface {
+	// RunScript runs script over SSH, returning its stdout and stderr
+	RunScript(ctx context.Context, host string, port int, user, privateKey, script string) (output string, err error)
+}
+
+// GPUBurnInRunner load-tests a session's GPUs before it is handed over
+type GPUBurnInRunner interface {
+	// RunBurnIn loads every GPU over SSH for duration and reports how they held up
+	RunBurnIn(ctx context.Context, host string, port int, user, privateKey string, duration time.Duration) (*sshverify.GPUBurnIn, error)
+}
+
+// WorkspaceSnapshotter saves the workspace of a session with storage policy
+// "preserve" when it is destroyed, and restores it into the consumer's next
+// such session
+type WorkspaceSnapshotter interface {
+	// Save uploads the workspace over SSH as the consumer's saved workspace,
+	// returning its size; 0 means the workspace was empty
+	Save(ctx context.Context, host string, port int, user, privateKey, consumerID string) (int64, error)
+	// Restore extracts the consumer's saved workspace over SSH, returning
+	// its size; 0 means none was saved
+	Restore(ctx context.Context, host string, port int, user, privateKey, consumerID string) (int64, error)
+}
+
+// ModelCache serves model weights from the operator's bucket, so instances
+// do not download them from Hugging Face
+type ModelCache interface {
+	// Pull returns the download of modelID's weights onto an instance, or
+	// modelcache.ErrNotCached
+	Pull(ctx context.Context, modelID string) (*modelcache.Pull, error)
+}
+
+// KernelLogReader reads the kernel log of a running session's instance
+type KernelLogReader interface {
+	// ReadKernelErrors returns the error lines of the kernel log over SSH,
+	// oldest first
+	ReadKernelErrors(ctx context.Context, host string, port int, user, privateKey string) ([]sshverify.KernelEvent, error)
+}
+
+// SSHRebooter reboots an instance over SSH, for providers that cannot
+// reboot instances themselves
+type SSHRebooter interface {
+	Reboot(ctx context.Context, host string, port int, user, privateKey string) error
+}
+
+// EventRecorder appends non-status events, such as bootstrap script and GPU
+// burn-in results, to a session's history
+type EventRecorder interface {
+	Record(ctx context.Context, event *models.SessionEvent) error
+}
+
+// Quarantine records instances that could not be destroyed until an operator
+// resolves them, and forgets them once a later destroy succeeds
+type Quarantine interface {
+	Quarantine(ctx context.Context, q *models.QuarantinedInstance) error
+	Resolve(ctx context.Context, provider, instanceID string) error
+}
+
 // HTTPVerifier defines the interface for HTTP endpoint verification
 type HTTPVerifier interface {
 	// CheckHealth checks if an HTTP endpoint is responding
@@ -185,9 +303,20 @@ type Service struct {
 	providers    ProviderRegistry
 	inventory    InventoryFinder // Optional: needed for auto-retry
 	costRecorder CostRecorder    // Optional: records final cost on session termination
+	budget       BudgetChecker   // Optional: enforces spend caps before provisioning
+	notifier     Notifier        // Optional: receives session lifecycle events
+	events       EventRecorder   // Optional: records bootstrap script results
+	features     FeatureFlags    // Optional: gates providers and provider behaviors
+	quarantine   Quarantine      // Optional: records instances that could not be destroyed
 	logger       *slog.Logger
 	deploymentID string
 
+	// Consumer ID ("*" for unlisted consumers) -> regions sessions may run in
+	allowedRegions map[string][]string
+
+	// Caps concurrent CreateInstance calls per provider (nil = unlimited)
+	createSlots *createLimiter
+
 	// SSH verification
 	sshVerifier          SSHVerifier
 	sshVerifyTimeout     time.Duration
@@ -195,15 +324,61 @@ type Service struct {
 	sshMaxInterval       time.Duration
 	sshBackoffMultiplier float64
 
+	// Post-provision throughput measurement; download speed is only
+	// measured when a test URL is set
+	bandwidthTestURL string
+
+	// Bootstrap scripts run once SSH is verified
+	bootstrapRunner  BootstrapRunner
+	bootstrapTimeout time.Duration
+
+	// GPU burn-in of sessions that ask for it, before they turn running
+	burnInRunner   GPUBurnInRunner
+	burnInDuration time.Duration
+
+	// Workspaces of preserve-policy sessions, saved before their instance
+	// is destroyed (nil = storage is not preserved)
+	workspaces           WorkspaceSnapshotter
+	workspaceSaveTimeout time.Duration
+	workspaceSaves       map[string]bool // Sessions whose workspace is being saved
+	workspaceSavesMu     sync.Mutex
+
+	// Model weights of SSH-mode sessions, pulled before their bootstrap
+	// script (nil = models are not cached)
+	modelCache       ModelCache
+	modelPullTimeout time.Duration
+
+	// Kernel logs of running sessions, read for OOM kills and GPU errors
+	// (0 interval = not watched)
+	kernelLogReader     KernelLogReader
+	kernelWatchInterval time.Duration
+	kernelDiagnoses     map[string]string // Session ID -> errors last found in its kernel log
+	kernelDiagnosesMu   sync.Mutex
+	kernelWatches       sync.WaitGroup
+	kernelWatchesMu     sync.Mutex // Held to add kernel watches and to stop
+
+	// Cancelled by Stop, ending background work that outlives requests
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Private keys of failover replacements, held until their consumer
+	// fetches them, since nobody receives the create response of a retry
+	replacementKeys   map[string]string
+	replacementKeysMu sync.Mutex
+
 	// API verification (for entrypoint mode)
 	httpVerifier     HTTPVerifier
 	apiVerifyTimeout time.Duration
 	apiCheckInterval time.Duration
+	readinessProbes  map[provider.WorkloadType]ReadinessProbe // nil uses defaultReadinessProbes
 
 	// Configuration
 	destroyTimeout time.Duration
 	destroyRetries int
 	sshKeyBits     int
+	resumeTimeout  time.Duration
+	rebootTimeout  time.Duration
+	sshRebooter    SSHRebooter
 
 	// Balance warning
 	lowBalanceThreshold float64
@@ -214,6 +389,11 @@ type Service struct {
 	// Verification goroutine tracking (for testing)
 	verifyWg sync.WaitGroup
 
+	// Sessions this process is verifying, so a recovery sweep does not
+	// verify them twice
+	verifying   map[string]bool
+	verifyingMu sync.Mutex
+
 	// Bug #6 fix: Per-session destroy locks to prevent concurrent destroy operations
 	destroyLocks   map[string]*sync.Mutex
 	destroyLocksMu sync.Mutex
@@ -264,6 +444,15 @@ func WithSSHBackoffMultiplier(m float64) Option {
 	}
 }
 
+// WithBandwidthTestURL sets a URL that new instances download from for up to
+// 10 seconds to measure their download speed. Without one, only disk
+// throughput is measured; providers may bill the transfer.
+func WithBandwidthTestURL(url string) Option {
+	return func(s *Service) {
+		s.bandwidthTestURL = url
+	}
+}
+
 // WithDestroyRetries sets the max number of destroy verification attempts
 func WithDestroyRetries(n int) Option {
 	return func(s *Service) {
@@ -271,6 +460,27 @@ func WithDestroyRetries(n int) Option {
 	}
 }
 
+// WithResumeTimeout sets how long resuming a paused session waits for its instance to start
+func WithResumeTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.resumeTimeout = d
+	}
+}
+
+// WithRebootTimeout sets how long rebooting a session may take until its instance answers SSH again
+func WithRebootTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.rebootTimeout = d
+	}
+}
+
+// WithSSHRebooter sets a custom rebooter of instances over SSH
+func WithSSHRebooter(r SSHRebooter) Option {
+	return func(s *Service) {
+		s.sshRebooter = r
+	}
+}
+
 // WithSSHVerifier sets a custom SSH verifier (useful for testing)
 func WithSSHVerifier(v SSHVerifier) Option {
 	return func(s *Service) {
@@ -285,6 +495,94 @@ func WithHTTPVerifier(v HTTPVerifier) Option {
 	}
 }
 
+// WithBootstrapRunner sets a custom runner for session bootstrap scripts
+func WithBootstrapRunner(r BootstrapRunner) Option {
+	return func(s *Service) {
+		s.bootstrapRunner = r
+	}
+}
+
+// WithBootstrapTimeout sets how long a session's bootstrap script may run
+func WithBootstrapTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.bootstrapTimeout = d
+	}
+}
+
+// WithGPUBurnInRunner sets a custom runner for GPU burn-ins
+func WithGPUBurnInRunner(r GPUBurnInRunner) Option {
+	return func(s *Service) {
+		s.burnInRunner = r
+	}
+}
+
+// WithGPUBurnInDuration sets how long a GPU burn-in loads the GPUs
+func WithGPUBurnInDuration(d time.Duration) Option {
+	return func(s *Service) {
+		s.burnInDuration = d
+	}
+}
+
+// WithWorkspaceSnapshotter sets where workspaces of sessions with storage
+// policy "preserve" are saved
+func WithWorkspaceSnapshotter(w WorkspaceSnapshotter) Option {
+	return func(s *Service) {
+		s.workspaces = w
+	}
+}
+
+// WithWorkspaceSaveTimeout bounds saving a session's workspace
+func WithWorkspaceSaveTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.workspaceSaveTimeout = d
+	}
+}
+
+// WithModelCache sets where the model weights of SSH-mode sessions are
+// downloaded from
+func WithModelCache(c ModelCache) Option {
+	return func(s *Service) {
+		s.modelCache = c
+	}
+}
+
+// WithModelPullTimeout bounds downloading a session's model weights
+func WithModelPullTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.modelPullTimeout = d
+	}
+}
+
+// WithKernelLogReader sets a custom reader of instance kernel logs
+func WithKernelLogReader(r KernelLogReader) Option {
+	return func(s *Service) {
+		s.kernelLogReader = r
+	}
+}
+
+// WithKernelWatchInterval sets how often running sessions' kernel logs are
+// read for OOM kills and GPU errors; 0, the default, disables watching
+func WithKernelWatchInterval(d time.Duration) Option {
+	return func(s *Service) {
+		s.kernelWatchInterval = d
+	}
+}
+
+// WithEventRecorder records bootstrap script and GPU burn-in results in
+// session histories
+func WithEventRecorder(r EventRecorder) Option {
+	return func(s *Service) {
+		s.events = r
+	}
+}
+
+// WithQuarantine records instances whose destruction could not be verified in q
+func WithQuarantine(q Quarantine) Option {
+	return func(s *Service) {
+		s.quarantine = q
+	}
+}
+
 // WithAPIVerifyTimeout sets how long to wait for API verification
 func WithAPIVerifyTimeout(d time.Duration) Option {
 	return func(s *Service) {
@@ -320,6 +618,55 @@ func WithCostRecorder(cr CostRecorder) Option {
 	}
 }
 
+// WithBudgetChecker configures an optional budget checker that can reject
+// sessions before any provider resources are created.
+func WithBudgetChecker(bc BudgetChecker) Option {
+	return func(s *Service) {
+		s.budget = bc
+	}
+}
+
+// WithNotifier sets the receiver for session lifecycle events
+func WithNotifier(n Notifier) Option {
+	return func(s *Service) {
+		s.notifier = n
+	}
+}
+
+// WithFeatureFlags sets the runtime feature flags. Provider behavior flags
+// are evaluated per session; "provider.<name>" flags gate provisioning on a
+// provider per consumer.
+func WithFeatureFlags(f FeatureFlags) Option {
+	return func(s *Service) {
+		s.features = f
+	}
+}
+
+// WithAllowedRegions restricts where each consumer's sessions may run, for
+// data residency. Keys are consumer IDs, with "*" applying to consumers not
+// listed; values are region names (see models.Regions) or ISO country codes.
+// A consumer listed with no regions may run anywhere. Consumer IDs match
+// case-insensitively, as configuration keys are lower-cased when loaded.
+func WithAllowedRegions(policy map[string][]string) Option {
+	return func(s *Service) {
+		s.allowedRegions = make(map[string][]string, len(policy))
+		for consumerID, regions := range policy {
+			s.allowedRegions[strings.ToLower(consumerID)] = regions
+		}
+	}
+}
+
+// WithCreateConcurrency caps how many instances are created at once on each
+// provider, so a burst of sessions does not trip provider rate limits.
+// defaultLimit applies to providers not in perProvider; 0 means unlimited.
+// Sessions over the limit wait up to queueTimeout for a slot, then fail
+// with a ProviderBusyError.
+func WithCreateConcurrency(defaultLimit int, perProvider map[string]int, queueTimeout time.Duration) Option {
+	return func(s *Service) {
+		s.createSlots = newCreateLimiter(defaultLimit, perProvider, queueTimeout)
+	}
+}
+
 // New creates a new provisioner service
 func New(store SessionStore, providers ProviderRegistry, opts ...Option) *Service {
 	s := &Service{
@@ -333,13 +680,25 @@ func New(store SessionStore, providers ProviderRegistry, opts ...Option) *Servic
 		sshBackoffMultiplier: DefaultSSHBackoffMultiplier,
 		apiVerifyTimeout:     DefaultAPIVerifyTimeout,
 		apiCheckInterval:     DefaultAPICheckInterval,
+		bootstrapTimeout:     DefaultBootstrapTimeout,
+		burnInDuration:       DefaultGPUBurnInDuration,
+		workspaceSaveTimeout: DefaultWorkspaceSaveTimeout,
+		modelPullTimeout:     DefaultModelPullTimeout,
+		workspaceSaves:       make(map[string]bool),
+		resumeTimeout:        DefaultResumeTimeout,
+		rebootTimeout:        DefaultRebootTimeout,
+		sshRebooter:          sshRebooter{},
 		destroyTimeout:       DefaultDestroyTimeout,
 		destroyRetries:       DefaultDestroyRetries,
 		sshKeyBits:           DefaultSSHKeyBits,
 		lowBalanceThreshold:  DefaultLowBalanceThreshold,
 		now:                  time.Now,
 		destroyLocks:         make(map[string]*sync.Mutex),
+		kernelDiagnoses:      make(map[string]string),
+		replacementKeys:      make(map[string]string),
+		verifying:            make(map[string]bool),
 	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	for _, opt := range opts {
 		opt(s)
@@ -358,6 +717,18 @@ func New(store SessionStore, providers ProviderRegistry, opts ...Option) *Servic
 		s.httpVerifier = NewDefaultHTTPVerifier()
 	}
 
+	if s.bootstrapRunner == nil {
+		s.bootstrapRunner = &sshBootstrapRunner{timeout: s.bootstrapTimeout}
+	}
+
+	if s.burnInRunner == nil {
+		s.burnInRunner = &sshBurnInRunner{}
+	}
+
+	if s.kernelLogReader == nil {
+		s.kernelLogReader = sshKernelLogReader{}
+	}
+
 	return s
 }
 
@@ -378,6 +749,30 @@ func (s *Service) CreateSession(ctx context.Context, req models.CreateSessionReq
 	return s.createSessionWithRetry(ctx, req, offer, nil, nil, 0, "")
 }
 
+// AllowedRegions returns the regions the consumer's sessions may run in, or
+// nil when they may run anywhere
+func (s *Service) AllowedRegions(consumerID string) []string {
+	if regions, ok := s.allowedRegions[strings.ToLower(consumerID)]; ok {
+		return regions
+	}
+	return s.allowedRegions["*"]
+}
+
+// offersInAllowedRegions drops offers outside the consumer's allowed regions
+func (s *Service) offersInAllowedRegions(consumerID string, offers []models.GPUOffer) []models.GPUOffer {
+	regions := s.AllowedRegions(consumerID)
+	if len(regions) == 0 {
+		return offers
+	}
+	allowed := offers[:0:0]
+	for _, offer := range offers {
+		if offer.InRegions(regions) {
+			allowed = append(allowed, offer)
+		}
+	}
+	return allowed
+}
+
 // createSessionWithRetry is the internal implementation that supports retry.
 // failedOfferIDs tracks offers that already failed, retryCount is the current attempt,
 // retryParentID links back to the original session if this is a retry.
@@ -388,10 +783,72 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		slog.String("provider", offer.Provider),
 		slog.Int("retry_count", retryCount))
 
-	// Check provider balance (warn-only)
+	// Reject offers that cannot hold the model before paying for boot
+	if req.ModelID != "" {
+		vramEstimation := EstimateVRAMRequirements(req.ModelID, req.Quantization, offer.GPUCount)
+		tight, err := ValidateVRAM(offer.VRAM, offer.GPUCount, vramEstimation)
+		if err != nil {
+			return nil, err
+		}
+		if tight {
+			s.logger.Warn("model leaves little VRAM headroom on this offer",
+				slog.String("model_id", req.ModelID),
+				slog.Int("required_gb", vramEstimation.RequiredGB),
+				slog.Int("vram_gb", offer.VRAM),
+				slog.Int("gpu_count", offer.GPUCount))
+		}
+	}
+
+	// Reject providers switched off (or outside their canary) by feature flag
+	if s.features != nil && !s.features.Enabled(ctx, featureflags.ProviderFlagPrefix+offer.Provider, req.ConsumerID, true) {
+		return nil, &ProviderDisabledError{Provider: offer.Provider}
+	}
+
+	// Only some providers can run the consumer's cloud-init
+	if !req.CloudInit.IsEmpty() {
+		if prov, err := s.providers.Get(offer.Provider); err == nil && !prov.SupportsFeature(provider.FeatureCloudInit) {
+			return nil, &CloudInitNotSupportedError{Provider: offer.Provider}
+		}
+	}
+
+	// Keep sessions where the consumer's data may be processed
+	if regions := s.AllowedRegions(req.ConsumerID); len(regions) > 0 && !offer.InRegions(regions) {
+		return nil, &RegionNotAllowedError{ConsumerID: req.ConsumerID, OfferID: offer.ID, Location: offer.Location}
+	}
+
+	// Interruptible offers are rented by bidding at least the offer's minimum bid
+	pricePerHour := offer.PricePerHour
+	var bidPrice float64
+	if offer.Interruptible {
+		bidPrice = offer.MinBid
+		if bidPrice <= 0 {
+			bidPrice = offer.PricePerHour
+		}
+		if req.BidPrice > 0 {
+			if req.BidPrice < offer.MinBid {
+				return nil, &InvalidBidError{OfferID: req.OfferID, BidPrice: req.BidPrice, MinBid: offer.MinBid}
+			}
+			bidPrice = req.BidPrice
+		}
+		pricePerHour = bidPrice
+	} else if req.BidPrice > 0 {
+		return nil, &InvalidBidError{OfferID: req.OfferID, BidPrice: req.BidPrice}
+	}
+
+	projectedCost := pricePerHour * float64(req.ReservationHrs)
+
+	// Check provider balance: reject if it cannot cover the reservation, warn if low
 	if prov, err := s.providers.Get(offer.Provider); err == nil {
-		if bp, ok := prov.(provider.BalanceProvider); ok {
+		if bp, ok := provider.As[provider.BalanceProvider](prov); ok {
 			if balance, err := bp.GetAccountBalance(ctx); err == nil {
+				if projectedCost > 0 && balance.Balance < projectedCost {
+					return nil, &InsufficientBalanceError{
+						Provider:      offer.Provider,
+						Balance:       balance.Balance,
+						ProjectedCost: projectedCost,
+						Currency:      balance.Currency,
+					}
+				}
 				if balance.Balance < s.lowBalanceThreshold {
 					s.logger.Warn("LOW BALANCE: provider account balance is below threshold",
 						slog.String("provider", offer.Provider),
@@ -425,6 +882,29 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		}
 	}
 
+	// Wait for a creation slot on the provider before recording the session,
+	// so a request that times out in the queue leaves nothing to clean up
+	releaseSlot, err := s.createSlots.acquire(ctx, offer.Provider)
+	if err != nil {
+		s.logger.Warn("no instance creation slot available",
+			slog.String("provider", offer.Provider),
+			slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer releaseSlot()
+
+	// Enforce spend caps. Retries are checked too since a comparable offer may
+	// cost more. The budgets stay held until the session is recorded below.
+	releaseBudget := func() {}
+	if s.budget != nil {
+		release, err := s.budget.ReserveBudget(ctx, req.ConsumerID, projectedCost)
+		if err != nil {
+			return nil, err
+		}
+		releaseBudget = sync.OnceFunc(release)
+		defer releaseBudget()
+	}
+
 	// Generate SSH key pair
 	privateKey, publicKey, err := s.generateSSHKeyPair()
 	if err != nil {
@@ -445,31 +925,50 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 
 	// PHASE 1: Create session record in database (survives crashes)
 	session := &models.Session{
-		ID:             uuid.New().String(),
-		ConsumerID:     req.ConsumerID,
-		Provider:       offer.Provider,
-		OfferID:        req.OfferID,
-		GPUType:        offer.GPUType,
-		GPUCount:       offer.GPUCount,
-		Status:         models.StatusPending,
-		SSHPublicKey:   publicKey,
-		SSHPrivateKey:  privateKey,
-		WorkloadType:   req.WorkloadType,
-		ReservationHrs: req.ReservationHrs,
-		IdleThreshold:  req.IdleThreshold,
-		StoragePolicy:  storagePolicy,
-		PricePerHour:   offer.PricePerHour,
-		CreatedAt:      now,
-		ExpiresAt:      expiresAt,
-		AutoRetry:      req.AutoRetry,
-		MaxRetries:     req.MaxRetries,
-		RetryScope:     req.RetryScope,
-		RetryCount:     retryCount,
-		RetryParentID:  retryParentID,
-		FailedOffers:   failedOffersStr,
-	}
-
-	if err := s.store.Create(ctx, session); err != nil {
+		ID:              uuid.New().String(),
+		ConsumerID:      req.ConsumerID,
+		Provider:        offer.Provider,
+		OfferID:         req.OfferID,
+		GPUType:         offer.GPUType,
+		GPUCount:        offer.GPUCount,
+		VRAM:            offer.VRAM,
+		Status:          models.StatusPending,
+		ProvisionPhase:  models.PhasePending,
+		Location:        offer.Location,
+		CUDAVersion:     offer.CUDAVersion,
+		DriverVersion:   offer.DriverVersion,
+		MachineID:       offer.MachineID,
+		SSHPublicKey:    publicKey,
+		SSHPrivateKey:   privateKey,
+		WorkloadType:    req.WorkloadType,
+		LaunchMode:      req.LaunchMode,
+		ReservationHrs:  req.ReservationHrs,
+		IdleThreshold:   req.IdleThreshold,
+		IdleGPUUtilPct:  req.IdleGPUUtilPct,
+		StoragePolicy:   storagePolicy,
+		PricePerHour:    pricePerHour,
+		Interruptible:   offer.Interruptible,
+		BidPrice:        bidPrice,
+		CreatedAt:       now,
+		ExpiresAt:       expiresAt,
+		AutoRetry:       req.AutoRetry,
+		MaxRetries:      req.MaxRetries,
+		RetryScope:      req.RetryScope,
+		RetryCount:      retryCount,
+		RetryParentID:   retryParentID,
+		FailedOffers:    failedOffersStr,
+		GroupID:         req.GroupID,
+		BootstrapScript: req.BootstrapScript,
+		GPUBurnIn:       req.GPUBurnIn,
+		ModelID:         req.ModelID,
+		HFToken:         req.HFToken,
+		OnStartCmd:      req.OnStartCmd,
+		CloudInit:       req.CloudInit,
+	}
+
+	err = s.store.Create(ctx, session)
+	releaseBudget()
+	if err != nil {
 		// Bug #47 fix: Handle race condition where another request created the session
 		// The database unique constraint catches this race at the DB level
 		if errors.Is(err, storage.ErrAlreadyExists) {
@@ -492,9 +991,6 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		return nil, fmt.Errorf("failed to create session record: %w", err)
 	}
 
-	// Bug fix: Increment pending gauge when session is first created.
-	metrics.UpdateSessionStatus(session.Provider, "", string(models.StatusPending))
-
 	s.logger.Info("session record created",
 		slog.String("session_id", session.ID),
 		slog.String("status", string(session.Status)))
@@ -502,7 +998,7 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 	// PHASE 2: Call provider to create instance
 	prov, err := s.providers.Get(offer.Provider)
 	if err != nil {
-		s.failSession(ctx, session, fmt.Sprintf("provider not found: %s", offer.Provider))
+		s.failSession(ctx, session, models.FailureProviderError, "provider_not_found", fmt.Sprintf("provider not found: %s", offer.Provider))
 		return nil, err
 	}
 
@@ -514,12 +1010,16 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		SessionID:    session.ID,
 		SSHPublicKey: publicKey,
 		Tags:         tags,
+		CloudInit:    req.CloudInit,
+	}
+	if s.features != nil {
+		instanceReq.Features = s.features.Evaluate(ctx, session.ID)
 	}
 
 	// For interruptible instances, pass the bid price so the provider
-	// includes it in the create request (prevents immediate outbidding).
-	if offer.Interruptible && offer.PricePerHour > 0 {
-		instanceReq.BidPrice = offer.PricePerHour
+	// creates a bid instead of an on-demand instance.
+	if session.Interruptible {
+		instanceReq.BidPrice = session.BidPrice
 	}
 
 	// Template-based provisioning (Vast.ai)
@@ -566,6 +1066,9 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		instanceReq.DockerImage = req.DockerImage
 		instanceReq.ExposedPorts = req.ExposedPorts
 		instanceReq.WorkloadConfig = s.buildWorkloadConfig(req)
+		if req.HFToken != "" {
+			instanceReq.EnvVars = hfTokenEnv(req.HFToken)
+		}
 	}
 
 	// Auto-inject benchmark script for benchmark workload type
@@ -589,26 +1092,21 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		instanceReq.OnStartCmd = req.OnStartCmd
 	}
 
-	session.Status = models.StatusProvisioning
-	if err := s.store.Update(ctx, session); err != nil {
+	if err := s.advancePhase(ctx, session, models.PhaseProvisioning); err != nil {
 		s.logger.Error("failed to update session to provisioning",
 			slog.String("session_id", session.ID),
 			slog.String("error", err.Error()))
 	}
-	// Bug #46 fix: Update metrics BEFORE CreateInstance so failSession can properly decrement
-	metrics.UpdateSessionStatus(session.Provider, string(models.StatusPending), string(models.StatusProvisioning))
-
 	instance, err := prov.CreateInstance(ctx, instanceReq)
+	// Free the slot before any retry, which waits for a slot of its own
+	releaseSlot()
 	if err != nil {
-		s.failSession(ctx, session, fmt.Sprintf("provider create failed: %s", err.Error()))
+		category := classifyCreateError(err)
+		s.failSession(ctx, session, category, "", fmt.Sprintf("provider create failed: %s", err.Error()))
 
 		// Record global offer failure and evict from cache for cross-session intelligence
 		if s.inventory != nil {
-			failType := "unknown"
-			if provider.ShouldRetryWithDifferentOffer(err) {
-				failType = "stale_inventory"
-			}
-			s.inventory.RecordOfferFailure(offer.ID, offer.Provider, offer.GPUType, failType, err.Error())
+			s.inventory.RecordOfferFailure(offer.ID, offer.Provider, offer.GPUType, offer.MachineID, string(category), err.Error())
 			s.inventory.EvictOffer(offer.ID)
 		}
 
@@ -628,6 +1126,7 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 				slog.String("scope", req.RetryScope))
 
 			alternatives, findErr := s.inventory.FindComparableOffers(ctx, offer, req.RetryScope, newFailedOffers, newFailedMachines)
+			alternatives = s.offersInAllowedRegions(req.ConsumerID, alternatives)
 			if findErr != nil {
 				s.logger.Warn("failed to find comparable offers for retry",
 					slog.String("error", findErr.Error()))
@@ -684,6 +1183,32 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 	if instance.ActualPricePerHour > 0 {
 		session.PricePerHour = instance.ActualPricePerHour
 	}
+	if instance.APIPort > 0 {
+		session.APIPort = instance.APIPort
+	}
+	if session.SSHHost != "" {
+		session.Progress.IPAssignedAt = time.Now()
+	}
+
+	// The deadline is stored so a verification interrupted by a restart
+	// resumes with the time it had left
+	verifyTimeout := s.apiVerifyTimeout
+	if req.LaunchMode != models.LaunchModeEntrypoint {
+		verifyTimeout = s.sshVerifyTimeout
+		if req.TemplateRecommendedSSHTimeout > 0 {
+			verifyTimeout = req.TemplateRecommendedSSHTimeout
+			s.logger.Info("using template-recommended SSH timeout",
+				slog.Duration("timeout", verifyTimeout))
+		}
+		// The timeout starts once the instance has had time to boot
+		verifyTimeout += bootDelay(session, prov)
+	}
+	session.VerifyDeadline = time.Now().Add(verifyTimeout)
+	if err := setPhase(session, models.PhaseBooting); err != nil {
+		s.logger.Warn("failed to move session to booting",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()))
+	}
 
 	if err := s.store.Update(ctx, session); err != nil {
 		// Critical: Instance exists but we failed to record it
@@ -702,6 +1227,8 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 				slog.String("destroy_error", destroyErr.Error()),
 				slog.String("db_error", err.Error()))
 			metrics.RecordOrphanDetected()
+			s.quarantineInstance(destroyCtx, session.Provider, instance.ProviderInstanceID, session.ID,
+				models.QuarantineOrphan, destroyErr.Error())
 		} else {
 			s.logger.Info("successfully destroyed orphaned instance after DB failure",
 				slog.String("session_id", session.ID),
@@ -727,32 +1254,24 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 		"reservation_hours", session.ReservationHrs)
 
 	metrics.RecordSessionCreated(session.Provider)
+	s.notify(ctx, models.WebhookEventSessionCreated, session)
 
 	// PHASE 4: Wait for verification (async - don't block API)
-	if req.LaunchMode == models.LaunchModeEntrypoint {
-		verifyCtx, cancel := context.WithTimeout(context.Background(), s.apiVerifyTimeout+5*time.Second)
-		s.verifyWg.Add(1)
-		go func() {
-			defer s.verifyWg.Done()
-			defer cancel()
-			s.waitForAPIVerifyAsync(verifyCtx, session.ID, prov)
-		}()
-	} else {
-		// SSH mode: wait for SSH connectivity
-		sshTimeout := s.sshVerifyTimeout
-		if req.TemplateRecommendedSSHTimeout > 0 {
-			sshTimeout = req.TemplateRecommendedSSHTimeout
-			s.logger.Info("using template-recommended SSH timeout",
-				slog.Duration("timeout", sshTimeout))
+	// Leave room past the deadline for the timeout to fail the session
+	verifyCtx, cancel := context.WithDeadline(context.Background(), session.VerifyDeadline.Add(5*time.Second))
+	s.startVerifying(session.ID)
+	s.verifyWg.Add(1)
+	go func() {
+		defer s.verifyWg.Done()
+		defer s.stopVerifying(session.ID)
+		defer cancel()
+		if req.LaunchMode == models.LaunchModeEntrypoint {
+			s.waitForAPIVerifyAsync(verifyCtx, session.ID, prov, session.VerifyDeadline, false)
+			return
 		}
-		verifyCtx, cancel := context.WithTimeout(context.Background(), sshTimeout+5*time.Second)
-		s.verifyWg.Add(1)
-		go func() {
-			defer s.verifyWg.Done()
-			defer cancel()
-			s.waitForSSHVerifyAsyncWithRetry(verifyCtx, session.ID, privateKey, prov, sshTimeout, req)
-		}()
-	}
+		// SSH mode: wait for SSH connectivity
+		s.verifySSHWithRetry(verifyCtx, session.ID, privateKey, prov, session.VerifyDeadline, req)
+	}()
 
 	return session, nil
 }
@@ -760,14 +1279,14 @@ func (s *Service) createSessionWithRetry(ctx context.Context, req models.CreateS
 // waitForSSHVerifyAsync waits for SSH verification in the background with default timeout.
 // privateKey is passed directly because it's not stored in the database for security
 func (s *Service) waitForSSHVerifyAsync(ctx context.Context, sessionID string, privateKey string, prov provider.Provider) {
-	s.waitForSSHVerifyAsyncWithTimeout(ctx, sessionID, privateKey, prov, s.sshVerifyTimeout)
+	s.verifySSH(ctx, sessionID, privateKey, prov, time.Now().Add(s.sshVerifyTimeout))
 }
 
-// waitForSSHVerifyAsyncWithRetry wraps SSH verification with auto-retry support.
+// verifySSHWithRetry wraps SSH verification with auto-retry support.
 // On failure (timeout or instance stopped), if auto_retry is enabled, it triggers
 // a new session with a comparable offer.
-func (s *Service) waitForSSHVerifyAsyncWithRetry(ctx context.Context, sessionID string, privateKey string, prov provider.Provider, sshTimeout time.Duration, req models.CreateSessionRequest) {
-	s.waitForSSHVerifyAsyncWithTimeout(ctx, sessionID, privateKey, prov, sshTimeout)
+func (s *Service) verifySSHWithRetry(ctx context.Context, sessionID string, privateKey string, prov provider.Provider, deadline time.Time, req models.CreateSessionRequest) {
+	s.verifySSH(ctx, sessionID, privateKey, prov, deadline)
 
 	// After SSH verification completes (success or failure), check if we need to retry
 	session, err := s.store.Get(context.Background(), sessionID)
@@ -790,6 +1309,12 @@ func (s *Service) triggerAsyncRetry(failedSession *models.Session, originalReq m
 	if strings.Contains(failedSession.Error, "instance stopped") {
 		reason = "instance_stopped"
 	}
+	if failedSession.Status == models.StatusPreempted {
+		reason = "preempted"
+	}
+	if failedSession.FailureCategory == models.FailureGPUUnhealthy || failedSession.FailureCategory == models.FailureGPUMismatch {
+		reason = string(failedSession.FailureCategory)
+	}
 	metrics.RecordRetryAttempt(failedSession.Provider, failedSession.RetryScope, reason)
 
 	// Build exclusion list from previously failed offers
@@ -805,11 +1330,14 @@ func (s *Service) triggerAsyncRetry(failedSession *models.Session, originalReq m
 	if err != nil {
 		// Build a synthetic offer from session data for comparison
 		originalOffer = &models.GPUOffer{
-			ID:           failedSession.OfferID,
-			Provider:     failedSession.Provider,
-			GPUType:      failedSession.GPUType,
-			GPUCount:     failedSession.GPUCount,
-			PricePerHour: failedSession.PricePerHour,
+			ID:            failedSession.OfferID,
+			Provider:      failedSession.Provider,
+			GPUType:       failedSession.GPUType,
+			GPUCount:      failedSession.GPUCount,
+			VRAM:          failedSession.VRAM,
+			PricePerHour:  failedSession.PricePerHour,
+			Interruptible: failedSession.Interruptible,
+			MachineID:     failedSession.MachineID,
 		}
 	}
 	if originalOffer.MachineID != "" {
@@ -817,6 +1345,7 @@ func (s *Service) triggerAsyncRetry(failedSession *models.Session, originalReq m
 	}
 
 	alternatives, err := s.inventory.FindComparableOffers(ctx, originalOffer, failedSession.RetryScope, failedOfferIDs, failedMachineIDs)
+	alternatives = s.offersInAllowedRegions(failedSession.ConsumerID, alternatives)
 	if err != nil || len(alternatives) == 0 {
 		s.logger.Warn("async retry: no comparable offers found",
 			slog.String("session_id", failedSession.ID),
@@ -852,303 +1381,6 @@ func (s *Service) triggerAsyncRetry(failedSession *models.Session, originalReq m
 		slog.String("new_session", newSession.ID))
 }
 
-// waitForSSHVerifyAsyncWithTimeout waits for SSH verification with a custom timeout.
-// BUG-005: Support template-specific timeouts for heavy images like vLLM.
-func (s *Service) waitForSSHVerifyAsyncWithTimeout(ctx context.Context, sessionID string, privateKey string, prov provider.Provider, sshTimeout time.Duration) {
-	logger := s.logger.With(slog.String("session_id", sessionID))
-	logger.Info("waiting for SSH verification")
-
-	start := time.Now()
-
-	// TensorDock-specific: wait for cloud-init to complete before polling
-	// TensorDock VMs need extra time for cloud-init runcmd to execute
-	session, err := s.store.Get(ctx, sessionID)
-	if err == nil && session.Provider == "tensordock" {
-		logger.Info("TensorDock: waiting for cloud-init before SSH polling",
-			slog.Duration("delay", TensorDockCloudInitDelay))
-		select {
-		case <-time.After(TensorDockCloudInitDelay):
-		case <-ctx.Done():
-			return
-		}
-	}
-
-	if err == nil && session.Provider == "bluelobster" {
-		logger.Info("Blue Lobster: waiting for post-boot stabilization before SSH polling",
-			slog.Duration("delay", BlueLobsterBootDelay))
-		select {
-		case <-time.After(BlueLobsterBootDelay):
-		case <-ctx.Done():
-			return
-		}
-	}
-
-	// Log warning about insecure host key verification once per session
-	// This is intentional for commodity GPU instances where host keys are unknown
-	logger.Warn("using insecure host key verification for commodity GPU instance",
-		slog.String("reason", "host keys are unknown for spot instances"))
-
-	// Create progressive backoff for SSH polling
-	// This reduces provider API load when instances take a long time to be ready
-	backoff := NewProgressiveBackoff(s.sshCheckInterval, s.sshMaxInterval, s.sshBackoffMultiplier)
-
-	// Use a timer instead of ticker for progressive backoff
-	nextInterval := backoff.Next()
-	pollTimer := time.NewTimer(nextInterval)
-	defer pollTimer.Stop()
-
-	timeout := time.NewTimer(sshTimeout)
-	defer timeout.Stop()
-
-	attemptCount := 0
-	lastErrorType := "none"
-	lastError := ""
-	var lastSSHErr error // tracks last SSH attempt result; non-nil triggers instance status check
-	consecutivePermanentErrors := 0
-	consecutiveNeeded := 1
-	if session != nil && session.Provider == "bluelobster" {
-		consecutiveNeeded = 2
-	}
-	consecutiveOK := 0
-	for {
-		select {
-		case <-timeout.C:
-			// SSH verification timeout - destroy instance and fail session
-			logger.Error("SSH verification timeout, destroying instance",
-				slog.Int("attempts", attemptCount),
-				slog.String("last_error_type", lastErrorType),
-				slog.String("last_error", lastError),
-				slog.Duration("elapsed", time.Since(start)))
-			session, err := s.store.Get(ctx, sessionID)
-			if err != nil {
-				logger.Error("failed to get session", slog.String("error", err.Error()))
-				return
-			}
-
-			if session.ProviderID != "" {
-				if err := prov.DestroyInstance(ctx, session.ProviderID); err != nil {
-					logger.Error("failed to destroy instance after SSH timeout",
-						slog.String("error", err.Error()))
-				}
-			}
-
-			s.failSession(ctx, session, "SSH verification timeout")
-			metrics.RecordSSHVerifyFailure()
-			// Bug #94 fix: Record session destroyed when SSH verification times out
-			metrics.RecordSessionDestroyed(session.Provider, "ssh_verify_timeout")
-
-			// Record global offer failure and evict from cache for cross-session intelligence
-			if s.inventory != nil {
-				s.inventory.RecordOfferFailure(session.OfferID, session.Provider, session.GPUType, "ssh_timeout", "SSH verification timeout")
-				s.inventory.EvictOffer(session.OfferID)
-			}
-			return
-
-		case <-pollTimer.C:
-			attemptCount++
-
-			// Log the current backoff interval
-			currentInterval := backoff.Current()
-			logger.Debug("SSH poll attempt",
-				slog.Int("attempt", attemptCount),
-				slog.Duration("next_interval", currentInterval))
-
-			// Get current session state
-			session, err := s.store.Get(ctx, sessionID)
-			if err != nil {
-				logger.Error("failed to get session", slog.String("error", err.Error()))
-				// Schedule next poll with progressive backoff
-				nextInterval = backoff.Next()
-				pollTimer.Reset(nextInterval)
-				continue
-			}
-
-			// Check if session is still in a valid state
-			if session.IsTerminal() {
-				logger.Info("session is terminal, stopping SSH verification")
-				return
-			}
-
-			// Poll provider for SSH info if we don't have it yet, OR check
-			// instance health after an SSH failure (detect reclaimed instances).
-			needStatusCheck := session.SSHHost == "" || lastSSHErr != nil
-			if needStatusCheck && session.ProviderID != "" {
-				status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
-				if err != nil {
-					if errors.Is(err, provider.ErrInstanceNotFound) {
-						logger.Error("instance no longer exists, failing session",
-							slog.String("provider_id", session.ProviderID))
-						s.failSession(ctx, session, "instance_vanished: no longer exists on provider")
-						metrics.RecordSessionDestroyed(session.Provider, "instance_vanished")
-						if s.inventory != nil {
-							s.inventory.RecordOfferFailure(session.OfferID, session.Provider, session.GPUType, "instance_vanished", "instance not found during SSH verification")
-							s.inventory.EvictOffer(session.OfferID)
-						}
-						return
-					}
-					logger.Warn("failed to get instance status", slog.String("error", err.Error()))
-					// Schedule next poll with progressive backoff
-					nextInterval = backoff.Next()
-					pollTimer.Reset(nextInterval)
-					continue
-				}
-
-				// BUG-011 fix: Fail fast if instance stopped unexpectedly
-				// Don't fail for transient states like "creating", "starting", or "loading"
-				if !status.Running && status.Status != "" &&
-					status.Status != "created" && status.Status != "creating" &&
-					status.Status != "starting" && status.Status != "provisioning" &&
-					status.Status != "booting" && status.Status != "loading" {
-					logger.Error("instance stopped unexpectedly",
-						slog.String("status", status.Status),
-						slog.String("error_detail", status.Error),
-						slog.String("provider_id", session.ProviderID))
-
-					// Attempt to destroy the failed instance
-					if err := prov.DestroyInstance(ctx, session.ProviderID); err != nil {
-						logger.Error("failed to destroy stopped instance",
-							slog.String("error", err.Error()))
-					}
-
-					failReason := classifyInstanceStopReason(status.Status, status.Error)
-					s.failSession(ctx, session, failReason)
-					metrics.RecordSessionDestroyed(session.Provider, "instance_stopped")
-
-					// Record global offer failure and evict from cache for cross-session intelligence
-					if s.inventory != nil {
-						s.inventory.RecordOfferFailure(session.OfferID, session.Provider, session.GPUType, "instance_stopped", failReason)
-						s.inventory.EvictOffer(session.OfferID)
-					}
-					return
-				}
-
-				// Populate SSH info from provider if we don't have it yet
-				if session.SSHHost == "" && status.SSHHost != "" {
-					session.SSHHost = status.SSHHost
-					if status.SSHPort != 0 {
-						session.SSHPort = status.SSHPort
-					}
-					if status.SSHUser != "" {
-						session.SSHUser = status.SSHUser
-					}
-					if err := s.store.Update(ctx, session); err != nil {
-						logger.Error("failed to update SSH info", slog.String("error", err.Error()))
-					} else {
-						logger.Info("SSH info updated",
-							slog.String("ssh_host", session.SSHHost),
-							slog.Int("ssh_port", session.SSHPort))
-						// Reset backoff when we get new SSH info
-						backoff.Reset()
-					}
-				}
-			}
-
-			// Try SSH verification if we have connection info
-			if session.SSHHost != "" && session.SSHPort > 0 {
-				logger.Debug("attempting SSH verification",
-					slog.String("host", session.SSHHost),
-					slog.Int("port", session.SSHPort))
-
-				// Try a single connection attempt using the private key passed to this function
-				err := s.sshVerifier.VerifyOnce(ctx, session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
-				if err == nil {
-					lastSSHErr = nil
-					consecutiveOK++
-					if consecutiveOK < consecutiveNeeded {
-						logger.Info("SSH succeeded, verifying stability",
-							slog.Int("consecutive", consecutiveOK),
-							slog.Int("needed", consecutiveNeeded))
-						// Quick re-check after 5 seconds
-						nextInterval = 5 * time.Second
-						pollTimer.Reset(nextInterval)
-						continue
-					}
-					// SSH verified successfully
-					duration := time.Since(start)
-					logger.Info("SSH verification successful",
-						slog.Duration("duration", duration),
-						slog.Int("attempts", attemptCount))
-
-					oldStatus := session.Status
-					session.Status = models.StatusRunning
-					if err := s.store.Update(ctx, session); err != nil {
-						logger.Error("failed to update session to running", slog.String("error", err.Error()))
-					}
-
-					// Bug #46 fix: Update metrics gauge on state transition
-					metrics.UpdateSessionStatus(session.Provider, string(oldStatus), string(models.StatusRunning))
-					metrics.RecordSSHVerifyDuration(session.Provider, duration)
-					metrics.RecordSSHVerifyAttempts(session.Provider, attemptCount)
-					// Bug #57 fix: Record provisioning duration when session becomes running
-					metrics.RecordProvisioningDuration(session.Provider, duration)
-
-					// BUG-004: Validate CUDA version after SSH success (async, non-blocking)
-					// This is informational - we don't fail the session on mismatch
-					go s.validateCUDAVersionAsync(session, privateKey, logger)
-
-					// Post-provision disk space check (async, non-blocking)
-					go s.validateDiskSpaceAsync(session, privateKey, logger)
-
-					return
-				}
-
-				lastSSHErr = err
-				lastErrorType = classifySSHError(err)
-				consecutiveOK = 0
-				lastError = err.Error()
-				logger.Info("SSH verification attempt failed",
-					slog.Int("attempt", attemptCount),
-					slog.String("error_type", lastErrorType),
-					slog.String("host", session.SSHHost),
-					slog.Int("port", session.SSHPort),
-					slog.String("error", lastError))
-				metrics.RecordSSHVerifyError(session.Provider, lastErrorType)
-
-				// Fail fast on permanent SSH errors (auth_failed, key_parse_failed)
-				if lastErrorType == "auth_failed" || lastErrorType == "key_parse_failed" {
-					consecutivePermanentErrors++
-					if consecutivePermanentErrors >= 3 {
-						logger.Error("permanent SSH error detected, failing session early",
-							slog.String("error_type", lastErrorType),
-							slog.Int("consecutive_errors", consecutivePermanentErrors))
-
-						// Set FailedOffers BEFORE failSession so it's persisted atomically
-						if session.OfferID != "" {
-							if session.FailedOffers == "" {
-								session.FailedOffers = session.OfferID
-							} else if !strings.Contains(session.FailedOffers, session.OfferID) {
-								session.FailedOffers = session.FailedOffers + "," + session.OfferID
-							}
-						}
-
-						s.failSession(ctx, session, "permanent SSH error: "+lastErrorType)
-
-						// Record global offer failure and evict from cache
-						if s.inventory != nil {
-							s.inventory.RecordOfferFailure(session.OfferID, session.Provider, session.GPUType, "auth_failed", "permanent SSH error: "+lastErrorType)
-							s.inventory.EvictOffer(session.OfferID)
-						}
-
-						metrics.RecordSSHVerifyFailure()
-						metrics.RecordSessionDestroyed(session.Provider, "permanent_ssh_error")
-						return
-					}
-				} else {
-					consecutivePermanentErrors = 0
-				}
-			}
-
-			// Schedule next poll with progressive backoff
-			nextInterval = backoff.Next()
-			pollTimer.Reset(nextInterval)
-
-		case <-ctx.Done():
-			logger.Warn("context cancelled while waiting for SSH verification")
-			return
-		}
-	}
-}
-
 // getDestroyLock returns a per-session mutex for destroy operations
 // Bug #6 fix: Ensures only one destroy operation runs per session
 func (s *Service) getDestroyLock(sessionID string) *sync.Mutex {
@@ -1171,8 +1403,16 @@ func (s *Service) cleanupDestroyLock(sessionID string) {
 	delete(s.destroyLocks, sessionID)
 }
 
-// DestroySession destroys a session with verification
+// DestroySession destroys a session with verification. A running session
+// that preserves its workspace stays running until the workspace is saved,
+// and is destroyed in the background; see SavingWorkspace.
 func (s *Service) DestroySession(ctx context.Context, sessionID string) error {
+	return s.destroySession(ctx, sessionID, false)
+}
+
+// destroySession destroys a session, first saving its workspace unless
+// workspaceSaved is set
+func (s *Service) destroySession(ctx context.Context, sessionID string, workspaceSaved bool) error {
 	// Bug #6 fix: Acquire per-session lock to prevent concurrent destroy operations
 	lock := s.getDestroyLock(sessionID)
 	lock.Lock()
@@ -1184,6 +1424,11 @@ func (s *Service) DestroySession(ctx context.Context, sessionID string) error {
 		return fmt.Errorf("session not found: %w", err)
 	}
 
+	// The session is destroyed once its workspace is saved
+	if !workspaceSaved && s.SavingWorkspace(sessionID) {
+		return nil
+	}
+
 	// Check for terminal OR stopping state (another destroy may have started)
 	if session.IsTerminal() || session.Status == models.StatusStopping {
 		// If a failed session still has a provider ID, the initial destroy may
@@ -1216,21 +1461,23 @@ func (s *Service) DestroySession(ctx context.Context, sessionID string) error {
 		return nil // Already terminated or being terminated
 	}
 
+	if !workspaceSaved && session.Status == models.StatusRunning && session.ProviderID != "" &&
+		s.preservesWorkspace(session) && s.startWorkspaceSave(ctx, session) {
+		s.logger.Info("saving workspace before destroying session",
+			slog.String("session_id", sessionID))
+		return nil
+	}
+
 	s.logger.Info("destroying session",
 		slog.String("session_id", sessionID),
 		slog.String("provider_id", session.ProviderID))
 
-	// Bug #46 fix: Track old status for metrics update
-	preDestroyStatus := session.Status
 	session.Status = models.StatusStopping
 	if err := s.store.Update(ctx, session); err != nil {
 		s.logger.Error("failed to update session to stopping",
 			slog.String("session_id", sessionID),
 			slog.String("error", err.Error()))
 	}
-	// Bug #46 fix: Update metrics gauge on state transition to stopping
-	metrics.UpdateSessionStatus(session.Provider, string(preDestroyStatus), string(models.StatusStopping))
-
 	// Get provider
 	prov, err := s.providers.Get(session.Provider)
 	if err != nil {
@@ -1242,7 +1489,6 @@ func (s *Service) DestroySession(ctx context.Context, sessionID string) error {
 		return err
 	}
 
-	oldStatus := session.Status
 	session.Status = models.StatusStopped
 	session.StoppedAt = s.now()
 	if err := s.store.Update(ctx, session); err != nil {
@@ -1263,7 +1509,7 @@ func (s *Service) DestroySession(ctx context.Context, sessionID string) error {
 		"reason", "user_requested")
 
 	metrics.RecordSessionDestroyed(session.Provider, "user_requested")
-	metrics.UpdateSessionStatus(session.Provider, string(oldStatus), string(models.StatusStopped))
+	s.notify(ctx, models.WebhookEventSessionDestroyed, session)
 
 	return nil
 }
@@ -1302,6 +1548,7 @@ func (s *Service) destroyWithVerification(ctx context.Context, session *models.S
 		if err != nil {
 			// Instance not found = successfully destroyed
 			if errors.Is(err, provider.ErrInstanceNotFound) {
+				s.releaseQuarantine(ctx, session)
 				return nil
 			}
 			s.logger.Warn("status check failed",
@@ -1312,6 +1559,7 @@ func (s *Service) destroyWithVerification(ctx context.Context, session *models.S
 		}
 
 		if !status.Running {
+			s.releaseQuarantine(ctx, session)
 			return nil
 		}
 
@@ -1329,11 +1577,100 @@ func (s *Service) destroyWithVerification(ctx context.Context, session *models.S
 	// Record metrics for destroy failure
 	metrics.RecordDestroyFailure()
 
-	return &DestroyVerificationError{
+	verifyErr := &DestroyVerificationError{
 		SessionID:  session.ID,
 		ProviderID: session.ProviderID,
 		Attempts:   s.destroyRetries,
 	}
+	s.quarantineInstance(ctx, session.Provider, session.ProviderID, session.ID,
+		models.QuarantineDestroy, verifyErr.Error())
+	return verifyErr
+}
+
+// quarantineInstance records an instance that could not be destroyed, so it
+// is surfaced until an operator resolves it
+func (s *Service) quarantineInstance(ctx context.Context, providerName, instanceID, sessionID string, source models.QuarantineSource, reason string) {
+	if s.quarantine == nil {
+		return
+	}
+	q := &models.QuarantinedInstance{
+		Provider:   providerName,
+		InstanceID: instanceID,
+		SessionID:  sessionID,
+		Source:     source,
+		Reason:     reason,
+		LastSeenAt: s.now(),
+	}
+	if err := s.quarantine.Quarantine(context.WithoutCancel(ctx), q); err != nil {
+		s.logger.Error("failed to quarantine instance",
+			slog.String("session_id", sessionID),
+			slog.String("provider_id", instanceID),
+			slog.String("error", err.Error()))
+		return
+	}
+	logging.Audit(ctx, "instance_quarantined",
+		"provider", providerName,
+		"provider_id", instanceID,
+		"session_id", sessionID,
+		"source", string(source),
+		"reason", reason)
+}
+
+// releaseQuarantine takes a session's destroyed instance out of quarantine,
+// if it was in
+func (s *Service) releaseQuarantine(ctx context.Context, session *models.Session) {
+	if s.quarantine == nil {
+		return
+	}
+	err := s.quarantine.Resolve(ctx, session.Provider, session.ProviderID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		s.logger.Warn("failed to release destroyed instance from quarantine",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// RegenerateSSHKey issues a new SSH key pair for a running session and attaches
+// the public key to the instance. The old key is not revoked. The private key is
+// returned once and is not stored.
+func (s *Service) RegenerateSSHKey(ctx context.Context, sessionID string) (string, error) {
+	session, err := s.store.Get(ctx, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("session not found: %w", err)
+	}
+
+	if session.Status != models.StatusRunning || session.ProviderID == "" {
+		return "", &SessionNotRunningError{ID: sessionID, Status: session.Status}
+	}
+
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return "", &ProviderNotFoundError{Name: session.Provider}
+	}
+	keyProvider, ok := provider.As[provider.SSHKeyProvider](prov)
+	if !ok {
+		return "", &SSHKeyRotationNotSupportedError{Provider: session.Provider}
+	}
+
+	privateKey, publicKey, err := s.generateSSHKeyPair()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+
+	if err := keyProvider.AttachSSHKey(ctx, session.ProviderID, publicKey); err != nil {
+		return "", fmt.Errorf("failed to attach SSH key: %w", err)
+	}
+
+	session.SSHPublicKey = publicKey
+	if err := s.store.Update(ctx, session); err != nil {
+		return "", fmt.Errorf("failed to update session: %w", err)
+	}
+
+	s.logger.Info("SSH key regenerated",
+		slog.String("session_id", sessionID),
+		slog.String("provider", session.Provider))
+
+	return privateKey, nil
 }
 
 // GetSession retrieves a session by ID
@@ -1347,8 +1684,9 @@ func (s *Service) ListSessions(ctx context.Context, filter models.SessionListFil
 }
 
 // validateCUDAVersionAsync runs CUDA validation asynchronously after SSH verification.
-// BUG-004: This is informational only - we log warnings but don't fail the session.
-// The validation helps identify provider inventory mismatches.
+// BUG-004: The session is never failed here. When the instance reports an older
+// CUDA or driver version than its offer advertised, the mismatch is recorded as
+// an offer failure so inventory with inaccurate versions is degraded.
 func (s *Service) validateCUDAVersionAsync(session *models.Session, privateKey string, logger *slog.Logger) {
 	// Use a short timeout for validation - we don't want to hold resources
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -1360,8 +1698,13 @@ func (s *Service) validateCUDAVersionAsync(session *models.Session, privateKey s
 		sshverify.WithExecutorCommandTimeout(15*time.Second),
 	)
 
-	conn, err := executor.Connect(ctx, session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+	conn, err := executor.Connect(s.pinHostKey(ctx, session), session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
 	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+			return
+		}
 		logger.Debug("CUDA validation: failed to connect for validation",
 			slog.String("error", err.Error()))
 		return
@@ -1382,8 +1725,95 @@ func (s *Service) validateCUDAVersionAsync(session *models.Session, privateKey s
 		slog.String("session_id", session.ID),
 		slog.String("provider", session.Provider))
 
-	// TODO: Compare with expected CUDA version from offer/template if available
-	// For now, we just log the detected version for observability
+	reason := cudaMismatch(session, cudaInfo)
+	if reason == "" {
+		return
+	}
+	logger.Warn("CUDA validation: instance does not match advertised versions",
+		slog.String("reason", reason),
+		slog.String("session_id", session.ID),
+		slog.String("offer_id", session.OfferID),
+		slog.String("provider", session.Provider))
+	if s.inventory != nil {
+		s.inventory.RecordOfferFailure(session.OfferID, session.Provider, session.GPUType, session.MachineID, string(models.FailureCUDAMismatch), reason)
+	}
+}
+
+// cudaMismatch compares the versions detected on an instance with those its
+// offer advertised, returning why they do not match or "" if they do.
+// Versions newer than advertised, and versions the offer did not advertise,
+// are not mismatches.
+func cudaMismatch(session *models.Session, detected *sshverify.CUDAInfo) string {
+	var reasons []string
+	// nvidia-smi reports one decimal place, so allow for rounding in the offer
+	if session.CUDAVersion > 0 && detected.CUDAVersion != "" && detected.CUDAVersionFloat() < session.CUDAVersion-0.05 {
+		reasons = append(reasons, fmt.Sprintf("CUDA %s, advertised %.1f", detected.CUDAVersion, session.CUDAVersion))
+	}
+	if session.DriverVersion != "" && detected.DriverVersion != "" && models.CompareVersions(detected.DriverVersion, session.DriverVersion) < 0 {
+		reasons = append(reasons, fmt.Sprintf("driver %s, advertised %s", detected.DriverVersion, session.DriverVersion))
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return "instance reports " + strings.Join(reasons, " and ")
+}
+
+// measureThroughputAsync measures the instance's disk write speed, and its
+// download speed when a test URL is configured, and records them on the
+// session. Informational only - does not fail the session.
+func (s *Service) measureThroughputAsync(session *models.Session, privateKey string, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	executor := sshverify.NewExecutor(
+		sshverify.WithExecutorConnectTimeout(10*time.Second),
+		sshverify.WithExecutorCommandTimeout(20*time.Second),
+	)
+
+	conn, err := executor.Connect(s.pinHostKey(ctx, session), session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+			return
+		}
+		logger.Debug("throughput check: failed to connect",
+			slog.String("error", err.Error()))
+		return
+	}
+	defer conn.Close()
+
+	diskMBps, err := executor.MeasureDiskWrite(ctx, conn)
+	if err != nil {
+		logger.Warn("throughput check: failed to measure disk write speed",
+			slog.String("error", err.Error()),
+			slog.String("session_id", session.ID))
+	}
+
+	var inetDownMbps float64
+	if s.bandwidthTestURL != "" {
+		inetDownMbps, err = executor.MeasureDownload(ctx, conn, s.bandwidthTestURL)
+		if err != nil {
+			logger.Warn("throughput check: failed to measure download speed",
+				slog.String("error", err.Error()),
+				slog.String("session_id", session.ID))
+		}
+	}
+
+	if diskMBps == 0 && inetDownMbps == 0 {
+		return
+	}
+	logger.Info("throughput check: measured",
+		slog.Float64("disk_bw_mbps", diskMBps),
+		slog.Float64("inet_down_mbps", inetDownMbps),
+		slog.String("session_id", session.ID),
+		slog.String("provider", session.Provider))
+
+	if err := s.store.UpdateThroughput(ctx, session.ID, inetDownMbps, diskMBps); err != nil {
+		logger.Warn("throughput check: failed to record measurements",
+			slog.String("error", err.Error()),
+			slog.String("session_id", session.ID))
+	}
 }
 
 // validateDiskSpaceAsync checks available disk space after SSH verification.
@@ -1397,8 +1827,13 @@ func (s *Service) validateDiskSpaceAsync(session *models.Session, privateKey str
 		sshverify.WithExecutorCommandTimeout(15*time.Second),
 	)
 
-	conn, err := executor.Connect(ctx, session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+	conn, err := executor.Connect(s.pinHostKey(ctx, session), session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
 	if err != nil {
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+			return
+		}
 		logger.Debug("disk check: failed to connect",
 			slog.String("error", err.Error()))
 		return
@@ -1450,31 +1885,17 @@ func (s *Service) validateDiskSpaceAsync(session *models.Session, privateKey str
 	}
 }
 
-// classifyInstanceStopReason provides a more descriptive failure reason based on
-// the instance status and error message from the provider.
-func classifyInstanceStopReason(status, errorMsg string) string {
-	base := fmt.Sprintf("instance stopped unexpectedly: %s", status)
-
-	if errorMsg != "" {
-		base += fmt.Sprintf(" (%s)", errorMsg)
-	}
-
-	// Add likely cause hints based on known patterns
-	lower := strings.ToLower(status + " " + errorMsg)
-	switch {
-	case strings.Contains(lower, "loading"):
-		base += " — likely cause: image pull failed, disk full, or driver incompatibility"
-	case strings.Contains(lower, "error"):
-		base += " — likely cause: runtime crash, OOM, or configuration error"
-	case strings.Contains(lower, "exited"):
-		base += " — likely cause: entrypoint failed or OOM kill"
-	}
-
-	return base
+// failSession marks a session as failed, recording the failure category and
+// its detail for failure analytics
+func (s *Service) failSession(ctx context.Context, session *models.Session, category models.FailureCategory, detail, reason string) {
+	session.FailureCategory = category
+	session.FailureDetail = detail
+	s.terminateSession(ctx, session, models.StatusFailed, reason, models.WebhookEventSessionFailed)
 }
 
-// failSession marks a session as failed
-func (s *Service) failSession(ctx context.Context, session *models.Session, reason string) {
+// terminateSession destroys the session's instance, moves it to a terminal
+// status, notifies the consumer and records the final cost.
+func (s *Service) terminateSession(ctx context.Context, session *models.Session, status models.SessionStatus, reason string, event models.WebhookEventType) {
 	// Destroy provider instance if one exists (defensive — prevents orphans)
 	if session.ProviderID != "" {
 		if prov, err := s.providers.Get(session.Provider); err == nil {
@@ -1497,29 +1918,95 @@ func (s *Service) failSession(ctx context.Context, session *models.Session, reas
 		}
 	}
 
-	oldStatus := session.Status
-	session.Status = models.StatusFailed
+	session.Status = status
 	session.Error = reason
 	session.StoppedAt = s.now()
 	if err := s.store.Update(ctx, session); err != nil {
-		s.logger.Error("failed to update session to failed",
+		s.logger.Error("failed to update session to "+string(status),
 			slog.String("session_id", session.ID),
 			slog.String("error", err.Error()))
 	}
 
-	// Bug #46 fix: Update metrics gauge on state transition
-	metrics.UpdateSessionStatus(session.Provider, string(oldStatus), string(models.StatusFailed))
+	s.notify(ctx, event, session)
 
 	// Record final cost so short-lived failed sessions are captured
 	if s.costRecorder != nil {
 		if err := s.costRecorder.RecordFinalCost(ctx, session); err != nil {
-			s.logger.Error("failed to record final cost for failed session",
+			s.logger.Error("failed to record final cost for "+string(status)+" session",
 				slog.String("session_id", session.ID),
 				slog.String("error", err.Error()))
 		}
 	}
 }
 
+// notify sends a session event to the notifier, if one is configured.
+// Cancellation is detached so events still queue after a verify timeout.
+func (s *Service) notify(ctx context.Context, eventType models.WebhookEventType, session *models.Session) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.NotifySession(context.WithoutCancel(ctx), eventType, session)
+}
+
+// notifyRunning announces that a session is running. The private key of a
+// replacement for a preempted session is kept for TakeReplacementKey, and a
+// failover event tells the consumer where their workload moved to.
+func (s *Service) notifyRunning(ctx context.Context, session *models.Session, privateKey string) {
+	s.notify(ctx, models.WebhookEventSessionRunning, session)
+
+	if session.RetryParentID == "" {
+		return
+	}
+	parent, err := s.store.Get(ctx, session.RetryParentID)
+	if err != nil || parent.Status != models.StatusPreempted {
+		return
+	}
+	if privateKey != "" {
+		s.replacementKeysMu.Lock()
+		s.replacementKeys[session.ID] = privateKey
+		s.replacementKeysMu.Unlock()
+	}
+
+	if fn, ok := s.notifier.(FailoverNotifier); ok {
+		fn.NotifyFailover(context.WithoutCancel(ctx), models.SessionFailover{
+			PreemptedSessionID: parent.ID,
+			Session:            session.ToResponse(),
+		})
+	}
+}
+
+// pinHostKey returns a context whose SSH connections verify the session's
+// host key. The first key seen is recorded on the session, which the caller
+// persists; after that a different key is refused.
+func (s *Service) pinHostKey(ctx context.Context, session *models.Session) context.Context {
+	if session.SSHHostKeyFingerprint != "" {
+		return sshverify.WithHostKeyCallback(ctx, sshverify.TrustOnFirstUse(session.SSHHostKeyFingerprint, nil))
+	}
+	return sshverify.WithHostKeyCallback(ctx, sshverify.TrustOnFirstUse("", func(fingerprint string) {
+		session.SSHHostKeyFingerprint = fingerprint
+	}))
+}
+
+// reportHostKeyMismatch alerts that a session's instance presented a
+// different host key than the one pinned on first use
+func (s *Service) reportHostKeyMismatch(ctx context.Context, session *models.Session, mismatch *sshverify.HostKeyMismatchError) {
+	s.logger.Error("SSH host key changed, refusing connection",
+		slog.String("session_id", session.ID),
+		slog.String("provider", session.Provider),
+		slog.String("addr", mismatch.Addr),
+		slog.String("expected", mismatch.Expected),
+		slog.String("actual", mismatch.Actual))
+	metrics.RecordSSHHostKeyMismatch(session.Provider)
+
+	if hn, ok := s.notifier.(HostKeyNotifier); ok {
+		hn.NotifyHostKeyChanged(context.WithoutCancel(ctx), models.SessionHostKeyChange{
+			Session:             session.ToResponse(),
+			ExpectedFingerprint: mismatch.Expected,
+			ActualFingerprint:   mismatch.Actual,
+		})
+	}
+}
+
 // generateSSHKeyPair generates an RSA SSH key pair
 func (s *Service) generateSSHKeyPair() (privateKeyPEM, publicKeyOpenSSH string, err error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, s.sshKeyBits)
@@ -1550,6 +2037,15 @@ func (s *Service) GetDeploymentID() string {
 	return s.deploymentID
 }
 
+// Stop ends the service's background work that outlives requests, such as
+// kernel log watches, and waits for it to finish
+func (s *Service) Stop() {
+	s.kernelWatchesMu.Lock()
+	s.cancel()
+	s.kernelWatchesMu.Unlock()
+	s.kernelWatches.Wait()
+}
+
 // WaitForVerificationComplete waits for all pending verification goroutines to complete.
 // This is primarily for testing to ensure no goroutine leaks.
 // Returns true if all verifications completed within the timeout, false otherwise.
@@ -1575,17 +2071,26 @@ func (s *Service) buildWorkloadConfig(req models.CreateSessionRequest) *provider
 		Quantization: req.Quantization,
 	}
 
-	// Determine workload type from session workload type
-	switch req.WorkloadType {
+	config.Type = providerWorkloadType(req.WorkloadType)
+
+	return config
+}
+
+// providerWorkloadType maps a session workload type to the inference server
+// a provider launches for it
+func providerWorkloadType(w models.WorkloadType) provider.WorkloadType {
+	switch w {
 	case models.WorkloadLLMVLLM:
-		config.Type = provider.WorkloadTypeVLLM
+		return provider.WorkloadTypeVLLM
 	case models.WorkloadLLMTGI:
-		config.Type = provider.WorkloadTypeTGI
+		return provider.WorkloadTypeTGI
+	case models.WorkloadLLMSGLang:
+		return provider.WorkloadTypeSGLang
+	case models.WorkloadLLMLlamaCpp:
+		return provider.WorkloadTypeLlamaCpp
 	default:
-		config.Type = provider.WorkloadTypeCustom
+		return provider.WorkloadTypeCustom
 	}
-
-	return config
 }
 
 // buildBenchmarkOnStart generates an on-start command that deploys and runs
@@ -1622,18 +2127,35 @@ func shellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
 }
 
-// waitForAPIVerifyAsync waits for API endpoint verification in the background
-func (s *Service) waitForAPIVerifyAsync(ctx context.Context, sessionID string, prov provider.Provider) {
+// waitForAPIVerifyAsync waits for API endpoint verification in the background,
+// until deadline. resumed is set when a restart interrupted the verification.
+func (s *Service) waitForAPIVerifyAsync(ctx context.Context, sessionID string, prov provider.Provider, deadline time.Time, resumed bool) {
 	logger := s.logger.With(slog.String("session_id", sessionID))
-	logger.Info("waiting for API verification")
+	logger.Info("waiting for API verification", slog.Bool("resumed", resumed))
 
 	start := time.Now()
 
+	// Workloads have no boot delay; their API is polled from the start,
+	// while warm-up watches the image pull and model load
+	warmup := false
+	if session, err := s.store.Get(ctx, sessionID); err == nil && !session.IsTerminal() {
+		warmup = s.warmupEnabled(ctx, session)
+		next := models.PhaseVerifying
+		if warmup {
+			next = models.PhaseWarmingUp
+		}
+		if phaseOrder[session.ProvisionPhase] < phaseOrder[next] {
+			if err := s.advancePhase(ctx, session, next); err != nil {
+				logger.Warn("failed to move session to "+string(next), slog.String("error", err.Error()))
+			}
+		}
+	}
+
 	// Poll for API info and verify connectivity
 	ticker := time.NewTicker(s.apiCheckInterval)
 	defer ticker.Stop()
 
-	timeout := time.NewTimer(s.apiVerifyTimeout)
+	timeout := time.NewTimer(time.Until(deadline))
 	defer timeout.Stop()
 
 	for {
@@ -1654,7 +2176,13 @@ func (s *Service) waitForAPIVerifyAsync(ctx context.Context, sessionID string, p
 				}
 			}
 
-			s.failSession(ctx, session, "API verification timeout")
+			// Name the warm-up stage that stalled instead of a bare timeout
+			detail, reason := "", "API verification timeout"
+			if warmup {
+				detail = warmupStage(session.Progress)
+				reason += ": " + warmupStageReasons[detail]
+			}
+			s.failSession(ctx, session, models.FailureAPITimeout, detail, reason)
 			metrics.RecordAPIVerifyFailure()
 			// Bug #94 fix: Record session destroyed when API verification times out
 			metrics.RecordSessionDestroyed(session.Provider, "api_verify_timeout")
@@ -1674,15 +2202,25 @@ func (s *Service) waitForAPIVerifyAsync(ctx context.Context, sessionID string, p
 				return
 			}
 
-			// Poll provider for connection info if we don't have it yet
-			if session.SSHHost == "" && session.ProviderID != "" {
-				status, err := prov.GetInstanceStatus(ctx, session.ProviderID)
+			warmingUp := warmup && session.ProvisionPhase == models.PhaseWarmingUp
+
+			// Poll provider for connection info if we don't have it yet,
+			// and for the container's progress while warming up
+			var status *provider.InstanceStatus
+			if (session.SSHHost == "" || warmingUp) && session.ProviderID != "" {
+				status, err = prov.GetInstanceStatus(ctx, session.ProviderID)
 				if err != nil {
 					logger.Debug("failed to get instance status", slog.String("error", err.Error()))
-					continue
+					status = nil
 				}
+			}
+			if warmingUp && !s.warmUp(ctx, session, prov, status, logger) {
+				return
+			}
+			if session.SSHHost == "" && status != nil {
 				if status.SSHHost != "" {
 					session.SSHHost = status.SSHHost
+					session.Progress.IPAssignedAt = time.Now()
 					if status.SSHPort != 0 {
 						session.SSHPort = status.SSHPort
 					}
@@ -1700,30 +2238,35 @@ func (s *Service) waitForAPIVerifyAsync(ctx context.Context, sessionID string, p
 
 			// Try API verification if we have host info
 			if session.SSHHost != "" && session.APIPort > 0 {
-				apiURL := fmt.Sprintf("http://%s:%d/health", session.SSHHost, session.APIPort)
+				healthPath := providerWorkloadType(session.WorkloadType).HealthPath()
+				apiURL := fmt.Sprintf("http://%s:%d%s", session.SSHHost, session.APIPort, healthPath)
 				logger.Debug("attempting API verification",
 					slog.String("url", apiURL))
 
-				// Try a health check
+				// Try a health check, then make sure the model is served
+				baseURL := fmt.Sprintf("http://%s:%d", session.SSHHost, session.APIPort)
 				err := s.httpVerifier.CheckHealth(ctx, apiURL)
+				if err == nil {
+					err = s.checkReady(ctx, session, baseURL)
+				}
 				if err == nil {
 					// API verified successfully
 					duration := time.Since(start)
 					logger.Info("API verification successful",
 						slog.Duration("duration", duration))
 
-					oldStatus := session.Status
-					session.Status = models.StatusRunning
-					session.APIEndpoint = fmt.Sprintf("http://%s:%d", session.SSHHost, session.APIPort)
-					if err := s.store.Update(ctx, session); err != nil {
+					session.APIEndpoint = baseURL
+					if err := s.advancePhase(ctx, session, models.PhaseRunning); err != nil {
 						logger.Error("failed to update session to running", slog.String("error", err.Error()))
 					}
 
-					// Bug #46 fix: Update metrics gauge on state transition
-					metrics.UpdateSessionStatus(session.Provider, string(oldStatus), string(models.StatusRunning))
-					metrics.RecordAPIVerifyDuration(session.Provider, duration)
-					// Bug #57 fix: Record provisioning duration when session becomes running
-					metrics.RecordProvisioningDuration(session.Provider, duration)
+					// Durations of a verification resumed partway are meaningless
+					if !resumed {
+						metrics.RecordAPIVerifyDuration(session.Provider, duration)
+						// Bug #57 fix: Record provisioning duration when session becomes running
+						metrics.RecordProvisioningDuration(session.Provider, duration)
+					}
+					s.notifyRunning(ctx, session, "")
 					return
 				}
 
@@ -1777,66 +2320,3 @@ func (v *DefaultHTTPVerifier) CheckHealth(ctx context.Context, url string) error
 
 	return fmt.Errorf("unhealthy status: %d", resp.StatusCode)
 }
-
-// classifySSHError categorizes SSH connection errors for logging
-// Returns: error_type (connection_refused, timeout, auth_failed, etc.)
-func classifySSHError(err error) string {
-	if err == nil {
-		return "none"
-	}
-
-	errStr := err.Error()
-
-	// Connection refused - instance not accepting connections yet
-	if strings.Contains(errStr, "connection refused") {
-		return "connection_refused"
-	}
-
-	// Connection timeout - network issues or firewall
-	if strings.Contains(errStr, "i/o timeout") ||
-		strings.Contains(errStr, "connection timed out") ||
-		strings.Contains(errStr, "deadline exceeded") {
-		return "timeout"
-	}
-
-	// No route to host - network unreachable
-	if strings.Contains(errStr, "no route to host") ||
-		strings.Contains(errStr, "network is unreachable") {
-		return "network_unreachable"
-	}
-
-	// DNS resolution failure
-	if strings.Contains(errStr, "no such host") ||
-		strings.Contains(errStr, "lookup") {
-		return "dns_failed"
-	}
-
-	// SSH handshake failures (auth)
-	if strings.Contains(errStr, "SSH handshake failed") ||
-		strings.Contains(errStr, "unable to authenticate") ||
-		strings.Contains(errStr, "permission denied") {
-		return "auth_failed"
-	}
-
-	// Private key issues
-	if strings.Contains(errStr, "failed to parse private key") {
-		return "key_parse_failed"
-	}
-
-	// Session/command failures
-	if strings.Contains(errStr, "failed to create session") ||
-		strings.Contains(errStr, "verify command failed") {
-		return "command_failed"
-	}
-
-	if strings.Contains(errStr, "unexpected packet") {
-		return "connection_closed"
-	}
-
-	// EOF typically means the connection was closed
-	if strings.Contains(errStr, "EOF") {
-		return "connection_closed"
-	}
-
-	return "unknown"
-}
@@ -3,14 +3,18 @@ package provisioner
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/service/budget"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -116,11 +120,13 @@ func (m *mockSessionStore) Get(ctx context.Context, id string) (*models.Session,
 func (m *mockSessionStore) Update(ctx context.Context, session *models.Session) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if _, ok := m.sessions[session.ID]; !ok {
+	stored, ok := m.sessions[session.ID]
+	if !ok {
 		return &SessionNotFoundError{ID: session.ID}
 	}
 	// Store a copy to avoid races when caller modifies the original
 	sessionCopy := *session
+	sessionCopy.RebootingUntil = stored.RebootingUntil
 	if session.ExposedPorts != nil {
 		sessionCopy.ExposedPorts = make([]int, len(session.ExposedPorts))
 		copy(sessionCopy.ExposedPorts, session.ExposedPorts)
@@ -129,6 +135,33 @@ func (m *mockSessionStore) Update(ctx context.Context, session *models.Session)
 	return nil
 }
 
+func (m *mockSessionStore) SetRebootingUntil(ctx context.Context, sessionID string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return &SessionNotFoundError{ID: sessionID}
+	}
+	session.RebootingUntil = until
+	return nil
+}
+
+func (m *mockSessionStore) UpdateThroughput(ctx context.Context, sessionID string, inetDownMbps, diskBandwidthMBps float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return &SessionNotFoundError{ID: sessionID}
+	}
+	if inetDownMbps > 0 {
+		session.MeasuredInetDownMbps = inetDownMbps
+	}
+	if diskBandwidthMBps > 0 {
+		session.MeasuredDiskBandwidthMBps = diskBandwidthMBps
+	}
+	return nil
+}
+
 func (m *mockSessionStore) GetActiveSessionByConsumerAndOffer(ctx context.Context, consumerID, offerID string) (*models.Session, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -181,6 +214,7 @@ type mockProvider struct {
 	createInstanceFn  func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error)
 	destroyInstanceFn func(ctx context.Context, instanceID string) error
 	getStatusFn       func(ctx context.Context, instanceID string) (*provider.InstanceStatus, error)
+	features          map[provider.ProviderFeature]bool
 
 	mu                sync.Mutex
 	createCalls       int
@@ -261,7 +295,7 @@ func (m *mockProvider) getDestroyCalls() int {
 }
 
 func (m *mockProvider) SupportsFeature(feature provider.ProviderFeature) bool {
-	return false
+	return m.features[feature]
 }
 
 func newTestLogger() *slog.Logger {
@@ -556,9 +590,11 @@ func TestService_DestroySession_VerificationFails(t *testing.T) {
 	}
 	store.sessions[session.ID] = session
 
+	quarantine := newMockQuarantine()
 	svc := New(store, registry,
 		WithLogger(newTestLogger()),
-		WithDestroyRetries(3))
+		WithDestroyRetries(3),
+		WithQuarantine(quarantine))
 
 	ctx := context.Background()
 	err := svc.DestroySession(ctx, "sess-001")
@@ -568,6 +604,52 @@ func TestService_DestroySession_VerificationFails(t *testing.T) {
 	assert.True(t, errors.As(err, &verifyErr))
 	assert.Equal(t, "sess-001", verifyErr.SessionID)
 	assert.Equal(t, 3, verifyErr.Attempts)
+
+	// The instance stays quarantined for an operator
+	q, ok := quarantine.get("vastai", "instance-123")
+	require.True(t, ok)
+	assert.Equal(t, "sess-001", q.SessionID)
+	assert.Equal(t, models.QuarantineDestroy, q.Source)
+	assert.Equal(t, verifyErr.Error(), q.Reason)
+
+	// ...until a later destroy succeeds
+	svc.releaseQuarantine(ctx, session)
+	_, ok = quarantine.get("vastai", "instance-123")
+	assert.False(t, ok)
+}
+
+// mockQuarantine implements Quarantine for testing
+type mockQuarantine struct {
+	mu        sync.Mutex
+	instances map[string]*models.QuarantinedInstance
+}
+
+func newMockQuarantine() *mockQuarantine {
+	return &mockQuarantine{instances: make(map[string]*models.QuarantinedInstance)}
+}
+
+func (m *mockQuarantine) Quarantine(ctx context.Context, q *models.QuarantinedInstance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instances[q.Provider+"/"+q.InstanceID] = q
+	return nil
+}
+
+func (m *mockQuarantine) Resolve(ctx context.Context, providerName, instanceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.instances[providerName+"/"+instanceID]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(m.instances, providerName+"/"+instanceID)
+	return nil
+}
+
+func (m *mockQuarantine) get(providerName, instanceID string) (*models.QuarantinedInstance, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, ok := m.instances[providerName+"/"+instanceID]
+	return q, ok
 }
 
 func TestService_DestroySession_NotFound(t *testing.T) {
@@ -758,6 +840,436 @@ func TestService_CreateSession_DuplicatePrevention(t *testing.T) {
 	assert.Equal(t, session1.ID, dupErr.SessionID)
 }
 
+// mockBudgetChecker implements BudgetChecker for testing
+type mockBudgetChecker struct {
+	err           error
+	calls         int
+	lastConsumer  string
+	lastProjected float64
+}
+
+func (m *mockBudgetChecker) ReserveBudget(ctx context.Context, consumerID string, projectedCost float64) (func(), error) {
+	m.calls++
+	m.lastConsumer = consumerID
+	m.lastProjected = projectedCost
+	if m.err != nil {
+		return nil, m.err
+	}
+	return func() {}, nil
+}
+
+func TestService_CreateSession_BudgetExceeded(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+	budgetErr := errors.New("budget exceeded")
+	checker := &mockBudgetChecker{err: budgetErr}
+
+	svc := New(store, registry, WithLogger(newTestLogger()), WithBudgetChecker(checker))
+
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 4,
+	}
+	offer := &models.GPUOffer{Provider: "vastai", ProviderID: "123", PricePerHour: 1.25}
+
+	_, err := svc.CreateSession(context.Background(), req, offer)
+	require.ErrorIs(t, err, budgetErr)
+
+	assert.Equal(t, 1, checker.calls)
+	assert.Equal(t, "consumer-001", checker.lastConsumer)
+	assert.Equal(t, 5.0, checker.lastProjected)
+
+	// Nothing was provisioned or persisted
+	assert.Equal(t, 0, prov.createCalls)
+	assert.Empty(t, store.sessions)
+}
+
+// activeSessions lists a mockSessionStore's active sessions for the budget service
+type activeSessions struct {
+	*mockSessionStore
+}
+
+func (a activeSessions) GetActiveSessions(ctx context.Context) ([]*models.Session, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var active []*models.Session
+	for _, session := range a.sessions {
+		if session.IsActive() {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func TestService_CreateSession_ConcurrentCreatesHoldBudget(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Migrate(context.Background()))
+
+	store := newMockSessionStore()
+	budgets := budget.New(storage.NewBudgetStore(db), storage.NewCostStore(db), activeSessions{store},
+		budget.WithLogger(newTestLogger()))
+	// Room for two 4-hour sessions at $1/hr, which commit at least $3 each
+	// beyond the current hour, but not three
+	require.NoError(t, budgets.SetBudget(context.Background(), &models.Budget{
+		Scope: models.BudgetScopeConsumer, ScopeID: "consumer-001", Period: models.BudgetPeriodMonthly, LimitUSD: 9,
+	}))
+
+	prov := newMockProvider("vastai")
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()), WithBudgetChecker(budgets))
+
+	const creates = 8
+	var wg sync.WaitGroup
+	errs := make([]error, creates)
+	for i := range creates {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := models.CreateSessionRequest{
+				ConsumerID:     "consumer-001",
+				OfferID:        fmt.Sprintf("offer-%d", i),
+				WorkloadType:   models.WorkloadLLM,
+				ReservationHrs: 4,
+			}
+			offer := &models.GPUOffer{ID: req.OfferID, Provider: "vastai", ProviderID: req.OfferID, PricePerHour: 1}
+			_, errs[i] = svc.CreateSession(context.Background(), req, offer)
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		var exceeded *budget.ExceededError
+		if err == nil {
+			created++
+		} else {
+			assert.ErrorAs(t, err, &exceeded)
+		}
+	}
+	assert.Equal(t, 2, created, "concurrent creates must not jointly overrun the cap")
+}
+
+// balanceProvider adds account balance support to mockProvider
+type balanceProvider struct {
+	*mockProvider
+	balance float64
+}
+
+func (b *balanceProvider) GetAccountBalance(ctx context.Context) (*provider.AccountBalance, error) {
+	return &provider.AccountBalance{Balance: b.balance, Currency: "USD"}, nil
+}
+
+func TestService_CreateSession_InsufficientBalance(t *testing.T) {
+	store := newMockSessionStore()
+	prov := &balanceProvider{mockProvider: newMockProvider("vastai"), balance: 3.00}
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+
+	svc := New(store, registry, WithLogger(newTestLogger()))
+
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 4,
+	}
+	offer := &models.GPUOffer{Provider: "vastai", ProviderID: "123", PricePerHour: 1.00}
+
+	_, err := svc.CreateSession(context.Background(), req, offer)
+	var balanceErr *InsufficientBalanceError
+	require.ErrorAs(t, err, &balanceErr)
+	assert.Equal(t, 3.00, balanceErr.Balance)
+	assert.Equal(t, 4.00, balanceErr.ProjectedCost)
+	assert.Equal(t, 0, prov.createCalls)
+}
+
+func TestService_CreateSession_InsufficientVRAM(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+
+	svc := New(store, registry, WithLogger(newTestLogger()))
+
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+		ModelID:        "meta-llama/Meta-Llama-3.1-70B-Instruct",
+	}
+	offer := &models.GPUOffer{Provider: "vastai", ProviderID: "123", PricePerHour: 0.50, VRAM: 24, GPUCount: 1}
+
+	_, err := svc.CreateSession(context.Background(), req, offer)
+	var vramErr *InsufficientVRAMError
+	require.ErrorAs(t, err, &vramErr)
+	assert.Equal(t, 24, vramErr.AvailableGB)
+	assert.Equal(t, 0, prov.createCalls)
+	assert.Empty(t, store.sessions)
+}
+
+// mockFeatureFlags returns fixed flag values and records evaluation keys
+type mockFeatureFlags struct {
+	flags        map[string]bool
+	enabledKeys  []string
+	evaluateKeys []string
+}
+
+func (m *mockFeatureFlags) Enabled(ctx context.Context, name, key string, def bool) bool {
+	m.enabledKeys = append(m.enabledKeys, key)
+	if v, ok := m.flags[name]; ok {
+		return v
+	}
+	return def
+}
+
+func (m *mockFeatureFlags) Evaluate(ctx context.Context, key string) map[string]bool {
+	m.evaluateKeys = append(m.evaluateKeys, key)
+	return m.flags
+}
+
+func TestService_CreateSession_ProviderDisabled(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+	flags := &mockFeatureFlags{flags: map[string]bool{"provider.vastai": false}}
+
+	svc := New(store, registry, WithLogger(newTestLogger()), WithFeatureFlags(flags))
+
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}
+	offer := &models.GPUOffer{Provider: "vastai", ProviderID: "123", PricePerHour: 0.50}
+
+	_, err := svc.CreateSession(context.Background(), req, offer)
+	var disabledErr *ProviderDisabledError
+	require.ErrorAs(t, err, &disabledErr)
+	assert.Equal(t, "vastai", disabledErr.Provider)
+	assert.Equal(t, []string{"consumer-001"}, flags.enabledKeys)
+	assert.Equal(t, 0, prov.createCalls)
+	assert.Empty(t, store.sessions)
+}
+
+func TestService_CreateSession_CloudInit(t *testing.T) {
+	cloudInit := &models.CloudInitConfig{RunCmd: []string{"pip install my-tool"}}
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+		CloudInit:      cloudInit,
+	}
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		store := newMockSessionStore()
+		prov := newMockProvider("vastai")
+		svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+
+		_, err := svc.CreateSession(context.Background(), req,
+			&models.GPUOffer{Provider: "vastai", ProviderID: "123", PricePerHour: 0.50})
+		var cloudInitErr *CloudInitNotSupportedError
+		require.ErrorAs(t, err, &cloudInitErr)
+		assert.Equal(t, "vastai", cloudInitErr.Provider)
+		assert.Equal(t, 0, prov.createCalls)
+		assert.Empty(t, store.sessions)
+	})
+
+	t.Run("passed to provider", func(t *testing.T) {
+		prov := newMockProvider("tensordock")
+		prov.features = map[provider.ProviderFeature]bool{provider.FeatureCloudInit: true}
+		var got *models.CloudInitConfig
+		prov.createInstanceFn = func(ctx context.Context, r provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+			got = r.CloudInit
+			return &provider.InstanceInfo{ProviderInstanceID: "inst-1", SSHHost: "192.168.1.100", SSHPort: 22}, nil
+		}
+		svc := New(newMockSessionStore(), NewSimpleProviderRegistry([]provider.Provider{prov}),
+			WithLogger(newTestLogger()),
+			WithSSHVerifier(NewMockSSHVerifier()),
+			WithSSHCheckInterval(10*time.Millisecond))
+
+		_, err := svc.CreateSession(context.Background(), req,
+			&models.GPUOffer{Provider: "tensordock", ProviderID: "123", PricePerHour: 0.50})
+		require.NoError(t, err)
+		assert.Equal(t, cloudInit, got)
+		svc.WaitForVerificationComplete(5 * time.Second)
+	})
+}
+
+func TestService_CreateSession_PassesFeatureFlags(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+	flags := &mockFeatureFlags{flags: map[string]bool{"tensordock.dedicated_ip": false}}
+
+	svc := New(store, registry, WithLogger(newTestLogger()), WithFeatureFlags(flags))
+
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}
+	offer := &models.GPUOffer{Provider: "vastai", ProviderID: "123", PricePerHour: 0.50}
+
+	session, err := svc.CreateSession(context.Background(), req, offer)
+	require.NoError(t, err)
+
+	// Behavior flags are bucketed by session
+	assert.Equal(t, []string{session.ID}, flags.evaluateKeys)
+	assert.False(t, prov.lastCreateRequest.FeatureEnabled("tensordock.dedicated_ip", true))
+}
+
+// sshKeyProvider adds SSH key attachment support to mockProvider
+type sshKeyProvider struct {
+	*mockProvider
+	attachedInstance string
+	attachedKey      string
+}
+
+func (p *sshKeyProvider) AttachSSHKey(ctx context.Context, instanceID string, sshPublicKey string) error {
+	p.attachedInstance = instanceID
+	p.attachedKey = sshPublicKey
+	return nil
+}
+
+func TestService_RegenerateSSHKey(t *testing.T) {
+	store := newMockSessionStore()
+	prov := &sshKeyProvider{mockProvider: newMockProvider("vastai")}
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+
+	svc := New(store, registry, WithLogger(newTestLogger()))
+
+	ctx := context.Background()
+	session := &models.Session{
+		ID:           "sess-1",
+		ConsumerID:   "consumer-001",
+		Provider:     "vastai",
+		ProviderID:   "inst-1",
+		Status:       models.StatusRunning,
+		SSHPublicKey: "ssh-ed25519 OLDKEY",
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	privateKey, err := svc.RegenerateSSHKey(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Contains(t, privateKey, "PRIVATE KEY")
+
+	assert.Equal(t, "inst-1", prov.attachedInstance)
+	updated, err := store.Get(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, prov.attachedKey, updated.SSHPublicKey)
+	assert.NotEqual(t, "ssh-ed25519 OLDKEY", updated.SSHPublicKey)
+}
+
+func TestService_RegenerateSSHKey_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not running", func(t *testing.T) {
+		store := newMockSessionStore()
+		prov := &sshKeyProvider{mockProvider: newMockProvider("vastai")}
+		svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}), WithLogger(newTestLogger()))
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusStopped,
+		}))
+
+		_, err := svc.RegenerateSSHKey(ctx, "sess-1")
+		var notRunning *SessionNotRunningError
+		require.ErrorAs(t, err, &notRunning)
+		assert.Empty(t, prov.attachedKey)
+	})
+
+	t.Run("provider unsupported", func(t *testing.T) {
+		store := newMockSessionStore()
+		svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}), WithLogger(newTestLogger()))
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID: "sess-1", Provider: "vastai", ProviderID: "inst-1", Status: models.StatusRunning,
+		}))
+
+		_, err := svc.RegenerateSSHKey(ctx, "sess-1")
+		var notSupported *SSHKeyRotationNotSupportedError
+		require.ErrorAs(t, err, &notSupported)
+	})
+}
+
+// recordingNotifier records session events
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []models.WebhookEventType
+}
+
+func (r *recordingNotifier) NotifySession(ctx context.Context, eventType models.WebhookEventType, session *models.Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType)
+}
+
+func (r *recordingNotifier) getEvents() []models.WebhookEventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.WebhookEventType(nil), r.events...)
+}
+
+func TestService_NotifiesSessionEvents(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+	notifier := &recordingNotifier{}
+
+	svc := New(store, registry,
+		WithLogger(newTestLogger()),
+		WithNotifier(notifier),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithSSHVerifyTimeout(100*time.Millisecond),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}
+	offer := &models.GPUOffer{Provider: "vastai", ProviderID: "123"}
+
+	session, err := svc.CreateSession(ctx, req, offer)
+	require.NoError(t, err)
+	require.NoError(t, svc.DestroySession(ctx, session.ID))
+
+	events := notifier.getEvents()
+	require.GreaterOrEqual(t, len(events), 2)
+	assert.Equal(t, models.WebhookEventSessionCreated, events[0])
+	assert.Contains(t, events, models.WebhookEventSessionDestroyed)
+}
+
+func TestService_NotifiesSessionFailed(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	prov.createInstanceFn = func(ctx context.Context, req provider.CreateInstanceRequest) (*provider.InstanceInfo, error) {
+		return nil, errors.New("no capacity")
+	}
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+	notifier := &recordingNotifier{}
+
+	svc := New(store, registry, WithLogger(newTestLogger()), WithNotifier(notifier))
+
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}
+	_, err := svc.CreateSession(context.Background(), req, &models.GPUOffer{Provider: "vastai", ProviderID: "123"})
+	require.Error(t, err)
+
+	assert.Equal(t, []models.WebhookEventType{models.WebhookEventSessionFailed}, notifier.getEvents())
+}
+
 func TestService_CreateSession_AllowsAfterStopped(t *testing.T) {
 	store := newMockSessionStore()
 	prov := newMockProvider("vastai")
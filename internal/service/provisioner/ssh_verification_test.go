@@ -2,17 +2,24 @@ package provisioner
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"os"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 )
 
 // TestSSHVerification_SuccessTransitionsToRunning verifies that successful SSH verification
@@ -73,6 +80,54 @@ func TestSSHVerification_SuccessTransitionsToRunning(t *testing.T) {
 	}
 }
 
+// TestSSHVerification_NativeKeyRegistrationSkipsCloudInitDelay verifies that
+// TensorDock sessions skip the cloud-init wait when the provider registers the
+// SSH key natively
+func TestSSHVerification_NativeKeyRegistrationSkipsCloudInitDelay(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("tensordock")
+	prov.features = map[provider.ProviderFeature]bool{provider.FeatureSSHKeyRegistration: true}
+	registry := NewSimpleProviderRegistry([]provider.Provider{prov})
+
+	mockSSH := NewMockSSHVerifier()
+	mockSSH.SetSucceed(true)
+
+	svc := New(store, registry,
+		WithLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))),
+		WithSSHVerifier(mockSSH),
+		WithSSHVerifyTimeout(5*time.Second),
+		WithSSHCheckInterval(100*time.Millisecond))
+
+	defer func() {
+		require.True(t, svc.WaitForVerificationComplete(10*time.Second), "verification goroutines should complete")
+	}()
+
+	ctx := context.Background()
+	req := models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}
+	offer := &models.GPUOffer{
+		Provider:   "tensordock",
+		ProviderID: "123",
+		GPUType:    "RTX4090",
+	}
+
+	session, err := svc.CreateSession(ctx, req, offer)
+	require.NoError(t, err)
+
+	// Well under TensorDockCloudInitDelay
+	require.Eventually(t, func() bool {
+		s, err := store.Get(ctx, session.ID)
+		if err != nil {
+			return false
+		}
+		return s.Status == models.StatusRunning
+	}, 5*time.Second, 50*time.Millisecond, "Session should not wait for cloud-init")
+}
+
 // TestSSHVerification_TimeoutDestroysInstance verifies that SSH verification timeout
 // destroys the instance and fails the session
 func TestSSHVerification_TimeoutDestroysInstance(t *testing.T) {
@@ -525,3 +580,151 @@ func TestSSHVerification_InstanceStoppedFailsFast(t *testing.T) {
 	// Verify provider destroy was called (once by stopped-instance handler, once defensively by failSession)
 	assert.Equal(t, 2, prov.destroyCalls, "Instance should be destroyed when it stops unexpectedly (caller + failSession)")
 }
+
+// hostKeyVerifier presents a host key to the host key callback the
+// provisioner puts in the context, like a real SSH handshake
+type hostKeyVerifier struct {
+	key ssh.PublicKey
+}
+
+func (v *hostKeyVerifier) VerifyOnce(ctx context.Context, host string, port int, user, privateKey string) error {
+	addr := &net.TCPAddr{IP: net.ParseIP(host), Port: port}
+	if err := sshverify.HostKeyCallback(ctx)(net.JoinHostPort(host, strconv.Itoa(port)), addr, v.key); err != nil {
+		return fmt.Errorf("SSH handshake failed: %w", err)
+	}
+	return nil
+}
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return key
+}
+
+// hostKeyNotifier records host key change alerts
+type hostKeyNotifier struct {
+	recordingNotifier
+	changes []models.SessionHostKeyChange
+}
+
+func (n *hostKeyNotifier) NotifyHostKeyChanged(ctx context.Context, change models.SessionHostKeyChange) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.changes = append(n.changes, change)
+}
+
+func (n *hostKeyNotifier) getChanges() []models.SessionHostKeyChange {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]models.SessionHostKeyChange(nil), n.changes...)
+}
+
+func TestSSHVerification_RecordsHostKeyOnFirstUse(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	hostKey := newHostKey(t)
+
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(&hostKeyVerifier{key: hostKey}),
+		WithSSHVerifyTimeout(5*time.Second),
+		WithSSHCheckInterval(10*time.Millisecond))
+	defer func() {
+		require.True(t, svc.WaitForVerificationComplete(10*time.Second), "verification goroutines should complete")
+	}()
+
+	ctx := context.Background()
+	session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+		ConsumerID:     "consumer-001",
+		OfferID:        "offer-123",
+		WorkloadType:   models.WorkloadLLM,
+		ReservationHrs: 1,
+	}, &models.GPUOffer{Provider: "vastai", ProviderID: "123", GPUType: "RTX4090"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		s, err := store.Get(ctx, session.ID)
+		return err == nil && s.Status == models.StatusRunning
+	}, 5*time.Second, 20*time.Millisecond)
+
+	s, err := store.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sshverify.Fingerprint(hostKey), s.SSHHostKeyFingerprint)
+}
+
+func TestSSHVerification_HostKeyChangedFailsAndAlerts(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	notifier := &hostKeyNotifier{}
+	pinned := sshverify.Fingerprint(newHostKey(t))
+	presented := newHostKey(t)
+
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithNotifier(notifier),
+		WithSSHVerifier(&hostKeyVerifier{key: presented}),
+		WithSSHVerifyTimeout(5*time.Second),
+		WithSSHCheckInterval(10*time.Millisecond))
+
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, store.Create(ctx, &models.Session{
+		ID:                    "sess-pinned",
+		ConsumerID:            "consumer-001",
+		Provider:              "vastai",
+		ProviderID:            "inst-1",
+		OfferID:               "vastai-1",
+		GPUType:               "RTX4090",
+		Status:                models.StatusProvisioning,
+		SSHHost:               "192.168.1.100",
+		SSHPort:               22,
+		SSHUser:               "root",
+		SSHHostKeyFingerprint: pinned,
+		CreatedAt:             now,
+		ExpiresAt:             now.Add(time.Hour),
+	}))
+
+	svc.waitForSSHVerifyAsync(ctx, "sess-pinned", "private-key", prov)
+
+	s, err := store.Get(ctx, "sess-pinned")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, s.Status)
+	assert.Equal(t, "host_key_changed", s.FailureDetail)
+	assert.Equal(t, pinned, s.SSHHostKeyFingerprint, "the pinned key is kept")
+
+	changes := notifier.getChanges()
+	require.Len(t, changes, 1, "a changed key is reported once per verification")
+	assert.Equal(t, "sess-pinned", changes[0].Session.ID)
+	assert.Equal(t, pinned, changes[0].ExpectedFingerprint)
+	assert.Equal(t, sshverify.Fingerprint(presented), changes[0].ActualFingerprint)
+}
+
+func TestCUDAMismatch(t *testing.T) {
+	detected := &sshverify.CUDAInfo{CUDAVersion: "12.2", CUDAMajor: 12, CUDAMinor: 2, DriverVersion: "535.104.05"}
+
+	tests := []struct {
+		name       string
+		advertised models.Session
+		want       string
+	}{
+		{"nothing advertised", models.Session{}, ""},
+		{"matches", models.Session{CUDAVersion: 12.2, DriverVersion: "535.104.05"}, ""},
+		{"newer than advertised", models.Session{CUDAVersion: 12.0, DriverVersion: "525.60"}, ""},
+		{"rounding in the offer", models.Session{CUDAVersion: 12.2000001}, ""},
+		{"older CUDA", models.Session{CUDAVersion: 12.4}, "instance reports CUDA 12.2, advertised 12.4"},
+		{"older driver", models.Session{DriverVersion: "550.54.14"}, "instance reports driver 535.104.05, advertised 550.54.14"},
+		{"both older", models.Session{CUDAVersion: 12.4, DriverVersion: "550.54.14"},
+			"instance reports CUDA 12.2, advertised 12.4 and driver 535.104.05, advertised 550.54.14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cudaMismatch(&tt.advertised, detected))
+		})
+	}
+
+	// Versions the instance did not report are not compared
+	assert.Empty(t, cudaMismatch(&models.Session{DriverVersion: "550.54.14"}, &sshverify.CUDAInfo{CUDAVersion: "12.2", CUDAMajor: 12, CUDAMinor: 2}))
+}
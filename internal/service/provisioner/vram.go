@@ -0,0 +1,87 @@
+package provisioner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// VRAMEstimation contains the breakdown of estimated GPU memory requirements.
+type VRAMEstimation struct {
+	ModelWeightGB   float64 `json:"model_weight_gb"`
+	KVCacheGB       float64 `json:"kv_cache_gb"`
+	RuntimeOverhead float64 `json:"runtime_overhead_gb"`
+	RequiredGB      int     `json:"required_gb"`
+	ModelID         string  `json:"model_id,omitempty"`
+	Quantization    string  `json:"quantization,omitempty"`
+	ParamCount      float64 `json:"param_count_b,omitempty"`
+}
+
+const (
+	kvCacheFraction     = 0.2 // KV cache and activations as a fraction of weights
+	cudaOverheadPerGPU  = 1.0 // GB reserved by the CUDA context on each GPU
+	vramWarningFraction = 0.9 // Warn when the estimate uses more than this share of VRAM
+)
+
+// EstimateVRAMRequirements calculates the GPU memory needed to serve a model
+// across gpuCount GPUs. Returns nil if the parameter count cannot be determined.
+func EstimateVRAMRequirements(modelID, quantization string, gpuCount int) *VRAMEstimation {
+	paramCount := parseParamCount(modelID)
+	if paramCount == 0 {
+		return nil
+	}
+
+	if quantization == "" {
+		quantization = inferQuantization(modelID)
+	}
+	if gpuCount < 1 {
+		gpuCount = 1
+	}
+
+	est := &VRAMEstimation{
+		ModelID:         modelID,
+		Quantization:    quantization,
+		ParamCount:      paramCount,
+		ModelWeightGB:   paramCount * bytesPerParam(quantization),
+		RuntimeOverhead: cudaOverheadPerGPU * float64(gpuCount),
+	}
+	est.KVCacheGB = est.ModelWeightGB * kvCacheFraction
+	est.RequiredGB = int(math.Ceil(est.ModelWeightGB + est.KVCacheGB + est.RuntimeOverhead))
+
+	return est
+}
+
+// ValidateVRAM checks if an offer's total VRAM can hold the estimated model.
+// Returns an InsufficientVRAMError if it cannot, nil otherwise. The returned
+// bool is true when the model fits but leaves little headroom.
+func ValidateVRAM(vramPerGPU, gpuCount int, estimation *VRAMEstimation) (bool, error) {
+	if estimation == nil || vramPerGPU <= 0 {
+		return false, nil
+	}
+	if gpuCount < 1 {
+		gpuCount = 1
+	}
+
+	available := vramPerGPU * gpuCount
+	if estimation.RequiredGB > available {
+		return false, &InsufficientVRAMError{
+			RequiredGB:  estimation.RequiredGB,
+			AvailableGB: available,
+			VRAMPerGPU:  vramPerGPU,
+			GPUCount:    gpuCount,
+			Estimation:  estimation,
+		}
+	}
+
+	return float64(estimation.RequiredGB) > float64(available)*vramWarningFraction, nil
+}
+
+// FormatBreakdown returns a human-readable breakdown of VRAM requirements.
+func (e *VRAMEstimation) FormatBreakdown() string {
+	parts := []string{
+		fmt.Sprintf("model weights: %.1f GB", e.ModelWeightGB),
+		fmt.Sprintf("kv cache: %.1f GB", e.KVCacheGB),
+		fmt.Sprintf("runtime overhead: %.0f GB", e.RuntimeOverhead),
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,83 @@
+package provisioner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateVRAMRequirements(t *testing.T) {
+	tests := []struct {
+		name       string
+		modelID    string
+		quant      string
+		gpuCount   int
+		expectedGB int
+	}{
+		// 8B FP16: 16 weights + 3.2 kv + 1 overhead = 20.2
+		{"8B FP16 single GPU", "meta-llama/Meta-Llama-3.1-8B", "", 1, 21},
+		// 70B FP16: 140 + 28 + 2 = 170
+		{"70B FP16 two GPUs", "meta-llama/Meta-Llama-3.1-70B", "", 2, 170},
+		// 70B AWQ inferred: 39.375 + 7.875 + 1 = 48.25
+		{"70B AWQ inferred", "TheBloke/Llama-2-70B-AWQ", "", 1, 49},
+		// 13B INT8: 13 + 2.6 + 1 = 16.6
+		{"13B explicit INT8", "meta-llama/Llama-2-13B-hf", "INT8", 1, 17},
+		// Zero GPU count is treated as one GPU
+		{"zero gpu count", "meta-llama/Meta-Llama-3.1-8B", "", 0, 21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est := EstimateVRAMRequirements(tt.modelID, tt.quant, tt.gpuCount)
+			require.NotNil(t, est)
+			assert.Equal(t, tt.expectedGB, est.RequiredGB)
+		})
+	}
+}
+
+func TestEstimateVRAMRequirements_UnknownModel(t *testing.T) {
+	assert.Nil(t, EstimateVRAMRequirements("openai/whisper-large", "", 1))
+	assert.Nil(t, EstimateVRAMRequirements("", "", 1))
+}
+
+func TestValidateVRAM(t *testing.T) {
+	est70B := EstimateVRAMRequirements("meta-llama/Meta-Llama-3.1-70B", "", 1)
+	est8B := EstimateVRAMRequirements("meta-llama/Meta-Llama-3.1-8B", "", 1)
+
+	t.Run("too small rejects", func(t *testing.T) {
+		tight, err := ValidateVRAM(24, 1, est70B)
+		require.Error(t, err)
+		assert.False(t, tight)
+
+		var vramErr *InsufficientVRAMError
+		require.ErrorAs(t, err, &vramErr)
+		assert.Equal(t, 24, vramErr.AvailableGB)
+		assert.Equal(t, est70B.RequiredGB, vramErr.RequiredGB)
+		assert.Contains(t, err.Error(), "insufficient GPU memory")
+	})
+
+	t.Run("multi GPU sums VRAM", func(t *testing.T) {
+		tight, err := ValidateVRAM(80, 4, est70B)
+		require.NoError(t, err)
+		assert.False(t, tight)
+	})
+
+	t.Run("tight fit warns", func(t *testing.T) {
+		tight, err := ValidateVRAM(22, 1, est8B)
+		require.NoError(t, err)
+		assert.True(t, tight)
+	})
+
+	t.Run("nil estimation passes", func(t *testing.T) {
+		tight, err := ValidateVRAM(24, 1, nil)
+		require.NoError(t, err)
+		assert.False(t, tight)
+	})
+
+	t.Run("unknown offer VRAM passes", func(t *testing.T) {
+		tight, err := ValidateVRAM(0, 1, est70B)
+		require.NoError(t, err)
+		assert.False(t, tight)
+	})
+}
@@ -0,0 +1,163 @@
+package provisioner
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/metrics"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// FeatureEntrypointWarmup gates the warm-up phase of entrypoint-mode
+// sessions, evaluated per session ID
+const FeatureEntrypointWarmup = "provisioner.entrypoint_warmup"
+
+// warmupLogTail is how many container log lines each warm-up poll reads
+const warmupLogTail = 200
+
+// weightsLoadedMarkers are log lines a workload prints once its model
+// weights are loaded
+var weightsLoadedMarkers = map[provider.WorkloadType][]string{
+	provider.WorkloadTypeVLLM:     {"Loading weights took", "Model loading took"},
+	provider.WorkloadTypeTGI:      {"Shard ready"},
+	provider.WorkloadTypeSGLang:   {"Load weight end"},
+	provider.WorkloadTypeLlamaCpp: {"model loaded"},
+}
+
+// serverStartedMarkers are log lines of an HTTP server accepting requests,
+// which any workload only starts once its weights are loaded
+var serverStartedMarkers = []string{"Application startup complete", "Uvicorn running on", "server is listening on"}
+
+// warmupFailures are log lines showing the workload cannot start, with what
+// they mean for the consumer
+var warmupFailures = []struct {
+	marker string
+	reason string
+}{
+	{"CUDA out of memory", "out of GPU memory"},
+	{"OutOfMemoryError", "out of GPU memory"},
+	{"No available memory for the cache blocks", "out of GPU memory"},
+	{"No space left on device", "out of disk space"},
+	{"GatedRepoError", "no access to the model"},
+	{"401 Client Error", "no access to the model"},
+	{"RepositoryNotFoundError", "model not found"},
+}
+
+// warmupStageReasons describe each warm-up stage for timeout errors
+var warmupStageReasons = map[string]string{
+	"image_pull":      "the image never finished pulling",
+	"container_start": "the container never started",
+	"weights_load":    "the model weights never finished loading",
+	"api_startup":     "the API never answered after the model loaded",
+}
+
+// warmupEnabled reports whether an entrypoint-mode session goes through the
+// warm-up phase before its API is verified
+func (s *Service) warmupEnabled(ctx context.Context, session *models.Session) bool {
+	if session.LaunchMode != models.LaunchModeEntrypoint {
+		return false
+	}
+	return s.features == nil || s.features.Enabled(ctx, FeatureEntrypointWarmup, session.ID, true)
+}
+
+// warmupStage names the first warm-up milestone a session has not reached
+func warmupStage(p models.ProvisionProgress) string {
+	switch {
+	case p.ImagePulledAt.IsZero():
+		return "image_pull"
+	case p.ContainerStartedAt.IsZero():
+		return "container_start"
+	case p.WeightsLoadedAt.IsZero():
+		return "weights_load"
+	default:
+		return "api_startup"
+	}
+}
+
+// observeWarmup stamps the warm-up milestones the instance status and
+// container logs show. It reports whether any milestone was stamped, and why
+// the workload cannot start if its logs say so. status may be nil if it
+// could not be fetched.
+func (s *Service) observeWarmup(ctx context.Context, session *models.Session, prov provider.Provider, status *provider.InstanceStatus, logger *slog.Logger) (changed bool, failure string) {
+	now := time.Now()
+	containerStarted := func() {
+		if session.Progress.ImagePulledAt.IsZero() {
+			session.Progress.ImagePulledAt = now
+			changed = true
+		}
+		if session.Progress.ContainerStartedAt.IsZero() {
+			session.Progress.ContainerStartedAt = now
+			changed = true
+		}
+	}
+
+	// A running instance has pulled its image and started the container
+	if status != nil && status.Running {
+		containerStarted()
+	}
+
+	lp, ok := provider.As[provider.LogsProvider](prov)
+	if !ok {
+		return changed, ""
+	}
+	logs, err := lp.GetInstanceLogs(ctx, session.ProviderID, warmupLogTail)
+	if err != nil {
+		logger.Debug("failed to read container logs for warm-up", slog.String("error", err.Error()))
+		return changed, ""
+	}
+	if strings.TrimSpace(logs) == "" {
+		return changed, ""
+	}
+	containerStarted()
+
+	for _, f := range warmupFailures {
+		if strings.Contains(logs, f.marker) {
+			return changed, f.reason
+		}
+	}
+
+	if !session.Progress.WeightsLoadedAt.IsZero() {
+		return changed, ""
+	}
+	for _, markers := range [][]string{weightsLoadedMarkers[providerWorkloadType(session.WorkloadType)], serverStartedMarkers} {
+		for _, m := range markers {
+			if strings.Contains(logs, m) {
+				session.Progress.WeightsLoadedAt = now
+				logger.Info("model weights loaded", slog.String("marker", m))
+				return true, ""
+			}
+		}
+	}
+	return changed, ""
+}
+
+// warmUp records the progress of a session in the warming_up phase, moving
+// it on to verifying once its weights are loaded. If the workload's logs
+// show it cannot start, the session is failed and warmUp returns false.
+func (s *Service) warmUp(ctx context.Context, session *models.Session, prov provider.Provider, status *provider.InstanceStatus, logger *slog.Logger) bool {
+	changed, failure := s.observeWarmup(ctx, session, prov, status, logger)
+	if failure != "" {
+		logger.Error("workload cannot start, destroying instance",
+			slog.String("reason", failure),
+			slog.String("stage", warmupStage(session.Progress)))
+		s.failSession(ctx, session, models.FailureWarmup, failure, "warm-up failed: "+failure)
+		metrics.RecordSessionDestroyed(session.Provider, "warmup_failed")
+		return false
+	}
+
+	if !session.Progress.WeightsLoadedAt.IsZero() {
+		if err := s.advancePhase(ctx, session, models.PhaseVerifying); err != nil {
+			logger.Error("failed to move session to verifying", slog.String("error", err.Error()))
+		}
+		return true
+	}
+	if changed {
+		if err := s.store.Update(ctx, session); err != nil {
+			logger.Error("failed to record warm-up progress", slog.String("error", err.Error()))
+		}
+	}
+	return true
+}
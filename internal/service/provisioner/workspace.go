@@ -0,0 +1,329 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/objectstore"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	sshverify "github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/ssh"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// Parts of a workspace upload are at least 16 MiB and at most the 5 GiB
+	// S3 allows. Each part gets a presigned URL, all of which are passed on
+	// the upload command line, so their number is capped.
+	minWorkspacePartSize = 16 << 20
+	maxWorkspacePartSize = 5 << 30
+	maxWorkspaceParts    = 64
+
+	// workspaceKeyWait bounds waiting for an instance to accept the key
+	// attached to save its workspace
+	workspaceKeyWait = 2 * time.Minute
+)
+
+// s3WorkspaceSnapshotter keeps one workspace tarball per consumer in an
+// S3-compatible bucket. Instances upload and download it themselves through
+// presigned URLs, so the data never passes through the server.
+type s3WorkspaceSnapshotter struct {
+	client *objectstore.Client
+	prefix string
+	path   string
+}
+
+// NewWorkspaceSnapshotter returns a WorkspaceSnapshotter that saves the
+// directory path of instances to client's bucket, under prefix
+func NewWorkspaceSnapshotter(client *objectstore.Client, prefix, path string) WorkspaceSnapshotter {
+	return &s3WorkspaceSnapshotter{client: client, prefix: prefix, path: path}
+}
+
+// key is the object a consumer's workspace is saved to. The last save wins.
+func (w *s3WorkspaceSnapshotter) key(consumerID string) string {
+	key := url.PathEscape(consumerID) + "/workspace.tar"
+	if w.prefix != "" {
+		key = w.prefix + "/" + key
+	}
+	return key
+}
+
+func (w *s3WorkspaceSnapshotter) Save(ctx context.Context, host string, port int, user, privateKey, consumerID string) (int64, error) {
+	executor := sshverify.NewExecutor(sshverify.WithExecutorConnectTimeout(30 * time.Second))
+	conn, err := executor.Connect(ctx, host, port, user, privateKey)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	size, files, err := executor.MeasureWorkspace(ctx, conn, w.path)
+	if err != nil {
+		return 0, err
+	}
+	if files == 0 {
+		return 0, nil
+	}
+	partSize, err := workspacePartSize(size, files)
+	if err != nil {
+		return 0, err
+	}
+
+	key := w.key(consumerID)
+	uploadID, err := w.client.CreateMultipartUpload(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	expires := time.Hour
+	if deadline, ok := ctx.Deadline(); ok {
+		expires = time.Until(deadline) + time.Minute
+	}
+	urls := make([]string, maxWorkspaceParts)
+	for i := range urls {
+		urls[i] = w.client.PresignUploadPart(key, uploadID, i+1, expires)
+	}
+
+	etags, uploaded, err := executor.UploadWorkspace(ctx, conn, w.path, partSize, urls)
+	if err == nil {
+		err = w.client.CompleteMultipartUpload(ctx, key, uploadID, etags)
+	}
+	if err != nil {
+		// Uploaded parts are stored, and billed, until the upload is aborted
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if abortErr := w.client.AbortMultipartUpload(abortCtx, key, uploadID); abortErr != nil {
+			err = fmt.Errorf("%w (abort failed: %v)", err, abortErr)
+		}
+		return 0, err
+	}
+	return uploaded, nil
+}
+
+func (w *s3WorkspaceSnapshotter) Restore(ctx context.Context, host string, port int, user, privateKey, consumerID string) (int64, error) {
+	key := w.key(consumerID)
+	size, err := w.client.Stat(ctx, key)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	executor := sshverify.NewExecutor(sshverify.WithExecutorConnectTimeout(30 * time.Second))
+	conn, err := executor.Connect(ctx, host, port, user, privateKey)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	expires := time.Hour
+	if deadline, ok := ctx.Deadline(); ok {
+		expires = time.Until(deadline) + time.Minute
+	}
+	if err := executor.RestoreWorkspace(ctx, conn, w.path, w.client.PresignGet(key, expires)); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// workspacePartSize picks the part size a workspace of size bytes in files
+// files and directories is uploaded in. tar adds up to 1 KiB per entry, more
+// for long names, and the workspace may grow while it is read.
+func workspacePartSize(size, files int64) (int64, error) {
+	bound := (size + files*2048 + 1<<20) * 11 / 10
+	partSize := (bound + maxWorkspaceParts - 1) / maxWorkspaceParts
+	partSize = (partSize + 1<<20 - 1) &^ (1<<20 - 1)
+	partSize = max(partSize, minWorkspacePartSize)
+	if partSize > maxWorkspacePartSize {
+		return 0, fmt.Errorf("workspace of %s is too large to save", formatBytes(size))
+	}
+	return partSize, nil
+}
+
+// formatBytes renders a byte count for session events
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// preservesWorkspace reports whether the session's workspace is saved when
+// it is destroyed, and the consumer's last saved workspace restored into it
+// when it starts
+func (s *Service) preservesWorkspace(session *models.Session) bool {
+	return s.workspaces != nil &&
+		session.StoragePolicy == models.StoragePreserve &&
+		session.LaunchMode != models.LaunchModeEntrypoint
+}
+
+// restoreWorkspace extracts the consumer's saved workspace onto the
+// session's instance before it is handed over, recording the result in the
+// session history. A failed restore does not hold the session back.
+func (s *Service) restoreWorkspace(session *models.Session, privateKey string, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.workspaceSaveTimeout)
+	defer cancel()
+
+	start := time.Now()
+	size, err := s.workspaces.Restore(s.pinHostKey(ctx, session),
+		session.SSHHost, session.SSHPort, session.SSHUser, privateKey, session.ConsumerID)
+
+	event := &models.SessionEvent{
+		SessionID:  session.ID,
+		Type:       models.SessionEventWorkspace,
+		FromStatus: session.Status,
+		ToStatus:   session.Status,
+	}
+	switch {
+	case err != nil:
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+		}
+		event.Reason = "workspace restore failed: " + err.Error()
+		logger.Warn("workspace restore failed", slog.String("error", err.Error()))
+	case size == 0:
+		event.Reason = "no saved workspace to restore"
+		logger.Info("no saved workspace to restore")
+	default:
+		event.Reason = "workspace restored: " + formatBytes(size)
+		logger.Info("workspace restored",
+			slog.Int64("bytes", size),
+			slog.Duration("duration", time.Since(start)))
+	}
+	s.recordEvent(event, logger)
+}
+
+// SavingWorkspace reports whether the session's workspace is being saved,
+// after which the session is destroyed
+func (s *Service) SavingWorkspace(sessionID string) bool {
+	s.workspaceSavesMu.Lock()
+	defer s.workspaceSavesMu.Unlock()
+	return s.workspaceSaves[sessionID]
+}
+
+// startWorkspaceSave saves a running session's workspace in the background,
+// then destroys the session. The session stays running meanwhile, since the
+// lifecycle manager fails sessions left stopping. It returns false if the
+// workspace cannot be saved, and the session should be destroyed now.
+func (s *Service) startWorkspaceSave(ctx context.Context, session *models.Session) bool {
+	logger := s.logger.With(slog.String("session_id", session.ID))
+
+	// The session key is not kept, so a new one is attached to log in
+	prov, err := s.providers.Get(session.Provider)
+	if err != nil {
+		return false
+	}
+	keyProvider, ok := provider.As[provider.SSHKeyProvider](prov)
+	if !ok {
+		s.recordEvent(&models.SessionEvent{
+			SessionID:  session.ID,
+			Type:       models.SessionEventWorkspace,
+			FromStatus: session.Status,
+			ToStatus:   session.Status,
+			Reason:     fmt.Sprintf("workspace not saved: %s cannot add SSH keys to running instances", session.Provider),
+		}, logger)
+		logger.Warn("workspace not saved, provider cannot add SSH keys",
+			slog.String("provider", session.Provider))
+		return false
+	}
+
+	s.workspaceSavesMu.Lock()
+	s.workspaceSaves[session.ID] = true
+	s.workspaceSavesMu.Unlock()
+
+	// The caller's context may end when it returns
+	ctx = context.WithoutCancel(ctx)
+	s.verifyWg.Add(1)
+	go func() {
+		defer s.verifyWg.Done()
+		defer func() {
+			s.workspaceSavesMu.Lock()
+			delete(s.workspaceSaves, session.ID)
+			s.workspaceSavesMu.Unlock()
+		}()
+
+		s.saveWorkspace(ctx, session, keyProvider, logger)
+		if err := s.destroySession(ctx, session.ID, true); err != nil {
+			logger.Error("failed to destroy session after saving its workspace",
+				slog.String("error", err.Error()))
+		}
+	}()
+	return true
+}
+
+// saveWorkspace uploads the session's workspace, recording the result in the
+// session history. Whether or not it succeeds, the session is destroyed next.
+func (s *Service) saveWorkspace(ctx context.Context, session *models.Session, keyProvider provider.SSHKeyProvider, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, s.workspaceSaveTimeout)
+	defer cancel()
+
+	start := time.Now()
+	size, err := s.uploadWorkspace(ctx, session, keyProvider)
+
+	event := &models.SessionEvent{
+		SessionID:  session.ID,
+		Type:       models.SessionEventWorkspace,
+		FromStatus: session.Status,
+		ToStatus:   session.Status,
+	}
+	switch {
+	case err != nil:
+		var mismatch *sshverify.HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			s.reportHostKeyMismatch(ctx, session, mismatch)
+		}
+		event.Reason = "workspace save failed: " + err.Error()
+		logger.Error("workspace save failed", slog.String("error", err.Error()))
+	case size == 0:
+		event.Reason = "workspace is empty, nothing saved"
+		logger.Info("workspace is empty, nothing saved")
+	default:
+		event.Reason = "workspace saved: " + formatBytes(size)
+		logger.Info("workspace saved",
+			slog.Int64("bytes", size),
+			slog.Duration("duration", time.Since(start)))
+	}
+	s.recordEvent(event, logger)
+}
+
+// uploadWorkspace attaches a new key to the session's instance, waits for
+// the instance to accept it, and saves the workspace
+func (s *Service) uploadWorkspace(ctx context.Context, session *models.Session, keyProvider provider.SSHKeyProvider) (int64, error) {
+	privateKey, publicKey, err := s.generateSSHKeyPair()
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+	if err := keyProvider.AttachSSHKey(ctx, session.ProviderID, publicKey); err != nil {
+		return 0, fmt.Errorf("failed to attach SSH key: %w", err)
+	}
+
+	// Providers install attached keys asynchronously
+	waitCtx, cancel := context.WithTimeout(ctx, workspaceKeyWait)
+	defer cancel()
+	for {
+		err = s.sshVerifier.VerifyOnce(s.pinHostKey(waitCtx, session),
+			session.SSHHost, session.SSHPort, session.SSHUser, privateKey)
+		var mismatch *sshverify.HostKeyMismatchError
+		if err == nil {
+			break
+		} else if errors.As(err, &mismatch) {
+			return 0, err
+		}
+		select {
+		case <-waitCtx.Done():
+			return 0, fmt.Errorf("instance did not accept the attached SSH key: %w", err)
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	return s.workspaces.Save(s.pinHostKey(ctx, session),
+		session.SSHHost, session.SSHPort, session.SSHUser, privateKey, session.ConsumerID)
+}
@@ -0,0 +1,198 @@
+package provisioner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspacePartSize(t *testing.T) {
+	partSize, err := workspacePartSize(100<<20, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(minWorkspacePartSize), partSize)
+
+	// Large workspaces are split across all parts, with room to grow
+	size := int64(50 << 30)
+	partSize, err = workspacePartSize(size, 200000)
+	require.NoError(t, err)
+	assert.Zero(t, partSize%(1<<20))
+	assert.Greater(t, partSize*maxWorkspaceParts, size*11/10)
+
+	_, err = workspacePartSize(400<<30, 1000)
+	assert.ErrorContains(t, err, "too large to save")
+}
+
+// workspaceStore is a fake WorkspaceSnapshotter. Saves block until release
+// is closed, if it is set.
+type workspaceStore struct {
+	mu       sync.Mutex
+	saved    int64
+	release  chan struct{}
+	saves    []string
+	restores []string
+}
+
+func (w *workspaceStore) Save(ctx context.Context, host string, port int, user, privateKey, consumerID string) (int64, error) {
+	if w.release != nil {
+		<-w.release
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.saves = append(w.saves, consumerID)
+	return w.saved, nil
+}
+
+func (w *workspaceStore) Restore(ctx context.Context, host string, port int, user, privateKey, consumerID string) (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.restores = append(w.restores, consumerID)
+	return w.saved, nil
+}
+
+func (w *workspaceStore) getCalls() (saves, restores []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.saves...), append([]string(nil), w.restores...)
+}
+
+func TestService_CreateSession_RestoresWorkspace(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     models.StoragePolicy
+		saved      int64
+		wantReason string
+	}{
+		{"saved workspace", models.StoragePreserve, 3 << 29, "workspace restored: 1.5 GiB"},
+		{"nothing saved", models.StoragePreserve, 0, "no saved workspace to restore"},
+		{"destroy policy", models.StorageDestroy, 3 << 29, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockSessionStore()
+			workspaces := &workspaceStore{saved: tt.saved}
+			events := &eventLog{}
+			svc := New(store, NewSimpleProviderRegistry([]provider.Provider{newMockProvider("vastai")}),
+				WithLogger(newTestLogger()),
+				WithSSHVerifier(NewMockSSHVerifier()),
+				WithSSHCheckInterval(10*time.Millisecond),
+				WithWorkspaceSnapshotter(workspaces),
+				WithEventRecorder(events))
+
+			ctx := context.Background()
+			session, err := svc.CreateSession(ctx, models.CreateSessionRequest{
+				ConsumerID:     "consumer-001",
+				OfferID:        "offer-1",
+				WorkloadType:   models.WorkloadInteractive,
+				ReservationHrs: 1,
+				StoragePolicy:  tt.policy,
+			}, &models.GPUOffer{ID: "offer-1", Provider: "vastai", PricePerHour: 0.50})
+			require.NoError(t, err)
+			require.True(t, svc.WaitForVerificationComplete(5*time.Second))
+
+			s, err := store.Get(ctx, session.ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusRunning, s.Status)
+
+			_, restores := workspaces.getCalls()
+			got := events.getEvents()
+			if tt.wantReason == "" {
+				assert.Empty(t, restores)
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, []string{"consumer-001"}, restores)
+			require.Len(t, got, 1)
+			assert.Equal(t, models.SessionEventWorkspace, got[0].Type)
+			assert.Equal(t, tt.wantReason, got[0].Reason)
+		})
+	}
+}
+
+func preservedSession() *models.Session {
+	return &models.Session{
+		ID:            "sess-001",
+		ConsumerID:    "consumer-001",
+		Provider:      "vastai",
+		ProviderID:    "instance-123",
+		Status:        models.StatusRunning,
+		StoragePolicy: models.StoragePreserve,
+		SSHHost:       "203.0.113.7",
+		SSHPort:       22,
+		SSHUser:       "root",
+	}
+}
+
+func TestService_DestroySession_SavesWorkspace(t *testing.T) {
+	store := newMockSessionStore()
+	prov := &sshKeyProvider{mockProvider: newMockProvider("vastai")}
+	workspaces := &workspaceStore{saved: 2 << 20, release: make(chan struct{})}
+	events := &eventLog{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithSSHVerifier(NewMockSSHVerifier()),
+		WithWorkspaceSnapshotter(workspaces),
+		WithEventRecorder(events))
+	store.sessions["sess-001"] = preservedSession()
+
+	ctx := context.Background()
+	require.NoError(t, svc.DestroySession(ctx, "sess-001"))
+
+	// The session keeps running until its workspace is saved
+	assert.True(t, svc.SavingWorkspace("sess-001"))
+	s, err := store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, s.Status)
+	require.NoError(t, svc.DestroySession(ctx, "sess-001"))
+	assert.Zero(t, prov.getDestroyCalls())
+
+	close(workspaces.release)
+	require.True(t, svc.WaitForVerificationComplete(15*time.Second))
+
+	assert.False(t, svc.SavingWorkspace("sess-001"))
+	s, err = store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusStopped, s.Status)
+	assert.Equal(t, 1, prov.getDestroyCalls())
+	assert.Equal(t, "instance-123", prov.attachedInstance)
+	assert.Contains(t, prov.attachedKey, "ssh-rsa")
+
+	saves, _ := workspaces.getCalls()
+	assert.Equal(t, []string{"consumer-001"}, saves)
+	got := events.getEvents()
+	require.Len(t, got, 1)
+	assert.Equal(t, models.SessionEventWorkspace, got[0].Type)
+	assert.Equal(t, "workspace saved: 2.0 MiB", got[0].Reason)
+}
+
+func TestService_DestroySession_WorkspaceNotSaved(t *testing.T) {
+	store := newMockSessionStore()
+	prov := newMockProvider("vastai")
+	workspaces := &workspaceStore{saved: 2 << 20}
+	events := &eventLog{}
+	svc := New(store, NewSimpleProviderRegistry([]provider.Provider{prov}),
+		WithLogger(newTestLogger()),
+		WithWorkspaceSnapshotter(workspaces),
+		WithEventRecorder(events))
+	store.sessions["sess-001"] = preservedSession()
+
+	// Without a way to log in, the session is destroyed straight away
+	ctx := context.Background()
+	require.NoError(t, svc.DestroySession(ctx, "sess-001"))
+
+	assert.False(t, svc.SavingWorkspace("sess-001"))
+	s, err := store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusStopped, s.Status)
+	saves, _ := workspaces.getCalls()
+	assert.Empty(t, saves)
+
+	got := events.getEvents()
+	require.Len(t, got, 1)
+	assert.Equal(t, "workspace not saved: vastai cannot add SSH keys to running instances", got[0].Reason)
+}
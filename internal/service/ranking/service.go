@@ -0,0 +1,366 @@
+// Package ranking scores GPU offers on several criteria at once, so the
+// inventory can recommend offers and explain the recommendation instead of
+// sorting on price alone.
+package ranking
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// DefaultHistoryWindow is how far back provisioning times are considered
+	DefaultHistoryWindow = 7 * 24 * time.Hour
+
+	// DefaultCacheTTL is how long historical data is reused between rankings
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+// Weights sets the relative importance of each criterion. Weights need not
+// sum to 1; they are normalized over the criteria that have data.
+type Weights struct {
+	Price        float64 `json:"price"`
+	Reliability  float64 `json:"reliability"`
+	Availability float64 `json:"availability"`
+	SSHTime      float64 `json:"ssh_time"`
+	Throughput   float64 `json:"throughput"`
+}
+
+// DefaultWeights favors price, then how likely the offer is to work
+var DefaultWeights = Weights{
+	Price:        0.35,
+	Reliability:  0.20,
+	Availability: 0.20,
+	SSHTime:      0.10,
+	Throughput:   0.15,
+}
+
+// ProvisioningTimeSource reports how long sessions historically took to
+// become reachable over SSH
+type ProvisioningTimeSource interface {
+	GetProvisioningTimes(ctx context.Context, since time.Time) ([]models.ProvisioningTimeStat, error)
+}
+
+// ThroughputSource reports benchmark throughput (tokens/s) by GPU name,
+// optionally for a single model
+type ThroughputSource interface {
+	ThroughputByGPU(ctx context.Context, modelName string) (map[string]float64, error)
+}
+
+// Component is one criterion's contribution to an offer's score
+type Component struct {
+	Value  float64 `json:"value"`  // Raw metric, e.g. price per GPU-hour or seconds to SSH
+	Score  float64 `json:"score"`  // Normalized 0-1, higher is better
+	Weight float64 `json:"weight"` // Share of the total score after normalizing weights
+}
+
+// Breakdown explains an offer's score. Criteria without data for any offer
+// are omitted and their weight is shared among the others.
+type Breakdown struct {
+	Score        float64    `json:"score"` // Weighted total, 0-1
+	Rank         int        `json:"rank"`  // 1 = best
+	Price        *Component `json:"price,omitempty"`
+	Reliability  *Component `json:"reliability,omitempty"`
+	Availability *Component `json:"availability,omitempty"`
+	SSHTime      *Component `json:"ssh_time,omitempty"`
+	Throughput   *Component `json:"throughput,omitempty"`
+}
+
+// RankedOffer is an offer with its ranking
+type RankedOffer struct {
+	models.GPUOffer
+	Ranking Breakdown `json:"ranking"`
+}
+
+// Service ranks offers
+type Service struct {
+	weights       Weights
+	provisioning  ProvisioningTimeSource
+	throughput    ThroughputSource
+	historyWindow time.Duration
+	cacheTTL      time.Duration
+	logger        *slog.Logger
+	now           func() time.Time
+
+	mu              sync.Mutex
+	sshTimes        map[string]float64
+	sshTimesAt      time.Time
+	throughputCache map[string]cachedThroughput
+}
+
+type cachedThroughput struct {
+	byGPU     map[string]float64
+	fetchedAt time.Time
+}
+
+// Option configures the ranking service
+type Option func(*Service)
+
+// WithWeights sets the criterion weights
+func WithWeights(w Weights) Option {
+	return func(s *Service) {
+		s.weights = w
+	}
+}
+
+// WithProvisioningTimeSource enables the SSH time criterion
+func WithProvisioningTimeSource(src ProvisioningTimeSource) Option {
+	return func(s *Service) {
+		s.provisioning = src
+	}
+}
+
+// WithThroughputSource enables the benchmark throughput criterion
+func WithThroughputSource(src ThroughputSource) Option {
+	return func(s *Service) {
+		s.throughput = src
+	}
+}
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(s *Service) {
+		s.now = fn
+	}
+}
+
+// New creates a new ranking service. Without sources, offers are ranked on
+// price, reliability and availability confidence only.
+func New(opts ...Option) *Service {
+	s := &Service{
+		weights:         DefaultWeights,
+		historyWindow:   DefaultHistoryWindow,
+		cacheTTL:        DefaultCacheTTL,
+		logger:          slog.Default(),
+		now:             time.Now,
+		throughputCache: make(map[string]cachedThroughput),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Rank scores offers and returns them best first. modelName narrows the
+// throughput criterion to benchmarks of that model. Price is compared per
+// GPU-hour so multi-GPU offers are not penalized for their size.
+func (s *Service) Rank(ctx context.Context, offers []models.GPUOffer, modelName string) []RankedOffer {
+	sshTimes := s.provisioningTimes(ctx)
+	throughput := s.throughputByGPU(ctx, modelName)
+
+	ranked := make([]RankedOffer, len(offers))
+	var minPrice, minSSH, maxTPS float64
+	for i, offer := range offers {
+		ranked[i].GPUOffer = offer
+		b := &ranked[i].Ranking
+
+		b.Price = &Component{Value: pricePerGPU(offer)}
+		if b.Price.Value > 0 && (minPrice == 0 || b.Price.Value < minPrice) {
+			minPrice = b.Price.Value
+		}
+		if offer.Reliability > 0 {
+			b.Reliability = &Component{Value: offer.Reliability, Score: clamp(offer.Reliability)}
+		}
+		confidence := offer.GetEffectiveAvailabilityConfidence()
+		b.Availability = &Component{Value: confidence, Score: clamp(confidence)}
+
+		if secs, ok := sshTimes[provisioningKey(offer.Provider, offer.GPUType)]; ok && secs > 0 {
+			b.SSHTime = &Component{Value: secs}
+			if minSSH == 0 || secs < minSSH {
+				minSSH = secs
+			}
+		}
+		if tps, ok := throughput[NormalizeGPUName(offer.GPUType)]; ok && tps > 0 {
+			b.Throughput = &Component{Value: tps}
+			if tps > maxTPS {
+				maxTPS = tps
+			}
+		}
+	}
+
+	// Relative criteria compare each offer to the best one in the set
+	for i := range ranked {
+		b := &ranked[i].Ranking
+		if b.Price.Value > 0 {
+			b.Price.Score = minPrice / b.Price.Value
+		} else {
+			b.Price.Score = 1
+		}
+		if b.SSHTime != nil {
+			b.SSHTime.Score = minSSH / b.SSHTime.Value
+		}
+		if b.Throughput != nil {
+			b.Throughput.Score = b.Throughput.Value / maxTPS
+		}
+		s.total(b)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Ranking.Score != ranked[j].Ranking.Score {
+			return ranked[i].Ranking.Score > ranked[j].Ranking.Score
+		}
+		return ranked[i].PricePerHour < ranked[j].PricePerHour
+	})
+	for i := range ranked {
+		ranked[i].Ranking.Rank = i + 1
+	}
+	return ranked
+}
+
+// total normalizes the weights of the criteria this offer has data for and
+// sums the weighted scores. An offer missing a criterion is scored on the
+// rest, so neither sparse history nor missing benchmarks sink it.
+func (s *Service) total(b *Breakdown) {
+	components := []struct {
+		c      *Component
+		weight float64
+	}{
+		{b.Price, s.weights.Price},
+		{b.Reliability, s.weights.Reliability},
+		{b.Availability, s.weights.Availability},
+		{b.SSHTime, s.weights.SSHTime},
+		{b.Throughput, s.weights.Throughput},
+	}
+
+	var sum float64
+	for _, comp := range components {
+		if comp.c != nil && comp.weight > 0 {
+			sum += comp.weight
+		}
+	}
+
+	b.Score = 0
+	for _, comp := range components {
+		if comp.c == nil {
+			continue
+		}
+		if sum > 0 && comp.weight > 0 {
+			comp.c.Weight = comp.weight / sum
+		}
+		b.Score += comp.c.Score * comp.c.Weight
+	}
+}
+
+// provisioningTimes returns average seconds to SSH keyed by provider and
+// normalized GPU type, refreshing the cache when it is stale
+func (s *Service) provisioningTimes(ctx context.Context) map[string]float64 {
+	if s.provisioning == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.sshTimes != nil && now.Sub(s.sshTimesAt) < s.cacheTTL {
+		return s.sshTimes
+	}
+
+	stats, err := s.provisioning.GetProvisioningTimes(ctx, now.Add(-s.historyWindow))
+	if err != nil {
+		// Keep ranking on the remaining criteria
+		s.logger.Warn("failed to load provisioning times for ranking",
+			slog.String("error", err.Error()))
+		return s.sshTimes
+	}
+
+	// Several provider GPU names can normalize to the same key
+	totals := make(map[string]float64)
+	samples := make(map[string]int)
+	for _, st := range stats {
+		key := provisioningKey(st.Provider, st.GPUType)
+		totals[key] += st.AvgSeconds * float64(st.Samples)
+		samples[key] += st.Samples
+	}
+	times := make(map[string]float64, len(totals))
+	for key, total := range totals {
+		if samples[key] > 0 {
+			times[key] = total / float64(samples[key])
+		}
+	}
+
+	s.sshTimes = times
+	s.sshTimesAt = now
+	return times
+}
+
+// throughputByGPU returns benchmark tokens/s keyed by normalized GPU name
+func (s *Service) throughputByGPU(ctx context.Context, modelName string) map[string]float64 {
+	if s.throughput == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if cached, ok := s.throughputCache[modelName]; ok && now.Sub(cached.fetchedAt) < s.cacheTTL {
+		return cached.byGPU
+	}
+
+	raw, err := s.throughput.ThroughputByGPU(ctx, modelName)
+	if err != nil {
+		s.logger.Warn("failed to load benchmark throughput for ranking",
+			slog.String("model", modelName),
+			slog.String("error", err.Error()))
+		return s.throughputCache[modelName].byGPU
+	}
+
+	// Benchmarks report names like "NVIDIA GeForce RTX 4090"; keep the best
+	// figure when several normalize to the same GPU
+	byGPU := make(map[string]float64, len(raw))
+	for name, tps := range raw {
+		key := NormalizeGPUName(name)
+		if tps > byGPU[key] {
+			byGPU[key] = tps
+		}
+	}
+
+	s.throughputCache[modelName] = cachedThroughput{byGPU: byGPU, fetchedAt: now}
+	return byGPU
+}
+
+// NormalizeGPUName reduces GPU names from providers and benchmarks to a
+// common key, e.g. "NVIDIA GeForce RTX 4090" and "RTX4090" both become
+// "rtx4090"
+func NormalizeGPUName(name string) string {
+	name = strings.ToLower(name)
+	for _, prefix := range []string{"nvidia", "geforce", "tesla"} {
+		name = strings.ReplaceAll(name, prefix, "")
+	}
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name)
+}
+
+func provisioningKey(provider, gpuType string) string {
+	return provider + "|" + NormalizeGPUName(gpuType)
+}
+
+func pricePerGPU(offer models.GPUOffer) float64 {
+	if offer.GPUCount > 1 {
+		return offer.PricePerHour / float64(offer.GPUCount)
+	}
+	return offer.PricePerHour
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
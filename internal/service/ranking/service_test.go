@@ -0,0 +1,148 @@
+package ranking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+type fakeProvisioningTimes struct {
+	stats []models.ProvisioningTimeStat
+	err   error
+	calls int
+}
+
+func (f *fakeProvisioningTimes) GetProvisioningTimes(ctx context.Context, since time.Time) ([]models.ProvisioningTimeStat, error) {
+	f.calls++
+	return f.stats, f.err
+}
+
+type fakeThroughput struct {
+	byModel map[string]map[string]float64
+}
+
+func (f *fakeThroughput) ThroughputByGPU(ctx context.Context, modelName string) (map[string]float64, error) {
+	return f.byModel[modelName], nil
+}
+
+func offer(id, gpu string, price, reliability, confidence float64) models.GPUOffer {
+	return models.GPUOffer{
+		ID:                     id,
+		Provider:               "vastai",
+		GPUType:                gpu,
+		GPUCount:               1,
+		PricePerHour:           price,
+		Reliability:            reliability,
+		AvailabilityConfidence: confidence,
+		Available:              true,
+	}
+}
+
+func TestNormalizeGPUName(t *testing.T) {
+	for _, name := range []string{"NVIDIA GeForce RTX 4090", "RTX 4090", "RTX4090", "rtx-4090"} {
+		assert.Equal(t, "rtx4090", NormalizeGPUName(name), name)
+	}
+	assert.Equal(t, "a100sxm4", NormalizeGPUName("NVIDIA A100-SXM4"))
+}
+
+func TestRank_OfferOnlyCriteria(t *testing.T) {
+	svc := New()
+	ranked := svc.Rank(context.Background(), []models.GPUOffer{
+		offer("cheap-flaky", "RTX 4090", 0.30, 0.80, 0.40),
+		offer("solid", "RTX 4090", 0.40, 0.99, 1.0),
+		offer("pricey", "RTX 4090", 0.90, 0.99, 1.0),
+	}, "")
+
+	require.Len(t, ranked, 3)
+	assert.Equal(t, "solid", ranked[0].ID)
+	assert.Equal(t, 1, ranked[0].Ranking.Rank)
+	assert.Equal(t, "pricey", ranked[2].ID)
+
+	b := ranked[0].Ranking
+	require.NotNil(t, b.Price)
+	require.NotNil(t, b.Reliability)
+	require.NotNil(t, b.Availability)
+	assert.Nil(t, b.SSHTime, "no provisioning history configured")
+	assert.Nil(t, b.Throughput, "no benchmarks configured")
+	assert.InDelta(t, 0.75, b.Price.Score, 0.001)
+
+	// Weights of the criteria present are renormalized to sum to 1
+	assert.InDelta(t, 1.0, b.Price.Weight+b.Reliability.Weight+b.Availability.Weight, 0.001)
+	want := b.Price.Score*b.Price.Weight + b.Reliability.Score*b.Reliability.Weight + b.Availability.Score*b.Availability.Weight
+	assert.InDelta(t, want, b.Score, 0.0001)
+}
+
+func TestRank_PricePerGPU(t *testing.T) {
+	multi := offer("multi", "A100", 4.00, 0, 1)
+	multi.GPUCount = 4
+	ranked := New().Rank(context.Background(), []models.GPUOffer{multi, offer("single", "A100", 1.25, 0, 1)}, "")
+
+	assert.Equal(t, "multi", ranked[0].ID)
+	assert.Equal(t, 1.0, ranked[0].Ranking.Price.Value)
+	assert.Equal(t, 1.0, ranked[0].Ranking.Price.Score)
+}
+
+func TestRank_HistoricalCriteria(t *testing.T) {
+	times := &fakeProvisioningTimes{stats: []models.ProvisioningTimeStat{
+		{Provider: "vastai", GPUType: "RTX 4090", Samples: 4, AvgSeconds: 60},
+		{Provider: "vastai", GPUType: "RTX4090", Samples: 4, AvgSeconds: 120},
+		{Provider: "vastai", GPUType: "RTX 3090", Samples: 2, AvgSeconds: 45},
+	}}
+	throughput := &fakeThroughput{byModel: map[string]map[string]float64{
+		"llama3.1:8b": {"NVIDIA GeForce RTX 4090": 120, "NVIDIA GeForce RTX 3090": 80},
+	}}
+	now := time.Now()
+	svc := New(
+		WithProvisioningTimeSource(times),
+		WithThroughputSource(throughput),
+		WithTimeFunc(func() time.Time { return now }))
+
+	offers := []models.GPUOffer{
+		offer("4090", "RTX 4090", 0.40, 0.95, 1),
+		offer("3090", "RTX 3090", 0.40, 0.95, 1),
+		offer("a100", "A100", 0.40, 0.95, 1),
+	}
+	ranked := svc.Rank(context.Background(), offers, "llama3.1:8b")
+	byID := make(map[string]Breakdown)
+	for _, r := range ranked {
+		byID[r.ID] = r.Ranking
+	}
+
+	// Samples for names that normalize alike are pooled: (4*60 + 4*120) / 8
+	require.NotNil(t, byID["4090"].SSHTime)
+	assert.InDelta(t, 90, byID["4090"].SSHTime.Value, 0.001)
+	assert.InDelta(t, 0.5, byID["4090"].SSHTime.Score, 0.001)
+	assert.InDelta(t, 1.0, byID["3090"].SSHTime.Score, 0.001)
+
+	require.NotNil(t, byID["4090"].Throughput)
+	assert.InDelta(t, 1.0, byID["4090"].Throughput.Score, 0.001)
+	assert.InDelta(t, 80.0/120.0, byID["3090"].Throughput.Score, 0.001)
+
+	// No history or benchmarks: scored on the remaining criteria
+	assert.Nil(t, byID["a100"].SSHTime)
+	assert.Nil(t, byID["a100"].Throughput)
+
+	// Without a model, benchmarks of other models do not count
+	ranked = svc.Rank(context.Background(), offers, "")
+	assert.Nil(t, ranked[0].Ranking.Throughput)
+
+	// Provisioning history is cached
+	assert.Equal(t, 1, times.calls)
+	now = now.Add(DefaultCacheTTL)
+	svc.Rank(context.Background(), offers, "")
+	assert.Equal(t, 2, times.calls)
+}
+
+func TestRank_SourceErrorKeepsRanking(t *testing.T) {
+	svc := New(WithProvisioningTimeSource(&fakeProvisioningTimes{err: errors.New("db down")}))
+	ranked := svc.Rank(context.Background(), []models.GPUOffer{offer("a", "RTX 4090", 0.40, 0.9, 1)}, "")
+	require.Len(t, ranked, 1)
+	assert.Nil(t, ranked[0].Ranking.SSHTime)
+	assert.Greater(t, ranked[0].Ranking.Score, 0.0)
+}
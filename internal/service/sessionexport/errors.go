@@ -0,0 +1,19 @@
+package sessionexport
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWrongPassphrase is returned when an export cannot be decrypted with the
+// supplied passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase for session export")
+
+// InvalidRequestError indicates an export or import request failed validation
+type InvalidRequestError struct {
+	Reason string
+}
+
+func (e *InvalidRequestError) Error() string {
+	return fmt.Sprintf("invalid session export request: %s", e.Reason)
+}
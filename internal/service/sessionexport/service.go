@@ -0,0 +1,277 @@
+// Package sessionexport exports sessions with their status history and cost
+// records into a portable bundle and imports them into another deployment,
+// for disaster recovery drills and controlled migrations.
+package sessionexport
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// MinPassphraseLength is the shortest passphrase accepted for encrypting keys
+	MinPassphraseLength = 12
+
+	// MaxExportSessions caps the number of sessions in a single export
+	MaxExportSessions = 1000
+)
+
+// SessionStore reads and imports sessions
+type SessionStore interface {
+	Get(ctx context.Context, id string) (*models.Session, error)
+	List(ctx context.Context, filter models.SessionListFilter) ([]*models.Session, error)
+	Import(ctx context.Context, session *models.Session, events []*models.SessionEvent, costs []models.CostRecord) error
+}
+
+// EventStore reads session status history
+type EventStore interface {
+	ListBySession(ctx context.Context, sessionID string) ([]*models.SessionEvent, error)
+}
+
+// CostStore reads session cost records
+type CostStore interface {
+	ListBySession(ctx context.Context, sessionID string) ([]models.CostRecord, error)
+}
+
+// Selection picks the sessions to export. Explicit session IDs take
+// precedence over a consumer ID.
+type Selection struct {
+	SessionIDs []string
+	ConsumerID string
+}
+
+// Service exports and imports session state
+type Service struct {
+	sessions     SessionStore
+	events       EventStore
+	costs        CostStore
+	deploymentID string
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+// Option configures the session export service
+type Option func(*Service)
+
+// WithLogger sets a custom logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithDeploymentID records the deployment exports are taken from
+func WithDeploymentID(id string) Option {
+	return func(s *Service) {
+		s.deploymentID = id
+	}
+}
+
+// WithTimeFunc sets a custom time function (for testing)
+func WithTimeFunc(fn func() time.Time) Option {
+	return func(s *Service) {
+		s.now = fn
+	}
+}
+
+// New creates a new session export service
+func New(sessions SessionStore, events EventStore, costs CostStore, opts ...Option) *Service {
+	s := &Service{
+		sessions: sessions,
+		events:   events,
+		costs:    costs,
+		logger:   slog.Default(),
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Export builds a bundle of the selected sessions. SSH keys are encrypted
+// with a key derived from the passphrase.
+func (s *Service) Export(ctx context.Context, sel Selection, passphrase string) (*models.SessionExport, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, &InvalidRequestError{Reason: fmt.Sprintf("passphrase must be at least %d characters", MinPassphraseLength)}
+	}
+
+	sessions, err := s.selectSessions(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := secrets.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := secrets.PassphraseAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	keyCheck, err := seal(aead, keyCheckPlaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &models.SessionExport{
+		Version:      models.SessionExportVersion,
+		DeploymentID: s.deploymentID,
+		ExportedAt:   s.now().UTC(),
+		KeySalt:      base64.StdEncoding.EncodeToString(salt),
+		KeyCheck:     keyCheck,
+		Sessions:     make([]models.SessionExportRecord, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		record := models.SessionExportRecord{Session: session}
+		if session.SSHPublicKey != "" {
+			if record.EncryptedSSHPublicKey, err = seal(aead, session.SSHPublicKey); err != nil {
+				return nil, err
+			}
+		}
+		if record.Events, err = s.events.ListBySession(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		if record.Costs, err = s.costs.ListBySession(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		if record.Events == nil {
+			record.Events = []*models.SessionEvent{}
+		}
+		if record.Costs == nil {
+			record.Costs = []models.CostRecord{}
+		}
+		bundle.Sessions = append(bundle.Sessions, record)
+	}
+
+	s.logger.Info("exported sessions",
+		slog.Int("count", len(bundle.Sessions)),
+		slog.String("consumer_id", sel.ConsumerID))
+
+	return bundle, nil
+}
+
+// selectSessions resolves a selection to session records
+func (s *Service) selectSessions(ctx context.Context, sel Selection) ([]*models.Session, error) {
+	if len(sel.SessionIDs) > 0 {
+		if len(sel.SessionIDs) > MaxExportSessions {
+			return nil, &InvalidRequestError{Reason: fmt.Sprintf("at most %d sessions can be exported at once", MaxExportSessions)}
+		}
+		sessions := make([]*models.Session, 0, len(sel.SessionIDs))
+		for _, id := range sel.SessionIDs {
+			session, err := s.sessions.Get(ctx, id)
+			if err != nil {
+				if errors.Is(err, storage.ErrNotFound) {
+					return nil, &InvalidRequestError{Reason: "session not found: " + id}
+				}
+				return nil, err
+			}
+			sessions = append(sessions, session)
+		}
+		return sessions, nil
+	}
+
+	if sel.ConsumerID == "" {
+		return nil, &InvalidRequestError{Reason: "session_ids or consumer_id is required"}
+	}
+	sessions, err := s.sessions.List(ctx, models.SessionListFilter{ConsumerID: sel.ConsumerID, Limit: MaxExportSessions + 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) > MaxExportSessions {
+		return nil, &InvalidRequestError{Reason: fmt.Sprintf("consumer has more than %d sessions; export by session ID", MaxExportSessions)}
+	}
+	return sessions, nil
+}
+
+// Import writes the sessions of a bundle into this deployment. Sessions that
+// already exist are skipped. Unless takeOver is set, sessions that were still
+// active are imported as stopped so this deployment never manages (and on
+// expiry destroys) instances the source deployment still owns.
+func (s *Service) Import(ctx context.Context, bundle *models.SessionExport, passphrase string, takeOver bool) (*models.SessionImportResult, error) {
+	if bundle == nil || bundle.Version != models.SessionExportVersion {
+		return nil, &InvalidRequestError{Reason: fmt.Sprintf("unsupported export version (want %d)", models.SessionExportVersion)}
+	}
+	salt, err := base64.StdEncoding.DecodeString(bundle.KeySalt)
+	if err != nil || len(salt) == 0 {
+		return nil, &InvalidRequestError{Reason: "export has no valid key salt"}
+	}
+	aead, err := secrets.PassphraseAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := open(aead, bundle.KeyCheck); err != nil || check != keyCheckPlaintext {
+		return nil, ErrWrongPassphrase
+	}
+
+	// Validate the whole bundle before writing anything
+	for i, record := range bundle.Sessions {
+		if record.Session == nil || record.Session.ID == "" || record.Session.ConsumerID == "" {
+			return nil, &InvalidRequestError{Reason: fmt.Sprintf("session %d is missing an id or consumer_id", i)}
+		}
+		if record.EncryptedSSHPublicKey != "" {
+			key, err := open(aead, record.EncryptedSSHPublicKey)
+			if err != nil {
+				return nil, err
+			}
+			record.Session.SSHPublicKey = key
+		}
+	}
+
+	result := &models.SessionImportResult{
+		Imported:    []string{},
+		Skipped:     []string{},
+		Deactivated: []string{},
+	}
+	now := s.now().UTC()
+
+	for _, record := range bundle.Sessions {
+		session := record.Session
+		events := record.Events
+		deactivated := false
+		if !takeOver && !session.IsTerminal() {
+			events = append(events, &models.SessionEvent{
+				SessionID:  session.ID,
+				FromStatus: session.Status,
+				ToStatus:   models.StatusStopped,
+				Reason:     "imported without taking over the instance",
+				CreatedAt:  now,
+			})
+			session.Status = models.StatusStopped
+			session.StoppedAt = now
+			deactivated = true
+		}
+
+		if err := s.sessions.Import(ctx, session, events, record.Costs); err != nil {
+			if errors.Is(err, storage.ErrAlreadyExists) {
+				result.Skipped = append(result.Skipped, session.ID)
+				continue
+			}
+			return result, fmt.Errorf("failed to import session %s: %w", session.ID, err)
+		}
+		result.Imported = append(result.Imported, session.ID)
+		if deactivated {
+			result.Deactivated = append(result.Deactivated, session.ID)
+		}
+	}
+
+	s.logger.Info("imported sessions",
+		slog.String("source_deployment_id", bundle.DeploymentID),
+		slog.Int("imported", len(result.Imported)),
+		slog.Int("skipped", len(result.Skipped)),
+		slog.Int("deactivated", len(result.Deactivated)),
+		slog.Bool("take_over", takeOver))
+
+	return result, nil
+}
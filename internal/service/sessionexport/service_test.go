@@ -0,0 +1,204 @@
+package sessionexport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/storage"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const testPassphrase = "correct horse battery"
+
+// mockStore implements SessionStore, EventStore and CostStore in memory
+type mockStore struct {
+	mu       sync.Mutex
+	sessions map[string]*models.Session
+	events   map[string][]*models.SessionEvent
+	costs    map[string][]models.CostRecord
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		sessions: make(map[string]*models.Session),
+		events:   make(map[string][]*models.SessionEvent),
+		costs:    make(map[string][]models.CostRecord),
+	}
+}
+
+func (m *mockStore) Get(ctx context.Context, id string) (*models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *mockStore) List(ctx context.Context, filter models.SessionListFilter) ([]*models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Session
+	for _, s := range m.sessions {
+		if filter.ConsumerID == "" || s.ConsumerID == filter.ConsumerID {
+			copied := *s
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStore) Import(ctx context.Context, session *models.Session, events []*models.SessionEvent, costs []models.CostRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[session.ID]; ok {
+		return storage.ErrAlreadyExists
+	}
+	copied := *session
+	m.sessions[session.ID] = &copied
+	m.events[session.ID] = events
+	m.costs[session.ID] = costs
+	return nil
+}
+
+// mockEvents and mockCosts adapt mockStore to the two ListBySession signatures
+type mockEvents struct{ *mockStore }
+
+func (m mockEvents) ListBySession(ctx context.Context, sessionID string) ([]*models.SessionEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.events[sessionID], nil
+}
+
+type mockCosts struct{ *mockStore }
+
+func (m mockCosts) ListBySession(ctx context.Context, sessionID string) ([]models.CostRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.costs[sessionID], nil
+}
+
+func newTestService(store *mockStore, opts ...Option) *Service {
+	return New(store, mockEvents{store}, mockCosts{store}, opts...)
+}
+
+func seedStore() *mockStore {
+	store := newMockStore()
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.sessions["sess-running"] = &models.Session{
+		ID: "sess-running", ConsumerID: "consumer-1", Provider: "vastai", ProviderID: "123",
+		Status: models.StatusRunning, SSHPublicKey: "ssh-rsa AAAA running", CreatedAt: created,
+	}
+	store.sessions["sess-stopped"] = &models.Session{
+		ID: "sess-stopped", ConsumerID: "consumer-1", Provider: "vastai",
+		Status: models.StatusStopped, SSHPublicKey: "ssh-rsa AAAA stopped", CreatedAt: created,
+	}
+	store.sessions["sess-other"] = &models.Session{
+		ID: "sess-other", ConsumerID: "consumer-2", Provider: "vastai", Status: models.StatusStopped,
+	}
+	store.events["sess-running"] = []*models.SessionEvent{
+		{SessionID: "sess-running", ToStatus: models.StatusPending, CreatedAt: created},
+		{SessionID: "sess-running", FromStatus: models.StatusPending, ToStatus: models.StatusRunning, CreatedAt: created.Add(time.Minute)},
+	}
+	store.costs["sess-running"] = []models.CostRecord{
+		{SessionID: "sess-running", ConsumerID: "consumer-1", Hour: created, Amount: 0.5, Currency: "USD"},
+	}
+	return store
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	src := newTestService(seedStore(), WithDeploymentID("deploy-a"))
+	ctx := context.Background()
+
+	bundle, err := src.Export(ctx, Selection{ConsumerID: "consumer-1"}, testPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, models.SessionExportVersion, bundle.Version)
+	assert.Equal(t, "deploy-a", bundle.DeploymentID)
+	require.Len(t, bundle.Sessions, 2)
+	for _, record := range bundle.Sessions {
+		assert.NotEmpty(t, record.EncryptedSSHPublicKey)
+		assert.NotContains(t, record.EncryptedSSHPublicKey, "ssh-rsa")
+	}
+
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	dstStore := newMockStore()
+	dst := newTestService(dstStore, WithTimeFunc(func() time.Time { return now }))
+
+	result, err := dst.Import(ctx, bundle, testPassphrase, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"sess-running", "sess-stopped"}, result.Imported)
+	assert.Equal(t, []string{"sess-running"}, result.Deactivated)
+	assert.Empty(t, result.Skipped)
+
+	running := dstStore.sessions["sess-running"]
+	assert.Equal(t, "ssh-rsa AAAA running", running.SSHPublicKey)
+	assert.Equal(t, models.StatusStopped, running.Status, "active sessions are not taken over by default")
+	assert.Equal(t, now, running.StoppedAt)
+	require.Len(t, dstStore.events["sess-running"], 3)
+	last := dstStore.events["sess-running"][2]
+	assert.Equal(t, models.StatusRunning, last.FromStatus)
+	assert.Equal(t, models.StatusStopped, last.ToStatus)
+	assert.Len(t, dstStore.costs["sess-running"], 1)
+
+	// Importing again skips everything
+	bundle, err = src.Export(ctx, Selection{ConsumerID: "consumer-1"}, testPassphrase)
+	require.NoError(t, err)
+	result, err = dst.Import(ctx, bundle, testPassphrase, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Imported)
+	assert.Len(t, result.Skipped, 2)
+}
+
+func TestImport_TakeOverKeepsStatus(t *testing.T) {
+	src := newTestService(seedStore())
+	ctx := context.Background()
+
+	bundle, err := src.Export(ctx, Selection{SessionIDs: []string{"sess-running"}}, testPassphrase)
+	require.NoError(t, err)
+
+	dstStore := newMockStore()
+	result, err := newTestService(dstStore).Import(ctx, bundle, testPassphrase, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess-running"}, result.Imported)
+	assert.Empty(t, result.Deactivated)
+	assert.Equal(t, models.StatusRunning, dstStore.sessions["sess-running"].Status)
+	assert.Len(t, dstStore.events["sess-running"], 2)
+}
+
+func TestImport_WrongPassphrase(t *testing.T) {
+	src := newTestService(seedStore())
+	ctx := context.Background()
+
+	bundle, err := src.Export(ctx, Selection{ConsumerID: "consumer-1"}, testPassphrase)
+	require.NoError(t, err)
+
+	dstStore := newMockStore()
+	_, err = newTestService(dstStore).Import(ctx, bundle, "not the passphrase", false)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+	assert.Empty(t, dstStore.sessions, "nothing is written on a wrong passphrase")
+}
+
+func TestExport_Validation(t *testing.T) {
+	svc := newTestService(seedStore())
+	ctx := context.Background()
+
+	var invalid *InvalidRequestError
+	_, err := svc.Export(ctx, Selection{ConsumerID: "consumer-1"}, "short")
+	assert.ErrorAs(t, err, &invalid)
+
+	_, err = svc.Export(ctx, Selection{}, testPassphrase)
+	assert.ErrorAs(t, err, &invalid)
+
+	_, err = svc.Export(ctx, Selection{SessionIDs: []string{"missing"}}, testPassphrase)
+	assert.ErrorAs(t, err, &invalid)
+
+	_, err = svc.Import(ctx, &models.SessionExport{Version: 99}, testPassphrase, false)
+	assert.ErrorAs(t, err, &invalid)
+}
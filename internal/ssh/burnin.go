@@ -0,0 +1,245 @@
+package ssh
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// burnInScript lists the GPUs, runs a half-precision matmul on every GPU at
+// once for BURN_SECONDS while sampling throttle reasons, then collects the
+// kernel's Xid errors. The load needs PyTorch with CUDA, which most GPU
+// images ship; without it only the listing, throttle and Xid checks run.
+const burnInScript = `echo "== gpus"
+nvidia-smi --query-gpu=index,name --format=csv,noheader 2>&1
+echo "== load"
+samples=$(mktemp)
+nvidia-smi --query-gpu=index,temperature.gpu,clocks_throttle_reasons.hw_slowdown,clocks_throttle_reasons.sw_thermal_slowdown --format=csv,noheader,nounits -lms 1000 >"$samples" 2>/dev/null &
+sampler=$!
+if python3 -c 'import sys, torch; sys.exit(0 if torch.cuda.is_available() else 1)' 2>/dev/null; then
+python3 - <<'PY'
+import os, threading, time, torch
+
+SECONDS = float(os.environ.get("BURN_SECONDS", "30"))
+SIZE = 4096
+
+def burn(i, out):
+    try:
+        d = torch.device("cuda", i)
+        g = torch.Generator(device=d).manual_seed(i)
+        a = torch.randn(SIZE, SIZE, device=d, dtype=torch.float16, generator=g)
+        b = torch.randn(SIZE, SIZE, device=d, dtype=torch.float16, generator=g)
+        ref = a @ b
+        torch.cuda.synchronize(d)
+        n, start = 0, time.time()
+        while time.time() - start < SECONDS:
+            for _ in range(10):
+                c = a @ b
+            torch.cuda.synchronize(d)
+            n += 10
+        elapsed = time.time() - start
+        ok = bool(torch.isfinite(c).all()) and torch.allclose(c, ref, rtol=1e-2, atol=1e-1)
+        out[i] = "gpu %d tflops %.1f ok %d" % (i, 2 * SIZE**3 * n / elapsed / 1e12, ok)
+    except Exception as e:
+        msg = str(e).strip().splitlines()
+        out[i] = "gpu %d error %s" % (i, msg[0] if msg else type(e).__name__)
+
+out = {}
+threads = [threading.Thread(target=burn, args=(i, out)) for i in range(torch.cuda.device_count())]
+for t in threads:
+    t.start()
+for t in threads:
+    t.join()
+for i in sorted(out):
+    print(out[i])
+PY
+else
+echo "skipped: PyTorch with CUDA is not installed"
+sleep 1
+fi
+kill "$sampler" 2>/dev/null
+wait "$sampler" 2>/dev/null
+echo "== throttle"
+cat "$samples"
+rm -f "$samples"
+echo "== xid"
+{ dmesg 2>/dev/null || journalctl -k --no-pager 2>/dev/null; } | grep "NVRM: Xid" | tail -5
+true
+`
+
+// GPUBurnIn is the outcome of a short load test of an instance's GPUs
+type GPUBurnIn struct {
+	GPUs       []string         // GPU names by index, as nvidia-smi lists them
+	LoadRan    bool             // False when the instance has no PyTorch with CUDA
+	TFLOPS     map[int]float64  // Half-precision matmul throughput by GPU index
+	BadResults []int            // GPUs whose matmul results were wrong or not finite
+	LoadErrors map[int]string   // GPUs the load failed on, with the error
+	Throttled  map[int][]string // Throttle reasons seen under load by GPU index
+	XidErrors  []string         // Kernel log lines reporting GPU errors
+	MaxTempC   int
+	RawOutput  string
+}
+
+// burnInLoadRe matches a load result line, e.g. "gpu 0 tflops 152.3 ok 1"
+var burnInLoadRe = regexp.MustCompile(`^gpu (\d+) tflops ([\d.]+) ok ([01])$`)
+
+// burnInErrorRe matches a GPU the load failed on, e.g. "gpu 1 error CUDA error: ..."
+var burnInErrorRe = regexp.MustCompile(`^gpu (\d+) error (.*)$`)
+
+// burnInThrottleReasons name the throttle columns sampled during the load
+var burnInThrottleReasons = []string{"hardware slowdown", "thermal slowdown"}
+
+// ParseGPUBurnIn parses the output of the burn-in script
+func ParseGPUBurnIn(output string) *GPUBurnIn {
+	b := &GPUBurnIn{
+		TFLOPS:     make(map[int]float64),
+		LoadErrors: make(map[int]string),
+		Throttled:  make(map[int][]string),
+		RawOutput:  output,
+	}
+
+	section := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "== ") {
+			section = strings.TrimPrefix(line, "== ")
+			continue
+		}
+
+		switch section {
+		case "gpus":
+			index, name, ok := strings.Cut(line, ",")
+			if _, err := strconv.Atoi(strings.TrimSpace(index)); ok && err == nil {
+				b.GPUs = append(b.GPUs, strings.TrimSpace(name))
+			}
+		case "load":
+			if m := burnInLoadRe.FindStringSubmatch(line); m != nil {
+				b.LoadRan = true
+				i, _ := strconv.Atoi(m[1])
+				b.TFLOPS[i], _ = strconv.ParseFloat(m[2], 64)
+				if m[3] != "1" {
+					b.BadResults = append(b.BadResults, i)
+				}
+			} else if m := burnInErrorRe.FindStringSubmatch(line); m != nil {
+				b.LoadRan = true
+				i, _ := strconv.Atoi(m[1])
+				b.LoadErrors[i] = m[2]
+			}
+		case "throttle":
+			b.parseThrottleSample(line)
+		case "xid":
+			b.XidErrors = append(b.XidErrors, line)
+		}
+	}
+	return b
+}
+
+// parseThrottleSample records one nvidia-smi sample line, e.g.
+// "0, 71, Not Active, Active"
+func (b *GPUBurnIn) parseThrottleSample(line string) {
+	parts := strings.Split(line, ",")
+	if len(parts) != 2+len(burnInThrottleReasons) {
+		return
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return
+	}
+	if temp, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil && temp > b.MaxTempC {
+		b.MaxTempC = temp
+	}
+	for j, reason := range burnInThrottleReasons {
+		if strings.TrimSpace(parts[2+j]) != "Active" {
+			continue
+		}
+		if !slices.Contains(b.Throttled[i], reason) {
+			b.Throttled[i] = append(b.Throttled[i], reason)
+		}
+	}
+}
+
+// Problems lists what makes the GPUs unfit to hand over, given how many GPUs
+// the instance should have; none means they passed. A GPU running at under
+// half the throughput of the fastest one is counted as degraded.
+func (b *GPUBurnIn) Problems(expectedGPUs int) []string {
+	var problems []string
+	if len(b.GPUs) == 0 {
+		problems = append(problems, "nvidia-smi lists no GPUs")
+	} else if len(b.GPUs) < expectedGPUs {
+		problems = append(problems, fmt.Sprintf("%d of %d GPUs visible", len(b.GPUs), expectedGPUs))
+	}
+	for _, i := range sortedKeys(b.LoadErrors) {
+		problems = append(problems, fmt.Sprintf("GPU %d failed the load: %s", i, b.LoadErrors[i]))
+	}
+	for _, i := range b.BadResults {
+		problems = append(problems, fmt.Sprintf("GPU %d computed wrong results", i))
+	}
+
+	fastest := 0.0
+	for _, tflops := range b.TFLOPS {
+		fastest = max(fastest, tflops)
+	}
+	for _, i := range sortedKeys(b.TFLOPS) {
+		if b.TFLOPS[i] < fastest/2 {
+			problems = append(problems, fmt.Sprintf("GPU %d ran at %.0f%% of the fastest GPU's throughput", i, b.TFLOPS[i]/fastest*100))
+		}
+	}
+
+	for _, i := range sortedKeys(b.Throttled) {
+		problems = append(problems, fmt.Sprintf("GPU %d throttled under load: %s", i, strings.Join(b.Throttled[i], ", ")))
+	}
+	if len(b.XidErrors) > 0 {
+		problems = append(problems, fmt.Sprintf("kernel reports Xid errors: %s", b.XidErrors[len(b.XidErrors)-1]))
+	}
+	return problems
+}
+
+// String returns a human-readable summary
+func (b *GPUBurnIn) String() string {
+	parts := []string{fmt.Sprintf("%d GPUs", len(b.GPUs))}
+	if b.LoadRan {
+		for _, i := range sortedKeys(b.TFLOPS) {
+			parts = append(parts, fmt.Sprintf("GPU %d %.1f TFLOPS", i, b.TFLOPS[i]))
+		}
+	} else {
+		parts = append(parts, "load skipped (no PyTorch with CUDA)")
+	}
+	if b.MaxTempC > 0 {
+		parts = append(parts, fmt.Sprintf("max %dC", b.MaxTempC))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// RunGPUBurnIn load-tests every GPU for the given duration and reports how
+// they held up. The instance is busy for the whole run.
+func (e *Executor) RunGPUBurnIn(ctx context.Context, conn *Connection, duration time.Duration) (*GPUBurnIn, error) {
+	// Allow for the GPU listing, CUDA start-up and kernel log scan
+	ctx, cancel := context.WithTimeout(ctx, duration+time.Minute)
+	defer cancel()
+
+	cmd := fmt.Sprintf("echo %s | base64 -d | BURN_SECONDS=%d bash",
+		base64.StdEncoding.EncodeToString([]byte(burnInScript)), int(duration.Seconds()))
+	stdout, stderr, err := e.RunCommand(ctx, conn, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("burn-in failed: %w (stderr: %s)", err, stderr)
+	}
+	return ParseGPUBurnIn(stdout), nil
+}
@@ -0,0 +1,91 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGPUBurnIn_Healthy(t *testing.T) {
+	output := `== gpus
+0, NVIDIA A100-SXM4-80GB
+1, NVIDIA A100-SXM4-80GB
+== load
+gpu 0 tflops 231.4 ok 1
+gpu 1 tflops 228.9 ok 1
+== throttle
+0, 61, Not Active, Not Active
+1, 63, Not Active, Not Active
+0, 74, Not Active, Not Active
+1, 77, Not Active, Not Active
+== xid`
+
+	b := ParseGPUBurnIn(output)
+	assert.Equal(t, []string{"NVIDIA A100-SXM4-80GB", "NVIDIA A100-SXM4-80GB"}, b.GPUs)
+	assert.True(t, b.LoadRan)
+	assert.Equal(t, map[int]float64{0: 231.4, 1: 228.9}, b.TFLOPS)
+	assert.Equal(t, 77, b.MaxTempC)
+	assert.Empty(t, b.Problems(2))
+	assert.Equal(t, "2 GPUs, GPU 0 231.4 TFLOPS, GPU 1 228.9 TFLOPS, max 77C", b.String())
+}
+
+func TestParseGPUBurnIn_Problems(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected int
+		want     []string
+	}{
+		{
+			name:     "nvidia-smi broken",
+			output:   "== gpus\nNVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver.\n== load\nskipped: PyTorch with CUDA is not installed\n== throttle\n== xid",
+			expected: 1,
+			want:     []string{"nvidia-smi lists no GPUs"},
+		},
+		{
+			name:     "missing GPU",
+			output:   "== gpus\n0, NVIDIA GeForce RTX 4090\n== load\ngpu 0 tflops 160.2 ok 1\n== throttle\n== xid",
+			expected: 2,
+			want:     []string{"1 of 2 GPUs visible"},
+		},
+		{
+			name:     "wrong results and load error",
+			output:   "== gpus\n0, H100\n1, H100\n== load\ngpu 0 tflops 600.1 ok 0\ngpu 1 error CUDA error: an illegal memory access was encountered\n== throttle\n== xid",
+			expected: 2,
+			want: []string{
+				"GPU 1 failed the load: CUDA error: an illegal memory access was encountered",
+				"GPU 0 computed wrong results",
+			},
+		},
+		{
+			name:     "slow GPU",
+			output:   "== gpus\n0, A100\n1, A100\n== load\ngpu 0 tflops 230.0 ok 1\ngpu 1 tflops 92.0 ok 1\n== throttle\n== xid",
+			expected: 2,
+			want:     []string{"GPU 1 ran at 40% of the fastest GPU's throughput"},
+		},
+		{
+			name:     "throttling",
+			output:   "== gpus\n0, A100\n== load\ngpu 0 tflops 180.0 ok 1\n== throttle\n0, 88, Not Active, Active\n0, 91, Active, Active\n== xid",
+			expected: 1,
+			want:     []string{"GPU 0 throttled under load: thermal slowdown, hardware slowdown"},
+		},
+		{
+			name:     "xid errors",
+			output:   "== gpus\n0, A100\n== load\ngpu 0 tflops 230.0 ok 1\n== throttle\n== xid\n[ 812.3] NVRM: Xid (PCI:0000:3b:00): 79, pid=1234, GPU has fallen off the bus.",
+			expected: 1,
+			want:     []string{"kernel reports Xid errors: [ 812.3] NVRM: Xid (PCI:0000:3b:00): 79, pid=1234, GPU has fallen off the bus."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseGPUBurnIn(tt.output).Problems(tt.expected))
+		})
+	}
+}
+
+func TestParseGPUBurnIn_LoadSkipped(t *testing.T) {
+	b := ParseGPUBurnIn("== gpus\n0, Tesla T4\n== load\nskipped: PyTorch with CUDA is not installed\n== throttle\n0, 40, Not Active, Not Active\n== xid")
+	assert.False(t, b.LoadRan)
+	assert.Empty(t, b.Problems(1))
+	assert.Equal(t, "1 GPUs, load skipped (no PyTorch with CUDA), max 40C", b.String())
+}
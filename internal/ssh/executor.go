@@ -116,7 +116,7 @@ func (e *Executor) Connect(ctx context.Context, host string, port int, user, pri
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(signer),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // GPU instances have dynamic host keys
+		HostKeyCallback: HostKeyCallback(ctx),
 		Timeout:         e.connectTimeout,
 	}
 
@@ -202,8 +202,7 @@ func (e *Executor) CheckHealth(ctx context.Context, conn *Connection) error {
 // GetGPUStatus runs nvidia-smi and returns parsed status
 func (e *Executor) GetGPUStatus(ctx context.Context, conn *Connection) (*GPUStatus, error) {
 	// Use nvidia-smi with CSV format for easy parsing
-	cmd := "nvidia-smi --query-gpu=name,memory.used,memory.total,utilization.gpu,temperature.gpu,power.draw --format=csv,noheader,nounits"
-	stdout, stderr, err := e.RunCommand(ctx, conn, cmd)
+	stdout, stderr, err := e.RunCommand(ctx, conn, nvidiaSMIQuery)
 	if err != nil {
 		return nil, fmt.Errorf("nvidia-smi failed: %w (stderr: %s)", err, stderr)
 	}
@@ -287,6 +286,37 @@ func (e *Executor) CheckOOM(ctx context.Context, conn *Connection) (*OOMStatus,
 	return ParseOOMOutput(stdout), nil
 }
 
+// MeasureDiskWrite writes 256MB to disk, flushing it before dd reports, and
+// returns the write rate in MB/s
+func (e *Executor) MeasureDiskWrite(ctx context.Context, conn *Connection) (float64, error) {
+	cmd := `dd if=/dev/zero of=/var/tmp/.gpu-shopper-ddtest bs=1M count=256 conv=fdatasync 2>&1; rm -f /var/tmp/.gpu-shopper-ddtest`
+	stdout, stderr, err := e.RunCommand(ctx, conn, cmd)
+	if err != nil {
+		return 0, fmt.Errorf("dd failed: %w (stderr: %s)", err, stderr)
+	}
+	return ParseDDThroughput(stdout)
+}
+
+// MeasureDownload downloads url for up to 10 seconds and returns the average
+// rate in Mbps. The transfer counts toward the instance's bandwidth charges.
+func (e *Executor) MeasureDownload(ctx context.Context, conn *Connection, url string) (float64, error) {
+	// curl still prints the rate when --max-time cuts a large download short
+	cmd := fmt.Sprintf(`curl -s -o /dev/null --max-time 10 -w '%%{speed_download}' '%s' || true`,
+		strings.ReplaceAll(url, "'", "'\\''"))
+	stdout, _, err := e.RunCommand(ctx, conn, cmd)
+	if err != nil {
+		return 0, fmt.Errorf("curl failed: %w", err)
+	}
+	mbps, err := ParseDownloadSpeed(stdout)
+	if err != nil {
+		return 0, err
+	}
+	if mbps == 0 {
+		return 0, fmt.Errorf("nothing downloaded from %s", url)
+	}
+	return mbps, nil
+}
+
 // GetCUDAVersion retrieves CUDA version information from the remote host.
 // BUG-004: Post-provision CUDA validation to detect version mismatches.
 func (e *Executor) GetCUDAVersion(ctx context.Context, conn *Connection) (*CUDAInfo, error) {
@@ -46,6 +46,9 @@ func (g *GPUStatus) String() string {
 	)
 }
 
+// nvidiaSMIQuery is the nvidia-smi command whose output ParseNvidiaSMI expects
+const nvidiaSMIQuery = "nvidia-smi --query-gpu=name,memory.used,memory.total,utilization.gpu,temperature.gpu,power.draw --format=csv,noheader,nounits"
+
 // ParseNvidiaSMI parses nvidia-smi output into GPUStatus
 // Expected format from: nvidia-smi --query-gpu=name,memory.used,memory.total,utilization.gpu,temperature.gpu,power.draw --format=csv,noheader,nounits
 // Example output: "NVIDIA GeForce RTX 3090, 1234, 24576, 45, 65, 250"
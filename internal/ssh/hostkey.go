@@ -0,0 +1,78 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// HostKeyMismatchError is returned when a host presents a different key
+// than the one recorded on first connection
+type HostKeyMismatchError struct {
+	Addr     string
+	Expected string // SHA256 fingerprint recorded on first use
+	Actual   string // SHA256 fingerprint the host presented
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("host key for %s changed: expected %s, got %s", e.Addr, e.Expected, e.Actual)
+}
+
+// Fingerprint returns the SHA256 fingerprint of a host key in the format
+// printed by ssh-keygen -l, e.g. "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"
+func Fingerprint(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
+
+// TrustOnFirstUse returns a host key callback that pins the first key it
+// sees. With an empty fingerprint the first key is accepted and passed to
+// record, which may be nil; every later key, and every key when fingerprint
+// is set, must match or the handshake fails with a HostKeyMismatchError.
+func TrustOnFirstUse(fingerprint string, record func(fingerprint string)) ssh.HostKeyCallback {
+	var mu sync.Mutex
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		actual := Fingerprint(key)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if fingerprint == "" {
+			fingerprint = actual
+			if record != nil {
+				record(actual)
+			}
+			return nil
+		}
+		if actual != fingerprint {
+			return &HostKeyMismatchError{Addr: hostname, Expected: fingerprint, Actual: actual}
+		}
+		return nil
+	}
+}
+
+// ErrNoHostKeyCallback is returned when connecting with a context that has
+// no host key callback, so the host key cannot be checked
+var ErrNoHostKeyCallback = errors.New("no host key callback to check the host key with")
+
+type hostKeyCallbackKey struct{}
+
+// WithHostKeyCallback returns a context that makes connections opened with
+// it by this package check host keys with callback. Without one, every
+// connection fails with ErrNoHostKeyCallback.
+func WithHostKeyCallback(ctx context.Context, callback ssh.HostKeyCallback) context.Context {
+	return context.WithValue(ctx, hostKeyCallbackKey{}, callback)
+}
+
+// HostKeyCallback returns the context's host key callback, or one that
+// rejects every key if none was set
+func HostKeyCallback(ctx context.Context) ssh.HostKeyCallback {
+	if callback, ok := ctx.Value(hostKeyCallbackKey{}).(ssh.HostKeyCallback); ok && callback != nil {
+		return callback
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		return fmt.Errorf("%s: %w", hostname, ErrNoHostKeyCallback)
+	}
+}
@@ -0,0 +1,152 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// startHandshakeServer runs an SSH server with the given host key that
+// accepts any client and closes each connection after the handshake
+func startHandshakeServer(t *testing.T, hostKey ssh.Signer) (string, int) {
+	t.Helper()
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			nc, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer nc.Close()
+				conn, chans, reqs, err := ssh.NewServerConn(nc, config)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				go ssh.DiscardRequests(reqs)
+				for newCh := range chans {
+					newCh.Reject(ssh.UnknownChannelType, "unsupported")
+				}
+			}()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestTrustOnFirstUse(t *testing.T) {
+	first := newTestSigner(t).PublicKey()
+	other := newTestSigner(t).PublicKey()
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22}
+
+	var recorded []string
+	callback := TrustOnFirstUse("", func(fingerprint string) {
+		recorded = append(recorded, fingerprint)
+	})
+
+	if err := callback("host:22", addr, first); err != nil {
+		t.Fatalf("first key should be trusted: %v", err)
+	}
+	if len(recorded) != 1 || recorded[0] != Fingerprint(first) {
+		t.Fatalf("expected first fingerprint to be recorded once, got %v", recorded)
+	}
+	if err := callback("host:22", addr, first); err != nil {
+		t.Errorf("same key should be accepted: %v", err)
+	}
+
+	err := callback("host:22", addr, other)
+	var mismatch *HostKeyMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected HostKeyMismatchError, got %v", err)
+	}
+	if mismatch.Expected != Fingerprint(first) || mismatch.Actual != Fingerprint(other) {
+		t.Errorf("unexpected mismatch details: %+v", mismatch)
+	}
+	if len(recorded) != 1 {
+		t.Errorf("only the first key should be recorded, got %v", recorded)
+	}
+
+	// A pinned fingerprint is enforced from the first connection
+	pinned := TrustOnFirstUse(Fingerprint(first), nil)
+	if err := pinned("host:22", addr, other); !errors.As(err, &mismatch) {
+		t.Errorf("expected HostKeyMismatchError for pinned key, got %v", err)
+	}
+	if err := pinned("host:22", addr, first); err != nil {
+		t.Errorf("pinned key should be accepted: %v", err)
+	}
+}
+
+func TestExecutorConnect_HostKeyCallback(t *testing.T) {
+	hostKey := newTestSigner(t)
+	host, port := startHandshakeServer(t, hostKey)
+
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(block))
+
+	e := NewExecutor()
+
+	// Without a callback in the context, the host key cannot be checked
+	_, err = e.Connect(context.Background(), host, port, "test", privateKey)
+	if !errors.Is(err, ErrNoHostKeyCallback) {
+		t.Fatalf("expected ErrNoHostKeyCallback without host key callback, got %v", err)
+	}
+
+	var recorded string
+	ctx := WithHostKeyCallback(context.Background(), TrustOnFirstUse("", func(fingerprint string) {
+		recorded = fingerprint
+	}))
+	conn, err := e.Connect(ctx, host, port, "test", privateKey)
+	if err != nil {
+		t.Fatalf("connect with trust on first use: %v", err)
+	}
+	conn.Close()
+	if recorded != Fingerprint(hostKey.PublicKey()) {
+		t.Errorf("expected host key %s to be recorded, got %q", Fingerprint(hostKey.PublicKey()), recorded)
+	}
+
+	wrong := Fingerprint(newTestSigner(t).PublicKey())
+	ctx = WithHostKeyCallback(context.Background(), TrustOnFirstUse(wrong, nil))
+	_, err = e.Connect(ctx, host, port, "test", privateKey)
+	var mismatch *HostKeyMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected HostKeyMismatchError for changed host key, got %v", err)
+	}
+	if mismatch.Expected != wrong {
+		t.Errorf("expected mismatch against %s, got %s", wrong, mismatch.Expected)
+	}
+}
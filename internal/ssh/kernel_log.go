@@ -0,0 +1,135 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// KernelEventKind classifies a kernel log error
+type KernelEventKind string
+
+const (
+	// KernelOOM is the OOM killer at work
+	KernelOOM KernelEventKind = "oom"
+	// KernelXid is an NVIDIA driver Xid error, reported for GPU faults
+	KernelXid KernelEventKind = "xid"
+	// KernelError is any other error the kernel logged, e.g. a segfault
+	// or an I/O error
+	KernelError KernelEventKind = "error"
+)
+
+// kernelErrorsCmd prints kernel log lines reporting errors, with their
+// seconds since boot. journalctl is the fallback where dmesg is restricted.
+const kernelErrorsCmd = `{ dmesg 2>/dev/null || journalctl -k --no-pager -o short-monotonic 2>/dev/null; } | ` +
+	`grep -E -i "out of memory|oom-kill|killed process|NVRM: Xid|segfault|general protection|hardware error|I/O error|kernel panic|BUG:" | tail -200; true`
+
+// KernelEvent is one error line of an instance's kernel log
+type KernelEvent struct {
+	Uptime  float64 // Seconds since boot when it was logged
+	Kind    KernelEventKind
+	Message string
+}
+
+// kernelLineRe matches a kernel log line with its monotonic timestamp, as
+// dmesg and journalctl -o short-monotonic print them:
+//
+//	[ 1234.567890] Out of memory: Killed process 1234 (python3) ...
+//	[ 1234.567890] host kernel: NVRM: Xid (PCI:0000:01:00): 79, ...
+var kernelLineRe = regexp.MustCompile(`^\[\s*(\d+\.\d+)\]\s*(?:\S+ kernel: )?(.*)$`)
+
+// xidRe extracts the Xid code from an NVRM: Xid line
+var xidRe = regexp.MustCompile(`NVRM: Xid \([^)]*\): (\d+)`)
+
+// ParseKernelErrors parses the output of kernelErrorsCmd. Lines without a
+// timestamp are skipped, since they cannot be told apart from one poll to
+// the next.
+func ParseKernelErrors(output string) []KernelEvent {
+	var events []KernelEvent
+	for _, line := range strings.Split(output, "\n") {
+		m := kernelLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		uptime, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		msg := strings.TrimSpace(m[2])
+		lower := strings.ToLower(msg)
+		kind := KernelError
+		switch {
+		case strings.Contains(msg, "NVRM: Xid"):
+			kind = KernelXid
+		case strings.Contains(lower, "out of memory") || strings.Contains(lower, "oom-kill") ||
+			strings.Contains(lower, "killed process"):
+			kind = KernelOOM
+		}
+		events = append(events, KernelEvent{Uptime: uptime, Kind: kind, Message: msg})
+	}
+	return events
+}
+
+// DescribeKernelEvents summarizes events of one kind, e.g. "OOM killer
+// killed python3" or "GPU Xid errors 79, 13"
+func DescribeKernelEvents(kind KernelEventKind, events []KernelEvent) string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	var last string
+	for _, e := range events {
+		if e.Kind != kind {
+			continue
+		}
+		last = e.Message
+		switch kind {
+		case KernelOOM:
+			if m := killedProcessRe.FindStringSubmatch(e.Message); m != nil {
+				add(m[1])
+			}
+		case KernelXid:
+			if m := xidRe.FindStringSubmatch(e.Message); m != nil {
+				add(m[1])
+			}
+		}
+	}
+	if last == "" {
+		return ""
+	}
+
+	switch kind {
+	case KernelOOM:
+		if len(names) == 0 {
+			return "out of memory"
+		}
+		return "OOM killer killed " + strings.Join(names, ", ")
+	case KernelXid:
+		if len(names) == 1 {
+			return "GPU Xid error " + names[0]
+		}
+		if len(names) > 1 {
+			return "GPU Xid errors " + strings.Join(names, ", ")
+		}
+		return "GPU Xid error"
+	default:
+		return "kernel error: " + last
+	}
+}
+
+// ReadKernelErrors returns the error lines of the instance's kernel log, such
+// as OOM kills and GPU Xid errors, oldest first. An instance whose kernel log
+// cannot be read reports none.
+func (e *Executor) ReadKernelErrors(ctx context.Context, conn *Connection) ([]KernelEvent, error) {
+	stdout, stderr, err := e.RunCommand(ctx, conn, kernelErrorsCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kernel log: %w (stderr: %s)", err, stderr)
+	}
+	return ParseKernelErrors(stdout), nil
+}
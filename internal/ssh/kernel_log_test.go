@@ -0,0 +1,46 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKernelErrors(t *testing.T) {
+	output := `[ 5021.118204] python3 invoked oom-killer: gfp_mask=0x140cca(GFP_HIGHUSER_MOVABLE|__GFP_COMP), order=0, oom_score_adj=0
+[ 5021.118391] Out of memory: Killed process 4242 (python3) total-vm:48812356kB, anon-rss:31822160kB
+[ 6100.000001] gpu-host kernel: NVRM: Xid (PCI:0000:01:00): 79, pid=4300, GPU has fallen off the bus.
+[ 7000.5] python3[5000]: segfault at 0 ip 00007f sp 00007ffd error 4 in libc.so.6
+Thu Feb  6 12:34:56 2026 Out of memory: Killed process 1 (init)
+`
+	events := ParseKernelErrors(output)
+	require.Len(t, events, 4)
+
+	assert.Equal(t, KernelEvent{Uptime: 5021.118204, Kind: KernelOOM,
+		Message: "python3 invoked oom-killer: gfp_mask=0x140cca(GFP_HIGHUSER_MOVABLE|__GFP_COMP), order=0, oom_score_adj=0"}, events[0])
+	assert.Equal(t, KernelOOM, events[1].Kind)
+	assert.Equal(t, KernelXid, events[2].Kind)
+	assert.Equal(t, "NVRM: Xid (PCI:0000:01:00): 79, pid=4300, GPU has fallen off the bus.", events[2].Message)
+	assert.Equal(t, KernelError, events[3].Kind)
+	assert.Equal(t, 7000.5, events[3].Uptime)
+
+	assert.Empty(t, ParseKernelErrors(""))
+}
+
+func TestDescribeKernelEvents(t *testing.T) {
+	events := ParseKernelErrors(`[ 10.0] Out of memory: Killed process 4242 (python3) total-vm:1kB
+[ 11.0] oom-kill:constraint=CONSTRAINT_NONE,task=ollama,pid=77
+[ 12.0] Out of memory: Killed process 77 (ollama) total-vm:1kB
+[ 13.0] Out of memory: Killed process 4243 (python3) total-vm:1kB
+[ 20.0] NVRM: Xid (PCI:0000:01:00): 79, pid=4300, GPU has fallen off the bus.
+[ 21.0] NVRM: Xid (PCI:0000:02:00): 13, pid=4300, Graphics Exception
+[ 30.0] blk_update_request: I/O error, dev nvme0n1, sector 2048`)
+
+	assert.Equal(t, "OOM killer killed python3, ollama", DescribeKernelEvents(KernelOOM, events))
+	assert.Equal(t, "GPU Xid errors 79, 13", DescribeKernelEvents(KernelXid, events))
+	assert.Equal(t, "GPU Xid error 79", DescribeKernelEvents(KernelXid, events[4:5]))
+	assert.Equal(t, "kernel error: blk_update_request: I/O error, dev nvme0n1, sector 2048", DescribeKernelEvents(KernelError, events))
+	assert.Equal(t, "out of memory", DescribeKernelEvents(KernelOOM, events[1:2]))
+	assert.Empty(t, DescribeKernelEvents(KernelOOM, events[4:]))
+}
@@ -0,0 +1,55 @@
+package ssh
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ddRateRe matches the transfer rate at the end of dd's summary line, e.g.
+// "268435456 bytes (268 MB, 256 MiB) copied, 0.52 s, 516 MB/s"
+var ddRateRe = regexp.MustCompile(`([\d.,]+)\s*([kMGT]?i?B)/s\s*$`)
+
+// ddUnits converts dd's rate units to megabytes
+var ddUnits = map[string]float64{
+	"B":   1e-6,
+	"kB":  1e-3,
+	"KiB": 1024 / 1e6,
+	"MB":  1,
+	"MiB": 1024 * 1024 / 1e6,
+	"GB":  1e3,
+	"GiB": 1024 * 1024 * 1024 / 1e6,
+	"TB":  1e6,
+}
+
+// ParseDDThroughput extracts the write rate in MB/s from dd's output
+func ParseDDThroughput(output string) (float64, error) {
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		matches := ddRateRe.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		unit, ok := ddUnits[matches[2]]
+		if !ok {
+			continue
+		}
+		// Some locales print a decimal comma
+		rate, err := strconv.ParseFloat(strings.ReplaceAll(matches[1], ",", "."), 64)
+		if err != nil {
+			continue
+		}
+		return rate * unit, nil
+	}
+	return 0, fmt.Errorf("no transfer rate in dd output: %q", output)
+}
+
+// ParseDownloadSpeed converts curl's %{speed_download}, in bytes per second,
+// to megabits per second
+func ParseDownloadSpeed(output string) (float64, error) {
+	bytesPerSec, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid curl speed %q: %w", output, err)
+	}
+	return bytesPerSec * 8 / 1e6, nil
+}
@@ -0,0 +1,73 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDDThroughput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "GNU dd",
+			output: `256+0 records in
+256+0 records out
+268435456 bytes (268 MB, 256 MiB) copied, 0.52 s, 516 MB/s`,
+			want: 516,
+		},
+		{
+			name: "GNU dd fast disk",
+			output: `256+0 records in
+256+0 records out
+268435456 bytes (268 MB, 256 MiB) copied, 0.128 s, 2.1 GB/s`,
+			want: 2100,
+		},
+		{
+			name:   "decimal comma",
+			output: `268435456 bytes (268 MB, 256 MiB) copied, 2,5 s, 107,4 MB/s`,
+			want:   107.4,
+		},
+		{
+			name:   "binary units",
+			output: `268435456 bytes (256MiB) copied, 0.5s, 512MiB/s`,
+			want:   512 * 1024 * 1024 / 1e6,
+		},
+		{
+			name:    "dd failed",
+			output:  `dd: failed to open '/var/tmp/.gpu-shopper-ddtest': Read-only file system`,
+			wantErr: true,
+		},
+		{
+			name:    "empty output",
+			output:  "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDDThroughput(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-6)
+		})
+	}
+}
+
+func TestParseDownloadSpeed(t *testing.T) {
+	mbps, err := ParseDownloadSpeed("125000000.000\n")
+	require.NoError(t, err)
+	assert.InDelta(t, 1000, mbps, 1e-9)
+
+	_, err = ParseDownloadSpeed("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Tunnel forwards connections from a local port to an address reachable
+// from the remote host, like ssh -L
+type Tunnel struct {
+	listener net.Listener
+	client   *ssh.Client
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// Forward listens on a random loopback port and forwards each connection
+// through conn to remoteAddr, e.g. "127.0.0.1:8000" for a server bound to
+// the remote host's loopback. Close the tunnel before the connection.
+func (e *Executor) Forward(conn *Connection, remoteAddr string) (*Tunnel, error) {
+	if conn == nil || conn.client == nil {
+		return nil, fmt.Errorf("connection is nil or closed")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for tunnel: %w", err)
+	}
+
+	t := &Tunnel{
+		listener: listener,
+		client:   conn.client,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	go t.serve(remoteAddr)
+	return t, nil
+}
+
+// Addr returns the local host:port that reaches the remote address
+func (t *Tunnel) Addr() string {
+	return t.listener.Addr().String()
+}
+
+// Close stops accepting connections and closes the forwarded ones
+func (t *Tunnel) Close() error {
+	err := t.listener.Close()
+	t.mu.Lock()
+	for c := range t.conns {
+		c.Close()
+	}
+	t.conns = nil
+	t.mu.Unlock()
+	return err
+}
+
+func (t *Tunnel) serve(remoteAddr string) {
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			return // Closed
+		}
+		go t.pipe(local, remoteAddr)
+	}
+}
+
+func (t *Tunnel) pipe(local net.Conn, remoteAddr string) {
+	remote, err := t.client.Dial("tcp", remoteAddr)
+	if err != nil {
+		local.Close()
+		return
+	}
+	if !t.track(local, remote) {
+		return
+	}
+	defer t.untrack(local, remote)
+
+	done := make(chan struct{})
+	go func() {
+		io.Copy(remote, local)
+		remote.Close()
+		close(done)
+	}()
+	io.Copy(local, remote)
+	local.Close()
+	<-done
+}
+
+// track registers conns for Close, or closes them if the tunnel already is
+func (t *Tunnel) track(conns ...net.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns == nil {
+		for _, c := range conns {
+			c.Close()
+		}
+		return false
+	}
+	for _, c := range conns {
+		t.conns[c] = struct{}{}
+	}
+	return true
+}
+
+func (t *Tunnel) untrack(conns ...net.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range conns {
+		delete(t.conns, c)
+	}
+}
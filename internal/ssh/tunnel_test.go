@@ -0,0 +1,128 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startForwardingServer runs an SSH server that accepts any client and
+// serves direct-tcpip (ssh -L) channels, and returns a connection to it
+func startForwardingServer(t *testing.T) *Connection {
+	t.Helper()
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		nc, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_, chans, reqs, err := ssh.NewServerConn(nc, config)
+		if err != nil {
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+		for newCh := range chans {
+			var target struct {
+				Host     string
+				Port     uint32
+				OrigHost string
+				OrigPort uint32
+			}
+			if newCh.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newCh.ExtraData(), &target) != nil {
+				newCh.Reject(ssh.UnknownChannelType, "unsupported")
+				continue
+			}
+			ch, chReqs, err := newCh.Accept()
+			if err != nil {
+				continue
+			}
+			go ssh.DiscardRequests(chReqs)
+			go func() {
+				defer ch.Close()
+				upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go io.Copy(upstream, ch)
+				io.Copy(ch, upstream)
+			}()
+		}
+	}()
+
+	addr := listener.Addr().String()
+	nc, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientConn, chans, reqs, err := ssh.NewClientConn(nc, addr, &ssh.ClientConfig{
+		User:            "test",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &Connection{client: ssh.NewClient(clientConn, chans, reqs), host: "127.0.0.1", user: "test"}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestExecutor_Forward(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer upstream.Close()
+
+	conn := startForwardingServer(t)
+	e := NewExecutor()
+	tunnel, err := e.Forward(conn, upstream.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Forward returned error: %v", err)
+	}
+
+	resp, err := http.Get("http://" + tunnel.Addr() + "/health")
+	if err != nil {
+		t.Fatalf("request through tunnel failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("expected upstream response through tunnel, got %q", body)
+	}
+
+	if err := tunnel.Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+	if _, err := net.Dial("tcp", tunnel.Addr()); err == nil {
+		t.Error("expected closed tunnel to refuse connections")
+	}
+}
+
+func TestExecutor_Forward_NilConnection(t *testing.T) {
+	if _, err := NewExecutor().Forward(nil, "127.0.0.1:8000"); err == nil {
+		t.Error("expected error for nil connection")
+	}
+}
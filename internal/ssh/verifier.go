@@ -182,7 +182,7 @@ func (v *Verifier) tryConnect(ctx context.Context, host string, port int, user s
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(signer),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // GPU instances have dynamic host keys
+		HostKeyCallback: HostKeyCallback(ctx),
 		Timeout:         v.connectTimeout,
 	}
 
@@ -262,6 +262,15 @@ func (v *Verifier) VerifyOnce(ctx context.Context, host string, port int, user,
 	return v.tryConnect(ctx, host, port, user, signer)
 }
 
+// ListGPUs connects via SSH and returns the status of every GPU nvidia-smi lists
+func (v *Verifier) ListGPUs(ctx context.Context, host string, port int, user, privateKey string) ([]*GPUStatus, error) {
+	stdout, err := RunCommand(ctx, host, port, user, privateKey, nvidiaSMIQuery)
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi failed: %w", err)
+	}
+	return ParseMultiGPUNvidiaSMI(stdout)
+}
+
 // RunCommand connects via SSH and runs an arbitrary command, returning stdout.
 func RunCommand(ctx context.Context, host string, port int, user, privateKey, command string) (string, error) {
 	if host == "" || port <= 0 || user == "" || privateKey == "" {
@@ -276,7 +285,7 @@ func RunCommand(ctx context.Context, host string, port int, user, privateKey, co
 	config := &ssh.ClientConfig{
 		User:            user,
 		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		HostKeyCallback: HostKeyCallback(ctx),
 		Timeout:         30 * time.Second,
 	}
 
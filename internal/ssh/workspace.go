@@ -0,0 +1,127 @@
+package ssh
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// workspaceUploadScript tars $W and uploads it in $PART-byte parts, one per
+// presigned URL in $URLS (base64, one per line). Each part is spooled to a
+// temporary file first, because presigned PUTs need a Content-Length. tar's
+// exit status 1 only means a file changed while it was read.
+const workspaceUploadScript = `set -o pipefail
+URLS_FILE=$(mktemp)
+trap 'rm -f "$URLS_FILE"' EXIT
+echo "$URLS" | base64 -d >"$URLS_FILE"
+export URLS_FILE
+{ tar -cf - -C "$W" .; [ $? -le 1 ]; } | SHELL=/bin/sh split -b "$PART" -d -a 3 --filter '
+n=$(expr "${FILE#x}" + 1)
+url=$(sed -n "${n}p" "$URLS_FILE")
+[ -n "$url" ] || { echo "no upload URL for part $n" >&2; exit 1; }
+part=$(mktemp)
+cat >"$part"
+size=$(wc -c <"$part")
+etag=$(curl -sSf --retry 3 -T "$part" -D - -o /dev/null "$url" | tr -d "\r" | sed -n "s/^[Ee][Tt][Aa][Gg]: *//p")
+rm -f "$part"
+[ -n "$etag" ] || { echo "no ETag for part $n" >&2; exit 1; }
+echo "part $n $size $etag"
+' -
+`
+
+// workspacePartRe matches an uploaded part, e.g. "part 1 16777216 "9b2c...""
+var workspacePartRe = regexp.MustCompile(`^part (\d+) (\d+) (\S+)$`)
+
+// ParseWorkspaceUpload parses the output of the workspace upload script into
+// the uploaded parts' ETags, in part order, and the total bytes uploaded
+func ParseWorkspaceUpload(output string) ([]string, int64, error) {
+	etags := make(map[int]string)
+	var size int64
+	for _, line := range strings.Split(output, "\n") {
+		m := workspacePartRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		partSize, _ := strconv.ParseInt(m[2], 10, 64)
+		etags[n] = m[3]
+		size += partSize
+	}
+	if len(etags) == 0 {
+		return nil, 0, fmt.Errorf("no parts in upload output: %q", output)
+	}
+
+	parts := make([]int, 0, len(etags))
+	for n := range etags {
+		parts = append(parts, n)
+	}
+	sort.Ints(parts)
+	ordered := make([]string, len(parts))
+	for i, n := range parts {
+		if n != i+1 {
+			return nil, 0, fmt.Errorf("part %d missing from upload output", i+1)
+		}
+		ordered[i] = etags[n]
+	}
+	return ordered, size, nil
+}
+
+// MeasureWorkspace returns the size in bytes and the number of files and
+// directories under dir, or zeros if dir does not exist
+func (e *Executor) MeasureWorkspace(ctx context.Context, conn *Connection, dir string) (size, files int64, err error) {
+	cmd := fmt.Sprintf(`W=%s; [ -d "$W" ] || { echo "0 0"; exit 0; }; echo "$(du -sb "$W" | cut -f1) $(find "$W" -mindepth 1 | wc -l)"`,
+		quoteShell(dir))
+	stdout, stderr, err := e.RunCommand(ctx, conn, cmd)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to measure %s: %w (stderr: %s)", dir, err, stderr)
+	}
+	fields := strings.Fields(stdout)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected workspace size output: %q", stdout)
+	}
+	if size, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid workspace size %q: %w", fields[0], err)
+	}
+	if files, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid workspace file count %q: %w", fields[1], err)
+	}
+	return size, files, nil
+}
+
+// UploadWorkspace tars dir and uploads it as a multipart upload, partSize
+// bytes per part, to the presigned part URLs in order. It returns the parts'
+// ETags and the size of the tarball. There must be enough URLs for every
+// part; the instance needs curl and GNU split, and partSize of free space in
+// its temporary directory.
+func (e *Executor) UploadWorkspace(ctx context.Context, conn *Connection, dir string, partSize int64, urls []string) ([]string, int64, error) {
+	cmd := fmt.Sprintf("echo %s | base64 -d | W=%s PART=%d URLS=%s bash",
+		base64.StdEncoding.EncodeToString([]byte(workspaceUploadScript)),
+		quoteShell(dir), partSize,
+		base64.StdEncoding.EncodeToString([]byte(strings.Join(urls, "\n"))))
+	stdout, stderr, err := e.RunCommand(ctx, conn, cmd)
+	if err != nil {
+		return nil, 0, fmt.Errorf("workspace upload failed: %w (stderr: %s)", err, stderr)
+	}
+	return ParseWorkspaceUpload(stdout)
+}
+
+// RestoreWorkspace downloads a tarball from url and extracts it into dir,
+// creating dir if needed. Files already in dir are overwritten.
+func (e *Executor) RestoreWorkspace(ctx context.Context, conn *Connection, dir, url string) error {
+	cmd := fmt.Sprintf(`set -o pipefail; mkdir -p %[1]s && curl -sSfL --retry 3 %[2]s | tar -xf - -C %[1]s`,
+		quoteShell(dir), quoteShell(url))
+	_, stderr, err := e.RunCommand(ctx, conn, "bash -c "+quoteShell(cmd))
+	if err != nil {
+		return fmt.Errorf("workspace restore failed: %w (stderr: %s)", err, stderr)
+	}
+	return nil
+}
+
+// quoteShell single-quotes s for a POSIX shell
+func quoteShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,34 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkspaceUpload(t *testing.T) {
+	// Parts are ordered by number, and other output, such as curl's retry
+	// notices, is ignored
+	output := `part 2 16777216 "b1946ac92492d2347c6235b4d2611184"
+part 1 16777216 "6f5902ac237024bdd0c176cb93063dc4"
+curl: (6) Could not resolve host, retrying
+part 3 1024 "7c6a180b36896a0a8c02787eeafb0e4c"
+`
+	etags, size, err := ParseWorkspaceUpload(output)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`"6f5902ac237024bdd0c176cb93063dc4"`,
+		`"b1946ac92492d2347c6235b4d2611184"`,
+		`"7c6a180b36896a0a8c02787eeafb0e4c"`,
+	}, etags)
+	assert.Equal(t, int64(16777216*2+1024), size)
+}
+
+func TestParseWorkspaceUpload_Incomplete(t *testing.T) {
+	_, _, err := ParseWorkspaceUpload("")
+	assert.Error(t, err)
+
+	_, _, err = ParseWorkspaceUpload("part 1 10 \"a\"\npart 3 10 \"c\"\n")
+	assert.ErrorContains(t, err, "part 2 missing")
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/google/uuid"
+)
+
+// AuditStore handles persistence of the admin audit trail
+type AuditStore struct {
+	db *DB
+}
+
+// NewAuditStore creates a new audit store
+func NewAuditStore(db *DB) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+const auditColumns = `id, actor, action, consumer_id, session_id, reason, details, request_id, created_at`
+
+// Record appends an entry to the audit trail. Entries are never updated or deleted.
+func (s *AuditStore) Record(ctx context.Context, entry *models.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	query := `INSERT INTO admin_audit_log (` + auditColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		entry.ID,
+		entry.Actor,
+		entry.Action,
+		entry.ConsumerID,
+		entry.SessionID,
+		entry.Reason,
+		entry.Details,
+		entry.RequestID,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves audit entries matching the filter, newest first
+func (s *AuditStore) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	query := `SELECT ` + auditColumns + ` FROM admin_audit_log WHERE 1=1`
+
+	var args []interface{}
+
+	if filter.Actor != "" {
+		query += " AND actor = ?"
+		args = append(args, filter.Actor)
+	}
+
+	if filter.ConsumerID != "" {
+		query += " AND consumer_id = ?"
+		args = append(args, filter.ConsumerID)
+	}
+
+	if filter.SessionID != "" {
+		query += " AND session_id = ?"
+		args = append(args, filter.SessionID)
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func scanAuditEntry(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AuditEntry, error) {
+	var entry models.AuditEntry
+	err := scanner.Scan(
+		&entry.ID,
+		&entry.Actor,
+		&entry.Action,
+		&entry.ConsumerID,
+		&entry.SessionID,
+		&entry.Reason,
+		&entry.Details,
+		&entry.RequestID,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditStore_RecordAndList(t *testing.T) {
+	db := newTestDB(t)
+	store := NewAuditStore(db)
+	ctx := context.Background()
+
+	base := time.Now().UTC().Truncate(time.Second)
+	entries := []*models.AuditEntry{
+		{Actor: "alice", Action: models.AuditActionListSessions, ConsumerID: "consumer-001", CreatedAt: base},
+		{Actor: "alice", Action: models.AuditActionExtendSession, ConsumerID: "consumer-001", SessionID: "sess-1",
+			Reason: "ticket-42", Details: "additional_hours=2", RequestID: "req-1", CreatedAt: base.Add(time.Minute)},
+		{Actor: "bob", Action: models.AuditActionDestroySession, ConsumerID: "consumer-002", SessionID: "sess-2", CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, e := range entries {
+		require.NoError(t, store.Record(ctx, e))
+		require.NotEmpty(t, e.ID)
+	}
+
+	all, err := store.List(ctx, models.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "bob", all[0].Actor, "newest first")
+
+	byConsumer, err := store.List(ctx, models.AuditFilter{ConsumerID: "consumer-001"})
+	require.NoError(t, err)
+	require.Len(t, byConsumer, 2)
+	assert.Equal(t, models.AuditActionExtendSession, byConsumer[0].Action)
+	assert.Equal(t, "sess-1", byConsumer[0].SessionID)
+	assert.Equal(t, "ticket-42", byConsumer[0].Reason)
+	assert.Equal(t, "additional_hours=2", byConsumer[0].Details)
+	assert.Equal(t, "req-1", byConsumer[0].RequestID)
+
+	byActor, err := store.List(ctx, models.AuditFilter{Actor: "bob"})
+	require.NoError(t, err)
+	require.Len(t, byActor, 1)
+
+	bySession, err := store.List(ctx, models.AuditFilter{SessionID: "sess-2"})
+	require.NoError(t, err)
+	require.Len(t, bySession, 1)
+
+	limited, err := store.List(ctx, models.AuditFilter{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
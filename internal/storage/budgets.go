@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/google/uuid"
+)
+
+// BudgetStore handles budget persistence
+type BudgetStore struct {
+	db *DB
+}
+
+// NewBudgetStore creates a new budget store
+func NewBudgetStore(db *DB) *BudgetStore {
+	return &BudgetStore{db: db}
+}
+
+const budgetColumns = `id, scope, scope_id, period, limit_usd, warning_sent_at, exceeded_sent_at, created_at, updated_at`
+
+// Upsert creates a budget, or replaces the limit of the existing budget with
+// the same scope, scope ID and period. Alert state is preserved on update.
+func (s *BudgetStore) Upsert(ctx context.Context, budget *models.Budget) error {
+	if budget.ID == "" {
+		budget.ID = uuid.New().String()
+	}
+	now := time.Now().UTC()
+	if budget.CreatedAt.IsZero() {
+		budget.CreatedAt = now
+	}
+	budget.UpdatedAt = now
+
+	query := `
+		INSERT INTO budgets (id, scope, scope_id, period, limit_usd, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(scope, scope_id, period) DO UPDATE SET
+			limit_usd = excluded.limit_usd,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := s.db.ExecContext(ctx, query,
+		budget.ID,
+		budget.Scope,
+		budget.ScopeID,
+		budget.Period,
+		budget.LimitUSD,
+		budget.CreatedAt,
+		budget.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert budget: %w", err)
+	}
+
+	// Reload so callers see the persisted ID when an existing row was updated
+	stored, err := s.getByScope(ctx, budget.Scope, budget.ScopeID, budget.Period)
+	if err != nil {
+		return err
+	}
+	*budget = *stored
+
+	return nil
+}
+
+// Get retrieves a budget by ID
+func (s *BudgetStore) Get(ctx context.Context, id string) (*models.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE id = ?`
+
+	budget, err := scanBudget(s.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+func (s *BudgetStore) getByScope(ctx context.Context, scope models.BudgetScope, scopeID string, period models.BudgetPeriod) (*models.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE scope = ? AND scope_id = ? AND period = ?`
+
+	budget, err := scanBudget(s.db.QueryRowContext(ctx, query, scope, scopeID, period))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+// List returns all budgets
+func (s *BudgetStore) List(ctx context.Context) ([]*models.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets ORDER BY scope, scope_id, period`
+	return s.query(ctx, query)
+}
+
+// ListForScope returns the budgets that apply to a scope ID
+func (s *BudgetStore) ListForScope(ctx context.Context, scope models.BudgetScope, scopeID string) ([]*models.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE scope = ? AND scope_id = ? ORDER BY period`
+	return s.query(ctx, query, scope, scopeID)
+}
+
+// Delete removes a budget
+func (s *BudgetStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM budgets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// MarkAlerted records when a threshold alert was sent for a budget.
+// alertType is "warning" or "exceeded".
+func (s *BudgetStore) MarkAlerted(ctx context.Context, id, alertType string, at time.Time) error {
+	var column string
+	switch alertType {
+	case "warning":
+		column = "warning_sent_at"
+	case "exceeded":
+		column = "exceeded_sent_at"
+	default:
+		return fmt.Errorf("unknown budget alert type: %s", alertType)
+	}
+
+	query := fmt.Sprintf(`UPDATE budgets SET %s = ? WHERE id = ?`, column)
+	result, err := s.db.ExecContext(ctx, query, at.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark budget alerted: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *BudgetStore) query(ctx context.Context, query string, args ...interface{}) ([]*models.Budget, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []*models.Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+// scanBudget scans a budget from a row
+func scanBudget(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Budget, error) {
+	var b models.Budget
+	var warningSentAt, exceededSentAt sql.NullTime
+
+	err := scanner.Scan(
+		&b.ID,
+		&b.Scope,
+		&b.ScopeID,
+		&b.Period,
+		&b.LimitUSD,
+		&warningSentAt,
+		&exceededSentAt,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if warningSentAt.Valid {
+		b.WarningSentAt = warningSentAt.Time
+	}
+	if exceededSentAt.Valid {
+		b.ExceededSentAt = exceededSentAt.Time
+	}
+
+	return &b, nil
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetStore_UpsertAndGet(t *testing.T) {
+	db := newTestDB(t)
+	store := NewBudgetStore(db)
+	ctx := context.Background()
+
+	budget := &models.Budget{
+		Scope:    models.BudgetScopeConsumer,
+		ScopeID:  "consumer-001",
+		Period:   models.BudgetPeriodMonthly,
+		LimitUSD: 100,
+	}
+	require.NoError(t, store.Upsert(ctx, budget))
+	require.NotEmpty(t, budget.ID)
+
+	got, err := store.Get(ctx, budget.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BudgetScopeConsumer, got.Scope)
+	assert.Equal(t, "consumer-001", got.ScopeID)
+	assert.Equal(t, models.BudgetPeriodMonthly, got.Period)
+	assert.Equal(t, 100.0, got.LimitUSD)
+	assert.True(t, got.WarningSentAt.IsZero())
+}
+
+func TestBudgetStore_UpsertUpdatesExisting(t *testing.T) {
+	db := newTestDB(t)
+	store := NewBudgetStore(db)
+	ctx := context.Background()
+
+	first := &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c1", Period: models.BudgetPeriodDaily, LimitUSD: 10}
+	require.NoError(t, store.Upsert(ctx, first))
+	require.NoError(t, store.MarkAlerted(ctx, first.ID, "warning", time.Now()))
+
+	second := &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c1", Period: models.BudgetPeriodDaily, LimitUSD: 25}
+	require.NoError(t, store.Upsert(ctx, second))
+
+	// Same row is updated, keeping its ID and alert state
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 25.0, second.LimitUSD)
+	assert.False(t, second.WarningSentAt.IsZero())
+
+	all, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestBudgetStore_ListForScope(t *testing.T) {
+	db := newTestDB(t)
+	store := NewBudgetStore(db)
+	ctx := context.Background()
+
+	require.NoError(t, store.Upsert(ctx, &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c1", Period: models.BudgetPeriodDaily, LimitUSD: 10}))
+	require.NoError(t, store.Upsert(ctx, &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c1", Period: models.BudgetPeriodMonthly, LimitUSD: 200}))
+	require.NoError(t, store.Upsert(ctx, &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c2", Period: models.BudgetPeriodDaily, LimitUSD: 5}))
+	require.NoError(t, store.Upsert(ctx, &models.Budget{Scope: models.BudgetScopeDeployment, ScopeID: "default", Period: models.BudgetPeriodMonthly, LimitUSD: 1000}))
+
+	budgets, err := store.ListForScope(ctx, models.BudgetScopeConsumer, "c1")
+	require.NoError(t, err)
+	assert.Len(t, budgets, 2)
+
+	budgets, err = store.ListForScope(ctx, models.BudgetScopeDeployment, "default")
+	require.NoError(t, err)
+	require.Len(t, budgets, 1)
+	assert.Equal(t, 1000.0, budgets[0].LimitUSD)
+}
+
+func TestBudgetStore_Delete(t *testing.T) {
+	db := newTestDB(t)
+	store := NewBudgetStore(db)
+	ctx := context.Background()
+
+	budget := &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c1", Period: models.BudgetPeriodWeekly, LimitUSD: 50}
+	require.NoError(t, store.Upsert(ctx, budget))
+
+	require.NoError(t, store.Delete(ctx, budget.ID))
+
+	_, err := store.Get(ctx, budget.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = store.Delete(ctx, budget.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBudgetStore_MarkAlerted(t *testing.T) {
+	db := newTestDB(t)
+	store := NewBudgetStore(db)
+	ctx := context.Background()
+
+	budget := &models.Budget{Scope: models.BudgetScopeConsumer, ScopeID: "c1", Period: models.BudgetPeriodMonthly, LimitUSD: 50}
+	require.NoError(t, store.Upsert(ctx, budget))
+
+	at := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, store.MarkAlerted(ctx, budget.ID, "exceeded", at))
+
+	got, err := store.Get(ctx, budget.ID)
+	require.NoError(t, err)
+	assert.True(t, got.ExceededSentAt.Equal(at))
+	assert.True(t, got.WarningSentAt.IsZero())
+
+	assert.Error(t, store.MarkAlerted(ctx, budget.ID, "bogus", at))
+	assert.ErrorIs(t, store.MarkAlerted(ctx, "missing", "warning", at), ErrNotFound)
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
@@ -26,14 +27,17 @@ func (s *CostStore) Record(ctx context.Context, record *models.CostRecord) error
 	if record.ID == "" {
 		record.ID = uuid.New().String()
 	}
+	if record.Category == "" {
+		record.Category = models.CostCategoryGPU
+	}
 
-	// Use ON CONFLICT to handle duplicate (session_id, hour) gracefully.
+	// Use ON CONFLICT to handle duplicate (session_id, hour, category) gracefully.
 	// When a duplicate is detected, we update the existing record with the latest values.
 	// This ensures idempotent behavior for repeated aggregation runs within the same hour.
 	query := `
-		INSERT INTO costs (id, session_id, consumer_id, provider, gpu_type, hour, amount, currency)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(session_id, hour) DO UPDATE SET
+		INSERT INTO costs (id, session_id, consumer_id, provider, gpu_type, category, hour, amount, currency)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, hour, category) DO UPDATE SET
 			amount = excluded.amount,
 			consumer_id = excluded.consumer_id,
 			provider = excluded.provider,
@@ -47,6 +51,7 @@ func (s *CostStore) Record(ctx context.Context, record *models.CostRecord) error
 		record.ConsumerID,
 		record.Provider,
 		record.GPUType,
+		record.Category,
 		record.Hour,
 		record.Amount,
 		record.Currency,
@@ -59,6 +64,60 @@ func (s *CostStore) Record(ctx context.Context, record *models.CostRecord) error
 	return nil
 }
 
+// ListBySession returns the cost records of a session, oldest hour first
+func (s *CostStore) ListBySession(ctx context.Context, sessionID string) ([]models.CostRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, session_id, consumer_id, provider, gpu_type, category, hour, amount, currency
+		FROM costs
+		WHERE session_id = ?
+		ORDER BY hour ASC, category ASC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session costs: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.CostRecord
+	for rows.Next() {
+		var r models.CostRecord
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.ConsumerID, &r.Provider, &r.GPUType,
+			&r.Category, &r.Hour, &r.Amount, &r.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan cost record: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// List returns the cost records matching query, oldest hour first
+func (s *CostStore) List(ctx context.Context, query models.CostQuery) ([]models.CostRecord, error) {
+	sqlQuery := `
+		SELECT id, session_id, consumer_id, provider, gpu_type, category, hour, amount, currency
+		FROM costs
+		WHERE 1=1
+	`
+	whereClause, args := s.buildCostFilterClause(query)
+	sqlQuery += whereClause
+	sqlQuery += " ORDER BY hour ASC, session_id ASC, category ASC"
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list costs: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.CostRecord
+	for rows.Next() {
+		var r models.CostRecord
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.ConsumerID, &r.Provider, &r.GPUType,
+			&r.Category, &r.Hour, &r.Amount, &r.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan cost record: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
 // GetSessionCost returns total cost for a session
 func (s *CostStore) GetSessionCost(ctx context.Context, sessionID string) (float64, error) {
 	query := `SELECT COALESCE(SUM(amount), 0) FROM costs WHERE session_id = ?`
@@ -72,6 +131,24 @@ func (s *CostStore) GetSessionCost(ctx context.Context, sessionID string) (float
 	return total, nil
 }
 
+// GetSessionCategoryCost returns a session's total cost for one line item,
+// counting only hours before the given time
+func (s *CostStore) GetSessionCategoryCost(ctx context.Context, sessionID string, category models.CostCategory, before time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM costs
+		WHERE session_id = ? AND category = ? AND hour < ?
+	`
+
+	var total float64
+	err := s.db.QueryRowContext(ctx, query, sessionID, category, before).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get session category cost: %w", err)
+	}
+
+	return total, nil
+}
+
 // GetConsumerCost returns total cost for a consumer in a time period
 func (s *CostStore) GetConsumerCost(ctx context.Context, consumerID string, start, end time.Time) (float64, error) {
 	query := `
@@ -89,13 +166,30 @@ func (s *CostStore) GetConsumerCost(ctx context.Context, consumerID string, star
 	return total, nil
 }
 
+// GetTotalCost returns total cost across all consumers in a time period
+func (s *CostStore) GetTotalCost(ctx context.Context, start, end time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM costs
+		WHERE hour >= ? AND hour < ?
+	`
+
+	var total float64
+	err := s.db.QueryRowContext(ctx, query, start, end).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total cost: %w", err)
+	}
+
+	return total, nil
+}
+
 // GetSummary returns a cost summary for the given query
 func (s *CostStore) GetSummary(ctx context.Context, query models.CostQuery) (*models.CostSummary, error) {
 	sqlQuery := `
 		SELECT
 			COALESCE(SUM(amount), 0) as total_cost,
 			COUNT(DISTINCT session_id) as session_count,
-			COUNT(*) as hours_used
+			COALESCE(SUM(CASE WHEN category = 'gpu' THEN 1 ELSE 0 END), 0) as hours_used
 		FROM costs
 		WHERE 1=1
 	`
@@ -132,9 +226,93 @@ func (s *CostStore) GetSummary(ctx context.Context, query models.CostQuery) (*mo
 		return nil, fmt.Errorf("failed to get GPU breakdown: %w", err)
 	}
 
+	// Get breakdown by line item
+	summary.ByCategory, err = s.aggregateCostsByColumn(ctx, "category", query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category breakdown: %w", err)
+	}
+
 	return summary, nil
 }
 
+// costGroupColumns maps grouping dimensions to cost columns. Deployment has
+// no column: every record in the database belongs to this deployment.
+var costGroupColumns = map[models.CostGroupBy]string{
+	models.CostGroupByConsumer: "consumer_id",
+	models.CostGroupByGPUType:  "gpu_type",
+	models.CostGroupByProvider: "provider",
+}
+
+// GetBreakdown returns spend matching query grouped by the given dimensions,
+// largest first
+func (s *CostStore) GetBreakdown(ctx context.Context, groupBy []models.CostGroupBy, query models.CostQuery) ([]models.CostGroup, error) {
+	var dims []models.CostGroupBy
+	var columns []string
+	for _, g := range groupBy {
+		if column, ok := costGroupColumns[g]; ok {
+			dims = append(dims, g)
+			columns = append(columns, column)
+		}
+	}
+
+	selectCols := ""
+	if len(columns) > 0 {
+		selectCols = strings.Join(columns, ", ") + ", "
+	}
+	sqlQuery := `
+		SELECT ` + selectCols + `
+			COALESCE(SUM(amount), 0) as total_cost,
+			COUNT(DISTINCT session_id) as session_count,
+			COALESCE(SUM(CASE WHEN category = 'gpu' THEN 1 ELSE 0 END), 0) as hours_used
+		FROM costs
+		WHERE 1=1
+	`
+	whereClause, args := s.buildCostFilterClause(query)
+	sqlQuery += whereClause
+	if len(columns) > 0 {
+		sqlQuery += " GROUP BY " + strings.Join(columns, ", ")
+	}
+	sqlQuery += " ORDER BY total_cost DESC"
+	if len(columns) > 0 {
+		sqlQuery += ", " + strings.Join(columns, ", ")
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.CostGroup
+	for rows.Next() {
+		var group models.CostGroup
+		dest := make([]interface{}, 0, len(dims)+3)
+		for _, d := range dims {
+			switch d {
+			case models.CostGroupByConsumer:
+				dest = append(dest, &group.ConsumerID)
+			case models.CostGroupByGPUType:
+				dest = append(dest, &group.GPUType)
+			case models.CostGroupByProvider:
+				dest = append(dest, &group.Provider)
+			}
+		}
+		dest = append(dest, &group.TotalCost, &group.SessionCount, &group.HoursUsed)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan cost breakdown row: %w", err)
+		}
+		if group.SessionCount == 0 {
+			continue // No records at all when nothing is grouped
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cost breakdown rows: %w", err)
+	}
+
+	return groups, nil
+}
+
 // buildCostFilterClause builds WHERE clause conditions and args from a CostQuery.
 // Returns the clause string (starting with " AND" if conditions exist) and the args slice.
 func (s *CostStore) buildCostFilterClause(query models.CostQuery) (string, []interface{}) {
@@ -236,6 +414,7 @@ func (s *CostStore) RecordHourlyForSession(ctx context.Context, session *models.
 		ConsumerID: session.ConsumerID,
 		Provider:   session.Provider,
 		GPUType:    session.GPUType,
+		Category:   models.CostCategoryGPU,
 		Hour:       time.Now().Truncate(time.Hour),
 		Amount:     session.PricePerHour,
 		Currency:   "USD",
@@ -496,3 +496,201 @@ func TestCostStore_Record_DifferentHoursNotDeduplicated(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1.50, total, "All three hours should be billed")
 }
+
+func TestCostStore_GetTotalCost(t *testing.T) {
+	db := newTestDB(t)
+	sessionStore := NewSessionStore(db)
+	costStore := NewCostStore(db)
+	ctx := context.Background()
+
+	s1 := createTestSession(t, sessionStore, "sess-total-001")
+	s2 := &models.Session{
+		ID: "sess-total-002", ConsumerID: "consumer-002", Provider: "vastai", OfferID: "offer-2",
+		GPUType: "RTX4090", GPUCount: 1, Status: models.StatusRunning, ReservationHrs: 1,
+		StoragePolicy: "destroy", PricePerHour: 0.75, CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, sessionStore.Create(ctx, s2))
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	require.NoError(t, costStore.Record(ctx, &models.CostRecord{SessionID: s1.ID, ConsumerID: "a", Provider: "vastai", GPUType: "RTX4090", Hour: hour, Amount: 1.25, Currency: "USD"}))
+	require.NoError(t, costStore.Record(ctx, &models.CostRecord{SessionID: s2.ID, ConsumerID: "b", Provider: "vastai", GPUType: "RTX4090", Hour: hour, Amount: 0.75, Currency: "USD"}))
+
+	total, err := costStore.GetTotalCost(ctx, hour.Add(-time.Hour), hour.Add(time.Hour))
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, total, 0.001)
+
+	total, err = costStore.GetTotalCost(ctx, hour.Add(time.Hour), hour.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, total)
+}
+
+func TestCostStore_LineItemCategories(t *testing.T) {
+	db := newTestDB(t)
+	sessionStore := NewSessionStore(db)
+	costStore := NewCostStore(db)
+	ctx := context.Background()
+
+	session := createTestSession(t, sessionStore, "sess-items-001")
+	hour := time.Now().UTC().Truncate(time.Hour)
+
+	record := func(category models.CostCategory, h time.Time, amount float64) {
+		require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+			SessionID: session.ID, ConsumerID: session.ConsumerID, Provider: session.Provider,
+			GPUType: session.GPUType, Category: category, Hour: h, Amount: amount, Currency: "USD",
+		}))
+	}
+
+	// GPU, storage and bandwidth share an hour without colliding
+	record(models.CostCategoryGPU, hour, 0.50)
+	record(models.CostCategoryStorage, hour, 0.02)
+	record(models.CostCategoryBandwidth, hour.Add(-time.Hour), 0.10)
+	record(models.CostCategoryBandwidth, hour, 0.05)
+
+	// Re-recording the same hour and category updates in place
+	record(models.CostCategoryStorage, hour, 0.03)
+
+	total, err := costStore.GetSessionCost(ctx, session.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.68, total, 0.0001)
+
+	prior, err := costStore.GetSessionCategoryCost(ctx, session.ID, models.CostCategoryBandwidth, hour)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.10, prior, 0.0001)
+
+	summary, err := costStore.GetSummary(ctx, models.CostQuery{SessionID: session.ID})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.50, summary.ByCategory["gpu"], 0.0001)
+	assert.InDelta(t, 0.03, summary.ByCategory["storage"], 0.0001)
+	assert.InDelta(t, 0.15, summary.ByCategory["bandwidth"], 0.0001)
+
+	// Line items do not count as GPU-hours
+	assert.Equal(t, 1.0, summary.HoursUsed)
+}
+
+func TestCostStore_RecordDefaultsToGPUCategory(t *testing.T) {
+	db := newTestDB(t)
+	sessionStore := NewSessionStore(db)
+	costStore := NewCostStore(db)
+	ctx := context.Background()
+
+	session := createTestSession(t, sessionStore, "sess-items-002")
+	record := &models.CostRecord{
+		SessionID: session.ID, ConsumerID: session.ConsumerID, Provider: session.Provider,
+		GPUType: session.GPUType, Hour: time.Now().Truncate(time.Hour), Amount: 0.50, Currency: "USD",
+	}
+	require.NoError(t, costStore.Record(ctx, record))
+	assert.Equal(t, models.CostCategoryGPU, record.Category)
+}
+
+func TestCostStore_List(t *testing.T) {
+	db := newTestDB(t)
+	sessionStore := NewSessionStore(db)
+	costStore := NewCostStore(db)
+	ctx := context.Background()
+
+	session := createTestSession(t, sessionStore, "sess-list")
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i := 2; i >= 0; i-- {
+		require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+			SessionID:  session.ID,
+			ConsumerID: session.ConsumerID,
+			Provider:   session.Provider,
+			GPUType:    session.GPUType,
+			Hour:       baseTime.Add(time.Duration(i) * time.Hour),
+			Amount:     0.50,
+			Currency:   "USD",
+		}))
+	}
+	require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+		SessionID:  session.ID,
+		ConsumerID: session.ConsumerID,
+		Provider:   session.Provider,
+		GPUType:    session.GPUType,
+		Category:   models.CostCategoryStorage,
+		Hour:       baseTime,
+		Amount:     0.02,
+		Currency:   "USD",
+	}))
+
+	records, err := costStore.List(ctx, models.CostQuery{ConsumerID: session.ConsumerID})
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.True(t, records[0].Hour.Equal(baseTime), "oldest hour first")
+	assert.Equal(t, models.CostCategoryGPU, records[0].Category)
+	assert.Equal(t, models.CostCategoryStorage, records[1].Category)
+
+	records, err = costStore.List(ctx, models.CostQuery{StartTime: baseTime.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = costStore.List(ctx, models.CostQuery{ConsumerID: "someone-else"})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestCostStore_GetBreakdown(t *testing.T) {
+	db := newTestDB(t)
+	sessionStore := NewSessionStore(db)
+	costStore := NewCostStore(db)
+	ctx := context.Background()
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	record := func(sessionID, consumerID, gpuType string, category models.CostCategory, amount float64) {
+		require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+			SessionID:  sessionID,
+			ConsumerID: consumerID,
+			Provider:   "vastai",
+			GPUType:    gpuType,
+			Category:   category,
+			Hour:       baseTime,
+			Amount:     amount,
+			Currency:   "USD",
+		}))
+	}
+	for _, id := range []string{"sess-a1", "sess-a2", "sess-b1"} {
+		require.NoError(t, sessionStore.Create(ctx, &models.Session{
+			ID:         id,
+			ConsumerID: "consumer-001",
+			Provider:   "vastai",
+			OfferID:    "offer-" + id,
+			GPUType:    "RTX4090",
+			Status:     models.StatusStopped,
+			CreatedAt:  baseTime,
+			ExpiresAt:  baseTime.Add(time.Hour),
+		}))
+	}
+	record("sess-a1", "team-a", "RTX4090", models.CostCategoryGPU, 0.50)
+	record("sess-a1", "team-a", "RTX4090", models.CostCategoryStorage, 0.02)
+	record("sess-a2", "team-a", "A100", models.CostCategoryGPU, 1.50)
+	record("sess-b1", "team-b", "RTX4090", models.CostCategoryGPU, 0.50)
+
+	groups, err := costStore.GetBreakdown(ctx, []models.CostGroupBy{models.CostGroupByConsumer}, models.CostQuery{})
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "team-a", groups[0].ConsumerID, "largest first")
+	assert.InDelta(t, 2.02, groups[0].TotalCost, 0.0001)
+	assert.Equal(t, 2, groups[0].SessionCount)
+	assert.Equal(t, 2.0, groups[0].HoursUsed)
+	assert.Empty(t, groups[0].GPUType)
+
+	groups, err = costStore.GetBreakdown(ctx, []models.CostGroupBy{models.CostGroupByConsumer, models.CostGroupByGPUType},
+		models.CostQuery{ConsumerID: "team-a"})
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "A100", groups[0].GPUType)
+	assert.Equal(t, "RTX4090", groups[1].GPUType)
+	assert.InDelta(t, 0.52, groups[1].TotalCost, 0.0001)
+
+	// Deployment has no column, so everything is one group
+	groups, err = costStore.GetBreakdown(ctx, []models.CostGroupBy{models.CostGroupByDeployment}, models.CostQuery{})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.InDelta(t, 2.52, groups[0].TotalCost, 0.0001)
+	assert.Equal(t, 3, groups[0].SessionCount)
+
+	groups, err = costStore.GetBreakdown(ctx, []models.CostGroupBy{models.CostGroupByDeployment},
+		models.CostQuery{StartTime: baseTime.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
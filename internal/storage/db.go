@@ -6,13 +6,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
 )
 
 // DB wraps the SQL database connection
 type DB struct {
 	*sql.DB
+
+	envelope *secrets.Envelope // Seals secret columns when set (see SetEncryption)
+
+	fullTextSearch bool // Session search uses the FTS5 index (see migrateSessionSearch)
 }
 
 // New creates a new database connection
@@ -43,7 +50,7 @@ func New(dbPath string) (*DB, error) {
 	db.SetMaxOpenConns(10) // Allow concurrent reads in WAL mode
 	db.SetMaxIdleConns(5)  // Keep some connections warm
 
-	return &DB{db}, nil
+	return &DB{DB: db}, nil
 }
 
 // Migrate runs database migrations
@@ -91,17 +98,216 @@ func (db *DB) Migrate(ctx context.Context) error {
 		_, _ = db.ExecContext(ctx, migration) // Ignore errors for idempotency
 	}
 
+	// Run cost line item column migration (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddCostCategory)
+
+	// Run interruptible (bid) session column migrations (idempotent)
+	bidMigrations := []string{
+		migrationAddInterruptible,
+		migrationAddBidPrice,
+	}
+	for _, migration := range bidMigrations {
+		_, _ = db.ExecContext(ctx, migration) // Ignore errors for idempotency
+	}
+
+	// Run idle policy and session health column migrations (idempotent)
+	healthMigrations := []string{
+		migrationAddIdleGPUUtilPct,
+		migrationAddHealthStatus,
+		migrationAddLastHeartbeatAt,
+		migrationAddGPUUtilPct,
+		migrationAddIdleSince,
+	}
+	for _, migration := range healthMigrations {
+		_, _ = db.ExecContext(ctx, migration) // Ignore errors for idempotency
+	}
+
+	// Run session group column migration (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddGroupID)
+	if _, err := db.ExecContext(ctx, migrationGroupIDIndex); err != nil {
+		return fmt.Errorf("session group index migration failed: %w", err)
+	}
+
+	// Run failure taxonomy column migrations (idempotent)
+	failureTaxonomyMigrations := []string{
+		migrationAddLocation,
+		migrationAddFailureCategory,
+		migrationAddFailureDetail,
+	}
+	for _, migration := range failureTaxonomyMigrations {
+		_, _ = db.ExecContext(ctx, migration) // Ignore errors for idempotency
+	}
+
+	// Run SSH host key pinning column migration (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddSSHHostKeyFingerprint)
+
+	// Run advertised CUDA/driver version column migrations (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddCUDAVersion)
+	_, _ = db.ExecContext(ctx, migrationAddDriverVersion)
+	_, _ = db.ExecContext(ctx, migrationAddSessionVRAM)
+
+	// Run measured throughput column migrations (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddMeasuredInetDown)
+	_, _ = db.ExecContext(ctx, migrationAddMeasuredDiskBandwidth)
+
+	// Run session machine ID column migration (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddSessionMachineID)
+
+	// Run provisioning state column migrations (idempotent)
+	phaseMigrations := []string{
+		migrationAddProvisionPhase,
+		migrationAddVerifyDeadline,
+		migrationAddLaunchMode,
+		migrationAddAPIPort,
+		migrationAddAPIEndpoint,
+		migrationAddInstanceCreatedAt,
+		migrationAddIPAssignedAt,
+		migrationAddCloudInitDoneAt,
+		migrationAddSSHVerifiedAt,
+		migrationAddBootstrapScript,
+		migrationAddImagePulledAt,
+		migrationAddContainerStartedAt,
+		migrationAddWeightsLoadedAt,
+		migrationAddGPUBurnIn,
+		migrationAddModelID,
+		migrationAddHFToken,
+		migrationAddOnStartCmd,
+		migrationAddCloudInit,
+	}
+	for _, migration := range phaseMigrations {
+		_, _ = db.ExecContext(ctx, migration) // Ignore errors for idempotency
+	}
+
+	// Run session pause column migrations (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddPausedAt)
+	_, _ = db.ExecContext(ctx, migrationAddPausedSeconds)
+
+	// Run session reboot column migration (idempotent)
+	_, _ = db.ExecContext(ctx, migrationAddRebootingUntil)
+
 	// Run offer failure tracking migrations
 	failureMigrations := []string{
 		migrationOfferFailures,
 		migrationOfferFailuresIndex,
 		migrationOfferSuppressions,
+		migrationOfferRules,
+		migrationLocationStats,
 	}
 	for _, migration := range failureMigrations {
 		if _, err := db.ExecContext(ctx, migration); err != nil {
 			return fmt.Errorf("offer failure migration failed: %w", err)
 		}
 	}
+	_, _ = db.ExecContext(ctx, migrationAddFailureMachineID) // Ignore errors for idempotency
+
+	// Run budget migrations
+	if _, err := db.ExecContext(ctx, migrationBudgets); err != nil {
+		return fmt.Errorf("budget migration failed: %w", err)
+	}
+
+	// Run admin audit trail migrations
+	auditMigrations := []string{
+		migrationAdminAuditLog,
+		migrationAdminAuditLogIndex,
+	}
+	for _, migration := range auditMigrations {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("audit log migration failed: %w", err)
+		}
+	}
+
+	// Run webhook notification migrations
+	webhookMigrations := []string{
+		migrationWebhookSubscriptions,
+		migrationWebhookDeliveries,
+		migrationWebhookDeliveriesIndex,
+	}
+	for _, migration := range webhookMigrations {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("webhook migration failed: %w", err)
+		}
+	}
+
+	// Run session status history migrations
+	sessionEventMigrations := []string{
+		migrationSessionEvents,
+		migrationSessionEventsIndex,
+		migrationSessionEventsInsertTrigger,
+		migrationSessionEventsUpdateTrigger,
+	}
+	for _, migration := range sessionEventMigrations {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("session event migration failed: %w", err)
+		}
+	}
+	for _, migration := range []string{migrationAddSessionEventType, migrationAddSessionEventOutput} {
+		_, _ = db.ExecContext(ctx, migration) // Ignore errors for idempotency
+	}
+
+	// Run price history migrations
+	priceHistoryMigrations := []string{
+		migrationPriceHistory,
+		migrationPriceHistoryIndex,
+	}
+	for _, migration := range priceHistoryMigrations {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("price history migration failed: %w", err)
+		}
+	}
+
+	// Run price watch migrations
+	priceWatchMigrations := []string{
+		migrationPriceWatches,
+		migrationPriceWatchesIndex,
+	}
+	for _, migration := range priceWatchMigrations {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("price watch migration failed: %w", err)
+		}
+	}
+
+	// Run session reservation queue migrations
+	reservationMigrations := []string{
+		migrationSessionReservations,
+		migrationSessionReservationsIndex,
+	}
+	for _, migration := range reservationMigrations {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("session reservation migration failed: %w", err)
+		}
+	}
+	_, _ = db.ExecContext(ctx, migrationAddReservationHFToken) // Ignore errors for idempotency
+
+	// Run feature flag migration
+	if _, err := db.ExecContext(ctx, migrationFeatureFlags); err != nil {
+		return fmt.Errorf("feature flag migration failed: %w", err)
+	}
+
+	// Run provider usage migration
+	if _, err := db.ExecContext(ctx, migrationProviderUsage); err != nil {
+		return fmt.Errorf("provider usage migration failed: %w", err)
+	}
+
+	// Run instance release migration
+	if _, err := db.ExecContext(ctx, migrationInstanceReleases); err != nil {
+		return fmt.Errorf("instance release migration failed: %w", err)
+	}
+
+	// Run quarantined instance migration
+	if _, err := db.ExecContext(ctx, migrationQuarantinedInstances); err != nil {
+		return fmt.Errorf("quarantined instance migration failed: %w", err)
+	}
+	_, _ = db.ExecContext(ctx, migrationAddQuarantineNextAttempt) // Ignore errors for idempotency
+
+	// Run leader election lease migration
+	if _, err := db.ExecContext(ctx, migrationLeases); err != nil {
+		return fmt.Errorf("lease migration failed: %w", err)
+	}
+
+	// Run session search index migration
+	if err := db.migrateSessionSearch(ctx); err != nil {
+		return fmt.Errorf("session search migration failed: %w", err)
+	}
 
 	// Run index migrations that may fail if already exists
 	indexMigrations := []string{
@@ -116,6 +322,41 @@ func (db *DB) Migrate(ctx context.Context) error {
 	return nil
 }
 
+// migrateSessionSearch creates the full-text index used by session search.
+// SQLite only includes FTS5 when built with the sqlite_fts5 tag; without it
+// the index is skipped and search falls back to LIKE scans.
+func (db *DB) migrateSessionSearch(ctx context.Context) error {
+	var existing int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE name = 'sessions_fts'`).Scan(&existing); err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, migrationSessionsFTS); err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			db.fullTextSearch = false
+			return nil
+		}
+		return err
+	}
+	for _, migration := range []string{
+		migrationSessionsFTSInsertTrigger,
+		migrationSessionsFTSUpdateTrigger,
+		migrationSessionsFTSDeleteTrigger,
+	} {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
+			return err
+		}
+	}
+	// Index sessions created before the index existed
+	if existing == 0 {
+		if _, err := db.ExecContext(ctx, migrationSessionsFTSBackfill); err != nil {
+			return err
+		}
+	}
+	db.fullTextSearch = true
+	return nil
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	return db.DB.Close()
@@ -228,11 +469,13 @@ ALTER TABLE sessions DROP COLUMN last_idle_seconds;
 `
 
 // migrationCostDeduplication adds a unique index to prevent duplicate cost records
-// for the same session and hour. This prevents duplicate billing when aggregation
-// runs more frequently than once per hour.
+// for the same session, hour and line item. This prevents duplicate billing when
+// aggregation runs more frequently than once per hour. It replaces the original
+// (session_id, hour) index, which predates per-category line items.
 const migrationCostDeduplication = `
-CREATE UNIQUE INDEX IF NOT EXISTS idx_costs_session_hour_unique
-ON costs(session_id, hour);
+DROP INDEX IF EXISTS idx_costs_session_hour_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_costs_session_hour_category_unique
+ON costs(session_id, hour, category);
 `
 
 // Auto-retry column migrations
@@ -263,6 +506,35 @@ CREATE TABLE IF NOT EXISTS offer_suppressions (
 );
 `
 
+// Provisioning attempts per provider location (TensorDock availability confidence)
+const migrationLocationStats = `
+CREATE TABLE IF NOT EXISTS location_stats (
+	provider TEXT NOT NULL,
+	location_id TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	successes INTEGER NOT NULL DEFAULT 0,
+	last_attempt_at DATETIME NOT NULL,
+	last_succeeded INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (provider, location_id)
+);
+`
+
+// Operator-managed offer suppression and allowlist rules; one rule per target
+const migrationOfferRules = `
+CREATE TABLE IF NOT EXISTS offer_rules (
+	id TEXT PRIMARY KEY,
+	action TEXT NOT NULL,
+	scope TEXT NOT NULL,
+	value TEXT NOT NULL,
+	provider TEXT NOT NULL DEFAULT '',
+	reason TEXT NOT NULL DEFAULT '',
+	created_by TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL,
+	expires_at DATETIME,
+	UNIQUE(scope, value, provider)
+);
+`
+
 const migrationAddAutoRetry = `ALTER TABLE sessions ADD COLUMN auto_retry INTEGER DEFAULT 0;`
 const migrationAddMaxRetries = `ALTER TABLE sessions ADD COLUMN max_retries INTEGER DEFAULT 0;`
 const migrationAddRetryScope = `ALTER TABLE sessions ADD COLUMN retry_scope TEXT DEFAULT '';`
@@ -270,3 +542,370 @@ const migrationAddRetryCount = `ALTER TABLE sessions ADD COLUMN retry_count INTE
 const migrationAddRetryParentID = `ALTER TABLE sessions ADD COLUMN retry_parent_id TEXT DEFAULT '';`
 const migrationAddRetryChildID = `ALTER TABLE sessions ADD COLUMN retry_child_id TEXT DEFAULT '';`
 const migrationAddFailedOffers = `ALTER TABLE sessions ADD COLUMN failed_offers TEXT DEFAULT '';`
+
+// Interruptible (bid) sessions can be preempted by the provider
+const migrationAddInterruptible = `ALTER TABLE sessions ADD COLUMN interruptible INTEGER DEFAULT 0;`
+const migrationAddBidPrice = `ALTER TABLE sessions ADD COLUMN bid_price REAL DEFAULT 0;`
+
+// Idle policy utilization threshold (idle_threshold_minutes predates it)
+const migrationAddIdleGPUUtilPct = `ALTER TABLE sessions ADD COLUMN idle_gpu_util_pct INTEGER DEFAULT 0;`
+
+// Latest heartbeat of running sessions, recorded by the lifecycle manager
+const migrationAddHealthStatus = `ALTER TABLE sessions ADD COLUMN health_status TEXT DEFAULT '';`
+const migrationAddLastHeartbeatAt = `ALTER TABLE sessions ADD COLUMN last_heartbeat_at DATETIME;`
+const migrationAddGPUUtilPct = `ALTER TABLE sessions ADD COLUMN gpu_util_pct REAL;`
+const migrationAddIdleSince = `ALTER TABLE sessions ADD COLUMN idle_since DATETIME;`
+
+// Sessions provisioned together share a group ID
+const migrationAddGroupID = `ALTER TABLE sessions ADD COLUMN group_id TEXT DEFAULT '';`
+
+// Failure taxonomy migrations: where a session ran and why it failed
+const migrationAddLocation = `ALTER TABLE sessions ADD COLUMN location TEXT DEFAULT '';`
+const migrationAddFailureCategory = `ALTER TABLE sessions ADD COLUMN failure_category TEXT DEFAULT '';`
+const migrationAddFailureDetail = `ALTER TABLE sessions ADD COLUMN failure_detail TEXT DEFAULT '';`
+
+// Host key fingerprint recorded on first SSH connection (trust on first use)
+const migrationAddSSHHostKeyFingerprint = `ALTER TABLE sessions ADD COLUMN ssh_host_key_fingerprint TEXT DEFAULT '';`
+
+// Advertised CUDA and driver versions of the session's offer
+const migrationAddCUDAVersion = `ALTER TABLE sessions ADD COLUMN cuda_version REAL DEFAULT 0;`
+
+const migrationAddDriverVersion = `ALTER TABLE sessions ADD COLUMN driver_version TEXT DEFAULT '';`
+
+const migrationAddSessionVRAM = `ALTER TABLE sessions ADD COLUMN vram_gb INTEGER DEFAULT 0;`
+
+// Network and disk throughput measured on the instance after provisioning
+const migrationAddMeasuredInetDown = `ALTER TABLE sessions ADD COLUMN measured_inet_down_mbps REAL DEFAULT 0;`
+
+const migrationAddMeasuredDiskBandwidth = `ALTER TABLE sessions ADD COLUMN measured_disk_bw_mbps REAL DEFAULT 0;`
+
+// Host-level failure tracking column migrations
+const migrationAddSessionMachineID = `ALTER TABLE sessions ADD COLUMN machine_id TEXT DEFAULT '';`
+const migrationAddFailureMachineID = `ALTER TABLE offer_failures ADD COLUMN machine_id TEXT NOT NULL DEFAULT '';`
+
+// Provisioning phase column migrations, so verification can resume after a restart
+const migrationAddProvisionPhase = `ALTER TABLE sessions ADD COLUMN provision_phase TEXT DEFAULT '';`
+const migrationAddVerifyDeadline = `ALTER TABLE sessions ADD COLUMN verify_deadline DATETIME;`
+const migrationAddLaunchMode = `ALTER TABLE sessions ADD COLUMN launch_mode TEXT DEFAULT '';`
+
+// Workload API columns, so entrypoint-mode verification can resume after a restart
+const migrationAddAPIPort = `ALTER TABLE sessions ADD COLUMN api_port INTEGER DEFAULT 0;`
+const migrationAddAPIEndpoint = `ALTER TABLE sessions ADD COLUMN api_endpoint TEXT DEFAULT '';`
+
+// Provisioning milestone timestamps, reported as session progress
+const migrationAddInstanceCreatedAt = `ALTER TABLE sessions ADD COLUMN instance_created_at DATETIME;`
+const migrationAddIPAssignedAt = `ALTER TABLE sessions ADD COLUMN ip_assigned_at DATETIME;`
+const migrationAddCloudInitDoneAt = `ALTER TABLE sessions ADD COLUMN cloud_init_done_at DATETIME;`
+const migrationAddSSHVerifiedAt = `ALTER TABLE sessions ADD COLUMN ssh_verified_at DATETIME;`
+
+const migrationAddBootstrapScript = `ALTER TABLE sessions ADD COLUMN bootstrap_script TEXT;`
+
+const migrationAddImagePulledAt = `ALTER TABLE sessions ADD COLUMN image_pulled_at DATETIME;`
+
+const migrationAddContainerStartedAt = `ALTER TABLE sessions ADD COLUMN container_started_at DATETIME;`
+
+const migrationAddWeightsLoadedAt = `ALTER TABLE sessions ADD COLUMN weights_loaded_at DATETIME;`
+
+const migrationAddGPUBurnIn = `ALTER TABLE sessions ADD COLUMN gpu_burn_in BOOLEAN DEFAULT 0;`
+
+const migrationAddModelID = `ALTER TABLE sessions ADD COLUMN model_id TEXT DEFAULT '';`
+
+const migrationAddHFToken = `ALTER TABLE sessions ADD COLUMN hf_token TEXT DEFAULT '';`
+
+// Custom start-up commands and cloud-init (as JSON), for failover replacements
+const migrationAddOnStartCmd = `ALTER TABLE sessions ADD COLUMN on_start_cmd TEXT DEFAULT '';`
+const migrationAddCloudInit = `ALTER TABLE sessions ADD COLUMN cloud_init TEXT DEFAULT '';`
+
+// When a paused session's current pause began, and how long its earlier
+// pauses lasted, so they do not count towards the hard max
+const migrationAddPausedAt = `ALTER TABLE sessions ADD COLUMN paused_at DATETIME;`
+const migrationAddPausedSeconds = `ALTER TABLE sessions ADD COLUMN paused_seconds INTEGER DEFAULT 0;`
+
+// When an in-place reboot of a session's instance under way gives up
+const migrationAddRebootingUntil = `ALTER TABLE sessions ADD COLUMN rebooting_until DATETIME;`
+
+const migrationGroupIDIndex = `CREATE INDEX IF NOT EXISTS idx_sessions_group_id ON sessions(group_id);`
+
+// Cost line items (gpu, storage, bandwidth, ip); existing rows are GPU-hours
+const migrationAddCostCategory = `ALTER TABLE costs ADD COLUMN category TEXT NOT NULL DEFAULT 'gpu';`
+
+// Budget enforcement (per-consumer and deployment-wide spend caps)
+const migrationBudgets = `
+CREATE TABLE IF NOT EXISTS budgets (
+	id TEXT PRIMARY KEY,
+	scope TEXT NOT NULL,
+	scope_id TEXT NOT NULL,
+	period TEXT NOT NULL,
+	limit_usd REAL NOT NULL,
+	warning_sent_at DATETIME,
+	exceeded_sent_at DATETIME,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	UNIQUE(scope, scope_id, period)
+);
+`
+
+// Admin audit trail (actions taken on behalf of consumers)
+const migrationAdminAuditLog = `
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+	id TEXT PRIMARY KEY,
+	actor TEXT NOT NULL,
+	action TEXT NOT NULL,
+	consumer_id TEXT NOT NULL,
+	session_id TEXT NOT NULL DEFAULT '',
+	reason TEXT NOT NULL DEFAULT '',
+	details TEXT NOT NULL DEFAULT '',
+	request_id TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL
+);
+`
+
+const migrationAdminAuditLogIndex = `CREATE INDEX IF NOT EXISTS idx_admin_audit_log_consumer ON admin_audit_log(consumer_id, created_at);`
+
+// Session status history. Populated by triggers so that every status change
+// is recorded atomically with the update that made it. The reason is the
+// session error when the transition set or changed it, or when it failed.
+const migrationSessionEvents = `
+CREATE TABLE IF NOT EXISTS session_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	from_status TEXT NOT NULL DEFAULT '',
+	to_status TEXT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL
+);
+`
+
+const migrationSessionEventsIndex = `CREATE INDEX IF NOT EXISTS idx_session_events_session ON session_events(session_id, id);`
+
+const migrationSessionEventsInsertTrigger = `
+CREATE TRIGGER IF NOT EXISTS trg_session_events_insert
+AFTER INSERT ON sessions
+BEGIN
+	INSERT INTO session_events (session_id, from_status, to_status, reason, created_at)
+	VALUES (NEW.id, '', NEW.status, IFNULL(NEW.error, ''), strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+`
+
+const migrationSessionEventsUpdateTrigger = `
+CREATE TRIGGER IF NOT EXISTS trg_session_events_update
+AFTER UPDATE OF status ON sessions
+WHEN OLD.status != NEW.status
+BEGIN
+	INSERT INTO session_events (session_id, from_status, to_status, reason, created_at)
+	VALUES (
+		NEW.id, OLD.status, NEW.status,
+		CASE
+			WHEN NEW.status = 'failed' OR IFNULL(NEW.error, '') != IFNULL(OLD.error, '') THEN IFNULL(NEW.error, '')
+			ELSE ''
+		END,
+		strftime('%Y-%m-%d %H:%M:%f', 'now')
+	);
+END;
+`
+
+// Events other than status transitions, such as bootstrap script results,
+// are written by SessionEventStore.Record with their own type and output
+const migrationAddSessionEventType = `ALTER TABLE session_events ADD COLUMN event_type TEXT NOT NULL DEFAULT 'status';`
+
+const migrationAddSessionEventOutput = `ALTER TABLE session_events ADD COLUMN output TEXT NOT NULL DEFAULT '';`
+
+// Offer price history, aggregated per provider and GPU type. Raw snapshots
+// are downsampled to hourly rows by PriceHistoryStore.CompactPriceHistory.
+const migrationPriceHistory = `
+CREATE TABLE IF NOT EXISTS price_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	provider TEXT NOT NULL,
+	gpu_type TEXT NOT NULL,
+	min_price REAL NOT NULL,
+	avg_price REAL NOT NULL,
+	max_price REAL NOT NULL,
+	offer_count INTEGER NOT NULL,
+	sampled_at DATETIME NOT NULL,
+	resolution TEXT NOT NULL DEFAULT 'raw'
+);
+`
+
+const migrationPriceHistoryIndex = `CREATE INDEX IF NOT EXISTS idx_price_history_gpu ON price_history(gpu_type COLLATE NOCASE, sampled_at);`
+
+// Consumer price watches on inventory
+const migrationPriceWatches = `
+CREATE TABLE IF NOT EXISTS price_watches (
+	id TEXT PRIMARY KEY,
+	consumer_id TEXT NOT NULL,
+	gpu_type TEXT NOT NULL,
+	max_price REAL NOT NULL,
+	region TEXT NOT NULL DEFAULT '',
+	provider TEXT NOT NULL DEFAULT '',
+	matching INTEGER NOT NULL DEFAULT 0,
+	notified_at DATETIME,
+	created_at DATETIME NOT NULL
+);
+`
+
+const migrationPriceWatchesIndex = `CREATE INDEX IF NOT EXISTS idx_price_watches_consumer ON price_watches(consumer_id);`
+
+// Session requests queued until a matching offer appears
+const migrationSessionReservations = `
+CREATE TABLE IF NOT EXISTS session_reservations (
+	id TEXT PRIMARY KEY,
+	consumer_id TEXT NOT NULL,
+	filter TEXT NOT NULL,
+	request TEXT NOT NULL,
+	group_id TEXT NOT NULL DEFAULT '',
+	template_disk_gb INTEGER NOT NULL DEFAULT 0,
+	template_ssh_timeout_seconds INTEGER NOT NULL DEFAULT 0,
+	status TEXT NOT NULL,
+	session_id TEXT NOT NULL DEFAULT '',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL,
+	fulfilled_at DATETIME
+);
+`
+
+const migrationAddReservationHFToken = `ALTER TABLE session_reservations ADD COLUMN hf_token TEXT NOT NULL DEFAULT '';`
+
+const migrationSessionReservationsIndex = `CREATE INDEX IF NOT EXISTS idx_session_reservations_status ON session_reservations(status, created_at);`
+
+// Runtime feature flags gating provider behaviors (canary rollout)
+const migrationFeatureFlags = `
+CREATE TABLE IF NOT EXISTS feature_flags (
+	name TEXT PRIMARY KEY,
+	enabled INTEGER NOT NULL DEFAULT 0,
+	rollout_percent INTEGER NOT NULL DEFAULT 100,
+	description TEXT NOT NULL DEFAULT '',
+	updated_by TEXT NOT NULL DEFAULT '',
+	updated_at DATETIME NOT NULL
+);
+`
+
+// Latest provider-reported billing per session (cost reconciliation)
+const migrationProviderUsage = `
+CREATE TABLE IF NOT EXISTS provider_usage (
+	session_id TEXT PRIMARY KEY,
+	provider TEXT NOT NULL,
+	hourly_rate REAL NOT NULL DEFAULT 0,
+	total REAL NOT NULL DEFAULT 0,
+	observed_at DATETIME NOT NULL
+);
+`
+
+// Provider instances this deployment disowned through the claim API
+const migrationInstanceReleases = `
+CREATE TABLE IF NOT EXISTS instance_releases (
+	provider TEXT NOT NULL,
+	instance_id TEXT NOT NULL,
+	deployment_id TEXT NOT NULL DEFAULT '',
+	released_by TEXT NOT NULL DEFAULT '',
+	released_at DATETIME NOT NULL,
+	PRIMARY KEY (provider, instance_id)
+);
+`
+
+// Provider instances that could not be destroyed, kept until an operator
+// resolves them
+const migrationQuarantinedInstances = `
+CREATE TABLE IF NOT EXISTS quarantined_instances (
+	provider TEXT NOT NULL,
+	instance_id TEXT NOT NULL,
+	session_id TEXT NOT NULL DEFAULT '',
+	source TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	failures INTEGER NOT NULL DEFAULT 1,
+	first_seen_at DATETIME NOT NULL,
+	last_seen_at DATETIME NOT NULL,
+	PRIMARY KEY (provider, instance_id)
+);
+`
+
+const migrationAddQuarantineNextAttempt = `ALTER TABLE quarantined_instances ADD COLUMN next_attempt_at DATETIME;`
+
+// Full-text index over session text operators search by, kept in sync by
+// triggers. It holds its own copy of the text rather than pointing at the
+// sessions table, whose implicit rowids can change on VACUUM.
+const migrationSessionsFTS = `
+CREATE VIRTUAL TABLE IF NOT EXISTS sessions_fts USING fts5(
+	session_id UNINDEXED,
+	consumer_id,
+	offer_id,
+	provider_instance_id,
+	error,
+	failure_detail
+);
+`
+
+const migrationSessionsFTSInsertTrigger = `
+CREATE TRIGGER IF NOT EXISTS trg_sessions_fts_insert
+AFTER INSERT ON sessions
+BEGIN
+	INSERT INTO sessions_fts (session_id, consumer_id, offer_id, provider_instance_id, error, failure_detail)
+	VALUES (NEW.id, NEW.consumer_id, NEW.offer_id, IFNULL(NEW.provider_instance_id, ''), IFNULL(NEW.error, ''), IFNULL(NEW.failure_detail, ''));
+END;
+`
+
+const migrationSessionsFTSUpdateTrigger = `
+CREATE TRIGGER IF NOT EXISTS trg_sessions_fts_update
+AFTER UPDATE OF consumer_id, offer_id, provider_instance_id, error, failure_detail ON sessions
+BEGIN
+	DELETE FROM sessions_fts WHERE session_id = OLD.id;
+	INSERT INTO sessions_fts (session_id, consumer_id, offer_id, provider_instance_id, error, failure_detail)
+	VALUES (NEW.id, NEW.consumer_id, NEW.offer_id, IFNULL(NEW.provider_instance_id, ''), IFNULL(NEW.error, ''), IFNULL(NEW.failure_detail, ''));
+END;
+`
+
+const migrationSessionsFTSDeleteTrigger = `
+CREATE TRIGGER IF NOT EXISTS trg_sessions_fts_delete
+AFTER DELETE ON sessions
+BEGIN
+	DELETE FROM sessions_fts WHERE session_id = OLD.id;
+END;
+`
+
+const migrationSessionsFTSBackfill = `
+INSERT INTO sessions_fts (session_id, consumer_id, offer_id, provider_instance_id, error, failure_detail)
+SELECT id, consumer_id, offer_id, IFNULL(provider_instance_id, ''), IFNULL(error, ''), IFNULL(failure_detail, '')
+FROM sessions;
+`
+
+// Named leases for leader election between replicas
+const migrationLeases = `
+CREATE TABLE IF NOT EXISTS leases (
+	name TEXT PRIMARY KEY,
+	holder TEXT NOT NULL,
+	acquired_at DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL
+);
+`
+
+// Webhook notifications (consumer subscriptions and delivery tracking)
+const migrationWebhookSubscriptions = `
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+	id TEXT PRIMARY KEY,
+	consumer_id TEXT NOT NULL,
+	url TEXT NOT NULL,
+	secret TEXT NOT NULL,
+	events TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL
+);
+`
+
+const migrationWebhookDeliveries = `
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id TEXT PRIMARY KEY,
+	subscription_id TEXT NOT NULL,
+	event_id TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	status TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_status_code INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	next_attempt_at DATETIME NOT NULL,
+	delivered_at DATETIME,
+	created_at DATETIME NOT NULL
+);
+`
+
+const migrationWebhookDeliveriesIndex = `CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);`
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
+)
+
+// encryptedColumns hold secrets and are sealed when encryption is enabled:
+// webhook signing secrets, delivery payloads, which carry session details,
+// HuggingFace tokens, and sessions' custom start-up commands and cloud-init
+var encryptedColumns = []struct {
+	table  string
+	column string
+}{
+	{"webhook_subscriptions", "secret"},
+	{"webhook_deliveries", "payload"},
+	{"sessions", "hf_token"},
+	{"sessions", "on_start_cmd"},
+	{"sessions", "cloud_init"},
+	{"session_reservations", "hf_token"},
+}
+
+// SetEncryption enables envelope encryption of secret columns. New values
+// are sealed on write; reads decrypt transparently and still accept values
+// written in plaintext.
+func (db *DB) SetEncryption(envelope *secrets.Envelope) {
+	db.envelope = envelope
+}
+
+// seal encrypts a secret column value if encryption is enabled
+func (db *DB) seal(value string) (string, error) {
+	if db.envelope == nil || value == "" {
+		return value, nil
+	}
+	sealed, err := db.envelope.Seal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value: %w", err)
+	}
+	return sealed, nil
+}
+
+// open decrypts a secret column value
+func (db *DB) open(value string) (string, error) {
+	if !secrets.IsSealed(value) {
+		return value, nil
+	}
+	if db.envelope == nil {
+		return "", ErrEncryptionKeyRequired
+	}
+	plaintext, err := db.envelope.Open(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// EncryptExisting seals secret column values that were written in
+// plaintext, returning how many were sealed. Encryption must be enabled.
+func (db *DB) EncryptExisting(ctx context.Context) (int, error) {
+	if db.envelope == nil {
+		return 0, fmt.Errorf("database encryption is not enabled")
+	}
+
+	sealed := 0
+	for _, c := range encryptedColumns {
+		n, err := db.encryptColumn(ctx, c.table, c.column)
+		sealed += n
+		if err != nil {
+			return sealed, fmt.Errorf("failed to encrypt %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	return sealed, nil
+}
+
+func (db *DB) encryptColumn(ctx context.Context, table, column string) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT id, %s FROM %s", column, table))
+	if err != nil {
+		return 0, err
+	}
+	plaintext := make(map[string]string)
+	for rows.Next() {
+		var id, value string
+		if err := rows.Scan(&id, &value); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if value != "" && !secrets.IsSealed(value) {
+			plaintext[id] = value
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", table, column)
+	for id, value := range plaintext {
+		sealed, err := db.seal(value)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, update, sealed, id); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(plaintext), nil
+}
@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/secrets"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEnvelope(t *testing.T, fill byte) *secrets.Envelope {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = fill
+	}
+	envelope, err := secrets.NewEnvelope(key)
+	require.NoError(t, err)
+	return envelope
+}
+
+// rawColumn reads a column without decryption
+func rawColumn(t *testing.T, db *DB, table, column, id string) string {
+	t.Helper()
+	var value string
+	require.NoError(t, db.QueryRow("SELECT "+column+" FROM "+table+" WHERE id = ?", id).Scan(&value))
+	return value
+}
+
+func TestEncryption_WebhookSecrets(t *testing.T) {
+	db := newTestDB(t)
+	db.SetEncryption(newTestEnvelope(t, 1))
+	store := NewWebhookStore(db)
+	ctx := context.Background()
+
+	sub := &models.WebhookSubscription{ConsumerID: "consumer-001", URL: "https://example.com/hook", Secret: "s3cret"}
+	require.NoError(t, store.CreateSubscription(ctx, sub))
+	payload := `{"type":"session.running","data":{"ssh_host":"10.0.0.2","ssh_user":"root"}}`
+	d := &models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        "evt-1",
+		EventType:      models.WebhookEventSessionRunning,
+		Payload:        payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now().UTC(),
+	}
+	require.NoError(t, store.CreateDelivery(ctx, d))
+
+	// Sealed at rest
+	rawSecret := rawColumn(t, db, "webhook_subscriptions", "secret", sub.ID)
+	assert.True(t, secrets.IsSealed(rawSecret))
+	rawPayload := rawColumn(t, db, "webhook_deliveries", "payload", d.ID)
+	assert.True(t, secrets.IsSealed(rawPayload))
+	assert.NotContains(t, rawPayload, "10.0.0.2")
+
+	// Decrypted on read
+	got, err := store.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", got.Secret)
+	deliveries, err := store.ListDeliveries(ctx, sub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, payload, deliveries[0].Payload)
+
+	// Without the key, or with another one, sealed values cannot be read
+	db.SetEncryption(nil)
+	_, err = store.GetSubscription(ctx, sub.ID)
+	assert.ErrorIs(t, err, ErrEncryptionKeyRequired)
+
+	db.SetEncryption(newTestEnvelope(t, 2))
+	_, err = store.ListDeliveries(ctx, sub.ID, 0)
+	var wrongKey *secrets.WrongKeyError
+	assert.ErrorAs(t, err, &wrongKey)
+}
+
+func TestEncryption_SessionSecrets(t *testing.T) {
+	db := newTestDB(t)
+	db.SetEncryption(newTestEnvelope(t, 1))
+	ctx := context.Background()
+
+	sessions := NewSessionStore(db)
+	session := &models.Session{
+		ID:             "sess-001",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "offer-1",
+		Status:         models.StatusPending,
+		WorkloadType:   models.WorkloadLLMVLLM,
+		ReservationHrs: 1,
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(time.Hour),
+		HFToken:        "hf_session",
+		OnStartCmd:     "export API_KEY=k3y",
+		CloudInit:      &models.CloudInitConfig{RunCmd: []string{"echo k3y > /etc/key"}},
+	}
+	require.NoError(t, sessions.Create(ctx, session))
+
+	reservations := NewReservationStore(db)
+	r := &models.QueuedReservation{
+		ConsumerID: "consumer-001",
+		Request:    models.CreateSessionRequest{ConsumerID: "consumer-001", HFToken: "hf_queued"},
+		Status:     models.ReservationWaiting,
+		CreatedAt:  time.Now().UTC(),
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	}
+	require.NoError(t, reservations.CreateReservation(ctx, r))
+
+	// Sealed at rest, and kept out of the stored request
+	assert.True(t, secrets.IsSealed(rawColumn(t, db, "sessions", "hf_token", session.ID)))
+	assert.True(t, secrets.IsSealed(rawColumn(t, db, "sessions", "on_start_cmd", session.ID)))
+	assert.True(t, secrets.IsSealed(rawColumn(t, db, "sessions", "cloud_init", session.ID)))
+	assert.True(t, secrets.IsSealed(rawColumn(t, db, "session_reservations", "hf_token", r.ID)))
+	assert.NotContains(t, rawColumn(t, db, "session_reservations", "request", r.ID), "hf_queued")
+
+	// Decrypted on read
+	got, err := sessions.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hf_session", got.HFToken)
+	assert.Equal(t, "export API_KEY=k3y", got.OnStartCmd)
+	assert.Equal(t, session.CloudInit, got.CloudInit)
+	queued, err := reservations.GetReservation(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hf_queued", queued.Request.HFToken)
+}
+
+func TestEncryption_EncryptExisting(t *testing.T) {
+	db := newTestDB(t)
+	store := NewWebhookStore(db)
+	ctx := context.Background()
+
+	// Rows written before encryption was enabled
+	sub := &models.WebhookSubscription{ConsumerID: "consumer-001", URL: "https://example.com/hook", Secret: "s3cret"}
+	require.NoError(t, store.CreateSubscription(ctx, sub))
+	d := &models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        "evt-1",
+		EventType:      models.WebhookEventSessionRunning,
+		Payload:        `{"type":"session.running"}`,
+		Status:         models.WebhookDeliveryDelivered,
+		NextAttemptAt:  time.Now().UTC(),
+	}
+	require.NoError(t, store.CreateDelivery(ctx, d))
+
+	_, err := db.EncryptExisting(ctx)
+	assert.Error(t, err, "encryption must be enabled first")
+
+	db.SetEncryption(newTestEnvelope(t, 1))
+
+	// Plaintext rows stay readable before migration
+	got, err := store.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", got.Secret)
+
+	n, err := db.EncryptExisting(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, secrets.IsSealed(rawColumn(t, db, "webhook_subscriptions", "secret", sub.ID)))
+	assert.True(t, secrets.IsSealed(rawColumn(t, db, "webhook_deliveries", "payload", d.ID)))
+
+	got, err = store.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", got.Secret)
+
+	// Already sealed rows are left alone
+	n, err = db.EncryptExisting(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
@@ -6,4 +6,8 @@ import "errors"
 var (
 	ErrNotFound      = errors.New("record not found")
 	ErrAlreadyExists = errors.New("record already exists")
+
+	// ErrEncryptionKeyRequired is returned when reading an encrypted value
+	// from a database opened without an encryption key
+	ErrEncryptionKeyRequired = errors.New("value is encrypted but no database encryption key is configured")
 )
@@ -2,8 +2,11 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
 // OfferFailureRecord represents a persisted offer failure event
@@ -12,6 +15,7 @@ type OfferFailureRecord struct {
 	OfferID     string
 	Provider    string
 	GPUType     string
+	MachineID   string // Empty if the offer's host is unknown
 	FailureType string
 	Reason      string
 	CreatedAt   time.Time
@@ -36,12 +40,12 @@ func NewOfferFailureStore(db *DB) *OfferFailureStore {
 }
 
 // RecordFailure persists a failure event
-func (s *OfferFailureStore) RecordFailure(ctx context.Context, offerID, provider, gpuType, failureType, reason string) error {
+func (s *OfferFailureStore) RecordFailure(ctx context.Context, offerID, provider, gpuType, machineID, failureType, reason string) error {
 	query := `
-		INSERT INTO offer_failures (offer_id, provider, gpu_type, failure_type, reason, created_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO offer_failures (offer_id, provider, gpu_type, machine_id, failure_type, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := s.db.ExecContext(ctx, query, offerID, provider, gpuType, failureType, reason, time.Now().UTC())
+	_, err := s.db.ExecContext(ctx, query, offerID, provider, gpuType, machineID, failureType, reason, time.Now().UTC())
 	if err != nil {
 		return fmt.Errorf("failed to record offer failure: %w", err)
 	}
@@ -76,7 +80,7 @@ func (s *OfferFailureStore) ClearSuppression(ctx context.Context, offerID string
 // LoadRecentFailures loads failure events newer than the given cutoff time
 func (s *OfferFailureStore) LoadRecentFailures(ctx context.Context, since time.Time) ([]OfferFailureRecord, error) {
 	query := `
-		SELECT offer_id, provider, gpu_type, failure_type, reason, created_at
+		SELECT offer_id, provider, gpu_type, machine_id, failure_type, reason, created_at
 		FROM offer_failures
 		WHERE created_at > ?
 		ORDER BY created_at ASC
@@ -90,7 +94,7 @@ func (s *OfferFailureStore) LoadRecentFailures(ctx context.Context, since time.T
 	var records []OfferFailureRecord
 	for rows.Next() {
 		var r OfferFailureRecord
-		if err := rows.Scan(&r.OfferID, &r.Provider, &r.GPUType, &r.FailureType, &r.Reason, &r.CreatedAt); err != nil {
+		if err := rows.Scan(&r.OfferID, &r.Provider, &r.GPUType, &r.MachineID, &r.FailureType, &r.Reason, &r.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan failure record: %w", err)
 		}
 		records = append(records, r)
@@ -128,6 +132,15 @@ func (s *OfferFailureStore) LoadActiveSuppressions(ctx context.Context, cooldown
 	return records, nil
 }
 
+// ClearFailures deletes every failure event recorded for an offer
+func (s *OfferFailureStore) ClearFailures(ctx context.Context, offerID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM offer_failures WHERE offer_id = ?`, offerID)
+	if err != nil {
+		return fmt.Errorf("failed to clear offer failures: %w", err)
+	}
+	return nil
+}
+
 // CleanupOldFailures deletes failure events older than the given cutoff
 func (s *OfferFailureStore) CleanupOldFailures(ctx context.Context, before time.Time) (int64, error) {
 	query := `DELETE FROM offer_failures WHERE created_at < ?`
@@ -148,6 +161,132 @@ func (s *OfferFailureStore) CleanupExpiredSuppressions(ctx context.Context, befo
 	return result.RowsAffected()
 }
 
+// SetRule creates an offer rule, replacing any rule for the same scope,
+// value and provider
+func (s *OfferFailureStore) SetRule(ctx context.Context, rule *models.OfferRule) error {
+	var expiresAt interface{}
+	if rule.ExpiresAt != nil {
+		expiresAt = rule.ExpiresAt.UTC()
+	}
+	query := `
+		INSERT INTO offer_rules (id, action, scope, value, provider, reason, created_by, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(scope, value, provider) DO UPDATE SET
+			id = excluded.id,
+			action = excluded.action,
+			reason = excluded.reason,
+			created_by = excluded.created_by,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at
+	`
+	_, err := s.db.ExecContext(ctx, query, rule.ID, string(rule.Action), string(rule.Scope), rule.Value,
+		rule.Provider, rule.Reason, rule.CreatedBy, rule.CreatedAt.UTC(), expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to set offer rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule removes an offer rule by ID
+func (s *OfferFailureStore) DeleteRule(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM offer_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete offer rule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LoadRules loads offer rules that have not expired by now
+func (s *OfferFailureStore) LoadRules(ctx context.Context, now time.Time) ([]*models.OfferRule, error) {
+	query := `
+		SELECT id, action, scope, value, provider, reason, created_by, created_at, expires_at
+		FROM offer_rules
+		WHERE expires_at IS NULL OR expires_at > ?
+		ORDER BY created_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load offer rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.OfferRule
+	for rows.Next() {
+		var r models.OfferRule
+		var action, scope string
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&r.ID, &action, &scope, &r.Value, &r.Provider, &r.Reason,
+			&r.CreatedBy, &r.CreatedAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan offer rule: %w", err)
+		}
+		r.Action = models.OfferRuleAction(action)
+		r.Scope = models.OfferRuleScope(scope)
+		if expiresAt.Valid {
+			r.ExpiresAt = &expiresAt.Time
+		}
+		rules = append(rules, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating offer rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SetLocationStats creates or replaces the provisioning stats for a location
+func (s *OfferFailureStore) SetLocationStats(ctx context.Context, stats *models.LocationStats) error {
+	query := `
+		INSERT INTO location_stats (provider, location_id, attempts, successes, last_attempt_at, last_succeeded)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(provider, location_id) DO UPDATE SET
+			attempts = excluded.attempts,
+			successes = excluded.successes,
+			last_attempt_at = excluded.last_attempt_at,
+			last_succeeded = excluded.last_succeeded
+	`
+	_, err := s.db.ExecContext(ctx, query, stats.Provider, stats.LocationID, stats.Attempts, stats.Successes,
+		stats.LastAttemptAt.UTC(), stats.LastSucceeded)
+	if err != nil {
+		return fmt.Errorf("failed to set location stats: %w", err)
+	}
+	return nil
+}
+
+// LoadLocationStats loads the provisioning stats of every location of a provider
+func (s *OfferFailureStore) LoadLocationStats(ctx context.Context, provider string) ([]models.LocationStats, error) {
+	query := `
+		SELECT provider, location_id, attempts, successes, last_attempt_at, last_succeeded
+		FROM location_stats
+		WHERE provider = ?
+		ORDER BY location_id
+	`
+	rows, err := s.db.QueryContext(ctx, query, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.LocationStats
+	for rows.Next() {
+		var st models.LocationStats
+		if err := rows.Scan(&st.Provider, &st.LocationID, &st.Attempts, &st.Successes,
+			&st.LastAttemptAt, &st.LastSucceeded); err != nil {
+			return nil, fmt.Errorf("failed to scan location stats: %w", err)
+		}
+		stats = append(stats, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating location stats: %w", err)
+	}
+	return stats, nil
+}
+
 // CountByOfferID returns the count of recent failures per offer
 func (s *OfferFailureStore) CountByOfferID(ctx context.Context, since time.Time) (map[string]int, error) {
 	query := `
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
 )
 
 func TestOfferFailureStore_RecordAndLoad(t *testing.T) {
@@ -15,11 +17,11 @@ func TestOfferFailureStore_RecordAndLoad(t *testing.T) {
 	ctx := context.Background()
 
 	// Record a failure
-	err := store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "stale_inventory", "no such machine")
+	err := store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "vastai-machine-7", "stale_inventory", "no such machine")
 	require.NoError(t, err)
 
 	// Record another failure for a different offer
-	err = store.RecordFailure(ctx, "offer-2", "tensordock", "H100 SXM5", "instance_stopped", "stopped immediately")
+	err = store.RecordFailure(ctx, "offer-2", "tensordock", "H100 SXM5", "", "instance_stopped", "stopped immediately")
 	require.NoError(t, err)
 
 	// Load recent failures
@@ -32,6 +34,7 @@ func TestOfferFailureStore_RecordAndLoad(t *testing.T) {
 	assert.Equal(t, "offer-1", failures[0].OfferID)
 	assert.Equal(t, "vastai", failures[0].Provider)
 	assert.Equal(t, "RTX 4090", failures[0].GPUType)
+	assert.Equal(t, "vastai-machine-7", failures[0].MachineID)
 	assert.Equal(t, "stale_inventory", failures[0].FailureType)
 	assert.Equal(t, "no such machine", failures[0].Reason)
 
@@ -39,6 +42,7 @@ func TestOfferFailureStore_RecordAndLoad(t *testing.T) {
 	assert.Equal(t, "offer-2", failures[1].OfferID)
 	assert.Equal(t, "tensordock", failures[1].Provider)
 	assert.Equal(t, "H100 SXM5", failures[1].GPUType)
+	assert.Empty(t, failures[1].MachineID)
 	assert.Equal(t, "instance_stopped", failures[1].FailureType)
 }
 
@@ -55,7 +59,7 @@ func TestOfferFailureStore_LoadRecentFailures_FiltersByTime(t *testing.T) {
 	require.NoError(t, err)
 
 	// Insert a recent failure
-	err = store.RecordFailure(ctx, "new-offer", "vastai", "RTX 4090", "stale_inventory", "recent failure")
+	err = store.RecordFailure(ctx, "new-offer", "vastai", "RTX 4090", "", "stale_inventory", "recent failure")
 	require.NoError(t, err)
 
 	// Load only failures from the last hour
@@ -182,11 +186,11 @@ func TestOfferFailureStore_CountByOfferID(t *testing.T) {
 	ctx := context.Background()
 
 	// Record multiple failures for different offers
-	err := store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "stale_inventory", "fail 1")
+	err := store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "", "stale_inventory", "fail 1")
 	require.NoError(t, err)
-	err = store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "ssh_timeout", "fail 2")
+	err = store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "", "ssh_timeout", "fail 2")
 	require.NoError(t, err)
-	err = store.RecordFailure(ctx, "offer-2", "tensordock", "H100", "instance_stopped", "fail 3")
+	err = store.RecordFailure(ctx, "offer-2", "tensordock", "H100", "", "instance_stopped", "fail 3")
 	require.NoError(t, err)
 
 	since := time.Now().Add(-1 * time.Hour)
@@ -226,6 +230,90 @@ func TestOfferFailureStore_MigrationsIdempotent(t *testing.T) {
 
 	// Store should still work
 	store := NewOfferFailureStore(db)
-	err = store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "stale_inventory", "test")
+	err = store.RecordFailure(ctx, "offer-1", "vastai", "RTX 4090", "", "stale_inventory", "test")
+	require.NoError(t, err)
+}
+
+func TestOfferFailureStore_Rules(t *testing.T) {
+	db := newTestDB(t)
+	store := NewOfferFailureStore(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	expired := now.Add(-time.Minute)
+	expires := now.Add(time.Hour)
+	require.NoError(t, store.SetRule(ctx, &models.OfferRule{
+		ID: "rule-1", Action: models.OfferRuleSuppress, Scope: models.OfferRuleScopeMachine,
+		Value: "12345", Provider: "vastai", Reason: "flaky host", CreatedBy: "alice", CreatedAt: now,
+	}))
+	require.NoError(t, store.SetRule(ctx, &models.OfferRule{
+		ID: "rule-2", Action: models.OfferRuleAllow, Scope: models.OfferRuleScopeOffer,
+		Value: "offer-1", CreatedAt: now, ExpiresAt: &expires,
+	}))
+	require.NoError(t, store.SetRule(ctx, &models.OfferRule{
+		ID: "rule-3", Action: models.OfferRuleSuppress, Scope: models.OfferRuleScopeLocation,
+		Value: "DE", CreatedAt: now, ExpiresAt: &expired,
+	}))
+
+	rules, err := store.LoadRules(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, rules, 2, "expired rules are not loaded")
+	assert.Equal(t, "rule-1", rules[0].ID)
+	assert.Equal(t, models.OfferRuleSuppress, rules[0].Action)
+	assert.Equal(t, models.OfferRuleScopeMachine, rules[0].Scope)
+	assert.Equal(t, "vastai", rules[0].Provider)
+	assert.Equal(t, "flaky host", rules[0].Reason)
+	assert.Nil(t, rules[0].ExpiresAt)
+	require.NotNil(t, rules[1].ExpiresAt)
+	assert.WithinDuration(t, expires, *rules[1].ExpiresAt, time.Second)
+
+	// A rule for the same target replaces the existing one
+	require.NoError(t, store.SetRule(ctx, &models.OfferRule{
+		ID: "rule-4", Action: models.OfferRuleAllow, Scope: models.OfferRuleScopeMachine,
+		Value: "12345", Provider: "vastai", CreatedAt: now,
+	}))
+	rules, err = store.LoadRules(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "rule-4", rules[0].ID)
+	assert.Equal(t, models.OfferRuleAllow, rules[0].Action)
+
+	require.NoError(t, store.DeleteRule(ctx, "rule-4"))
+	assert.ErrorIs(t, store.DeleteRule(ctx, "rule-4"), ErrNotFound)
+	rules, err = store.LoadRules(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "rule-2", rules[0].ID)
+}
+
+func TestOfferFailureStore_LocationStats(t *testing.T) {
+	db := newTestDB(t)
+	store := NewOfferFailureStore(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, store.SetLocationStats(ctx, &models.LocationStats{
+		Provider: "tensordock", LocationID: "loc-1", Attempts: 3, Successes: 1, LastAttemptAt: now,
+	}))
+	require.NoError(t, store.SetLocationStats(ctx, &models.LocationStats{
+		Provider: "vastai", LocationID: "loc-1", Attempts: 1, Successes: 1, LastAttemptAt: now, LastSucceeded: true,
+	}))
+
+	// Updates replace the previous counts
+	require.NoError(t, store.SetLocationStats(ctx, &models.LocationStats{
+		Provider: "tensordock", LocationID: "loc-1", Attempts: 4, Successes: 2, LastAttemptAt: now, LastSucceeded: true,
+	}))
+
+	stats, err := store.LoadLocationStats(ctx, "tensordock")
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "loc-1", stats[0].LocationID)
+	assert.Equal(t, 4, stats[0].Attempts)
+	assert.Equal(t, 2, stats[0].Successes)
+	assert.True(t, stats[0].LastSucceeded)
+	assert.WithinDuration(t, now, stats[0].LastAttemptAt, time.Second)
+
+	stats, err = store.LoadLocationStats(ctx, "bluelobster")
 	require.NoError(t, err)
+	assert.Empty(t, stats)
 }
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// FeatureFlagStore handles persistence of runtime feature flags
+type FeatureFlagStore struct {
+	db *DB
+}
+
+// NewFeatureFlagStore creates a new feature flag store
+func NewFeatureFlagStore(db *DB) *FeatureFlagStore {
+	return &FeatureFlagStore{db: db}
+}
+
+const featureFlagColumns = `name, enabled, rollout_percent, description, updated_by, updated_at`
+
+// SetFlag creates or replaces a feature flag
+func (s *FeatureFlagStore) SetFlag(ctx context.Context, flag *models.FeatureFlag) error {
+	if flag.UpdatedAt.IsZero() {
+		flag.UpdatedAt = time.Now().UTC()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO feature_flags (`+featureFlagColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			enabled = excluded.enabled,
+			rollout_percent = excluded.rollout_percent,
+			description = excluded.description,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at
+	`, flag.Name, flag.Enabled, flag.RolloutPercent, flag.Description, flag.UpdatedBy, flag.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set feature flag: %w", err)
+	}
+	return nil
+}
+
+// GetFlag retrieves a feature flag by name
+func (s *FeatureFlagStore) GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+featureFlagColumns+` FROM feature_flags WHERE name = ?`, name)
+	flag, err := scanFeatureFlag(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return flag, nil
+}
+
+// ListFlags returns all feature flags ordered by name
+func (s *FeatureFlagStore) ListFlags(ctx context.Context) ([]*models.FeatureFlag, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+featureFlagColumns+` FROM feature_flags ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []*models.FeatureFlag
+	for rows.Next() {
+		flag, err := scanFeatureFlag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+// DeleteFlag removes a feature flag, reverting it to its built-in default
+func (s *FeatureFlagStore) DeleteFlag(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanFeatureFlag(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	if err := scanner.Scan(&flag.Name, &flag.Enabled, &flag.RolloutPercent,
+		&flag.Description, &flag.UpdatedBy, &flag.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
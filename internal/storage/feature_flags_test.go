@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagStore_CRUD(t *testing.T) {
+	db := newTestDB(t)
+	store := NewFeatureFlagStore(db)
+	ctx := context.Background()
+
+	_, err := store.GetFlag(ctx, "tensordock.dedicated_ip")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	flag := &models.FeatureFlag{
+		Name:           "tensordock.dedicated_ip",
+		Enabled:        true,
+		RolloutPercent: 10,
+		Description:    "canary port-forward fallback",
+		UpdatedBy:      "ops-bob",
+	}
+	require.NoError(t, store.SetFlag(ctx, flag))
+
+	got, err := store.GetFlag(ctx, "tensordock.dedicated_ip")
+	require.NoError(t, err)
+	assert.True(t, got.Enabled)
+	assert.Equal(t, 10, got.RolloutPercent)
+	assert.Equal(t, "ops-bob", got.UpdatedBy)
+	assert.False(t, got.UpdatedAt.IsZero())
+
+	// Upsert replaces
+	require.NoError(t, store.SetFlag(ctx, &models.FeatureFlag{
+		Name: "tensordock.dedicated_ip", Enabled: false, RolloutPercent: 0, UpdatedBy: "ops-carol",
+	}))
+	got, err = store.GetFlag(ctx, "tensordock.dedicated_ip")
+	require.NoError(t, err)
+	assert.False(t, got.Enabled)
+	assert.Equal(t, "ops-carol", got.UpdatedBy)
+
+	require.NoError(t, store.SetFlag(ctx, &models.FeatureFlag{Name: "provider.bluelobster", Enabled: true, RolloutPercent: 100}))
+	flags, err := store.ListFlags(ctx)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "provider.bluelobster", flags[0].Name)
+
+	require.NoError(t, store.DeleteFlag(ctx, "provider.bluelobster"))
+	assert.ErrorIs(t, store.DeleteFlag(ctx, "provider.bluelobster"), ErrNotFound)
+}
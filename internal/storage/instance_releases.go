@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// InstanceReleaseStore handles persistence of provider instances this
+// deployment has released
+type InstanceReleaseStore struct {
+	db *DB
+}
+
+// NewInstanceReleaseStore creates a new instance release store
+func NewInstanceReleaseStore(db *DB) *InstanceReleaseStore {
+	return &InstanceReleaseStore{db: db}
+}
+
+// Release records a released instance, replacing an earlier release of it
+func (s *InstanceReleaseStore) Release(ctx context.Context, release *models.InstanceRelease) error {
+	if release.ReleasedAt.IsZero() {
+		release.ReleasedAt = time.Now().UTC()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO instance_releases (provider, instance_id, deployment_id, released_by, released_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(provider, instance_id) DO UPDATE SET
+			deployment_id = excluded.deployment_id,
+			released_by = excluded.released_by,
+			released_at = excluded.released_at
+	`, release.Provider, release.InstanceID, release.DeploymentID, release.ReleasedBy, release.ReleasedAt)
+	if err != nil {
+		return fmt.Errorf("failed to release instance: %w", err)
+	}
+	return nil
+}
+
+// ListReleases returns every released instance, ordered by provider and ID
+func (s *InstanceReleaseStore) ListReleases(ctx context.Context) ([]*models.InstanceRelease, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT provider, instance_id, deployment_id, released_by, released_at
+		FROM instance_releases ORDER BY provider, instance_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instance releases: %w", err)
+	}
+	defer rows.Close()
+
+	var releases []*models.InstanceRelease
+	for rows.Next() {
+		var r models.InstanceRelease
+		if err := rows.Scan(&r.Provider, &r.InstanceID, &r.DeploymentID, &r.ReleasedBy, &r.ReleasedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan instance release: %w", err)
+		}
+		releases = append(releases, &r)
+	}
+	return releases, rows.Err()
+}
+
+// DeleteRelease forgets a released instance, so the reconciler treats it as
+// any other again
+func (s *InstanceReleaseStore) DeleteRelease(ctx context.Context, provider, instanceID string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM instance_releases WHERE provider = ? AND instance_id = ?`, provider, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete instance release: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
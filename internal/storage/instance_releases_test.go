@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceReleaseStore_CRUD(t *testing.T) {
+	db := newTestDB(t)
+	store := NewInstanceReleaseStore(db)
+	ctx := context.Background()
+
+	releases, err := store.ListReleases(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, releases)
+
+	require.NoError(t, store.Release(ctx, &models.InstanceRelease{
+		Provider: "vastai", InstanceID: "12345", DeploymentID: "prod", ReleasedBy: "ops-bob",
+	}))
+	require.NoError(t, store.Release(ctx, &models.InstanceRelease{
+		Provider: "tensordock", InstanceID: "abc", DeploymentID: "prod",
+	}))
+	// Releasing again replaces the release
+	require.NoError(t, store.Release(ctx, &models.InstanceRelease{
+		Provider: "vastai", InstanceID: "12345", DeploymentID: "prod", ReleasedBy: "ops-carol",
+	}))
+
+	releases, err = store.ListReleases(ctx)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, "tensordock", releases[0].Provider)
+	assert.Equal(t, "12345", releases[1].InstanceID)
+	assert.Equal(t, "ops-carol", releases[1].ReleasedBy)
+	assert.False(t, releases[1].ReleasedAt.IsZero())
+
+	require.NoError(t, store.DeleteRelease(ctx, "vastai", "12345"))
+	assert.ErrorIs(t, store.DeleteRelease(ctx, "vastai", "12345"), ErrNotFound)
+	releases, err = store.ListReleases(ctx)
+	require.NoError(t, err)
+	assert.Len(t, releases, 1)
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LeaseStore grants named, time-limited leases. Server replicas sharing a
+// database use them to elect the one instance that runs background work.
+type LeaseStore struct {
+	db *DB
+}
+
+// NewLeaseStore creates a new lease store
+func NewLeaseStore(db *DB) *LeaseStore {
+	return &LeaseStore{db: db}
+}
+
+// TryAcquire takes the named lease for holder, or extends it if holder
+// already has it, so that it expires ttl from now. It reports false when
+// another holder has an unexpired lease.
+func (s *LeaseStore) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO leases (name, holder, acquired_at, expires_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			holder = excluded.holder,
+			acquired_at = CASE WHEN leases.holder = excluded.holder THEN leases.acquired_at ELSE excluded.acquired_at END,
+			expires_at = excluded.expires_at
+		WHERE leases.holder = excluded.holder OR leases.expires_at < ?
+	`, name, holder, now, now.Add(ttl), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	return n == 1, nil
+}
+
+// Release gives up the named lease if holder has it, letting another
+// holder take it without waiting for it to expire
+func (s *LeaseStore) Release(ctx context.Context, name, holder string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM leases WHERE name = ? AND holder = ?`, name, holder); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseStore_TryAcquire(t *testing.T) {
+	db := newTestDB(t)
+	store := NewLeaseStore(db)
+	ctx := context.Background()
+
+	ok, err := store.TryAcquire(ctx, "background", "replica-a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "a free lease is granted")
+
+	ok, err = store.TryAcquire(ctx, "background", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "a held lease is not granted to another holder")
+
+	ok, err = store.TryAcquire(ctx, "background", "replica-a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "the holder can renew")
+
+	ok, err = store.TryAcquire(ctx, "other", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "leases are independent by name")
+
+	// Releasing as a different holder has no effect
+	require.NoError(t, store.Release(ctx, "background", "replica-b"))
+	ok, err = store.TryAcquire(ctx, "background", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Release(ctx, "background", "replica-a"))
+	ok, err = store.TryAcquire(ctx, "background", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "a released lease can be taken")
+}
+
+func TestLeaseStore_TryAcquire_Expired(t *testing.T) {
+	db := newTestDB(t)
+	store := NewLeaseStore(db)
+	ctx := context.Background()
+
+	ok, err := store.TryAcquire(ctx, "background", "replica-a", time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	time.Sleep(5 * time.Millisecond)
+
+	ok, err = store.TryAcquire(ctx, "background", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "an expired lease can be taken over")
+
+	ok, err = store.TryAcquire(ctx, "background", "replica-a", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "the previous holder lost the lease")
+}
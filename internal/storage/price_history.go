@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+const (
+	// RawPriceRetention is how long individual price snapshots are kept
+	// before being downsampled into hourly buckets
+	RawPriceRetention = 48 * time.Hour
+
+	// PriceHistoryRetention is how long hourly price buckets are kept
+	PriceHistoryRetention = 90 * 24 * time.Hour
+)
+
+// PriceHistoryStore handles persistence of offer price snapshots
+type PriceHistoryStore struct {
+	db *DB
+}
+
+// NewPriceHistoryStore creates a new price history store
+func NewPriceHistoryStore(db *DB) *PriceHistoryStore {
+	return &PriceHistoryStore{db: db}
+}
+
+// RecordPriceSnapshots stores raw price snapshots
+func (s *PriceHistoryStore) RecordPriceSnapshots(ctx context.Context, snapshots []models.PriceSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO price_history (provider, gpu_type, min_price, avg_price, max_price, offer_count, sampled_at, resolution)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 'raw')
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare price snapshot insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, snap := range snapshots {
+		if _, err := stmt.ExecContext(ctx,
+			snap.Provider, snap.GPUType, snap.MinPrice, snap.AvgPrice, snap.MaxPrice, snap.OfferCount, snap.SampledAt.UTC(),
+		); err != nil {
+			return fmt.Errorf("failed to record price snapshot: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListPriceHistory returns snapshots matching the filter, oldest first
+func (s *PriceHistoryStore) ListPriceHistory(ctx context.Context, filter models.PriceHistoryFilter) ([]models.PriceSnapshot, error) {
+	query := `
+		SELECT provider, gpu_type, min_price, avg_price, max_price, offer_count, sampled_at
+		FROM price_history
+		WHERE gpu_type = ? COLLATE NOCASE`
+	args := []interface{}{filter.GPUType}
+
+	if filter.Provider != "" {
+		query += " AND provider = ?"
+		args = append(args, filter.Provider)
+	}
+
+	if !filter.Since.IsZero() {
+		query += " AND sampled_at >= ?"
+		args = append(args, filter.Since.UTC())
+	}
+
+	query += " ORDER BY sampled_at ASC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price history: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.PriceSnapshot
+	for rows.Next() {
+		var snap models.PriceSnapshot
+		if err := rows.Scan(&snap.Provider, &snap.GPUType, &snap.MinPrice, &snap.AvgPrice,
+			&snap.MaxPrice, &snap.OfferCount, &snap.SampledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
+// CompactPriceHistory downsamples raw snapshots older than RawPriceRetention
+// into one row per provider, GPU type and hour, and deletes hourly rows older
+// than PriceHistoryRetention. This keeps the table bounded regardless of how
+// often inventory is refreshed.
+func (s *PriceHistoryStore) CompactPriceHistory(ctx context.Context, now time.Time) error {
+	rawCutoff := now.Add(-RawPriceRetention).UTC()
+	hourlyCutoff := now.Add(-PriceHistoryRetention).UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Bucket in Go so hour truncation doesn't depend on how the driver
+	// formats stored timestamps
+	rows, err := tx.QueryContext(ctx, `
+		SELECT provider, gpu_type, min_price, avg_price, max_price, offer_count, sampled_at
+		FROM price_history
+		WHERE resolution = 'raw' AND sampled_at < ?
+	`, rawCutoff)
+	if err != nil {
+		return fmt.Errorf("failed to read raw price history: %w", err)
+	}
+
+	type bucketKey struct {
+		provider string
+		gpuType  string
+		hour     time.Time
+	}
+	type bucket struct {
+		min, max, sumAvg float64
+		samples          int
+		maxOffers        int
+	}
+	buckets := make(map[bucketKey]*bucket)
+	for rows.Next() {
+		var snap models.PriceSnapshot
+		if err := rows.Scan(&snap.Provider, &snap.GPUType, &snap.MinPrice, &snap.AvgPrice,
+			&snap.MaxPrice, &snap.OfferCount, &snap.SampledAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan price snapshot: %w", err)
+		}
+		key := bucketKey{snap.Provider, snap.GPUType, snap.SampledAt.UTC().Truncate(time.Hour)}
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{min: snap.MinPrice, max: snap.MaxPrice}
+			buckets[key] = b
+		}
+		if snap.MinPrice < b.min {
+			b.min = snap.MinPrice
+		}
+		if snap.MaxPrice > b.max {
+			b.max = snap.MaxPrice
+		}
+		if snap.OfferCount > b.maxOffers {
+			b.maxOffers = snap.OfferCount
+		}
+		b.sumAvg += snap.AvgPrice
+		b.samples++
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("failed to read raw price history: %w", err)
+	}
+
+	for key, b := range buckets {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO price_history (provider, gpu_type, min_price, avg_price, max_price, offer_count, sampled_at, resolution)
+			VALUES (?, ?, ?, ?, ?, ?, ?, 'hour')
+		`, key.provider, key.gpuType, b.min, b.sumAvg/float64(b.samples), b.max, b.maxOffers, key.hour); err != nil {
+			return fmt.Errorf("failed to write hourly price bucket: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM price_history WHERE resolution = 'raw' AND sampled_at < ?`, rawCutoff); err != nil {
+		return fmt.Errorf("failed to delete raw price history: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM price_history WHERE sampled_at < ?`, hourlyCutoff); err != nil {
+		return fmt.Errorf("failed to delete expired price history: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistoryStore_RecordAndList(t *testing.T) {
+	db := newTestDB(t)
+	store := NewPriceHistoryStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	require.NoError(t, store.RecordPriceSnapshots(ctx, []models.PriceSnapshot{
+		{Provider: "vastai", GPUType: "RTX4090", MinPrice: 0.40, AvgPrice: 0.50, MaxPrice: 0.60, OfferCount: 12, SampledAt: now.Add(-2 * time.Hour)},
+		{Provider: "tensordock", GPUType: "RTX4090", MinPrice: 0.45, AvgPrice: 0.55, MaxPrice: 0.70, OfferCount: 3, SampledAt: now.Add(-time.Hour)},
+		{Provider: "vastai", GPUType: "A100", MinPrice: 1.10, AvgPrice: 1.40, MaxPrice: 1.90, OfferCount: 5, SampledAt: now},
+	}))
+
+	got, err := store.ListPriceHistory(ctx, models.PriceHistoryFilter{GPUType: "rtx4090"})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "vastai", got[0].Provider)
+	assert.Equal(t, "tensordock", got[1].Provider)
+	assert.Equal(t, 0.40, got[0].MinPrice)
+	assert.Equal(t, 12, got[0].OfferCount)
+
+	got, err = store.ListPriceHistory(ctx, models.PriceHistoryFilter{GPUType: "RTX4090", Provider: "vastai"})
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	got, err = store.ListPriceHistory(ctx, models.PriceHistoryFilter{GPUType: "RTX4090", Since: now.Add(-90 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "tensordock", got[0].Provider)
+}
+
+func TestPriceHistoryStore_Compact(t *testing.T) {
+	db := newTestDB(t)
+	store := NewPriceHistoryStore(db)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-RawPriceRetention - 3*time.Hour).Truncate(time.Hour)
+
+	require.NoError(t, store.RecordPriceSnapshots(ctx, []models.PriceSnapshot{
+		// Two raw samples in the same old hour
+		{Provider: "vastai", GPUType: "RTX4090", MinPrice: 0.40, AvgPrice: 0.50, MaxPrice: 0.60, OfferCount: 10, SampledAt: old.Add(5 * time.Minute)},
+		{Provider: "vastai", GPUType: "RTX4090", MinPrice: 0.35, AvgPrice: 0.60, MaxPrice: 0.55, OfferCount: 14, SampledAt: old.Add(35 * time.Minute)},
+		// A sample in the next hour
+		{Provider: "vastai", GPUType: "RTX4090", MinPrice: 0.45, AvgPrice: 0.55, MaxPrice: 0.65, OfferCount: 9, SampledAt: old.Add(65 * time.Minute)},
+		// Recent sample stays raw
+		{Provider: "vastai", GPUType: "RTX4090", MinPrice: 0.42, AvgPrice: 0.52, MaxPrice: 0.62, OfferCount: 11, SampledAt: now.Add(-time.Hour)},
+		// Beyond total retention
+		{Provider: "vastai", GPUType: "RTX4090", MinPrice: 0.90, AvgPrice: 0.90, MaxPrice: 0.90, OfferCount: 1, SampledAt: now.Add(-PriceHistoryRetention - time.Hour)},
+	}))
+
+	require.NoError(t, store.CompactPriceHistory(ctx, now))
+
+	got, err := store.ListPriceHistory(ctx, models.PriceHistoryFilter{GPUType: "RTX4090"})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	first := got[0]
+	assert.True(t, first.SampledAt.Equal(old), "bucket timestamp is the start of the hour")
+	assert.Equal(t, 0.35, first.MinPrice)
+	assert.Equal(t, 0.60, first.MaxPrice)
+	assert.InDelta(t, 0.55, first.AvgPrice, 1e-9)
+	assert.Equal(t, 14, first.OfferCount)
+
+	assert.True(t, got[1].SampledAt.Equal(old.Add(time.Hour)))
+	assert.True(t, got[2].SampledAt.Equal(now.Add(-time.Hour)))
+
+	// Compaction is idempotent
+	require.NoError(t, store.CompactPriceHistory(ctx, now))
+	got, err = store.ListPriceHistory(ctx, models.PriceHistoryFilter{GPUType: "RTX4090"})
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// PriceWatchStore handles persistence of consumer price watches
+type PriceWatchStore struct {
+	db *DB
+}
+
+// NewPriceWatchStore creates a new price watch store
+func NewPriceWatchStore(db *DB) *PriceWatchStore {
+	return &PriceWatchStore{db: db}
+}
+
+const priceWatchColumns = `id, consumer_id, gpu_type, max_price, region, provider, matching, notified_at, created_at`
+
+// CreatePriceWatch creates a new price watch
+func (s *PriceWatchStore) CreatePriceWatch(ctx context.Context, watch *models.PriceWatch) error {
+	if watch.ID == "" {
+		watch.ID = uuid.New().String()
+	}
+	if watch.CreatedAt.IsZero() {
+		watch.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO price_watches (`+priceWatchColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		watch.ID, watch.ConsumerID, watch.GPUType, watch.MaxPrice, watch.Region, watch.Provider,
+		watch.Matching, watch.NotifiedAt, watch.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create price watch: %w", err)
+	}
+	return nil
+}
+
+// GetPriceWatch retrieves a price watch by ID
+func (s *PriceWatchStore) GetPriceWatch(ctx context.Context, id string) (*models.PriceWatch, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+priceWatchColumns+` FROM price_watches WHERE id = ?`, id)
+	watch, err := scanPriceWatch(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price watch: %w", err)
+	}
+	return watch, nil
+}
+
+// ListPriceWatches lists price watches, optionally for a single consumer
+func (s *PriceWatchStore) ListPriceWatches(ctx context.Context, consumerID string) ([]*models.PriceWatch, error) {
+	query := `SELECT ` + priceWatchColumns + ` FROM price_watches`
+	var args []interface{}
+	if consumerID != "" {
+		query += " WHERE consumer_id = ?"
+		args = append(args, consumerID)
+	}
+	query += " ORDER BY created_at"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price watches: %w", err)
+	}
+	defer rows.Close()
+
+	var watches []*models.PriceWatch
+	for rows.Next() {
+		watch, err := scanPriceWatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price watch: %w", err)
+		}
+		watches = append(watches, watch)
+	}
+	return watches, rows.Err()
+}
+
+// SetPriceWatchMatching records whether the watch currently matches. When
+// notifiedAt is non-nil it is stored as the last notification time.
+func (s *PriceWatchStore) SetPriceWatchMatching(ctx context.Context, id string, matching bool, notifiedAt *time.Time) error {
+	var err error
+	if notifiedAt != nil {
+		_, err = s.db.ExecContext(ctx, `UPDATE price_watches SET matching = ?, notified_at = ? WHERE id = ?`,
+			matching, notifiedAt.UTC(), id)
+	} else {
+		_, err = s.db.ExecContext(ctx, `UPDATE price_watches SET matching = ? WHERE id = ?`, matching, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update price watch: %w", err)
+	}
+	return nil
+}
+
+// DeletePriceWatch deletes a price watch
+func (s *PriceWatchStore) DeletePriceWatch(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM price_watches WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete price watch: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanPriceWatch(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.PriceWatch, error) {
+	var watch models.PriceWatch
+	var notifiedAt sql.NullTime
+	if err := scanner.Scan(&watch.ID, &watch.ConsumerID, &watch.GPUType, &watch.MaxPrice, &watch.Region,
+		&watch.Provider, &watch.Matching, &notifiedAt, &watch.CreatedAt); err != nil {
+		return nil, err
+	}
+	if notifiedAt.Valid {
+		watch.NotifiedAt = &notifiedAt.Time
+	}
+	return &watch, nil
+}
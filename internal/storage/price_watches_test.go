@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+func TestPriceWatchStore_CRUD(t *testing.T) {
+	db := newTestDB(t)
+	store := NewPriceWatchStore(db)
+	ctx := context.Background()
+
+	watch := &models.PriceWatch{ConsumerID: "consumer-001", GPUType: "RTX 4090", MaxPrice: 0.30, Region: "US"}
+	require.NoError(t, store.CreatePriceWatch(ctx, watch))
+	require.NotEmpty(t, watch.ID)
+	require.NoError(t, store.CreatePriceWatch(ctx, &models.PriceWatch{ConsumerID: "consumer-002", GPUType: "A100", MaxPrice: 1.0}))
+
+	got, err := store.GetPriceWatch(ctx, watch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "RTX 4090", got.GPUType)
+	assert.Equal(t, 0.30, got.MaxPrice)
+	assert.Equal(t, "US", got.Region)
+	assert.False(t, got.Matching)
+	assert.Nil(t, got.NotifiedAt)
+
+	all, err := store.ListPriceWatches(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	mine, err := store.ListPriceWatches(ctx, "consumer-001")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+
+	notified := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, store.SetPriceWatchMatching(ctx, watch.ID, true, &notified))
+	got, err = store.GetPriceWatch(ctx, watch.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Matching)
+	require.NotNil(t, got.NotifiedAt)
+	assert.True(t, notified.Equal(*got.NotifiedAt))
+
+	// Re-arming keeps the last notification time
+	require.NoError(t, store.SetPriceWatchMatching(ctx, watch.ID, false, nil))
+	got, err = store.GetPriceWatch(ctx, watch.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Matching)
+	assert.NotNil(t, got.NotifiedAt)
+
+	require.NoError(t, store.DeletePriceWatch(ctx, watch.ID))
+	_, err = store.GetPriceWatch(ctx, watch.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.DeletePriceWatch(ctx, watch.ID), ErrNotFound)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// UsageStore handles persistence of provider-reported session usage
+type UsageStore struct {
+	db *DB
+}
+
+// NewUsageStore creates a new usage store
+func NewUsageStore(db *DB) *UsageStore {
+	return &UsageStore{db: db}
+}
+
+// UpsertProviderUsage records the latest provider-reported usage for a session
+func (s *UsageStore) UpsertProviderUsage(ctx context.Context, usage *models.ProviderUsage) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO provider_usage (session_id, provider, hourly_rate, total, observed_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			provider = excluded.provider,
+			hourly_rate = excluded.hourly_rate,
+			total = excluded.total,
+			observed_at = excluded.observed_at
+	`, usage.SessionID, usage.Provider, usage.HourlyRate, usage.Total, usage.ObservedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record provider usage: %w", err)
+	}
+	return nil
+}
+
+// GetProviderUsage returns the latest provider-reported usage for a session
+func (s *UsageStore) GetProviderUsage(ctx context.Context, sessionID string) (*models.ProviderUsage, error) {
+	var usage models.ProviderUsage
+	err := s.db.QueryRowContext(ctx, `
+		SELECT session_id, provider, hourly_rate, total, observed_at
+		FROM provider_usage WHERE session_id = ?
+	`, sessionID).Scan(&usage.SessionID, &usage.Provider, &usage.HourlyRate, &usage.Total, &usage.ObservedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider usage: %w", err)
+	}
+	return &usage, nil
+}
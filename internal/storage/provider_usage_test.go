@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageStore_Upsert(t *testing.T) {
+	db := newTestDB(t)
+	store := NewUsageStore(db)
+	ctx := context.Background()
+
+	_, err := store.GetProviderUsage(ctx, "sess-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	observed := time.Date(2026, 3, 18, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, store.UpsertProviderUsage(ctx, &models.ProviderUsage{
+		SessionID: "sess-1", Provider: "vastai", HourlyRate: 0.40, Total: 1.20, ObservedAt: observed,
+	}))
+	require.NoError(t, store.UpsertProviderUsage(ctx, &models.ProviderUsage{
+		SessionID: "sess-1", Provider: "vastai", HourlyRate: 0.40, Total: 1.60, ObservedAt: observed.Add(time.Hour),
+	}))
+
+	got, err := store.GetProviderUsage(ctx, "sess-1")
+	require.NoError(t, err)
+	assert.Equal(t, "vastai", got.Provider)
+	assert.Equal(t, 1.60, got.Total)
+	assert.True(t, got.ObservedAt.Equal(observed.Add(time.Hour)))
+}
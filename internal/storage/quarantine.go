@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// QuarantineStore handles persistence of quarantined provider instances
+type QuarantineStore struct {
+	db *DB
+}
+
+// NewQuarantineStore creates a new quarantine store
+func NewQuarantineStore(db *DB) *QuarantineStore {
+	return &QuarantineStore{db: db}
+}
+
+// Quarantine records a failed destroy of an instance. An instance already
+// quarantined keeps its first sighting and counts one more failure. Without a
+// next attempt time, destroying it is due at once.
+func (s *QuarantineStore) Quarantine(ctx context.Context, q *models.QuarantinedInstance) error {
+	if q.LastSeenAt.IsZero() {
+		q.LastSeenAt = time.Now().UTC()
+	}
+	if q.NextAttemptAt.IsZero() {
+		q.NextAttemptAt = q.LastSeenAt
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO quarantined_instances
+			(provider, instance_id, session_id, source, reason, failures, first_seen_at, last_seen_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, 1, ?, ?, ?)
+		ON CONFLICT(provider, instance_id) DO UPDATE SET
+			session_id = excluded.session_id,
+			source = excluded.source,
+			reason = excluded.reason,
+			failures = quarantined_instances.failures + 1,
+			last_seen_at = excluded.last_seen_at,
+			next_attempt_at = excluded.next_attempt_at
+	`, q.Provider, q.InstanceID, q.SessionID, q.Source, q.Reason, q.LastSeenAt, q.LastSeenAt, q.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to quarantine instance: %w", err)
+	}
+	return nil
+}
+
+// ListQuarantined returns every quarantined instance, oldest first
+func (s *QuarantineStore) ListQuarantined(ctx context.Context) ([]*models.QuarantinedInstance, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT provider, instance_id, session_id, source, reason, failures, first_seen_at, last_seen_at, next_attempt_at
+		FROM quarantined_instances ORDER BY first_seen_at, provider, instance_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined instances: %w", err)
+	}
+	defer rows.Close()
+
+	var instances []*models.QuarantinedInstance
+	for rows.Next() {
+		var q models.QuarantinedInstance
+		var nextAttemptAt sql.NullTime
+		if err := rows.Scan(&q.Provider, &q.InstanceID, &q.SessionID, &q.Source, &q.Reason,
+			&q.Failures, &q.FirstSeenAt, &q.LastSeenAt, &nextAttemptAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quarantined instance: %w", err)
+		}
+		q.NextAttemptAt = q.LastSeenAt
+		if nextAttemptAt.Valid {
+			q.NextAttemptAt = nextAttemptAt.Time
+		}
+		instances = append(instances, &q)
+	}
+	return instances, rows.Err()
+}
+
+// Resolve removes an instance from quarantine. It returns ErrNotFound if the
+// instance was not quarantined.
+func (s *QuarantineStore) Resolve(ctx context.Context, provider, instanceID string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM quarantined_instances WHERE provider = ? AND instance_id = ?`, provider, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve quarantined instance: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuarantineStore(t *testing.T) {
+	db := newTestDB(t)
+	store := NewQuarantineStore(db)
+	ctx := context.Background()
+
+	instances, err := store.ListQuarantined(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+
+	first := time.Date(2026, 1, 29, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Quarantine(ctx, &models.QuarantinedInstance{
+		Provider: "vastai", InstanceID: "12345", Source: models.QuarantineOrphan,
+		Reason: "provider API error", LastSeenAt: first,
+	}))
+	require.NoError(t, store.Quarantine(ctx, &models.QuarantinedInstance{
+		Provider: "tensordock", InstanceID: "abc", SessionID: "sess-1", Source: models.QuarantineDestroy,
+		Reason: "failed to verify destruction", LastSeenAt: first.Add(time.Minute),
+	}))
+	// A repeated failure counts up and keeps the first sighting
+	require.NoError(t, store.Quarantine(ctx, &models.QuarantinedInstance{
+		Provider: "vastai", InstanceID: "12345", Source: models.QuarantineOrphan,
+		Reason: "instance locked", LastSeenAt: first.Add(time.Hour), NextAttemptAt: first.Add(2 * time.Hour),
+	}))
+
+	instances, err = store.ListQuarantined(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "12345", instances[0].InstanceID)
+	assert.Equal(t, 2, instances[0].Failures)
+	assert.Equal(t, "instance locked", instances[0].Reason)
+	assert.True(t, first.Equal(instances[0].FirstSeenAt))
+	assert.True(t, first.Add(time.Hour).Equal(instances[0].LastSeenAt))
+	assert.True(t, first.Add(2*time.Hour).Equal(instances[0].NextAttemptAt))
+	assert.Equal(t, "sess-1", instances[1].SessionID)
+	// Without a scheduled attempt, one is due at once
+	assert.True(t, first.Add(time.Minute).Equal(instances[1].NextAttemptAt))
+	assert.Equal(t, models.QuarantineDestroy, instances[1].Source)
+
+	require.NoError(t, store.Resolve(ctx, "vastai", "12345"))
+	assert.ErrorIs(t, store.Resolve(ctx, "vastai", "12345"), ErrNotFound)
+	instances, err = store.ListQuarantined(ctx)
+	require.NoError(t, err)
+	assert.Len(t, instances, 1)
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// ReservationStore handles persistence of queued session requests
+type ReservationStore struct {
+	db *DB
+}
+
+// NewReservationStore creates a new reservation store
+func NewReservationStore(db *DB) *ReservationStore {
+	return &ReservationStore{db: db}
+}
+
+const reservationColumns = `id, consumer_id, filter, request, group_id, template_disk_gb, template_ssh_timeout_seconds,
+	status, session_id, attempts, last_error, created_at, expires_at, fulfilled_at, hf_token`
+
+// CreateReservation queues a new session request
+func (s *ReservationStore) CreateReservation(ctx context.Context, r *models.QueuedReservation) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+
+	filterJSON, err := json.Marshal(r.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter: %w", err)
+	}
+	requestJSON, err := json.Marshal(r.Request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	hfToken, err := s.db.seal(r.Request.HFToken)
+	if err != nil {
+		return err
+	}
+
+	// Fields the request keeps out of JSON are stored in their own columns
+	_, err = s.db.ExecContext(ctx, `INSERT INTO session_reservations (`+reservationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.ID, r.ConsumerID, string(filterJSON), string(requestJSON), r.Request.GroupID,
+		r.Request.TemplateRecommendedDiskGB, int(r.Request.TemplateRecommendedSSHTimeout.Seconds()),
+		r.Status, r.SessionID, r.Attempts, r.LastError, r.CreatedAt, r.ExpiresAt, r.FulfilledAt, hfToken)
+	if err != nil {
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+	return nil
+}
+
+// GetReservation retrieves a queued request by ID
+func (s *ReservationStore) GetReservation(ctx context.Context, id string) (*models.QueuedReservation, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+reservationColumns+` FROM session_reservations WHERE id = ?`, id)
+	r, err := s.scanReservation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return r, nil
+}
+
+// ListReservations lists queued requests oldest first, optionally for a
+// single consumer and status
+func (s *ReservationStore) ListReservations(ctx context.Context, consumerID string, status models.ReservationStatus) ([]*models.QueuedReservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM session_reservations WHERE 1=1`
+	var args []interface{}
+	if consumerID != "" {
+		query += " AND consumer_id = ?"
+		args = append(args, consumerID)
+	}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []*models.QueuedReservation
+	for rows.Next() {
+		r, err := s.scanReservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, r)
+	}
+	return reservations, rows.Err()
+}
+
+// UpdateReservation records the outcome of fulfilment attempts
+func (s *ReservationStore) UpdateReservation(ctx context.Context, r *models.QueuedReservation) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE session_reservations
+		SET status = ?, session_id = ?, attempts = ?, last_error = ?, fulfilled_at = ?
+		WHERE id = ?`,
+		r.Status, r.SessionID, r.Attempts, r.LastError, r.FulfilledAt, r.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update reservation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *ReservationStore) scanReservation(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.QueuedReservation, error) {
+	var r models.QueuedReservation
+	var filterJSON, requestJSON, groupID, hfToken string
+	var templateDiskGB, templateSSHTimeoutSecs int
+	var fulfilledAt sql.NullTime
+	if err := scanner.Scan(&r.ID, &r.ConsumerID, &filterJSON, &requestJSON, &groupID,
+		&templateDiskGB, &templateSSHTimeoutSecs, &r.Status, &r.SessionID, &r.Attempts,
+		&r.LastError, &r.CreatedAt, &r.ExpiresAt, &fulfilledAt, &hfToken); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(filterJSON), &r.Filter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal filter: %w", err)
+	}
+	if err := json.Unmarshal([]byte(requestJSON), &r.Request); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	r.Request.GroupID = groupID
+	r.Request.TemplateRecommendedDiskGB = templateDiskGB
+	r.Request.TemplateRecommendedSSHTimeout = time.Duration(templateSSHTimeoutSecs) * time.Second
+	token, err := s.db.open(hfToken)
+	if err != nil {
+		return nil, err
+	}
+	r.Request.HFToken = token
+	if fulfilledAt.Valid {
+		r.FulfilledAt = &fulfilledAt.Time
+	}
+	return &r, nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+func TestReservationStore_CRUD(t *testing.T) {
+	db := newTestDB(t)
+	store := NewReservationStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &models.QueuedReservation{
+		ConsumerID: "consumer-001",
+		Filter:     models.OfferFilter{GPUType: "RTX 4090", MaxPrice: 0.50},
+		Request: models.CreateSessionRequest{
+			ConsumerID:                    "consumer-001",
+			WorkloadType:                  models.WorkloadLLM,
+			ReservationHrs:                2,
+			GroupID:                       "sweep-1",
+			TemplateRecommendedSSHTimeout: 15 * time.Minute,
+			HFToken:                       "hf_example",
+		},
+		Status:    models.ReservationWaiting,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, store.CreateReservation(ctx, r))
+	require.NotEmpty(t, r.ID)
+	require.NoError(t, store.CreateReservation(ctx, &models.QueuedReservation{
+		ConsumerID: "consumer-002",
+		Filter:     models.OfferFilter{GPUType: "A100"},
+		Status:     models.ReservationWaiting,
+		CreatedAt:  now.Add(time.Second),
+		ExpiresAt:  now.Add(time.Hour),
+	}))
+
+	got, err := store.GetReservation(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "RTX 4090", got.Filter.GPUType)
+	assert.Equal(t, 0.50, got.Filter.MaxPrice)
+	assert.Equal(t, 2, got.Request.ReservationHrs)
+	// Fields kept out of the request JSON survive the round trip
+	assert.Equal(t, "sweep-1", got.Request.GroupID)
+	assert.Equal(t, "hf_example", got.Request.HFToken)
+	assert.Equal(t, 15*time.Minute, got.Request.TemplateRecommendedSSHTimeout)
+	assert.True(t, got.ExpiresAt.Equal(now.Add(time.Hour)))
+	assert.Nil(t, got.FulfilledAt)
+
+	waiting, err := store.ListReservations(ctx, "", models.ReservationWaiting)
+	require.NoError(t, err)
+	require.Len(t, waiting, 2)
+	assert.Equal(t, r.ID, waiting[0].ID)
+
+	fulfilledAt := now.Add(time.Minute)
+	got.Status = models.ReservationFulfilled
+	got.SessionID = "sess-1"
+	got.Attempts = 2
+	got.FulfilledAt = &fulfilledAt
+	require.NoError(t, store.UpdateReservation(ctx, got))
+
+	mine, err := store.ListReservations(ctx, "consumer-001", "")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	assert.Equal(t, models.ReservationFulfilled, mine[0].Status)
+	assert.Equal(t, "sess-1", mine[0].SessionID)
+	assert.Equal(t, 2, mine[0].Attempts)
+	require.NotNil(t, mine[0].FulfilledAt)
+
+	waiting, err = store.ListReservations(ctx, "", models.ReservationWaiting)
+	require.NoError(t, err)
+	assert.Len(t, waiting, 1)
+
+	_, err = store.GetReservation(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.UpdateReservation(ctx, &models.QueuedReservation{ID: "missing"}), ErrNotFound)
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// SessionEventStore reads the session status history. Status events are
+// written by database triggers on the sessions table, so every status change
+// is captured regardless of which code path made it; other events are
+// written through Record.
+type SessionEventStore struct {
+	db *DB
+}
+
+// NewSessionEventStore creates a new session event store
+func NewSessionEventStore(db *DB) *SessionEventStore {
+	return &SessionEventStore{db: db}
+}
+
+// Record appends a non-status event, such as a bootstrap script result, to a
+// session's history. The event's ID and creation time are set on success.
+func (s *SessionEventStore) Record(ctx context.Context, event *models.SessionEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO session_events (session_id, event_type, from_status, to_status, reason, output, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, event.SessionID, event.Type, event.FromStatus, event.ToStatus, event.Reason, event.Output, event.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record session event: %w", err)
+	}
+	event.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// ListBySession returns the events of a session, oldest first
+func (s *SessionEventStore) ListBySession(ctx context.Context, sessionID string) ([]*models.SessionEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, session_id, event_type, from_status, to_status, reason, output, created_at
+		FROM session_events
+		WHERE session_id = ?
+		ORDER BY id ASC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.SessionEvent
+	for rows.Next() {
+		var e models.SessionEvent
+		if err := rows.Scan(&e.ID, &e.SessionID, &e.Type, &e.FromStatus, &e.ToStatus, &e.Reason, &e.Output, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan session event: %w", err)
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
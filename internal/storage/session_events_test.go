@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionEventStore_RecordsTransitions(t *testing.T) {
+	db := newTestDB(t)
+	sessions := NewSessionStore(db)
+	events := NewSessionEventStore(db)
+	ctx := context.Background()
+
+	session := &models.Session{
+		ID:             "sess-events",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "offer-123",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusPending,
+		WorkloadType:   "ml-training",
+		ReservationHrs: 2,
+		StoragePolicy:  "destroy",
+		PricePerHour:   0.50,
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(2 * time.Hour),
+	}
+	require.NoError(t, sessions.Create(ctx, session))
+
+	session.Status = models.StatusProvisioning
+	require.NoError(t, sessions.Update(ctx, session))
+
+	// Updates that don't change status are not recorded
+	session.SSHHost = "10.0.0.1"
+	require.NoError(t, sessions.Update(ctx, session))
+
+	session.Status = models.StatusFailed
+	session.Error = "SSH verification timeout after 10m0s"
+	require.NoError(t, sessions.Update(ctx, session))
+
+	// Error unchanged on the way to stopped: no reason repeated
+	session.Status = models.StatusStopped
+	require.NoError(t, sessions.Update(ctx, session))
+
+	got, err := events.ListBySession(ctx, "sess-events")
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	assert.Equal(t, models.SessionStatus(""), got[0].FromStatus)
+	assert.Equal(t, models.StatusPending, got[0].ToStatus)
+
+	assert.Equal(t, models.StatusPending, got[1].FromStatus)
+	assert.Equal(t, models.StatusProvisioning, got[1].ToStatus)
+	assert.Empty(t, got[1].Reason)
+
+	assert.Equal(t, models.StatusProvisioning, got[2].FromStatus)
+	assert.Equal(t, models.StatusFailed, got[2].ToStatus)
+	assert.Equal(t, "SSH verification timeout after 10m0s", got[2].Reason)
+
+	assert.Equal(t, models.StatusFailed, got[3].FromStatus)
+	assert.Equal(t, models.StatusStopped, got[3].ToStatus)
+	assert.Empty(t, got[3].Reason)
+
+	for _, e := range got {
+		assert.Equal(t, models.SessionEventStatus, e.Type)
+		assert.Equal(t, "sess-events", e.SessionID)
+		assert.WithinDuration(t, time.Now(), e.CreatedAt, time.Minute)
+	}
+}
+
+func TestSessionEventStore_Record(t *testing.T) {
+	db := newTestDB(t)
+	sessions := NewSessionStore(db)
+	events := NewSessionEventStore(db)
+	ctx := context.Background()
+
+	session := &models.Session{
+		ID:             "sess-bootstrap",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "offer-123",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusRunning,
+		WorkloadType:   "ml-training",
+		ReservationHrs: 2,
+		StoragePolicy:  "destroy",
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(2 * time.Hour),
+	}
+	require.NoError(t, sessions.Create(ctx, session))
+
+	event := &models.SessionEvent{
+		SessionID:  "sess-bootstrap",
+		Type:       models.SessionEventBootstrap,
+		FromStatus: models.StatusRunning,
+		ToStatus:   models.StatusRunning,
+		Reason:     "bootstrap script failed: exit status 1",
+		Output:     "fatal: repository not found",
+	}
+	require.NoError(t, events.Record(ctx, event))
+	assert.NotZero(t, event.ID)
+
+	got, err := events.ListBySession(ctx, "sess-bootstrap")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, models.SessionEventStatus, got[0].Type)
+	assert.Equal(t, models.SessionEventBootstrap, got[1].Type)
+	assert.Equal(t, "fatal: repository not found", got[1].Output)
+	assert.Equal(t, "bootstrap script failed: exit status 1", got[1].Reason)
+
+	// Bootstrap events do not count as provisioning transitions
+	stats, err := sessions.GetProvisioningTimes(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestSessionEventStore_ListUnknownSession(t *testing.T) {
+	db := newTestDB(t)
+	events := NewSessionEventStore(db)
+
+	got, err := events.ListBySession(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestSessionStore_GetProvisioningTimes(t *testing.T) {
+	db := newTestDB(t)
+	sessions := NewSessionStore(db)
+	ctx := context.Background()
+
+	// Two RTX4090 sessions reach running after 60s and 120s; one A100 never does
+	for i, secs := range []int{60, 120, -1} {
+		gpu := "RTX4090"
+		if secs < 0 {
+			gpu = "A100"
+		}
+		session := &models.Session{
+			ID:             fmt.Sprintf("sess-%d", i),
+			ConsumerID:     "consumer-001",
+			Provider:       "vastai",
+			OfferID:        fmt.Sprintf("offer-%d", i),
+			GPUType:        gpu,
+			GPUCount:       1,
+			Status:         models.StatusProvisioning,
+			WorkloadType:   "llm",
+			ReservationHrs: 1,
+			StoragePolicy:  "destroy",
+			CreatedAt:      time.Now(),
+			ExpiresAt:      time.Now().Add(time.Hour),
+		}
+		require.NoError(t, sessions.Create(ctx, session))
+		if secs < 0 {
+			continue
+		}
+		session.Status = models.StatusRunning
+		require.NoError(t, sessions.Update(ctx, session))
+
+		// Backdate the creation event so the transition took secs
+		_, err := db.ExecContext(ctx, `UPDATE session_events
+			SET created_at = strftime('%Y-%m-%d %H:%M:%f', 'now', ?)
+			WHERE session_id = ? AND from_status = ''`, fmt.Sprintf("-%d seconds", secs), session.ID)
+		require.NoError(t, err)
+	}
+
+	stats, err := sessions.GetProvisioningTimes(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "vastai", stats[0].Provider)
+	assert.Equal(t, "RTX4090", stats[0].GPUType)
+	assert.Equal(t, 2, stats[0].Samples)
+	assert.InDelta(t, 90, stats[0].AvgSeconds, 1)
+
+	// Nothing recorded since
+	stats, err = sessions.GetProvisioningTimes(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
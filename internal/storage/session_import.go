@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+)
+
+// Import writes a session exported from another deployment together with its
+// status history and cost records, in a single transaction. The history
+// recorded by the session triggers is replaced with the exported events so
+// the original timeline is preserved. Returns ErrAlreadyExists if the session
+// is already present.
+func (s *SessionStore) Import(ctx context.Context, session *models.Session, events []*models.SessionEvent, costs []models.CostRecord) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existing string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM sessions WHERE id = ?`, session.ID).Scan(&existing)
+	if err == nil {
+		return ErrAlreadyExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check session: %w", err)
+	}
+
+	sealed, err := s.db.sealSessionSecrets(session)
+	if err != nil {
+		return err
+	}
+	if err := insertSession(ctx, tx, session, sealed); err != nil {
+		return fmt.Errorf("failed to import session: %w", err)
+	}
+
+	if len(events) > 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM session_events WHERE session_id = ?`, session.ID); err != nil {
+			return fmt.Errorf("failed to clear session events: %w", err)
+		}
+		for _, e := range events {
+			eventType := e.Type
+			if eventType == "" {
+				eventType = models.SessionEventStatus // Exported before events had types
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO session_events (session_id, event_type, from_status, to_status, reason, output, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, session.ID, eventType, e.FromStatus, e.ToStatus, e.Reason, e.Output, e.CreatedAt.UTC()); err != nil {
+				return fmt.Errorf("failed to import session event: %w", err)
+			}
+		}
+	}
+
+	for _, c := range costs {
+		if c.ID == "" {
+			c.ID = uuid.New().String()
+		}
+		if c.Category == "" {
+			c.Category = models.CostCategoryGPU
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO costs (id, session_id, consumer_id, provider, gpu_type, category, hour, amount, currency)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(session_id, hour, category) DO NOTHING
+		`, c.ID, session.ID, c.ConsumerID, c.Provider, c.GPUType, c.Category, c.Hour, c.Amount, c.Currency); err != nil {
+			return fmt.Errorf("failed to import cost record: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session import: %w", err)
+	}
+	return nil
+}
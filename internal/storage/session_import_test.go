@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStore_ImportRoundTrip(t *testing.T) {
+	src := newTestDB(t)
+	srcSessions := NewSessionStore(src)
+	srcCosts := NewCostStore(src)
+	ctx := context.Background()
+
+	session := createTestSession(t, srcSessions, "sess-export")
+	session.Status = models.StatusStopped
+	session.StoppedAt = time.Now()
+	session.SSHPublicKey = "ssh-rsa AAAA test"
+	require.NoError(t, srcSessions.Update(ctx, session))
+	hour := time.Now().UTC().Truncate(time.Hour)
+	require.NoError(t, srcCosts.Record(ctx, &models.CostRecord{
+		SessionID: session.ID, ConsumerID: session.ConsumerID, Provider: "vastai",
+		GPUType: "RTX4090", Hour: hour, Amount: 0.50, Currency: "USD",
+	}))
+
+	exported, err := srcSessions.Get(ctx, session.ID)
+	require.NoError(t, err)
+	events, err := NewSessionEventStore(src).ListBySession(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	costs, err := srcCosts.ListBySession(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, costs, 1)
+
+	dst := newTestDB(t)
+	dstSessions := NewSessionStore(dst)
+	require.NoError(t, dstSessions.Import(ctx, exported, events, costs))
+
+	imported, err := dstSessions.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusStopped, imported.Status)
+	assert.Equal(t, "ssh-rsa AAAA test", imported.SSHPublicKey)
+
+	// The exported history replaces the one recorded by the insert trigger
+	importedEvents, err := NewSessionEventStore(dst).ListBySession(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, importedEvents, 2)
+	assert.Equal(t, models.StatusRunning, importedEvents[0].ToStatus)
+	assert.Equal(t, models.StatusStopped, importedEvents[1].ToStatus)
+	assert.WithinDuration(t, events[0].CreatedAt, importedEvents[0].CreatedAt, time.Millisecond)
+
+	total, err := NewCostStore(dst).GetSessionCost(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0.50, total)
+
+	assert.ErrorIs(t, dstSessions.Import(ctx, exported, events, costs), ErrAlreadyExists)
+}
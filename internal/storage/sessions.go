@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -22,6 +23,62 @@ func NewSessionStore(db *DB) *SessionStore {
 
 // Create inserts a new session
 func (s *SessionStore) Create(ctx context.Context, session *models.Session) error {
+	sealed, err := s.db.sealSessionSecrets(session)
+	if err != nil {
+		return err
+	}
+	err = insertSession(ctx, s.db, session, sealed)
+	if err != nil {
+		// Bug #47 fix: Detect SQLite UNIQUE constraint violation for duplicate active sessions
+		// This catches races where two requests pass the app-level check simultaneously
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") &&
+			strings.Contains(err.Error(), "idx_sessions_consumer_offer_active") {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// execer is satisfied by both *DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// sessionSecrets are a session's secret column values, sealed for storage
+type sessionSecrets struct {
+	hfToken    string
+	onStartCmd string
+	cloudInit  string // JSON
+}
+
+// sealSessionSecrets seals a session's HuggingFace token, start-up command
+// and cloud-init
+func (db *DB) sealSessionSecrets(session *models.Session) (sessionSecrets, error) {
+	var sealed sessionSecrets
+	var err error
+	if sealed.hfToken, err = db.seal(session.HFToken); err != nil {
+		return sealed, err
+	}
+	if sealed.onStartCmd, err = db.seal(session.OnStartCmd); err != nil {
+		return sealed, err
+	}
+	if !session.CloudInit.IsEmpty() {
+		cloudInit, err := json.Marshal(session.CloudInit)
+		if err != nil {
+			return sealed, fmt.Errorf("failed to marshal cloud-init: %w", err)
+		}
+		if sealed.cloudInit, err = db.seal(string(cloudInit)); err != nil {
+			return sealed, err
+		}
+	}
+	return sealed, nil
+}
+
+// insertSession writes a session row, with its secrets as sealed by the
+// caller
+func insertSession(ctx context.Context, db execer, session *models.Session, sealed sessionSecrets) error {
 	query := `
 		INSERT INTO sessions (
 			id, consumer_id, provider, provider_instance_id, offer_id,
@@ -31,7 +88,14 @@ func (s *SessionStore) Create(ctx context.Context, session *models.Session) erro
 			idle_threshold_minutes, storage_policy,
 			price_per_hour, created_at, expires_at, stopped_at,
 			auto_retry, max_retries, retry_scope,
-			retry_count, retry_parent_id, retry_child_id, failed_offers
+			retry_count, retry_parent_id, retry_child_id, failed_offers,
+			interruptible, bid_price, group_id, idle_gpu_util_pct,
+			location, failure_category, failure_detail,
+			ssh_host_key_fingerprint, cuda_version, driver_version, machine_id,
+			provision_phase, verify_deadline, launch_mode, api_port, api_endpoint,
+			instance_created_at, ip_assigned_at, cloud_init_done_at, ssh_verified_at,
+			bootstrap_script, image_pulled_at, container_started_at, weights_loaded_at,
+			gpu_burn_in, vram_gb, model_id, hf_token, on_start_cmd, cloud_init
 		) VALUES (
 			?, ?, ?, ?, ?,
 			?, ?, ?, ?,
@@ -40,11 +104,18 @@ func (s *SessionStore) Create(ctx context.Context, session *models.Session) erro
 			?, ?,
 			?, ?, ?, ?,
 			?, ?, ?,
-			?, ?, ?, ?
+			?, ?, ?, ?,
+			?, ?, ?, ?,
+			?, ?, ?,
+			?, ?, ?, ?,
+			?, ?, ?, ?, ?,
+			?, ?, ?, ?,
+			?, ?, ?, ?,
+			?, ?, ?, ?, ?, ?
 		)
 	`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		session.ID, session.ConsumerID, session.Provider, session.ProviderID, session.OfferID,
 		session.GPUType, session.GPUCount, session.Status, session.Error,
 		session.SSHHost, session.SSHPort, session.SSHUser, session.SSHPublicKey,
@@ -53,19 +124,17 @@ func (s *SessionStore) Create(ctx context.Context, session *models.Session) erro
 		session.PricePerHour, session.CreatedAt, session.ExpiresAt, nullTime(session.StoppedAt),
 		session.AutoRetry, session.MaxRetries, session.RetryScope,
 		session.RetryCount, session.RetryParentID, session.RetryChildID, session.FailedOffers,
+		session.Interruptible, session.BidPrice, session.GroupID, session.IdleGPUUtilPct,
+		session.Location, session.FailureCategory, session.FailureDetail,
+		session.SSHHostKeyFingerprint, session.CUDAVersion, session.DriverVersion, session.MachineID,
+		session.ProvisionPhase, nullTime(session.VerifyDeadline), session.LaunchMode, session.APIPort, session.APIEndpoint,
+		nullTime(session.Progress.InstanceCreatedAt), nullTime(session.Progress.IPAssignedAt),
+		nullTime(session.Progress.CloudInitDoneAt), nullTime(session.Progress.SSHVerifiedAt),
+		session.BootstrapScript, nullTime(session.Progress.ImagePulledAt),
+		nullTime(session.Progress.ContainerStartedAt), nullTime(session.Progress.WeightsLoadedAt),
+		session.GPUBurnIn, session.VRAM, session.ModelID, sealed.hfToken, sealed.onStartCmd, sealed.cloudInit,
 	)
-
-	if err != nil {
-		// Bug #47 fix: Detect SQLite UNIQUE constraint violation for duplicate active sessions
-		// This catches races where two requests pass the app-level check simultaneously
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") &&
-			strings.Contains(err.Error(), "idx_sessions_consumer_offer_active") {
-			return ErrAlreadyExists
-		}
-		return fmt.Errorf("failed to create session: %w", err)
-	}
-
-	return nil
+	return err
 }
 
 // sessionColumns is the list of columns for session queries
@@ -77,18 +146,49 @@ const sessionColumns = `
 	idle_threshold_minutes, storage_policy,
 	price_per_hour, created_at, expires_at, stopped_at,
 	auto_retry, max_retries, retry_scope,
-	retry_count, retry_parent_id, retry_child_id, failed_offers
+	retry_count, retry_parent_id, retry_child_id, failed_offers,
+	interruptible, bid_price, group_id, idle_gpu_util_pct,
+	health_status, last_heartbeat_at, gpu_util_pct, idle_since,
+	location, failure_category, failure_detail,
+	ssh_host_key_fingerprint, cuda_version, driver_version,
+	measured_inet_down_mbps, measured_disk_bw_mbps, machine_id,
+	provision_phase, verify_deadline, launch_mode, api_port, api_endpoint,
+	instance_created_at, ip_assigned_at, cloud_init_done_at, ssh_verified_at,
+	bootstrap_script, image_pulled_at, container_started_at, weights_loaded_at,
+	gpu_burn_in, vram_gb, model_id, hf_token,
+	paused_at, paused_seconds, on_start_cmd, cloud_init, rebooting_until
 `
 
-// scanSession scans a row into a Session model, handling nullable fields
+// scanSession scans a row into a Session model, handling nullable fields,
+// and returns its secret columns still sealed
 func scanSession(scanner interface {
 	Scan(dest ...interface{}) error
-}) (*models.Session, error) {
+}) (*models.Session, sessionSecrets, error) {
 	session := &models.Session{}
 	var stoppedAt sql.NullTime
 	var providerID, sshHost, sshUser, sshPublicKey, errorStr sql.NullString
 	var sshPort sql.NullInt64
 	var retryScope, retryParentID, retryChildID, failedOffers sql.NullString
+	var interruptible sql.NullBool
+	var bidPrice sql.NullFloat64
+	var groupID sql.NullString
+	var idleGPUUtilPct sql.NullInt64
+	var healthStatus sql.NullString
+	var lastHeartbeatAt, idleSince sql.NullTime
+	var gpuUtilPct sql.NullFloat64
+	var location, failureCategory, failureDetail sql.NullString
+	var sshHostKeyFingerprint, driverVersion, machineID sql.NullString
+	var cudaVersion, measuredInetDown, measuredDiskBandwidth sql.NullFloat64
+	var provisionPhase, launchMode, apiEndpoint, bootstrapScript, modelID, hfToken sql.NullString
+	var onStartCmd, cloudInit sql.NullString
+	var verifyDeadline sql.NullTime
+	var instanceCreatedAt, ipAssignedAt, cloudInitDoneAt, sshVerifiedAt sql.NullTime
+	var imagePulledAt, containerStartedAt, weightsLoadedAt sql.NullTime
+	var gpuBurnIn sql.NullBool
+	var apiPort, vram sql.NullInt64
+	var pausedAt sql.NullTime
+	var pausedSeconds sql.NullInt64
+	var rebootingUntil sql.NullTime
 
 	err := scanner.Scan(
 		&session.ID, &session.ConsumerID, &session.Provider, &providerID, &session.OfferID,
@@ -99,9 +199,19 @@ func scanSession(scanner interface {
 		&session.PricePerHour, &session.CreatedAt, &session.ExpiresAt, &stoppedAt,
 		&session.AutoRetry, &session.MaxRetries, &retryScope,
 		&session.RetryCount, &retryParentID, &retryChildID, &failedOffers,
+		&interruptible, &bidPrice, &groupID, &idleGPUUtilPct,
+		&healthStatus, &lastHeartbeatAt, &gpuUtilPct, &idleSince,
+		&location, &failureCategory, &failureDetail,
+		&sshHostKeyFingerprint, &cudaVersion, &driverVersion,
+		&measuredInetDown, &measuredDiskBandwidth, &machineID,
+		&provisionPhase, &verifyDeadline, &launchMode, &apiPort, &apiEndpoint,
+		&instanceCreatedAt, &ipAssignedAt, &cloudInitDoneAt, &sshVerifiedAt,
+		&bootstrapScript, &imagePulledAt, &containerStartedAt, &weightsLoadedAt,
+		&gpuBurnIn, &vram, &modelID, &hfToken,
+		&pausedAt, &pausedSeconds, &onStartCmd, &cloudInit, &rebootingUntil,
 	)
 	if err != nil {
-		return nil, err
+		return nil, sessionSecrets{}, err
 	}
 
 	session.ProviderID = providerID.String
@@ -114,10 +224,81 @@ func scanSession(scanner interface {
 	session.RetryParentID = retryParentID.String
 	session.RetryChildID = retryChildID.String
 	session.FailedOffers = failedOffers.String
+	session.Interruptible = interruptible.Bool
+	session.BidPrice = bidPrice.Float64
+	session.GroupID = groupID.String
+	session.IdleGPUUtilPct = int(idleGPUUtilPct.Int64)
+	session.Health.Status = models.HealthStatus(healthStatus.String)
+	session.Health.LastHeartbeatAt = lastHeartbeatAt.Time
+	session.Health.IdleSince = idleSince.Time
+	session.Location = location.String
+	session.FailureCategory = models.FailureCategory(failureCategory.String)
+	session.FailureDetail = failureDetail.String
+	session.SSHHostKeyFingerprint = sshHostKeyFingerprint.String
+	session.CUDAVersion = cudaVersion.Float64
+	session.DriverVersion = driverVersion.String
+	session.MeasuredInetDownMbps = measuredInetDown.Float64
+	session.MeasuredDiskBandwidthMBps = measuredDiskBandwidth.Float64
+	session.MachineID = machineID.String
+	session.ProvisionPhase = models.ProvisionPhase(provisionPhase.String)
+	session.VerifyDeadline = verifyDeadline.Time
+	session.LaunchMode = models.LaunchMode(launchMode.String)
+	session.APIPort = int(apiPort.Int64)
+	session.APIEndpoint = apiEndpoint.String
+	session.Progress.InstanceCreatedAt = instanceCreatedAt.Time
+	session.Progress.IPAssignedAt = ipAssignedAt.Time
+	session.Progress.CloudInitDoneAt = cloudInitDoneAt.Time
+	session.Progress.SSHVerifiedAt = sshVerifiedAt.Time
+	session.BootstrapScript = bootstrapScript.String
+	session.Progress.ImagePulledAt = imagePulledAt.Time
+	session.Progress.ContainerStartedAt = containerStartedAt.Time
+	session.Progress.WeightsLoadedAt = weightsLoadedAt.Time
+	session.GPUBurnIn = gpuBurnIn.Bool
+	session.VRAM = int(vram.Int64)
+	session.ModelID = modelID.String
+	session.PausedAt = pausedAt.Time
+	session.PausedTotal = time.Duration(pausedSeconds.Int64) * time.Second
+	session.RebootingUntil = rebootingUntil.Time
+	if gpuUtilPct.Valid {
+		pct := gpuUtilPct.Float64
+		session.Health.GPUUtilPct = &pct
+	}
 	if stoppedAt.Valid {
 		session.StoppedAt = stoppedAt.Time
 	}
 
+	sealed := sessionSecrets{
+		hfToken:    hfToken.String,
+		onStartCmd: onStartCmd.String,
+		cloudInit:  cloudInit.String,
+	}
+	return session, sealed, nil
+}
+
+// scan scans a session row and decrypts its secrets
+func (s *SessionStore) scan(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Session, error) {
+	session, sealed, err := scanSession(scanner)
+	if err != nil {
+		return nil, err
+	}
+	if session.HFToken, err = s.db.open(sealed.hfToken); err != nil {
+		return nil, err
+	}
+	if session.OnStartCmd, err = s.db.open(sealed.onStartCmd); err != nil {
+		return nil, err
+	}
+	cloudInit, err := s.db.open(sealed.cloudInit)
+	if err != nil {
+		return nil, err
+	}
+	if cloudInit != "" {
+		session.CloudInit = &models.CloudInitConfig{}
+		if err := json.Unmarshal([]byte(cloudInit), session.CloudInit); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cloud-init: %w", err)
+		}
+	}
 	return session, nil
 }
 
@@ -125,7 +306,7 @@ func scanSession(scanner interface {
 func (s *SessionStore) Get(ctx context.Context, id string) (*models.Session, error) {
 	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = ?`
 
-	session, err := scanSession(s.db.QueryRowContext(ctx, query, id))
+	session, err := s.scan(s.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
@@ -146,13 +327,30 @@ func (s *SessionStore) Update(ctx context.Context, session *models.Session) erro
 			ssh_host = ?,
 			ssh_port = ?,
 			ssh_user = ?,
+			ssh_public_key = ?,
+			ssh_host_key_fingerprint = ?,
 			hard_max_override = ?,
 			reservation_hours = ?,
 			expires_at = ?,
 			stopped_at = ?,
 			retry_count = ?,
 			retry_child_id = ?,
-			failed_offers = ?
+			failed_offers = ?,
+			failure_category = ?,
+			failure_detail = ?,
+			provision_phase = ?,
+			verify_deadline = ?,
+			api_port = ?,
+			api_endpoint = ?,
+			instance_created_at = ?,
+			ip_assigned_at = ?,
+			cloud_init_done_at = ?,
+			ssh_verified_at = ?,
+			image_pulled_at = ?,
+			container_started_at = ?,
+			weights_loaded_at = ?,
+			paused_at = ?,
+			paused_seconds = ?
 		WHERE id = ?
 	`
 
@@ -163,6 +361,8 @@ func (s *SessionStore) Update(ctx context.Context, session *models.Session) erro
 		session.SSHHost,
 		session.SSHPort,
 		session.SSHUser,
+		session.SSHPublicKey,
+		session.SSHHostKeyFingerprint,
 		session.HardMaxOverride,
 		session.ReservationHrs,
 		session.ExpiresAt,
@@ -170,6 +370,21 @@ func (s *SessionStore) Update(ctx context.Context, session *models.Session) erro
 		session.RetryCount,
 		session.RetryChildID,
 		session.FailedOffers,
+		session.FailureCategory,
+		session.FailureDetail,
+		session.ProvisionPhase,
+		nullTime(session.VerifyDeadline),
+		session.APIPort,
+		session.APIEndpoint,
+		nullTime(session.Progress.InstanceCreatedAt),
+		nullTime(session.Progress.IPAssignedAt),
+		nullTime(session.Progress.CloudInitDoneAt),
+		nullTime(session.Progress.SSHVerifiedAt),
+		nullTime(session.Progress.ImagePulledAt),
+		nullTime(session.Progress.ContainerStartedAt),
+		nullTime(session.Progress.WeightsLoadedAt),
+		nullTime(session.PausedAt),
+		int64(session.PausedTotal/time.Second),
 		session.ID,
 	)
 
@@ -188,6 +403,79 @@ func (s *SessionStore) Update(ctx context.Context, session *models.Session) erro
 	return nil
 }
 
+// UpdateThroughput records the network and disk throughput measured on a
+// session's instance. Zero values leave the stored measurement unchanged.
+func (s *SessionStore) UpdateThroughput(ctx context.Context, sessionID string, inetDownMbps, diskBandwidthMBps float64) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE sessions SET
+			measured_inet_down_mbps = CASE WHEN ? > 0 THEN ? ELSE measured_inet_down_mbps END,
+			measured_disk_bw_mbps = CASE WHEN ? > 0 THEN ? ELSE measured_disk_bw_mbps END
+		WHERE id = ?
+	`, inetDownMbps, inetDownMbps, diskBandwidthMBps, diskBandwidthMBps, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to update session throughput: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetRebootingUntil marks a session's instance as rebooting in place until
+// the given time, or no longer rebooting if it is zero. It is kept apart
+// from Update so saving other changes never drops the mark.
+func (s *SessionStore) SetRebootingUntil(ctx context.Context, sessionID string, until time.Time) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE sessions SET rebooting_until = ? WHERE id = ?`, nullTime(until), sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to update session reboot: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateHealth records a session's latest heartbeat. It is kept apart from
+// Update so heartbeats never overwrite a concurrent status change.
+func (s *SessionStore) UpdateHealth(ctx context.Context, sessionID string, health models.SessionHealth) error {
+	var gpuUtilPct sql.NullFloat64
+	if health.GPUUtilPct != nil {
+		gpuUtilPct = sql.NullFloat64{Float64: *health.GPUUtilPct, Valid: true}
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE sessions SET
+			health_status = ?,
+			last_heartbeat_at = ?,
+			gpu_util_pct = ?,
+			idle_since = ?
+		WHERE id = ?
+	`, health.Status, nullTime(health.LastHeartbeatAt), gpuUtilPct, nullTime(health.IdleSince), sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to update session health: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // ListInternal returns sessions matching the internal filter (used by lifecycle and other internal services)
 func (s *SessionStore) ListInternal(ctx context.Context, filter SessionFilter) ([]*models.Session, error) {
 	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE 1=1`
@@ -227,11 +515,72 @@ func (s *SessionStore) ListInternal(ctx context.Context, filter SessionFilter) (
 		query += " AND provider_instance_id != ''"
 	}
 
-	query += " ORDER BY created_at DESC"
+	if filter.GroupID != "" {
+		query += " AND group_id = ?"
+		args = append(args, filter.GroupID)
+	}
+
+	if filter.Health != "" {
+		query += " AND health_status = ?"
+		args = append(args, filter.Health)
+	}
+
+	if filter.ExcludeTerminal {
+		query += " AND status NOT IN (?, ?, ?)"
+		args = append(args, models.StatusStopped, models.StatusFailed, models.StatusPreempted)
+	}
+
+	if filter.GPUType != "" {
+		query += " AND gpu_type = ? COLLATE NOCASE"
+		args = append(args, filter.GPUType)
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.CreatedAfter)
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		query += " AND created_at < ?"
+		args = append(args, filter.CreatedBefore)
+	}
+
+	if filter.Search != "" {
+		if s.db.fullTextSearch {
+			query += " AND id IN (SELECT session_id FROM sessions_fts WHERE sessions_fts MATCH ?)"
+			args = append(args, ftsPhrase(filter.Search))
+		} else {
+			query += ` AND (IFNULL(error, '') LIKE ? ESCAPE '\' OR IFNULL(failure_detail, '') LIKE ? ESCAPE '\'` +
+				` OR offer_id LIKE ? ESCAPE '\' OR IFNULL(provider_instance_id, '') LIKE ? ESCAPE '\' OR consumer_id LIKE ? ESCAPE '\')`
+			pattern := likeContains(filter.Search)
+			args = append(args, pattern, pattern, pattern, pattern, pattern)
+		}
+	}
+
+	direction := "DESC"
+	if filter.SortAscending {
+		direction = "ASC"
+	}
+	switch filter.SortBy {
+	case models.SessionSortPrice:
+		query += " ORDER BY price_per_hour " + direction + ", created_at DESC"
+	case models.SessionSortCost:
+		query += " ORDER BY (SELECT COALESCE(SUM(amount), 0) FROM costs WHERE costs.session_id = sessions.id) " + direction + ", created_at DESC"
+	default:
+		query += " ORDER BY created_at " + direction
+	}
+	// id breaks ties so pages don't overlap
+	query += ", id"
 
 	if filter.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
 	}
+	if filter.Offset > 0 {
+		if filter.Limit <= 0 {
+			query += " LIMIT -1"
+		}
+		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
+	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -241,7 +590,7 @@ func (s *SessionStore) ListInternal(ctx context.Context, filter SessionFilter) (
 
 	var sessions []*models.Session
 	for rows.Next() {
-		session, err := scanSession(rows)
+		session, err := s.scan(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
@@ -265,7 +614,8 @@ func (s *SessionStore) GetActiveSessions(ctx context.Context) ([]*models.Session
 	})
 }
 
-// GetActiveSessionsByProvider returns active sessions for a specific provider
+// GetActiveSessionsByProvider returns active sessions for a specific
+// provider, including paused ones, whose instances still exist
 func (s *SessionStore) GetActiveSessionsByProvider(ctx context.Context, provider string) ([]*models.Session, error) {
 	return s.ListInternal(ctx, SessionFilter{
 		Provider: provider,
@@ -273,6 +623,7 @@ func (s *SessionStore) GetActiveSessionsByProvider(ctx context.Context, provider
 			models.StatusPending,
 			models.StatusProvisioning,
 			models.StatusRunning,
+			models.StatusPaused,
 		},
 	})
 }
@@ -296,6 +647,18 @@ func (s *SessionStore) GetFailedSessionsWithInstances(ctx context.Context) ([]*m
 	})
 }
 
+// GetActiveSessionsByGroup returns the active sessions in a session group
+func (s *SessionStore) GetActiveSessionsByGroup(ctx context.Context, groupID string) ([]*models.Session, error) {
+	return s.ListInternal(ctx, SessionFilter{
+		Statuses: []models.SessionStatus{
+			models.StatusPending,
+			models.StatusProvisioning,
+			models.StatusRunning,
+		},
+		GroupID: groupID,
+	})
+}
+
 // GetSessionsByStatus returns sessions with specific statuses
 func (s *SessionStore) GetSessionsByStatus(ctx context.Context, statuses ...models.SessionStatus) ([]*models.Session, error) {
 	return s.ListInternal(ctx, SessionFilter{
@@ -310,7 +673,18 @@ func (s *SessionStore) List(ctx context.Context, filter models.SessionListFilter
 		ConsumerID: filter.ConsumerID,
 		Provider:   filter.Provider,
 		Status:     filter.Status,
+		GroupID:    filter.GroupID,
+		Health:     filter.Health,
 		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+
+		GPUType:         filter.GPUType,
+		CreatedAfter:    filter.CreatedAfter,
+		CreatedBefore:   filter.CreatedBefore,
+		ExcludeTerminal: filter.ExcludeTerminal,
+		Search:          filter.Search,
+		SortBy:          filter.SortBy,
+		SortAscending:   filter.SortAscending,
 	})
 }
 
@@ -322,7 +696,29 @@ type SessionFilter struct {
 	Statuses          []models.SessionStatus
 	ExpiresBeforeTime time.Time
 	HasProviderID     bool
+	GroupID           string
+	Health            models.HealthStatus
+	GPUType           string
+	CreatedAfter      time.Time
+	CreatedBefore     time.Time
 	Limit             int
+	Offset            int
+	ExcludeTerminal   bool
+	Search            string
+	SortBy            models.SessionSortField
+	SortAscending     bool
+}
+
+// ftsPhrase quotes a search as a single FTS5 phrase, so that its words
+// must appear in order and operators in it are matched as plain text
+func ftsPhrase(search string) string {
+	return `"` + strings.ReplaceAll(search, `"`, `""`) + `"`
+}
+
+// likeContains builds a LIKE pattern matching search anywhere in a value
+func likeContains(search string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search)
+	return "%" + escaped + "%"
 }
 
 // nullTime converts a time to sql.NullTime
@@ -365,6 +761,71 @@ func (s *SessionStore) CountSessionsByProviderAndStatus(ctx context.Context) ([]
 	return counts, rows.Err()
 }
 
+// GetFailureCounts counts sessions created in [start, end) by provider, GPU
+// type, location and failure category. Sessions that did not fail have an
+// empty category; failed or preempted sessions recorded before categories
+// existed are counted as unknown or preempted.
+func (s *SessionStore) GetFailureCounts(ctx context.Context, start, end time.Time) ([]models.FailureCount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT provider, gpu_type, COALESCE(location, ''),
+			CASE
+				WHEN COALESCE(failure_category, '') != '' THEN failure_category
+				WHEN status = 'preempted' THEN 'preempted'
+				WHEN status = 'failed' THEN 'unknown'
+				ELSE ''
+			END AS category,
+			COUNT(*)
+		FROM sessions
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY provider, gpu_type, COALESCE(location, ''), category
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count session failures: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []models.FailureCount
+	for rows.Next() {
+		var c models.FailureCount
+		if err := rows.Scan(&c.Provider, &c.GPUType, &c.Location, &c.Category, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan failure count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetProvisioningTimes returns the average time sessions created since the
+// given time took to become running (SSH verified), grouped by provider and
+// GPU type. Times come from the session status history.
+func (s *SessionStore) GetProvisioningTimes(ctx context.Context, since time.Time) ([]models.ProvisioningTimeStat, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.provider, s.gpu_type, COUNT(*),
+			AVG((julianday(r.created_at) - julianday(c.created_at)) * 86400)
+		FROM session_events r
+		JOIN session_events c ON c.session_id = r.session_id AND c.from_status = ''
+		JOIN sessions s ON s.id = r.session_id
+		WHERE r.from_status = 'provisioning' AND r.to_status = 'running'
+			AND c.created_at >= ?
+			AND julianday(r.created_at) IS NOT NULL AND julianday(c.created_at) IS NOT NULL
+		GROUP BY s.provider, s.gpu_type
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provisioning times: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.ProvisioningTimeStat
+	for rows.Next() {
+		var st models.ProvisioningTimeStat
+		if err := rows.Scan(&st.Provider, &st.GPUType, &st.Samples, &st.AvgSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan provisioning time: %w", err)
+		}
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
+
 // GetActiveSessionByConsumerAndOffer returns an active session for the given consumer and offer, if one exists.
 // Active sessions are those with status pending, provisioning, or running.
 // Returns ErrNotFound if no active session exists.
@@ -377,7 +838,7 @@ func (s *SessionStore) GetActiveSessionByConsumerAndOffer(ctx context.Context, c
 		LIMIT 1
 	`
 
-	session, err := scanSession(s.db.QueryRowContext(ctx, query, consumerID, offerID))
+	session, err := s.scan(s.db.QueryRowContext(ctx, query, consumerID, offerID))
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
@@ -17,19 +17,28 @@ func TestSessionStore_Create(t *testing.T) {
 	ctx := context.Background()
 
 	session := &models.Session{
-		ID:             "sess-001",
-		ConsumerID:     "consumer-001",
-		Provider:       "vastai",
-		OfferID:        "offer-123",
-		GPUType:        "RTX4090",
-		GPUCount:       1,
-		Status:         models.StatusPending,
-		WorkloadType:   "ml-training",
-		ReservationHrs: 4,
-		StoragePolicy:  "destroy",
-		PricePerHour:   0.50,
-		CreatedAt:      time.Now(),
-		ExpiresAt:      time.Now().Add(4 * time.Hour),
+		ID:              "sess-001",
+		ConsumerID:      "consumer-001",
+		Provider:        "vastai",
+		OfferID:         "offer-123",
+		GPUType:         "RTX4090",
+		GPUCount:        1,
+		VRAM:            24,
+		Status:          models.StatusPending,
+		WorkloadType:    "ml-training",
+		ReservationHrs:  4,
+		StoragePolicy:   "destroy",
+		PricePerHour:    0.50,
+		CUDAVersion:     12.4,
+		MachineID:       "vastai-machine-42",
+		DriverVersion:   "550.54.14",
+		ProvisionPhase:  models.PhasePending,
+		LaunchMode:      models.LaunchModeSSH,
+		CreatedAt:       time.Now(),
+		ExpiresAt:       time.Now().Add(4 * time.Hour),
+		BootstrapScript: "git clone https://example.com/repo.git",
+		GPUBurnIn:       true,
+		ModelID:         "org/model",
 	}
 
 	err := store.Create(ctx, session)
@@ -43,6 +52,16 @@ func TestSessionStore_Create(t *testing.T) {
 	assert.Equal(t, session.Provider, retrieved.Provider)
 	assert.Equal(t, session.GPUType, retrieved.GPUType)
 	assert.Equal(t, session.Status, retrieved.Status)
+	assert.Equal(t, 12.4, retrieved.CUDAVersion)
+	assert.Equal(t, 24, retrieved.VRAM)
+	assert.Equal(t, "vastai-machine-42", retrieved.MachineID)
+	assert.Equal(t, "550.54.14", retrieved.DriverVersion)
+	assert.Equal(t, models.PhasePending, retrieved.ProvisionPhase)
+	assert.Equal(t, models.LaunchModeSSH, retrieved.LaunchMode)
+	assert.True(t, retrieved.VerifyDeadline.IsZero())
+	assert.Equal(t, "git clone https://example.com/repo.git", retrieved.BootstrapScript)
+	assert.True(t, retrieved.GPUBurnIn)
+	assert.Equal(t, "org/model", retrieved.ModelID)
 }
 
 func TestSessionStore_Get_NotFound(t *testing.T) {
@@ -84,6 +103,15 @@ func TestSessionStore_Update(t *testing.T) {
 	session.SSHHost = "192.168.1.100"
 	session.SSHPort = 22
 	session.SSHUser = "root"
+	session.SSHPublicKey = "ssh-ed25519 AAAA rotated"
+	session.SSHHostKeyFingerprint = "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"
+	session.ProvisionPhase = models.PhaseVerifying
+	session.VerifyDeadline = time.Now().Add(8 * time.Minute).Truncate(time.Second)
+	session.APIPort = 8000
+	session.APIEndpoint = "http://192.168.1.100:8000"
+	session.Progress.InstanceCreatedAt = time.Now().Add(-2 * time.Minute).Truncate(time.Second)
+	session.Progress.IPAssignedAt = time.Now().Add(-time.Minute).Truncate(time.Second)
+	session.Progress.ContainerStartedAt = time.Now().Add(-30 * time.Second).Truncate(time.Second)
 
 	err = store.Update(ctx, session)
 	require.NoError(t, err)
@@ -95,6 +123,17 @@ func TestSessionStore_Update(t *testing.T) {
 	assert.Equal(t, "provider-instance-123", retrieved.ProviderID)
 	assert.Equal(t, "192.168.1.100", retrieved.SSHHost)
 	assert.Equal(t, 22, retrieved.SSHPort)
+	assert.Equal(t, "ssh-ed25519 AAAA rotated", retrieved.SSHPublicKey)
+	assert.Equal(t, "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8", retrieved.SSHHostKeyFingerprint)
+	assert.Equal(t, models.PhaseVerifying, retrieved.ProvisionPhase)
+	assert.True(t, session.VerifyDeadline.Equal(retrieved.VerifyDeadline))
+	assert.Equal(t, 8000, retrieved.APIPort)
+	assert.Equal(t, "http://192.168.1.100:8000", retrieved.APIEndpoint)
+	assert.True(t, session.Progress.InstanceCreatedAt.Equal(retrieved.Progress.InstanceCreatedAt))
+	assert.True(t, session.Progress.IPAssignedAt.Equal(retrieved.Progress.IPAssignedAt))
+	assert.True(t, session.Progress.ContainerStartedAt.Equal(retrieved.Progress.ContainerStartedAt))
+	assert.True(t, retrieved.Progress.WeightsLoadedAt.IsZero())
+	assert.True(t, retrieved.Progress.SSHVerifiedAt.IsZero())
 }
 
 func TestSessionStore_Update_NotFound(t *testing.T) {
@@ -197,6 +236,133 @@ func TestSessionStore_List(t *testing.T) {
 	results, err = store.ListInternal(ctx, SessionFilter{Limit: 1})
 	require.NoError(t, err)
 	assert.Len(t, results, 1)
+
+	// Test excluding terminal sessions
+	results, err = store.ListInternal(ctx, SessionFilter{ExcludeTerminal: true})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, s := range results {
+		assert.NotEqual(t, "sess-list-3", s.ID)
+	}
+
+	// Test offset, with and without a limit (newest first)
+	results, err = store.ListInternal(ctx, SessionFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "sess-list-1", results[0].ID)
+	results, err = store.ListInternal(ctx, SessionFilter{Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "sess-list-3", results[0].ID)
+
+	// Test GPU type and creation time filters
+	results, err = store.ListInternal(ctx, SessionFilter{GPUType: "rtx4090"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "sess-list-1", results[0].ID)
+
+	results, err = store.ListInternal(ctx, SessionFilter{CreatedAfter: now.Add(-time.Minute), CreatedBefore: now.Add(time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "sess-list-1", results[0].ID)
+
+	// Test sorting by price and by recorded cost
+	ids := func(sessions []*models.Session) []string {
+		var ids []string
+		for _, s := range sessions {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+	results, err = store.ListInternal(ctx, SessionFilter{SortBy: models.SessionSortPrice})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess-list-2", "sess-list-1", "sess-list-3"}, ids(results))
+
+	results, err = store.ListInternal(ctx, SessionFilter{SortBy: models.SessionSortCreatedAt, SortAscending: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess-list-3", "sess-list-1", "sess-list-2"}, ids(results))
+
+	costStore := NewCostStore(db)
+	for i, record := range []struct {
+		sessionID string
+		amount    float64
+	}{{"sess-list-3", 2.00}, {"sess-list-3", 1.50}, {"sess-list-1", 0.50}} {
+		require.NoError(t, costStore.Record(ctx, &models.CostRecord{
+			SessionID: record.sessionID, ConsumerID: "consumer-001", Provider: "vastai", GPUType: "RTX3090",
+			Hour: now.Add(-time.Duration(i+1) * time.Hour).Truncate(time.Hour), Amount: record.amount,
+		}))
+	}
+	results, err = store.ListInternal(ctx, SessionFilter{SortBy: models.SessionSortCost})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess-list-3", "sess-list-1", "sess-list-2"}, ids(results))
+
+	results, err = store.ListInternal(ctx, SessionFilter{SortBy: models.SessionSortCost, SortAscending: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess-list-2"}, ids(results))
+}
+
+func TestSessionStore_Search(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	for i, s := range []struct {
+		id, consumer, offer string
+	}{
+		{"sess-search-1", "team-a", "vastai-111"},
+		{"sess-search-2", "team-b", "vastai-222"},
+		{"sess-search-3", "team-a", "tensordock-333"},
+	} {
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID: s.id, ConsumerID: s.consumer, Provider: "vastai", OfferID: s.offer, GPUType: "RTX4090", GPUCount: 1,
+			Status: models.StatusPending, WorkloadType: "ml-training", ReservationHrs: 1, StoragePolicy: "destroy",
+			PricePerHour: 0.5, CreatedAt: now.Add(time.Duration(i) * time.Minute), ExpiresAt: now.Add(time.Hour),
+		}))
+	}
+
+	// Text set after creation is searchable
+	failed, err := store.Get(ctx, "sess-search-1")
+	require.NoError(t, err)
+	failed.Status = models.StatusFailed
+	failed.Error = "instance failed: no available public IPs"
+	require.NoError(t, store.Update(ctx, failed))
+	running, err := store.Get(ctx, "sess-search-2")
+	require.NoError(t, err)
+	running.ProviderID = "inst-9"
+	running.FailureDetail = "ssh_timeout"
+	require.NoError(t, store.Update(ctx, running))
+
+	ids := func(sessions []*models.Session) []string {
+		var out []string
+		for _, s := range sessions {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+
+	// Both the FTS5 index (when compiled in) and the LIKE fallback
+	for _, fullText := range []bool{db.fullTextSearch, false} {
+		db.fullTextSearch = fullText
+		search := func(text string) []string {
+			t.Helper()
+			results, err := store.ListInternal(ctx, SessionFilter{Search: text})
+			require.NoError(t, err)
+			return ids(results)
+		}
+
+		assert.Equal(t, []string{"sess-search-1"}, search("No Available Public IPs"), "fts=%v", fullText)
+		assert.Equal(t, []string{"sess-search-2"}, search("inst-9"), "fts=%v", fullText)
+		assert.Equal(t, []string{"sess-search-2"}, search("ssh_timeout"), "fts=%v", fullText)
+		assert.Equal(t, []string{"sess-search-3"}, search("tensordock-333"), "fts=%v", fullText)
+		assert.Equal(t, []string{"sess-search-3", "sess-search-1"}, search("team-a"), "fts=%v", fullText)
+		assert.Empty(t, search(`"out of memory" OR *`), "fts=%v", fullText)
+		assert.Empty(t, search("100%"), "fts=%v", fullText)
+
+		results, err := store.ListInternal(ctx, SessionFilter{Search: "team-a", Status: models.StatusFailed})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sess-search-1"}, ids(results), "fts=%v", fullText)
+	}
 }
 
 func TestSessionStore_GetActiveSessions(t *testing.T) {
@@ -333,6 +499,353 @@ func TestSessionStore_NullableFields(t *testing.T) {
 	assert.True(t, retrieved.StoppedAt.IsZero())
 }
 
+func TestSessionStore_Groups(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	for i, status := range []models.SessionStatus{models.StatusRunning, models.StatusStopped, models.StatusRunning} {
+		groupID := "job-1"
+		if i == 2 {
+			groupID = ""
+		}
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID:             fmt.Sprintf("sess-%d", i),
+			ConsumerID:     "consumer-001",
+			Provider:       "vastai",
+			OfferID:        fmt.Sprintf("offer-%d", i),
+			GPUType:        "RTX4090",
+			GPUCount:       1,
+			Status:         status,
+			WorkloadType:   "ml-training",
+			ReservationHrs: 1,
+			StoragePolicy:  "destroy",
+			GroupID:        groupID,
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(time.Hour),
+		}))
+	}
+
+	retrieved, err := store.Get(ctx, "sess-0")
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", retrieved.GroupID)
+
+	members, err := store.List(ctx, models.SessionListFilter{GroupID: "job-1"})
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	active, err := store.GetActiveSessionsByGroup(ctx, "job-1")
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "sess-0", active[0].ID)
+}
+
+func TestSessionStore_Interruptible(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-bid",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "vastai-bid-1",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusPending,
+		WorkloadType:   "ml-training",
+		ReservationHrs: 4,
+		StoragePolicy:  "destroy",
+		PricePerHour:   0.25,
+		Interruptible:  true,
+		BidPrice:       0.25,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(4 * time.Hour),
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	retrieved, err := store.Get(ctx, "sess-bid")
+	require.NoError(t, err)
+	assert.True(t, retrieved.Interruptible)
+	assert.Equal(t, 0.25, retrieved.BidPrice)
+
+	running, err := store.GetSessionsByStatus(ctx, models.StatusPending)
+	require.NoError(t, err)
+	require.Len(t, running, 1)
+	assert.True(t, running[0].Interruptible)
+}
+
+func TestSessionStore_GetFailureCounts(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	newSession := func(id, provider, location string, status models.SessionStatus, category models.FailureCategory, created time.Time) *models.Session {
+		return &models.Session{
+			ID: id, ConsumerID: "c1", Provider: provider, OfferID: "offer-" + id,
+			GPUType: "RTX4090", GPUCount: 1, Status: status, Location: location,
+			WorkloadType: "ml", ReservationHrs: 4, StoragePolicy: "destroy",
+			PricePerHour: 0.5, CreatedAt: created, ExpiresAt: created.Add(4 * time.Hour),
+			FailureCategory: category,
+		}
+	}
+	for _, session := range []*models.Session{
+		newSession("ok-1", "vastai", "US", models.StatusStopped, "", now),
+		newSession("ssh-1", "vastai", "US", models.StatusFailed, models.FailureSSHTimeout, now),
+		newSession("ssh-2", "vastai", "US", models.StatusFailed, models.FailureSSHTimeout, now),
+		newSession("legacy", "tensordock", "", models.StatusFailed, "", now),
+		newSession("lost", "vastai", "EU", models.StatusPreempted, "", now),
+		newSession("old", "vastai", "US", models.StatusFailed, models.FailureSSHTimeout, now.Add(-48*time.Hour)),
+	} {
+		require.NoError(t, store.Create(ctx, session))
+	}
+
+	// Categories set on update are persisted
+	updated := newSession("stale-1", "vastai", "EU", models.StatusPending, "", now)
+	require.NoError(t, store.Create(ctx, updated))
+	updated.Status = models.StatusFailed
+	updated.FailureCategory = models.FailureStaleInventory
+	updated.FailureDetail = "offer gone"
+	require.NoError(t, store.Update(ctx, updated))
+	retrieved, err := store.Get(ctx, "stale-1")
+	require.NoError(t, err)
+	assert.Equal(t, "EU", retrieved.Location)
+	assert.Equal(t, models.FailureStaleInventory, retrieved.FailureCategory)
+	assert.Equal(t, "offer gone", retrieved.FailureDetail)
+
+	counts, err := store.GetFailureCounts(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	got := make(map[models.FailureCount]bool)
+	for _, c := range counts {
+		got[c] = true
+	}
+	assert.Len(t, counts, 5)
+	assert.True(t, got[models.FailureCount{Provider: "vastai", GPUType: "RTX4090", Location: "US", Category: "", Count: 1}])
+	assert.True(t, got[models.FailureCount{Provider: "vastai", GPUType: "RTX4090", Location: "US", Category: models.FailureSSHTimeout, Count: 2}])
+	assert.True(t, got[models.FailureCount{Provider: "tensordock", GPUType: "RTX4090", Location: "", Category: models.FailureUnknown, Count: 1}])
+	assert.True(t, got[models.FailureCount{Provider: "vastai", GPUType: "RTX4090", Location: "EU", Category: models.FailurePreempted, Count: 1}])
+	assert.True(t, got[models.FailureCount{Provider: "vastai", GPUType: "RTX4090", Location: "EU", Category: models.FailureStaleInventory, Count: 1}])
+}
+
+func TestSessionStore_IdlePolicy(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	session := &models.Session{
+		ID:             "sess-idle",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "vastai-1",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusRunning,
+		WorkloadType:   "interactive",
+		ReservationHrs: 4,
+		IdleThreshold:  30,
+		IdleGPUUtilPct: 10,
+		StoragePolicy:  "destroy",
+		PricePerHour:   0.40,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(4 * time.Hour),
+	}
+	require.NoError(t, store.Create(ctx, session))
+
+	retrieved, err := store.Get(ctx, "sess-idle")
+	require.NoError(t, err)
+	assert.Equal(t, models.IdlePolicy{After: 30 * time.Minute, MaxGPUUtilPct: 10}, retrieved.IdlePolicy())
+
+	retrieved.IdleGPUUtilPct = 0
+	assert.Equal(t, models.DefaultIdleGPUUtilPct, retrieved.IdlePolicy().MaxGPUUtilPct)
+	retrieved.IdleThreshold = 0
+	assert.False(t, retrieved.IdlePolicy().Enabled())
+}
+
+func TestSessionStore_Pause(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	session := createTestSession(t, store, "sess-paused")
+	pausedAt := time.Now().UTC().Truncate(time.Second)
+	session.Status = models.StatusPaused
+	session.PausedAt = pausedAt
+	session.PausedTotal = 90 * time.Minute
+	require.NoError(t, store.Update(ctx, session))
+
+	retrieved, err := store.Get(ctx, "sess-paused")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPaused, retrieved.Status)
+	assert.True(t, pausedAt.Equal(retrieved.PausedAt))
+	assert.Equal(t, 90*time.Minute, retrieved.PausedTotal)
+
+	// Paused instances still exist, so the reconciler must see their sessions
+	byProvider, err := store.GetActiveSessionsByProvider(ctx, "vastai")
+	require.NoError(t, err)
+	require.Len(t, byProvider, 1)
+	active, err := store.GetActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	retrieved.Status = models.StatusRunning
+	retrieved.PausedAt = time.Time{}
+	require.NoError(t, store.Update(ctx, retrieved))
+	retrieved, err = store.Get(ctx, "sess-paused")
+	require.NoError(t, err)
+	assert.True(t, retrieved.PausedAt.IsZero())
+	assert.Equal(t, 90*time.Minute, retrieved.PausedTotal)
+}
+
+func TestSessionStore_UpdateThroughput(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, store.Create(ctx, &models.Session{
+		ID:             "sess-001",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "vastai-123",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusRunning,
+		WorkloadType:   "interactive",
+		ReservationHrs: 4,
+		StoragePolicy:  "destroy",
+		PricePerHour:   0.40,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(4 * time.Hour),
+	}))
+
+	require.NoError(t, store.UpdateThroughput(ctx, "sess-001", 850.5, 1200))
+	retrieved, err := store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.Equal(t, 850.5, retrieved.MeasuredInetDownMbps)
+	assert.Equal(t, 1200.0, retrieved.MeasuredDiskBandwidthMBps)
+
+	// A measurement that was not taken keeps the previous value
+	require.NoError(t, store.UpdateThroughput(ctx, "sess-001", 0, 900))
+	retrieved, err = store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.Equal(t, 850.5, retrieved.MeasuredInetDownMbps)
+	assert.Equal(t, 900.0, retrieved.MeasuredDiskBandwidthMBps)
+
+	assert.ErrorIs(t, store.UpdateThroughput(ctx, "nonexistent", 100, 100), ErrNotFound)
+}
+
+func TestSessionStore_SetRebootingUntil(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, store.Create(ctx, &models.Session{
+		ID:             "sess-001",
+		ConsumerID:     "consumer-001",
+		Provider:       "vastai",
+		OfferID:        "vastai-123",
+		GPUType:        "RTX4090",
+		GPUCount:       1,
+		Status:         models.StatusRunning,
+		WorkloadType:   "interactive",
+		ReservationHrs: 4,
+		StoragePolicy:  "destroy",
+		PricePerHour:   0.40,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(4 * time.Hour),
+	}))
+
+	require.NoError(t, store.SetRebootingUntil(ctx, "sess-001", now.Add(10*time.Minute)))
+	retrieved, err := store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.True(t, retrieved.Rebooting(now))
+
+	// Saving other changes keeps the mark
+	retrieved.SSHPort = 40022
+	retrieved.RebootingUntil = time.Time{}
+	require.NoError(t, store.Update(ctx, retrieved))
+	retrieved, err = store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.True(t, retrieved.Rebooting(now))
+
+	require.NoError(t, store.SetRebootingUntil(ctx, "sess-001", time.Time{}))
+	retrieved, err = store.Get(ctx, "sess-001")
+	require.NoError(t, err)
+	assert.False(t, retrieved.Rebooting(now))
+
+	assert.ErrorIs(t, store.SetRebootingUntil(ctx, "nonexistent", now), ErrNotFound)
+}
+
+func TestSessionStore_UpdateHealth(t *testing.T) {
+	db := newTestDB(t)
+	store := NewSessionStore(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, id := range []string{"sess-ok", "sess-silent"} {
+		require.NoError(t, store.Create(ctx, &models.Session{
+			ID:             id,
+			ConsumerID:     "consumer-001",
+			Provider:       "vastai",
+			OfferID:        "vastai-" + id,
+			GPUType:        "RTX4090",
+			GPUCount:       1,
+			Status:         models.StatusRunning,
+			WorkloadType:   "interactive",
+			ReservationHrs: 4,
+			StoragePolicy:  "destroy",
+			PricePerHour:   0.40,
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(4 * time.Hour),
+		}))
+	}
+
+	retrieved, err := store.Get(ctx, "sess-ok")
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Health.Status, "no health until the first heartbeat check")
+	assert.Nil(t, retrieved.Health.GPUUtilPct)
+
+	util := 2.5
+	require.NoError(t, store.UpdateHealth(ctx, "sess-ok", models.SessionHealth{
+		Status:          models.HealthHealthy,
+		LastHeartbeatAt: now,
+		GPUUtilPct:      &util,
+		IdleSince:       now.Add(-10 * time.Minute),
+	}))
+	require.NoError(t, store.UpdateHealth(ctx, "sess-silent", models.SessionHealth{
+		Status:          models.HealthDegraded,
+		LastHeartbeatAt: now.Add(-10 * time.Minute),
+	}))
+
+	retrieved, err = store.Get(ctx, "sess-ok")
+	require.NoError(t, err)
+	assert.Equal(t, models.HealthHealthy, retrieved.Health.Status)
+	assert.WithinDuration(t, now, retrieved.Health.LastHeartbeatAt, time.Second)
+	require.NotNil(t, retrieved.Health.GPUUtilPct)
+	assert.Equal(t, 2.5, *retrieved.Health.GPUUtilPct)
+	assert.InDelta(t, 10*time.Minute, retrieved.Health.IdleFor(), float64(time.Second))
+
+	// Full updates leave the health columns alone
+	retrieved.Health = models.SessionHealth{}
+	require.NoError(t, store.Update(ctx, retrieved))
+	retrieved, err = store.Get(ctx, "sess-ok")
+	require.NoError(t, err)
+	assert.Equal(t, models.HealthHealthy, retrieved.Health.Status)
+
+	degraded, err := store.List(ctx, models.SessionListFilter{Health: models.HealthDegraded})
+	require.NoError(t, err)
+	require.Len(t, degraded, 1)
+	assert.Equal(t, "sess-silent", degraded[0].ID)
+
+	assert.ErrorIs(t, store.UpdateHealth(ctx, "missing", models.SessionHealth{}), ErrNotFound)
+}
+
 func TestSessionStore_GetActiveSessionByConsumerAndOffer(t *testing.T) {
 	db := newTestDB(t)
 	store := NewSessionStore(db)
@@ -0,0 +1,331 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/google/uuid"
+)
+
+// WebhookStore handles persistence of webhook subscriptions and deliveries
+type WebhookStore struct {
+	db *DB
+}
+
+// NewWebhookStore creates a new webhook store
+func NewWebhookStore(db *DB) *WebhookStore {
+	return &WebhookStore{db: db}
+}
+
+const webhookSubscriptionColumns = `id, consumer_id, url, secret, events, created_at`
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	last_status_code, last_error, next_attempt_at, delivered_at, created_at`
+
+// CreateSubscription creates a new webhook subscription
+func (s *WebhookStore) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	if sub.ID == "" {
+		sub.ID = uuid.New().String()
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now().UTC()
+	}
+
+	secret, err := s.db.seal(sub.Secret)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO webhook_subscriptions (` + webhookSubscriptionColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err = s.db.ExecContext(ctx, query,
+		sub.ID,
+		sub.ConsumerID,
+		sub.URL,
+		secret,
+		joinEventTypes(sub.Events),
+		sub.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscription retrieves a webhook subscription by ID
+func (s *WebhookStore) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = ?`
+
+	sub, err := scanWebhookSubscription(s.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if sub.Secret, err = s.db.open(sub.Secret); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// ListSubscriptions lists webhook subscriptions, optionally for a single consumer
+func (s *WebhookStore) ListSubscriptions(ctx context.Context, consumerID string) ([]*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions`
+
+	var args []interface{}
+	if consumerID != "" {
+		query += " WHERE consumer_id = ?"
+		args = append(args, consumerID)
+	}
+	query += " ORDER BY created_at"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*models.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		if sub.Secret, err = s.db.open(sub.Secret); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// DeleteSubscription deletes a webhook subscription. Delivery history is kept.
+func (s *WebhookStore) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateDelivery records a new delivery
+func (s *WebhookStore) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now().UTC()
+	}
+
+	payload, err := s.db.seal(d.Payload)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = s.db.ExecContext(ctx, query,
+		d.ID,
+		d.SubscriptionID,
+		d.EventID,
+		d.EventType,
+		payload,
+		d.Status,
+		d.Attempts,
+		d.LastStatusCode,
+		d.LastError,
+		d.NextAttemptAt,
+		nullTime(d.DeliveredAt),
+		d.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (s *WebhookStore) UpdateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = ?, attempts = ?, last_status_code = ?, last_error = ?,
+			next_attempt_at = ?, delivered_at = ?
+		WHERE id = ?
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		d.Status,
+		d.Attempts,
+		d.LastStatusCode,
+		d.LastError,
+		d.NextAttemptAt,
+		nullTime(d.DeliveredAt),
+		d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDueDeliveries returns pending deliveries whose next attempt is at or before now, oldest first
+func (s *WebhookStore) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at`
+
+	args := []interface{}{models.WebhookDeliveryPending, now}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	return s.listDeliveries(ctx, query, args...)
+}
+
+// ListDeliveries returns deliveries for a subscription, newest first
+func (s *WebhookStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY created_at DESC`
+
+	args := []interface{}{subscriptionID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	return s.listDeliveries(ctx, query, args...)
+}
+
+func (s *WebhookStore) listDeliveries(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		if d.Payload, err = s.db.open(d.Payload); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhookSubscription(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	var events string
+
+	err := scanner.Scan(
+		&sub.ID,
+		&sub.ConsumerID,
+		&sub.URL,
+		&sub.Secret,
+		&events,
+		&sub.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.Events = splitEventTypes(events)
+	return &sub, nil
+}
+
+func scanWebhookDelivery(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var deliveredAt sql.NullTime
+
+	err := scanner.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.LastStatusCode,
+		&d.LastError,
+		&d.NextAttemptAt,
+		&deliveredAt,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveredAt.Valid {
+		d.DeliveredAt = deliveredAt.Time
+	}
+
+	return &d, nil
+}
+
+// joinEventTypes stores event types as a comma-separated list
+func joinEventTypes(events []models.WebhookEventType) string {
+	parts := make([]string, len(events))
+	for i, e := range events {
+		parts[i] = string(e)
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitEventTypes(s string) []models.WebhookEventType {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	events := make([]models.WebhookEventType, len(parts))
+	for i, p := range parts {
+		events[i] = models.WebhookEventType(p)
+	}
+	return events
+}
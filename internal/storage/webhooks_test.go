@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookStore_Subscriptions(t *testing.T) {
+	db := newTestDB(t)
+	store := NewWebhookStore(db)
+	ctx := context.Background()
+
+	sub := &models.WebhookSubscription{
+		ConsumerID: "consumer-001",
+		URL:        "https://example.com/hook",
+		Secret:     "s3cret",
+		Events:     []models.WebhookEventType{models.WebhookEventSessionRunning, models.WebhookEventSessionFailed},
+	}
+	require.NoError(t, store.CreateSubscription(ctx, sub))
+	require.NotEmpty(t, sub.ID)
+
+	all := &models.WebhookSubscription{ConsumerID: "consumer-002", URL: "https://example.com/other", Secret: "x"}
+	require.NoError(t, store.CreateSubscription(ctx, all))
+
+	got, err := store.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", got.URL)
+	assert.Equal(t, "s3cret", got.Secret)
+	assert.Equal(t, sub.Events, got.Events)
+
+	gotAll, err := store.GetSubscription(ctx, all.ID)
+	require.NoError(t, err)
+	assert.Nil(t, gotAll.Events)
+
+	subs, err := store.ListSubscriptions(ctx, "consumer-001")
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+
+	subs, err = store.ListSubscriptions(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, subs, 2)
+
+	require.NoError(t, store.DeleteSubscription(ctx, sub.ID))
+	_, err = store.GetSubscription(ctx, sub.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.DeleteSubscription(ctx, sub.ID), ErrNotFound)
+}
+
+func TestWebhookStore_Deliveries(t *testing.T) {
+	db := newTestDB(t)
+	store := NewWebhookStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	due := &models.WebhookDelivery{
+		SubscriptionID: "sub-1",
+		EventID:        "evt-1",
+		EventType:      models.WebhookEventSessionCreated,
+		Payload:        `{"id":"evt-1"}`,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  now.Add(-time.Minute),
+	}
+	later := &models.WebhookDelivery{
+		SubscriptionID: "sub-1",
+		EventID:        "evt-2",
+		EventType:      models.WebhookEventSessionRunning,
+		Payload:        `{"id":"evt-2"}`,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  now.Add(time.Hour),
+	}
+	require.NoError(t, store.CreateDelivery(ctx, due))
+	require.NoError(t, store.CreateDelivery(ctx, later))
+
+	dueList, err := store.ListDueDeliveries(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, dueList, 1)
+	assert.Equal(t, due.ID, dueList[0].ID)
+	assert.Equal(t, `{"id":"evt-1"}`, dueList[0].Payload)
+	assert.True(t, dueList[0].DeliveredAt.IsZero())
+
+	due.Status = models.WebhookDeliveryDelivered
+	due.Attempts = 1
+	due.LastStatusCode = 200
+	due.DeliveredAt = now
+	require.NoError(t, store.UpdateDelivery(ctx, due))
+
+	dueList, err = store.ListDueDeliveries(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, dueList)
+
+	history, err := store.ListDeliveries(ctx, "sub-1", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for _, d := range history {
+		if d.ID == due.ID {
+			assert.Equal(t, models.WebhookDeliveryDelivered, d.Status)
+			assert.Equal(t, 200, d.LastStatusCode)
+			assert.WithinDuration(t, now, d.DeliveredAt, time.Second)
+		}
+	}
+
+	assert.ErrorIs(t, store.UpdateDelivery(ctx, &models.WebhookDelivery{ID: "missing"}), ErrNotFound)
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// AuditAction identifies an admin operation, usually performed on behalf of a
+// consumer
+type AuditAction string
+
+const (
+	AuditActionListSessions       AuditAction = "list_sessions"
+	AuditActionRegenerateSSHKey   AuditAction = "regenerate_ssh_key"
+	AuditActionExtendSession      AuditAction = "extend_session"
+	AuditActionDestroySession     AuditAction = "destroy_session"
+	AuditActionSetFeatureFlag     AuditAction = "set_feature_flag"
+	AuditActionResetFeatureFlag   AuditAction = "reset_feature_flag"
+	AuditActionExportSessions     AuditAction = "export_sessions"
+	AuditActionImportSessions     AuditAction = "import_sessions"
+	AuditActionReconcile          AuditAction = "reconcile"
+	AuditActionAddCatalogModel    AuditAction = "add_catalog_model"
+	AuditActionRemoveCatalogModel AuditAction = "remove_catalog_model"
+	AuditActionAddCatalogGPU      AuditAction = "add_catalog_gpu"
+	AuditActionRemoveCatalogGPU   AuditAction = "remove_catalog_gpu"
+	AuditActionSetOfferRule       AuditAction = "set_offer_rule"
+	AuditActionDeleteOfferRule    AuditAction = "delete_offer_rule"
+	AuditActionClearSuppression   AuditAction = "clear_offer_suppression"
+	AuditActionAdoptInstance      AuditAction = "adopt_instance"
+	AuditActionReleaseInstance    AuditAction = "release_instance"
+	AuditActionUnreleaseInstance  AuditAction = "unrelease_instance"
+	AuditActionResolveQuarantine  AuditAction = "resolve_quarantine"
+)
+
+// AuditEntry records a single admin action taken on behalf of a consumer
+type AuditEntry struct {
+	ID         string      `json:"id"`
+	Actor      string      `json:"actor"` // Support operator who performed the action
+	Action     AuditAction `json:"action"`
+	ConsumerID string      `json:"consumer_id"`
+	SessionID  string      `json:"session_id,omitempty"`
+	Reason     string      `json:"reason,omitempty"`
+	Details    string      `json:"details,omitempty"`
+	RequestID  string      `json:"request_id,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// AuditFilter defines filters for listing audit entries
+type AuditFilter struct {
+	Actor      string
+	ConsumerID string
+	SessionID  string
+	Limit      int
+}
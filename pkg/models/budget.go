@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// BudgetScope identifies what a budget caps spend for
+type BudgetScope string
+
+const (
+	BudgetScopeConsumer   BudgetScope = "consumer"   // Spend by a single consumer_id
+	BudgetScopeDeployment BudgetScope = "deployment" // Spend across every consumer of this deployment
+)
+
+// IsValid returns true if the scope is a recognized value.
+func (s BudgetScope) IsValid() bool {
+	return s == BudgetScopeConsumer || s == BudgetScopeDeployment
+}
+
+// BudgetPeriod is the window over which spend is accumulated
+type BudgetPeriod string
+
+const (
+	BudgetPeriodDaily   BudgetPeriod = "daily"
+	BudgetPeriodWeekly  BudgetPeriod = "weekly"
+	BudgetPeriodMonthly BudgetPeriod = "monthly"
+)
+
+// IsValid returns true if the period is a recognized value.
+func (p BudgetPeriod) IsValid() bool {
+	return p == BudgetPeriodDaily || p == BudgetPeriodWeekly || p == BudgetPeriodMonthly
+}
+
+// Bounds returns the start (inclusive) and end (exclusive) of the period containing t.
+// Weeks start on Monday.
+func (p BudgetPeriod) Bounds(t time.Time) (start, end time.Time) {
+	switch p {
+	case BudgetPeriodDaily:
+		start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 1)
+	case BudgetPeriodWeekly:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		start = time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 7)
+	default:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// Budget is a spend cap for a consumer or the whole deployment over a period
+type Budget struct {
+	ID             string       `json:"id"`
+	Scope          BudgetScope  `json:"scope"`
+	ScopeID        string       `json:"scope_id"` // consumer_id or deployment_id
+	Period         BudgetPeriod `json:"period"`
+	LimitUSD       float64      `json:"limit_usd"`
+	WarningSentAt  time.Time    `json:"warning_sent_at,omitempty"`  // Last 80% alert
+	ExceededSentAt time.Time    `json:"exceeded_sent_at,omitempty"` // Last 100% alert
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// BudgetStatus reports spend against a budget for the current period
+type BudgetStatus struct {
+	Budget      Budget    `json:"budget"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Spend       float64   `json:"spend"`     // Recorded cost in the period
+	Committed   float64   `json:"committed"` // Remaining reserved hours of active sessions
+	Remaining   float64   `json:"remaining"` // Limit minus spend and committed (may be negative)
+	Utilization float64   `json:"utilization"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetPeriodBounds(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 3, 18, 10, 30, 0, 0, time.UTC)
+
+	start, end := BudgetPeriodDaily.Bounds(now)
+	assert.Equal(t, time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = BudgetPeriodWeekly.Bounds(now)
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = BudgetPeriodMonthly.Bounds(now)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), end)
+}
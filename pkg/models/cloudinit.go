@@ -0,0 +1,46 @@
+package models
+
+// Limits on consumer-supplied cloud-init, which is sent to the provider
+// with every instance create request
+const (
+	MaxCloudInitBytes   = 16 * 1024 // Total size of runcmd entries and file contents
+	MaxCloudInitRunCmds = 50
+	MaxCloudInitFiles   = 20
+)
+
+// CloudInitConfig is cloud-init user data a consumer adds to a session's
+// instance. It runs after the shopper's own commands, which install the
+// session's SSH key, so a broken script cannot lock the shopper out.
+type CloudInitConfig struct {
+	RunCmd     []string        `json:"runcmd,omitempty"`
+	WriteFiles []CloudInitFile `json:"write_files,omitempty"`
+}
+
+// CloudInitFile is a file cloud-init writes before running commands
+type CloudInitFile struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	Encoding    string `json:"encoding,omitempty"`    // "b64" for base64 content, plain text otherwise
+	Permissions string `json:"permissions,omitempty"` // Octal mode, e.g. "0755"
+	Owner       string `json:"owner,omitempty"`       // "user" or "user:group"
+}
+
+// Size returns the bytes counted against MaxCloudInitBytes
+func (c *CloudInitConfig) Size() int {
+	if c == nil {
+		return 0
+	}
+	n := 0
+	for _, cmd := range c.RunCmd {
+		n += len(cmd)
+	}
+	for _, f := range c.WriteFiles {
+		n += len(f.Path) + len(f.Content)
+	}
+	return n
+}
+
+// IsEmpty reports whether the config has nothing for cloud-init to do
+func (c *CloudInitConfig) IsEmpty() bool {
+	return c == nil || (len(c.RunCmd) == 0 && len(c.WriteFiles) == 0)
+}
@@ -4,16 +4,27 @@ import "time"
 
 // CostRecord represents a cost entry for a session
 type CostRecord struct {
-	ID         string    `json:"id"`
-	SessionID  string    `json:"session_id"`
-	ConsumerID string    `json:"consumer_id"`
-	Provider   string    `json:"provider"`
-	GPUType    string    `json:"gpu_type"`
-	Hour       time.Time `json:"hour"`     // Truncated to hour
-	Amount     float64   `json:"amount"`   // Cost in USD
-	Currency   string    `json:"currency"` // Always "USD" for now
+	ID         string       `json:"id"`
+	SessionID  string       `json:"session_id"`
+	ConsumerID string       `json:"consumer_id"`
+	Provider   string       `json:"provider"`
+	GPUType    string       `json:"gpu_type"`
+	Category   CostCategory `json:"category"` // Line item; defaults to "gpu"
+	Hour       time.Time    `json:"hour"`     // Truncated to hour
+	Amount     float64      `json:"amount"`   // Cost in USD
+	Currency   string       `json:"currency"` // Always "USD" for now
 }
 
+// CostCategory identifies the line item a cost record bills for
+type CostCategory string
+
+const (
+	CostCategoryGPU       CostCategory = "gpu"       // Hourly instance rate
+	CostCategoryStorage   CostCategory = "storage"   // Provider-reported disk charges
+	CostCategoryBandwidth CostCategory = "bandwidth" // Provider-reported network transfer charges
+	CostCategoryIP        CostCategory = "ip"        // Provider-reported public IP charges
+)
+
 // CostSummary provides aggregated cost information
 type CostSummary struct {
 	ConsumerID   string             `json:"consumer_id,omitempty"`
@@ -22,10 +33,51 @@ type CostSummary struct {
 	HoursUsed    float64            `json:"hours_used"`
 	ByProvider   map[string]float64 `json:"by_provider,omitempty"`
 	ByGPUType    map[string]float64 `json:"by_gpu_type,omitempty"`
+	ByCategory   map[string]float64 `json:"by_category,omitempty"`
 	PeriodStart  time.Time          `json:"period_start,omitempty"`
 	PeriodEnd    time.Time          `json:"period_end,omitempty"`
 }
 
+// CostGroupBy is a dimension costs can be grouped by for chargeback
+type CostGroupBy string
+
+const (
+	CostGroupByConsumer   CostGroupBy = "consumer"
+	CostGroupByDeployment CostGroupBy = "deployment" // One value per server, for combining exports
+	CostGroupByGPUType    CostGroupBy = "gpu_type"
+	CostGroupByProvider   CostGroupBy = "provider"
+)
+
+// IsValid reports whether g is a known grouping dimension
+func (g CostGroupBy) IsValid() bool {
+	switch g {
+	case CostGroupByConsumer, CostGroupByDeployment, CostGroupByGPUType, CostGroupByProvider:
+		return true
+	}
+	return false
+}
+
+// CostGroup is the spend for one combination of grouping dimensions. Only
+// the dimensions being grouped by are set.
+type CostGroup struct {
+	ConsumerID   string  `json:"consumer_id,omitempty"`
+	Deployment   string  `json:"deployment,omitempty"`
+	GPUType      string  `json:"gpu_type,omitempty"`
+	Provider     string  `json:"provider,omitempty"`
+	TotalCost    float64 `json:"total_cost"`
+	SessionCount int     `json:"session_count"`
+	HoursUsed    float64 `json:"hours_used"`
+}
+
+// CostBreakdown is spend in a period grouped for chargeback
+type CostBreakdown struct {
+	GroupBy     []CostGroupBy `json:"group_by"`
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	Groups      []CostGroup   `json:"groups"`
+	TotalCost   float64       `json:"total_cost"`
+}
+
 // CostQuery defines criteria for querying costs
 type CostQuery struct {
 	ConsumerID string    `json:"consumer_id,omitempty"`
@@ -56,4 +108,8 @@ type BudgetAlert struct {
 	Percentage   float64   `json:"percentage"`
 	AlertType    string    `json:"alert_type"` // "warning" (80%) or "exceeded" (100%)
 	Timestamp    time.Time `json:"timestamp"`
+
+	// Set for alerts raised by scoped budgets (see Budget)
+	Scope  BudgetScope  `json:"scope,omitempty"`
+	Period BudgetPeriod `json:"period,omitempty"`
 }
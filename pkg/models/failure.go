@@ -0,0 +1,144 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// FailureCategory classifies why a session failed or was lost
+type FailureCategory string
+
+const (
+	// FailureStaleInventory means the offer was gone by the time it was rented
+	FailureStaleInventory FailureCategory = "stale_inventory"
+	// FailureProviderError means the provider rejected or failed the create call
+	FailureProviderError FailureCategory = "provider_error"
+	// FailureInstanceStopped means the instance stopped before it was usable
+	FailureInstanceStopped FailureCategory = "instance_stopped"
+	// FailureInstanceVanished means the provider no longer knows the instance
+	FailureInstanceVanished FailureCategory = "instance_vanished"
+	// FailureSSHTimeout means SSH never became reachable in time
+	FailureSSHTimeout FailureCategory = "ssh_timeout"
+	// FailureSSHAuth means SSH was reachable but kept rejecting the session key
+	FailureSSHAuth FailureCategory = "ssh_auth_failed"
+	// FailureAPITimeout means the workload API never became healthy in time
+	FailureAPITimeout FailureCategory = "api_timeout"
+	// FailureWarmup means an entrypoint workload's logs showed it cannot
+	// start, e.g. out of GPU memory or no access to the model
+	FailureWarmup FailureCategory = "warmup_failed"
+	// FailureProvisioningTimeout means the session was stuck in a transitional state
+	FailureProvisioningTimeout FailureCategory = "provisioning_timeout"
+	// FailureCUDAMismatch means the instance reported an older CUDA or driver
+	// version than its offer advertised. It is recorded against the offer;
+	// the session itself keeps running.
+	FailureCUDAMismatch FailureCategory = "cuda_mismatch"
+	// FailureGPUUnhealthy means the GPU burn-in found a broken, missing or
+	// throttling GPU before the session was handed over
+	FailureGPUUnhealthy FailureCategory = "gpu_unhealthy"
+	// FailureGPUMismatch means nvidia-smi reported fewer GPUs, a different
+	// GPU model or less VRAM than the offer advertised
+	FailureGPUMismatch FailureCategory = "gpu_mismatch"
+	// FailurePreempted means the provider reclaimed a running instance
+	FailurePreempted FailureCategory = "preempted"
+	// FailureUnknown is used for failures recorded before categories existed
+	FailureUnknown FailureCategory = "unknown"
+)
+
+// FailureCount is the number of sessions sharing a provider, GPU type,
+// location and failure category. An empty category counts sessions that did
+// not fail.
+type FailureCount struct {
+	Provider string
+	GPUType  string
+	Location string
+	Category FailureCategory
+	Count    int
+}
+
+// FailureBreakdown is failure statistics for one value of a dimension
+type FailureBreakdown struct {
+	Key         string                  `json:"key"`
+	Sessions    int                     `json:"sessions"`
+	Failures    int                     `json:"failures"`
+	FailureRate float64                 `json:"failure_rate"` // Failures / Sessions
+	ByCategory  map[FailureCategory]int `json:"by_category"`
+}
+
+// FailureAnalytics summarizes session failures over a period
+type FailureAnalytics struct {
+	PeriodStart time.Time               `json:"period_start"`
+	PeriodEnd   time.Time               `json:"period_end"`
+	Sessions    int                     `json:"sessions"`
+	Failures    int                     `json:"failures"`
+	FailureRate float64                 `json:"failure_rate"`
+	ByCategory  map[FailureCategory]int `json:"by_category"`
+	ByProvider  []FailureBreakdown      `json:"by_provider"`
+	ByGPUType   []FailureBreakdown      `json:"by_gpu_type"`
+	ByLocation  []FailureBreakdown      `json:"by_location"`
+}
+
+// NewFailureAnalytics aggregates failure counts for sessions created in
+// [start, end). Breakdowns are ordered by failures, most first.
+func NewFailureAnalytics(start, end time.Time, counts []FailureCount) *FailureAnalytics {
+	a := &FailureAnalytics{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		ByCategory:  make(map[FailureCategory]int),
+	}
+	byProvider := make(map[string]*FailureBreakdown)
+	byGPUType := make(map[string]*FailureBreakdown)
+	byLocation := make(map[string]*FailureBreakdown)
+
+	for _, c := range counts {
+		a.Sessions += c.Count
+		if c.Category != "" {
+			a.Failures += c.Count
+			a.ByCategory[c.Category] += c.Count
+		}
+		addFailureCount(byProvider, c.Provider, c)
+		addFailureCount(byGPUType, c.GPUType, c)
+		location := c.Location
+		if location == "" {
+			location = "unknown"
+		}
+		addFailureCount(byLocation, location, c)
+	}
+
+	if a.Sessions > 0 {
+		a.FailureRate = float64(a.Failures) / float64(a.Sessions)
+	}
+	a.ByProvider = sortedFailureBreakdowns(byProvider)
+	a.ByGPUType = sortedFailureBreakdowns(byGPUType)
+	a.ByLocation = sortedFailureBreakdowns(byLocation)
+	return a
+}
+
+func addFailureCount(m map[string]*FailureBreakdown, key string, c FailureCount) {
+	b, ok := m[key]
+	if !ok {
+		b = &FailureBreakdown{Key: key, ByCategory: make(map[FailureCategory]int)}
+		m[key] = b
+	}
+	b.Sessions += c.Count
+	if c.Category != "" {
+		b.Failures += c.Count
+		b.ByCategory[c.Category] += c.Count
+	}
+}
+
+func sortedFailureBreakdowns(m map[string]*FailureBreakdown) []FailureBreakdown {
+	result := make([]FailureBreakdown, 0, len(m))
+	for _, b := range m {
+		if b.Sessions > 0 {
+			b.FailureRate = float64(b.Failures) / float64(b.Sessions)
+		}
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Failures != result[j].Failures {
+			return result[i].Failures > result[j].Failures
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// FeatureFlag gates a risky provisioning behavior at runtime. When Enabled,
+// the behavior applies to RolloutPercent of sessions, chosen by a stable hash
+// of the session ID so the same session always gets the same answer.
+type FeatureFlag struct {
+	Name           string    `json:"name"`
+	Enabled        bool      `json:"enabled"`
+	RolloutPercent int       `json:"rollout_percent"` // 0-100
+	Description    string    `json:"description,omitempty"`
+	UpdatedBy      string    `json:"updated_by,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// SessionCostForecast is a session's cost so far and projected at its
+// current burn rate
+type SessionCostForecast struct {
+	SessionID  string        `json:"session_id"`
+	ConsumerID string        `json:"consumer_id"`
+	Status     SessionStatus `json:"status"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+
+	BurnRate          float64 `json:"burn_rate_per_hour"`  // Zero once the session has ended
+	AccruedCost       float64 `json:"accrued_cost"`        // Cost records so far, all line items
+	ProjectedAtExpiry float64 `json:"projected_at_expiry"` // Accrued plus the rest of the reservation
+
+	ExtendHours         int     `json:"extend_hours"`
+	ProjectedIfExtended float64 `json:"projected_if_extended"` // Projected at expiry after extending by ExtendHours
+
+	Currency string `json:"currency"`
+}
+
+// FleetCostForecast projects spend across active sessions at their current
+// burn rate
+type FleetCostForecast struct {
+	ConsumerID     string    `json:"consumer_id,omitempty"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	ActiveSessions int       `json:"active_sessions"`
+
+	BurnRate          float64 `json:"burn_rate_per_hour"`
+	SpentToday        float64 `json:"spent_today"`         // Cost records for today, including ended sessions
+	ProjectedToday    float64 `json:"projected_today"`     // SpentToday plus active sessions until midnight or expiry
+	ProjectedAtExpiry float64 `json:"projected_at_expiry"` // Active sessions' accrued plus remaining reservation
+
+	Sessions []SessionCostForecast `json:"sessions"`
+	Currency string                `json:"currency"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"math/rand/v2"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,28 +18,47 @@ type CompatibleTemplate struct {
 // GPUOffer represents an available GPU instance for rent
 type GPUOffer struct {
 	ID                     string    `json:"id"`
-	Provider               string    `json:"provider"`                // "vastai" | "tensordock"
-	ProviderID             string    `json:"provider_id"`             // Provider's ID for this offer
-	GPUType                string    `json:"gpu_type"`                // "RTX 4090", "A100", etc.
-	GPUCount               int       `json:"gpu_count"`               // Number of GPUs
-	VRAM                   int       `json:"vram_gb"`                 // VRAM in GB
-	PricePerHour           float64   `json:"price_per_hour"`          // USD per hour
-	Location               string    `json:"location"`                // Geographic location
-	Reliability            float64   `json:"reliability"`             // 0-1 score if available
-	Available              bool      `json:"available"`               // Currently available
-	MaxDuration            int       `json:"max_duration_hours"`      // 0 = unlimited
-	FetchedAt              time.Time `json:"fetched_at"`              // When this offer was fetched
-	AvailabilityConfidence float64   `json:"availability_confidence"` // 0-1 confidence that offer is actually available (default 1.0)
-	CUDAVersion            float64   `json:"cuda_version,omitempty"`  // Max supported CUDA version (e.g., 12.9). Only for Vast.ai.
-	MachineID              string    `json:"machine_id,omitempty"`    // Physical host identifier (e.g., Vast.ai machine_id). Used for host-level failure avoidance.
-	Interruptible          bool      `json:"interruptible,omitempty"` // True if this is a spot/interruptible instance that can be reclaimed.
-	MinBid                 float64   `json:"min_bid,omitempty"`       // Minimum bid for interruptible instances (0 = on-demand).
+	Provider               string    `json:"provider"`                 // "vastai" | "tensordock"
+	ProviderID             string    `json:"provider_id"`              // Provider's ID for this offer
+	GPUType                string    `json:"gpu_type"`                 // "RTX 4090", "A100", etc.
+	GPUCount               int       `json:"gpu_count"`                // Number of GPUs
+	VRAM                   int       `json:"vram_gb"`                  // VRAM in GB
+	PricePerHour           float64   `json:"price_per_hour"`           // USD per hour
+	Location               string    `json:"location"`                 // Geographic location
+	Reliability            float64   `json:"reliability"`              // 0-1 score if available
+	Available              bool      `json:"available"`                // Currently available
+	MaxDuration            int       `json:"max_duration_hours"`       // 0 = unlimited
+	FetchedAt              time.Time `json:"fetched_at"`               // When this offer was fetched
+	AvailabilityConfidence float64   `json:"availability_confidence"`  // 0-1 confidence that offer is actually available (default 1.0)
+	CUDAVersion            float64   `json:"cuda_version,omitempty"`   // Max supported CUDA version (e.g., 12.9). Only for Vast.ai.
+	DriverVersion          string    `json:"driver_version,omitempty"` // NVIDIA driver version (e.g., "550.54.14"). Only for Vast.ai.
+	MachineID              string    `json:"machine_id,omitempty"`     // Physical host identifier (e.g., Vast.ai machine_id). Used for host-level failure avoidance.
+	Interruptible          bool      `json:"interruptible,omitempty"`  // True if this is a spot/interruptible instance that can be reclaimed.
+	MinBid                 float64   `json:"min_bid,omitempty"`        // Minimum bid for interruptible instances (0 = on-demand).
+
+	// Host network and disk throughput, 0 when unknown. BandwidthEstimated
+	// is set when the provider does not report them and they are estimates.
+	InetDownMbps       float64 `json:"inet_down_mbps,omitempty"`
+	InetUpMbps         float64 `json:"inet_up_mbps,omitempty"`
+	DiskBandwidthMBps  float64 `json:"disk_bw_mbps,omitempty"`
+	BandwidthEstimated bool    `json:"bandwidth_estimated,omitempty"`
 
 	// CompatibleTemplates lists templates that can run on this offer.
 	// Only populated when include_templates=true is requested, and only for Vast.ai offers.
 	CompatibleTemplates []CompatibleTemplate `json:"compatible_templates,omitempty"`
 }
 
+// InventorySummary aggregates the current offers for one GPU type. Prices
+// are per GPU-hour so that multi-GPU offers compare with single-GPU ones.
+type InventorySummary struct {
+	GPUType                   string   `json:"gpu_type"`
+	OfferCount                int      `json:"offer_count"`
+	MinPrice                  float64  `json:"min_price"`
+	MedianPrice               float64  `json:"median_price"`
+	Providers                 []string `json:"providers"`
+	AvgAvailabilityConfidence float64  `json:"avg_availability_confidence"`
+}
+
 // OfferFilter defines criteria for filtering GPU offers
 type OfferFilter struct {
 	Provider                  string  `json:"provider,omitempty"`                    // Filter by provider
@@ -50,6 +70,18 @@ type OfferFilter struct {
 	MinGPUCount               int     `json:"min_gpu_count,omitempty"`               // Minimum GPU count
 	MinAvailabilityConfidence float64 `json:"min_availability_confidence,omitempty"` // Minimum availability confidence (0-1)
 	MinCUDAVersion            float64 `json:"min_cuda_version,omitempty"`            // Minimum CUDA version (e.g., 12.9)
+	MinDriverVersion          string  `json:"min_driver_version,omitempty"`          // Minimum NVIDIA driver version (e.g., "535.104")
+	MinInetDownMbps           float64 `json:"min_inet_down_mbps,omitempty"`          // Minimum download bandwidth in Mbps
+	MinDiskBandwidthMBps      float64 `json:"min_disk_bw_mbps,omitempty"`            // Minimum disk bandwidth in MB/s
+	Interruptible             bool    `json:"interruptible,omitempty"`               // List interruptible (bid) offers instead of on-demand
+
+	// Regions restricts offers to these regions (see Regions) or ISO country
+	// codes; offers in any of them match
+	Regions []string `json:"regions,omitempty"`
+
+	// AllowedRegions applies the consumer's data-residency policy on top of
+	// Regions. It is set by the provisioner, never taken from callers.
+	AllowedRegions []string `json:"-"`
 }
 
 // MatchesFilter checks if the offer matches the given filter
@@ -81,9 +113,58 @@ func (o *GPUOffer) MatchesFilter(f OfferFilter) bool {
 	if f.MinCUDAVersion > 0 && o.CUDAVersion < f.MinCUDAVersion {
 		return false
 	}
+	if f.MinDriverVersion != "" && CompareVersions(o.DriverVersion, f.MinDriverVersion) < 0 {
+		return false
+	}
+	if f.MinInetDownMbps > 0 && o.InetDownMbps < f.MinInetDownMbps {
+		return false
+	}
+	if f.MinDiskBandwidthMBps > 0 && o.DiskBandwidthMBps < f.MinDiskBandwidthMBps {
+		return false
+	}
+	if o.Interruptible != f.Interruptible {
+		return false
+	}
+	if len(f.Regions) > 0 && !o.InRegions(f.Regions) {
+		return false
+	}
+	if len(f.AllowedRegions) > 0 && !o.InRegions(f.AllowedRegions) {
+		return false
+	}
 	return true
 }
 
+// CompareVersions compares dotted numeric versions such as driver versions
+// ("535.104.05"), returning -1, 0 or 1. Missing parts count as zero, so
+// "535" equals "535.0", and an empty version is older than any other.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(strings.TrimSpace(as[i]))
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(strings.TrimSpace(bs[i]))
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// PricePerGPUHour returns the offer price divided by its GPU count
+func (o *GPUOffer) PricePerGPUHour() float64 {
+	if o.GPUCount > 1 {
+		return o.PricePerHour / float64(o.GPUCount)
+	}
+	return o.PricePerHour
+}
+
 // GetEffectiveAvailabilityConfidence returns the availability confidence,
 // defaulting to 1.0 if not explicitly set (for backwards compatibility)
 func (o *GPUOffer) GetEffectiveAvailabilityConfidence() float64 {
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"535.104.05", "535.104.05", 0},
+		{"535", "535.0", 0},
+		{"535.104.05", "535.86.10", 1},
+		{"525.60.13", "535.104", -1},
+		{"550", "535.104.05", 1},
+		{"", "535", -1},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CompareVersions(tt.a, tt.b))
+		})
+	}
+}
+
+func TestGPUOffer_MatchesFilter_Versions(t *testing.T) {
+	offer := GPUOffer{CUDAVersion: 12.2, DriverVersion: "535.104.05"}
+
+	assert.True(t, offer.MatchesFilter(OfferFilter{MinCUDAVersion: 12.1, MinDriverVersion: "535.104"}))
+	assert.False(t, offer.MatchesFilter(OfferFilter{MinCUDAVersion: 12.4}))
+	assert.False(t, offer.MatchesFilter(OfferFilter{MinDriverVersion: "550"}))
+
+	// Offers that do not advertise a driver version never meet a minimum
+	unknown := GPUOffer{CUDAVersion: 12.2}
+	assert.True(t, unknown.MatchesFilter(OfferFilter{}))
+	assert.False(t, unknown.MatchesFilter(OfferFilter{MinDriverVersion: "470"}))
+}
+
+func TestGPUOffer_MatchesFilter_Throughput(t *testing.T) {
+	offer := GPUOffer{InetDownMbps: 940, DiskBandwidthMBps: 2100}
+
+	assert.True(t, offer.MatchesFilter(OfferFilter{MinInetDownMbps: 500, MinDiskBandwidthMBps: 1000}))
+	assert.False(t, offer.MatchesFilter(OfferFilter{MinInetDownMbps: 1000}))
+	assert.False(t, offer.MatchesFilter(OfferFilter{MinDiskBandwidthMBps: 3000}))
+
+	// Offers with unknown throughput never meet a minimum
+	unknown := GPUOffer{}
+	assert.False(t, unknown.MatchesFilter(OfferFilter{MinInetDownMbps: 1}))
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// InstanceRelease records that this deployment disowns a shopper-labeled
+// provider instance, such as another deployment's, so the reconciler never
+// treats it as an orphan
+type InstanceRelease struct {
+	Provider     string    `json:"provider"`
+	InstanceID   string    `json:"instance_id"`
+	DeploymentID string    `json:"deployment_id,omitempty"` // Deployment that released it
+	ReleasedBy   string    `json:"released_by,omitempty"`
+	ReleasedAt   time.Time `json:"released_at"`
+}
+
+// UnclaimedInstance is a shopper-labeled provider instance that no active
+// session of this deployment owns
+type UnclaimedInstance struct {
+	Provider     string     `json:"provider"`
+	InstanceID   string     `json:"instance_id"`
+	Name         string     `json:"name,omitempty"`
+	Status       string     `json:"status"`
+	PricePerHour float64    `json:"price_per_hour"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	SessionID    string     `json:"session_id,omitempty"`    // From the instance's label
+	DeploymentID string     `json:"deployment_id,omitempty"` // From the instance's tag, if the provider keeps one
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`    // From the instance's tag
+	// Orphan is true when the reconciler counts the instance as this
+	// deployment's orphan, and destroys it
+	Orphan bool `json:"orphan"`
+	// Released is true when this deployment released the instance
+	Released bool `json:"released"`
+}
+
+// UnclaimedProvider summarizes unclaimed instances for one provider
+type UnclaimedProvider struct {
+	Provider  string `json:"provider"`
+	Unclaimed int    `json:"unclaimed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// UnclaimedInstances lists the shopper-labeled instances the providers report
+// that no active session owns, to be adopted or released
+type UnclaimedInstances struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Providers   []UnclaimedProvider `json:"providers"`
+	Instances   []UnclaimedInstance `json:"instances"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// OfferRuleAction is what an offer rule does to the offers it matches
+type OfferRuleAction string
+
+const (
+	// OfferRuleSuppress hides matching offers from inventory results
+	OfferRuleSuppress OfferRuleAction = "suppress"
+	// OfferRuleAllow exempts matching offers from automatic failure
+	// suppression and confidence degradation
+	OfferRuleAllow OfferRuleAction = "allow"
+)
+
+// OfferRuleScope is what an offer rule's value identifies
+type OfferRuleScope string
+
+const (
+	OfferRuleScopeOffer    OfferRuleScope = "offer"    // A single offer ID
+	OfferRuleScopeMachine  OfferRuleScope = "machine"  // Every offer on a physical host
+	OfferRuleScopeLocation OfferRuleScope = "location" // A full location or a country code
+)
+
+// OfferRule is an operator-managed suppression or allowlist entry. Suppress
+// rules take precedence over allow rules when both match an offer.
+type OfferRule struct {
+	ID        string          `json:"id"`
+	Action    OfferRuleAction `json:"action"`
+	Scope     OfferRuleScope  `json:"scope"`
+	Value     string          `json:"value"`
+	Provider  string          `json:"provider,omitempty"` // Empty = any provider
+	Reason    string          `json:"reason,omitempty"`
+	CreatedBy string          `json:"created_by,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"` // Nil = until removed
+}
+
+// IsValid reports whether the rule has a known action and scope and a value
+func (r *OfferRule) IsValid() bool {
+	switch r.Action {
+	case OfferRuleSuppress, OfferRuleAllow:
+	default:
+		return false
+	}
+	switch r.Scope {
+	case OfferRuleScopeOffer, OfferRuleScopeMachine, OfferRuleScopeLocation:
+	default:
+		return false
+	}
+	return strings.TrimSpace(r.Value) != ""
+}
+
+// IsActive reports whether the rule has not expired at now
+func (r *OfferRule) IsActive(now time.Time) bool {
+	return r.ExpiresAt == nil || now.Before(*r.ExpiresAt)
+}
+
+// Matches reports whether the rule applies to the offer. Location rules
+// match the offer's full location or its country code, ignoring case.
+func (r *OfferRule) Matches(offer *GPUOffer) bool {
+	if r.Provider != "" && !strings.EqualFold(r.Provider, offer.Provider) {
+		return false
+	}
+	switch r.Scope {
+	case OfferRuleScopeOffer:
+		return offer.ID == r.Value
+	case OfferRuleScopeMachine:
+		return offer.MachineID != "" && offer.MachineID == r.Value
+	case OfferRuleScopeLocation:
+		value := strings.TrimSpace(r.Value)
+		return strings.EqualFold(strings.TrimSpace(offer.Location), value) ||
+			(offer.CountryCode() != "" && strings.EqualFold(offer.CountryCode(), value))
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfferRule_Matches(t *testing.T) {
+	offer := &GPUOffer{ID: "vastai-1", Provider: "vastai", MachineID: "4242", Location: "Frankfurt, Hesse, Germany"}
+
+	tests := []struct {
+		name     string
+		rule     OfferRule
+		expected bool
+	}{
+		{"offer ID", OfferRule{Scope: OfferRuleScopeOffer, Value: "vastai-1"}, true},
+		{"other offer", OfferRule{Scope: OfferRuleScopeOffer, Value: "vastai-2"}, false},
+		{"machine", OfferRule{Scope: OfferRuleScopeMachine, Value: "4242"}, true},
+		{"machine of provider", OfferRule{Scope: OfferRuleScopeMachine, Value: "4242", Provider: "VastAI"}, true},
+		{"machine of other provider", OfferRule{Scope: OfferRuleScopeMachine, Value: "4242", Provider: "tensordock"}, false},
+		{"full location", OfferRule{Scope: OfferRuleScopeLocation, Value: "frankfurt, hesse, germany"}, true},
+		{"country code", OfferRule{Scope: OfferRuleScopeLocation, Value: "de"}, true},
+		{"other country", OfferRule{Scope: OfferRuleScopeLocation, Value: "FR"}, false},
+		{"partial location", OfferRule{Scope: OfferRuleScopeLocation, Value: "Hesse"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Matches(offer))
+		})
+	}
+
+	// Offers without a machine ID never match machine rules
+	assert.False(t, (&OfferRule{Scope: OfferRuleScopeMachine, Value: ""}).Matches(&GPUOffer{}))
+}
+
+func TestOfferRule_IsValidAndActive(t *testing.T) {
+	assert.True(t, (&OfferRule{Action: OfferRuleAllow, Scope: OfferRuleScopeLocation, Value: "EU"}).IsValid())
+	assert.False(t, (&OfferRule{Action: "block", Scope: OfferRuleScopeOffer, Value: "x"}).IsValid())
+	assert.False(t, (&OfferRule{Action: OfferRuleSuppress, Scope: "region", Value: "x"}).IsValid())
+	assert.False(t, (&OfferRule{Action: OfferRuleSuppress, Scope: OfferRuleScopeOffer, Value: " "}).IsValid())
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	assert.True(t, (&OfferRule{}).IsActive(now))
+	assert.True(t, (&OfferRule{ExpiresAt: &future}).IsActive(now))
+	assert.False(t, (&OfferRule{ExpiresAt: &past}).IsActive(now))
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// PriceSnapshot summarizes the prices of available offers for one GPU type on
+// one provider at a point in time. Prices are per GPU-hour so multi-GPU offers
+// are comparable. Snapshots older than the raw retention window are
+// downsampled into hourly buckets.
+type PriceSnapshot struct {
+	Provider   string    `json:"provider"`
+	GPUType    string    `json:"gpu_type"`
+	MinPrice   float64   `json:"min_price"`
+	AvgPrice   float64   `json:"avg_price"`
+	MaxPrice   float64   `json:"max_price"`
+	OfferCount int       `json:"offer_count"`
+	SampledAt  time.Time `json:"sampled_at"`
+}
+
+// PriceHistoryFilter defines filters for querying price history
+type PriceHistoryFilter struct {
+	GPUType  string // Required; matched case-insensitively
+	Provider string
+	Since    time.Time
+}
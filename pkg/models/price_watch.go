@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// PriceWatch asks to be notified when an offer for a GPU type appears at or
+// below a price. MaxPrice is per GPU-hour so multi-GPU offers are compared
+// fairly.
+type PriceWatch struct {
+	ID         string     `json:"id"`
+	ConsumerID string     `json:"consumer_id"`
+	GPUType    string     `json:"gpu_type"`           // Matched case-insensitively
+	MaxPrice   float64    `json:"max_price"`          // USD per GPU-hour
+	Region     string     `json:"region,omitempty"`   // Substring of the offer location; empty matches any
+	Provider   string     `json:"provider,omitempty"` // Empty matches any provider
+	Matching   bool       `json:"matching"`           // A matching offer was present at the last evaluation
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Matches reports whether an offer satisfies the watch
+func (w *PriceWatch) Matches(offer GPUOffer) bool {
+	if !offer.Available || offer.PricePerHour <= 0 {
+		return false
+	}
+	if !strings.EqualFold(offer.GPUType, w.GPUType) {
+		return false
+	}
+	if w.Provider != "" && offer.Provider != w.Provider {
+		return false
+	}
+	if w.Region != "" && !strings.Contains(strings.ToLower(offer.Location), strings.ToLower(w.Region)) {
+		return false
+	}
+	return offer.PricePerGPUHour() <= w.MaxPrice
+}
+
+// PriceWatchAlert is the payload sent when a watch starts matching
+type PriceWatchAlert struct {
+	Watch      *PriceWatch `json:"watch"`
+	Offer      GPUOffer    `json:"offer"`       // Cheapest matching offer
+	MatchCount int         `json:"match_count"` // Number of matching offers
+}
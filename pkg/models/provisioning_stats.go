@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ProvisioningTimeStat is the historical time from session creation to a
+// verified SSH connection for one provider and GPU type
+type ProvisioningTimeStat struct {
+	Provider   string  `json:"provider"`
+	GPUType    string  `json:"gpu_type"`
+	Samples    int     `json:"samples"`
+	AvgSeconds float64 `json:"avg_seconds"`
+}
+
+// LocationStats counts provisioning attempts at one provider location, so a
+// provider can estimate how likely its listed offers there are to provision
+type LocationStats struct {
+	Provider      string    `json:"provider"`
+	LocationID    string    `json:"location_id"`
+	Attempts      int       `json:"attempts"`
+	Successes     int       `json:"successes"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+	LastSucceeded bool      `json:"last_succeeded"`
+}
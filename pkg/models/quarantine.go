@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// QuarantineSource says how an instance came to be quarantined
+type QuarantineSource string
+
+const (
+	// QuarantineOrphan is an orphan the reconciler failed to destroy
+	QuarantineOrphan QuarantineSource = "orphan"
+	// QuarantineDestroy is a session's instance whose destruction could not
+	// be verified
+	QuarantineDestroy QuarantineSource = "destroy"
+)
+
+// QuarantinedInstance is a provider instance that could not be safely removed.
+// It may still be running and billing, so destroying it is re-attempted on a
+// schedule, and it stays listed until the provider confirms it is gone or an
+// operator resolves it.
+type QuarantinedInstance struct {
+	Provider      string           `json:"provider"`
+	InstanceID    string           `json:"instance_id"`
+	SessionID     string           `json:"session_id,omitempty"`
+	Source        QuarantineSource `json:"source"`
+	Reason        string           `json:"reason"`   // Last failure
+	Failures      int              `json:"failures"` // Failed destroys so far
+	FirstSeenAt   time.Time        `json:"first_seen_at"`
+	LastSeenAt    time.Time        `json:"last_seen_at"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"` // Next scheduled destroy
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// ProviderUsage is the latest provider-reported billing seen for a session's
+// instance. It is refreshed while the session runs and kept after it ends.
+type ProviderUsage struct {
+	SessionID  string    `json:"session_id"`
+	Provider   string    `json:"provider"`
+	HourlyRate float64   `json:"hourly_rate"` // All-in rate in USD at ObservedAt
+	Total      float64   `json:"total"`       // Billed through ObservedAt in USD
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// CostReconciliationEntry compares one session's recorded cost with what its
+// provider reports
+type CostReconciliationEntry struct {
+	SessionID  string `json:"session_id"`
+	ConsumerID string `json:"consumer_id"`
+	Provider   string `json:"provider"`
+	GPUType    string `json:"gpu_type"`
+
+	RecordedCost float64 `json:"recorded_cost"` // Cost records for the session, all line items
+
+	// Provider-reported cost, extended at the reported rate from the last
+	// observation to when the session stopped (or now). Unset when the
+	// provider does not report usage or it was never observed.
+	ReportedCost   *float64   `json:"reported_cost,omitempty"`
+	ObservedAt     *time.Time `json:"observed_at,omitempty"`
+	Discrepancy    float64    `json:"discrepancy"`     // Recorded minus reported
+	DiscrepancyPct float64    `json:"discrepancy_pct"` // |Discrepancy| / reported, as a percentage
+	Flagged        bool       `json:"flagged"`         // DiscrepancyPct above the threshold
+}
+
+// CostReconciliation is a report of recorded against provider-reported cost
+// for the sessions billed in a period
+type CostReconciliation struct {
+	GeneratedAt  time.Time                 `json:"generated_at"`
+	PeriodStart  time.Time                 `json:"period_start"`
+	PeriodEnd    time.Time                 `json:"period_end"`
+	ThresholdPct float64                   `json:"threshold_pct"`
+	Sessions     []CostReconciliationEntry `json:"sessions"`
+	Recorded     float64                   `json:"recorded_total"`
+	Reported     float64                   `json:"reported_total"` // Verified sessions only
+	Verified     int                       `json:"verified"`
+	Unverified   int                       `json:"unverified"`
+	Flagged      int                       `json:"flagged"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import "strings"
+
+// Regions groups ISO 3166-1 alpha-2 country codes under names that can be
+// used wherever a country code is accepted, e.g. to keep EU data in EU
+// datacenters
+var Regions = map[string][]string{
+	"EU":   euCountries,
+	"EEA":  append([]string{"IS", "LI", "NO"}, euCountries...),
+	"NA":   {"US", "CA", "MX"},
+	"APAC": {"AU", "CN", "HK", "ID", "IN", "JP", "KR", "MY", "NZ", "PH", "SG", "TH", "TW", "VN"},
+}
+
+var euCountries = []string{
+	"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU",
+	"IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK",
+}
+
+// countryNames maps the full country names providers report to country codes
+var countryNames = map[string]string{
+	"australia": "AU", "austria": "AT", "belgium": "BE", "brazil": "BR", "bulgaria": "BG",
+	"canada": "CA", "chile": "CL", "china": "CN", "croatia": "HR", "cyprus": "CY",
+	"czech republic": "CZ", "czechia": "CZ", "denmark": "DK", "estonia": "EE", "finland": "FI",
+	"france": "FR", "germany": "DE", "greece": "GR", "hong kong": "HK", "hungary": "HU",
+	"iceland": "IS", "india": "IN", "indonesia": "ID", "ireland": "IE", "israel": "IL",
+	"italy": "IT", "japan": "JP", "latvia": "LV", "liechtenstein": "LI", "lithuania": "LT",
+	"luxembourg": "LU", "malaysia": "MY", "malta": "MT", "mexico": "MX", "netherlands": "NL",
+	"the netherlands": "NL", "new zealand": "NZ", "norway": "NO", "philippines": "PH",
+	"poland": "PL", "portugal": "PT", "romania": "RO", "serbia": "RS", "singapore": "SG",
+	"slovakia": "SK", "slovenia": "SI", "south africa": "ZA", "south korea": "KR", "korea": "KR",
+	"spain": "ES", "sweden": "SE", "switzerland": "CH", "taiwan": "TW", "thailand": "TH",
+	"turkey": "TR", "ukraine": "UA", "united arab emirates": "AE", "united kingdom": "GB",
+	"uk": "GB", "great britain": "GB", "united states": "US", "united states of america": "US",
+	"usa": "US", "vietnam": "VN",
+}
+
+// CountryCode returns the offer's ISO country code, read from the last part
+// of its location ("California, US", "Frankfurt, Hesse, Germany"), or ""
+// when the location does not name a known country
+func (o *GPUOffer) CountryCode() string {
+	location := o.Location
+	if i := strings.LastIndex(location, ","); i >= 0 {
+		location = location[i+1:]
+	}
+	location = strings.TrimSpace(location)
+
+	if isCountryCode(strings.ToUpper(location)) {
+		return strings.ToUpper(location)
+	}
+	return countryNames[strings.ToLower(location)]
+}
+
+// InRegions reports whether the offer is in one of the given regions or
+// countries. Offers whose country is unknown match no region.
+func (o *GPUOffer) InRegions(regions []string) bool {
+	country := o.CountryCode()
+	if country == "" {
+		return false
+	}
+	for _, region := range regions {
+		region = strings.ToUpper(strings.TrimSpace(region))
+		if region == country {
+			return true
+		}
+		for _, member := range Regions[region] {
+			if member == country {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsValidRegion reports whether name is a region in Regions or has the shape
+// of an ISO country code
+func IsValidRegion(name string) bool {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if _, ok := Regions[name]; ok {
+		return true
+	}
+	return isCountryCode(name)
+}
+
+// isCountryCode reports whether s has the shape of an upper-case ISO code
+func isCountryCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGPUOffer_CountryCode(t *testing.T) {
+	tests := []struct {
+		location string
+		expected string
+	}{
+		{"California, US", "US"},
+		{"Sweden, se", "SE"},
+		{"Frankfurt, Hesse, Germany", "DE"},
+		{"Manchester, England, United Kingdom", "GB"},
+		{"Helsinki, , Finland", "FI"},
+		{"NL", "NL"},
+		{"lab", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			offer := GPUOffer{Location: tt.location}
+			assert.Equal(t, tt.expected, offer.CountryCode())
+		})
+	}
+}
+
+func TestGPUOffer_InRegions(t *testing.T) {
+	frankfurt := GPUOffer{Location: "Frankfurt, Hesse, Germany"}
+	oslo := GPUOffer{Location: "Oslo, NO"}
+	virginia := GPUOffer{Location: "Virginia, US"}
+	unknown := GPUOffer{Location: "lab"}
+
+	assert.True(t, frankfurt.InRegions([]string{"EU"}))
+	assert.True(t, frankfurt.InRegions([]string{"de"}))
+	assert.False(t, oslo.InRegions([]string{"EU"}), "Norway is in the EEA but not the EU")
+	assert.True(t, oslo.InRegions([]string{"eea"}))
+	assert.False(t, virginia.InRegions([]string{"EU", "CH"}))
+	assert.True(t, virginia.InRegions([]string{"EU", "NA"}))
+	assert.False(t, unknown.InRegions([]string{"EU", "NA", "APAC"}), "unknown locations match no region")
+
+	filter := OfferFilter{Regions: []string{"EEA"}, AllowedRegions: []string{"EU"}}
+	assert.True(t, frankfurt.MatchesFilter(filter))
+	assert.False(t, oslo.MatchesFilter(filter), "both the filter and the consumer policy apply")
+}
+
+func TestIsValidRegion(t *testing.T) {
+	assert.True(t, IsValidRegion("EU"))
+	assert.True(t, IsValidRegion("apac"))
+	assert.True(t, IsValidRegion("de"))
+	assert.False(t, IsValidRegion("Europe"))
+	assert.False(t, IsValidRegion("D1"))
+	assert.False(t, IsValidRegion(""))
+}
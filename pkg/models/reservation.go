@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ReservationStatus is the state of a queued session request
+type ReservationStatus string
+
+const (
+	ReservationWaiting   ReservationStatus = "waiting"   // No matching offer yet
+	ReservationFulfilled ReservationStatus = "fulfilled" // A session was created
+	ReservationExpired   ReservationStatus = "expired"   // Max wait elapsed without a match
+	ReservationCancelled ReservationStatus = "cancelled" // Cancelled by the consumer
+	ReservationFailed    ReservationStatus = "failed"    // The request can never succeed (e.g. invalid disk size)
+)
+
+// QueuedReservation is a session request waiting for inventory. It is
+// fulfilled with the cheapest available offer matching Filter, in the order
+// requests were queued, or expires at ExpiresAt.
+type QueuedReservation struct {
+	ID          string               `json:"id"`
+	ConsumerID  string               `json:"consumer_id"`
+	Filter      OfferFilter          `json:"filter"`
+	Request     CreateSessionRequest `json:"-"` // OfferID is set when an offer is chosen
+	Status      ReservationStatus    `json:"status"`
+	Position    int                  `json:"position,omitempty"` // 1-based place among waiting requests; computed, not stored
+	SessionID   string               `json:"session_id,omitempty"`
+	Attempts    int                  `json:"attempts"` // Offers tried so far
+	LastError   string               `json:"last_error,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+	FulfilledAt *time.Time           `json:"fulfilled_at,omitempty"`
+}
@@ -9,29 +9,48 @@ const (
 	StatusPending      SessionStatus = "pending"      // Session created, not yet provisioned
 	StatusProvisioning SessionStatus = "provisioning" // Provider instance being created
 	StatusRunning      SessionStatus = "running"      // Instance running and SSH accessible
+	StatusPaused       SessionStatus = "paused"       // Instance stopped with its disk kept; not billed for compute
 	StatusStopping     SessionStatus = "stopping"     // Destruction in progress
 	StatusStopped      SessionStatus = "stopped"      // Successfully terminated
 	StatusFailed       SessionStatus = "failed"       // Failed to provision or crashed
+	StatusPreempted    SessionStatus = "preempted"    // Instance reclaimed or terminated by the provider
+)
+
+// ProvisionPhase is the step of provisioning a session has reached. It
+// refines StatusPending and StatusProvisioning, and is persisted on every
+// transition so a verification interrupted by a restart can be resumed.
+type ProvisionPhase string
+
+const (
+	PhasePending      ProvisionPhase = "pending"      // Session recorded, provider not called yet
+	PhaseProvisioning ProvisionPhase = "provisioning" // Provider creating the instance
+	PhaseBooting      ProvisionPhase = "booting"      // Instance created, waiting for it to boot
+	PhaseWarmingUp    ProvisionPhase = "warming_up"   // Entrypoint workload pulling its image and loading weights
+	PhaseVerifying    ProvisionPhase = "verifying"    // Polling SSH or the workload API
+	PhaseRunning      ProvisionPhase = "running"      // Verified and ready for use
 )
 
 // WorkloadType represents the type of workload for the session
 type WorkloadType string
 
 const (
-	WorkloadLLM         WorkloadType = "llm"         // LLM inference hosting (generic)
-	WorkloadLLMVLLM     WorkloadType = "llm_vllm"    // LLM inference via vLLM
-	WorkloadLLMTGI      WorkloadType = "llm_tgi"     // LLM inference via TGI
-	WorkloadTraining    WorkloadType = "training"    // ML model training
-	WorkloadBatch       WorkloadType = "batch"       // Batch processing job
-	WorkloadInteractive WorkloadType = "interactive" // Interactive SSH session
-	WorkloadInference   WorkloadType = "inference"   // Generic inference
-	WorkloadSSH         WorkloadType = "ssh"         // SSH access (alias for interactive)
-	WorkloadBenchmark   WorkloadType = "benchmark"   // Automated GPU benchmark
+	WorkloadLLM         WorkloadType = "llm"          // LLM inference hosting (generic)
+	WorkloadLLMVLLM     WorkloadType = "llm_vllm"     // LLM inference via vLLM
+	WorkloadLLMTGI      WorkloadType = "llm_tgi"      // LLM inference via TGI
+	WorkloadLLMSGLang   WorkloadType = "llm_sglang"   // LLM inference via SGLang
+	WorkloadLLMLlamaCpp WorkloadType = "llm_llamacpp" // LLM inference via llama.cpp server (GGUF)
+	WorkloadTraining    WorkloadType = "training"     // ML model training
+	WorkloadBatch       WorkloadType = "batch"        // Batch processing job
+	WorkloadInteractive WorkloadType = "interactive"  // Interactive SSH session
+	WorkloadInference   WorkloadType = "inference"    // Generic inference
+	WorkloadSSH         WorkloadType = "ssh"          // SSH access (alias for interactive)
+	WorkloadBenchmark   WorkloadType = "benchmark"    // Automated GPU benchmark
 )
 
 // ValidWorkloadTypes enumerates all accepted workload type values.
 var ValidWorkloadTypes = map[WorkloadType]bool{
 	WorkloadLLM: true, WorkloadLLMVLLM: true, WorkloadLLMTGI: true,
+	WorkloadLLMSGLang: true, WorkloadLLMLlamaCpp: true,
 	WorkloadTraining: true, WorkloadBatch: true, WorkloadInteractive: true,
 	WorkloadInference: true, WorkloadSSH: true, WorkloadBenchmark: true,
 }
@@ -80,6 +99,16 @@ type Session struct {
 	GPUCount   int           `json:"gpu_count"`
 	Status     SessionStatus `json:"status"`
 	Error      string        `json:"error,omitempty"`
+	Location   string        `json:"location,omitempty"` // Offer's geographic location
+
+	// Provisioning progress; kept after a failure to show where it failed
+	ProvisionPhase ProvisionPhase    `json:"provision_phase,omitempty"`
+	VerifyDeadline time.Time         `json:"verify_deadline,omitempty"` // When verification of a booting instance times out
+	Progress       ProvisionProgress `json:"progress"`
+
+	// Why the session failed or was lost, for failure analytics
+	FailureCategory FailureCategory `json:"failure_category,omitempty"`
+	FailureDetail   string          `json:"failure_detail,omitempty"` // e.g. SSH error type or instance status
 
 	// Connection details (SSH mode)
 	SSHHost       string `json:"ssh_host,omitempty"`
@@ -88,6 +117,27 @@ type Session struct {
 	SSHPrivateKey string `json:"ssh_private_key,omitempty"` // Only returned once at creation
 	SSHPublicKey  string `json:"-"`                         // Stored but not exposed
 
+	// Physical host the offer ran on (GPUOffer.MachineID), used to track
+	// failures per host across offer IDs
+	MachineID string `json:"machine_id,omitempty"`
+
+	// SHA256 fingerprint of the instance's SSH host key, recorded on the first
+	// successful connection and verified on every later one
+	SSHHostKeyFingerprint string `json:"ssh_host_key_fingerprint,omitempty"`
+
+	// CUDA and driver versions the offer advertised, checked against the
+	// instance once SSH is reachable
+	CUDAVersion   float64 `json:"cuda_version,omitempty"`
+	DriverVersion string  `json:"driver_version,omitempty"`
+
+	// Per-GPU VRAM in GB the offer advertised, checked against the instance
+	// along with its GPU model once SSH is reachable
+	VRAM int `json:"vram_gb,omitempty"`
+
+	// Throughput measured on the instance after provisioning, 0 if not measured
+	MeasuredInetDownMbps      float64 `json:"measured_inet_down_mbps,omitempty"`
+	MeasuredDiskBandwidthMBps float64 `json:"measured_disk_bw_mbps,omitempty"`
+
 	// API endpoint details (entrypoint mode)
 	LaunchMode  LaunchMode `json:"launch_mode,omitempty"`
 	APIEndpoint string     `json:"api_endpoint,omitempty"` // Full URL to API (e.g., http://host:port)
@@ -106,6 +156,23 @@ type Session struct {
 	// Storage configuration
 	DiskGB int `json:"disk_gb,omitempty"` // Disk space in GB (cannot be changed after creation)
 
+	// Script run over SSH once the session is verified; stored but not
+	// exposed, as it may hold credentials
+	BootstrapScript string `json:"-"`
+
+	// HuggingFace token for gated and private models, never exposed. Only
+	// accepted with database encryption, so it is stored encrypted.
+	HFToken string `json:"-"`
+
+	// Custom start-up commands and cloud-init from the create request, kept
+	// for failover replacements but never exposed, as they may hold
+	// credentials. Encrypted when database encryption is configured.
+	OnStartCmd string           `json:"-"`
+	CloudInit  *CloudInitConfig `json:"-"`
+
+	// Load-test the GPUs before the session is handed over (SSH mode only)
+	GPUBurnIn bool `json:"gpu_burn_in,omitempty"`
+
 	// Auto-retry configuration (set at creation)
 	AutoRetry  bool   `json:"auto_retry,omitempty"`
 	MaxRetries int    `json:"max_retries,omitempty"`
@@ -117,20 +184,41 @@ type Session struct {
 	RetryChildID  string `json:"retry_child_id,omitempty"`  // New session created by retry
 	FailedOffers  string `json:"failed_offers,omitempty"`   // Comma-separated list of offer IDs that failed
 
+	// Sessions provisioned together (e.g. by a batch request) share a group
+	GroupID string `json:"group_id,omitempty"`
+
 	// Configuration
 	WorkloadType    WorkloadType  `json:"workload_type"`
 	ReservationHrs  int           `json:"reservation_hours"`
 	HardMaxOverride bool          `json:"hard_max_override"`
-	IdleThreshold   int           `json:"idle_threshold_minutes"` // 0 = disabled
+	IdleThreshold   int           `json:"idle_threshold_minutes"`      // 0 = disabled
+	IdleGPUUtilPct  int           `json:"idle_gpu_util_pct,omitempty"` // GPU utilization below which the session is idle (0 = DefaultIdleGPUUtilPct)
 	StoragePolicy   StoragePolicy `json:"storage_policy"`
 
 	// Cost tracking
 	PricePerHour float64 `json:"price_per_hour"`
 
+	// Latest heartbeat, recorded by the lifecycle manager while running
+	Health SessionHealth `json:"health"`
+
+	// Interruptible (bid) instances can be reclaimed when outbid
+	Interruptible bool    `json:"interruptible,omitempty"`
+	BidPrice      float64 `json:"bid_price,omitempty"` // USD per hour bid for the instance
+
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 	StoppedAt time.Time `json:"stopped_at,omitempty"`
+
+	// Pausing: when the current pause began (zero unless paused), and how
+	// long earlier pauses lasted in total
+	PausedAt    time.Time     `json:"paused_at,omitempty"`
+	PausedTotal time.Duration `json:"-"`
+
+	// RebootingUntil is when an in-place reboot of the instance under way
+	// gives up (zero unless rebooting). It is stored so no server takes the
+	// instance for stopped or lost meanwhile.
+	RebootingUntil time.Time `json:"-"`
 }
 
 // CreateSessionRequest is the request to create a new session
@@ -140,6 +228,7 @@ type CreateSessionRequest struct {
 	WorkloadType   WorkloadType  `json:"workload_type" binding:"required"`
 	ReservationHrs int           `json:"reservation_hours" binding:"required,min=1,max=12"`
 	IdleThreshold  int           `json:"idle_threshold_minutes,omitempty"`
+	IdleGPUUtilPct int           `json:"idle_gpu_util_pct,omitempty"`
 	StoragePolicy  StoragePolicy `json:"storage_policy,omitempty"`
 
 	// Entrypoint mode configuration
@@ -161,41 +250,67 @@ type CreateSessionRequest struct {
 	MaxRetries int    `json:"max_retries,omitempty"`
 	RetryScope string `json:"retry_scope,omitempty"` // "same_gpu", "same_vram", "any"
 
+	// Bid for interruptible offers in USD per hour (0 = offer's minimum bid)
+	BidPrice float64 `json:"bid_price,omitempty"`
+
 	// On-start command (injected by benchmark runner or user)
 	OnStartCmd string `json:"on_start_cmd,omitempty"` // Script to run after provisioning
 
+	// Custom cloud-init merged after the shopper's own (providers with cloud-init only)
+	CloudInit *CloudInitConfig `json:"cloud_init,omitempty"`
+
+	// Script run over SSH once the session is verified (SSH mode only)
+	BootstrapScript string `json:"bootstrap_script,omitempty"`
+
+	// Load-test the GPUs before the session turns running (SSH mode only)
+	GPUBurnIn bool `json:"gpu_burn_in,omitempty"`
+
 	// SSH timeout override
 	SSHTimeoutMinutes int `json:"ssh_timeout_minutes,omitempty"` // Client-specified SSH timeout (1-30 min)
 
 	// Internal fields (set by handler, not from JSON)
+	GroupID                       string        `json:"-"` // Session group the session joins
+	HFToken                       string        `json:"-"` // HuggingFace token; kept out of JSON, which is stored in plaintext
 	TemplateRecommendedDiskGB     int           `json:"-"` // Template's recommended disk, used for estimation floor
 	TemplateRecommendedSSHTimeout time.Duration `json:"-"` // BUG-005: Template's recommended SSH timeout for heavy images
 }
 
 // SessionResponse is the API response for a session (hides sensitive fields after creation)
 type SessionResponse struct {
-	ID             string        `json:"id"`
-	ConsumerID     string        `json:"consumer_id"`
-	Provider       string        `json:"provider"`
-	GPUType        string        `json:"gpu_type"`
-	GPUCount       int           `json:"gpu_count"`
-	Status         SessionStatus `json:"status"`
-	Error          string        `json:"error,omitempty"`
-	SSHHost        string        `json:"ssh_host,omitempty"`
-	SSHPort        int           `json:"ssh_port,omitempty"`
-	SSHUser        string        `json:"ssh_user,omitempty"`
-	LaunchMode     LaunchMode    `json:"launch_mode,omitempty"`
-	APIEndpoint    string        `json:"api_endpoint,omitempty"`
-	APIPort        int           `json:"api_port,omitempty"`
-	ModelID        string        `json:"model_id,omitempty"`
-	TemplateHashID string        `json:"template_hash_id,omitempty"` // Vast.ai template used
-	TemplateName   string        `json:"template_name,omitempty"`    // Template name for display
-	DiskGB         int           `json:"disk_gb,omitempty"`          // Disk space in GB
-	WorkloadType   WorkloadType  `json:"workload_type"`
-	ReservationHrs int           `json:"reservation_hours"`
-	PricePerHour   float64       `json:"price_per_hour"`
-	CreatedAt      time.Time     `json:"created_at"`
-	ExpiresAt      time.Time     `json:"expires_at"`
+	ID                    string         `json:"id"`
+	ConsumerID            string         `json:"consumer_id"`
+	Provider              string         `json:"provider"`
+	GPUType               string         `json:"gpu_type"`
+	GPUCount              int            `json:"gpu_count"`
+	VRAM                  int            `json:"vram_gb,omitempty"`
+	Status                SessionStatus  `json:"status"`
+	ProvisionPhase        ProvisionPhase `json:"provision_phase,omitempty"`
+	Error                 string         `json:"error,omitempty"`
+	Location              string         `json:"location,omitempty"`
+	SSHHost               string         `json:"ssh_host,omitempty"`
+	SSHPort               int            `json:"ssh_port,omitempty"`
+	SSHUser               string         `json:"ssh_user,omitempty"`
+	SSHHostKeyFingerprint string         `json:"ssh_host_key_fingerprint,omitempty"`
+	CUDAVersion           float64        `json:"cuda_version,omitempty"`
+	DriverVersion         string         `json:"driver_version,omitempty"`
+	LaunchMode            LaunchMode     `json:"launch_mode,omitempty"`
+	APIEndpoint           string         `json:"api_endpoint,omitempty"`
+	APIPort               int            `json:"api_port,omitempty"`
+	ModelID               string         `json:"model_id,omitempty"`
+	TemplateHashID        string         `json:"template_hash_id,omitempty"` // Vast.ai template used
+	TemplateName          string         `json:"template_name,omitempty"`    // Template name for display
+	DiskGB                int            `json:"disk_gb,omitempty"`          // Disk space in GB
+	GPUBurnIn             bool           `json:"gpu_burn_in,omitempty"`
+	WorkloadType          WorkloadType   `json:"workload_type"`
+	ReservationHrs        int            `json:"reservation_hours"`
+	IdleThreshold         int            `json:"idle_threshold_minutes,omitempty"`
+	IdleGPUUtilPct        int            `json:"idle_gpu_util_pct,omitempty"`
+	PricePerHour          float64        `json:"price_per_hour"`
+	Interruptible         bool           `json:"interruptible,omitempty"`
+	BidPrice              float64        `json:"bid_price,omitempty"`
+	CreatedAt             time.Time      `json:"created_at"`
+	ExpiresAt             time.Time      `json:"expires_at"`
+	PausedAt              *time.Time     `json:"paused_at,omitempty"` // Paused sessions only
 
 	// Retry tracking
 	AutoRetry     bool   `json:"auto_retry,omitempty"`
@@ -203,39 +318,110 @@ type SessionResponse struct {
 	RetryParentID string `json:"retry_parent_id,omitempty"`
 	RetryChildID  string `json:"retry_child_id,omitempty"`
 	FailedOffers  string `json:"failed_offers,omitempty"`
+
+	GroupID string `json:"group_id,omitempty"`
+
+	FailureCategory FailureCategory `json:"failure_category,omitempty"`
+	FailureDetail   string          `json:"failure_detail,omitempty"`
+
+	MeasuredInetDownMbps      float64 `json:"measured_inet_down_mbps,omitempty"`
+	MeasuredDiskBandwidthMBps float64 `json:"measured_disk_bw_mbps,omitempty"`
+
+	Health   *SessionHealthResponse     `json:"health,omitempty"`   // Running sessions only
+	Progress *ProvisionProgressResponse `json:"progress,omitempty"` // Once the instance is created
+}
+
+// ProvisionProgressResponse is a session's provisioning milestones in API
+// responses. Milestones not reached yet are omitted.
+type ProvisionProgressResponse struct {
+	InstanceCreatedAt  *time.Time `json:"instance_created_at,omitempty"`
+	IPAssignedAt       *time.Time `json:"ip_assigned_at,omitempty"`
+	CloudInitDoneAt    *time.Time `json:"cloud_init_done_at,omitempty"`
+	ImagePulledAt      *time.Time `json:"image_pulled_at,omitempty"`
+	ContainerStartedAt *time.Time `json:"container_started_at,omitempty"`
+	WeightsLoadedAt    *time.Time `json:"weights_loaded_at,omitempty"`
+	SSHVerifiedAt      *time.Time `json:"ssh_verified_at,omitempty"`
+}
+
+// SessionHealthResponse is a running session's health in API responses
+type SessionHealthResponse struct {
+	Status          HealthStatus `json:"status"`
+	LastHeartbeatAt *time.Time   `json:"last_heartbeat_at,omitempty"`
+	GPUUtilPct      *float64     `json:"gpu_utilization,omitempty"`
+	IdleSeconds     int          `json:"idle_seconds"`
 }
 
 // ToResponse converts a Session to a SessionResponse (without secrets)
 func (s *Session) ToResponse() SessionResponse {
-	return SessionResponse{
-		ID:             s.ID,
-		ConsumerID:     s.ConsumerID,
-		Provider:       s.Provider,
-		GPUType:        s.GPUType,
-		GPUCount:       s.GPUCount,
-		Status:         s.Status,
-		Error:          s.Error,
-		SSHHost:        s.SSHHost,
-		SSHPort:        s.SSHPort,
-		SSHUser:        s.SSHUser,
-		LaunchMode:     s.LaunchMode,
-		APIEndpoint:    s.APIEndpoint,
-		APIPort:        s.APIPort,
-		ModelID:        s.ModelID,
-		TemplateHashID: s.TemplateHashID,
-		TemplateName:   s.TemplateName,
-		DiskGB:         s.DiskGB,
-		WorkloadType:   s.WorkloadType,
-		ReservationHrs: s.ReservationHrs,
-		PricePerHour:   s.PricePerHour,
-		CreatedAt:      s.CreatedAt,
-		ExpiresAt:      s.ExpiresAt,
-		AutoRetry:      s.AutoRetry,
-		RetryCount:     s.RetryCount,
-		RetryParentID:  s.RetryParentID,
-		RetryChildID:   s.RetryChildID,
-		FailedOffers:   s.FailedOffers,
+	resp := SessionResponse{
+		ID:                    s.ID,
+		ConsumerID:            s.ConsumerID,
+		Provider:              s.Provider,
+		GPUType:               s.GPUType,
+		GPUCount:              s.GPUCount,
+		VRAM:                  s.VRAM,
+		Status:                s.Status,
+		ProvisionPhase:        s.ProvisionPhase,
+		Error:                 s.Error,
+		Location:              s.Location,
+		SSHHost:               s.SSHHost,
+		SSHPort:               s.SSHPort,
+		SSHUser:               s.SSHUser,
+		SSHHostKeyFingerprint: s.SSHHostKeyFingerprint,
+		CUDAVersion:           s.CUDAVersion,
+		DriverVersion:         s.DriverVersion,
+		LaunchMode:            s.LaunchMode,
+		APIEndpoint:           s.APIEndpoint,
+		APIPort:               s.APIPort,
+		ModelID:               s.ModelID,
+		TemplateHashID:        s.TemplateHashID,
+		TemplateName:          s.TemplateName,
+		DiskGB:                s.DiskGB,
+		GPUBurnIn:             s.GPUBurnIn,
+		WorkloadType:          s.WorkloadType,
+		ReservationHrs:        s.ReservationHrs,
+		IdleThreshold:         s.IdleThreshold,
+		PricePerHour:          s.PricePerHour,
+		Interruptible:         s.Interruptible,
+		BidPrice:              s.BidPrice,
+		CreatedAt:             s.CreatedAt,
+		ExpiresAt:             s.ExpiresAt,
+		AutoRetry:             s.AutoRetry,
+		RetryCount:            s.RetryCount,
+		RetryParentID:         s.RetryParentID,
+		RetryChildID:          s.RetryChildID,
+		FailedOffers:          s.FailedOffers,
+		GroupID:               s.GroupID,
+
+		FailureCategory: s.FailureCategory,
+		FailureDetail:   s.FailureDetail,
+
+		MeasuredInetDownMbps:      s.MeasuredInetDownMbps,
+		MeasuredDiskBandwidthMBps: s.MeasuredDiskBandwidthMBps,
+	}
+	if s.IdleThreshold > 0 {
+		resp.IdleGPUUtilPct = s.IdlePolicy().MaxGPUUtilPct
+	}
+	if s.Status == StatusRunning && s.Health.Status != "" {
+		h := &SessionHealthResponse{
+			Status:      s.Health.Status,
+			GPUUtilPct:  s.Health.GPUUtilPct,
+			IdleSeconds: int(s.Health.IdleFor().Seconds()),
+		}
+		if !s.Health.LastHeartbeatAt.IsZero() {
+			last := s.Health.LastHeartbeatAt
+			h.LastHeartbeatAt = &last
+		}
+		resp.Health = h
+	}
+	if !s.Progress.InstanceCreatedAt.IsZero() {
+		resp.Progress = s.Progress.toResponse()
 	}
+	if s.Status == StatusPaused && !s.PausedAt.IsZero() {
+		pausedAt := s.PausedAt
+		resp.PausedAt = &pausedAt
+	}
+	return resp
 }
 
 // IsActive returns true if the session is in an active state
@@ -245,15 +431,172 @@ func (s *Session) IsActive() bool {
 		s.Status == StatusRunning
 }
 
+// RunningFor returns how long the session has existed at now, less the time
+// it spent paused. The hard maximum session duration applies to it.
+func (s *Session) RunningFor(now time.Time) time.Duration {
+	d := now.Sub(s.CreatedAt) - s.PausedTotal
+	if s.Status == StatusPaused && !s.PausedAt.IsZero() {
+		d -= now.Sub(s.PausedAt)
+	}
+	return d
+}
+
+// Rebooting reports whether the session's instance is being rebooted in
+// place at now, by this or any other server
+func (s *Session) Rebooting(now time.Time) bool {
+	return now.Before(s.RebootingUntil)
+}
+
+// ProjectedCost returns the expected total cost of the session if it runs
+// for its full reservation at the current hourly price.
+func (s *Session) ProjectedCost() float64 {
+	return s.PricePerHour * float64(s.ReservationHrs)
+}
+
+// MaxBootstrapScriptBytes limits a session's bootstrap script, which is
+// passed to the instance on the SSH command line
+const MaxBootstrapScriptBytes = 16 * 1024
+
+// DefaultIdleGPUUtilPct is the GPU utilization below which a session counts
+// as idle when it does not set its own
+const DefaultIdleGPUUtilPct = 5
+
+// IdlePolicy says when the lifecycle manager destroys an idle session
+type IdlePolicy struct {
+	After         time.Duration // How long the GPUs must stay idle; 0 = disabled
+	MaxGPUUtilPct int           // Utilization below this percentage is idle
+}
+
+// Idle reports whether a GPU utilization counts as idle under the policy
+func (p IdlePolicy) Idle(gpuUtilPct float64) bool {
+	return gpuUtilPct < float64(p.MaxGPUUtilPct)
+}
+
+// Enabled reports whether the policy destroys idle sessions at all
+func (p IdlePolicy) Enabled() bool {
+	return p.After > 0
+}
+
+// IdlePolicy returns the session's idle policy, filling in the default
+// utilization threshold. Sessions without an idle threshold get a disabled
+// policy that still says what counts as idle, for their health.
+func (s *Session) IdlePolicy() IdlePolicy {
+	pct := s.IdleGPUUtilPct
+	if pct <= 0 {
+		pct = DefaultIdleGPUUtilPct
+	}
+	if s.IdleThreshold <= 0 {
+		return IdlePolicy{MaxGPUUtilPct: pct}
+	}
+	return IdlePolicy{After: time.Duration(s.IdleThreshold) * time.Minute, MaxGPUUtilPct: pct}
+}
+
+// HealthStatus is whether a running session's heartbeats are arriving
+type HealthStatus string
+
+const (
+	HealthHealthy  HealthStatus = "healthy"  // The last heartbeat is recent
+	HealthDegraded HealthStatus = "degraded" // No heartbeat within the heartbeat timeout
+)
+
+// IsValid returns true if the health status is a recognized value
+func (h HealthStatus) IsValid() bool {
+	return h == HealthHealthy || h == HealthDegraded
+}
+
+// ProvisionProgress records when provisioning reached each milestone, so
+// clients can show how far a session has got. Zero times are milestones not
+// reached yet.
+type ProvisionProgress struct {
+	InstanceCreatedAt time.Time `json:"instance_created_at,omitempty"` // Provider created the instance
+	IPAssignedAt      time.Time `json:"ip_assigned_at,omitempty"`      // SSH host first known
+	CloudInitDoneAt   time.Time `json:"cloud_init_done_at,omitempty"`  // Boot wait over, verification polling began
+	SSHVerifiedAt     time.Time `json:"ssh_verified_at,omitempty"`     // SSH login verified (SSH mode only)
+
+	// Entrypoint-mode warm-up, observed through the provider's instance
+	// status and container logs
+	ImagePulledAt      time.Time `json:"image_pulled_at,omitempty"`
+	ContainerStartedAt time.Time `json:"container_started_at,omitempty"`
+	WeightsLoadedAt    time.Time `json:"weights_loaded_at,omitempty"`
+}
+
+func (p ProvisionProgress) toResponse() *ProvisionProgressResponse {
+	at := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+	return &ProvisionProgressResponse{
+		InstanceCreatedAt:  at(p.InstanceCreatedAt),
+		IPAssignedAt:       at(p.IPAssignedAt),
+		CloudInitDoneAt:    at(p.CloudInitDoneAt),
+		ImagePulledAt:      at(p.ImagePulledAt),
+		ContainerStartedAt: at(p.ContainerStartedAt),
+		WeightsLoadedAt:    at(p.WeightsLoadedAt),
+		SSHVerifiedAt:      at(p.SSHVerifiedAt),
+	}
+}
+
+// SessionHealth is what the lifecycle manager last learned about a running
+// session. A heartbeat is a provider status poll that finds the instance
+// running, with its GPU utilization where the provider reports one.
+type SessionHealth struct {
+	Status          HealthStatus `json:"status,omitempty"`
+	LastHeartbeatAt time.Time    `json:"last_heartbeat_at,omitempty"`
+	GPUUtilPct      *float64     `json:"gpu_utilization,omitempty"` // Percent; nil if not reported
+	IdleSince       time.Time    `json:"idle_since,omitempty"`      // First heartbeat of the current idle stretch
+}
+
+// IdleFor is how long the GPUs had been idle as of the last heartbeat
+func (h SessionHealth) IdleFor() time.Duration {
+	if h.IdleSince.IsZero() || h.LastHeartbeatAt.Before(h.IdleSince) {
+		return 0
+	}
+	return h.LastHeartbeatAt.Sub(h.IdleSince)
+}
+
 // IsTerminal returns true if the session is in a terminal state
 func (s *Session) IsTerminal() bool {
-	return s.Status == StatusStopped || s.Status == StatusFailed
+	return s.Status == StatusStopped || s.Status == StatusFailed || s.Status == StatusPreempted
+}
+
+// SessionSortField is what a session listing is ordered by
+type SessionSortField string
+
+const (
+	SessionSortCreatedAt SessionSortField = "created_at"
+	SessionSortPrice     SessionSortField = "price" // Hourly price
+	SessionSortCost      SessionSortField = "cost"  // Recorded cost so far
+)
+
+// IsValid returns true if the sort field is a recognized value
+func (f SessionSortField) IsValid() bool {
+	return f == SessionSortCreatedAt || f == SessionSortPrice || f == SessionSortCost
 }
 
 // SessionListFilter defines parameters for listing sessions
 type SessionListFilter struct {
-	ConsumerID string
-	Status     SessionStatus
-	Provider   string // Bug #100 fix: Add provider filter
-	Limit      int
+	ConsumerID    string
+	Status        SessionStatus
+	Provider      string // Bug #100 fix: Add provider filter
+	GroupID       string
+	Health        HealthStatus
+	GPUType       string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Limit         int
+	Offset        int
+
+	// ExcludeTerminal leaves out stopped, failed and preempted sessions
+	ExcludeTerminal bool
+
+	// Search matches text in the session's error, failure detail, offer ID,
+	// provider instance ID or consumer ID
+	Search string
+
+	// SortBy orders the listing, created_at when empty. Sessions are listed
+	// highest first unless SortAscending is set.
+	SortBy        SessionSortField
+	SortAscending bool
 }
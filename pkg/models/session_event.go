@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// SessionEventType distinguishes status transitions from other recorded
+// session activity
+type SessionEventType string
+
+const (
+	// SessionEventStatus is a status transition, recorded for every change
+	SessionEventStatus SessionEventType = "status"
+	// SessionEventBootstrap is the result of the session's bootstrap script
+	SessionEventBootstrap SessionEventType = "bootstrap"
+	// SessionEventBurnIn is the result of the session's GPU burn-in
+	SessionEventBurnIn SessionEventType = "burn_in"
+	// SessionEventWorkspace is the result of restoring or saving the
+	// session's workspace
+	SessionEventWorkspace SessionEventType = "workspace"
+	// SessionEventModelCache is the result of pulling the session's model
+	// weights from the model cache
+	SessionEventModelCache SessionEventType = "model_cache"
+	// SessionEventKernel is an error found in the kernel log of the
+	// session's instance while it runs, such as an OOM kill or a GPU Xid
+	// error
+	SessionEventKernel SessionEventType = "kernel"
+)
+
+// SessionEvent records a single session status transition, or the result of
+// a step run on the session's instance
+type SessionEvent struct {
+	ID         int64            `json:"id"`
+	SessionID  string           `json:"session_id"`
+	Type       SessionEventType `json:"type"`
+	FromStatus SessionStatus    `json:"from_status,omitempty"` // Empty for the event recorded at creation
+	ToStatus   SessionStatus    `json:"to_status"`
+	Reason     string           `json:"reason,omitempty"` // Session error at the time of the transition, if any, or why the step failed
+	Output     string           `json:"output,omitempty"` // Output of the step, for bootstrap and model cache events, or the kernel log lines of kernel events
+	CreatedAt  time.Time        `json:"created_at"`
+}
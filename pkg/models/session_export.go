@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// SessionExportVersion is the current session export bundle format
+const SessionExportVersion = 1
+
+// SessionExport is a portable snapshot of sessions, their status history and
+// cost records, used for disaster recovery drills and migrations between
+// deployments. SSH keys are encrypted with a key derived from a passphrase.
+type SessionExport struct {
+	Version      int                   `json:"version"`
+	DeploymentID string                `json:"deployment_id,omitempty"` // Deployment the sessions were exported from
+	ExportedAt   time.Time             `json:"exported_at"`
+	KeySalt      string                `json:"key_salt"`  // Base64 salt for the passphrase-derived key
+	KeyCheck     string                `json:"key_check"` // Encrypted known value, verifies the passphrase on import
+	Sessions     []SessionExportRecord `json:"sessions"`
+}
+
+// SessionExportRecord is the exported state of a single session
+type SessionExportRecord struct {
+	Session               *Session        `json:"session"`
+	EncryptedSSHPublicKey string          `json:"encrypted_ssh_public_key,omitempty"`
+	Events                []*SessionEvent `json:"events"`
+	Costs                 []CostRecord    `json:"costs"`
+}
+
+// SessionImportResult reports what an import did
+type SessionImportResult struct {
+	Imported    []string `json:"imported"`
+	Skipped     []string `json:"skipped"`     // Already present in this deployment
+	Deactivated []string `json:"deactivated"` // Imported as stopped instead of taking over the instance
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ProviderSpend reconciles this deployment's estimated spend on one provider
+// against the spend the provider itself reports
+type ProviderSpend struct {
+	Provider       string   `json:"provider"`
+	BillingSupport bool     `json:"billing_supported"` // Provider reports an account balance
+	EstimatedSpend float64  `json:"estimated_spend"`   // Recorded cost this month
+	ReportedSpend  float64  `json:"reported_spend"`    // Balance drawn down this month, deposits excluded
+	Discrepancy    float64  `json:"discrepancy"`       // Reported minus estimated
+	Balance        *float64 `json:"balance,omitempty"` // Last balance the provider reported
+	Error          string   `json:"error,omitempty"`   // Why the last balance check failed
+}
+
+// SpendCeilingStatus reports provider-reported spend against the hard spend
+// ceiling. Once tripped, every active session is terminated on each check
+// until the next month.
+type SpendCeilingStatus struct {
+	CeilingUSD         float64         `json:"ceiling_usd"` // 0 = kill switch disabled
+	PeriodStart        time.Time       `json:"period_start"`
+	CheckedAt          time.Time       `json:"checked_at,omitempty"`
+	EstimatedSpend     float64         `json:"estimated_spend"`
+	ReportedSpend      float64         `json:"reported_spend"`
+	Tripped            bool            `json:"tripped"`
+	TrippedAt          *time.Time      `json:"tripped_at,omitempty"`
+	SessionsTerminated int             `json:"sessions_terminated"`
+	Providers          []ProviderSpend `json:"providers"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// TerminationIssue is a reason a provider instance might outlive its session
+type TerminationIssue string
+
+const (
+	// TerminationIssueNoSession means no active session owns the instance
+	TerminationIssueNoSession TerminationIssue = "no_session"
+	// TerminationIssueNoExpiryLabel means the instance carries no expiry tag,
+	// so nothing on the provider side records when it should be gone
+	TerminationIssueNoExpiryLabel TerminationIssue = "no_expiry_label"
+	// TerminationIssueExpired means the instance's expiry tag or its
+	// session's expiry is in the past
+	TerminationIssueExpired TerminationIssue = "expired"
+	// TerminationIssueNoHeartbeat means the instance's session is degraded
+	TerminationIssueNoHeartbeat TerminationIssue = "no_heartbeat"
+)
+
+// TerminationAuditInstance is one provider instance in a termination audit
+type TerminationAuditInstance struct {
+	Provider      string             `json:"provider"`
+	InstanceID    string             `json:"instance_id"`
+	Name          string             `json:"name,omitempty"`
+	Status        string             `json:"status"`
+	PricePerHour  float64            `json:"price_per_hour"`
+	SessionID     string             `json:"session_id,omitempty"`
+	SessionStatus SessionStatus      `json:"session_status,omitempty"`
+	ExpiresAt     *time.Time         `json:"expires_at,omitempty"` // From the instance's tag
+	Issues        []TerminationIssue `json:"issues"`
+}
+
+// TerminationAuditProvider summarizes a termination audit for one provider
+type TerminationAuditProvider struct {
+	Provider string `json:"provider"`
+	// Instances audited, excluding those tagged for other deployments
+	Instances        int    `json:"instances"`
+	OtherDeployments int    `json:"other_deployments"`
+	Flagged          int    `json:"flagged"`
+	Error            string `json:"error,omitempty"`
+}
+
+// TerminationAudit cross-references every instance the providers report with
+// our sessions, flagging instances that may never be terminated
+type TerminationAudit struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Providers   []TerminationAuditProvider `json:"providers"`
+	Instances   []TerminationAuditInstance `json:"instances"`
+	Flagged     int                        `json:"flagged"`
+}
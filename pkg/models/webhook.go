@@ -0,0 +1,142 @@
+package models
+
+import "time"
+
+// WebhookEventType identifies an event that can be delivered to a webhook
+type WebhookEventType string
+
+const (
+	WebhookEventSessionCreated      WebhookEventType = "session.created"
+	WebhookEventSessionRunning      WebhookEventType = "session.running"
+	WebhookEventSessionFailed       WebhookEventType = "session.failed"
+	WebhookEventSessionDestroyed    WebhookEventType = "session.destroyed"
+	WebhookEventSessionPreempted    WebhookEventType = "session.preempted"
+	WebhookEventSessionFailedOver   WebhookEventType = "session.failed_over"
+	WebhookEventSessionExpiringSoon WebhookEventType = "session.expiring_soon"
+	WebhookEventSessionIdle         WebhookEventType = "session.idle"
+	WebhookEventHostKeyChanged      WebhookEventType = "session.host_key_changed"
+	WebhookEventDestroyFailed       WebhookEventType = "session.destroy_failed"
+	WebhookEventOrphanDetected      WebhookEventType = "orphan.detected"
+	WebhookEventBudgetAlert         WebhookEventType = "budget.alert"
+	WebhookEventPriceWatchMatched   WebhookEventType = "price_watch.matched"
+)
+
+// AllWebhookEventTypes lists every event type a subscription can select
+var AllWebhookEventTypes = []WebhookEventType{
+	WebhookEventSessionCreated,
+	WebhookEventSessionRunning,
+	WebhookEventSessionFailed,
+	WebhookEventSessionDestroyed,
+	WebhookEventSessionPreempted,
+	WebhookEventSessionFailedOver,
+	WebhookEventSessionExpiringSoon,
+	WebhookEventSessionIdle,
+	WebhookEventHostKeyChanged,
+	WebhookEventDestroyFailed,
+	WebhookEventOrphanDetected,
+	WebhookEventBudgetAlert,
+	WebhookEventPriceWatchMatched,
+}
+
+// IsValid returns true if the event type is a recognized value.
+func (t WebhookEventType) IsValid() bool {
+	for _, valid := range AllWebhookEventTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the JSON body posted to subscribers
+type WebhookEvent struct {
+	ID         string           `json:"id"`
+	Type       WebhookEventType `json:"type"`
+	ConsumerID string           `json:"consumer_id"`
+	SessionID  string           `json:"session_id,omitempty"`
+	Timestamp  time.Time        `json:"timestamp"`
+	Data       interface{}      `json:"data,omitempty"`
+}
+
+// SessionFailover is the data of a session.failed_over event. The
+// replacement's SSH private key is not included; consumers fetch it once
+// from the API.
+type SessionFailover struct {
+	PreemptedSessionID string          `json:"preempted_session_id"`
+	Session            SessionResponse `json:"session"`
+}
+
+// WebhookSubscription is a consumer-configured URL that receives events
+type WebhookSubscription struct {
+	ID         string             `json:"id"`
+	ConsumerID string             `json:"consumer_id"`
+	URL        string             `json:"url"`
+	Secret     string             `json:"-"`                // HMAC signing key, only returned at creation
+	Events     []WebhookEventType `json:"events,omitempty"` // Empty means all events
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// Wants returns true if the subscription should receive the event type.
+func (s *WebhookSubscription) Wants(eventType WebhookEventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus tracks a delivery through retries
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for the first or next attempt
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // Subscriber returned 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Gave up after the maximum attempts
+)
+
+// WebhookDelivery records one event sent to one subscription
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	SubscriptionID string                `json:"subscription_id"`
+	EventID        string                `json:"event_id"`
+	EventType      WebhookEventType      `json:"event_type"`
+	Payload        string                `json:"-"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    time.Time             `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+// SessionIdleWarning is the data of a session.idle event, sent once before
+// the idle policy destroys a session. Any GPU activity before TerminateAt
+// cancels the termination.
+type SessionIdleWarning struct {
+	Session     SessionResponse `json:"session"`
+	GPUUtilPct  float64         `json:"gpu_util_pct"`
+	IdleSince   time.Time       `json:"idle_since"`
+	TerminateAt time.Time       `json:"terminate_at"`
+}
+
+// SessionDestroyFailure is the data of a session.destroy_failed event, sent
+// each time a scheduled re-attempt fails to destroy a session's instance. The
+// instance may still be billing.
+type SessionDestroyFailure struct {
+	Session  SessionResponse     `json:"session"`
+	Instance QuarantinedInstance `json:"instance"`
+}
+
+// SessionHostKeyChange is the data of a session.host_key_changed event, sent
+// when a session's instance presents a different SSH host key than the one
+// recorded on first connection. The connection is refused.
+type SessionHostKeyChange struct {
+	Session             SessionResponse `json:"session"`
+	ExpectedFingerprint string          `json:"expected_fingerprint"`
+	ActualFingerprint   string          `json:"actual_fingerprint"`
+}
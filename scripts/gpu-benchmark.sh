@@ -478,6 +478,7 @@ GPU_AVG_TEMP=0
 GPU_MAX_TEMP=0
 GPU_AVG_POWER=0
 GPU_MAX_POWER=0
+GPU_TOTAL_POWER=0
 
 if [ -f "$GPU_STATS_FILE" ] && [ -s "$GPU_STATS_FILE" ]; then
   GPU_AVG_UTIL=$(awk -F',' '{sum+=$2;n++} END{if(n>0)printf "%.1f",sum/n;else print 0}' "$GPU_STATS_FILE")
@@ -486,8 +487,11 @@ if [ -f "$GPU_STATS_FILE" ] && [ -s "$GPU_STATS_FILE" ]; then
   GPU_MAX_MEM=$(awk -F',' 'BEGIN{max=0}{if($3>max)max=$3}END{printf "%d",max}' "$GPU_STATS_FILE")
   GPU_AVG_TEMP=$(awk -F',' '{sum+=$5;n++} END{if(n>0)printf "%.1f",sum/n;else print 0}' "$GPU_STATS_FILE")
   GPU_MAX_TEMP=$(awk -F',' 'BEGIN{max=0}{if($5>max)max=$5}END{printf "%.1f",max}' "$GPU_STATS_FILE")
-  GPU_AVG_POWER=$(awk -F',' '{sum+=$6;n++} END{if(n>0)printf "%.1f",sum/n;else print 0}' "$GPU_STATS_FILE")
-  GPU_MAX_POWER=$(awk -F',' 'BEGIN{max=0}{if($6>max)max=$6}END{printf "%.1f",max}' "$GPU_STATS_FILE")
+  # power.draw reads "[N/A]" on GPUs without a power sensor; skip those rows
+  GPU_AVG_POWER=$(awk -F',' '$6 ~ /[0-9]/{sum+=$6;n++} END{if(n>0)printf "%.1f",sum/n;else print 0}' "$GPU_STATS_FILE")
+  GPU_MAX_POWER=$(awk -F',' 'BEGIN{max=0} $6 ~ /[0-9]/{if($6>max)max=$6}END{printf "%.1f",max}' "$GPU_STATS_FILE")
+  # Total draw across all GPUs: sum each sample's rows, then average the samples
+  GPU_TOTAL_POWER=$(awk -F',' '$6 ~ /[0-9]/{t[$1]+=$6} END{for(k in t){sum+=t[k];n++} if(n>0)printf "%.1f",sum/n;else print 0}' "$GPU_STATS_FILE")
 fi
 
 # ── Step 10: Get model info from Ollama ─────────────────────────────────────
@@ -539,6 +543,7 @@ GPU_AVG_TEMP=$(ensure_numeric "$GPU_AVG_TEMP")
 GPU_MAX_TEMP=$(ensure_numeric "$GPU_MAX_TEMP")
 GPU_AVG_POWER=$(ensure_numeric "$GPU_AVG_POWER")
 GPU_MAX_POWER=$(ensure_numeric "$GPU_MAX_POWER")
+GPU_TOTAL_POWER=$(ensure_numeric "$GPU_TOTAL_POWER")
 : "${QUALITY_RESULTS:=[]}"
 : "${THROUGHPUT_DURATION:=0}" "${THROUGHPUT_MAX_TOKENS:=500}"
 
@@ -582,6 +587,7 @@ jq -n \
   --argjson gpu_max_temp "${GPU_MAX_TEMP:-0}" \
   --argjson gpu_avg_power "${GPU_AVG_POWER:-0}" \
   --argjson gpu_max_power "${GPU_MAX_POWER:-0}" \
+  --argjson gpu_total_power "${GPU_TOTAL_POWER:-0}" \
   --arg provider "$PROVIDER" \
   --arg location "$LOCATION" \
   --argjson price_per_hour "$PRICE_PER_HOUR" \
@@ -650,7 +656,8 @@ jq -n \
       avg_temperature_c: $gpu_avg_temp,
       max_temperature_c: $gpu_max_temp,
       avg_power_draw_w: $gpu_avg_power,
-      max_power_draw_w: $gpu_max_power
+      max_power_draw_w: $gpu_max_power,
+      avg_total_power_w: $gpu_total_power
     },
     provider: $provider,
     location: $location,
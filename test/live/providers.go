@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider"
+	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/middleware"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/tensordock"
 	"github.com/cloud-gpu-shopper/cloud-gpu-shopper/internal/provider/vastai"
 )
@@ -78,7 +79,7 @@ func (f *ProviderFactory) createVastAIClient() (provider.Provider, error) {
 		return nil, fmt.Errorf("VASTAI_API_KEY not configured")
 	}
 
-	return vastai.NewClient(apiKey), nil
+	return middleware.Wrap(vastai.NewClient(apiKey)), nil
 }
 
 // createTensorDockClient creates a TensorDock provider client.
@@ -101,7 +102,7 @@ func (f *ProviderFactory) createTensorDockClient() (provider.Provider, error) {
 		return nil, fmt.Errorf("TENSORDOCK_AUTH_ID and TENSORDOCK_API_TOKEN must be set")
 	}
 
-	return tensordock.NewClient(authID, apiToken), nil
+	return middleware.Wrap(tensordock.NewClient(authID, apiToken)), nil
 }
 
 // GetEnabledProviders returns provider instances for all enabled providers.